@@ -13,7 +13,10 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/validators"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/sirupsen/logrus"
 )
@@ -29,7 +32,9 @@ type Service struct {
 	headFetcher          blockchain.HeadFetcher
 	participationFetcher blockchain.ParticipationFetcher
 	stateNotifier        statefeed.Notifier
+	stateGen             *stategen.State
 	lastArchivedEpoch    uint64
+	lastFinalizedEpoch   uint64
 }
 
 // Config options for the archiver service.
@@ -38,6 +43,7 @@ type Config struct {
 	HeadFetcher          blockchain.HeadFetcher
 	ParticipationFetcher blockchain.ParticipationFetcher
 	StateNotifier        statefeed.Notifier
+	StateGen             *stategen.State
 }
 
 // NewArchiverService initializes the service from configuration options.
@@ -50,6 +56,7 @@ func NewArchiverService(ctx context.Context, cfg *Config) *Service {
 		headFetcher:          cfg.HeadFetcher,
 		participationFetcher: cfg.ParticipationFetcher,
 		stateNotifier:        cfg.StateNotifier,
+		stateGen:             cfg.StateGen,
 	}
 }
 
@@ -139,6 +146,29 @@ func (s *Service) archiveBalances(ctx context.Context, balances []uint64, epoch
 	return nil
 }
 
+// We archive the change of validator balances since the previously archived finalized epoch,
+// from which the balance history can be reconstructed without keeping historical states.
+func (s *Service) archiveBalanceDeltas(ctx context.Context, cpt *ethpb.Checkpoint) error {
+	root := bytesutil.ToBytes32(cpt.Root)
+	var finalizedState *state.BeaconState
+	var err error
+	if featureconfig.Get().NewStateMgmt {
+		finalizedState, err = s.stateGen.StateByRoot(ctx, root)
+	} else {
+		finalizedState, err = s.beaconDB.State(ctx, root)
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve finalized state")
+	}
+	if finalizedState == nil {
+		return fmt.Errorf("finalized state with root %#x not found", root)
+	}
+	if err := s.beaconDB.SaveArchivedBalanceDeltas(ctx, cpt.Epoch, finalizedState.Balances()); err != nil {
+		return errors.Wrap(err, "could not archive balance deltas")
+	}
+	return nil
+}
+
 func (s *Service) run(ctx context.Context) {
 	stateChannel := make(chan *feed.Event, 1)
 	stateSub := s.stateNotifier.StateFeed().Subscribe(stateChannel)
@@ -158,6 +188,14 @@ func (s *Service) run(ctx context.Context) {
 					log.WithError(err).Error("Head state is not available")
 					continue
 				}
+				if headState.FinalizedCheckpointEpoch() > s.lastFinalizedEpoch {
+					cpt := headState.FinalizedCheckpoint()
+					if err := s.archiveBalanceDeltas(ctx, cpt); err != nil {
+						log.WithError(err).Error("Could not archive finalized balance deltas")
+					} else {
+						s.lastFinalizedEpoch = cpt.Epoch
+					}
+				}
 				slot := headState.Slot()
 				currentEpoch := helpers.SlotToEpoch(slot)
 				if !helpers.IsEpochEnd(slot) && currentEpoch <= s.lastArchivedEpoch {
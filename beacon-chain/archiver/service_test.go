@@ -211,6 +211,55 @@ func TestArchiverService_SavesIndicesAndBalances(t *testing.T) {
 	testutil.AssertLogsContain(t, hook, "Successfully archived")
 }
 
+func TestArchiverService_SavesFinalizedBalanceDeltas(t *testing.T) {
+	hook := logTest.NewGlobal()
+	validatorCount := uint64(100)
+	finalizedState, err := setupState(validatorCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, beaconDB := setupService(t)
+	finalizedRoot := [32]byte{'f'}
+	if err := beaconDB.SaveState(svc.ctx, finalizedState, finalizedRoot); err != nil {
+		t.Fatal(err)
+	}
+	headState, err := setupState(validatorCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := headState.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 1, Root: finalizedRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	svc.headFetcher = &mock.ChainService{
+		State: headState,
+	}
+	event := &feed.Event{
+		Type: statefeed.BlockProcessed,
+		Data: &statefeed.BlockProcessedData{
+			BlockRoot: [32]byte{1, 2, 3},
+			Verified:  true,
+		},
+	}
+	triggerStateEvent(t, svc, event)
+
+	var epochs []uint64
+	err = beaconDB.ArchivedBalanceHistory(context.Background(), []uint64{0, validatorCount - 1}, 1, func(epoch uint64, balances []uint64) bool {
+		epochs = append(epochs, epoch)
+		want := []uint64{finalizedState.Balances()[0], finalizedState.Balances()[validatorCount-1]}
+		if !reflect.DeepEqual(want, balances) {
+			t.Errorf("Wanted balances %v, retrieved %v", want, balances)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(epochs, []uint64{1}) {
+		t.Errorf("Wanted balance deltas archived for epochs %v, retrieved %v", []uint64{1}, epochs)
+	}
+	testutil.AssertLogsDoNotContain(t, hook, "Could not archive finalized balance deltas")
+}
+
 func TestArchiverService_SavesCommitteeInfo(t *testing.T) {
 	hook := logTest.NewGlobal()
 	validatorCount := uint64(100)
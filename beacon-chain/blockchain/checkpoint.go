@@ -0,0 +1,160 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+// LoadCheckpoint reads a trusted checkpoint state and the block it was produced by, both SSZ
+// encoded. Each of them is given as a file path or as an http(s) URL to fetch it from.
+func LoadCheckpoint(
+	ctx context.Context,
+	statePath string,
+	blockPath string,
+) (*stateTrie.BeaconState, *ethpb.SignedBeaconBlock, error) {
+	enc, err := readCheckpointData(ctx, statePath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read checkpoint state")
+	}
+	protoState := &pb.BeaconState{}
+	if err := protoState.UnmarshalSSZ(enc); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint state")
+	}
+	st, err := stateTrie.InitializeFromProtoUnsafe(protoState)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not initialize checkpoint state")
+	}
+
+	enc, err = readCheckpointData(ctx, blockPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read checkpoint block")
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := blk.UnmarshalSSZ(enc); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint block")
+	}
+	return st, blk, nil
+}
+
+func readCheckpointData(ctx context.Context, path string) ([]byte, error) {
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		return ioutil.ReadFile(path)
+	}
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s returned status %s", path, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// checkpointOf verifies the checkpoint state is the state at the start of an epoch of the chain
+// of the checkpoint block, and returns the checkpoint of that epoch with the block root.
+func checkpointOf(
+	ctx context.Context,
+	st *stateTrie.BeaconState,
+	blk *ethpb.SignedBeaconBlock,
+) (*ethpb.Checkpoint, error) {
+	if blk == nil || blk.Block == nil {
+		return nil, errors.New("nil checkpoint block")
+	}
+	if st.Slot()%params.BeaconConfig().SlotsPerEpoch != 0 {
+		return nil, fmt.Errorf("checkpoint state slot %d is not the start of an epoch", st.Slot())
+	}
+	stateRoot, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash checkpoint state")
+	}
+	// The state root of the latest block header is only filled in by the next slot processing,
+	// it is still empty when the state slot is the one of the block.
+	header := st.LatestBlockHeader()
+	if bytesutil.ToBytes32(header.StateRoot) == params.BeaconConfig().ZeroHash {
+		header.StateRoot = stateRoot[:]
+	}
+	headerRoot, err := stateutil.BlockHeaderRoot(header)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash latest block header")
+	}
+	blkRoot, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash checkpoint block")
+	}
+	if headerRoot != blkRoot {
+		return nil, fmt.Errorf("checkpoint block %#x is not the latest block of the checkpoint state, wanted %#x",
+			blkRoot, headerRoot)
+	}
+	return &ethpb.Checkpoint{Epoch: helpers.SlotToEpoch(st.Slot()), Root: blkRoot[:]}, nil
+}
+
+// saveCheckpointData saves a trusted checkpoint state and block in an empty database, so the node
+// starts from them as if it was restarted with the checkpoint finalized. The checkpoint block
+// takes the place of the genesis block as the oldest block in the database, and only the blocks
+// after it are synced.
+func (s *Service) saveCheckpointData(
+	ctx context.Context,
+	st *stateTrie.BeaconState,
+	blk *ethpb.SignedBeaconBlock,
+) error {
+	cp, err := checkpointOf(ctx, st, blk)
+	if err != nil {
+		return err
+	}
+	root := bytesutil.ToBytes32(cp.Root)
+
+	if err := s.beaconDB.SaveBlock(ctx, blk); err != nil {
+		return errors.Wrap(err, "could not save checkpoint block")
+	}
+	if featureconfig.Get().NewStateMgmt {
+		if err := s.stateGen.SaveFinalizedState(ctx, root, st); err != nil {
+			return errors.Wrap(err, "could not save checkpoint state")
+		}
+	} else {
+		if err := s.beaconDB.SaveState(ctx, st, root); err != nil {
+			return errors.Wrap(err, "could not save checkpoint state")
+		}
+	}
+	if err := s.beaconDB.SaveGenesisBlockRoot(ctx, root); err != nil {
+		return errors.Wrap(err, "could not save checkpoint block root")
+	}
+	if err := s.beaconDB.SaveHeadBlockRoot(ctx, root); err != nil {
+		return errors.Wrap(err, "could not save head block root")
+	}
+	if err := s.beaconDB.SaveJustifiedCheckpoint(ctx, cp); err != nil {
+		return errors.Wrap(err, "could not save justified checkpoint")
+	}
+	if err := s.beaconDB.SaveFinalizedCheckpoint(ctx, cp); err != nil {
+		return errors.Wrap(err, "could not save finalized checkpoint")
+	}
+
+	log.WithFields(logrus.Fields{
+		"epoch": cp.Epoch,
+		"slot":  st.Slot(),
+		"root":  fmt.Sprintf("%#x", bytesutil.Trunc(cp.Root)),
+	}).Info("Initialized beacon chain from checkpoint state")
+	return nil
+}
@@ -0,0 +1,167 @@
+package blockchain
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	b "github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// checkpointData returns the state at the start of epoch 1 of a chain without blocks after
+// genesis, and the genesis block as its latest block.
+func checkpointData(t *testing.T) (*beaconstate.BeaconState, *ethpb.SignedBeaconBlock) {
+	genesisState, _ := testutil.DeterministicGenesisState(t, 64)
+	stateRoot, err := genesisState.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := b.NewGenesisBlock(stateRoot[:])
+	st, err := state.ProcessSlots(context.Background(), genesisState, params.BeaconConfig().SlotsPerEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st, blk
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	st, blk := checkpointData(t)
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	enc, err := st.InnerStateUnsafe().MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(dir, "state.ssz")
+	if err := ioutil.WriteFile(statePath, enc, 0644); err != nil {
+		t.Fatal(err)
+	}
+	blkEnc, err := blk.MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/block.ssz" {
+			http.NotFound(w, r)
+			return
+		}
+		if _, err := w.Write(blkEnc); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	loadedState, loadedBlk, err := LoadCheckpoint(context.Background(), statePath, srv.URL+"/block.ssz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotRoot, err := loadedState.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotRoot != wantRoot {
+		t.Errorf("Wanted state root %#x, received %#x", wantRoot, gotRoot)
+	}
+	if _, err := checkpointOf(context.Background(), loadedState, loadedBlk); err != nil {
+		t.Errorf("Loaded checkpoint does not verify: %v", err)
+	}
+
+	if _, _, err := LoadCheckpoint(context.Background(), statePath, srv.URL+"/missing"); err == nil {
+		t.Error("Expected error fetching a missing block")
+	}
+}
+
+func TestCheckpointOf_Invalid(t *testing.T) {
+	st, blk := checkpointData(t)
+	cp, err := checkpointOf(context.Background(), st, blk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Epoch != 1 {
+		t.Errorf("Wanted checkpoint epoch 1, received %d", cp.Epoch)
+	}
+
+	other := testutil.NewBeaconBlock()
+	if _, err := checkpointOf(context.Background(), st, other); err == nil {
+		t.Error("Expected error for a block which is not the latest block of the state")
+	}
+
+	st, err = state.ProcessSlots(context.Background(), st, st.Slot()+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := checkpointOf(context.Background(), st, blk); err == nil {
+		t.Error("Expected error for a state which is not at the start of an epoch")
+	}
+}
+
+func TestSaveCheckpointData_InitializesChainInfo(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	st, blk := checkpointData(t)
+	blkRoot, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Service{beaconDB: db, stateGen: stategen.New(db, cache.NewStateSummaryCache())}
+	if err := c.saveCheckpointData(ctx, st, blk); err != nil {
+		t.Fatal(err)
+	}
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finalized.Epoch != 1 || bytesutil.ToBytes32(finalized.Root) != blkRoot {
+		t.Errorf("Unexpected finalized checkpoint %v", finalized)
+	}
+	if !db.IsFinalizedBlock(ctx, blkRoot) {
+		t.Error("Expected checkpoint block to be finalized")
+	}
+
+	if err := c.initializeChainInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if c.genesisRoot != blkRoot {
+		t.Errorf("Wanted checkpoint block root %#x in place of genesis, received %#x", blkRoot, c.genesisRoot)
+	}
+	headRoot, err := c.HeadRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytesutil.ToBytes32(headRoot) != blkRoot {
+		t.Errorf("Wanted head root %#x, received %#x", blkRoot, headRoot)
+	}
+	headState, err := c.HeadState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headState.Slot() != params.BeaconConfig().SlotsPerEpoch {
+		t.Errorf("Wanted head state at slot %d, received %d", params.BeaconConfig().SlotsPerEpoch, headState.Slot())
+	}
+}
@@ -0,0 +1,37 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// LoadGenesisState reads an SSZ encoded genesis state, given as a file path or as an http(s) URL
+// to fetch it from.
+func LoadGenesisState(ctx context.Context, path string) (*stateTrie.BeaconState, error) {
+	enc, err := readCheckpointData(ctx, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read genesis state")
+	}
+	return UnmarshalGenesisState(enc)
+}
+
+// UnmarshalGenesisState decodes an SSZ encoded genesis state, such as the one embedded for a
+// known network, and checks it is the state of the genesis slot.
+func UnmarshalGenesisState(enc []byte) (*stateTrie.BeaconState, error) {
+	protoState := &pb.BeaconState{}
+	if err := protoState.UnmarshalSSZ(enc); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal genesis state")
+	}
+	if protoState.Slot != 0 {
+		return nil, fmt.Errorf("genesis state slot is %d, not 0", protoState.Slot)
+	}
+	st, err := stateTrie.InitializeFromProtoUnsafe(protoState)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not initialize genesis state")
+	}
+	return st, nil
+}
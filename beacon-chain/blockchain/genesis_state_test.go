@@ -0,0 +1,92 @@
+package blockchain
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLoadGenesisState(t *testing.T) {
+	genesisState, _ := testutil.DeterministicGenesisState(t, 64)
+	dir, err := ioutil.TempDir("", "genesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	enc, err := genesisState.InnerStateUnsafe().MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "genesis.ssz")
+	if err := ioutil.WriteFile(path, enc, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadGenesisState(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRoot, err := genesisState.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotRoot, err := loaded.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotRoot != wantRoot {
+		t.Errorf("Wanted state root %#x, received %#x", wantRoot, gotRoot)
+	}
+
+	// A state after genesis is not a genesis state.
+	st, _ := checkpointData(t)
+	enc, err = st.InnerStateUnsafe().MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnmarshalGenesisState(enc); err == nil {
+		t.Error("Expected error for a state which is not at the genesis slot")
+	}
+	if _, err := UnmarshalGenesisState([]byte("not a state")); err == nil {
+		t.Error("Expected error for an invalid encoding")
+	}
+}
+
+func TestChainStartStop_KnownGenesisState(t *testing.T) {
+	hook := logTest.NewGlobal()
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	genesisState, _ := testutil.DeterministicGenesisState(t, 64)
+
+	chainService := setupBeaconChain(t, db)
+	chainService.knownGenesisState = genesisState
+	chainService.Start()
+	if err := chainService.Stop(); err != nil {
+		t.Fatalf("unable to stop chain service: %v", err)
+	}
+
+	testutil.AssertLogsContain(t, hook, "Initialized beacon chain from known genesis state")
+	savedState, err := db.GenesisState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if savedState == nil {
+		t.Fatal("Expected the genesis state to be saved")
+	}
+	if savedState.GenesisTime() != genesisState.GenesisTime() {
+		t.Errorf("Wanted genesis time %d, received %d", genesisState.GenesisTime(), savedState.GenesisTime())
+	}
+	if chainService.genesisTime.Unix() != int64(genesisState.GenesisTime()) {
+		t.Errorf("Wanted the service genesis time %d, received %d", genesisState.GenesisTime(), chainService.genesisTime.Unix())
+	}
+}
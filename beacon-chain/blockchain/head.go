@@ -8,6 +8,7 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice/protoarray"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
@@ -117,16 +118,28 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 
 	// A chain re-org occurred, so we fire an event notifying the rest of the services.
 	if bytesutil.ToBytes32(newHeadBlock.Block.ParentRoot) != s.headRoot() {
+		reorg := &statefeed.ReorgData{
+			NewSlot:     newHeadBlock.Block.Slot,
+			OldSlot:     s.headSlot(),
+			NewHeadRoot: headRoot,
+			OldHeadRoot: s.headRoot(),
+		}
+		ancestorRoot, ancestorSlot, err := s.commonAncestor(ctx, reorg.OldHeadRoot, headRoot)
+		if err != nil {
+			log.WithError(err).Debug("Could not find common ancestor of reorged chains")
+		} else {
+			reorg.CommonAncestorRoot = ancestorRoot
+			reorg.CommonAncestorSlot = ancestorSlot
+			reorg.Depth = reorg.OldSlot - ancestorSlot
+		}
 		log.WithFields(logrus.Fields{
-			"newSlot": fmt.Sprintf("%d", newHeadBlock.Block.Slot),
-			"oldSlot": fmt.Sprintf("%d", s.headSlot()),
+			"newSlot": fmt.Sprintf("%d", reorg.NewSlot),
+			"oldSlot": fmt.Sprintf("%d", reorg.OldSlot),
+			"depth":   reorg.Depth,
 		}).Debug("Chain reorg occurred")
 		s.stateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Reorg,
-			Data: &statefeed.ReorgData{
-				NewSlot: newHeadBlock.Block.Slot,
-				OldSlot: s.headSlot(),
-			},
+			Data: reorg,
 		})
 
 		reorgCount.Inc()
@@ -134,6 +147,9 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 
 	// Cache the new head info.
 	s.setHead(headRoot, newHeadBlock, newHeadState)
+	if err := helpers.UpdateRecentRootsCache(headRoot, newHeadState); err != nil {
+		log.WithError(err).Error("Could not update recent roots cache")
+	}
 
 	// Save the new head root to DB.
 	if err := s.beaconDB.SaveHeadBlockRoot(ctx, headRoot); err != nil {
@@ -181,6 +197,43 @@ func (s *Service) saveHeadNoDB(ctx context.Context, b *ethpb.SignedBeaconBlock,
 	return nil
 }
 
+// This returns the root and slot of the latest common ancestor of two blocks, walking back the
+// chain of the later block until the chains meet.
+func (s *Service) commonAncestor(ctx context.Context, a [32]byte, b [32]byte) ([32]byte, uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "blockchain.commonAncestor")
+	defer span.End()
+
+	aBlk, err := s.beaconDB.Block(ctx, a)
+	if err != nil {
+		return [32]byte{}, 0, errors.Wrap(err, "could not get block")
+	}
+	bBlk, err := s.beaconDB.Block(ctx, b)
+	if err != nil {
+		return [32]byte{}, 0, errors.Wrap(err, "could not get block")
+	}
+	for {
+		if ctx.Err() != nil {
+			return [32]byte{}, 0, ctx.Err()
+		}
+		if aBlk == nil || aBlk.Block == nil || bBlk == nil || bBlk.Block == nil {
+			return [32]byte{}, 0, errors.New("nil block")
+		}
+		if a == b {
+			return a, aBlk.Block.Slot, nil
+		}
+		if aBlk.Block.Slot >= bBlk.Block.Slot {
+			a = bytesutil.ToBytes32(aBlk.Block.ParentRoot)
+			aBlk, err = s.beaconDB.Block(ctx, a)
+		} else {
+			b = bytesutil.ToBytes32(bBlk.Block.ParentRoot)
+			bBlk, err = s.beaconDB.Block(ctx, b)
+		}
+		if err != nil {
+			return [32]byte{}, 0, errors.Wrap(err, "could not get block")
+		}
+	}
+}
+
 // This sets head view object which is used to track the head slot, root, block and state.
 func (s *Service) setHead(root [32]byte, block *ethpb.SignedBeaconBlock, state *state.BeaconState) {
 	s.headLock.Lock()
@@ -140,6 +140,54 @@ func TestSaveHead_Different_Reorg(t *testing.T) {
 	testutil.AssertLogsContain(t, hook, "Chain reorg occurred")
 }
 
+func TestCommonAncestor(t *testing.T) {
+	db := testDB.SetupDB(t)
+	service := setupBeaconChain(t, db)
+	ctx := context.Background()
+
+	// The chain forks after slot 1 into 2 <- 4 and 3.
+	save := func(slot uint64, parent [32]byte) [32]byte {
+		blk := testutil.NewBeaconBlock()
+		blk.Block.Slot = slot
+		blk.Block.ParentRoot = parent[:]
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		r, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	r1 := save(1, [32]byte{})
+	r2 := save(2, r1)
+	r3 := save(3, r1)
+	r4 := save(4, r2)
+
+	tests := []struct {
+		a, b     [32]byte
+		wantRoot [32]byte
+		wantSlot uint64
+	}{
+		{a: r4, b: r3, wantRoot: r1, wantSlot: 1},
+		{a: r3, b: r4, wantRoot: r1, wantSlot: 1},
+		{a: r4, b: r2, wantRoot: r2, wantSlot: 2},
+		{a: r3, b: r3, wantRoot: r3, wantSlot: 3},
+	}
+	for _, tt := range tests {
+		root, slot, err := service.commonAncestor(ctx, tt.a, tt.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != tt.wantRoot || slot != tt.wantSlot {
+			t.Errorf("Wanted common ancestor %#x at slot %d, received %#x at slot %d", tt.wantRoot, tt.wantSlot, root, slot)
+		}
+	}
+	if _, _, err := service.commonAncestor(ctx, r4, [32]byte{'x'}); err == nil {
+		t.Error("Wanted an error for an unknown block")
+	}
+}
+
 func TestUpdateRecentCanonicalBlocks_CanUpdateWithoutParent(t *testing.T) {
 	db := testDB.SetupDB(t)
 	service := setupBeaconChain(t, db)
@@ -7,6 +7,9 @@ import (
 	"strconv"
 
 	"github.com/emicklei/dot"
+	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice/protoarray"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
 const template = `<html>
@@ -32,54 +35,119 @@ const template = `<html>
 </body>
 </html>`
 
-// TreeHandler is a handler to serve /tree page in metrics.
-func (s *Service) TreeHandler(w http.ResponseWriter, _ *http.Request) {
+// TreeHandler is a handler to serve /tree page in metrics. An optional `epochs` query
+// parameter limits the rendered tree to the most recent N epochs.
+func (s *Service) TreeHandler(w http.ResponseWriter, r *http.Request) {
 	if s.headState() == nil {
 		if _, err := w.Write([]byte("Unavailable during initial syncing")); err != nil {
 			log.WithError(err).Error("Failed to render p2p info page")
 		}
+		return
 	}
+	graph := s.forkChoiceGraph(epochsQueryParam(r))
 
+	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "text/html")
+	if _, err := fmt.Fprintf(w, template, graph.String()); err != nil {
+		log.WithError(err).Error("Failed to render p2p info page")
+	}
+}
+
+// TreeDotHandler is a handler to serve the raw Graphviz DOT export of the fork choice block
+// tree at /tree/dot, so fork incidents can be rendered offline. An optional `epochs` query
+// parameter limits the export to the most recent N epochs.
+func (s *Service) TreeDotHandler(w http.ResponseWriter, r *http.Request) {
+	if s.headState() == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if _, err := w.Write([]byte("Unavailable during initial syncing")); err != nil {
+			log.WithError(err).Error("Failed to render block tree")
+		}
+		return
+	}
+	graph := s.forkChoiceGraph(epochsQueryParam(r))
+
+	w.Header().Set("Content-Type", "text/vnd.graphviz")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(w, graph.String()); err != nil {
+		log.WithError(err).Error("Failed to render block tree")
+	}
+}
+
+// forkChoiceGraph builds a DOT graph of the fork choice store nodes within the most recent
+// epochs, annotated with weight, votes and finality information. An epochs value of 0
+// includes every node in the store.
+func (s *Service) forkChoiceGraph(epochs uint64) *dot.Graph {
 	nodes := s.forkChoiceStore.Nodes()
 
 	graph := dot.NewGraph(dot.Directed)
 	graph.Attr("rankdir", "RL")
 	graph.Attr("labeljust", "l")
 
+	headSlot := s.headSlot()
+	var lowestSlot uint64
+	if span := epochs * params.BeaconConfig().SlotsPerEpoch; epochs > 0 && headSlot > span {
+		lowestSlot = headSlot - span
+	}
+	var finalizedRoot, justifiedRoot [32]byte
+	if cp := s.FinalizedCheckpt(); cp != nil {
+		finalizedRoot = bytesutil.ToBytes32(cp.Root)
+	}
+	if cp := s.CurrentJustifiedCheckpt(); cp != nil {
+		justifiedRoot = bytesutil.ToBytes32(cp.Root)
+	}
+
 	dotNodes := make([]*dot.Node, len(nodes))
 	avgBalance := uint64(averageBalance(s.headState().Balances()))
 
 	for i := len(nodes) - 1; i >= 0; i-- {
+		if nodes[i].Slot < lowestSlot {
+			continue
+		}
 		// Construct label for each node.
 		slot := strconv.Itoa(int(nodes[i].Slot))
 		weight := strconv.Itoa(int(nodes[i].Weight / 1e9)) // Convert unit Gwei to unit ETH.
-		votes := strconv.Itoa(int(nodes[i].Weight / 1e9 / avgBalance))
+		votes := "0"
+		if avgBalance > 0 {
+			votes = strconv.Itoa(int(nodes[i].Weight / 1e9 / avgBalance))
+		}
 		index := strconv.Itoa(i)
 		g := nodes[i].Graffiti[:]
 		graffiti := hex.EncodeToString(g[:8])
-		label := "slot: " + slot + "\n votes: " + votes + "\n weight: " + weight + "\n graffiti: " + graffiti
-		var dotN dot.Node
-		if nodes[i].Parent != ^uint64(0) {
-			dotN = graph.Node(index).Box().Attr("label", label)
-		}
+		root := hex.EncodeToString(nodes[i].Root[:4])
+		justified := strconv.Itoa(int(nodes[i].JustifiedEpoch))
+		finalized := strconv.Itoa(int(nodes[i].FinalizedEpoch))
+		label := "slot: " + slot + "\n root: " + root + "\n votes: " + votes + "\n weight: " + weight +
+			"\n justified: " + justified + "\n finalized: " + finalized + "\n graffiti: " + graffiti
+		dotN := graph.Node(index).Box().Attr("label", label)
 
-		if nodes[i].Slot == s.headSlot() &&
-			nodes[i].BestDescendent == ^uint64(0) {
+		switch {
+		case nodes[i].Slot == headSlot && nodes[i].BestDescendent == protoarray.NonExistentNode:
 			dotN = dotN.Attr("color", "green")
+		case nodes[i].Root == finalizedRoot:
+			dotN = dotN.Attr("color", "blue")
+		case nodes[i].Root == justifiedRoot:
+			dotN = dotN.Attr("color", "orange")
 		}
 
 		dotNodes[i] = &dotN
 	}
 
 	for i := len(nodes) - 1; i >= 0; i-- {
-		if nodes[i].Parent != ^uint64(0) && nodes[i].Parent < uint64(len(dotNodes)) {
-			graph.Edge(*dotNodes[i], *dotNodes[nodes[i].Parent])
+		if dotNodes[i] == nil {
+			continue
+		}
+		parent := nodes[i].Parent
+		if parent != protoarray.NonExistentNode && parent < uint64(len(dotNodes)) && dotNodes[parent] != nil {
+			graph.Edge(*dotNodes[i], *dotNodes[parent])
 		}
 	}
+	return graph
+}
 
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "text/html")
-	if _, err := fmt.Fprintf(w, template, graph.String()); err != nil {
-		log.WithError(err).Error("Failed to render p2p info page")
+func epochsQueryParam(r *http.Request) uint64 {
+	epochs, err := strconv.ParseUint(r.URL.Query().Get("epochs"), 10, 64)
+	if err != nil {
+		return 0
 	}
+	return epochs
 }
@@ -0,0 +1,52 @@
+package blockchain
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice/protoarray"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestTreeDotHandler_RecentEpochs(t *testing.T) {
+	ctx := context.Background()
+	s, err := state.InitializeFromProto(&pb.BeaconState{Balances: []uint64{params.BeaconConfig().MaxEffectiveBalance}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Service{forkChoiceStore: protoarray.New(0, 0, [32]byte{'A'})}
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	if err := c.forkChoiceStore.ProcessBlock(ctx, 0, [32]byte{'A'}, [32]byte{}, [32]byte{}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.forkChoiceStore.ProcessBlock(ctx, 1, [32]byte{'B'}, [32]byte{'A'}, [32]byte{}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.forkChoiceStore.ProcessBlock(ctx, 3*slotsPerEpoch, [32]byte{'C'}, [32]byte{'B'}, [32]byte{}, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	c.head = &head{slot: 3 * slotsPerEpoch, root: [32]byte{'C'}, state: s}
+
+	rec := httptest.NewRecorder()
+	c.TreeDotHandler(rec, httptest.NewRequest(http.MethodGet, "/tree/dot", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, rec.Code)
+	}
+	if got := strings.Count(rec.Body.String(), "slot: "); got != 3 {
+		t.Errorf("Wanted 3 nodes in full export, received %d", got)
+	}
+
+	rec = httptest.NewRecorder()
+	c.TreeDotHandler(rec, httptest.NewRequest(http.MethodGet, "/tree/dot?epochs=1", nil))
+	if got := strings.Count(rec.Body.String(), "slot: "); got != 1 {
+		t.Errorf("Wanted 1 node in recent epoch export, received %d", got)
+	}
+	if !strings.Contains(rec.Body.String(), "digraph") {
+		t.Error("Expected a directed graph in DOT output")
+	}
+}
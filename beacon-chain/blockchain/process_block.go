@@ -7,11 +7,13 @@ import (
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/attestationutil"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -19,12 +21,16 @@ import (
 	"go.opencensus.io/trace"
 )
 
-// This defines size of the upper bound for initial sync block cache.
-var initialSyncBlockCacheSize = 2 * params.BeaconConfig().SlotsPerEpoch
+// This defines size of the upper bound for initial sync block cache. It is read from the config
+// when used, as a chain config file is loaded after the package is initialized.
+func initialSyncBlockCacheSize() uint64 {
+	return 2 * params.BeaconConfig().SlotsPerEpoch
+}
 
 // onBlock is called when a gossip block is received. It runs regular state transition on the block.
 // The block's signing root should be computed before calling this method to avoid redundant
-// computation in this method and methods it calls into.
+// computation in this method and methods it calls into. The signatures of the block in verified,
+// which were verified ahead of the state transition, are not verified again.
 //
 // Spec pseudocode definition:
 //   def on_block(store: Store, block: BeaconBlock) -> None:
@@ -55,7 +61,7 @@ var initialSyncBlockCacheSize = 2 * params.BeaconConfig().SlotsPerEpoch
 //    # Update finalized checkpoint
 //    if state.finalized_checkpoint.epoch > store.finalized_checkpoint.epoch:
 //        store.finalized_checkpoint = state.finalized_checkpoint
-func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock, blockRoot [32]byte) (*stateTrie.BeaconState, error) {
+func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock, blockRoot [32]byte, verified *bls.SignatureSet) (*stateTrie.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "blockchain.onBlock")
 	defer span.End()
 
@@ -76,10 +82,18 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock,
 		"root": fmt.Sprintf("0x%s...", hex.EncodeToString(blockRoot[:])[:8]),
 	}).Debug("Executing state transition on block")
 
-	postState, err := state.ExecuteStateTransition(ctx, preState, signed)
+	// All the signatures of the block are verified at once after the state transition, which
+	// only reports the invalid signature if the batch fails.
+	set, postState, err := state.ExecuteStateTransitionNoVerifyAnySig(ctx, preState, signed)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not execute state transition")
 	}
+	if err := blocks.VerifySignatureSet(withoutVerifiedSignatures(set, verified)); err != nil {
+		return nil, errors.Wrap(err, "could not verify block signatures")
+	}
+	if err := s.verifyWeakSubjectivityCheckpoint(ctx, postState); err != nil {
+		return nil, err
+	}
 
 	if err := s.beaconDB.SaveBlock(ctx, signed); err != nil {
 		return nil, errors.Wrapf(err, "could not save block from slot %d", b.Slot)
@@ -139,11 +153,16 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock,
 
 		s.prevFinalizedCheckpt = s.finalizedCheckpt
 		s.finalizedCheckpt = postState.FinalizedCheckpoint()
+		s.notifyFinalizedCheckpoint()
 
 		if err := s.finalizedImpliesNewJustified(ctx, postState); err != nil {
 			return nil, errors.Wrap(err, "could not save new justified")
 		}
 
+		if err := s.saveFinalizedValidatorIndices(ctx, bytesutil.ToBytes32(s.finalizedCheckpt.Root)); err != nil {
+			return nil, errors.Wrap(err, "could not save finalized validator indices")
+		}
+
 		if featureconfig.Get().NewStateMgmt {
 			fRoot := bytesutil.ToBytes32(postState.FinalizedCheckpoint().Root)
 			fBlock, err := s.beaconDB.Block(ctx, fRoot)
@@ -177,10 +196,13 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock,
 		return nil, err
 	}
 
-	// Delete the processed block attester slashings from slashings pool.
+	// Delete the processed block slashings from slashings pool.
 	for i := 0; i < len(b.Body.AttesterSlashings); i++ {
 		s.slashingPool.MarkIncludedAttesterSlashing(b.Body.AttesterSlashings[i])
 	}
+	for i := 0; i < len(b.Body.ProposerSlashings); i++ {
+		s.slashingPool.MarkIncludedProposerSlashing(b.Body.ProposerSlashings[i])
+	}
 
 	defer reportAttestationInclusion(b)
 
@@ -219,6 +241,9 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 	if err != nil {
 		return errors.Wrap(err, "could not execute state transition")
 	}
+	if err := s.verifyWeakSubjectivityCheckpoint(ctx, postState); err != nil {
+		return err
+	}
 
 	if !featureconfig.Get().NoInitSyncBatchSaveBlocks {
 		s.saveInitSyncBlock(blockRoot, signed)
@@ -258,7 +283,7 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 	}
 
 	// Rate limit how many blocks (2 epochs worth of blocks) a node keeps in the memory.
-	if len(s.getInitSyncBlocks()) > int(initialSyncBlockCacheSize) {
+	if len(s.getInitSyncBlocks()) > int(initialSyncBlockCacheSize()) {
 		if err := s.beaconDB.SaveBlocks(ctx, s.getInitSyncBlocks()); err != nil {
 			return err
 		}
@@ -295,11 +320,16 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 
 		s.prevFinalizedCheckpt = s.finalizedCheckpt
 		s.finalizedCheckpt = postState.FinalizedCheckpoint()
+		s.notifyFinalizedCheckpoint()
 
 		if err := s.finalizedImpliesNewJustified(ctx, postState); err != nil {
 			return errors.Wrap(err, "could not save new justified")
 		}
 
+		if err := s.saveFinalizedValidatorIndices(ctx, bytesutil.ToBytes32(s.finalizedCheckpt.Root)); err != nil {
+			return errors.Wrap(err, "could not save finalized validator indices")
+		}
+
 		if featureconfig.Get().NewStateMgmt {
 			fRoot := bytesutil.ToBytes32(postState.FinalizedCheckpoint().Root)
 			fBlock, err := s.beaconDB.Block(ctx, fRoot)
@@ -0,0 +1,94 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"sync"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"go.opencensus.io/trace"
+)
+
+// verifyProposerSignatures verifies the block signatures and randao reveals of a batch of blocks
+// ahead of their state transitions. The signing roots and domains of the blocks are computed and
+// verified concurrently, each worker verifying its share of the batch at once. The public keys of
+// the proposers are read from the head state, as the key of a validator never changes once it
+// is in the registry.
+//
+// It returns the verified signatures of each block, which its state transition does not verify
+// again. The signatures of a block are nil if they could not be verified ahead, for example when
+// a signature in the same share is invalid, in which case they are verified during the state
+// transition as usual, which reports the invalid signature.
+func (s *Service) verifyProposerSignatures(ctx context.Context, blks []*ethpb.SignedBeaconBlock) []*bls.SignatureSet {
+	_, span := trace.StartSpan(ctx, "blockchain.verifyProposerSignatures")
+	defer span.End()
+
+	sets := make([]*bls.SignatureSet, len(blks))
+	if len(blks) == 0 || !s.hasHeadState() {
+		return sets
+	}
+	headState := s.headState()
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(blks) {
+		workers = len(blks)
+	}
+	share := (len(blks) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(blks); start += share {
+		end := start + share
+		if end > len(blks) {
+			end = len(blks)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			batch := bls.NewSet()
+			for i := start; i < end; i++ {
+				set, err := blocks.ProposerSignatureSet(headState, blks[i])
+				if err != nil {
+					log.WithError(err).Debug("Could not retrieve proposer signatures of block ahead of its state transition")
+					continue
+				}
+				sets[i] = set
+				batch.Join(set)
+			}
+			if valid, err := batch.Verify(); err != nil || !valid {
+				for i := start; i < end; i++ {
+					sets[i] = nil
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return sets
+}
+
+// withoutVerifiedSignatures returns the signatures of a set which are not in the set of verified
+// signatures, with the same public key and message.
+func withoutVerifiedSignatures(set *bls.SignatureSet, verified *bls.SignatureSet) *bls.SignatureSet {
+	if verified == nil || len(verified.Signatures) == 0 {
+		return set
+	}
+	remaining := bls.NewSet()
+	for i, sig := range set.Signatures {
+		if !hasSignature(verified, sig, set.PublicKeys[i], set.Messages[i]) {
+			remaining.Add(sig, set.PublicKeys[i], set.Messages[i], set.Descriptions[i])
+		}
+	}
+	return remaining
+}
+
+func hasSignature(set *bls.SignatureSet, sig *bls.Signature, pubKey *bls.PublicKey, msg [32]byte) bool {
+	for i, m := range set.Messages {
+		if m == msg &&
+			bytes.Equal(set.Signatures[i].Marshal(), sig.Marshal()) &&
+			bytes.Equal(set.PublicKeys[i].Marshal(), pubKey.Marshal()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestVerifyProposerSignatures(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewService(ctx, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	beaconState, privs := testutil.DeterministicGenesisState(t, 64)
+	service.setHead([32]byte{}, &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{}}, beaconState)
+
+	preState := beaconState.Copy()
+	var blks []*ethpb.SignedBeaconBlock
+	for i := uint64(1); i <= 4; i++ {
+		blk, err := testutil.GenerateFullBlock(beaconState, privs, testutil.DefaultBlockGenConfig(), i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		beaconState, err = state.ExecuteStateTransition(ctx, beaconState, blk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blks = append(blks, blk)
+	}
+
+	verified := service.verifyProposerSignatures(ctx, blks)
+	for i, set := range verified {
+		if set == nil || len(set.Signatures) != 2 {
+			t.Fatalf("Wanted the block signature and randao reveal of block %d to be verified, received %v", i, set)
+		}
+	}
+
+	// The verified signatures are not verified again during the state transition.
+	set, _, err := state.ExecuteStateTransitionNoVerifyAnySig(ctx, preState, blks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	remaining := withoutVerifiedSignatures(set, verified[0])
+	if len(remaining.Signatures) != len(set.Signatures)-2 {
+		t.Errorf("Wanted %d signatures left to verify, received %d", len(set.Signatures)-2, len(remaining.Signatures))
+	}
+	for _, desc := range remaining.Descriptions {
+		if desc == "block" || desc == "randao" {
+			t.Errorf("Wanted %s signature to be skipped", desc)
+		}
+	}
+	if remaining := withoutVerifiedSignatures(set, verified[1]); len(remaining.Signatures) != len(set.Signatures) {
+		t.Error("Wanted the signatures of another block not to be skipped")
+	}
+
+	// An invalid signature is left to the state transition, which reports it.
+	blks[2].Signature = blks[1].Signature
+	verified = service.verifyProposerSignatures(ctx, blks)
+	if verified[2] != nil {
+		t.Error("Wanted the invalid block signature not to be verified")
+	}
+}
+
+func TestVerifyProposerSignatures_NoHeadState(t *testing.T) {
+	service, err := NewService(context.Background(), &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blks := []*ethpb.SignedBeaconBlock{testutil.NewBeaconBlock()}
+	verified := service.verifyProposerSignatures(context.Background(), blks)
+	if len(verified) != 1 || verified[0] != nil {
+		t.Errorf("Wanted no verified signatures, received %v", verified)
+	}
+}
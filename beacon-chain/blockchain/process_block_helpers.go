@@ -7,6 +7,8 @@ import (
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
@@ -14,14 +16,14 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
 )
 
 // CurrentSlot returns the current slot based on time.
 func (s *Service) CurrentSlot() uint64 {
-	now := roughtime.Now().Unix()
+	now := slotutil.Now().Unix()
 	genesis := s.genesisTime.Unix()
 	if now < genesis {
 		return 0
@@ -321,6 +323,42 @@ func (s *Service) updateJustified(ctx context.Context, state *stateTrie.BeaconSt
 	return s.beaconDB.SaveJustifiedCheckpoint(ctx, cpt)
 }
 
+// saveFinalizedValidatorIndices saves the public key to index mappings of the validators which
+// entered the registry by the finalized checkpoint. Only validators added since the last
+// finalized checkpoint are saved, as finalized registry indices never change.
+func (s *Service) saveFinalizedValidatorIndices(ctx context.Context, fRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "blockchain.saveFinalizedValidatorIndices")
+	defer span.End()
+
+	count, err := s.beaconDB.ValidatorIndicesCount(ctx)
+	if err != nil {
+		return err
+	}
+	var fState *stateTrie.BeaconState
+	if featureconfig.Get().NewStateMgmt {
+		fState, err = s.stateGen.StateByRoot(ctx, fRoot)
+	} else {
+		fState, err = s.beaconDB.State(ctx, fRoot)
+	}
+	if err != nil {
+		return err
+	}
+	// The finalized state may not be saved yet during initial sync, the indices are
+	// caught up on a later finalized checkpoint.
+	if fState == nil {
+		return nil
+	}
+	numValidators := uint64(fState.NumValidators())
+	if numValidators <= count {
+		return nil
+	}
+	pubKeys := make([][48]byte, 0, numValidators-count)
+	for i := count; i < numValidators; i++ {
+		pubKeys = append(pubKeys, fState.PubkeyAtIndex(i))
+	}
+	return s.beaconDB.SaveValidatorIndices(ctx, count, pubKeys)
+}
+
 // This saves every finalized state in DB during initial sync, needed as part of optimization to
 // use cache state during initial sync in case of restart.
 func (s *Service) saveInitState(ctx context.Context, state *stateTrie.BeaconState) error {
@@ -511,3 +549,14 @@ func (s *Service) deletePoolAtts(atts []*ethpb.Attestation) error {
 
 	return nil
 }
+
+// notifyFinalizedCheckpoint sends the new finalized checkpoint of the chain to the state feed.
+func (s *Service) notifyFinalizedCheckpoint() {
+	s.stateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.FinalizedCheckpoint,
+		Data: &statefeed.FinalizedCheckpointData{
+			Epoch:     s.finalizedCheckpt.Epoch,
+			BlockRoot: bytesutil.ToBytes32(s.finalizedCheckpt.Root),
+		},
+	})
+}
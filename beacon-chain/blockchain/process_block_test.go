@@ -123,7 +123,7 @@ func TestStore_OnBlock(t *testing.T) {
 			if err != nil {
 				t.Error(err)
 			}
-			_, err = service.onBlock(ctx, &ethpb.SignedBeaconBlock{Block: tt.blk}, root)
+			_, err = service.onBlock(ctx, &ethpb.SignedBeaconBlock{Block: tt.blk}, root, nil)
 			if err == nil || !strings.Contains(err.Error(), tt.wantErrString) {
 				t.Errorf("Store.OnBlock() error = %v, wantErr = %v", err, tt.wantErrString)
 			}
@@ -772,3 +772,61 @@ func TestCurrentSlot_HandlesOverflow(t *testing.T) {
 		t.Fatalf("Expected slot to be 0, got %d", slot)
 	}
 }
+
+func TestSaveFinalizedValidatorIndices_SavesNewValidators(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+
+	cfg := &Config{BeaconDB: db}
+	service, err := NewService(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validators := make([]*ethpb.Validator, 4)
+	for i := range validators {
+		pubKey := make([]byte, 48)
+		pubKey[0] = byte(i + 1)
+		validators[i] = &ethpb.Validator{PublicKey: pubKey}
+	}
+	fRoot := [32]byte{'a'}
+	s := testutil.NewBeaconState()
+	if err := s.SetValidators(validators[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, s, fRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.saveFinalizedValidatorIndices(ctx, fRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	fRoot = [32]byte{'b'}
+	s = testutil.NewBeaconState()
+	if err := s.SetValidators(validators); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, s, fRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.saveFinalizedValidatorIndices(ctx, fRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := db.ValidatorIndicesCount(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != uint64(len(validators)) {
+		t.Errorf("Wanted %d saved indices, received %d", len(validators), count)
+	}
+	for i, v := range validators {
+		index, ok, err := db.ValidatorIndex(ctx, bytesutil.ToBytes48(v.PublicKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || index != uint64(i) {
+			t.Errorf("Wanted index %d, received %d (found=%v)", i, index, ok)
+		}
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"github.com/sirupsen/logrus"
@@ -24,6 +26,7 @@ type BlockReceiver interface {
 	ReceiveBlockNoPubsub(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte) error
 	ReceiveBlockNoPubsubForkchoice(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte) error
 	ReceiveBlockNoVerify(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte) error
+	ReceiveBlockBatch(ctx context.Context, blocks []*ethpb.SignedBeaconBlock, blockRoots [][32]byte) error
 	HasInitSyncBlock(root [32]byte) bool
 }
 
@@ -65,10 +68,15 @@ func (s *Service) ReceiveBlock(ctx context.Context, block *ethpb.SignedBeaconBlo
 func (s *Service) ReceiveBlockNoPubsub(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "beacon-chain.blockchain.ReceiveBlockNoPubsub")
 	defer span.End()
+	done, err := s.startBlockProcessing()
+	if err != nil {
+		return err
+	}
+	defer done()
 	blockCopy := stateTrie.CopySignedBeaconBlock(block)
 
 	// Apply state transition on the new block.
-	postState, err := s.onBlock(ctx, blockCopy, blockRoot)
+	postState, err := s.onBlock(ctx, blockCopy, blockRoot, nil /* verified */)
 	if err != nil {
 		err := errors.Wrap(err, "could not process block")
 		traceutil.AnnotateError(span, err)
@@ -80,6 +88,7 @@ func (s *Service) ReceiveBlockNoPubsub(ctx context.Context, block *ethpb.SignedB
 		log.Errorf("Could not save attestation for fork choice: %v", err)
 		return nil
 	}
+	tracer.Include(blockCopy.Block.Slot, blockRoot, blockCopy.Block.Body.Attestations)
 	for _, exit := range block.Block.Body.VoluntaryExits {
 		s.exitPool.MarkIncluded(exit)
 	}
@@ -127,10 +136,40 @@ func (s *Service) ReceiveBlockNoPubsub(ctx context.Context, block *ethpb.SignedB
 func (s *Service) ReceiveBlockNoPubsubForkchoice(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "beacon-chain.blockchain.ReceiveBlockNoForkchoice")
 	defer span.End()
+	return s.receiveBlockNoPubsubForkchoice(ctx, block, blockRoot, nil /* verified */)
+}
+
+// ReceiveBlockBatch processes a batch of consecutive blocks received from initial sync, each as
+// ReceiveBlockNoPubsubForkchoice does. The proposer signatures of the whole batch are verified
+// concurrently ahead of the state transitions, which are still applied one block at a time.
+// It stops at the first block which fails to be processed.
+func (s *Service) ReceiveBlockBatch(ctx context.Context, blocks []*ethpb.SignedBeaconBlock, blockRoots [][32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "beacon-chain.blockchain.ReceiveBlockBatch")
+	defer span.End()
+	if len(blocks) != len(blockRoots) {
+		return errors.Errorf("received %d blocks but %d block roots", len(blocks), len(blockRoots))
+	}
+
+	verified := s.verifyProposerSignatures(ctx, blocks)
+	for i, block := range blocks {
+		if err := s.receiveBlockNoPubsubForkchoice(ctx, block, blockRoots[i], verified[i]); err != nil {
+			return errors.Wrapf(err, "could not process block %#x", blockRoots[i])
+		}
+	}
+	return nil
+}
+
+func (s *Service) receiveBlockNoPubsubForkchoice(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte, verified *bls.SignatureSet) error {
+	span := trace.FromContext(ctx)
+	done, err := s.startBlockProcessing()
+	if err != nil {
+		return err
+	}
+	defer done()
 	blockCopy := stateTrie.CopySignedBeaconBlock(block)
 
 	// Apply state transition on the new block.
-	_, err := s.onBlock(ctx, blockCopy, blockRoot)
+	_, err = s.onBlock(ctx, blockCopy, blockRoot, verified)
 	if err != nil {
 		err := errors.Wrap(err, "could not process block")
 		traceutil.AnnotateError(span, err)
@@ -179,6 +218,11 @@ func (s *Service) ReceiveBlockNoPubsubForkchoice(ctx context.Context, block *eth
 func (s *Service) ReceiveBlockNoVerify(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "beacon-chain.blockchain.ReceiveBlockNoVerify")
 	defer span.End()
+	done, err := s.startBlockProcessing()
+	if err != nil {
+		return err
+	}
+	defer done()
 	blockCopy := stateTrie.CopySignedBeaconBlock(block)
 
 	// Apply state transition on the incoming newly received blockCopy without verifying its BLS contents.
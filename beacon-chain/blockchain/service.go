@@ -37,12 +37,15 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"go.opencensus.io/trace"
 )
 
 // Service represents a service that handles the internal
 // logic of managing the full PoS beacon chain.
 type Service struct {
+	// lastSlotTick is accessed atomically and kept first for 64-bit alignment.
+	lastSlotTick              int64
 	ctx                       context.Context
 	cancel                    context.CancelFunc
 	beaconDB                  db.HeadAccessDatabase
@@ -79,6 +82,12 @@ type Service struct {
 	initSyncBlocksLock        sync.RWMutex
 	recentCanonicalBlocks     map[[32]byte]bool
 	recentCanonicalBlocksLock sync.RWMutex
+	blockProcessingLock       sync.RWMutex
+	stopping                  bool
+	wsCheckpoint              *ethpb.Checkpoint
+	checkpointSyncState       *stateTrie.BeaconState
+	checkpointSyncBlock       *ethpb.SignedBeaconBlock
+	knownGenesisState         *stateTrie.BeaconState
 }
 
 // Config options for the service.
@@ -96,6 +105,15 @@ type Config struct {
 	ForkChoiceStore   f.ForkChoicer
 	OpsService        *attestations.Service
 	StateGen          *stategen.State
+	// WeakSubjectivityCheckpoint is a trusted checkpoint the chain must contain, if set.
+	WeakSubjectivityCheckpoint *ethpb.Checkpoint
+	// CheckpointState and CheckpointBlock are a trusted state and its block to start from instead
+	// of genesis, if set and the database is empty.
+	CheckpointState *stateTrie.BeaconState
+	CheckpointBlock *ethpb.SignedBeaconBlock
+	// GenesisState is the known genesis state of the chain, saved as genesis if set and the
+	// database is empty instead of waiting for the genesis of the deposit contract.
+	GenesisState *stateTrie.BeaconState
 }
 
 // NewService instantiates a new block service instance that will
@@ -123,6 +141,10 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 		stateGen:              cfg.StateGen,
 		initSyncBlocks:        make(map[[32]byte]*ethpb.SignedBeaconBlock),
 		recentCanonicalBlocks: make(map[[32]byte]bool),
+		wsCheckpoint:          cfg.WeakSubjectivityCheckpoint,
+		checkpointSyncState:   cfg.CheckpointState,
+		checkpointSyncBlock:   cfg.CheckpointBlock,
+		knownGenesisState:     cfg.GenesisState,
 	}, nil
 }
 
@@ -134,6 +156,24 @@ func (s *Service) Start() {
 		log.Fatalf("Could not fetch beacon state: %v", err)
 	}
 
+	if s.checkpointSyncState != nil {
+		if beaconState != nil {
+			log.Warn("Blockchain data already exists in DB, ignoring checkpoint state")
+		} else {
+			if err := s.saveCheckpointData(ctx, s.checkpointSyncState, s.checkpointSyncBlock); err != nil {
+				log.Fatalf("Could not initialize beacon chain from checkpoint state: %v", err)
+			}
+			beaconState = s.checkpointSyncState
+		}
+	}
+	if s.knownGenesisState != nil && beaconState == nil {
+		if err := s.saveGenesisData(ctx, s.knownGenesisState); err != nil {
+			log.Fatalf("Could not save genesis state: %v", err)
+		}
+		log.Info("Initialized beacon chain from known genesis state")
+		beaconState = s.knownGenesisState
+	}
+
 	// For running initial sync with state cache, in an event of restart, we use
 	// last finalized check point as start point to sync instead of head
 	// state. This is because we no longer save state every slot during sync.
@@ -163,6 +203,7 @@ func (s *Service) Start() {
 	if beaconState != nil {
 		log.Info("Blockchain data already exists in DB, initializing...")
 		s.genesisTime = time.Unix(int64(beaconState.GenesisTime()), 0)
+		slotutil.AnchorClock(s.genesisTime)
 		s.opsService.SetGenesisTime(beaconState.GenesisTime())
 		if err := s.initializeChainInfo(ctx); err != nil {
 			log.Fatalf("Could not set up chain info: %v", err)
@@ -184,6 +225,14 @@ func (s *Service) Start() {
 		s.prevFinalizedCheckpt = stateTrie.CopyCheckpoint(finalizedCheckpoint)
 		s.resumeForkChoice(justifiedCheckpoint, finalizedCheckpoint)
 
+		if err := s.verifyWeakSubjectivityCheckpoint(ctx, beaconState); err != nil {
+			log.Fatalf("Could not verify weak subjectivity checkpoint: %v", err)
+		}
+
+		if err := s.restoreOperationPools(ctx, beaconState); err != nil {
+			log.WithError(err).Warn("Could not restore operation pools")
+		}
+
 		if !featureconfig.Get().NewStateMgmt {
 			if finalizedCheckpoint.Epoch > 1 {
 				if err := s.pruneGarbageState(ctx, helpers.StartSlot(finalizedCheckpoint.Epoch)-params.BeaconConfig().SlotsPerEpoch); err != nil {
@@ -235,6 +284,28 @@ func (s *Service) Start() {
 	}
 
 	go s.processAttestation(attestationProcessorSubscribed)
+	go s.clearRecentRootsOnReorg()
+}
+
+// clearRecentRootsOnReorg clears the recent roots cache on every reorg, as the cached roots of the
+// slots since the common ancestor belong to the old chain.
+func (s *Service) clearRecentRootsOnReorg() {
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.stateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	for {
+		select {
+		case event := <-stateChannel:
+			if event.Type == statefeed.Reorg {
+				helpers.ClearRecentRootsCache()
+			}
+		case <-s.ctx.Done():
+			return
+		case err := <-stateSub.Err():
+			log.WithError(err).Error("Subscription to state notifier failed")
+			return
+		}
+	}
 }
 
 // processChainStartTime initializes a series of deposits from the ChainStart deposits in the eth1
@@ -265,6 +336,7 @@ func (s *Service) initializeBeaconChain(
 	_, span := trace.StartSpan(context.Background(), "beacon-chain.Service.initializeBeaconChain")
 	defer span.End()
 	s.genesisTime = genesisTime
+	slotutil.AnchorClock(genesisTime)
 	unixTime := uint64(genesisTime.Unix())
 
 	genesisState, err := state.OptimizedGenesisBeaconState(unixTime, preGenesisState, eth1data)
@@ -294,9 +366,14 @@ func (s *Service) initializeBeaconChain(
 	return genesisState, nil
 }
 
-// Stop the blockchain service's main event loop and associated goroutines.
+// Stop the blockchain service's main event loop and associated goroutines. Blocks being
+// processed are finished first, then the data only held in memory is persisted.
 func (s *Service) Stop() error {
 	defer s.cancel()
+	s.drainBlockProcessing()
+	if err := s.persistOnShutdown(s.ctx); err != nil {
+		log.WithError(err).Error("Could not persist chain data on shutdown")
+	}
 	return nil
 }
 
@@ -0,0 +1,190 @@
+package blockchain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/sirupsen/logrus"
+)
+
+// errStopping is returned for blocks received after the service started shutting down.
+var errStopping = errors.New("blockchain service is stopping")
+
+// startBlockProcessing marks a block as in flight, so shutdown waits for it to be processed.
+// The returned function must be called once processing is done.
+func (s *Service) startBlockProcessing() (func(), error) {
+	s.blockProcessingLock.RLock()
+	if s.stopping {
+		s.blockProcessingLock.RUnlock()
+		return nil, errStopping
+	}
+	return s.blockProcessingLock.RUnlock, nil
+}
+
+// drainBlockProcessing waits for the blocks in flight to be processed and rejects new ones.
+func (s *Service) drainBlockProcessing() {
+	s.blockProcessingLock.Lock()
+	defer s.blockProcessingLock.Unlock()
+	s.stopping = true
+}
+
+// persistOnShutdown writes the data only held in memory to the DB: the blocks and states cached
+// during initial sync, the head block root, the cached state summaries and the operation pools.
+func (s *Service) persistOnShutdown(ctx context.Context) error {
+	if blks := s.getInitSyncBlocks(); len(blks) > 0 {
+		if err := s.beaconDB.SaveBlocks(ctx, blks); err != nil {
+			return errors.Wrap(err, "could not save initial sync blocks")
+		}
+		s.clearInitSyncBlocks()
+	}
+	if !featureconfig.Get().NewStateMgmt {
+		if err := s.persistInitSyncStates(ctx); err != nil {
+			return errors.Wrap(err, "could not save initial sync states")
+		}
+	}
+	if headRoot := s.headRoot(); headRoot != params.BeaconConfig().ZeroHash && s.beaconDB.HasBlock(ctx, headRoot) {
+		// The head state may have been pruned from the initial sync cache without being
+		// saved, in which case the node resumes from the last head saved to the DB.
+		if err := s.beaconDB.SaveHeadBlockRoot(ctx, headRoot); err != nil {
+			log.WithError(err).Warn("Could not save head block root")
+		}
+	}
+	if featureconfig.Get().NewStateMgmt && s.stateGen != nil {
+		if err := s.stateGen.SaveStateSummariesToDB(ctx); err != nil {
+			return errors.Wrap(err, "could not save state summaries")
+		}
+	}
+	if s.slashingPool != nil && s.exitPool != nil {
+		proposerSlashings, attesterSlashings := s.slashingPool.AllPendingSlashings()
+		exits := s.exitPool.AllPendingExits()
+		if err := s.beaconDB.SavePendingOperations(ctx, proposerSlashings, attesterSlashings, exits); err != nil {
+			return errors.Wrap(err, "could not save operation pools")
+		}
+		log.WithFields(logrus.Fields{
+			"proposerSlashings": len(proposerSlashings),
+			"attesterSlashings": len(attesterSlashings),
+			"voluntaryExits":    len(exits),
+		}).Debug("Saved operation pools")
+	}
+	if s.attPool != nil {
+		// Attestations are aggregated in the background, so the pools are read aggregated first
+		// for an attestation to be persisted at least once when aggregated in between.
+		aggregated := s.attPool.AggregatedAttestations()
+		unaggregated := s.attPool.UnaggregatedAttestations()
+		if err := s.beaconDB.SavePendingAttestations(ctx, aggregated, unaggregated); err != nil {
+			return errors.Wrap(err, "could not save attestation pools")
+		}
+		log.WithFields(logrus.Fields{
+			"aggregatedAttestations":   len(aggregated),
+			"unaggregatedAttestations": len(unaggregated),
+		}).Debug("Saved attestation pools")
+	}
+	return nil
+}
+
+// persistInitSyncStates writes the states cached during initial sync to the DB: the epoch
+// boundary states, which are otherwise only written once enough of them are cached, and the head
+// state, so the node resumes from its head rather than the last state written.
+func (s *Service) persistInitSyncStates(ctx context.Context) error {
+	headRoot := s.headRoot()
+	s.initSyncStateLock.Lock()
+	defer s.initSyncStateLock.Unlock()
+	roots := make([][32]byte, 0, len(s.boundaryRoots)+1)
+	states := make([]*stateTrie.BeaconState, 0, len(s.boundaryRoots)+1)
+	for _, rt := range s.boundaryRoots {
+		if st, ok := s.initSyncState[rt]; ok && rt != headRoot {
+			roots = append(roots, rt)
+			states = append(states, st)
+		}
+	}
+	if st, ok := s.initSyncState[headRoot]; ok {
+		roots = append(roots, headRoot)
+		states = append(states, st)
+	}
+	if len(roots) == 0 {
+		return nil
+	}
+	if err := s.beaconDB.SaveStates(ctx, states, roots); err != nil {
+		return err
+	}
+	log.WithField("states", len(roots)).Debug("Saved initial sync states")
+	return nil
+}
+
+// restoreOperationPools inserts the operations persisted on the last shutdown back into the
+// pools. They are verified against the head state again, so operations which were included
+// or became invalid in the meantime are dropped.
+func (s *Service) restoreOperationPools(ctx context.Context, headState *stateTrie.BeaconState) error {
+	if headState == nil {
+		return nil
+	}
+	if err := s.restoreAttestationPools(ctx, headState.GenesisTime()); err != nil {
+		return err
+	}
+	if s.slashingPool == nil || s.exitPool == nil {
+		return nil
+	}
+	proposerSlashings, attesterSlashings, exits, err := s.beaconDB.PendingOperations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not read operation pools")
+	}
+	for _, ps := range proposerSlashings {
+		if err := s.slashingPool.InsertProposerSlashing(ctx, headState, ps); err != nil {
+			log.WithError(err).Debug("Dropping persisted proposer slashing")
+		}
+	}
+	for _, as := range attesterSlashings {
+		if err := s.slashingPool.InsertAttesterSlashing(ctx, headState, as); err != nil {
+			log.WithError(err).Debug("Dropping persisted attester slashing")
+		}
+	}
+	for _, e := range exits {
+		s.exitPool.InsertVoluntaryExit(ctx, headState, e)
+	}
+	return nil
+}
+
+// restoreAttestationPools inserts the attestations persisted on the last shutdown back into the
+// pools, except for the attestations which expired in the meantime, being over an epoch old.
+func (s *Service) restoreAttestationPools(ctx context.Context, genesisTime uint64) error {
+	if s.attPool == nil {
+		return nil
+	}
+	aggregated, unaggregated, err := s.beaconDB.PendingAttestations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not read attestation pools")
+	}
+	currentSlot := slotutil.SlotsSinceGenesis(time.Unix(int64(genesisTime), 0))
+	expired := func(att *ethpb.Attestation) bool {
+		return att.Data.Slot+params.BeaconConfig().SlotsPerEpoch <= currentSlot
+	}
+	restored := 0
+	for _, att := range aggregated {
+		if expired(att) {
+			continue
+		}
+		if err := s.attPool.SaveAggregatedAttestation(att); err != nil {
+			log.WithError(err).Debug("Dropping persisted aggregated attestation")
+			continue
+		}
+		restored++
+	}
+	for _, att := range unaggregated {
+		if expired(att) {
+			continue
+		}
+		if err := s.attPool.SaveUnaggregatedAttestation(att); err != nil {
+			log.WithError(err).Debug("Dropping persisted unaggregated attestation")
+			continue
+		}
+		restored++
+	}
+	log.WithField("attestations", restored).Debug("Restored attestation pools")
+	return nil
+}
@@ -0,0 +1,179 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/slashings"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStop_PersistsOperationPools(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	beaconState, _ := testutil.DeterministicGenesisState(t, 8)
+
+	cctx, cancel := context.WithCancel(ctx)
+	s := &Service{
+		ctx:            cctx,
+		cancel:         cancel,
+		beaconDB:       db,
+		slashingPool:   slashings.NewPool(),
+		exitPool:       voluntaryexits.NewPool(),
+		initSyncBlocks: make(map[[32]byte]*ethpb.SignedBeaconBlock),
+	}
+	exit := &ethpb.SignedVoluntaryExit{Exit: &ethpb.VoluntaryExit{ValidatorIndex: 1}, Signature: make([]byte, 96)}
+	s.exitPool.InsertVoluntaryExit(ctx, beaconState, exit)
+
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	_, _, exits, err := db.PendingOperations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exits) != 1 || !proto.Equal(exits[0], exit) {
+		t.Errorf("Wanted persisted exits %v, received %v", []*ethpb.SignedVoluntaryExit{exit}, exits)
+	}
+
+	// A restarted service restores the pool.
+	restarted := &Service{
+		beaconDB:     db,
+		slashingPool: slashings.NewPool(),
+		exitPool:     voluntaryexits.NewPool(),
+	}
+	if err := restarted.restoreOperationPools(ctx, beaconState); err != nil {
+		t.Fatal(err)
+	}
+	if restored := restarted.exitPool.AllPendingExits(); len(restored) != 1 || !proto.Equal(restored[0], exit) {
+		t.Errorf("Wanted restored exits %v, received %v", []*ethpb.SignedVoluntaryExit{exit}, restored)
+	}
+}
+
+func TestStop_PersistsAttestationPools(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	beaconState, _ := testutil.DeterministicGenesisState(t, 8)
+	// The current slot is two epochs after genesis.
+	genesisTime := uint64(roughtime.Now().Unix()) - 2*params.BeaconConfig().SlotsPerEpoch*params.BeaconConfig().SecondsPerSlot
+	if err := beaconState.SetGenesisTime(genesisTime); err != nil {
+		t.Fatal(err)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	s := &Service{
+		ctx:            cctx,
+		cancel:         cancel,
+		beaconDB:       db,
+		attPool:        attestations.NewPool(),
+		initSyncBlocks: make(map[[32]byte]*ethpb.SignedBeaconBlock),
+	}
+	att := func(slot uint64, bits bitfield.Bitlist) *ethpb.Attestation {
+		return &ethpb.Attestation{
+			Data: &ethpb.AttestationData{
+				Slot:            slot,
+				BeaconBlockRoot: make([]byte, 32),
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			},
+			AggregationBits: bits,
+			Signature:       make([]byte, 96),
+		}
+	}
+	currentSlot := 2 * params.BeaconConfig().SlotsPerEpoch
+	if err := s.attPool.SaveAggregatedAttestations([]*ethpb.Attestation{
+		att(currentSlot-1, bitfield.Bitlist{0b1011}),
+		// Expired once restored.
+		att(currentSlot-params.BeaconConfig().SlotsPerEpoch, bitfield.Bitlist{0b1011}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.attPool.SaveUnaggregatedAttestation(att(currentSlot-2, bitfield.Bitlist{0b1001})); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	aggregated, unaggregated, err := db.PendingAttestations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aggregated) != 2 || len(unaggregated) != 1 {
+		t.Errorf("Wanted 2 aggregated and 1 unaggregated persisted attestations, received %d and %d", len(aggregated), len(unaggregated))
+	}
+
+	// A restarted service restores the attestations which did not expire.
+	restarted := &Service{
+		beaconDB: db,
+		attPool:  attestations.NewPool(),
+	}
+	if err := restarted.restoreOperationPools(ctx, beaconState); err != nil {
+		t.Fatal(err)
+	}
+	if restored := restarted.attPool.AggregatedAttestations(); len(restored) != 1 || restored[0].Data.Slot != currentSlot-1 {
+		t.Errorf("Wanted the aggregated attestation of slot %d restored, received %v", currentSlot-1, restored)
+	}
+	if restored := restarted.attPool.UnaggregatedAttestations(); len(restored) != 1 || restored[0].Data.Slot != currentSlot-2 {
+		t.Errorf("Wanted the unaggregated attestation of slot %d restored, received %v", currentSlot-2, restored)
+	}
+}
+
+func TestStop_RejectsNewBlocks(t *testing.T) {
+	db := testDB.SetupDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Service{
+		ctx:            ctx,
+		cancel:         cancel,
+		beaconDB:       db,
+		initSyncBlocks: make(map[[32]byte]*ethpb.SignedBeaconBlock),
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1}}
+	if err := s.ReceiveBlockNoPubsub(context.Background(), blk, [32]byte{'a'}); err != errStopping {
+		t.Errorf("Wanted error %v, received %v", errStopping, err)
+	}
+	if err := s.ReceiveBlockNoVerify(context.Background(), blk, [32]byte{'a'}); err != errStopping {
+		t.Errorf("Wanted error %v, received %v", errStopping, err)
+	}
+}
+
+func TestStop_PersistsInitSyncStates(t *testing.T) {
+	db := testDB.SetupDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	beaconState, _ := testutil.DeterministicGenesisState(t, 8)
+	boundaryRoot, headRoot, prunedRoot := [32]byte{'a'}, [32]byte{'b'}, [32]byte{'c'}
+	s := &Service{
+		ctx:            ctx,
+		cancel:         cancel,
+		beaconDB:       db,
+		initSyncBlocks: make(map[[32]byte]*ethpb.SignedBeaconBlock),
+		initSyncState: map[[32]byte]*stateTrie.BeaconState{
+			boundaryRoot: beaconState,
+			headRoot:     beaconState,
+			prunedRoot:   beaconState,
+		},
+		boundaryRoots: [][32]byte{boundaryRoot},
+		head:          &head{root: headRoot},
+	}
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if !db.HasState(context.Background(), boundaryRoot) || !db.HasState(context.Background(), headRoot) {
+		t.Error("Expected the boundary and head states to be saved")
+	}
+	if db.HasState(context.Background(), prunedRoot) {
+		t.Error("Expected a cached state which is neither a boundary nor the head state not to be saved")
+	}
+}
@@ -43,6 +43,7 @@ type ChainService struct {
 	opNotifier                  opfeed.Notifier
 	ValidAttestation            bool
 	ForkChoiceStore             *protoarray.Store
+	CanonicalRoots              map[[32]byte]bool
 }
 
 // StateNotifier mocks the same method in the chain service.
@@ -123,6 +124,16 @@ func (ms *ChainService) ReceiveBlockNoPubsub(ctx context.Context, block *ethpb.S
 	return nil
 }
 
+// ReceiveBlockBatch mocks ReceiveBlockBatch method in chain service.
+func (ms *ChainService) ReceiveBlockBatch(ctx context.Context, blocks []*ethpb.SignedBeaconBlock, blockRoots [][32]byte) error {
+	for i, block := range blocks {
+		if err := ms.ReceiveBlockNoPubsubForkchoice(ctx, block, blockRoots[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ReceiveBlockNoPubsubForkchoice mocks ReceiveBlockNoPubsubForkchoice method in chain service.
 func (ms *ChainService) ReceiveBlockNoPubsubForkchoice(ctx context.Context, block *ethpb.SignedBeaconBlock, blockRoot [32]byte) error {
 	if ms.State == nil {
@@ -174,6 +185,11 @@ func (ms *ChainService) HeadState(context.Context) (*stateTrie.BeaconState, erro
 	return ms.State, nil
 }
 
+// IsCanonical returns whether the block root is one of the mocked canonical roots.
+func (ms *ChainService) IsCanonical(_ context.Context, blockRoot [32]byte) (bool, error) {
+	return ms.CanonicalRoots[blockRoot], nil
+}
+
 // CurrentFork mocks HeadState method in chain service.
 func (ms *ChainService) CurrentFork() *pb.Fork {
 	return ms.Fork
@@ -0,0 +1,148 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// ParseWeakSubjectivityCheckpoint parses a checkpoint given as block_root:epoch, where the
+// block root is hex encoded with an optional 0x prefix.
+func ParseWeakSubjectivityCheckpoint(s string) (*ethpb.Checkpoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("checkpoint %q is not in the format block_root:epoch", s)
+	}
+	root, err := hex.DecodeString(strings.TrimPrefix(parts[0], "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode checkpoint block root")
+	}
+	if len(root) != 32 {
+		return nil, fmt.Errorf("checkpoint block root has %d bytes, wanted 32", len(root))
+	}
+	epoch, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse checkpoint epoch")
+	}
+	return &ethpb.Checkpoint{Root: root, Epoch: epoch}, nil
+}
+
+// WeakSubjectivityCheckpoint returns the latest weak subjectivity checkpoint of the head state
+// and the weak subjectivity period it was computed with. The checkpoint root is the finalized
+// block at the start slot of the checkpoint epoch, or the last one before it if the slot is empty.
+func (s *Service) WeakSubjectivityCheckpoint(ctx context.Context) (*ethpb.Checkpoint, uint64, error) {
+	if !s.hasHeadState() {
+		return nil, 0, errors.New("head state is not available")
+	}
+	headState := s.headState()
+	period, err := helpers.ComputeWeakSubjectivityPeriod(headState)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "could not compute weak subjectivity period")
+	}
+	epoch, err := helpers.LatestWeakSubjectivityEpoch(headState)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "could not compute weak subjectivity epoch")
+	}
+	root, err := s.finalizedRootAtSlot(ctx, helpers.StartSlot(epoch))
+	if err != nil {
+		return nil, 0, err
+	}
+	return &ethpb.Checkpoint{Epoch: epoch, Root: root[:]}, period, nil
+}
+
+// finalizedRootAtSlot returns the root of the finalized block at the slot, or of the last
+// finalized block before it.
+func (s *Service) finalizedRootAtSlot(ctx context.Context, slot uint64) ([32]byte, error) {
+	// HighestSlotBlocksBelow is exclusive, so start one slot above.
+	below := slot + 1
+	for {
+		blks, err := s.beaconDB.HighestSlotBlocksBelow(ctx, below)
+		if err != nil {
+			return [32]byte{}, errors.Wrap(err, "could not get blocks")
+		}
+		if len(blks) == 0 || blks[0] == nil || blks[0].Block == nil {
+			return [32]byte{}, fmt.Errorf("no finalized block found at or below slot %d", slot)
+		}
+		for _, b := range blks {
+			r, err := stateutil.BlockRoot(b.Block)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			if s.beaconDB.IsFinalizedBlock(ctx, r) {
+				return r, nil
+			}
+		}
+		if blks[0].Block.Slot == 0 {
+			return [32]byte{}, fmt.Errorf("no finalized block found at or below slot %d", slot)
+		}
+		below = blks[0].Block.Slot
+	}
+}
+
+// verifyWeakSubjectivityCheckpoint returns an error if the chain of the post state conflicts with
+// the weak subjectivity checkpoint the node was started with. While the checkpoint slot is within
+// the block roots of the state, its root must match. Past that, the checkpoint must be part of the
+// finalized chain once finalization reached its epoch.
+func (s *Service) verifyWeakSubjectivityCheckpoint(ctx context.Context, postState *stateTrie.BeaconState) error {
+	if s.wsCheckpoint == nil {
+		return nil
+	}
+	wsSlot := helpers.StartSlot(s.wsCheckpoint.Epoch)
+	if postState.Slot() <= wsSlot {
+		return nil
+	}
+	wsRoot := bytesutil.ToBytes32(s.wsCheckpoint.Root)
+	if postState.Slot() <= wsSlot+params.BeaconConfig().SlotsPerHistoricalRoot {
+		r, err := helpers.BlockRootAtSlot(postState, wsSlot)
+		if err != nil {
+			return errors.Wrap(err, "could not get block root at weak subjectivity checkpoint slot")
+		}
+		if bytesutil.ToBytes32(r) != wsRoot {
+			return fmt.Errorf("chain conflicts with weak subjectivity checkpoint %#x at epoch %d, got block root %#x",
+				wsRoot, s.wsCheckpoint.Epoch, r)
+		}
+		return nil
+	}
+	if s.finalizedCheckpt != nil && s.finalizedCheckpt.Epoch >= s.wsCheckpoint.Epoch && !s.beaconDB.IsFinalizedBlock(ctx, wsRoot) {
+		return fmt.Errorf("weak subjectivity checkpoint %#x at epoch %d is not part of the finalized chain",
+			wsRoot, s.wsCheckpoint.Epoch)
+	}
+	return nil
+}
+
+// WeakSubjectivityHandler serves the latest weak subjectivity checkpoint as JSON at
+// /weak_subjectivity, in the block_root:epoch format accepted by --weak-subjectivity-checkpoint.
+func (s *Service) WeakSubjectivityHandler(w http.ResponseWriter, r *http.Request) {
+	cp, period, err := s.WeakSubjectivityCheckpoint(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	resp := struct {
+		Checkpoint string `json:"checkpoint"`
+		Epoch      uint64 `json:"epoch"`
+		Root       string `json:"root"`
+		Period     uint64 `json:"period"`
+	}{
+		Checkpoint: fmt.Sprintf("%#x:%d", cp.Root, cp.Epoch),
+		Epoch:      cp.Epoch,
+		Root:       fmt.Sprintf("%#x", cp.Root),
+		Period:     period,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.WithError(err).Error("Could not write weak subjectivity response")
+	}
+}
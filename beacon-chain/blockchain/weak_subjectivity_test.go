@@ -0,0 +1,105 @@
+package blockchain
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestParseWeakSubjectivityCheckpoint(t *testing.T) {
+	root := "0x" + strings.Repeat("ab", 32)
+	cp, err := ParseWeakSubjectivityCheckpoint(root + ":100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Epoch != 100 || cp.Root[0] != 0xab || len(cp.Root) != 32 {
+		t.Errorf("Unexpected checkpoint %v", cp)
+	}
+
+	for _, s := range []string{root, root + ":x", "0xabcd:100", "zz:100", root + ":1:2"} {
+		if _, err := ParseWeakSubjectivityCheckpoint(s); err == nil {
+			t.Errorf("Expected error parsing %q", s)
+		}
+	}
+}
+
+func TestVerifyWeakSubjectivityCheckpoint(t *testing.T) {
+	wsRoot := [32]byte{'a'}
+	blockRoots := make([][]byte, params.BeaconConfig().SlotsPerHistoricalRoot)
+	for i := range blockRoots {
+		blockRoots[i] = make([]byte, 32)
+	}
+	wsSlot := params.BeaconConfig().SlotsPerEpoch
+	blockRoots[wsSlot] = wsRoot[:]
+
+	tests := []struct {
+		name      string
+		stateSlot uint64
+		root      [32]byte
+		wantErr   bool
+	}{
+		{name: "before checkpoint", stateSlot: wsSlot, root: [32]byte{'b'}},
+		{name: "matching root", stateSlot: wsSlot + 8, root: wsRoot},
+		{name: "conflicting root", stateSlot: wsSlot + 8, root: [32]byte{'b'}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := stateTrie.InitializeFromProto(&pb.BeaconState{Slot: tt.stateSlot, BlockRoots: blockRoots})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := &Service{wsCheckpoint: &ethpb.Checkpoint{Epoch: 1, Root: tt.root[:]}}
+			err = s.verifyWeakSubjectivityCheckpoint(context.Background(), st)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyWeakSubjectivityCheckpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Without a checkpoint nothing is verified.
+	st, err := stateTrie.InitializeFromProto(&pb.BeaconState{Slot: wsSlot + 8, BlockRoots: blockRoots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&Service{}).verifyWeakSubjectivityCheckpoint(context.Background(), st); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFinalizedRootAtSlot_SkipsUnfinalizedBlocks(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	s := &Service{beaconDB: db}
+
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{}}
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	// A block which is not part of the finalized chain.
+	orphan := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 5, ParentRoot: genesisRoot[:]}}
+	if err := db.SaveBlock(ctx, orphan); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := s.finalizedRootAtSlot(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != genesisRoot {
+		t.Errorf("Wanted genesis root %#x, received %#x", genesisRoot, root)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"sync"
 	"time"
 
@@ -13,16 +12,11 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"k8s.io/client-go/tools/cache"
 )
 
 var (
-	// Delay parameters
-	minDelay    = float64(10)        // 10 nanoseconds
-	maxDelay    = float64(100000000) // 0.1 second
-	delayFactor = 1.1
-
-	// Metrics
 	attestationCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "attestation_cache_miss",
 		Help: "The number of attestation data requests that aren't present in the cache.",
@@ -43,22 +37,27 @@ var (
 var ErrAlreadyInProgress = errors.New("already in progress")
 
 // AttestationCache is used to store the cached results of an AttestationData request.
+// Results are short lived: they expire after a slot, as the head they vote for may have
+// changed by then.
 type AttestationCache struct {
 	cache      *cache.FIFO
 	lock       sync.RWMutex
-	inProgress map[string]bool
+	inProgress map[string]chan struct{}
+	ttl        time.Duration
 }
 
 // NewAttestationCache initializes the map and underlying cache.
 func NewAttestationCache() *AttestationCache {
 	return &AttestationCache{
 		cache:      cache.NewFIFO(wrapperToKey),
-		inProgress: make(map[string]bool),
+		inProgress: make(map[string]chan struct{}),
+		ttl:        time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second,
 	}
 }
 
 // Get waits for any in progress calculation to complete before returning a
-// cached response, if any.
+// cached response, if any. Concurrent identical requests thereby share a single
+// calculation.
 func (c *AttestationCache) Get(ctx context.Context, req *ethpb.AttestationDataRequest) (*ethpb.AttestationData, error) {
 	if req == nil {
 		return nil, errors.New("nil attestation data request")
@@ -69,27 +68,17 @@ func (c *AttestationCache) Get(ctx context.Context, req *ethpb.AttestationDataRe
 		return nil, e
 	}
 
-	delay := minDelay
-
 	// Another identical request may be in progress already. Let's wait until
-	// any in progress request resolves or our timeout is exceeded.
-	for {
-		if ctx.Err() != nil {
+	// any in progress request resolves or our context is done.
+	c.lock.RLock()
+	done, inProgress := c.inProgress[s]
+	c.lock.RUnlock()
+	if inProgress {
+		select {
+		case <-done:
+		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
-
-		c.lock.RLock()
-		if !c.inProgress[s] {
-			c.lock.RUnlock()
-			break
-		}
-		c.lock.RUnlock()
-
-		// This increasing backoff is to decrease the CPU cycles while waiting
-		// for the in progress boolean to flip to false.
-		time.Sleep(time.Duration(delay) * time.Nanosecond)
-		delay *= delayFactor
-		delay = math.Min(delay, maxDelay)
 	}
 
 	item, exists, err := c.cache.GetByKey(s)
@@ -97,7 +86,8 @@ func (c *AttestationCache) Get(ctx context.Context, req *ethpb.AttestationDataRe
 		return nil, err
 	}
 
-	if exists && item != nil && item.(*attestationReqResWrapper).res != nil {
+	if exists && item != nil && item.(*attestationReqResWrapper).res != nil &&
+		time.Since(item.(*attestationReqResWrapper).created) < c.ttl {
 		attestationCacheHit.Inc()
 		if featureconfig.Get().ReduceAttesterStateCopy {
 			return state.CopyAttestationData(item.(*attestationReqResWrapper).res), nil
@@ -117,10 +107,10 @@ func (c *AttestationCache) MarkInProgress(req *ethpb.AttestationDataRequest) err
 	if e != nil {
 		return e
 	}
-	if c.inProgress[s] {
+	if _, ok := c.inProgress[s]; ok {
 		return ErrAlreadyInProgress
 	}
-	c.inProgress[s] = true
+	c.inProgress[s] = make(chan struct{})
 	return nil
 }
 
@@ -133,17 +123,21 @@ func (c *AttestationCache) MarkNotInProgress(req *ethpb.AttestationDataRequest)
 	if e != nil {
 		return e
 	}
-	delete(c.inProgress, s)
+	if done, ok := c.inProgress[s]; ok {
+		close(done)
+		delete(c.inProgress, s)
+	}
 	return nil
 }
 
-// Put the response in the cache.
+// Put the response in the cache, replacing any expired response of the request.
 func (c *AttestationCache) Put(ctx context.Context, req *ethpb.AttestationDataRequest, res *ethpb.AttestationData) error {
 	data := &attestationReqResWrapper{
-		req,
-		res,
+		req:     req,
+		res:     res,
+		created: time.Now(),
 	}
-	if err := c.cache.AddIfNotPresent(data); err != nil {
+	if err := c.cache.Add(data); err != nil {
 		return err
 	}
 	trim(c.cache, maxCacheSize)
@@ -174,6 +168,7 @@ func reqToKey(req *ethpb.AttestationDataRequest) (string, error) {
 }
 
 type attestationReqResWrapper struct {
-	req *ethpb.AttestationDataRequest
-	res *ethpb.AttestationData
+	req     *ethpb.AttestationDataRequest
+	res     *ethpb.AttestationData
+	created time.Time
 }
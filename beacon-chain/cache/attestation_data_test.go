@@ -3,6 +3,7 @@ package cache_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -52,3 +53,39 @@ func TestAttestationCache_RoundTrip(t *testing.T) {
 		t.Error("Expected equal protos to return from cache")
 	}
 }
+
+func TestAttestationCache_GetWaitsForInProgress(t *testing.T) {
+	c := cache.NewAttestationCache()
+	req := &ethpb.AttestationDataRequest{Slot: 1}
+	res := &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 5}}
+
+	if err := c.MarkInProgress(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MarkInProgress(req); err != cache.ErrAlreadyInProgress {
+		t.Errorf("Expected %v, got %v", cache.ErrAlreadyInProgress, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, req); err != context.DeadlineExceeded {
+		t.Errorf("Expected the wait to end with the context, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		if err := c.Put(context.Background(), req, res); err != nil {
+			t.Error(err)
+		}
+		if err := c.MarkNotInProgress(req); err != nil {
+			t.Error(err)
+		}
+	}()
+	response, err := c.Get(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(response, res) {
+		t.Error("Expected the in progress result to be returned once resolved")
+	}
+}
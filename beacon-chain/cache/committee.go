@@ -4,11 +4,9 @@ import (
 	"errors"
 	"sync"
 
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/shared/cachemanager"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
-	"k8s.io/client-go/tools/cache"
 )
 
 var (
@@ -20,17 +18,6 @@ var (
 	// Due to reorgs, it's good to keep the old cache around for quickly switch over. 10 is a generous
 	// cache size as it considers 3 concurrent branches over 3 epochs.
 	maxCommitteesCacheSize = 10
-
-	// CommitteeCacheMiss tracks the number of committee requests that aren't present in the cache.
-	CommitteeCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
-		Name: "committee_cache_miss",
-		Help: "The number of committee requests that aren't present in the cache.",
-	})
-	// CommitteeCacheHit tracks the number of committee requests that are in the cache.
-	CommitteeCacheHit = promauto.NewCounter(prometheus.CounterOpts{
-		Name: "committee_cache_hit",
-		Help: "The number of committee requests that are present in the cache.",
-	})
 )
 
 // Committees defines the shuffled committees seed.
@@ -43,25 +30,23 @@ type Committees struct {
 }
 
 // CommitteeCache is a struct with 1 queue for looking up shuffled indices list by seed.
+// It is shared by everything reading committees, like the attestation gossip validation and
+// the duties of the RPC server, so the validators of a seed are only shuffled once.
 type CommitteeCache struct {
-	CommitteeCache *cache.FIFO
+	CommitteeCache *cachemanager.Cache
 	lock           sync.RWMutex
-}
-
-// committeeKeyFn takes the seed as the key to retrieve shuffled indices of a committee in a given epoch.
-func committeeKeyFn(obj interface{}) (string, error) {
-	info, ok := obj.(*Committees)
-	if !ok {
-		return "", ErrNotCommittee
-	}
-
-	return key(info.Seed), nil
+	inProgress     map[string]chan struct{}
+	inProgressLock sync.Mutex
 }
 
 // NewCommitteesCache creates a new committee cache for storing/accessing shuffled indices of a committee.
 func NewCommitteesCache() *CommitteeCache {
 	return &CommitteeCache{
-		CommitteeCache: cache.NewFIFO(committeeKeyFn),
+		CommitteeCache: cachemanager.NewCache(cachemanager.Config{
+			Name:       "committee",
+			MaxEntries: maxCommitteesCacheSize,
+		}),
+		inProgress: make(map[string]chan struct{}),
 	}
 }
 
@@ -71,23 +56,11 @@ func (c *CommitteeCache) Committee(slot uint64, seed [32]byte, index uint64) ([]
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
-	obj, exists, err := c.CommitteeCache.GetByKey(key(seed))
-	if err != nil {
+	item, err := c.committees(seed)
+	if err != nil || item == nil || item.ShuffledIndices == nil {
 		return nil, err
 	}
 
-	if exists {
-		CommitteeCacheHit.Inc()
-	} else {
-		CommitteeCacheMiss.Inc()
-		return nil, nil
-	}
-
-	item, ok := obj.(*Committees)
-	if !ok {
-		return nil, ErrNotCommittee
-	}
-
 	committeeCountPerSlot := uint64(1)
 	if item.CommitteeCount/params.BeaconConfig().SlotsPerEpoch > 1 {
 		committeeCountPerSlot = item.CommitteeCount / params.BeaconConfig().SlotsPerEpoch
@@ -109,10 +82,20 @@ func (c *CommitteeCache) AddCommitteeShuffledList(committees *Committees) error
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	if err := c.CommitteeCache.AddIfNotPresent(committees); err != nil {
+	existing, err := c.committees(committees.Seed)
+	if err != nil {
 		return err
 	}
-	trim(c.CommitteeCache, maxCommitteesCacheSize)
+	if existing != nil {
+		if existing.ShuffledIndices != nil {
+			return nil
+		}
+		// Keep the proposer indices of the seed, which may be cached before its committees.
+		if committees.ProposerIndices == nil {
+			committees.ProposerIndices = existing.ProposerIndices
+		}
+	}
+	c.CommitteeCache.Set(key(committees.Seed), committees, committeesCost(committees))
 	return nil
 }
 
@@ -121,27 +104,17 @@ func (c *CommitteeCache) AddProposerIndicesList(seed [32]byte, indices []uint64)
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	obj, exists, err := c.CommitteeCache.GetByKey(key(seed))
-	if err != nil {
-		return err
-	}
-	if !exists {
-		committees := &Committees{ProposerIndices: indices}
-		if err := c.CommitteeCache.Add(committees); err != nil {
-			return err
-		}
-	} else {
-		committees, ok := obj.(*Committees)
+	obj, exists := c.CommitteeCache.Peek(key(seed))
+	committees := &Committees{ProposerIndices: indices}
+	if exists {
+		var ok bool
+		committees, ok = obj.(*Committees)
 		if !ok {
 			return ErrNotCommittee
 		}
 		committees.ProposerIndices = indices
-		if err := c.CommitteeCache.Add(committees); err != nil {
-			return err
-		}
 	}
-
-	trim(c.CommitteeCache, maxCommitteesCacheSize)
+	c.CommitteeCache.Set(key(seed), committees, committeesCost(committees))
 	return nil
 }
 
@@ -149,23 +122,11 @@ func (c *CommitteeCache) AddProposerIndicesList(seed [32]byte, indices []uint64)
 func (c *CommitteeCache) ActiveIndices(seed [32]byte) ([]uint64, error) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	obj, exists, err := c.CommitteeCache.GetByKey(key(seed))
-	if err != nil {
-		return nil, err
-	}
 
-	if exists {
-		CommitteeCacheHit.Inc()
-	} else {
-		CommitteeCacheMiss.Inc()
-		return nil, nil
-	}
-
-	item, ok := obj.(*Committees)
-	if !ok {
-		return nil, ErrNotCommittee
+	item, err := c.committees(seed)
+	if err != nil || item == nil {
+		return nil, err
 	}
-
 	return item.SortedIndices, nil
 }
 
@@ -173,24 +134,70 @@ func (c *CommitteeCache) ActiveIndices(seed [32]byte) ([]uint64, error) {
 func (c *CommitteeCache) ProposerIndices(seed [32]byte) ([]uint64, error) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	obj, exists, err := c.CommitteeCache.GetByKey(key(seed))
-	if err != nil {
+
+	item, err := c.committees(seed)
+	if err != nil || item == nil {
 		return nil, err
 	}
+	return item.ProposerIndices, nil
+}
 
-	if exists {
-		CommitteeCacheHit.Inc()
-	} else {
-		CommitteeCacheMiss.Inc()
-		return nil, nil
+// HasCommittees returns true if the committees of the seed are in the cache.
+func (c *CommitteeCache) HasCommittees(seed [32]byte) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	obj, exists := c.CommitteeCache.Peek(key(seed))
+	if !exists {
+		return false
+	}
+	item, ok := obj.(*Committees)
+	return ok && item.ShuffledIndices != nil
+}
+
+// MarkInProgress marks the committees of the seed as being computed by the caller, which has to
+// call MarkNotInProgress once done. If another caller is computing them already, it returns true
+// along with a channel which is closed once that caller is done, so the validators are not
+// shuffled concurrently for the same seed.
+func (c *CommitteeCache) MarkInProgress(seed [32]byte) (<-chan struct{}, bool) {
+	c.inProgressLock.Lock()
+	defer c.inProgressLock.Unlock()
+
+	if done, ok := c.inProgress[key(seed)]; ok {
+		return done, true
+	}
+	c.inProgress[key(seed)] = make(chan struct{})
+	return nil, false
+}
+
+// MarkNotInProgress marks the computation of the committees of the seed as done, releasing the
+// callers waiting for it.
+func (c *CommitteeCache) MarkNotInProgress(seed [32]byte) {
+	c.inProgressLock.Lock()
+	defer c.inProgressLock.Unlock()
+
+	if done, ok := c.inProgress[key(seed)]; ok {
+		close(done)
+		delete(c.inProgress, key(seed))
 	}
+}
 
+// committees returns the committees of the seed, or nil if they are not in the cache.
+func (c *CommitteeCache) committees(seed [32]byte) (*Committees, error) {
+	obj, exists := c.CommitteeCache.Get(key(seed))
+	if !exists {
+		return nil, nil
+	}
 	item, ok := obj.(*Committees)
 	if !ok {
 		return nil, ErrNotCommittee
 	}
+	return item, nil
+}
 
-	return item.ProposerIndices, nil
+// committeesCost estimates the size in bytes of the committees of a seed.
+func committeesCost(c *Committees) int64 {
+	return int64(8*(len(c.ShuffledIndices)+len(c.SortedIndices)+len(c.ProposerIndices)) + 64)
 }
 
 func startEndIndices(c *Committees, index uint64) (uint64, uint64) {
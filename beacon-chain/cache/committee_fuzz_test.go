@@ -7,22 +7,6 @@ import (
 	fuzz "github.com/google/gofuzz"
 )
 
-func TestCommitteeKeyFuzz_OK(t *testing.T) {
-	fuzzer := fuzz.NewWithSeed(0)
-	c := &Committees{}
-
-	for i := 0; i < 100000; i++ {
-		fuzzer.Fuzz(c)
-		k, err := committeeKeyFn(c)
-		if err != nil {
-			t.Fatal(err)
-		}
-		if k != key(c.Seed) {
-			t.Errorf("Incorrect hash k: %s, expected %s", k, key(c.Seed))
-		}
-	}
-}
-
 func TestCommitteeCache_FuzzCommitteesByEpoch(t *testing.T) {
 	cache := NewCommitteesCache()
 	fuzzer := fuzz.NewWithSeed(0)
@@ -38,7 +22,7 @@ func TestCommitteeCache_FuzzCommitteesByEpoch(t *testing.T) {
 		}
 	}
 
-	if len(cache.CommitteeCache.ListKeys()) != maxCommitteesCacheSize {
+	if cache.CommitteeCache.Len() != maxCommitteesCacheSize {
 		t.Error("Incorrect key size")
 	}
 }
@@ -62,7 +46,7 @@ func TestCommitteeCache_FuzzActiveIndices(t *testing.T) {
 		}
 	}
 
-	if len(cache.CommitteeCache.ListKeys()) != maxCommitteesCacheSize {
+	if cache.CommitteeCache.Len() != maxCommitteesCacheSize {
 		t.Error("Incorrect key size")
 	}
 }
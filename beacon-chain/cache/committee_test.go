@@ -11,29 +11,6 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
-func TestCommitteeKeyFn_OK(t *testing.T) {
-	item := &Committees{
-		CommitteeCount:  1,
-		Seed:            [32]byte{'A'},
-		ShuffledIndices: []uint64{1, 2, 3, 4, 5},
-	}
-
-	k, err := committeeKeyFn(item)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if k != key(item.Seed) {
-		t.Errorf("Incorrect hash k: %s, expected %s", k, key(item.Seed))
-	}
-}
-
-func TestCommitteeKeyFn_InvalidObj(t *testing.T) {
-	_, err := committeeKeyFn("bad")
-	if err != ErrNotCommittee {
-		t.Errorf("Expected error %v, got %v", ErrNotCommittee, err)
-	}
-}
-
 func TestCommitteeCache_CommitteesByEpoch(t *testing.T) {
 	cache := NewCommitteesCache()
 
@@ -156,7 +133,10 @@ func TestCommitteeCache_CanRotate(t *testing.T) {
 		}
 	}
 
-	k := cache.CommitteeCache.ListKeys()
+	var k []string
+	for _, obj := range cache.CommitteeCache.Keys() {
+		k = append(k, obj.(string))
+	}
 	if len(k) != maxCommitteesCacheSize {
 		t.Errorf("wanted: %d, got: %d", maxCommitteesCacheSize, len(k))
 	}
@@ -177,7 +157,7 @@ func TestCommitteeCache_CanRotate(t *testing.T) {
 func TestCommitteeCacheOutOfRange(t *testing.T) {
 	cache := NewCommitteesCache()
 	seed := bytesutil.ToBytes32([]byte("foo"))
-	err := cache.CommitteeCache.Add(&Committees{
+	err := cache.AddCommitteeShuffledList(&Committees{
 		CommitteeCount:  1,
 		Seed:            seed,
 		ShuffledIndices: []uint64{0},
@@ -192,3 +172,69 @@ func TestCommitteeCacheOutOfRange(t *testing.T) {
 		t.Fatal("Did not fail as expected")
 	}
 }
+
+func TestCommitteeCache_AddCommitteeShuffledListKeepsProposerIndices(t *testing.T) {
+	cache := NewCommitteesCache()
+
+	seed := [32]byte{'A'}
+	proposerIndices := []uint64{3, 1}
+	if err := cache.AddProposerIndicesList(seed, proposerIndices); err != nil {
+		t.Fatal(err)
+	}
+	if cache.HasCommittees(seed) {
+		t.Error("Expected no committees for a seed with only proposer indices")
+	}
+	indices, err := cache.Committee(1, seed, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indices != nil {
+		t.Errorf("Expected no committee for a seed with only proposer indices, received %v", indices)
+	}
+
+	item := &Committees{
+		Seed:            seed,
+		ShuffledIndices: []uint64{1, 2, 3, 4},
+		SortedIndices:   []uint64{1, 2, 3, 4},
+		CommitteeCount:  params.BeaconConfig().SlotsPerEpoch,
+	}
+	if err := cache.AddCommitteeShuffledList(item); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.HasCommittees(seed) {
+		t.Error("Expected committees to be cached")
+	}
+	received, err := cache.ProposerIndices(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(received, proposerIndices) {
+		t.Errorf("Wanted proposer indices %v, received %v", proposerIndices, received)
+	}
+}
+
+func TestCommitteeCache_InProgress(t *testing.T) {
+	cache := NewCommitteesCache()
+
+	seed := [32]byte{'A'}
+	if _, inProgress := cache.MarkInProgress(seed); inProgress {
+		t.Fatal("Expected seed not to be in progress")
+	}
+	done, inProgress := cache.MarkInProgress(seed)
+	if !inProgress {
+		t.Fatal("Expected seed to be in progress")
+	}
+	if _, inProgress := cache.MarkInProgress([32]byte{'B'}); inProgress {
+		t.Error("Expected other seed not to be in progress")
+	}
+
+	cache.MarkNotInProgress(seed)
+	select {
+	case <-done:
+	default:
+		t.Error("Expected waiters to be released")
+	}
+	if _, inProgress := cache.MarkInProgress(seed); inProgress {
+		t.Error("Expected seed not to be in progress once done")
+	}
+}
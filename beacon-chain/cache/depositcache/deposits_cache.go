@@ -15,8 +15,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
 	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/trieutil"
 	log "github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
@@ -33,6 +36,7 @@ type DepositFetcher interface {
 	AllDeposits(ctx context.Context, beforeBlk *big.Int) []*ethpb.Deposit
 	DepositByPubkey(ctx context.Context, pubKey []byte) (*ethpb.Deposit, *big.Int)
 	DepositsNumberAndRootAtHeight(ctx context.Context, blockHeight *big.Int) (uint64, [32]byte)
+	DepositSnapshotAtHeight(ctx context.Context, blockHeight *big.Int) *DepositSnapshot
 }
 
 // DepositCache stores all in-memory deposit objects. This
@@ -41,9 +45,12 @@ type DepositCache struct {
 	// Beacon chain deposits in memory.
 	pendingDeposits    []*dbpb.DepositContainer
 	deposits           []*dbpb.DepositContainer
+	depositTrie        *trieutil.SparseMerkleTrie // Updated with every inserted deposit.
 	depositsLock       sync.RWMutex
 	chainStartDeposits []*ethpb.Deposit
 	chainStartPubkeys  map[string]bool
+	depositsVersion    uint64           // Incremented with every change of the deposits.
+	snapshot           *DepositSnapshot // Snapshot of the latest requested height, if still current.
 }
 
 // NewDepositCache instantiates a new deposit cache
@@ -76,6 +83,8 @@ func (dc *DepositCache) InsertDeposit(ctx context.Context, d *ethpb.Deposit, blo
 	heightIdx := sort.Search(len(dc.deposits), func(i int) bool { return dc.deposits[i].Index >= index })
 	newDeposits := append([]*dbpb.DepositContainer{{Deposit: d, Eth1BlockHeight: blockNum, DepositRoot: depositRoot[:], Index: index}}, dc.deposits[heightIdx:]...)
 	dc.deposits = append(dc.deposits[:heightIdx], newDeposits...)
+	dc.insertDepositLeaf(d, index)
+	dc.invalidateSnapshot()
 	historicalDepositsCount.Inc()
 }
 
@@ -88,9 +97,42 @@ func (dc *DepositCache) InsertDepositContainers(ctx context.Context, ctrs []*dbp
 
 	sort.SliceStable(ctrs, func(i int, j int) bool { return ctrs[i].Index < ctrs[j].Index })
 	dc.deposits = ctrs
+	dc.depositTrie = nil
+	for _, ctr := range ctrs {
+		dc.insertDepositLeaf(ctr.Deposit, ctr.Index)
+	}
+	dc.invalidateSnapshot()
 	historicalDepositsCount.Add(float64(len(ctrs)))
 }
 
+// invalidateSnapshot drops the kept deposit snapshot after a change of the deposits. The caller
+// must hold the write lock.
+func (dc *DepositCache) invalidateSnapshot() {
+	dc.depositsVersion++
+	dc.snapshot = nil
+}
+
+// insertDepositLeaf inserts the hash of the deposit data into the deposit trie at the index.
+func (dc *DepositCache) insertDepositLeaf(d *ethpb.Deposit, index int64) {
+	if d == nil || d.Data == nil || index < 0 {
+		return
+	}
+	leaf, err := ssz.HashTreeRoot(d.Data)
+	if err != nil {
+		log.WithError(err).WithField("index", index).Error("Could not hash deposit data")
+		return
+	}
+	if dc.depositTrie == nil {
+		trie, err := trieutil.NewTrie(int(params.BeaconConfig().DepositContractTreeDepth))
+		if err != nil {
+			log.WithError(err).Error("Could not create deposit trie")
+			return
+		}
+		dc.depositTrie = trie
+	}
+	dc.depositTrie.Insert(leaf[:], int(index))
+}
+
 // AllDepositContainers returns a list of deposits all historical deposit containers until the given block number.
 func (dc *DepositCache) AllDepositContainers(ctx context.Context) []*dbpb.DepositContainer {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.AllDepositContainers")
@@ -152,6 +194,19 @@ func (dc *DepositCache) DepositsNumberAndRootAtHeight(ctx context.Context, block
 	return uint64(heightIdx), bytesutil.ToBytes32(dc.deposits[heightIdx-1].DepositRoot)
 }
 
+// DepositsWithProofs returns the deposits at the given indices with their Merkle proofs against
+// the deposit trie of the first depositCount deposits, which must have the given root. The proofs
+// are read from the incrementally updated deposit trie rather than a trie regenerated from all
+// deposits.
+func (dc *DepositCache) DepositsWithProofs(ctx context.Context, indices []uint64, depositCount uint64, depositRoot [32]byte) ([]*ethpb.Deposit, error) {
+	ctx, span := trace.StartSpan(ctx, "DepositsCache.DepositsWithProofs")
+	defer span.End()
+	dc.depositsLock.RLock()
+	defer dc.depositsLock.RUnlock()
+
+	return depositsWithProofs(dc.depositTrie, dc.deposits, indices, depositCount, depositRoot)
+}
+
 // DepositByPubkey looks through historical deposits and finds one which contains
 // a certain public key within its deposit data.
 func (dc *DepositCache) DepositByPubkey(ctx context.Context, pubKey []byte) (*ethpb.Deposit, *big.Int) {
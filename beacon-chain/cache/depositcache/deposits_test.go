@@ -7,8 +7,11 @@ import (
 	"testing"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
 	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/trieutil"
 	logTest "github.com/sirupsen/logrus/hooks/test"
 )
 
@@ -273,3 +276,138 @@ func TestBeaconDB_DepositByPubkey_ReturnsFirstMatchingDeposit(t *testing.T) {
 		t.Errorf("Returned wrong block number %v", blkNum)
 	}
 }
+
+func TestDepositsWithProofs(t *testing.T) {
+	ctx := context.Background()
+	dc := NewDepositCache()
+	depositTrie, err := trieutil.NewTrie(int(params.BeaconConfig().DepositContractTreeDepth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roots [][32]byte
+	for i := int64(0); i < 10; i++ {
+		deposit := &ethpb.Deposit{
+			Data: &ethpb.Deposit_Data{
+				PublicKey:             bytesutil.PadTo([]byte{byte(i)}, 48),
+				WithdrawalCredentials: make([]byte, 32),
+				Signature:             make([]byte, 96),
+			},
+		}
+		leaf, err := ssz.HashTreeRoot(deposit.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		depositTrie.Insert(leaf[:], int(i))
+		roots = append(roots, depositTrie.Root())
+		dc.InsertDeposit(ctx, deposit, uint64(i), i, depositTrie.Root())
+	}
+
+	// The proofs of the deposits are against the root of the first 6 deposits.
+	deposits, err := dc.DepositsWithProofs(ctx, []uint64{3, 4, 5}, 6, roots[5])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 3 {
+		t.Fatalf("Wanted 3 deposits, received %d", len(deposits))
+	}
+	for i, dep := range deposits {
+		leaf, err := ssz.HashTreeRoot(dep.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !trieutil.VerifyMerkleBranch(roots[5][:], leaf[:], 3+i, dep.Proof) {
+			t.Errorf("Proof of deposit %d did not verify", 3+i)
+		}
+	}
+
+	if _, err := dc.DepositsWithProofs(ctx, []uint64{3}, 6, roots[6]); err == nil {
+		t.Error("Expected an error for a root which does not match the deposit count")
+	}
+	if _, err := dc.DepositsWithProofs(ctx, []uint64{7}, 6, roots[5]); err == nil {
+		t.Error("Expected an error for a deposit outside of the deposit count")
+	}
+
+	// Deposit containers loaded at once make up the same deposit trie.
+	loaded := NewDepositCache()
+	loaded.InsertDepositContainers(ctx, dc.AllDepositContainers(ctx))
+	deposits, err = loaded.DepositsWithProofs(ctx, []uint64{9}, 10, roots[9])
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := ssz.HashTreeRoot(deposits[0].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trieutil.VerifyMerkleBranch(roots[9][:], leaf[:], 9, deposits[0].Proof) {
+		t.Error("Proof of deposit 9 did not verify")
+	}
+}
+
+func TestDepositSnapshotAtHeight_UnaffectedByInsertions(t *testing.T) {
+	ctx := context.Background()
+	dc := NewDepositCache()
+	depositTrie, err := trieutil.NewTrie(int(params.BeaconConfig().DepositContractTreeDepth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roots [][32]byte
+	insert := func(i int64) {
+		deposit := &ethpb.Deposit{
+			Data: &ethpb.Deposit_Data{
+				PublicKey:             bytesutil.PadTo([]byte{byte(i)}, 48),
+				WithdrawalCredentials: make([]byte, 32),
+				Signature:             make([]byte, 96),
+			},
+		}
+		leaf, err := ssz.HashTreeRoot(deposit.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		depositTrie.Insert(leaf[:], int(i))
+		roots = append(roots, depositTrie.Root())
+		dc.InsertDeposit(ctx, deposit, uint64(i/2), i, depositTrie.Root())
+	}
+	for i := int64(0); i < 6; i++ {
+		insert(i)
+	}
+
+	// Deposits 0 to 3 are made up to eth1 block 1.
+	snapshot := dc.DepositSnapshotAtHeight(ctx, big.NewInt(1))
+	if snapshot.DepositCount() != 4 || snapshot.DepositRoot() != roots[3] {
+		t.Fatalf("Unexpected snapshot of %d deposits with root %#x", snapshot.DepositCount(), snapshot.DepositRoot())
+	}
+	if dc.DepositSnapshotAtHeight(ctx, big.NewInt(1)) != snapshot {
+		t.Error("Expected the snapshot of the same height to be kept")
+	}
+
+	for i := int64(6); i < 10; i++ {
+		insert(i)
+	}
+	if snapshot.DepositCount() != 4 || len(snapshot.Deposits()) != 4 {
+		t.Errorf("Expected the snapshot to keep 4 deposits, got %d", snapshot.DepositCount())
+	}
+	deposits, err := snapshot.DepositsWithProofs([]uint64{2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, dep := range deposits {
+		leaf, err := ssz.HashTreeRoot(dep.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !trieutil.VerifyMerkleBranch(roots[3][:], leaf[:], 2+i, dep.Proof) {
+			t.Errorf("Proof of deposit %d did not verify", 2+i)
+		}
+	}
+	if _, err := snapshot.DepositsWithProofs([]uint64{4}); err == nil {
+		t.Error("Expected an error for a deposit outside of the snapshot")
+	}
+
+	latest := dc.DepositSnapshotAtHeight(ctx, big.NewInt(1))
+	if latest == snapshot {
+		t.Error("Expected a new snapshot after deposits were inserted")
+	}
+	if latest.DepositCount() != 4 || latest.DepositRoot() != roots[3] {
+		t.Errorf("Unexpected snapshot of %d deposits with root %#x", latest.DepositCount(), latest.DepositRoot())
+	}
+}
@@ -0,0 +1,131 @@
+package depositcache
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/trieutil"
+	"go.opencensus.io/trace"
+)
+
+// DepositSnapshot is an immutable view of the deposits up to an eth1 block. Eth1 data voting and
+// block production read the deposit count, root and proofs from the same snapshot, so they are
+// consistent with each other while deposits keep being inserted into the cache.
+type DepositSnapshot struct {
+	blockHeight uint64
+	deposits    []*dbpb.DepositContainer
+	depositTrie *trieutil.SparseMerkleTrie
+}
+
+// DepositSnapshotAtHeight returns a snapshot of the deposits made up to the eth1 block height
+// (inclusive). The snapshot of the latest requested height is kept until deposits are inserted,
+// so repeated requests for the same eth1 vote share it.
+func (dc *DepositCache) DepositSnapshotAtHeight(ctx context.Context, blockHeight *big.Int) *DepositSnapshot {
+	ctx, span := trace.StartSpan(ctx, "DepositsCache.DepositSnapshotAtHeight")
+	defer span.End()
+	height := blockHeight.Uint64()
+
+	dc.depositsLock.RLock()
+	if snapshot := dc.snapshot; snapshot != nil && snapshot.blockHeight == height {
+		dc.depositsLock.RUnlock()
+		return snapshot
+	}
+	heightIdx := sort.Search(len(dc.deposits), func(i int) bool { return dc.deposits[i].Eth1BlockHeight > height })
+	deposits := make([]*dbpb.DepositContainer, heightIdx)
+	copy(deposits, dc.deposits[:heightIdx])
+	var depositTrie *trieutil.SparseMerkleTrie
+	if dc.depositTrie != nil {
+		depositTrie = dc.depositTrie.Copy()
+	}
+	version := dc.depositsVersion
+	dc.depositsLock.RUnlock()
+
+	snapshot := &DepositSnapshot{
+		blockHeight: height,
+		deposits:    deposits,
+		depositTrie: depositTrie,
+	}
+	dc.depositsLock.Lock()
+	// Deposits inserted while the lock was released make the snapshot outdated for later requests.
+	if dc.depositsVersion == version {
+		dc.snapshot = snapshot
+	}
+	dc.depositsLock.Unlock()
+	return snapshot
+}
+
+// BlockHeight of the eth1 block the snapshot was taken at.
+func (s *DepositSnapshot) BlockHeight() uint64 {
+	return s.blockHeight
+}
+
+// DepositCount returns the number of deposits made up to the eth1 block of the snapshot.
+func (s *DepositSnapshot) DepositCount() uint64 {
+	return uint64(len(s.deposits))
+}
+
+// DepositRoot returns the deposit root of the latest deposit of the snapshot, or the zero root if
+// there are no deposits.
+func (s *DepositSnapshot) DepositRoot() [32]byte {
+	if len(s.deposits) == 0 {
+		return [32]byte{}
+	}
+	return bytesutil.ToBytes32(s.deposits[len(s.deposits)-1].DepositRoot)
+}
+
+// Deposits returns all deposits of the snapshot.
+func (s *DepositSnapshot) Deposits() []*ethpb.Deposit {
+	deposits := make([]*ethpb.Deposit, len(s.deposits))
+	for i, ctnr := range s.deposits {
+		deposits[i] = ctnr.Deposit
+	}
+	return deposits
+}
+
+// DepositsWithProofs returns the deposits at the given indices with their Merkle proofs against
+// the deposit root of the snapshot.
+func (s *DepositSnapshot) DepositsWithProofs(indices []uint64) ([]*ethpb.Deposit, error) {
+	if len(indices) == 0 {
+		return []*ethpb.Deposit{}, nil
+	}
+	return depositsWithProofs(s.depositTrie, s.deposits, indices, s.DepositCount(), s.DepositRoot())
+}
+
+// depositsWithProofs returns the deposits at the given indices with their Merkle proofs against the
+// deposit trie of the first depositCount deposits, which must have the given root.
+func depositsWithProofs(
+	depositTrie *trieutil.SparseMerkleTrie,
+	ctnrs []*dbpb.DepositContainer,
+	indices []uint64,
+	depositCount uint64,
+	depositRoot [32]byte,
+) ([]*ethpb.Deposit, error) {
+	if depositTrie == nil {
+		return nil, errors.New("no deposits in the deposit trie")
+	}
+	root, err := depositTrie.PrefixHashTreeRoot(depositCount)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute deposit root")
+	}
+	if root != depositRoot {
+		return nil, errors.Errorf("deposit root of %d deposits %#x does not match %#x", depositCount, root, depositRoot)
+	}
+	deposits := make([]*ethpb.Deposit, 0, len(indices))
+	for _, idx := range indices {
+		i := sort.Search(len(ctnrs), func(i int) bool { return ctnrs[i].Index >= int64(idx) })
+		if i == len(ctnrs) || ctnrs[i].Index != int64(idx) {
+			return nil, errors.Errorf("no deposit at index %d", idx)
+		}
+		proof, err := depositTrie.PrefixMerkleProof(int(idx), depositCount)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not generate merkle proof for deposit at index %d", idx)
+		}
+		deposits = append(deposits, &ethpb.Deposit{Data: ctnrs[i].Deposit.Data, Proof: proof})
+	}
+	return deposits, nil
+}
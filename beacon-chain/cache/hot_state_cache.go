@@ -1,39 +1,30 @@
 package cache
 
 import (
-	lru "github.com/hashicorp/golang-lru"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/cachemanager"
+	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
 var (
 	// hotStateCacheSize defines the max number of hot state this can cache.
 	hotStateCacheSize = 32
-	// Metrics
-	hotStateCacheHit = promauto.NewCounter(prometheus.CounterOpts{
-		Name: "hot_state_cache_hit",
-		Help: "The total number of cache hits on the hot state cache.",
-	})
-	hotStateCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
-		Name: "hot_state_cache_miss",
-		Help: "The total number of cache misses on the hot state cache.",
-	})
+	// validatorCost is the estimated size in bytes of a validator and its balance in a state.
+	validatorCost = int64(200)
 )
 
 // HotStateCache is used to store the processed beacon state after finalized check point..
 type HotStateCache struct {
-	cache *lru.Cache
+	cache *cachemanager.Cache
 }
 
 // NewHotStateCache initializes the map and underlying cache.
 func NewHotStateCache() *HotStateCache {
-	cache, err := lru.New(hotStateCacheSize)
-	if err != nil {
-		panic(err)
-	}
 	return &HotStateCache{
-		cache: cache,
+		cache: cachemanager.NewCache(cachemanager.Config{
+			Name:       "hot_state",
+			MaxEntries: hotStateCacheSize,
+		}),
 	}
 }
 
@@ -41,12 +32,9 @@ func NewHotStateCache() *HotStateCache {
 // The response is copied by default.
 func (c *HotStateCache) Get(root [32]byte) *stateTrie.BeaconState {
 	item, exists := c.cache.Get(root)
-
 	if exists && item != nil {
-		hotStateCacheHit.Inc()
 		return item.(*stateTrie.BeaconState).Copy()
 	}
-	hotStateCacheMiss.Inc()
 	return nil
 }
 
@@ -54,16 +42,14 @@ func (c *HotStateCache) Get(root [32]byte) *stateTrie.BeaconState {
 func (c *HotStateCache) GetWithoutCopy(root [32]byte) *stateTrie.BeaconState {
 	item, exists := c.cache.Get(root)
 	if exists && item != nil {
-		hotStateCacheHit.Inc()
 		return item.(*stateTrie.BeaconState)
 	}
-	hotStateCacheMiss.Inc()
 	return nil
 }
 
 // Put the response in the cache.
 func (c *HotStateCache) Put(root [32]byte, state *stateTrie.BeaconState) {
-	c.cache.Add(root, state)
+	c.cache.Set(root, state, stateCost(state))
 }
 
 // Has returns true if the key exists in the cache.
@@ -73,5 +59,13 @@ func (c *HotStateCache) Has(root [32]byte) bool {
 
 // Delete deletes the key exists in the cache.
 func (c *HotStateCache) Delete(root [32]byte) bool {
-	return c.cache.Remove(root)
+	return c.cache.Delete(root)
+}
+
+// stateCost estimates the size in bytes of a state from its historical vectors and the number
+// of its validators.
+func stateCost(state *stateTrie.BeaconState) int64 {
+	cfg := params.BeaconConfig()
+	roots := 2*cfg.SlotsPerHistoricalRoot + cfg.EpochsPerHistoricalVector
+	return int64(roots*32+cfg.EpochsPerSlashingsVector*8) + int64(state.NumValidators())*validatorCost
 }
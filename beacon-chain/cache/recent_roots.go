@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+var (
+	// recentRootsCacheSize defines the number of recent slots of which the roots are cached. It
+	// covers the attestation propagation range and the epoch boundary targets of two epochs.
+	recentRootsCacheSize = uint64(128)
+
+	// Metrics
+	recentRootsCacheHit = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "recent_roots_cache_hit",
+		Help: "The total number of cache hits on the recent roots cache.",
+	})
+	recentRootsCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "recent_roots_cache_miss",
+		Help: "The total number of cache misses on the recent roots cache.",
+	})
+)
+
+// RecentRootsCache keeps the block roots and state roots of the recent slots of the canonical
+// chain, as in the block_roots and state_roots vectors of the head state, so they can be looked
+// up without copying the vectors of a state or reading the DB. It is updated with every new head
+// state and only serves lookups for the chain of the latest head root.
+type RecentRootsCache struct {
+	lock       sync.RWMutex
+	headRoot   [32]byte
+	headSlot   uint64 // The roots are known for the slots before the head slot.
+	minSlot    uint64 // The lowest slot with known roots.
+	blockRoots [][32]byte
+	stateRoots [][32]byte
+}
+
+// NewRecentRootsCache initializes an empty recent roots cache.
+func NewRecentRootsCache() *RecentRootsCache {
+	return &RecentRootsCache{
+		blockRoots: make([][32]byte, recentRootsCacheSize),
+		stateRoots: make([][32]byte, recentRootsCacheSize),
+	}
+}
+
+// Update records the roots of the recent slots of a new head state with the given block root.
+// Only the slots after the previous head are read from the state, unless the state is not a
+// descendant of the previous head, in which case all recent roots are read again.
+func (c *RecentRootsCache) Update(headRoot [32]byte, headState *stateTrie.BeaconState) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	slot := headState.Slot()
+	historicalRoots := params.BeaconConfig().SlotsPerHistoricalRoot
+	if c.headSlot > c.minSlot && c.headSlot <= slot && c.headSlot+historicalRoots > slot {
+		// The previous head is an ancestor if the state has the same root at the latest known slot.
+		latest := c.headSlot - 1
+		r, err := headState.BlockRootAtIndex(latest % historicalRoots)
+		if err != nil {
+			return errors.Wrap(err, "could not get block root")
+		}
+		if bytesutil.ToBytes32(r) != c.blockRoots[latest%recentRootsCacheSize] {
+			c.clear()
+		}
+	} else {
+		c.clear()
+	}
+
+	// Only the roots of the last slots fit in the cache, and in the vectors of the state.
+	lowest := uint64(0)
+	if slot > recentRootsCacheSize {
+		lowest = slot - recentRootsCacheSize
+	}
+	if slot > historicalRoots && lowest < slot-historicalRoots {
+		lowest = slot - historicalRoots
+	}
+	start := c.headSlot
+	if start < lowest {
+		start = lowest
+	}
+	if c.minSlot < lowest {
+		c.minSlot = lowest
+	}
+	for s := start; s < slot; s++ {
+		blockRoot, err := headState.BlockRootAtIndex(s % historicalRoots)
+		if err != nil {
+			return errors.Wrap(err, "could not get block root")
+		}
+		stateRoot, err := headState.StateRootAtIndex(s % historicalRoots)
+		if err != nil {
+			return errors.Wrap(err, "could not get state root")
+		}
+		c.blockRoots[s%recentRootsCacheSize] = bytesutil.ToBytes32(blockRoot)
+		c.stateRoots[s%recentRootsCacheSize] = bytesutil.ToBytes32(stateRoot)
+	}
+	c.headRoot = headRoot
+	c.headSlot = slot
+	return nil
+}
+
+// BlockRootAtSlot returns the block root at a recent slot of the chain of the given head root,
+// and false if it is not cached.
+func (c *RecentRootsCache) BlockRootAtSlot(headRoot [32]byte, slot uint64) ([32]byte, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if !c.has(headRoot, slot) {
+		recentRootsCacheMiss.Inc()
+		return [32]byte{}, false
+	}
+	recentRootsCacheHit.Inc()
+	return c.blockRoots[slot%recentRootsCacheSize], true
+}
+
+// StateRootAtSlot returns the state root at a recent slot of the chain of the given head root,
+// and false if it is not cached.
+func (c *RecentRootsCache) StateRootAtSlot(headRoot [32]byte, slot uint64) ([32]byte, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if !c.has(headRoot, slot) {
+		recentRootsCacheMiss.Inc()
+		return [32]byte{}, false
+	}
+	recentRootsCacheHit.Inc()
+	return c.stateRoots[slot%recentRootsCacheSize], true
+}
+
+// HasBlockRoot returns true if the block root is the root of the latest block at or before the
+// slot on the chain of the given head root, that is the head root itself from the head slot on.
+// It returns false if the block root differs or the slot is not cached.
+func (c *RecentRootsCache) HasBlockRoot(headRoot [32]byte, blockRoot [32]byte, slot uint64) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.headSlot != 0 && headRoot == c.headRoot && slot >= c.headSlot {
+		recentRootsCacheHit.Inc()
+		return blockRoot == headRoot
+	}
+	if !c.has(headRoot, slot) {
+		recentRootsCacheMiss.Inc()
+		return false
+	}
+	recentRootsCacheHit.Inc()
+	return c.blockRoots[slot%recentRootsCacheSize] == blockRoot
+}
+
+// Clear removes all cached roots, e.g. after a reorg.
+func (c *RecentRootsCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.clear()
+}
+
+func (c *RecentRootsCache) clear() {
+	c.headRoot = [32]byte{}
+	c.headSlot = 0
+	c.minSlot = 0
+}
+
+func (c *RecentRootsCache) has(headRoot [32]byte, slot uint64) bool {
+	return c.headSlot != 0 && headRoot == c.headRoot && slot >= c.minSlot && slot < c.headSlot
+}
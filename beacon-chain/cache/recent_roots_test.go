@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"testing"
+
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// chainState returns a state at the slot with the block root fork and slot and the state
+// root fork, slot and 1 at each of its recent slots.
+func chainState(t *testing.T, slot uint64, fork byte) *stateTrie.BeaconState {
+	historicalRoots := params.BeaconConfig().SlotsPerHistoricalRoot
+	blockRoots := make([][]byte, historicalRoots)
+	stateRoots := make([][]byte, historicalRoots)
+	for i := range blockRoots {
+		blockRoots[i] = make([]byte, 32)
+		stateRoots[i] = make([]byte, 32)
+	}
+	for s := uint64(0); s < slot; s++ {
+		blockRoots[s%historicalRoots][0], blockRoots[s%historicalRoots][1] = fork, byte(s)
+		stateRoots[s%historicalRoots][0], stateRoots[s%historicalRoots][1] = fork, byte(s)
+		stateRoots[s%historicalRoots][2] = 1
+	}
+	st, err := stateTrie.InitializeFromProto(&pb.BeaconState{Slot: slot, BlockRoots: blockRoots, StateRoots: stateRoots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestRecentRootsCache_Update(t *testing.T) {
+	c := NewRecentRootsCache()
+	head := [32]byte{'a'}
+	if _, ok := c.BlockRootAtSlot(head, 1); ok {
+		t.Error("Wanted a miss on an empty cache")
+	}
+
+	if err := c.Update(head, chainState(t, 10, 'a')); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := c.BlockRootAtSlot(head, 3); !ok || r != [32]byte{'a', 3} {
+		t.Errorf("Wanted the block root of slot 3, received %#x %v", r, ok)
+	}
+	if r, ok := c.StateRootAtSlot(head, 3); !ok || r != [32]byte{'a', 3, 1} {
+		t.Errorf("Wanted the state root of slot 3, received %#x %v", r, ok)
+	}
+	if _, ok := c.BlockRootAtSlot(head, 10); ok {
+		t.Error("Wanted a miss on the head slot")
+	}
+	if _, ok := c.BlockRootAtSlot([32]byte{'b'}, 3); ok {
+		t.Error("Wanted a miss for another head root")
+	}
+
+	// A descendant of the head extends the cached roots.
+	head = [32]byte{'a', 1}
+	if err := c.Update(head, chainState(t, 12, 'a')); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := c.BlockRootAtSlot(head, 11); !ok || r != [32]byte{'a', 11} {
+		t.Errorf("Wanted the block root of slot 11, received %#x %v", r, ok)
+	}
+
+	// A state of another chain replaces the cached roots.
+	head = [32]byte{'b'}
+	if err := c.Update(head, chainState(t, 13, 'b')); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := c.BlockRootAtSlot(head, 3); !ok || r != [32]byte{'b', 3} {
+		t.Errorf("Wanted the block root of slot 3 of the new chain, received %#x %v", r, ok)
+	}
+
+	c.Clear()
+	if _, ok := c.BlockRootAtSlot(head, 3); ok {
+		t.Error("Wanted a miss after clearing the cache")
+	}
+}
+
+func TestRecentRootsCache_OnlyRecentSlots(t *testing.T) {
+	c := NewRecentRootsCache()
+	head := [32]byte{'a'}
+	slot := recentRootsCacheSize + 20
+	if err := c.Update(head, chainState(t, slot, 'a')); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.BlockRootAtSlot(head, 19); ok {
+		t.Error("Wanted a miss on a slot which does not fit in the cache")
+	}
+	if r, ok := c.BlockRootAtSlot(head, 20); !ok || r != [32]byte{'a', 20} {
+		t.Errorf("Wanted the block root of slot 20, received %#x %v", r, ok)
+	}
+
+	// Slots overwritten after a skip of more slots than fit in the cache are not served.
+	if err := c.Update(head, chainState(t, slot+recentRootsCacheSize+5, 'a')); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.BlockRootAtSlot(head, slot); ok {
+		t.Error("Wanted a miss on a slot which was overwritten")
+	}
+	if r, ok := c.BlockRootAtSlot(head, slot+5); !ok || r != [32]byte{'a', byte(slot + 5)} {
+		t.Errorf("Wanted the block root of slot %d, received %#x %v", slot+5, r, ok)
+	}
+}
+
+func TestRecentRootsCache_HasBlockRoot(t *testing.T) {
+	c := NewRecentRootsCache()
+	head := [32]byte{'a'}
+	if c.HasBlockRoot(head, head, 10) {
+		t.Error("Wanted a miss on an empty cache")
+	}
+	if err := c.Update(head, chainState(t, 10, 'a')); err != nil {
+		t.Fatal(err)
+	}
+	if !c.HasBlockRoot(head, [32]byte{'a', 3}, 3) {
+		t.Error("Wanted the block root of slot 3 to be found")
+	}
+	if c.HasBlockRoot(head, [32]byte{'a', 3}, 4) {
+		t.Error("Wanted the block root of slot 3 not to be found at slot 4")
+	}
+	if !c.HasBlockRoot(head, head, 12) || c.HasBlockRoot(head, [32]byte{'a', 3}, 12) {
+		t.Error("Wanted only the head root to be found after the head slot")
+	}
+	if c.HasBlockRoot([32]byte{'b'}, [32]byte{'a', 3}, 3) {
+		t.Error("Wanted a miss for another head root")
+	}
+}
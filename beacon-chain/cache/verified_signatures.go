@@ -0,0 +1,69 @@
+package cache
+
+import (
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+)
+
+var (
+	// verifiedSignatureCacheSize defines the number of verified signatures the cache contains.
+	// It covers the aggregates and blocks of a few epochs, the window in which an attestation
+	// seen on gossip is included in a block.
+	verifiedSignatureCacheSize = 1 << 15
+
+	// Metrics
+	verifiedSignatureCacheHit = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "verified_signature_cache_hit",
+		Help: "The total number of signatures which were not verified again as they are in the verified signature cache.",
+	})
+	verifiedSignatureCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "verified_signature_cache_miss",
+		Help: "The total number of signatures which are not in the verified signature cache.",
+	})
+)
+
+// VerifiedSignatureCache keeps the signatures known to be valid, so that a signature received
+// again, for example an attestation seen on gossip and then included in a block or a block seen
+// on gossip and then received by RPC, is not verified again. A signature is keyed by its signing
+// root and the public key it was verified against, the aggregate public key for an aggregate
+// signature, so an entry vouches for nothing more than the pairing check which added it.
+type VerifiedSignatureCache struct {
+	cache *lru.Cache
+}
+
+// NewVerifiedSignatureCache initializes an empty verified signature cache.
+func NewVerifiedSignatureCache() *VerifiedSignatureCache {
+	c, err := lru.New(verifiedSignatureCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &VerifiedSignatureCache{cache: c}
+}
+
+// Verified reports whether the signature of the signing root by the public key is valid
+// according to the cache.
+func (c *VerifiedSignatureCache) Verified(root [32]byte, signature []byte, pubKey []byte) bool {
+	if c.cache.Contains(verifiedSignatureKey(root, signature, pubKey)) {
+		verifiedSignatureCacheHit.Inc()
+		return true
+	}
+	verifiedSignatureCacheMiss.Inc()
+	return false
+}
+
+// MarkVerified records that the signature of the signing root by the public key is valid.
+func (c *VerifiedSignatureCache) MarkVerified(root [32]byte, signature []byte, pubKey []byte) {
+	c.cache.Add(verifiedSignatureKey(root, signature, pubKey), struct{}{})
+}
+
+func verifiedSignatureKey(root [32]byte, signature []byte, pubKey []byte) [32]byte {
+	b := make([]byte, 0, len(root)+8+len(signature)+len(pubKey))
+	b = append(b, root[:]...)
+	b = append(b, bytesutil.Bytes8(uint64(len(signature)))...)
+	b = append(b, signature...)
+	b = append(b, pubKey...)
+	return hashutil.Hash(b)
+}
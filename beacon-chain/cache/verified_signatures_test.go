@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+)
+
+func TestVerifiedSignatureCache(t *testing.T) {
+	c := NewVerifiedSignatureCache()
+	root := [32]byte{'a'}
+	sig := []byte{'s', 'i', 'g'}
+	pubKey := []byte{'p', 'u', 'b'}
+	if c.Verified(root, sig, pubKey) {
+		t.Error("Wanted a miss on an empty cache")
+	}
+	c.MarkVerified(root, sig, pubKey)
+	if !c.Verified(root, sig, pubKey) {
+		t.Error("Wanted a hit for the verified signature")
+	}
+
+	// The signature is only valid for the same signing root and public key.
+	if c.Verified([32]byte{'b'}, sig, pubKey) {
+		t.Error("Wanted a miss for another signing root")
+	}
+	if c.Verified(root, sig, []byte{'k', 'e', 'y'}) {
+		t.Error("Wanted a miss for another public key")
+	}
+	if c.Verified(root, []byte{'s', 'i'}, []byte{'g', 'p', 'u', 'b'}) {
+		t.Error("Wanted a miss for another signature")
+	}
+}
+
+func TestVerifiedSignatureCache_Evicts(t *testing.T) {
+	c := NewVerifiedSignatureCache()
+	for i := 0; i <= verifiedSignatureCacheSize; i++ {
+		c.MarkVerified([32]byte{byte(i), byte(i >> 8)}, nil, nil)
+	}
+	if c.Verified([32]byte{}, nil, nil) {
+		t.Error("Wanted the least recently verified signature to be evicted")
+	}
+	if !c.Verified([32]byte{byte(verifiedSignatureCacheSize), byte(verifiedSignatureCacheSize >> 8)}, nil, nil) {
+		t.Error("Wanted a hit for the last verified signature")
+	}
+}
@@ -387,13 +387,38 @@ func ProcessProposerSlashings(
 	ctx context.Context,
 	beaconState *stateTrie.BeaconState,
 	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processProposerSlashings(ctx, beaconState, body, VerifyProposerSlashing)
+}
+
+// ProcessProposerSlashingsNoVerifySignature processes the proposer slashings of a block body
+// without verifying the signatures of their headers.
+//
+// WARNING: The header signatures must be verified separately, as part of the signature set
+// of the block.
+func ProcessProposerSlashingsNoVerifySignature(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processProposerSlashings(ctx, beaconState, body, func(beaconState *stateTrie.BeaconState, slashing *ethpb.ProposerSlashing) error {
+		_, err := verifyProposerSlashingConditions(beaconState, slashing)
+		return err
+	})
+}
+
+func processProposerSlashings(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	verify func(*stateTrie.BeaconState, *ethpb.ProposerSlashing) error,
 ) (*stateTrie.BeaconState, error) {
 	var err error
 	for idx, slashing := range body.ProposerSlashings {
 		if slashing == nil {
 			return nil, errors.New("nil proposer slashings in block body")
 		}
-		if err = VerifyProposerSlashing(beaconState, slashing); err != nil {
+		if err = verify(beaconState, slashing); err != nil {
 			return nil, errors.Wrapf(err, "could not verify proposer slashing %d", idx)
 		}
 		beaconState, err = v.SlashValidator(
@@ -411,25 +436,10 @@ func VerifyProposerSlashing(
 	beaconState *stateTrie.BeaconState,
 	slashing *ethpb.ProposerSlashing,
 ) error {
-	if slashing.Header_1 == nil || slashing.Header_1.Header == nil || slashing.Header_2 == nil || slashing.Header_2.Header == nil {
-		return errors.New("nil header cannot be verified")
-	}
-	if slashing.Header_1.Header.Slot != slashing.Header_2.Header.Slot {
-		return fmt.Errorf("mismatched header slots, received %d == %d", slashing.Header_1.Header.Slot, slashing.Header_2.Header.Slot)
-	}
-	if slashing.Header_1.Header.ProposerIndex != slashing.Header_2.Header.ProposerIndex {
-		return fmt.Errorf("mismatched indices, received %d == %d", slashing.Header_1.Header.ProposerIndex, slashing.Header_2.Header.ProposerIndex)
-	}
-	if proto.Equal(slashing.Header_1, slashing.Header_2) {
-		return errors.New("expected slashing headers to differ")
-	}
-	proposer, err := beaconState.ValidatorAtIndexReadOnly(slashing.Header_1.Header.ProposerIndex)
+	proposer, err := verifyProposerSlashingConditions(beaconState, slashing)
 	if err != nil {
 		return err
 	}
-	if !helpers.IsSlashableValidatorUsingTrie(proposer, helpers.SlotToEpoch(beaconState.Slot())) {
-		return fmt.Errorf("validator with key %#x is not slashable", proposer.PublicKey())
-	}
 	// Using headerEpoch1 here because both of the headers should have the same epoch.
 	domain, err := helpers.Domain(beaconState.Fork(), helpers.SlotToEpoch(slashing.Header_1.Header.Slot), params.BeaconConfig().DomainBeaconProposer, beaconState.GenesisValidatorRoot())
 	if err != nil {
@@ -445,6 +455,34 @@ func VerifyProposerSlashing(
 	return nil
 }
 
+// verifyProposerSlashingConditions verifies a proposer slashing except for the signatures of
+// its headers, and returns the slashed proposer.
+func verifyProposerSlashingConditions(
+	beaconState *stateTrie.BeaconState,
+	slashing *ethpb.ProposerSlashing,
+) (*stateTrie.ReadOnlyValidator, error) {
+	if slashing.Header_1 == nil || slashing.Header_1.Header == nil || slashing.Header_2 == nil || slashing.Header_2.Header == nil {
+		return nil, errors.New("nil header cannot be verified")
+	}
+	if slashing.Header_1.Header.Slot != slashing.Header_2.Header.Slot {
+		return nil, fmt.Errorf("mismatched header slots, received %d == %d", slashing.Header_1.Header.Slot, slashing.Header_2.Header.Slot)
+	}
+	if slashing.Header_1.Header.ProposerIndex != slashing.Header_2.Header.ProposerIndex {
+		return nil, fmt.Errorf("mismatched indices, received %d == %d", slashing.Header_1.Header.ProposerIndex, slashing.Header_2.Header.ProposerIndex)
+	}
+	if proto.Equal(slashing.Header_1, slashing.Header_2) {
+		return nil, errors.New("expected slashing headers to differ")
+	}
+	proposer, err := beaconState.ValidatorAtIndexReadOnly(slashing.Header_1.Header.ProposerIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !helpers.IsSlashableValidatorUsingTrie(proposer, helpers.SlotToEpoch(beaconState.Slot())) {
+		return nil, fmt.Errorf("validator with key %#x is not slashable", proposer.PublicKey())
+	}
+	return proposer, nil
+}
+
 // ProcessAttesterSlashings is one of the operations performed
 // on each processed beacon block to slash attesters based on
 // Casper FFG slashing conditions if any slashable events occurred.
@@ -468,9 +506,33 @@ func ProcessAttesterSlashings(
 	ctx context.Context,
 	beaconState *stateTrie.BeaconState,
 	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processAttesterSlashings(ctx, beaconState, body, VerifyAttesterSlashing)
+}
+
+// ProcessAttesterSlashingsNoVerifySignature processes the attester slashings of a block body
+// without verifying the signatures of their attestations.
+//
+// WARNING: The attestation signatures must be verified separately, as part of the signature
+// set of the block.
+func ProcessAttesterSlashingsNoVerifySignature(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processAttesterSlashings(ctx, beaconState, body, func(ctx context.Context, _ *stateTrie.BeaconState, slashing *ethpb.AttesterSlashing) error {
+		return verifyAttesterSlashingConditions(ctx, slashing)
+	})
+}
+
+func processAttesterSlashings(
+	ctx context.Context,
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	verify func(context.Context, *stateTrie.BeaconState, *ethpb.AttesterSlashing) error,
 ) (*stateTrie.BeaconState, error) {
 	for idx, slashing := range body.AttesterSlashings {
-		if err := VerifyAttesterSlashing(ctx, beaconState, slashing); err != nil {
+		if err := verify(ctx, beaconState, slashing); err != nil {
 			return nil, errors.Wrapf(err, "could not verify attester slashing %d", idx)
 		}
 		slashableIndices := slashableAttesterIndices(slashing)
@@ -504,6 +566,34 @@ func ProcessAttesterSlashings(
 
 // VerifyAttesterSlashing validates the attestation data in both attestations in the slashing object.
 func VerifyAttesterSlashing(ctx context.Context, beaconState *stateTrie.BeaconState, slashing *ethpb.AttesterSlashing) error {
+	if err := verifyAttesterSlashingData(slashing); err != nil {
+		return err
+	}
+	if err := VerifyIndexedAttestation(ctx, beaconState, slashing.Attestation_1); err != nil {
+		return errors.Wrap(err, "could not validate indexed attestation")
+	}
+	if err := VerifyIndexedAttestation(ctx, beaconState, slashing.Attestation_2); err != nil {
+		return errors.Wrap(err, "could not validate indexed attestation")
+	}
+	return nil
+}
+
+// verifyAttesterSlashingConditions verifies an attester slashing except for the signatures of
+// its attestations.
+func verifyAttesterSlashingConditions(ctx context.Context, slashing *ethpb.AttesterSlashing) error {
+	if err := verifyAttesterSlashingData(slashing); err != nil {
+		return err
+	}
+	if err := attestationutil.IsValidAttestationIndices(ctx, slashing.Attestation_1); err != nil {
+		return errors.Wrap(err, "could not validate indexed attestation")
+	}
+	if err := attestationutil.IsValidAttestationIndices(ctx, slashing.Attestation_2); err != nil {
+		return errors.Wrap(err, "could not validate indexed attestation")
+	}
+	return nil
+}
+
+func verifyAttesterSlashingData(slashing *ethpb.AttesterSlashing) error {
 	if slashing == nil {
 		return errors.New("nil slashing")
 	}
@@ -513,19 +603,9 @@ func VerifyAttesterSlashing(ctx context.Context, beaconState *stateTrie.BeaconSt
 	if slashing.Attestation_1.Data == nil || slashing.Attestation_2.Data == nil {
 		return errors.New("nil attestation data")
 	}
-	att1 := slashing.Attestation_1
-	att2 := slashing.Attestation_2
-	data1 := att1.Data
-	data2 := att2.Data
-	if !IsSlashableAttestationData(data1, data2) {
+	if !IsSlashableAttestationData(slashing.Attestation_1.Data, slashing.Attestation_2.Data) {
 		return errors.New("attestations are not slashable")
 	}
-	if err := VerifyIndexedAttestation(ctx, beaconState, att1); err != nil {
-		return errors.Wrap(err, "could not validate indexed attestation")
-	}
-	if err := VerifyIndexedAttestation(ctx, beaconState, att2); err != nil {
-		return errors.Wrap(err, "could not validate indexed attestation")
-	}
 	return nil
 }
 
@@ -782,8 +862,32 @@ func VerifyIndexedAttestation(ctx context.Context, beaconState *stateTrie.Beacon
 			pubkeys = append(pubkeys, pk)
 		}
 	}
-	return attestationutil.VerifyIndexedAttestationSig(ctx, indexedAtt, pubkeys, domain)
+	if len(pubkeys) == 0 {
+		return attestationutil.VerifyIndexedAttestationSig(ctx, indexedAtt, pubkeys, domain)
+	}
 
+	// The same attestation is usually verified on gossip, by fork choice and in a block, so the
+	// signature is verified once against the aggregate public key of its attesters.
+	root, err := helpers.ComputeSigningRoot(indexedAtt.Data, domain)
+	if err != nil {
+		return errors.Wrap(err, "could not get signing root of object")
+	}
+	aggPubKey, err := pubkeys[0].Copy()
+	if err != nil {
+		return errors.Wrap(err, "could not copy public key")
+	}
+	for _, pk := range pubkeys[1:] {
+		aggPubKey = aggPubKey.Aggregate(pk)
+	}
+	aggPubKeyBytes := aggPubKey.Marshal()
+	if helpers.SignatureVerified(root, indexedAtt.Signature, aggPubKeyBytes) {
+		return nil
+	}
+	if err := attestationutil.VerifyIndexedAttestationSig(ctx, indexedAtt, pubkeys, domain); err != nil {
+		return err
+	}
+	helpers.MarkSignatureVerified(root, indexedAtt.Signature, aggPubKeyBytes)
+	return nil
 }
 
 // VerifyAttestation converts and attestation into an indexed attestation and verifies
@@ -1016,6 +1120,28 @@ func ProcessVoluntaryExits(
 	ctx context.Context,
 	beaconState *stateTrie.BeaconState,
 	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processVoluntaryExits(beaconState, body, VerifyExit)
+}
+
+// ProcessVoluntaryExitsNoVerifySignature processes the voluntary exits of a block body with
+// all the checks of ProcessVoluntaryExits but the verification of their signatures.
+//
+// WARNING: The exit signatures must be verified separately, as part of the signature set of
+// the block.
+func ProcessVoluntaryExitsNoVerifySignature(
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+) (*stateTrie.BeaconState, error) {
+	return processVoluntaryExits(beaconState, body, func(validator *stateTrie.ReadOnlyValidator, currentSlot uint64, _ *pb.Fork, signed *ethpb.SignedVoluntaryExit, _ []byte) error {
+		return verifyExitConditions(validator, currentSlot, signed)
+	})
+}
+
+func processVoluntaryExits(
+	beaconState *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody,
+	verify func(*stateTrie.ReadOnlyValidator, uint64, *pb.Fork, *ethpb.SignedVoluntaryExit, []byte) error,
 ) (*stateTrie.BeaconState, error) {
 	exits := body.VoluntaryExits
 	for idx, exit := range exits {
@@ -1033,7 +1159,7 @@ func ProcessVoluntaryExits(
 		if err != nil {
 			return nil, err
 		}
-		if err := VerifyExit(val, beaconState.Slot(), beaconState.Fork(), exit, beaconState.GenesisValidatorRoot()); err != nil {
+		if err := verify(val, beaconState.Slot(), beaconState.Fork(), exit, beaconState.GenesisValidatorRoot()); err != nil {
 			return nil, errors.Wrapf(err, "could not verify exit %d", idx)
 		}
 		beaconState, err = v.InitiateValidatorExit(beaconState, exit.Exit.ValidatorIndex)
@@ -1085,6 +1211,22 @@ func ProcessVoluntaryExitsNoVerify(
 //    domain = get_domain(state, DOMAIN_VOLUNTARY_EXIT, exit.epoch)
 //    assert bls_verify(validator.pubkey, signing_root(exit), exit.signature, domain)
 func VerifyExit(validator *stateTrie.ReadOnlyValidator, currentSlot uint64, fork *pb.Fork, signed *ethpb.SignedVoluntaryExit, genesisRoot []byte) error {
+	if err := verifyExitConditions(validator, currentSlot, signed); err != nil {
+		return err
+	}
+	domain, err := helpers.Domain(fork, signed.Exit.Epoch, params.BeaconConfig().DomainVoluntaryExit, genesisRoot)
+	if err != nil {
+		return err
+	}
+	valPubKey := validator.PublicKey()
+	if err := helpers.VerifySigningRoot(signed.Exit, valPubKey[:], signed.Signature, domain); err != nil {
+		return helpers.ErrSigFailedToVerify
+	}
+	return nil
+}
+
+// verifyExitConditions verifies a voluntary exit except for its signature.
+func verifyExitConditions(validator *stateTrie.ReadOnlyValidator, currentSlot uint64, signed *ethpb.SignedVoluntaryExit) error {
 	if signed == nil || signed.Exit == nil {
 		return errors.New("nil exit")
 	}
@@ -1111,13 +1253,5 @@ func VerifyExit(validator *stateTrie.ReadOnlyValidator, currentSlot uint64, fork
 			validator.ActivationEpoch()+params.BeaconConfig().PersistentCommitteePeriod,
 		)
 	}
-	domain, err := helpers.Domain(fork, exit.Epoch, params.BeaconConfig().DomainVoluntaryExit, genesisRoot)
-	if err != nil {
-		return err
-	}
-	valPubKey := validator.PublicKey()
-	if err := helpers.VerifySigningRoot(exit, valPubKey[:], signed.Signature, domain); err != nil {
-		return helpers.ErrSigFailedToVerify
-	}
 	return nil
 }
@@ -0,0 +1,350 @@
+package blocks
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/attestationutil"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// signatureSet returns the set of a single signature of the object by the given public key.
+func signatureSet(obj interface{}, pub []byte, signature []byte, domain []byte, description string) (*bls.SignatureSet, error) {
+	publicKey, err := bls.PublicKeyFromBytes(pub)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert bytes to public key")
+	}
+	sig, err := bls.SignatureFromBytes(signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert bytes to signature")
+	}
+	root, err := helpers.ComputeSigningRoot(obj, domain)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	set := bls.NewSet()
+	set.Add(sig, publicKey, root, description)
+	return set, nil
+}
+
+// BlockSignatureSet retrieves the proposer signature of a block with the public key and
+// signing root it is verified against, as VerifyBlockSignature does.
+func BlockSignatureSet(beaconState *stateTrie.BeaconState, block *ethpb.SignedBeaconBlock) (*bls.SignatureSet, error) {
+	proposer, err := beaconState.ValidatorAtIndexReadOnly(block.Block.ProposerIndex)
+	if err != nil {
+		return nil, err
+	}
+	currentEpoch := helpers.SlotToEpoch(beaconState.Slot())
+	domain, err := helpers.Domain(beaconState.Fork(), currentEpoch, params.BeaconConfig().DomainBeaconProposer, beaconState.GenesisValidatorRoot())
+	if err != nil {
+		return nil, err
+	}
+	proposerPubKey := proposer.PublicKey()
+	return signatureSet(block.Block, proposerPubKey[:], block.Signature, domain, "block")
+}
+
+// RandaoSignatureSet retrieves the randao reveal of a block with the public key and signing
+// root it is verified against, as ProcessRandao does.
+func RandaoSignatureSet(beaconState *stateTrie.BeaconState, body *ethpb.BeaconBlockBody) (*bls.SignatureSet, error) {
+	proposerIdx, err := helpers.BeaconProposerIndex(beaconState)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get beacon proposer index")
+	}
+	proposerPub := beaconState.PubkeyAtIndex(proposerIdx)
+
+	currentEpoch := helpers.SlotToEpoch(beaconState.Slot())
+	domain, err := helpers.Domain(beaconState.Fork(), currentEpoch, params.BeaconConfig().DomainRandao, beaconState.GenesisValidatorRoot())
+	if err != nil {
+		return nil, err
+	}
+	return randaoSignatureSet(proposerPub[:], body.RandaoReveal, currentEpoch, domain)
+}
+
+// ProposerSignatureSet retrieves the block signature and the randao reveal of a block with the
+// public key of its proposer and the signing roots they are verified against. Unlike
+// BlockSignatureSet and RandaoSignatureSet, the domains are computed at the epoch of the block
+// instead of the epoch of the state, so that an earlier state of the chain can be used to verify
+// the signatures of a batch of blocks ahead of their state transitions.
+func ProposerSignatureSet(beaconState *stateTrie.BeaconState, signed *ethpb.SignedBeaconBlock) (*bls.SignatureSet, error) {
+	if signed == nil || signed.Block == nil || signed.Block.Body == nil {
+		return nil, errors.New("nil block")
+	}
+	proposer, err := beaconState.ValidatorAtIndexReadOnly(signed.Block.ProposerIndex)
+	if err != nil {
+		return nil, err
+	}
+	proposerPubKey := proposer.PublicKey()
+
+	epoch := helpers.SlotToEpoch(signed.Block.Slot)
+	domain, err := helpers.Domain(beaconState.Fork(), epoch, params.BeaconConfig().DomainBeaconProposer, beaconState.GenesisValidatorRoot())
+	if err != nil {
+		return nil, err
+	}
+	blockSet, err := signatureSet(signed.Block, proposerPubKey[:], signed.Signature, domain, "block")
+	if err != nil {
+		return nil, err
+	}
+	randaoDomain, err := helpers.Domain(beaconState.Fork(), epoch, params.BeaconConfig().DomainRandao, beaconState.GenesisValidatorRoot())
+	if err != nil {
+		return nil, err
+	}
+	randaoSet, err := randaoSignatureSet(proposerPubKey[:], signed.Block.Body.RandaoReveal, epoch, randaoDomain)
+	if err != nil {
+		return nil, err
+	}
+	return blockSet.Join(randaoSet), nil
+}
+
+// randaoSignatureSet returns the set of a randao reveal, which signs the epoch of the block.
+func randaoSignatureSet(pub []byte, reveal []byte, epoch uint64, domain []byte) (*bls.SignatureSet, error) {
+	buf := make([]byte, 32)
+	binary.LittleEndian.PutUint64(buf, epoch)
+
+	publicKey, err := bls.PublicKeyFromBytes(pub)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert bytes to public key")
+	}
+	sig, err := bls.SignatureFromBytes(reveal)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert bytes to signature")
+	}
+	root, err := ssz.HashTreeRoot(&pb.SigningRoot{
+		ObjectRoot: buf,
+		Domain:     domain,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash container")
+	}
+	set := bls.NewSet()
+	set.Add(sig, publicKey, root, "randao")
+	return set, nil
+}
+
+// ProposerSlashingSignatureSet retrieves the signatures of both headers of a proposer slashing
+// with the public key and signing roots they are verified against, as VerifyProposerSlashing does.
+func ProposerSlashingSignatureSet(beaconState *stateTrie.BeaconState, slashing *ethpb.ProposerSlashing) (*bls.SignatureSet, error) {
+	if slashing == nil || slashing.Header_1 == nil || slashing.Header_1.Header == nil || slashing.Header_2 == nil || slashing.Header_2.Header == nil {
+		return nil, errors.New("nil header cannot be verified")
+	}
+	proposer, err := beaconState.ValidatorAtIndexReadOnly(slashing.Header_1.Header.ProposerIndex)
+	if err != nil {
+		return nil, err
+	}
+	// Using headerEpoch1 here because both of the headers should have the same epoch.
+	domain, err := helpers.Domain(beaconState.Fork(), helpers.SlotToEpoch(slashing.Header_1.Header.Slot), params.BeaconConfig().DomainBeaconProposer, beaconState.GenesisValidatorRoot())
+	if err != nil {
+		return nil, err
+	}
+	proposerPubKey := proposer.PublicKey()
+	set := bls.NewSet()
+	for i, header := range []*ethpb.SignedBeaconBlockHeader{slashing.Header_1, slashing.Header_2} {
+		headerSet, err := signatureSet(header.Header, proposerPubKey[:], header.Signature, domain, fmt.Sprintf("proposer slashing header %d", i+1))
+		if err != nil {
+			return nil, err
+		}
+		set.Join(headerSet)
+	}
+	return set, nil
+}
+
+// IndexedAttestationSignatureSet retrieves the aggregate signature of an indexed attestation
+// with the aggregate public key of its attesters and the signing root it is verified against,
+// as VerifyIndexedAttestation does. An attestation without attesters has an empty set.
+func IndexedAttestationSignatureSet(ctx context.Context, beaconState *stateTrie.BeaconState, indexedAtt *ethpb.IndexedAttestation) (*bls.SignatureSet, error) {
+	if err := attestationutil.IsValidAttestationIndices(ctx, indexedAtt); err != nil {
+		return nil, err
+	}
+	if len(indexedAtt.AttestingIndices) == 0 {
+		return nil, errEmptyAttestingIndices
+	}
+	domain, err := helpers.Domain(beaconState.Fork(), indexedAtt.Data.Target.Epoch, params.BeaconConfig().DomainBeaconAttester, beaconState.GenesisValidatorRoot())
+	if err != nil {
+		return nil, err
+	}
+	root, err := helpers.ComputeSigningRoot(indexedAtt.Data, domain)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get signing root of object")
+	}
+	return indexedAttestationSignatureSet(beaconState, indexedAtt, root)
+}
+
+// errEmptyAttestingIndices is returned for attestations without attesting indices, as their
+// aggregate signature cannot verify against an empty set of public keys.
+var errEmptyAttestingIndices = errors.New("attestation has no attesting indices")
+
+// indexedAttestationSignatureSet returns the signature set of a valid indexed attestation with
+// the given signing root.
+func indexedAttestationSignatureSet(beaconState *stateTrie.BeaconState, indexedAtt *ethpb.IndexedAttestation, root [32]byte) (*bls.SignatureSet, error) {
+	set := bls.NewSet()
+	indices := indexedAtt.AttestingIndices
+	if len(indices) == 0 {
+		return nil, errEmptyAttestingIndices
+	}
+	var aggPubKey *bls.PublicKey
+	for _, idx := range indices {
+		pubkeyAtIdx := beaconState.PubkeyAtIndex(idx)
+		pk, err := bls.PublicKeyFromBytes(pubkeyAtIdx[:])
+		if err != nil {
+			return nil, errors.Wrap(err, "could not deserialize validator public key")
+		}
+		if aggPubKey == nil {
+			aggPubKey = pk
+			continue
+		}
+		aggPubKey = aggPubKey.Aggregate(pk)
+	}
+	sig, err := bls.SignatureFromBytes(indexedAtt.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert bytes to signature")
+	}
+	set.Add(sig, aggPubKey, root, "attestation")
+	return set, nil
+}
+
+// AttestationSignatureSet retrieves the signature sets of the attestations of a block body. The
+// signing roots of the attestations are computed in parallel.
+func AttestationSignatureSet(ctx context.Context, beaconState *stateTrie.BeaconState, atts []*ethpb.Attestation) (*bls.SignatureSet, error) {
+	indexedAtts := make([]*ethpb.IndexedAttestation, len(atts))
+	// The attestations of a block target at most two epochs, so they are signed with few domains.
+	byDomain := make(map[string][]int)
+	var domains []string
+	for i, att := range atts {
+		if att == nil || att.Data == nil || att.Data.Target == nil {
+			return nil, errors.New("nil attestation data target")
+		}
+		committee, err := helpers.BeaconCommitteeFromState(beaconState, att.Data.Slot, att.Data.CommitteeIndex)
+		if err != nil {
+			return nil, err
+		}
+		indexedAtt := attestationutil.ConvertToIndexed(ctx, att, committee)
+		if err := attestationutil.IsValidAttestationIndices(ctx, indexedAtt); err != nil {
+			return nil, errors.Wrapf(err, "could not get signature set of attestation %d", i)
+		}
+		indexedAtts[i] = indexedAtt
+		if len(indexedAtt.AttestingIndices) == 0 {
+			return nil, errors.Wrapf(errEmptyAttestingIndices, "could not get signature set of attestation %d", i)
+		}
+		domain, err := helpers.Domain(beaconState.Fork(), att.Data.Target.Epoch, params.BeaconConfig().DomainBeaconAttester, beaconState.GenesisValidatorRoot())
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get signature set of attestation %d", i)
+		}
+		if _, ok := byDomain[string(domain)]; !ok {
+			domains = append(domains, string(domain))
+		}
+		byDomain[string(domain)] = append(byDomain[string(domain)], i)
+	}
+
+	roots := make([][32]byte, len(atts))
+	for _, domain := range domains {
+		indices := byDomain[domain]
+		objects := make([]interface{}, len(indices))
+		for j, i := range indices {
+			objects[j] = indexedAtts[i].Data
+		}
+		domainRoots, err := helpers.ComputeSigningRoots(objects, []byte(domain))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get signing roots of attestations")
+		}
+		for j, i := range indices {
+			roots[i] = domainRoots[j]
+		}
+	}
+
+	set := bls.NewSet()
+	for i, indexedAtt := range indexedAtts {
+		attSet, err := indexedAttestationSignatureSet(beaconState, indexedAtt, roots[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get signature set of attestation %d", i)
+		}
+		for j := range attSet.Descriptions {
+			attSet.Descriptions[j] = fmt.Sprintf("attestation %d", i)
+		}
+		set.Join(attSet)
+	}
+	return set, nil
+}
+
+// AttesterSlashingSignatureSet retrieves the signature sets of both attestations of an attester
+// slashing.
+func AttesterSlashingSignatureSet(ctx context.Context, beaconState *stateTrie.BeaconState, slashing *ethpb.AttesterSlashing) (*bls.SignatureSet, error) {
+	if slashing == nil || slashing.Attestation_1 == nil || slashing.Attestation_2 == nil {
+		return nil, errors.New("nil attestation")
+	}
+	if slashing.Attestation_1.Data == nil || slashing.Attestation_2.Data == nil {
+		return nil, errors.New("nil attestation data")
+	}
+	set := bls.NewSet()
+	for i, att := range []*ethpb.IndexedAttestation{slashing.Attestation_1, slashing.Attestation_2} {
+		attSet, err := IndexedAttestationSignatureSet(ctx, beaconState, att)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not validate indexed attestation")
+		}
+		for j := range attSet.Descriptions {
+			attSet.Descriptions[j] = fmt.Sprintf("attester slashing attestation %d", i+1)
+		}
+		set.Join(attSet)
+	}
+	return set, nil
+}
+
+// ExitSignatureSet retrieves the signature of a voluntary exit with the public key of the
+// exiting validator and the signing root it is verified against, as VerifyExit does.
+func ExitSignatureSet(beaconState *stateTrie.BeaconState, exit *ethpb.SignedVoluntaryExit) (*bls.SignatureSet, error) {
+	if exit == nil || exit.Exit == nil {
+		return nil, errors.New("nil voluntary exit in block body")
+	}
+	if int(exit.Exit.ValidatorIndex) >= beaconState.NumValidators() {
+		return nil, fmt.Errorf(
+			"validator index out of bound %d > %d",
+			exit.Exit.ValidatorIndex,
+			beaconState.NumValidators(),
+		)
+	}
+	domain, err := helpers.Domain(beaconState.Fork(), exit.Exit.Epoch, params.BeaconConfig().DomainVoluntaryExit, beaconState.GenesisValidatorRoot())
+	if err != nil {
+		return nil, err
+	}
+	valPubKey := beaconState.PubkeyAtIndex(exit.Exit.ValidatorIndex)
+	return signatureSet(exit.Exit, valPubKey[:], exit.Signature, domain, fmt.Sprintf("voluntary exit of validator %d", exit.Exit.ValidatorIndex))
+}
+
+// VerifySignatureSet verifies the signatures of a set as a batch. If the batch is invalid, the
+// signatures are verified one by one to report which one failed. Signatures verified before,
+// such as those of attestations seen on gossip, are not verified again.
+func VerifySignatureSet(set *bls.SignatureSet) error {
+	unverified := bls.NewSet()
+	var sigs, pubKeys [][]byte
+	for i, sig := range set.Signatures {
+		sigBytes, pubKeyBytes := sig.Marshal(), set.PublicKeys[i].Marshal()
+		if helpers.SignatureVerified(set.Messages[i], sigBytes, pubKeyBytes) {
+			continue
+		}
+		unverified.Add(sig, set.PublicKeys[i], set.Messages[i], set.Descriptions[i])
+		sigs = append(sigs, sigBytes)
+		pubKeys = append(pubKeys, pubKeyBytes)
+	}
+	set = unverified
+
+	valid, err := set.Verify()
+	if err != nil {
+		return errors.Wrap(err, "could not batch verify signatures")
+	}
+	if valid {
+		for i := range sigs {
+			helpers.MarkSignatureVerified(set.Messages[i], sigs[i], pubKeys[i])
+		}
+		return nil
+	}
+	if desc, ok := set.VerifyEach(); !ok {
+		return errors.Wrapf(helpers.ErrSigFailedToVerify, "could not verify %s signature", desc)
+	}
+	return helpers.ErrSigFailedToVerify
+}
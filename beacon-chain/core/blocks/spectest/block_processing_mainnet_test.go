@@ -5,5 +5,5 @@ import (
 )
 
 func TestBlockProcessingMainnetYaml(t *testing.T) {
-	runBlockProcessingTest(t, "mainnet")
+	runBlockProcessingTest(t, "mainnet", "sanity/blocks/pyspec_tests")
 }
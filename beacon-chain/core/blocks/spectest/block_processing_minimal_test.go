@@ -5,5 +5,5 @@ import (
 )
 
 func TestBlockProcessingMinimalYaml(t *testing.T) {
-	runBlockProcessingTest(t, "minimal")
+	runBlockProcessingTest(t, "minimal", "sanity/blocks/pyspec_tests")
 }
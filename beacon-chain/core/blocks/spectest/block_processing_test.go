@@ -27,12 +27,15 @@ func init() {
 	state.SkipSlotCache.Disable()
 }
 
-func runBlockProcessingTest(t *testing.T, config string) {
+// runBlockProcessingTest runs the block sanity style tests in the given folder, applying each
+// block of a test case in order with the full state transition. This format is shared by the
+// sanity/blocks and finality/finality test suites.
+func runBlockProcessingTest(t *testing.T, config string, folderPath string) {
 	if err := spectest.SetConfig(t, config); err != nil {
 		t.Fatal(err)
 	}
 
-	testFolders, testsFolderPath := testutil.TestFolders(t, config, "sanity/blocks/pyspec_tests")
+	testFolders, testsFolderPath := testutil.TestFolders(t, config, folderPath)
 	for _, folder := range testFolders {
 		t.Run(folder.Name(), func(t *testing.T) {
 			helpers.ClearCache()
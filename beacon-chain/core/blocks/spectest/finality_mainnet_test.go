@@ -0,0 +1,9 @@
+package spectest
+
+import (
+	"testing"
+)
+
+func TestFinalityMainnet(t *testing.T) {
+	runBlockProcessingTest(t, "mainnet", "finality/finality/pyspec_tests")
+}
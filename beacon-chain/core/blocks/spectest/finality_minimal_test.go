@@ -0,0 +1,9 @@
+package spectest
+
+import (
+	"testing"
+)
+
+func TestFinalityMinimal(t *testing.T) {
+	runBlockProcessingTest(t, "minimal", "finality/finality/pyspec_tests")
+}
@@ -230,16 +230,16 @@ func ProcessSlashings(state *stateTrie.BeaconState) (*stateTrie.BeaconState, err
 //    state.previous_epoch_attestations = state.current_epoch_attestations
 //    state.current_epoch_attestations = []
 func ProcessFinalUpdates(state *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
-	currentEpoch := helpers.CurrentEpoch(state)
-	nextEpoch := currentEpoch + 1
-
-	// Reset ETH1 data votes.
-	if nextEpoch%params.BeaconConfig().EpochsPerEth1VotingPeriod == 0 {
-		if err := state.SetEth1DataVotes([]*ethpb.Eth1Data{}); err != nil {
-			return nil, err
-		}
+	state, err := ProcessEffectiveBalanceUpdates(state)
+	if err != nil {
+		return nil, err
 	}
+	return ProcessFinalUpdatesNoEffectiveBalances(state)
+}
 
+// ProcessEffectiveBalanceUpdates updates the effective balances of the validators with hysteresis,
+// as part of the final updates during epoch processing.
+func ProcessEffectiveBalanceUpdates(state *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
 	effBalanceInc := params.BeaconConfig().EffectiveBalanceIncrement
 	maxEffBalance := params.BeaconConfig().MaxEffectiveBalance
 	hysteresisInc := effBalanceInc / params.BeaconConfig().HysteresisQuotient
@@ -270,6 +270,21 @@ func ProcessFinalUpdates(state *stateTrie.BeaconState) (*stateTrie.BeaconState,
 	if err := state.ApplyToEveryValidator(validatorFunc); err != nil {
 		return nil, err
 	}
+	return state, nil
+}
+
+// ProcessFinalUpdatesNoEffectiveBalances processes the final updates during epoch processing other
+// than the effective balance updates, for callers which update the effective balances separately.
+func ProcessFinalUpdatesNoEffectiveBalances(state *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+	currentEpoch := helpers.CurrentEpoch(state)
+	nextEpoch := currentEpoch + 1
+
+	// Reset ETH1 data votes.
+	if nextEpoch%params.BeaconConfig().EpochsPerEth1VotingPeriod == 0 {
+		if err := state.SetEth1DataVotes([]*ethpb.Eth1Data{}); err != nil {
+			return nil, err
+		}
+	}
 
 	// Set total slashed balances.
 	slashedExitLength := params.BeaconConfig().EpochsPerSlashingsVector
@@ -0,0 +1,44 @@
+package precompute
+
+import (
+	"github.com/pkg/errors"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// ProcessEffectiveBalanceUpdatesPrecompute updates the effective balances of the validators with
+// hysteresis. This is an optimized version by passing in precomputed validator records: the
+// registry is not scanned, the balances are compared with the precomputed effective balances, and
+// only the validators whose effective balance changes are read from and written to the state.
+// It must run after the rewards, penalties and slashings of the epoch are applied to the balances.
+func ProcessEffectiveBalanceUpdatesPrecompute(state *stateTrie.BeaconState, vp []*Validator) (*stateTrie.BeaconState, error) {
+	bals := state.Balances()
+	if len(vp) != state.NumValidators() || len(vp) != len(bals) {
+		return nil, errors.New("precomputed registries not the same length as state registries")
+	}
+	effBalanceInc := params.BeaconConfig().EffectiveBalanceIncrement
+	maxEffBalance := params.BeaconConfig().MaxEffectiveBalance
+	hysteresisInc := effBalanceInc / params.BeaconConfig().HysteresisQuotient
+	downwardThreshold := hysteresisInc * params.BeaconConfig().HysteresisDownwardMultiplier
+	upwardThreshold := hysteresisInc * params.BeaconConfig().HysteresisUpwardMultiplier
+
+	for idx, v := range vp {
+		balance := bals[idx]
+		effBalance := v.CurrentEpochEffectiveBalance
+		if balance+downwardThreshold >= effBalance && effBalance+upwardThreshold >= balance {
+			continue
+		}
+		validator, err := state.ValidatorAtIndex(uint64(idx))
+		if err != nil {
+			return nil, err
+		}
+		validator.EffectiveBalance = maxEffBalance
+		if validator.EffectiveBalance > balance-balance%effBalanceInc {
+			validator.EffectiveBalance = balance - balance%effBalanceInc
+		}
+		if err := state.UpdateValidatorAtIndex(uint64(idx), validator); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,82 @@
+package precompute
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/epoch"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestProcessEffectiveBalanceUpdatesPrecompute_MatchesSpec(t *testing.T) {
+	maxBal := params.BeaconConfig().MaxEffectiveBalance
+	inc := params.BeaconConfig().EffectiveBalanceIncrement
+	base := buildState(params.BeaconConfig().SlotsPerEpoch*5, 64)
+	// Balances within the hysteresis thresholds keep their effective balance.
+	base.Balances[0] = maxBal - inc/10
+	base.Balances[1] = maxBal + inc
+	// Balances past the thresholds update the effective balance, capped at the maximum.
+	base.Balances[2] = maxBal - inc
+	base.Balances[3] = 0
+	base.Balances[4] = maxBal + 3*inc
+	base.Validators[4].EffectiveBalance = maxBal - 2*inc
+
+	want, err := state.InitializeFromProto(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := want.Copy()
+	want, err = epoch.ProcessEffectiveBalanceUpdates(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vp, _, err := New(context.Background(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = ProcessEffectiveBalanceUpdatesPrecompute(s, vp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Validators(), want.Validators()) {
+		t.Error("Precomputed effective balance updates differ from the spec effective balance updates")
+	}
+	if s.Validators()[2].EffectiveBalance != maxBal-inc || s.Validators()[4].EffectiveBalance != maxBal {
+		t.Error("Effective balances past the hysteresis thresholds were not updated")
+	}
+}
+
+func BenchmarkProcessEffectiveBalanceUpdatesPrecompute(b *testing.B) {
+	s, err := state.InitializeFromProto(buildState(params.BeaconConfig().SlotsPerEpoch*5, 100000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	vp, _, err := New(context.Background(), s)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ProcessEffectiveBalanceUpdatesPrecompute(s.Copy(), vp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProcessEffectiveBalanceUpdates(b *testing.B) {
+	s, err := state.InitializeFromProto(buildState(params.BeaconConfig().SlotsPerEpoch*5, 100000))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := epoch.ProcessEffectiveBalanceUpdates(s.Copy()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
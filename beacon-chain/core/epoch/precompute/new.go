@@ -32,6 +32,9 @@ func New(ctx context.Context, state *stateTrie.BeaconState) ([]*Validator, *Bala
 			IsSlashed:                    val.Slashed(),
 			IsWithdrawableCurrentEpoch:   withdrawable,
 			CurrentEpochEffectiveBalance: val.EffectiveBalance(),
+			IsEligibleForActivationQueue: helpers.IsEligibleForActivationQueueUsingTrie(val),
+			IsActivationPending:          val.ActivationEpoch() == params.BeaconConfig().FarFutureEpoch,
+			ActivationEligibilityEpoch:   val.ActivationEligibilityEpoch(),
 		}
 		// Was validator active current epoch
 		if helpers.IsActiveValidatorUsingTrie(val, currentEpoch) {
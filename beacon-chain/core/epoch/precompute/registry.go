@@ -0,0 +1,91 @@
+package precompute
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/validators"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// ProcessRegistryUpdatesPrecompute rotates validators in and out of the active pool.
+// This is an optimized version by passing in precomputed validator records: the registry is not
+// scanned, and only the validators whose activation eligibility, ejection or activation changes
+// are read from and written to the state.
+func ProcessRegistryUpdatesPrecompute(state *stateTrie.BeaconState, vp []*Validator) (*stateTrie.BeaconState, error) {
+	if len(vp) != state.NumValidators() {
+		return nil, errors.New("precomputed registry not the same length as state registry")
+	}
+	currentEpoch := helpers.CurrentEpoch(state)
+	finalizedEpoch := state.FinalizedCheckpointEpoch()
+	ejectionBal := params.BeaconConfig().EjectionBalance
+	activationEligibilityEpoch := currentEpoch + 1
+
+	var err error
+	var activationQ []uint64
+	activeCount := uint64(0)
+	for idx, v := range vp {
+		// Process the validators for activation eligibility.
+		if v.IsEligibleForActivationQueue {
+			validator, err := state.ValidatorAtIndex(uint64(idx))
+			if err != nil {
+				return nil, err
+			}
+			validator.ActivationEligibilityEpoch = activationEligibilityEpoch
+			if err := state.UpdateValidatorAtIndex(uint64(idx), validator); err != nil {
+				return nil, err
+			}
+		}
+
+		// Process the validators for ejection. An ejected validator remains active in the
+		// current epoch.
+		if v.IsActiveCurrentEpoch {
+			activeCount++
+			if v.CurrentEpochEffectiveBalance <= ejectionBal {
+				state, err = validators.InitiateValidatorExit(state, uint64(idx))
+				if err != nil {
+					return nil, errors.Wrapf(err, "could not initiate exit for validator %d", idx)
+				}
+			}
+		}
+
+		// Queue validators eligible for activation and not yet dequeued for activation. Validators
+		// made eligible above are past the finalized epoch, so they are not queued.
+		if v.IsActivationPending && v.ActivationEligibilityEpoch <= finalizedEpoch {
+			activationQ = append(activationQ, uint64(idx))
+		}
+	}
+
+	sort.Slice(activationQ, func(i, j int) bool {
+		a, b := vp[activationQ[i]], vp[activationQ[j]]
+		if a.ActivationEligibilityEpoch == b.ActivationEligibilityEpoch {
+			return activationQ[i] < activationQ[j]
+		}
+		return a.ActivationEligibilityEpoch < b.ActivationEligibilityEpoch
+	})
+
+	// Only activate just enough validators according to the activation churn limit.
+	churnLimit, err := helpers.ValidatorChurnLimit(activeCount)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get churn limit")
+	}
+	limit := len(activationQ)
+	if int(churnLimit) < limit {
+		limit = int(churnLimit)
+	}
+
+	activationExitEpoch := helpers.ActivationExitEpoch(currentEpoch)
+	for _, index := range activationQ[:limit] {
+		validator, err := state.ValidatorAtIndex(index)
+		if err != nil {
+			return nil, err
+		}
+		validator.ActivationEpoch = activationExitEpoch
+		if err := state.UpdateValidatorAtIndex(index, validator); err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,90 @@
+package precompute
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/epoch"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestProcessRegistryUpdatesPrecompute_MatchesSpec(t *testing.T) {
+	ffe := params.BeaconConfig().FarFutureEpoch
+	maxBal := params.BeaconConfig().MaxEffectiveBalance
+	base := buildState(params.BeaconConfig().SlotsPerEpoch*5, 64)
+	base.FinalizedCheckpoint = &ethpb.Checkpoint{Epoch: 3}
+	// Validators 0 and 1 enter the activation queue.
+	base.Validators[0] = &ethpb.Validator{ActivationEligibilityEpoch: ffe, ActivationEpoch: ffe, ExitEpoch: ffe, WithdrawableEpoch: ffe, EffectiveBalance: maxBal}
+	base.Validators[1] = &ethpb.Validator{ActivationEligibilityEpoch: ffe, ActivationEpoch: ffe, ExitEpoch: ffe, WithdrawableEpoch: ffe, EffectiveBalance: maxBal}
+	// Validator 2 is ejected.
+	base.Validators[2] = &ethpb.Validator{ExitEpoch: ffe, WithdrawableEpoch: ffe, EffectiveBalance: params.BeaconConfig().EjectionBalance}
+	// Validators 3 to 12 wait for activation, more than the churn limit allows, in reverse order of eligibility.
+	for i := 3; i < 13; i++ {
+		base.Validators[i] = &ethpb.Validator{
+			ActivationEligibilityEpoch: uint64(13-i) % 4,
+			ActivationEpoch:            ffe,
+			ExitEpoch:                  ffe,
+			WithdrawableEpoch:          ffe,
+			EffectiveBalance:           maxBal,
+		}
+	}
+	// Validator 13 is eligible for activation only after the finalized epoch.
+	base.Validators[13] = &ethpb.Validator{ActivationEligibilityEpoch: 4, ActivationEpoch: ffe, ExitEpoch: ffe, WithdrawableEpoch: ffe, EffectiveBalance: maxBal}
+
+	want, err := state.InitializeFromProto(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := want.Copy()
+	want, err = epoch.ProcessRegistryUpdates(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vp, _, err := New(context.Background(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = ProcessRegistryUpdatesPrecompute(s, vp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Validators(), want.Validators()) {
+		t.Error("Precomputed registry updates differ from the spec registry updates")
+	}
+}
+
+func BenchmarkProcessRegistryUpdatesPrecompute(b *testing.B) {
+	s, err := state.InitializeFromProto(buildState(params.BeaconConfig().SlotsPerEpoch*5, 100000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	vp, _, err := New(context.Background(), s)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ProcessRegistryUpdatesPrecompute(s.Copy(), vp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProcessRegistryUpdates(b *testing.B) {
+	s, err := state.InitializeFromProto(buildState(params.BeaconConfig().SlotsPerEpoch*5, 100000))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := epoch.ProcessRegistryUpdates(s.Copy()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
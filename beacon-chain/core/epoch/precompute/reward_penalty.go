@@ -27,31 +27,32 @@ func ProcessRewardsAndPenaltiesPrecompute(
 		return state, errors.New("precomputed registries not the same length as state registries")
 	}
 
-	attsRewards, attsPenalties, err := attestationDeltas(state, pBal, vp)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get attestation delta")
-	}
-	proposerRewards, err := proposerDeltaPrecompute(state, pBal, vp)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get attestation delta")
+	// The attestation and proposer deltas are gathered in a single pass over the precomputed
+	// registry, then applied to a copy of the balances which is written back to the state once.
+	prevEpoch := helpers.PrevEpoch(state)
+	finalizedEpoch := state.FinalizedCheckpointEpoch()
+	rewards := make([]uint64, numOfVals)
+	penalties := make([]uint64, numOfVals)
+	for i, v := range vp {
+		r, p := attestationDelta(pBal, v, prevEpoch, finalizedEpoch)
+		rewards[i] += r
+		penalties[i] = p
+		rewards[v.ProposerIndex] += proposerDelta(pBal, v)
 	}
-	for i := 0; i < numOfVals; i++ {
-		vp[i].BeforeEpochTransitionBalance, err = state.BalanceAtIndex(uint64(i))
-		if err != nil {
-			return nil, errors.Wrap(err, "could not get validator balance before epoch")
-		}
-
-		if err := helpers.IncreaseBalance(state, uint64(i), attsRewards[i]+proposerRewards[i]); err != nil {
-			return nil, err
-		}
-		if err := helpers.DecreaseBalance(state, uint64(i), attsPenalties[i]); err != nil {
-			return nil, err
-		}
 
-		vp[i].AfterEpochTransitionBalance, err = state.BalanceAtIndex(uint64(i))
-		if err != nil {
-			return nil, errors.Wrap(err, "could not get validator balance after epoch")
+	bals := state.Balances()
+	for i := 0; i < numOfVals; i++ {
+		vp[i].BeforeEpochTransitionBalance = bals[i]
+		bals[i] += rewards[i]
+		if penalties[i] > bals[i] {
+			bals[i] = 0
+		} else {
+			bals[i] -= penalties[i]
 		}
+		vp[i].AfterEpochTransitionBalance = bals[i]
+	}
+	if err := state.SetBalances(bals); err != nil {
+		return nil, errors.Wrap(err, "could not set balances after epoch")
 	}
 
 	return state, nil
@@ -64,19 +65,20 @@ func attestationDeltas(state *stateTrie.BeaconState, pBal *Balance, vp []*Valida
 	rewards := make([]uint64, numOfVals)
 	penalties := make([]uint64, numOfVals)
 
+	prevEpoch := helpers.PrevEpoch(state)
+	finalizedEpoch := state.FinalizedCheckpointEpoch()
 	for i, v := range vp {
-		rewards[i], penalties[i] = attestationDelta(state, pBal, v)
+		rewards[i], penalties[i] = attestationDelta(pBal, v, prevEpoch, finalizedEpoch)
 	}
 	return rewards, penalties, nil
 }
 
-func attestationDelta(state *stateTrie.BeaconState, pBal *Balance, v *Validator) (uint64, uint64) {
+func attestationDelta(pBal *Balance, v *Validator, prevEpoch uint64, finalizedEpoch uint64) (uint64, uint64) {
 	eligible := v.IsActivePrevEpoch || (v.IsSlashed && !v.IsWithdrawableCurrentEpoch)
 	if !eligible || pBal.ActiveCurrentEpoch == 0 {
 		return 0, 0
 	}
 
-	e := helpers.PrevEpoch(state)
 	vb := v.CurrentEpochEffectiveBalance
 	br := vb * params.BeaconConfig().BaseRewardFactor / mathutil.IntegerSquareRoot(pBal.ActiveCurrentEpoch) / params.BeaconConfig().BaseRewardsPerEpoch
 	r, p := uint64(0), uint64(0)
@@ -112,8 +114,7 @@ func attestationDelta(state *stateTrie.BeaconState, pBal *Balance, v *Validator)
 	}
 
 	// Process finality delay penalty
-	finalizedEpoch := state.FinalizedCheckpointEpoch()
-	finalityDelay := e - finalizedEpoch
+	finalityDelay := prevEpoch - finalizedEpoch
 	if finalityDelay > params.BeaconConfig().MinEpochsToInactivityPenalty {
 		p += params.BeaconConfig().BaseRewardsPerEpoch * br
 		if !v.IsPrevEpochTargetAttester {
@@ -123,25 +124,12 @@ func attestationDelta(state *stateTrie.BeaconState, pBal *Balance, v *Validator)
 	return r, p
 }
 
-// This computes the rewards and penalties differences for individual validators based on the
-// proposer inclusion records.
-func proposerDeltaPrecompute(state *stateTrie.BeaconState, pBal *Balance, vp []*Validator) ([]uint64, error) {
-	numofVals := state.NumValidators()
-	rewards := make([]uint64, numofVals)
-
-	totalBalance := pBal.ActiveCurrentEpoch
-
-	baseRewardFactor := params.BeaconConfig().BaseRewardFactor
-	balanceSqrt := mathutil.IntegerSquareRoot(totalBalance)
-	baseRewardsPerEpoch := params.BeaconConfig().BaseRewardsPerEpoch
-	proposerRewardQuotient := params.BeaconConfig().ProposerRewardQuotient
-	for _, v := range vp {
-		if v.IsPrevEpochAttester {
-			vBalance := v.CurrentEpochEffectiveBalance
-			baseReward := vBalance * baseRewardFactor / balanceSqrt / baseRewardsPerEpoch
-			proposerReward := baseReward / proposerRewardQuotient
-			rewards[v.ProposerIndex] += proposerReward
-		}
+// proposerDelta returns the reward of the proposer who included the validator's attestation.
+func proposerDelta(pBal *Balance, v *Validator) uint64 {
+	if !v.IsPrevEpochAttester || pBal.ActiveCurrentEpoch == 0 {
+		return 0
 	}
-	return rewards, nil
+	baseReward := v.CurrentEpochEffectiveBalance * params.BeaconConfig().BaseRewardFactor /
+		mathutil.IntegerSquareRoot(pBal.ActiveCurrentEpoch) / params.BeaconConfig().BaseRewardsPerEpoch
+	return baseReward / params.BeaconConfig().ProposerRewardQuotient
 }
@@ -196,6 +196,24 @@ func TestAttestationDeltas_ZeroEpoch(t *testing.T) {
 	}
 }
 
+func BenchmarkProcessRewardsAndPenaltiesPrecompute(b *testing.B) {
+	s, err := state.InitializeFromProto(buildState(params.BeaconConfig().SlotsPerEpoch*5, 100000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	vp, bp, err := New(context.Background(), s)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ProcessRewardsAndPenaltiesPrecompute(s.Copy(), bp, vp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func buildState(slot uint64, validatorCount uint64) *pb.BeaconState {
 	validators := make([]*ethpb.Validator, validatorCount)
 	for i := 0; i < len(validators); i++ {
@@ -1,7 +1,8 @@
 package precompute
 
 import (
-	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"fmt"
+
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/mathutil"
@@ -24,18 +25,31 @@ func ProcessSlashingsPrecompute(state *stateTrie.BeaconState, pBal *Balance) err
 	minSlashing := mathutil.Min(totalSlashing*3, pBal.ActiveCurrentEpoch)
 	epochToWithdraw := currentEpoch + exitLength/2
 	increment := params.BeaconConfig().EffectiveBalanceIncrement
-	validatorFunc := func(idx int, val *ethpb.Validator) (bool, error) {
-		correctEpoch := epochToWithdraw == val.WithdrawableEpoch
-		if val.Slashed && correctEpoch {
-			penaltyNumerator := val.EffectiveBalance / increment * minSlashing
+	// Penalties are applied to a copy of the balances, so the registry is only read and the
+	// balances are written back once, and only if a validator was penalized.
+	bals := state.Balances()
+	penalized := false
+	if err := state.ReadFromEveryValidator(func(idx int, val *stateTrie.ReadOnlyValidator) error {
+		correctEpoch := epochToWithdraw == val.WithdrawableEpoch()
+		if val.Slashed() && correctEpoch {
+			if idx >= len(bals) {
+				return fmt.Errorf("validator index exceeds balances length in state %d >= %d", idx, len(bals))
+			}
+			penaltyNumerator := val.EffectiveBalance() / increment * minSlashing
 			penalty := penaltyNumerator / pBal.ActiveCurrentEpoch * increment
-			if err := helpers.DecreaseBalance(state, uint64(idx), penalty); err != nil {
-				return false, err
+			if penalty > bals[idx] {
+				bals[idx] = 0
+			} else {
+				bals[idx] -= penalty
 			}
-			return true, nil
+			penalized = true
 		}
-		return false, nil
+		return nil
+	}); err != nil {
+		return err
 	}
-
-	return state.ApplyToEveryValidator(validatorFunc)
+	if !penalized {
+		return nil
+	}
+	return state.SetBalances(bals)
 }
@@ -22,6 +22,10 @@ type Validator struct {
 	IsPrevEpochTargetAttester bool
 	// IsHeadAttester is true if the validator attested head.
 	IsPrevEpochHeadAttester bool
+	// IsEligibleForActivationQueue is true if the validator is to be placed into the activation queue.
+	IsEligibleForActivationQueue bool
+	// IsActivationPending is true if the validator has not been assigned an activation epoch yet.
+	IsActivationPending bool
 
 	// CurrentEpochEffectiveBalance is how much effective balance this validator validator has current epoch.
 	CurrentEpochEffectiveBalance uint64
@@ -29,6 +33,8 @@ type Validator struct {
 	InclusionSlot uint64
 	// InclusionDistance is the distance between the assigned slot and this validator's attestation was included in block.
 	InclusionDistance uint64
+	// ActivationEligibilityEpoch is the epoch when the validator became eligible for activation.
+	ActivationEligibilityEpoch uint64
 	// ProposerIndex is the index of proposer at slot where this validator's attestation was included.
 	ProposerIndex uint64
 	// BeforeEpochTransitionBalance is the validator balance prior to epoch transition.
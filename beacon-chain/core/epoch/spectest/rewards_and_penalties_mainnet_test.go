@@ -0,0 +1,9 @@
+package spectest
+
+import (
+	"testing"
+)
+
+func TestRewardsAndPenaltiesMainnet(t *testing.T) {
+	runRewardsAndPenaltiesTests(t, "mainnet")
+}
@@ -0,0 +1,9 @@
+package spectest
+
+import (
+	"testing"
+)
+
+func TestRewardsAndPenaltiesMinimal(t *testing.T) {
+	runRewardsAndPenaltiesTests(t, "minimal")
+}
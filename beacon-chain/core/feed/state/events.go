@@ -17,6 +17,8 @@ const (
 	// Reorg is an event sent when the new head state's slot after a block
 	// transition is lower than its previous head state slot value.
 	Reorg
+	// FinalizedCheckpoint is sent when the finalized checkpoint of the chain advances.
+	FinalizedCheckpoint
 )
 
 // BlockProcessedData is the data sent with BlockProcessed events.
@@ -55,4 +57,23 @@ type ReorgData struct {
 	NewSlot uint64
 	// OldSlot is the slot of the head state before the reorg.
 	OldSlot uint64
+	// NewHeadRoot is the block root of the new head.
+	NewHeadRoot [32]byte
+	// OldHeadRoot is the block root of the head before the reorg.
+	OldHeadRoot [32]byte
+	// CommonAncestorSlot is the slot of the latest block of both the old and the new chain.
+	CommonAncestorSlot uint64
+	// CommonAncestorRoot is the block root of the latest block of both the old and the new chain.
+	CommonAncestorRoot [32]byte
+	// Depth is the number of slots of the old chain replaced by the reorg, from the common
+	// ancestor to the old head.
+	Depth uint64
+}
+
+// FinalizedCheckpointData is the data sent with FinalizedCheckpoint events.
+type FinalizedCheckpointData struct {
+	// Epoch of the new finalized checkpoint.
+	Epoch uint64
+	// BlockRoot of the new finalized checkpoint.
+	BlockRoot [32]byte
 }
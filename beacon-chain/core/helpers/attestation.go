@@ -123,7 +123,7 @@ func AggregateAttestation(a1 *ethpb.Attestation, a2 *ethpb.Attestation) (*ethpb.
 //    domain = get_domain(state, DOMAIN_BEACON_ATTESTER, compute_epoch_at_slot(slot))
 //    return bls_sign(privkey, hash_tree_root(slot), domain)
 func SlotSignature(state *stateTrie.BeaconState, slot uint64, privKey *bls.SecretKey) (*bls.Signature, error) {
-	d, err := Domain(state.Fork(), CurrentEpoch(state), params.BeaconConfig().DomainBeaconAttester, state.GenesisValidatorRoot())
+	d, err := Domain(state.Fork(), SlotToEpoch(slot), params.BeaconConfig().DomainBeaconAttester, state.GenesisValidatorRoot())
 	if err != nil {
 		return nil, err
 	}
@@ -176,3 +176,16 @@ func AggregateSignature(attestations []*ethpb.Attestation) (*bls.Signature, erro
 func IsAggregated(attestation *ethpb.Attestation) bool {
 	return attestation.AggregationBits.Count() > 1
 }
+
+// ComputeSubnetForCommittee returns the attestation subnet on which the attestations of a
+// committee are gossiped.
+//
+// Spec pseudocode definition:
+//   def compute_subnet_for_attestation(state: BeaconState, attestation: Attestation) -> uint64:
+//    """
+//    Compute the correct subnet for an attestation for Phase 0.
+//    """
+//    return attestation.data.index % ATTESTATION_SUBNET_COUNT
+func ComputeSubnetForCommittee(committeeIndex uint64) uint64 {
+	return committeeIndex % params.BeaconNetworkConfig().AttestationSubnetCount
+}
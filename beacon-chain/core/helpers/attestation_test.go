@@ -271,6 +271,42 @@ func TestSlotSignature_Verify(t *testing.T) {
 	}
 }
 
+func TestSlotSignature_UsesForkOfSlotEpoch(t *testing.T) {
+	priv := bls.RandKey()
+	pub := priv.PublicKey()
+	forkEpoch := uint64(4)
+	state, err := beaconstate.InitializeFromProto(&pb.BeaconState{
+		Fork: &pb.Fork{
+			CurrentVersion:  []byte{1, 0, 0, 0},
+			PreviousVersion: params.BeaconConfig().GenesisForkVersion,
+			Epoch:           forkEpoch,
+		},
+		Slot: forkEpoch * params.BeaconConfig().SlotsPerEpoch,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The slot is before the fork, while the state is past it.
+	slot := forkEpoch*params.BeaconConfig().SlotsPerEpoch - 1
+
+	sig, err := helpers.SlotSignature(state, slot, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domain, err := helpers.ComputeDomain(params.BeaconConfig().DomainBeaconAttester, params.BeaconConfig().GenesisForkVersion, state.GenesisValidatorRoot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := helpers.ComputeSigningRoot(slot, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(pub, msg[:]) {
+		t.Error("Wanted the slot signature with the previous fork version")
+	}
+}
+
 func TestIsAggregator_True(t *testing.T) {
 	beaconState, privKeys := testutil.DeterministicGenesisState(t, 100)
 
@@ -348,3 +384,22 @@ func TestAggregateSignature_False(t *testing.T) {
 		t.Error("Signature not suppose to verify")
 	}
 }
+
+func TestComputeSubnetForCommittee(t *testing.T) {
+	subnetCount := params.BeaconNetworkConfig().AttestationSubnetCount
+	tests := []struct {
+		committeeIndex uint64
+		subnet         uint64
+	}{
+		{committeeIndex: 0, subnet: 0},
+		{committeeIndex: 5, subnet: 5},
+		{committeeIndex: subnetCount - 1, subnet: subnetCount - 1},
+		{committeeIndex: subnetCount, subnet: 0},
+		{committeeIndex: subnetCount + 3, subnet: 3},
+	}
+	for _, tt := range tests {
+		if subnet := helpers.ComputeSubnetForCommittee(tt.committeeIndex); subnet != tt.subnet {
+			t.Errorf("Wanted subnet %d for committee index %d, received %d", tt.subnet, tt.committeeIndex, subnet)
+		}
+	}
+}
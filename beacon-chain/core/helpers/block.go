@@ -2,10 +2,13 @@ package helpers
 
 import (
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
+var recentRootsCache = cache.NewRecentRootsCache()
+
 // BlockRootAtSlot returns the block root stored in the BeaconState for a recent slot.
 // It returns an error if the requested block root is not within the slot range.
 //
@@ -34,3 +37,35 @@ func BlockRootAtSlot(state *stateTrie.BeaconState, slot uint64) ([]byte, error)
 func BlockRoot(state *stateTrie.BeaconState, epoch uint64) ([]byte, error) {
 	return BlockRootAtSlot(state, StartSlot(epoch))
 }
+
+// HeadBlockRootAtSlot returns the block root at a recent slot like BlockRootAtSlot, for a state on
+// the canonical chain of the given head root: the head state, one of its ancestors, or one of those
+// advanced through empty slots. The roots of the slots before the head are read from the recent
+// roots cache, without touching the roots vector of the state.
+func HeadBlockRootAtSlot(headRoot [32]byte, state *stateTrie.BeaconState, slot uint64) ([]byte, error) {
+	if slot >= state.Slot() || state.Slot() > slot+params.BeaconConfig().SlotsPerHistoricalRoot {
+		return []byte{}, errors.Errorf("slot %d out of bounds", slot)
+	}
+	if root, ok := recentRootsCache.BlockRootAtSlot(headRoot, slot); ok {
+		return root[:], nil
+	}
+	return state.BlockRootAtIndex(slot % params.BeaconConfig().SlotsPerHistoricalRoot)
+}
+
+// IsRecentHeadBlockRoot returns true if the block root is the block root at a recent slot, or the
+// head root at or after the head slot, on the canonical chain of the given head root, as recorded
+// in the recent roots cache. It returns false if the root is not found in the cache.
+func IsRecentHeadBlockRoot(headRoot [32]byte, blockRoot [32]byte, slot uint64) bool {
+	return recentRootsCache.HasBlockRoot(headRoot, blockRoot, slot)
+}
+
+// UpdateRecentRootsCache records the recent block and state roots of a new head state in the
+// recent roots cache.
+func UpdateRecentRootsCache(headRoot [32]byte, headState *stateTrie.BeaconState) error {
+	return recentRootsCache.Update(headRoot, headState)
+}
+
+// ClearRecentRootsCache removes the roots of the recent roots cache, which are stale after a reorg.
+func ClearRecentRootsCache() {
+	recentRootsCache.Clear()
+}
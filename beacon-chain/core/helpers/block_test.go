@@ -127,3 +127,49 @@ func TestBlockRootAtSlot_OutOfBounds(t *testing.T) {
 		}
 	}
 }
+
+func TestHeadBlockRootAtSlot_UsesRecentRootsCache(t *testing.T) {
+	blockRoots := make([][]byte, params.BeaconConfig().SlotsPerHistoricalRoot)
+	stateRoots := make([][]byte, params.BeaconConfig().SlotsPerHistoricalRoot)
+	for i := range blockRoots {
+		blockRoots[i] = []byte{byte(i)}
+		stateRoots[i] = make([]byte, 32)
+	}
+	headRoot := [32]byte{'h'}
+	head, err := beaconstate.InitializeFromProto(&pb.BeaconState{Slot: 10, BlockRoots: blockRoots, StateRoots: stateRoots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := helpers.UpdateRecentRootsCache(headRoot, head); err != nil {
+		t.Fatal(err)
+	}
+	defer helpers.ClearRecentRootsCache()
+
+	// An ancestor state with other roots in its vector shows which lookups are cached.
+	zeroRoots := make([][]byte, len(blockRoots))
+	for i := range zeroRoots {
+		zeroRoots[i] = make([]byte, 32)
+	}
+	ancestor, err := beaconstate.InitializeFromProto(&pb.BeaconState{Slot: 8, BlockRoots: zeroRoots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := helpers.HeadBlockRootAtSlot(headRoot, ancestor, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, append([]byte{5}, make([]byte, 31)...)) {
+		t.Errorf("Wanted the cached root of slot 5, received %#x", root)
+	}
+	if _, err := helpers.HeadBlockRootAtSlot(headRoot, ancestor, 8); err == nil {
+		t.Error("Wanted an error for a slot out of the bounds of the state")
+	}
+	// Lookups for another head fall back to the state.
+	root, err = helpers.HeadBlockRootAtSlot([32]byte{'o'}, ancestor, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, make([]byte, 32)) {
+		t.Errorf("Wanted the root in the state, received %#x", root)
+	}
+}
@@ -298,41 +298,57 @@ func ShuffledIndices(state *stateTrie.BeaconState, epoch uint64) ([]uint64, erro
 // list with committee index and epoch number. It caches the shuffled indices for current epoch and next epoch.
 func UpdateCommitteeCache(state *stateTrie.BeaconState, epoch uint64) error {
 	for _, e := range []uint64{epoch, epoch + 1} {
-		seed, err := Seed(state, e, params.BeaconConfig().DomainBeaconAttester)
-		if err != nil {
+		if err := updateCommitteeCache(state, e); err != nil {
 			return err
 		}
-		if _, exists, err := committeeCache.CommitteeCache.GetByKey(string(seed[:])); err == nil && exists {
-			return nil
-		}
+	}
+	return nil
+}
 
-		shuffledIndices, err := ShuffledIndices(state, e)
-		if err != nil {
-			return err
-		}
+// updateCommitteeCache computes and caches the committees of the given epoch, unless they are
+// cached already. Concurrent callers for the same epoch, typically at epoch boundaries when
+// attestations are validated and duties are requested at once, wait for the first caller
+// instead of shuffling the validators again.
+func updateCommitteeCache(state *stateTrie.BeaconState, epoch uint64) error {
+	seed, err := Seed(state, epoch, params.BeaconConfig().DomainBeaconAttester)
+	if err != nil {
+		return err
+	}
+	if committeeCache.HasCommittees(seed) {
+		return nil
+	}
+	if done, inProgress := committeeCache.MarkInProgress(seed); inProgress {
+		<-done
+		return nil
+	}
+	defer committeeCache.MarkNotInProgress(seed)
+	// The committees may have been cached by a caller which was done before the seed was marked.
+	if committeeCache.HasCommittees(seed) {
+		return nil
+	}
 
-		count := SlotCommitteeCount(uint64(len(shuffledIndices)))
-
-		// Store the sorted indices as well as shuffled indices. In current spec,
-		// sorted indices is required to retrieve proposer index. This is also
-		// used for failing verify signature fallback.
-		sortedIndices := make([]uint64, len(shuffledIndices))
-		copy(sortedIndices, shuffledIndices)
-		sort.Slice(sortedIndices, func(i, j int) bool {
-			return sortedIndices[i] < sortedIndices[j]
-		})
-
-		if err := committeeCache.AddCommitteeShuffledList(&cache.Committees{
-			ShuffledIndices: shuffledIndices,
-			CommitteeCount:  count * params.BeaconConfig().SlotsPerEpoch,
-			Seed:            seed,
-			SortedIndices:   sortedIndices,
-		}); err != nil {
-			return err
-		}
+	shuffledIndices, err := ShuffledIndices(state, epoch)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	count := SlotCommitteeCount(uint64(len(shuffledIndices)))
+
+	// Store the sorted indices as well as shuffled indices. In current spec,
+	// sorted indices is required to retrieve proposer index. This is also
+	// used for failing verify signature fallback.
+	sortedIndices := make([]uint64, len(shuffledIndices))
+	copy(sortedIndices, shuffledIndices)
+	sort.Slice(sortedIndices, func(i, j int) bool {
+		return sortedIndices[i] < sortedIndices[j]
+	})
+
+	return committeeCache.AddCommitteeShuffledList(&cache.Committees{
+		ShuffledIndices: shuffledIndices,
+		CommitteeCount:  count * params.BeaconConfig().SlotsPerEpoch,
+		Seed:            seed,
+		SortedIndices:   sortedIndices,
+	})
 }
 
 // UpdateProposerIndicesInCache updates proposer indices entry of the committee cache.
@@ -356,9 +372,10 @@ func UpdateProposerIndicesInCache(state *stateTrie.BeaconState, epoch uint64) er
 	return nil
 }
 
-// ClearCache clears the committee cache
+// ClearCache clears the committee cache and the verified signature cache.
 func ClearCache() {
 	committeeCache = cache.NewCommitteesCache()
+	verifiedSignatures = cache.NewVerifiedSignatureCache()
 }
 
 // This computes proposer indices of the current epoch and returns a list of proposer indices,
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -822,3 +823,79 @@ func TestPrecomputeProposerIndices_Ok(t *testing.T) {
 		t.Error("Did not precompute proposer indices correctly")
 	}
 }
+
+func TestUpdateCommitteeCache_CachesNextEpoch(t *testing.T) {
+	ClearCache()
+	validators := make([]*ethpb.Validator, 16*params.BeaconConfig().SlotsPerEpoch)
+	for i := 0; i < len(validators); i++ {
+		validators[i] = &ethpb.Validator{
+			ExitEpoch: params.BeaconConfig().FarFutureEpoch,
+		}
+	}
+	state, err := beaconstate.InitializeFromProto(&pb.BeaconState{
+		Validators:  validators,
+		RandaoMixes: make([][]byte, params.BeaconConfig().EpochsPerHistoricalVector),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cache the current epoch only, as done when its committees are read before the epoch
+	// transition.
+	if err := updateCommitteeCache(state, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateCommitteeCache(state, 0); err != nil {
+		t.Fatal(err)
+	}
+	seed, err := Seed(state, 1, params.BeaconConfig().DomainBeaconAttester)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !committeeCache.HasCommittees(seed) {
+		t.Error("Expected committees of the next epoch to be cached")
+	}
+}
+
+func TestUpdateCommitteeCache_Concurrent(t *testing.T) {
+	ClearCache()
+	validators := make([]*ethpb.Validator, 16*params.BeaconConfig().SlotsPerEpoch)
+	for i := 0; i < len(validators); i++ {
+		validators[i] = &ethpb.Validator{
+			ExitEpoch: params.BeaconConfig().FarFutureEpoch,
+		}
+	}
+	state, err := beaconstate.InitializeFromProto(&pb.BeaconState{
+		Validators:  validators,
+		RandaoMixes: make([][]byte, params.BeaconConfig().EpochsPerHistoricalVector),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted, err := BeaconCommitteeFromState(state, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ClearCache()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			committee, err := BeaconCommitteeFromState(state, 0, 0)
+			if err == nil && !reflect.DeepEqual(committee, wanted) {
+				err = fmt.Errorf("wanted committee %v, received %v", wanted, committee)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Signing latencies range from microseconds for cached roots to milliseconds for reflection
+// hashing of large objects. The hit rate of the domain cache is reported by the cache manager
+// as cache_hits_total and cache_misses_total with the cache label "domain".
+var latencyBuckets = prometheus.ExponentialBuckets(0.000005, 4, 9)
+
+var (
+	signingRootHistogram = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "signing_root_computation_seconds",
+			Help:    "The time to compute the signing root of an object with a domain.",
+			Buckets: latencyBuckets,
+		},
+	)
+	hashTreeRootHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "signing_object_hash_tree_root_seconds",
+			Help:    "The time to compute the hash tree root of a signed object, by hashing method.",
+			Buckets: latencyBuckets,
+		},
+		[]string{"type"},
+	)
+	domainHistogram = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "domain_computation_seconds",
+			Help:    "The time to compute a signature domain, including domain cache lookups.",
+			Buckets: latencyBuckets,
+		},
+	)
+)
+
+// The hash tree root observers are bound once per hashing method, so that signing roots are
+// computed without formatting the object type or looking up the label.
+var (
+	generatedRootObserver       = hashTreeRootHistogram.WithLabelValues("generated")
+	blockRootObserver           = hashTreeRootHistogram.WithLabelValues("block")
+	blockHeaderRootObserver     = hashTreeRootHistogram.WithLabelValues("block_header")
+	attestationDataRootObserver = hashTreeRootHistogram.WithLabelValues("attestation_data")
+	reflectionRootObserver      = hashTreeRootHistogram.WithLabelValues("reflection")
+)
@@ -1,14 +1,24 @@
 package helpers
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/cachemanager"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+	"github.com/prysmaticlabs/prysm/shared/mputil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
@@ -22,6 +32,20 @@ const DomainByteLength = 4
 // failed to verify.
 var ErrSigFailedToVerify = errors.New("signature did not verify")
 
+// HashRoot is an object computing its own hash tree root, such as one with generated SSZ
+// methods. Signing roots of these objects are computed without reflection.
+type HashRoot interface {
+	HashTreeRoot() ([32]byte, error)
+}
+
+// domainCache caches signature domains by domain type, fork version and genesis validators root,
+// which are constant within a fork, so that verifying signatures does not hash the fork data
+// every time.
+var domainCache = cachemanager.NewCache(cachemanager.Config{
+	Name:       "domain",
+	MaxEntries: 64,
+})
+
 // ComputeSigningRoot computes the root of the object by calculating the root of the object domain tree.
 //
 // Spec pseudocode definition:
@@ -35,26 +59,84 @@ var ErrSigFailedToVerify = errors.New("signature did not verify")
 //    )
 //    return hash_tree_root(domain_wrapped_object)
 func ComputeSigningRoot(object interface{}, domain []byte) ([32]byte, error) {
-	return signingRoot(func() ([32]byte, error) {
-		switch object.(type) {
-		case *ethpb.BeaconBlock:
-			return stateutil.BlockRoot(object.(*ethpb.BeaconBlock))
-		case *ethpb.AttestationData:
-			return stateutil.AttestationDataRoot(object.(*ethpb.AttestationData))
-		default:
-			// utilise generic ssz library
-			return ssz.HashTreeRoot(object)
+	switch obj := object.(type) {
+	case HashRoot:
+		return signingRoot(generatedRootObserver, obj.HashTreeRoot, domain)
+	case *ethpb.BeaconBlock:
+		return signingRoot(blockRootObserver, func() ([32]byte, error) {
+			return stateutil.BlockRoot(obj)
+		}, domain)
+	case *ethpb.BeaconBlockHeader:
+		return signingRoot(blockHeaderRootObserver, func() ([32]byte, error) {
+			return stateutil.BlockHeaderRoot(obj)
+		}, domain)
+	case *ethpb.AttestationData:
+		return signingRoot(attestationDataRootObserver, func() ([32]byte, error) {
+			return stateutil.AttestationDataRoot(obj)
+		}, domain)
+	default:
+		// utilise generic ssz library
+		return signingRoot(reflectionRootObserver, func() ([32]byte, error) {
+			return reflectHashTreeRoot(object)
+		}, domain)
+	}
+}
+
+// reflectHashTreeRoot computes the hash tree root of the object by reflection, returning an
+// error instead of panicking for types the ssz library cannot hash.
+func reflectHashTreeRoot(object interface{}) (root [32]byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("could not hash object of type %T: %v", object, r)
 		}
-	}, domain)
+	}()
+	return ssz.HashTreeRoot(object)
+}
+
+// ComputeSigningRoots computes the signing roots of objects signed with the same domain, in
+// parallel. The roots are returned in the order of the objects.
+func ComputeSigningRoots(objects []interface{}, domain []byte) ([][32]byte, error) {
+	if len(objects) == 0 {
+		return [][32]byte{}, nil
+	}
+	results, err := mputil.Scatter(len(objects), func(offset int, entries int, _ *sync.RWMutex) (interface{}, error) {
+		roots := make([][32]byte, entries)
+		for i := range roots {
+			root, err := ComputeSigningRoot(objects[offset+i], domain)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not compute signing root of object %d", offset+i)
+			}
+			roots[i] = root
+		}
+		return roots, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	roots := make([][32]byte, len(objects))
+	for _, result := range results {
+		copy(roots[result.Offset:], result.Extent.([][32]byte))
+	}
+	return roots, nil
 }
 
 // Computes the signing root by utilising the provided root function and then
-// returning the signing root of the container object.
-func signingRoot(rootFunc func() ([32]byte, error), domain []byte) ([32]byte, error) {
+// returning the signing root of the container object. The latency of the root
+// function is reported to the observer of its hashing method.
+func signingRoot(rootObserver prometheus.Observer, rootFunc func() ([32]byte, error), domain []byte) ([32]byte, error) {
+	start := time.Now()
+	defer func() {
+		signingRootHistogram.Observe(time.Since(start).Seconds())
+	}()
 	objRoot, err := rootFunc()
 	if err != nil {
 		return [32]byte{}, err
 	}
+	rootObserver.Observe(time.Since(start).Seconds())
+	// The signing root container of two 32 byte roots is merkleized as a single hash.
+	if len(domain) == 32 {
+		return hashutil.Hash(append(objRoot[:], domain...)), nil
+	}
 	container := &p2ppb.SigningRoot{
 		ObjectRoot: objRoot[:],
 		Domain:     domain,
@@ -62,8 +144,32 @@ func signingRoot(rootFunc func() ([32]byte, error), domain []byte) ([32]byte, er
 	return ssz.HashTreeRoot(container)
 }
 
-// VerifySigningRoot verifies the signing root of an object given it's public key, signature and domain.
-func VerifySigningRoot(obj interface{}, pub []byte, signature []byte, domain []byte) error {
+// verifiedSignatures caches the signatures verified by the helpers, so that objects received
+// more than once are verified once.
+var verifiedSignatures = cache.NewVerifiedSignatureCache()
+
+// SignatureVerified reports whether the signature of the signing root by the public key, or the
+// aggregate public key of an aggregate signature, was verified before.
+func SignatureVerified(root [32]byte, signature []byte, pub []byte) bool {
+	return verifiedSignatures.Verified(root, signature, pub)
+}
+
+// MarkSignatureVerified records that the signature of the signing root by the public key, or the
+// aggregate public key of an aggregate signature, is valid. Nothing is recorded when BLS
+// verification is skipped.
+func MarkSignatureVerified(root [32]byte, signature []byte, pub []byte) {
+	if featureconfig.Get().SkipBLSVerify {
+		return
+	}
+	verifiedSignatures.MarkVerified(root, signature, pub)
+}
+
+// verifySignature verifies the signature of a signing root given its public key, unless the
+// signature was verified before.
+func verifySignature(root [32]byte, pub []byte, signature []byte) error {
+	if SignatureVerified(root, signature, pub) {
+		return nil
+	}
 	publicKey, err := bls.PublicKeyFromBytes(pub)
 	if err != nil {
 		return errors.Wrap(err, "could not convert bytes to public key")
@@ -72,59 +178,43 @@ func VerifySigningRoot(obj interface{}, pub []byte, signature []byte, domain []b
 	if err != nil {
 		return errors.Wrap(err, "could not convert bytes to signature")
 	}
-	root, err := ComputeSigningRoot(obj, domain)
-	if err != nil {
-		return errors.Wrap(err, "could not compute signing root")
-	}
 	if !sig.Verify(publicKey, root[:]) {
 		return ErrSigFailedToVerify
 	}
+	MarkSignatureVerified(root, signature, pub)
 	return nil
 }
 
-// VerifyBlockSigningRoot verifies the signing root of a block given it's public key, signature and domain.
-func VerifyBlockSigningRoot(blk *ethpb.BeaconBlock, pub []byte, signature []byte, domain []byte) error {
-	publicKey, err := bls.PublicKeyFromBytes(pub)
-	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to public key")
-	}
-	sig, err := bls.SignatureFromBytes(signature)
+// VerifySigningRoot verifies the signing root of an object given it's public key, signature and domain.
+func VerifySigningRoot(obj interface{}, pub []byte, signature []byte, domain []byte) error {
+	root, err := ComputeSigningRoot(obj, domain)
 	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to signature")
+		return errors.Wrap(err, "could not compute signing root")
 	}
-	root, err := signingRoot(func() ([32]byte, error) {
+	return verifySignature(root, pub, signature)
+}
+
+// VerifyBlockSigningRoot verifies the signing root of a block given it's public key, signature and domain.
+func VerifyBlockSigningRoot(blk *ethpb.BeaconBlock, pub []byte, signature []byte, domain []byte) error {
+	root, err := signingRoot(blockRootObserver, func() ([32]byte, error) {
 		// utilize custom block hashing function
 		return stateutil.BlockRoot(blk)
 	}, domain)
 	if err != nil {
 		return errors.Wrap(err, "could not compute signing root")
 	}
-	if !sig.Verify(publicKey, root[:]) {
-		return ErrSigFailedToVerify
-	}
-	return nil
+	return verifySignature(root, pub, signature)
 }
 
 // VerifyBlockHeaderSigningRoot verifies the signing root of a block header given it's public key, signature and domain.
 func VerifyBlockHeaderSigningRoot(blkHdr *ethpb.BeaconBlockHeader, pub []byte, signature []byte, domain []byte) error {
-	publicKey, err := bls.PublicKeyFromBytes(pub)
-	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to public key")
-	}
-	sig, err := bls.SignatureFromBytes(signature)
-	if err != nil {
-		return errors.Wrap(err, "could not convert bytes to signature")
-	}
-	root, err := signingRoot(func() ([32]byte, error) {
+	root, err := signingRoot(blockHeaderRootObserver, func() ([32]byte, error) {
 		return stateutil.BlockHeaderRoot(blkHdr)
 	}, domain)
 	if err != nil {
 		return errors.Wrap(err, "could not compute signing root")
 	}
-	if !sig.Verify(publicKey, root[:]) {
-		return ErrSigFailedToVerify
-	}
-	return nil
+	return verifySignature(root, pub, signature)
 }
 
 // ComputeDomain returns the domain version for BLS private key to sign and verify with a zeroed 4-byte
@@ -141,6 +231,10 @@ func VerifyBlockHeaderSigningRoot(blkHdr *ethpb.BeaconBlockHeader, pub []byte, s
 //    fork_data_root = compute_fork_data_root(fork_version, genesis_validators_root)
 //    return Domain(domain_type + fork_data_root[:28])
 func ComputeDomain(domainType [DomainByteLength]byte, forkVersion []byte, genesisValidatorsRoot []byte) ([]byte, error) {
+	start := time.Now()
+	defer func() {
+		domainHistogram.Observe(time.Since(start).Seconds())
+	}()
 	if forkVersion == nil {
 		forkVersion = params.BeaconConfig().GenesisForkVersion
 	}
@@ -150,12 +244,18 @@ func ComputeDomain(domainType [DomainByteLength]byte, forkVersion []byte, genesi
 	forkBytes := [ForkVersionByteLength]byte{}
 	copy(forkBytes[:], forkVersion)
 
-	forkDataRoot, err := computeForkDataRoot(forkBytes[:], genesisValidatorsRoot)
+	key := string(domainType[:]) + string(forkBytes[:]) + string(genesisValidatorsRoot)
+	if d, ok := domainCache.Get(key); ok {
+		// Callers may modify the returned domain.
+		return append([]byte{}, d.([]byte)...), nil
+	}
+	forkDataRoot, err := ComputeForkDataRoot(forkBytes[:], genesisValidatorsRoot)
 	if err != nil {
 		return nil, err
 	}
-
-	return domain(domainType, forkDataRoot[:]), nil
+	d := domain(domainType, forkDataRoot[:])
+	domainCache.Set(key, append([]byte{}, d...), int64(len(key)+len(d)))
+	return d, nil
 }
 
 // This returns the bls domain given by the domain type and fork data root.
@@ -166,7 +266,7 @@ func domain(domainType [DomainByteLength]byte, forkDataRoot []byte) []byte {
 	return b
 }
 
-// this returns the 32byte fork data root for the ``current_version`` and ``genesis_validators_root``.
+// ComputeForkDataRoot returns the 32byte fork data root for the ``current_version`` and ``genesis_validators_root``.
 // This is used primarily in signature domains to avoid collisions across forks/chains.
 //
 // Spec pseudocode definition:
@@ -179,7 +279,7 @@ func domain(domainType [DomainByteLength]byte, forkDataRoot []byte) []byte {
 //        current_version=current_version,
 //        genesis_validators_root=genesis_validators_root,
 //    ))
-func computeForkDataRoot(version []byte, root []byte) ([32]byte, error) {
+func ComputeForkDataRoot(version []byte, root []byte) ([32]byte, error) {
 	r, err := ssz.HashTreeRoot(&pb.ForkData{
 		CurrentVersion:        version,
 		GenesisValidatorsRoot: root,
@@ -201,9 +301,9 @@ func computeForkDataRoot(version []byte, root []byte) ([32]byte, error) {
 //    """
 //    return ForkDigest(compute_fork_data_root(current_version, genesis_validators_root)[:4])
 func ComputeForkDigest(version []byte, genesisValidatorsRoot []byte) ([4]byte, error) {
-	dataRoot, err := computeForkDataRoot(version, genesisValidatorsRoot)
+	dataRoot, err := ComputeForkDataRoot(version, genesisValidatorsRoot)
 	if err != nil {
-		return [4]byte{}, nil
+		return [4]byte{}, err
 	}
 	return bytesutil.ToBytes4(dataRoot[:]), nil
 }
@@ -6,8 +6,11 @@ import (
 
 	fuzz "github.com/google/gofuzz"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	ethereum_beacon_p2p_v1 "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
@@ -19,6 +22,68 @@ func TestSigningRoot_ComputeOK(t *testing.T) {
 	}
 }
 
+// rootedObject computes its own hash tree root, counting the calls.
+type rootedObject struct {
+	root  [32]byte
+	calls int
+}
+
+func (o *rootedObject) HashTreeRoot() ([32]byte, error) {
+	o.calls++
+	return o.root, nil
+}
+
+func TestSigningRoot_HashRoot(t *testing.T) {
+	obj := &rootedObject{root: [32]byte{'r', 'o', 'o', 't'}}
+	domain := bytesutil.PadTo([]byte("domain"), 32)
+	root, err := ComputeSigningRoot(obj, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.calls != 1 {
+		t.Errorf("Wanted the root of the object to be computed by the object, received %d calls", obj.calls)
+	}
+	want, err := ssz.HashTreeRoot(&ethereum_beacon_p2p_v1.SigningRoot{ObjectRoot: obj.root[:], Domain: domain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != want {
+		t.Errorf("Wanted signing root %#x, received %#x", want, root)
+	}
+}
+
+func TestSigningRoot_BlockHeader(t *testing.T) {
+	header := &ethpb.BeaconBlockHeader{
+		Slot:          3,
+		ProposerIndex: 5,
+		ParentRoot:    bytesutil.PadTo([]byte("parent"), 32),
+		StateRoot:     bytesutil.PadTo([]byte("state"), 32),
+		BodyRoot:      bytesutil.PadTo([]byte("body"), 32),
+	}
+	domain := bytesutil.PadTo([]byte("domain"), 32)
+	root, err := ComputeSigningRoot(header, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headerRoot, err := ssz.HashTreeRoot(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ssz.HashTreeRoot(&ethereum_beacon_p2p_v1.SigningRoot{ObjectRoot: headerRoot[:], Domain: domain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != want {
+		t.Errorf("Wanted signing root %#x, received %#x", want, root)
+	}
+}
+
+func TestSigningRoot_UnsupportedType(t *testing.T) {
+	if _, err := ComputeSigningRoot(map[string]int{"a": 1}, make([]byte, 32)); err == nil {
+		t.Error("Expected error for an object which cannot be hashed")
+	}
+}
+
 func TestComputeDomain_OK(t *testing.T) {
 	tests := []struct {
 		epoch      uint64
@@ -38,6 +103,72 @@ func TestComputeDomain_OK(t *testing.T) {
 	}
 }
 
+func TestComputeDomain_Cached(t *testing.T) {
+	domainType := [4]byte{7, 0, 0, 0}
+	version := []byte{'A', 'B', 'C', 'D'}
+	genesisValidatorsRoot := [32]byte{'i', 'o', 'p'}
+	forkDataRoot, err := ComputeForkDataRoot(version, genesisValidatorsRoot[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain(domainType, forkDataRoot[:])
+
+	for i := 0; i < 2; i++ {
+		d, err := ComputeDomain(domainType, version, genesisValidatorsRoot[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(d, want) {
+			t.Errorf("wanted domain %#x, got %#x", want, d)
+		}
+		// Modifying the returned domain does not modify the cached domain.
+		d[0] = 0
+	}
+
+	other, err := ComputeDomain(domainType, []byte{'i', 'm', 'n', 'a'}, genesisValidatorsRoot[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(other, want) {
+		t.Error("Expected domain of another fork version to differ")
+	}
+}
+
+func TestComputeSigningRoots_OK(t *testing.T) {
+	domain := []byte("domain")
+	var objects []interface{}
+	for i := uint64(0); i < 100; i++ {
+		objects = append(objects, &ethpb.AttestationData{
+			Slot:            i,
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Epoch: i / 8, Root: make([]byte, 32)},
+		})
+	}
+	objects = append(objects, &ethpb.BeaconBlock{Slot: 5})
+
+	roots, err := ComputeSigningRoots(objects, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != len(objects) {
+		t.Fatalf("Wanted %d roots, received %d", len(objects), len(roots))
+	}
+	for i, obj := range objects {
+		want, err := ComputeSigningRoot(obj, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if roots[i] != want {
+			t.Errorf("Wanted root %#x of object %d, received %#x", want, i, roots[i])
+		}
+	}
+
+	if roots, err := ComputeSigningRoots(nil, domain); err != nil || len(roots) != 0 {
+		t.Errorf("Wanted no roots without objects, received %v, %v", roots, err)
+	}
+}
+
 func TestSigningRoot_Compatibility(t *testing.T) {
 	parRoot := [32]byte{'A'}
 	stateRoot := [32]byte{'B'}
@@ -52,7 +183,7 @@ func TestSigningRoot_Compatibility(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	newRoot, err := signingRoot(func() ([32]byte, error) {
+	newRoot, err := signingRoot(blockRootObserver, func() ([32]byte, error) {
 		return stateutil.BlockRoot(blk)
 	}, params.BeaconConfig().DomainBeaconProposer[:])
 	if err != nil {
@@ -107,3 +238,35 @@ func TestFuzzverifySigningRoot_10000(t *testing.T) {
 		_ = err
 	}
 }
+
+func TestVerifySigningRoot_CachesVerifiedSignatures(t *testing.T) {
+	ClearCache()
+	defer ClearCache()
+	priv := bls.RandKey()
+	pub := priv.PublicKey().Marshal()
+	domain := make([]byte, 32)
+	header := &ethpb.BeaconBlockHeader{Slot: 1}
+	root, err := ComputeSigningRoot(header, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := priv.Sign(root[:]).Marshal()
+	invalidSig := priv.Sign([]byte{'b', 'a', 'd'}).Marshal()
+
+	if err := VerifySigningRoot(header, pub, invalidSig, domain); err != ErrSigFailedToVerify {
+		t.Errorf("Wanted %v, received %v", ErrSigFailedToVerify, err)
+	}
+	if SignatureVerified(root, invalidSig, pub) {
+		t.Error("Invalid signature should not be cached")
+	}
+	if err := VerifySigningRoot(header, pub, sig, domain); err != nil {
+		t.Fatal(err)
+	}
+	if !SignatureVerified(root, sig, pub) {
+		t.Error("Valid signature should be cached")
+	}
+	// The signature is verified for the header and its block alike, which have the same root.
+	if err := VerifyBlockHeaderSigningRoot(header, pub, sig, domain); err != nil {
+		t.Error(err)
+	}
+}
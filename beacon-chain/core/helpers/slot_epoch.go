@@ -7,7 +7,7 @@ import (
 
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 )
 
 // SlotToEpoch returns the epoch number of the input slot.
@@ -99,7 +99,7 @@ func VerifySlotTime(genesisTime uint64, slot uint64, timeTolerance time.Duration
 	if err != nil {
 		return err
 	}
-	currentTime := roughtime.Now()
+	currentTime := slotutil.Now()
 	diff := slotTime.Sub(currentTime)
 
 	if diff > timeTolerance {
@@ -119,7 +119,7 @@ func SlotToTime(genesisTimeSec uint64, slot uint64) (time.Time, error) {
 
 // SlotsSince computes the number of time slots that have occurred since the given timestamp.
 func SlotsSince(time time.Time) uint64 {
-	return uint64(roughtime.Since(time).Seconds()) / params.BeaconConfig().SecondsPerSlot
+	return uint64(slotutil.Since(time).Seconds()) / params.BeaconConfig().SecondsPerSlot
 }
 
 // RoundUpToNearestEpoch rounds up the provided slot value to the nearest epoch.
@@ -0,0 +1,23 @@
+package spectest
+
+// ForkDataRootTestCase --
+type ForkDataRootTestCase struct {
+	CurrentVersion        string `yaml:"current_version"`
+	GenesisValidatorsRoot string `yaml:"genesis_validators_root"`
+	Result                string `yaml:"result"`
+}
+
+// DomainTestCase --
+type DomainTestCase struct {
+	DomainType            string `yaml:"domain_type"`
+	ForkVersion           string `yaml:"fork_version"`
+	GenesisValidatorsRoot string `yaml:"genesis_validators_root"`
+	Result                string `yaml:"result"`
+}
+
+// SigningRootTestCase --
+type SigningRootTestCase struct {
+	ObjectRoot string `yaml:"object_root"`
+	Domain     string `yaml:"domain"`
+	Result     string `yaml:"result"`
+}
@@ -0,0 +1,105 @@
+package spectest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-yaml/yaml"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+)
+
+// objectRoot is an object whose hash tree root is given by the test vector, as the signing root
+// only depends on the root of the signed object.
+type objectRoot [32]byte
+
+func (r objectRoot) HashTreeRoot() ([32]byte, error) {
+	return r, nil
+}
+
+func TestForkDataRoot(t *testing.T) {
+	runHelperTests(t, "compute_fork_data_root", func(t *testing.T, data []byte) {
+		testCase := &ForkDataRootTestCase{}
+		if err := yaml.Unmarshal(data, testCase); err != nil {
+			t.Fatalf("could not unmarshal YAML file into test struct: %v", err)
+		}
+		root, err := helpers.ComputeForkDataRoot(decodeHex(t, testCase.CurrentVersion), decodeHex(t, testCase.GenesisValidatorsRoot))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := decodeHex(t, testCase.Result); !bytes.Equal(root[:], want) {
+			t.Errorf("fork data root: expected %#x, actual %#x", want, root)
+		}
+	})
+}
+
+func TestDomain(t *testing.T) {
+	runHelperTests(t, "compute_domain", func(t *testing.T, data []byte) {
+		testCase := &DomainTestCase{}
+		if err := yaml.Unmarshal(data, testCase); err != nil {
+			t.Fatalf("could not unmarshal YAML file into test struct: %v", err)
+		}
+		domainType := [helpers.DomainByteLength]byte{}
+		copy(domainType[:], decodeHex(t, testCase.DomainType))
+		domain, err := helpers.ComputeDomain(domainType, decodeHex(t, testCase.ForkVersion), decodeHex(t, testCase.GenesisValidatorsRoot))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := decodeHex(t, testCase.Result); !bytes.Equal(domain, want) {
+			t.Errorf("domain: expected %#x, actual %#x", want, domain)
+		}
+	})
+}
+
+func TestSigningRoot(t *testing.T) {
+	runHelperTests(t, "compute_signing_root", func(t *testing.T, data []byte) {
+		testCase := &SigningRootTestCase{}
+		if err := yaml.Unmarshal(data, testCase); err != nil {
+			t.Fatalf("could not unmarshal YAML file into test struct: %v", err)
+		}
+		var object objectRoot
+		copy(object[:], decodeHex(t, testCase.ObjectRoot))
+		root, err := helpers.ComputeSigningRoot(object, decodeHex(t, testCase.Domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := decodeHex(t, testCase.Result); !bytes.Equal(root[:], want) {
+			t.Errorf("signing root: expected %#x, actual %#x", want, root)
+		}
+	})
+}
+
+// runHelperTests runs the data.yaml test case of each folder of the vectors of the handler in
+// testdata. The v0.11.3 spec tests do not ship helper vectors, so these are generated with the
+// helper functions of the pyspec. The vectors do not depend on the config, as every input of the
+// helpers is given.
+func runHelperTests(t *testing.T, handler string, run func(t *testing.T, data []byte)) {
+	folderPath := filepath.Join("testdata", handler)
+	testFolders, err := ioutil.ReadDir(folderPath)
+	if err != nil {
+		t.Fatalf("could not read %s vectors: %v", handler, err)
+	}
+	if len(testFolders) == 0 {
+		t.Fatalf("no %s vectors in %s", handler, folderPath)
+	}
+	for _, folder := range testFolders {
+		t.Run(folder.Name(), func(t *testing.T) {
+			data, err := ioutil.ReadFile(filepath.Join(folderPath, folder.Name(), "data.yaml"))
+			if err != nil {
+				t.Fatalf("could not read YAML tests directory: %v", err)
+			}
+			run(t, data)
+		})
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		t.Fatalf("could not decode %q: %v", s, err)
+	}
+	return b
+}
@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// ComputeWeakSubjectivityPeriod returns the number of epochs within which a node has to
+// sync from a trusted checkpoint, so the safety of the chain decays by at most SAFETY_DECAY
+// percent of the validator set.
+//
+// Spec pseudocode definition:
+//  def compute_weak_subjectivity_period(state: BeaconState) -> uint64:
+//    weak_subjectivity_period = MIN_VALIDATOR_WITHDRAWABILITY_DELAY
+//    validator_count = len(get_active_validator_indices(state, get_current_epoch(state)))
+//    if validator_count >= MIN_PER_EPOCH_CHURN_LIMIT * CHURN_LIMIT_QUOTIENT:
+//        weak_subjectivity_period += SAFETY_DECAY * CHURN_LIMIT_QUOTIENT // (2 * 100)
+//    else:
+//        weak_subjectivity_period += SAFETY_DECAY * validator_count // (2 * 100 * MIN_PER_EPOCH_CHURN_LIMIT)
+//    return weak_subjectivity_period
+func ComputeWeakSubjectivityPeriod(state *stateTrie.BeaconState) (uint64, error) {
+	cfg := params.BeaconConfig()
+	validatorCount, err := ActiveValidatorCount(state, CurrentEpoch(state))
+	if err != nil {
+		return 0, err
+	}
+	period := cfg.MinValidatorWithdrawabilityDelay
+	if validatorCount >= cfg.MinPerEpochChurnLimit*cfg.ChurnLimitQuotient {
+		period += cfg.SafetyDecay * cfg.ChurnLimitQuotient / (2 * 100)
+	} else {
+		period += cfg.SafetyDecay * validatorCount / (2 * 100 * cfg.MinPerEpochChurnLimit)
+	}
+	return period, nil
+}
+
+// LatestWeakSubjectivityEpoch returns the epoch of the latest weak subjectivity checkpoint,
+// the last finalized epoch which is a multiple of the weak subjectivity period.
+//
+// Spec pseudocode definition:
+//  def get_latest_weak_subjectivity_checkpoint_epoch(state: BeaconState) -> Epoch:
+//    finalized_epoch = state.finalized_checkpoint.epoch
+//    weak_subjectivity_period = compute_weak_subjectivity_period(state)
+//    return finalized_epoch - (finalized_epoch % weak_subjectivity_period)
+func LatestWeakSubjectivityEpoch(state *stateTrie.BeaconState) (uint64, error) {
+	period, err := ComputeWeakSubjectivityPeriod(state)
+	if err != nil {
+		return 0, err
+	}
+	finalizedEpoch := state.FinalizedCheckpointEpoch()
+	return finalizedEpoch - finalizedEpoch%period, nil
+}
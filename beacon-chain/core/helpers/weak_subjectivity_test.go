@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestComputeWeakSubjectivityPeriod(t *testing.T) {
+	tests := []struct {
+		validatorCount uint64
+		want           uint64
+	}{
+		{validatorCount: 0, want: 256},
+		{validatorCount: 1000, want: 256 + 12},
+		{validatorCount: 16384, want: 256 + 204},
+	}
+	for _, tt := range tests {
+		validators := make([]*ethpb.Validator, tt.validatorCount)
+		for i := range validators {
+			validators[i] = &ethpb.Validator{ExitEpoch: params.BeaconConfig().FarFutureEpoch}
+		}
+		state, err := beaconstate.InitializeFromProto(&pb.BeaconState{Validators: validators})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ComputeWeakSubjectivityPeriod(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("ComputeWeakSubjectivityPeriod() with %d validators = %d, want %d", tt.validatorCount, got, tt.want)
+		}
+	}
+}
+
+func TestLatestWeakSubjectivityEpoch(t *testing.T) {
+	validators := make([]*ethpb.Validator, 16384)
+	for i := range validators {
+		validators[i] = &ethpb.Validator{ExitEpoch: params.BeaconConfig().FarFutureEpoch}
+	}
+	state, err := beaconstate.InitializeFromProto(&pb.BeaconState{
+		Slot:                1001 * params.BeaconConfig().SlotsPerEpoch,
+		Validators:          validators,
+		FinalizedCheckpoint: &ethpb.Checkpoint{Epoch: 1000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := LatestWeakSubjectivityEpoch(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The period is 460 epochs, so the latest multiple below epoch 1000 is 920.
+	if got != 920 {
+		t.Errorf("LatestWeakSubjectivityEpoch() = %d, want 920", got)
+	}
+}
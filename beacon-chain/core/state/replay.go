@@ -0,0 +1,163 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	b "github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"go.opencensus.io/trace"
+)
+
+// The steps of a replayed state transition.
+const (
+	ReplaySlots            = "slots"
+	ReplayBlockHeader      = "block_header"
+	ReplayRandao           = "randao"
+	ReplayEth1Data         = "eth1_data"
+	ReplayOperationLengths = "operation_lengths"
+	ReplayProposerSlashing = "proposer_slashing"
+	ReplayAttesterSlashing = "attester_slashing"
+	ReplayAttestation      = "attestation"
+	ReplayDeposit          = "deposit"
+	ReplayVoluntaryExit    = "voluntary_exit"
+	ReplayStateRoot        = "state_root"
+)
+
+// ReplayResult is the outcome of a step of a replayed state transition.
+type ReplayResult struct {
+	// Step is the part of the transition, one of the Replay constants.
+	Step string
+	// Index is the position of the operation in its list of the block body, or 0 for the steps
+	// which are not operations.
+	Index int
+	// Err is the reason the step failed, nil if it succeeded.
+	Err error
+	// BalanceDeltas are the balance changes of the validators caused by the step, in Gwei, by
+	// validator index.
+	BalanceDeltas map[uint64]int64
+}
+
+// ReplayBlock reruns the state transition of a block against the state of its parent, one step
+// at a time: the slots up to the block, the block header, the randao reveal, the eth1 data, each
+// of the operations of the block and the state root. Unlike ExecuteStateTransition, it does not
+// stop at the first invalid operation: a failed step leaves the state as it was before the step,
+// so every invalid operation of the block is reported. The replay stops if the slots cannot be
+// processed, as no other step can be checked then.
+//
+// The given state is not modified. The post state is returned along with the results of every
+// step.
+func ReplayBlock(
+	ctx context.Context,
+	state *stateTrie.BeaconState,
+	signed *ethpb.SignedBeaconBlock,
+) ([]*ReplayResult, *stateTrie.BeaconState, error) {
+	if signed == nil || signed.Block == nil || signed.Block.Body == nil {
+		return nil, nil, errors.New("nil block")
+	}
+	ctx, span := trace.StartSpan(ctx, "beacon-chain.state.ReplayBlock")
+	defer span.End()
+
+	r := &replayer{state: state}
+	r.run(ReplaySlots, 0, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+		return ProcessSlots(ctx, st, signed.Block.Slot)
+	})
+	if r.results[0].Err != nil {
+		return r.results, r.state, nil
+	}
+
+	blk := signed.Block
+	body := blk.Body
+	r.run(ReplayBlockHeader, 0, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+		return b.ProcessBlockHeader(st, signed)
+	})
+	r.run(ReplayRandao, 0, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+		return b.ProcessRandao(st, body)
+	})
+	r.run(ReplayEth1Data, 0, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+		return b.ProcessEth1DataInBlock(st, blk)
+	})
+	r.run(ReplayOperationLengths, 0, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+		return st, verifyOperationLengths(st, body)
+	})
+	for i, slashing := range body.ProposerSlashings {
+		r.run(ReplayProposerSlashing, i, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+			return b.ProcessProposerSlashings(ctx, st, &ethpb.BeaconBlockBody{ProposerSlashings: []*ethpb.ProposerSlashing{slashing}})
+		})
+	}
+	for i, slashing := range body.AttesterSlashings {
+		r.run(ReplayAttesterSlashing, i, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+			return b.ProcessAttesterSlashings(ctx, st, &ethpb.BeaconBlockBody{AttesterSlashings: []*ethpb.AttesterSlashing{slashing}})
+		})
+	}
+	for i, att := range body.Attestations {
+		r.run(ReplayAttestation, i, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+			return b.ProcessAttestation(ctx, st, att)
+		})
+	}
+	for i, deposit := range body.Deposits {
+		r.run(ReplayDeposit, i, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+			if deposit == nil || deposit.Data == nil {
+				return nil, errors.New("nil deposit")
+			}
+			// The deposit proof is checked against the deposit index of the state, which is the
+			// index the deposit is expected to have.
+			depositIndex := st.Eth1DepositIndex()
+			st, err := b.ProcessDeposit(st, deposit)
+			return st, errors.Wrapf(err, "deposit index %d", depositIndex)
+		})
+	}
+	for i, exit := range body.VoluntaryExits {
+		r.run(ReplayVoluntaryExit, i, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+			return b.ProcessVoluntaryExits(ctx, st, &ethpb.BeaconBlockBody{VoluntaryExits: []*ethpb.SignedVoluntaryExit{exit}})
+		})
+	}
+	r.run(ReplayStateRoot, 0, func(st *stateTrie.BeaconState) (*stateTrie.BeaconState, error) {
+		root, err := st.HashTreeRoot(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(root[:], blk.StateRoot) {
+			return nil, fmt.Errorf("validate state root failed, wanted: %#x, received: %#x", root[:], blk.StateRoot)
+		}
+		return st, nil
+	})
+	return r.results, r.state, nil
+}
+
+// replayer runs the steps of a replayed state transition on copies of the state, keeping the
+// state of the last successful step.
+type replayer struct {
+	state   *stateTrie.BeaconState
+	results []*ReplayResult
+}
+
+func (r *replayer) run(step string, index int, f func(*stateTrie.BeaconState) (*stateTrie.BeaconState, error)) {
+	result := &ReplayResult{Step: step, Index: index}
+	r.results = append(r.results, result)
+	post, err := f(r.state.Copy())
+	if err != nil {
+		result.Err = err
+		return
+	}
+	result.BalanceDeltas = balanceDeltas(r.state.Balances(), post.Balances())
+	r.state = post
+}
+
+// balanceDeltas returns the changed balances, including the balances of new validators.
+func balanceDeltas(pre []uint64, post []uint64) map[uint64]int64 {
+	deltas := make(map[uint64]int64)
+	for i, balance := range post {
+		var before uint64
+		if i < len(pre) {
+			before = pre[i]
+		}
+		if balance != before {
+			deltas[uint64(i)] = int64(balance) - int64(before)
+		}
+	}
+	return deltas
+}
@@ -0,0 +1,107 @@
+package state_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestReplayBlock_ValidBlock(t *testing.T) {
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 100)
+	conf := testutil.DefaultBlockGenConfig()
+	conf.NumAttestations = 2
+	block, err := testutil.GenerateFullBlock(beaconState, privKeys, conf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := beaconState.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, postState, err := state.ReplayBlock(context.Background(), beaconState, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []string
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("Step %s %d failed: %v", r.Step, r.Index, r.Err)
+		}
+		steps = append(steps, r.Step)
+	}
+	wanted := []string{
+		state.ReplaySlots,
+		state.ReplayBlockHeader,
+		state.ReplayRandao,
+		state.ReplayEth1Data,
+		state.ReplayOperationLengths,
+	}
+	for range block.Block.Body.Attestations {
+		wanted = append(wanted, state.ReplayAttestation)
+	}
+	wanted = append(wanted, state.ReplayStateRoot)
+	if !reflect.DeepEqual(steps, wanted) {
+		t.Errorf("Wanted steps %v, received %v", wanted, steps)
+	}
+	if postState.Slot() != 1 {
+		t.Errorf("Wanted post state at slot 1, received %d", postState.Slot())
+	}
+	// The given state is left untouched.
+	preRoot, err := beaconState.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preRoot != root {
+		t.Error("Replay modified the parent state")
+	}
+}
+
+func TestReplayBlock_ReportsEveryInvalidOperation(t *testing.T) {
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 100)
+	conf := testutil.DefaultBlockGenConfig()
+	conf.NumAttestations = 3
+	block, err := testutil.GenerateFullBlock(beaconState, privKeys, conf, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Break the signatures of the first and the last attestation.
+	atts := block.Block.Body.Attestations
+	last := len(atts) - 1
+	atts[0].Signature = atts[1].Signature
+	atts[last].Signature = atts[1].Signature
+	sig, err := testutil.BlockSignature(beaconState.Copy(), block.Block, privKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Signature = sig.Marshal()
+
+	results, _, err := state.ReplayBlock(context.Background(), beaconState, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := make(map[string][]int)
+	for _, r := range results {
+		if r.Err != nil {
+			failed[r.Step] = append(failed[r.Step], r.Index)
+		}
+	}
+	wanted := map[string][]int{
+		state.ReplayAttestation: {0, last},
+		// The block state root included the broken attestations.
+		state.ReplayStateRoot: {0},
+	}
+	if !reflect.DeepEqual(failed, wanted) {
+		t.Errorf("Wanted failed steps %v, received %v", wanted, failed)
+	}
+}
+
+func TestReplayBlock_NilBlock(t *testing.T) {
+	beaconState, _ := testutil.DeterministicGenesisState(t, 1)
+	if _, _, err := state.ReplayBlock(context.Background(), beaconState, nil); err == nil {
+		t.Error("Expected error for nil block")
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state/interop"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/mathutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
@@ -129,6 +130,66 @@ func ExecuteStateTransitionNoVerifyAttSigs(
 	return state, nil
 }
 
+// ExecuteStateTransitionNoVerifyAnySig defines the procedure for a state transition function.
+// It performs every check of ExecuteStateTransition but the verification of the signatures in
+// the block, which are returned as a signature set instead, so they can be verified as a batch
+// at about the cost of a single signature verification.
+//
+// WARNING: The post state must not be trusted before the returned signature set is verified,
+// for example with blocks.VerifySignatureSet. This method also modifies the passed in state.
+//
+// Spec pseudocode definition:
+//  def state_transition(state: BeaconState, block: BeaconBlock, validate_state_root: bool=False) -> BeaconState:
+//    # Process slots (including those with no blocks) since block
+//    process_slots(state, block.slot)
+//    # Process block
+//    process_block(state, block)
+//    # Validate state root (`validate_state_root == True` in production)
+//    if validate_state_root:
+//        assert block.state_root == hash_tree_root(state)
+//    # Return post-state
+//    return state
+func ExecuteStateTransitionNoVerifyAnySig(
+	ctx context.Context,
+	state *stateTrie.BeaconState,
+	signed *ethpb.SignedBeaconBlock,
+) (*bls.SignatureSet, *stateTrie.BeaconState, error) {
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
+	}
+	if signed == nil || signed.Block == nil {
+		return nil, nil, errors.New("nil block")
+	}
+
+	ctx, span := trace.StartSpan(ctx, "beacon-chain.ChainService.ExecuteStateTransitionNoVerifyAnySig")
+	defer span.End()
+	var err error
+	// Execute per slots transition.
+	state, err = ProcessSlots(ctx, state, signed.Block.Slot)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not process slot")
+	}
+
+	// Execute per block transition.
+	set, state, err := ProcessBlockNoVerifyAnySig(ctx, state, signed)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not process block in slot %d", signed.Block.Slot)
+	}
+
+	interop.WriteBlockToDisk(signed, false)
+	interop.WriteStateToDisk(state)
+
+	postStateRoot, err := state.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(postStateRoot[:], signed.Block.StateRoot) {
+		return nil, nil, fmt.Errorf("validate state root failed, wanted: %#x, received: %#x",
+			postStateRoot[:], signed.Block.StateRoot)
+	}
+	return set, state, nil
+}
+
 // CalculateStateRoot defines the procedure for a state transition function.
 // This does not validate any BLS signatures in a block, it is used for calculating the
 // state root of the state for the block proposer to use.
@@ -337,7 +398,8 @@ func ProcessSlots(ctx context.Context, state *stateTrie.BeaconState, slot uint64
 
 // ProcessBlock creates a new, modified beacon state by applying block operation
 // transformations as defined in the Ethereum Serenity specification, including processing proposer slashings,
-// processing block attestations, and more.
+// processing block attestations, and more. The proposer, randao, slashing, attestation and exit
+// signatures of the block are collected while processing it, and verified together as a batch.
 //
 // Spec pseudocode definition:
 //
@@ -354,6 +416,38 @@ func ProcessBlock(
 	ctx, span := trace.StartSpan(ctx, "beacon-chain.ChainService.state.ProcessBlock")
 	defer span.End()
 
+	set, state, err := ProcessBlockNoVerifyAnySig(ctx, state, signed)
+	if err != nil {
+		traceutil.AnnotateError(span, err)
+		return nil, err
+	}
+	if err := b.VerifySignatureSet(set); err != nil {
+		traceutil.AnnotateError(span, err)
+		return nil, errors.Wrap(err, "could not verify block signatures")
+	}
+
+	return state, nil
+}
+
+// ProcessBlockNoVerifyAttSigs creates a new, modified beacon state by applying block operation
+// transformations as defined in the Ethereum Serenity specification. It does not validate
+// block attestation signatures.
+//
+// Spec pseudocode definition:
+//
+//  def process_block(state: BeaconState, block: BeaconBlock) -> None:
+//    process_block_header(state, block)
+//    process_randao(state, block.body)
+//    process_eth1_data(state, block.body)
+//    process_operations(state, block.body)
+func ProcessBlockNoVerifyAttSigs(
+	ctx context.Context,
+	state *stateTrie.BeaconState,
+	signed *ethpb.SignedBeaconBlock,
+) (*stateTrie.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "beacon-chain.ChainService.state.ProcessBlock")
+	defer span.End()
+
 	state, err := b.ProcessBlockHeader(state, signed)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
@@ -372,7 +466,7 @@ func ProcessBlock(
 		return nil, errors.Wrap(err, "could not process eth1 data")
 	}
 
-	state, err = ProcessOperations(ctx, state, signed.Block.Body)
+	state, err = ProcessOperationsNoVerify(ctx, state, signed.Block.Body)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
 		return nil, errors.Wrap(err, "could not process block operation")
@@ -381,9 +475,12 @@ func ProcessBlock(
 	return state, nil
 }
 
-// ProcessBlockNoVerifyAttSigs creates a new, modified beacon state by applying block operation
-// transformations as defined in the Ethereum Serenity specification. It does not validate
-// block attestation signatures.
+// ProcessBlockNoVerifyAnySig creates a new, modified beacon state by applying block operation
+// transformations as defined in the Ethereum Serenity specification. It does not verify any
+// signature of the block, but returns the set of all of them: the proposer signature, the
+// randao reveal and the signatures of the attestations, slashings and voluntary exits.
+//
+// WARNING: The returned signature set must be verified for the block to be valid.
 //
 // Spec pseudocode definition:
 //
@@ -392,39 +489,49 @@ func ProcessBlock(
 //    process_randao(state, block.body)
 //    process_eth1_data(state, block.body)
 //    process_operations(state, block.body)
-func ProcessBlockNoVerifyAttSigs(
+func ProcessBlockNoVerifyAnySig(
 	ctx context.Context,
 	state *stateTrie.BeaconState,
 	signed *ethpb.SignedBeaconBlock,
-) (*stateTrie.BeaconState, error) {
-	ctx, span := trace.StartSpan(ctx, "beacon-chain.ChainService.state.ProcessBlock")
+) (*bls.SignatureSet, *stateTrie.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "beacon-chain.ChainService.state.ProcessBlockNoVerifyAnySig")
 	defer span.End()
 
-	state, err := b.ProcessBlockHeader(state, signed)
+	state, err := b.ProcessBlockHeaderNoVerify(state, signed.Block)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
-		return nil, errors.Wrap(err, "could not process block header")
+		return nil, nil, errors.Wrap(err, "could not process block header")
+	}
+	blockSet, err := b.BlockSignatureSet(state, signed)
+	if err != nil {
+		traceutil.AnnotateError(span, err)
+		return nil, nil, errors.Wrap(err, "could not retrieve block signature set")
 	}
 
-	state, err = b.ProcessRandao(state, signed.Block.Body)
+	randaoSet, err := b.RandaoSignatureSet(state, signed.Block.Body)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
-		return nil, errors.Wrap(err, "could not verify and process randao")
+		return nil, nil, errors.Wrap(err, "could not retrieve randao signature set")
+	}
+	state, err = b.ProcessRandaoNoVerify(state, signed.Block.Body)
+	if err != nil {
+		traceutil.AnnotateError(span, err)
+		return nil, nil, errors.Wrap(err, "could not process randao")
 	}
 
 	state, err = b.ProcessEth1DataInBlock(state, signed.Block)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
-		return nil, errors.Wrap(err, "could not process eth1 data")
+		return nil, nil, errors.Wrap(err, "could not process eth1 data")
 	}
 
-	state, err = ProcessOperationsNoVerify(ctx, state, signed.Block.Body)
+	operationsSet, state, err := ProcessOperationsNoVerifyAnySig(ctx, state, signed.Block.Body)
 	if err != nil {
 		traceutil.AnnotateError(span, err)
-		return nil, errors.Wrap(err, "could not process block operation")
+		return nil, nil, errors.Wrap(err, "could not process block operation")
 	}
 
-	return state, nil
+	return bls.NewSet().Join(blockSet).Join(randaoSet).Join(operationsSet), state, nil
 }
 
 // ProcessOperations processes the operations in the beacon block and updates beacon state
@@ -544,6 +651,81 @@ func ProcessOperationsNoVerify(
 	return state, nil
 }
 
+// ProcessOperationsNoVerifyAnySig processes the operations in the beacon block and updates
+// beacon state with the operations in block. It performs every check of ProcessOperations but
+// the signature verifications, and returns the signatures of the attestations, slashings and
+// voluntary exits as a set instead. Deposit signatures are still verified on their own, as an
+// invalid deposit signature does not invalidate the block.
+//
+// WARNING: The returned signature set must be verified for the operations to be valid.
+func ProcessOperationsNoVerifyAnySig(
+	ctx context.Context,
+	state *stateTrie.BeaconState,
+	body *ethpb.BeaconBlockBody) (*bls.SignatureSet, *stateTrie.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "beacon-chain.ChainService.state.ProcessOperationsNoVerifyAnySig")
+	defer span.End()
+
+	if err := verifyOperationLengths(state, body); err != nil {
+		return nil, nil, errors.Wrap(err, "could not verify operation lengths")
+	}
+
+	// Signature sets are retrieved once each kind of operation passed its other checks. The
+	// public keys and domains they depend on are not changed by processing the block.
+	set := bls.NewSet()
+	state, err := b.ProcessProposerSlashingsNoVerifySignature(ctx, state, body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not process block proposer slashings")
+	}
+	for idx, slashing := range body.ProposerSlashings {
+		slashingSet, err := b.ProposerSlashingSignatureSet(state, slashing)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not retrieve signature set of proposer slashing %d", idx)
+		}
+		set.Join(slashingSet)
+	}
+
+	state, err = b.ProcessAttesterSlashingsNoVerifySignature(ctx, state, body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not process block attester slashings")
+	}
+	for idx, slashing := range body.AttesterSlashings {
+		slashingSet, err := b.AttesterSlashingSignatureSet(ctx, state, slashing)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not retrieve signature set of attester slashing %d", idx)
+		}
+		set.Join(slashingSet)
+	}
+
+	state, err = b.ProcessAttestationsNoVerify(ctx, state, body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not process block attestations")
+	}
+	attSet, err := b.AttestationSignatureSet(ctx, state, body.Attestations)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not retrieve attestation signature set")
+	}
+	set.Join(attSet)
+
+	state, err = b.ProcessDeposits(ctx, state, body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not process block validator deposits")
+	}
+
+	state, err = b.ProcessVoluntaryExitsNoVerifySignature(state, body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not process validator exits")
+	}
+	for idx, exit := range body.VoluntaryExits {
+		exitSet, err := b.ExitSignatureSet(state, exit)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not retrieve signature set of exit %d", idx)
+		}
+		set.Join(exitSet)
+	}
+
+	return set, state, nil
+}
+
 func verifyOperationLengths(state *stateTrie.BeaconState, body *ethpb.BeaconBlockBody) error {
 	if uint64(len(body.ProposerSlashings)) > params.BeaconConfig().MaxProposerSlashings {
 		return fmt.Errorf(
@@ -633,7 +815,7 @@ func ProcessEpochPrecompute(ctx context.Context, state *stateTrie.BeaconState) (
 		return nil, errors.Wrap(err, "could not process rewards and penalties")
 	}
 
-	state, err = e.ProcessRegistryUpdates(state)
+	state, err = precompute.ProcessRegistryUpdatesPrecompute(state, vp)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process registry updates")
 	}
@@ -643,7 +825,12 @@ func ProcessEpochPrecompute(ctx context.Context, state *stateTrie.BeaconState) (
 		return nil, err
 	}
 
-	state, err = e.ProcessFinalUpdates(state)
+	state, err = precompute.ProcessEffectiveBalanceUpdatesPrecompute(state, vp)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not process effective balance updates")
+	}
+
+	state, err = e.ProcessFinalUpdatesNoEffectiveBalances(state)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not process final updates")
 	}
@@ -1100,3 +1100,102 @@ func TestProcessSlots_LowerSlotAsParentState(t *testing.T) {
 		t.Error("Did not get wanted error")
 	}
 }
+
+func TestExecuteStateTransitionNoVerifyAnySig_SignatureSet(t *testing.T) {
+	ctx := context.Background()
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 100)
+	block, err := testutil.GenerateFullBlock(beaconState, privKeys, testutil.DefaultBlockGenConfig(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, _, err := state.ExecuteStateTransitionNoVerifyAnySig(ctx, beaconState.Copy(), block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The proposer signature, the randao reveal and the signature of each operation.
+	wanted := 2 + len(block.Block.Body.Attestations)
+	if len(set.Signatures) != wanted {
+		t.Errorf("Wanted %d signatures in set, received %d", wanted, len(set.Signatures))
+	}
+	if err := blocks.VerifySignatureSet(set); err != nil {
+		t.Errorf("Expected signature set to verify: %v", err)
+	}
+
+	// An invalid randao reveal is attributed when the batch fails.
+	preState, err := state.ProcessSlots(ctx, beaconState.Copy(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Block.Body.RandaoReveal = privKeys[0].Sign([]byte("not the epoch")).Marshal()
+	sig, err := testutil.BlockSignature(beaconState.Copy(), block.Block, privKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Signature = sig.Marshal()
+	set, _, err = state.ProcessBlockNoVerifyAnySig(ctx, preState, block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := blocks.VerifySignatureSet(set); err == nil || !strings.Contains(err.Error(), "randao") {
+		t.Errorf("Expected invalid randao signature, received %v", err)
+	}
+}
+
+func TestProcessBlock_BatchVerifiesSignatures(t *testing.T) {
+	ctx := context.Background()
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 100)
+	block, err := testutil.GenerateFullBlock(beaconState, privKeys, testutil.DefaultBlockGenConfig(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preState, err := state.ProcessSlots(ctx, beaconState.Copy(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.ProcessBlock(ctx, preState.Copy(), block); err != nil {
+		t.Fatalf("Expected block to be processed: %v", err)
+	}
+
+	// An attestation signature of another message fails the block once verified in the batch.
+	block.Block.Body.Attestations[0].Signature = privKeys[0].Sign([]byte("not the attestation")).Marshal()
+	sig, err := testutil.BlockSignature(beaconState.Copy(), block.Block, privKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Signature = sig.Marshal()
+	want := "could not verify attestation 0 signature"
+	if _, err := state.ProcessBlock(ctx, preState.Copy(), block); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected %s, received %v", want, err)
+	}
+}
+
+func TestProcessBlock_RejectsAttestationWithoutParticipants(t *testing.T) {
+	ctx := context.Background()
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 100)
+	block, err := testutil.GenerateFullBlock(beaconState, privKeys, testutil.DefaultBlockGenConfig(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preState, err := state.ProcessSlots(ctx, beaconState.Copy(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without attesting indices, the aggregate signature is verified against no public key and
+	// the attestation is invalid whatever its signature.
+	att := block.Block.Body.Attestations[0]
+	att.AggregationBits = bitfield.NewBitlist(att.AggregationBits.Len())
+	att.Signature = make([]byte, 96)
+	sig, err := testutil.BlockSignature(beaconState.Copy(), block.Block, privKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Signature = sig.Marshal()
+	want := "attestation has no attesting indices"
+	if _, err := state.ProcessBlock(ctx, preState.Copy(), block); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected %s, received %v", want, err)
+	}
+	if _, _, err := state.ExecuteStateTransitionNoVerifyAnySig(ctx, beaconState.Copy(), block); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected %s from the signature set, received %v", want, err)
+	}
+}
@@ -0,0 +1,496 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	fastssz "github.com/ferranbt/fastssz"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/era"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var dbCommands = &cli.Command{
+	Name:     "db",
+	Category: "db",
+	Usage:    "defines commands for interacting with the beacon node database",
+	Subcommands: []*cli.Command{
+		{
+			Name: "inspect",
+			Description: `prints bucket sizes, key counts, largest values and slot span statistics
+of the beacon node database in the given data directory, for capacity planning and support triage`,
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+			},
+			Action: inspectDB,
+		},
+		{
+			Name: "export-era",
+			Description: `writes the finalized canonical blocks of the beacon node database to era files,
+one per SLOTS_PER_HISTORICAL_ROOT slots, which can be served by a node started with --era-dir`,
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+				flags.EraDirFlag,
+			},
+			Action: exportEra,
+		},
+		{
+			Name: "backup",
+			Description: `writes a consistent snapshot of the beacon node database to the backups directory
+of the database. When a running node holds the database, the backup is requested from its
+/db/backup monitoring endpoint, which requires --enable-backup-webhook`,
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+				flags.MonitoringPortFlag,
+			},
+			Action: backupDB,
+		},
+		{
+			Name:        "restore",
+			Description: `replaces the beacon node database with a backup. The beacon node must be stopped`,
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+				flags.RestoreSourceFileFlag,
+			},
+			Action: restoreDB,
+		},
+		{
+			Name: "dump",
+			Description: `writes the blocks at a slot with their pre and post states, or the state at the slot
+if it has no block, as SSZ files. The files can be shared to report consensus bugs, replayed with
+the pcli state-transition command, or saved to another database with the db import command`,
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+				flags.DumpSlotFlag,
+				flags.DumpOutputDirFlag,
+			},
+			Action: dumpDB,
+		},
+		{
+			Name: "import",
+			Description: `saves SSZ encoded blocks and post block states, like the ones written by the db dump
+command, to the beacon node database. States advanced past the slot of their latest block are
+rejected. The beacon node must be stopped`,
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+				flags.ImportBlockFileFlag,
+				flags.ImportStateFileFlag,
+			},
+			Action: importDB,
+		},
+	},
+}
+
+func openStore(cliCtx *cli.Context) (*kv.Store, error) {
+	dbPath := path.Join(cliCtx.String(cmd.DataDirFlag.Name), "beaconchaindata")
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, errors.Wrapf(err, "could not find beacon database at %s", dbPath)
+	}
+	store, err := kv.NewKVStore(dbPath, cache.NewStateSummaryCache())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open beacon database")
+	}
+	return store, nil
+}
+
+func backupDB(cliCtx *cli.Context) error {
+	store, err := openStore(cliCtx)
+	if errors.Cause(err) == kv.ErrDatabaseInUse {
+		return requestBackup(cliCtx.Int64(flags.MonitoringPortFlag.Name))
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close database")
+		}
+	}()
+	return store.Backup(context.Background())
+}
+
+// requestBackup asks the beacon node running on the database to back it up, as the database
+// cannot be opened by another process.
+func requestBackup(monitoringPort int64) error {
+	url := fmt.Sprintf("http://127.0.0.1:%d/db/backup", monitoringPort)
+	logrus.WithField("url", url).Info("Database is in use, requesting the backup from the running beacon node")
+	client := &http.Client{Timeout: 30 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return errors.Wrap(err, "could not request backup from the beacon node")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close response body")
+		}
+	}()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		logrus.Info("Beacon node wrote the backup to the backups directory of its database")
+		return nil
+	case http.StatusNotFound:
+		return errors.New("the beacon node does not serve backups, restart it with --enable-backup-webhook")
+	default:
+		return fmt.Errorf("beacon node failed to write the backup: %s", resp.Status)
+	}
+}
+
+func restoreDB(cliCtx *cli.Context) error {
+	src := cliCtx.String(flags.RestoreSourceFileFlag.Name)
+	if src == "" {
+		return fmt.Errorf("--%s is required", flags.RestoreSourceFileFlag.Name)
+	}
+	dbPath := path.Join(cliCtx.String(cmd.DataDirFlag.Name), "beaconchaindata")
+	if err := kv.Restore(context.Background(), src, dbPath); err != nil {
+		if err == kv.ErrDatabaseInUse {
+			return errors.Wrap(err, "stop the beacon node before restoring")
+		}
+		return errors.Wrap(err, "could not restore beacon database")
+	}
+	logrus.WithFields(logrus.Fields{
+		"backup":   src,
+		"database": dbPath,
+	}).Info("Restored database")
+	return nil
+}
+
+func dumpDB(cliCtx *cli.Context) error {
+	if !cliCtx.IsSet(flags.DumpSlotFlag.Name) {
+		return fmt.Errorf("--%s is required", flags.DumpSlotFlag.Name)
+	}
+	slot := cliCtx.Uint64(flags.DumpSlotFlag.Name)
+	outputDir := cliCtx.String(flags.DumpOutputDirFlag.Name)
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return errors.Wrap(err, "could not create output directory")
+	}
+	store, err := openStore(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close database")
+		}
+	}()
+	ctx := context.Background()
+	sg := stategen.New(store, cache.NewStateSummaryCache())
+	if _, err := sg.Resume(ctx); err != nil {
+		return errors.Wrap(err, "could not resume state generator")
+	}
+
+	blks, err := store.Blocks(ctx, filters.NewFilter().SetStartSlot(slot).SetEndSlot(slot))
+	if err != nil {
+		return errors.Wrapf(err, "could not get blocks at slot %d", slot)
+	}
+	if len(blks) == 0 {
+		st, err := sg.StateBySlot(ctx, slot)
+		if err != nil {
+			return errors.Wrapf(err, "could not get state at slot %d", slot)
+		}
+		return writeSSZ(filepath.Join(outputDir, fmt.Sprintf("state_%d.ssz", slot)), st.InnerStateUnsafe())
+	}
+	// Forks can have several blocks at the slot, so the files are named after the block roots.
+	for _, blk := range blks {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return errors.Wrap(err, "could not compute block root")
+		}
+		suffix := fmt.Sprintf("%d_%#x.ssz", slot, bytesutil.Trunc(root[:]))
+		if err := writeSSZ(filepath.Join(outputDir, "block_"+suffix), blk); err != nil {
+			return err
+		}
+		preState, err := dumpStateByRoot(ctx, store, sg, bytesutil.ToBytes32(blk.Block.ParentRoot))
+		if err != nil {
+			return errors.Wrapf(err, "could not get pre state of block %#x", root)
+		}
+		if err := writeSSZ(filepath.Join(outputDir, "pre_state_"+suffix), preState.InnerStateUnsafe()); err != nil {
+			return err
+		}
+		postState, err := dumpStateByRoot(ctx, store, sg, root)
+		if err != nil {
+			return errors.Wrapf(err, "could not get post state of block %#x", root)
+		}
+		if err := writeSSZ(filepath.Join(outputDir, "post_state_"+suffix), postState.InnerStateUnsafe()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpStateByRoot returns the state saved for a block root, or regenerates it from the closest
+// saved ancestor state.
+func dumpStateByRoot(ctx context.Context, store *kv.Store, sg *stategen.State, root [32]byte) (*stateTrie.BeaconState, error) {
+	if store.HasState(ctx, root) {
+		return store.State(ctx, root)
+	}
+	st, err := sg.StateByRoot(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, errors.New("state not found")
+	}
+	return st, nil
+}
+
+func writeSSZ(fileName string, msg fastssz.Marshaler) error {
+	enc, err := msg.MarshalSSZ()
+	if err != nil {
+		return errors.Wrapf(err, "could not encode %s", fileName)
+	}
+	if err := ioutil.WriteFile(fileName, enc, 0600); err != nil {
+		return errors.Wrapf(err, "could not write %s", fileName)
+	}
+	logrus.WithField("file", fileName).Info("Wrote SSZ file")
+	return nil
+}
+
+func importDB(cliCtx *cli.Context) error {
+	blockFile := cliCtx.String(flags.ImportBlockFileFlag.Name)
+	stateFile := cliCtx.String(flags.ImportStateFileFlag.Name)
+	if blockFile == "" && stateFile == "" {
+		return fmt.Errorf("--%s or --%s is required", flags.ImportBlockFileFlag.Name, flags.ImportStateFileFlag.Name)
+	}
+	store, err := openStore(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close database")
+		}
+	}()
+	ctx := context.Background()
+
+	// The block and state are written together, so an interrupted import saves neither.
+	batch := store.NewWriteBatch()
+	var blockFields, stateFields logrus.Fields
+	if blockFile != "" {
+		enc, err := ioutil.ReadFile(blockFile)
+		if err != nil {
+			return errors.Wrap(err, "could not read block file")
+		}
+		blk := &ethpb.SignedBeaconBlock{}
+		if err := blk.UnmarshalSSZ(enc); err != nil {
+			return errors.Wrap(err, "could not unmarshal block")
+		}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return errors.Wrap(err, "could not compute block root")
+		}
+		batch.SaveBlock(blk)
+		blockFields = logrus.Fields{
+			"slot": blk.Block.Slot,
+			"root": fmt.Sprintf("%#x", root),
+		}
+	}
+
+	if stateFile != "" {
+		enc, err := ioutil.ReadFile(stateFile)
+		if err != nil {
+			return errors.Wrap(err, "could not read state file")
+		}
+		protoState := &pb.BeaconState{}
+		if err := protoState.UnmarshalSSZ(enc); err != nil {
+			return errors.Wrap(err, "could not unmarshal state")
+		}
+		st, err := stateTrie.InitializeFromProtoUnsafe(protoState)
+		if err != nil {
+			return errors.Wrap(err, "could not initialize state")
+		}
+		// States are keyed by the root of their latest block, so only post block states, at the
+		// slot of their latest block, can be saved. A state advanced through empty slots would be
+		// loaded as the post state of the block.
+		header := st.LatestBlockHeader()
+		if st.Slot() != header.Slot {
+			return fmt.Errorf("state at slot %d is not the post state of its latest block at slot %d", st.Slot(), header.Slot)
+		}
+		// The state root of the latest block header is only filled in by the slot processing
+		// after the block, so it is the root of the state itself for a post block state.
+		if bytes.Equal(header.StateRoot, params.BeaconConfig().ZeroHash[:]) {
+			stateRoot, err := st.HashTreeRoot(ctx)
+			if err != nil {
+				return errors.Wrap(err, "could not compute state root")
+			}
+			header.StateRoot = stateRoot[:]
+		}
+		root, err := stateutil.BlockHeaderRoot(header)
+		if err != nil {
+			return errors.Wrap(err, "could not compute latest block root")
+		}
+		batch.SaveState(st, root)
+		batch.SaveStateSummary(&pb.StateSummary{Slot: st.Slot(), Root: root[:]})
+		stateFields = logrus.Fields{
+			"slot":      st.Slot(),
+			"blockRoot": fmt.Sprintf("%#x", root),
+		}
+	}
+
+	if err := store.WriteBatch(ctx, batch); err != nil {
+		return errors.Wrap(err, "could not save imported objects")
+	}
+	if blockFields != nil {
+		logrus.WithFields(blockFields).Info("Imported block")
+	}
+	if stateFields != nil {
+		logrus.WithFields(stateFields).Info("Imported state")
+	}
+	return nil
+}
+
+func inspectDB(cliCtx *cli.Context) error {
+	store, err := openStore(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close database")
+		}
+	}()
+	report, err := store.Inspect(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "could not inspect beacon database")
+	}
+
+	fmt.Printf("Database: %s (%d bytes)\n\n", report.DatabasePath, report.FileSize)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tKEYS\tKEY BYTES\tVALUE BYTES\tLARGEST VALUE\tLARGEST KEY")
+	for _, b := range report.Buckets {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%#x\n",
+			b.Name, b.KeyCount, b.TotalKeyBytes, b.TotalValueBytes, b.LargestValue, b.LargestValueKey)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	s := report.Spans
+	fmt.Println()
+	fmt.Printf("Block slots:     %d indexed, lowest %d, highest %d\n", s.BlockSlotCount, s.LowestBlockSlot, s.HighestBlockSlot)
+	fmt.Printf("Archived points: %d stored, lowest %d, highest %d, interval %d, last archived index %d\n",
+		s.ArchivedPointCount, s.LowestArchivedPoint, s.HighestArchivedPoint, s.ArchivedPointInterval, s.LastArchivedIndex)
+	return nil
+}
+
+func exportEra(cliCtx *cli.Context) error {
+	eraDir := cliCtx.String(flags.EraDirFlag.Name)
+	if eraDir == "" {
+		return fmt.Errorf("--%s is required", flags.EraDirFlag.Name)
+	}
+	if err := os.MkdirAll(eraDir, 0700); err != nil {
+		return errors.Wrap(err, "could not create era directory")
+	}
+	store, err := openStore(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close database")
+		}
+	}()
+	ctx := context.Background()
+
+	// Walk the canonical chain back from the finalized block, so blocks of abandoned
+	// forks are not exported.
+	cp, err := store.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get finalized checkpoint")
+	}
+	blk, err := store.Block(ctx, bytesutil.ToBytes32(cp.Root))
+	if err != nil {
+		return errors.Wrap(err, "could not get finalized block")
+	}
+	if blk == nil {
+		return errors.New("finalized block not found in database")
+	}
+	var canonical []*ethpb.SignedBeaconBlock
+	for blk != nil {
+		canonical = append(canonical, blk)
+		if blk.Block.Slot == 0 {
+			break
+		}
+		blk, err = store.Block(ctx, bytesutil.ToBytes32(blk.Block.ParentRoot))
+		if err != nil {
+			return errors.Wrap(err, "could not get parent block")
+		}
+	}
+
+	for i, j := 0, len(canonical)-1; i < j; i, j = i+1, j-1 {
+		canonical[i], canonical[j] = canonical[j], canonical[i]
+	}
+
+	// Only whole periods below the finalized slot are exported, so that written files
+	// never need to be updated. Periods before the oldest block in the database, for a
+	// node that did not sync from genesis, are skipped as the archive cannot be complete.
+	period := params.BeaconConfig().SlotsPerHistoricalRoot
+	oldestSlot := canonical[0].Block.Slot
+	finalizedSlot := canonical[len(canonical)-1].Block.Slot
+	exported := 0
+	next := 0
+	for start := uint64(0); start+period <= finalizedSlot; start += period {
+		var blks []*ethpb.SignedBeaconBlock
+		for ; next < len(canonical) && canonical[next].Block.Slot < start+period; next++ {
+			blks = append(blks, canonical[next])
+		}
+		if start < oldestSlot {
+			continue
+		}
+		fileName := filepath.Join(eraDir, fmt.Sprintf("%08d%s", start/period, era.FileExtension))
+		if err := writeEraFile(fileName, start, period, blks); err != nil {
+			return err
+		}
+		exported++
+	}
+	logrus.WithFields(logrus.Fields{
+		"files":         exported,
+		"finalizedSlot": finalizedSlot,
+	}).Info("Exported era files")
+	return nil
+}
+
+func writeEraFile(fileName string, startSlot uint64, count uint64, blks []*ethpb.SignedBeaconBlock) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrap(err, "could not create era file")
+	}
+	bw := bufio.NewWriter(f)
+	w, err := era.NewWriter(bw, startSlot, count)
+	if err != nil {
+		return errors.Wrapf(err, "could not write %s", fileName)
+	}
+	for _, blk := range blks {
+		if err := w.WriteBlock(blk); err != nil {
+			return errors.Wrapf(err, "could not write %s", fileName)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrapf(err, "could not write %s", fileName)
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrapf(err, "could not write %s", fileName)
+	}
+	return f.Close()
+}
@@ -0,0 +1,109 @@
+package era
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// Archive is a directory of era files covering a contiguous slot range.
+type Archive struct {
+	files []*File
+}
+
+// OpenArchive opens all era files in a directory. The files must cover a contiguous
+// range of slots, so that any slot in the range can be served from the archive.
+func OpenArchive(dir string) (*Archive, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read era directory")
+	}
+	a := &Archive{}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), FileExtension) {
+			continue
+		}
+		f, err := Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			closeErr := a.Close()
+			if closeErr != nil {
+				return nil, errors.Wrap(closeErr, err.Error())
+			}
+			return nil, err
+		}
+		a.files = append(a.files, f)
+	}
+	sort.Slice(a.files, func(i, j int) bool {
+		return a.files[i].StartSlot() < a.files[j].StartSlot()
+	})
+	for i := 1; i < len(a.files); i++ {
+		if a.files[i].StartSlot() != a.files[i-1].EndSlot() {
+			err := fmt.Errorf("era files are not contiguous: slot %d follows slot %d", a.files[i].StartSlot(), a.files[i-1].EndSlot())
+			if closeErr := a.Close(); closeErr != nil {
+				return nil, errors.Wrap(closeErr, err.Error())
+			}
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// StartSlot is the first slot covered by the archive.
+func (a *Archive) StartSlot() uint64 {
+	if len(a.files) == 0 {
+		return 0
+	}
+	return a.files[0].StartSlot()
+}
+
+// EndSlot is the slot after the last slot covered by the archive, or 0 if it is empty.
+func (a *Archive) EndSlot() uint64 {
+	if len(a.files) == 0 {
+		return 0
+	}
+	return a.files[len(a.files)-1].EndSlot()
+}
+
+// BlocksByRange returns the blocks at every step-th slot of [startSlot, endSlot],
+// skipping empty slots. The range must lie within the archive.
+func (a *Archive) BlocksByRange(startSlot uint64, endSlot uint64, step uint64) ([]*ethpb.SignedBeaconBlock, error) {
+	if step == 0 {
+		return nil, errors.New("step must be greater than 0")
+	}
+	if startSlot < a.StartSlot() || endSlot >= a.EndSlot() {
+		return nil, fmt.Errorf("range [%d, %d] outside of archive range [%d, %d)", startSlot, endSlot, a.StartSlot(), a.EndSlot())
+	}
+	i := sort.Search(len(a.files), func(i int) bool {
+		return a.files[i].EndSlot() > startSlot
+	})
+	var blks []*ethpb.SignedBeaconBlock
+	for slot := startSlot; slot <= endSlot; slot += step {
+		for a.files[i].EndSlot() <= slot {
+			i++
+		}
+		blk, err := a.files[i].Block(slot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read block at slot %d", slot)
+		}
+		if blk != nil {
+			blks = append(blks, blk)
+		}
+	}
+	return blks, nil
+}
+
+// Close all files of the archive.
+func (a *Archive) Close() error {
+	var firstErr error
+	for _, f := range a.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
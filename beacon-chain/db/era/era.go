@@ -0,0 +1,218 @@
+// Package era implements reading and writing of era archive files, flat files holding the
+// finalized blocks of a contiguous slot range. They allow operators to keep deep block
+// history outside of the key-value store, for example on a cheap object storage mount,
+// while still serving it to peers.
+//
+// An era file is a sequence of entries, each starting with an 8 byte header made of a
+// 2 byte type, a 4 byte little-endian data length and 2 reserved zero bytes:
+//
+//   Version | Block* | SlotIndex
+//
+// Block entries hold snappy compressed SSZ encoded signed beacon blocks. The trailing
+// SlotIndex entry holds the start slot, one little-endian int64 offset per slot from the
+// start of the index entry to the block of that slot (0 for empty slots) and the slot count.
+package era
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// FileExtension of era archive files.
+const FileExtension = ".era"
+
+const headerSize = 8
+
+var (
+	typeVersion   = [2]byte{0x65, 0x32}
+	typeBlock     = [2]byte{0x01, 0x00}
+	typeSlotIndex = [2]byte{0x69, 0x32}
+)
+
+// Writer writes the blocks of a contiguous slot range to an era file.
+type Writer struct {
+	w         io.Writer
+	offset    int64
+	startSlot uint64
+	positions []int64
+}
+
+// NewWriter starts an era file covering count slots from startSlot.
+func NewWriter(w io.Writer, startSlot uint64, count uint64) (*Writer, error) {
+	ew := &Writer{
+		w:         w,
+		startSlot: startSlot,
+		positions: make([]int64, count),
+	}
+	if err := ew.writeEntry(typeVersion, nil); err != nil {
+		return nil, err
+	}
+	return ew, nil
+}
+
+// WriteBlock appends a block to the file. Blocks must be written in increasing slot order.
+func (w *Writer) WriteBlock(blk *ethpb.SignedBeaconBlock) error {
+	if blk == nil || blk.Block == nil {
+		return errors.New("nil block")
+	}
+	slot := blk.Block.Slot
+	if slot < w.startSlot || slot-w.startSlot >= uint64(len(w.positions)) {
+		return fmt.Errorf("block slot %d outside of era file range [%d, %d)", slot, w.startSlot, w.startSlot+uint64(len(w.positions)))
+	}
+	if w.positions[slot-w.startSlot] != 0 {
+		return fmt.Errorf("block for slot %d already written", slot)
+	}
+	enc, err := blk.MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not marshal block")
+	}
+	w.positions[slot-w.startSlot] = w.offset
+	return w.writeEntry(typeBlock, snappy.Encode(nil, enc))
+}
+
+// Close writes the slot index. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	count := uint64(len(w.positions))
+	data := make([]byte, 8+8*count+8)
+	binary.LittleEndian.PutUint64(data, w.startSlot)
+	for i, pos := range w.positions {
+		var rel int64
+		if pos != 0 {
+			rel = pos - w.offset
+		}
+		binary.LittleEndian.PutUint64(data[8+8*i:], uint64(rel))
+	}
+	binary.LittleEndian.PutUint64(data[8+8*count:], count)
+	return w.writeEntry(typeSlotIndex, data)
+}
+
+func (w *Writer) writeEntry(typ [2]byte, data []byte) error {
+	header := make([]byte, headerSize)
+	copy(header, typ[:])
+	binary.LittleEndian.PutUint32(header[2:], uint32(len(data)))
+	if _, err := w.w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	w.offset += int64(headerSize + len(data))
+	return nil
+}
+
+// File is an opened era file.
+type File struct {
+	f           *os.File
+	startSlot   uint64
+	indexOffset int64
+	positions   []int64
+}
+
+// Open an era file and read its slot index.
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	ef, err := readIndex(f)
+	if err != nil {
+		if closeErr := f.Close(); closeErr != nil {
+			return nil, errors.Wrap(closeErr, err.Error())
+		}
+		return nil, errors.Wrapf(err, "could not read slot index of %s", path)
+	}
+	return ef, nil
+}
+
+func readIndex(f *os.File) (*File, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size < headerSize+16 {
+		return nil, errors.New("file too small")
+	}
+	buf := make([]byte, 8)
+	if _, err := f.ReadAt(buf, size-8); err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint64(buf)
+	indexSize := int64(headerSize + 8 + 8*count + 8)
+	if count == 0 || count > uint64(size) || indexSize > size {
+		return nil, fmt.Errorf("invalid slot count %d", count)
+	}
+	index := make([]byte, indexSize)
+	indexOffset := size - indexSize
+	if _, err := f.ReadAt(index, indexOffset); err != nil {
+		return nil, err
+	}
+	if index[0] != typeSlotIndex[0] || index[1] != typeSlotIndex[1] {
+		return nil, errors.New("missing slot index entry")
+	}
+	data := index[headerSize:]
+	ef := &File{
+		f:           f,
+		startSlot:   binary.LittleEndian.Uint64(data),
+		indexOffset: indexOffset,
+		positions:   make([]int64, count),
+	}
+	for i := range ef.positions {
+		ef.positions[i] = int64(binary.LittleEndian.Uint64(data[8+8*i:]))
+	}
+	return ef, nil
+}
+
+// StartSlot is the first slot covered by the file.
+func (f *File) StartSlot() uint64 {
+	return f.startSlot
+}
+
+// EndSlot is the slot after the last slot covered by the file.
+func (f *File) EndSlot() uint64 {
+	return f.startSlot + uint64(len(f.positions))
+}
+
+// Block returns the block at the given slot, or nil if the slot is empty.
+func (f *File) Block(slot uint64) (*ethpb.SignedBeaconBlock, error) {
+	if slot < f.startSlot || slot >= f.EndSlot() {
+		return nil, fmt.Errorf("slot %d outside of era file range [%d, %d)", slot, f.startSlot, f.EndSlot())
+	}
+	rel := f.positions[slot-f.startSlot]
+	if rel == 0 {
+		return nil, nil
+	}
+	pos := f.indexOffset + rel
+	header := make([]byte, headerSize)
+	if _, err := f.f.ReadAt(header, pos); err != nil {
+		return nil, err
+	}
+	if header[0] != typeBlock[0] || header[1] != typeBlock[1] {
+		return nil, fmt.Errorf("entry for slot %d is not a block", slot)
+	}
+	length := binary.LittleEndian.Uint32(header[2:])
+	data := make([]byte, length)
+	if _, err := f.f.ReadAt(data, pos+headerSize); err != nil {
+		return nil, err
+	}
+	enc, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress block")
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := blk.UnmarshalSSZ(enc); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal block")
+	}
+	return blk, nil
+}
+
+// Close the file.
+func (f *File) Close() error {
+	return f.f.Close()
+}
@@ -0,0 +1,137 @@
+package era
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func writeEraFile(t *testing.T, dir string, name string, startSlot uint64, count uint64, slots []uint64) {
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	w, err := NewWriter(f, startSlot, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, slot := range slots {
+		blk := testutil.NewBeaconBlock()
+		blk.Block.Slot = slot
+		blk.Block.Body.RandaoReveal = make([]byte, 96)
+		if err := w.WriteBlock(blk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func slotsOf(blks []*ethpb.SignedBeaconBlock) []uint64 {
+	slots := make([]uint64, len(blks))
+	for i, b := range blks {
+		slots[i] = b.Block.Slot
+	}
+	return slots
+}
+
+func TestArchive_BlocksByRange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "era")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	writeEraFile(t, dir, "test-00000.era", 0, 8, []uint64{0, 1, 3, 4, 7})
+	writeEraFile(t, dir, "test-00001.era", 8, 8, []uint64{8, 10, 15})
+	// Files with other extensions are ignored.
+	if err := ioutil.WriteFile(filepath.Join(dir, "README"), []byte("not an era file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := OpenArchive(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := a.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if a.StartSlot() != 0 || a.EndSlot() != 16 {
+		t.Fatalf("Wanted archive range [0, 16), received [%d, %d)", a.StartSlot(), a.EndSlot())
+	}
+
+	tests := []struct {
+		start, end, step uint64
+		want             []uint64
+	}{
+		{start: 0, end: 15, step: 1, want: []uint64{0, 1, 3, 4, 7, 8, 10, 15}},
+		{start: 3, end: 10, step: 1, want: []uint64{3, 4, 7, 8, 10}},
+		{start: 1, end: 15, step: 3, want: []uint64{1, 4, 7, 10}},
+		{start: 5, end: 6, step: 1, want: []uint64{}},
+	}
+	for _, tt := range tests {
+		blks, err := a.BlocksByRange(tt.start, tt.end, tt.step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := slotsOf(blks)
+		if len(got) != len(tt.want) {
+			t.Errorf("Range [%d, %d] step %d: wanted slots %v, received %v", tt.start, tt.end, tt.step, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Range [%d, %d] step %d: wanted slots %v, received %v", tt.start, tt.end, tt.step, tt.want, got)
+				break
+			}
+		}
+	}
+
+	if _, err := a.BlocksByRange(10, 16, 1); err == nil {
+		t.Error("Expected error for range beyond the archive")
+	}
+}
+
+func TestOpenArchive_NotContiguous(t *testing.T) {
+	dir, err := ioutil.TempDir("", "era")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	writeEraFile(t, dir, "test-00000.era", 0, 8, []uint64{0})
+	writeEraFile(t, dir, "test-00002.era", 16, 8, []uint64{16})
+	if _, err := OpenArchive(dir); err == nil {
+		t.Error("Expected error for non contiguous era files")
+	}
+}
+
+func TestWriter_RejectsOutOfRangeBlock(t *testing.T) {
+	w, err := NewWriter(ioutil.Discard, 8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 16
+	if err := w.WriteBlock(blk); err == nil {
+		t.Error("Expected error for block outside of the era file range")
+	}
+}
@@ -55,6 +55,7 @@ type ReadOnlyDatabase interface {
 	ArchivedCommitteeInfo(ctx context.Context, epoch uint64) (*ethereum_beacon_p2p_v1.ArchivedCommitteeInfo, error)
 	ArchivedBalances(ctx context.Context, epoch uint64) ([]uint64, error)
 	ArchivedValidatorParticipation(ctx context.Context, epoch uint64) (*eth.ValidatorParticipation, error)
+	ArchivedBalanceHistory(ctx context.Context, indices []uint64, endEpoch uint64, f func(epoch uint64, balances []uint64) bool) error
 	ArchivedPointRoot(ctx context.Context, index uint64) [32]byte
 	HasArchivedPoint(ctx context.Context, index uint64) bool
 	LastArchivedIndexRoot(ctx context.Context) [32]byte
@@ -63,6 +64,16 @@ type ReadOnlyDatabase interface {
 	DepositContractAddress(ctx context.Context) ([]byte, error)
 	// Powchain operations.
 	PowchainData(ctx context.Context) (*db.ETH1ChainData, error)
+	// Operation pools persisted across restarts.
+	PendingOperations(ctx context.Context) ([]*eth.ProposerSlashing, []*eth.AttesterSlashing, []*eth.SignedVoluntaryExit, error)
+	PendingAttestations(ctx context.Context) ([]*eth.Attestation, []*eth.Attestation, error)
+	// Finalized validator public key to index mappings.
+	ValidatorIndex(ctx context.Context, publicKey [48]byte) (uint64, bool, error)
+	ValidatorIndicesCount(ctx context.Context) (uint64, error)
+	// Gossip seen before a restart.
+	SeenGossip(ctx context.Context) (map[string]int64, error)
+	// Network the db was created for.
+	NetworkName(ctx context.Context) (string, error)
 }
 
 // NoHeadAccessDatabase -- See github.com/prysmaticlabs/prysm/beacon-chain/db.NoHeadAccessDatabase
@@ -83,6 +94,7 @@ type NoHeadAccessDatabase interface {
 	// State related methods.
 	SaveState(ctx context.Context, state *state.BeaconState, blockRoot [32]byte) error
 	SaveStates(ctx context.Context, states []*state.BeaconState, blockRoots [][32]byte) error
+	SaveStateDiff(ctx context.Context, state *state.BeaconState, blockRoot [32]byte, baseRoot [32]byte) error
 	DeleteState(ctx context.Context, blockRoot [32]byte) error
 	DeleteStates(ctx context.Context, blockRoots [][32]byte) error
 	SaveStateSummary(ctx context.Context, summary *ethereum_beacon_p2p_v1.StateSummary) error
@@ -103,12 +115,30 @@ type NoHeadAccessDatabase interface {
 	SaveArchivedCommitteeInfo(ctx context.Context, epoch uint64, info *ethereum_beacon_p2p_v1.ArchivedCommitteeInfo) error
 	SaveArchivedBalances(ctx context.Context, epoch uint64, balances []uint64) error
 	SaveArchivedValidatorParticipation(ctx context.Context, epoch uint64, part *eth.ValidatorParticipation) error
+	SaveArchivedBalanceDeltas(ctx context.Context, epoch uint64, balances []uint64) error
 	SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte, index uint64) error
 	SaveLastArchivedIndex(ctx context.Context, index uint64) error
 	// Deposit contract related handlers.
 	SaveDepositContractAddress(ctx context.Context, addr common.Address) error
 	// Powchain operations.
 	SavePowchainData(ctx context.Context, data *db.ETH1ChainData) error
+	// Operation pools persisted across restarts.
+	SavePendingOperations(
+		ctx context.Context,
+		proposerSlashings []*eth.ProposerSlashing,
+		attesterSlashings []*eth.AttesterSlashing,
+		exits []*eth.SignedVoluntaryExit,
+	) error
+	SavePendingAttestations(ctx context.Context, aggregated []*eth.Attestation, unaggregated []*eth.Attestation) error
+	// Finalized validator public key to index mappings.
+	SaveValidatorIndices(ctx context.Context, startIndex uint64, publicKeys [][48]byte) error
+	// Gossip seen before a restart.
+	SaveSeenGossip(ctx context.Context, seen map[string]int64) error
+	// Network the db was created for.
+	SaveNetworkName(ctx context.Context, name string) error
+	// Pruning of the history behind the finalized checkpoint.
+	PruneForks(ctx context.Context, startSlot uint64) (int, error)
+	PruneBefore(ctx context.Context, slot uint64) (int, error)
 }
 
 // HeadAccessDatabase -- See github.com/prysmaticlabs/prysm/beacon-chain/db.HeadAccessDatabase
@@ -129,10 +159,31 @@ type Database interface {
 
 	DatabasePath() string
 	ClearDB() error
+	CheckWritable(ctx context.Context) error
 
 	// Backup and restore methods
 	Backup(ctx context.Context) error
 
+	// Batched writes, applied atomically.
+	NewWriteBatch() WriteBatch
+	WriteBatch(ctx context.Context, batch WriteBatch) error
+
 	// HistoricalStatesDeleted verifies historical states exist in DB.
 	HistoricalStatesDeleted(ctx context.Context) error
+
+	// Recovery from a shutdown which did not complete.
+	DirtyShutdown(ctx context.Context) (bool, error)
+	SaveDirtyShutdown(ctx context.Context, dirty bool) error
+	CheckIntegrity(ctx context.Context) error
+	RederiveHead(ctx context.Context) ([32]byte, error)
+}
+
+// WriteBatch collects writes which the database applies atomically with Database.WriteBatch, so
+// that an interrupted write never leaves a block without its state or a head without its block.
+// Batches are created by the database which writes them.
+type WriteBatch interface {
+	SaveBlock(block *eth.SignedBeaconBlock)
+	SaveState(state *state.BeaconState, blockRoot [32]byte)
+	SaveStateSummary(summary *ethereum_beacon_p2p_v1.StateSummary)
+	SaveHeadBlockRoot(blockRoot [32]byte)
 }
@@ -125,3 +125,37 @@ func (e Exporter) SaveBlocks(ctx context.Context, blocks []*eth.SignedBeaconBloc
 
 	return e.db.SaveBlocks(ctx, blocks)
 }
+
+// exportBatch records the blocks of a write batch, which are published when it is written.
+type exportBatch struct {
+	iface.WriteBatch
+	blocks []*eth.SignedBeaconBlock
+}
+
+// SaveBlock adds the block to the batch.
+func (b *exportBatch) SaveBlock(block *eth.SignedBeaconBlock) {
+	b.blocks = append(b.blocks, block)
+	b.WriteBatch.SaveBlock(block)
+}
+
+// NewWriteBatch returns a batch of the wrapped db which records its blocks.
+func (e Exporter) NewWriteBatch() iface.WriteBatch {
+	return &exportBatch{WriteBatch: e.db.NewWriteBatch()}
+}
+
+// WriteBatch publishes the blocks of the batch to the kafka topic for beacon blocks.
+func (e Exporter) WriteBatch(ctx context.Context, batch iface.WriteBatch) error {
+	b, ok := batch.(*exportBatch)
+	if !ok {
+		return e.db.WriteBatch(ctx, batch)
+	}
+	go func() {
+		for _, block := range b.blocks {
+			if err := e.publish(ctx, "beacon_block", block); err != nil {
+				log.WithError(err).Error("Failed to publish block")
+			}
+		}
+	}()
+
+	return e.db.WriteBatch(ctx, b.WriteBatch)
+}
@@ -23,6 +23,31 @@ func (e Exporter) ClearDB() error {
 	return e.db.ClearDB()
 }
 
+// CheckWritable -- passthrough.
+func (e Exporter) CheckWritable(ctx context.Context) error {
+	return e.db.CheckWritable(ctx)
+}
+
+// DirtyShutdown -- passthrough.
+func (e Exporter) DirtyShutdown(ctx context.Context) (bool, error) {
+	return e.db.DirtyShutdown(ctx)
+}
+
+// SaveDirtyShutdown -- passthrough.
+func (e Exporter) SaveDirtyShutdown(ctx context.Context, dirty bool) error {
+	return e.db.SaveDirtyShutdown(ctx, dirty)
+}
+
+// CheckIntegrity -- passthrough.
+func (e Exporter) CheckIntegrity(ctx context.Context) error {
+	return e.db.CheckIntegrity(ctx)
+}
+
+// RederiveHead -- passthrough.
+func (e Exporter) RederiveHead(ctx context.Context) ([32]byte, error) {
+	return e.db.RederiveHead(ctx)
+}
+
 // Backup -- passthrough.
 func (e Exporter) Backup(ctx context.Context) error {
 	return e.db.Backup(ctx)
@@ -223,6 +248,11 @@ func (e Exporter) SaveStates(ctx context.Context, states []*state.BeaconState, b
 	return e.db.SaveStates(ctx, states, blockRoots)
 }
 
+// SaveStateDiff -- passthrough.
+func (e Exporter) SaveStateDiff(ctx context.Context, state *state.BeaconState, blockRoot [32]byte, baseRoot [32]byte) error {
+	return e.db.SaveStateDiff(ctx, state, blockRoot, baseRoot)
+}
+
 // SaveProposerSlashing -- passthrough.
 func (e Exporter) SaveProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error {
 	return e.db.SaveProposerSlashing(ctx, slashing)
@@ -308,6 +338,31 @@ func (e Exporter) SavePowchainData(ctx context.Context, data *db.ETH1ChainData)
 	return e.db.SavePowchainData(ctx, data)
 }
 
+// PendingOperations -- passthrough
+func (e Exporter) PendingOperations(ctx context.Context) ([]*eth.ProposerSlashing, []*eth.AttesterSlashing, []*eth.SignedVoluntaryExit, error) {
+	return e.db.PendingOperations(ctx)
+}
+
+// SavePendingOperations -- passthrough
+func (e Exporter) SavePendingOperations(
+	ctx context.Context,
+	proposerSlashings []*eth.ProposerSlashing,
+	attesterSlashings []*eth.AttesterSlashing,
+	exits []*eth.SignedVoluntaryExit,
+) error {
+	return e.db.SavePendingOperations(ctx, proposerSlashings, attesterSlashings, exits)
+}
+
+// PendingAttestations -- passthrough
+func (e Exporter) PendingAttestations(ctx context.Context) ([]*eth.Attestation, []*eth.Attestation, error) {
+	return e.db.PendingAttestations(ctx)
+}
+
+// SavePendingAttestations -- passthrough
+func (e Exporter) SavePendingAttestations(ctx context.Context, aggregated []*eth.Attestation, unaggregated []*eth.Attestation) error {
+	return e.db.SavePendingAttestations(ctx, aggregated, unaggregated)
+}
+
 // SaveArchivedPointRoot -- passthrough
 func (e Exporter) SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte, index uint64) error {
 	return e.db.SaveArchivedPointRoot(ctx, blockRoot, index)
@@ -362,3 +417,63 @@ func (e Exporter) LastArchivedIndex(ctx context.Context) (uint64, error) {
 func (e Exporter) HistoricalStatesDeleted(ctx context.Context) error {
 	return e.db.HistoricalStatesDeleted(ctx)
 }
+
+// ValidatorIndex -- passthrough
+func (e Exporter) ValidatorIndex(ctx context.Context, publicKey [48]byte) (uint64, bool, error) {
+	return e.db.ValidatorIndex(ctx, publicKey)
+}
+
+// ValidatorIndicesCount -- passthrough
+func (e Exporter) ValidatorIndicesCount(ctx context.Context) (uint64, error) {
+	return e.db.ValidatorIndicesCount(ctx)
+}
+
+// SaveValidatorIndices -- passthrough
+func (e Exporter) SaveValidatorIndices(ctx context.Context, startIndex uint64, publicKeys [][48]byte) error {
+	return e.db.SaveValidatorIndices(ctx, startIndex, publicKeys)
+}
+
+// ArchivedBalanceHistory -- passthrough
+func (e Exporter) ArchivedBalanceHistory(
+	ctx context.Context,
+	indices []uint64,
+	endEpoch uint64,
+	f func(epoch uint64, balances []uint64) bool,
+) error {
+	return e.db.ArchivedBalanceHistory(ctx, indices, endEpoch, f)
+}
+
+// SaveArchivedBalanceDeltas -- passthrough
+func (e Exporter) SaveArchivedBalanceDeltas(ctx context.Context, epoch uint64, balances []uint64) error {
+	return e.db.SaveArchivedBalanceDeltas(ctx, epoch, balances)
+}
+
+// SeenGossip -- passthrough
+func (e Exporter) SeenGossip(ctx context.Context) (map[string]int64, error) {
+	return e.db.SeenGossip(ctx)
+}
+
+// SaveSeenGossip -- passthrough
+func (e Exporter) SaveSeenGossip(ctx context.Context, seen map[string]int64) error {
+	return e.db.SaveSeenGossip(ctx, seen)
+}
+
+// NetworkName -- passthrough
+func (e Exporter) NetworkName(ctx context.Context) (string, error) {
+	return e.db.NetworkName(ctx)
+}
+
+// SaveNetworkName -- passthrough
+func (e Exporter) SaveNetworkName(ctx context.Context, name string) error {
+	return e.db.SaveNetworkName(ctx, name)
+}
+
+// PruneForks -- passthrough
+func (e Exporter) PruneForks(ctx context.Context, startSlot uint64) (int, error) {
+	return e.db.PruneForks(ctx, startSlot)
+}
+
+// PruneBefore -- passthrough
+func (e Exporter) PruneBefore(ctx context.Context, slot uint64) (int, error) {
+	return e.db.PruneBefore(ctx, slot)
+}
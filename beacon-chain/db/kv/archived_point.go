@@ -10,13 +10,19 @@ import (
 )
 
 // SaveArchivedPointRoot saves an archived point root to the DB. This is used for cold state management.
+// The state saved with the root, if any, is compressed as an archived state.
 func (k *Store) SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte, index uint64) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveArchivedPointRoot")
 	defer span.End()
 
 	return k.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
-		return bucket.Put(bytesutil.Uint64ToBytes(index), blockRoot[:])
+		if err := bucket.Put(bytesutil.Uint64ToBytes(index), blockRoot[:]); err != nil {
+			return err
+		}
+		// Archived states are kept as diffs from a base snapshot, which are reconstructed
+		// transparently when the state is read.
+		return compressArchivedState(tx, blockRoot[:])
 	})
 }
 
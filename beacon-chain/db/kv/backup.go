@@ -3,8 +3,10 @@ package kv
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -16,6 +18,9 @@ const backupsDirectoryName = "backups"
 
 // Backup the database to the datadir backup directory.
 // Example for backup at slot 345: $DATADIR/backups/prysm_beacondb_at_slot_0000345.backup
+//
+// The backup is a snapshot of a single read transaction, so it is consistent and can be taken
+// while the node keeps writing to the database.
 func (k *Store) Backup(ctx context.Context) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Backup")
 	defer span.End()
@@ -35,28 +40,98 @@ func (k *Store) Backup(ctx context.Context) error {
 	backupPath := path.Join(backupsDir, fmt.Sprintf("prysm_beacondb_at_slot_%07d.backup", head.Block.Slot))
 	logrus.WithField("prefix", "db").WithField("backup", backupPath).Info("Writing backup database.")
 
-	copyDB, err := bolt.Open(backupPath, 0666, nil)
+	// The snapshot is written to a temporary file first, so an interrupted backup never
+	// leaves a truncated backup behind.
+	tmpPath := backupPath + ".tmp"
+	if err := k.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(tmpPath, 0600)
+	}); err != nil {
+		if rmErr := os.Remove(tmpPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			logrus.WithError(rmErr).Error("Failed to remove incomplete backup")
+		}
+		return errors.Wrap(err, "could not write backup")
+	}
+	return os.Rename(tmpPath, backupPath)
+}
+
+// Restore replaces the database in the directory with a backup created by Backup. The database
+// must not be in use, so the beacon node has to be stopped before restoring. The backup is
+// checked to be a beacon node database before the current database is replaced.
+func Restore(ctx context.Context, backupPath string, dirPath string) error {
+	_, span := trace.StartSpan(ctx, "BeaconDB.Restore")
+	defer span.End()
+
+	backup, err := bolt.Open(backupPath, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		return errors.Wrapf(err, "could not open backup %s", backupPath)
+	}
+	if err := backup.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(blocksBucket) == nil || tx.Bucket(stateBucket) == nil {
+			return errors.New("not a beacon node database")
+		}
+		return nil
+	}); err != nil {
+		if closeErr := backup.Close(); closeErr != nil {
+			logrus.WithError(closeErr).Error("Failed to close backup database")
+		}
+		return errors.Wrapf(err, "invalid backup %s", backupPath)
+	}
+	if err := backup.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dirPath, 0700); err != nil {
+		return err
+	}
+	// Hold the lock of the current database while it is replaced, so a beacon node cannot
+	// start on a partially restored database.
+	datafile := path.Join(dirPath, databaseFileName)
+	if _, err := os.Stat(datafile); err == nil {
+		current, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second})
+		if err != nil {
+			if err == bolt.ErrTimeout {
+				return ErrDatabaseInUse
+			}
+			return errors.Wrap(err, "could not open current database")
+		}
+		defer func() {
+			if err := current.Close(); err != nil {
+				logrus.WithError(err).Error("Failed to close replaced database")
+			}
+		}()
+	}
+
+	tmpPath := datafile + ".restore"
+	if err := copyFile(backupPath, tmpPath); err != nil {
+		if rmErr := os.Remove(tmpPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			logrus.WithError(rmErr).Error("Failed to remove incomplete restore")
+		}
+		return errors.Wrap(err, "could not copy backup")
+	}
+	return os.Rename(tmpPath, datafile)
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer func() {
-		if err := copyDB.Close(); err != nil {
-			logrus.WithError(err).Error("Failed to close destination database")
+		if err := in.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close backup file")
 		}
 	}()
-
-	return k.db.View(func(tx *bolt.Tx) error {
-		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
-			logrus.Debugf("Copying bucket %s\n", name)
-			return copyDB.Update(func(tx2 *bolt.Tx) error {
-				b2, err := tx2.CreateBucketIfNotExists(name)
-				if err != nil {
-					return err
-				}
-				return b.ForEach(func(k []byte, v []byte) error {
-					return b2.Put(k, v)
-				})
-			})
-		})
-	})
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
 }
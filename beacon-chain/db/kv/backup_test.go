@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
 	"path"
@@ -9,6 +10,7 @@ import (
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
+	bolt "go.etcd.io/bbolt"
 )
 
 func TestStore_Backup(t *testing.T) {
@@ -44,3 +46,58 @@ func TestStore_Backup(t *testing.T) {
 		t.Fatal("No backups created.")
 	}
 }
+
+func TestRestore(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	head := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 5000}}
+	if err := db.SaveBlock(ctx, head); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(head.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	backupPath := path.Join(db.databasePath, backupsDirectoryName, "prysm_beacondb_at_slot_0005000.backup")
+
+	// The database of a running node cannot be replaced.
+	if err := Restore(ctx, backupPath, db.databasePath); err != ErrDatabaseInUse {
+		t.Errorf("Wanted %v, received %v", ErrDatabaseInUse, err)
+	}
+	// Only beacon node databases are restored.
+	restoreDir := path.Join(db.databasePath, "restored")
+	if err := Restore(ctx, path.Join(db.databasePath, "missing.backup"), restoreDir); err == nil {
+		t.Error("Wanted error restoring a missing backup")
+	}
+
+	if err := Restore(ctx, backupPath, restoreDir); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := bolt.Open(path.Join(restoreDir, databaseFileName), 0600, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := restored.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := restored.View(func(tx *bolt.Tx) error {
+		if headRoot := tx.Bucket(blocksBucket).Get(headBlockRootKey); !bytes.Equal(headRoot, root[:]) {
+			t.Errorf("Wanted restored head block root %#x, received %#x", root, headRoot)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
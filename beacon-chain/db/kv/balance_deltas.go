@@ -0,0 +1,107 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// SaveArchivedBalanceDeltas archives the per-validator change between the given balances of a
+// finalized epoch and the balances of the previously archived epoch, the first archived epoch
+// storing its balances as changes from zero. Deltas can only be appended, the balances of an
+// epoch at or before the last archived epoch are ignored.
+func (k *Store) SaveArchivedBalanceDeltas(ctx context.Context, epoch uint64, balances []uint64) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveArchivedBalanceDeltas")
+	defer span.End()
+
+	return k.db.Update(func(tx *bolt.Tx) error {
+		metadata := tx.Bucket(chainMetadataBucket)
+		var prevBalances []uint64
+		if enc := metadata.Get(lastArchivedBalancesKey); enc != nil {
+			if epoch <= binary.BigEndian.Uint64(enc[:8]) {
+				return nil
+			}
+			prevBalances = unmarshalBalances(enc[8:])
+		}
+		// Most balances change by small amounts between finalized epochs, so the
+		// deltas are stored as signed varints.
+		deltas := make([]byte, 0, len(balances)*binary.MaxVarintLen32)
+		buf := make([]byte, binary.MaxVarintLen64)
+		for i, bal := range balances {
+			var prev uint64
+			if i < len(prevBalances) {
+				prev = prevBalances[i]
+			}
+			n := binary.PutVarint(buf, int64(bal-prev))
+			deltas = append(deltas, buf[:n]...)
+		}
+		key := balanceDeltasKey(epoch)
+		if err := tx.Bucket(archivedBalanceDeltasBucket).Put(key, deltas); err != nil {
+			return err
+		}
+		return metadata.Put(lastArchivedBalancesKey, append(key, marshalBalances(balances)...))
+	})
+}
+
+// ArchivedBalanceHistory reconstructs the balances of the given validator indices at every
+// archived epoch up to and including endEpoch, calling f with them in ascending epoch order.
+// The balances are accumulated from the archived deltas one epoch at a time, so the history is
+// only decoded as far as f consumes it; f returning false stops the reconstruction. A validator
+// not yet in the registry at an archived epoch has a zero balance. f must not write to the db.
+func (k *Store) ArchivedBalanceHistory(
+	ctx context.Context,
+	indices []uint64,
+	endEpoch uint64,
+	f func(epoch uint64, balances []uint64) bool,
+) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.ArchivedBalanceHistory")
+	defer span.End()
+
+	positions := make(map[uint64][]int, len(indices))
+	var maxIndex uint64
+	for i, idx := range indices {
+		positions[idx] = append(positions[idx], i)
+		if idx > maxIndex {
+			maxIndex = idx
+		}
+	}
+	balances := make([]uint64, len(indices))
+	return k.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(archivedBalanceDeltasBucket).Cursor()
+		for key, enc := c.First(); key != nil; key, enc = c.Next() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			epoch := binary.BigEndian.Uint64(key)
+			if epoch > endEpoch {
+				return nil
+			}
+			for idx := uint64(0); idx <= maxIndex && len(enc) > 0; idx++ {
+				delta, n := binary.Varint(enc)
+				if n <= 0 {
+					return errors.Errorf("could not decode balance delta of validator %d at epoch %d", idx, epoch)
+				}
+				enc = enc[n:]
+				for _, pos := range positions[idx] {
+					balances[pos] += uint64(delta)
+				}
+			}
+			epochBalances := make([]uint64, len(balances))
+			copy(epochBalances, balances)
+			if !f(epoch, epochBalances) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// balanceDeltasKey encodes the epoch big endian, so the deltas bucket is iterated in epoch order.
+func balanceDeltasKey(epoch uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, epoch)
+	return key
+}
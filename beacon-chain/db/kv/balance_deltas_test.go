@@ -0,0 +1,64 @@
+package kv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestStore_ArchivedBalanceHistory(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	history := map[uint64][]uint64{
+		2: {32e9, 32e9},
+		3: {32e9 + 1000, 31e9, 32e9},
+		5: {32e9 + 500, 31e9 + 7, 32e9 + 3, 1e9},
+	}
+	for _, epoch := range []uint64{2, 3, 5} {
+		if err := db.SaveArchivedBalanceDeltas(ctx, epoch, history[epoch]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Deltas are only appended, balances of an already archived epoch are ignored.
+	if err := db.SaveArchivedBalanceDeltas(ctx, 3, []uint64{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	indices := []uint64{2, 0}
+	var epochs []uint64
+	err := db.ArchivedBalanceHistory(ctx, indices, 4, func(epoch uint64, balances []uint64) bool {
+		epochs = append(epochs, epoch)
+		var want []uint64
+		for _, idx := range indices {
+			if idx < uint64(len(history[epoch])) {
+				want = append(want, history[epoch][idx])
+			} else {
+				want = append(want, 0)
+			}
+		}
+		if !reflect.DeepEqual(want, balances) {
+			t.Errorf("Wanted balances %v at epoch %d, received %v", want, epoch, balances)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(epochs, []uint64{2, 3}) {
+		t.Errorf("Wanted history of epochs %v, received %v", []uint64{2, 3}, epochs)
+	}
+
+	// Stopping early does not decode later epochs.
+	epochs = nil
+	err = db.ArchivedBalanceHistory(ctx, []uint64{3}, 5, func(epoch uint64, balances []uint64) bool {
+		epochs = append(epochs, epoch)
+		return false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(epochs, []uint64{2}) {
+		t.Errorf("Wanted history of epochs %v, received %v", []uint64{2}, epochs)
+	}
+}
@@ -0,0 +1,112 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// writeBatch collects the writes of a batch in memory until WriteBatch applies them.
+type writeBatch struct {
+	blocks     []*ethpb.SignedBeaconBlock
+	states     []*state.BeaconState
+	stateRoots [][32]byte
+	summaries  []*pb.StateSummary
+	head       *[32]byte
+}
+
+// SaveBlock adds the block to the batch.
+func (b *writeBatch) SaveBlock(block *ethpb.SignedBeaconBlock) {
+	b.blocks = append(b.blocks, block)
+}
+
+// SaveState adds the state of the block root to the batch.
+func (b *writeBatch) SaveState(st *state.BeaconState, blockRoot [32]byte) {
+	b.states = append(b.states, st)
+	b.stateRoots = append(b.stateRoots, blockRoot)
+}
+
+// SaveStateSummary adds the state summary to the batch.
+func (b *writeBatch) SaveStateSummary(summary *pb.StateSummary) {
+	b.summaries = append(b.summaries, summary)
+}
+
+// SaveHeadBlockRoot sets the head block root written by the batch, which is checked against the
+// states and state summaries of the db and of the batch.
+func (b *writeBatch) SaveHeadBlockRoot(blockRoot [32]byte) {
+	b.head = &blockRoot
+}
+
+// NewWriteBatch returns an empty batch of writes for WriteBatch.
+func (k *Store) NewWriteBatch() iface.WriteBatch {
+	return &writeBatch{}
+}
+
+// WriteBatch writes the blocks, states, state summaries and head block root of the batch in a
+// single transaction, so either all or none of them are saved. The states and summaries are
+// encoded before the transaction, which holds the write lock of the db only to put them.
+func (k *Store) WriteBatch(ctx context.Context, batch iface.WriteBatch) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.WriteBatch")
+	defer span.End()
+	b, ok := batch.(*writeBatch)
+	if !ok {
+		return errors.New("write batch was not created by the db")
+	}
+	stateEncs := make([][]byte, len(b.states))
+	for i, st := range b.states {
+		if st == nil {
+			return errors.New("nil state")
+		}
+		enc, err := encode(st.InnerStateUnsafe())
+		if err != nil {
+			return err
+		}
+		stateEncs[i] = enc
+	}
+	summaryEncs := make([][]byte, len(b.summaries))
+	for i, summary := range b.summaries {
+		enc, err := encode(summary)
+		if err != nil {
+			return err
+		}
+		summaryEncs[i] = enc
+	}
+
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		for _, block := range b.blocks {
+			if err := k.putBlock(ctx, tx, block); err != nil {
+				return err
+			}
+		}
+		for i, st := range b.states {
+			if err := k.putState(ctx, tx, b.stateRoots[i], stateEncs[i], st.Slot()); err != nil {
+				return err
+			}
+		}
+		for i, summary := range b.summaries {
+			if err := tx.Bucket(stateSummaryBucket).Put(summary.Root, summaryEncs[i]); err != nil {
+				return err
+			}
+		}
+		if b.head != nil {
+			return k.putHeadBlockRoot(tx, *b.head)
+		}
+		return nil
+	})
+	if err != nil {
+		// The blocks of a failed batch must not be served from the cache.
+		for _, block := range b.blocks {
+			if root, rootErr := stateutil.BlockRoot(block.Block); rootErr == nil {
+				k.blockCache.Del(string(root[:]))
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStore_WriteBatch(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: make([]byte, 32)}}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := testutil.NewBeaconState()
+	if err := st.SetSlot(1); err != nil {
+		t.Fatal(err)
+	}
+	batch := db.NewWriteBatch()
+	batch.SaveBlock(blk)
+	batch.SaveState(st, root)
+	batch.SaveStateSummary(&pb.StateSummary{Slot: 1, Root: root[:]})
+	// The head is checked against the state of the same batch.
+	batch.SaveHeadBlockRoot(root)
+	if err := db.WriteBatch(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	if !db.HasBlock(ctx, root) || !db.HasState(ctx, root) || !db.HasStateSummary(ctx, root) {
+		t.Error("Expected the block, state and state summary of the batch to be saved")
+	}
+	head, err := db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head == nil || head.Block.Slot != 1 {
+		t.Errorf("Expected the head block of the batch to be saved, received %v", head)
+	}
+
+	// A batch whose head has no state is not written at all.
+	blk = &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 2, ParentRoot: root[:]}}
+	root, err = stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch = db.NewWriteBatch()
+	batch.SaveBlock(blk)
+	batch.SaveHeadBlockRoot(root)
+	if err := db.WriteBatch(ctx, batch); err == nil {
+		t.Fatal("Expected a batch with a head without state to fail")
+	}
+	if db.HasBlock(ctx, root) {
+		t.Error("Expected the block of the failed batch not to be saved")
+	}
+}
@@ -227,30 +227,8 @@ func (k *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBloc
 	defer span.End()
 
 	return k.db.Update(func(tx *bolt.Tx) error {
-		bkt := tx.Bucket(blocksBucket)
 		for _, block := range blocks {
-			if err := k.setBlockSlotBitField(ctx, tx, block.Block.Slot); err != nil {
-				return err
-			}
-			blockRoot, err := stateutil.BlockRoot(block.Block)
-			if err != nil {
-				return err
-			}
-
-			if existingBlock := bkt.Get(blockRoot[:]); existingBlock != nil {
-				continue
-			}
-			enc, err := encode(block)
-			if err != nil {
-				return err
-			}
-			indicesByBucket := createBlockIndicesFromBlock(block.Block)
-			if err := updateValueForIndices(indicesByBucket, blockRoot[:], tx); err != nil {
-				return errors.Wrap(err, "could not update DB indices")
-			}
-			k.blockCache.Set(string(blockRoot[:]), block, int64(len(enc)))
-
-			if err := bkt.Put(blockRoot[:], enc); err != nil {
+			if err := k.putBlock(ctx, tx, block); err != nil {
 				return err
 			}
 		}
@@ -258,27 +236,59 @@ func (k *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBloc
 	})
 }
 
+// putBlock writes the block and its indices in the transaction, unless it is already saved.
+func (k *Store) putBlock(ctx context.Context, tx *bolt.Tx, block *ethpb.SignedBeaconBlock) error {
+	if err := k.setBlockSlotBitField(ctx, tx, block.Block.Slot); err != nil {
+		return err
+	}
+	blockRoot, err := stateutil.BlockRoot(block.Block)
+	if err != nil {
+		return err
+	}
+
+	bkt := tx.Bucket(blocksBucket)
+	if existingBlock := bkt.Get(blockRoot[:]); existingBlock != nil {
+		return nil
+	}
+	enc, err := encode(block)
+	if err != nil {
+		return err
+	}
+	indicesByBucket := createBlockIndicesFromBlock(block.Block)
+	if err := updateValueForIndices(indicesByBucket, blockRoot[:], tx); err != nil {
+		return errors.Wrap(err, "could not update DB indices")
+	}
+	k.blockCache.Set(string(blockRoot[:]), block, int64(len(enc)))
+	return bkt.Put(blockRoot[:], enc)
+}
+
 // SaveHeadBlockRoot to the db.
 func (k *Store) SaveHeadBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveHeadBlockRoot")
 	defer span.End()
 	return k.db.Update(func(tx *bolt.Tx) error {
-		if featureconfig.Get().NewStateMgmt {
-			hasStateSummaryInCache := k.stateSummaryCache.Has(blockRoot)
-			hasStateSummaryInDB := tx.Bucket(stateSummaryBucket).Get(blockRoot[:]) != nil
-			hasStateInDB := tx.Bucket(stateBucket).Get(blockRoot[:]) != nil
-			if !(hasStateInDB || hasStateSummaryInDB || hasStateSummaryInCache) {
-				return errors.New("no state or state summary found with head block root")
-			}
-		} else {
-			if tx.Bucket(stateBucket).Get(blockRoot[:]) == nil {
-				return errors.New("no state found with head block root")
-			}
+		return k.putHeadBlockRoot(tx, blockRoot)
+	})
+}
+
+// putHeadBlockRoot writes the head block root in the transaction, once its state or state summary
+// is saved.
+func (k *Store) putHeadBlockRoot(tx *bolt.Tx, blockRoot [32]byte) error {
+	if featureconfig.Get().NewStateMgmt {
+		hasStateSummaryInCache := k.stateSummaryCache.Has(blockRoot)
+		hasStateSummaryInDB := tx.Bucket(stateSummaryBucket).Get(blockRoot[:]) != nil
+		hasState := hasStateInDB(tx, blockRoot[:])
+		if !(hasState || hasStateSummaryInDB || hasStateSummaryInCache) {
+			return errors.New("no state or state summary found with head block root")
 		}
+	} else {
+		if !hasStateInDB(tx, blockRoot[:]) {
+			return errors.New("no state found with head block root")
+		}
+	}
 
-		bucket := tx.Bucket(blocksBucket)
-		return bucket.Put(headBlockRootKey, blockRoot[:])
-	})
+	bucket := tx.Bucket(blocksBucket)
+	return bucket.Put(headBlockRootKey, blockRoot[:])
 }
 
 // GenesisBlock retrieves the genesis block of the beacon chain.
@@ -68,15 +68,15 @@ func (k *Store) SaveJustifiedCheckpoint(ctx context.Context, checkpoint *ethpb.C
 		if featureconfig.Get().NewStateMgmt {
 			hasStateSummaryInDB := tx.Bucket(stateSummaryBucket).Get(checkpoint.Root) != nil
 			hasStateSummaryInCache := k.stateSummaryCache.Has(bytesutil.ToBytes32(checkpoint.Root))
-			hasStateInDB := tx.Bucket(stateBucket).Get(checkpoint.Root) != nil
-			if !(hasStateInDB || hasStateSummaryInDB || hasStateSummaryInCache) {
+			hasState := hasStateInDB(tx, checkpoint.Root)
+			if !(hasState || hasStateSummaryInDB || hasStateSummaryInCache) {
 				return errors.New("missing state summary for finalized root")
 			}
 		} else {
 			// The corresponding state must exist or there is a risk that the beacondb enters a state
 			// where the justified beaconState is missing. This may be a fatal condition requiring
 			// a new sync from genesis.
-			if tx.Bucket(stateBucket).Get(checkpoint.Root) == nil {
+			if !hasStateInDB(tx, checkpoint.Root) {
 				traceutil.AnnotateError(span, errMissingStateForCheckpoint)
 				return errMissingStateForCheckpoint
 			}
@@ -99,15 +99,15 @@ func (k *Store) SaveFinalizedCheckpoint(ctx context.Context, checkpoint *ethpb.C
 		if featureconfig.Get().NewStateMgmt {
 			hasStateSummaryInDB := tx.Bucket(stateSummaryBucket).Get(checkpoint.Root) != nil
 			hasStateSummaryInCache := k.stateSummaryCache.Has(bytesutil.ToBytes32(checkpoint.Root))
-			hasStateInDB := tx.Bucket(stateBucket).Get(checkpoint.Root) != nil
-			if !(hasStateInDB || hasStateSummaryInDB || hasStateSummaryInCache) {
+			hasState := hasStateInDB(tx, checkpoint.Root)
+			if !(hasState || hasStateSummaryInDB || hasStateSummaryInCache) {
 				return errors.New("missing state summary for finalized root")
 			}
 		} else {
 			// The corresponding state must exist or there is a risk that the beacondb enters a state
 			// where the finalized beaconState is missing. This would be a fatal condition requiring
 			// a new sync from genesis.
-			if tx.Bucket(stateBucket).Get(checkpoint.Root) == nil {
+			if !hasStateInDB(tx, checkpoint.Root) {
 				traceutil.AnnotateError(span, errMissingStateForCheckpoint)
 				return errMissingStateForCheckpoint
 			}
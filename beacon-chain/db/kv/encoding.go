@@ -9,16 +9,29 @@ import (
 	"github.com/golang/snappy"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bufutil"
 )
 
 func decode(data []byte, dst proto.Message) error {
+	if isWhitelisted(dst) {
+		// The generated SSZ code copies what it keeps, so the decompressed bytes can be
+		// decoded from a pooled buffer.
+		size, err := snappy.DecodedLen(data)
+		if err != nil {
+			return err
+		}
+		buf := bufutil.Get(size)
+		defer bufutil.Put(buf)
+		data, err = snappy.Decode((*buf)[:size], data)
+		if err != nil {
+			return err
+		}
+		return dst.(fastssz.Unmarshaler).UnmarshalSSZ(data)
+	}
 	data, err := snappy.Decode(nil, data)
 	if err != nil {
 		return err
 	}
-	if isWhitelisted(dst) {
-		return dst.(fastssz.Unmarshaler).UnmarshalSSZ(data)
-	}
 	return proto.Unmarshal(data, dst)
 }
 
@@ -26,18 +39,21 @@ func encode(msg proto.Message) ([]byte, error) {
 	if msg == nil || reflect.ValueOf(msg).IsNil() {
 		return nil, errors.New("cannot encode nil message")
 	}
-	var enc []byte
-	var err error
 	if isWhitelisted(msg) {
-		enc, err = msg.(fastssz.Marshaler).MarshalSSZ()
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		enc, err = proto.Marshal(msg)
+		// Only the compressed encoding is kept, the SSZ encoding is written to a pooled buffer.
+		m := msg.(fastssz.Marshaler)
+		buf := bufutil.Get(m.SizeSSZ())
+		defer bufutil.Put(buf)
+		enc, err := m.MarshalSSZTo(*buf)
 		if err != nil {
 			return nil, err
 		}
+		*buf = enc
+		return snappy.Encode(nil, enc), nil
+	}
+	enc, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
 	}
 	return snappy.Encode(nil, enc), nil
 }
@@ -0,0 +1,147 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"sort"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// BucketStats summarizes the contents of a single bolt bucket.
+type BucketStats struct {
+	Name            string
+	KeyCount        uint64
+	TotalKeyBytes   uint64
+	TotalValueBytes uint64
+	LargestValueKey []byte
+	LargestValue    uint64
+}
+
+// SpanStats summarizes the slot and archived point ranges covered by the database.
+type SpanStats struct {
+	BlockSlotCount        uint64
+	LowestBlockSlot       uint64
+	HighestBlockSlot      uint64
+	ArchivedPointCount    uint64
+	LowestArchivedPoint   uint64
+	HighestArchivedPoint  uint64
+	LastArchivedIndex     uint64
+	ArchivedPointInterval uint64
+}
+
+// InspectionReport is the result of inspecting the beacon database for capacity
+// planning and support triage.
+type InspectionReport struct {
+	DatabasePath string
+	FileSize     int64
+	Buckets      []*BucketStats
+	Spans        *SpanStats
+}
+
+// Inspect walks every bucket of the database in a single read transaction and
+// reports key counts, sizes, the largest stored value per bucket and the slot spans
+// covered by blocks and archived points. Buckets are sorted by total value size, descending.
+func (k *Store) Inspect(ctx context.Context) (*InspectionReport, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Inspect")
+	defer span.End()
+
+	report := &InspectionReport{
+		DatabasePath: k.databasePath,
+		Buckets:      make([]*BucketStats, 0),
+		Spans:        &SpanStats{},
+	}
+	err := k.db.View(func(tx *bolt.Tx) error {
+		report.FileSize = tx.Size()
+		if err := tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			stats := &BucketStats{Name: string(name)}
+			if err := b.ForEach(func(k, v []byte) error {
+				stats.KeyCount++
+				stats.TotalKeyBytes += uint64(len(k))
+				stats.TotalValueBytes += uint64(len(v))
+				if uint64(len(v)) > stats.LargestValue {
+					stats.LargestValue = uint64(len(v))
+					stats.LargestValueKey = append([]byte{}, k...)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			report.Buckets = append(report.Buckets, stats)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := blockSlotSpan(tx.Bucket(blockSlotIndicesBucket), report.Spans); err != nil {
+			return err
+		}
+		archivedPointSpan(tx.Bucket(archivedIndexRootBucket), report.Spans)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(report.Buckets, func(i, j int) bool {
+		return report.Buckets[i].TotalValueBytes > report.Buckets[j].TotalValueBytes
+	})
+	return report, nil
+}
+
+// blockSlotSpan relies on the slot indices being stored as left-padded decimal strings,
+// so the first and last cursor positions are the lowest and highest slots.
+func blockSlotSpan(bkt *bolt.Bucket, spans *SpanStats) error {
+	if bkt == nil {
+		return nil
+	}
+	spans.BlockSlotCount = uint64(bkt.Stats().KeyN)
+	c := bkt.Cursor()
+	first, _ := c.First()
+	if first == nil {
+		return nil
+	}
+	last, _ := c.Last()
+	lowest, err := strconv.ParseUint(string(first), 10, 64)
+	if err != nil {
+		return err
+	}
+	highest, err := strconv.ParseUint(string(last), 10, 64)
+	if err != nil {
+		return err
+	}
+	spans.LowestBlockSlot = lowest
+	spans.HighestBlockSlot = highest
+	return nil
+}
+
+// archivedPointSpan scans the archived index bucket. Indices are stored little endian,
+// so cursor order does not match numeric order and the whole bucket is walked.
+func archivedPointSpan(bkt *bolt.Bucket, spans *SpanStats) {
+	if bkt == nil {
+		return
+	}
+	if b := bkt.Get(lastArchivedIndexKey); b != nil {
+		spans.LastArchivedIndex = binary.LittleEndian.Uint64(b)
+	}
+	indices := make([]uint64, 0)
+	c := bkt.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		if len(k) != 8 {
+			continue
+		}
+		indices = append(indices, binary.LittleEndian.Uint64(k))
+	}
+	if len(indices) == 0 {
+		return
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+	spans.ArchivedPointCount = uint64(len(indices))
+	spans.LowestArchivedPoint = indices[0]
+	spans.HighestArchivedPoint = indices[len(indices)-1]
+	if len(indices) > 1 {
+		spans.ArchivedPointInterval = (spans.HighestArchivedPoint - spans.LowestArchivedPoint) / uint64(len(indices)-1)
+	}
+}
@@ -0,0 +1,65 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+func TestStore_Inspect(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	for _, slot := range []uint64{3, 100, 42} {
+		b := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: slot}}
+		if err := db.SaveBlock(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, i := range []uint64{2, 4, 6} {
+		if err := db.SaveArchivedPointRoot(ctx, [32]byte{byte(i)}, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SaveLastArchivedIndex(ctx, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := db.Inspect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blocks *BucketStats
+	for _, b := range report.Buckets {
+		if b.Name == string(blocksBucket) {
+			blocks = b
+		}
+	}
+	if blocks == nil {
+		t.Fatal("Expected blocks bucket in report")
+	}
+	if blocks.KeyCount != 3 {
+		t.Errorf("Wanted 3 blocks, received %d", blocks.KeyCount)
+	}
+	if blocks.LargestValue == 0 || blocks.LargestValueKey == nil {
+		t.Error("Expected largest value to be recorded")
+	}
+
+	spans := report.Spans
+	if spans.LowestBlockSlot != 3 || spans.HighestBlockSlot != 100 {
+		t.Errorf("Unexpected block slot span [%d, %d]", spans.LowestBlockSlot, spans.HighestBlockSlot)
+	}
+	if spans.ArchivedPointCount != 3 {
+		t.Errorf("Wanted 3 archived points, received %d", spans.ArchivedPointCount)
+	}
+	if spans.LowestArchivedPoint != 2 || spans.HighestArchivedPoint != 6 {
+		t.Errorf("Unexpected archived point span [%d, %d]", spans.LowestArchivedPoint, spans.HighestArchivedPoint)
+	}
+	if spans.ArchivedPointInterval != 2 {
+		t.Errorf("Wanted archived point interval 2, received %d", spans.ArchivedPointInterval)
+	}
+	if spans.LastArchivedIndex != 6 {
+		t.Errorf("Wanted last archived index 6, received %d", spans.LastArchivedIndex)
+	}
+}
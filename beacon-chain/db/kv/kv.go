@@ -3,6 +3,7 @@
 package kv
 
 import (
+	"context"
 	"os"
 	"path"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	prombolt "github.com/prysmaticlabs/prombbolt"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -44,6 +46,10 @@ type Store struct {
 	stateSummaryCache   *cache.StateSummaryCache
 }
 
+// ErrDatabaseInUse is returned when the database file is locked by another process, such as a
+// running beacon node.
+var ErrDatabaseInUse = errors.New("cannot obtain database lock, database may be in use by another process")
+
 // NewKVStore initializes a new boltDB key-value store at the directory
 // path specified, creates the kv-buckets based on the schema, and stores
 // an open connection db object as a property of the Store struct.
@@ -55,7 +61,7 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, InitialMmapSize: 10e6})
 	if err != nil {
 		if err == bolt.ErrTimeout {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+			return nil, ErrDatabaseInUse
 		}
 		return nil, err
 	}
@@ -101,10 +107,21 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 			archivedCommitteeInfoBucket,
 			archivedBalancesBucket,
 			archivedValidatorParticipationBucket,
+			archivedBalanceDeltasBucket,
 			powchainBucket,
 			stateSummaryBucket,
 			archivedIndexRootBucket,
 			slotsHasObjectBucket,
+			pendingProposerSlashingsBucket,
+			pendingAttesterSlashingsBucket,
+			pendingVoluntaryExitsBucket,
+			pendingAggregatedAttsBucket,
+			pendingUnaggregatedAttsBucket,
+			validatorIndicesBucket,
+			seenGossipBucket,
+			archivedStateDiffsBucket,
+			archivedStateBasesBucket,
+			hotStateDiffsBucket,
 			// Indices buckets.
 			attestationHeadBlockRootBucket,
 			attestationSourceRootIndicesBucket,
@@ -146,6 +163,14 @@ func (k *Store) DatabasePath() string {
 	return k.databasePath
 }
 
+// CheckWritable writes the current time to the database, failing if it does not accept
+// writes, e.g. because the disk is full or was remounted read-only.
+func (k *Store) CheckWritable(ctx context.Context) error {
+	return k.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(chainMetadataBucket).Put(livenessCheckKey, bytesutil.Bytes8(uint64(time.Now().Unix())))
+	})
+}
+
 func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
 	for _, bucket := range buckets {
 		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
@@ -36,3 +37,10 @@ func setupDB(t testing.TB) *Store {
 	})
 	return db
 }
+
+func TestStore_CheckWritable(t *testing.T) {
+	db := setupDB(t)
+	if err := db.CheckWritable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
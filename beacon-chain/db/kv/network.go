@@ -0,0 +1,30 @@
+package kv
+
+import (
+	"context"
+
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// NetworkName returns the name of the network the db was created for, or an empty string if
+// the node was not started with a network selected.
+func (k *Store) NetworkName(ctx context.Context) (string, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.NetworkName")
+	defer span.End()
+	var name string
+	err := k.db.View(func(tx *bolt.Tx) error {
+		name = string(tx.Bucket(chainMetadataBucket).Get(networkNameKey))
+		return nil
+	})
+	return name, err
+}
+
+// SaveNetworkName records the name of the network the db was created for.
+func (k *Store) SaveNetworkName(ctx context.Context, name string) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveNetworkName")
+	defer span.End()
+	return k.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(chainMetadataBucket).Put(networkNameKey, []byte(name))
+	})
+}
@@ -0,0 +1,29 @@
+package kv
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStore_NetworkName(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	name, err := db.NetworkName(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "" {
+		t.Errorf("Expected no network name in a new db, received %s", name)
+	}
+	if err := db.SaveNetworkName(ctx, "testnet"); err != nil {
+		t.Fatal(err)
+	}
+	name, err = db.NetworkName(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "testnet" {
+		t.Errorf("Wanted network name testnet, received %s", name)
+	}
+}
@@ -0,0 +1,167 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// SavePendingOperations replaces the persisted contents of the operation pools with the given
+// slashings and voluntary exits, so operations waiting for block inclusion survive a restart.
+func (k *Store) SavePendingOperations(
+	ctx context.Context,
+	proposerSlashings []*ethpb.ProposerSlashing,
+	attesterSlashings []*ethpb.AttesterSlashing,
+	exits []*ethpb.SignedVoluntaryExit,
+) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SavePendingOperations")
+	defer span.End()
+
+	proposerMsgs := make([]proto.Message, len(proposerSlashings))
+	for i, s := range proposerSlashings {
+		proposerMsgs[i] = s
+	}
+	attesterMsgs := make([]proto.Message, len(attesterSlashings))
+	for i, s := range attesterSlashings {
+		attesterMsgs[i] = s
+	}
+	exitMsgs := make([]proto.Message, len(exits))
+	for i, e := range exits {
+		exitMsgs[i] = e
+	}
+	return k.db.Update(func(tx *bolt.Tx) error {
+		if err := replaceBucketContents(tx, pendingProposerSlashingsBucket, proposerMsgs); err != nil {
+			return err
+		}
+		if err := replaceBucketContents(tx, pendingAttesterSlashingsBucket, attesterMsgs); err != nil {
+			return err
+		}
+		return replaceBucketContents(tx, pendingVoluntaryExitsBucket, exitMsgs)
+	})
+}
+
+// PendingOperations retrieves the slashings and voluntary exits persisted by SavePendingOperations.
+func (k *Store) PendingOperations(ctx context.Context) (
+	[]*ethpb.ProposerSlashing,
+	[]*ethpb.AttesterSlashing,
+	[]*ethpb.SignedVoluntaryExit,
+	error,
+) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PendingOperations")
+	defer span.End()
+
+	var proposerSlashings []*ethpb.ProposerSlashing
+	var attesterSlashings []*ethpb.AttesterSlashing
+	var exits []*ethpb.SignedVoluntaryExit
+	err := k.db.View(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(pendingProposerSlashingsBucket).ForEach(func(_ []byte, v []byte) error {
+			s := &ethpb.ProposerSlashing{}
+			if err := decode(v, s); err != nil {
+				return err
+			}
+			proposerSlashings = append(proposerSlashings, s)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if err := tx.Bucket(pendingAttesterSlashingsBucket).ForEach(func(_ []byte, v []byte) error {
+			s := &ethpb.AttesterSlashing{}
+			if err := decode(v, s); err != nil {
+				return err
+			}
+			attesterSlashings = append(attesterSlashings, s)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.Bucket(pendingVoluntaryExitsBucket).ForEach(func(_ []byte, v []byte) error {
+			e := &ethpb.SignedVoluntaryExit{}
+			if err := decode(v, e); err != nil {
+				return err
+			}
+			exits = append(exits, e)
+			return nil
+		})
+	})
+	return proposerSlashings, attesterSlashings, exits, err
+}
+
+// SavePendingAttestations replaces the persisted contents of the attestation pools with the given
+// aggregated and unaggregated attestations, so a node restarted shortly before proposing still has
+// attestations to include in its block.
+func (k *Store) SavePendingAttestations(ctx context.Context, aggregated []*ethpb.Attestation, unaggregated []*ethpb.Attestation) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SavePendingAttestations")
+	defer span.End()
+
+	aggregatedMsgs := make([]proto.Message, len(aggregated))
+	for i, att := range aggregated {
+		aggregatedMsgs[i] = att
+	}
+	unaggregatedMsgs := make([]proto.Message, len(unaggregated))
+	for i, att := range unaggregated {
+		unaggregatedMsgs[i] = att
+	}
+	return k.db.Update(func(tx *bolt.Tx) error {
+		if err := replaceBucketContents(tx, pendingAggregatedAttsBucket, aggregatedMsgs); err != nil {
+			return err
+		}
+		return replaceBucketContents(tx, pendingUnaggregatedAttsBucket, unaggregatedMsgs)
+	})
+}
+
+// PendingAttestations retrieves the aggregated and unaggregated attestations persisted by
+// SavePendingAttestations.
+func (k *Store) PendingAttestations(ctx context.Context) ([]*ethpb.Attestation, []*ethpb.Attestation, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PendingAttestations")
+	defer span.End()
+
+	var aggregated []*ethpb.Attestation
+	var unaggregated []*ethpb.Attestation
+	err := k.db.View(func(tx *bolt.Tx) error {
+		var err error
+		if aggregated, err = attestationsInBucket(tx, pendingAggregatedAttsBucket); err != nil {
+			return err
+		}
+		unaggregated, err = attestationsInBucket(tx, pendingUnaggregatedAttsBucket)
+		return err
+	})
+	return aggregated, unaggregated, err
+}
+
+func attestationsInBucket(tx *bolt.Tx, bucketName []byte) ([]*ethpb.Attestation, error) {
+	var atts []*ethpb.Attestation
+	err := tx.Bucket(bucketName).ForEach(func(_ []byte, v []byte) error {
+		att := &ethpb.Attestation{}
+		if err := decode(v, att); err != nil {
+			return err
+		}
+		atts = append(atts, att)
+		return nil
+	})
+	return atts, err
+}
+
+// replaceBucketContents empties a bucket and stores the messages keyed by their position.
+func replaceBucketContents(tx *bolt.Tx, bucketName []byte, msgs []proto.Message) error {
+	if err := tx.DeleteBucket(bucketName); err != nil {
+		return err
+	}
+	bkt, err := tx.CreateBucket(bucketName)
+	if err != nil {
+		return err
+	}
+	for i, msg := range msgs {
+		enc, err := encode(msg)
+		if err != nil {
+			return err
+		}
+		if err := bkt.Put(bytesutil.Bytes8(uint64(i)), enc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestStore_PendingOperations(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	proposerSlashings, attesterSlashings, exits, err := db.PendingOperations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposerSlashings) != 0 || len(attesterSlashings) != 0 || len(exits) != 0 {
+		t.Fatal("Expected no pending operations in a new db")
+	}
+
+	ps := &ethpb.ProposerSlashing{
+		Header_1: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{ProposerIndex: 5},
+			Signature: make([]byte, 96),
+		},
+		Header_2: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{ProposerIndex: 5, Slot: 1},
+			Signature: make([]byte, 96),
+		},
+	}
+	as := &ethpb.AttesterSlashing{
+		Attestation_1: &ethpb.IndexedAttestation{AttestingIndices: []uint64{1, 2}},
+		Attestation_2: &ethpb.IndexedAttestation{AttestingIndices: []uint64{2, 3}},
+	}
+	exit1 := &ethpb.SignedVoluntaryExit{Exit: &ethpb.VoluntaryExit{ValidatorIndex: 7}, Signature: make([]byte, 96)}
+	exit2 := &ethpb.SignedVoluntaryExit{Exit: &ethpb.VoluntaryExit{ValidatorIndex: 8}, Signature: make([]byte, 96)}
+	if err := db.SavePendingOperations(ctx, []*ethpb.ProposerSlashing{ps}, []*ethpb.AttesterSlashing{as}, []*ethpb.SignedVoluntaryExit{exit1, exit2}); err != nil {
+		t.Fatal(err)
+	}
+	proposerSlashings, attesterSlashings, exits, err = db.PendingOperations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposerSlashings) != 1 || !proto.Equal(proposerSlashings[0], ps) {
+		t.Errorf("Wanted proposer slashings %v, received %v", []*ethpb.ProposerSlashing{ps}, proposerSlashings)
+	}
+	if len(attesterSlashings) != 1 || !proto.Equal(attesterSlashings[0], as) {
+		t.Errorf("Wanted attester slashings %v, received %v", []*ethpb.AttesterSlashing{as}, attesterSlashings)
+	}
+	if len(exits) != 2 {
+		t.Errorf("Wanted 2 exits, received %d", len(exits))
+	}
+
+	// Saving again replaces the previous contents.
+	if err := db.SavePendingOperations(ctx, nil, nil, []*ethpb.SignedVoluntaryExit{exit2}); err != nil {
+		t.Fatal(err)
+	}
+	proposerSlashings, attesterSlashings, exits, err = db.PendingOperations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposerSlashings) != 0 || len(attesterSlashings) != 0 {
+		t.Error("Expected slashings to have been replaced")
+	}
+	if len(exits) != 1 || !proto.Equal(exits[0], exit2) {
+		t.Errorf("Wanted exits %v, received %v", []*ethpb.SignedVoluntaryExit{exit2}, exits)
+	}
+}
+
+func TestStore_PendingAttestations(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	aggregated, unaggregated, err := db.PendingAttestations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aggregated) != 0 || len(unaggregated) != 0 {
+		t.Fatal("Expected no pending attestations in a new db")
+	}
+
+	att := func(slot uint64, bits bitfield.Bitlist) *ethpb.Attestation {
+		return &ethpb.Attestation{
+			Data: &ethpb.AttestationData{
+				Slot:            slot,
+				BeaconBlockRoot: make([]byte, 32),
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			},
+			AggregationBits: bits,
+			Signature:       make([]byte, 96),
+		}
+	}
+	agg := att(1, bitfield.Bitlist{0b1011})
+	unagg1 := att(2, bitfield.Bitlist{0b1001})
+	unagg2 := att(3, bitfield.Bitlist{0b1010})
+	if err := db.SavePendingAttestations(ctx, []*ethpb.Attestation{agg}, []*ethpb.Attestation{unagg1, unagg2}); err != nil {
+		t.Fatal(err)
+	}
+	aggregated, unaggregated, err = db.PendingAttestations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aggregated) != 1 || aggregated[0].Data.Slot != 1 || !bytes.Equal(aggregated[0].AggregationBits, agg.AggregationBits) {
+		t.Errorf("Wanted aggregated attestations %v, received %v", []*ethpb.Attestation{agg}, aggregated)
+	}
+	if len(unaggregated) != 2 || unaggregated[0].Data.Slot != 2 || unaggregated[1].Data.Slot != 3 {
+		t.Errorf("Wanted unaggregated attestations %v, received %v", []*ethpb.Attestation{unagg1, unagg2}, unaggregated)
+	}
+
+	// Saving again replaces the previous contents.
+	if err := db.SavePendingAttestations(ctx, nil, []*ethpb.Attestation{unagg2}); err != nil {
+		t.Fatal(err)
+	}
+	aggregated, unaggregated, err = db.PendingAttestations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aggregated) != 0 {
+		t.Error("Expected aggregated attestations to have been replaced")
+	}
+	if len(unaggregated) != 1 || unaggregated[0].Data.Slot != 3 {
+		t.Errorf("Wanted unaggregated attestations %v, received %v", []*ethpb.Attestation{unagg2}, unaggregated)
+	}
+}
@@ -0,0 +1,242 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// errPruneNotFinalized is returned when pruning would delete blocks which are not finalized yet.
+var errPruneNotFinalized = errors.New("cannot prune blocks past the finalized checkpoint")
+
+// PruneForks deletes the blocks from the start slot up to the finalized checkpoint which are not
+// ancestors of the finalized block, i.e. the blocks of the forks which can never become canonical,
+// along with their states and state summaries. It returns the number of deleted blocks.
+func (k *Store) PruneForks(ctx context.Context, startSlot uint64) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PruneForks")
+	defer span.End()
+
+	pruned := 0
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		checkpoint, err := finalizedCheckpoint(tx)
+		if err != nil {
+			return err
+		}
+		finalizedSlot := helpers.StartSlot(checkpoint.Epoch)
+		if finalizedSlot <= startSlot {
+			return nil
+		}
+
+		// Walk the canonical chain back from the finalized block. When an ancestor is missing,
+		// e.g. as the history before it was pruned, the blocks below the lowest canonical block
+		// found cannot be told apart from forks and are kept.
+		canonical := make(map[[32]byte]bool)
+		canonicalSlots := make(map[uint64]bool)
+		bkt := tx.Bucket(blocksBucket)
+		genesisRoot := bkt.Get(genesisBlockRootKey)
+		lowestSlot := finalizedSlot
+		for root := checkpoint.Root; ; {
+			enc := bkt.Get(root)
+			if enc == nil {
+				startSlot = lowestSlot
+				break
+			}
+			block := &ethpb.SignedBeaconBlock{}
+			if err := decode(enc, block); err != nil {
+				return err
+			}
+			if block.Block.Slot < startSlot {
+				break
+			}
+			canonical[bytesutil.ToBytes32(root)] = true
+			canonicalSlots[block.Block.Slot] = true
+			lowestSlot = block.Block.Slot
+			if bytes.Equal(root, genesisRoot) || block.Block.Slot == 0 {
+				break
+			}
+			root = block.Block.ParentRoot
+		}
+		if startSlot >= finalizedSlot {
+			return nil
+		}
+
+		keys, err := getBlockRootsByFilter(ctx, tx, filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(finalizedSlot-1))
+		if err != nil {
+			return err
+		}
+		forks := make(map[[32]byte]bool)
+		for _, key := range keys {
+			if r := bytesutil.ToBytes32(key); !canonical[r] {
+				forks[r] = true
+			}
+		}
+		pruned, err = k.deleteBlocksAndStates(ctx, tx, forks, canonicalSlots)
+		return err
+	})
+	return pruned, err
+}
+
+// PruneBefore deletes the blocks of the slots before the given slot, along with their states,
+// state summaries, archived points and finalized block roots index entries, so that only the
+// recent history is kept. The slot must not be past the finalized checkpoint. The genesis block
+// and state are kept, as are the last archived state and the base snapshots of the archived
+// states which are kept. It returns the number of deleted blocks.
+func (k *Store) PruneBefore(ctx context.Context, slot uint64) (int, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PruneBefore")
+	defer span.End()
+
+	pruned := 0
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		checkpoint, err := finalizedCheckpoint(tx)
+		if err != nil {
+			return err
+		}
+		if slot > helpers.StartSlot(checkpoint.Epoch) {
+			return errPruneNotFinalized
+		}
+		if slot <= 1 {
+			return nil
+		}
+		keys, err := getBlockRootsByFilter(ctx, tx, filters.NewFilter().SetStartSlot(1).SetEndSlot(slot-1))
+		if err != nil {
+			return err
+		}
+		roots := make(map[[32]byte]bool, len(keys))
+		for _, key := range keys {
+			roots[bytesutil.ToBytes32(key)] = true
+		}
+		pruned, err = k.deleteBlocksAndStates(ctx, tx, roots, nil /* keepSlots */)
+		return err
+	})
+	return pruned, err
+}
+
+// deleteBlocksAndStates deletes the blocks of the roots with their states, state summaries,
+// archived points and finalized block roots index entries. The genesis, finalized, head and last
+// archived blocks are never deleted, nor are the base snapshots of archived states which are not
+// deleted. The slots in keepSlots still have other blocks, so they stay marked as saved.
+func (k *Store) deleteBlocksAndStates(ctx context.Context, tx *bolt.Tx, roots map[[32]byte]bool, keepSlots map[uint64]bool) (int, error) {
+	if len(roots) == 0 {
+		return 0, nil
+	}
+	checkpoint, err := finalizedCheckpoint(tx)
+	if err != nil {
+		return 0, err
+	}
+	blocksBkt := tx.Bucket(blocksBucket)
+	archivedBkt := tx.Bucket(archivedIndexRootBucket)
+	protected := map[[32]byte]bool{
+		bytesutil.ToBytes32(blocksBkt.Get(genesisBlockRootKey)): true,
+		bytesutil.ToBytes32(blocksBkt.Get(headBlockRootKey)):    true,
+		bytesutil.ToBytes32(checkpoint.Root):                    true,
+	}
+	if lastIndex := archivedBkt.Get(lastArchivedIndexKey); lastIndex != nil {
+		protected[bytesutil.ToBytes32(archivedBkt.Get(lastIndex))] = true
+	}
+
+	// States stored as diffs from a deleted base keep the base alive.
+	keepBases := make(map[[32]byte]bool)
+	diffsBkt := tx.Bucket(archivedStateDiffsBucket)
+	hotDiffsBkt := tx.Bucket(hotStateDiffsBucket)
+	for _, bkt := range []*bolt.Bucket{diffsBkt, hotDiffsBkt} {
+		if err := bkt.ForEach(func(root, enc []byte) error {
+			if r := bytesutil.ToBytes32(root); !roots[r] || protected[r] {
+				keepBases[bytesutil.ToBytes32(enc[:32])] = true
+			}
+			return nil
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	stateBkt := tx.Bucket(stateBucket)
+	basesBkt := tx.Bucket(archivedStateBasesBucket)
+	summaryBkt := tx.Bucket(stateSummaryBucket)
+	finalizedBkt := tx.Bucket(finalizedBlockRootsIndexBucket)
+	deleted := 0
+	for root := range roots {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if protected[root] {
+			continue
+		}
+		enc := blocksBkt.Get(root[:])
+		if enc == nil {
+			continue
+		}
+		block := &ethpb.SignedBeaconBlock{}
+		if err := decode(enc, block); err != nil {
+			return 0, err
+		}
+		slot := block.Block.Slot
+		if err := deleteValueForIndices(createBlockIndicesFromBlock(block.Block), root[:], tx); err != nil {
+			return 0, errors.Wrap(err, "could not delete root for DB indices")
+		}
+		k.blockCache.Del(string(root[:]))
+		if !keepSlots[slot] {
+			if err := k.clearBlockSlotBitField(ctx, tx, slot); err != nil {
+				return 0, err
+			}
+		}
+		if err := blocksBkt.Delete(root[:]); err != nil {
+			return 0, err
+		}
+		if err := finalizedBkt.Delete(root[:]); err != nil {
+			return 0, err
+		}
+		deleted++
+
+		if keepBases[root] {
+			continue
+		}
+		if hasStateInDB(tx, root[:]) && !keepSlots[slot] {
+			if err := k.clearStateSlotBitField(ctx, tx, slot); err != nil {
+				return 0, err
+			}
+		}
+		for _, bkt := range []*bolt.Bucket{stateBkt, diffsBkt, hotDiffsBkt, basesBkt, summaryBkt} {
+			if err := bkt.Delete(root[:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// Archived points of deleted states can no longer be restored.
+	var indices [][]byte
+	if err := archivedBkt.ForEach(func(index, root []byte) error {
+		if len(root) == 32 && roots[bytesutil.ToBytes32(root)] && !protected[bytesutil.ToBytes32(root)] && !keepBases[bytesutil.ToBytes32(root)] {
+			indices = append(indices, bytesutil.SafeCopyBytes(index))
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	for _, index := range indices {
+		if err := archivedBkt.Delete(index); err != nil {
+			return 0, err
+		}
+	}
+	return deleted, nil
+}
+
+// finalizedCheckpoint returns the finalized checkpoint saved in the transaction, or the genesis
+// block root at epoch 0 if none was saved yet.
+func finalizedCheckpoint(tx *bolt.Tx) (*ethpb.Checkpoint, error) {
+	enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey)
+	if enc == nil {
+		return &ethpb.Checkpoint{Root: tx.Bucket(blocksBucket).Get(genesisBlockRootKey)}, nil
+	}
+	checkpoint := &ethpb.Checkpoint{}
+	if err := decode(enc, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
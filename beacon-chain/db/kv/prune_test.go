@@ -0,0 +1,153 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// savePruneTestChain saves a canonical chain of a block per slot up to the given slot, with a
+// fork block next to each canonical block of the first epoch, and finalizes epoch 1. It returns
+// the canonical and fork block roots by slot.
+func savePruneTestChain(t *testing.T, db *Store, headSlot uint64) (map[uint64][32]byte, map[uint64][32]byte) {
+	ctx := context.Background()
+	canonical := make(map[uint64][32]byte)
+	forks := make(map[uint64][32]byte)
+	st := testutil.NewBeaconState()
+
+	parent := [32]byte{}
+	for slot := uint64(0); slot <= headSlot; slot++ {
+		blk := testutil.NewBeaconBlock()
+		blk.Block.Slot = slot
+		blk.Block.ParentRoot = parent[:]
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveState(ctx, st, root); err != nil {
+			t.Fatal(err)
+		}
+		canonical[slot] = root
+
+		if slot > 0 && slot < params.BeaconConfig().SlotsPerEpoch {
+			fork := testutil.NewBeaconBlock()
+			fork.Block.Slot = slot
+			fork.Block.ParentRoot = parent[:]
+			fork.Block.Body.Graffiti = []byte("fork")
+			forkRoot, err := stateutil.BlockRoot(fork.Block)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.SaveBlock(ctx, fork); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.SaveState(ctx, st, forkRoot); err != nil {
+				t.Fatal(err)
+			}
+			forks[slot] = forkRoot
+		}
+		parent = root
+	}
+
+	if err := db.SaveGenesisBlockRoot(ctx, canonical[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, canonical[headSlot]); err != nil {
+		t.Fatal(err)
+	}
+	finalizedRoot := canonical[params.BeaconConfig().SlotsPerEpoch]
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: finalizedRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	return canonical, forks
+}
+
+func TestStore_PruneForks(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	canonical, forks := savePruneTestChain(t, db, slotsPerEpoch+4)
+
+	pruned, err := db.PruneForks(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != len(forks) {
+		t.Errorf("Wanted %d pruned blocks, received %d", len(forks), pruned)
+	}
+	for slot, root := range forks {
+		if db.HasBlock(ctx, root) {
+			t.Errorf("Wanted fork block at slot %d to be pruned", slot)
+		}
+		if db.HasState(ctx, root) {
+			t.Errorf("Wanted fork state at slot %d to be pruned", slot)
+		}
+	}
+	for slot, root := range canonical {
+		if !db.HasBlock(ctx, root) {
+			t.Errorf("Wanted canonical block at slot %d to be kept", slot)
+		}
+	}
+
+	// The slots of the pruned forks still have their canonical blocks.
+	blks, err := db.HighestSlotBlocksBelow(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blks) == 0 || blks[0].Block.Slot != 1 {
+		t.Errorf("Wanted the highest block below slot 2 to be at slot 1, received %v", blks)
+	}
+
+	pruned, err = db.PruneForks(ctx, slotsPerEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 0 {
+		t.Errorf("Wanted nothing to prune past the finalized checkpoint, received %d", pruned)
+	}
+}
+
+func TestStore_PruneBefore(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	canonical, forks := savePruneTestChain(t, db, slotsPerEpoch+4)
+
+	if _, err := db.PruneBefore(ctx, slotsPerEpoch+1); err != errPruneNotFinalized {
+		t.Errorf("Wanted %v, received %v", errPruneNotFinalized, err)
+	}
+
+	pruned, err := db.PruneBefore(ctx, slotsPerEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int(slotsPerEpoch-1) + len(forks); pruned != want {
+		t.Errorf("Wanted %d pruned blocks, received %d", want, pruned)
+	}
+	for slot := uint64(1); slot < slotsPerEpoch; slot++ {
+		if db.HasBlock(ctx, canonical[slot]) || db.HasBlock(ctx, forks[slot]) {
+			t.Errorf("Wanted blocks at slot %d to be pruned", slot)
+		}
+		if db.HasState(ctx, canonical[slot]) {
+			t.Errorf("Wanted state at slot %d to be pruned", slot)
+		}
+	}
+	for _, slot := range []uint64{0, slotsPerEpoch, slotsPerEpoch + 4} {
+		if !db.HasBlock(ctx, canonical[slot]) {
+			t.Errorf("Wanted block at slot %d to be kept", slot)
+		}
+		if !db.HasState(ctx, canonical[slot]) {
+			t.Errorf("Wanted state at slot %d to be kept", slot)
+		}
+	}
+	if db.IsFinalizedBlock(ctx, canonical[1]) {
+		t.Error("Wanted pruned block to be removed from the finalized index")
+	}
+}
@@ -20,9 +20,20 @@ var (
 	archivedCommitteeInfoBucket          = []byte("archived-committee-info")
 	archivedBalancesBucket               = []byte("archived-balances")
 	archivedValidatorParticipationBucket = []byte("archived-validator-participation")
+	archivedBalanceDeltasBucket          = []byte("archived-balance-deltas")
 	powchainBucket                       = []byte("powchain")
 	archivedIndexRootBucket              = []byte("archived-index-root")
 	slotsHasObjectBucket                 = []byte("slots-has-objects")
+	pendingProposerSlashingsBucket       = []byte("pending-proposer-slashings")
+	pendingAttesterSlashingsBucket       = []byte("pending-attester-slashings")
+	pendingVoluntaryExitsBucket          = []byte("pending-voluntary-exits")
+	pendingAggregatedAttsBucket          = []byte("pending-aggregated-attestations")
+	pendingUnaggregatedAttsBucket        = []byte("pending-unaggregated-attestations")
+	validatorIndicesBucket               = []byte("validator-indices")
+	seenGossipBucket                     = []byte("seen-gossip")
+	archivedStateDiffsBucket             = []byte("archived-state-diffs")
+	archivedStateBasesBucket             = []byte("archived-state-bases")
+	hotStateDiffsBucket                  = []byte("hot-state-diffs")
 
 	// Key indices buckets.
 	blockParentRootIndicesBucket        = []byte("block-parent-root-indices")
@@ -44,6 +55,12 @@ var (
 	lastArchivedIndexKey      = []byte("last-archived")
 	savedBlockSlotsKey        = []byte("saved-block-slots")
 	savedStateSlotsKey        = []byte("saved-state-slots")
+	livenessCheckKey          = []byte("liveness-check")
+	validatorIndicesCountKey  = []byte("validator-indices-count")
+	lastArchivedBalancesKey   = []byte("last-archived-balances")
+	lastArchivedStateBaseKey  = []byte("last-archived-state-base")
+	networkNameKey            = []byte("network-name")
+	dirtyShutdownKey          = []byte("dirty-shutdown")
 
 	// New state management service compatibility bucket.
 	newStateServiceCompatibleBucket = []byte("new-state-compatible")
@@ -0,0 +1,47 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// SaveSeenGossip replaces the persisted seen gossip with the given keys of gossip objects
+// mapped to the unix time they were first seen.
+func (k *Store) SaveSeenGossip(ctx context.Context, seen map[string]int64) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveSeenGossip")
+	defer span.End()
+
+	return k.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(seenGossipBucket); err != nil {
+			return err
+		}
+		bkt, err := tx.CreateBucket(seenGossipBucket)
+		if err != nil {
+			return err
+		}
+		for key, seenAt := range seen {
+			if err := bkt.Put([]byte(key), bytesutil.Bytes8(uint64(seenAt))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SeenGossip retrieves the seen gossip persisted by SaveSeenGossip.
+func (k *Store) SeenGossip(ctx context.Context) (map[string]int64, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SeenGossip")
+	defer span.End()
+
+	seen := make(map[string]int64)
+	err := k.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(seenGossipBucket).ForEach(func(key, enc []byte) error {
+			seen[string(key)] = int64(bytesutil.FromBytes8(enc))
+			return nil
+		})
+	})
+	return seen, err
+}
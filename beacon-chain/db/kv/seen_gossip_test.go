@@ -0,0 +1,45 @@
+package kv
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestStore_SeenGossip(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	seen, err := db.SeenGossip(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 0 {
+		t.Fatalf("Expected no seen gossip in a new db, received %v", seen)
+	}
+
+	want := map[string]int64{"a": 100, "b": 200}
+	if err := db.SaveSeenGossip(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	seen, err = db.SeenGossip(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, seen) {
+		t.Errorf("Wanted seen gossip %v, received %v", want, seen)
+	}
+
+	// Saving replaces the previously persisted seen gossip.
+	want = map[string]int64{"c": 300}
+	if err := db.SaveSeenGossip(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	seen, err = db.SeenGossip(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, seen) {
+		t.Errorf("Wanted seen gossip %v, received %v", want, seen)
+	}
+}
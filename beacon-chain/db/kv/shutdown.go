@@ -0,0 +1,123 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// maxIntegrityErrors is the number of consistency errors reported by CheckIntegrity.
+const maxIntegrityErrors = 10
+
+// DirtyShutdown returns true if the node using the db did not shut down cleanly the last time
+// it ran, e.g. because of a crash or a power loss.
+func (k *Store) DirtyShutdown(ctx context.Context) (bool, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.DirtyShutdown")
+	defer span.End()
+	var dirty bool
+	err := k.db.View(func(tx *bolt.Tx) error {
+		dirty = tx.Bucket(chainMetadataBucket).Get(dirtyShutdownKey) != nil
+		return nil
+	})
+	return dirty, err
+}
+
+// SaveDirtyShutdown marks the db as in use by a running node, so a shutdown which does not clear
+// the mark is detected on the next start.
+func (k *Store) SaveDirtyShutdown(ctx context.Context, dirty bool) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveDirtyShutdown")
+	defer span.End()
+	return k.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(chainMetadataBucket)
+		if !dirty {
+			return bkt.Delete(dirtyShutdownKey)
+		}
+		return bkt.Put(dirtyShutdownKey, []byte{1})
+	})
+}
+
+// CheckIntegrity verifies the consistency of the pages of the db file, returning the first
+// errors found if it is corrupted.
+func (k *Store) CheckIntegrity(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.CheckIntegrity")
+	defer span.End()
+	return k.db.View(func(tx *bolt.Tx) error {
+		var msgs []string
+		for err := range tx.Check() {
+			if len(msgs) < maxIntegrityErrors {
+				msgs = append(msgs, err.Error())
+			}
+		}
+		if len(msgs) > 0 {
+			return fmt.Errorf("database is corrupted: %v", msgs)
+		}
+		return nil
+	})
+}
+
+// RederiveHead verifies that the head block root points to a stored block whose state can be
+// loaded. Otherwise, as after a write interrupted by a crash, the head is set to the latest
+// ancestor of the head block with a state or state summary, or else to the finalized checkpoint.
+// It returns the head block root.
+func (k *Store) RederiveHead(ctx context.Context) ([32]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.RederiveHead")
+	defer span.End()
+	var head []byte
+	err := k.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(blocksBucket)
+		finalized := &ethpb.Checkpoint{Root: bkt.Get(genesisBlockRootKey)}
+		if enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey); enc != nil {
+			if err := decode(enc, finalized); err != nil {
+				return err
+			}
+		}
+		hasState := func(root []byte) bool {
+			return hasStateInDB(tx, root) ||
+				tx.Bucket(stateSummaryBucket).Get(root) != nil ||
+				k.stateSummaryCache.Has(bytesutil.ToBytes32(root))
+		}
+
+		saved := bkt.Get(headBlockRootKey)
+		if saved == nil && finalized.Root == nil {
+			// The chain has not started yet.
+			return nil
+		}
+		for root := saved; root != nil; {
+			enc := bkt.Get(root)
+			if enc == nil {
+				break
+			}
+			if hasState(root) {
+				head = root
+				break
+			}
+			blk := &ethpb.SignedBeaconBlock{}
+			if err := decode(enc, blk); err != nil {
+				return err
+			}
+			if blk.Block.Slot <= helpers.StartSlot(finalized.Epoch) {
+				break
+			}
+			root = blk.Block.ParentRoot
+		}
+		if head == nil {
+			if finalized.Root == nil || bkt.Get(finalized.Root) == nil || !hasState(finalized.Root) {
+				return errors.New("no stored block with a state to derive the head from")
+			}
+			head = finalized.Root
+		}
+		if bytes.Equal(head, saved) {
+			return nil
+		}
+		head = append([]byte{}, head...)
+		return bkt.Put(headBlockRootKey, head)
+	})
+	return bytesutil.ToBytes32(head), err
+}
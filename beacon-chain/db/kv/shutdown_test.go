@@ -0,0 +1,93 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore_DirtyShutdown(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	for _, dirty := range []bool{true, false} {
+		if err := db.SaveDirtyShutdown(ctx, dirty); err != nil {
+			t.Fatal(err)
+		}
+		got, err := db.DirtyShutdown(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != dirty {
+			t.Errorf("Wanted dirty shutdown %v, received %v", dirty, got)
+		}
+	}
+	if err := db.CheckIntegrity(ctx); err != nil {
+		t.Errorf("Expected a consistent database, received %v", err)
+	}
+}
+
+func TestStore_RederiveHead(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	var roots [][32]byte
+	parentRoot := make([]byte, 32)
+	for slot := uint64(0); slot < 3; slot++ {
+		blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot, ParentRoot: parentRoot}}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		// The state of the latest block was not written before the crash.
+		if slot < 2 {
+			if err := db.SaveState(ctx, testutil.NewBeaconState(), root); err != nil {
+				t.Fatal(err)
+			}
+		}
+		roots = append(roots, root)
+		parentRoot = root[:]
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	saveHead := func(root [32]byte) {
+		if err := db.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(blocksBucket).Put(headBlockRootKey, root[:])
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saveHead(roots[2])
+	head, err := db.RederiveHead(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != roots[1] {
+		t.Errorf("Wanted the head to be the latest block with a state %#x, received %#x", roots[1], head)
+	}
+
+	saveHead([32]byte{'a'})
+	head, err = db.RederiveHead(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head != roots[0] {
+		t.Errorf("Wanted the head to be the finalized block %#x for a missing head block, received %#x", roots[0], head)
+	}
+	headBlock, err := db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headBlock == nil || headBlock.Block.Slot != 0 {
+		t.Errorf("Expected the re-derived head to be saved, received %v", headBlock)
+	}
+}
@@ -22,14 +22,8 @@ func (k *Store) State(ctx context.Context, blockRoot [32]byte) (*state.BeaconSta
 	defer span.End()
 	var s *pb.BeaconState
 	err := k.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(stateBucket)
-		enc := bucket.Get(blockRoot[:])
-		if enc == nil {
-			return nil
-		}
-
 		var err error
-		s, err = createState(enc)
+		s, err = stateByRoot(tx, blockRoot[:])
 		return err
 	})
 	if err != nil {
@@ -52,14 +46,8 @@ func (k *Store) HeadState(ctx context.Context) (*state.BeaconState, error) {
 		bucket := tx.Bucket(blocksBucket)
 		headBlkRoot := bucket.Get(headBlockRootKey)
 
-		bucket = tx.Bucket(stateBucket)
-		enc := bucket.Get(headBlkRoot)
-		if enc == nil {
-			return nil
-		}
-
 		var err error
-		s, err = createState(enc)
+		s, err = stateByRoot(tx, headBlkRoot)
 		return err
 	})
 	if err != nil {
@@ -86,14 +74,8 @@ func (k *Store) GenesisState(ctx context.Context) (*state.BeaconState, error) {
 		bucket := tx.Bucket(blocksBucket)
 		genesisBlockRoot := bucket.Get(genesisBlockRootKey)
 
-		bucket = tx.Bucket(stateBucket)
-		enc := bucket.Get(genesisBlockRoot)
-		if enc == nil {
-			return nil
-		}
-
 		var err error
-		s, err = createState(enc)
+		s, err = stateByRoot(tx, genesisBlockRoot)
 		return err
 	})
 	if err != nil {
@@ -118,11 +100,7 @@ func (k *Store) SaveState(ctx context.Context, state *state.BeaconState, blockRo
 	}
 
 	return k.db.Update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(stateBucket)
-		if err := bucket.Put(blockRoot[:], enc); err != nil {
-			return err
-		}
-		return k.setStateSlotBitField(ctx, tx, state.Slot())
+		return k.putState(ctx, tx, blockRoot, enc, state.Slot())
 	})
 }
 
@@ -143,17 +121,71 @@ func (k *Store) SaveStates(ctx context.Context, states []*state.BeaconState, blo
 	}
 
 	return k.db.Update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(stateBucket)
 		for i, rt := range blockRoots {
-			if err := k.setStateSlotBitField(ctx, tx, states[i].Slot()); err != nil {
+			if err := k.putState(ctx, tx, rt, multipleEncs[i], states[i].Slot()); err != nil {
 				return err
 			}
-			err = bucket.Put(rt[:], multipleEncs[i])
+		}
+		return nil
+	})
+}
+
+// putState writes the encoded state of the block root in the transaction.
+func (k *Store) putState(ctx context.Context, tx *bolt.Tx, blockRoot [32]byte, enc []byte, slot uint64) error {
+	if err := tx.Bucket(stateBucket).Put(blockRoot[:], enc); err != nil {
+		return err
+	}
+	// The full state replaces a previously saved diff.
+	if err := tx.Bucket(archivedStateDiffsBucket).Delete(blockRoot[:]); err != nil {
+		return err
+	}
+	if err := tx.Bucket(hotStateDiffsBucket).Delete(blockRoot[:]); err != nil {
+		return err
+	}
+	return k.setStateSlotBitField(ctx, tx, slot)
+}
+
+// SaveStateDiff stores a hot state as its diff from the full state saved with the base block root,
+// which is a small fraction of the full state when the states are a few epochs apart. The state
+// is saved in full instead when the base state is not saved in full or cannot be diffed against.
+func (k *Store) SaveStateDiff(ctx context.Context, st *state.BeaconState, blockRoot [32]byte, baseRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveStateDiff")
+	defer span.End()
+	if st == nil {
+		return errors.New("nil state")
+	}
+
+	return k.db.Update(func(tx *bolt.Tx) error {
+		stateBkt := tx.Bucket(stateBucket)
+		if stateBkt.Get(blockRoot[:]) != nil {
+			return nil
+		}
+		var diff []byte
+		if baseEnc := stateBkt.Get(baseRoot[:]); baseEnc != nil {
+			base, err := createState(baseEnc)
 			if err != nil {
 				return err
 			}
+			diff, err = diffState(base, st.InnerStateUnsafe())
+			if err != nil && err != errIncompatibleBase {
+				return err
+			}
 		}
-		return nil
+		if diff == nil {
+			enc, err := encode(st.InnerStateUnsafe())
+			if err != nil {
+				return err
+			}
+			if err := stateBkt.Put(blockRoot[:], enc); err != nil {
+				return err
+			}
+			if err := tx.Bucket(hotStateDiffsBucket).Delete(blockRoot[:]); err != nil {
+				return err
+			}
+		} else if err := tx.Bucket(hotStateDiffsBucket).Put(blockRoot[:], append(baseRoot[:], diff...)); err != nil {
+			return err
+		}
+		return k.setStateSlotBitField(ctx, tx, st.Slot())
 	})
 }
 
@@ -163,8 +195,7 @@ func (k *Store) HasState(ctx context.Context, blockRoot [32]byte) bool {
 	defer span.End()
 	var exists bool
 	if err := k.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(stateBucket)
-		exists = bucket.Get(blockRoot[:]) != nil
+		exists = hasStateInDB(tx, blockRoot[:])
 		return nil
 	}); err != nil { // This view never returns an error, but we'll handle anyway for sanity.
 		panic(err)
@@ -197,6 +228,9 @@ func (k *Store) DeleteState(ctx context.Context, blockRoot [32]byte) error {
 		if bytes.Equal(blockRoot[:], checkpoint.Root) || bytes.Equal(blockRoot[:], genesisBlockRoot) || bytes.Equal(blockRoot[:], headBlkRoot) {
 			return errors.New("cannot delete genesis, finalized, or head state")
 		}
+		if tx.Bucket(archivedStateBasesBucket).Get(blockRoot[:]) != nil {
+			return errArchivedStateBase
+		}
 
 		slot, err := slotByBlockRoot(ctx, tx, blockRoot[:])
 		if err != nil {
@@ -206,6 +240,15 @@ func (k *Store) DeleteState(ctx context.Context, blockRoot [32]byte) error {
 			return err
 		}
 
+		if err := tx.Bucket(archivedStateDiffsBucket).Delete(blockRoot[:]); err != nil {
+			return err
+		}
+		if err := tx.Bucket(hotStateDiffsBucket).Delete(blockRoot[:]); err != nil {
+			return err
+		}
+		if err := rebaseHotStateDiffs(tx, blockRoot[:]); err != nil {
+			return err
+		}
 		bkt = tx.Bucket(stateBucket)
 		return bkt.Delete(blockRoot[:])
 	})
@@ -242,6 +285,44 @@ func (k *Store) DeleteStates(ctx context.Context, blockRoots [][32]byte) error {
 		blockBkt := tx.Bucket(blocksBucket)
 		headBlkRoot := blockBkt.Get(headBlockRootKey)
 		bkt = tx.Bucket(stateBucket)
+
+		// States stored as diffs are not in the state bucket. They are deleted first, as their slots
+		// are read from their base states when they have no summary or block.
+		diffBkt := tx.Bucket(archivedStateDiffsBucket)
+		hotDiffBkt := tx.Bucket(hotStateDiffsBucket)
+		for blockRoot := range rootMap {
+			if diffBkt.Get(blockRoot[:]) == nil && hotDiffBkt.Get(blockRoot[:]) == nil {
+				continue
+			}
+			if bytes.Equal(blockRoot[:], checkpoint.Root) || bytes.Equal(blockRoot[:], genesisBlockRoot) || bytes.Equal(blockRoot[:], headBlkRoot) {
+				return errors.New("cannot delete genesis, finalized, or head state")
+			}
+			slot, err := slotByBlockRoot(ctx, tx, blockRoot[:])
+			if err != nil {
+				return err
+			}
+			if err := k.clearStateSlotBitField(ctx, tx, slot); err != nil {
+				return err
+			}
+			if err := diffBkt.Delete(blockRoot[:]); err != nil {
+				return err
+			}
+			if err := hotDiffBkt.Delete(blockRoot[:]); err != nil {
+				return err
+			}
+		}
+
+		// Hot states stored as diffs from the deleted states are saved against a new base first,
+		// as the state bucket cannot be written to while deleting with its cursor.
+		for blockRoot := range rootMap {
+			if bkt.Get(blockRoot[:]) == nil {
+				continue
+			}
+			if err := rebaseHotStateDiffs(tx, blockRoot[:]); err != nil {
+				return err
+			}
+		}
+
 		c := bkt.Cursor()
 
 		for blockRoot, _ := c.First(); blockRoot != nil; blockRoot, _ = c.Next() {
@@ -250,6 +331,9 @@ func (k *Store) DeleteStates(ctx context.Context, blockRoots [][32]byte) error {
 				if bytes.Equal(blockRoot[:], checkpoint.Root) || bytes.Equal(blockRoot[:], genesisBlockRoot) || bytes.Equal(blockRoot[:], headBlkRoot) {
 					return errors.New("cannot delete genesis, finalized, or head state")
 				}
+				if tx.Bucket(archivedStateBasesBucket).Get(blockRoot) != nil {
+					return errArchivedStateBase
+				}
 
 				slot, err := slotByBlockRoot(ctx, tx, blockRoot)
 				if err != nil {
@@ -293,12 +377,10 @@ func slotByBlockRoot(ctx context.Context, tx *bolt.Tx, blockRoot []byte) (uint64
 
 		if enc == nil {
 			// Fallback and check the state.
-			bkt = tx.Bucket(stateBucket)
-			enc = bkt.Get(blockRoot)
-			if enc == nil {
+			if !hasStateInDB(tx, blockRoot) {
 				return 0, errors.New("state enc can't be nil")
 			}
-			s, err := createState(enc)
+			s, err := stateByRoot(tx, blockRoot)
 			if err != nil {
 				return 0, err
 			}
@@ -416,17 +498,15 @@ func (k *Store) statesAtSlotBitfieldIndex(ctx context.Context, tx *bolt.Tx, inde
 		return nil, errors.New("could not get one block root to get state")
 	}
 
-	stateBkt := tx.Bucket(stateBucket)
 	states := make([]*state.BeaconState, 0, len(keys))
 	for i := range keys {
-		enc := stateBkt.Get(keys[i][:])
-		if enc == nil {
-			continue
-		}
-		pbState, err := createState(enc)
+		pbState, err := stateByRoot(tx, keys[i][:])
 		if err != nil {
 			return nil, err
 		}
+		if pbState == nil {
+			continue
+		}
 		s, err := state.InitializeFromProtoUnsafe(pbState)
 		if err != nil {
 			return nil, err
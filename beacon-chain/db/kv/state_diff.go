@@ -0,0 +1,423 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+// archivedStateSnapshotInterval is the number of archived states sharing a base snapshot. The
+// first archived state of every interval is kept in full, the following ones are stored as the
+// diff from it.
+const archivedStateSnapshotInterval = 16
+
+// errIncompatibleBase is returned when a state cannot be expressed as a diff from a base state,
+// such as when the base has more validators or historical roots than the state.
+var errIncompatibleBase = errors.New("state cannot be diffed against base state")
+
+// errArchivedStateBase is returned when deleting a base snapshot, which archived states are
+// reconstructed from.
+var errArchivedStateBase = errors.New("cannot delete base state of archived states")
+
+// compressArchivedState replaces the full state of an archived block root with its diff from the
+// last base snapshot, or makes it the new base snapshot once the last base is shared by
+// archivedStateSnapshotInterval states.
+func compressArchivedState(tx *bolt.Tx, blockRoot []byte) error {
+	stateBkt := tx.Bucket(stateBucket)
+	basesBkt := tx.Bucket(archivedStateBasesBucket)
+	// Archived states are only diffed against archived bases, so a hot state diff is saved in
+	// full first.
+	if hotEnc := tx.Bucket(hotStateDiffsBucket).Get(blockRoot); hotEnc != nil {
+		st, err := stateByRoot(tx, blockRoot)
+		if err != nil {
+			return err
+		}
+		enc, err := encode(st)
+		if err != nil {
+			return err
+		}
+		if err := stateBkt.Put(blockRoot, enc); err != nil {
+			return err
+		}
+		if err := tx.Bucket(hotStateDiffsBucket).Delete(blockRoot); err != nil {
+			return err
+		}
+	}
+	enc := stateBkt.Get(blockRoot)
+	if enc == nil || basesBkt.Get(blockRoot) != nil {
+		return nil
+	}
+	metadata := tx.Bucket(chainMetadataBucket)
+	if last := metadata.Get(lastArchivedStateBaseKey); last != nil {
+		// Copied, as the value is only valid until the metadata bucket is written to.
+		baseRoot := make([]byte, 32)
+		copy(baseRoot, last[:32])
+		diffs := binary.BigEndian.Uint64(last[32:])
+		if baseEnc := stateBkt.Get(baseRoot); baseEnc != nil && diffs+1 < archivedStateSnapshotInterval {
+			base, err := createState(baseEnc)
+			if err != nil {
+				return err
+			}
+			st, err := createState(enc)
+			if err != nil {
+				return err
+			}
+			diff, err := diffState(base, st)
+			if err == nil {
+				if err := tx.Bucket(archivedStateDiffsBucket).Put(blockRoot, append(baseRoot, diff...)); err != nil {
+					return err
+				}
+				if err := rebaseHotStateDiffs(tx, blockRoot); err != nil {
+					return err
+				}
+				if err := stateBkt.Delete(blockRoot); err != nil {
+					return err
+				}
+				return metadata.Put(lastArchivedStateBaseKey, archivedStateBaseValue(baseRoot, diffs+1))
+			}
+			if err != errIncompatibleBase {
+				return err
+			}
+		}
+	}
+	if err := basesBkt.Put(blockRoot, []byte{1}); err != nil {
+		return err
+	}
+	return metadata.Put(lastArchivedStateBaseKey, archivedStateBaseValue(blockRoot, 0))
+}
+
+// archivedStateBaseValue encodes the root of the last base snapshot followed by the number of
+// archived states stored as diffs from it.
+func archivedStateBaseValue(baseRoot []byte, diffs uint64) []byte {
+	value := make([]byte, 40)
+	copy(value, baseRoot)
+	binary.BigEndian.PutUint64(value[32:], diffs)
+	return value
+}
+
+// stateByRoot returns the state saved with the block root, reconstructing it from its base
+// snapshot when it was archived or saved as a hot state diff. A nil state is returned when no
+// state was saved.
+func stateByRoot(tx *bolt.Tx, blockRoot []byte) (*pb.BeaconState, error) {
+	if enc := tx.Bucket(stateBucket).Get(blockRoot); enc != nil {
+		return createState(enc)
+	}
+	enc := tx.Bucket(archivedStateDiffsBucket).Get(blockRoot)
+	if enc == nil {
+		enc = tx.Bucket(hotStateDiffsBucket).Get(blockRoot)
+	}
+	if enc == nil {
+		return nil, nil
+	}
+	baseEnc := tx.Bucket(stateBucket).Get(enc[:32])
+	if baseEnc == nil {
+		return nil, errors.Errorf("missing base state %#x of state %#x", enc[:32], blockRoot)
+	}
+	base, err := createState(baseEnc)
+	if err != nil {
+		return nil, err
+	}
+	return applyStateDiff(base, enc[32:])
+}
+
+// hasStateInDB returns whether a full, archived or hot state diff was saved with the block root.
+func hasStateInDB(tx *bolt.Tx, blockRoot []byte) bool {
+	return tx.Bucket(stateBucket).Get(blockRoot) != nil ||
+		tx.Bucket(archivedStateDiffsBucket).Get(blockRoot) != nil ||
+		tx.Bucket(hotStateDiffsBucket).Get(blockRoot) != nil
+}
+
+// rebaseHotStateDiffs is called before the full state of the base block root is removed. The hot
+// states stored as diffs from it are stored as diffs from the earliest of them instead, which is
+// saved in full.
+func rebaseHotStateDiffs(tx *bolt.Tx, baseRoot []byte) error {
+	hotBkt := tx.Bucket(hotStateDiffsBucket)
+	var roots [][]byte
+	if err := hotBkt.ForEach(func(root, enc []byte) error {
+		if bytes.Equal(enc[:32], baseRoot) {
+			roots = append(roots, bytesutil.SafeCopyBytes(root))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(roots) == 0 {
+		return nil
+	}
+
+	states := make([]*pb.BeaconState, len(roots))
+	first := 0
+	for i, root := range roots {
+		st, err := stateByRoot(tx, root)
+		if err != nil {
+			return err
+		}
+		states[i] = st
+		if st.Slot < states[first].Slot {
+			first = i
+		}
+	}
+	stateBkt := tx.Bucket(stateBucket)
+	for i, root := range roots {
+		if i != first {
+			diff, err := diffState(states[first], states[i])
+			if err == nil {
+				if err := hotBkt.Put(root, append(bytesutil.SafeCopyBytes(roots[first]), diff...)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != errIncompatibleBase {
+				return err
+			}
+		}
+		enc, err := encode(states[i])
+		if err != nil {
+			return err
+		}
+		if err := stateBkt.Put(root, enc); err != nil {
+			return err
+		}
+		if err := hotBkt.Delete(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffState encodes the changes from the base state to the given state. The fields which grow or
+// rotate, the validators, balances, historical roots and root vectors, are stored as the entries
+// which changed, the remaining fields are small and stored in full.
+func diffState(base *pb.BeaconState, st *pb.BeaconState) ([]byte, error) {
+	if len(st.Validators) < len(base.Validators) ||
+		len(st.HistoricalRoots) < len(base.HistoricalRoots) ||
+		len(st.BlockRoots) != len(base.BlockRoots) ||
+		len(st.StateRoots) != len(base.StateRoots) ||
+		len(st.RandaoMixes) != len(base.RandaoMixes) {
+		return nil, errIncompatibleBase
+	}
+	for i, root := range base.HistoricalRoots {
+		if !bytes.Equal(root, st.HistoricalRoots[i]) {
+			return nil, errIncompatibleBase
+		}
+	}
+
+	rest, err := proto.Marshal(&pb.BeaconState{
+		GenesisTime:                 st.GenesisTime,
+		GenesisValidatorsRoot:       st.GenesisValidatorsRoot,
+		Slot:                        st.Slot,
+		Fork:                        st.Fork,
+		LatestBlockHeader:           st.LatestBlockHeader,
+		Eth1Data:                    st.Eth1Data,
+		Eth1DataVotes:               st.Eth1DataVotes,
+		Eth1DepositIndex:            st.Eth1DepositIndex,
+		Slashings:                   st.Slashings,
+		PreviousEpochAttestations:   st.PreviousEpochAttestations,
+		CurrentEpochAttestations:    st.CurrentEpochAttestations,
+		JustificationBits:           st.JustificationBits,
+		PreviousJustifiedCheckpoint: st.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  st.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         st.FinalizedCheckpoint,
+	})
+	if err != nil {
+		return nil, err
+	}
+	w := &diffWriter{}
+	w.bytes(rest)
+
+	// Validators appended since the base are changes from an empty entry.
+	var changed []int
+	for i, v := range st.Validators {
+		if i >= len(base.Validators) || !proto.Equal(base.Validators[i], v) {
+			changed = append(changed, i)
+		}
+	}
+	w.uvarint(uint64(len(st.Validators)))
+	w.uvarint(uint64(len(changed)))
+	for _, i := range changed {
+		enc, err := proto.Marshal(st.Validators[i])
+		if err != nil {
+			return nil, err
+		}
+		w.uvarint(uint64(i))
+		w.bytes(enc)
+	}
+
+	// Most balances change by small amounts between archived points, so the deltas are stored
+	// as signed varints.
+	w.uvarint(uint64(len(st.Balances)))
+	for i, bal := range st.Balances {
+		var prev uint64
+		if i < len(base.Balances) {
+			prev = base.Balances[i]
+		}
+		w.varint(int64(bal - prev))
+	}
+
+	w.uvarint(uint64(len(st.HistoricalRoots) - len(base.HistoricalRoots)))
+	for _, root := range st.HistoricalRoots[len(base.HistoricalRoots):] {
+		w.bytes(root)
+	}
+	w.rootChanges(base.BlockRoots, st.BlockRoots)
+	w.rootChanges(base.StateRoots, st.StateRoots)
+	w.rootChanges(base.RandaoMixes, st.RandaoMixes)
+	return snappy.Encode(nil, w.buf.Bytes()), nil
+}
+
+// applyStateDiff reconstructs a state from its base state and the diff encoded by diffState. The
+// base state is modified and shares its fields with the returned state.
+func applyStateDiff(base *pb.BeaconState, enc []byte) (*pb.BeaconState, error) {
+	data, err := snappy.Decode(nil, enc)
+	if err != nil {
+		return nil, err
+	}
+	r := &diffReader{r: bytes.NewReader(data)}
+	st := &pb.BeaconState{}
+	if err := proto.Unmarshal(r.bytes(), st); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal state diff")
+	}
+
+	numValidators := r.uvarint()
+	if r.err == nil && numValidators < uint64(len(base.Validators)) {
+		return nil, errors.New("state diff has fewer validators than its base state")
+	}
+	st.Validators = make([]*ethpb.Validator, numValidators)
+	copy(st.Validators, base.Validators)
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		i := r.uvarint()
+		v := &ethpb.Validator{}
+		if err := proto.Unmarshal(r.bytes(), v); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal validator of state diff")
+		}
+		if i >= numValidators {
+			return nil, errors.Errorf("validator index %d of state diff out of range", i)
+		}
+		st.Validators[i] = v
+	}
+
+	numBalances := r.uvarint()
+	st.Balances = make([]uint64, 0, numBalances)
+	for i := uint64(0); i < numBalances && r.err == nil; i++ {
+		bal := uint64(r.varint())
+		if i < uint64(len(base.Balances)) {
+			bal += base.Balances[i]
+		}
+		st.Balances = append(st.Balances, bal)
+	}
+
+	st.HistoricalRoots = base.HistoricalRoots
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		st.HistoricalRoots = append(st.HistoricalRoots, r.bytes())
+	}
+	st.BlockRoots = r.applyRootChanges(base.BlockRoots)
+	st.StateRoots = r.applyRootChanges(base.StateRoots)
+	st.RandaoMixes = r.applyRootChanges(base.RandaoMixes)
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "could not decode state diff")
+	}
+	for i, v := range st.Validators {
+		if v == nil {
+			return nil, errors.Errorf("state diff is missing validator %d", i)
+		}
+	}
+	return st, nil
+}
+
+type diffWriter struct {
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *diffWriter) uvarint(x uint64) {
+	n := binary.PutUvarint(w.scratch[:], x)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *diffWriter) varint(x int64) {
+	n := binary.PutVarint(w.scratch[:], x)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *diffWriter) bytes(b []byte) {
+	w.uvarint(uint64(len(b)))
+	w.buf.Write(b)
+}
+
+// rootChanges writes the indices and values of the roots which differ from the base roots of the
+// same length.
+func (w *diffWriter) rootChanges(base [][]byte, roots [][]byte) {
+	var changed []int
+	for i, root := range roots {
+		if !bytes.Equal(base[i], root) {
+			changed = append(changed, i)
+		}
+	}
+	w.uvarint(uint64(len(changed)))
+	for _, i := range changed {
+		w.uvarint(uint64(i))
+		w.bytes(roots[i])
+	}
+}
+
+// diffReader decodes a state diff, keeping the first error so the reads can be checked once.
+type diffReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (r *diffReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	x, err := binary.ReadUvarint(r.r)
+	r.err = err
+	return x
+}
+
+func (r *diffReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	x, err := binary.ReadVarint(r.r)
+	r.err = err
+	return x
+}
+
+func (r *diffReader) bytes() []byte {
+	n := r.uvarint()
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(r.r.Len()) {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := make([]byte, n)
+	_, r.err = io.ReadFull(r.r, b)
+	return b
+}
+
+// applyRootChanges sets the changed roots written by rootChanges on the base roots.
+func (r *diffReader) applyRootChanges(base [][]byte) [][]byte {
+	for n := r.uvarint(); n > 0 && r.err == nil; n-- {
+		i := r.uvarint()
+		root := r.bytes()
+		if r.err != nil {
+			break
+		}
+		if i >= uint64(len(base)) {
+			r.err = errors.Errorf("root index %d of state diff out of range", i)
+			break
+		}
+		base[i] = root
+	}
+	return base
+}
@@ -0,0 +1,227 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStateDiff_RoundTrip(t *testing.T) {
+	base, _ := testutil.DeterministicGenesisState(t, 8)
+	st := changedState(t, base, 64)
+
+	diff, err := diffState(base.CloneInnerState(), st.CloneInnerState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	received, err := applyStateDiff(base.CloneInnerState(), diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(st.InnerStateUnsafe(), received) {
+		t.Error("Reconstructed state does not match the diffed state")
+	}
+}
+
+func TestStateDiff_IncompatibleBase(t *testing.T) {
+	base, _ := testutil.DeterministicGenesisState(t, 8)
+	st, _ := testutil.DeterministicGenesisState(t, 4)
+	if _, err := diffState(base.CloneInnerState(), st.CloneInnerState()); err != errIncompatibleBase {
+		t.Errorf("Wanted %v, received %v", errIncompatibleBase, err)
+	}
+}
+
+func TestStore_ArchivedStatesStoredAsDiffs(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	base, _ := testutil.DeterministicGenesisState(t, 8)
+	states := []*state.BeaconState{base}
+	for i := uint64(1); i <= archivedStateSnapshotInterval; i++ {
+		states = append(states, changedState(t, states[i-1], i*64))
+	}
+	roots := make([][32]byte, len(states))
+	for i, st := range states {
+		roots[i] = [32]byte{byte(i + 1)}
+		if err := db.SaveState(ctx, st, roots[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveArchivedPointRoot(ctx, roots[i], uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.db.View(func(tx *bolt.Tx) error {
+		for i, r := range roots {
+			// The first state of every snapshot interval is kept in full.
+			isBase := i%archivedStateSnapshotInterval == 0
+			if hasFull := tx.Bucket(stateBucket).Get(r[:]) != nil; hasFull != isBase {
+				t.Errorf("Archived state %d stored in full: %v, wanted %v", i, hasFull, isBase)
+			}
+			if hasDiff := tx.Bucket(archivedStateDiffsBucket).Get(r[:]) != nil; hasDiff == isBase {
+				t.Errorf("Archived state %d stored as diff: %v, wanted %v", i, hasDiff, !isBase)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, r := range roots {
+		if !db.HasState(ctx, r) {
+			t.Errorf("Wanted archived state %d in db", i)
+		}
+		received, err := db.State(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(states[i].InnerStateUnsafe(), received.InnerStateUnsafe()) {
+			t.Errorf("Archived state %d does not match the saved state", i)
+		}
+	}
+
+	if err := db.DeleteState(ctx, roots[0]); err != errArchivedStateBase {
+		t.Errorf("Wanted %v, received %v", errArchivedStateBase, err)
+	}
+	if err := db.DeleteState(ctx, roots[1]); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasState(ctx, roots[1]) {
+		t.Error("Wanted deleted archived state to not be in db")
+	}
+}
+
+func TestStore_HotStatesStoredAsDiffs(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	base, _ := testutil.DeterministicGenesisState(t, 8)
+	states := []*state.BeaconState{base}
+	for i := uint64(1); i < 4; i++ {
+		states = append(states, changedState(t, states[i-1], i*64))
+	}
+	roots := [][32]byte{{1}, {2}, {3}, {4}}
+	if err := db.SaveState(ctx, states[0], roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(states); i++ {
+		if err := db.SaveStateDiff(ctx, states[i], roots[i], roots[0]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A state is saved in full when its base is not saved.
+	fullRoot := [32]byte{'a'}
+	if err := db.SaveStateDiff(ctx, states[1], fullRoot, [32]byte{'b'}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.db.View(func(tx *bolt.Tx) error {
+		for i, r := range roots[1:] {
+			if tx.Bucket(stateBucket).Get(r[:]) != nil || tx.Bucket(hotStateDiffsBucket).Get(r[:]) == nil {
+				t.Errorf("Wanted hot state %d to be stored as diff", i+1)
+			}
+		}
+		if tx.Bucket(stateBucket).Get(fullRoot[:]) == nil {
+			t.Error("Wanted the state without base to be stored in full")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkStates := func(from int) {
+		for i := from; i < len(states); i++ {
+			if !db.HasState(ctx, roots[i]) {
+				t.Errorf("Wanted hot state %d in db", i)
+			}
+			received, err := db.State(ctx, roots[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if received == nil {
+				t.Fatalf("Hot state %d not found", i)
+			}
+			if !stateRootsEqual(t, states[i], received) {
+				t.Errorf("Hot state %d does not match the saved state", i)
+			}
+		}
+	}
+	checkStates(0)
+
+	// Deleting the base saves the earliest remaining state in full and the others as diffs from it.
+	if err := db.DeleteStates(ctx, [][32]byte{roots[0], roots[1]}); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasState(ctx, roots[0]) || db.HasState(ctx, roots[1]) {
+		t.Error("Wanted deleted states to not be in db")
+	}
+	checkStates(2)
+	if err := db.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(stateBucket).Get(roots[2][:]) == nil {
+			t.Error("Wanted the earliest remaining hot state to be stored in full")
+		}
+		if enc := tx.Bucket(hotStateDiffsBucket).Get(roots[3][:]); enc == nil || string(enc[:32]) != string(roots[2][:]) {
+			t.Error("Wanted the hot state to be stored as diff from the new base")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.DeleteState(ctx, roots[2]); err != nil {
+		t.Fatal(err)
+	}
+	checkStates(3)
+}
+
+// stateRootsEqual compares states by their hash tree roots.
+func stateRootsEqual(t *testing.T, a *state.BeaconState, b *state.BeaconState) bool {
+	rootA, err := a.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootB, err := b.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rootA == rootB
+}
+
+// changedState returns a copy of the state at the given slot, with the fields which are stored as
+// diffs changed.
+func changedState(t *testing.T, st *state.BeaconState, slot uint64) *state.BeaconState {
+	st = st.Copy()
+	if err := st.SetSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.AppendValidator(&ethpb.Validator{
+		PublicKey:             bytesutil.PadTo([]byte{byte(slot)}, 48),
+		WithdrawalCredentials: make([]byte, 32),
+		ExitEpoch:             slot,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.AppendBalance(slot); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.UpdateBalancesAtIndex(0, st.Balances()[0]-slot); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.UpdateBlockRootAtIndex(slot, [32]byte{byte(slot)}); err != nil {
+		t.Fatal(err)
+	}
+	mix := [32]byte{byte(slot)}
+	if err := st.UpdateRandaoMixesAtIndex(slot, mix[:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.AppendHistoricalRoots([32]byte{byte(slot)}); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
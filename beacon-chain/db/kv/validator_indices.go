@@ -0,0 +1,76 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// ValidatorIndex retrieves the registry index of the validator with the given public key, if
+// the validator has been saved to the db by SaveValidatorIndices.
+func (k *Store) ValidatorIndex(ctx context.Context, publicKey [48]byte) (uint64, bool, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.ValidatorIndex")
+	defer span.End()
+
+	var index uint64
+	var ok bool
+	err := k.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(validatorIndicesBucket).Get(publicKey[:])
+		if enc == nil {
+			return nil
+		}
+		index = bytesutil.FromBytes8(enc)
+		ok = true
+		return nil
+	})
+	return index, ok, err
+}
+
+// ValidatorIndicesCount returns the number of validators, from the start of the registry, whose
+// indices have been saved to the db.
+func (k *Store) ValidatorIndicesCount(ctx context.Context) (uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.ValidatorIndicesCount")
+	defer span.End()
+
+	var count uint64
+	err := k.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(chainMetadataBucket).Get(validatorIndicesCountKey)
+		if enc != nil {
+			count = bytesutil.FromBytes8(enc)
+		}
+		return nil
+	})
+	return count, err
+}
+
+// SaveValidatorIndices saves the public key to index mappings of the validators at indices
+// [startIndex, startIndex+len(publicKeys)) of the registry. The saved range has to extend the
+// indices already in the db, so the db always holds a contiguous prefix of the registry.
+func (k *Store) SaveValidatorIndices(ctx context.Context, startIndex uint64, publicKeys [][48]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveValidatorIndices")
+	defer span.End()
+
+	return k.db.Update(func(tx *bolt.Tx) error {
+		metadata := tx.Bucket(chainMetadataBucket)
+		var count uint64
+		if enc := metadata.Get(validatorIndicesCountKey); enc != nil {
+			count = bytesutil.FromBytes8(enc)
+		}
+		if startIndex > count {
+			return errors.Errorf("cannot save validator indices from %d, only %d indices are saved", startIndex, count)
+		}
+		bkt := tx.Bucket(validatorIndicesBucket)
+		for i, key := range publicKeys {
+			if err := bkt.Put(key[:], bytesutil.Bytes8(startIndex+uint64(i))); err != nil {
+				return err
+			}
+		}
+		if end := startIndex + uint64(len(publicKeys)); end > count {
+			return metadata.Put(validatorIndicesCountKey, bytesutil.Bytes8(end))
+		}
+		return nil
+	})
+}
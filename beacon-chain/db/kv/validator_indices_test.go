@@ -0,0 +1,61 @@
+package kv
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStore_ValidatorIndices(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	keys := make([][48]byte, 4)
+	for i := range keys {
+		keys[i][0] = byte(i + 1)
+	}
+	if err := db.SaveValidatorIndices(ctx, 0, keys[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveValidatorIndices(ctx, 2, keys[2:]); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := db.ValidatorIndicesCount(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != uint64(len(keys)) {
+		t.Errorf("Wanted %d saved indices, received %d", len(keys), count)
+	}
+	for i, key := range keys {
+		index, ok, err := db.ValidatorIndex(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("Index of validator %d not found", i)
+		}
+		if index != uint64(i) {
+			t.Errorf("Wanted index %d, received %d", i, index)
+		}
+	}
+	if _, ok, err := db.ValidatorIndex(ctx, [48]byte{'a'}); err != nil || ok {
+		t.Errorf("Expected unknown public key to not be found, received ok=%v err=%v", ok, err)
+	}
+}
+
+func TestStore_SaveValidatorIndices_NotContiguous(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	if err := db.SaveValidatorIndices(ctx, 1, [][48]byte{{'a'}}); err == nil {
+		t.Error("Expected error saving indices past the saved registry prefix")
+	}
+	count, err := db.ValidatorIndicesCount(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Wanted no saved indices, received %d", count)
+	}
+}
@@ -0,0 +1,228 @@
+// Package diskmonitor defines a service which watches the free disk space of the beacon node
+// data directory, warns when it runs low and prunes historical states before database writes
+// start failing.
+package diskmonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/diskutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "diskmonitor")
+
+var _ = shared.Service(&Service{})
+
+var (
+	availableBytesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "disk_available_bytes",
+		Help: "Free disk space of the data directory in bytes.",
+	})
+	prunedStatesCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "disk_emergency_pruned_states_total",
+		Help: "Number of states deleted because the free disk space fell below the emergency threshold.",
+	})
+)
+
+// checkInterval is the time between two checks of the free disk space.
+const checkInterval = time.Minute
+
+// pruneInterval is the minimum time between two prunings while in emergency mode, as
+// pruning walks all the finalized block roots.
+const pruneInterval = 10 * time.Minute
+
+// Config options for the disk monitor service.
+type Config struct {
+	// DataDir is the directory whose file system is watched.
+	DataDir string
+	// BeaconDB is pruned when the free space falls below the emergency threshold.
+	BeaconDB db.NoHeadAccessDatabase
+	// WarnThreshold in bytes below which a warning is logged.
+	WarnThreshold uint64
+	// EmergencyThreshold in bytes below which states are pruned, 0 disables pruning.
+	EmergencyThreshold uint64
+}
+
+// Service periodically checks the free disk space of the data directory.
+type Service struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
+	cfg            *Config
+	availableBytes func(path string) (uint64, error)
+	lock           sync.RWMutex
+	emergency      bool
+	lastPrune      time.Time
+}
+
+// NewService creates a new disk monitor service.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:            ctx,
+		cancel:         cancel,
+		cfg:            cfg,
+		availableBytes: diskutil.AvailableBytes,
+	}
+}
+
+// CheckAvailableSpace is run before the node starts writing to the data directory. It fails if
+// the free space is already below the emergency threshold and warns if it is below the warn
+// threshold, which should cover the expected growth of the database.
+func CheckAvailableSpace(dataDir string, warnThreshold uint64, emergencyThreshold uint64) error {
+	available, err := diskutil.AvailableBytes(dataDir)
+	if err != nil {
+		return errors.Wrap(err, "could not check free disk space")
+	}
+	if available < emergencyThreshold {
+		return fmt.Errorf(
+			"only %s of disk space available in %s, at least %s are required",
+			formatBytes(available), dataDir, formatBytes(emergencyThreshold),
+		)
+	}
+	if available < warnThreshold {
+		log.WithFields(logrus.Fields{
+			"available": formatBytes(available),
+			"expected":  formatBytes(warnThreshold),
+		}).Warn("Free disk space is lower than the expected growth of the database")
+	}
+	return nil
+}
+
+// Start the disk space checks.
+func (s *Service) Start() {
+	go s.run()
+}
+
+// Stop the disk space checks.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status returns an error while the free disk space is below the emergency threshold.
+func (s *Service) Status() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.emergency {
+		return errors.New("free disk space is below the emergency threshold")
+	}
+	return nil
+}
+
+func (s *Service) run() {
+	s.check(s.ctx)
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.check(s.ctx)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// check compares the free disk space with the thresholds and prunes states in emergency mode.
+func (s *Service) check(ctx context.Context) {
+	available, err := s.availableBytes(s.cfg.DataDir)
+	if err != nil {
+		log.WithError(err).Debug("Could not check free disk space")
+		return
+	}
+	availableBytesGauge.Set(float64(available))
+
+	emergency := available < s.cfg.EmergencyThreshold
+	s.lock.Lock()
+	wasEmergency := s.emergency
+	s.emergency = emergency
+	prune := emergency && time.Since(s.lastPrune) >= pruneInterval
+	if prune {
+		s.lastPrune = time.Now()
+	}
+	s.lock.Unlock()
+
+	fields := logrus.Fields{
+		"available": formatBytes(available),
+		"dataDir":   s.cfg.DataDir,
+	}
+	switch {
+	case emergency && !wasEmergency:
+		log.WithFields(fields).Error("Free disk space is critically low, pruning historical states")
+	case !emergency && wasEmergency:
+		log.WithFields(fields).Info("Free disk space recovered, leaving emergency mode")
+	case !emergency && available < s.cfg.WarnThreshold:
+		log.WithFields(fields).Warn("Free disk space is running low")
+	}
+	if !prune {
+		return
+	}
+	pruned, err := pruneStates(ctx, s.cfg.BeaconDB)
+	if err != nil {
+		log.WithError(err).Error("Could not prune states")
+		return
+	}
+	prunedStatesCount.Add(float64(pruned))
+	log.WithField("prunedStates", pruned).Info("Pruned finalized states")
+}
+
+// pruneStates deletes the states of the finalized blocks, except the genesis and finalized
+// states and, with the new state management, the archived points from which all other states
+// are regenerated. It returns the number of deleted states.
+func pruneStates(ctx context.Context, beaconDB db.NoHeadAccessDatabase) (int, error) {
+	cp, err := beaconDB.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get finalized checkpoint")
+	}
+	finalizedSlot := helpers.StartSlot(cp.Epoch)
+	if finalizedSlot <= 1 {
+		return 0, nil
+	}
+	keep := make(map[[32]byte]bool)
+	if featureconfig.Get().NewStateMgmt {
+		lastIndex, err := beaconDB.LastArchivedIndex(ctx)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not get last archived index")
+		}
+		for i := uint64(0); i <= lastIndex; i++ {
+			keep[beaconDB.ArchivedPointRoot(ctx, i)] = true
+		}
+	}
+	roots, err := beaconDB.BlockRoots(ctx, filters.NewFilter().SetStartSlot(1).SetEndSlot(finalizedSlot-1))
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get finalized block roots")
+	}
+	pruned := 0
+	for _, r := range roots {
+		if ctx.Err() != nil {
+			return pruned, ctx.Err()
+		}
+		if keep[r] || r == params.BeaconConfig().ZeroHash || !beaconDB.HasState(ctx, r) {
+			continue
+		}
+		if err := beaconDB.DeleteState(ctx, r); err != nil {
+			log.WithError(err).WithField("root", fmt.Sprintf("%#x", r)).Debug("Could not delete state")
+			continue
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// formatBytes formats a number of bytes in GB with one decimal.
+func formatBytes(b uint64) string {
+	return fmt.Sprintf("%.1fGB", float64(b)/(1<<30))
+}
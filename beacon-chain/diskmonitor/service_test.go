@@ -0,0 +1,89 @@
+package diskmonitor
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestCheck_EmergencyMode(t *testing.T) {
+	db := testDB.SetupDB(t)
+	available := uint64(100)
+	s := NewService(context.Background(), &Config{
+		BeaconDB:           db,
+		WarnThreshold:      50,
+		EmergencyThreshold: 10,
+	})
+	s.availableBytes = func(string) (uint64, error) {
+		return available, nil
+	}
+
+	s.check(context.Background())
+	if err := s.Status(); err != nil {
+		t.Errorf("Unexpected status error with enough disk space: %v", err)
+	}
+	available = 5
+	s.check(context.Background())
+	if err := s.Status(); err == nil {
+		t.Error("Expected status error below the emergency threshold")
+	}
+	if s.lastPrune.IsZero() {
+		t.Error("Expected states to be pruned in emergency mode")
+	}
+	available = 20
+	s.check(context.Background())
+	if err := s.Status(); err != nil {
+		t.Errorf("Unexpected status error after disk space recovered: %v", err)
+	}
+}
+
+func TestPruneStates_KeepsGenesisAndFinalizedStates(t *testing.T) {
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+
+	var roots [][32]byte
+	parentRoot := params.BeaconConfig().ZeroHash
+	for _, slot := range []uint64{0, 1, 2, params.BeaconConfig().SlotsPerEpoch} {
+		blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot, ParentRoot: parentRoot[:]}}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := testutil.NewBeaconState()
+		if err := st.SetSlot(slot); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveState(ctx, st, root); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+		parentRoot = root
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: roots[3][:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := pruneStates(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 2 {
+		t.Errorf("Wanted 2 pruned states, received %d", pruned)
+	}
+	for i, want := range []bool{true, false, false, true} {
+		if has := db.HasState(ctx, roots[i]); has != want {
+			t.Errorf("State %d: wanted present %v, received %v", i, want, has)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Package explorer defines a service serving the finalized blocks, attestations, deposits,
+// voluntary exits and slashings of the canonical chain as cursor paginated streams, so block
+// explorers and indexers can consume each object exactly once and resume after restarts.
+package explorer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "explorer")
+
+var _ = shared.Service(&Service{})
+
+var marshaler = &jsonpb.Marshaler{}
+
+// StreamsPath is the path the streams are served under, followed by the stream name.
+const StreamsPath = "/explorer/v1/"
+
+const (
+	// defaultLimit is the number of entries returned when the request does not set a limit.
+	defaultLimit = 100
+	// maxLimit is the largest number of entries returned by a single request.
+	maxLimit = 1000
+	// maxScanSlots is the widest slot range scanned by a single request, bounding the work of
+	// requests whose filters match few entries. The returned cursor resumes after the range.
+	maxScanSlots = 4096
+	// scanBatchSlots is the slot range of the blocks read from the db at once.
+	scanBatchSlots = 256
+)
+
+// Config options for the explorer service.
+type Config struct {
+	// Address the streams are served on.
+	Address             string
+	BeaconDB            db.ReadOnlyDatabase
+	FinalizationFetcher blockchain.FinalizationFetcher
+}
+
+// Service serves the streams of finalized chain data.
+type Service struct {
+	beaconDB            db.ReadOnlyDatabase
+	finalizationFetcher blockchain.FinalizationFetcher
+	server              *http.Server
+	failStatus          error
+}
+
+type streamEntry struct {
+	Kind      string          `json:"kind"`
+	Slot      uint64          `json:"slot,string"`
+	BlockRoot string          `json:"block_root"`
+	Index     uint64          `json:"index,string"`
+	Cursor    string          `json:"cursor"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type streamResponse struct {
+	Entries       []*streamEntry `json:"entries"`
+	NextCursor    string         `json:"next_cursor"`
+	FinalizedSlot uint64         `json:"finalized_slot,string"`
+}
+
+// NewService initializes the explorer service from configuration options.
+func NewService(cfg *Config) *Service {
+	s := &Service{
+		beaconDB:            cfg.BeaconDB,
+		finalizationFetcher: cfg.FinalizationFetcher,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(StreamsPath, s.StreamHandler)
+	s.server = &http.Server{Addr: cfg.Address, Handler: mux}
+	return s
+}
+
+// Start serving the streams.
+func (s *Service) Start() {
+	go func() {
+		log.WithField("address", s.server.Addr).Info("Starting explorer API server")
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("Could not serve explorer API")
+			s.failStatus = err
+		}
+	}()
+}
+
+// Stop the server gracefully.
+func (s *Service) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// Status returns the error the server failed with, if any.
+func (s *Service) Status() error {
+	return s.failStatus
+}
+
+// StreamHandler serves a page of the stream named by the request path, starting at the `cursor`
+// query parameter and returning up to `limit` entries matching the stream's filter parameters.
+// Every entry carries the cursor resuming after it, and the page carries the cursor resuming after
+// the scanned slots. Only finalized blocks of the canonical chain are streamed, so the entries
+// after a cursor never change.
+func (s *Service) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, StreamsPath)
+	st, ok := streams[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown stream %q", name), http.StatusNotFound)
+		return
+	}
+	query := r.URL.Query()
+	filter, err := st.parseFilters(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c, err := decodeCursor(name, query.Get(cursorParam))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := defaultLimit
+	if l := query.Get(limitParam); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	resp, err := s.streamPage(r.Context(), st, c, limit, filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.WithError(err).Error("Could not write explorer response")
+	}
+}
+
+func (s *Service) streamPage(
+	ctx context.Context,
+	st *stream,
+	c *cursor,
+	limit int,
+	filter entryFilter,
+) (*streamResponse, error) {
+	chain, err := s.finalizedChain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &streamResponse{Entries: []*streamEntry{}, FinalizedSlot: chain.slot}
+	if c.slot > chain.slot {
+		resp.NextCursor = c.encode()
+		return resp, nil
+	}
+	scanEnd := chain.slot
+	if c.slot+maxScanSlots-1 < scanEnd {
+		scanEnd = c.slot + maxScanSlots - 1
+	}
+
+	next := c
+	for start := c.slot; start <= scanEnd; start += scanBatchSlots {
+		end := start + scanBatchSlots - 1
+		if end > scanEnd {
+			end = scanEnd
+		}
+		blocks, err := s.finalizedBlocks(ctx, chain, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, blk := range blocks {
+			entries := st.entries(blk)
+			first := uint64(0)
+			if blk.Block.Slot == c.slot {
+				first = c.index
+			}
+			for i := first; i < uint64(len(entries)); i++ {
+				if len(resp.Entries) == limit {
+					resp.NextCursor = (&cursor{stream: c.stream, slot: blk.Block.Slot, index: i}).encode()
+					return resp, nil
+				}
+				if !filter(blk, entries[i]) {
+					continue
+				}
+				e, err := newStreamEntry(blk, entries[i], i)
+				if err != nil {
+					return nil, err
+				}
+				e.Cursor = (&cursor{stream: c.stream, slot: blk.Block.Slot, index: i + 1}).encode()
+				resp.Entries = append(resp.Entries, e)
+			}
+		}
+		next = &cursor{stream: c.stream, slot: end + 1}
+		if len(resp.Entries) == limit {
+			break
+		}
+	}
+	resp.NextCursor = next.encode()
+	return resp, nil
+}
+
+func newStreamEntry(blk *ethpb.SignedBeaconBlock, e *entry, index uint64) (*streamEntry, error) {
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block root")
+	}
+	data, err := marshalJSON(e.msg)
+	if err != nil {
+		return nil, err
+	}
+	return &streamEntry{
+		Kind:      e.kind,
+		Slot:      blk.Block.Slot,
+		BlockRoot: fmt.Sprintf("%#x", root),
+		Index:     index,
+		Data:      data,
+	}, nil
+}
+
+// finalizedChain is the finalized part of the canonical chain.
+type finalizedChain struct {
+	// slot of the finalized block.
+	slot uint64
+	// epochStart is the first slot of the finalized block's epoch.
+	epochStart uint64
+	// recent are the roots of the canonical blocks from epochStart to the finalized block. The
+	// finalized block roots index is not canonical for the epoch of the finalized block until a
+	// later epoch is finalized, so these blocks are found by walking back from the finalized block.
+	recent map[[32]byte]bool
+}
+
+func (s *Service) finalizedChain(ctx context.Context) (*finalizedChain, error) {
+	cp := s.finalizationFetcher.FinalizedCheckpt()
+	var blk *ethpb.SignedBeaconBlock
+	var err error
+	if cp == nil || bytesutil.ToBytes32(cp.Root) == params.BeaconConfig().ZeroHash {
+		blk, err = s.beaconDB.GenesisBlock(ctx)
+	} else {
+		blk, err = s.beaconDB.Block(ctx, bytesutil.ToBytes32(cp.Root))
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get finalized block")
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, errors.New("finalized block not found")
+	}
+	chain := &finalizedChain{
+		slot:       blk.Block.Slot,
+		epochStart: helpers.StartSlot(helpers.SlotToEpoch(blk.Block.Slot)),
+		recent:     make(map[[32]byte]bool),
+	}
+	for blk != nil && blk.Block != nil && blk.Block.Slot >= chain.epochStart {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute block root")
+		}
+		chain.recent[root] = true
+		if blk.Block.Slot == 0 {
+			break
+		}
+		blk, err = s.beaconDB.Block(ctx, bytesutil.ToBytes32(blk.Block.ParentRoot))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get block")
+		}
+	}
+	return chain, nil
+}
+
+// finalizedBlocks returns the finalized canonical blocks of the slot range, ordered by slot. The
+// range must end at or before the finalized block.
+func (s *Service) finalizedBlocks(
+	ctx context.Context,
+	chain *finalizedChain,
+	startSlot uint64,
+	endSlot uint64,
+) ([]*ethpb.SignedBeaconBlock, error) {
+	var blocks []*ethpb.SignedBeaconBlock
+	if endSlot == 0 {
+		// A zero end slot leaves the slot range of the db query unbounded, the genesis block is
+		// the only block of the range.
+		genesis, err := s.beaconDB.GenesisBlock(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get genesis block")
+		}
+		blocks = append(blocks, genesis)
+	} else {
+		var err error
+		blocks, err = s.beaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(endSlot))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get blocks")
+		}
+	}
+
+	canonical := make([]*ethpb.SignedBeaconBlock, 0, len(blocks))
+	for _, blk := range blocks {
+		if blk == nil || blk.Block == nil || blk.Block.Slot < startSlot || blk.Block.Slot > endSlot {
+			continue
+		}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute block root")
+		}
+		if blk.Block.Slot >= chain.epochStart {
+			if chain.recent[root] {
+				canonical = append(canonical, blk)
+			}
+		} else if s.beaconDB.IsFinalizedBlock(ctx, root) {
+			canonical = append(canonical, blk)
+		}
+	}
+	sort.Slice(canonical, func(i, j int) bool {
+		return canonical[i].Block.Slot < canonical[j].Block.Slot
+	})
+	return canonical, nil
+}
+
+func marshalJSON(msg proto.Message) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := marshaler.Marshal(buf, msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,195 @@
+package explorer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// setupChain saves a chain of blocks at slots 0 to 40, finalized at the block of slot 32, and a
+// fork block at slot 32. Attestations of committees 0 and 1 are included at slot 3 and of
+// committee 1 at slot 10.
+func setupChain(t *testing.T) *Service {
+	ctx := context.Background()
+	db := dbtest.SetupDB(t)
+
+	parent := make([]byte, 32)
+	var finalizedRoot [32]byte
+	for slot := uint64(0); slot <= 40; slot++ {
+		blk := testutil.NewBeaconBlock()
+		blk.Block.Slot = slot
+		blk.Block.ParentRoot = parent
+		switch slot {
+		case 3:
+			blk.Block.Body.Attestations = []*ethpb.Attestation{attestation(0), attestation(1)}
+		case 10:
+			blk.Block.Body.Attestations = []*ethpb.Attestation{attestation(1)}
+		case 32:
+			fork := testutil.NewBeaconBlock()
+			fork.Block.Slot = slot
+			fork.Block.ParentRoot = parent
+			fork.Block.Body.Graffiti = bytesutil.PadTo([]byte("fork"), 32)
+			if err := db.SaveBlock(ctx, fork); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slot == 0 {
+			if err := db.SaveGenesisBlockRoot(ctx, root); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if slot == 32 {
+			finalizedRoot = root
+		}
+		parent = root[:]
+	}
+
+	cp := &ethpb.Checkpoint{Epoch: 1, Root: finalizedRoot[:]}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), finalizedRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, cp); err != nil {
+		t.Fatal(err)
+	}
+	return NewService(&Config{
+		BeaconDB:            db,
+		FinalizationFetcher: &mock.ChainService{FinalizedCheckPoint: cp},
+	})
+}
+
+func attestation(committeeIndex uint64) *ethpb.Attestation {
+	return &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			CommitteeIndex:  committeeIndex,
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		AggregationBits: []byte{1},
+		Signature:       make([]byte, 96),
+	}
+}
+
+func requestStream(t *testing.T, s *Service, name string, query url.Values) (*streamResponse, int) {
+	rec := httptest.NewRecorder()
+	s.StreamHandler(rec, httptest.NewRequest(http.MethodGet, StreamsPath+name+"?"+query.Encode(), nil))
+	if rec.Code != http.StatusOK {
+		return nil, rec.Code
+	}
+	resp := &streamResponse{}
+	if err := json.NewDecoder(rec.Body).Decode(resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp, rec.Code
+}
+
+func TestStreamHandler_BlocksExactlyOnce(t *testing.T) {
+	s := setupChain(t)
+
+	var slots []uint64
+	var graffiti []string
+	query := url.Values{"limit": {"10"}}
+	for i := 0; i < 10; i++ {
+		resp, code := requestStream(t, s, BlocksStream, query)
+		if code != http.StatusOK {
+			t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+		}
+		if resp.FinalizedSlot != 32 {
+			t.Errorf("Wanted finalized slot 32, received %d", resp.FinalizedSlot)
+		}
+		if len(resp.Entries) == 0 {
+			break
+		}
+		for _, e := range resp.Entries {
+			slots = append(slots, e.Slot)
+			blk := &ethpb.SignedBeaconBlock{}
+			if err := jsonpb.UnmarshalString(string(e.Data), blk); err != nil {
+				t.Fatal(err)
+			}
+			graffiti = append(graffiti, string(blk.Block.Body.Graffiti))
+		}
+		query.Set("cursor", resp.NextCursor)
+	}
+
+	var want []uint64
+	for slot := uint64(0); slot <= 32; slot++ {
+		want = append(want, slot)
+	}
+	if !reflect.DeepEqual(want, slots) {
+		t.Errorf("Wanted blocks at slots %v, received %v", want, slots)
+	}
+	for _, g := range graffiti {
+		if strings.HasPrefix(g, "fork") {
+			t.Error("Streamed fork block which is not canonical")
+		}
+	}
+}
+
+func TestStreamHandler_FilteredAttestations(t *testing.T) {
+	s := setupChain(t)
+
+	query := url.Values{"committee_index": {"1"}, "limit": {"1"}}
+	var positions []string
+	for i := 0; i < 5; i++ {
+		resp, code := requestStream(t, s, AttestationsStream, query)
+		if code != http.StatusOK {
+			t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+		}
+		if len(resp.Entries) == 0 {
+			break
+		}
+		e := resp.Entries[0]
+		positions = append(positions, fmt.Sprintf("%d:%d", e.Slot, e.Index))
+
+		query.Set("cursor", resp.NextCursor)
+	}
+	want := []string{"3:1", "10:0"}
+	if !reflect.DeepEqual(want, positions) {
+		t.Errorf("Wanted attestations at %v, received %v", want, positions)
+	}
+}
+
+func TestStreamHandler_InvalidRequests(t *testing.T) {
+	s := setupChain(t)
+	blocksCursor := (&cursor{stream: BlocksStream, slot: 3}).encode()
+	tests := []struct {
+		name   string
+		stream string
+		query  url.Values
+		code   int
+	}{
+		{name: "unknown stream", stream: "transactions", code: http.StatusNotFound},
+		{name: "cursor of other stream", stream: DepositsStream, query: url.Values{"cursor": {blocksCursor}}, code: http.StatusBadRequest},
+		{name: "unsupported filter", stream: BlocksStream, query: url.Values{"committee_index": {"1"}}, code: http.StatusBadRequest},
+		{name: "invalid filter value", stream: VoluntaryExitsStream, query: url.Values{"validator_index": {"a"}}, code: http.StatusBadRequest},
+		{name: "limit too large", stream: BlocksStream, query: url.Values{"limit": {"1001"}}, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, code := requestStream(t, s, tt.stream, tt.query); code != tt.code {
+				t.Errorf("Wanted status %d, received %d", tt.code, code)
+			}
+		})
+	}
+}
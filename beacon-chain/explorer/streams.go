@@ -0,0 +1,234 @@
+package explorer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// Names of the streams, which are served at StreamsPath followed by the name.
+const (
+	BlocksStream         = "blocks"
+	AttestationsStream   = "attestations"
+	DepositsStream       = "deposits"
+	VoluntaryExitsStream = "voluntary_exits"
+	SlashingsStream      = "slashings"
+)
+
+// Query parameters of the streams. Parameters other than the cursor and limit filter the entries.
+const (
+	cursorParam         = "cursor"
+	limitParam          = "limit"
+	proposerIndexParam  = "proposer_index"
+	committeeIndexParam = "committee_index"
+	validatorIndexParam = "validator_index"
+	publicKeyParam      = "pubkey"
+)
+
+// entry is an object of a stream, with the kind of object for streams of several kinds.
+type entry struct {
+	kind string
+	msg  proto.Message
+}
+
+// stream defines the entries of a stream in a block and the filters which select them. An entry
+// is identified by its block slot and its position in the block's entries, which does not depend
+// on the filters, so cursors can be resumed with other filters.
+type stream struct {
+	entries func(blk *ethpb.SignedBeaconBlock) []*entry
+	// filters maps the supported query parameters to the parser of their values.
+	filters map[string]func(value string) (entryFilter, error)
+}
+
+// entryFilter returns whether an entry of the block is selected.
+type entryFilter func(blk *ethpb.SignedBeaconBlock, e *entry) bool
+
+var streams = map[string]*stream{
+	BlocksStream: {
+		entries: func(blk *ethpb.SignedBeaconBlock) []*entry {
+			return []*entry{{kind: "block", msg: blk}}
+		},
+		filters: map[string]func(string) (entryFilter, error){
+			proposerIndexParam: indexFilter(func(blk *ethpb.SignedBeaconBlock, _ *entry) []uint64 {
+				return []uint64{blk.Block.ProposerIndex}
+			}),
+		},
+	},
+	AttestationsStream: {
+		entries: func(blk *ethpb.SignedBeaconBlock) []*entry {
+			entries := make([]*entry, len(blk.Block.Body.Attestations))
+			for i, att := range blk.Block.Body.Attestations {
+				entries[i] = &entry{kind: "attestation", msg: att}
+			}
+			return entries
+		},
+		filters: map[string]func(string) (entryFilter, error){
+			committeeIndexParam: indexFilter(func(_ *ethpb.SignedBeaconBlock, e *entry) []uint64 {
+				return []uint64{e.msg.(*ethpb.Attestation).Data.CommitteeIndex}
+			}),
+		},
+	},
+	DepositsStream: {
+		entries: func(blk *ethpb.SignedBeaconBlock) []*entry {
+			entries := make([]*entry, len(blk.Block.Body.Deposits))
+			for i, deposit := range blk.Block.Body.Deposits {
+				entries[i] = &entry{kind: "deposit", msg: deposit}
+			}
+			return entries
+		},
+		filters: map[string]func(string) (entryFilter, error){
+			publicKeyParam: func(value string) (entryFilter, error) {
+				pubKey, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+				if err != nil || len(pubKey) != 48 {
+					return nil, fmt.Errorf("invalid %s %q", publicKeyParam, value)
+				}
+				return func(_ *ethpb.SignedBeaconBlock, e *entry) bool {
+					return bytes.Equal(e.msg.(*ethpb.Deposit).Data.PublicKey, pubKey)
+				}, nil
+			},
+		},
+	},
+	VoluntaryExitsStream: {
+		entries: func(blk *ethpb.SignedBeaconBlock) []*entry {
+			entries := make([]*entry, len(blk.Block.Body.VoluntaryExits))
+			for i, exit := range blk.Block.Body.VoluntaryExits {
+				entries[i] = &entry{kind: "voluntary_exit", msg: exit}
+			}
+			return entries
+		},
+		filters: map[string]func(string) (entryFilter, error){
+			validatorIndexParam: indexFilter(func(_ *ethpb.SignedBeaconBlock, e *entry) []uint64 {
+				return []uint64{e.msg.(*ethpb.SignedVoluntaryExit).Exit.ValidatorIndex}
+			}),
+		},
+	},
+	SlashingsStream: {
+		entries: func(blk *ethpb.SignedBeaconBlock) []*entry {
+			var entries []*entry
+			for _, slashing := range blk.Block.Body.ProposerSlashings {
+				entries = append(entries, &entry{kind: "proposer_slashing", msg: slashing})
+			}
+			for _, slashing := range blk.Block.Body.AttesterSlashings {
+				entries = append(entries, &entry{kind: "attester_slashing", msg: slashing})
+			}
+			return entries
+		},
+		filters: map[string]func(string) (entryFilter, error){
+			validatorIndexParam: indexFilter(func(_ *ethpb.SignedBeaconBlock, e *entry) []uint64 {
+				return slashedIndices(e.msg)
+			}),
+		},
+	},
+}
+
+// slashedIndices returns the indices of the validators slashed by a proposer or attester slashing,
+// the attester slashing slashing the validators which attested to both attestations.
+func slashedIndices(msg proto.Message) []uint64 {
+	switch slashing := msg.(type) {
+	case *ethpb.ProposerSlashing:
+		return []uint64{slashing.Header_1.Header.ProposerIndex}
+	case *ethpb.AttesterSlashing:
+		attested := make(map[uint64]bool)
+		for _, index := range slashing.Attestation_1.AttestingIndices {
+			attested[index] = true
+		}
+		var indices []uint64
+		for _, index := range slashing.Attestation_2.AttestingIndices {
+			if attested[index] {
+				indices = append(indices, index)
+			}
+		}
+		return indices
+	}
+	return nil
+}
+
+// indexFilter returns the parser of a filter selecting the entries one of whose indices is the
+// filter value.
+func indexFilter(indices func(blk *ethpb.SignedBeaconBlock, e *entry) []uint64) func(string) (entryFilter, error) {
+	return func(value string) (entryFilter, error) {
+		want, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q", value)
+		}
+		return func(blk *ethpb.SignedBeaconBlock, e *entry) bool {
+			for _, index := range indices(blk, e) {
+				if index == want {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+}
+
+// parseFilters returns the filter of the query parameters, failing on parameters the stream does
+// not support and on invalid values.
+func (s *stream) parseFilters(query url.Values) (entryFilter, error) {
+	var selected []entryFilter
+	for param := range query {
+		if param == cursorParam || param == limitParam {
+			continue
+		}
+		parse, ok := s.filters[param]
+		if !ok {
+			return nil, fmt.Errorf("unsupported parameter %q", param)
+		}
+		filter, err := parse(query.Get(param))
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, filter)
+	}
+	return func(blk *ethpb.SignedBeaconBlock, e *entry) bool {
+		for _, filter := range selected {
+			if !filter(blk, e) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// cursor is the position in a stream of the next entry to return: the slot of its block and its
+// position in the block's entries.
+type cursor struct {
+	stream string
+	slot   uint64
+	index  uint64
+}
+
+// encode returns the cursor as an opaque resume token.
+func (c *cursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d:%d", c.stream, c.slot, c.index)))
+}
+
+// decodeCursor parses a resume token of the stream. An empty token is the start of the stream.
+func decodeCursor(streamName string, token string) (*cursor, error) {
+	c := &cursor{stream: streamName}
+	if token == "" {
+		return c, nil
+	}
+	dec, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor %q", token)
+	}
+	parts := strings.Split(string(dec), ":")
+	if len(parts) != 3 || parts[0] != streamName {
+		return nil, fmt.Errorf("cursor %q is not a cursor of the %s stream", token, streamName)
+	}
+	if c.slot, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid cursor %q", token)
+	}
+	if c.index, err = strconv.ParseUint(parts[2], 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid cursor %q", token)
+	}
+	return c, nil
+}
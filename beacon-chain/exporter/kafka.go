@@ -0,0 +1,54 @@
+// +build kafka_enabled
+
+package exporter
+
+import (
+	"context"
+
+	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+	_ "gopkg.in/confluentinc/confluent-kafka-go.v1/kafka/librdkafka" // Required for c++ kafka library.
+)
+
+// flushTimeoutMs is how long closing the sink waits for queued records to be delivered.
+const flushTimeoutMs = 10 * 1000
+
+type kafkaSink struct {
+	p *kafka.Producer
+}
+
+// NewKafkaSink creates a producer publishing the records of every topic to the kafka topic of
+// the same name on the given bootstrap servers.
+func NewKafkaSink(bootstrapServers string) (Sink, error) {
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": bootstrapServers})
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaSink{p: p}, nil
+}
+
+// Write queues the records for delivery by the producer.
+func (k *kafkaSink) Write(ctx context.Context, records []*Record) error {
+	for _, r := range records {
+		topic := r.Topic
+		if err := k.p.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{
+				Topic:     &topic,
+				Partition: kafka.PartitionAny,
+			},
+			Key:   []byte(r.Key),
+			Value: r.Data,
+		}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close delivers the queued records and closes the producer.
+func (k *kafkaSink) Close() error {
+	if remaining := k.p.Flush(flushTimeoutMs); remaining > 0 {
+		log.WithField("records", remaining).Warn("Could not deliver all exported records to kafka")
+	}
+	k.p.Close()
+	return nil
+}
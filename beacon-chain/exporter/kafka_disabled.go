@@ -0,0 +1,12 @@
+// +build !kafka_enabled
+
+package exporter
+
+import (
+	"errors"
+)
+
+// NewKafkaSink is not available in nodes built without the kafka_enabled tag.
+func NewKafkaSink(bootstrapServers string) (Sink, error) {
+	return nil, errors.New("exporting to kafka requires a beacon node built with the kafka_enabled tag")
+}
@@ -0,0 +1,62 @@
+package exporter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// postgresDriver is the database/sql driver name of PostgreSQL.
+const postgresDriver = "postgres"
+
+type postgresSink struct {
+	db *sql.DB
+}
+
+// NewPostgresSink connects to the PostgreSQL database at the given URL and creates a table for
+// every exported topic, holding the records' keys and JSON data.
+func NewPostgresSink(ctx context.Context, url string) (Sink, error) {
+	db, err := sql.Open(postgresDriver, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open postgres database")
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not connect to postgres database")
+	}
+	for _, topic := range topics {
+		query := fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, data JSONB NOT NULL, exported_at TIMESTAMPTZ NOT NULL DEFAULT now())",
+			topic,
+		)
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return nil, errors.Wrapf(err, "could not create table %s", topic)
+		}
+	}
+	return &postgresSink{db: db}, nil
+}
+
+// Write inserts the records in a single transaction. Records whose key was already exported
+// are skipped.
+func (p *postgresSink) Write(ctx context.Context, records []*Record) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		query := fmt.Sprintf("INSERT INTO %s (key, data) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING", r.Topic)
+		if _, err := tx.ExecContext(ctx, query, r.Key, string(r.Data)); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.WithError(rollbackErr).Error("Could not roll back export transaction")
+			}
+			return errors.Wrapf(err, "could not insert record into %s", r.Topic)
+		}
+	}
+	return tx.Commit()
+}
+
+// Close the database connections.
+func (p *postgresSink) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,5 @@
+package exporter
+
+import (
+	_ "github.com/lib/pq" // Registers the postgres database/sql driver.
+)
@@ -0,0 +1,355 @@
+// Package exporter defines a service which writes finalized blocks, attestations, validator
+// status changes and chain reorgs to external sinks such as a PostgreSQL database or kafka
+// topics, so chain data can be indexed without scraping the node's RPC.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "exporter")
+
+var _ = shared.Service(&Service{})
+
+var marshaler = &jsonpb.Marshaler{}
+
+// Config options for the exporter service.
+type Config struct {
+	BeaconDB      db.NoHeadAccessDatabase
+	HeadFetcher   blockchain.HeadFetcher
+	StateNotifier statefeed.Notifier
+	StateGen      *stategen.State
+	// Sinks the chain data is written to.
+	Sinks []Sink
+}
+
+// Service exports chain data to the configured sinks as it is finalized.
+type Service struct {
+	ctx                context.Context
+	cancel             context.CancelFunc
+	cfg                *Config
+	lastFinalizedEpoch uint64
+	lastExportedSlot   uint64
+	exportedAny        bool
+	// validators of the last exported finalized state, to find the changed validators of the next.
+	validators []*ethpb.Validator
+	lock       sync.RWMutex
+	lastErr    error
+}
+
+// validatorStatus is the exported registry entry of a validator which changed between two
+// finalized states.
+type validatorStatus struct {
+	Index                      uint64 `json:"index"`
+	PublicKey                  string `json:"public_key"`
+	Epoch                      uint64 `json:"epoch"`
+	ActivationEligibilityEpoch uint64 `json:"activation_eligibility_epoch"`
+	ActivationEpoch            uint64 `json:"activation_epoch"`
+	ExitEpoch                  uint64 `json:"exit_epoch"`
+	WithdrawableEpoch          uint64 `json:"withdrawable_epoch"`
+	Slashed                    bool   `json:"slashed"`
+}
+
+// reorg is the exported record of a chain reorg.
+type reorg struct {
+	OldSlot            uint64 `json:"old_slot"`
+	NewSlot            uint64 `json:"new_slot"`
+	OldHeadRoot        string `json:"old_head_root"`
+	NewHeadRoot        string `json:"new_head_root"`
+	CommonAncestorSlot uint64 `json:"common_ancestor_slot"`
+	Depth              uint64 `json:"depth"`
+}
+
+// NewService initializes the exporter service from configuration options.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:    ctx,
+		cancel: cancel,
+		cfg:    cfg,
+	}
+}
+
+// Start the exporter event loop.
+func (s *Service) Start() {
+	go s.run()
+}
+
+// Stop the exporter event loop and close the sinks.
+func (s *Service) Stop() error {
+	s.cancel()
+	for _, sink := range s.cfg.Sinks {
+		if err := sink.Close(); err != nil {
+			log.WithError(err).Error("Could not close export sink")
+		}
+	}
+	return nil
+}
+
+// Status returns the error of the last failed export, if the following exports have not succeeded.
+func (s *Service) Status() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.lastErr
+}
+
+func (s *Service) run() {
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.cfg.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	for {
+		select {
+		case event := <-stateChannel:
+			var err error
+			switch event.Type {
+			case statefeed.BlockProcessed:
+				err = s.exportFinalized(s.ctx)
+			case statefeed.Reorg:
+				data, ok := event.Data.(*statefeed.ReorgData)
+				if !ok {
+					log.Error("Event feed data is not type *statefeed.ReorgData")
+					continue
+				}
+				err = s.exportReorg(s.ctx, data)
+			default:
+				continue
+			}
+			if err != nil {
+				log.WithError(err).Error("Could not export chain data")
+			}
+			s.lock.Lock()
+			s.lastErr = err
+			s.lock.Unlock()
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting goroutine")
+			return
+		case err := <-stateSub.Err():
+			log.WithError(err).Error("Subscription to state feed notifier failed")
+			return
+		}
+	}
+}
+
+// exportFinalized writes the blocks finalized since the last export, their attestations and the
+// validators changed by them once the head state's finalized checkpoint advances. The first
+// export after startup only covers the finalized checkpoint block and the full registry.
+func (s *Service) exportFinalized(ctx context.Context) error {
+	headState, err := s.cfg.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve head state")
+	}
+	cpt := headState.FinalizedCheckpoint()
+	if cpt == nil || cpt.Epoch == 0 || cpt.Epoch <= s.lastFinalizedEpoch {
+		return nil
+	}
+	root := bytesutil.ToBytes32(cpt.Root)
+
+	blocks, err := s.finalizedBlocks(ctx, root)
+	if err != nil {
+		return err
+	}
+	var records []*Record
+	for _, blk := range blocks {
+		blkRecords, err := blockRecords(blk)
+		if err != nil {
+			return err
+		}
+		records = append(records, blkRecords...)
+	}
+	finalizedState, err := s.finalizedState(ctx, root)
+	if err != nil {
+		return err
+	}
+	validators := finalizedState.Validators()
+	statusRecords, err := s.validatorStatusRecords(validators, cpt.Epoch)
+	if err != nil {
+		return err
+	}
+	records = append(records, statusRecords...)
+
+	if err := s.write(ctx, records); err != nil {
+		return err
+	}
+	s.lastFinalizedEpoch = cpt.Epoch
+	if len(blocks) > 0 {
+		s.lastExportedSlot = blocks[len(blocks)-1].Block.Slot
+	}
+	s.exportedAny = true
+	s.validators = validators
+	log.WithFields(logrus.Fields{
+		"epoch":   cpt.Epoch,
+		"blocks":  len(blocks),
+		"records": len(records),
+	}).Debug("Exported finalized chain data")
+	return nil
+}
+
+// finalizedBlocks walks back from the finalized block root to the last exported block, returning
+// the blocks in ascending slot order.
+func (s *Service) finalizedBlocks(ctx context.Context, root [32]byte) ([]*ethpb.SignedBeaconBlock, error) {
+	var blocks []*ethpb.SignedBeaconBlock
+	for {
+		blk, err := s.cfg.BeaconDB.Block(ctx, root)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not retrieve block %#x", root)
+		}
+		if blk == nil || (s.exportedAny && blk.Block.Slot <= s.lastExportedSlot) {
+			break
+		}
+		blocks = append(blocks, blk)
+		if !s.exportedAny || blk.Block.Slot == 0 {
+			break
+		}
+		root = bytesutil.ToBytes32(blk.Block.ParentRoot)
+	}
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+	return blocks, nil
+}
+
+func (s *Service) finalizedState(ctx context.Context, root [32]byte) (*state.BeaconState, error) {
+	var finalizedState *state.BeaconState
+	var err error
+	if featureconfig.Get().NewStateMgmt {
+		finalizedState, err = s.cfg.StateGen.StateByRoot(ctx, root)
+	} else {
+		finalizedState, err = s.cfg.BeaconDB.State(ctx, root)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not retrieve finalized state")
+	}
+	if finalizedState == nil {
+		return nil, fmt.Errorf("finalized state with root %#x not found", root)
+	}
+	return finalizedState, nil
+}
+
+// validatorStatusRecords returns records of the validators which were added or changed since the
+// previously exported finalized state.
+func (s *Service) validatorStatusRecords(validators []*ethpb.Validator, epoch uint64) ([]*Record, error) {
+	var records []*Record
+	for i, v := range validators {
+		if i < len(s.validators) && sameStatus(s.validators[i], v) {
+			continue
+		}
+		status := &validatorStatus{
+			Index:                      uint64(i),
+			PublicKey:                  fmt.Sprintf("%#x", v.PublicKey),
+			Epoch:                      epoch,
+			ActivationEligibilityEpoch: v.ActivationEligibilityEpoch,
+			ActivationEpoch:            v.ActivationEpoch,
+			ExitEpoch:                  v.ExitEpoch,
+			WithdrawableEpoch:          v.WithdrawableEpoch,
+			Slashed:                    v.Slashed,
+		}
+		data, err := json.Marshal(status)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &Record{
+			Topic: ValidatorStatusTopic,
+			Key:   fmt.Sprintf("%d:%d", i, epoch),
+			Data:  data,
+		})
+	}
+	return records, nil
+}
+
+// sameStatus returns whether the validator fields which make up its status, leaving out its
+// balance, are equal.
+func sameStatus(a *ethpb.Validator, b *ethpb.Validator) bool {
+	return a.ActivationEligibilityEpoch == b.ActivationEligibilityEpoch &&
+		a.ActivationEpoch == b.ActivationEpoch &&
+		a.ExitEpoch == b.ExitEpoch &&
+		a.WithdrawableEpoch == b.WithdrawableEpoch &&
+		a.Slashed == b.Slashed
+}
+
+func (s *Service) exportReorg(ctx context.Context, data *statefeed.ReorgData) error {
+	enc, err := json.Marshal(&reorg{
+		OldSlot:            data.OldSlot,
+		NewSlot:            data.NewSlot,
+		OldHeadRoot:        fmt.Sprintf("%#x", data.OldHeadRoot),
+		NewHeadRoot:        fmt.Sprintf("%#x", data.NewHeadRoot),
+		CommonAncestorSlot: data.CommonAncestorSlot,
+		Depth:              data.Depth,
+	})
+	if err != nil {
+		return err
+	}
+	return s.write(ctx, []*Record{{
+		Topic: ReorgsTopic,
+		Key:   fmt.Sprintf("%#x:%#x", data.OldHeadRoot, data.NewHeadRoot),
+		Data:  enc,
+	}})
+}
+
+func (s *Service) write(ctx context.Context, records []*Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	for _, sink := range s.cfg.Sinks {
+		if err := sink.Write(ctx, records); err != nil {
+			return errors.Wrap(err, "could not write to export sink")
+		}
+	}
+	return nil
+}
+
+// blockRecords returns the records of a block and of the attestations it includes.
+func blockRecords(blk *ethpb.SignedBeaconBlock) ([]*Record, error) {
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block root")
+	}
+	data, err := marshalJSON(blk)
+	if err != nil {
+		return nil, err
+	}
+	records := []*Record{{
+		Topic: BlocksTopic,
+		Key:   fmt.Sprintf("%#x", root),
+		Data:  data,
+	}}
+	for i, att := range blk.Block.Body.Attestations {
+		data, err := marshalJSON(att)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &Record{
+			Topic: AttestationsTopic,
+			Key:   fmt.Sprintf("%#x:%d", root, i),
+			Data:  data,
+		})
+	}
+	return records, nil
+}
+
+func marshalJSON(msg proto.Message) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := marshaler.Marshal(buf, msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
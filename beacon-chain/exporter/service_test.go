@@ -0,0 +1,177 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+type memorySink struct {
+	records []*Record
+}
+
+func (m *memorySink) Write(_ context.Context, records []*Record) error {
+	m.records = append(m.records, records...)
+	return nil
+}
+
+func (m *memorySink) Close() error {
+	return nil
+}
+
+func (m *memorySink) keys(topic string) []string {
+	var keys []string
+	for _, r := range m.records {
+		if r.Topic == topic {
+			keys = append(keys, r.Key)
+		}
+	}
+	return keys
+}
+
+func newValidator(key byte, exitEpoch uint64) *ethpb.Validator {
+	return &ethpb.Validator{
+		PublicKey:             bytesutil.PadTo([]byte{key}, 48),
+		WithdrawalCredentials: make([]byte, 32),
+		ExitEpoch:             exitEpoch,
+	}
+}
+
+func TestExportFinalized_ExportsBlocksSinceLastFinalized(t *testing.T) {
+	ctx := context.Background()
+	db := dbutil.SetupDB(t)
+
+	// Build the chain of blocks at slots 0 to 3, the block at slot 2 including an attestation.
+	var roots [][32]byte
+	parent := make([]byte, 32)
+	for slot := uint64(0); slot < 4; slot++ {
+		blk := testutil.NewBeaconBlock()
+		blk.Block.Slot = slot
+		blk.Block.ParentRoot = parent
+		if slot == 2 {
+			blk.Block.Body.Attestations = []*ethpb.Attestation{{
+				Data: &ethpb.AttestationData{
+					Slot:            1,
+					BeaconBlockRoot: make([]byte, 32),
+					Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+					Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				},
+				AggregationBits: []byte{1},
+				Signature:       make([]byte, 96),
+			}}
+		}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+		parent = root[:]
+	}
+
+	validators := []*ethpb.Validator{
+		newValidator('a', 100),
+		newValidator('b', 100),
+	}
+	firstState := testutil.NewBeaconState()
+	if err := firstState.SetValidators(validators); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, firstState, roots[1]); err != nil {
+		t.Fatal(err)
+	}
+	secondState := testutil.NewBeaconState()
+	if err := secondState.SetValidators([]*ethpb.Validator{
+		validators[0],
+		newValidator('b', 3),
+		newValidator('c', 100),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, secondState, roots[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	sink := &memorySink{}
+	headState := testutil.NewBeaconState()
+	s := NewService(ctx, &Config{
+		BeaconDB:    db,
+		HeadFetcher: &mock.ChainService{State: headState},
+		Sinks:       []Sink{sink},
+	})
+
+	// The first export only covers the finalized block and the full registry.
+	if err := headState.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 1, Root: roots[1][:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.exportFinalized(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantBlocks := []string{fmt.Sprintf("%#x", roots[1])}
+	if keys := sink.keys(BlocksTopic); !reflect.DeepEqual(wantBlocks, keys) {
+		t.Errorf("Wanted exported blocks %v, received %v", wantBlocks, keys)
+	}
+	wantStatuses := []string{"0:1", "1:1"}
+	if keys := sink.keys(ValidatorStatusTopic); !reflect.DeepEqual(wantStatuses, keys) {
+		t.Errorf("Wanted exported validator statuses %v, received %v", wantStatuses, keys)
+	}
+
+	// Exporting the same finalized checkpoint again is a no-op.
+	exported := len(sink.records)
+	if err := s.exportFinalized(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.records) != exported {
+		t.Errorf("Wanted no new records, received %d", len(sink.records)-exported)
+	}
+
+	// The next export covers the blocks since the last finalized block and the changed validators.
+	sink.records = nil
+	if err := headState.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 2, Root: roots[3][:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.exportFinalized(ctx); err != nil {
+		t.Fatal(err)
+	}
+	wantBlocks = []string{fmt.Sprintf("%#x", roots[2]), fmt.Sprintf("%#x", roots[3])}
+	if keys := sink.keys(BlocksTopic); !reflect.DeepEqual(wantBlocks, keys) {
+		t.Errorf("Wanted exported blocks %v, received %v", wantBlocks, keys)
+	}
+	wantAtts := []string{fmt.Sprintf("%#x:0", roots[2])}
+	if keys := sink.keys(AttestationsTopic); !reflect.DeepEqual(wantAtts, keys) {
+		t.Errorf("Wanted exported attestations %v, received %v", wantAtts, keys)
+	}
+	wantStatuses = []string{"1:2", "2:2"}
+	if keys := sink.keys(ValidatorStatusTopic); !reflect.DeepEqual(wantStatuses, keys) {
+		t.Errorf("Wanted exported validator statuses %v, received %v", wantStatuses, keys)
+	}
+}
+
+func TestExportReorg(t *testing.T) {
+	sink := &memorySink{}
+	s := NewService(context.Background(), &Config{Sinks: []Sink{sink}})
+	data := &statefeed.ReorgData{
+		OldSlot:     5,
+		NewSlot:     4,
+		OldHeadRoot: [32]byte{'a'},
+		NewHeadRoot: [32]byte{'b'},
+	}
+	if err := s.exportReorg(context.Background(), data); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{fmt.Sprintf("%#x:%#x", data.OldHeadRoot, data.NewHeadRoot)}
+	if keys := sink.keys(ReorgsTopic); !reflect.DeepEqual(want, keys) {
+		t.Errorf("Wanted exported reorgs %v, received %v", want, keys)
+	}
+}
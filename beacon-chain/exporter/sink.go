@@ -0,0 +1,38 @@
+package exporter
+
+import (
+	"context"
+)
+
+// Topics of the exported records, each written to the kafka topic or SQL table of the same name.
+const (
+	// BlocksTopic holds finalized signed blocks keyed by block root.
+	BlocksTopic = "beacon_blocks"
+	// AttestationsTopic holds the attestations included in finalized blocks.
+	AttestationsTopic = "beacon_attestations"
+	// ValidatorStatusTopic holds the validator registry changes observed between finalized states.
+	ValidatorStatusTopic = "validator_status_changes"
+	// ReorgsTopic holds the chain reorgs observed by the node.
+	ReorgsTopic = "chain_reorgs"
+)
+
+var topics = []string{BlocksTopic, AttestationsTopic, ValidatorStatusTopic, ReorgsTopic}
+
+// Record is a single item of exported chain data.
+type Record struct {
+	// Topic is the kind of data of the record.
+	Topic string
+	// Key uniquely identifies the record within its topic, so records exported twice can
+	// be deduplicated by the sink's consumers.
+	Key string
+	// Data is the JSON encoding of the record.
+	Data []byte
+}
+
+// Sink is a destination chain data is exported to.
+type Sink interface {
+	// Write the records to the sink. The records are written in order.
+	Write(ctx context.Context, records []*Record) error
+	// Close flushes pending records and releases the sink's connections.
+	Close() error
+}
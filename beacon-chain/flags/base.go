@@ -3,14 +3,17 @@
 package flags
 
 import (
+	"math"
+	"time"
+
 	"github.com/urfave/cli/v2"
 )
 
 var (
-	// HTTPWeb3ProviderFlag provides an HTTP access endpoint to an ETH 1.0 RPC.
+	// HTTPWeb3ProviderFlag provides HTTP access endpoints to an ETH 1.0 RPC.
 	HTTPWeb3ProviderFlag = &cli.StringFlag{
 		Name:  "http-web3provider",
-		Usage: "A mainchain web3 provider string http endpoint",
+		Usage: "A mainchain web3 provider string http endpoint. Comma separated endpoints are fallen back to in order when one fails or stalls",
 		Value: "https://goerli.prylabs.net",
 	}
 	// DepositContractFlag defines a flag for the deposit contract address.
@@ -38,6 +41,20 @@ var (
 		Usage: "Max number of items returned per page in RPC responses for paginated endpoints.",
 		Value: 500,
 	}
+	// RPCMaxRecvMsgSizeFlag defines the max size of the messages received by the RPC server.
+	RPCMaxRecvMsgSizeFlag = &cli.IntFlag{
+		Name:  "rpc-max-recv-msg-size",
+		Usage: "Max size in bytes of the messages received by the RPC server",
+		Value: 1 << 22,
+	}
+	// RPCMaxSendMsgSizeFlag defines the max size of the messages sent by the RPC server.
+	RPCMaxSendMsgSizeFlag = &cli.IntFlag{
+		Name: "rpc-max-send-msg-size",
+		Usage: "Max size in bytes of the messages sent by the RPC server. Clients limit the size of the messages " +
+			"they receive as well, to 4MB by default, which mainnet beacon states exceed: raise their limit or " +
+			"stream states in chunks from the DebugStream service",
+		Value: math.MaxInt32,
+	}
 	// MonitoringPortFlag defines the http port used to serve prometheus metrics.
 	MonitoringPortFlag = &cli.Int64Flag{
 		Name:  "monitoring-port",
@@ -54,6 +71,39 @@ var (
 		Name:  "tls-key",
 		Usage: "Key for secure gRPC. Pass this and the tls-cert flag in order to use gRPC securely.",
 	}
+	// ClientCACertFlag defines the certificate authority that client certificates must be signed by.
+	ClientCACertFlag = &cli.StringFlag{
+		Name: "tls-client-ca",
+		Usage: "Certificate authority file for mutual TLS. If set, gRPC clients such as validators must present " +
+			"a certificate signed by it. Requires tls-cert and tls-key",
+	}
+	// RPCAuthFlag requires an auth token for mutating RPC and gateway endpoints.
+	RPCAuthFlag = &cli.BoolFlag{
+		Name: "rpc-auth",
+		Usage: "Require an auth token for RPC and JSON-HTTP gateway endpoints which change the state of the node. " +
+			"The token is generated on first start and stored in the file given by --rpc-auth-token-file",
+	}
+	// RPCAuthTokenFileFlag defines the file the RPC auth token is stored in.
+	RPCAuthTokenFileFlag = &cli.StringFlag{
+		Name:  "rpc-auth-token-file",
+		Usage: "File the RPC auth token is read from or generated in, defaults to auth-token in the data directory",
+	}
+	// RPCQuotaConfigFlag defines a YAML file of per-method RPC quotas.
+	RPCQuotaConfigFlag = &cli.StringFlag{
+		Name: "rpc-quota-config",
+		Usage: "YAML file mapping RPC methods or services to per-client request quotas, replacing the default quotas " +
+			"of heavyweight state and history queries",
+	}
+	// DisableRPCQuotasFlag disables rate limiting of RPC requests.
+	DisableRPCQuotasFlag = &cli.BoolFlag{
+		Name:  "disable-rpc-quotas",
+		Usage: "Disable per-method rate limiting of RPC and JSON-HTTP gateway requests",
+	}
+	// RPCLogRequestsFlag enables logging of RPC requests.
+	RPCLogRequestsFlag = &cli.BoolFlag{
+		Name:  "rpc-log-requests",
+		Usage: "Log every RPC request at debug level and slow requests at warn level",
+	}
 	// DisableGRPCGateway for JSON-HTTP requests to the beacon node.
 	DisableGRPCGateway = &cli.BoolFlag{
 		Name:  "disable-grpc-gateway",
@@ -130,9 +180,142 @@ var (
 		Usage: "The factor by which block batch limit may increase on burst.",
 		Value: 10,
 	}
+	// BlockBatchLimitGlobalFactor specifies how many peers' block batch limits the node serves at most per second.
+	BlockBatchLimitGlobalFactor = &cli.IntFlag{
+		Name:  "block-batch-limit-global-factor",
+		Usage: "The factor of the block batch limit bounding the blocks served to all peers combined.",
+		Value: 8,
+	}
 	// EnableDebugRPCEndpoints as /v1/beacon/state.
 	EnableDebugRPCEndpoints = &cli.BoolFlag{
 		Name:  "enable-debug-rpc-endpoints",
-		Usage: "Enables the debug rpc service, containing utility endpoints such as /eth/v1alpha1/beacon/state. Requires --new-state-mgmt",
+		Usage: "Enables the debug rpc service, containing utility endpoints such as /eth/v1alpha1/beacon/state and the block replay routes of the HTTP API. Requires --new-state-mgmt",
+	}
+	// EraDirFlag defines a directory of era archive files to serve old blocks from.
+	EraDirFlag = &cli.StringFlag{
+		Name:  "era-dir",
+		Usage: "Directory of era archive files to serve blocks by range requests for the slots they cover, instead of the database",
+	}
+	// RestoreSourceFileFlag defines the backup file restored by the db restore command.
+	RestoreSourceFileFlag = &cli.StringFlag{
+		Name:  "restore-source-file",
+		Usage: "Backup file created by the db backup command, or the /db/backup monitoring endpoint, to restore the database from",
+	}
+	// DumpSlotFlag defines the slot of which the db dump command writes the blocks and states.
+	DumpSlotFlag = &cli.Uint64Flag{
+		Name:  "slot",
+		Usage: "Slot of which the blocks, their pre states and post states are written as SSZ files",
+	}
+	// DumpOutputDirFlag defines the directory the db dump command writes the SSZ files to.
+	DumpOutputDirFlag = &cli.StringFlag{
+		Name:  "output-dir",
+		Usage: "Directory to write the SSZ encoded blocks and states to",
+		Value: ".",
+	}
+	// ImportBlockFileFlag defines an SSZ encoded signed block saved by the db import command.
+	ImportBlockFileFlag = &cli.StringFlag{
+		Name:  "block-file",
+		Usage: "File of an SSZ encoded signed beacon block to save to the database",
+	}
+	// ImportStateFileFlag defines an SSZ encoded state saved by the db import command.
+	ImportStateFileFlag = &cli.StringFlag{
+		Name:  "state-file",
+		Usage: "File of an SSZ encoded post block state to save to the database, under the root of its latest block",
+	}
+	// ShutdownTimeoutFlag defines how long the node waits for its services to stop before closing the database.
+	ShutdownTimeoutFlag = &cli.DurationFlag{
+		Name:  "shutdown-timeout",
+		Usage: "Maximum time to wait for services to stop and persist their data on shutdown before closing the database",
+		Value: 30 * time.Second,
+	}
+	// DiskWarnThresholdFlag defines the free disk space below which the node warns about running out of space.
+	DiskWarnThresholdFlag = &cli.Uint64Flag{
+		Name:  "disk-warn-threshold-gb",
+		Usage: "Free disk space in GB of the data directory, i.e. the expected database growth, below which the node warns at startup and while running",
+		Value: 50,
+	}
+	// DiskEmergencyThresholdFlag defines the free disk space below which the node prunes historical states.
+	DiskEmergencyThresholdFlag = &cli.Uint64Flag{
+		Name: "disk-emergency-threshold-gb",
+		Usage: "Free disk space in GB of the data directory below which the node refuses to start and, while running, " +
+			"deletes finalized states which are not needed to regenerate others. 0 disables the check",
+		Value: 10,
+	}
+	// PruneStatesFlag enables the pruning of forks and of the history older than the retention period.
+	PruneStatesFlag = &cli.BoolFlag{
+		Name: "prune-states",
+		Usage: "Deletes the blocks and states of finalized forks, and the finalized blocks and states older than " +
+			"--retention-epochs. The node can no longer serve the pruned history",
+	}
+	// RetentionEpochsFlag defines how many epochs of finalized blocks and states are kept when pruning.
+	RetentionEpochsFlag = &cli.Uint64Flag{
+		Name: "retention-epochs",
+		Usage: "Number of epochs behind the finalized checkpoint of which blocks and states are kept with --prune-states. " +
+			"Values below the weak subjectivity period are raised to it",
+	}
+	// WeakSubjectivityCheckpointFlag defines a trusted checkpoint the node's chain must contain.
+	WeakSubjectivityCheckpointFlag = &cli.StringFlag{
+		Name: "weak-subjectivity-checkpoint",
+		Usage: "Trusted weak subjectivity checkpoint in the format block_root:epoch, e.g. 0x1234...:100. " +
+			"The node refuses to follow a chain which does not contain the checkpoint",
+	}
+	// CheckpointStateFlag defines a trusted state the beacon node starts from instead of genesis.
+	CheckpointStateFlag = &cli.StringFlag{
+		Name: "checkpoint-state",
+		Usage: "File path or http(s) URL of a trusted SSZ encoded beacon state at the start of a finalized epoch, " +
+			"to start the node from instead of genesis. Must be used with --checkpoint-block",
+	}
+	// CheckpointBlockFlag defines the block of the trusted checkpoint state.
+	CheckpointBlockFlag = &cli.StringFlag{
+		Name:  "checkpoint-block",
+		Usage: "File path or http(s) URL of the SSZ encoded signed block of the --checkpoint-state",
+	}
+	// GenesisStateFlag defines the known genesis state the beacon node starts from.
+	GenesisStateFlag = &cli.StringFlag{
+		Name: "genesis-state",
+		Usage: "File path or http(s) URL of the SSZ encoded genesis state of the chain, to start from it instead of " +
+			"detecting genesis from the deposit contract. Defaults to the genesis state embedded for the --network",
+	}
+	// ExportPostgresURLFlag defines a PostgreSQL database finalized chain data is exported to.
+	ExportPostgresURLFlag = &cli.StringFlag{
+		Name: "export-postgres-url",
+		Usage: "PostgreSQL connection URL to export finalized blocks, attestations, validator status changes " +
+			"and reorgs to",
+	}
+	// ExportKafkaURLFlag defines kafka servers finalized chain data is exported to.
+	ExportKafkaURLFlag = &cli.StringFlag{
+		Name: "export-kafka-url",
+		Usage: "Kafka bootstrap servers to export finalized blocks, attestations, validator status changes " +
+			"and reorgs to. Requires a beacon node built with the kafka_enabled tag",
+	}
+	// EnableGraphQLFlag enables the GraphQL endpoint of the gRPC gateway.
+	EnableGraphQLFlag = &cli.BoolFlag{
+		Name:  "enable-graphql",
+		Usage: "Serves chain and validator data over a GraphQL endpoint at /graphql on the gRPC gateway port",
+	}
+	// GraphQLMaxComplexityFlag defines the most expensive query the GraphQL endpoint executes.
+	GraphQLMaxComplexityFlag = &cli.Int64Flag{
+		Name: "graphql-max-complexity",
+		Usage: "Maximum complexity of a GraphQL query, where each resolved block, attestation and validator " +
+			"costs 1 and each loaded state costs 100",
+		Value: 10000,
+	}
+	// ExplorerAPIPortFlag defines the port of the explorer streaming API.
+	ExplorerAPIPortFlag = &cli.IntFlag{
+		Name: "explorer-api-port",
+		Usage: "Port to serve cursor paginated streams of finalized blocks, attestations, deposits, exits and " +
+			"slashings on, for block explorers and indexers. Disabled when 0",
+	}
+	// HTTPAPIPortFlag defines the port of the Eth2 beacon node HTTP API.
+	HTTPAPIPortFlag = &cli.IntFlag{
+		Name: "http-api-port",
+		Usage: "Port to serve the /eth/v1/beacon, /eth/v1/node and /eth/v1/validator routes of the standard " +
+			"Eth2 beacon node HTTP API on, alongside gRPC. Disabled when 0",
+	}
+	// CacheMemoryBudgetFlag defines the memory shared by the committee, hot state, domain and public key caches.
+	CacheMemoryBudgetFlag = &cli.Uint64Flag{
+		Name: "cache-memory-budget-mb",
+		Usage: "Estimated memory in MB shared by the committee, hot state, domain and public key caches, " +
+			"above which their least recently used entries are evicted. Unlimited when 0",
 	}
 )
@@ -20,6 +20,7 @@ type GlobalFlags struct {
 	DeploymentBlock                   int
 	BlockBatchLimit                   int
 	BlockBatchLimitBurstFactor        int
+	BlockBatchLimitGlobalFactor       int
 }
 
 var globalConfig *GlobalFlags
@@ -61,6 +62,7 @@ func ConfigureGlobalFlags(ctx *cli.Context) {
 	}
 	cfg.BlockBatchLimit = ctx.Int(BlockBatchLimit.Name)
 	cfg.BlockBatchLimitBurstFactor = ctx.Int(BlockBatchLimitBurstFactor.Name)
+	cfg.BlockBatchLimitGlobalFactor = ctx.Int(BlockBatchLimitGlobalFactor.Name)
 	cfg.MaxPageSize = ctx.Int(RPCMaxPageSize.Name)
 	cfg.DeploymentBlock = ctx.Int(ContractDeploymentBlock.Name)
 	configureMinimumPeers(ctx, cfg)
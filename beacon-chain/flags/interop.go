@@ -8,7 +8,7 @@ var (
 	// InteropGenesisStateFlag defines a flag for the beacon node to load genesis state via file.
 	InteropGenesisStateFlag = &cli.StringFlag{
 		Name:  "interop-genesis-state",
-		Usage: "The genesis state file (.SSZ) to load from",
+		Usage: "The genesis state file (.SSZ) to load from. Unless --http-web3provider is set, the node runs without an eth1 connection",
 	}
 	// InteropMockEth1DataVotesFlag enables mocking the eth1 proof-of-work chain data put into blocks by proposers.
 	InteropMockEth1DataVotesFlag = &cli.BoolFlag{
@@ -24,7 +24,8 @@ var (
 	}
 	// InteropNumValidatorsFlag specifies number of genesis validators for state generation.
 	InteropNumValidatorsFlag = &cli.Uint64Flag{
-		Name:  "interop-num-validators",
-		Usage: "Specify number of genesis validators to generate for interop. Must be used with --interop-genesis-time",
+		Name: "interop-num-validators",
+		Usage: "Specify number of genesis validators to generate for interop. Genesis time defaults to now unless " +
+			"--interop-genesis-time is set. Unless --http-web3provider is set, the node runs without an eth1 connection",
 	}
 )
@@ -109,10 +109,10 @@ func (s *Store) insert(ctx context.Context,
 	return nil
 }
 
-// applyWeightChanges iterates backwards through the Nodes in store. It checks all Nodes parent
-// and its best child. For each node, it updates the weight with input delta and
-// back propagate the Nodes delta to its parents delta. After scoring changes,
-// the best child is then updated along with best descendant.
+// applyWeightChanges iterates backwards through the Nodes in store. For each node, it updates
+// the weight with input delta and back propagate the Nodes delta to its parents delta. After
+// scoring changes, the best child is then updated along with best descendant in a second pass,
+// so that every child is compared with the final weights of its siblings.
 func (s *Store) applyWeightChanges(ctx context.Context, justifiedEpoch uint64, finalizedEpoch uint64, delta []int) error {
 	ctx, span := trace.StartSpan(ctx, "protoArrayForkChoice.applyWeightChanges")
 	defer span.End()
@@ -155,17 +155,25 @@ func (s *Store) applyWeightChanges(ctx context.Context, justifiedEpoch uint64, f
 
 		s.Nodes[i] = n
 
-		// Update parent's best child and descendent if the node has a known parent.
+		// Back propagate the Nodes delta to its parent if the node has a known parent.
 		if n.Parent != NonExistentNode {
 			// Protection against node parent index out of bound. This should not happen.
 			if int(n.Parent) >= len(delta) {
 				return errInvalidParentDelta
 			}
-			// Back propagate the Nodes delta to its parent.
 			delta[n.Parent] += nodeDelta
-			if err := s.updateBestChildAndDescendant(n.Parent, uint64(i)); err != nil {
-				return err
-			}
+		}
+	}
+
+	// Update parent's best child and descendent of every node with a known parent. Iterating
+	// backwards, the best descendant of a node is final before its parent is updated.
+	for i := len(s.Nodes) - 1; i >= 0; i-- {
+		n := s.Nodes[i]
+		if n.Root == params.BeaconConfig().ZeroHash || n.Parent == NonExistentNode {
+			continue
+		}
+		if err := s.updateBestChildAndDescendant(n.Parent, uint64(i)); err != nil {
+			return err
 		}
 	}
 
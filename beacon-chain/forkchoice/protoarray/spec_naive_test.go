@@ -0,0 +1,185 @@
+package protoarray
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// naiveForkChoice is the LMD GHOST fork choice as written in the specification, computing the
+// weight of every block from the latest votes of all validators on every head computation. It is
+// the reference of the proto-array fork choice in tests and benchmarks.
+type naiveForkChoice struct {
+	parents  map[[32]byte][32]byte
+	children map[[32]byte][][32]byte
+	votes    map[uint64]naiveVote
+}
+
+// naiveVote is the latest message of a validator.
+type naiveVote struct {
+	root  [32]byte
+	epoch uint64
+}
+
+func newNaiveForkChoice() *naiveForkChoice {
+	return &naiveForkChoice{
+		parents:  make(map[[32]byte][32]byte),
+		children: make(map[[32]byte][][32]byte),
+		votes:    make(map[uint64]naiveVote),
+	}
+}
+
+func (n *naiveForkChoice) processBlock(root [32]byte, parent [32]byte) {
+	n.parents[root] = parent
+	n.children[parent] = append(n.children[parent], root)
+}
+
+// processAttestation updates the latest messages of the validators, unless they already voted
+// in the target epoch or a later one.
+func (n *naiveForkChoice) processAttestation(indices []uint64, root [32]byte, targetEpoch uint64) {
+	for _, i := range indices {
+		if vote, ok := n.votes[i]; !ok || targetEpoch > vote.epoch {
+			n.votes[i] = naiveVote{root: root, epoch: targetEpoch}
+		}
+	}
+}
+
+// head walks down from the justified root to the child of the highest weight, breaking ties by
+// the highest root.
+func (n *naiveForkChoice) head(justifiedRoot [32]byte, balances []uint64) [32]byte {
+	head := justifiedRoot
+	for {
+		children := n.children[head]
+		if len(children) == 0 {
+			return head
+		}
+		best, bestWeight := children[0], n.weight(children[0], balances)
+		for _, c := range children[1:] {
+			w := n.weight(c, balances)
+			if w > bestWeight || (w == bestWeight && bytes.Compare(c[:], best[:]) > 0) {
+				best, bestWeight = c, w
+			}
+		}
+		head = best
+	}
+}
+
+// weight returns the balance of the validators whose latest vote is for the block or one of its
+// descendants.
+func (n *naiveForkChoice) weight(root [32]byte, balances []uint64) uint64 {
+	var weight uint64
+	for i, vote := range n.votes {
+		for r, ok := vote.root, true; ok; r, ok = n.parents[r] {
+			if r == root {
+				weight += balances[i]
+				break
+			}
+		}
+	}
+	return weight
+}
+
+// forkChoiceScenario inserts a random block tree into both fork choices, returning the block roots.
+func forkChoiceScenario(t testing.TB, rng *rand.Rand, f *ForkChoice, n *naiveForkChoice, numBlocks int) [][32]byte {
+	roots := [][32]byte{params.BeaconConfig().ZeroHash}
+	for i := 1; i <= numBlocks; i++ {
+		// Blocks build on one of the recent blocks, forking the chain.
+		parent := roots[len(roots)-1-rng.Intn(min(len(roots), 4))]
+		root := indexToHash(uint64(i))
+		if err := f.ProcessBlock(context.Background(), uint64(i), root, parent, [32]byte{}, 1, 1); err != nil {
+			t.Fatal(err)
+		}
+		n.processBlock(root, parent)
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// randomVotes moves the votes of a random subset of the validators to random blocks. The zero
+// root of the anchor block is not voted for, as proto-array takes it for the absence of a vote.
+func randomVotes(rng *rand.Rand, f *ForkChoice, n *naiveForkChoice, roots [][32]byte, numValidators int, numVotes int, epoch uint64) {
+	for i := 0; i < numVotes; i++ {
+		index := []uint64{uint64(rng.Intn(numValidators))}
+		root := roots[1+rng.Intn(len(roots)-1)]
+		f.ProcessAttestation(context.Background(), index, root, epoch)
+		n.processAttestation(index, root, epoch)
+	}
+}
+
+func randomBalances(rng *rand.Rand, numValidators int) []uint64 {
+	balances := make([]uint64, numValidators)
+	for i := range balances {
+		balances[i] = uint64(rng.Intn(32)+1) * 1e9
+	}
+	return balances
+}
+
+func TestHead_MatchesSpecNaive(t *testing.T) {
+	const numValidators = 256
+	for seed := int64(1); seed <= 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		f, n := setup(1, 1), newNaiveForkChoice()
+		roots := forkChoiceScenario(t, rng, f, n, 64)
+		for epoch := uint64(1); epoch <= 5; epoch++ {
+			randomVotes(rng, f, n, roots, numValidators, numValidators/2, epoch)
+			// Balances change between head computations as in the justified state.
+			balances := randomBalances(rng, numValidators)
+			head, err := f.Head(context.Background(), 1, params.BeaconConfig().ZeroHash, balances, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := n.head(params.BeaconConfig().ZeroHash, balances); head != want {
+				t.Errorf("Seed %d epoch %d: proto-array head %#x, spec head %#x", seed, epoch, head, want)
+			}
+		}
+	}
+}
+
+// The head computation benchmarks move the votes of a slot's worth of validators between every
+// head computation, as happens when the attestations of a slot are processed.
+const (
+	benchmarkBlocks     = 256
+	benchmarkValidators = 4096
+)
+
+func BenchmarkHead_ProtoArray(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	f, n := setup(1, 1), newNaiveForkChoice()
+	roots := forkChoiceScenario(b, rng, f, n, benchmarkBlocks)
+	balances := randomBalances(rng, benchmarkValidators)
+	votesPerSlot := benchmarkValidators / int(params.BeaconConfig().SlotsPerEpoch)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		randomVotes(rng, f, n, roots, benchmarkValidators, votesPerSlot, uint64(i+1))
+		b.StartTimer()
+		if _, err := f.Head(context.Background(), 1, params.BeaconConfig().ZeroHash, balances, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHead_SpecNaive(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	f, n := setup(1, 1), newNaiveForkChoice()
+	roots := forkChoiceScenario(b, rng, f, n, benchmarkBlocks)
+	balances := randomBalances(rng, benchmarkValidators)
+	votesPerSlot := benchmarkValidators / int(params.BeaconConfig().SlotsPerEpoch)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		randomVotes(rng, f, n, roots, benchmarkValidators, votesPerSlot, uint64(i+1))
+		b.StartTimer()
+		n.head(params.BeaconConfig().ZeroHash, balances)
+	}
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCorsHandler_Preflight(t *testing.T) {
+	srv := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := newCorsHandler(srv, []string{"http://localhost:4242", " "})
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{origin: "http://localhost:4242", allowed: true},
+		{origin: "http://example.com", allowed: false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodOptions, "/eth/v1alpha1/node/version", nil)
+		req.Header.Set("Origin", tt.origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		got := rec.Header().Get("Access-Control-Allow-Origin")
+		if tt.allowed && got != tt.origin {
+			t.Errorf("Wanted origin %s to be allowed, received %q", tt.origin, got)
+		}
+		if !tt.allowed && got != "" {
+			t.Errorf("Wanted origin %s to be rejected, received %q", tt.origin, got)
+		}
+	}
+}
+
+func TestCorsHandler_NoOrigins(t *testing.T) {
+	srv := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	// The split of an unset flag is a single empty origin.
+	handler := newCorsHandler(srv, []string{""})
+
+	req := httptest.NewRequest(http.MethodGet, "/eth/v1alpha1/node/version", nil)
+	req.Header.Set("Origin", "http://localhost:4242")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("Wanted request to reach the server, received status %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Wanted no CORS headers, received %q", got)
+	}
+}
@@ -13,8 +13,10 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1_gateway"
 	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1_gateway"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/grpcutils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
 )
 
 var _ = shared.Service(&Gateway{})
@@ -27,12 +29,14 @@ type Gateway struct {
 	cancel                  context.CancelFunc
 	gatewayAddr             string
 	remoteAddr              string
+	remoteCreds             credentials.TransportCredentials
 	server                  *http.Server
 	mux                     *http.ServeMux
 	allowedOrigins          []string
 	startFailure            error
 	enableDebugRPCEndpoints bool
 	maxCallRecvMsgSize      uint64
+	maxCallSendMsgSize      uint64
 }
 
 // Start the gateway service. This serves the HTTP JSON traffic on the specified
@@ -120,15 +124,18 @@ func (g *Gateway) Stop() error {
 }
 
 // New returns a new gateway server which translates HTTP into gRPC.
-// Accepts a context and optional http.ServeMux.
+// Accepts a context and optional http.ServeMux. The gateway connects to the gRPC
+// server with remoteCreds, or insecurely if they are nil.
 func New(
 	ctx context.Context,
-	remoteAddress,
+	remoteAddress string,
+	remoteCreds credentials.TransportCredentials,
 	gatewayAddress string,
 	mux *http.ServeMux,
 	allowedOrigins []string,
 	enableDebugRPCEndpoints bool,
 	maxCallRecvMsgSize uint64,
+	maxCallSendMsgSize uint64,
 ) *Gateway {
 	if mux == nil {
 		mux = http.NewServeMux()
@@ -136,12 +143,14 @@ func New(
 
 	return &Gateway{
 		remoteAddr:              remoteAddress,
+		remoteCreds:             remoteCreds,
 		gatewayAddr:             gatewayAddress,
 		ctx:                     ctx,
 		mux:                     mux,
 		allowedOrigins:          allowedOrigins,
 		enableDebugRPCEndpoints: enableDebugRPCEndpoints,
 		maxCallRecvMsgSize:      maxCallRecvMsgSize,
+		maxCallSendMsgSize:      maxCallSendMsgSize,
 	}
 }
 
@@ -160,11 +169,22 @@ func (g *Gateway) dial(ctx context.Context, network, addr string) (*grpc.ClientC
 // dialTCP creates a client connection via TCP.
 // "addr" must be a valid TCP address with a port number.
 func (g *Gateway) dialTCP(ctx context.Context, addr string) (*grpc.ClientConn, error) {
-	opts := []grpc.DialOption{grpc.WithInsecure()}
-
-	if g.enableDebugRPCEndpoints {
+	security := grpc.WithInsecure()
+	if g.remoteCreds != nil {
+		security = grpc.WithTransportCredentials(g.remoteCreds)
+	}
+	opts := []grpc.DialOption{
+		security,
+		grpc.WithUnaryInterceptor(grpcutils.CompressLargeResponses),
+	}
+	// Responses such as validator lists are not limited to debug endpoints, so the
+	// receive size limit applies to every call.
+	if g.maxCallRecvMsgSize > 0 {
 		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(int(g.maxCallRecvMsgSize))))
 	}
+	if g.maxCallSendMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(int(g.maxCallSendMsgSize))))
+	}
 
 	return grpc.DialContext(
 		ctx,
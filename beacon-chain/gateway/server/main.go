@@ -6,13 +6,16 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 
 	joonix "github.com/joonix/log"
 	"github.com/prysmaticlabs/prysm/beacon-chain/gateway"
+	"github.com/prysmaticlabs/prysm/shared/grpcutils"
 	"github.com/sirupsen/logrus"
 	_ "go.uber.org/automaxprocs"
+	"google.golang.org/grpc/credentials"
 )
 
 var (
@@ -22,6 +25,10 @@ var (
 	allowedOrigins          = flag.String("corsdomain", "", "A comma separated list of CORS domains to allow")
 	enableDebugRPCEndpoints = flag.Bool("enable-debug-rpc-endpoints", false, "Enable debug rpc endpoints such as /eth/v1alpha1/beacon/state")
 	grpcMaxMsgSize          = flag.Int("grpc-max-msg-size", 1<<22, "Integer to define max recieve message call size")
+	grpcMaxSendMsgSize      = flag.Int("grpc-max-send-msg-size", math.MaxInt32, "Integer to define max send message call size")
+	tlsCert                 = flag.String("tls-cert", "", "Certificate of the beacon chain gRPC endpoint, which is connected to insecurely if not set")
+	tlsClientCert           = flag.String("tls-client-cert", "", "Certificate presented to a beacon chain gRPC endpoint which requires client certificates")
+	tlsClientKey            = flag.String("tls-client-key", "", "Key of the certificate presented to the beacon chain gRPC endpoint")
 )
 
 func init() {
@@ -36,15 +43,26 @@ func main() {
 		log.SetLevel(logrus.DebugLevel)
 	}
 
+	var creds credentials.TransportCredentials
+	if *tlsCert != "" {
+		c, err := grpcutils.ClientTLSCredentials(*tlsCert, *tlsClientCert, *tlsClientKey)
+		if err != nil {
+			log.WithError(err).Fatal("Could not load TLS credentials")
+		}
+		creds = c
+	}
+
 	mux := http.NewServeMux()
 	gw := gateway.New(
 		context.Background(),
 		*beaconRPC,
+		creds,
 		fmt.Sprintf("0.0.0.0:%d", *port),
 		mux,
 		strings.Split(*allowedOrigins, ","),
 		*enableDebugRPCEndpoints,
 		uint64(*grpcMaxMsgSize),
+		uint64(*grpcMaxSendMsgSize),
 	)
 	mux.HandleFunc("/swagger/", gateway.SwaggerServer())
 	mux.HandleFunc("/healthz", healthzServer(gw))
@@ -0,0 +1,378 @@
+package graphql
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+type queryResolver struct {
+	s *Server
+}
+
+// Head resolves the head block.
+func (r *queryResolver) Head(ctx context.Context) (*blockResolver, error) {
+	blk, err := r.s.headFetcher.HeadBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head block")
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, nil
+	}
+	return r.s.newBlockResolver(ctx, blk)
+}
+
+type blockArgs struct {
+	Root *string
+	Slot *Uint64
+}
+
+// Block resolves a block by root or slot.
+func (r *queryResolver) Block(ctx context.Context, args blockArgs) (*blockResolver, error) {
+	switch {
+	case args.Root != nil && args.Slot == nil:
+		root, err := hex.DecodeString(strings.TrimPrefix(*args.Root, "0x"))
+		if err != nil || len(root) != 32 {
+			return nil, errors.New("invalid block root")
+		}
+		return r.s.blockByRoot(ctx, bytesutil.ToBytes32(root))
+	case args.Slot != nil && args.Root == nil:
+		blocks, err := r.s.canonicalBlocks(ctx, uint64(*args.Slot), uint64(*args.Slot))
+		if err != nil || len(blocks) == 0 {
+			return nil, err
+		}
+		return blocks[0], nil
+	default:
+		return nil, errors.New("exactly one of root or slot must be given")
+	}
+}
+
+type blocksArgs struct {
+	StartSlot Uint64
+	EndSlot   Uint64
+}
+
+// Blocks resolves the canonical blocks of a slot range.
+func (r *queryResolver) Blocks(ctx context.Context, args blocksArgs) ([]*blockResolver, error) {
+	if args.EndSlot < args.StartSlot {
+		return nil, errors.New("end slot is before start slot")
+	}
+	if args.EndSlot-args.StartSlot >= maxBlocksPerQuery {
+		return nil, fmt.Errorf("slot range exceeds the maximum of %d slots", maxBlocksPerQuery)
+	}
+	return r.s.canonicalBlocks(ctx, uint64(args.StartSlot), uint64(args.EndSlot))
+}
+
+type validatorArgs struct {
+	Index Uint64
+}
+
+// Validator resolves a validator of the head state.
+func (r *queryResolver) Validator(ctx context.Context, args validatorArgs) (*validatorResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	st, err := r.s.headFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head state")
+	}
+	if st == nil || uint64(args.Index) >= uint64(st.NumValidators()) {
+		return nil, nil
+	}
+	return newValidatorResolver(st, uint64(args.Index))
+}
+
+func (s *Server) blockByRoot(ctx context.Context, root [32]byte) (*blockResolver, error) {
+	blk, err := s.beaconDB.Block(ctx, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get block")
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, nil
+	}
+	return s.newBlockResolver(ctx, blk)
+}
+
+// canonicalBlocks returns the blocks of the canonical chain in the slot range, leaving out the
+// blocks of forks.
+func (s *Server) canonicalBlocks(ctx context.Context, startSlot uint64, endSlot uint64) ([]*blockResolver, error) {
+	var blocks []*ethpb.SignedBeaconBlock
+	if endSlot == 0 {
+		// A zero end slot leaves the slot range of the db query unbounded, the genesis block is
+		// the only block of the range.
+		genesis, err := s.beaconDB.GenesisBlock(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get genesis block")
+		}
+		blocks = append(blocks, genesis)
+	} else {
+		var err error
+		blocks, err = s.beaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(endSlot))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get blocks")
+		}
+	}
+	resolvers := make([]*blockResolver, 0, len(blocks))
+	for _, blk := range blocks {
+		if blk == nil || blk.Block == nil {
+			continue
+		}
+		resolver, err := s.newBlockResolver(ctx, blk)
+		if err != nil {
+			return nil, err
+		}
+		canonical, err := s.canonicalFetcher.IsCanonical(ctx, resolver.root)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not check if block is canonical")
+		}
+		if canonical {
+			resolvers = append(resolvers, resolver)
+		}
+	}
+	return resolvers, nil
+}
+
+type blockResolver struct {
+	s    *Server
+	blk  *ethpb.SignedBeaconBlock
+	root [32]byte
+
+	// The post-state of the block is loaded once, on the first field which needs it.
+	stateOnce sync.Once
+	state     *stateTrie.BeaconState
+	stateErr  error
+}
+
+func (s *Server) newBlockResolver(ctx context.Context, blk *ethpb.SignedBeaconBlock) (*blockResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block root")
+	}
+	return &blockResolver{s: s, blk: blk, root: root}, nil
+}
+
+// postState returns the state after processing the block.
+func (b *blockResolver) postState(ctx context.Context) (*stateTrie.BeaconState, error) {
+	b.stateOnce.Do(func() {
+		if b.stateErr = charge(ctx, stateCost); b.stateErr != nil {
+			return
+		}
+		if featureconfig.Get().NewStateMgmt {
+			b.state, b.stateErr = b.s.stateGen.StateByRoot(ctx, b.root)
+		} else {
+			b.state, b.stateErr = b.s.beaconDB.State(ctx, b.root)
+		}
+		if b.stateErr != nil {
+			b.stateErr = errors.Wrap(b.stateErr, "could not get state")
+			return
+		}
+		if b.state == nil {
+			b.stateErr = fmt.Errorf("state of block %#x not found", b.root)
+		}
+	})
+	return b.state, b.stateErr
+}
+
+// Root of the block.
+func (b *blockResolver) Root() string {
+	return fmt.Sprintf("%#x", b.root)
+}
+
+// Slot of the block.
+func (b *blockResolver) Slot() Uint64 {
+	return Uint64(b.blk.Block.Slot)
+}
+
+// ProposerIndex of the block.
+func (b *blockResolver) ProposerIndex() Uint64 {
+	return Uint64(b.blk.Block.ProposerIndex)
+}
+
+// ParentRoot of the block.
+func (b *blockResolver) ParentRoot() string {
+	return fmt.Sprintf("%#x", b.blk.Block.ParentRoot)
+}
+
+// StateRoot of the block.
+func (b *blockResolver) StateRoot() string {
+	return fmt.Sprintf("%#x", b.blk.Block.StateRoot)
+}
+
+// Parent resolves the parent block, which is nil for the genesis block.
+func (b *blockResolver) Parent(ctx context.Context) (*blockResolver, error) {
+	return b.s.blockByRoot(ctx, bytesutil.ToBytes32(b.blk.Block.ParentRoot))
+}
+
+// Proposer resolves the proposer of the block.
+func (b *blockResolver) Proposer(ctx context.Context) (*validatorResolver, error) {
+	if err := charge(ctx, 1); err != nil {
+		return nil, err
+	}
+	st, err := b.postState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newValidatorResolver(st, b.blk.Block.ProposerIndex)
+}
+
+// Attestations resolves the attestations included in the block.
+func (b *blockResolver) Attestations(ctx context.Context) ([]*attestationResolver, error) {
+	atts := b.blk.Block.Body.Attestations
+	if err := charge(ctx, int64(len(atts))); err != nil {
+		return nil, err
+	}
+	resolvers := make([]*attestationResolver, len(atts))
+	for i, att := range atts {
+		resolvers[i] = &attestationResolver{block: b, att: att}
+	}
+	return resolvers, nil
+}
+
+type attestationResolver struct {
+	block *blockResolver
+	att   *ethpb.Attestation
+}
+
+// Slot of the attestation data.
+func (a *attestationResolver) Slot() Uint64 {
+	return Uint64(a.att.Data.Slot)
+}
+
+// CommitteeIndex of the attestation data.
+func (a *attestationResolver) CommitteeIndex() Uint64 {
+	return Uint64(a.att.Data.CommitteeIndex)
+}
+
+// BeaconBlockRoot voted for by the attestation.
+func (a *attestationResolver) BeaconBlockRoot() string {
+	return fmt.Sprintf("%#x", a.att.Data.BeaconBlockRoot)
+}
+
+// SourceEpoch of the attestation.
+func (a *attestationResolver) SourceEpoch() Uint64 {
+	return Uint64(a.att.Data.Source.Epoch)
+}
+
+// TargetEpoch of the attestation.
+func (a *attestationResolver) TargetEpoch() Uint64 {
+	return Uint64(a.att.Data.Target.Epoch)
+}
+
+// AggregationBits of the attestation.
+func (a *attestationResolver) AggregationBits() string {
+	return fmt.Sprintf("%#x", []byte(a.att.AggregationBits))
+}
+
+// Committee resolves the validators of the attestation's committee.
+func (a *attestationResolver) Committee(ctx context.Context) ([]*validatorResolver, error) {
+	return a.committee(ctx, false)
+}
+
+// Attesters resolves the committee validators whose aggregation bits are set.
+func (a *attestationResolver) Attesters(ctx context.Context) ([]*validatorResolver, error) {
+	return a.committee(ctx, true)
+}
+
+func (a *attestationResolver) committee(ctx context.Context, attestersOnly bool) ([]*validatorResolver, error) {
+	st, err := a.block.postState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	committee, err := helpers.BeaconCommitteeFromState(st, a.att.Data.Slot, a.att.Data.CommitteeIndex)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get committee")
+	}
+	var indices []uint64
+	for i, index := range committee {
+		if !attestersOnly || a.att.AggregationBits.BitAt(uint64(i)) {
+			indices = append(indices, index)
+		}
+	}
+	if err := charge(ctx, int64(len(indices))); err != nil {
+		return nil, err
+	}
+	resolvers := make([]*validatorResolver, len(indices))
+	for i, index := range indices {
+		if resolvers[i], err = newValidatorResolver(st, index); err != nil {
+			return nil, err
+		}
+	}
+	return resolvers, nil
+}
+
+type validatorResolver struct {
+	index   uint64
+	v       *ethpb.Validator
+	balance uint64
+}
+
+func newValidatorResolver(st *stateTrie.BeaconState, index uint64) (*validatorResolver, error) {
+	v, err := st.ValidatorAtIndex(index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get validator %d", index)
+	}
+	balance, err := st.BalanceAtIndex(index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get balance of validator %d", index)
+	}
+	return &validatorResolver{index: index, v: v, balance: balance}, nil
+}
+
+// Index of the validator.
+func (v *validatorResolver) Index() Uint64 {
+	return Uint64(v.index)
+}
+
+// PublicKey of the validator.
+func (v *validatorResolver) PublicKey() string {
+	return fmt.Sprintf("%#x", v.v.PublicKey)
+}
+
+// Balance of the validator.
+func (v *validatorResolver) Balance() Uint64 {
+	return Uint64(v.balance)
+}
+
+// EffectiveBalance of the validator.
+func (v *validatorResolver) EffectiveBalance() Uint64 {
+	return Uint64(v.v.EffectiveBalance)
+}
+
+// Slashed is true if the validator was slashed.
+func (v *validatorResolver) Slashed() bool {
+	return v.v.Slashed
+}
+
+// ActivationEligibilityEpoch of the validator.
+func (v *validatorResolver) ActivationEligibilityEpoch() Uint64 {
+	return Uint64(v.v.ActivationEligibilityEpoch)
+}
+
+// ActivationEpoch of the validator.
+func (v *validatorResolver) ActivationEpoch() Uint64 {
+	return Uint64(v.v.ActivationEpoch)
+}
+
+// ExitEpoch of the validator.
+func (v *validatorResolver) ExitEpoch() Uint64 {
+	return Uint64(v.v.ExitEpoch)
+}
+
+// WithdrawableEpoch of the validator.
+func (v *validatorResolver) WithdrawableEpoch() Uint64 {
+	return Uint64(v.v.WithdrawableEpoch)
+}
@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// schema of the GraphQL API. Blocks resolve to their attestations, attestations to the validators
+// of their committee, and validators to their balances in the post-state of the block.
+const schema = `
+	# A uint64 value, encoded as a decimal string. Integers and strings are accepted as input.
+	scalar Uint64
+
+	schema {
+		query: Query
+	}
+
+	type Query {
+		# The head block of the canonical chain.
+		head: Block
+		# The block with the given root, or the canonical block at the given slot.
+		block(root: String, slot: Uint64): Block
+		# The canonical blocks from the start slot to the end slot, inclusive.
+		blocks(startSlot: Uint64!, endSlot: Uint64!): [Block!]!
+		# The validator with the given index in the head state.
+		validator(index: Uint64!): Validator
+	}
+
+	type Block {
+		root: String!
+		slot: Uint64!
+		proposerIndex: Uint64!
+		parentRoot: String!
+		stateRoot: String!
+		parent: Block
+		# The proposer of the block in its post-state.
+		proposer: Validator!
+		attestations: [Attestation!]!
+	}
+
+	type Attestation {
+		slot: Uint64!
+		committeeIndex: Uint64!
+		beaconBlockRoot: String!
+		sourceEpoch: Uint64!
+		targetEpoch: Uint64!
+		aggregationBits: String!
+		# The validators of the attestation's committee in the post-state of the including block.
+		committee: [Validator!]!
+		# The committee validators which took part in the attestation.
+		attesters: [Validator!]!
+	}
+
+	type Validator {
+		index: Uint64!
+		publicKey: String!
+		balance: Uint64!
+		effectiveBalance: Uint64!
+		slashed: Boolean!
+		activationEligibilityEpoch: Uint64!
+		activationEpoch: Uint64!
+		exitEpoch: Uint64!
+		withdrawableEpoch: Uint64!
+	}
+`
+
+// Uint64 is the GraphQL scalar of uint64 values, which do not fit the 32-bit GraphQL Int.
+type Uint64 uint64
+
+// ImplementsGraphQLType maps the type to the Uint64 scalar of the schema.
+func (Uint64) ImplementsGraphQLType(name string) bool {
+	return name == "Uint64"
+}
+
+// UnmarshalGraphQL decodes a Uint64 query argument.
+func (u *Uint64) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case string:
+		x, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Uint64 %q", v)
+		}
+		*u = Uint64(x)
+	case int32:
+		if v < 0 {
+			return fmt.Errorf("invalid Uint64 %d", v)
+		}
+		*u = Uint64(v)
+	case float64:
+		if v < 0 || v != float64(uint64(v)) {
+			return fmt.Errorf("invalid Uint64 %v", v)
+		}
+		*u = Uint64(v)
+	default:
+		return fmt.Errorf("invalid Uint64 of type %T", input)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the value as a decimal string, as JSON numbers lose precision above 2^53
+// in most clients.
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(u), 10))
+}
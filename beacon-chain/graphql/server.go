@@ -0,0 +1,134 @@
+// Package graphql serves chain and validator data over a GraphQL endpoint, so nested data such
+// as the balances of the committees of a block's attestations can be fetched in a single query.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "graphql")
+
+// Path the GraphQL endpoint is served at.
+const Path = "/graphql"
+
+const (
+	// maxQueryDepth is the deepest nesting of fields a query may select.
+	maxQueryDepth = 10
+	// maxBlocksPerQuery is the widest slot range the blocks query may request.
+	maxBlocksPerQuery = 100
+	// stateCost is the complexity charged for loading the post-state of a block, which is far
+	// more expensive than resolving a single object.
+	stateCost = 100
+)
+
+// Config options for the GraphQL server.
+type Config struct {
+	HeadFetcher      blockchain.HeadFetcher
+	CanonicalFetcher blockchain.CanonicalFetcher
+	BeaconDB         db.ReadOnlyDatabase
+	StateGen         *stategen.State
+	// MaxComplexity is the total cost of the objects and states a query may resolve, each
+	// block, attestation and validator costing 1 and each state stateCost.
+	MaxComplexity int64
+}
+
+// Server serves GraphQL queries of chain and validator data.
+type Server struct {
+	headFetcher      blockchain.HeadFetcher
+	canonicalFetcher blockchain.CanonicalFetcher
+	beaconDB         db.ReadOnlyDatabase
+	stateGen         *stategen.State
+	maxComplexity    int64
+	schema           *graphql.Schema
+}
+
+// NewServer returns a GraphQL server.
+func NewServer(cfg *Config) (*Server, error) {
+	s := &Server{
+		headFetcher:      cfg.HeadFetcher,
+		canonicalFetcher: cfg.CanonicalFetcher,
+		beaconDB:         cfg.BeaconDB,
+		stateGen:         cfg.StateGen,
+		maxComplexity:    cfg.MaxComplexity,
+	}
+	parsed, err := graphql.ParseSchema(schema, &queryResolver{s: s}, graphql.MaxDepth(maxQueryDepth))
+	if err != nil {
+		return nil, err
+	}
+	s.schema = parsed
+	return s, nil
+}
+
+// RegisterHandlers adds the GraphQL endpoint to the mux.
+func (s *Server) RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc(Path, s.Handler)
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler executes the GraphQL query of a POST request with a JSON body, or of the `query`
+// parameter of a GET request.
+func (s *Server) Handler(w http.ResponseWriter, r *http.Request) {
+	req := &request{}
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := withComplexityBudget(r.Context(), s.maxComplexity)
+	resp := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.WithError(err).Error("Could not write GraphQL response")
+	}
+}
+
+type complexityKey struct{}
+
+// complexityBudget is the complexity a query has left to spend. It is shared by the resolvers of
+// a query, which may run concurrently.
+type complexityBudget struct {
+	limit     int64
+	remaining int64
+}
+
+func withComplexityBudget(ctx context.Context, limit int64) context.Context {
+	if limit <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, complexityKey{}, &complexityBudget{limit: limit, remaining: limit})
+}
+
+// charge spends the cost from the query's complexity budget, failing once the budget is spent.
+func charge(ctx context.Context, cost int64) error {
+	budget, ok := ctx.Value(complexityKey{}).(*complexityBudget)
+	if !ok {
+		return nil
+	}
+	if atomic.AddInt64(&budget.remaining, -cost) < 0 {
+		return fmt.Errorf("query exceeds the complexity limit of %d", budget.limit)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+const nestedQuery = `{
+	block(slot: "1") {
+		slot
+		attestations {
+			committee { index balance }
+			attesters { index }
+		}
+	}
+}`
+
+type nestedResponse struct {
+	Data struct {
+		Block struct {
+			Slot         string `json:"slot"`
+			Attestations []struct {
+				Committee []struct {
+					Index   string `json:"index"`
+					Balance string `json:"balance"`
+				} `json:"committee"`
+				Attesters []struct {
+					Index string `json:"index"`
+				} `json:"attesters"`
+			} `json:"attestations"`
+		} `json:"block"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func setupServer(t *testing.T, maxComplexity int64) (*Server, []uint64) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	st, _ := testutil.DeterministicGenesisState(t, 64)
+
+	committee, err := helpers.BeaconCommitteeFromState(st, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggregationBits := bitfield.NewBitlist(uint64(len(committee)))
+	aggregationBits.SetBitAt(0, true)
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 1
+	blk.Block.Body.Attestations = []*ethpb.Attestation{{
+		Data: &ethpb.AttestationData{
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		AggregationBits: aggregationBits,
+		Signature:       make([]byte, 96),
+	}}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, root); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &mock.ChainService{State: st, Block: blk, CanonicalRoots: map[[32]byte]bool{root: true}}
+	s, err := NewServer(&Config{
+		HeadFetcher:      chain,
+		CanonicalFetcher: chain,
+		BeaconDB:         db,
+		MaxComplexity:    maxComplexity,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, committee
+}
+
+func query(t *testing.T, s *Server, q string) *nestedResponse {
+	body, err := json.Marshal(&request{Query: q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	resp := &nestedResponse{}
+	if err := json.NewDecoder(rec.Body).Decode(resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestHandler_NestedQuery(t *testing.T) {
+	s, committee := setupServer(t, 10000)
+	resp := query(t, s, nestedQuery)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", resp.Errors)
+	}
+	if resp.Data.Block.Slot != "1" {
+		t.Errorf("Wanted block at slot 1, received slot %s", resp.Data.Block.Slot)
+	}
+	if len(resp.Data.Block.Attestations) != 1 {
+		t.Fatalf("Wanted 1 attestation, received %d", len(resp.Data.Block.Attestations))
+	}
+	att := resp.Data.Block.Attestations[0]
+	var indices []uint64
+	for _, v := range att.Committee {
+		var index Uint64
+		if err := index.UnmarshalGraphQL(v.Index); err != nil {
+			t.Fatal(err)
+		}
+		indices = append(indices, uint64(index))
+		if v.Balance != "32000000000" {
+			t.Errorf("Wanted balance 32000000000 of validator %s, received %s", v.Index, v.Balance)
+		}
+	}
+	if !reflect.DeepEqual(committee, indices) {
+		t.Errorf("Wanted committee %v, received %v", committee, indices)
+	}
+	if len(att.Attesters) != 1 || att.Attesters[0].Index != att.Committee[0].Index {
+		t.Errorf("Wanted the first committee member as the only attester, received %v", att.Attesters)
+	}
+}
+
+func TestHandler_ComplexityLimit(t *testing.T) {
+	// Loading the post-state of the block alone exceeds the limit.
+	s, _ := setupServer(t, stateCost)
+	resp := query(t, s, nestedQuery)
+	if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "complexity limit") {
+		t.Errorf("Wanted complexity limit error, received %v", resp.Errors)
+	}
+
+	// Fields which need no state are within the limit.
+	resp = query(t, s, `{ block(slot: "1") { slot } }`)
+	if len(resp.Errors) > 0 {
+		t.Errorf("Unexpected errors: %v", resp.Errors)
+	}
+}
+
+func TestHandler_DepthLimit(t *testing.T) {
+	s, _ := setupServer(t, 10000)
+	q := "{ head { " + strings.Repeat("parent { ", maxQueryDepth) + "slot" + strings.Repeat(" }", maxQueryDepth) + " } }"
+	resp := query(t, s, q)
+	if len(resp.Errors) == 0 {
+		t.Error("Wanted error for query exceeding the depth limit")
+	}
+}
+
+func TestUint64_UnmarshalGraphQL(t *testing.T) {
+	tests := []struct {
+		input   interface{}
+		want    Uint64
+		wantErr bool
+	}{
+		{input: "18446744073709551615", want: 18446744073709551615},
+		{input: int32(5), want: 5},
+		{input: float64(7), want: 7},
+		{input: int32(-1), wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: float64(1.5), wantErr: true},
+		{input: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		var u Uint64
+		err := u.UnmarshalGraphQL(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("UnmarshalGraphQL(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && u != tt.want {
+			t.Errorf("UnmarshalGraphQL(%v) = %d, want %d", tt.input, u, tt.want)
+		}
+	}
+}
@@ -38,6 +38,7 @@ type Service struct {
 	depositCache       *depositcache.DepositCache
 	genesisPath        string
 	chainStartDeposits []*ethpb.Deposit
+	chainStartEth1Data *ethpb.Eth1Data
 }
 
 // Config options for the interop service.
@@ -53,7 +54,6 @@ type Config struct {
 // into the beacon chain database and running services at start up. This service should not be used in production
 // as it does not have any value other than ease of use for testing purposes.
 func NewColdStartService(ctx context.Context, cfg *Config) *Service {
-	log.Warn("Saving generated genesis state in database for interop testing")
 	ctx, cancel := context.WithCancel(ctx)
 
 	s := &Service{
@@ -66,6 +66,23 @@ func NewColdStartService(ctx context.Context, cfg *Config) *Service {
 		genesisPath:   cfg.GenesisPath,
 	}
 
+	// A node restarted with the same flags resumes its chain instead of overwriting it with a
+	// new genesis, which would differ when the genesis time defaults to now.
+	existing, err := s.beaconDB.GenesisState(ctx)
+	if err != nil {
+		log.Fatalf("Could not get genesis state from database: %v", err)
+	}
+	if existing != nil {
+		log.WithField("genesisTime", existing.GenesisTime()).Info("Using interop genesis state from database")
+		if s.numValidators > 0 && uint64(existing.NumValidators()) != s.numValidators {
+			log.WithField("validators", existing.NumValidators()).Warn("Genesis state in database has a different number of validators than requested")
+		}
+		s.genesisTime = existing.GenesisTime()
+		s.setChainStart(existing)
+		return s
+	}
+	log.Warn("Saving generated genesis state in database for interop testing")
+
 	if s.genesisPath != "" {
 		data, err := ioutil.ReadFile(s.genesisPath)
 		if err != nil {
@@ -130,9 +147,13 @@ func (s *Service) ChainStartDeposits() []*ethpb.Deposit {
 	return s.chainStartDeposits
 }
 
-// ChainStartEth1Data mocks out the powchain functionality for interop.
+// ChainStartEth1Data mocks out the powchain functionality for interop with the eth1 data of
+// the genesis state.
 func (s *Service) ChainStartEth1Data() *ethpb.Eth1Data {
-	return &ethpb.Eth1Data{}
+	if s.chainStartEth1Data == nil {
+		return &ethpb.Eth1Data{}
+	}
+	return s.chainStartEth1Data
 }
 
 // PreGenesisState returns an empty beacon state.
@@ -155,8 +176,12 @@ func (s *Service) DepositsNumberAndRootAtHeight(ctx context.Context, blockHeight
 	return 0, [32]byte{}
 }
 
+// DepositSnapshotAtHeight mocks out the deposit cache functionality for interop.
+func (s *Service) DepositSnapshotAtHeight(ctx context.Context, blockHeight *big.Int) *depositcache.DepositSnapshot {
+	return &depositcache.DepositSnapshot{}
+}
+
 func (s *Service) saveGenesisState(ctx context.Context, genesisState *stateTrie.BeaconState) error {
-	s.chainStartDeposits = make([]*ethpb.Deposit, genesisState.NumValidators())
 	stateRoot, err := genesisState.HashTreeRoot(ctx)
 	if err != nil {
 		return err
@@ -192,18 +217,21 @@ func (s *Service) saveGenesisState(ctx context.Context, genesisState *stateTrie.
 	if err := s.beaconDB.SaveFinalizedCheckpoint(ctx, genesisCheckpoint); err != nil {
 		return errors.Wrap(err, "could save finalized checkpoint")
 	}
+	s.setChainStart(genesisState)
+	return nil
+}
 
-	pubKeys := make([][48]byte, 0, genesisState.NumValidators())
-	indices := make([]uint64, 0, genesisState.NumValidators())
+// setChainStart mocks the chain start deposits and eth1 data of the powchain service from the
+// genesis state.
+func (s *Service) setChainStart(genesisState *stateTrie.BeaconState) {
+	s.chainStartDeposits = make([]*ethpb.Deposit, genesisState.NumValidators())
 	for i := uint64(0); i < uint64(genesisState.NumValidators()); i++ {
 		pk := genesisState.PubkeyAtIndex(i)
-		pubKeys = append(pubKeys, pk)
-		indices = append(indices, i)
 		s.chainStartDeposits[i] = &ethpb.Deposit{
 			Data: &ethpb.Deposit_Data{
 				PublicKey: pk[:],
 			},
 		}
 	}
-	return nil
+	s.chainStartEth1Data = genesisState.Eth1Data()
 }
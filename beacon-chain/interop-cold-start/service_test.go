@@ -0,0 +1,61 @@
+package interopcoldstart
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+)
+
+func TestNewColdStartService_ReusesGenesisFromDatabase(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := dbutil.SetupDB(t)
+
+	svc := NewColdStartService(ctx, &Config{
+		GenesisTime:   100,
+		NumValidators: 8,
+		BeaconDB:      beaconDB,
+	})
+	if len(svc.ChainStartDeposits()) != 8 {
+		t.Errorf("Wanted 8 chain start deposits, received %d", len(svc.ChainStartDeposits()))
+	}
+	genesisState, err := beaconDB.GenesisState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc.ChainStartEth1Data().DepositCount != 8 {
+		t.Errorf("Wanted chain start eth1 data of the genesis state, received %v", svc.ChainStartEth1Data())
+	}
+	head, err := beaconDB.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A restart with another genesis time keeps the chain in the database.
+	svc = NewColdStartService(ctx, &Config{
+		GenesisTime:   200,
+		NumValidators: 8,
+		BeaconDB:      beaconDB,
+	})
+	received, err := beaconDB.GenesisState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.GenesisTime() != genesisState.GenesisTime() {
+		t.Errorf("Wanted genesis time %d, received %d", genesisState.GenesisTime(), received.GenesisTime())
+	}
+	if svc.genesisTime != 100 {
+		t.Errorf("Wanted genesis time 100, received %d", svc.genesisTime)
+	}
+	if len(svc.ChainStartDeposits()) != 8 {
+		t.Errorf("Wanted 8 chain start deposits, received %d", len(svc.ChainStartDeposits()))
+	}
+	receivedHead, err := beaconDB.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receivedHead.Block.Slot != head.Block.Slot || !bytes.Equal(receivedHead.Block.StateRoot, head.Block.StateRoot) {
+		t.Error("Wanted head block to be kept")
+	}
+}
@@ -0,0 +1,151 @@
+package lightclient
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// stateSchema describes the merkle layout of the beacon state of a fork: the depth of the
+// tree over the state fields and the leaf index of every field in it. Forks adding fields
+// to the state, or light client specific fields such as sync committees, register their
+// own schema in schemaForFork.
+type stateSchema struct {
+	name   string
+	depth  uint64
+	fields map[string]uint64
+}
+
+var phase0Schema = &stateSchema{
+	name:  "phase0",
+	depth: 5,
+	fields: map[string]uint64{
+		"genesis_time":                  0,
+		"genesis_validators_root":       1,
+		"slot":                          2,
+		"fork":                          3,
+		"latest_block_header":           4,
+		"block_roots":                   5,
+		"state_roots":                   6,
+		"historical_roots":              7,
+		"eth1_data":                     8,
+		"eth1_data_votes":               9,
+		"eth1_deposit_index":            10,
+		"validators":                    11,
+		"balances":                      12,
+		"randao_mixes":                  13,
+		"slashings":                     14,
+		"previous_epoch_attestations":   15,
+		"current_epoch_attestations":    16,
+		"justification_bits":            17,
+		"previous_justified_checkpoint": 18,
+		"current_justified_checkpoint":  19,
+		"finalized_checkpoint":          20,
+	},
+}
+
+// schemaForFork returns the state schema of the fork with the given version.
+func schemaForFork(version []byte) (*stateSchema, error) {
+	if bytes.Equal(version, params.BeaconConfig().GenesisForkVersion) {
+		return phase0Schema, nil
+	}
+	return nil, fmt.Errorf("light client proofs are not supported for fork version %#x", version)
+}
+
+// FieldProof is a merkle proof of a node of the state tree, such as the root of a state field,
+// against the state root.
+type FieldProof struct {
+	// Field is the field path of the node, or empty for a node requested by generalized index.
+	Field string
+	// GeneralizedIndex is the position of the leaf in the state tree, as defined by the
+	// SSZ merkle proof formats.
+	GeneralizedIndex uint64
+	Leaf             [32]byte
+	// Branch holds the sibling nodes from the leaf up to the state root.
+	Branch [][32]byte
+}
+
+// stateProver computes merkle proofs of the nodes of the tree of a beacon state.
+type stateProver struct {
+	tree *node
+}
+
+func newStateProver(st *stateTrie.BeaconState) (*stateProver, error) {
+	schema, err := schemaForFork(st.Fork().CurrentVersion)
+	if err != nil {
+		return nil, err
+	}
+	roots, err := stateutil.ComputeFieldRoots(st.InnerStateUnsafe())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute state field roots")
+	}
+	fieldRoots := make([][32]byte, len(roots))
+	for i, r := range roots {
+		copy(fieldRoots[i][:], r)
+	}
+	return &stateProver{tree: stateTree(st.CloneInnerState(), schema, fieldRoots)}, nil
+}
+
+// fieldProof returns the proof of the node of a field path, such as `slot`,
+// `finalized_checkpoint.root` or `validators[3].effective_balance`. The elements of lists of
+// uint64 such as `balances[3]` are proven by the leaf they are packed in with three others.
+func (p *stateProver) fieldProof(path string) (*FieldProof, error) {
+	bits, err := p.tree.pathBits(path)
+	if err != nil {
+		return nil, err
+	}
+	gIndex, err := generalizedIndex(bits)
+	if err != nil {
+		return nil, err
+	}
+	return p.proof(path, gIndex, bits)
+}
+
+// generalizedIndexProof returns the proof of the node at the generalized index.
+func (p *stateProver) generalizedIndexProof(gIndex uint64) (*FieldProof, error) {
+	bits, err := generalizedIndexBits(gIndex)
+	if err != nil {
+		return nil, err
+	}
+	return p.proof("", gIndex, bits)
+}
+
+func (p *stateProver) proof(field string, gIndex uint64, bits []bool) (*FieldProof, error) {
+	leaf, branch, err := p.tree.proof(bits)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not prove generalized index %d", gIndex)
+	}
+	return &FieldProof{
+		Field:            field,
+		GeneralizedIndex: gIndex,
+		Leaf:             leaf,
+		Branch:           branch,
+	}, nil
+}
+
+// finalizedRootProof returns the proof of the root of the finalized checkpoint, the leaf
+// light clients use to verify a finalized header against an attested state root.
+func (p *stateProver) finalizedRootProof(finalizedRoot [32]byte, finalizedEpoch uint64) (*FieldProof, error) {
+	cpProof, err := p.fieldProof("finalized_checkpoint")
+	if err != nil {
+		return nil, err
+	}
+	// A checkpoint is a container of the epoch and the root, so the root is the right leaf
+	// one level below the checkpoint root.
+	epochRoot := stateutil.Uint64Root(finalizedEpoch)
+	return &FieldProof{
+		Field:            "finalized_checkpoint.root",
+		GeneralizedIndex: cpProof.GeneralizedIndex*2 + 1,
+		Leaf:             finalizedRoot,
+		Branch:           append([][32]byte{epochRoot}, cpProof.Branch...),
+	}, nil
+}
+
+// stateRoot returns the root of the state the proofs are computed against.
+func (p *stateProver) stateRoot() [32]byte {
+	return p.tree.root()
+}
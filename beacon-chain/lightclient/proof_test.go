@@ -0,0 +1,195 @@
+package lightclient
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/shared/trieutil"
+)
+
+func TestStateProver_FieldProof(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	if err := st.SetSlot(5); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prover, err := newStateProver(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prover.stateRoot() != stateRoot {
+		t.Fatalf("Wanted state root %#x, received %#x", stateRoot, prover.stateRoot())
+	}
+
+	for _, field := range []string{"genesis_time", "slot", "validators", "finalized_checkpoint"} {
+		proof, err := prover.fieldProof(field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verify(stateRoot, proof) {
+			t.Errorf("Proof of %s does not verify", field)
+		}
+	}
+	if _, err := prover.fieldProof("sync_committee"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
+
+func TestStateProver_FinalizedRootProof(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	finalizedRoot := [32]byte{'f'}
+	if err := st.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 3, Root: finalizedRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prover, err := newStateProver(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := prover.finalizedRootProof(finalizedRoot, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The generalized index light clients use for the finalized root.
+	if proof.GeneralizedIndex != 105 {
+		t.Errorf("Wanted generalized index 105, received %d", proof.GeneralizedIndex)
+	}
+	if !verify(stateRoot, proof) {
+		t.Error("Finalized root proof does not verify")
+	}
+	if proof, err := prover.finalizedRootProof([32]byte{'x'}, 3); err != nil || verify(stateRoot, proof) {
+		t.Error("Expected proof of another root not to verify")
+	}
+}
+
+func TestStateProver_FieldPaths(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	if err := st.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 3, Root: bytesutil.PadTo([]byte{'f'}, 32)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.UpdateBalancesAtIndex(5, 1234); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetHistoricalRoots([][]byte{bytesutil.PadTo([]byte{'h'}, 32)}); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prover, err := newStateProver(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, err := st.ValidatorAtIndex(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The balance of validator 5 is packed with the balances of validators 4 to 7.
+	balancesLeaf := [32]byte{}
+	for i, b := range st.Balances()[4:8] {
+		binary.LittleEndian.PutUint64(balancesLeaf[i*8:], b)
+	}
+
+	tests := []struct {
+		path   string
+		gIndex uint64
+		leaf   [32]byte
+	}{
+		{path: "finalized_checkpoint.root", gIndex: 105, leaf: bytesutil.ToBytes32([]byte{'f'})},
+		{path: "slot", gIndex: 34, leaf: stateutil.Uint64Root(st.Slot())},
+		{path: "fork.current_version", gIndex: 141, leaf: bytesutil.ToBytes32(st.Fork().CurrentVersion)},
+		{path: "historical_roots[0]", gIndex: 39 << 25, leaf: bytesutil.ToBytes32([]byte{'h'})},
+		{path: "balances[5]", gIndex: 88<<38 + 1, leaf: balancesLeaf},
+		{path: "validators[3].effective_balance", gIndex: (86<<40+3)<<3 + 2, leaf: stateutil.Uint64Root(val.EffectiveBalance)},
+		{path: "block_roots[2]", gIndex: 37<<13 + 2, leaf: bytesutil.ToBytes32(st.BlockRoots()[2])},
+	}
+	for _, tt := range tests {
+		proof, err := prover.fieldProof(tt.path)
+		if err != nil {
+			t.Fatalf("Could not prove %s: %v", tt.path, err)
+		}
+		if proof.GeneralizedIndex != tt.gIndex {
+			t.Errorf("Wanted generalized index %d for %s, received %d", tt.gIndex, tt.path, proof.GeneralizedIndex)
+		}
+		if proof.Leaf != tt.leaf {
+			t.Errorf("Wanted leaf %#x for %s, received %#x", tt.leaf, tt.path, proof.Leaf)
+		}
+		if !verify(stateRoot, proof) {
+			t.Errorf("Proof of %s does not verify", tt.path)
+		}
+	}
+
+	for _, path := range []string{"balances[8]", "slot.epoch", "fork[0]", "validators[1].unknown", "[1]"} {
+		if _, err := prover.fieldProof(path); err == nil {
+			t.Errorf("Expected error for field path %s", path)
+		}
+	}
+}
+
+func TestStateProver_GeneralizedIndexProof(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	prover, err := newStateProver(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The state root, the root of the validators, the length of the registry and an inner node
+	// of the state tree.
+	for _, tt := range []struct {
+		gIndex uint64
+		leaf   [32]byte
+	}{
+		{gIndex: 1, leaf: stateRoot},
+		{gIndex: 43, leaf: prover.tree.leaves[11]},
+		{gIndex: 87, leaf: stateutil.Uint64Root(8)},
+		{gIndex: 6, leaf: [32]byte{}},
+	} {
+		proof, err := prover.generalizedIndexProof(tt.gIndex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.leaf != ([32]byte{}) && proof.Leaf != tt.leaf {
+			t.Errorf("Wanted leaf %#x at generalized index %d, received %#x", tt.leaf, tt.gIndex, proof.Leaf)
+		}
+		if !verify(stateRoot, proof) {
+			t.Errorf("Proof of generalized index %d does not verify", tt.gIndex)
+		}
+	}
+
+	// Below the slot, a basic value, and below the length of the registry.
+	for _, gIndex := range []uint64{0, 34 * 2, 87 * 2} {
+		if _, err := prover.generalizedIndexProof(gIndex); err == nil {
+			t.Errorf("Expected error for generalized index %d", gIndex)
+		}
+	}
+}
+
+func TestSchemaForFork(t *testing.T) {
+	if _, err := schemaForFork([]byte{1, 2, 3, 4}); err == nil {
+		t.Error("Expected error for unknown fork version")
+	}
+}
+
+func verify(root [32]byte, proof *FieldProof) bool {
+	branch := make([][]byte, len(proof.Branch))
+	for i := range proof.Branch {
+		branch[i] = proof.Branch[i][:]
+	}
+	return trieutil.VerifyMerkleBranch(root[:], proof.Leaf[:], int(proof.GeneralizedIndex), branch)
+}
@@ -0,0 +1,368 @@
+// Package lightclient serves finalized headers and merkle proofs of beacon state fields over
+// HTTP, so resource-constrained consumers can follow finality without syncing the chain.
+package lightclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "lightclient")
+
+const (
+	// FinalityUpdatePath serves the head header, the finalized header and the proof linking them.
+	FinalityUpdatePath = "/eth/v1alpha1/lightclient/finality_update"
+	// StateProofPath serves proofs of selected nodes of the state of a block.
+	StateProofPath = "/eth/v1alpha1/lightclient/state_proof"
+)
+
+// Config options for the light client server.
+type Config struct {
+	HeadFetcher         blockchain.HeadFetcher
+	FinalizationFetcher blockchain.FinalizationFetcher
+	BeaconDB            db.ReadOnlyDatabase
+	StateGen            *stategen.State
+}
+
+// Server serves light client data.
+type Server struct {
+	headFetcher         blockchain.HeadFetcher
+	finalizationFetcher blockchain.FinalizationFetcher
+	beaconDB            db.ReadOnlyDatabase
+	stateGen            *stategen.State
+}
+
+// NewServer returns a light client server.
+func NewServer(cfg *Config) *Server {
+	return &Server{
+		headFetcher:         cfg.HeadFetcher,
+		finalizationFetcher: cfg.FinalizationFetcher,
+		beaconDB:            cfg.BeaconDB,
+		stateGen:            cfg.StateGen,
+	}
+}
+
+// RegisterHandlers adds the light client endpoints to the mux.
+func (s *Server) RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc(FinalityUpdatePath, s.FinalityUpdateHandler)
+	mux.HandleFunc(StateProofPath, s.StateProofHandler)
+}
+
+type headerJSON struct {
+	Slot          uint64 `json:"slot,string"`
+	ProposerIndex uint64 `json:"proposer_index,string"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+	BodyRoot      string `json:"body_root"`
+	Root          string `json:"root"`
+}
+
+type proofJSON struct {
+	Field            string   `json:"field,omitempty"`
+	GeneralizedIndex uint64   `json:"generalized_index,string"`
+	Leaf             string   `json:"leaf"`
+	Branch           []string `json:"branch"`
+}
+
+type finalityUpdateJSON struct {
+	ForkVersion     string      `json:"fork_version"`
+	AttestedHeader  *headerJSON `json:"attested_header"`
+	FinalizedHeader *headerJSON `json:"finalized_header"`
+	FinalizedEpoch  uint64      `json:"finalized_epoch,string"`
+	FinalityProof   *proofJSON  `json:"finality_proof"`
+}
+
+type stateProofJSON struct {
+	ForkVersion string       `json:"fork_version"`
+	Header      *headerJSON  `json:"header"`
+	Proofs      []*proofJSON `json:"proofs"`
+}
+
+// FinalityUpdateHandler serves the header of the head block, the header of the block it
+// finalized and the proof of the finalized block root against the head state root. A light
+// client which trusts the head header can verify the finalized header from the proof alone.
+func (s *Server) FinalityUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	update, err := s.finalityUpdate(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, update)
+}
+
+func (s *Server) finalityUpdate(ctx context.Context) (*finalityUpdateJSON, error) {
+	headBlock, err := s.headFetcher.HeadBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head block")
+	}
+	headState, err := s.headFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head state")
+	}
+	if headBlock == nil || headBlock.Block == nil || headState == nil {
+		return nil, errors.New("head is not available")
+	}
+	prover, err := newStateProver(headState)
+	if err != nil {
+		return nil, err
+	}
+	if root := prover.stateRoot(); !bytes.Equal(root[:], headBlock.Block.StateRoot) {
+		return nil, errors.New("head state does not match the head block")
+	}
+
+	finalized := headState.FinalizedCheckpoint()
+	finalizedRoot := bytesutil.ToBytes32(finalized.Root)
+	if finalizedRoot == params.BeaconConfig().ZeroHash {
+		return nil, errors.New("chain has not finalized a block yet")
+	}
+	finalizedBlock, err := s.beaconDB.Block(ctx, finalizedRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get finalized block")
+	}
+	if finalizedBlock == nil || finalizedBlock.Block == nil {
+		return nil, fmt.Errorf("finalized block %#x not found", finalizedRoot)
+	}
+	proof, err := prover.finalizedRootProof(finalizedRoot, finalized.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	attestedHeader, err := blockHeader(headBlock.Block)
+	if err != nil {
+		return nil, err
+	}
+	finalizedHeader, err := blockHeader(finalizedBlock.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &finalityUpdateJSON{
+		ForkVersion:     fmt.Sprintf("%#x", headState.Fork().CurrentVersion),
+		AttestedHeader:  attestedHeader,
+		FinalizedHeader: finalizedHeader,
+		FinalizedEpoch:  finalized.Epoch,
+		FinalityProof:   toProofJSON(proof),
+	}, nil
+}
+
+// StateProofHandler serves proofs of nodes of the state of a block. The nodes are given by the
+// comma separated field paths of the `fields` query parameter, such as `slot`,
+// `finalized_checkpoint.root` or `validators[3].effective_balance`, and by the comma separated
+// generalized indices of the `gindices` query parameter. The state is given by its root in the
+// `state_root` query parameter, which covers the blocks of the last SLOTS_PER_HISTORICAL_ROOT
+// slots of the canonical chain, or by the root of a finalized block in the `block_root` query
+// parameter. It defaults to the state of the latest finalized block.
+func (s *Server) StateProofHandler(w http.ResponseWriter, r *http.Request) {
+	fields := splitList(r.URL.Query().Get("fields"))
+	var gIndices []uint64
+	for _, g := range splitList(r.URL.Query().Get("gindices")) {
+		gIndex, err := strconv.ParseUint(g, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid generalized index %s", g), http.StatusBadRequest)
+			return
+		}
+		gIndices = append(gIndices, gIndex)
+	}
+	if len(fields) == 0 && len(gIndices) == 0 {
+		http.Error(w, "no state fields requested", http.StatusBadRequest)
+		return
+	}
+	blockRoot := bytesutil.ToBytes32(s.finalizationFetcher.FinalizedCheckpt().Root)
+	q, stateRootQ := r.URL.Query().Get("block_root"), r.URL.Query().Get("state_root")
+	if q != "" && stateRootQ != "" {
+		http.Error(w, "only one of block_root and state_root may be given", http.StatusBadRequest)
+		return
+	}
+	if q != "" {
+		b, err := hex.DecodeString(strings.TrimPrefix(q, "0x"))
+		if err != nil || len(b) != 32 {
+			http.Error(w, "invalid block root", http.StatusBadRequest)
+			return
+		}
+		blockRoot = bytesutil.ToBytes32(b)
+		if !s.beaconDB.IsFinalizedBlock(r.Context(), blockRoot) {
+			http.Error(w, "block is not finalized", http.StatusNotFound)
+			return
+		}
+	}
+	if stateRootQ != "" {
+		b, err := hex.DecodeString(strings.TrimPrefix(stateRootQ, "0x"))
+		if err != nil || len(b) != 32 {
+			http.Error(w, "invalid state root", http.StatusBadRequest)
+			return
+		}
+		root, err := s.blockRootByStateRoot(r.Context(), bytesutil.ToBytes32(b))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		blockRoot = root
+	}
+
+	resp, err := s.stateProof(r.Context(), blockRoot, fields, gIndices)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// blockRootByStateRoot returns the root of the block with the given state root, looked up in
+// the head state's history of the last SLOTS_PER_HISTORICAL_ROOT slots.
+func (s *Server) blockRootByStateRoot(ctx context.Context, stateRoot [32]byte) ([32]byte, error) {
+	headState, err := s.headFetcher.HeadState(ctx)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not get head state")
+	}
+	headBlock, err := s.headFetcher.HeadBlock(ctx)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not get head block")
+	}
+	if headState == nil || headBlock == nil || headBlock.Block == nil {
+		return [32]byte{}, errors.New("head is not available")
+	}
+	if bytes.Equal(headBlock.Block.StateRoot, stateRoot[:]) {
+		return stateutil.BlockRoot(headBlock.Block)
+	}
+	blockRoots := headState.BlockRoots()
+	for i, r := range headState.StateRoots() {
+		if !bytes.Equal(r, stateRoot[:]) || i >= len(blockRoots) {
+			continue
+		}
+		// The state root of a slot without a block is not the state root of the latest block.
+		blockRoot := bytesutil.ToBytes32(blockRoots[i])
+		blk, err := s.beaconDB.Block(ctx, blockRoot)
+		if err != nil {
+			return [32]byte{}, errors.Wrap(err, "could not get block")
+		}
+		if blk != nil && blk.Block != nil && bytes.Equal(blk.Block.StateRoot, stateRoot[:]) {
+			return blockRoot, nil
+		}
+	}
+	return [32]byte{}, fmt.Errorf("no recent block with state root %#x", stateRoot)
+}
+
+func (s *Server) stateProof(ctx context.Context, blockRoot [32]byte, fields []string, gIndices []uint64) (*stateProofJSON, error) {
+	blk, err := s.beaconDB.Block(ctx, blockRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get block")
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, fmt.Errorf("block %#x not found", blockRoot)
+	}
+	st, err := s.stateByRoot(ctx, blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	prover, err := newStateProver(st)
+	if err != nil {
+		return nil, err
+	}
+	header, err := blockHeader(blk.Block)
+	if err != nil {
+		return nil, err
+	}
+	resp := &stateProofJSON{
+		ForkVersion: fmt.Sprintf("%#x", st.Fork().CurrentVersion),
+		Header:      header,
+		Proofs:      make([]*proofJSON, 0, len(fields)+len(gIndices)),
+	}
+	for _, f := range fields {
+		proof, err := prover.fieldProof(f)
+		if err != nil {
+			return nil, err
+		}
+		resp.Proofs = append(resp.Proofs, toProofJSON(proof))
+	}
+	for _, g := range gIndices {
+		proof, err := prover.generalizedIndexProof(g)
+		if err != nil {
+			return nil, err
+		}
+		resp.Proofs = append(resp.Proofs, toProofJSON(proof))
+	}
+	return resp, nil
+}
+
+func (s *Server) stateByRoot(ctx context.Context, blockRoot [32]byte) (*stateTrie.BeaconState, error) {
+	var st *stateTrie.BeaconState
+	var err error
+	if featureconfig.Get().NewStateMgmt {
+		st, err = s.stateGen.StateByRoot(ctx, blockRoot)
+	} else {
+		st, err = s.beaconDB.State(ctx, blockRoot)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get state")
+	}
+	if st == nil {
+		return nil, fmt.Errorf("state of block %#x not found", blockRoot)
+	}
+	return st, nil
+}
+
+func blockHeader(b *ethpb.BeaconBlock) (*headerJSON, error) {
+	bodyRoot, err := stateutil.BlockBodyRoot(b.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block body root")
+	}
+	root, err := stateutil.BlockRoot(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block root")
+	}
+	return &headerJSON{
+		Slot:          b.Slot,
+		ProposerIndex: b.ProposerIndex,
+		ParentRoot:    fmt.Sprintf("%#x", b.ParentRoot),
+		StateRoot:     fmt.Sprintf("%#x", b.StateRoot),
+		BodyRoot:      fmt.Sprintf("%#x", bodyRoot),
+		Root:          fmt.Sprintf("%#x", root),
+	}, nil
+}
+
+func toProofJSON(p *FieldProof) *proofJSON {
+	branch := make([]string, len(p.Branch))
+	for i, b := range p.Branch {
+		branch[i] = fmt.Sprintf("%#x", b)
+	}
+	return &proofJSON{
+		Field:            p.Field,
+		GeneralizedIndex: p.GeneralizedIndex,
+		Leaf:             fmt.Sprintf("%#x", p.Leaf),
+		Branch:           branch,
+	}
+}
+
+// splitList returns the non empty elements of a comma separated list.
+func splitList(list string) []string {
+	var elems []string
+	for _, e := range strings.Split(list, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Could not write light client response")
+	}
+}
@@ -0,0 +1,139 @@
+package lightclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestFinalityUpdateHandler(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+
+	finalizedBlock := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 32, Body: &ethpb.BeaconBlockBody{}}}
+	if err := db.SaveBlock(ctx, finalizedBlock); err != nil {
+		t.Fatal(err)
+	}
+	finalizedRoot, err := stateutil.BlockRoot(finalizedBlock.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headState, _ := testutil.DeterministicGenesisState(t, 8)
+	if err := headState.SetFinalizedCheckpoint(&ethpb.Checkpoint{Epoch: 1, Root: finalizedRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := headState.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headBlock := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 100, StateRoot: stateRoot[:], Body: &ethpb.BeaconBlockBody{}}}
+
+	s := NewServer(&Config{
+		HeadFetcher: &mock.ChainService{State: headState, Block: headBlock},
+		BeaconDB:    db,
+	})
+	rec := httptest.NewRecorder()
+	s.FinalityUpdateHandler(rec, httptest.NewRequest(http.MethodGet, FinalityUpdatePath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	update := &finalityUpdateJSON{}
+	if err := json.NewDecoder(rec.Body).Decode(update); err != nil {
+		t.Fatal(err)
+	}
+	if update.FinalizedHeader.Root != fmt.Sprintf("%#x", finalizedRoot) {
+		t.Errorf("Wanted finalized header root %#x, received %s", finalizedRoot, update.FinalizedHeader.Root)
+	}
+	if update.AttestedHeader.StateRoot != fmt.Sprintf("%#x", stateRoot) {
+		t.Errorf("Wanted attested state root %#x, received %s", stateRoot, update.AttestedHeader.StateRoot)
+	}
+	if update.FinalityProof.Leaf != update.FinalizedHeader.Root || len(update.FinalityProof.Branch) != 6 {
+		t.Errorf("Unexpected finality proof %v", update.FinalityProof)
+	}
+
+	// A head block which does not match the head state is rejected.
+	headBlock.Block.StateRoot = make([]byte, 32)
+	rec = httptest.NewRecorder()
+	s.FinalityUpdateHandler(rec, httptest.NewRequest(http.MethodGet, FinalityUpdatePath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Wanted status %d, received %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+func TestStateProofHandler_RequiresFields(t *testing.T) {
+	s := NewServer(&Config{FinalizationFetcher: &mock.ChainService{}})
+	rec := httptest.NewRecorder()
+	s.StateProofHandler(rec, httptest.NewRequest(http.MethodGet, StateProofPath, nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d, received %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestStateProofHandler_StateRoot(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	stateRoot, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, StateRoot: stateRoot[:], Body: &ethpb.BeaconBlockBody{}}}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	blockRoot, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, blockRoot); err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(&Config{
+		HeadFetcher:         &mock.ChainService{State: st, Block: blk},
+		FinalizationFetcher: &mock.ChainService{FinalizedCheckPoint: &ethpb.Checkpoint{Root: make([]byte, 32)}},
+		BeaconDB:            db,
+	})
+
+	url := fmt.Sprintf("%s?state_root=%#x&fields=balances[5],validators[3].pubkey&gindices=87", StateProofPath, stateRoot)
+	rec := httptest.NewRecorder()
+	s.StateProofHandler(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	resp := &stateProofJSON{}
+	if err := json.NewDecoder(rec.Body).Decode(resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.StateRoot != fmt.Sprintf("%#x", stateRoot) {
+		t.Errorf("Wanted state root %#x, received %s", stateRoot, resp.Header.StateRoot)
+	}
+	if len(resp.Proofs) != 3 {
+		t.Fatalf("Wanted 3 proofs, received %d", len(resp.Proofs))
+	}
+	if resp.Proofs[0].Field != "balances[5]" || resp.Proofs[2].GeneralizedIndex != 87 {
+		t.Errorf("Unexpected proofs %v", resp.Proofs)
+	}
+
+	for _, query := range []string{
+		fmt.Sprintf("state_root=%#x&gindices=87", [32]byte{'x'}),
+		"state_root=0x01&fields=slot",
+		"gindices=slot",
+	} {
+		rec := httptest.NewRecorder()
+		s.StateProofHandler(rec, httptest.NewRequest(http.MethodGet, StateProofPath+"?"+query, nil))
+		if rec.Code == http.StatusOK {
+			t.Errorf("Expected query %s to fail", query)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package lightclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/trieutil"
+)
+
+// maxProofDepth bounds the depth of the requested generalized indices, so that they fit in an
+// uint64.
+const maxProofDepth = 63
+
+// node is a subtree of the merkle tree of a state: its leaves merkleized into a tree of the
+// given depth, with the length mixed in for lists. The subtrees of composite leaves, such as
+// a validator of the registry, are only built when a proof descends into them.
+type node struct {
+	// fields names the leaves of a container.
+	fields []string
+	leaves [][32]byte
+	depth  uint64
+	isList bool
+	length uint64
+	// perChunk is the number of elements packed in a leaf, for lists and vectors of uint64.
+	perChunk uint64
+	child    func(i uint64) (*node, error)
+	layers   [][][32]byte
+}
+
+func containerNode(fields []string, leaves [][32]byte) *node {
+	return &node{fields: fields, leaves: leaves, depth: depthFor(uint64(len(leaves)))}
+}
+
+func vectorNode(leaves [][32]byte, perChunk uint64) *node {
+	return &node{leaves: leaves, depth: depthFor(uint64(len(leaves))), perChunk: perChunk}
+}
+
+func listNode(leaves [][32]byte, limit uint64, length uint64, perChunk uint64) *node {
+	return &node{leaves: leaves, depth: depthFor(limit), isList: true, length: length, perChunk: perChunk}
+}
+
+// depthFor returns the depth of the smallest tree with at least the given number of leaves.
+func depthFor(leaves uint64) uint64 {
+	depth := uint64(0)
+	for uint64(1)<<depth < leaves {
+		depth++
+	}
+	return depth
+}
+
+// nodeAt returns the node at the given height above the leaves, where the leaves past the
+// end of the list are zero.
+func (n *node) nodeAt(height uint64, index uint64) [32]byte {
+	if n.layers == nil {
+		n.layers = make([][][32]byte, n.depth+1)
+		n.layers[0] = n.leaves
+		for h := uint64(0); h < n.depth; h++ {
+			layer := n.layers[h]
+			if len(layer)%2 == 1 {
+				layer = append(layer[:len(layer):len(layer)], trieutil.ZeroHashes[h])
+			}
+			n.layers[h+1] = make([][32]byte, len(layer)/2)
+			hashutil.HashPairs(n.layers[h+1], layer)
+		}
+	}
+	if index >= uint64(len(n.layers[height])) {
+		return trieutil.ZeroHashes[height]
+	}
+	return n.layers[height][index]
+}
+
+func (n *node) root() [32]byte {
+	root := n.nodeAt(n.depth, 0)
+	if !n.isList {
+		return root
+	}
+	length := stateutil.Uint64Root(n.length)
+	return hashutil.Hash(append(root[:], length[:]...))
+}
+
+// proof returns the node at the end of the path from the root of the subtree, where false
+// goes to the left child and true to the right one, and the sibling nodes from it up to the
+// root.
+func (n *node) proof(path []bool) ([32]byte, [][32]byte, error) {
+	if len(path) == 0 {
+		return n.root(), nil, nil
+	}
+	var top [][32]byte
+	if n.isList {
+		// The root of a list is the hash of the root of its elements and of its length.
+		length := stateutil.Uint64Root(n.length)
+		if path[0] {
+			if len(path) > 1 {
+				return [32]byte{}, nil, errors.New("generalized index is below the length of a list")
+			}
+			return length, [][32]byte{n.nodeAt(n.depth, 0)}, nil
+		}
+		top = [][32]byte{length}
+		path = path[1:]
+	}
+
+	steps := uint64(len(path))
+	if steps > n.depth {
+		steps = n.depth
+	}
+	height := n.depth - steps
+	index := uint64(0)
+	for _, right := range path[:steps] {
+		index *= 2
+		if right {
+			index++
+		}
+	}
+	branch := make([][32]byte, 0, steps+uint64(len(top)))
+	for h, i := height, index; h < n.depth; h, i = h+1, i/2 {
+		branch = append(branch, n.nodeAt(h, i^1))
+	}
+	branch = append(branch, top...)
+
+	if rest := path[steps:]; len(rest) > 0 {
+		if n.child == nil || index >= uint64(len(n.leaves)) {
+			return [32]byte{}, nil, errors.New("generalized index is below a leaf of the state tree")
+		}
+		child, err := n.child(index)
+		if err != nil {
+			return [32]byte{}, nil, err
+		}
+		leaf, childBranch, err := child.proof(rest)
+		if err != nil {
+			return [32]byte{}, nil, err
+		}
+		return leaf, append(childBranch, branch...), nil
+	}
+	return n.nodeAt(height, index), branch, nil
+}
+
+// pathBits returns the path from the root of the subtree to the node of a field path such as
+// `finalized_checkpoint.root`, `balances[3]` or `validators[3].effective_balance`. The
+// elements of lists and vectors of uint64 resolve to the leaf they are packed in.
+func (n *node) pathBits(path string) ([]bool, error) {
+	var elems []string
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		var indices []string
+		if i := strings.Index(part, "["); i >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("invalid field path %q", path)
+			}
+			name = part[:i]
+			indices = strings.Split(part[i+1:len(part)-1], "][")
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid field path %q", path)
+		}
+		elems = append(elems, name)
+		for _, index := range indices {
+			elems = append(elems, "["+index+"]")
+		}
+	}
+
+	var bits []bool
+	cur := n
+	for i, elem := range elems {
+		var leaf uint64
+		if strings.HasPrefix(elem, "[") {
+			if cur.fields != nil {
+				return nil, fmt.Errorf("cannot index the container %s of field path %q", elems[i-1], path)
+			}
+			index, err := strconv.ParseUint(strings.Trim(elem, "[]"), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %s in field path %q", elem, path)
+			}
+			perChunk := cur.perChunk
+			if perChunk == 0 {
+				perChunk = 1
+			}
+			size := uint64(len(cur.leaves)) * perChunk
+			if cur.isList {
+				size = cur.length
+				bits = append(bits, false)
+			}
+			if index >= size {
+				return nil, fmt.Errorf("index %d of field path %q is out of range", index, path)
+			}
+			leaf = index / perChunk
+		} else {
+			found := false
+			for j, f := range cur.fields {
+				if f == elem {
+					leaf, found = uint64(j), true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown field %s in field path %q", elem, path)
+			}
+		}
+		for d := cur.depth; d > 0; d-- {
+			bits = append(bits, (leaf>>(d-1))&1 == 1)
+		}
+		if i == len(elems)-1 {
+			break
+		}
+		if cur.child == nil {
+			return nil, fmt.Errorf("field path %q descends below a leaf of the state tree", path)
+		}
+		child, err := cur.child(leaf)
+		if err != nil {
+			return nil, err
+		}
+		cur = child
+	}
+	return bits, nil
+}
+
+// generalizedIndexBits returns the path from the root of the state to the node at the
+// generalized index.
+func generalizedIndexBits(gIndex uint64) ([]bool, error) {
+	if gIndex == 0 {
+		return nil, errors.New("generalized index 0 is not a node of the tree")
+	}
+	depth := uint64(0)
+	for gIndex>>(depth+1) > 0 {
+		depth++
+	}
+	if depth > maxProofDepth {
+		return nil, fmt.Errorf("generalized index %d is too deep", gIndex)
+	}
+	bits := make([]bool, depth)
+	for d := uint64(0); d < depth; d++ {
+		bits[d] = (gIndex>>(depth-1-d))&1 == 1
+	}
+	return bits, nil
+}
+
+func generalizedIndex(bits []bool) (uint64, error) {
+	if len(bits) > maxProofDepth {
+		return 0, errors.New("field path is too deep for a generalized index")
+	}
+	gIndex := uint64(1)
+	for _, right := range bits {
+		gIndex *= 2
+		if right {
+			gIndex++
+		}
+	}
+	return gIndex, nil
+}
+
+// stateTree returns the merkle tree of the state, from the roots of its fields.
+func stateTree(st *pb.BeaconState, schema *stateSchema, fieldRoots [][32]byte) *node {
+	fields := make([]string, len(schema.fields))
+	for name, i := range schema.fields {
+		fields[i] = name
+	}
+	tree := containerNode(fields, fieldRoots)
+	tree.depth = schema.depth
+	tree.child = func(i uint64) (*node, error) {
+		return stateFieldTree(st, fields[i])
+	}
+	return tree
+}
+
+func stateFieldTree(st *pb.BeaconState, field string) (*node, error) {
+	cfg := params.BeaconConfig()
+	hasher := hashutil.CustomSHA256Hasher()
+	switch field {
+	case "fork":
+		return forkTree(st.Fork), nil
+	case "latest_block_header":
+		return blockHeaderTree(st.LatestBlockHeader), nil
+	case "block_roots":
+		return vectorNode(rootLeaves(st.BlockRoots), 1), nil
+	case "state_roots":
+		return vectorNode(rootLeaves(st.StateRoots), 1), nil
+	case "historical_roots":
+		return listNode(rootLeaves(st.HistoricalRoots), cfg.HistoricalRootsLimit, uint64(len(st.HistoricalRoots)), 1), nil
+	case "eth1_data":
+		return eth1DataTree(st.Eth1Data), nil
+	case "eth1_data_votes":
+		leaves := make([][32]byte, len(st.Eth1DataVotes))
+		for i, vote := range st.Eth1DataVotes {
+			root, err := stateutil.Eth1Root(hasher, vote)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not compute eth1 data root")
+			}
+			leaves[i] = root
+		}
+		tree := listNode(leaves, cfg.EpochsPerEth1VotingPeriod*cfg.SlotsPerEpoch, uint64(len(leaves)), 1)
+		tree.child = func(i uint64) (*node, error) {
+			return eth1DataTree(st.Eth1DataVotes[i]), nil
+		}
+		return tree, nil
+	case "validators":
+		leaves := make([][32]byte, len(st.Validators))
+		for i, val := range st.Validators {
+			root, err := stateutil.ValidatorRoot(hasher, val)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not compute validator root")
+			}
+			leaves[i] = root
+		}
+		tree := listNode(leaves, cfg.ValidatorRegistryLimit, uint64(len(leaves)), 1)
+		tree.child = func(i uint64) (*node, error) {
+			return validatorTree(st.Validators[i]), nil
+		}
+		return tree, nil
+	case "balances":
+		limit := (cfg.ValidatorRegistryLimit*8 + 31) / 32
+		return listNode(packUint64s(st.Balances), limit, uint64(len(st.Balances)), 4), nil
+	case "randao_mixes":
+		return vectorNode(rootLeaves(st.RandaoMixes), 1), nil
+	case "slashings":
+		return vectorNode(packUint64s(st.Slashings), 4), nil
+	case "previous_epoch_attestations", "current_epoch_attestations":
+		atts := st.PreviousEpochAttestations
+		if field == "current_epoch_attestations" {
+			atts = st.CurrentEpochAttestations
+		}
+		leaves := make([][32]byte, len(atts))
+		for i, att := range atts {
+			root, err := stateutil.PendingAttestationRoot(hasher, att)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not compute pending attestation root")
+			}
+			leaves[i] = root
+		}
+		return listNode(leaves, cfg.MaxAttestations*cfg.SlotsPerEpoch, uint64(len(leaves)), 1), nil
+	case "previous_justified_checkpoint":
+		return checkpointTree(st.PreviousJustifiedCheckpoint), nil
+	case "current_justified_checkpoint":
+		return checkpointTree(st.CurrentJustifiedCheckpoint), nil
+	case "finalized_checkpoint":
+		return checkpointTree(st.FinalizedCheckpoint), nil
+	}
+	return nil, fmt.Errorf("state field %s has no subtree", field)
+}
+
+func forkTree(fork *pb.Fork) *node {
+	if fork == nil {
+		fork = &pb.Fork{}
+	}
+	return containerNode(
+		[]string{"previous_version", "current_version", "epoch"},
+		[][32]byte{
+			bytesutil.ToBytes32(fork.PreviousVersion),
+			bytesutil.ToBytes32(fork.CurrentVersion),
+			stateutil.Uint64Root(fork.Epoch),
+		},
+	)
+}
+
+func blockHeaderTree(header *ethpb.BeaconBlockHeader) *node {
+	if header == nil {
+		header = &ethpb.BeaconBlockHeader{}
+	}
+	return containerNode(
+		[]string{"slot", "proposer_index", "parent_root", "state_root", "body_root"},
+		[][32]byte{
+			stateutil.Uint64Root(header.Slot),
+			stateutil.Uint64Root(header.ProposerIndex),
+			bytesutil.ToBytes32(header.ParentRoot),
+			bytesutil.ToBytes32(header.StateRoot),
+			bytesutil.ToBytes32(header.BodyRoot),
+		},
+	)
+}
+
+func eth1DataTree(eth1Data *ethpb.Eth1Data) *node {
+	if eth1Data == nil {
+		eth1Data = &ethpb.Eth1Data{}
+	}
+	return containerNode(
+		[]string{"deposit_root", "deposit_count", "block_hash"},
+		[][32]byte{
+			bytesutil.ToBytes32(eth1Data.DepositRoot),
+			stateutil.Uint64Root(eth1Data.DepositCount),
+			bytesutil.ToBytes32(eth1Data.BlockHash),
+		},
+	)
+}
+
+func checkpointTree(cp *ethpb.Checkpoint) *node {
+	if cp == nil {
+		cp = &ethpb.Checkpoint{}
+	}
+	return containerNode(
+		[]string{"epoch", "root"},
+		[][32]byte{stateutil.Uint64Root(cp.Epoch), bytesutil.ToBytes32(cp.Root)},
+	)
+}
+
+func validatorTree(val *ethpb.Validator) *node {
+	// The 48 byte public key spans two leaves.
+	pubKey := make([]byte, 64)
+	copy(pubKey, val.PublicKey)
+	slashed := [32]byte{}
+	if val.Slashed {
+		slashed[0] = 1
+	}
+	return containerNode(
+		[]string{
+			"pubkey", "withdrawal_credentials", "effective_balance", "slashed",
+			"activation_eligibility_epoch", "activation_epoch", "exit_epoch", "withdrawable_epoch",
+		},
+		[][32]byte{
+			hashutil.Hash(pubKey),
+			bytesutil.ToBytes32(val.WithdrawalCredentials),
+			stateutil.Uint64Root(val.EffectiveBalance),
+			slashed,
+			stateutil.Uint64Root(val.ActivationEligibilityEpoch),
+			stateutil.Uint64Root(val.ActivationEpoch),
+			stateutil.Uint64Root(val.ExitEpoch),
+			stateutil.Uint64Root(val.WithdrawableEpoch),
+		},
+	)
+}
+
+func rootLeaves(roots [][]byte) [][32]byte {
+	leaves := make([][32]byte, len(roots))
+	for i, r := range roots {
+		leaves[i] = bytesutil.ToBytes32(r)
+	}
+	return leaves
+}
+
+// packUint64s packs the values four to a leaf, little endian.
+func packUint64s(vals []uint64) [][32]byte {
+	leaves := make([][32]byte, (len(vals)+3)/4)
+	for i, v := range vals {
+		root := stateutil.Uint64Root(v)
+		copy(leaves[i/4][(i%4)*8:], root[:8])
+	}
+	return leaves
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
@@ -9,18 +10,16 @@ import (
 
 	gethlog "github.com/ethereum/go-ethereum/log"
 	golog "github.com/ipfs/go-log/v2"
-	joonix "github.com/joonix/log"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	"github.com/prysmaticlabs/prysm/beacon-chain/node"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/logutil"
+	"github.com/prysmaticlabs/prysm/shared/reload"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
-	"github.com/urfave/cli/v2/altsrc"
-	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 	_ "go.uber.org/automaxprocs"
 )
 
@@ -31,10 +30,19 @@ var appFlags = []cli.Flag{
 	flags.RPCPort,
 	flags.CertFlag,
 	flags.KeyFlag,
+	flags.ClientCACertFlag,
+	flags.RPCAuthFlag,
+	flags.RPCAuthTokenFileFlag,
+	flags.RPCQuotaConfigFlag,
+	flags.DisableRPCQuotasFlag,
+	flags.RPCLogRequestsFlag,
 	flags.DisableGRPCGateway,
 	flags.GRPCGatewayPort,
+	flags.GPRCGatewayCorsDomain,
 	flags.MinSyncPeers,
 	flags.RPCMaxPageSize,
+	flags.RPCMaxRecvMsgSizeFlag,
+	flags.RPCMaxSendMsgSizeFlag,
 	flags.ContractDeploymentBlock,
 	flags.SetGCPercent,
 	flags.UnsafeSync,
@@ -43,6 +51,7 @@ var appFlags = []cli.Flag{
 	flags.DisableDiscv5,
 	flags.BlockBatchLimit,
 	flags.BlockBatchLimitBurstFactor,
+	flags.BlockBatchLimitGlobalFactor,
 	flags.InteropMockEth1DataVotesFlag,
 	flags.InteropGenesisStateFlag,
 	flags.InteropNumValidatorsFlag,
@@ -53,9 +62,27 @@ var appFlags = []cli.Flag{
 	flags.ArchiveAttestationsFlag,
 	flags.SlotsPerArchivedPoint,
 	flags.EnableDebugRPCEndpoints,
+	flags.EraDirFlag,
+	flags.ShutdownTimeoutFlag,
+	flags.DiskWarnThresholdFlag,
+	flags.DiskEmergencyThresholdFlag,
+	flags.PruneStatesFlag,
+	flags.RetentionEpochsFlag,
+	flags.WeakSubjectivityCheckpointFlag,
+	flags.CheckpointStateFlag,
+	flags.CheckpointBlockFlag,
+	flags.GenesisStateFlag,
+	flags.ExportPostgresURLFlag,
+	flags.ExportKafkaURLFlag,
+	flags.EnableGraphQLFlag,
+	flags.GraphQLMaxComplexityFlag,
+	flags.ExplorerAPIPortFlag,
+	flags.HTTPAPIPortFlag,
+	flags.CacheMemoryBudgetFlag,
 	cmd.BootstrapNode,
 	cmd.NoDiscovery,
 	cmd.StaticPeers,
+	cmd.TrustedPeers,
 	cmd.RelayNode,
 	cmd.P2PUDPPort,
 	cmd.P2PTCPPort,
@@ -63,6 +90,8 @@ var appFlags = []cli.Flag{
 	cmd.P2PHost,
 	cmd.P2PHostDNS,
 	cmd.P2PMaxPeers,
+	cmd.P2PMaxInboundPeers,
+	cmd.P2PMaxOutboundPeers,
 	cmd.P2PPrivKey,
 	cmd.P2PMetadata,
 	cmd.P2PWhitelist,
@@ -71,12 +100,19 @@ var appFlags = []cli.Flag{
 	cmd.P2PPubsub,
 	cmd.DataDirFlag,
 	cmd.VerbosityFlag,
+	cmd.VModuleFlag,
 	cmd.EnableTracingFlag,
 	cmd.TracingProcessNameFlag,
 	cmd.TracingEndpointFlag,
 	cmd.TraceSampleFractionFlag,
 	flags.MonitoringPortFlag,
 	cmd.DisableMonitoringFlag,
+	cmd.ClientStatsAPIURLFlag,
+	cmd.ClientStatsIntervalFlag,
+	cmd.EnableRoughtimeFlag,
+	cmd.RoughtimeIntervalFlag,
+	cmd.RoughtimeMaxOffsetFlag,
+	cmd.SlotsPerSecondFlag,
 	cmd.ClearDB,
 	cmd.ForceClearDB,
 	cmd.LogFormat,
@@ -89,15 +125,25 @@ var appFlags = []cli.Flag{
 	debug.TraceFlag,
 	cmd.LogFileName,
 	cmd.EnableUPnPFlag,
+	cmd.DisableNATFlag,
 	cmd.ConfigFileFlag,
 	cmd.ChainConfigFileFlag,
+	cmd.NetworkFlag,
 	cmd.GrpcMaxCallRecvMsgSizeFlag,
+	cmd.GrpcMaxCallSendMsgSizeFlag,
 }
 
 func init() {
 	appFlags = cmd.WrapFlags(append(appFlags, featureconfig.BeaconChainFlags...))
 }
 
+// secretFlags may contain credentials, such as API keys in eth1 endpoint URLs. They can
+// be provided through the environment and are redacted from logs.
+var secretFlags = []*cli.StringFlag{
+	flags.HTTPWeb3ProviderFlag,
+	cmd.ClientStatsAPIURLFlag,
+}
+
 func main() {
 	log := logrus.WithField("prefix", "main")
 	app := cli.App{}
@@ -105,40 +151,24 @@ func main() {
 	app.Usage = "this is a beacon chain implementation for Ethereum 2.0"
 	app.Action = startNode
 	app.Version = version.GetVersion()
+	app.Commands = []*cli.Command{
+		dbCommands,
+		validateConfigCommand(appFlags),
+	}
 
 	app.Flags = appFlags
 
 	app.Before = func(ctx *cli.Context) error {
-		// Load any flags from file, if specified.
-		if ctx.IsSet(cmd.ConfigFileFlag.Name) {
-			if err := altsrc.InitInputSourceWithContext(appFlags, altsrc.NewYamlSourceFromFlagFunc(cmd.ConfigFileFlag.Name))(ctx); err != nil {
-				return err
-			}
+		logrus.AddHook(&logutil.RedactionHook{})
+		if err := prepareFlags(ctx); err != nil {
+			return err
 		}
 
-		format := ctx.String(cmd.LogFormat.Name)
-		switch format {
-		case "text":
-			formatter := new(prefixed.TextFormatter)
-			formatter.TimestampFormat = "2006-01-02 15:04:05"
-			formatter.FullTimestamp = true
-			// If persistent log files are written - we disable the log messages coloring because
-			// the colors are ANSI codes and seen as gibberish in the log files.
-			formatter.DisableColors = ctx.String(cmd.LogFileName.Name) != ""
-			logrus.SetFormatter(formatter)
-			break
-		case "fluentd":
-			f := joonix.NewFormatter()
-			if err := joonix.DisableTimestampFormat(f); err != nil {
-				panic(err)
-			}
-			logrus.SetFormatter(f)
-			break
-		case "json":
-			logrus.SetFormatter(&logrus.JSONFormatter{})
-			break
-		default:
-			return fmt.Errorf("unknown log format %s", format)
+		// If persistent log files are written - we disable the log messages coloring because
+		// the colors are ANSI codes and seen as gibberish in the log files.
+		disableColors := ctx.String(cmd.LogFileName.Name) != ""
+		if err := logutil.ConfigureFormatter(ctx.String(cmd.LogFormat.Name), disableColors); err != nil {
+			return err
 		}
 
 		logFileName := ctx.String(cmd.LogFileName.Name)
@@ -157,8 +187,10 @@ func main() {
 
 	defer func() {
 		if x := recover(); x != nil {
-			log.Errorf("Runtime panic: %v\n%v", x, string(runtimeDebug.Stack()))
-			panic(x)
+			// The panic is not raised again, as the runtime would print its value and stack to
+			// stderr without redacting secrets.
+			log.Errorf("Runtime panic: %v\n%v", logutil.Redact(fmt.Sprint(x)), logutil.Redact(string(runtimeDebug.Stack())))
+			os.Exit(1)
 		}
 	}()
 
@@ -168,14 +200,20 @@ func main() {
 	}
 }
 
+// prepareFlags fills the flags from the config file, if specified, and the environment, at
+// startup and when the configuration is reloaded.
+func prepareFlags(ctx *cli.Context) error {
+	if err := cmd.LoadFlagsFromConfigFile(ctx, appFlags); err != nil {
+		return err
+	}
+	return cmd.LoadSecrets(ctx, secretFlags)
+}
+
 func startNode(ctx *cli.Context) error {
-	verbosity := ctx.String(cmd.VerbosityFlag.Name)
-	level, err := logrus.ParseLevel(verbosity)
-	if err != nil {
+	if err := logutil.SetVerbosity(ctx.String(cmd.VerbosityFlag.Name), ctx.String(cmd.VModuleFlag.Name)); err != nil {
 		return err
 	}
-	logrus.SetLevel(level)
-	if level == logrus.TraceLevel {
+	if logrus.GetLevel() == logrus.TraceLevel {
 		// libp2p specific logging.
 		golog.SetAllLoggers(golog.LevelDebug)
 		// Geth specific logging.
@@ -188,6 +226,18 @@ func startNode(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	reloader, err := reload.New(ctx, &reload.Config{
+		Flags:    appFlags,
+		Args:     os.Args[1:],
+		Prepare:  prepareFlags,
+		Settings: beacon.ReloadSettings(),
+	})
+	if err != nil {
+		return err
+	}
+	reloadCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Run(reloadCtx)
 	beacon.Start()
 	return nil
 }
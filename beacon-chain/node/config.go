@@ -0,0 +1,88 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/apimiddleware"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/urfave/cli/v2"
+)
+
+// ValidateConfig cross-checks the flags of a beacon node and the files they refer to without
+// starting anything. All problems found are returned, so they can be fixed at once.
+func ValidateConfig(cliCtx *cli.Context) []error {
+	var errs []error
+	if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+		if _, err := params.ReadChainConfigFile(cliCtx.String(cmd.ChainConfigFileFlag.Name)); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid --%s", cmd.ChainConfigFileFlag.Name))
+		}
+	}
+
+	if cliCtx.IsSet(cmd.NetworkFlag.Name) {
+		if _, err := params.NetworkByName(cliCtx.String(cmd.NetworkFlag.Name)); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid --%s", cmd.NetworkFlag.Name))
+		}
+	}
+
+	if !cliCtx.Bool(testSkipPowFlag) {
+		depAddress := cliCtx.String(flags.DepositContractFlag.Name)
+		if depAddress == "" {
+			errs = append(errs, fmt.Errorf("--%s is required", flags.DepositContractFlag.Name))
+		} else if !common.IsHexAddress(depAddress) {
+			errs = append(errs, fmt.Errorf("invalid deposit contract address given: %s", depAddress))
+		}
+	}
+
+	genesisValidators := cliCtx.Uint64(flags.InteropNumValidatorsFlag.Name)
+	if cliCtx.IsSet(flags.InteropGenesisTimeFlag.Name) && genesisValidators == 0 {
+		errs = append(errs, fmt.Errorf("--%s must be used with --%s", flags.InteropGenesisTimeFlag.Name, flags.InteropNumValidatorsFlag.Name))
+	}
+	if genesisValidators > 0 && cliCtx.String(flags.InteropGenesisStateFlag.Name) != "" {
+		errs = append(errs, fmt.Errorf("--%s cannot be used with --%s", flags.InteropNumValidatorsFlag.Name, flags.InteropGenesisStateFlag.Name))
+	}
+
+	cert := cliCtx.String(flags.CertFlag.Name)
+	key := cliCtx.String(flags.KeyFlag.Name)
+	if cliCtx.String(flags.ClientCACertFlag.Name) != "" && (cert == "" || key == "") {
+		errs = append(errs, fmt.Errorf("--%s requires --%s and --%s", flags.ClientCACertFlag.Name, flags.CertFlag.Name, flags.KeyFlag.Name))
+	}
+
+	if path := cliCtx.String(flags.RPCQuotaConfigFlag.Name); path != "" && !cliCtx.Bool(flags.DisableRPCQuotasFlag.Name) {
+		if _, err := apimiddleware.LoadQuotaConfig(path); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid --%s", flags.RPCQuotaConfigFlag.Name))
+		}
+	}
+
+	if cp := cliCtx.String(flags.WeakSubjectivityCheckpointFlag.Name); cp != "" {
+		if _, err := blockchain.ParseWeakSubjectivityCheckpoint(cp); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid --%s", flags.WeakSubjectivityCheckpointFlag.Name))
+		}
+	}
+
+	if cliCtx.IsSet(flags.CheckpointStateFlag.Name) != cliCtx.IsSet(flags.CheckpointBlockFlag.Name) {
+		errs = append(errs, fmt.Errorf("--%s and --%s must be used together", flags.CheckpointStateFlag.Name, flags.CheckpointBlockFlag.Name))
+	}
+	if cliCtx.IsSet(flags.CheckpointStateFlag.Name) && (genesisValidators > 0 || cliCtx.String(flags.InteropGenesisStateFlag.Name) != "") {
+		errs = append(errs, fmt.Errorf("--%s cannot be used with an interop genesis state", flags.CheckpointStateFlag.Name))
+	}
+	if cliCtx.String(flags.GenesisStateFlag.Name) != "" && (genesisValidators > 0 || cliCtx.String(flags.InteropGenesisStateFlag.Name) != "") {
+		errs = append(errs, fmt.Errorf("--%s cannot be used with an interop genesis state", flags.GenesisStateFlag.Name))
+	}
+
+	warnThreshold := cliCtx.Uint64(flags.DiskWarnThresholdFlag.Name)
+	if emergencyThreshold := cliCtx.Uint64(flags.DiskEmergencyThresholdFlag.Name); emergencyThreshold > warnThreshold {
+		errs = append(errs, fmt.Errorf("--%s must not be greater than --%s", flags.DiskEmergencyThresholdFlag.Name, flags.DiskWarnThresholdFlag.Name))
+	}
+	if cliCtx.IsSet(flags.RetentionEpochsFlag.Name) && !cliCtx.Bool(flags.PruneStatesFlag.Name) {
+		errs = append(errs, fmt.Errorf("--%s requires --%s", flags.RetentionEpochsFlag.Name, flags.PruneStatesFlag.Name))
+	}
+	if cliCtx.Bool(cmd.EnableRoughtimeFlag.Name) && cliCtx.Duration(cmd.RoughtimeIntervalFlag.Name) <= 0 {
+		errs = append(errs, fmt.Errorf("--%s must be positive", cmd.RoughtimeIntervalFlag.Name))
+	}
+	return errs
+}
@@ -0,0 +1,108 @@
+package node
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/urfave/cli/v2"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr []string
+	}{
+		{
+			name: "valid",
+			args: []string{"--" + flags.DepositContractFlag.Name, "0x0000000000000000000000000000000000000000"},
+		},
+		{
+			name:    "missing deposit contract",
+			wantErr: []string{"--deposit-contract is required"},
+		},
+		{
+			name: "all problems reported",
+			args: []string{
+				"--" + flags.DepositContractFlag.Name, "0x1234",
+				"--" + flags.InteropGenesisTimeFlag.Name, "100",
+				"--" + flags.ClientCACertFlag.Name, "ca.crt",
+				"--" + flags.WeakSubjectivityCheckpointFlag.Name, "0x1234:10",
+				"--" + flags.DiskEmergencyThresholdFlag.Name, "100",
+				"--" + flags.RetentionEpochsFlag.Name, "1000",
+				"--" + cmd.EnableRoughtimeFlag.Name,
+				"--" + cmd.RoughtimeIntervalFlag.Name, "0s",
+			},
+			wantErr: []string{
+				"invalid deposit contract address",
+				"--interop-genesis-time must be used with --interop-num-validators",
+				"--tls-client-ca requires",
+				"invalid --weak-subjectivity-checkpoint",
+				"--disk-emergency-threshold-gb must not be greater",
+				"--retention-epochs requires --prune-states",
+				"--roughtime-interval must be positive",
+			},
+		},
+		{
+			name: "interop genesis sources",
+			args: []string{
+				"--" + testSkipPowFlag,
+				"--" + flags.InteropNumValidatorsFlag.Name, "64",
+				"--" + flags.InteropGenesisStateFlag.Name, "genesis.ssz",
+			},
+			wantErr: []string{"--interop-num-validators cannot be used with --interop-genesis-state"},
+		},
+		{
+			name: "genesis state with interop genesis",
+			args: []string{
+				"--" + testSkipPowFlag,
+				"--" + flags.InteropNumValidatorsFlag.Name, "64",
+				"--" + flags.GenesisStateFlag.Name, "genesis.ssz",
+			},
+			wantErr: []string{"--genesis-state cannot be used with an interop genesis state"},
+		},
+		{
+			name: "unknown network",
+			args: []string{
+				"--" + flags.DepositContractFlag.Name, "0x0000000000000000000000000000000000000000",
+				"--" + cmd.NetworkFlag.Name, "unknown",
+			},
+			wantErr: []string{"invalid --network: unknown network"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := flag.NewFlagSet("test", 0)
+			set.Bool(testSkipPowFlag, false, "")
+			set.String(flags.DepositContractFlag.Name, "", "")
+			set.String(cmd.NetworkFlag.Name, "", "")
+			set.Uint64(flags.InteropGenesisTimeFlag.Name, 0, "")
+			set.Uint64(flags.InteropNumValidatorsFlag.Name, 0, "")
+			set.String(flags.InteropGenesisStateFlag.Name, "", "")
+			set.String(flags.GenesisStateFlag.Name, "", "")
+			set.String(flags.ClientCACertFlag.Name, "", "")
+			set.String(flags.WeakSubjectivityCheckpointFlag.Name, "", "")
+			set.Uint64(flags.DiskWarnThresholdFlag.Name, flags.DiskWarnThresholdFlag.Value, "")
+			set.Uint64(flags.DiskEmergencyThresholdFlag.Name, flags.DiskEmergencyThresholdFlag.Value, "")
+			set.Bool(flags.PruneStatesFlag.Name, false, "")
+			set.Uint64(flags.RetentionEpochsFlag.Name, 0, "")
+			set.Bool(cmd.EnableRoughtimeFlag.Name, false, "")
+			set.Duration(cmd.RoughtimeIntervalFlag.Name, cmd.RoughtimeIntervalFlag.Value, "")
+			if err := set.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			errs := ValidateConfig(cli.NewContext(&cli.App{}, set, nil))
+			if len(errs) != len(tt.wantErr) {
+				t.Fatalf("Wanted %d errors, received %v", len(tt.wantErr), errs)
+			}
+			for i, want := range tt.wantErr {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("Wanted error containing %q, received %v", want, errs[i])
+				}
+			}
+		})
+	}
+}
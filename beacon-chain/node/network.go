@@ -0,0 +1,120 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/urfave/cli/v2"
+)
+
+// configureNetwork applies the network selected with --network to the chain config and to the
+// bootnode and deposit contract flags which are not set explicitly. The data directory becomes
+// a subdirectory of --datadir named after the network, so the databases of different networks
+// never share a directory. No network is returned if --network is not set.
+func configureNetwork(cliCtx *cli.Context) (*params.Network, error) {
+	if !cliCtx.IsSet(cmd.NetworkFlag.Name) {
+		return nil, nil
+	}
+	network, err := params.NetworkByName(cliCtx.String(cmd.NetworkFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	if !cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+		params.OverrideBeaconConfig(network.BeaconConfig())
+	}
+	if !cliCtx.IsSet(cmd.BootstrapNode.Name) {
+		if err := cliCtx.Set(cmd.BootstrapNode.Name, strings.Join(network.BootstrapNodes, ",")); err != nil {
+			return nil, err
+		}
+	}
+	if !cliCtx.IsSet(flags.DepositContractFlag.Name) {
+		if network.DepositContractAddress == "" {
+			return nil, fmt.Errorf("network %s requires --%s", network.Name, flags.DepositContractFlag.Name)
+		}
+		if err := cliCtx.Set(flags.DepositContractFlag.Name, network.DepositContractAddress); err != nil {
+			return nil, err
+		}
+	}
+	datadir := cliCtx.String(cmd.DataDirFlag.Name)
+	if datadir == "" {
+		datadir = cmd.DefaultDataDir()
+	}
+	if err := cliCtx.Set(cmd.DataDirFlag.Name, filepath.Join(datadir, network.Name)); err != nil {
+		return nil, err
+	}
+	log.WithField("network", network.Name).Info("Using network config")
+	return network, nil
+}
+
+// checkDBNetwork refuses a database created for a different network than the selected one,
+// comparing the network name stored in the database and, once the network has launched, the
+// genesis validators root of the stored genesis state. A database without a network name is
+// marked as belonging to the selected network.
+func checkDBNetwork(ctx context.Context, d db.Database, network *params.Network) error {
+	name, err := d.NetworkName(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve network of database")
+	}
+	if name != "" && name != network.Name {
+		return fmt.Errorf("database was created for network %s, not %s", name, network.Name)
+	}
+	if len(network.GenesisValidatorsRoot) > 0 {
+		genesisState, err := d.GenesisState(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve genesis state")
+		}
+		if genesisState != nil && !bytes.Equal(genesisState.GenesisValidatorRoot(), network.GenesisValidatorsRoot) {
+			return fmt.Errorf(
+				"database genesis validators root %#x does not match network %s",
+				genesisState.GenesisValidatorRoot(),
+				network.Name,
+			)
+		}
+	}
+	if name == "" {
+		return d.SaveNetworkName(ctx, network.Name)
+	}
+	return nil
+}
+
+// loadGenesisState loads the known genesis state given with --genesis-state, or else the one
+// embedded for the selected network. No state is returned if the genesis state is not known.
+func loadGenesisState(ctx context.Context, cliCtx *cli.Context, network *params.Network) (*stateTrie.BeaconState, error) {
+	var genesisState *stateTrie.BeaconState
+	if path := cliCtx.String(flags.GenesisStateFlag.Name); path != "" {
+		st, err := blockchain.LoadGenesisState(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		genesisState = st
+	} else if network != nil && len(network.GenesisState) > 0 {
+		st, err := blockchain.UnmarshalGenesisState(network.GenesisState)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not load genesis state of network %s", network.Name)
+		}
+		genesisState = st
+	}
+	if genesisState == nil {
+		return nil, nil
+	}
+	if network != nil && len(network.GenesisValidatorsRoot) > 0 &&
+		!bytes.Equal(genesisState.GenesisValidatorRoot(), network.GenesisValidatorsRoot) {
+		return nil, fmt.Errorf(
+			"genesis validators root %#x of the genesis state does not match network %s",
+			genesisState.GenesisValidatorRoot(),
+			network.Name,
+		)
+	}
+	log.WithField("genesisTime", genesisState.GenesisTime()).Info("Loaded known genesis state")
+	return genesisState, nil
+}
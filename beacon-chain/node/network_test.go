@@ -0,0 +1,106 @@
+package node
+
+import (
+	"context"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/urfave/cli/v2"
+)
+
+func networkContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet("test", 0)
+	set.String(cmd.NetworkFlag.Name, "", "")
+	set.String(cmd.DataDirFlag.Name, "/tmp/eth2", "")
+	set.String(cmd.BootstrapNode.Name, "", "")
+	set.String(cmd.ChainConfigFileFlag.Name, "", "")
+	set.String(flags.DepositContractFlag.Name, "", "")
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(&cli.App{}, set, nil)
+}
+
+func TestConfigureNetwork(t *testing.T) {
+	defer params.OverrideBeaconConfig(params.BeaconConfig())
+
+	cliCtx := networkContext(t, "--"+cmd.NetworkFlag.Name, params.TestnetName)
+	network, err := configureNetwork(cliCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.Name != params.TestnetName {
+		t.Errorf("Wanted network %s, received %s", params.TestnetName, network.Name)
+	}
+	if datadir := cliCtx.String(cmd.DataDirFlag.Name); datadir != filepath.Join("/tmp/eth2", params.TestnetName) {
+		t.Errorf("Wanted network datadir, received %s", datadir)
+	}
+	if bootnodes := cliCtx.String(cmd.BootstrapNode.Name); bootnodes != strings.Join(network.BootstrapNodes, ",") {
+		t.Errorf("Wanted network bootnodes, received %s", bootnodes)
+	}
+	if contract := cliCtx.String(flags.DepositContractFlag.Name); contract != network.DepositContractAddress {
+		t.Errorf("Wanted network deposit contract, received %s", contract)
+	}
+}
+
+func TestConfigureNetwork_ExplicitFlagsWin(t *testing.T) {
+	defer params.OverrideBeaconConfig(params.BeaconConfig())
+
+	contract := "0x0000000000000000000000000000000000000001"
+	cliCtx := networkContext(t,
+		"--"+cmd.NetworkFlag.Name, "minimal",
+		"--"+flags.DepositContractFlag.Name, contract,
+		"--"+cmd.BootstrapNode.Name, "enr:-abc",
+	)
+	if _, err := configureNetwork(cliCtx); err != nil {
+		t.Fatal(err)
+	}
+	if cliCtx.String(flags.DepositContractFlag.Name) != contract {
+		t.Errorf("Wanted deposit contract %s, received %s", contract, cliCtx.String(flags.DepositContractFlag.Name))
+	}
+	if cliCtx.String(cmd.BootstrapNode.Name) != "enr:-abc" {
+		t.Errorf("Wanted explicit bootnode, received %s", cliCtx.String(cmd.BootstrapNode.Name))
+	}
+	if params.BeaconConfig().SlotsPerEpoch != params.MinimalSpecConfig().SlotsPerEpoch {
+		t.Error("Wanted minimal config to be used")
+	}
+}
+
+func TestConfigureNetwork_RequiresDepositContract(t *testing.T) {
+	defer params.OverrideBeaconConfig(params.BeaconConfig())
+
+	cliCtx := networkContext(t, "--"+cmd.NetworkFlag.Name, "minimal")
+	if _, err := configureNetwork(cliCtx); err == nil || !strings.Contains(err.Error(), "requires --deposit-contract") {
+		t.Errorf("Wanted missing deposit contract error, received %v", err)
+	}
+}
+
+func TestCheckDBNetwork(t *testing.T) {
+	ctx := context.Background()
+	d := dbutil.SetupDB(t)
+	testnet, err := params.NetworkByName(params.TestnetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minimal, err := params.NetworkByName("minimal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkDBNetwork(ctx, d, testnet); err != nil {
+		t.Fatal(err)
+	}
+	// Reopening the db for the same network is allowed.
+	if err := checkDBNetwork(ctx, d, testnet); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkDBNetwork(ctx, d, minimal); err == nil || !strings.Contains(err.Error(), "created for network testnet") {
+		t.Errorf("Wanted network mismatch error, received %v", err)
+	}
+}
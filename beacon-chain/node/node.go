@@ -8,47 +8,73 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/signal"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/archiver"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache/depositcache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/era"
+	"github.com/prysmaticlabs/prysm/beacon-chain/diskmonitor"
+	"github.com/prysmaticlabs/prysm/beacon-chain/explorer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/exporter"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice"
 	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice/protoarray"
 	"github.com/prysmaticlabs/prysm/beacon-chain/gateway"
+	"github.com/prysmaticlabs/prysm/beacon-chain/graphql"
 	interopcoldstart "github.com/prysmaticlabs/prysm/beacon-chain/interop-cold-start"
+	"github.com/prysmaticlabs/prysm/beacon-chain/lightclient"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/slashings"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/pruner"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/apimiddleware"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
 	initialsync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/cachemanager"
+	"github.com/prysmaticlabs/prysm/shared/clientstats"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
+	"github.com/prysmaticlabs/prysm/shared/dirlock"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/grpcutils"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/prometheus"
+	"github.com/prysmaticlabs/prysm/shared/reload"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
+	"github.com/prysmaticlabs/prysm/shared/sdnotify"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/shared/tracing"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc/credentials"
 )
 
 var log = logrus.WithField("prefix", "node")
@@ -77,6 +103,11 @@ type BeaconNode struct {
 	opFeed            *event.Feed
 	forkChoiceStore   forkchoice.ForkChoicer
 	stateGen          *stategen.State
+	eraArchive        *era.Archive
+	dirLock           dirlock.Releaser
+	rpcAuthToken      string
+	network           *params.Network
+	genesisState      *stateTrie.BeaconState
 }
 
 // NewBeaconNode creates a new node instance, sets up configuration options, and registers
@@ -92,6 +123,15 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if errs := ValidateConfig(cliCtx); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	network, err := configureNetwork(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+
 	if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
 		chainConfigFileName := cliCtx.String(cmd.ChainConfigFileFlag.Name)
 		params.LoadChainConfigFile(chainConfigFileName)
@@ -105,6 +145,7 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 
 	featureconfig.ConfigureBeaconChain(cliCtx)
 	flags.ConfigureGlobalFlags(cliCtx)
+	cachemanager.Default().SetBudget(int64(cliCtx.Uint64(flags.CacheMemoryBudgetFlag.Name)) << 20)
 	registry := shared.NewServiceRegistry()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -121,6 +162,21 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		exitPool:          voluntaryexits.NewPool(),
 		slashingsPool:     slashings.NewPool(),
 		stateSummaryCache: cache.NewStateSummaryCache(),
+		network:           network,
+	}
+
+	dirLock, err := dirlock.Acquire(cliCtx.String(cmd.DataDirFlag.Name), dirlock.BeaconLockFileName)
+	if err != nil {
+		return nil, err
+	}
+	beacon.dirLock = dirLock
+
+	if err := diskmonitor.CheckAvailableSpace(
+		cliCtx.String(cmd.DataDirFlag.Name),
+		cliCtx.Uint64(flags.DiskWarnThresholdFlag.Name)<<30,
+		cliCtx.Uint64(flags.DiskEmergencyThresholdFlag.Name)<<30,
+	); err != nil {
+		return nil, err
 	}
 
 	if err := beacon.startDB(cliCtx); err != nil {
@@ -129,6 +185,18 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 
 	beacon.startStateGen()
 
+	genesisState, err := loadGenesisState(ctx, cliCtx, network)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load genesis state")
+	}
+	beacon.genesisState = genesisState
+
+	// The clock is adjusted before the services depending on slot timing start.
+	if err := beacon.registerRoughtimeService(); err != nil {
+		return nil, err
+	}
+	configureSlotClock(cliCtx)
+
 	if err := beacon.registerP2P(cliCtx); err != nil {
 		return nil, err
 	}
@@ -171,12 +239,36 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if err := beacon.registerPrunerService(); err != nil {
+		return nil, err
+	}
+
+	if err := beacon.registerExporterService(); err != nil {
+		return nil, err
+	}
+
+	if err := beacon.registerExplorerService(); err != nil {
+		return nil, err
+	}
+
 	if !cliCtx.Bool(cmd.DisableMonitoringFlag.Name) {
 		if err := beacon.registerPrometheusService(); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := beacon.registerClientStatsService(); err != nil {
+		return nil, err
+	}
+
+	if err := beacon.registerDiskMonitorService(); err != nil {
+		return nil, err
+	}
+
+	if err := beacon.registerWatchdogService(); err != nil {
+		return nil, err
+	}
+
 	return beacon, nil
 }
 
@@ -204,6 +296,9 @@ func (b *BeaconNode) Start() {
 	}).Info("Starting beacon node")
 
 	b.services.StartAll()
+	if _, err := sdnotify.Notify(sdnotify.Ready); err != nil {
+		log.WithError(err).Error("Could not notify systemd of startup")
+	}
 
 	stop := b.stop
 	b.lock.Unlock()
@@ -235,11 +330,44 @@ func (b *BeaconNode) Close() {
 	defer b.lock.Unlock()
 
 	log.Info("Stopping beacon node")
+	if _, err := sdnotify.Notify(sdnotify.Stopping); err != nil {
+		log.WithError(err).Debug("Could not notify systemd of shutdown")
+	}
+	// Services stop in reverse order of registration: the RPC servers and sync stop accepting
+	// blocks and say goodbye to peers, the chain service drains block processing and persists
+	// its in-memory data, and the p2p host disconnects last. The database is closed after them,
+	// even if they do not stop in time.
+	stopped := make(chan struct{})
+	go func() {
+		b.services.StopAll()
+		close(stopped)
+	}()
+	var timeout <-chan time.Time
+	if d := b.cliCtx.Duration(flags.ShutdownTimeoutFlag.Name); d > 0 {
+		timeout = time.After(d)
+	}
+	// The db is only marked as shut down cleanly once every service persisted its data, so the
+	// db is checked on the next start otherwise.
+	select {
+	case <-stopped:
+		if err := b.db.SaveDirtyShutdown(b.ctx, false); err != nil {
+			log.WithError(err).Error("Could not mark the database as shut down cleanly")
+		}
+	case <-timeout:
+		log.Error("Timed out waiting for services to stop, the database will be checked on the next start")
+	}
 	b.cancel() // Cancel the beacon node struct's context.
-	b.services.StopAll()
 	if err := b.db.Close(); err != nil {
 		log.Errorf("Failed to close database: %v", err)
 	}
+	if b.eraArchive != nil {
+		if err := b.eraArchive.Close(); err != nil {
+			log.Errorf("Failed to close era archive: %v", err)
+		}
+	}
+	if err := b.dirLock.Release(); err != nil {
+		log.Errorf("Failed to release data directory lock: %v", err)
+	}
 	close(b.stop)
 }
 
@@ -287,12 +415,45 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context) error {
 		}
 	}
 
+	if b.network != nil {
+		if err := checkDBNetwork(b.ctx, d, b.network); err != nil {
+			return err
+		}
+	}
+
+	if err := recoverDirtyShutdown(b.ctx, d); err != nil {
+		return err
+	}
+
 	log.WithField("database-path", dbPath).Info("Checking DB")
 	b.db = d
 	b.depositCache = depositcache.NewDepositCache()
 	return nil
 }
 
+// recoverDirtyShutdown checks the db if the node did not shut down cleanly the last time it ran,
+// e.g. on a crash or a power loss, and marks it in use until the node shuts down cleanly. The db
+// file is checked for corruption and the head is re-derived from the stored blocks and states,
+// as the head block root may point to a block or state whose write was interrupted.
+func recoverDirtyShutdown(ctx context.Context, d db.Database) error {
+	dirty, err := d.DirtyShutdown(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not check for a dirty shutdown")
+	}
+	if dirty {
+		log.Warn("Node did not shut down cleanly, checking database integrity")
+		if err := d.CheckIntegrity(ctx); err != nil {
+			return errors.Wrap(err, "database integrity check failed, restore a backup or resync with --clear-db")
+		}
+		headRoot, err := d.RederiveHead(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not re-derive the head from the database")
+		}
+		log.WithField("headRoot", fmt.Sprintf("%#x", bytesutil.Trunc(headRoot[:]))).Info("Database is consistent")
+	}
+	return d.SaveDirtyShutdown(ctx, true)
+}
+
 func (b *BeaconNode) startStateGen() {
 	b.stateGen = stategen.New(b.db, b.stateSummaryCache)
 }
@@ -315,9 +476,14 @@ func (b *BeaconNode) registerP2P(cliCtx *cli.Context) error {
 		datadir = cmd.DefaultDataDir()
 	}
 
+	if _, err := encoder.ByName(cliCtx.String(cmd.P2PEncoding.Name)); err != nil {
+		return err
+	}
+
 	svc, err := p2p.NewService(&p2p.Config{
 		NoDiscovery:       cliCtx.Bool(cmd.NoDiscovery.Name),
 		StaticPeers:       sliceutil.SplitCommaSeparated(cliCtx.StringSlice(cmd.StaticPeers.Name)),
+		TrustedPeers:      sliceutil.SplitCommaSeparated(cliCtx.StringSlice(cmd.TrustedPeers.Name)),
 		BootstrapNodeAddr: bootnodeAddrs,
 		RelayNodeAddr:     cliCtx.String(cmd.RelayNode.Name),
 		DataDir:           datadir,
@@ -329,9 +495,12 @@ func (b *BeaconNode) registerP2P(cliCtx *cli.Context) error {
 		TCPPort:           cliCtx.Uint(cmd.P2PTCPPort.Name),
 		UDPPort:           cliCtx.Uint(cmd.P2PUDPPort.Name),
 		MaxPeers:          cliCtx.Uint(cmd.P2PMaxPeers.Name),
+		MaxInboundPeers:   cliCtx.Uint(cmd.P2PMaxInboundPeers.Name),
+		MaxOutboundPeers:  cliCtx.Uint(cmd.P2PMaxOutboundPeers.Name),
 		WhitelistCIDR:     cliCtx.String(cmd.P2PWhitelist.Name),
 		BlacklistCIDR:     sliceutil.SplitCommaSeparated(cliCtx.StringSlice(cmd.P2PBlacklist.Name)),
 		EnableUPnP:        cliCtx.Bool(cmd.EnableUPnPFlag.Name),
+		DisableNAT:        cliCtx.Bool(cmd.DisableNATFlag.Name),
 		DisableDiscv5:     cliCtx.Bool(flags.DisableDiscv5.Name),
 		Encoding:          cliCtx.String(cmd.P2PEncoding.Name),
 		StateNotifier:     b,
@@ -373,19 +542,42 @@ func (b *BeaconNode) registerBlockchainService() error {
 	}
 
 	maxRoutines := b.cliCtx.Int64(cmd.MaxGoroutines.Name)
+	var wsCheckpoint *ethpb.Checkpoint
+	if cp := b.cliCtx.String(flags.WeakSubjectivityCheckpointFlag.Name); cp != "" {
+		var err error
+		wsCheckpoint, err = blockchain.ParseWeakSubjectivityCheckpoint(cp)
+		if err != nil {
+			return errors.Wrap(err, "could not parse weak subjectivity checkpoint")
+		}
+	}
+
+	var checkpointState *stateTrie.BeaconState
+	var checkpointBlock *ethpb.SignedBeaconBlock
+	if statePath := b.cliCtx.String(flags.CheckpointStateFlag.Name); statePath != "" {
+		var err error
+		checkpointState, checkpointBlock, err = blockchain.LoadCheckpoint(b.ctx, statePath, b.cliCtx.String(flags.CheckpointBlockFlag.Name))
+		if err != nil {
+			return errors.Wrap(err, "could not load checkpoint state")
+		}
+	}
+
 	blockchainService, err := blockchain.NewService(b.ctx, &blockchain.Config{
-		BeaconDB:          b.db,
-		DepositCache:      b.depositCache,
-		ChainStartFetcher: web3Service,
-		AttPool:           b.attestationPool,
-		ExitPool:          b.exitPool,
-		SlashingPool:      b.slashingsPool,
-		P2p:               b.fetchP2P(),
-		MaxRoutines:       maxRoutines,
-		StateNotifier:     b,
-		ForkChoiceStore:   b.forkChoiceStore,
-		OpsService:        opsService,
-		StateGen:          b.stateGen,
+		BeaconDB:                   b.db,
+		DepositCache:               b.depositCache,
+		ChainStartFetcher:          web3Service,
+		AttPool:                    b.attestationPool,
+		ExitPool:                   b.exitPool,
+		SlashingPool:               b.slashingsPool,
+		P2p:                        b.fetchP2P(),
+		MaxRoutines:                maxRoutines,
+		StateNotifier:              b,
+		ForkChoiceStore:            b.forkChoiceStore,
+		OpsService:                 opsService,
+		StateGen:                   b.stateGen,
+		WeakSubjectivityCheckpoint: wsCheckpoint,
+		CheckpointState:            checkpointState,
+		CheckpointBlock:            checkpointBlock,
+		GenesisState:               b.genesisState,
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not register blockchain service")
@@ -398,24 +590,28 @@ func (b *BeaconNode) registerPOWChainService() error {
 		return b.services.RegisterService(&powchain.Service{})
 	}
 	depAddress := b.cliCtx.String(flags.DepositContractFlag.Name)
-	if depAddress == "" {
-		log.Fatal(fmt.Sprintf("%s is required", flags.DepositContractFlag.Name))
-	}
 
-	if !common.IsHexAddress(depAddress) {
-		log.Fatalf("Invalid deposit contract address given: %s", depAddress)
+	// The first endpoint is connected to, the others are fallen back to.
+	endpoints := strings.Split(b.cliCtx.String(flags.HTTPWeb3ProviderFlag.Name), ",")
+	for i := range endpoints {
+		endpoints[i] = strings.TrimSpace(endpoints[i])
 	}
-
-	if !b.cliCtx.IsSet(flags.HTTPWeb3ProviderFlag.Name) {
+	httpEndpoint, fallbackEndpoints := endpoints[0], endpoints[1:]
+	if b.runWithoutEth1() {
+		log.Info("Interop genesis configured without --http-web3provider, skipping eth1 connection")
+		httpEndpoint, fallbackEndpoints = "", nil
+	} else if !b.cliCtx.IsSet(flags.HTTPWeb3ProviderFlag.Name) {
 		log.Warn("Using default ETH1 connection provided by Prysmatic Labs. Please consider running your own ETH1 node for better uptime, security, and decentralization of ETH2. Visit https://docs.prylabs.network/docs/prysm-usage/setup-eth1 for more information.")
 	}
 
 	cfg := &powchain.Web3ServiceConfig{
-		HTTPEndPoint:    b.cliCtx.String(flags.HTTPWeb3ProviderFlag.Name),
-		DepositContract: common.HexToAddress(depAddress),
-		BeaconDB:        b.db,
-		DepositCache:    b.depositCache,
-		StateNotifier:   b,
+		HTTPEndPoint:          httpEndpoint,
+		DepositContract:       common.HexToAddress(depAddress),
+		BeaconDB:              b.db,
+		DepositCache:          b.depositCache,
+		StateNotifier:         b,
+		FallbackHTTPEndPoints: fallbackEndpoints,
+		GenesisState:          b.genesisState,
 	}
 	web3Service, err := powchain.NewService(b.ctx, cfg)
 	if err != nil {
@@ -452,7 +648,7 @@ func (b *BeaconNode) registerSyncService() error {
 		return err
 	}
 
-	rs := prysmsync.NewRegularSync(&prysmsync.Config{
+	cfg := &prysmsync.Config{
 		DB:                  b.db,
 		P2P:                 b.fetchP2P(),
 		Chain:               chainService,
@@ -465,7 +661,20 @@ func (b *BeaconNode) registerSyncService() error {
 		SlashingPool:        b.slashingsPool,
 		StateSummaryCache:   b.stateSummaryCache,
 		StateGen:            b.stateGen,
-	})
+	}
+	if eraDir := b.cliCtx.String(flags.EraDirFlag.Name); eraDir != "" {
+		archive, err := era.OpenArchive(eraDir)
+		if err != nil {
+			return errors.Wrap(err, "could not open era archive")
+		}
+		log.WithFields(logrus.Fields{
+			"startSlot": archive.StartSlot(),
+			"endSlot":   archive.EndSlot(),
+		}).Info("Serving blocks from era archive")
+		b.eraArchive = archive
+		cfg.ColdHistory = archive
+	}
+	rs := prysmsync.NewRegularSync(cfg)
 
 	return b.services.RegisterService(rs)
 }
@@ -502,11 +711,9 @@ func (b *BeaconNode) registerRPCService() error {
 		return err
 	}
 
-	genesisValidators := b.cliCtx.Uint64(flags.InteropNumValidatorsFlag.Name)
-	genesisStatePath := b.cliCtx.String(flags.InteropGenesisStateFlag.Name)
 	var depositFetcher depositcache.DepositFetcher
 	var chainStartFetcher powchain.ChainStartFetcher
-	if genesisValidators > 0 || genesisStatePath != "" {
+	if b.interopGenesis() {
 		var interopService *interopcoldstart.Service
 		if err := b.services.FetchService(&interopService); err != nil {
 			return err
@@ -522,22 +729,54 @@ func (b *BeaconNode) registerRPCService() error {
 	port := b.cliCtx.String(flags.RPCPort.Name)
 	cert := b.cliCtx.String(flags.CertFlag.Name)
 	key := b.cliCtx.String(flags.KeyFlag.Name)
+	clientCACert := b.cliCtx.String(flags.ClientCACertFlag.Name)
 	slasherCert := b.cliCtx.String(flags.SlasherCertFlag.Name)
 	slasherProvider := b.cliCtx.String(flags.SlasherProviderFlag.Name)
-	mockEth1DataVotes := b.cliCtx.Bool(flags.InteropMockEth1DataVotesFlag.Name)
+	mockEth1DataVotes := b.cliCtx.Bool(flags.InteropMockEth1DataVotesFlag.Name) || b.runWithoutEth1()
 	enableDebugRPCEndpoints := b.cliCtx.Bool(flags.EnableDebugRPCEndpoints.Name)
+	var authToken string
+	if b.cliCtx.Bool(flags.RPCAuthFlag.Name) {
+		tokenFile := b.cliCtx.String(flags.RPCAuthTokenFileFlag.Name)
+		if tokenFile == "" {
+			tokenFile = filepath.Join(b.cliCtx.String(cmd.DataDirFlag.Name), rpcauth.TokenFileName)
+		}
+		token, err := rpcauth.LoadOrCreateToken(tokenFile)
+		if err != nil {
+			return errors.Wrap(err, "could not load RPC auth token")
+		}
+		logutil.RegisterSecret(token)
+		log.WithField("tokenFile", tokenFile).Info("Loaded RPC auth token")
+		authToken = token
+		b.rpcAuthToken = token
+	}
+	var quotaConfig *apimiddleware.QuotaConfig
+	if !b.cliCtx.Bool(flags.DisableRPCQuotasFlag.Name) {
+		quotaConfig = apimiddleware.DefaultQuotaConfig()
+		if path := b.cliCtx.String(flags.RPCQuotaConfigFlag.Name); path != "" {
+			cfg, err := apimiddleware.LoadQuotaConfig(path)
+			if err != nil {
+				return err
+			}
+			quotaConfig = cfg
+		}
+	}
 	p2pService := b.fetchP2P()
 	rpcService := rpc.NewService(b.ctx, &rpc.Config{
 		Host:                    host,
 		Port:                    port,
 		CertFlag:                cert,
 		KeyFlag:                 key,
+		ClientCACertFlag:        clientCACert,
 		BeaconDB:                b.db,
 		Broadcaster:             p2pService,
 		PeersFetcher:            p2pService,
+		PeerManager:             p2pService,
+		IdentityFetcher:         p2pService,
+		MetadataFetcher:         p2pService,
 		HeadFetcher:             chainService,
 		ForkFetcher:             chainService,
 		FinalizationFetcher:     chainService,
+		CanonicalFetcher:        chainService,
 		ParticipationFetcher:    chainService,
 		BlockReceiver:           chainService,
 		AttestationReceiver:     chainService,
@@ -559,6 +798,12 @@ func (b *BeaconNode) registerRPCService() error {
 		SlasherProvider:         slasherProvider,
 		StateGen:                b.stateGen,
 		EnableDebugRPCEndpoints: enableDebugRPCEndpoints,
+		AuthToken:               authToken,
+		QuotaConfig:             quotaConfig,
+		LogRequests:             b.cliCtx.Bool(flags.RPCLogRequestsFlag.Name),
+		HTTPAPIPort:             b.cliCtx.Int(flags.HTTPAPIPortFlag.Name),
+		MaxRecvMsgSize:          b.cliCtx.Int(flags.RPCMaxRecvMsgSizeFlag.Name),
+		MaxSendMsgSize:          b.cliCtx.Int(flags.RPCMaxSendMsgSizeFlag.Name),
 	})
 
 	return b.services.RegisterService(rpcService)
@@ -571,6 +816,7 @@ func (b *BeaconNode) registerPrometheusService() error {
 		panic(err)
 	}
 	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/p2p", Handler: p.InfoHandler})
+	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/p2p/fork", Handler: p.ForkHandler})
 
 	var c *blockchain.Service
 	if err := b.services.FetchService(&c); err != nil {
@@ -582,6 +828,16 @@ func (b *BeaconNode) registerPrometheusService() error {
 	}
 
 	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/tree", Handler: c.TreeHandler})
+	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/tree/dot", Handler: c.TreeDotHandler})
+	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/weak_subjectivity", Handler: c.WeakSubjectivityHandler})
+	additionalHandlers = append(additionalHandlers, prometheus.Handler{Path: "/features", Handler: featureconfig.FeaturesHandler})
+	// Toggling features requires the RPC auth token, so it is only possible with --rpc-auth.
+	if b.rpcAuthToken != "" {
+		additionalHandlers = append(additionalHandlers, prometheus.Handler{
+			Path:    "/features/toggle",
+			Handler: rpcauth.RequireToken(b.rpcAuthToken, featureconfig.ToggleHandler),
+		})
+	}
 
 	service := prometheus.NewPrometheusService(
 		fmt.Sprintf(":%d", b.cliCtx.Int64(flags.MonitoringPortFlag.Name)),
@@ -598,19 +854,66 @@ func (b *BeaconNode) registerGRPCGateway() error {
 		return nil
 	}
 	gatewayPort := b.cliCtx.Int(flags.GRPCGatewayPort.Name)
-	selfAddress := fmt.Sprintf("127.0.0.1:%d", b.cliCtx.Int(flags.RPCPort.Name))
+	rpcHost := b.cliCtx.String(flags.RPCHost.Name)
+	if rpcHost == "" || rpcHost == "0.0.0.0" {
+		rpcHost = "127.0.0.1"
+	}
+	selfAddress := fmt.Sprintf("%s:%d", rpcHost, b.cliCtx.Int(flags.RPCPort.Name))
 	gatewayAddress := fmt.Sprintf("0.0.0.0:%d", gatewayPort)
 	allowedOrigins := strings.Split(b.cliCtx.String(flags.GPRCGatewayCorsDomain.Name), ",")
 	enableDebugRPCEndpoints := b.cliCtx.Bool(flags.EnableDebugRPCEndpoints.Name)
+
+	// The gateway connects like any other client, so it uses TLS when the gRPC server does,
+	// and presents the certificate of the node when client certificates are required.
+	var remoteCreds credentials.TransportCredentials
+	if cert := b.cliCtx.String(flags.CertFlag.Name); cert != "" {
+		var clientCert, clientKey string
+		if b.cliCtx.String(flags.ClientCACertFlag.Name) != "" {
+			clientCert, clientKey = cert, b.cliCtx.String(flags.KeyFlag.Name)
+		}
+		creds, err := grpcutils.ClientTLSCredentials(cert, clientCert, clientKey)
+		if err != nil {
+			return errors.Wrap(err, "could not load gateway TLS credentials")
+		}
+		remoteCreds = creds
+	}
+
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	lightclient.NewServer(&lightclient.Config{
+		HeadFetcher:         chainService,
+		FinalizationFetcher: chainService,
+		BeaconDB:            b.db,
+		StateGen:            b.stateGen,
+	}).RegisterHandlers(mux)
+	if b.cliCtx.Bool(flags.EnableGraphQLFlag.Name) {
+		graphqlServer, err := graphql.NewServer(&graphql.Config{
+			HeadFetcher:      chainService,
+			CanonicalFetcher: chainService,
+			BeaconDB:         b.db,
+			StateGen:         b.stateGen,
+			MaxComplexity:    b.cliCtx.Int64(flags.GraphQLMaxComplexityFlag.Name),
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not create GraphQL server")
+		}
+		graphqlServer.RegisterHandlers(mux)
+	}
+
 	return b.services.RegisterService(
 		gateway.New(
 			b.ctx,
 			selfAddress,
+			remoteCreds,
 			gatewayAddress,
-			nil, /*optional mux*/
+			mux,
 			allowedOrigins,
 			enableDebugRPCEndpoints,
 			b.cliCtx.Uint64(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
+			b.cliCtx.Uint64(cmd.GrpcMaxCallSendMsgSizeFlag.Name),
 		),
 	)
 }
@@ -620,7 +923,7 @@ func (b *BeaconNode) registerInteropServices() error {
 	genesisValidators := b.cliCtx.Uint64(flags.InteropNumValidatorsFlag.Name)
 	genesisStatePath := b.cliCtx.String(flags.InteropGenesisStateFlag.Name)
 
-	if genesisValidators > 0 || genesisStatePath != "" {
+	if b.interopGenesis() {
 		svc := interopcoldstart.NewColdStartService(b.ctx, &interopcoldstart.Config{
 			GenesisTime:   genesisTime,
 			NumValidators: genesisValidators,
@@ -634,6 +937,32 @@ func (b *BeaconNode) registerInteropServices() error {
 	return nil
 }
 
+func (b *BeaconNode) registerClientStatsService() error {
+	url := b.cliCtx.String(cmd.ClientStatsAPIURLFlag.Name)
+	if url == "" {
+		return nil
+	}
+	svc := clientstats.NewService(b.ctx, &clientstats.Config{
+		URL:         url,
+		Interval:    b.cliCtx.Duration(cmd.ClientStatsIntervalFlag.Name),
+		Process:     clientstats.ProcessBeaconNode,
+		DatabaseDir: b.db.DatabasePath(),
+	})
+	return b.services.RegisterService(svc)
+}
+
+// interopGenesis returns true if the genesis state is generated or loaded from the interop flags
+// instead of being derived from the deposit contract.
+func (b *BeaconNode) interopGenesis() bool {
+	return b.cliCtx.Uint64(flags.InteropNumValidatorsFlag.Name) > 0 || b.cliCtx.String(flags.InteropGenesisStateFlag.Name) != ""
+}
+
+// runWithoutEth1 returns true if the node should not follow an eth1 chain, which is the case
+// for an interop genesis unless an eth1 endpoint is explicitly given.
+func (b *BeaconNode) runWithoutEth1() bool {
+	return b.interopGenesis() && !b.cliCtx.IsSet(flags.HTTPWeb3ProviderFlag.Name)
+}
+
 func (b *BeaconNode) registerArchiverService() error {
 	if !flags.Get().EnableArchive {
 		return nil
@@ -647,6 +976,156 @@ func (b *BeaconNode) registerArchiverService() error {
 		HeadFetcher:          chainService,
 		ParticipationFetcher: chainService,
 		StateNotifier:        b,
+		StateGen:             b.stateGen,
+	})
+	return b.services.RegisterService(svc)
+}
+
+func (b *BeaconNode) registerPrunerService() error {
+	if !b.cliCtx.Bool(flags.PruneStatesFlag.Name) {
+		return nil
+	}
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	svc := pruner.NewService(b.ctx, &pruner.Config{
+		BeaconDB:        b.db,
+		HeadFetcher:     chainService,
+		StateNotifier:   b,
+		RetentionEpochs: b.cliCtx.Uint64(flags.RetentionEpochsFlag.Name),
+	})
+	return b.services.RegisterService(svc)
+}
+
+func (b *BeaconNode) registerExporterService() error {
+	var sinks []exporter.Sink
+	if url := b.cliCtx.String(flags.ExportPostgresURLFlag.Name); url != "" {
+		sink, err := exporter.NewPostgresSink(b.ctx, url)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	if url := b.cliCtx.String(flags.ExportKafkaURLFlag.Name); url != "" {
+		sink, err := exporter.NewKafkaSink(url)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	svc := exporter.NewService(b.ctx, &exporter.Config{
+		BeaconDB:      b.db,
+		HeadFetcher:   chainService,
+		StateNotifier: b,
+		StateGen:      b.stateGen,
+		Sinks:         sinks,
+	})
+	return b.services.RegisterService(svc)
+}
+
+func (b *BeaconNode) registerExplorerService() error {
+	port := b.cliCtx.Int(flags.ExplorerAPIPortFlag.Name)
+	if port == 0 {
+		return nil
+	}
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	svc := explorer.NewService(&explorer.Config{
+		Address:             fmt.Sprintf("0.0.0.0:%d", port),
+		BeaconDB:            b.db,
+		FinalizationFetcher: chainService,
+	})
+	return b.services.RegisterService(svc)
+}
+
+func (b *BeaconNode) registerDiskMonitorService() error {
+	svc := diskmonitor.NewService(b.ctx, &diskmonitor.Config{
+		DataDir:            b.cliCtx.String(cmd.DataDirFlag.Name),
+		BeaconDB:           b.db,
+		WarnThreshold:      b.cliCtx.Uint64(flags.DiskWarnThresholdFlag.Name) << 30,
+		EmergencyThreshold: b.cliCtx.Uint64(flags.DiskEmergencyThresholdFlag.Name) << 30,
+	})
+	return b.services.RegisterService(svc)
+}
+
+// configureSlotClock sets an accelerated slot clock if requested for development.
+func configureSlotClock(cliCtx *cli.Context) {
+	slotsPerSecond := cliCtx.Float64(cmd.SlotsPerSecondFlag.Name)
+	if slotsPerSecond <= 0 {
+		return
+	}
+	log.WithField("slotsPerSecond", slotsPerSecond).Warn("Running an accelerated slot clock, for development only")
+	slotutil.SetClock(slotutil.NewClockWithSlotsPerSecond(slotsPerSecond, params.BeaconConfig().SecondsPerSlot))
+}
+
+func (b *BeaconNode) registerRoughtimeService() error {
+	if !b.cliCtx.Bool(cmd.EnableRoughtimeFlag.Name) {
+		return nil
+	}
+	svc := roughtime.NewService(b.ctx, &roughtime.Config{
+		Interval:  b.cliCtx.Duration(cmd.RoughtimeIntervalFlag.Name),
+		MaxOffset: b.cliCtx.Duration(cmd.RoughtimeMaxOffsetFlag.Name),
 	})
 	return b.services.RegisterService(svc)
 }
+
+// registerWatchdogService sends keepalives to the systemd watchdog, if enabled, as long as the
+// slot ticker of the chain service advances and the database accepts writes.
+func (b *BeaconNode) registerWatchdogService() error {
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	slotDuration := time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+	svc := sdnotify.NewService(b.ctx, []sdnotify.Check{
+		{Name: "slot ticker", Check: slotTickerCheck(chainService.LastSlotTick, slotDuration)},
+		{Name: "database", Check: b.db.CheckWritable},
+	})
+	return b.services.RegisterService(svc)
+}
+
+// slotTickerCheck fails when no slot tick was handled for two slots. It passes before the
+// chain started, when there are no ticks yet.
+func slotTickerCheck(lastTick func() time.Time, slotDuration time.Duration) func(context.Context) error {
+	return func(_ context.Context) error {
+		t := lastTick()
+		if t.IsZero() {
+			return nil
+		}
+		if since := time.Since(t); since > 2*slotDuration {
+			return fmt.Errorf("no slot tick for %v", since)
+		}
+		return nil
+	}
+}
+
+// ReloadSettings returns the settings which are applied again on SIGHUP: the log level, the
+// peer limit and the client stats endpoint.
+func (b *BeaconNode) ReloadSettings() []*reload.Setting {
+	settings := []*reload.Setting{reload.LogLevel()}
+	var p *p2p.Service
+	if err := b.services.FetchService(&p); err == nil {
+		settings = append(settings, reload.FlagSetting("peer limit", cmd.P2PMaxPeers.Name, func(value string) error {
+			n, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				return err
+			}
+			return p.SetMaxPeers(uint(n))
+		}))
+	}
+	var stats *clientstats.Service
+	if err := b.services.FetchService(&stats); err == nil {
+		settings = append(settings, reload.FlagSetting("client stats endpoint", cmd.ClientStatsAPIURLFlag.Name, stats.SetURL))
+	}
+	return settings
+}
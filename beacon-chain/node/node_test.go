@@ -1,12 +1,15 @@
 package node
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 	logTest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/urfave/cli/v2"
@@ -47,3 +50,44 @@ func TestNodeClose_OK(t *testing.T) {
 		t.Log(err)
 	}
 }
+
+func TestRecoverDirtyShutdown(t *testing.T) {
+	hook := logTest.NewGlobal()
+	ctx := context.Background()
+	d := dbutil.SetupDB(t)
+
+	if err := recoverDirtyShutdown(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertLogsDoNotContain(t, hook, "Node did not shut down cleanly")
+	dirty, err := d.DirtyShutdown(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dirty {
+		t.Error("Expected the database to be marked in use")
+	}
+
+	// The node restarts without having cleared the mark.
+	if err := recoverDirtyShutdown(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertLogsContain(t, hook, "Node did not shut down cleanly")
+	testutil.AssertLogsContain(t, hook, "Database is consistent")
+}
+
+func TestSlotTickerCheck(t *testing.T) {
+	var lastTick time.Time
+	check := slotTickerCheck(func() time.Time { return lastTick }, time.Second)
+	if err := check(context.Background()); err != nil {
+		t.Errorf("Expected no error before the chain started, received %v", err)
+	}
+	lastTick = time.Now()
+	if err := check(context.Background()); err != nil {
+		t.Errorf("Expected no error for a recent tick, received %v", err)
+	}
+	lastTick = time.Now().Add(-3 * time.Second)
+	if err := check(context.Background()); err == nil {
+		t.Error("Expected an error for a stale tick")
+	}
+}
@@ -0,0 +1,42 @@
+package attestations
+
+import (
+	"time"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+)
+
+// Aggregate the unaggregated attestations in the pool four times per slot, so the
+// proposers find the attestations maximally packed at any time of the slot.
+var aggregateAttsPeriod = slotutil.DivideSlotBy(4 /* times-per-slot */)
+
+// This aggregates the unaggregated attestations of the pool in the background
+// every aggregateAttsPeriod.
+func (s *Service) aggregateRoutine() {
+	ticker := time.NewTicker(aggregateAttsPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.aggregateAtts()
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting routine")
+			return
+		}
+	}
+}
+
+// This aggregates the compatible unaggregated attestations, those with the same data and
+// disjoint aggregation bits, into the aggregated attestations of the pool.
+func (s *Service) aggregateAtts() {
+	if err := s.pool.AggregateUnaggregatedAttestations(); err != nil {
+		log.WithError(err).Error("Could not aggregate unaggregated attestations")
+	}
+	if t := tracer.Default(); t != nil {
+		for _, att := range s.pool.AggregatedAttestations() {
+			t.Observe(att, tracer.Aggregated)
+		}
+	}
+	s.updateMetrics()
+}
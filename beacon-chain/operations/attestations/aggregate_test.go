@@ -0,0 +1,52 @@
+package attestations
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+func TestAggregateAtts_MergesIntoAggregated(t *testing.T) {
+	s, err := NewService(context.Background(), &Config{Pool: NewPool()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sk := bls.RandKey()
+	sig := sk.Sign([]byte("dummy_test_data"))
+	mockRoot := [32]byte{}
+	d := &ethpb.AttestationData{
+		BeaconBlockRoot: mockRoot[:],
+		Source:          &ethpb.Checkpoint{Root: mockRoot[:]},
+		Target:          &ethpb.Checkpoint{Root: mockRoot[:]},
+	}
+
+	aggregatedAtt := &ethpb.Attestation{Data: d, AggregationBits: bitfield.Bitlist{0b100011}, Signature: sig.Marshal()}
+	unaggregatedAtts := []*ethpb.Attestation{
+		{Data: d, AggregationBits: bitfield.Bitlist{0b100100}, Signature: sig.Marshal()},
+		{Data: d, AggregationBits: bitfield.Bitlist{0b100001}, Signature: sig.Marshal()}, // Already aggregated.
+	}
+	if err := s.pool.SaveAggregatedAttestation(aggregatedAtt); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.pool.SaveUnaggregatedAttestations(unaggregatedAtts); err != nil {
+		t.Fatal(err)
+	}
+
+	s.aggregateAtts()
+
+	atts := s.pool.AggregatedAttestations()
+	if len(atts) != 1 {
+		t.Fatalf("Wanted a single aggregated attestation, received %d", len(atts))
+	}
+	want := bitfield.Bitlist{0b100111}
+	if !atts[0].AggregationBits.Contains(want) || atts[0].AggregationBits.Count() != want.Count() {
+		t.Errorf("Wanted aggregation bits %#b, received %#b", want, atts[0].AggregationBits)
+	}
+	if count := s.pool.UnaggregatedAttestationCount(); count != 0 {
+		t.Errorf("Wanted the unaggregated attestations to be removed, %d are left", count)
+	}
+}
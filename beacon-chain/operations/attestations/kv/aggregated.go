@@ -10,7 +10,9 @@ import (
 )
 
 // AggregateUnaggregatedAttestations aggregates the unaggregated attestations and save the
-// newly aggregated attestations in the pool.
+// newly aggregated attestations in the pool. The unaggregated attestations are aggregated
+// together with the aggregated attestations of the same data, so attestations which are
+// disjoint with an existing aggregate are merged into it.
 // It tracks the unaggregated attestations that weren't able to aggregate to prevent
 // the deletion of unaggregated attestations in the pool.
 func (p *AttCaches) AggregateUnaggregatedAttestations() error {
@@ -29,7 +31,11 @@ func (p *AttCaches) AggregateUnaggregatedAttestations() error {
 	leftOverUnaggregatedAtt := make(map[[32]byte]bool)
 	for _, atts := range attsByDataRoot {
 		aggregatedAtts := make([]*ethpb.Attestation, 0, len(atts))
-		processedAtts, err := helpers.AggregateAttestations(atts)
+		existingAtts, err := p.aggregatedAttestationsByData(atts[0].Data)
+		if err != nil {
+			return err
+		}
+		processedAtts, err := helpers.AggregateAttestations(append(existingAtts, atts...))
 		if err != nil {
 			return err
 		}
@@ -66,6 +72,24 @@ func (p *AttCaches) AggregateUnaggregatedAttestations() error {
 	return nil
 }
 
+// aggregatedAttestationsByData returns copies of the aggregated attestations in cache
+// with the given attestation data.
+func (p *AttCaches) aggregatedAttestationsByData(data *ethpb.AttestationData) ([]*ethpb.Attestation, error) {
+	r, err := hashFn(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not tree hash attestation data")
+	}
+
+	p.aggregatedAttLock.RLock()
+	defer p.aggregatedAttLock.RUnlock()
+	atts := make([]*ethpb.Attestation, 0, len(p.aggregatedAtt[r]))
+	for _, a := range p.aggregatedAtt[r] {
+		atts = append(atts, stateTrie.CopyAttestation(a) /* Copied */)
+	}
+
+	return atts, nil
+}
+
 // SaveAggregatedAttestation saves an aggregated attestation in cache.
 func (p *AttCaches) SaveAggregatedAttestation(att *ethpb.Attestation) error {
 	if att == nil || att.Data == nil {
@@ -39,6 +39,36 @@ func TestKV_AggregateUnaggregatedAttestations(t *testing.T) {
 	}
 }
 
+func TestKV_AggregateUnaggregatedAttestations_MergesExisting(t *testing.T) {
+	cache := NewAttCaches()
+	priv := bls.RandKey()
+	sig := priv.Sign([]byte{'a'})
+	aggregated := &ethpb.Attestation{Data: &ethpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b10011}, Signature: sig.Marshal()}
+	att1 := &ethpb.Attestation{Data: &ethpb.AttestationData{Slot: 1}, AggregationBits: bitfield.Bitlist{0b10100}, Signature: sig.Marshal()}
+	att2 := &ethpb.Attestation{Data: &ethpb.AttestationData{Slot: 2}, AggregationBits: bitfield.Bitlist{0b10100}, Signature: sig.Marshal()}
+	if err := cache.SaveAggregatedAttestation(aggregated); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.SaveUnaggregatedAttestations([]*ethpb.Attestation{att1, att2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.AggregateUnaggregatedAttestations(); err != nil {
+		t.Fatal(err)
+	}
+
+	atts := cache.AggregatedAttestationsBySlotIndex(1, 0)
+	if len(atts) != 1 || !reflect.DeepEqual(atts[0].AggregationBits, bitfield.Bitlist{0b10111}) {
+		t.Fatalf("Did not merge into the aggregated attestation, received %v", atts)
+	}
+	// The attestation of slot 2 has nothing to aggregate with.
+	if unaggregated := cache.UnaggregatedAttestations(); len(unaggregated) != 1 || unaggregated[0].Data.Slot != 2 {
+		t.Errorf("Unexpected unaggregated attestations %v", unaggregated)
+	}
+	if len(cache.AggregatedAttestationsBySlotIndex(2, 0)) != 0 {
+		t.Error("Unexpected aggregated attestation of slot 2")
+	}
+}
+
 func TestKV_Aggregated_CanSaveRetrieve(t *testing.T) {
 	cache := NewAttCaches()
 
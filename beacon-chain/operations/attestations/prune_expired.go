@@ -3,17 +3,20 @@ package attestations
 import (
 	"time"
 
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 )
 
 // Prune expired attestations from the pool every slot interval.
-var pruneExpiredAttsPeriod = time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+func pruneExpiredAttsPeriod() time.Duration {
+	return time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+}
 
 // This prunes attestations pool by running pruneExpiredAtts
 // at every pruneExpiredAttsPeriod.
 func (s *Service) pruneAttsPool() {
-	ticker := time.NewTicker(pruneExpiredAttsPeriod)
+	ticker := time.NewTicker(pruneExpiredAttsPeriod())
 	for {
 		select {
 		case <-ticker.C:
@@ -30,6 +33,7 @@ func (s *Service) pruneExpiredAtts() {
 	aggregatedAtts := s.pool.AggregatedAttestations()
 	for _, att := range aggregatedAtts {
 		if s.expired(att.Data.Slot) {
+			tracer.Evict(att)
 			if err := s.pool.DeleteAggregatedAttestation(att); err != nil {
 				log.WithError(err).Error("Could not delete expired aggregated attestation")
 			}
@@ -40,6 +44,7 @@ func (s *Service) pruneExpiredAtts() {
 	unAggregatedAtts := s.pool.UnaggregatedAttestations()
 	for _, att := range unAggregatedAtts {
 		if s.expired(att.Data.Slot) {
+			tracer.Evict(att)
 			if err := s.pool.DeleteUnaggregatedAttestation(att); err != nil {
 				log.WithError(err).Error("Could not delete expired unaggregated attestation")
 			}
@@ -63,7 +68,7 @@ func (s *Service) pruneExpiredAtts() {
 func (s *Service) expired(slot uint64) bool {
 	expirationSlot := slot + params.BeaconConfig().SlotsPerEpoch
 	expirationTime := s.genesisTime + expirationSlot*params.BeaconConfig().SecondsPerSlot
-	currentTime := uint64(roughtime.Now().Unix())
+	currentTime := uint64(slotutil.Now().Unix())
 	if currentTime >= expirationTime {
 		return true
 	}
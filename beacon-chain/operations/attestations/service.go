@@ -46,6 +46,7 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 // Start an attestation pool service's main event loop.
 func (s *Service) Start() {
 	go s.prepareForkChoiceAtts()
+	go s.aggregateRoutine()
 	go s.pruneAttsPool()
 }
 
@@ -0,0 +1,7 @@
+package tracer
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "attestation-tracer")
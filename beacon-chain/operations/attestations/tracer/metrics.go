@@ -0,0 +1,14 @@
+package tracer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var attestationsDropped = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "traced_attestations_dropped_total",
+		Help: "The number of traced attestations dropped before their inclusion, by reason.",
+	},
+	[]string{"reason"},
+)
@@ -0,0 +1,383 @@
+// Package tracer follows the attestations of validators from their production through gossip,
+// aggregation and block inclusion, and records why an attestation was dropped on the way, so
+// operators can tell why a validator missed an attestation. An attester is identified by the
+// slot and committee index of its attestation and by its position in the committee, the bit it
+// sets in the aggregation bits.
+package tracer
+
+import (
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+)
+
+// Stage is a step of the path of an attestation from its production to its inclusion.
+type Stage string
+
+const (
+	// Produced is the submission of the attestation by a validator client of this node.
+	Produced Stage = "produced"
+	// Received is the reception of the unaggregated attestation on its gossip subnet.
+	Received Stage = "received"
+	// Aggregated is the inclusion of the attestation in an aggregate, received on gossip or
+	// aggregated in the pool of this node.
+	Aggregated Stage = "aggregated"
+	// Dropped is the loss of the attestation, along with the reason it was dropped.
+	Dropped Stage = "dropped"
+	// Included is the inclusion of the attestation in a processed block.
+	Included Stage = "included"
+)
+
+// DropReason is the reason an attestation was dropped before its inclusion.
+type DropReason string
+
+const (
+	// Late attestations arrived outside of the attestation propagation slot range.
+	Late DropReason = "late"
+	// Unaggregated attestations expired from the pool without being part of any aggregate.
+	Unaggregated DropReason = "unaggregated"
+	// PoolEviction is the expiry from the pool of an aggregated attestation which was not included.
+	PoolEviction DropReason = "pool_eviction"
+	// PackingLoss is the exclusion of an attestation in the pool from a block produced by this
+	// node, because it was invalid against the block or others covered more attesters.
+	PackingLoss DropReason = "packing_loss"
+)
+
+// Status is the outcome of the attestation of an attester, as far as the node has seen it.
+type Status string
+
+const (
+	// StatusIncluded attestations were included in a block.
+	StatusIncluded Status = "included"
+	// StatusDropped attestations were dropped before any block included them.
+	StatusDropped Status = "dropped"
+	// StatusPending attestations were seen and not dropped, but are not included yet.
+	StatusPending Status = "pending"
+	// StatusNotSeen attestations were never seen by the node.
+	StatusNotSeen Status = "not_seen"
+)
+
+// maxEventsPerAttester bounds the events recorded for an attester, as it is part of every
+// aggregate and every block proposal considering its attestation.
+const maxEventsPerAttester = 32
+
+// Event is a step of the path of an attestation.
+type Event struct {
+	Stage Stage
+	// Reason is the reason the attestation was dropped, for the Dropped stage.
+	Reason DropReason
+	Time   time.Time
+	// Slot and BlockRoot are the block which included the attestation, for the Included stage,
+	// and the slot of the block which left the attestation out, for a packing loss.
+	Slot      uint64
+	BlockRoot [32]byte
+}
+
+// Trace is the path of the attestation of an attester.
+type Trace struct {
+	Slot           uint64
+	CommitteeIndex uint64
+	Position       uint64
+	Events         []*Event
+}
+
+// Status returns the outcome of the attestation along with the reason it was dropped, when it
+// was not included. An attestation is reported as included if any block included it. Otherwise
+// the reason is the first of late, packing loss, unaggregated and pool eviction recorded, from the
+// most to the least specific: an attestation lost to packing or never aggregated ends up evicted
+// from the pool as well.
+func (t *Trace) Status() (Status, DropReason) {
+	if t == nil || len(t.Events) == 0 {
+		return StatusNotSeen, ""
+	}
+	drops := make(map[DropReason]bool)
+	for _, e := range t.Events {
+		if e.Stage == Included {
+			return StatusIncluded, ""
+		}
+		if e.Stage == Dropped {
+			drops[e.Reason] = true
+		}
+	}
+	for _, reason := range []DropReason{Late, PackingLoss, Unaggregated, PoolEviction} {
+		if drops[reason] {
+			return StatusDropped, reason
+		}
+	}
+	return StatusPending, ""
+}
+
+// Inclusion returns the first event including the attestation in a block, nil if it was not
+// included.
+func (t *Trace) Inclusion() *Event {
+	if t == nil {
+		return nil
+	}
+	for _, e := range t.Events {
+		if e.Stage == Included {
+			return e
+		}
+	}
+	return nil
+}
+
+// committeeKey identifies the attestations of a committee.
+type committeeKey struct {
+	slot           uint64
+	committeeIndex uint64
+}
+
+// committeeTrace holds the events of the attesters of a committee by their position.
+type committeeTrace struct {
+	events map[uint64][]*Event
+}
+
+// Tracer records the paths of the attestations of the latest committees.
+type Tracer struct {
+	lock       sync.Mutex
+	committees *lru.Cache
+}
+
+// NewTracer creates a tracer holding the attestations of the given number of committees.
+func NewTracer(size int) *Tracer {
+	c, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	return &Tracer{committees: c}
+}
+
+// Observe records that the attesters of the attestation reached the stage.
+func (t *Tracer) Observe(att *ethpb.Attestation, stage Stage) {
+	t.record(att, &Event{Stage: stage, Time: roughtime.Now()})
+}
+
+// Drop records that the attestation was dropped for the reason.
+func (t *Tracer) Drop(att *ethpb.Attestation, reason DropReason) {
+	t.record(att, &Event{Stage: Dropped, Reason: reason, Time: roughtime.Now()})
+	if att.GetData() != nil {
+		attestationsDropped.WithLabelValues(string(reason)).Inc()
+		log.WithFields(logrus.Fields{
+			"slot":           att.Data.Slot,
+			"committeeIndex": att.Data.CommitteeIndex,
+			"attesters":      att.AggregationBits.Count(),
+			"reason":         reason,
+		}).Debug("Attestation dropped")
+	}
+}
+
+// Evict records that the attestation expired from the pool. The attesters which were never part
+// of an aggregate are dropped as unaggregated, the others for the pool eviction, unless a block
+// included them already.
+func (t *Tracer) Evict(att *ethpb.Attestation) {
+	if att.GetData() == nil || att.AggregationBits == nil {
+		return
+	}
+	unaggregated := bitfield.NewBitlist(att.AggregationBits.Len())
+	aggregated := bitfield.NewBitlist(att.AggregationBits.Len())
+	t.lock.Lock()
+	c := t.committee(committeeKey{slot: att.Data.Slot, committeeIndex: att.Data.CommitteeIndex})
+	for _, i := range bitIndices(att.AggregationBits) {
+		switch {
+		case hasStage(c.events[i], Included):
+		case hasStage(c.events[i], Aggregated):
+			aggregated.SetBitAt(i, true)
+		default:
+			unaggregated.SetBitAt(i, true)
+		}
+	}
+	t.lock.Unlock()
+	if unaggregated.Count() > 0 {
+		t.Drop(&ethpb.Attestation{Data: att.Data, AggregationBits: unaggregated}, Unaggregated)
+	}
+	if aggregated.Count() > 0 {
+		t.Drop(&ethpb.Attestation{Data: att.Data, AggregationBits: aggregated}, PoolEviction)
+	}
+}
+
+// Pack records the attesters of the candidate attestations which are left out of the block at the
+// slot, those not covered by any selected attestation with the same data.
+func (t *Tracer) Pack(slot uint64, candidates []*ethpb.Attestation, selected []*ethpb.Attestation) {
+	covered := make(map[[32]byte]bitfield.Bitlist)
+	for _, att := range selected {
+		root, err := stateutil.AttestationDataRoot(att.Data)
+		if err != nil {
+			continue
+		}
+		if bits, ok := covered[root]; ok && bits.Len() == att.AggregationBits.Len() {
+			covered[root] = bits.Or(att.AggregationBits)
+			continue
+		}
+		covered[root] = att.AggregationBits
+	}
+	for _, att := range candidates {
+		root, err := stateutil.AttestationDataRoot(att.Data)
+		if err != nil {
+			continue
+		}
+		left := bitfield.NewBitlist(att.AggregationBits.Len())
+		bits, ok := covered[root]
+		for _, i := range bitIndices(att.AggregationBits) {
+			if !ok || bits.Len() != left.Len() || !bits.BitAt(i) {
+				left.SetBitAt(i, true)
+			}
+		}
+		if left.Count() == 0 {
+			continue
+		}
+		lost := &ethpb.Attestation{Data: att.Data, AggregationBits: left}
+		t.record(lost, &Event{Stage: Dropped, Reason: PackingLoss, Time: roughtime.Now(), Slot: slot})
+		attestationsDropped.WithLabelValues(string(PackingLoss)).Inc()
+		log.WithFields(logrus.Fields{
+			"slot":           att.Data.Slot,
+			"committeeIndex": att.Data.CommitteeIndex,
+			"attesters":      left.Count(),
+			"blockSlot":      slot,
+		}).Debug("Attestation left out of block")
+	}
+}
+
+// Include records that the attestations were included in the block with the slot and root.
+func (t *Tracer) Include(slot uint64, blockRoot [32]byte, atts []*ethpb.Attestation) {
+	now := roughtime.Now()
+	for _, att := range atts {
+		t.record(att, &Event{Stage: Included, Time: now, Slot: slot, BlockRoot: blockRoot})
+	}
+}
+
+// Trace returns the path of the attestation of the attester at the position of the committee.
+func (t *Tracer) Trace(slot uint64, committeeIndex uint64, position uint64) *Trace {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tr := &Trace{Slot: slot, CommitteeIndex: committeeIndex, Position: position}
+	v, ok := t.committees.Get(committeeKey{slot: slot, committeeIndex: committeeIndex})
+	if !ok {
+		return tr
+	}
+	events := v.(*committeeTrace).events[position]
+	tr.Events = make([]*Event, len(events))
+	for i, e := range events {
+		ev := *e
+		tr.Events[i] = &ev
+	}
+	return tr
+}
+
+func (t *Tracer) record(att *ethpb.Attestation, event *Event) {
+	if att.GetData() == nil || att.AggregationBits == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	c := t.committee(committeeKey{slot: att.Data.Slot, committeeIndex: att.Data.CommitteeIndex})
+	for _, i := range bitIndices(att.AggregationBits) {
+		events := c.events[i]
+		if len(events) >= maxEventsPerAttester || recorded(events, event) {
+			continue
+		}
+		c.events[i] = append(events, event)
+	}
+}
+
+// committee returns the trace of the committee, creating it if needed. The lock must be held.
+func (t *Tracer) committee(key committeeKey) *committeeTrace {
+	if v, ok := t.committees.Get(key); ok {
+		return v.(*committeeTrace)
+	}
+	c := &committeeTrace{events: make(map[uint64][]*Event)}
+	t.committees.Add(key, c)
+	return c
+}
+
+// recorded reports whether the same step is recorded already, as the attestation of an attester
+// is aggregated, packed and dropped again and again.
+func recorded(events []*Event, event *Event) bool {
+	for _, e := range events {
+		if e.Stage == event.Stage && e.Reason == event.Reason && e.Slot == event.Slot && e.BlockRoot == event.BlockRoot {
+			return true
+		}
+	}
+	return false
+}
+
+func hasStage(events []*Event, stage Stage) bool {
+	for _, e := range events {
+		if e.Stage == stage {
+			return true
+		}
+	}
+	return false
+}
+
+func bitIndices(bits bitfield.Bitlist) []uint64 {
+	var indices []uint64
+	for i := uint64(0); i < bits.Len(); i++ {
+		if bits.BitAt(i) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+var (
+	defaultTracer     *Tracer
+	defaultTracerOnce sync.Once
+)
+
+// Default returns the tracer of the node, holding the attestations of the committees of the last
+// two epochs, or nil if attestation tracing is not enabled.
+func Default() *Tracer {
+	if !featureconfig.Get().EnableAttestationTracing {
+		return nil
+	}
+	defaultTracerOnce.Do(func() {
+		cfg := params.BeaconConfig()
+		defaultTracer = NewTracer(int(2 * cfg.SlotsPerEpoch * cfg.MaxCommitteesPerSlot))
+	})
+	return defaultTracer
+}
+
+// Observe records the stage of the attestation with the tracer of the node, if tracing is enabled.
+func Observe(att *ethpb.Attestation, stage Stage) {
+	if t := Default(); t != nil {
+		t.Observe(att, stage)
+	}
+}
+
+// Drop records the dropped attestation with the tracer of the node, if tracing is enabled.
+func Drop(att *ethpb.Attestation, reason DropReason) {
+	if t := Default(); t != nil {
+		t.Drop(att, reason)
+	}
+}
+
+// Evict records the attestation expired from the pool with the tracer of the node, if tracing
+// is enabled.
+func Evict(att *ethpb.Attestation) {
+	if t := Default(); t != nil {
+		t.Evict(att)
+	}
+}
+
+// Pack records the attestations left out of a block with the tracer of the node, if tracing is
+// enabled.
+func Pack(slot uint64, candidates []*ethpb.Attestation, selected []*ethpb.Attestation) {
+	if t := Default(); t != nil {
+		t.Pack(slot, candidates, selected)
+	}
+}
+
+// Include records the attestations of a block with the tracer of the node, if tracing is enabled.
+func Include(slot uint64, blockRoot [32]byte, atts []*ethpb.Attestation) {
+	if t := Default(); t != nil {
+		t.Include(slot, blockRoot, atts)
+	}
+}
@@ -0,0 +1,129 @@
+package tracer
+
+import (
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func attestation(slot uint64, committeeIndex uint64, blockRoot byte, bits ...uint64) *ethpb.Attestation {
+	aggregationBits := bitfield.NewBitlist(8)
+	for _, i := range bits {
+		aggregationBits.SetBitAt(i, true)
+	}
+	return &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			Slot:            slot,
+			CommitteeIndex:  committeeIndex,
+			BeaconBlockRoot: []byte{blockRoot},
+			Source:          &ethpb.Checkpoint{},
+			Target:          &ethpb.Checkpoint{},
+		},
+		AggregationBits: aggregationBits,
+	}
+}
+
+func TestTracer_Included(t *testing.T) {
+	tr := NewTracer(16)
+	att := attestation(5, 1, 'a', 2)
+	tr.Observe(att, Produced)
+	tr.Observe(attestation(5, 1, 'a', 2, 3), Aggregated)
+	// Packing loss in an earlier block does not hide the inclusion.
+	tr.Pack(6, []*ethpb.Attestation{att}, nil)
+	tr.Include(7, [32]byte{'b'}, []*ethpb.Attestation{attestation(5, 1, 'a', 2, 3)})
+
+	trace := tr.Trace(5, 1, 2)
+	if status, reason := trace.Status(); status != StatusIncluded || reason != "" {
+		t.Errorf("Wanted status %s, received %s %s", StatusIncluded, status, reason)
+	}
+	stages := []Stage{Produced, Aggregated, Dropped, Included}
+	if len(trace.Events) != len(stages) {
+		t.Fatalf("Wanted %d events, received %d", len(stages), len(trace.Events))
+	}
+	for i, stage := range stages {
+		if trace.Events[i].Stage != stage {
+			t.Errorf("Wanted stage %s for event %d, received %s", stage, i, trace.Events[i].Stage)
+		}
+	}
+	inclusion := trace.Inclusion()
+	if inclusion == nil || inclusion.Slot != 7 || inclusion.BlockRoot != [32]byte{'b'} {
+		t.Errorf("Unexpected inclusion %+v", inclusion)
+	}
+
+	// The other attesters of the committee and other committees are traced apart.
+	if status, _ := tr.Trace(5, 1, 3).Status(); status != StatusIncluded {
+		t.Errorf("Wanted status %s for position 3, received %s", StatusIncluded, status)
+	}
+	if status, _ := tr.Trace(5, 1, 4).Status(); status != StatusNotSeen {
+		t.Errorf("Wanted status %s for position 4, received %s", StatusNotSeen, status)
+	}
+	if status, _ := tr.Trace(5, 0, 2).Status(); status != StatusNotSeen {
+		t.Errorf("Wanted status %s for committee 0, received %s", StatusNotSeen, status)
+	}
+}
+
+func TestTracer_DropReasons(t *testing.T) {
+	tr := NewTracer(16)
+	tr.Observe(attestation(1, 0, 'a', 0, 1, 2, 3, 4), Received)
+	tr.Drop(attestation(1, 0, 'a', 0), Late)
+	tr.Observe(attestation(1, 0, 'a', 1, 2, 3), Aggregated)
+	// Attester 1 is left out of the block at slot 2 by an aggregate with the same data, attester
+	// 2 has no selected attestation with its data.
+	tr.Pack(2, []*ethpb.Attestation{attestation(1, 0, 'a', 1, 3), attestation(1, 0, 'b', 2)},
+		[]*ethpb.Attestation{attestation(1, 0, 'a', 3)})
+	tr.Evict(attestation(1, 0, 'a', 0, 1, 2, 3, 4))
+
+	tests := []struct {
+		position uint64
+		status   Status
+		reason   DropReason
+	}{
+		{position: 0, status: StatusDropped, reason: Late},
+		{position: 1, status: StatusDropped, reason: PackingLoss},
+		{position: 2, status: StatusDropped, reason: PackingLoss},
+		{position: 3, status: StatusDropped, reason: PoolEviction},
+		{position: 4, status: StatusDropped, reason: Unaggregated},
+	}
+	for _, tt := range tests {
+		status, reason := tr.Trace(1, 0, tt.position).Status()
+		if status != tt.status || reason != tt.reason {
+			t.Errorf("Wanted %s %s for position %d, received %s %s", tt.status, tt.reason, tt.position, status, reason)
+		}
+	}
+
+	tr.Observe(attestation(3, 0, 'a', 0), Received)
+	if status, _ := tr.Trace(3, 0, 0).Status(); status != StatusPending {
+		t.Errorf("Wanted status %s, received %s", StatusPending, status)
+	}
+}
+
+func TestTracer_EvictIncluded(t *testing.T) {
+	tr := NewTracer(16)
+	att := attestation(1, 0, 'a', 0)
+	tr.Include(2, [32]byte{'b'}, []*ethpb.Attestation{att})
+	tr.Evict(att)
+	for _, e := range tr.Trace(1, 0, 0).Events {
+		if e.Stage == Dropped {
+			t.Errorf("Included attestation was dropped for %s", e.Reason)
+		}
+	}
+}
+
+func TestTracer_BoundsEvents(t *testing.T) {
+	tr := NewTracer(1)
+	for i := 0; i < 2*maxEventsPerAttester; i++ {
+		tr.Pack(uint64(i), []*ethpb.Attestation{attestation(1, 0, 'a', 0)}, nil)
+		// The same step is recorded once.
+		tr.Observe(attestation(1, 0, 'a', 0), Aggregated)
+	}
+	if n := len(tr.Trace(1, 0, 0).Events); n != maxEventsPerAttester {
+		t.Errorf("Wanted %d events, received %d", maxEventsPerAttester, n)
+	}
+
+	// Only the latest committee is kept.
+	tr.Observe(attestation(2, 0, 'a', 0), Received)
+	if n := len(tr.Trace(1, 0, 0).Events); n != 0 {
+		t.Errorf("Wanted the evicted committee to have no events, received %d", n)
+	}
+}
@@ -27,8 +27,8 @@ func NewPool() *Pool {
 // PendingAttesterSlashings returns attester slashings that are able to be included into a block.
 // This method will not return more than the block enforced MaxAttesterSlashings.
 func (p *Pool) PendingAttesterSlashings(ctx context.Context, state *beaconstate.BeaconState) []*ethpb.AttesterSlashing {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.lock.Lock()
+	defer p.lock.Unlock()
 	ctx, span := trace.StartSpan(ctx, "operations.PendingAttesterSlashing")
 	defer span.End()
 
@@ -67,8 +67,8 @@ func (p *Pool) PendingAttesterSlashings(ctx context.Context, state *beaconstate.
 // PendingProposerSlashings returns proposer slashings that are able to be included into a block.
 // This method will not return more than the block enforced MaxProposerSlashings.
 func (p *Pool) PendingProposerSlashings(ctx context.Context, state *beaconstate.BeaconState) []*ethpb.ProposerSlashing {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.lock.Lock()
+	defer p.lock.Unlock()
 	ctx, span := trace.StartSpan(ctx, "operations.PendingProposerSlashing")
 	defer span.End()
 
@@ -97,6 +97,27 @@ func (p *Pool) PendingProposerSlashings(ctx context.Context, state *beaconstate.
 	return pending
 }
 
+// AllPendingSlashings returns every slashing in the pool without checking it against a state,
+// for example to persist the pool on shutdown.
+func (p *Pool) AllPendingSlashings() ([]*ethpb.ProposerSlashing, []*ethpb.AttesterSlashing) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	proposerSlashings := make([]*ethpb.ProposerSlashing, len(p.pendingProposerSlashing))
+	copy(proposerSlashings, p.pendingProposerSlashing)
+	// An attester slashing is pending once for every validator it slashes.
+	seen := make(map[*ethpb.AttesterSlashing]bool)
+	attesterSlashings := make([]*ethpb.AttesterSlashing, 0, len(p.pendingAttesterSlashing))
+	for _, s := range p.pendingAttesterSlashing {
+		if seen[s.attesterSlashing] {
+			continue
+		}
+		seen[s.attesterSlashing] = true
+		attesterSlashings = append(attesterSlashings, s.attesterSlashing)
+	}
+	return proposerSlashings, attesterSlashings
+}
+
 // InsertAttesterSlashing into the pool. This method is a no-op if the attester slashing already exists in the pool,
 // has been included into a block recently, or the validator is already exited.
 func (p *Pool) InsertAttesterSlashing(
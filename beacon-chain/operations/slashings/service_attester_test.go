@@ -694,3 +694,28 @@ func TestPool_PendingAttesterSlashings_NoDuplicates(t *testing.T) {
 		t.Errorf("Unexpected return from PendingAttesterSlashings, wanted %v, received %v", want, got)
 	}
 }
+
+func TestPool_AllPendingSlashings(t *testing.T) {
+	as := &ethpb.AttesterSlashing{
+		Attestation_1: &ethpb.IndexedAttestation{AttestingIndices: []uint64{1, 2}},
+		Attestation_2: &ethpb.IndexedAttestation{AttestingIndices: []uint64{1, 2}},
+	}
+	ps := &ethpb.ProposerSlashing{
+		Header_1: &ethpb.SignedBeaconBlockHeader{Header: &ethpb.BeaconBlockHeader{ProposerIndex: 3}},
+	}
+	p := &Pool{
+		pendingProposerSlashing: []*ethpb.ProposerSlashing{ps},
+		pendingAttesterSlashing: []*PendingAttesterSlashing{
+			{attesterSlashing: as, validatorToSlash: 1},
+			{attesterSlashing: as, validatorToSlash: 2},
+		},
+	}
+	proposerSlashings, attesterSlashings := p.AllPendingSlashings()
+	if !reflect.DeepEqual(proposerSlashings, []*ethpb.ProposerSlashing{ps}) {
+		t.Errorf("Wanted proposer slashings %v, received %v", []*ethpb.ProposerSlashing{ps}, proposerSlashings)
+	}
+	// The attester slashing is pending for two validators but only returned once.
+	if !reflect.DeepEqual(attesterSlashings, []*ethpb.AttesterSlashing{as}) {
+		t.Errorf("Wanted attester slashings %v, received %v", []*ethpb.AttesterSlashing{as}, attesterSlashings)
+	}
+}
@@ -48,6 +48,16 @@ func (p *Pool) PendingExits(state *beaconstate.BeaconState, slot uint64) []*ethp
 	return pending
 }
 
+// AllPendingExits returns every exit in the pool without checking it against a state, for
+// example to persist the pool on shutdown.
+func (p *Pool) AllPendingExits() []*ethpb.SignedVoluntaryExit {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	pending := make([]*ethpb.SignedVoluntaryExit, len(p.pending))
+	copy(pending, p.pending)
+	return pending
+}
+
 // InsertVoluntaryExit into the pool. This method is a no-op if the pending exit already exists,
 // has been included recently, or the validator is already exited.
 func (p *Pool) InsertVoluntaryExit(ctx context.Context, state *beaconstate.BeaconState, exit *ethpb.SignedVoluntaryExit) {
@@ -66,9 +76,8 @@ func (p *Pool) InsertVoluntaryExit(ctx context.Context, state *beaconstate.Beaco
 
 	// Does this validator exist in the list already? Use binary search to find the answer.
 	if found := sort.Search(len(p.pending), func(i int) bool {
-		e := p.pending[i].Exit
-		return e.ValidatorIndex == exit.Exit.ValidatorIndex
-	}); found != len(p.pending) {
+		return p.pending[i].Exit.ValidatorIndex >= exit.Exit.ValidatorIndex
+	}); found != len(p.pending) && p.pending[found].Exit.ValidatorIndex == exit.Exit.ValidatorIndex {
 		// If an exit exists with this validator index, prefer one with an earlier exit epoch.
 		if p.pending[found].Exit.Epoch > exit.Exit.Epoch {
 			p.pending[found] = exit
@@ -89,9 +98,9 @@ func (p *Pool) MarkIncluded(exit *ethpb.SignedVoluntaryExit) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	i := sort.Search(len(p.pending), func(i int) bool {
-		return p.pending[i].Exit.ValidatorIndex == exit.Exit.ValidatorIndex
+		return p.pending[i].Exit.ValidatorIndex >= exit.Exit.ValidatorIndex
 	})
-	if i != len(p.pending) {
+	if i != len(p.pending) && p.pending[i].Exit.ValidatorIndex == exit.Exit.ValidatorIndex {
 		p.pending = append(p.pending[:i], p.pending[i+1:]...)
 	}
 	p.included[exit.Exit.ValidatorIndex] = true
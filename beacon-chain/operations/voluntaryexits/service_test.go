@@ -191,6 +191,39 @@ func TestPool_InsertVoluntaryExit(t *testing.T) {
 			},
 			want: []*ethpb.SignedVoluntaryExit{},
 		},
+		{
+			name: "Duplicate in the middle of the list with an earlier epoch",
+			fields: fields{
+				pending: []*ethpb.SignedVoluntaryExit{
+					{
+						Exit: &ethpb.VoluntaryExit{Epoch: 12, ValidatorIndex: 0},
+					},
+					{
+						Exit: &ethpb.VoluntaryExit{Epoch: 12, ValidatorIndex: 1},
+					},
+					{
+						Exit: &ethpb.VoluntaryExit{Epoch: 12, ValidatorIndex: 3},
+					},
+				},
+				included: make(map[uint64]bool),
+			},
+			args: args{
+				exit: &ethpb.SignedVoluntaryExit{
+					Exit: &ethpb.VoluntaryExit{Epoch: 10, ValidatorIndex: 1},
+				},
+			},
+			want: []*ethpb.SignedVoluntaryExit{
+				{
+					Exit: &ethpb.VoluntaryExit{Epoch: 12, ValidatorIndex: 0},
+				},
+				{
+					Exit: &ethpb.VoluntaryExit{Epoch: 10, ValidatorIndex: 1},
+				},
+				{
+					Exit: &ethpb.VoluntaryExit{Epoch: 12, ValidatorIndex: 3},
+				},
+			},
+		},
 	}
 	ctx := context.Background()
 	validators := []*ethpb.Validator{
@@ -308,6 +341,41 @@ func TestPool_MarkIncluded(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Removes the first of the pending list",
+			fields: fields{
+				pending: []*ethpb.SignedVoluntaryExit{
+					{
+						Exit: &ethpb.VoluntaryExit{ValidatorIndex: 1},
+					},
+					{
+						Exit: &ethpb.VoluntaryExit{ValidatorIndex: 2},
+					},
+					{
+						Exit: &ethpb.VoluntaryExit{ValidatorIndex: 3},
+					},
+				},
+				included: make(map[uint64]bool),
+			},
+			args: args{
+				exit: &ethpb.SignedVoluntaryExit{
+					Exit: &ethpb.VoluntaryExit{ValidatorIndex: 1},
+				},
+			},
+			want: fields{
+				pending: []*ethpb.SignedVoluntaryExit{
+					{
+						Exit: &ethpb.VoluntaryExit{ValidatorIndex: 2},
+					},
+					{
+						Exit: &ethpb.VoluntaryExit{ValidatorIndex: 3},
+					},
+				},
+				included: map[uint64]bool{
+					1: true,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -460,3 +528,20 @@ func TestPool_PendingExits(t *testing.T) {
 		})
 	}
 }
+
+func TestPool_AllPendingExits(t *testing.T) {
+	pending := []*ethpb.SignedVoluntaryExit{
+		{Exit: &ethpb.VoluntaryExit{ValidatorIndex: 1, Epoch: 12}},
+		{Exit: &ethpb.VoluntaryExit{ValidatorIndex: 2, Epoch: 0}},
+	}
+	p := &Pool{pending: pending}
+	got := p.AllPendingExits()
+	if !reflect.DeepEqual(got, pending) {
+		t.Errorf("Wanted %v, received %v", pending, got)
+	}
+	// Modifying the result must not change the pool.
+	got[0] = nil
+	if p.pending[0] == nil {
+		t.Error("Expected a copy of the pending exits")
+	}
+}
@@ -9,8 +9,10 @@ import (
 type Config struct {
 	NoDiscovery           bool
 	EnableUPnP            bool
+	DisableNAT            bool
 	DisableDiscv5         bool
 	StaticPeers           []string
+	TrustedPeers          []string
 	BootstrapNodeAddr     []string
 	KademliaBootStrapAddr []string
 	Discv5BootStrapAddr   []string
@@ -24,6 +26,8 @@ type Config struct {
 	TCPPort               uint
 	UDPPort               uint
 	MaxPeers              uint
+	MaxInboundPeers       uint
+	MaxOutboundPeers      uint
 	WhitelistCIDR         string
 	BlacklistCIDR         []string
 	Encoding              string
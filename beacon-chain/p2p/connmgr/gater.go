@@ -0,0 +1,97 @@
+package connmgr
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Gater decides which peers the node keeps connections with, rejecting bad peers and limiting the
+// number of peers connected inbound and outbound apart, so that inbound peers cannot take up every
+// connection slot of the node. Allowed peers, like static and trusted peers, are never rejected as
+// bad peers nor for exceeding a limit. Peers are checked before being dialed, and the connection
+// handler of the node disconnects the peers of new connections which are rejected.
+type Gater struct {
+	maxInbound  int
+	maxOutbound int
+	isBad       func(peer.ID) bool
+
+	lock    sync.RWMutex
+	allowed map[peer.ID]bool
+}
+
+// NewGater creates a connection gater. A limit of zero does not limit the peers connected in that
+// direction. The isBad function reports the peers to reject, and may be nil.
+func NewGater(maxInbound, maxOutbound int, isBad func(peer.ID) bool) *Gater {
+	return &Gater{
+		maxInbound:  maxInbound,
+		maxOutbound: maxOutbound,
+		isBad:       isBad,
+		allowed:     make(map[peer.ID]bool),
+	}
+}
+
+// Allow exempts the peer from the bad peer check and the peer limits.
+func (g *Gater) Allow(pid peer.ID) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.allowed[pid] = true
+}
+
+// Allowed returns whether the peer is exempt from the bad peer check and the peer limits.
+func (g *Gater) Allowed(pid peer.ID) bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.allowed[pid]
+}
+
+// AllowDial returns whether the peer may be dialed, given the open connections of the node. Bad
+// peers are rejected, and new peers once the outbound limit is reached.
+func (g *Gater) AllowDial(p peer.ID, conns []network.Conn) bool {
+	return g.AllowConnection(network.DirOutbound, p, conns)
+}
+
+// AllowConnection returns whether a connection with the peer in the direction may be kept, given
+// the open connections of the node, which may include the connection itself. Bad peers are
+// rejected, and new peers once the limit of the direction is reached. Further connections of
+// connected peers do not count against the limits.
+func (g *Gater) AllowConnection(dir network.Direction, p peer.ID, conns []network.Conn) bool {
+	if g.Allowed(p) {
+		return true
+	}
+	if g.isBad != nil && g.isBad(p) {
+		log.Debugf("rejecting connection of bad peer %s", p)
+		return false
+	}
+	inbound, outbound := PeersByDirection(conns)
+	same, other, max, name := inbound, outbound, g.maxInbound, "inbound"
+	if dir == network.DirOutbound {
+		same, other, max, name = outbound, inbound, g.maxOutbound, "outbound"
+	}
+	if other[p] {
+		return true
+	}
+	// The peer counts once against the limit, whether or not its connection is already open.
+	delete(same, p)
+	if max > 0 && len(same) >= max {
+		log.Debugf("rejecting %s connection of peer %s: at %s peer limit", name, p, name)
+		return false
+	}
+	return true
+}
+
+// PeersByDirection returns the peers with inbound and outbound connections among the connections.
+// Connections of unknown direction are counted as inbound, as the node did not initiate them.
+func PeersByDirection(conns []network.Conn) (map[peer.ID]bool, map[peer.ID]bool) {
+	inbound := make(map[peer.ID]bool)
+	outbound := make(map[peer.ID]bool)
+	for _, c := range conns {
+		if c.Stat().Direction == network.DirOutbound {
+			outbound[c.RemotePeer()] = true
+			continue
+		}
+		inbound[c.RemotePeer()] = true
+	}
+	return inbound, outbound
+}
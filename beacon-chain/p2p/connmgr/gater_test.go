@@ -0,0 +1,81 @@
+package connmgr
+
+import (
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	tu "github.com/libp2p/go-libp2p-core/test"
+)
+
+type dirConn struct {
+	tconn
+	dir network.Direction
+}
+
+func (c *dirConn) Stat() network.Stat {
+	return network.Stat{Direction: c.dir}
+}
+
+func connWith(p peer.ID, dir network.Direction) network.Conn {
+	return &dirConn{tconn: tconn{peer: p}, dir: dir}
+}
+
+func TestGater_PeerLimits(t *testing.T) {
+	g := NewGater(1, 2, nil)
+
+	in := tu.RandPeerIDFatal(t)
+	conns := []network.Conn{connWith(in, network.DirInbound)}
+	newPeer := tu.RandPeerIDFatal(t)
+	if g.AllowConnection(network.DirInbound, newPeer, append(conns, connWith(newPeer, network.DirInbound))) {
+		t.Error("Inbound peer accepted above the inbound limit")
+	}
+	// The inbound limit leaves the outbound slots to the node.
+	if !g.AllowDial(tu.RandPeerIDFatal(t), conns) {
+		t.Error("Outbound peer rejected below the outbound limit")
+	}
+	// The connection being checked does not count against the limit.
+	if !g.AllowConnection(network.DirInbound, in, conns) {
+		t.Error("Connection of the only inbound peer rejected")
+	}
+
+	for i := 0; i < 2; i++ {
+		conns = append(conns, connWith(tu.RandPeerIDFatal(t), network.DirOutbound))
+	}
+	inbound, outbound := PeersByDirection(conns)
+	if len(inbound) != 1 || len(outbound) != 2 {
+		t.Errorf("Wanted 1 inbound and 2 outbound peers, received %d and %d", len(inbound), len(outbound))
+	}
+	if g.AllowDial(tu.RandPeerIDFatal(t), conns) {
+		t.Error("Outbound peer dialed above the outbound limit")
+	}
+	// Connected peers may open further connections in the other direction.
+	if !g.AllowDial(in, conns) {
+		t.Error("Further connection of a connected peer rejected")
+	}
+
+	allowed := tu.RandPeerIDFatal(t)
+	g.Allow(allowed)
+	if !g.AllowDial(allowed, conns) || !g.AllowConnection(network.DirInbound, allowed, conns) {
+		t.Error("Allowed peer rejected at the peer limits")
+	}
+
+	if !g.AllowConnection(network.DirInbound, newPeer, conns[1:]) {
+		t.Error("Inbound peer rejected below the inbound limit")
+	}
+}
+
+func TestGater_BadPeers(t *testing.T) {
+	bad := tu.RandPeerIDFatal(t)
+	g := NewGater(0, 0, func(p peer.ID) bool { return p == bad })
+	if g.AllowDial(bad, nil) || g.AllowConnection(network.DirInbound, bad, nil) {
+		t.Error("Bad peer accepted")
+	}
+	if !g.AllowDial(tu.RandPeerIDFatal(t), nil) {
+		t.Error("Peer rejected without limits")
+	}
+	g.Allow(bad)
+	if !g.AllowDial(bad, nil) {
+		t.Error("Allowed bad peer rejected")
+	}
+}
@@ -0,0 +1,33 @@
+package encoder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// registry maps the names of the supported encoding formats to their network encoders.
+var registry = map[string]NetworkEncoding{
+	SSZ:       &SszNetworkEncoder{},
+	SSZSnappy: &SszNetworkEncoder{UseSnappyCompression: true},
+}
+
+// ByName returns the network encoder of the encoding format, as given by the p2p
+// encoding flag.
+func ByName(name string) (NetworkEncoding, error) {
+	e, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown network encoding %q, supported encodings are %s and %s", name, SSZ, SSZSnappy)
+	}
+	return e, nil
+}
+
+// ByProtocolID returns the network encoder indicated by the suffix of a protocol ID
+// or gossip topic, such as /ssz_snappy.
+func ByProtocolID(protocolID string) (NetworkEncoding, error) {
+	for _, e := range registry {
+		if strings.HasSuffix(protocolID, e.ProtocolSuffix()) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("no network encoding for protocol %s", protocolID)
+}
@@ -0,0 +1,55 @@
+package encoder
+
+import (
+	"testing"
+)
+
+func TestByName(t *testing.T) {
+	e, err := ByName(SSZSnappy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ProtocolSuffix() != "/ssz_snappy" {
+		t.Errorf("Wanted the snappy encoder, received suffix %s", e.ProtocolSuffix())
+	}
+	e, err = ByName(SSZ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ProtocolSuffix() != "/ssz" {
+		t.Errorf("Wanted the ssz encoder, received suffix %s", e.ProtocolSuffix())
+	}
+	if _, err := ByName("ssz_snappy"); err == nil {
+		t.Error("Expected error for an unknown encoding")
+	}
+}
+
+func TestByProtocolID(t *testing.T) {
+	tests := []struct {
+		protocolID string
+		snappy     bool
+		wantErr    bool
+	}{
+		{protocolID: "/eth2/beacon_chain/req/status/1/ssz_snappy", snappy: true},
+		{protocolID: "/eth2/beacon_chain/req/status/1/ssz"},
+		{protocolID: "/eth2/e7a75d5a/beacon_attestation_1/ssz_snappy", snappy: true},
+		{protocolID: "/eth2/e7a75d5a/beacon_block/ssz"},
+		{protocolID: "/eth2/e7a75d5a/beacon_block", wantErr: true},
+	}
+	for _, tt := range tests {
+		e, err := ByProtocolID(tt.protocolID)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Expected error for protocol %s", tt.protocolID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Could not get encoding of protocol %s: %v", tt.protocolID, err)
+			continue
+		}
+		if e.(*SszNetworkEncoder).UseSnappyCompression != tt.snappy {
+			t.Errorf("Wrong encoding for protocol %s", tt.protocolID)
+		}
+	}
+}
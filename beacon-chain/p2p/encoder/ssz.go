@@ -10,6 +10,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/shared/bufutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
@@ -29,14 +30,42 @@ var bufWriterPool = new(sync.Pool)
 // can be constantly reused.
 var bufReaderPool = new(sync.Pool)
 
+// marshalerTo is implemented by messages with generated SSZ code, which can be encoded into
+// a pooled buffer.
+type marshalerTo interface {
+	MarshalSSZTo(dst []byte) ([]byte, error)
+	SizeSSZ() int
+}
+
+// unmarshaler is implemented by messages with generated SSZ code. The generated code copies
+// the bytes it keeps, so these messages can be decoded from a pooled buffer.
+type unmarshaler interface {
+	UnmarshalSSZ(buf []byte) error
+}
+
 // SszNetworkEncoder supports p2p networking encoding using SimpleSerialize
 // with snappy compression (if enabled).
 type SszNetworkEncoder struct {
 	UseSnappyCompression bool
 }
 
-func (e SszNetworkEncoder) doEncode(msg interface{}) ([]byte, error) {
-	return ssz.Marshal(msg)
+// doEncode marshals the message, into a pooled buffer if the message has generated SSZ code.
+// The returned buffer, which may be nil, is released with bufutil.Put once the encoding has
+// been written.
+func (e SszNetworkEncoder) doEncode(msg interface{}) ([]byte, *[]byte, error) {
+	m, ok := msg.(marshalerTo)
+	if !ok {
+		b, err := ssz.Marshal(msg)
+		return b, nil, err
+	}
+	buf := bufutil.Get(m.SizeSSZ())
+	b, err := m.MarshalSSZTo(*buf)
+	if err != nil {
+		bufutil.Put(buf)
+		return nil, nil, err
+	}
+	*buf = b
+	return b, buf, nil
 }
 
 // Encode the proto message to the io.Writer.
@@ -44,10 +73,11 @@ func (e SszNetworkEncoder) Encode(w io.Writer, msg interface{}) (int, error) {
 	if msg == nil {
 		return 0, nil
 	}
-	b, err := e.doEncode(msg)
+	b, buf, err := e.doEncode(msg)
 	if err != nil {
 		return 0, err
 	}
+	defer bufutil.Put(buf)
 	if e.UseSnappyCompression {
 		return writeSnappyBuffer(w, b)
 	}
@@ -59,15 +89,18 @@ func (e SszNetworkEncoder) EncodeGossip(w io.Writer, msg interface{}) (int, erro
 	if msg == nil {
 		return 0, nil
 	}
-	b, err := e.doEncode(msg)
+	b, buf, err := e.doEncode(msg)
 	if err != nil {
 		return 0, err
 	}
+	defer bufutil.Put(buf)
 	if len(b) > int(MaxGossipSize) {
 		return 0, errors.Errorf("gossip message exceeds max gossip size: %d bytes > %d bytes", len(b), MaxGossipSize)
 	}
 	if e.UseSnappyCompression {
-		b = snappy.Encode(nil /*dst*/, b)
+		dst := bufutil.Get(snappy.MaxEncodedLen(len(b)))
+		defer bufutil.Put(dst)
+		b = snappy.Encode((*dst)[:cap(*dst)], b)
 	}
 	return w.Write(b)
 }
@@ -78,10 +111,11 @@ func (e SszNetworkEncoder) EncodeWithLength(w io.Writer, msg interface{}) (int,
 	if msg == nil {
 		return 0, nil
 	}
-	b, err := e.doEncode(msg)
+	b, buf, err := e.doEncode(msg)
 	if err != nil {
 		return 0, err
 	}
+	defer bufutil.Put(buf)
 	// write varint first
 	_, err = w.Write(proto.EncodeVarint(uint64(len(b))))
 	if err != nil {
@@ -99,10 +133,11 @@ func (e SszNetworkEncoder) EncodeWithMaxLength(w io.Writer, msg interface{}, max
 	if msg == nil {
 		return 0, nil
 	}
-	b, err := e.doEncode(msg)
+	b, buf, err := e.doEncode(msg)
 	if err != nil {
 		return 0, err
 	}
+	defer bufutil.Put(buf)
 	if uint64(len(b)) > maxSize {
 		return 0, fmt.Errorf("size of encoded message is %d which is larger than the provided max limit of %d", len(b), maxSize)
 	}
@@ -118,9 +153,23 @@ func (e SszNetworkEncoder) EncodeWithMaxLength(w io.Writer, msg interface{}, max
 }
 
 func (e SszNetworkEncoder) doDecode(b []byte, to interface{}) error {
+	if m, ok := to.(unmarshaler); ok {
+		return m.UnmarshalSSZ(b)
+	}
 	return ssz.Unmarshal(b, to)
 }
 
+// decodeBuffer returns a buffer of the given length to read the encoding of a message into.
+// The buffer is pooled if the message does not keep references to it once decoded, and must
+// then be released with bufutil.Put.
+func decodeBuffer(to interface{}, length int) ([]byte, *[]byte) {
+	if _, ok := to.(unmarshaler); !ok {
+		return make([]byte, length), nil
+	}
+	buf := bufutil.Get(length)
+	return (*buf)[:length], buf
+}
+
 // Decode the bytes to the protobuf message provided.
 func (e SszNetworkEncoder) Decode(b []byte, to interface{}) error {
 	if e.UseSnappyCompression {
@@ -128,7 +177,8 @@ func (e SszNetworkEncoder) Decode(b []byte, to interface{}) error {
 		r := newBufferedReader(newBuffer)
 		defer bufReaderPool.Put(r)
 
-		newObj := make([]byte, len(b))
+		newObj, buf := decodeBuffer(to, len(b))
+		defer bufutil.Put(buf)
 		numOfBytes, err := r.Read(newObj)
 		if err != nil {
 			return err
@@ -141,8 +191,18 @@ func (e SszNetworkEncoder) Decode(b []byte, to interface{}) error {
 // DecodeGossip decodes the bytes to the protobuf gossip message provided.
 func (e SszNetworkEncoder) DecodeGossip(b []byte, to interface{}) error {
 	if e.UseSnappyCompression {
-		var err error
-		b, err = snappy.Decode(nil /*dst*/, b)
+		// Check the length in the snappy header first, so an oversized message is rejected
+		// before its decoded buffer is allocated.
+		decodedLen, err := snappy.DecodedLen(b)
+		if err != nil {
+			return err
+		}
+		if decodedLen > int(MaxGossipSize) {
+			return errors.Errorf("gossip message exceeds max gossip size: %d bytes > %d bytes", decodedLen, MaxGossipSize)
+		}
+		newObj, buf := decodeBuffer(to, decodedLen)
+		defer bufutil.Put(buf)
+		b, err = snappy.Decode(newObj, b)
 		if err != nil {
 			return err
 		}
@@ -175,7 +235,8 @@ func (e SszNetworkEncoder) DecodeWithMaxLength(r io.Reader, to interface{}, maxS
 	if msgLen > maxSize {
 		return fmt.Errorf("size of decoded message is %d which is larger than the provided max limit of %d", msgLen, maxSize)
 	}
-	b := make([]byte, e.MaxLength(int(msgLen)))
+	b, buf := decodeBuffer(to, e.MaxLength(int(msgLen)))
+	defer bufutil.Put(buf)
 	numOfBytes, err := r.Read(b)
 	if err != nil {
 		return err
@@ -2,12 +2,14 @@ package encoder_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	testpb "github.com/prysmaticlabs/prysm/proto/testing"
 )
 
@@ -134,3 +136,56 @@ func TestSszNetworkEncoder_DecodeWithMaxLength_TooLarge(t *testing.T) {
 		t.Error("Expected error to contain 'exceeds max chunk size'")
 	}
 }
+
+func TestSszNetworkEncoder_DecodeGossip_OversizedSnappyHeader(t *testing.T) {
+	e := &encoder.SszNetworkEncoder{UseSnappyCompression: true}
+	// A snappy block only consisting of a header which claims a decoded length larger than
+	// the maximum gossip size.
+	b := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(b, encoder.MaxGossipSize+1)
+	err := e.DecodeGossip(b[:n], &testpb.TestSimpleMessage{})
+	if err == nil || !strings.Contains(err.Error(), "exceeds max gossip size") {
+		t.Errorf("Expected error to contain 'exceeds max gossip size', received %v", err)
+	}
+}
+
+func TestSszNetworkEncoder_PooledBuffersNotRetained(t *testing.T) {
+	for _, e := range []*encoder.SszNetworkEncoder{{UseSnappyCompression: false}, {UseSnappyCompression: true}} {
+		first := &pb.Status{ForkDigest: []byte{1, 2, 3, 4}, FinalizedRoot: bytes.Repeat([]byte{'a'}, 32), HeadRoot: bytes.Repeat([]byte{'b'}, 32), HeadSlot: 10}
+		second := &pb.Status{ForkDigest: []byte{5, 6, 7, 8}, FinalizedRoot: bytes.Repeat([]byte{'c'}, 32), HeadRoot: bytes.Repeat([]byte{'d'}, 32), HeadSlot: 20}
+
+		firstBuf, secondBuf := new(bytes.Buffer), new(bytes.Buffer)
+		if _, err := e.EncodeGossip(firstBuf, first); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.EncodeGossip(secondBuf, second); err != nil {
+			t.Fatal(err)
+		}
+		decodedFirst, decodedSecond := &pb.Status{}, &pb.Status{}
+		if err := e.DecodeGossip(firstBuf.Bytes(), decodedFirst); err != nil {
+			t.Fatal(err)
+		}
+		if err := e.DecodeGossip(secondBuf.Bytes(), decodedSecond); err != nil {
+			t.Fatal(err)
+		}
+		// Decoding the second message must not overwrite the first through a shared buffer.
+		if !proto.Equal(decodedFirst, first) {
+			t.Errorf("Wanted %v, received %v", first, decodedFirst)
+		}
+		if !proto.Equal(decodedSecond, second) {
+			t.Errorf("Wanted %v, received %v", second, decodedSecond)
+		}
+
+		buf := new(bytes.Buffer)
+		if _, err := e.EncodeWithLength(buf, first); err != nil {
+			t.Fatal(err)
+		}
+		decoded := &pb.Status{}
+		if err := e.DecodeWithLength(buf, decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(decoded, first) {
+			t.Errorf("Wanted %v, received %v", first, decoded)
+		}
+	}
+}
@@ -3,19 +3,18 @@ package p2p
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"math"
+	"net/http"
 	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/go-ssz"
-	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/p2putils"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/sirupsen/logrus"
 )
 
@@ -28,6 +27,49 @@ func (s *Service) forkDigest() ([4]byte, error) {
 	return p2putils.CreateForkDigest(s.genesisTime, s.genesisValidatorsRoot)
 }
 
+// ForkHandler is a handler to serve the /p2p/fork page in metrics. It reports the fork digests
+// and the eth2 ENR entry the node derives from the active config, so external tooling can check
+// it computes the same values.
+func (s *Service) ForkHandler(w http.ResponseWriter, _ *http.Request) {
+	digest, err := s.forkDigest()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	nextDigest, err := p2putils.NextForkDigest(s.genesisTime, s.genesisValidatorsRoot)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	enrForkID, err := p2putils.CreateENRForkID(s.genesisTime, s.genesisValidatorsRoot)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entry, err := ssz.Marshal(enrForkID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := struct {
+		CurrentForkDigest string `json:"current_fork_digest"`
+		NextForkDigest    string `json:"next_fork_digest"`
+		NextForkVersion   string `json:"next_fork_version"`
+		NextForkEpoch     uint64 `json:"next_fork_epoch"`
+		ENREntry          string `json:"enr_eth2_entry"`
+	}{
+		CurrentForkDigest: fmt.Sprintf("%#x", digest),
+		NextForkDigest:    fmt.Sprintf("%#x", nextDigest),
+		NextForkVersion:   fmt.Sprintf("%#x", enrForkID.NextForkVersion),
+		NextForkEpoch:     enrForkID.NextForkEpoch,
+		ENREntry:          fmt.Sprintf("%#x", entry),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.WithError(err).Error("Could not write fork response")
+	}
+}
+
 // Compares fork ENRs between an incoming peer's record and our node's
 // local record values for current and next fork version/epoch.
 func (s *Service) compareForkENR(record *enr.Record) error {
@@ -85,38 +127,54 @@ func addForkEntry(
 	genesisTime time.Time,
 	genesisValidatorsRoot []byte,
 ) (*enode.LocalNode, error) {
-	digest, err := p2putils.CreateForkDigest(genesisTime, genesisValidatorsRoot)
+	enc, err := p2putils.ENRForkEntry(genesisTime, genesisValidatorsRoot)
 	if err != nil {
 		return nil, err
 	}
-	currentSlot := helpers.SlotsSince(genesisTime)
-	currentEpoch := helpers.SlotToEpoch(currentSlot)
-	if roughtime.Now().Before(genesisTime) {
-		currentSlot, currentEpoch = 0, 0
+	forkEntry := enr.WithEntry(eth2ENRKey, enc)
+	node.Set(forkEntry)
+	return node, nil
+}
+
+// refreshForkEntry updates the fork entry of the local ENR once the fork digest or the next
+// planned fork changed, so that after a fork transition peers filter the node by the fork it
+// is on rather than the one it started with.
+func (s *Service) refreshForkEntry() {
+	if s.genesisValidatorsRoot == nil {
+		return
 	}
-	fork, err := p2putils.Fork(currentEpoch)
+	updated, err := updateForkEntry(s.dv5Listener.LocalNode(), s.genesisTime, s.genesisValidatorsRoot)
 	if err != nil {
-		return nil, err
+		log.WithError(err).Error("Could not update eth2 fork entry of the local ENR")
+		return
 	}
+	if updated {
+		digest, err := s.forkDigest()
+		if err != nil {
+			log.WithError(err).Error("Could not compute fork digest")
+			return
+		}
+		log.WithField("forkDigest", fmt.Sprintf("%#x", digest)).Info("Updated eth2 fork entry of the local ENR")
+	}
+}
 
-	nextForkEpoch := params.BeaconConfig().NextForkEpoch
-	nextForkVersion := params.BeaconConfig().NextForkVersion
-	// Set to the current fork version if our next fork is not planned.
-	if nextForkEpoch == math.MaxUint64 {
-		nextForkVersion = fork.CurrentVersion
-	}
-	enrForkID := &pb.ENRForkID{
-		CurrentForkDigest: digest[:],
-		NextForkVersion:   nextForkVersion,
-		NextForkEpoch:     nextForkEpoch,
-	}
-	enc, err := ssz.Marshal(enrForkID)
+// updateForkEntry sets the fork entry for the current time on the local node if it differs
+// from the entry of the node, returning whether the entry was updated.
+func updateForkEntry(
+	node *enode.LocalNode,
+	genesisTime time.Time,
+	genesisValidatorsRoot []byte,
+) (bool, error) {
+	enc, err := p2putils.ENRForkEntry(genesisTime, genesisValidatorsRoot)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	forkEntry := enr.WithEntry(eth2ENRKey, enc)
-	node.Set(forkEntry)
-	return node, nil
+	current := make([]byte, len(enc))
+	if err := node.Node().Record().Load(enr.WithEntry(eth2ENRKey, &current)); err == nil && bytes.Equal(current, enc) {
+		return false, nil
+	}
+	node.Set(enr.WithEntry(eth2ENRKey, enc))
+	return true, nil
 }
 
 // Retrieves an enrForkID from an ENR record by key lookup
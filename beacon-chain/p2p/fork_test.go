@@ -2,7 +2,11 @@ package p2p
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"strconv"
@@ -288,3 +292,98 @@ func TestAddForkEntry_Genesis(t *testing.T) {
 		t.Errorf("Wanted Next Fork Version to be equal to genesis fork version, instead got %#x", forkEntry.NextForkVersion)
 	}
 }
+
+func TestUpdateForkEntry_ForkTransition(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	db, err := enode.OpenDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkey, err := privKey(&Config{Encoding: "ssz", DataDir: testutil.TempDir()})
+	if err != nil {
+		t.Fatalf("Could not get private key: %v", err)
+	}
+	genesisTime := time.Now()
+	genesisValidatorsRoot := make([]byte, 32)
+	localNode, err := addForkEntry(enode.NewLocalNode(db, pkey), genesisTime, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := localNode.Node().Seq()
+
+	updated, err := updateForkEntry(localNode, genesisTime, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated || localNode.Node().Seq() != seq {
+		t.Error("Fork entry updated without a fork transition")
+	}
+
+	// Pretend the chain forked to a new version at the current epoch.
+	forkVersion := []byte{0, 0, 0, 1}
+	c := params.BeaconConfig()
+	c.ForkVersionSchedule = map[uint64][]byte{0: forkVersion}
+	params.OverrideBeaconConfig(c)
+	updated, err = updateForkEntry(localNode, genesisTime, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated || localNode.Node().Seq() <= seq {
+		t.Error("Fork entry not updated after a fork transition")
+	}
+	forkEntry, err := retrieveForkEntry(localNode.Node().Record())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := helpers.ComputeForkDigest(forkVersion, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(forkEntry.CurrentForkDigest, want[:]) {
+		t.Errorf("Wanted fork digest %#x, received %#x", want, forkEntry.CurrentForkDigest)
+	}
+}
+
+func TestForkHandler(t *testing.T) {
+	genesisValidatorsRoot := make([]byte, 32)
+	s := &Service{
+		genesisTime:           time.Now(),
+		genesisValidatorsRoot: genesisValidatorsRoot,
+	}
+	rec := httptest.NewRecorder()
+	s.ForkHandler(rec, httptest.NewRequest(http.MethodGet, "/p2p/fork", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	resp := make(map[string]interface{})
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := p2putils.CreateForkDigest(s.genesisTime, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("%#x", digest); resp["current_fork_digest"] != want {
+		t.Errorf("Wanted current fork digest %s, received %v", want, resp["current_fork_digest"])
+	}
+	// No fork is planned, so the next fork digest is the current one.
+	if resp["next_fork_digest"] != resp["current_fork_digest"] {
+		t.Errorf("Wanted next fork digest %v, received %v", resp["current_fork_digest"], resp["next_fork_digest"])
+	}
+	enc, err := p2putils.ENRForkEntry(s.genesisTime, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("%#x", enc); resp["enr_eth2_entry"] != want {
+		t.Errorf("Wanted ENR entry %s, received %v", want, resp["enr_eth2_entry"])
+	}
+}
+
+func TestForkHandler_BeforeChainStart(t *testing.T) {
+	s := &Service{}
+	rec := httptest.NewRecorder()
+	s.ForkHandler(rec, httptest.NewRequest(http.MethodGet, "/p2p/fork", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Wanted status %d, received %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
@@ -78,7 +78,7 @@ func (s *Service) AddConnectionHandler(reqFunc func(ctx context.Context, id peer
 					return
 				}
 				s.peers.Add(nil /* ENR */, remotePeer, conn.RemoteMultiaddr(), conn.Stat().Direction)
-				if len(s.peers.Active()) >= int(s.cfg.MaxPeers) {
+				if len(s.peers.Active()) >= int(s.MaxPeers()) && !s.gater.Allowed(remotePeer) {
 					log.WithField("reason", "at peer limit").Trace("Ignoring connection request")
 					if err := goodbyeFunc(context.Background(), remotePeer); err != nil {
 						log.WithError(err).Trace("Unable to send goodbye message to peer")
@@ -91,6 +91,14 @@ func (s *Service) AddConnectionHandler(reqFunc func(ctx context.Context, id peer
 					disconnectFromPeer()
 					return
 				}
+				if !s.gater.AllowConnection(conn.Stat().Direction, remotePeer, s.host.Network().Conns()) {
+					log.WithField("reason", "at directional peer limit").Trace("Ignoring connection request")
+					if err := goodbyeFunc(context.Background(), remotePeer); err != nil {
+						log.WithError(err).Trace("Unable to send goodbye message to peer")
+					}
+					disconnectFromPeer()
+					return
+				}
 				validPeerConnection := func() {
 					s.host.ConnManager().Protect(conn.RemotePeer(), "protocol")
 					s.peers.SetConnectionState(conn.RemotePeer(), peers.PeerConnected)
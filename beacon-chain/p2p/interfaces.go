@@ -3,10 +3,12 @@ package p2p
 import (
 	"context"
 
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/gogo/protobuf/proto"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -23,6 +25,7 @@ type P2P interface {
 	ConnectionHandler
 	PeersProvider
 	MetadataProvider
+	IdentityProvider
 }
 
 // Broadcaster broadcasts messages to peers over the p2p pubsub protocol.
@@ -56,7 +59,7 @@ type PeerManager interface {
 	Disconnect(peer.ID) error
 	PeerID() peer.ID
 	RefreshENR()
-	FindPeersWithSubnet(index uint64) (bool, error)
+	FindPeersWithSubnet(ctx context.Context, index uint64) (bool, error)
 	AddPingMethod(reqFunc func(ctx context.Context, id peer.ID) error)
 }
 
@@ -75,3 +78,10 @@ type MetadataProvider interface {
 	Metadata() *pb.MetaData
 	MetadataSeq() uint64
 }
+
+// IdentityProvider returns the identity of the local peer on the network.
+type IdentityProvider interface {
+	PeerID() peer.ID
+	ENR() *enode.Node
+	ListeningAddrs() []ma.Multiaddr
+}
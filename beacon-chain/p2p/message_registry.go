@@ -0,0 +1,139 @@
+package p2p
+
+import (
+	"math"
+	"sort"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// SchemaVersion is a version of the layout of the containers sent over gossip and req/resp. A
+// hard fork which changes the layout of containers introduces a new schema version.
+type SchemaVersion uint8
+
+const (
+	// Phase0Schema is the layout of the phase 0 containers.
+	Phase0Schema SchemaVersion = iota
+)
+
+// gossipTopicSchemas maps the schema versions to their gossip topics and message types.
+var gossipTopicSchemas = map[SchemaVersion]map[string]proto.Message{
+	Phase0Schema: GossipTopicMappings,
+}
+
+// rpcTopicSchemas maps the schema versions to their rpc methods and request types.
+var rpcTopicSchemas = map[SchemaVersion]map[string]interface{}{
+	Phase0Schema: RPCTopicMappings,
+}
+
+// forkSchemas maps the fork versions which change the layout of containers to the schema
+// version they introduce. A fork which is not listed keeps the schema of the fork before it,
+// starting with the phase 0 schema at genesis.
+var forkSchemas = map[[4]byte]SchemaVersion{}
+
+// RegisterRPCMessage maps the rpc method with the given protocol ID to its request type in the
+// schema version. It is meant to be called from init functions, for the rpc methods defined
+// outside of this package.
+func RegisterRPCMessage(version SchemaVersion, topic string, base interface{}) {
+	mappings, ok := rpcTopicSchemas[version]
+	if !ok {
+		mappings = make(map[string]interface{})
+		rpcTopicSchemas[version] = mappings
+	}
+	mappings[topic] = base
+}
+
+// MessageRegistry maps the fork digests of a chain to the schema version of the messages sent
+// under them, so that the message types of gossip topics and rpc methods are looked up by fork
+// digest instead of being decided at each call site.
+type MessageRegistry struct {
+	genesisValidatorsRoot []byte
+	schemas               map[[4]byte]SchemaVersion
+}
+
+// NewMessageRegistry creates the registry of the chain with the given genesis validators root,
+// from the genesis fork and the forks scheduled by the active config.
+func NewMessageRegistry(genesisValidatorsRoot []byte) (*MessageRegistry, error) {
+	if len(genesisValidatorsRoot) == 0 {
+		return nil, errors.New("genesis validators root is not set")
+	}
+	cfg := params.BeaconConfig()
+	forks := map[uint64][]byte{0: cfg.GenesisForkVersion}
+	for epoch, version := range cfg.ForkVersionSchedule {
+		forks[epoch] = version
+	}
+	if _, ok := forks[cfg.NextForkEpoch]; !ok && cfg.NextForkEpoch != math.MaxUint64 {
+		forks[cfg.NextForkEpoch] = cfg.NextForkVersion
+	}
+	epochs := make([]uint64, 0, len(forks))
+	for epoch := range forks {
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+
+	r := &MessageRegistry{
+		genesisValidatorsRoot: genesisValidatorsRoot,
+		schemas:               make(map[[4]byte]SchemaVersion, len(forks)),
+	}
+	schema := Phase0Schema
+	for _, epoch := range epochs {
+		version := forks[epoch]
+		if s, ok := forkSchemas[bytesutil.ToBytes4(version)]; ok {
+			schema = s
+		}
+		digest, err := helpers.ComputeForkDigest(version, genesisValidatorsRoot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not compute fork digest of fork version %#x", version)
+		}
+		r.schemas[digest] = schema
+	}
+	return r, nil
+}
+
+// GenesisValidatorsRoot returns the genesis validators root of the chain of the registry.
+func (r *MessageRegistry) GenesisValidatorsRoot() []byte {
+	return r.genesisValidatorsRoot
+}
+
+// SchemaVersion returns the schema version of the messages sent under the fork digest.
+func (r *MessageRegistry) SchemaVersion(digest [4]byte) (SchemaVersion, error) {
+	schema, ok := r.schemas[digest]
+	if !ok {
+		return 0, errors.Errorf("unknown fork digest %#x", digest)
+	}
+	return schema, nil
+}
+
+// GossipMessage returns the base message of the gossip topic format under the fork digest. The
+// base message is shared, so it must be cloned before decoding into it.
+func (r *MessageRegistry) GossipMessage(digest [4]byte, topicFormat string) (proto.Message, error) {
+	schema, err := r.SchemaVersion(digest)
+	if err != nil {
+		return nil, err
+	}
+	base, ok := gossipTopicSchemas[schema][topicFormat]
+	if !ok {
+		return nil, errors.Errorf("no message mapped for topic %s in schema version %d", topicFormat, schema)
+	}
+	return base, nil
+}
+
+// RPCMessage returns the base request of the rpc method with the given protocol ID under the
+// fork digest.
+func (r *MessageRegistry) RPCMessage(digest [4]byte, topic string) (interface{}, error) {
+	schema, err := r.SchemaVersion(digest)
+	if err != nil {
+		return nil, err
+	}
+	base, ok := rpcTopicSchemas[schema][topic]
+	if !ok {
+		return nil, errors.Errorf("no request mapped for rpc topic %s in schema version %d", topic, schema)
+	}
+	return base, nil
+}
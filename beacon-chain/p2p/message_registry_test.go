@@ -0,0 +1,115 @@
+package p2p
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	testpb "github.com/prysmaticlabs/prysm/proto/testing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestMessageRegistry_Phase0(t *testing.T) {
+	root := []byte{'r', 'o', 'o', 't'}
+	r, err := NewMessageRegistry(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := helpers.ComputeForkDigest(params.BeaconConfig().GenesisForkVersion, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := r.SchemaVersion(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema != Phase0Schema {
+		t.Errorf("Wanted schema version %d, received %d", Phase0Schema, schema)
+	}
+	for topic, want := range GossipTopicMappings {
+		base, err := r.GossipMessage(digest, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if base != want {
+			t.Errorf("Wanted %T for topic %s, received %T", want, topic, base)
+		}
+	}
+	for topic, want := range RPCTopicMappings {
+		base, err := r.RPCMessage(digest, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reflect.TypeOf(base) != reflect.TypeOf(want) {
+			t.Errorf("Wanted %T for rpc topic %s, received %T", want, topic, base)
+		}
+	}
+
+	if _, err := r.GossipMessage(digest, "/eth2/%x/unknown"); err == nil {
+		t.Error("Expected an error for an unmapped topic")
+	}
+	if _, err := r.SchemaVersion([4]byte{'b', 'a', 'd'}); err == nil || !strings.Contains(err.Error(), "unknown fork digest") {
+		t.Errorf("Expected an unknown fork digest error, received %v", err)
+	}
+	if _, err := NewMessageRegistry(nil); err == nil {
+		t.Error("Expected an error without a genesis validators root")
+	}
+}
+
+func TestMessageRegistry_ScheduledFork(t *testing.T) {
+	const nextSchema = Phase0Schema + 1
+	root := []byte{'r', 'o', 'o', 't'}
+	forkVersion := []byte{1, 0, 0, 0}
+	laterForkVersion := []byte{2, 0, 0, 0}
+
+	cfg := params.BeaconConfig()
+	defer params.OverrideBeaconConfig(cfg)
+	newCfg := *cfg
+	newCfg.ForkVersionSchedule = map[uint64][]byte{10: forkVersion, 20: laterForkVersion}
+	params.OverrideBeaconConfig(&newCfg)
+
+	topic := "/eth2/%x/beacon_block"
+	gossipTopicSchemas[nextSchema] = map[string]proto.Message{topic: &testpb.TestSimpleMessage{}}
+	forkSchemas[[4]byte{1, 0, 0, 0}] = nextSchema
+	defer func() {
+		delete(gossipTopicSchemas, nextSchema)
+		delete(forkSchemas, [4]byte{1, 0, 0, 0})
+	}()
+
+	r, err := NewMessageRegistry(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		version []byte
+		schema  SchemaVersion
+		message proto.Message
+	}{
+		{version: cfg.GenesisForkVersion, schema: Phase0Schema, message: GossipTopicMappings[topic]},
+		{version: forkVersion, schema: nextSchema, message: &testpb.TestSimpleMessage{}},
+		// A fork which does not change containers keeps the schema of the fork before it.
+		{version: laterForkVersion, schema: nextSchema, message: &testpb.TestSimpleMessage{}},
+	}
+	for _, tt := range tests {
+		digest, err := helpers.ComputeForkDigest(tt.version, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema, err := r.SchemaVersion(digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schema != tt.schema {
+			t.Errorf("Wanted schema version %d for fork version %#x, received %d", tt.schema, tt.version, schema)
+		}
+		base, err := r.GossipMessage(digest, topic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reflect.TypeOf(base) != reflect.TypeOf(tt.message) {
+			t.Errorf("Wanted %T for fork version %#x, received %T", tt.message, tt.version, base)
+		}
+	}
+}
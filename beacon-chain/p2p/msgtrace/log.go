@@ -0,0 +1,7 @@
+package msgtrace
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "p2p-message-tracer")
@@ -0,0 +1,11 @@
+package msgtrace
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var droppedFileMessages = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "p2p_traced_messages_file_dropped_total",
+	Help: "The number of traced p2p messages left out of the trace file, as the disk could not keep up.",
+})
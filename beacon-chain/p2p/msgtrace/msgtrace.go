@@ -0,0 +1,204 @@
+// Package msgtrace records the gossip and req/resp messages exchanged with peers, with their
+// topic, peer, size, decoded type and the outcome of their validation or handling, so protocol
+// issues between clients can be diagnosed without capturing packets and decoding them by hand.
+// The latest messages are kept in a ring buffer and may be appended to a file as JSON lines.
+package msgtrace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+// Direction of a message relative to this node.
+type Direction string
+
+const (
+	// Inbound messages are received from a peer.
+	Inbound Direction = "inbound"
+	// Outbound messages are sent to a peer, or published for gossip messages.
+	Outbound Direction = "outbound"
+)
+
+// Protocol a message is exchanged with.
+type Protocol string
+
+const (
+	// Gossip messages are exchanged over gossipsub.
+	Gossip Protocol = "gossip"
+	// RPC messages are req/resp requests.
+	RPC Protocol = "rpc"
+)
+
+// bufferSize is the number of messages kept by the tracer of the node.
+const bufferSize = 4096
+
+// fileQueueSize bounds the messages waiting to be written to the trace file. Messages are dropped
+// from the file rather than slowing down the network when the disk cannot keep up.
+const fileQueueSize = 1024
+
+// Message is a traced message.
+type Message struct {
+	Time      time.Time `json:"time"`
+	Direction Direction `json:"direction"`
+	Protocol  Protocol  `json:"protocol"`
+	Topic     string    `json:"topic"`
+	// Peer is the peer the message was received from or sent to, empty for published gossip.
+	Peer string `json:"peer,omitempty"`
+	// Size is the size of the encoded message in bytes.
+	Size int `json:"size"`
+	// Type is the Go type the message was decoded to or encoded from, empty if it was not decoded.
+	Type string `json:"type,omitempty"`
+	// Result is the outcome of the validation of gossip messages, accept, ignore or reject, or of
+	// the handling of req/resp messages, ok or the error.
+	Result string `json:"result"`
+}
+
+// Tracer keeps the latest messages in a ring buffer.
+type Tracer struct {
+	lock     sync.RWMutex
+	messages []*Message
+	next     int
+	full     bool
+	queue    chan *Message
+}
+
+// NewTracer creates a tracer keeping the given number of messages.
+func NewTracer(size int) *Tracer {
+	return &Tracer{messages: make([]*Message, size)}
+}
+
+// WriteTo appends the messages recorded from now on to the file as JSON lines.
+func (t *Tracer) WriteTo(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	t.lock.Lock()
+	t.queue = make(chan *Message, fileQueueSize)
+	queue := t.queue
+	t.lock.Unlock()
+	go writeMessages(f, queue)
+	return nil
+}
+
+// writeMessages writes the queued messages to the file, flushing whenever the queue is drained.
+func writeMessages(f *os.File, queue <-chan *Message) {
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for m := range queue {
+		if err := enc.Encode(m); err != nil {
+			log.WithError(err).Error("Could not write traced message, stopped writing the trace file")
+			return
+		}
+		if len(queue) > 0 {
+			continue
+		}
+		if err := w.Flush(); err != nil {
+			log.WithError(err).Error("Could not write traced messages, stopped writing the trace file")
+			return
+		}
+	}
+}
+
+// Record the message.
+func (t *Tracer) Record(m *Message) {
+	if m.Time.IsZero() {
+		m.Time = roughtime.Now()
+	}
+	t.lock.Lock()
+	t.messages[t.next] = m
+	t.next = (t.next + 1) % len(t.messages)
+	if t.next == 0 {
+		t.full = true
+	}
+	queue := t.queue
+	t.lock.Unlock()
+	if queue == nil {
+		return
+	}
+	select {
+	case queue <- m:
+	default:
+		droppedFileMessages.Inc()
+	}
+}
+
+// Messages returns the recorded messages from the oldest to the latest.
+func (t *Tracer) Messages() []*Message {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	var messages []*Message
+	if t.full {
+		messages = append(messages, t.messages[t.next:]...)
+	}
+	messages = append(messages, t.messages[:t.next]...)
+	copied := make([]*Message, len(messages))
+	for i, m := range messages {
+		c := *m
+		copied[i] = &c
+	}
+	return copied
+}
+
+var (
+	defaultTracer     *Tracer
+	defaultTracerOnce sync.Once
+)
+
+// Default returns the tracer of the node, or nil if message tracing is not enabled. The tracer
+// writes to the trace file of the node, if one is configured.
+func Default() *Tracer {
+	cfg := featureconfig.Get()
+	if !cfg.EnableP2PMessageTracing {
+		return nil
+	}
+	defaultTracerOnce.Do(func() {
+		defaultTracer = NewTracer(bufferSize)
+		if cfg.P2PMessageTraceFile == "" {
+			return
+		}
+		if err := defaultTracer.WriteTo(cfg.P2PMessageTraceFile); err != nil {
+			log.WithError(err).Error("Could not open the p2p message trace file")
+		}
+	})
+	return defaultTracer
+}
+
+// Record the message with the tracer of the node, if tracing is enabled. The type of the message
+// is recorded if it was decoded, msg being nil otherwise, and the peer if it is not empty.
+func Record(direction Direction, protocol Protocol, topic string, pid peer.ID, size int, msg interface{}, result string) {
+	t := Default()
+	if t == nil {
+		return
+	}
+	m := &Message{
+		Direction: direction,
+		Protocol:  protocol,
+		Topic:     topic,
+		Size:      size,
+		Result:    result,
+	}
+	if pid != "" {
+		m.Peer = pid.String()
+	}
+	if msg != nil {
+		m.Type = fmt.Sprintf("%T", msg)
+	}
+	t.Record(m)
+}
+
+// Result returns the result recorded for a handled message and its handling error.
+func Result(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
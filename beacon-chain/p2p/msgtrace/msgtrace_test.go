@@ -0,0 +1,77 @@
+package msgtrace
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestTracer_Messages(t *testing.T) {
+	tr := NewTracer(3)
+	if len(tr.Messages()) != 0 {
+		t.Fatal("Expected no messages")
+	}
+	for i := 1; i <= 4; i++ {
+		tr.Record(&Message{Topic: "topic", Size: i})
+	}
+	messages := tr.Messages()
+	if len(messages) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(messages))
+	}
+	for i, m := range messages {
+		if m.Size != i+2 {
+			t.Errorf("Expected message %d of size %d, got %d", i, i+2, m.Size)
+		}
+		if m.Time.IsZero() {
+			t.Error("Expected the time of the message to be set")
+		}
+	}
+	messages[0].Size = 100
+	if tr.Messages()[0].Size != 2 {
+		t.Error("Expected the returned messages to be copies")
+	}
+}
+
+func TestTracer_WriteTo(t *testing.T) {
+	path := filepath.Join(testutil.TempDir(), "p2p-trace.jsonl")
+	defer func() {
+		if err := os.Remove(path); err != nil {
+			t.Error(err)
+		}
+	}()
+	tr := NewTracer(8)
+	if err := tr.WriteTo(path); err != nil {
+		t.Fatal(err)
+	}
+	tr.Record(&Message{Direction: Inbound, Protocol: Gossip, Topic: "a", Result: "accept"})
+	tr.Record(&Message{Direction: Outbound, Protocol: RPC, Topic: "b", Result: "ok"})
+
+	var lines []*Message
+	for i := 0; i < 100 && len(lines) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = nil
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			m := &Message{}
+			if err := json.Unmarshal(scanner.Bytes(), m); err != nil {
+				t.Fatal(err)
+			}
+			lines = append(lines, m)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(lines) != 2 || lines[0].Topic != "a" || lines[1].Result != "ok" {
+		t.Errorf("Unexpected messages in trace file %v", lines)
+	}
+}
@@ -0,0 +1,117 @@
+package p2p
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/nat"
+	"github.com/sirupsen/logrus"
+)
+
+// natMappingName is the description of the port mappings on the router.
+const natMappingName = "prysm p2p"
+
+// natMappingLifetime is how long the router keeps the port mappings unless they are renewed.
+const natMappingLifetime = 20 * time.Minute
+
+// externalIPRefreshPeriod is how often the port mappings are renewed and the external IP of the
+// router is checked for changes.
+var externalIPRefreshPeriod = 5 * time.Minute
+
+// natInterface returns the mechanism mapping the ports of the node on the router, which is
+// nil when NAT traversal is disabled.
+func natInterface(cfg *Config) nat.Interface {
+	if cfg.DisableNAT {
+		return nil
+	}
+	if cfg.EnableUPnP {
+		return nat.UPnP()
+	}
+	// Detects whichever of UPnP and NAT-PMP the router supports.
+	return nat.Any()
+}
+
+// startNAT maps the tcp and discovery ports of the node on the router, so that peers can dial
+// the node behind a NAT, and advertises the external IP of the router in the ENR of the node
+// unless a host address is configured. The port mappings are removed when the service stops.
+func (s *Service) startNAT() {
+	if s.nat == nil {
+		return
+	}
+	// The router may take a few seconds to be discovered, which must not hold up the start.
+	go func() {
+		s.refreshNAT()
+		ticker := time.NewTicker(externalIPRefreshPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.refreshNAT()
+			case <-s.ctx.Done():
+				s.unmapPorts()
+				return
+			}
+		}
+	}()
+}
+
+// refreshNAT maps or renews the port mappings of the node. The external IP of the router is only
+// advertised once the ports are mapped, as peers cannot dial the node at that address otherwise.
+func (s *Service) refreshNAT() {
+	if !s.mapPorts() {
+		return
+	}
+	if s.cfg.HostAddress == "" {
+		s.updateExternalIP()
+	}
+}
+
+// natPorts returns the protocols and ports of the node to map on the router.
+func (s *Service) natPorts() map[string]int {
+	ports := map[string]int{"tcp": int(s.cfg.TCPPort)}
+	if s.dv5Listener != nil {
+		ports["udp"] = int(s.cfg.UDPPort)
+	}
+	return ports
+}
+
+// mapPorts maps the ports of the node on the router, returning whether the router accepted all
+// the mappings.
+func (s *Service) mapPorts() bool {
+	for protocol, port := range s.natPorts() {
+		if err := s.nat.AddMapping(protocol, port, port, natMappingName, natMappingLifetime); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{
+				"protocol": protocol,
+				"port":     port,
+				"nat":      s.nat.String(),
+			}).Debug("Could not map port on the router")
+			return false
+		}
+	}
+	return true
+}
+
+// unmapPorts removes the port mappings of the node from the router.
+func (s *Service) unmapPorts() {
+	for protocol, port := range s.natPorts() {
+		if err := s.nat.DeleteMapping(protocol, port, port); err != nil {
+			log.WithError(err).WithField("protocol", protocol).Debug("Could not remove port mapping from the router")
+		}
+	}
+}
+
+// updateExternalIP updates the ENR of the node once the external IP of the router changes.
+func (s *Service) updateExternalIP() {
+	ip, err := s.nat.ExternalIP()
+	if err != nil {
+		log.WithError(err).Debug("Could not detect external IP address")
+		return
+	}
+	if ip.Equal(s.externalIP) {
+		return
+	}
+	s.externalIP = ip
+	if s.dv5Listener != nil {
+		s.dv5Listener.LocalNode().SetStaticIP(ip)
+	}
+	log.WithField("ip", ip).WithField("nat", s.nat.String()).Info("Detected external IP address")
+}
@@ -0,0 +1,83 @@
+package p2p
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/nat"
+)
+
+func TestNATInterface(t *testing.T) {
+	if natInterface(&Config{DisableNAT: true}) != nil {
+		t.Error("Wanted no NAT traversal when disabled")
+	}
+	if natInterface(&Config{}) == nil {
+		t.Error("Wanted NAT traversal by default")
+	}
+}
+
+func TestUpdateExternalIP(t *testing.T) {
+	ipAddr, pkey := createAddrAndPrivKey(t)
+	s := &Service{
+		cfg:                   &Config{},
+		genesisTime:           time.Now(),
+		genesisValidatorsRoot: []byte{'A'},
+	}
+	listener := s.createListener(ipAddr, pkey)
+	defer listener.Close()
+	s.dv5Listener = listener
+	seq := listener.Self().Seq()
+
+	externalIP := net.ParseIP("8.8.8.8")
+	s.nat = nat.ExtIP(externalIP)
+	s.updateExternalIP()
+	if !listener.Self().IP().Equal(externalIP) {
+		t.Errorf("Wanted ENR ip %v, received %v", externalIP, listener.Self().IP())
+	}
+	if listener.Self().Seq() <= seq {
+		t.Error("ENR sequence number was not increased")
+	}
+
+	// The ENR is not updated while the external IP does not change.
+	seq = listener.Self().Seq()
+	s.updateExternalIP()
+	if listener.Self().Seq() != seq {
+		t.Errorf("Wanted ENR sequence number %d, received %d", seq, listener.Self().Seq())
+	}
+}
+
+// refusingNAT is a router with an external IP which refuses port mappings.
+type refusingNAT struct {
+	nat.Interface
+}
+
+func (refusingNAT) AddMapping(string, int, int, string, time.Duration) error {
+	return errors.New("mapping refused")
+}
+
+func TestRefreshNAT(t *testing.T) {
+	ipAddr, pkey := createAddrAndPrivKey(t)
+	s := &Service{
+		cfg:                   &Config{},
+		genesisTime:           time.Now(),
+		genesisValidatorsRoot: []byte{'A'},
+	}
+	listener := s.createListener(ipAddr, pkey)
+	defer listener.Close()
+	s.dv5Listener = listener
+
+	externalIP := net.ParseIP("8.8.8.8")
+	s.nat = refusingNAT{Interface: nat.ExtIP(externalIP)}
+	s.refreshNAT()
+	if s.externalIP != nil || listener.Self().IP().Equal(externalIP) {
+		t.Error("External IP advertised although the router refused the port mappings")
+	}
+
+	s.nat = nat.ExtIP(externalIP)
+	s.refreshNAT()
+	if !listener.Self().IP().Equal(externalIP) {
+		t.Errorf("Wanted ENR ip %v once the ports are mapped, received %v", externalIP, listener.Self().IP())
+	}
+}
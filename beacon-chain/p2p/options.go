@@ -35,9 +35,6 @@ func buildOptions(cfg *Config, ip net.IP, priKey *ecdsa.PrivateKey) []libp2p.Opt
 		// Enable NOISE for the beacon node
 		options = append(options, libp2p.Security(noise.ID, noise.New))
 	}
-	if cfg.EnableUPnP {
-		options = append(options, libp2p.NATPortMap()) //Allow to use UPnP
-	}
 	if cfg.RelayNodeAddr != "" {
 		options = append(options, libp2p.AddrsFactory(withRelayAddrs(cfg.RelayNodeAddr)))
 	}
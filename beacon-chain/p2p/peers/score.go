@@ -0,0 +1,147 @@
+package peers
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+const (
+	// badResponseWeight is the score lost for an invalid response to a request, like a block
+	// which does not match the request or does not decode.
+	badResponseWeight = 1.0
+	// requestTimeoutWeight is the score lost for a request which timed out. Timeouts weigh less
+	// than bad responses as they are also caused by honest but congested peers.
+	requestTimeoutWeight = 0.5
+	// gossipPenaltyWeight is the score lost for a gossip message rejected by validation.
+	gossipPenaltyWeight = 1.0
+	// banDuration is how long a peer is banned once its score falls to the ban threshold. It
+	// spans more than one decay, so a banned peer is not redialed right after the next decay.
+	banDuration = 2 * time.Hour
+)
+
+// Score returns the score of the peer, which starts at zero and decreases with every bad
+// response, request timeout and gossip penalty of the peer. Peers with a score at or below
+// the ban threshold of -maxBadResponses are banned.
+// This will error if the peer does not exist.
+func (p *Status) Score(pid peer.ID) (float64, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.score(), nil
+	}
+	return 0, ErrPeerUnknown
+}
+
+// IncrementRequestTimeouts increments the number of requests to the given remote peer which timed out.
+func (p *Status) IncrementRequestTimeouts(pid peer.ID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.fetch(pid)
+	status.requestTimeouts++
+	p.banIfBelowThreshold(status)
+}
+
+// RequestTimeouts obtains the number of requests to the given remote peer which timed out.
+// This will error if the peer does not exist.
+func (p *Status) RequestTimeouts(pid peer.ID) (int, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.requestTimeouts, nil
+	}
+	return -1, ErrPeerUnknown
+}
+
+// IncrementGossipPenalties increments the number of gossip messages from the given remote peer
+// which were rejected by validation.
+func (p *Status) IncrementGossipPenalties(pid peer.ID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.fetch(pid)
+	status.gossipPenalties++
+	p.banIfBelowThreshold(status)
+}
+
+// GossipPenalties obtains the number of gossip messages from the given remote peer which were
+// rejected by validation.
+// This will error if the peer does not exist.
+func (p *Status) GossipPenalties(pid peer.ID) (int, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.gossipPenalties, nil
+	}
+	return -1, ErrPeerUnknown
+}
+
+// BannedUntil returns the time until which the given remote peer is banned, which is the zero
+// time if the peer was never banned.
+// This will error if the peer does not exist.
+func (p *Status) BannedUntil(pid peer.ID) (time.Time, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.bannedUntil, nil
+	}
+	return time.Time{}, ErrPeerUnknown
+}
+
+// SetTrusted marks the peer as trusted. Trusted peers are still scored, but they are never
+// banned nor considered bad.
+func (p *Status) SetTrusted(pid peer.ID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.fetch(pid)
+	status.trusted = true
+	status.bannedUntil = time.Time{}
+}
+
+// IsTrusted states if the peer was marked as trusted.
+func (p *Status) IsTrusted(pid peer.ID) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.trusted
+	}
+	return false
+}
+
+// banThreshold is the score at or below which a peer is banned.
+func (p *Status) banThreshold() float64 {
+	return -float64(p.maxBadResponses)
+}
+
+// banIfBelowThreshold bans the peer for the ban duration once its score falls to the ban
+// threshold. The ban holds even if the penalties of the peer decay in the meantime.
+// This requires the lock to be held.
+func (p *Status) banIfBelowThreshold(status *peerStatus) {
+	if !status.trusted && status.score() <= p.banThreshold() {
+		status.bannedUntil = roughtime.Now().Add(banDuration)
+	}
+}
+
+// isBad returns whether the peer is untrusted, and banned or its score is at or below the ban
+// threshold.
+// This requires the lock to be held.
+func (p *Status) isBad(status *peerStatus) bool {
+	if status.trusted {
+		return false
+	}
+	return status.score() <= p.banThreshold() || roughtime.Now().Before(status.bannedUntil)
+}
+
+func (s *peerStatus) score() float64 {
+	return -(float64(s.badResponses)*badResponseWeight +
+		float64(s.requestTimeouts)*requestTimeoutWeight +
+		float64(s.gossipPenalties)*gossipPenaltyWeight)
+}
@@ -0,0 +1,114 @@
+package peers_test
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+func TestScore(t *testing.T) {
+	maxBadResponses := 2
+	p := peers.NewStatus(maxBadResponses)
+	id := addPeer(t, p, peers.PeerConnected)
+
+	p.IncrementBadResponses(id)
+	p.IncrementRequestTimeouts(id)
+	score, err := p.Score(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != -1.5 {
+		t.Errorf("Unexpected score: expected -1.5, received %v", score)
+	}
+	if p.IsBad(id) {
+		t.Error("Peer above the ban threshold marked as bad")
+	}
+	if until, err := p.BannedUntil(id); err != nil || !until.IsZero() {
+		t.Errorf("Unexpected ban of peer above the ban threshold: %v, %v", until, err)
+	}
+
+	p.IncrementGossipPenalties(id)
+	score, err = p.Score(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != -2.5 {
+		t.Errorf("Unexpected score: expected -2.5, received %v", score)
+	}
+	if !p.IsBad(id) {
+		t.Error("Peer below the ban threshold not marked as bad")
+	}
+	if bad := p.Bad(); len(bad) != 1 || bad[0] != id {
+		t.Errorf("Unexpected bad peers %v", bad)
+	}
+	until, err := p.BannedUntil(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !until.After(roughtime.Now()) {
+		t.Errorf("Expected peer to be banned, banned until %v", until)
+	}
+
+	if _, err := p.Score("unknown"); err != peers.ErrPeerUnknown {
+		t.Errorf("Unexpected error for unknown peer: %v", err)
+	}
+}
+
+func TestScore_BanOutlastsDecay(t *testing.T) {
+	maxBadResponses := 2
+	p := peers.NewStatus(maxBadResponses)
+	id := addPeer(t, p, peers.PeerConnected)
+
+	p.IncrementGossipPenalties(id)
+	p.IncrementRequestTimeouts(id)
+	p.IncrementRequestTimeouts(id)
+	if !p.IsBad(id) {
+		t.Fatal("Peer below the ban threshold not marked as bad")
+	}
+
+	p.Decay()
+	penalties, err := p.GossipPenalties(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeouts, err := p.RequestTimeouts(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if penalties != 0 || timeouts != 1 {
+		t.Errorf("Unexpected decayed penalties %d and timeouts %d", penalties, timeouts)
+	}
+	if !p.IsBad(id) {
+		t.Error("Banned peer no longer bad after decay")
+	}
+}
+
+func TestScore_TrustedPeerNotBanned(t *testing.T) {
+	maxBadResponses := 2
+	p := peers.NewStatus(maxBadResponses)
+	id := addPeer(t, p, peers.PeerConnected)
+	p.SetTrusted(id)
+	if !p.IsTrusted(id) {
+		t.Fatal("Peer not marked as trusted")
+	}
+
+	for i := 0; i < 2*maxBadResponses; i++ {
+		p.IncrementBadResponses(id)
+	}
+	if score, err := p.Score(id); err != nil || score != -4 {
+		t.Errorf("Unexpected score of trusted peer: %v, %v", score, err)
+	}
+	if p.IsBad(id) {
+		t.Error("Trusted peer marked as bad")
+	}
+	if len(p.Bad()) != 0 {
+		t.Errorf("Unexpected bad peers %v", p.Bad())
+	}
+	if until, err := p.BannedUntil(id); err != nil || !until.IsZero() {
+		t.Errorf("Unexpected ban of trusted peer: %v, %v", until, err)
+	}
+	if p.IsTrusted("unknown") {
+		t.Error("Unknown peer marked as trusted")
+	}
+}
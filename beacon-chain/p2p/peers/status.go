@@ -73,6 +73,10 @@ type peerStatus struct {
 	metaData              *pb.MetaData
 	chainStateLastUpdated time.Time
 	badResponses          int
+	requestTimeouts       int
+	gossipPenalties       int
+	bannedUntil           time.Time
+	trusted               bool
 }
 
 // NewStatus creates a new status entity.
@@ -280,6 +284,7 @@ func (p *Status) IncrementBadResponses(pid peer.ID) {
 
 	status := p.fetch(pid)
 	status.badResponses++
+	p.banIfBelowThreshold(status)
 }
 
 // BadResponses obtains the number of bad responses we have received from the given remote peer.
@@ -294,14 +299,15 @@ func (p *Status) BadResponses(pid peer.ID) (int, error) {
 	return -1, ErrPeerUnknown
 }
 
-// IsBad states if the peer is to be considered bad.
+// IsBad states if the peer is to be considered bad, which is the case while it is banned or its
+// score is at or below the ban threshold.
 // If the peer is unknown this will return `false`, which makes using this function easier than returning an error.
 func (p *Status) IsBad(pid peer.ID) bool {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
 	if status, ok := p.status[pid]; ok {
-		return status.badResponses >= p.maxBadResponses
+		return p.isBad(status)
 	}
 	return false
 }
@@ -390,7 +396,7 @@ func (p *Status) Bad() []peer.ID {
 	defer p.lock.RUnlock()
 	peers := make([]peer.ID, 0)
 	for pid, status := range p.status {
-		if status.badResponses >= p.maxBadResponses {
+		if p.isBad(status) {
 			peers = append(peers, pid)
 		}
 	}
@@ -408,7 +414,8 @@ func (p *Status) All() []peer.ID {
 	return pids
 }
 
-// Decay reduces the bad responses of all peers, giving reformed peers a chance to join the network.
+// Decay reduces the bad responses, request timeouts and gossip penalties of all peers, giving reformed peers a chance
+// to join the network once their ban is over.
 // This can be run periodically, although note that each time it runs it does give all bad peers another chance as well to clog up
 // the network with bad responses, so should not be run too frequently; once an hour would be reasonable.
 func (p *Status) Decay() {
@@ -418,6 +425,12 @@ func (p *Status) Decay() {
 		if status.badResponses > 0 {
 			status.badResponses--
 		}
+		if status.requestTimeouts > 0 {
+			status.requestTimeouts--
+		}
+		if status.gossipPenalties > 0 {
+			status.gossipPenalties--
+		}
 	}
 }
 
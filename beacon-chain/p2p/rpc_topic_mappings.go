@@ -1,5 +1,9 @@
 package p2p
 
+import (
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
 const (
 	// RPCStatusTopic defines the topic for the status rpc method.
 	RPCStatusTopic = "/eth2/beacon_chain/req/status/1"
@@ -13,4 +17,20 @@ const (
 	RPCPingTopic = "/eth2/beacon_chain/req/ping/1"
 	// RPCMetaDataTopic defines the topic for the metadata rpc method.
 	RPCMetaDataTopic = "/eth2/beacon_chain/req/metadata/1"
+	// RPCStateSnapshotTopic defines the topic for the finalized state snapshot rpc method. It is
+	// not part of the eth2 networking spec, so only Prysm peers serve it.
+	RPCStateSnapshotTopic = "/prysm/beacon_chain/req/state_snapshot/1"
 )
+
+// RPCTopicMappings map the protocol ID of the rpc methods to the type of their request, which
+// is used to initialize new requests for decoding. Requests are either pointers or a slice of
+// roots. Protocols defined outside of this package, such as the Prysm-only ones, are added with
+// RegisterRPCMessage.
+var RPCTopicMappings = map[string]interface{}{
+	RPCStatusTopic:        &pb.Status{},
+	RPCGoodByeTopic:       new(uint64),
+	RPCBlocksByRangeTopic: &pb.BeaconBlocksByRangeRequest{},
+	RPCBlocksByRootTopic:  [][32]byte{},
+	RPCPingTopic:          new(uint64),
+	RPCMetaDataTopic:      new(interface{}),
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/msgtrace"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
@@ -43,7 +44,9 @@ func (s *Service) Send(ctx context.Context, message interface{}, baseTopic strin
 		return stream, nil
 	}
 
-	if _, err := s.Encoding().EncodeWithLength(stream, message); err != nil {
+	size, err := s.Encoding().EncodeWithLength(stream, message)
+	msgtrace.Record(msgtrace.Outbound, msgtrace.RPC, topic, pid, size, message, msgtrace.Result(err))
+	if err != nil {
 		traceutil.AnnotateError(span, err)
 		return nil, err
 	}
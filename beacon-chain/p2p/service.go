@@ -8,13 +8,17 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/gogo/protobuf/proto"
 	ds "github.com/ipfs/go-datastore"
 	dsync "github.com/ipfs/go-datastore/sync"
@@ -33,6 +37,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/connmgr"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -69,12 +74,17 @@ const (
 
 // Service for managing peer to peer (p2p) networking.
 type Service struct {
+	// maxPeers is accessed atomically and kept first for 64-bit alignment.
+	maxPeers              uint64
 	started               bool
 	isPreGenesis          bool
 	pingMethod            func(ctx context.Context, id peer.ID) error
 	cancel                context.CancelFunc
 	cfg                   *Config
 	peers                 *peers.Status
+	gater                 *connmgr.Gater
+	nat                   nat.Interface
+	externalIP            net.IP
 	dht                   *kaddht.IpfsDHT
 	privKey               *ecdsa.PrivateKey
 	exclusionList         *ristretto.Cache
@@ -87,6 +97,8 @@ type Service struct {
 	host                  host.Host
 	genesisTime           time.Time
 	genesisValidatorsRoot []byte
+	subnetLookupLock      sync.Mutex
+	subnetLookups         map[uint64]bool
 }
 
 // NewService initializes a new p2p service compatible with shared.Service interface. No
@@ -111,6 +123,9 @@ func NewService(cfg *Config) (*Service, error) {
 		cfg:           cfg,
 		exclusionList: cache,
 		isPreGenesis:  true,
+		maxPeers:      uint64(cfg.MaxPeers),
+		nat:           natInterface(cfg),
+		subnetLookups: make(map[uint64]bool),
 	}
 
 	dv5Nodes, kadDHTNodes := parseBootStrapAddrs(s.cfg.BootstrapNodeAddr)
@@ -130,6 +145,13 @@ func NewService(cfg *Config) (*Service, error) {
 		return nil, err
 	}
 
+	s.peers = peers.NewStatus(maxBadResponses)
+	s.gater = connmgr.NewGater(int(s.cfg.MaxInboundPeers), int(s.cfg.MaxOutboundPeers), s.peers.IsBad)
+	if err := s.allowPeers(); err != nil {
+		log.WithError(err).Error("Failed to parse static and trusted peers")
+		return nil, err
+	}
+
 	opts := buildOptions(s.cfg, ipAddr, s.privKey)
 	h, err := libp2p.New(s.ctx, opts...)
 	if err != nil {
@@ -185,11 +207,38 @@ func NewService(cfg *Config) (*Service, error) {
 	}
 	s.pubsub = gs
 
-	s.peers = peers.NewStatus(maxBadResponses)
-
 	return s, nil
 }
 
+// allowPeers exempts the static and trusted peers from the peer limits, and the trusted peers
+// from bans as well.
+func (s *Service) allowPeers() error {
+	staticAddrs, err := peersFromStringAddrs(s.cfg.StaticPeers)
+	if err != nil {
+		return errors.Wrap(err, "could not parse static peers")
+	}
+	staticPeers, err := peer.AddrInfosFromP2pAddrs(staticAddrs...)
+	if err != nil {
+		return errors.Wrap(err, "could not parse static peers")
+	}
+	for _, info := range staticPeers {
+		s.gater.Allow(info.ID)
+	}
+	trustedAddrs, err := peersFromStringAddrs(s.cfg.TrustedPeers)
+	if err != nil {
+		return errors.Wrap(err, "could not parse trusted peers")
+	}
+	trustedPeers, err := peer.AddrInfosFromP2pAddrs(trustedAddrs...)
+	if err != nil {
+		return errors.Wrap(err, "could not parse trusted peers")
+	}
+	for _, info := range trustedPeers {
+		s.gater.Allow(info.ID)
+		s.peers.SetTrusted(info.ID)
+	}
+	return nil
+}
+
 // Start the p2p service.
 func (s *Service) Start() {
 	if s.started {
@@ -213,6 +262,7 @@ func (s *Service) Start() {
 			log.WithError(err).Errorf("Could not create peer")
 		}
 		s.host.ConnManager().Protect(peer.ID, "relay")
+		s.gater.Allow(peer.ID)
 	}
 
 	if !s.cfg.NoDiscovery && !s.cfg.DisableDiscv5 {
@@ -254,6 +304,7 @@ func (s *Service) Start() {
 				log.WithError(err).Errorf("Could not create peer")
 			}
 			s.host.ConnManager().Protect(peer.ID, "bootnode")
+			s.gater.Allow(peer.ID)
 		}
 		bcfg := kaddht.DefaultBootstrapConfig
 		bcfg.Period = 30 * time.Second
@@ -269,9 +320,21 @@ func (s *Service) Start() {
 		if err != nil {
 			log.Errorf("Could not connect to static peer: %v", err)
 		}
+		// Static peers are redialed along with the relay and bootnodes whenever disconnected.
+		for _, addr := range addrs {
+			peersToWatch = append(peersToWatch, addr.String())
+			info, err := peer.AddrInfoFromP2pAddr(addr)
+			if err != nil {
+				log.WithError(err).Errorf("Could not create peer")
+				continue
+			}
+			s.host.ConnManager().Protect(info.ID, "static")
+		}
 		s.connectWithAllPeers(addrs)
 	}
 
+	s.startNAT()
+
 	// Periodic functions.
 	runutil.RunEvery(s.ctx, 5*time.Second, func() {
 		ensurePeerConnections(s.ctx, s.host, peersToWatch...)
@@ -305,6 +368,12 @@ func (s *Service) Stop() error {
 	if s.dv5Listener != nil {
 		s.dv5Listener.Close()
 	}
+	// Closing the host closes all peer connections, after the sync service said goodbye.
+	if s.host != nil {
+		if err := s.host.Close(); err != nil {
+			return errors.Wrap(err, "could not close libp2p host")
+		}
+	}
 	return nil
 }
 
@@ -330,15 +399,11 @@ func (s *Service) Started() bool {
 
 // Encoding returns the configured networking encoding.
 func (s *Service) Encoding() encoder.NetworkEncoding {
-	encoding := s.cfg.Encoding
-	switch encoding {
-	case encoder.SSZ:
-		return &encoder.SszNetworkEncoder{}
-	case encoder.SSZSnappy:
-		return &encoder.SszNetworkEncoder{UseSnappyCompression: true}
-	default:
+	e, err := encoder.ByName(s.cfg.Encoding)
+	if err != nil {
 		panic("Invalid Network Encoding Flag Provided")
 	}
+	return e
 }
 
 // PubSub returns the p2p pubsub framework.
@@ -357,6 +422,19 @@ func (s *Service) PeerID() peer.ID {
 	return s.host.ID()
 }
 
+// ENR returns the local peer as advertised by its node record, nil when discovery is disabled.
+func (s *Service) ENR() *enode.Node {
+	if s.dv5Listener == nil {
+		return nil
+	}
+	return s.dv5Listener.Self()
+}
+
+// ListeningAddrs returns the addresses the local peer listens on for libp2p connections.
+func (s *Service) ListeningAddrs() []ma.Multiaddr {
+	return s.host.Addrs()
+}
+
 // Disconnect from a peer.
 func (s *Service) Disconnect(pid peer.ID) error {
 	return s.host.Network().ClosePeer(pid)
@@ -385,11 +463,13 @@ func (s *Service) MetadataSeq() uint64 {
 // RefreshENR uses an epoch to refresh the enr entry for our node
 // with the tracked committee id's for the epoch, allowing our node
 // to be dynamically discoverable by others given our tracked committee id's.
+// The fork entry is refreshed as well, to follow fork transitions.
 func (s *Service) RefreshENR() {
 	// return early if discv5 isnt running
 	if s.dv5Listener == nil {
 		return
 	}
+	s.refreshForkEntry()
 	bitV := bitfield.NewBitvector64()
 	committees := cache.CommitteeIDs.GetAllCommittees()
 	for _, idx := range committees {
@@ -409,63 +489,6 @@ func (s *Service) RefreshENR() {
 	s.pingPeers()
 }
 
-// FindPeersWithSubnet performs a network search for peers
-// subscribed to a particular subnet. Then we try to connect
-// with those peers.
-func (s *Service) FindPeersWithSubnet(index uint64) (bool, error) {
-	if s.dv5Listener == nil {
-		// return if discovery isn't set
-		return false, nil
-	}
-	iterator := s.dv5Listener.RandomNodes()
-	nodes := enode.ReadNodes(iterator, lookupLimit)
-	exists := false
-	for _, node := range nodes {
-		if node.IP() == nil {
-			continue
-		}
-		// do not look for nodes with no tcp port set
-		if err := node.Record().Load(enr.WithEntry("tcp", new(enr.TCP))); err != nil {
-			if !enr.IsNotFound(err) {
-				log.WithError(err).Debug("Could not retrieve tcp port")
-			}
-			continue
-		}
-		subnets, err := retrieveAttSubnets(node.Record())
-		if err != nil {
-			log.Debugf("could not retrieve subnets: %v", err)
-			continue
-		}
-		for _, comIdx := range subnets {
-			if comIdx == index {
-				multiAddr, err := convertToSingleMultiAddr(node)
-				if err != nil {
-					return false, err
-				}
-				info, err := peer.AddrInfoFromP2pAddr(multiAddr)
-				if err != nil {
-					return false, err
-				}
-				if s.peers.IsActive(info.ID) {
-					exists = true
-					continue
-				}
-				if s.host.Network().Connectedness(info.ID) == network.Connected {
-					exists = true
-					continue
-				}
-				s.peers.Add(node.Record(), info.ID, multiAddr, network.DirUnknown)
-				if err := s.connectWithPeer(*info); err != nil {
-					log.WithError(err).Tracef("Could not connect with peer %s", info.String())
-					continue
-				}
-				exists = true
-			}
-		}
-	}
-	return exists, nil
-}
-
 // AddPingMethod adds the metadata ping rpc method to the p2p service, so that it can
 // be used to refresh ENR.
 func (s *Service) AddPingMethod(reqFunc func(ctx context.Context, id peer.ID) error) {
@@ -539,8 +562,24 @@ func (s *Service) connectWithAllPeers(multiAddrs []ma.Multiaddr) {
 	}
 }
 
+// MaxPeers returns the current limit of active peers.
+func (s *Service) MaxPeers() uint {
+	return uint(atomic.LoadUint64(&s.maxPeers))
+}
+
+// SetMaxPeers changes the limit of active peers for new connections. The connection manager
+// keeps trimming connections above the limit the service started with, so the limit cannot
+// be raised above it without a restart.
+func (s *Service) SetMaxPeers(n uint) error {
+	if n > s.cfg.MaxPeers {
+		return fmt.Errorf("peer limit %d exceeds the startup limit %d, restart to raise it", n, s.cfg.MaxPeers)
+	}
+	atomic.StoreUint64(&s.maxPeers, uint64(n))
+	return nil
+}
+
 func (s *Service) connectWithPeer(info peer.AddrInfo) error {
-	if len(s.Peers().Active()) >= int(s.cfg.MaxPeers) {
+	if len(s.Peers().Active()) >= int(s.MaxPeers()) && !s.gater.Allowed(info.ID) {
 		log.WithFields(logrus.Fields{"peer": info.ID.String(),
 			"reason": "at peer limit"}).Trace("Not dialing peer")
 		return nil
@@ -551,6 +590,11 @@ func (s *Service) connectWithPeer(info peer.AddrInfo) error {
 	if s.Peers().IsBad(info.ID) {
 		return nil
 	}
+	if !s.gater.AllowDial(info.ID, s.host.Network().Conns()) {
+		log.WithFields(logrus.Fields{"peer": info.ID.String(),
+			"reason": "at outbound peer limit"}).Trace("Not dialing peer")
+		return nil
+	}
 	if err := s.host.Connect(s.ctx, info); err != nil {
 		s.Peers().IncrementBadResponses(info.ID)
 		return err
@@ -292,3 +292,19 @@ func TestPeer_Disconnect(t *testing.T) {
 		t.Fatalf("Number of connections is %d when it was supposed to be %d", len(s.host.Network().Conns()), 0)
 	}
 }
+
+func TestService_SetMaxPeers(t *testing.T) {
+	s := &Service{cfg: &Config{MaxPeers: 30}, maxPeers: 30}
+	if err := s.SetMaxPeers(20); err != nil {
+		t.Fatal(err)
+	}
+	if s.MaxPeers() != 20 {
+		t.Errorf("Wanted peer limit 20, received %d", s.MaxPeers())
+	}
+	if err := s.SetMaxPeers(40); err == nil {
+		t.Error("Expected error when raising the limit above the startup limit")
+	}
+	if s.MaxPeers() != 20 {
+		t.Errorf("Wanted peer limit to stay 20, received %d", s.MaxPeers())
+	}
+}
@@ -1,8 +1,12 @@
 package p2p
 
 import (
+	"context"
+
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
@@ -11,6 +15,100 @@ var attestationSubnetCount = params.BeaconNetworkConfig().AttestationSubnetCount
 
 var attSubnetEnrKey = params.BeaconNetworkConfig().AttSubnetKey
 
+// minimumPeersInSubnet is the number of peers subscribed to a subnet a lookup tries to be
+// connected to, so messages on the subnet propagate even if some peers leave.
+const minimumPeersInSubnet = 4
+
+// FindPeersWithSubnet performs a network search for peers subscribed to a particular subnet,
+// querying discovery for nodes advertising the subnet in their attnets bitfield and dialing
+// them. The search stops once enough peers of the subnet are connected or the context is done.
+// It returns whether any peer subscribed to the subnet is connected.
+func (s *Service) FindPeersWithSubnet(ctx context.Context, index uint64) (bool, error) {
+	if s.dv5Listener == nil {
+		// return if discovery isn't set
+		return false, nil
+	}
+	// Only one search runs per subnet, the others would dial the same nodes.
+	if !s.startSubnetLookup(index) {
+		return len(s.peers.SubscribedToSubnet(index)) > 0, nil
+	}
+	defer s.endSubnetLookup(index)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	iterator := enode.Filter(s.dv5Listener.RandomNodes(), s.filterPeerForSubnet(index))
+	// Closing the iterator unblocks the discovery queries once the context is done.
+	go func() {
+		<-ctx.Done()
+		iterator.Close()
+	}()
+
+	connected := len(s.peers.SubscribedToSubnet(index))
+	for connected < minimumPeersInSubnet && iterator.Next() {
+		node := iterator.Node()
+		multiAddr, err := convertToSingleMultiAddr(node)
+		if err != nil {
+			log.WithError(err).Debug("Could not convert to multiAddr")
+			continue
+		}
+		info, err := peer.AddrInfoFromP2pAddr(multiAddr)
+		if err != nil {
+			log.WithError(err).Debug("Could not get peer id")
+			continue
+		}
+		if s.peers.IsActive(info.ID) || s.host.Network().Connectedness(info.ID) == network.Connected {
+			connected++
+			continue
+		}
+		s.peers.Add(node.Record(), info.ID, multiAddr, network.DirUnknown)
+		if err := s.connectWithPeer(*info); err != nil {
+			log.WithError(err).Tracef("Could not connect with peer %s", info.String())
+			continue
+		}
+		connected++
+	}
+	return connected > 0, nil
+}
+
+// filterPeerForSubnet returns a filter of the discovered nodes which can be dialed and
+// advertise the given subnet.
+func (s *Service) filterPeerForSubnet(index uint64) func(node *enode.Node) bool {
+	return func(node *enode.Node) bool {
+		if node.IP() == nil {
+			return false
+		}
+		// do not look for nodes with no tcp port set
+		if err := node.Record().Load(enr.WithEntry("tcp", new(enr.TCP))); err != nil {
+			if !enr.IsNotFound(err) {
+				log.WithError(err).Debug("Could not retrieve tcp port")
+			}
+			return false
+		}
+		bitV, err := retrieveBitvector(node.Record())
+		if err != nil {
+			log.Debugf("could not retrieve subnets: %v", err)
+			return false
+		}
+		return index < attestationSubnetCount && bitV.BitAt(index)
+	}
+}
+
+func (s *Service) startSubnetLookup(index uint64) bool {
+	s.subnetLookupLock.Lock()
+	defer s.subnetLookupLock.Unlock()
+	if s.subnetLookups[index] {
+		return false
+	}
+	s.subnetLookups[index] = true
+	return true
+}
+
+func (s *Service) endSubnetLookup(index uint64) {
+	s.subnetLookupLock.Lock()
+	defer s.subnetLookupLock.Unlock()
+	delete(s.subnetLookups, index)
+}
+
 func intializeAttSubnets(node *enode.LocalNode) *enode.LocalNode {
 	bitV := bitfield.NewBitvector64()
 	entry := enr.WithEntry(attSubnetEnrKey, bitV.Bytes())
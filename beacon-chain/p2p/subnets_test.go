@@ -1,6 +1,7 @@
 package p2p
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -102,16 +103,23 @@ func TestStartDiscV5_DiscoverPeersWithSubnets(t *testing.T) {
 	// Wait for the nodes to have their local routing tables to be populated with the other nodes
 	time.Sleep(6 * discoveryWaitTime)
 
+	// Each lookup runs until enough subnet peers are connected or its deadline passes.
+	findPeers := func(index uint64) (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		return s.FindPeersWithSubnet(ctx, index)
+	}
+
 	// look up 3 different subnets
-	exists, err := s.FindPeersWithSubnet(1)
+	exists, err := findPeers(1)
 	if err != nil {
 		t.Fatal(err)
 	}
-	exists2, err := s.FindPeersWithSubnet(2)
+	exists2, err := findPeers(2)
 	if err != nil {
 		t.Fatal(err)
 	}
-	exists3, err := s.FindPeersWithSubnet(3)
+	exists3, err := findPeers(3)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -128,7 +136,7 @@ func TestStartDiscV5_DiscoverPeersWithSubnets(t *testing.T) {
 	testService.RefreshENR()
 	time.Sleep(2 * time.Second)
 
-	exists, err = s.FindPeersWithSubnet(2)
+	exists, err = findPeers(2)
 	if err != nil {
 		t.Fatal(err)
 	}
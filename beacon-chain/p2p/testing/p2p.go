@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/p2p/enr"
 	"github.com/gogo/protobuf/proto"
 	bhost "github.com/libp2p/go-libp2p-blankhost"
@@ -19,6 +20,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/protocol"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	swarmt "github.com/libp2p/go-libp2p-swarm/testing"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -35,6 +37,7 @@ type TestP2P struct {
 	Digest          [4]byte
 	peers           *peers.Status
 	LocalMetadata   *pb.MetaData
+	LocalENR        *enode.Node
 }
 
 // NewTestP2P initializes a new p2p test service.
@@ -240,7 +243,7 @@ func (p *TestP2P) Peers() *peers.Status {
 }
 
 // FindPeersWithSubnet mocks the p2p func.
-func (p *TestP2P) FindPeersWithSubnet(index uint64) (bool, error) {
+func (p *TestP2P) FindPeersWithSubnet(ctx context.Context, index uint64) (bool, error) {
 	return false, nil
 }
 
@@ -264,6 +267,16 @@ func (p *TestP2P) MetadataSeq() uint64 {
 	return p.LocalMetadata.SeqNumber
 }
 
+// ENR mocks the node record of the peer.
+func (p *TestP2P) ENR() *enode.Node {
+	return p.LocalENR
+}
+
+// ListeningAddrs returns the addresses of the test host.
+func (p *TestP2P) ListeningAddrs() []ma.Multiaddr {
+	return p.Host.Addrs()
+}
+
 // AddPingMethod mocks the p2p func.
 func (p *TestP2P) AddPingMethod(reqFunc func(ctx context.Context, id peer.ID) error) {
 	// no-op
@@ -70,23 +70,40 @@ func (s *Service) BlockTimeByHeight(ctx context.Context, height *big.Int) (uint6
 }
 
 // BlockNumberByTimestamp returns the most recent block number up to a given timestamp.
-// This is a naive implementation that will use O(ETH1_FOLLOW_DISTANCE) calls to cache
-// or ETH1. This is called for multiple times but only changes every
-// SlotsPerEth1VotingPeriod (1024 slots) so the whole method should be cached.
+// This is called for every proposal with the start time of the eth1 voting period, so the
+// result of the latest scan is memoized: it is returned as is once a more recent block is known,
+// otherwise only the blocks which arrived since the previous scan are scanned.
 func (s *Service) BlockNumberByTimestamp(ctx context.Context, time uint64) (*big.Int, error) {
 	ctx, span := trace.StartSpan(ctx, "beacon-chain.web3service.BlockByTimestamp")
 	defer span.End()
 
+	cached := s.cachedVotingBlock(time)
+	if cached != nil && cached.final() {
+		votingBlockCacheHit.Inc()
+		return big.NewInt(0).Set(cached.number), nil
+	}
+	votingBlockCacheMiss.Inc()
+
 	head, err := s.blockFetcher.BlockByNumber(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
+	if cached != nil && head.Number().Cmp(cached.scanned) < 0 {
+		// The eth1 chain went back since the previous scan, scan it again.
+		cached = nil
+	}
 
 	for bn := head.Number(); ; bn = big.NewInt(0).Sub(bn, big.NewInt(1)) {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		}
 
+		if cached != nil && bn.Cmp(cached.scanned) <= 0 {
+			// The blocks up to the previous scanned head do not change the voting block.
+			s.cacheVotingBlock(&votingBlock{time: time, number: cached.number, scanned: head.Number()})
+			return big.NewInt(0).Set(cached.number), nil
+		}
+
 		exists, info, err := s.blockCache.BlockInfoByHeight(bn)
 		if err != nil {
 			return nil, err
@@ -104,7 +121,8 @@ func (s *Service) BlockNumberByTimestamp(ctx context.Context, time uint64) (*big
 		}
 
 		if info.Time <= time {
-			return info.Number, nil
+			s.cacheVotingBlock(&votingBlock{time: time, number: info.Number, scanned: head.Number()})
+			return big.NewInt(0).Set(info.Number), nil
 		}
 	}
 }
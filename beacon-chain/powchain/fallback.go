@@ -0,0 +1,94 @@
+package powchain
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxEndpointScore is the health score of an eth1 endpoint without recent failures.
+	maxEndpointScore = 10
+	// endpointFailurePenalty lowers the health score of an eth1 endpoint on every failure, so
+	// that an endpoint failing repeatedly is fallen back from once another endpoint is healthier.
+	endpointFailurePenalty = 5
+)
+
+// eth1HeadStallTimeout is the age of the latest eth1 block after which an endpoint is considered
+// stalled. Blocks have been mined in under 5 minutes since the beginning of mainnet.
+var eth1HeadStallTimeout = 5 * time.Minute
+
+// eth1Endpoint is an eth1 endpoint along with its health score, which is lowered by errors and
+// stalls and restored by successful requests.
+type eth1Endpoint struct {
+	url   string
+	score int
+}
+
+func newEth1Endpoints(urls []string) []*eth1Endpoint {
+	endpoints := make([]*eth1Endpoint, 0, len(urls))
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		endpoints = append(endpoints, &eth1Endpoint{url: url, score: maxEndpointScore})
+	}
+	return endpoints
+}
+
+// endpointSucceeded raises the health score of the current eth1 endpoint.
+func (s *Service) endpointSucceeded() {
+	if len(s.endpoints) == 0 {
+		return
+	}
+	if e := s.endpoints[s.currEndpoint]; e.score < maxEndpointScore {
+		e.score++
+	}
+}
+
+// endpointFailed lowers the health score of the current eth1 endpoint for the error and selects
+// the healthiest endpoint for the next connection, returning whether the endpoint changed.
+func (s *Service) endpointFailed(err error) bool {
+	if len(s.endpoints) == 0 {
+		return false
+	}
+	curr := s.endpoints[s.currEndpoint]
+	curr.score -= endpointFailurePenalty
+	if curr.score < 0 {
+		curr.score = 0
+	}
+	next := s.healthiestEndpoint()
+	if next == s.currEndpoint {
+		return false
+	}
+	log.WithError(err).WithFields(logrus.Fields{
+		"from": curr.url,
+		"to":   s.endpoints[next].url,
+	}).Warn("Falling back to another eth1 endpoint")
+	s.currEndpoint = next
+	s.httpEndpoint = s.endpoints[next].url
+	return true
+}
+
+// healthiestEndpoint returns the eth1 endpoint with the highest health score. The current
+// endpoint is kept among equals, otherwise endpoints are preferred in the configured order.
+func (s *Service) healthiestEndpoint() int {
+	best := s.currEndpoint
+	for i, e := range s.endpoints {
+		if e.score > s.endpoints[best].score {
+			best = i
+		}
+	}
+	return best
+}
+
+// fallback falls back from the current eth1 endpoint after the error, reconnecting to the
+// healthiest endpoint if it is another one.
+func (s *Service) fallback(err error) {
+	if !s.endpointFailed(err) {
+		return
+	}
+	if err := s.connectToPowChain(); err != nil {
+		log.WithError(err).Error("Could not connect to fallback eth1 endpoint")
+	}
+}
@@ -0,0 +1,68 @@
+package powchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+)
+
+func TestNewService_FallbackEndpoints(t *testing.T) {
+	beaconDB := dbutil.SetupDB(t)
+	web3Service, err := NewService(context.Background(), &Web3ServiceConfig{
+		HTTPEndPoint:          "http://primary",
+		FallbackHTTPEndPoints: []string{"http://fallback", ""},
+		BeaconDB:              beaconDB,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(web3Service.endpoints) != 2 {
+		t.Fatalf("Wanted 2 endpoints, received %d", len(web3Service.endpoints))
+	}
+	if web3Service.httpEndpoint != "http://primary" {
+		t.Errorf("Wanted the primary endpoint, received %s", web3Service.httpEndpoint)
+	}
+}
+
+func TestEndpointFailed_FallsBackToHealthiest(t *testing.T) {
+	s := &Service{endpoints: newEth1Endpoints([]string{"http://a", "http://b", "http://c"})}
+	s.httpEndpoint = s.endpoints[0].url
+
+	// The current endpoint is kept after a failure while it is the healthiest.
+	s.endpoints[1].score, s.endpoints[2].score = 0, 0
+	if s.endpointFailed(errors.New("timeout")) {
+		t.Error("Expected the healthiest current endpoint to be kept")
+	}
+	if want := maxEndpointScore - endpointFailurePenalty; s.endpoints[0].score != want {
+		t.Errorf("Wanted score %d, received %d", want, s.endpoints[0].score)
+	}
+
+	s.endpoints[1].score, s.endpoints[2].score = maxEndpointScore-1, maxEndpointScore
+	if !s.endpointFailed(errors.New("timeout")) {
+		t.Fatal("Expected a fall back to another endpoint")
+	}
+	if s.currEndpoint != 2 || s.httpEndpoint != "http://c" {
+		t.Errorf("Wanted the healthiest endpoint http://c, received %s", s.httpEndpoint)
+	}
+	if s.endpoints[0].score != 0 {
+		t.Errorf("Wanted the score of the failed endpoint floored at 0, received %d", s.endpoints[0].score)
+	}
+
+	// Successes restore the score of the current endpoint up to the maximum.
+	s.endpoints[2].score = maxEndpointScore - 1
+	s.endpointSucceeded()
+	s.endpointSucceeded()
+	if s.endpoints[2].score != maxEndpointScore {
+		t.Errorf("Wanted score %d after successes, received %d", maxEndpointScore, s.endpoints[2].score)
+	}
+}
+
+func TestEndpointFailed_NoEndpoints(t *testing.T) {
+	s := &Service{}
+	if s.endpointFailed(errors.New("timeout")) {
+		t.Error("Expected no fall back without endpoints")
+	}
+	s.endpointSucceeded()
+}
@@ -50,6 +50,10 @@ var (
 		Name: "powchain_missed_deposit_logs",
 		Help: "The number of times a missed deposit log is detected",
 	})
+	eth1ConnectedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "powchain_sync_eth1_connected",
+		Help: "Boolean indicating whether the node is connected to an eth1 endpoint",
+	})
 )
 
 // time to wait before trying to reconnect with the eth1 node.
@@ -124,11 +128,15 @@ type Service struct {
 	headerChan              chan *gethTypes.Header
 	headTicker              *time.Ticker
 	httpEndpoint            string
+	endpoints               []*eth1Endpoint
+	currEndpoint            int
 	stateNotifier           statefeed.Notifier
 	httpLogger              bind.ContractFilterer
 	blockFetcher            RPCBlockFetcher
 	rpcClient               RPCClient
 	blockCache              *blockCache // cache to store block hash/block height.
+	votingBlock             *votingBlock
+	votingBlockLock         sync.RWMutex
 	latestEth1Data          *protodb.LatestETH1Data
 	depositContractCaller   *contracts.DepositContractCaller
 	depositRoot             []byte
@@ -148,6 +156,11 @@ type Web3ServiceConfig struct {
 	BeaconDB        db.HeadAccessDatabase
 	DepositCache    *depositcache.DepositCache
 	StateNotifier   statefeed.Notifier
+	// FallbackHTTPEndPoints are fallen back to when the HTTP endpoint fails or stalls.
+	FallbackHTTPEndPoints []string
+	// GenesisState is the known genesis state of the chain, if any. The chain is then considered
+	// started, so genesis is not detected from the deposit contract.
+	GenesisState *stateTrie.BeaconState
 }
 
 // NewService sets up a new instance with an ethclient when
@@ -170,6 +183,7 @@ func NewService(ctx context.Context, config *Web3ServiceConfig) (*Service, error
 		cancel:       cancel,
 		headerChan:   make(chan *gethTypes.Header),
 		httpEndpoint: config.HTTPEndPoint,
+		endpoints:    newEth1Endpoints(append([]string{config.HTTPEndPoint}, config.FallbackHTTPEndPoints...)),
 		latestEth1Data: &protodb.LatestETH1Data{
 			BlockHeight:        0,
 			BlockTime:          0,
@@ -190,6 +204,9 @@ func NewService(ctx context.Context, config *Web3ServiceConfig) (*Service, error
 		preGenesisState:         genState,
 		headTicker:              time.NewTicker(time.Duration(params.BeaconConfig().SecondsPerETH1Block) * time.Second),
 	}
+	if len(s.endpoints) > 0 {
+		s.httpEndpoint = s.endpoints[0].url
+	}
 
 	eth1Data, err := config.BeaconDB.PowchainData(ctx)
 	if err != nil {
@@ -210,11 +227,21 @@ func NewService(ctx context.Context, config *Web3ServiceConfig) (*Service, error
 			return nil, errors.Wrap(err, "could not initialize caches")
 		}
 	}
+	if config.GenesisState != nil && !s.chainStartData.Chainstarted {
+		s.chainStartData.Chainstarted = true
+		s.chainStartData.GenesisTime = config.GenesisState.GenesisTime()
+		s.chainStartData.Eth1Data = config.GenesisState.Eth1Data()
+	}
 	return s, nil
 }
 
 // Start a web3 service's main event loop.
 func (s *Service) Start() {
+	// Without an endpoint, such as when running in interop mode, there is no eth1 chain to follow.
+	if s.httpEndpoint == "" {
+		log.Warn("No eth1 endpoint configured, running without an eth1 connection")
+		return
+	}
 	go func() {
 		s.waitForConnection()
 		s.run(s.ctx.Done())
@@ -357,12 +384,14 @@ func (s *Service) waitForConnection() {
 	err := s.connectToPowChain()
 	if err == nil {
 		s.connectedETH1 = true
+		eth1ConnectedGauge.Set(1)
 		log.WithFields(logrus.Fields{
 			"endpoint": s.httpEndpoint,
 		}).Info("Connected to eth1 proof-of-work chain")
 		return
 	}
 	log.WithError(err).Error("Could not connect to powchain endpoint")
+	s.endpointFailed(err)
 	ticker := time.NewTicker(backOffPeriod)
 	for {
 		select {
@@ -370,6 +399,7 @@ func (s *Service) waitForConnection() {
 			err := s.connectToPowChain()
 			if err == nil {
 				s.connectedETH1 = true
+				eth1ConnectedGauge.Set(1)
 				log.WithFields(logrus.Fields{
 					"endpoint": s.httpEndpoint,
 				}).Info("Connected to eth1 proof-of-work chain")
@@ -377,6 +407,7 @@ func (s *Service) waitForConnection() {
 				return
 			}
 			log.WithError(err).Error("Could not connect to powchain endpoint")
+			s.endpointFailed(err)
 		case <-s.ctx.Done():
 			ticker.Stop()
 			log.Debug("Received cancelled context,closing existing powchain service")
@@ -522,6 +553,7 @@ func (s *Service) handleDelayTicker() {
 	if err := s.requestBatchedLogs(context.Background()); err != nil {
 		s.runError = err
 		log.Error(err)
+		s.fallback(err)
 		return
 	}
 	// Reset the Status.
@@ -535,6 +567,8 @@ func (s *Service) initPOWService() {
 	retryETH1Node := func(err error) {
 		s.runError = err
 		s.connectedETH1 = false
+		eth1ConnectedGauge.Set(0)
+		s.endpointFailed(err)
 		s.waitForConnection()
 		// reset value in the event of a successful connection.
 		s.runError = nil
@@ -568,11 +602,32 @@ func (s *Service) initPOWService() {
 				retryETH1Node(err)
 				continue
 			}
+			if err := s.findGenesisBlock(context.Background()); err != nil {
+				log.WithError(err).Warn("Could not find the eth1 block of the genesis state")
+			}
 			return
 		}
 	}
 }
 
+// findGenesisBlock sets the number of the eth1 block of the chain start eth1 data, which is not
+// known when the chain was started from a known genesis state.
+func (s *Service) findGenesisBlock(ctx context.Context) error {
+	if !s.chainStartData.Chainstarted || s.chainStartData.GenesisBlock != 0 || s.chainStartData.Eth1Data == nil {
+		return nil
+	}
+	hash := common.BytesToHash(s.chainStartData.Eth1Data.BlockHash)
+	exists, number, err := s.BlockExists(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("eth1 block %#x not found", hash)
+	}
+	s.chainStartData.GenesisBlock = number.Uint64()
+	return nil
+}
+
 // run subscribes to all the services for the ETH1.0 chain.
 func (s *Service) run(done <-chan struct{}) {
 	s.isRunning = true
@@ -589,15 +644,22 @@ func (s *Service) run(done <-chan struct{}) {
 			s.isRunning = false
 			s.runError = nil
 			s.connectedETH1 = false
+			eth1ConnectedGauge.Set(0)
 			log.Debug("Context closed, exiting goroutine")
 			return
 		case <-s.headTicker.C:
 			head, err := s.blockFetcher.HeaderByNumber(s.ctx, nil)
 			if err != nil {
 				log.WithError(err).Debug("Could not fetch latest eth1 header")
+				s.fallback(err)
 				continue
 			}
 			s.processBlockHeader(head)
+			if time.Unix(int64(head.Time), 0).Add(eth1HeadStallTimeout).Before(roughtime.Now()) {
+				s.fallback(errors.New("latest eth1 block is too old, endpoint is stalled"))
+				continue
+			}
+			s.endpointSucceeded()
 		case <-ticker.C:
 			s.handleDelayTicker()
 		}
@@ -153,6 +153,72 @@ func TestStart_OK(t *testing.T) {
 	web3Service.cancel()
 }
 
+func TestStart_NoEndpoint(t *testing.T) {
+	hook := logTest.NewGlobal()
+	beaconDB := dbutil.SetupDB(t)
+	web3Service, err := NewService(context.Background(), &Web3ServiceConfig{
+		BeaconDB: beaconDB,
+	})
+	if err != nil {
+		t.Fatalf("unable to setup web3 ETH1.0 chain service: %v", err)
+	}
+	web3Service.Start()
+	testutil.AssertLogsContain(t, hook, "No eth1 endpoint configured")
+	if web3Service.IsConnectedToETH1() {
+		t.Error("Expected service to not be connected to eth1")
+	}
+	if err := web3Service.Status(); err != nil {
+		t.Errorf("Unexpected status error: %v", err)
+	}
+}
+
+func TestNewService_KnownGenesisState(t *testing.T) {
+	beaconDB := dbutil.SetupDB(t)
+	testAcc, err := contracts.Setup()
+	if err != nil {
+		t.Fatalf("Unable to set up simulated backend %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		testAcc.Backend.Commit()
+	}
+	genesisBlock := testAcc.Backend.Blockchain().CurrentBlock()
+	genesisState, _ := testutil.DeterministicGenesisState(t, 16)
+	eth1Data := genesisState.Eth1Data()
+	eth1Data.BlockHash = genesisBlock.Hash().Bytes()
+	if err := genesisState.SetEth1Data(eth1Data); err != nil {
+		t.Fatal(err)
+	}
+
+	web3Service, err := NewService(context.Background(), &Web3ServiceConfig{
+		HTTPEndPoint:    endpoint,
+		DepositContract: testAcc.ContractAddr,
+		BeaconDB:        beaconDB,
+		GenesisState:    genesisState,
+	})
+	if err != nil {
+		t.Fatalf("unable to setup web3 ETH1.0 chain service: %v", err)
+	}
+	web3Service = setDefaultMocks(web3Service)
+	web3Service.blockFetcher = &goodFetcher{backend: testAcc.Backend}
+
+	if !web3Service.chainStartData.Chainstarted {
+		t.Fatal("Expected the chain to be started with a known genesis state")
+	}
+	if !bytes.Equal(web3Service.ChainStartEth1Data().BlockHash, eth1Data.BlockHash) {
+		t.Errorf("Wanted chain start eth1 block %#x, received %#x", eth1Data.BlockHash, web3Service.ChainStartEth1Data().BlockHash)
+	}
+	if err := web3Service.findGenesisBlock(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	genesisTime, blockNumber := web3Service.Eth2GenesisPowchainInfo()
+	if genesisTime != genesisState.GenesisTime() {
+		t.Errorf("Wanted genesis time %d, received %d", genesisState.GenesisTime(), genesisTime)
+	}
+	if blockNumber.Cmp(genesisBlock.Number()) != 0 {
+		t.Errorf("Wanted genesis eth1 block %v, received %v", genesisBlock.Number(), blockNumber)
+	}
+}
+
 func TestStop_OK(t *testing.T) {
 	hook := logTest.NewGlobal()
 	testAcc, err := contracts.Setup()
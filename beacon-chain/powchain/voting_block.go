@@ -0,0 +1,49 @@
+package powchain
+
+import (
+	"math/big"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	votingBlockCacheHit = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "powchain_voting_block_cache_hit",
+		Help: "The number of voting period block requests answered without scanning eth1 blocks.",
+	})
+	votingBlockCacheMiss = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "powchain_voting_block_cache_miss",
+		Help: "The number of voting period block requests which scanned eth1 blocks.",
+	})
+)
+
+// votingBlock is the most recent eth1 block up to the start time of an eth1 voting period, as
+// found by the latest scan of the eth1 chain from the head block it scanned from.
+type votingBlock struct {
+	time    uint64
+	number  *big.Int
+	scanned *big.Int
+}
+
+// final returns whether a block more recent than the voting block has been scanned, in which
+// case no block arriving later is able to change the voting block of the period.
+func (b *votingBlock) final() bool {
+	return b.number.Cmp(b.scanned) < 0
+}
+
+// cachedVotingBlock returns the voting block of the time from the latest scan, if any.
+func (s *Service) cachedVotingBlock(time uint64) *votingBlock {
+	s.votingBlockLock.RLock()
+	defer s.votingBlockLock.RUnlock()
+	if s.votingBlock == nil || s.votingBlock.time != time {
+		return nil
+	}
+	return s.votingBlock
+}
+
+func (s *Service) cacheVotingBlock(b *votingBlock) {
+	s.votingBlockLock.Lock()
+	defer s.votingBlockLock.Unlock()
+	s.votingBlock = b
+}
@@ -0,0 +1,89 @@
+package powchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// chainFetcher serves a chain of blocks mined every 10 seconds up to the head, counting the
+// blocks fetched by number.
+type chainFetcher struct {
+	head    uint64
+	fetched int
+}
+
+func (c *chainFetcher) block(number uint64) *gethTypes.Block {
+	return gethTypes.NewBlockWithHeader(&gethTypes.Header{
+		Number: big.NewInt(int64(number)),
+		Time:   number * 10,
+	})
+}
+
+func (c *chainFetcher) HeaderByNumber(_ context.Context, number *big.Int) (*gethTypes.Header, error) {
+	if number == nil {
+		return c.block(c.head).Header(), nil
+	}
+	return c.block(number.Uint64()).Header(), nil
+}
+
+func (c *chainFetcher) BlockByNumber(_ context.Context, number *big.Int) (*gethTypes.Block, error) {
+	c.fetched++
+	if number == nil {
+		return c.block(c.head), nil
+	}
+	return c.block(number.Uint64()), nil
+}
+
+func (c *chainFetcher) BlockByHash(_ context.Context, _ common.Hash) (*gethTypes.Block, error) {
+	return nil, nil
+}
+
+func TestBlockNumberByTimestamp_VotingBlockCache(t *testing.T) {
+	fetcher := &chainFetcher{head: 20}
+	s := &Service{blockFetcher: fetcher, blockCache: newBlockCache()}
+	ctx := context.Background()
+
+	blockNumber := func(time uint64, want int64) {
+		t.Helper()
+		bn, err := s.BlockNumberByTimestamp(ctx, time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bn.Cmp(big.NewInt(want)) != 0 {
+			t.Errorf("Wanted block %d for time %d, received %v", want, time, bn)
+		}
+	}
+
+	// The head is the most recent block up to the time, the voting block is not final yet.
+	blockNumber(205, 20)
+	if s.votingBlock.final() {
+		t.Error("Wanted voting block at the head not to be final")
+	}
+
+	// Only the head and the blocks which arrived since the previous scan are fetched.
+	fetcher.head = 23
+	fetcher.fetched = 0
+	s.blockCache = newBlockCache()
+	blockNumber(205, 20)
+	if want := 4; fetcher.fetched != want {
+		t.Errorf("Wanted %d blocks fetched, received %d", want, fetcher.fetched)
+	}
+	if !s.votingBlock.final() {
+		t.Error("Wanted voting block to be final")
+	}
+
+	// A final voting block does not scan the chain anymore.
+	fetcher.head = 30
+	fetcher.fetched = 0
+	blockNumber(205, 20)
+	if fetcher.fetched != 0 {
+		t.Errorf("Wanted no blocks fetched, received %d", fetcher.fetched)
+	}
+
+	// Another voting period is scanned from the head.
+	blockNumber(255, 25)
+}
@@ -0,0 +1,189 @@
+// Package pruner defines a service which deletes the finalized forks and the finalized history
+// older than a retention period from the beacon node database, keeping at least the weak
+// subjectivity period.
+package pruner
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "pruner")
+
+var _ = shared.Service(&Service{})
+
+var (
+	prunedForkBlocksCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pruner_fork_blocks_total",
+		Help: "Number of finalized fork blocks deleted with their states.",
+	})
+	prunedHistoryBlocksCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pruner_history_blocks_total",
+		Help: "Number of blocks older than the retention period deleted with their states.",
+	})
+	prunedSlotGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "pruner_pruned_slot",
+		Help: "Slot before which the blocks and states were deleted.",
+	})
+)
+
+// Config options for the pruner service.
+type Config struct {
+	// BeaconDB is pruned whenever the finalized checkpoint advances.
+	BeaconDB db.NoHeadAccessDatabase
+	// HeadFetcher provides the head state from which the weak subjectivity period is computed.
+	HeadFetcher blockchain.HeadFetcher
+	// StateNotifier sends the finalized checkpoint events.
+	StateNotifier statefeed.Notifier
+	// RetentionEpochs is the number of finalized epochs to keep, raised to the weak
+	// subjectivity period.
+	RetentionEpochs uint64
+}
+
+// Service prunes the database when the finalized checkpoint advances.
+type Service struct {
+	ctx            context.Context
+	cancel         context.CancelFunc
+	cfg            *Config
+	lastForkSlot   uint64
+	lastPrunedSlot uint64
+}
+
+// NewService creates a new pruner service.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:    ctx,
+		cancel: cancel,
+		cfg:    cfg,
+	}
+}
+
+// Start the pruner service event loop.
+func (s *Service) Start() {
+	go s.run()
+}
+
+// Stop the pruner service event loop.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status of the pruner service, it is always healthy as a failed pruning is retried at the
+// next finalized checkpoint.
+func (s *Service) Status() error {
+	return nil
+}
+
+func (s *Service) run() {
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.cfg.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	for {
+		select {
+		case event := <-stateChannel:
+			if event.Type != statefeed.FinalizedCheckpoint {
+				continue
+			}
+			data, ok := event.Data.(*statefeed.FinalizedCheckpointData)
+			if !ok {
+				log.Error("Event feed data is not type *statefeed.FinalizedCheckpointData")
+				continue
+			}
+			if err := s.prune(s.ctx, data.Epoch); err != nil {
+				log.WithError(err).Error("Could not prune database")
+			}
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting goroutine")
+			return
+		case err := <-stateSub.Err():
+			log.WithError(err).Error("Subscription to state feed notifier failed")
+			return
+		}
+	}
+}
+
+// prune deletes the forks finalized since the last pruning, then the blocks and states older
+// than the retention period behind the finalized epoch.
+func (s *Service) prune(ctx context.Context, finalizedEpoch uint64) error {
+	finalizedSlot := helpers.StartSlot(finalizedEpoch)
+	if finalizedSlot > s.lastForkSlot {
+		pruned, err := s.cfg.BeaconDB.PruneForks(ctx, s.lastForkSlot)
+		if err != nil {
+			return errors.Wrap(err, "could not prune forks")
+		}
+		prunedForkBlocksCount.Add(float64(pruned))
+		if pruned > 0 {
+			log.WithField("blocks", pruned).Debug("Pruned finalized forks")
+		}
+		s.lastForkSlot = finalizedSlot
+	}
+
+	horizon, err := s.horizon(ctx, finalizedEpoch)
+	if err != nil {
+		return err
+	}
+	// Pruning is split in steps of an archived point, so a single database transaction never
+	// holds too many deletions.
+	total := 0
+	for s.lastPrunedSlot < horizon {
+		slot := s.lastPrunedSlot + params.BeaconConfig().SlotsPerArchivedPoint
+		if slot > horizon {
+			slot = horizon
+		}
+		pruned, err := s.cfg.BeaconDB.PruneBefore(ctx, slot)
+		if err != nil {
+			return errors.Wrapf(err, "could not prune blocks before slot %d", slot)
+		}
+		total += pruned
+		prunedHistoryBlocksCount.Add(float64(pruned))
+		prunedSlotGauge.Set(float64(slot))
+		s.lastPrunedSlot = slot
+	}
+	if total > 0 {
+		log.WithFields(logrus.Fields{
+			"blocks":     total,
+			"beforeSlot": horizon,
+		}).Info("Pruned blocks and states older than the retention period")
+	}
+	return nil
+}
+
+// horizon returns the slot before which blocks and states are deleted. It keeps the greater
+// of the configured retention and the weak subjectivity period behind the finalized epoch, and
+// is rounded down to an archived point so the states in the retention period can still be
+// regenerated.
+func (s *Service) horizon(ctx context.Context, finalizedEpoch uint64) (uint64, error) {
+	headState, err := s.cfg.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get head state")
+	}
+	if headState == nil {
+		return 0, errors.New("head state is not available")
+	}
+	wsPeriod, err := helpers.ComputeWeakSubjectivityPeriod(headState)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not compute weak subjectivity period")
+	}
+	retention := s.cfg.RetentionEpochs
+	if retention < wsPeriod {
+		retention = wsPeriod
+	}
+	if finalizedEpoch <= retention {
+		return 0, nil
+	}
+	horizon := helpers.StartSlot(finalizedEpoch - retention)
+	return horizon - horizon%params.BeaconConfig().SlotsPerArchivedPoint, nil
+}
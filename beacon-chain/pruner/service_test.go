@@ -0,0 +1,94 @@
+package pruner
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+type mockDB struct {
+	db.NoHeadAccessDatabase
+	forkStartSlots []uint64
+	beforeSlots    []uint64
+}
+
+func (m *mockDB) PruneForks(_ context.Context, startSlot uint64) (int, error) {
+	m.forkStartSlots = append(m.forkStartSlots, startSlot)
+	return 1, nil
+}
+
+func (m *mockDB) PruneBefore(_ context.Context, slot uint64) (int, error) {
+	m.beforeSlots = append(m.beforeSlots, slot)
+	return 1, nil
+}
+
+func setupService(t *testing.T, retentionEpochs uint64) (*Service, *mockDB) {
+	beaconState, _ := testutil.DeterministicGenesisState(t, 64)
+	beaconDB := &mockDB{}
+	return NewService(context.Background(), &Config{
+		BeaconDB:        beaconDB,
+		HeadFetcher:     &mock.ChainService{State: beaconState},
+		RetentionEpochs: retentionEpochs,
+	}), beaconDB
+}
+
+func TestPrune_KeepsWeakSubjectivityPeriod(t *testing.T) {
+	svc, beaconDB := setupService(t, 1)
+	wsPeriod := params.BeaconConfig().MinValidatorWithdrawabilityDelay
+
+	if err := svc.prune(context.Background(), wsPeriod); err != nil {
+		t.Fatal(err)
+	}
+	if len(beaconDB.beforeSlots) != 0 {
+		t.Errorf("Wanted nothing pruned within the weak subjectivity period, received %v", beaconDB.beforeSlots)
+	}
+
+	// The horizon is rounded down to an archived point, and pruned an archived point at a time.
+	finalizedEpoch := wsPeriod + 200
+	if err := svc.prune(context.Background(), finalizedEpoch); err != nil {
+		t.Fatal(err)
+	}
+	point := params.BeaconConfig().SlotsPerArchivedPoint
+	horizon := helpers.StartSlot(200) / point * point
+	var want []uint64
+	for slot := point; slot <= horizon; slot += point {
+		want = append(want, slot)
+	}
+	if !reflect.DeepEqual(beaconDB.beforeSlots, want) {
+		t.Errorf("Wanted pruning before slots %v, received %v", want, beaconDB.beforeSlots)
+	}
+	if wantForks := []uint64{0, helpers.StartSlot(wsPeriod)}; !reflect.DeepEqual(beaconDB.forkStartSlots, wantForks) {
+		t.Errorf("Wanted forks pruned from slots %v, received %v", wantForks, beaconDB.forkStartSlots)
+	}
+
+	// Pruning resumes from the previous horizon.
+	beaconDB.beforeSlots = nil
+	if err := svc.prune(context.Background(), finalizedEpoch+1); err != nil {
+		t.Fatal(err)
+	}
+	if len(beaconDB.beforeSlots) != 0 {
+		t.Errorf("Wanted nothing pruned before the next archived point, received %v", beaconDB.beforeSlots)
+	}
+}
+
+func TestPrune_RetentionEpochs(t *testing.T) {
+	wsPeriod := params.BeaconConfig().MinValidatorWithdrawabilityDelay
+	retention := wsPeriod + 100
+	svc, beaconDB := setupService(t, retention)
+
+	finalizedEpoch := retention + 200
+	if err := svc.prune(context.Background(), finalizedEpoch); err != nil {
+		t.Fatal(err)
+	}
+	point := params.BeaconConfig().SlotsPerArchivedPoint
+	horizon := helpers.StartSlot(200) / point * point
+	if len(beaconDB.beforeSlots) == 0 || beaconDB.beforeSlots[len(beaconDB.beforeSlots)-1] != horizon {
+		t.Errorf("Wanted pruning before slot %d, received %v", horizon, beaconDB.beforeSlots)
+	}
+}
@@ -0,0 +1,99 @@
+// Package apimiddleware defines a configurable chain of gRPC server middlewares for the
+// beacon node API, such as authentication, per-method rate limiting, request logging
+// and metrics. Requests to the JSON-HTTP gateway are proxied to the gRPC server and pass
+// through the same chain.
+package apimiddleware
+
+import (
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+)
+
+// Middleware intercepts unary and streaming calls to the gRPC server.
+type Middleware interface {
+	Name() string
+	UnaryInterceptor() grpc.UnaryServerInterceptor
+	StreamInterceptor() grpc.StreamServerInterceptor
+}
+
+// Chain is an ordered list of middlewares. Calls pass through the middlewares in the
+// order they were added.
+type Chain struct {
+	middlewares []Middleware
+}
+
+// NewChain returns a chain of the given middlewares.
+func NewChain(middlewares ...Middleware) *Chain {
+	return &Chain{middlewares: middlewares}
+}
+
+// Use appends a middleware to the chain.
+func (c *Chain) Use(m Middleware) {
+	c.middlewares = append(c.middlewares, m)
+}
+
+// Names of the middlewares in the chain, in order.
+func (c *Chain) Names() []string {
+	names := make([]string, len(c.middlewares))
+	for i, m := range c.middlewares {
+		names[i] = m.Name()
+	}
+	return names
+}
+
+// ServerOptions returns the gRPC server options installing the chain.
+func (c *Chain) ServerOptions() []grpc.ServerOption {
+	unary := make([]grpc.UnaryServerInterceptor, len(c.middlewares))
+	stream := make([]grpc.StreamServerInterceptor, len(c.middlewares))
+	for i, m := range c.middlewares {
+		unary[i] = m.UnaryInterceptor()
+		stream[i] = m.StreamInterceptor()
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(middleware.ChainUnaryServer(unary...)),
+		grpc.StreamInterceptor(middleware.ChainStreamServer(stream...)),
+	}
+}
+
+// FromInterceptors wraps a pair of existing interceptors as a middleware.
+func FromInterceptors(name string, unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) Middleware {
+	return &interceptors{name: name, unary: unary, stream: stream}
+}
+
+type interceptors struct {
+	name   string
+	unary  grpc.UnaryServerInterceptor
+	stream grpc.StreamServerInterceptor
+}
+
+func (i *interceptors) Name() string {
+	return i.name
+}
+
+func (i *interceptors) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return i.unary
+}
+
+func (i *interceptors) StreamInterceptor() grpc.StreamServerInterceptor {
+	return i.stream
+}
+
+// matchMethod returns the most specific pattern matching a full gRPC method name: the
+// method itself, its service (such as /ethereum.beacon.rpc.v1.Debug/), or "*".
+func matchMethod(method string, has func(pattern string) bool) (string, bool) {
+	if has(method) {
+		return method, true
+	}
+	for i := len(method) - 1; i > 0; i-- {
+		if method[i] == '/' {
+			if has(method[:i+1]) {
+				return method[:i+1], true
+			}
+			break
+		}
+	}
+	if has("*") {
+		return "*", true
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+package apimiddleware
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return FromInterceptors(
+		name,
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			*calls = append(*calls, name)
+			return handler(ctx, req)
+		},
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			*calls = append(*calls, name)
+			return handler(srv, ss)
+		},
+	)
+}
+
+func TestChain_Order(t *testing.T) {
+	var calls []string
+	chain := NewChain(recordingMiddleware("first", &calls))
+	chain.Use(recordingMiddleware("second", &calls))
+
+	if names := chain.Names(); !reflect.DeepEqual(names, []string{"first", "second"}) {
+		t.Errorf("Unexpected middleware names %v", names)
+	}
+	if len(chain.ServerOptions()) != 2 {
+		t.Error("Expected a unary and a stream server option")
+	}
+
+	unary := make([]grpc.UnaryServerInterceptor, len(chain.middlewares))
+	for i, m := range chain.middlewares {
+		unary[i] = m.UnaryInterceptor()
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	}
+	_, err := unary[0](context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return unary[1](ctx, req, info, handler)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, []string{"first", "second", "handler"}) {
+		t.Errorf("Unexpected call order %v", calls)
+	}
+}
+
+func TestMatchMethod(t *testing.T) {
+	patterns := map[string]bool{
+		"/test.Service/Exact": true,
+		"/test.Debug/":        true,
+		"*":                   true,
+	}
+	has := func(p string) bool { return patterns[p] }
+	tests := map[string]string{
+		"/test.Service/Exact": "/test.Service/Exact",
+		"/test.Debug/Any":     "/test.Debug/",
+		"/test.Service/Other": "*",
+	}
+	for method, want := range tests {
+		got, ok := matchMethod(method, has)
+		if !ok || got != want {
+			t.Errorf("matchMethod(%s) = %s, wanted %s", method, got, want)
+		}
+	}
+	if _, ok := matchMethod("/test.Service/Other", func(string) bool { return false }); ok {
+		t.Error("Expected no match without patterns")
+	}
+}
@@ -0,0 +1,64 @@
+package apimiddleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RequestLogger logs every call with its duration and status code at debug level, and
+// unary calls slower than a threshold at warn level.
+type RequestLogger struct {
+	log           logrus.FieldLogger
+	slowThreshold time.Duration
+}
+
+// NewRequestLogger returns a request logging middleware. A zero slowThreshold disables
+// the logging of slow calls.
+func NewRequestLogger(log logrus.FieldLogger, slowThreshold time.Duration) *RequestLogger {
+	return &RequestLogger{log: log, slowThreshold: slowThreshold}
+}
+
+// Name of the middleware.
+func (l *RequestLogger) Name() string {
+	return "logging"
+}
+
+// UnaryInterceptor logs unary calls.
+func (l *RequestLogger) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l.logCall(ctx, info.FullMethod, start, err, l.slowThreshold)
+		return resp, err
+	}
+}
+
+// StreamInterceptor logs streams when they end. Streams are long lived, so they are never
+// logged as slow.
+func (l *RequestLogger) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		l.logCall(ss.Context(), info.FullMethod, start, err, 0)
+		return err
+	}
+}
+
+func (l *RequestLogger) logCall(ctx context.Context, method string, start time.Time, err error, slowThreshold time.Duration) {
+	duration := time.Since(start)
+	entry := l.log.WithFields(logrus.Fields{
+		"method":   method,
+		"duration": duration,
+		"code":     status.Code(err).String(),
+		"client":   clientKey(ctx),
+	})
+	if slowThreshold > 0 && duration > slowThreshold {
+		entry.Warn("Slow RPC request")
+		return
+	}
+	entry.Debug("RPC request")
+}
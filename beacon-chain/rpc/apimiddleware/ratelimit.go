@@ -0,0 +1,172 @@
+package apimiddleware
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"strings"
+
+	"github.com/kevinms/leakybucket-go"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+var rateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rpc_rate_limited_requests_total",
+	Help: "Number of RPC requests rejected because the client exceeded the method quota",
+}, []string{"method"})
+
+// Quota is the allowed request rate of a method per client.
+type Quota struct {
+	// Rate is the number of requests per second a client may sustain.
+	Rate float64 `yaml:"rate"`
+	// Burst is the number of requests a client may make at once.
+	Burst int64 `yaml:"burst"`
+}
+
+// QuotaConfig maps method patterns to quotas. A pattern is a full method name such as
+// /ethereum.eth.v1alpha1.BeaconChain/ListValidators, a service such as
+// /ethereum.beacon.rpc.v1.Debug/, or "*" for all other methods. Methods without a
+// matching pattern are not limited.
+type QuotaConfig struct {
+	Methods map[string]Quota `yaml:"methods"`
+}
+
+// DefaultQuotaConfig gives heavyweight state and history queries tighter quotas, while
+// leaving validator duty endpoints unlimited.
+func DefaultQuotaConfig() *QuotaConfig {
+	return &QuotaConfig{
+		Methods: map[string]Quota{
+			"/ethereum.beacon.rpc.v1.Debug/GetBeaconState":                    {Rate: 0.5, Burst: 2},
+			"/ethereum.beacon.rpc.v1.Debug/GetProtoArrayForkChoice":           {Rate: 1, Burst: 2},
+			"/ethereum.beacon.rpc.v1.DebugStream/StreamBeaconState":           {Rate: 0.5, Burst: 2},
+			"/ethereum.eth.v1alpha1.BeaconChain/ListValidators":               {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/ListValidatorBalances":        {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/ListValidatorAssignments":     {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/ListBeaconCommittees":         {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/ListBlocks":                   {Rate: 10, Burst: 20},
+			"/ethereum.eth.v1alpha1.BeaconChain/ListAttestations":             {Rate: 10, Burst: 20},
+			"/ethereum.eth.v1alpha1.BeaconChain/ListIndexedAttestations":      {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/GetValidatorActiveSetChanges": {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/GetValidatorParticipation":    {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/GetValidatorQueue":            {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/GetValidatorPerformance":      {Rate: 5, Burst: 10},
+			"/ethereum.eth.v1alpha1.BeaconChain/StreamValidatorsInfo":         {Rate: 1, Burst: 5},
+			"/ethereum.eth.v1alpha1.BeaconChain/StreamIndexedAttestations":    {Rate: 1, Burst: 5},
+		},
+	}
+}
+
+// LoadQuotaConfig reads a quota config from a YAML file.
+func LoadQuotaConfig(path string) (*QuotaConfig, error) {
+	// #nosec G304
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read quota config")
+	}
+	cfg := &QuotaConfig{}
+	if err := yaml.Unmarshal(enc, cfg); err != nil {
+		return nil, errors.Wrap(err, "could not parse quota config")
+	}
+	for pattern, q := range cfg.Methods {
+		if q.Rate <= 0 || q.Burst <= 0 {
+			return nil, errors.Errorf("quota of %s must have a positive rate and burst", pattern)
+		}
+	}
+	return cfg, nil
+}
+
+// RateLimiter rejects calls of clients which exceed the quota of the called method.
+// Clients are identified by their remote IP address, see clientKey.
+type RateLimiter struct {
+	limiters map[string]*leakybucket.Collector
+}
+
+// NewRateLimiter returns a rate limiting middleware for the given quotas.
+func NewRateLimiter(cfg *QuotaConfig) *RateLimiter {
+	limiters := make(map[string]*leakybucket.Collector, len(cfg.Methods))
+	for pattern, q := range cfg.Methods {
+		limiters[pattern] = leakybucket.NewCollector(q.Rate, q.Burst, true /* deleteEmptyBuckets */)
+	}
+	return &RateLimiter{limiters: limiters}
+}
+
+// Name of the middleware.
+func (r *RateLimiter) Name() string {
+	return "ratelimit"
+}
+
+// UnaryInterceptor limits unary calls.
+func (r *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := r.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor limits the opening of streams.
+func (r *RateLimiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := r.allow(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (r *RateLimiter) allow(ctx context.Context, method string) error {
+	pattern, ok := matchMethod(method, func(p string) bool {
+		_, ok := r.limiters[p]
+		return ok
+	})
+	if !ok {
+		return nil
+	}
+	// Add only fills the bucket up to its capacity, so concurrent calls cannot both take the
+	// last request of the quota.
+	if r.limiters[pattern].Add(clientKey(ctx), 1) < 1 {
+		rateLimitedRequests.WithLabelValues(method).Inc()
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", method)
+	}
+	return nil
+}
+
+// clientKey identifies the client of a call by its IP address. The JSON gateway calls the
+// server from the loopback address for all its clients, so for calls from loopback the client
+// is identified by the address the gateway received the request from, which it forwards as
+// the last address of the x-forwarded-for metadata. Other clients cannot claim an address.
+func clientKey(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return host
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return host
+	}
+	forwarded := md.Get("x-forwarded-for")
+	if len(forwarded) == 0 {
+		return host
+	}
+	addrs := strings.Split(forwarded[len(forwarded)-1], ",")
+	if addr := strings.TrimSpace(addrs[len(addrs)-1]); addr != "" {
+		return addr
+	}
+	return host
+}
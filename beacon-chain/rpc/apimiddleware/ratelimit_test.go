@@ -0,0 +1,120 @@
+package apimiddleware
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000},
+	})
+}
+
+func TestRateLimiter_PerMethodQuota(t *testing.T) {
+	limiter := NewRateLimiter(&QuotaConfig{
+		Methods: map[string]Quota{
+			"/test.Service/Heavy": {Rate: 0.001, Burst: 2},
+		},
+	})
+	interceptor := limiter.UnaryInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	call := func(ctx context.Context, method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	ctx := peerContext("10.0.0.1")
+	for i := 0; i < 2; i++ {
+		if err := call(ctx, "/test.Service/Heavy"); err != nil {
+			t.Fatalf("Call %d within burst failed: %v", i, err)
+		}
+	}
+	if err := call(ctx, "/test.Service/Heavy"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected resource exhausted after burst, received %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := call(ctx, "/test.Service/Light"); err != nil {
+			t.Fatalf("Unlimited method failed: %v", err)
+		}
+	}
+	if err := call(peerContext("10.0.0.2"), "/test.Service/Heavy"); err != nil {
+		t.Errorf("Expected separate quota for another client, received %v", err)
+	}
+
+	// Clients of the gateway have their own quotas, while remote clients cannot claim one.
+	gateway := func(forwarded string) context.Context {
+		return metadata.NewIncomingContext(peerContext("127.0.0.1"), metadata.Pairs("x-forwarded-for", forwarded))
+	}
+	for i := 0; i < 2; i++ {
+		if err := call(gateway("10.0.0.3"), "/test.Service/Heavy"); err != nil {
+			t.Fatalf("Gateway call %d within burst failed: %v", i, err)
+		}
+	}
+	if err := call(gateway("10.0.0.3"), "/test.Service/Heavy"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected resource exhausted after burst of gateway client, received %v", err)
+	}
+	if err := call(gateway("10.0.0.1, 10.0.0.4"), "/test.Service/Heavy"); err != nil {
+		t.Errorf("Expected separate quota for another gateway client, received %v", err)
+	}
+	spoofed := metadata.NewIncomingContext(peerContext("10.0.0.1"), metadata.Pairs("x-forwarded-for", "10.0.0.5"))
+	if err := call(spoofed, "/test.Service/Heavy"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected forwarded address of remote client to be ignored, received %v", err)
+	}
+}
+
+func TestLoadQuotaConfig(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(), "quotas")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	content := `methods:
+  /ethereum.beacon.rpc.v1.Debug/:
+    rate: 1
+    burst: 2
+  "*":
+    rate: 50
+    burst: 100
+`
+	if err := ioutil.WriteFile(valid, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadQuotaConfig(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := cfg.Methods["/ethereum.beacon.rpc.v1.Debug/"]; q.Rate != 1 || q.Burst != 2 {
+		t.Errorf("Unexpected debug quota %+v", q)
+	}
+	if q := cfg.Methods["*"]; q.Rate != 50 || q.Burst != 100 {
+		t.Errorf("Unexpected default quota %+v", q)
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := ioutil.WriteFile(invalid, []byte("methods:\n  \"*\":\n    rate: 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadQuotaConfig(invalid); err == nil {
+		t.Error("Expected error for quota without burst")
+	}
+}
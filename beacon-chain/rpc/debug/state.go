@@ -17,6 +17,18 @@ func (ds *Server) GetBeaconState(
 	ctx context.Context,
 	req *pbrpc.BeaconStateRequest,
 ) (*pbrpc.SSZResponse, error) {
+	encoded, err := ds.encodedBeaconState(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &pbrpc.SSZResponse{
+		Encoded: encoded,
+	}, nil
+}
+
+// encodedBeaconState retrieves the ssz-encoded beacon state requested by either a slot or
+// block root.
+func (ds *Server) encodedBeaconState(ctx context.Context, req *pbrpc.BeaconStateRequest) ([]byte, error) {
 	if !featureconfig.Get().NewStateMgmt {
 		return nil, status.Error(codes.FailedPrecondition, "Requires --enable-new-state-mgmt to function")
 	}
@@ -42,9 +54,7 @@ func (ds *Server) GetBeaconState(
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not ssz encode beacon state: %v", err)
 		}
-		return &pbrpc.SSZResponse{
-			Encoded: encoded,
-		}, nil
+		return encoded, nil
 	case *pbrpc.BeaconStateRequest_BlockRoot:
 		st, err := ds.StateGen.StateByRoot(ctx, bytesutil.ToBytes32(q.BlockRoot))
 		if err != nil {
@@ -54,9 +64,7 @@ func (ds *Server) GetBeaconState(
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not ssz encode beacon state: %v", err)
 		}
-		return &pbrpc.SSZResponse{
-			Encoded: encoded,
-		}, nil
+		return encoded, nil
 	default:
 		return nil, status.Error(codes.InvalidArgument, "Need to specify either a block root or slot to request state")
 	}
@@ -0,0 +1,133 @@
+package debug
+
+import (
+	"context"
+
+	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StateChunkSize is the size of the chunks of the ssz-encoded beacon states streamed by
+// StreamBeaconState, well below the 4MB message size gRPC clients accept by default.
+const StateChunkSize = 1 << 20
+
+// StreamBeaconStateMethod is the full gRPC method name of StreamBeaconState.
+const StreamBeaconStateMethod = "/ethereum.beacon.rpc.v1.DebugStream/StreamBeaconState"
+
+// StreamServer is the server API of the streaming debug endpoints, which transfer
+// responses too large for a single gRPC message in chunks.
+type StreamServer interface {
+	StreamBeaconState(*pbrpc.BeaconStateRequest, BeaconStateServerStream) error
+}
+
+// BeaconStateServerStream is the server side of a StreamBeaconState stream.
+type BeaconStateServerStream interface {
+	Send(*pbrpc.SSZResponse) error
+	grpc.ServerStream
+}
+
+type beaconStateServerStream struct {
+	grpc.ServerStream
+}
+
+func (x *beaconStateServerStream) Send(m *pbrpc.SSZResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func streamBeaconStateHandler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(pbrpc.BeaconStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamServer).StreamBeaconState(m, &beaconStateServerStream{stream})
+}
+
+// The service is described by hand, as the proto definitions of the debug service cannot
+// declare it without regenerating the gateway of the unary endpoints, which cannot stream.
+var streamServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ethereum.beacon.rpc.v1.DebugStream",
+	HandlerType: (*StreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBeaconState",
+			Handler:       streamBeaconStateHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/beacon/rpc/v1/debug.proto",
+}
+
+// RegisterStreamServer registers the streaming debug endpoints on the gRPC server.
+func RegisterStreamServer(s *grpc.Server, srv StreamServer) {
+	s.RegisterService(&streamServiceDesc, srv)
+}
+
+// StreamBeaconState streams an ssz-encoded beacon state from the beacon node by either a slot
+// or block root, in chunks of StateChunkSize bytes to be concatenated by the client. It is the
+// variant of GetBeaconState for states larger than the message size limit of the client.
+func (ds *Server) StreamBeaconState(req *pbrpc.BeaconStateRequest, stream BeaconStateServerStream) error {
+	encoded, err := ds.encodedBeaconState(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(encoded); start += StateChunkSize {
+		end := start + StateChunkSize
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		if err := stream.Send(&pbrpc.SSZResponse{Encoded: encoded[start:end]}); err != nil {
+			return status.Errorf(codes.Unavailable, "Could not send beacon state chunk: %v", err)
+		}
+	}
+	return nil
+}
+
+// StreamClient is the client API of the streaming debug endpoints.
+type StreamClient interface {
+	StreamBeaconState(ctx context.Context, in *pbrpc.BeaconStateRequest, opts ...grpc.CallOption) (BeaconStateClientStream, error)
+}
+
+// BeaconStateClientStream is the client side of a StreamBeaconState stream.
+type BeaconStateClientStream interface {
+	Recv() (*pbrpc.SSZResponse, error)
+	grpc.ClientStream
+}
+
+type streamClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewStreamClient creates a client of the streaming debug endpoints.
+func NewStreamClient(cc *grpc.ClientConn) StreamClient {
+	return &streamClient{cc}
+}
+
+func (c *streamClient) StreamBeaconState(ctx context.Context, in *pbrpc.BeaconStateRequest, opts ...grpc.CallOption) (BeaconStateClientStream, error) {
+	stream, err := c.cc.NewStream(ctx, &streamServiceDesc.Streams[0], StreamBeaconStateMethod, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &beaconStateClientStream{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type beaconStateClientStream struct {
+	grpc.ClientStream
+}
+
+func (x *beaconStateClientStream) Recv() (*pbrpc.SSZResponse, error) {
+	m := new(pbrpc.SSZResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,116 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/grpcutils"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+func TestServer_StreamBeaconState(t *testing.T) {
+	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{NewStateMgmt: true})
+	defer resetCfg()
+
+	db := dbTest.SetupDB(t)
+	ctx := context.Background()
+	st := testutil.NewBeaconState()
+	b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{}}
+	if err := db.SaveBlock(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(b.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := stategen.New(db, cache.NewStateSummaryCache())
+	if err := gen.SaveState(ctx, root, st); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, root); err != nil {
+		t.Fatal(err)
+	}
+	wanted, err := ssz.Marshal(st.CloneInnerState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wanted) <= StateChunkSize {
+		t.Fatalf("State of %d bytes is streamed in a single chunk", len(wanted))
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	RegisterStreamServer(server, &Server{StateGen: gen, GenesisTimeFetcher: &mock.ChainService{}})
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			t.Log(err)
+		}
+	}()
+	defer server.Stop()
+
+	// The client accepts messages of the size of a chunk only, and compresses the stream.
+	conn, err := grpc.Dial(
+		lis.Addr().String(),
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(StateChunkSize+1024)),
+		grpc.WithStreamInterceptor(grpcutils.CompressLargeStreamResponses),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	stream, err := NewStreamClient(conn).StreamBeaconState(ctx, &pbrpc.BeaconStateRequest{
+		QueryFilter: &pbrpc.BeaconStateRequest_BlockRoot{BlockRoot: root[:]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encoded []byte
+	chunks := 0
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded = append(encoded, res.Encoded...)
+		chunks++
+	}
+	if wantedChunks := (len(wanted) + StateChunkSize - 1) / StateChunkSize; chunks != wantedChunks {
+		t.Errorf("Wanted %d chunks, received %d", wantedChunks, chunks)
+	}
+	if !bytes.Equal(wanted, encoded) {
+		t.Error("Streamed state does not match the state")
+	}
+
+	stream, err = NewStreamClient(conn).StreamBeaconState(ctx, &pbrpc.BeaconStateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("Expected error without a query filter, received nil")
+	}
+}
@@ -0,0 +1,253 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/epoch/precompute"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+const (
+	// defaultPerformanceEpochs is the number of epochs served by validatorPerformance when the
+	// `epochs` query parameter is not given.
+	defaultPerformanceEpochs = 4
+	// maxPerformanceEpochs bounds the number of states replayed for a single request.
+	maxPerformanceEpochs = 32
+)
+
+// epochValidators serves the balances, effective balances and attestation participation of the
+// validators given by the `id` query parameters at a past epoch, along with the participation
+// rate of the whole network, so rewards can be accounted for without replaying states. All
+// validators are served when no ids are given.
+//
+// The values are taken from the state at the start of the next epoch, once the epoch is over and
+// its attestations are included. Finalized epochs are served from the cold state storage.
+func (s *Server) epochValidators(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	epoch, err := strconv.ParseUint(vars["epoch"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid epoch %q", vars["epoch"]))
+		return
+	}
+	if currentEpoch := helpers.SlotToEpoch(s.GenesisTimeFetcher.CurrentSlot()); epoch >= currentEpoch {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("epoch %d is not over, current epoch is %d", epoch, currentEpoch))
+		return
+	}
+	if !featureconfig.Get().NewStateMgmt {
+		writeError(w, http.StatusNotFound, "historical states are only available with new state management")
+		return
+	}
+	st, vp, bp, err := s.epochParticipation(r.Context(), epoch)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	var indices []uint64
+	ids := queryList(r, "id")
+	if len(ids) == 0 {
+		for i := 0; i < st.NumValidators(); i++ {
+			indices = append(indices, uint64(i))
+		}
+	}
+	for _, id := range ids {
+		index, err := validatorIndex(st, id)
+		if err != nil {
+			writeErr(w, err)
+			return
+		}
+		indices = append(indices, index)
+	}
+
+	validators := make([]interface{}, 0, len(indices))
+	for _, index := range indices {
+		v, err := st.ValidatorAtIndexReadOnly(index)
+		if err != nil {
+			writeErr(w, err)
+			return
+		}
+		balance, err := st.BalanceAtIndex(index)
+		if err != nil {
+			writeErr(w, err)
+			return
+		}
+		pubKey := v.PublicKey()
+		data := participationJSON(vp[index])
+		data["index"] = strconv.FormatUint(index, 10)
+		data["pubkey"] = encode(pubKey[:])
+		data["balance"] = strconv.FormatUint(balance, 10)
+		data["effective_balance"] = strconv.FormatUint(v.EffectiveBalance(), 10)
+		validators = append(validators, data)
+	}
+
+	var rate float64
+	if bp.ActivePrevEpoch > 0 {
+		rate = float64(bp.PrevEpochTargetAttested) / float64(bp.ActivePrevEpoch)
+	}
+	writeData(w, map[string]interface{}{
+		"epoch":     strconv.FormatUint(epoch, 10),
+		"finalized": epoch <= s.FinalizationFetcher.FinalizedCheckpt().Epoch,
+		"participation": map[string]interface{}{
+			"global_participation_rate": rate,
+			"voted_ether":               strconv.FormatUint(bp.PrevEpochTargetAttested, 10),
+			"eligible_ether":            strconv.FormatUint(bp.ActivePrevEpoch, 10),
+		},
+		"validators": validators,
+	})
+}
+
+// validatorPerformance serves the attestation inclusion distance, the source, target and head
+// correctness and the balance change of the validators given by the `id` query parameters at
+// each of the last `epochs` epochs which are over, most recent first, so operators can tell why
+// their rewards dropped without an external explorer.
+//
+// The balance change of an epoch is the difference between the balances at the start of the
+// epoch and at the start of the next one. Validators are left out of the epochs before their
+// deposit was processed.
+func (s *Server) validatorPerformance(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	ids := queryList(r, "id")
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "no validator id given")
+		return
+	}
+	epochs, ok, err := queryUint(r, "epochs")
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	if !ok {
+		epochs = defaultPerformanceEpochs
+	}
+	if epochs == 0 || epochs > maxPerformanceEpochs {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("epochs must be between 1 and %d", maxPerformanceEpochs))
+		return
+	}
+	if !featureconfig.Get().NewStateMgmt {
+		writeError(w, http.StatusNotFound, "historical states are only available with new state management")
+		return
+	}
+	currentEpoch := helpers.SlotToEpoch(s.GenesisTimeFetcher.CurrentSlot())
+	if currentEpoch == 0 {
+		writeError(w, http.StatusBadRequest, "no epoch is over yet")
+		return
+	}
+	if epochs > currentEpoch {
+		epochs = currentEpoch
+	}
+
+	// The ids are resolved against the most recent state, which knows about all the validators.
+	epoch := currentEpoch - 1
+	st, vp, _, err := s.epochParticipation(r.Context(), epoch)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	indices := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		index, err := validatorIndex(st, id)
+		if err != nil {
+			writeErr(w, err)
+			return
+		}
+		indices = append(indices, index)
+	}
+
+	performances := make([][]interface{}, len(indices))
+	for n := uint64(0); n < epochs; n++ {
+		prevSt, err := s.StateGen.StateBySlot(r.Context(), helpers.StartSlot(epoch))
+		if err != nil {
+			writeErr(w, err)
+			return
+		}
+		for i, index := range indices {
+			if index >= uint64(len(vp)) {
+				continue
+			}
+			balance, err := st.BalanceAtIndex(index)
+			if err != nil {
+				writeErr(w, err)
+				return
+			}
+			data := participationJSON(vp[index])
+			data["epoch"] = strconv.FormatUint(epoch, 10)
+			data["balance"] = strconv.FormatUint(balance, 10)
+			if index < uint64(prevSt.NumValidators()) {
+				prevBalance, err := prevSt.BalanceAtIndex(index)
+				if err != nil {
+					writeErr(w, err)
+					return
+				}
+				data["balance_change"] = strconv.FormatInt(int64(balance)-int64(prevBalance), 10)
+			}
+			performances[i] = append(performances[i], data)
+		}
+		if n+1 == epochs {
+			break
+		}
+		epoch--
+		st = prevSt
+		if vp, _, err = participation(r.Context(), st); err != nil {
+			writeErr(w, err)
+			return
+		}
+	}
+
+	validators := make([]interface{}, 0, len(indices))
+	for i, index := range indices {
+		v, err := st.ValidatorAtIndexReadOnly(index)
+		if err != nil {
+			writeErr(w, err)
+			return
+		}
+		pubKey := v.PublicKey()
+		validators = append(validators, map[string]interface{}{
+			"index":  strconv.FormatUint(index, 10),
+			"pubkey": encode(pubKey[:]),
+			"epochs": performances[i],
+		})
+	}
+	writeData(w, validators)
+}
+
+// epochParticipation returns the state at the start of the epoch after the given one, along with
+// the attestation participation during the given epoch.
+func (s *Server) epochParticipation(
+	ctx context.Context, epoch uint64,
+) (*stateTrie.BeaconState, []*precompute.Validator, *precompute.Balance, error) {
+	st, err := s.StateGen.StateBySlot(ctx, helpers.StartSlot(epoch+1))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vp, bp, err := participation(ctx, st)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return st, vp, bp, nil
+}
+
+// participation returns the attestation participation during the epoch before the one of the state.
+func participation(ctx context.Context, st *stateTrie.BeaconState) ([]*precompute.Validator, *precompute.Balance, error) {
+	vp, bp, err := precompute.New(ctx, st)
+	if err != nil {
+		return nil, nil, err
+	}
+	return precompute.ProcessAttestations(ctx, st, vp, bp)
+}
+
+// participationJSON returns the attestation participation of a validator as served by the API.
+func participationJSON(p *precompute.Validator) map[string]interface{} {
+	data := map[string]interface{}{
+		"is_active":       p.IsActivePrevEpoch,
+		"source_attested": p.IsPrevEpochAttester,
+		"target_attested": p.IsPrevEpochTargetAttester,
+		"head_attested":   p.IsPrevEpochHeadAttester,
+	}
+	if p.IsPrevEpochAttester {
+		data["inclusion_distance"] = strconv.FormatUint(p.InclusionDistance, 10)
+	}
+	return data
+}
@@ -0,0 +1,204 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestServer_EpochValidators(t *testing.T) {
+	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{NewStateMgmt: true})
+	defer resetCfg()
+	db := dbTest.SetupDB(t)
+	ctx := context.Background()
+
+	validators := make([]*ethpb.Validator, 4)
+	balances := make([]uint64, len(validators))
+	for i := range validators {
+		validators[i] = &ethpb.Validator{
+			PublicKey:        bytesutil.PadTo([]byte{byte(i)}, 48),
+			ExitEpoch:        params.BeaconConfig().FarFutureEpoch,
+			EffectiveBalance: params.BeaconConfig().MaxEffectiveBalance,
+		}
+		balances[i] = params.BeaconConfig().MaxEffectiveBalance + uint64(i)
+	}
+	st := testutil.NewBeaconState()
+	if err := st.SetSlot(params.BeaconConfig().SlotsPerEpoch); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetValidators(validators); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetBalances(balances); err != nil {
+		t.Fatal(err)
+	}
+	b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: params.BeaconConfig().SlotsPerEpoch}}
+	if err := db.SaveBlock(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(b.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, root); err != nil {
+		t.Fatal(err)
+	}
+
+	slotDuration := time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+	chain := &mock.ChainService{
+		Genesis:             time.Now().Add(-time.Duration(2*params.BeaconConfig().SlotsPerEpoch) * slotDuration),
+		FinalizedCheckPoint: &ethpb.Checkpoint{Epoch: 0},
+	}
+	s := &Server{
+		GenesisTimeFetcher:  chain,
+		FinalizationFetcher: chain,
+		StateGen:            stategen.New(db, cache.NewStateSummaryCache()),
+	}
+
+	rec, resp := serve(t, s, http.MethodGet, "/prysm/v1/archive/epochs/0/validators?id=1,3", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["epoch"] != "0" || data["finalized"] != true {
+		t.Errorf("Unexpected epoch %v and finalized %v", data["epoch"], data["finalized"])
+	}
+	participation := data["participation"].(map[string]interface{})
+	if eligible := strconv.FormatUint(4*params.BeaconConfig().MaxEffectiveBalance, 10); participation["eligible_ether"] != eligible {
+		t.Errorf("Wanted eligible ether %s, received %v", eligible, participation["eligible_ether"])
+	}
+	vals := data["validators"].([]interface{})
+	if len(vals) != 2 {
+		t.Fatalf("Wanted 2 validators, received %v", vals)
+	}
+	for i, index := range []uint64{1, 3} {
+		v := vals[i].(map[string]interface{})
+		if v["index"] != strconv.FormatUint(index, 10) ||
+			v["balance"] != strconv.FormatUint(balances[index], 10) ||
+			v["effective_balance"] != strconv.FormatUint(params.BeaconConfig().MaxEffectiveBalance, 10) ||
+			v["is_active"] != true || v["target_attested"] != false {
+			t.Errorf("Unexpected validator %d: %v", index, v)
+		}
+		if _, ok := v["inclusion_distance"]; ok {
+			t.Errorf("Unexpected inclusion distance of validator %d without attestation", index)
+		}
+	}
+
+	rec, _ = serve(t, s, http.MethodGet, "/prysm/v1/archive/epochs/2/validators", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for the current epoch, received %d", rec.Code)
+	}
+	rec, _ = serve(t, s, http.MethodGet, "/prysm/v1/archive/epochs/0/validators?id=9", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 for an unknown validator, received %d", rec.Code)
+	}
+}
+
+func TestServer_ValidatorPerformance(t *testing.T) {
+	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{NewStateMgmt: true})
+	defer resetCfg()
+	db := dbTest.SetupDB(t)
+	ctx := context.Background()
+
+	validators := make([]*ethpb.Validator, 4)
+	balances := make([]uint64, len(validators))
+	for i := range validators {
+		validators[i] = &ethpb.Validator{
+			PublicKey:        bytesutil.PadTo([]byte{byte(i)}, 48),
+			ExitEpoch:        params.BeaconConfig().FarFutureEpoch,
+			EffectiveBalance: params.BeaconConfig().MaxEffectiveBalance,
+		}
+		balances[i] = params.BeaconConfig().MaxEffectiveBalance
+	}
+	st := testutil.NewBeaconState()
+	if err := st.SetSlot(params.BeaconConfig().SlotsPerEpoch); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetValidators(validators); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetBalances(balances); err != nil {
+		t.Fatal(err)
+	}
+	b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: params.BeaconConfig().SlotsPerEpoch}}
+	if err := db.SaveBlock(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(b.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, root); err != nil {
+		t.Fatal(err)
+	}
+
+	slotDuration := time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+	chain := &mock.ChainService{
+		Genesis: time.Now().Add(-time.Duration(3*params.BeaconConfig().SlotsPerEpoch) * slotDuration),
+	}
+	s := &Server{
+		GenesisTimeFetcher: chain,
+		StateGen:           stategen.New(db, cache.NewStateSummaryCache()),
+	}
+
+	rec, resp := serve(t, s, http.MethodGet, "/prysm/v1/archive/validators/performance?id=2&epochs=2", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	vals := resp["data"].([]interface{})
+	if len(vals) != 1 {
+		t.Fatalf("Wanted 1 validator, received %v", vals)
+	}
+	v := vals[0].(map[string]interface{})
+	if v["index"] != "2" {
+		t.Errorf("Unexpected validator %v", v)
+	}
+	epochs := v["epochs"].([]interface{})
+	if len(epochs) != 2 {
+		t.Fatalf("Wanted 2 epochs, received %v", epochs)
+	}
+	for i, epoch := range []uint64{2, 1} {
+		e := epochs[i].(map[string]interface{})
+		before, err := s.StateGen.StateBySlot(ctx, helpers.StartSlot(epoch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, err := s.StateGen.StateBySlot(ctx, helpers.StartSlot(epoch+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		change := int64(after.Balances()[2]) - int64(before.Balances()[2])
+		if change >= 0 {
+			t.Errorf("Expected a penalty for missing attestations at epoch %d, balance change %d", epoch, change)
+		}
+		if e["epoch"] != strconv.FormatUint(epoch, 10) ||
+			e["balance"] != strconv.FormatUint(after.Balances()[2], 10) ||
+			e["balance_change"] != strconv.FormatInt(change, 10) ||
+			e["target_attested"] != false {
+			t.Errorf("Unexpected performance at epoch %d: %v", epoch, e)
+		}
+	}
+
+	rec, _ = serve(t, s, http.MethodGet, "/prysm/v1/archive/validators/performance", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 without validator ids, received %d", rec.Code)
+	}
+	rec, _ = serve(t, s, http.MethodGet, "/prysm/v1/archive/validators/performance?id=1&epochs=33", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for too many epochs, received %d", rec.Code)
+	}
+}
@@ -0,0 +1,551 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func (s *Server) genesis(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	genesis, err := s.NodeServer.GetGenesis(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	if genesis.GenesisTime == nil || genesis.GenesisTime.Seconds == 0 {
+		writeError(w, http.StatusNotFound, "chain genesis info is not yet known")
+		return
+	}
+	writeData(w, map[string]interface{}{
+		"genesis_time":            strconv.FormatInt(genesis.GenesisTime.Seconds, 10),
+		"genesis_validators_root": encode(genesis.GenesisValidatorsRoot),
+		"genesis_fork_version":    encode(params.BeaconConfig().GenesisForkVersion),
+	})
+}
+
+func (s *Server) stateRoot(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	st, err := s.stateByID(r.Context(), vars["state_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	root, err := st.HashTreeRoot(r.Context())
+	if err != nil {
+		writeErr(w, errors.Wrap(err, "could not compute state root"))
+		return
+	}
+	writeData(w, map[string]interface{}{"root": encode(root[:])})
+}
+
+func (s *Server) stateFork(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	st, err := s.stateByID(r.Context(), vars["state_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, encode(st.Fork()))
+}
+
+func (s *Server) finalityCheckpoints(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	st, err := s.stateByID(r.Context(), vars["state_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, map[string]interface{}{
+		"previous_justified": encode(st.PreviousJustifiedCheckpoint()),
+		"current_justified":  encode(st.CurrentJustifiedCheckpoint()),
+		"finalized":          encode(st.FinalizedCheckpoint()),
+	})
+}
+
+// validators serves the validators of the state given by the `id` query parameters, as indices
+// or public keys, and with the statuses given by the `status` query parameters. All validators
+// are served when no ids are given.
+func (s *Server) validators(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	st, err := s.stateByID(r.Context(), vars["state_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	var indices []uint64
+	ids := queryList(r, "id")
+	if len(ids) == 0 {
+		for i := 0; i < st.NumValidators(); i++ {
+			indices = append(indices, uint64(i))
+		}
+	}
+	for _, id := range ids {
+		index, err := validatorIndex(st, id)
+		if err != nil {
+			// Unknown validators are left out of the list.
+			continue
+		}
+		indices = append(indices, index)
+	}
+	statuses := make(map[string]bool)
+	for _, status := range queryList(r, "status") {
+		statuses[status] = true
+	}
+
+	resp := make([]interface{}, 0, len(indices))
+	for _, index := range indices {
+		v, err := validatorJSON(st, index)
+		if err != nil {
+			writeErr(w, err)
+			return
+		}
+		if len(statuses) > 0 && !statuses[v["status"].(string)] {
+			continue
+		}
+		resp = append(resp, v)
+	}
+	writeData(w, resp)
+}
+
+func (s *Server) validator(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	st, err := s.stateByID(r.Context(), vars["state_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	index, err := validatorIndex(st, vars["validator_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	v, err := validatorJSON(st, index)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, v)
+}
+
+func (s *Server) blockHeader(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	blk, root, err := s.blockByID(r.Context(), vars["block_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	bodyRoot, err := stateutil.BlockBodyRoot(blk.Block.Body)
+	if err != nil {
+		writeErr(w, errors.Wrap(err, "could not compute block body root"))
+		return
+	}
+	canonical, err := s.CanonicalFetcher.IsCanonical(r.Context(), root)
+	if err != nil {
+		writeErr(w, errors.Wrap(err, "could not determine if block is canonical"))
+		return
+	}
+	writeData(w, map[string]interface{}{
+		"root":      encode(root[:]),
+		"canonical": canonical,
+		"header": encode(&ethpb.SignedBeaconBlockHeader{
+			Header: &ethpb.BeaconBlockHeader{
+				Slot:          blk.Block.Slot,
+				ProposerIndex: blk.Block.ProposerIndex,
+				ParentRoot:    blk.Block.ParentRoot,
+				StateRoot:     blk.Block.StateRoot,
+				BodyRoot:      bodyRoot[:],
+			},
+			Signature: blk.Signature,
+		}),
+	})
+}
+
+func (s *Server) block(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	blk, _, err := s.blockByID(r.Context(), vars["block_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, encode(blk))
+}
+
+func (s *Server) blockRoot(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	_, root, err := s.blockByID(r.Context(), vars["block_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, map[string]interface{}{"root": encode(root[:])})
+}
+
+func (s *Server) submitBlock(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := decode(body, blk); err != nil || blk.Block == nil || blk.Block.Body == nil {
+		writeErr(w, badRequest("invalid block: %v", err))
+		return
+	}
+	if _, err := s.ValidatorServer.ProposeBlock(r.Context(), blk); err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// poolAttestations serves the aggregated attestations of the pool, optionally filtered by the
+// `slot` and `committee_index` query parameters.
+func (s *Server) poolAttestations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	slot, filterSlot, err := queryUint(r, "slot")
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	committee, filterCommittee, err := queryUint(r, "committee_index")
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	atts, err := s.attestationPool(r.Context())
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	resp := make([]interface{}, 0, len(atts))
+	for _, att := range atts {
+		if (filterSlot && att.Data.Slot != slot) || (filterCommittee && att.Data.CommitteeIndex != committee) {
+			continue
+		}
+		resp = append(resp, encode(att))
+	}
+	writeData(w, resp)
+}
+
+func (s *Server) submitAttestations(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitAll(w, r, func(ctx context.Context, item interface{}) error {
+		att := &ethpb.Attestation{}
+		if err := decode(item, att); err != nil || att.Data == nil {
+			return errors.Errorf("invalid attestation: %v", err)
+		}
+		_, err := s.ValidatorServer.ProposeAttestation(ctx, att)
+		return err
+	})
+}
+
+func (s *Server) submitVoluntaryExit(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	exit := &ethpb.SignedVoluntaryExit{}
+	if err := decode(body, exit); err != nil || exit.Exit == nil {
+		writeErr(w, badRequest("invalid voluntary exit: %v", err))
+		return
+	}
+	if _, err := s.ValidatorServer.ProposeExit(r.Context(), exit); err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) submitProposerSlashing(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	slashing := &ethpb.ProposerSlashing{}
+	if err := decode(body, slashing); err != nil || slashing.Header_1 == nil || slashing.Header_2 == nil {
+		writeErr(w, badRequest("invalid proposer slashing: %v", err))
+		return
+	}
+	if _, err := s.BeaconChainServer.SubmitProposerSlashing(r.Context(), slashing); err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) submitAttesterSlashing(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	slashing := &ethpb.AttesterSlashing{}
+	if err := decode(body, slashing); err != nil || slashing.Attestation_1 == nil || slashing.Attestation_2 == nil {
+		writeErr(w, badRequest("invalid attester slashing: %v", err))
+		return
+	}
+	if _, err := s.BeaconChainServer.SubmitAttesterSlashing(r.Context(), slashing); err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// attestationPool pages through the aggregated attestations of the pool.
+func (s *Server) attestationPool(ctx context.Context) ([]*ethpb.Attestation, error) {
+	var atts []*ethpb.Attestation
+	req := &ethpb.AttestationPoolRequest{PageSize: int32(flags.Get().MaxPageSize)}
+	for {
+		resp, err := s.BeaconChainServer.AttestationPool(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		atts = append(atts, resp.Attestations...)
+		if len(atts) >= int(resp.TotalSize) || len(resp.Attestations) == 0 {
+			return atts, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// stateByID returns the state given by a state id of the API: head, genesis, finalized, justified,
+// a slot or a 0x prefixed state root. Only the state roots of the head and checkpoint states are
+// known without replaying states, so other roots are not found.
+func (s *Server) stateByID(ctx context.Context, stateID string) (*stateTrie.BeaconState, error) {
+	switch stateID {
+	case "head":
+		return s.HeadFetcher.HeadState(ctx)
+	case "genesis":
+		st, err := s.BeaconDB.GenesisState(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get genesis state")
+		}
+		if st == nil {
+			return nil, notFound("genesis state not found")
+		}
+		return st, nil
+	case "finalized":
+		return s.stateByBlockRoot(ctx, s.FinalizationFetcher.FinalizedCheckpt().Root)
+	case "justified":
+		return s.stateByBlockRoot(ctx, s.FinalizationFetcher.CurrentJustifiedCheckpt().Root)
+	}
+
+	if strings.HasPrefix(stateID, "0x") {
+		root, err := hex.DecodeString(stateID[2:])
+		if err != nil || len(root) != 32 {
+			return nil, badRequest("invalid state id %q", stateID)
+		}
+		return s.stateByStateRoot(ctx, root)
+	}
+	slot, err := strconv.ParseUint(stateID, 10, 64)
+	if err != nil {
+		return nil, badRequest("invalid state id %q", stateID)
+	}
+	if slot > s.GenesisTimeFetcher.CurrentSlot() {
+		return nil, badRequest("slot %d is in the future", slot)
+	}
+	if slot == s.HeadFetcher.HeadSlot() {
+		return s.HeadFetcher.HeadState(ctx)
+	}
+	if !featureconfig.Get().NewStateMgmt {
+		return nil, notFound("states by slot are only available with new state management")
+	}
+	return s.StateGen.StateBySlot(ctx, slot)
+}
+
+func (s *Server) stateByBlockRoot(ctx context.Context, root []byte) (*stateTrie.BeaconState, error) {
+	var st *stateTrie.BeaconState
+	var err error
+	if featureconfig.Get().NewStateMgmt {
+		st, err = s.StateGen.StateByRoot(ctx, bytesutil.ToBytes32(root))
+	} else {
+		st, err = s.BeaconDB.State(ctx, bytesutil.ToBytes32(root))
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get state")
+	}
+	if st == nil {
+		return nil, notFound("state of block %#x not found", root)
+	}
+	return st, nil
+}
+
+// stateByStateRoot returns the head, finalized or justified state with the given root.
+func (s *Server) stateByStateRoot(ctx context.Context, root []byte) (*stateTrie.BeaconState, error) {
+	head, err := s.HeadFetcher.HeadBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head block")
+	}
+	if head != nil && head.Block != nil && bytes.Equal(head.Block.StateRoot, root) {
+		return s.HeadFetcher.HeadState(ctx)
+	}
+	for _, cp := range []*ethpb.Checkpoint{
+		s.FinalizationFetcher.FinalizedCheckpt(),
+		s.FinalizationFetcher.CurrentJustifiedCheckpt(),
+	} {
+		blk, err := s.BeaconDB.Block(ctx, bytesutil.ToBytes32(cp.Root))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get checkpoint block")
+		}
+		if blk != nil && blk.Block != nil && bytes.Equal(blk.Block.StateRoot, root) {
+			return s.stateByBlockRoot(ctx, cp.Root)
+		}
+	}
+	return nil, notFound("state %#x not found", root)
+}
+
+// blockByID returns the block and block root given by a block id of the API: head, genesis,
+// finalized, a slot or a 0x prefixed block root. The canonical block is returned for a slot.
+func (s *Server) blockByID(ctx context.Context, blockID string) (*ethpb.SignedBeaconBlock, [32]byte, error) {
+	req := &ethpb.ListBlocksRequest{}
+	switch blockID {
+	case "head":
+		root, err := s.HeadFetcher.HeadRoot(ctx)
+		if err != nil {
+			return nil, [32]byte{}, errors.Wrap(err, "could not get head root")
+		}
+		req.QueryFilter = &ethpb.ListBlocksRequest_Root{Root: root}
+	case "genesis":
+		req.QueryFilter = &ethpb.ListBlocksRequest_Genesis{Genesis: true}
+	case "finalized":
+		req.QueryFilter = &ethpb.ListBlocksRequest_Root{Root: s.FinalizationFetcher.FinalizedCheckpt().Root}
+	default:
+		if strings.HasPrefix(blockID, "0x") {
+			root, err := hex.DecodeString(blockID[2:])
+			if err != nil || len(root) != 32 {
+				return nil, [32]byte{}, badRequest("invalid block id %q", blockID)
+			}
+			req.QueryFilter = &ethpb.ListBlocksRequest_Root{Root: root}
+			break
+		}
+		slot, err := strconv.ParseUint(blockID, 10, 64)
+		if err != nil {
+			return nil, [32]byte{}, badRequest("invalid block id %q", blockID)
+		}
+		req.QueryFilter = &ethpb.ListBlocksRequest_Slot{Slot: slot}
+	}
+
+	resp, err := s.BeaconChainServer.ListBlocks(ctx, req)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	for _, c := range resp.BlockContainers {
+		if c.Block == nil || c.Block.Block == nil {
+			continue
+		}
+		root := bytesutil.ToBytes32(c.BlockRoot)
+		if len(resp.BlockContainers) > 1 {
+			canonical, err := s.CanonicalFetcher.IsCanonical(ctx, root)
+			if err != nil {
+				return nil, [32]byte{}, errors.Wrap(err, "could not determine if block is canonical")
+			}
+			if !canonical {
+				continue
+			}
+		}
+		return c.Block, root, nil
+	}
+	return nil, [32]byte{}, notFound("block %s not found", blockID)
+}
+
+// validatorIndex returns the index of a validator id of the API, an index or a 0x prefixed
+// public key.
+func validatorIndex(st *stateTrie.BeaconState, id string) (uint64, error) {
+	if strings.HasPrefix(id, "0x") {
+		pubkey, err := hex.DecodeString(id[2:])
+		if err != nil || len(pubkey) != params.BeaconConfig().BLSPubkeyLength {
+			return 0, badRequest("invalid validator id %q", id)
+		}
+		index, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(pubkey))
+		if !ok {
+			return 0, notFound("validator %s not found", id)
+		}
+		return index, nil
+	}
+	index, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, badRequest("invalid validator id %q", id)
+	}
+	if index >= uint64(st.NumValidators()) {
+		return 0, notFound("validator %d not found", index)
+	}
+	return index, nil
+}
+
+func validatorJSON(st *stateTrie.BeaconState, index uint64) (map[string]interface{}, error) {
+	v, err := st.ValidatorAtIndex(index)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get validator")
+	}
+	balance, err := st.BalanceAtIndex(index)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get validator balance")
+	}
+	return map[string]interface{}{
+		"index":     strconv.FormatUint(index, 10),
+		"balance":   strconv.FormatUint(balance, 10),
+		"status":    validatorStatus(v, helpers.CurrentEpoch(st)),
+		"validator": encode(v),
+	}, nil
+}
+
+// validatorStatus returns the status of a validator at an epoch as defined by the API.
+func validatorStatus(v *ethpb.Validator, epoch uint64) string {
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	switch {
+	case v.ActivationEpoch > epoch:
+		if v.ActivationEligibilityEpoch == farFuture {
+			return "pending_initialized"
+		}
+		return "pending_queued"
+	case v.ExitEpoch > epoch:
+		if v.Slashed {
+			return "active_slashed"
+		}
+		if v.ExitEpoch == farFuture {
+			return "active_ongoing"
+		}
+		return "active_exiting"
+	case v.WithdrawableEpoch > epoch:
+		if v.Slashed {
+			return "exited_slashed"
+		}
+		return "exited_unslashed"
+	case v.EffectiveBalance > 0:
+		return "withdrawal_possible"
+	default:
+		return "withdrawal_done"
+	}
+}
+
+// queryList returns the values of a query parameter given repeatedly or comma separated.
+func queryList(r *http.Request, key string) []string {
+	var values []string
+	for _, v := range r.URL.Query()[key] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// queryUint returns the value of an integer query parameter and whether it was given.
+func queryUint(r *http.Request, key string) (uint64, bool, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, false, badRequest("invalid %s %q", key, v)
+	}
+	return n, true, nil
+}
@@ -0,0 +1,239 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/msgtrace"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// attestationTrace serves the path of the attestation of a validator at a slot, from its
+// production through gossip, aggregation and block inclusion, along with its outcome and the
+// reason it was dropped when it missed inclusion. Traces are kept for the last two epochs and
+// require the --enable-attestation-tracing flag.
+func (s *Server) attestationTrace(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	t := tracer.Default()
+	if t == nil {
+		writeError(w, http.StatusNotFound, "attestation tracing is not enabled, use --enable-attestation-tracing")
+		return
+	}
+	index, err := strconv.ParseUint(vars["validator_index"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid validator index %q", vars["validator_index"]))
+		return
+	}
+	slot, err := strconv.ParseUint(vars["slot"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid slot %q", vars["slot"]))
+		return
+	}
+	committeeIndex, position, err := s.committeePosition(r, index, slot)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	tr := t.Trace(slot, committeeIndex, position)
+	status, reason := tr.Status()
+	events := make([]interface{}, 0, len(tr.Events))
+	for _, e := range tr.Events {
+		event := map[string]interface{}{
+			"stage": e.Stage,
+			"time":  e.Time.UTC().Format(time.RFC3339Nano),
+		}
+		switch {
+		case e.Stage == tracer.Included:
+			event["block_slot"] = strconv.FormatUint(e.Slot, 10)
+			event["block_root"] = fmt.Sprintf("%#x", e.BlockRoot)
+		case e.Reason == tracer.PackingLoss:
+			event["block_slot"] = strconv.FormatUint(e.Slot, 10)
+		}
+		if e.Reason != "" {
+			event["reason"] = e.Reason
+		}
+		events = append(events, event)
+	}
+	data := map[string]interface{}{
+		"validator_index":    strconv.FormatUint(index, 10),
+		"slot":               strconv.FormatUint(slot, 10),
+		"committee_index":    strconv.FormatUint(committeeIndex, 10),
+		"committee_position": strconv.FormatUint(position, 10),
+		"status":             status,
+		"events":             events,
+	}
+	if reason != "" {
+		data["reason"] = reason
+	}
+	if inclusion := tr.Inclusion(); inclusion != nil {
+		data["inclusion_slot"] = strconv.FormatUint(inclusion.Slot, 10)
+		data["inclusion_block_root"] = fmt.Sprintf("%#x", inclusion.BlockRoot)
+	}
+	writeData(w, data)
+}
+
+// p2pMessages serves the latest gossip and req/resp messages exchanged with peers, oldest first,
+// optionally filtered by topic and peer and limited to the latest given number of messages.
+// Messages are kept in a ring buffer and require the --enable-p2p-message-tracing flag.
+func (s *Server) p2pMessages(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	t := msgtrace.Default()
+	if t == nil {
+		writeError(w, http.StatusNotFound, "p2p message tracing is not enabled, use --enable-p2p-message-tracing")
+		return
+	}
+	limit, hasLimit, err := queryUint(r, "limit")
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	topic := r.URL.Query().Get("topic")
+	pid := r.URL.Query().Get("peer")
+
+	messages := make([]interface{}, 0)
+	for _, m := range t.Messages() {
+		if (topic != "" && m.Topic != topic) || (pid != "" && m.Peer != pid) {
+			continue
+		}
+		message := map[string]interface{}{
+			"time":      m.Time.UTC().Format(time.RFC3339Nano),
+			"direction": m.Direction,
+			"protocol":  m.Protocol,
+			"topic":     m.Topic,
+			"size":      strconv.Itoa(m.Size),
+			"result":    m.Result,
+		}
+		if m.Peer != "" {
+			message["peer"] = m.Peer
+		}
+		if m.Type != "" {
+			message["type"] = m.Type
+		}
+		messages = append(messages, message)
+	}
+	if hasLimit && uint64(len(messages)) > limit {
+		messages = messages[uint64(len(messages))-limit:]
+	}
+	writeData(w, messages)
+}
+
+// committeePosition returns the committee of the validator at the slot and its position in the
+// committee, according to the head state.
+func (s *Server) committeePosition(r *http.Request, index uint64, slot uint64) (uint64, uint64, error) {
+	st, err := s.HeadFetcher.HeadState(r.Context())
+	if err != nil {
+		return 0, 0, err
+	}
+	if st == nil {
+		return 0, 0, notFound("head state not found")
+	}
+	epoch := helpers.SlotToEpoch(slot)
+	if epoch > helpers.NextEpoch(st) {
+		return 0, 0, badRequest("slot %d is too far ahead of the head slot %d", slot, st.Slot())
+	}
+	activeCount, err := helpers.ActiveValidatorCount(st, epoch)
+	if err != nil {
+		return 0, 0, err
+	}
+	for committeeIndex := uint64(0); committeeIndex < helpers.SlotCommitteeCount(activeCount); committeeIndex++ {
+		committee, err := helpers.BeaconCommitteeFromState(st, slot, committeeIndex)
+		if err != nil {
+			return 0, 0, err
+		}
+		for position, validatorIndex := range committee {
+			if validatorIndex == index {
+				return committeeIndex, uint64(position), nil
+			}
+		}
+	}
+	return 0, 0, notFound("validator %d is not assigned to attest at slot %d", index, slot)
+}
+
+// maxReplaySkipEpochs is the number of epochs a replayed block may be ahead of its parent. The
+// empty slots up to the block are processed before the block, so the distance bounds the work
+// done for a request.
+const maxReplaySkipEpochs = 4
+
+// replayStoredBlock serves the replayed state transition of a stored block, see replayBlock.
+func (s *Server) replayStoredBlock(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	blk, _, err := s.blockByID(r.Context(), vars["block_id"])
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	s.replayBlock(w, r, blk)
+}
+
+// replaySubmittedBlock serves the replayed state transition of the signed block of the request
+// body, so that blocks rejected by the node, which are not stored, can be examined as well.
+func (s *Server) replaySubmittedBlock(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := decode(body, blk); err != nil || blk.Block == nil || blk.Block.Body == nil {
+		writeErr(w, badRequest("invalid block: %v", err))
+		return
+	}
+	s.replayBlock(w, r, blk)
+}
+
+// replayBlock reruns the state transition of the block against the state of its parent and
+// serves the result of each step: which of the operations failed and why, and the balance
+// changes caused by each step. The block is valid when every step succeeded.
+func (s *Server) replayBlock(w http.ResponseWriter, r *http.Request, blk *ethpb.SignedBeaconBlock) {
+	parentState, err := s.stateByBlockRoot(r.Context(), blk.Block.ParentRoot)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	if maxSlot := parentState.Slot() + maxReplaySkipEpochs*params.BeaconConfig().SlotsPerEpoch; blk.Block.Slot > maxSlot {
+		writeErr(w, badRequest("block slot %d is more than %d epochs after its parent state at slot %d",
+			blk.Block.Slot, maxReplaySkipEpochs, parentState.Slot()))
+		return
+	}
+	results, _, err := state.ReplayBlock(r.Context(), parentState, blk)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+
+	valid := true
+	steps := make([]interface{}, 0, len(results))
+	for _, res := range results {
+		deltas := make(map[string]string, len(res.BalanceDeltas))
+		for index, delta := range res.BalanceDeltas {
+			deltas[strconv.FormatUint(index, 10)] = strconv.FormatInt(delta, 10)
+		}
+		step := map[string]interface{}{
+			"step":           res.Step,
+			"index":          strconv.Itoa(res.Index),
+			"balance_deltas": deltas,
+		}
+		if res.Err != nil {
+			valid = false
+			step["error"] = res.Err.Error()
+		}
+		steps = append(steps, step)
+	}
+	writeData(w, map[string]interface{}{
+		"block_root":  fmt.Sprintf("%#x", root),
+		"slot":        strconv.FormatUint(blk.Block.Slot, 10),
+		"parent_root": fmt.Sprintf("%#x", blk.Block.ParentRoot),
+		"valid":       valid,
+		"steps":       steps,
+	})
+}
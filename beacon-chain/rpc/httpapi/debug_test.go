@@ -0,0 +1,196 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/msgtrace"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestServer_AttestationTrace(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 64)
+	chain := &mock.ChainService{State: st}
+	s := &Server{HeadFetcher: chain, GenesisTimeFetcher: chain}
+
+	rec, _ := serve(t, s, http.MethodGet, "/prysm/v1/debug/attestations/1/1", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 with tracing disabled, received %d", rec.Code)
+	}
+
+	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{EnableAttestationTracing: true})
+	defer resetCfg()
+
+	const slot = 1
+	committee, err := helpers.BeaconCommitteeFromState(st, slot, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := bitfield.NewBitlist(uint64(len(committee)))
+	bits.SetBitAt(1, true)
+	att := &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			Slot:            slot,
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		AggregationBits: bits,
+	}
+	tracer.Observe(att, tracer.Received)
+	tracer.Include(slot+1, [32]byte{'a'}, []*ethpb.Attestation{att})
+
+	rec, resp := serve(t, s, http.MethodGet, fmt.Sprintf("/prysm/v1/debug/attestations/%d/%d", committee[1], slot), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["status"] != string(tracer.StatusIncluded) || data["committee_position"] != "1" || data["inclusion_slot"] != "2" {
+		t.Errorf("Unexpected trace %v", data)
+	}
+	if events := data["events"].([]interface{}); len(events) != 2 {
+		t.Errorf("Wanted 2 events, received %d", len(events))
+	}
+
+	rec, resp = serve(t, s, http.MethodGet, fmt.Sprintf("/prysm/v1/debug/attestations/%d/%d", committee[0], slot), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := resp["data"].(map[string]interface{})["status"]; got != string(tracer.StatusNotSeen) {
+		t.Errorf("Wanted status %s, received %v", tracer.StatusNotSeen, got)
+	}
+
+	rec, _ = serve(t, s, http.MethodGet, "/prysm/v1/debug/attestations/64/1", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 for a validator without assignment, received %d", rec.Code)
+	}
+	rec, _ = serve(t, s, http.MethodGet, "/prysm/v1/debug/attestations/1/1000", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for a slot ahead of the head, received %d", rec.Code)
+	}
+}
+
+func TestServer_P2PMessages(t *testing.T) {
+	s := &Server{}
+	rec, _ := serve(t, s, http.MethodGet, "/prysm/v1/debug/p2p/messages", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 with tracing disabled, received %d", rec.Code)
+	}
+
+	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{EnableP2PMessageTracing: true})
+	defer resetCfg()
+
+	msgtrace.Record(msgtrace.Inbound, msgtrace.Gossip, "/eth2/beacon_block", "", 100, &ethpb.SignedBeaconBlock{}, "accept")
+	msgtrace.Record(msgtrace.Outbound, msgtrace.RPC, "/eth2/status", "", 84, nil, "ok")
+	msgtrace.Record(msgtrace.Inbound, msgtrace.Gossip, "/eth2/beacon_block", "", 200, nil, "reject")
+
+	rec, resp := serve(t, s, http.MethodGet, "/prysm/v1/debug/p2p/messages?topic=/eth2/beacon_block&limit=1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	messages := resp["data"].([]interface{})
+	if len(messages) != 1 {
+		t.Fatalf("Wanted 1 message, received %d", len(messages))
+	}
+	if m := messages[0].(map[string]interface{}); m["size"] != "200" || m["result"] != "reject" {
+		t.Errorf("Unexpected message %v", m)
+	}
+}
+
+func TestServer_ReplayBlock(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	ctx := context.Background()
+	st, privKeys := testutil.DeterministicGenesisState(t, 64)
+	blk, err := testutil.GenerateFullBlock(st, privKeys, testutil.DefaultBlockGenConfig(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, bytesutil.ToBytes32(blk.Block.ParentRoot)); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcs := &fakeBeaconChainServer{blocks: []*ethpb.BeaconBlockContainer{{Block: blk, BlockRoot: root[:]}}}
+	s := &Server{BeaconDB: db, BeaconChainServer: bcs}
+
+	rec, _ := serve(t, s, http.MethodGet, fmt.Sprintf("/prysm/v1/debug/blocks/%#x/replay", root), "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 without debug routes enabled, received %d", rec.Code)
+	}
+	s.EnableDebugRoutes = true
+	rec, resp := serve(t, s, http.MethodGet, fmt.Sprintf("/prysm/v1/debug/blocks/%#x/replay", root), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["valid"] != true {
+		t.Errorf("Wanted valid block, received %v", data)
+	}
+
+	// A block with an invalid attestation, which the node would have rejected.
+	blk.Block.Body.Attestations[0].Signature = privKeys[0].Sign([]byte("not the attestation")).Marshal()
+	body, err := json.Marshal(encode(blk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, resp = serve(t, s, http.MethodPost, "/prysm/v1/debug/blocks/replay", string(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	data = resp["data"].(map[string]interface{})
+	if data["valid"] != false {
+		t.Errorf("Wanted invalid block, received %v", data)
+	}
+	var failed []string
+	for _, step := range data["steps"].([]interface{}) {
+		step := step.(map[string]interface{})
+		if step["error"] != nil {
+			failed = append(failed, fmt.Sprintf("%s %s", step["step"], step["index"]))
+		}
+	}
+	// The block header fails on the block signature, which covers the changed attestation.
+	wanted := []string{"block_header 0", "attestation 0", "state_root 0"}
+	if !reflect.DeepEqual(failed, wanted) {
+		t.Errorf("Wanted failed steps %v, received %v", wanted, failed)
+	}
+
+	rec, _ = serve(t, s, http.MethodPost, "/prysm/v1/debug/blocks/replay", "{}")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for an empty block, received %d", rec.Code)
+	}
+
+	// Replaying a block far ahead of its parent would process every empty slot in between.
+	blk.Block.Slot = 1 << 40
+	body, err = json.Marshal(encode(blk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, _ = serve(t, s, http.MethodPost, "/prysm/v1/debug/blocks/replay", string(body))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for a block far ahead of its parent, received %d", rec.Code)
+	}
+
+	s.AuthToken = "secret"
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/prysm/v1/debug/blocks/%#x/replay", root), nil)
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Wanted 401 without the auth token, received %d", rec.Code)
+	}
+}
@@ -0,0 +1,174 @@
+package httpapi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// specNames renames the fields of messages whose protobuf names differ from the names of the
+// Eth2 API spec, keyed by message type and Go field name. Fields tagged with a spec-name are
+// renamed by their tag.
+var specNames = map[string]string{
+	"AttestationData.CommitteeIndex": "index",
+	"ProposerSlashing.Header_1":      "signed_header_1",
+	"ProposerSlashing.Header_2":      "signed_header_2",
+	"SignedBeaconBlock.Block":        "message",
+	"SignedBeaconBlockHeader.Header": "message",
+	"SignedVoluntaryExit.Exit":       "message",
+}
+
+// encode converts a protobuf message to its Eth2 API spec JSON value, in which byte slices and
+// bitfields are 0x prefixed hex strings and integers are decimal strings.
+func encode(msg interface{}) interface{} {
+	return encodeValue(reflect.ValueOf(msg))
+}
+
+func encodeValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]interface{})
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if name, ok := specName(t, t.Field(i)); ok {
+				out[name] = encodeValue(v.Field(i))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return "0x" + hex.EncodeToString(v.Bytes())
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = encodeValue(v.Index(i))
+		}
+		return out
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Int64, reflect.Int32:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	}
+	return nil
+}
+
+// decode sets the protobuf message msg points to from its Eth2 API spec JSON value, as parsed
+// by encoding/json into interface{}.
+func decode(data interface{}, msg interface{}) error {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("decode target must be a non-nil pointer")
+	}
+	return decodeValue(data, v.Elem())
+}
+
+func decodeValue(data interface{}, v reflect.Value) error {
+	if data == nil {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(data, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Struct:
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object, received %T", data)
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := specName(t, t.Field(i))
+			if !ok {
+				continue
+			}
+			if err := decodeValue(obj[name], v.Field(i)); err != nil {
+				return errors.Wrap(err, name)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := data.(string)
+			if !ok || !strings.HasPrefix(s, "0x") {
+				return fmt.Errorf("expected 0x prefixed hex string, received %v", data)
+			}
+			b, err := hex.DecodeString(s[2:])
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		arr, ok := data.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected array, received %T", data)
+		}
+		out := reflect.MakeSlice(v.Type(), len(arr), len(arr))
+		for i, item := range arr {
+			if err := decodeValue(item, out.Index(i)); err != nil {
+				return errors.Wrapf(err, "%d", i)
+			}
+		}
+		v.Set(out)
+		return nil
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		s, ok := data.(string)
+		if !ok {
+			return fmt.Errorf("expected decimal string, received %v", data)
+		}
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Bool:
+		b, ok := data.(bool)
+		if !ok {
+			return fmt.Errorf("expected boolean, received %v", data)
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.String:
+		s, ok := data.(string)
+		if !ok {
+			return fmt.Errorf("expected string, received %v", data)
+		}
+		v.SetString(s)
+		return nil
+	}
+	return fmt.Errorf("unsupported field type %s", v.Type())
+}
+
+// specName returns the Eth2 API spec name of a message field, or false for protobuf internal
+// fields.
+func specName(t reflect.Type, f reflect.StructField) (string, bool) {
+	if strings.HasPrefix(f.Name, "XXX_") {
+		return "", false
+	}
+	if name, ok := specNames[t.Name()+"."+f.Name]; ok {
+		return name, true
+	}
+	if name := f.Tag.Get("spec-name"); name != "" {
+		return name, true
+	}
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	return name, name != "" && name != "-"
+}
@@ -0,0 +1,137 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestEncode_SpecNames(t *testing.T) {
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 5
+	blk.Block.Body.Attestations = []*ethpb.Attestation{{
+		AggregationBits: bitfield.Bitlist{0b1101},
+		Data: &ethpb.AttestationData{
+			CommitteeIndex:  3,
+			BeaconBlockRoot: []byte{0xab, 0xcd},
+			Source:          &ethpb.Checkpoint{},
+			Target:          &ethpb.Checkpoint{Epoch: 1},
+		},
+	}}
+	blk.Block.Body.ProposerSlashings = []*ethpb.ProposerSlashing{{
+		Header_1: &ethpb.SignedBeaconBlockHeader{Header: &ethpb.BeaconBlockHeader{Slot: 1}},
+	}}
+
+	enc, err := json.Marshal(encode(blk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(enc, &got); err != nil {
+		t.Fatal(err)
+	}
+	message := got["message"].(map[string]interface{})
+	if message["slot"] != "5" {
+		t.Errorf("Wanted slot \"5\", received %v", message["slot"])
+	}
+	body := message["body"].(map[string]interface{})
+	att := body["attestations"].([]interface{})[0].(map[string]interface{})
+	if att["aggregation_bits"] != "0x0d" {
+		t.Errorf("Wanted aggregation bits 0x0d, received %v", att["aggregation_bits"])
+	}
+	data := att["data"].(map[string]interface{})
+	if data["index"] != "3" || data["beacon_block_root"] != "0xabcd" {
+		t.Errorf("Unexpected attestation data %v", data)
+	}
+	slashing := body["proposer_slashings"].([]interface{})[0].(map[string]interface{})
+	header := slashing["signed_header_1"].(map[string]interface{})["message"].(map[string]interface{})
+	if header["slot"] != "1" {
+		t.Errorf("Unexpected proposer slashing %v", slashing)
+	}
+	if _, ok := message["XXX_sizecache"]; ok {
+		t.Error("Expected protobuf internal fields to be left out")
+	}
+
+	v := encode(&ethpb.Validator{PublicKey: []byte{1}}).(map[string]interface{})
+	if v["pubkey"] != "0x01" {
+		t.Errorf("Wanted pubkey 0x01, received %v", v["pubkey"])
+	}
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 10
+	blk.Block.ProposerIndex = 7
+	blk.Block.Body.Attestations = []*ethpb.Attestation{{
+		AggregationBits: bitfield.Bitlist{0b101},
+		Data: &ethpb.AttestationData{
+			Slot:            9,
+			CommitteeIndex:  2,
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+		},
+		Signature: make([]byte, 96),
+	}}
+	blk.Block.Body.Deposits = []*ethpb.Deposit{{
+		Proof: [][]byte{{1, 2}, {3}},
+		Data:  &ethpb.Deposit_Data{PublicKey: make([]byte, 48), Amount: 32000000000},
+	}}
+
+	enc, err := json.Marshal(encode(blk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(enc, &data); err != nil {
+		t.Fatal(err)
+	}
+	decoded := &ethpb.SignedBeaconBlock{}
+	if err := decode(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	reenc, err := json.Marshal(encode(decoded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(reenc) != string(enc) {
+		t.Errorf("Wanted %s, received %s", enc, reenc)
+	}
+	if decoded.Block.Body.Attestations[0].Data.CommitteeIndex != 2 {
+		t.Errorf("Wanted committee index 2, received %d", decoded.Block.Body.Attestations[0].Data.CommitteeIndex)
+	}
+	if !reflect.DeepEqual(decoded.Block.Body.Deposits[0].Proof, blk.Block.Body.Deposits[0].Proof) {
+		t.Errorf("Wanted deposit proof %v, received %v", blk.Block.Body.Deposits[0].Proof, decoded.Block.Body.Deposits[0].Proof)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+	}{
+		{name: "number instead of string", data: map[string]interface{}{"slot": 1.0}},
+		{name: "bytes without prefix", data: map[string]interface{}{"beacon_block_root": "abcd"}},
+		{name: "bad hex", data: map[string]interface{}{"beacon_block_root": "0xzz"}},
+		{name: "array instead of object", data: []interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := decode(tt.data, &ethpb.AttestationData{}); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	var indices []uint64
+	if err := decode([]interface{}{"1", "20"}, &indices); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(indices, []uint64{1, 20}) {
+		t.Errorf("Wanted [1 20], received %v", indices)
+	}
+}
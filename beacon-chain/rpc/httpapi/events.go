@@ -0,0 +1,193 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+)
+
+// Topics of the event stream.
+const (
+	headTopic                = "head"
+	finalizedCheckpointTopic = "finalized_checkpoint"
+	chainReorgTopic          = "chain_reorg"
+)
+
+// events streams the chain events of the topics given by the `topics` query parameters as
+// server-sent events. Events are pushed from the state feed of the blockchain service, so
+// clients following the chain do not have to poll the head every slot.
+func (s *Server) events(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	topics := make(map[string]bool)
+	for _, topic := range queryList(r, "topics") {
+		if topic != headTopic && topic != finalizedCheckpointTopic && topic != chainReorgTopic {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid topic %q", topic))
+			return
+		}
+		topics[topic] = true
+	}
+	if len(topics) == 0 {
+		writeError(w, http.StatusBadRequest, "no topics given")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok || s.StateNotifier == nil {
+		writeError(w, http.StatusInternalServerError, "event streaming is not supported")
+		return
+	}
+
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := r.Context()
+	var headRoot [32]byte
+	var headSlot uint64
+	for {
+		select {
+		case ev := <-stateChannel:
+			var topic string
+			var data interface{}
+			switch ev.Type {
+			case statefeed.BlockProcessed:
+				if !topics[headTopic] {
+					continue
+				}
+				blk, root, err := s.headBlock(ctx)
+				if err != nil {
+					log.WithError(err).Error("Could not get head block")
+					continue
+				}
+				if root == headRoot {
+					// The processed block did not change the head.
+					continue
+				}
+				epochTransition := headRoot != [32]byte{} && helpers.SlotToEpoch(blk.Slot) > helpers.SlotToEpoch(headSlot)
+				headRoot, headSlot = root, blk.Slot
+				topic, data = headTopic, map[string]interface{}{
+					"slot":             encode(blk.Slot),
+					"block":            encode(root[:]),
+					"state":            encode(blk.StateRoot),
+					"epoch_transition": epochTransition,
+				}
+			case statefeed.FinalizedCheckpoint:
+				if !topics[finalizedCheckpointTopic] {
+					continue
+				}
+				evData, ok := ev.Data.(*statefeed.FinalizedCheckpointData)
+				if !ok {
+					continue
+				}
+				checkpoint, err := s.finalizedCheckpointEvent(ctx, evData)
+				if err != nil {
+					log.WithError(err).Error("Could not get finalized checkpoint event")
+					continue
+				}
+				topic, data = finalizedCheckpointTopic, checkpoint
+			case statefeed.Reorg:
+				if !topics[chainReorgTopic] {
+					continue
+				}
+				evData, ok := ev.Data.(*statefeed.ReorgData)
+				if !ok {
+					continue
+				}
+				reorg, err := s.chainReorgEvent(ctx, evData)
+				if err != nil {
+					log.WithError(err).Error("Could not get chain reorg event")
+					continue
+				}
+				topic, data = chainReorgTopic, reorg
+			default:
+				continue
+			}
+			if err := writeEvent(w, topic, data); err != nil {
+				log.WithError(err).Debug("Could not write event")
+				return
+			}
+			flusher.Flush()
+		case <-stateSub.Err():
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// headBlock returns the head block of the chain and its root.
+func (s *Server) headBlock(ctx context.Context) (*ethpb.BeaconBlock, [32]byte, error) {
+	blk, err := s.HeadFetcher.HeadBlock(ctx)
+	if err != nil {
+		return nil, [32]byte{}, errors.Wrap(err, "could not get head block")
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, [32]byte{}, errors.New("head block is nil")
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return nil, [32]byte{}, errors.Wrap(err, "could not compute head block root")
+	}
+	return blk.Block, root, nil
+}
+
+// finalizedCheckpointEvent returns the event of the finalized checkpoint, with the state root of
+// the finalized block.
+func (s *Server) finalizedCheckpointEvent(ctx context.Context, data *statefeed.FinalizedCheckpointData) (map[string]interface{}, error) {
+	blk, err := s.BeaconDB.Block(ctx, data.BlockRoot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get finalized block")
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, fmt.Errorf("finalized block %#x not found", data.BlockRoot)
+	}
+	return map[string]interface{}{
+		"block": encode(data.BlockRoot[:]),
+		"state": encode(blk.Block.StateRoot),
+		"epoch": encode(data.Epoch),
+	}, nil
+}
+
+// chainReorgEvent returns the event of a reorg, with the state roots of the old and new head blocks.
+func (s *Server) chainReorgEvent(ctx context.Context, data *statefeed.ReorgData) (map[string]interface{}, error) {
+	stateRoots := make([][]byte, 2)
+	for i, root := range [][32]byte{data.OldHeadRoot, data.NewHeadRoot} {
+		blk, err := s.BeaconDB.Block(ctx, root)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get head block")
+		}
+		if blk == nil || blk.Block == nil {
+			return nil, fmt.Errorf("head block %#x not found", root)
+		}
+		stateRoots[i] = blk.Block.StateRoot
+	}
+	return map[string]interface{}{
+		"slot":           encode(data.NewSlot),
+		"depth":          encode(data.Depth),
+		"old_head_block": encode(data.OldHeadRoot[:]),
+		"new_head_block": encode(data.NewHeadRoot[:]),
+		"old_head_state": encode(stateRoots[0]),
+		"new_head_state": encode(stateRoots[1]),
+		"epoch":          encode(helpers.SlotToEpoch(data.NewSlot)),
+	}, nil
+}
+
+// writeEvent writes a server-sent event of the topic with the JSON data.
+func writeEvent(w http.ResponseWriter, topic string, data interface{}) error {
+	enc, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", topic, enc)
+	return err
+}
@@ -0,0 +1,189 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func (s *Server) version(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	version, err := s.NodeServer.GetVersion(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, map[string]interface{}{"version": version.Version})
+}
+
+// versionMetadata serves the metadata of the version of the node: its build, chain config, fork
+// version and identity.
+func (s *Server) versionMetadata(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	version, err := s.NodeServer.GetVersion(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, json.RawMessage(version.Metadata))
+}
+
+func (s *Server) identity(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	peerID := s.IdentityFetcher.PeerID().Pretty()
+	p2pAddresses := make([]string, 0)
+	for _, addr := range s.IdentityFetcher.ListeningAddrs() {
+		p2pAddresses = append(p2pAddresses, fmt.Sprintf("%s/p2p/%s", addr.String(), peerID))
+	}
+	// The ENR and discovery addresses are empty when discovery is disabled.
+	var enr string
+	discoveryAddresses := make([]string, 0)
+	if node := s.IdentityFetcher.ENR(); node != nil {
+		enr = node.String()
+		if ip := node.IP(); ip != nil && node.UDP() != 0 {
+			protocol := "ip4"
+			if ip.To4() == nil {
+				protocol = "ip6"
+			}
+			discoveryAddresses = append(discoveryAddresses, fmt.Sprintf("/%s/%s/udp/%d/p2p/%s", protocol, ip, node.UDP(), peerID))
+		}
+	}
+	metadata := s.MetadataFetcher.Metadata()
+	writeData(w, map[string]interface{}{
+		"peer_id":             peerID,
+		"enr":                 enr,
+		"p2p_addresses":       p2pAddresses,
+		"discovery_addresses": discoveryAddresses,
+		"metadata": map[string]interface{}{
+			"seq_number": strconv.FormatUint(metadata.SeqNumber, 10),
+			"attnets":    fmt.Sprintf("%#x", []byte(metadata.Attnets)),
+		},
+	})
+}
+
+func (s *Server) syncing(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	headSlot := s.HeadFetcher.HeadSlot()
+	var distance uint64
+	if currentSlot := s.GenesisTimeFetcher.CurrentSlot(); currentSlot > headSlot {
+		distance = currentSlot - headSlot
+	}
+	writeData(w, map[string]interface{}{
+		"head_slot":     strconv.FormatUint(headSlot, 10),
+		"sync_distance": strconv.FormatUint(distance, 10),
+	})
+}
+
+// health responds with 200 when the node is synced and 206 while it is syncing.
+func (s *Server) health(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	syncStatus, err := s.NodeServer.GetSyncStatus(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	if syncStatus.Syncing {
+		w.WriteHeader(http.StatusPartialContent)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) peers(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	peers, err := s.NodeServer.ListPeers(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	resp := make([]interface{}, 0, len(peers.Peers))
+	for _, p := range peers.Peers {
+		// The address of a peer ends with its id, as in /ip4/127.0.0.1/tcp/13000/p2p/16Uiu2...
+		address, id := p.Address, ""
+		if i := strings.LastIndex(p.Address, "/p2p/"); i >= 0 {
+			address, id = p.Address[:i], p.Address[i+len("/p2p/"):]
+		}
+		resp = append(resp, map[string]interface{}{
+			"peer_id":               id,
+			"last_seen_p2p_address": address,
+			"state":                 "connected",
+			"direction":             strings.ToLower(p.Direction.String()),
+		})
+	}
+	writeData(w, resp)
+}
+
+// peerStates are the names of the peer connection states in the peer scores.
+var peerStates = map[peers.PeerConnectionState]string{
+	peers.PeerDisconnected:  "disconnected",
+	peers.PeerConnecting:    "connecting",
+	peers.PeerConnected:     "connected",
+	peers.PeerDisconnecting: "disconnecting",
+}
+
+// peerScores extends the peers of the node with the scores of all known peers, including the
+// disconnected and banned ones, lowest scores first. It is meant for debugging peer scoring.
+func (s *Server) peerScores(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+	if s.PeersFetcher == nil {
+		writeError(w, http.StatusInternalServerError, "peer status is not available")
+		return
+	}
+	status := s.PeersFetcher.Peers()
+	type peerScore struct {
+		id    string
+		score float64
+		data  map[string]interface{}
+	}
+	scores := make([]*peerScore, 0)
+	for _, pid := range status.All() {
+		score, err := status.Score(pid)
+		if err != nil {
+			continue
+		}
+		state, err := status.ConnectionState(pid)
+		if err != nil {
+			continue
+		}
+		badResponses, err := status.BadResponses(pid)
+		if err != nil {
+			continue
+		}
+		timeouts, err := status.RequestTimeouts(pid)
+		if err != nil {
+			continue
+		}
+		penalties, err := status.GossipPenalties(pid)
+		if err != nil {
+			continue
+		}
+		bannedUntil, err := status.BannedUntil(pid)
+		if err != nil {
+			continue
+		}
+		data := map[string]interface{}{
+			"peer_id":          pid.Pretty(),
+			"state":            peerStates[state],
+			"score":            score,
+			"bad_responses":    strconv.Itoa(badResponses),
+			"request_timeouts": strconv.Itoa(timeouts),
+			"gossip_penalties": strconv.Itoa(penalties),
+			"is_bad":           status.IsBad(pid),
+		}
+		if !bannedUntil.IsZero() {
+			data["banned_until"] = bannedUntil.UTC().Format(time.RFC3339)
+		}
+		scores = append(scores, &peerScore{id: pid.Pretty(), score: score, data: data})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score < scores[j].score
+		}
+		return scores[i].id < scores[j].id
+	})
+	resp := make([]interface{}, len(scores))
+	for i, sc := range scores {
+		resp[i] = sc.data
+	}
+	writeData(w, resp)
+}
@@ -0,0 +1,289 @@
+// Package httpapi serves the /eth/v1/beacon, /eth/v1/node, /eth/v1/validator and /eth/v1/events
+// routes of the Eth2 beacon node API over HTTP/JSON, so tooling written against the standard API
+// can talk to a beacon node. Requests are mapped onto the gRPC service implementations. Routes
+// beyond the standard API are served under /prysm/v1.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logrus.WithField("prefix", "httpapi")
+
+// Server serves the Eth2 beacon node API.
+type Server struct {
+	BeaconDB            db.ReadOnlyDatabase
+	HeadFetcher         blockchain.HeadFetcher
+	FinalizationFetcher blockchain.FinalizationFetcher
+	CanonicalFetcher    blockchain.CanonicalFetcher
+	GenesisTimeFetcher  blockchain.TimeFetcher
+	StateGen            *stategen.State
+	StateNotifier       statefeed.Notifier
+	PeersFetcher        p2p.PeersProvider
+	IdentityFetcher     p2p.IdentityProvider
+	MetadataFetcher     p2p.MetadataProvider
+	NodeServer          ethpb.NodeServer
+	BeaconChainServer   ethpb.BeaconChainServer
+	ValidatorServer     ethpb.BeaconNodeValidatorServer
+	// AuthToken is required as bearer token by the routes which change the state of the node
+	// or broadcast to the network, like their gRPC methods. Not required when empty.
+	AuthToken string
+	// EnableDebugRoutes serves the debug routes which run state transitions, like the debug
+	// gRPC endpoints enabled with --enable-debug-rpc-endpoints. They also require AuthToken.
+	EnableDebugRoutes bool
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, vars map[string]string)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+	mutating bool
+	debug    bool
+}
+
+// apiError is the error body of the Eth2 API.
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Handler returns the HTTP handler serving the API routes.
+func (s *Server) Handler() http.Handler {
+	routes := []*route{
+		newRoute(http.MethodGet, "/eth/v1/beacon/genesis", s.genesis),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/root", s.stateRoot),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/fork", s.stateFork),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/finality_checkpoints", s.finalityCheckpoints),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators", s.validators),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators/{validator_id}", s.validator),
+		newRoute(http.MethodGet, "/eth/v1/beacon/headers/{block_id}", s.blockHeader),
+		newRoute(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}", s.block),
+		newRoute(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/root", s.blockRoot),
+		newMutatingRoute(http.MethodPost, "/eth/v1/beacon/blocks", s.submitBlock),
+		newRoute(http.MethodGet, "/eth/v1/beacon/pool/attestations", s.poolAttestations),
+		newMutatingRoute(http.MethodPost, "/eth/v1/beacon/pool/attestations", s.submitAttestations),
+		newMutatingRoute(http.MethodPost, "/eth/v1/beacon/pool/voluntary_exits", s.submitVoluntaryExit),
+		newMutatingRoute(http.MethodPost, "/eth/v1/beacon/pool/proposer_slashings", s.submitProposerSlashing),
+		newMutatingRoute(http.MethodPost, "/eth/v1/beacon/pool/attester_slashings", s.submitAttesterSlashing),
+
+		newRoute(http.MethodGet, "/eth/v1/node/identity", s.identity),
+		newRoute(http.MethodGet, "/eth/v1/node/version", s.version),
+		newRoute(http.MethodGet, "/eth/v1/node/syncing", s.syncing),
+		newRoute(http.MethodGet, "/eth/v1/node/health", s.health),
+		newRoute(http.MethodGet, "/eth/v1/node/peers", s.peers),
+
+		newRoute(http.MethodPost, "/eth/v1/validator/duties/attester/{epoch}", s.attesterDuties),
+		newRoute(http.MethodGet, "/eth/v1/validator/duties/proposer/{epoch}", s.proposerDuties),
+		newRoute(http.MethodGet, "/eth/v1/validator/blocks/{slot}", s.produceBlock),
+		newRoute(http.MethodGet, "/eth/v1/validator/attestation_data", s.attestationData),
+		newRoute(http.MethodGet, "/eth/v1/validator/aggregate_attestation", s.aggregateAttestation),
+		newMutatingRoute(http.MethodPost, "/eth/v1/validator/aggregate_and_proofs", s.submitAggregateAndProofs),
+		newRoute(http.MethodPost, "/eth/v1/validator/liveness/{epoch}", s.liveness),
+		newMutatingRoute(http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", s.beaconCommitteeSubscriptions),
+
+		newRoute(http.MethodGet, "/eth/v1/events", s.events),
+
+		newRoute(http.MethodGet, "/prysm/v1/node/version", s.versionMetadata),
+		newRoute(http.MethodGet, "/prysm/v1/node/peer_scores", s.peerScores),
+		newRoute(http.MethodGet, "/prysm/v1/archive/epochs/{epoch}/validators", s.epochValidators),
+		newRoute(http.MethodGet, "/prysm/v1/archive/validators/performance", s.validatorPerformance),
+		newRoute(http.MethodPost, "/prysm/v1/validator/duties/{epoch}", s.duties),
+		newRoute(http.MethodGet, "/prysm/v1/debug/attestations/{validator_index}/{slot}", s.attestationTrace),
+		newDebugRoute(http.MethodGet, "/prysm/v1/debug/blocks/{block_id}/replay", s.replayStoredBlock),
+		newDebugRoute(http.MethodPost, "/prysm/v1/debug/blocks/replay", s.replaySubmittedBlock),
+		newRoute(http.MethodGet, "/prysm/v1/debug/p2p/messages", s.p2pMessages),
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		segments := splitPath(r.URL.Path)
+		pathFound := false
+		for _, rt := range routes {
+			vars, ok := rt.match(segments)
+			if !ok {
+				continue
+			}
+			pathFound = true
+			if rt.method != r.Method {
+				continue
+			}
+			if rt.debug && !s.EnableDebugRoutes {
+				writeError(w, http.StatusNotFound, "debug routes are not enabled, use --enable-debug-rpc-endpoints")
+				return
+			}
+			if (rt.mutating || rt.debug) && s.AuthToken != "" {
+				rpcauth.RequireToken(s.AuthToken, func(w http.ResponseWriter, r *http.Request) {
+					rt.handler(w, r, vars)
+				})(w, r)
+				return
+			}
+			rt.handler(w, r, vars)
+			return
+		}
+		if pathFound {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeError(w, http.StatusNotFound, "route not found")
+	})
+}
+
+func newRoute(method string, pattern string, handler handlerFunc) *route {
+	return &route{method: method, segments: splitPath(pattern), handler: handler}
+}
+
+func newMutatingRoute(method string, pattern string, handler handlerFunc) *route {
+	rt := newRoute(method, pattern, handler)
+	rt.mutating = true
+	return rt
+}
+
+func newDebugRoute(method string, pattern string, handler handlerFunc) *route {
+	rt := newRoute(method, pattern, handler)
+	rt.debug = true
+	return rt
+}
+
+// match returns the values of the {param} segments of the route if the path matches it.
+func (rt *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	vars := make(map[string]string)
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			vars[seg[1:len(seg)-1]] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return vars, true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// writeData writes the response in the data envelope of the API.
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, &apiError{Code: code, Message: message})
+}
+
+// httpError is an error with the HTTP status it is served with.
+type httpError struct {
+	code    int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &httpError{code: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) error {
+	return &httpError{code: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+// writeErr writes an httpError with its status, or the error of a gRPC service method with the
+// HTTP status matching its status code.
+func writeErr(w http.ResponseWriter, err error) {
+	if e, ok := err.(*httpError); ok {
+		writeError(w, e.code, e.message)
+		return
+	}
+	code := http.StatusInternalServerError
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument, codes.OutOfRange:
+			code = http.StatusBadRequest
+		case codes.NotFound:
+			code = http.StatusNotFound
+		case codes.Unauthenticated:
+			code = http.StatusUnauthorized
+		case codes.Unavailable, codes.FailedPrecondition:
+			code = http.StatusServiceUnavailable
+		}
+		writeError(w, code, st.Message())
+		return
+	}
+	writeError(w, code, err.Error())
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
+
+// readJSON parses the request body into a generic JSON value for decode.
+func readJSON(r *http.Request) (interface{}, error) {
+	var body interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid request body: %v", err)
+	}
+	return body, nil
+}
+
+// submitAll calls submit for each item of a JSON array body, responding with the indices and
+// errors of the failed items.
+func submitAll(w http.ResponseWriter, r *http.Request, submit func(ctx context.Context, item interface{}) error) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	items, ok := body.([]interface{})
+	if !ok {
+		writeError(w, http.StatusBadRequest, "request body must be an array")
+		return
+	}
+	type failure struct {
+		Index   int    `json:"index"`
+		Message string `json:"message"`
+	}
+	var failures []*failure
+	for i, item := range items {
+		if err := submit(r.Context(), item); err != nil {
+			if st, ok := status.FromError(err); ok {
+				err = fmt.Errorf("%s", st.Message())
+			}
+			failures = append(failures, &failure{Index: i, Message: err.Error()})
+		}
+	}
+	if len(failures) > 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"code":     http.StatusBadRequest,
+			"message":  "some items failed to be submitted",
+			"failures": failures,
+		})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
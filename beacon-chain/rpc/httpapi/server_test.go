@@ -0,0 +1,592 @@
+package httpapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/gogo/protobuf/proto"
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	p2pTest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeBeaconChainServer struct {
+	ethpb.BeaconChainServer
+	blocks []*ethpb.BeaconBlockContainer
+	// indexedAtts are the indexed attestations included in blocks, by epoch of the blocks.
+	indexedAtts map[uint64][]*ethpb.IndexedAttestation
+	// proposerSlashings are the submitted proposer slashings.
+	proposerSlashings []*ethpb.ProposerSlashing
+}
+
+func (f *fakeBeaconChainServer) SubmitProposerSlashing(
+	_ context.Context, req *ethpb.ProposerSlashing,
+) (*ethpb.SubmitSlashingResponse, error) {
+	f.proposerSlashings = append(f.proposerSlashings, req)
+	return &ethpb.SubmitSlashingResponse{SlashedIndices: []uint64{req.Header_1.Header.ProposerIndex}}, nil
+}
+
+func (f *fakeBeaconChainServer) ListIndexedAttestations(
+	_ context.Context, req *ethpb.ListIndexedAttestationsRequest,
+) (*ethpb.ListIndexedAttestationsResponse, error) {
+	atts := f.indexedAtts[req.QueryFilter.(*ethpb.ListIndexedAttestationsRequest_Epoch).Epoch]
+	return &ethpb.ListIndexedAttestationsResponse{IndexedAttestations: atts, TotalSize: int32(len(atts))}, nil
+}
+
+func (f *fakeBeaconChainServer) ListBlocks(
+	_ context.Context, req *ethpb.ListBlocksRequest,
+) (*ethpb.ListBlocksResponse, error) {
+	var containers []*ethpb.BeaconBlockContainer
+	for _, c := range f.blocks {
+		switch q := req.QueryFilter.(type) {
+		case *ethpb.ListBlocksRequest_Slot:
+			if c.Block.Block.Slot == q.Slot {
+				containers = append(containers, c)
+			}
+		case *ethpb.ListBlocksRequest_Root:
+			if string(c.BlockRoot) == string(q.Root) {
+				containers = append(containers, c)
+			}
+		case *ethpb.ListBlocksRequest_Epoch:
+			if helpers.SlotToEpoch(c.Block.Block.Slot) == q.Epoch {
+				containers = append(containers, c)
+			}
+		}
+	}
+	return &ethpb.ListBlocksResponse{BlockContainers: containers, TotalSize: int32(len(containers))}, nil
+}
+
+type fakeValidatorServer struct {
+	ethpb.BeaconNodeValidatorServer
+	attestations  []*ethpb.Attestation
+	duties        *ethpb.DutiesResponse
+	subscriptions *ethpb.CommitteeSubnetsSubscribeRequest
+}
+
+func (f *fakeValidatorServer) GetDuties(_ context.Context, _ *ethpb.DutiesRequest) (*ethpb.DutiesResponse, error) {
+	return f.duties, nil
+}
+
+func (f *fakeValidatorServer) SubscribeCommitteeSubnets(
+	_ context.Context, req *ethpb.CommitteeSubnetsSubscribeRequest,
+) (*ptypes.Empty, error) {
+	f.subscriptions = req
+	return &ptypes.Empty{}, nil
+}
+
+func (f *fakeValidatorServer) ProposeAttestation(
+	_ context.Context, att *ethpb.Attestation,
+) (*ethpb.AttestResponse, error) {
+	if att.Data.Slot > 100 {
+		return nil, status.Error(codes.InvalidArgument, "slot too high")
+	}
+	f.attestations = append(f.attestations, att)
+	return &ethpb.AttestResponse{}, nil
+}
+
+func serve(t *testing.T, s *Server, method string, target string, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	var resp map[string]interface{}
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Could not decode response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec, resp
+}
+
+func TestServer_Routes(t *testing.T) {
+	s := &Server{}
+	rec, resp := serve(t, s, http.MethodGet, "/eth/v1/beacon/unknown", "")
+	if rec.Code != http.StatusNotFound || resp["code"] != float64(http.StatusNotFound) {
+		t.Errorf("Wanted 404, received %d: %v", rec.Code, resp)
+	}
+	rec, _ = serve(t, s, http.MethodDelete, "/eth/v1/beacon/pool/attestations", "")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Wanted 405, received %d", rec.Code)
+	}
+}
+
+func TestServer_PeerScores(t *testing.T) {
+	fetcher := &p2pTest.MockPeersProvider{}
+	s := &Server{PeersFetcher: fetcher}
+	connected := fetcher.Peers().Connected()
+	if len(connected) != 2 {
+		t.Fatalf("Wanted 2 connected peers, received %d", len(connected))
+	}
+	bad := connected[1]
+	for i := 0; i < fetcher.Peers().MaxBadResponses(); i++ {
+		fetcher.Peers().IncrementGossipPenalties(bad)
+	}
+
+	rec, resp := serve(t, s, http.MethodGet, "/prysm/v1/node/peer_scores", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	scores := resp["data"].([]interface{})
+	if len(scores) != 2 {
+		t.Fatalf("Wanted 2 peer scores, received %v", scores)
+	}
+	worst := scores[0].(map[string]interface{})
+	if worst["peer_id"] != bad.Pretty() || worst["score"] != float64(-fetcher.Peers().MaxBadResponses()) ||
+		worst["gossip_penalties"] != "5" || worst["is_bad"] != true || worst["banned_until"] == nil {
+		t.Errorf("Unexpected score of the penalized peer %v", worst)
+	}
+	if best := scores[1].(map[string]interface{}); best["score"] != float64(0) || best["is_bad"] != false || best["state"] != "connected" {
+		t.Errorf("Unexpected score of the good peer %v", best)
+	}
+}
+
+func TestServer_Identity(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := enode.OpenDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	localNode := enode.NewLocalNode(db, key)
+	localNode.Set(enr.IPv4{127, 0, 0, 1})
+	localNode.Set(enr.UDP(12000))
+	p := p2pTest.NewTestP2P(t)
+	p.LocalENR = localNode.Node()
+	p.LocalMetadata = &pb.MetaData{SeqNumber: 3, Attnets: bitfield.Bitvector64{0x01, 0, 0, 0, 0, 0, 0, 0}}
+	s := &Server{IdentityFetcher: p, MetadataFetcher: p}
+
+	rec, resp := serve(t, s, http.MethodGet, "/eth/v1/node/identity", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	data := resp["data"].(map[string]interface{})
+	if data["peer_id"] != p.PeerID().Pretty() || data["enr"] != p.LocalENR.String() {
+		t.Errorf("Unexpected identity %v", data)
+	}
+	wantDiscovery := fmt.Sprintf("/ip4/127.0.0.1/udp/12000/p2p/%s", p.PeerID().Pretty())
+	if addrs := data["discovery_addresses"].([]interface{}); len(addrs) != 1 || addrs[0] != wantDiscovery {
+		t.Errorf("Wanted discovery address %s, received %v", wantDiscovery, addrs)
+	}
+	if addrs := data["p2p_addresses"].([]interface{}); len(addrs) != len(p.Host.Addrs()) {
+		t.Errorf("Wanted %d p2p addresses, received %v", len(p.Host.Addrs()), addrs)
+	}
+	metadata := data["metadata"].(map[string]interface{})
+	if metadata["seq_number"] != "3" || metadata["attnets"] != "0x0100000000000000" {
+		t.Errorf("Unexpected metadata %v", metadata)
+	}
+}
+
+func TestServer_StateEndpoints(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 4)
+	finalized := &ethpb.Checkpoint{Epoch: 2, Root: []byte{'f'}}
+	if err := st.SetFinalizedCheckpoint(finalized); err != nil {
+		t.Fatal(err)
+	}
+	chain := &mock.ChainService{State: st}
+	s := &Server{HeadFetcher: chain, GenesisTimeFetcher: chain}
+
+	rec, resp := serve(t, s, http.MethodGet, "/eth/v1/beacon/states/head/finality_checkpoints", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	cp := resp["data"].(map[string]interface{})["finalized"].(map[string]interface{})
+	if cp["epoch"] != "2" || cp["root"] != fmt.Sprintf("%#x", finalized.Root) {
+		t.Errorf("Unexpected finalized checkpoint %v", cp)
+	}
+
+	rec, resp = serve(t, s, http.MethodGet, "/eth/v1/beacon/states/head/root", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	root, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp["data"].(map[string]interface{})["root"]; got != fmt.Sprintf("%#x", root) {
+		t.Errorf("Wanted root %#x, received %v", root, got)
+	}
+
+	rec, _ = serve(t, s, http.MethodGet, "/eth/v1/beacon/states/bad/root", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for invalid state id, received %d", rec.Code)
+	}
+}
+
+func TestServer_Validators(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 4)
+	chain := &mock.ChainService{State: st}
+	s := &Server{HeadFetcher: chain, GenesisTimeFetcher: chain}
+
+	pubkey := st.PubkeyAtIndex(2)
+	target := fmt.Sprintf("/eth/v1/beacon/states/head/validators?id=1,%#x&id=99", pubkey)
+	rec, resp := serve(t, s, http.MethodGet, target, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	validators := resp["data"].([]interface{})
+	if len(validators) != 2 {
+		t.Fatalf("Wanted 2 validators, received %d", len(validators))
+	}
+	v := validators[1].(map[string]interface{})
+	if v["index"] != "2" || v["status"] != "active_ongoing" {
+		t.Errorf("Unexpected validator %v", v)
+	}
+	if got := v["validator"].(map[string]interface{})["pubkey"]; got != fmt.Sprintf("%#x", pubkey) {
+		t.Errorf("Wanted pubkey %#x, received %v", pubkey, got)
+	}
+	if v["balance"] != fmt.Sprint(params.BeaconConfig().MaxEffectiveBalance) {
+		t.Errorf("Unexpected balance %v", v["balance"])
+	}
+
+	rec, resp = serve(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators?status=pending_queued", "")
+	if rec.Code != http.StatusOK || len(resp["data"].([]interface{})) != 0 {
+		t.Errorf("Expected no pending validators, received %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec, _ = serve(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators/4", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 for unknown validator, received %d", rec.Code)
+	}
+}
+
+func TestServer_BlockBySlot_Canonical(t *testing.T) {
+	canonical := testutil.NewBeaconBlock()
+	canonical.Block.Slot = 3
+	orphan := testutil.NewBeaconBlock()
+	orphan.Block.Slot = 3
+	orphan.Block.ProposerIndex = 1
+	canonicalRoot, err := stateutil.BlockRoot(canonical.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orphanRoot, err := stateutil.BlockRoot(orphan.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		CanonicalFetcher: &mock.ChainService{CanonicalRoots: map[[32]byte]bool{canonicalRoot: true}},
+		BeaconChainServer: &fakeBeaconChainServer{blocks: []*ethpb.BeaconBlockContainer{
+			{Block: orphan, BlockRoot: orphanRoot[:]},
+			{Block: canonical, BlockRoot: canonicalRoot[:]},
+		}},
+	}
+
+	rec, resp := serve(t, s, http.MethodGet, "/eth/v1/beacon/blocks/3/root", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := resp["data"].(map[string]interface{})["root"]; got != fmt.Sprintf("%#x", canonicalRoot) {
+		t.Errorf("Wanted canonical root %#x, received %v", canonicalRoot, got)
+	}
+
+	rec, resp = serve(t, s, http.MethodGet, fmt.Sprintf("/eth/v1/beacon/headers/%#x", orphanRoot), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	header := resp["data"].(map[string]interface{})
+	if header["canonical"] != false {
+		t.Errorf("Expected orphaned block not to be canonical")
+	}
+	message := header["header"].(map[string]interface{})["message"].(map[string]interface{})
+	if message["proposer_index"] != "1" {
+		t.Errorf("Unexpected header %v", message)
+	}
+
+	rec, _ = serve(t, s, http.MethodGet, "/eth/v1/beacon/blocks/4", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 for empty slot, received %d", rec.Code)
+	}
+}
+
+func TestServer_SubmitAttestations(t *testing.T) {
+	validatorServer := &fakeValidatorServer{}
+	s := &Server{ValidatorServer: validatorServer, AuthToken: "token"}
+	att := func(slot uint64) interface{} {
+		return encode(&ethpb.Attestation{
+			AggregationBits: []byte{1},
+			Data: &ethpb.AttestationData{
+				Slot:            slot,
+				BeaconBlockRoot: make([]byte, 32),
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			},
+			Signature: make([]byte, 96),
+		})
+	}
+	body, err := json.Marshal([]interface{}{att(1), att(200)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/eth/v1/beacon/pool/attestations", strings.NewReader(string(body)))
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Wanted 401 without token, received %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/eth/v1/beacon/pool/attestations", strings.NewReader(string(body)))
+	req.Header.Set("Authorization", "Bearer token")
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Wanted 400 for failed attestation, received %d", rec.Code)
+	}
+	var resp struct {
+		Failures []struct {
+			Index   int    `json:"index"`
+			Message string `json:"message"`
+		} `json:"failures"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Failures) != 1 || resp.Failures[0].Index != 1 || resp.Failures[0].Message != "slot too high" {
+		t.Errorf("Unexpected failures %+v", resp.Failures)
+	}
+	if len(validatorServer.attestations) != 1 || validatorServer.attestations[0].Data.Slot != 1 {
+		t.Errorf("Expected valid attestation to be submitted, received %v", validatorServer.attestations)
+	}
+}
+
+func TestServer_SubmitProposerSlashing(t *testing.T) {
+	beaconServer := &fakeBeaconChainServer{}
+	s := &Server{BeaconChainServer: beaconServer}
+
+	rec, resp := serve(t, s, http.MethodPost, "/eth/v1/beacon/pool/proposer_slashings", `{
+		"signed_header_1": {"message": {"slot": "3", "proposer_index": "5"}, "signature": "0x01"},
+		"signed_header_2": {"message": {"slot": "3", "proposer_index": "5"}, "signature": "0x02"}
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %v", rec.Code, resp)
+	}
+	if len(beaconServer.proposerSlashings) != 1 || beaconServer.proposerSlashings[0].Header_2.Header.ProposerIndex != 5 {
+		t.Errorf("Wanted the proposer slashing to be submitted, received %v", beaconServer.proposerSlashings)
+	}
+
+	rec, _ = serve(t, s, http.MethodPost, "/eth/v1/beacon/pool/proposer_slashings", `{"signed_header_1": {}}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for an invalid slashing, received %d", rec.Code)
+	}
+}
+
+func TestServer_Liveness(t *testing.T) {
+	indexedAtt := func(targetEpoch uint64, indices ...uint64) *ethpb.IndexedAttestation {
+		return &ethpb.IndexedAttestation{
+			AttestingIndices: indices,
+			Data:             &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: targetEpoch}},
+		}
+	}
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 2*params.BeaconConfig().SlotsPerEpoch + 1
+	blk.Block.ProposerIndex = 7
+	s := &Server{BeaconChainServer: &fakeBeaconChainServer{
+		blocks: []*ethpb.BeaconBlockContainer{{Block: blk}},
+		indexedAtts: map[uint64][]*ethpb.IndexedAttestation{
+			1: {indexedAtt(1, 9)},
+			2: {indexedAtt(1, 10), indexedAtt(2, 1, 2)},
+			3: {indexedAtt(2, 3), indexedAtt(3, 4)},
+		},
+	}}
+
+	rec, resp := serve(t, s, http.MethodPost, "/eth/v1/validator/liveness/2", `["1","3","4","7","9"]`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %v", rec.Code, resp)
+	}
+	want := map[string]bool{"1": true, "3": true, "4": false, "7": true, "9": false}
+	data := resp["data"].([]interface{})
+	if len(data) != len(want) {
+		t.Fatalf("Wanted %d validators, received %v", len(want), data)
+	}
+	for _, d := range data {
+		v := d.(map[string]interface{})
+		if v["is_live"] != want[v["index"].(string)] {
+			t.Errorf("Wanted liveness %v of validator %v, received %v", want[v["index"].(string)], v["index"], v["is_live"])
+		}
+	}
+
+	rec, _ = serve(t, s, http.MethodPost, "/eth/v1/validator/liveness/2", `[]`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 without indices, received %d", rec.Code)
+	}
+}
+
+func TestServer_Duties(t *testing.T) {
+	pubkey := bytes.Repeat([]byte{1}, 48)
+	subnetCount := params.BeaconNetworkConfig().AttestationSubnetCount
+	s := &Server{ValidatorServer: &fakeValidatorServer{duties: &ethpb.DutiesResponse{
+		CurrentEpochDuties: []*ethpb.DutiesResponse_Duty{
+			{PublicKey: pubkey, ValidatorIndex: 3, Committee: []uint64{5, 3}, CommitteeIndex: 2, AttesterSlot: 7},
+		},
+		NextEpochDuties: []*ethpb.DutiesResponse_Duty{
+			{PublicKey: pubkey, ValidatorIndex: 3, Committee: []uint64{3}, CommitteeIndex: subnetCount + 1, AttesterSlot: 40},
+		},
+	}}}
+
+	rec, resp := serve(t, s, http.MethodPost, "/prysm/v1/validator/duties/0", fmt.Sprintf(`["%#x"]`, pubkey))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %v", rec.Code, resp)
+	}
+	data := resp["data"].(map[string]interface{})
+	current := data["current_epoch"].([]interface{})[0].(map[string]interface{})
+	if current["slot"] != "7" || current["validator_committee_index"] != "1" || current["subnet_id"] != "2" {
+		t.Errorf("Unexpected current epoch duty %v", current)
+	}
+	next := data["next_epoch"].([]interface{})[0].(map[string]interface{})
+	if next["slot"] != "40" || next["subnet_id"] != "1" {
+		t.Errorf("Unexpected next epoch duty %v", next)
+	}
+
+	rec, _ = serve(t, s, http.MethodPost, "/prysm/v1/validator/duties/0", `[]`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 without public keys, received %d", rec.Code)
+	}
+}
+
+func TestServer_BeaconCommitteeSubscriptions(t *testing.T) {
+	validatorServer := &fakeValidatorServer{}
+	s := &Server{ValidatorServer: validatorServer}
+
+	rec, resp := serve(t, s, http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", `[
+		{"validator_index":"1","committee_index":"2","committees_at_slot":"4","slot":"40","is_aggregator":true},
+		{"validator_index":"2","committee_index":"3","committees_at_slot":"4","slot":"41","is_aggregator":false}
+	]`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %v", rec.Code, resp)
+	}
+	want := &ethpb.CommitteeSubnetsSubscribeRequest{
+		Slots:        []uint64{40, 41},
+		CommitteeIds: []uint64{2, 3},
+		IsAggregator: []bool{true, false},
+	}
+	if !proto.Equal(validatorServer.subscriptions, want) {
+		t.Errorf("Wanted subscriptions %v, received %v", want, validatorServer.subscriptions)
+	}
+
+	rec, _ = serve(t, s, http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", `[{"slot":40}]`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for invalid subscription, received %d", rec.Code)
+	}
+}
+
+func TestServer_Events(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	finalized := testutil.NewBeaconBlock()
+	finalized.Block.StateRoot = bytes.Repeat([]byte{'f'}, 32)
+	if err := db.SaveBlock(context.Background(), finalized); err != nil {
+		t.Fatal(err)
+	}
+	finalizedRoot, err := stateutil.BlockRoot(finalized.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := testutil.NewBeaconBlock()
+	head.Block.Slot = 5
+	head.Block.StateRoot = bytes.Repeat([]byte{'h'}, 32)
+	headRoot, err := stateutil.BlockRoot(head.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(context.Background(), head); err != nil {
+		t.Fatal(err)
+	}
+	chain := &mock.ChainService{Block: head}
+	s := &Server{BeaconDB: db, HeadFetcher: chain, StateNotifier: chain.StateNotifier()}
+
+	rec, _ := serve(t, s, http.MethodGet, "/eth/v1/events?topics=unknown", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for an unknown topic, received %d", rec.Code)
+	}
+
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/eth/v1/events?topics=head,finalized_checkpoint,chain_reorg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Wanted an event stream, received %q", ct)
+	}
+
+	stateFeed := s.StateNotifier.StateFeed()
+	processed := &feed.Event{Type: statefeed.BlockProcessed, Data: &statefeed.BlockProcessedData{Slot: 5}}
+	stateFeed.Send(processed)
+	// A processed block not changing the head is not sent.
+	stateFeed.Send(processed)
+	stateFeed.Send(&feed.Event{
+		Type: statefeed.FinalizedCheckpoint,
+		Data: &statefeed.FinalizedCheckpointData{Epoch: 1, BlockRoot: finalizedRoot},
+	})
+	stateFeed.Send(&feed.Event{
+		Type: statefeed.Reorg,
+		Data: &statefeed.ReorgData{NewSlot: 5, OldSlot: 4, NewHeadRoot: headRoot, OldHeadRoot: finalizedRoot, Depth: 4},
+	})
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, map[string]interface{}) {
+		var topic string
+		var data map[string]interface{}
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				return topic, data
+			case strings.HasPrefix(line, "event: "):
+				topic = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+	topic, data := readEvent()
+	if topic != "head" || data["slot"] != "5" || data["block"] != fmt.Sprintf("%#x", headRoot) ||
+		data["state"] != fmt.Sprintf("%#x", head.Block.StateRoot) {
+		t.Errorf("Unexpected head event %s %v", topic, data)
+	}
+	topic, data = readEvent()
+	if topic != "finalized_checkpoint" || data["epoch"] != "1" || data["block"] != fmt.Sprintf("%#x", finalizedRoot) ||
+		data["state"] != fmt.Sprintf("%#x", finalized.Block.StateRoot) {
+		t.Errorf("Unexpected finalized checkpoint event %s %v", topic, data)
+	}
+	topic, data = readEvent()
+	if topic != "chain_reorg" || data["slot"] != "5" || data["depth"] != "4" ||
+		data["old_head_block"] != fmt.Sprintf("%#x", finalizedRoot) || data["new_head_block"] != fmt.Sprintf("%#x", headRoot) ||
+		data["old_head_state"] != fmt.Sprintf("%#x", finalized.Block.StateRoot) || data["new_head_state"] != fmt.Sprintf("%#x", head.Block.StateRoot) {
+		t.Errorf("Unexpected chain reorg event %s %v", topic, data)
+	}
+}
@@ -0,0 +1,436 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+)
+
+// attesterDuties serves the attester duties at an epoch of the validators whose indices are
+// given by the request body.
+func (s *Server) attesterDuties(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	epoch, err := strconv.ParseUint(vars["epoch"], 10, 64)
+	if err != nil {
+		writeErr(w, badRequest("invalid epoch %q", vars["epoch"]))
+		return
+	}
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	var indices []uint64
+	if err := decode(body, &indices); err != nil || len(indices) == 0 {
+		writeErr(w, badRequest("request body must be a non-empty array of validator indices"))
+		return
+	}
+	assignments, err := s.assignments(r.Context(), epoch, indices)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	resp := make([]interface{}, 0, len(assignments))
+	for _, a := range assignments {
+		position := 0
+		for i, index := range a.BeaconCommittees {
+			if index == a.ValidatorIndex {
+				position = i
+				break
+			}
+		}
+		resp = append(resp, map[string]interface{}{
+			"pubkey":                    encode(a.PublicKey),
+			"validator_index":           strconv.FormatUint(a.ValidatorIndex, 10),
+			"committee_index":           strconv.FormatUint(a.CommitteeIndex, 10),
+			"committee_length":          strconv.Itoa(len(a.BeaconCommittees)),
+			"validator_committee_index": strconv.Itoa(position),
+			"slot":                      strconv.FormatUint(a.AttesterSlot, 10),
+		})
+	}
+	writeData(w, resp)
+}
+
+// proposerDuties serves the block proposers of the slots of an epoch.
+func (s *Server) proposerDuties(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	epoch, err := strconv.ParseUint(vars["epoch"], 10, 64)
+	if err != nil {
+		writeErr(w, badRequest("invalid epoch %q", vars["epoch"]))
+		return
+	}
+	assignments, err := s.assignments(r.Context(), epoch, nil)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	type duty struct {
+		assignment *ethpb.ValidatorAssignments_CommitteeAssignment
+		slot       uint64
+	}
+	var duties []duty
+	for _, a := range assignments {
+		for _, slot := range a.ProposerSlots {
+			duties = append(duties, duty{assignment: a, slot: slot})
+		}
+	}
+	sort.Slice(duties, func(i, j int) bool {
+		return duties[i].slot < duties[j].slot
+	})
+	resp := make([]interface{}, 0, len(duties))
+	for _, d := range duties {
+		resp = append(resp, map[string]interface{}{
+			"pubkey":          encode(d.assignment.PublicKey),
+			"validator_index": strconv.FormatUint(d.assignment.ValidatorIndex, 10),
+			"slot":            strconv.FormatUint(d.slot, 10),
+		})
+	}
+	writeData(w, resp)
+}
+
+// duties serves the duties at an epoch and the next epoch of the validators whose public keys
+// are given by the request body, with the subnet of each attester committee, so a validator can
+// announce its aggregation duties of the next epoch ahead of time.
+func (s *Server) duties(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	epoch, err := strconv.ParseUint(vars["epoch"], 10, 64)
+	if err != nil {
+		writeErr(w, badRequest("invalid epoch %q", vars["epoch"]))
+		return
+	}
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	var pubkeys [][]byte
+	if err := decode(body, &pubkeys); err != nil || len(pubkeys) == 0 {
+		writeErr(w, badRequest("request body must be a non-empty array of validator public keys"))
+		return
+	}
+	resp, err := s.ValidatorServer.GetDuties(r.Context(), &ethpb.DutiesRequest{
+		Epoch:      epoch,
+		PublicKeys: pubkeys,
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, map[string]interface{}{
+		"current_epoch": encodeDuties(resp.CurrentEpochDuties),
+		"next_epoch":    encodeDuties(resp.NextEpochDuties),
+	})
+}
+
+func encodeDuties(duties []*ethpb.DutiesResponse_Duty) []interface{} {
+	resp := make([]interface{}, 0, len(duties))
+	for _, d := range duties {
+		position := 0
+		for i, index := range d.Committee {
+			if index == d.ValidatorIndex {
+				position = i
+				break
+			}
+		}
+		proposerSlots := make([]string, 0, len(d.ProposerSlots))
+		for _, slot := range d.ProposerSlots {
+			proposerSlots = append(proposerSlots, strconv.FormatUint(slot, 10))
+		}
+		resp = append(resp, map[string]interface{}{
+			"pubkey":                    encode(d.PublicKey),
+			"validator_index":           strconv.FormatUint(d.ValidatorIndex, 10),
+			"status":                    d.Status.String(),
+			"committee_index":           strconv.FormatUint(d.CommitteeIndex, 10),
+			"committee_length":          strconv.Itoa(len(d.Committee)),
+			"validator_committee_index": strconv.Itoa(position),
+			"slot":                      strconv.FormatUint(d.AttesterSlot, 10),
+			"subnet_id":                 strconv.FormatUint(helpers.ComputeSubnetForCommittee(d.CommitteeIndex), 10),
+			"proposer_slots":            proposerSlots,
+		})
+	}
+	return resp
+}
+
+// beaconCommitteeSubscription is an attester duty announced by a validator, and whether the
+// validator aggregates the attestations of its committee.
+type beaconCommitteeSubscription struct {
+	ValidatorIndex   uint64 `json:"validator_index"`
+	CommitteeIndex   uint64 `json:"committee_index"`
+	CommitteesAtSlot uint64 `json:"committees_at_slot"`
+	Slot             uint64 `json:"slot"`
+	IsAggregator     bool   `json:"is_aggregator"`
+}
+
+// beaconCommitteeSubscriptions subscribes the node to the subnets of the announced duties.
+// Aggregators are joined to the subnet of their committee an epoch before their duty, attesters
+// only get peers on the subnet to publish to.
+func (s *Server) beaconCommitteeSubscriptions(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	var subs []*beaconCommitteeSubscription
+	if err := decode(body, &subs); err != nil {
+		writeErr(w, badRequest("invalid committee subscriptions: %v", err))
+		return
+	}
+	req := &ethpb.CommitteeSubnetsSubscribeRequest{
+		Slots:        make([]uint64, 0, len(subs)),
+		CommitteeIds: make([]uint64, 0, len(subs)),
+		IsAggregator: make([]bool, 0, len(subs)),
+	}
+	for _, sub := range subs {
+		req.Slots = append(req.Slots, sub.Slot)
+		req.CommitteeIds = append(req.CommitteeIds, sub.CommitteeIndex)
+		req.IsAggregator = append(req.IsAggregator, sub.IsAggregator)
+	}
+	if _, err := s.ValidatorServer.SubscribeCommitteeSubnets(r.Context(), req); err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// produceBlock serves an unsigned block for the slot with the `randao_reveal` and optional
+// `graffiti` query parameters.
+func (s *Server) produceBlock(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	slot, err := strconv.ParseUint(vars["slot"], 10, 64)
+	if err != nil {
+		writeErr(w, badRequest("invalid slot %q", vars["slot"]))
+		return
+	}
+	randaoReveal, err := queryHex(r, "randao_reveal")
+	if err != nil || len(randaoReveal) == 0 {
+		writeErr(w, badRequest("invalid or missing randao_reveal"))
+		return
+	}
+	graffiti, err := queryHex(r, "graffiti")
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	blk, err := s.ValidatorServer.GetBlock(r.Context(), &ethpb.BlockRequest{
+		Slot:         slot,
+		RandaoReveal: randaoReveal,
+		Graffiti:     graffiti,
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, encode(blk))
+}
+
+// attestationData serves the attestation data for the `slot` and `committee_index` query
+// parameters.
+func (s *Server) attestationData(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	slot, ok, err := queryUint(r, "slot")
+	if err != nil || !ok {
+		writeErr(w, badRequest("invalid or missing slot"))
+		return
+	}
+	committee, ok, err := queryUint(r, "committee_index")
+	if err != nil || !ok {
+		writeErr(w, badRequest("invalid or missing committee_index"))
+		return
+	}
+	data, err := s.ValidatorServer.GetAttestationData(r.Context(), &ethpb.AttestationDataRequest{
+		Slot:           slot,
+		CommitteeIndex: committee,
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeData(w, encode(data))
+}
+
+// aggregateAttestation serves the pool attestation with the most attesters for the
+// `attestation_data_root` and `slot` query parameters.
+func (s *Server) aggregateAttestation(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	dataRoot, err := queryHex(r, "attestation_data_root")
+	if err != nil || len(dataRoot) != 32 {
+		writeErr(w, badRequest("invalid or missing attestation_data_root"))
+		return
+	}
+	slot, ok, err := queryUint(r, "slot")
+	if err != nil || !ok {
+		writeErr(w, badRequest("invalid or missing slot"))
+		return
+	}
+	atts, err := s.attestationPool(r.Context())
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	var best *ethpb.Attestation
+	for _, att := range atts {
+		if att.Data.Slot != slot {
+			continue
+		}
+		root, err := stateutil.AttestationDataRoot(att.Data)
+		if err != nil {
+			writeErr(w, errors.Wrap(err, "could not compute attestation data root"))
+			return
+		}
+		if !bytes.Equal(root[:], dataRoot) {
+			continue
+		}
+		if best == nil || att.AggregationBits.Count() > best.AggregationBits.Count() {
+			best = att
+		}
+	}
+	if best == nil {
+		writeErr(w, notFound("no attestation with data root %#x at slot %d", dataRoot, slot))
+		return
+	}
+	writeData(w, encode(best))
+}
+
+func (s *Server) submitAggregateAndProofs(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	submitAll(w, r, func(ctx context.Context, item interface{}) error {
+		agg := &ethpb.SignedAggregateAttestationAndProof{}
+		if err := decode(item, agg); err != nil || agg.Message == nil || agg.Message.Aggregate == nil {
+			return errors.Errorf("invalid aggregate and proof: %v", err)
+		}
+		_, err := s.ValidatorServer.SubmitSignedAggregateSelectionProof(ctx, &ethpb.SignedAggregateSubmitRequest{
+			SignedAggregateAndProof: agg,
+		})
+		return err
+	})
+}
+
+// liveness serves whether the validators whose indices are given by the request body were live
+// in an epoch, that is whether a block of the epoch was proposed by them or an attestation of
+// the epoch by them was included in a block.
+func (s *Server) liveness(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	epoch, err := strconv.ParseUint(vars["epoch"], 10, 64)
+	if err != nil {
+		writeErr(w, badRequest("invalid epoch %q", vars["epoch"]))
+		return
+	}
+	body, err := readJSON(r)
+	if err != nil {
+		writeErr(w, badRequest("%v", err))
+		return
+	}
+	var indices []uint64
+	if err := decode(body, &indices); err != nil || len(indices) == 0 {
+		writeErr(w, badRequest("request body must be a non-empty array of validator indices"))
+		return
+	}
+	live, err := s.liveIndices(r.Context(), epoch)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	resp := make([]interface{}, 0, len(indices))
+	for _, index := range indices {
+		resp = append(resp, map[string]interface{}{
+			"index":   strconv.FormatUint(index, 10),
+			"is_live": live[index],
+		})
+	}
+	writeData(w, resp)
+}
+
+// liveIndices returns the indices of the validators which proposed a block of the epoch, or
+// whose attestations of the epoch were included in blocks of the epoch or the next one.
+func (s *Server) liveIndices(ctx context.Context, epoch uint64) (map[uint64]bool, error) {
+	live := make(map[uint64]bool)
+	blocksReq := &ethpb.ListBlocksRequest{
+		QueryFilter: &ethpb.ListBlocksRequest_Epoch{Epoch: epoch},
+		PageSize:    int32(flags.Get().MaxPageSize),
+	}
+	for seen := 0; ; {
+		resp, err := s.BeaconChainServer.ListBlocks(ctx, blocksReq)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range resp.BlockContainers {
+			live[c.Block.Block.ProposerIndex] = true
+		}
+		seen += len(resp.BlockContainers)
+		if seen >= int(resp.TotalSize) || len(resp.BlockContainers) == 0 {
+			break
+		}
+		blocksReq.PageToken = resp.NextPageToken
+	}
+	// Attestations are included in blocks up to an epoch after their target epoch.
+	for _, inclusionEpoch := range []uint64{epoch, epoch + 1} {
+		attsReq := &ethpb.ListIndexedAttestationsRequest{
+			QueryFilter: &ethpb.ListIndexedAttestationsRequest_Epoch{Epoch: inclusionEpoch},
+			PageSize:    int32(flags.Get().MaxPageSize),
+		}
+		for seen := 0; ; {
+			resp, err := s.BeaconChainServer.ListIndexedAttestations(ctx, attsReq)
+			if err != nil {
+				return nil, err
+			}
+			for _, att := range resp.IndexedAttestations {
+				if att.Data.Target.Epoch != epoch {
+					continue
+				}
+				for _, index := range att.AttestingIndices {
+					live[index] = true
+				}
+			}
+			seen += len(resp.IndexedAttestations)
+			if seen >= int(resp.TotalSize) || len(resp.IndexedAttestations) == 0 {
+				break
+			}
+			attsReq.PageToken = resp.NextPageToken
+		}
+	}
+	return live, nil
+}
+
+// assignments pages through the committee assignments at an epoch of the given validators, or
+// of all active validators if none are given.
+func (s *Server) assignments(
+	ctx context.Context,
+	epoch uint64,
+	indices []uint64,
+) ([]*ethpb.ValidatorAssignments_CommitteeAssignment, error) {
+	var assignments []*ethpb.ValidatorAssignments_CommitteeAssignment
+	req := &ethpb.ListValidatorAssignmentsRequest{
+		QueryFilter: &ethpb.ListValidatorAssignmentsRequest_Epoch{Epoch: epoch},
+		Indices:     indices,
+		PageSize:    int32(flags.Get().MaxPageSize),
+	}
+	for {
+		resp, err := s.BeaconChainServer.ListValidatorAssignments(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, resp.Assignments...)
+		if len(assignments) >= int(resp.TotalSize) || len(resp.Assignments) == 0 {
+			return assignments, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// queryHex returns the bytes of a 0x prefixed hex query parameter, or nil if it is not given.
+func queryHex(r *http.Request, key string) ([]byte, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(v, "0x") {
+		return nil, badRequest("invalid %s %q", key, v)
+	}
+	b, err := hex.DecodeString(v[2:])
+	if err != nil {
+		return nil, badRequest("invalid %s %q", key, v)
+	}
+	return b, nil
+}
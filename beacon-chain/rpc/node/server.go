@@ -5,6 +5,7 @@ package node
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"time"
@@ -13,9 +14,13 @@ import (
 	"github.com/libp2p/go-libp2p-core/network"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/p2putils"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -30,6 +35,7 @@ type Server struct {
 	Server             *grpc.Server
 	BeaconDB           db.ReadOnlyDatabase
 	PeersFetcher       p2p.PeersProvider
+	IdentityFetcher    p2p.IdentityProvider
 	GenesisTimeFetcher blockchain.TimeFetcher
 	GenesisFetcher     blockchain.GenesisFetcher
 }
@@ -68,10 +74,59 @@ func (ns *Server) GetGenesis(ctx context.Context, _ *ptypes.Empty) (*ethpb.Genes
 	}, nil
 }
 
-// GetVersion checks the version information of the beacon node.
+// buildMetadata describes the build, configuration and identity of the running node, so fleet
+// tooling can verify exactly what is deployed.
+type buildMetadata struct {
+	*version.BuildInfo
+	FeatureFlags   []string `json:"feature_flags"`
+	ConfigName     string   `json:"config_name"`
+	ConfigChecksum string   `json:"config_checksum"`
+	ForkVersion    string   `json:"fork_version"`
+	PeerID         string   `json:"peer_id,omitempty"`
+	ENR            string   `json:"enr,omitempty"`
+}
+
+// GetVersion checks the version information of the beacon node. The metadata is a JSON
+// object with the semantic version, git commit, build date, Go version, enabled feature
+// flags, the name and checksum of the active chain config, the current fork version and the
+// peer id and ENR of the node.
 func (ns *Server) GetVersion(ctx context.Context, _ *ptypes.Empty) (*ethpb.Version, error) {
+	checksum, err := params.BeaconConfig().Checksum()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not compute config checksum: %v", err)
+	}
+	flags := featureconfig.Get().EnabledFlags
+	if flags == nil {
+		flags = []string{}
+	}
+	var currentEpoch uint64
+	if ns.GenesisTimeFetcher != nil {
+		currentEpoch = helpers.SlotToEpoch(ns.GenesisTimeFetcher.CurrentSlot())
+	}
+	fork, err := p2putils.Fork(currentEpoch)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not retrieve fork: %v", err)
+	}
+	metadata := &buildMetadata{
+		BuildInfo:      version.GetBuildInfo(),
+		FeatureFlags:   flags,
+		ConfigName:     params.BeaconConfig().ConfigName,
+		ConfigChecksum: fmt.Sprintf("%#x", checksum),
+		ForkVersion:    fmt.Sprintf("%#x", fork.CurrentVersion),
+	}
+	if ns.IdentityFetcher != nil {
+		metadata.PeerID = ns.IdentityFetcher.PeerID().String()
+		if node := ns.IdentityFetcher.ENR(); node != nil {
+			metadata.ENR = node.String()
+		}
+	}
+	enc, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not encode build metadata: %v", err)
+	}
 	return &ethpb.Version{
-		Version: version.GetVersion(),
+		Version:  version.GetVersion(),
+		Metadata: string(enc),
 	}, nil
 }
 
@@ -3,10 +3,15 @@ package node
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	ptypes "github.com/gogo/protobuf/types"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
@@ -14,6 +19,8 @@ import (
 	mockP2p "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"google.golang.org/grpc"
@@ -103,6 +110,71 @@ func TestNodeServer_GetVersion(t *testing.T) {
 	}
 }
 
+func TestNodeServer_GetVersionMetadata(t *testing.T) {
+	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{EnabledFlags: []string{"enable-ssz-cache"}})
+	defer resetCfg()
+	ns := &Server{}
+	res, err := ns.GetVersion(context.Background(), &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := &buildMetadata{}
+	if err := json.Unmarshal([]byte(res.Metadata), metadata); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := params.BeaconConfig().Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.ConfigChecksum != fmt.Sprintf("%#x", checksum) {
+		t.Errorf("Wanted config checksum %#x, received %s", checksum, metadata.ConfigChecksum)
+	}
+	if len(metadata.FeatureFlags) != 1 || metadata.FeatureFlags[0] != "enable-ssz-cache" {
+		t.Errorf("Unexpected feature flags %v", metadata.FeatureFlags)
+	}
+	if metadata.BuildInfo == nil || metadata.GoVersion != runtime.Version() {
+		t.Errorf("Unexpected build info %v", metadata.BuildInfo)
+	}
+	if metadata.ConfigName != params.BeaconConfig().ConfigName {
+		t.Errorf("Wanted config name %s, received %s", params.BeaconConfig().ConfigName, metadata.ConfigName)
+	}
+	if metadata.ForkVersion != fmt.Sprintf("%#x", params.BeaconConfig().GenesisForkVersion) {
+		t.Errorf("Wanted genesis fork version, received %s", metadata.ForkVersion)
+	}
+	if metadata.PeerID != "" || metadata.ENR != "" {
+		t.Errorf("Wanted no identity without p2p, received %s %s", metadata.PeerID, metadata.ENR)
+	}
+}
+
+func TestNodeServer_GetVersionIdentity(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := enode.OpenDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	peer := mockP2p.NewTestP2P(t)
+	peer.LocalENR = enode.NewLocalNode(db, key).Node()
+	ns := &Server{IdentityFetcher: peer}
+	res, err := ns.GetVersion(context.Background(), &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := &buildMetadata{}
+	if err := json.Unmarshal([]byte(res.Metadata), metadata); err != nil {
+		t.Fatal(err)
+	}
+	if metadata.PeerID != peer.PeerID().String() {
+		t.Errorf("Wanted peer id %s, received %s", peer.PeerID(), metadata.PeerID)
+	}
+	if metadata.ENR != peer.LocalENR.String() {
+		t.Errorf("Wanted ENR %s, received %s", peer.LocalENR, metadata.ENR)
+	}
+}
+
 func TestNodeServer_GetImplementedServices(t *testing.T) {
 	server := grpc.NewServer()
 	ns := &Server{
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
+	"time"
 
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
@@ -26,8 +28,10 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/apimiddleware"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/beacon"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/debug"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/httpapi"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/node"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/validator"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
@@ -36,12 +40,17 @@ import (
 	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/grpcutils"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/plugin/ocgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	// Registers the gzip compressor, so the responses to the requests compressed with gzip are
+	// compressed as well.
+	_ "google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -60,6 +69,7 @@ type Service struct {
 	headFetcher             blockchain.HeadFetcher
 	forkFetcher             blockchain.ForkFetcher
 	finalizationFetcher     blockchain.FinalizationFetcher
+	canonicalFetcher        blockchain.CanonicalFetcher
 	participationFetcher    blockchain.ParticipationFetcher
 	genesisTimeFetcher      blockchain.TimeFetcher
 	genesisFetcher          blockchain.GenesisFetcher
@@ -78,12 +88,16 @@ type Service struct {
 	listener                net.Listener
 	withCert                string
 	withKey                 string
+	withClientCACert        string
 	grpcServer              *grpc.Server
 	canonicalStateChan      chan *pbp2p.BeaconState
 	incomingAttestation     chan *ethpb.Attestation
 	credentialError         error
 	p2p                     p2p.Broadcaster
 	peersFetcher            p2p.PeersProvider
+	peerManager             p2p.PeerManager
+	identityFetcher         p2p.IdentityProvider
+	metadataFetcher         p2p.MetadataProvider
 	depositFetcher          depositcache.DepositFetcher
 	pendingDepositFetcher   depositcache.PendingDepositsFetcher
 	stateNotifier           statefeed.Notifier
@@ -95,6 +109,13 @@ type Service struct {
 	slasherCredentialError  error
 	slasherClient           slashpb.SlasherClient
 	stateGen                *stategen.State
+	authToken               string
+	quotaConfig             *apimiddleware.QuotaConfig
+	logRequests             bool
+	httpAPIPort             int
+	httpServer              *http.Server
+	maxRecvMsgSize          int
+	maxSendMsgSize          int
 }
 
 // Config options for the beacon node RPC server.
@@ -103,10 +124,12 @@ type Config struct {
 	Port                    string
 	CertFlag                string
 	KeyFlag                 string
+	ClientCACertFlag        string
 	BeaconDB                db.HeadAccessDatabase
 	HeadFetcher             blockchain.HeadFetcher
 	ForkFetcher             blockchain.ForkFetcher
 	FinalizationFetcher     blockchain.FinalizationFetcher
+	CanonicalFetcher        blockchain.CanonicalFetcher
 	ParticipationFetcher    blockchain.ParticipationFetcher
 	AttestationReceiver     blockchain.AttestationReceiver
 	BlockReceiver           blockchain.BlockReceiver
@@ -122,6 +145,9 @@ type Config struct {
 	SyncService             sync.Checker
 	Broadcaster             p2p.Broadcaster
 	PeersFetcher            p2p.PeersProvider
+	PeerManager             p2p.PeerManager
+	IdentityFetcher         p2p.IdentityProvider
+	MetadataFetcher         p2p.MetadataProvider
 	DepositFetcher          depositcache.DepositFetcher
 	PendingDepositFetcher   depositcache.PendingDepositsFetcher
 	SlasherProvider         string
@@ -130,6 +156,33 @@ type Config struct {
 	BlockNotifier           blockfeed.Notifier
 	OperationNotifier       opfeed.Notifier
 	StateGen                *stategen.State
+	AuthToken               string
+	QuotaConfig             *apimiddleware.QuotaConfig
+	LogRequests             bool
+	// HTTPAPIPort is the port the Eth2 beacon node HTTP API is served on. Disabled when 0.
+	HTTPAPIPort int
+	// MaxRecvMsgSize and MaxSendMsgSize bound the size of the messages received and sent by the
+	// server. The gRPC defaults apply when 0.
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+}
+
+// slowRequestThreshold is the duration above which requests are logged as slow when
+// request logging is enabled.
+const slowRequestThreshold = 2 * time.Second
+
+// mutatingMethods are the RPC methods which change the state of the node or broadcast
+// to the network. They require the auth token when one is configured.
+var mutatingMethods = []string{
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/ProposeBlock",
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/ProposeAttestation",
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/ProposeExit",
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/SubmitAggregateSelectionProof",
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/SubmitSignedAggregateSelectionProof",
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/SubscribeCommitteeSubnets",
+	"/ethereum.eth.v1alpha1.BeaconChain/SubmitAttesterSlashing",
+	"/ethereum.eth.v1alpha1.BeaconChain/SubmitProposerSlashing",
+	"/ethereum.beacon.rpc.v1.Debug/SetLoggingLevel",
 }
 
 // NewService instantiates a new RPC service instance that will
@@ -143,6 +196,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		headFetcher:             cfg.HeadFetcher,
 		forkFetcher:             cfg.ForkFetcher,
 		finalizationFetcher:     cfg.FinalizationFetcher,
+		canonicalFetcher:        cfg.CanonicalFetcher,
 		participationFetcher:    cfg.ParticipationFetcher,
 		genesisTimeFetcher:      cfg.GenesisTimeFetcher,
 		genesisFetcher:          cfg.GenesisFetcher,
@@ -150,6 +204,9 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		blockReceiver:           cfg.BlockReceiver,
 		p2p:                     cfg.Broadcaster,
 		peersFetcher:            cfg.PeersFetcher,
+		peerManager:             cfg.PeerManager,
+		identityFetcher:         cfg.IdentityFetcher,
+		metadataFetcher:         cfg.MetadataFetcher,
 		powChainService:         cfg.POWChainService,
 		chainStartFetcher:       cfg.ChainStartFetcher,
 		mockEth1Votes:           cfg.MockEth1Votes,
@@ -161,6 +218,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		port:                    cfg.Port,
 		withCert:                cfg.CertFlag,
 		withKey:                 cfg.KeyFlag,
+		withClientCACert:        cfg.ClientCACertFlag,
 		depositFetcher:          cfg.DepositFetcher,
 		pendingDepositFetcher:   cfg.PendingDepositFetcher,
 		canonicalStateChan:      make(chan *pbp2p.BeaconState, params.BeaconConfig().DefaultBufferSize),
@@ -172,6 +230,12 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		slasherCert:             cfg.SlasherCert,
 		stateGen:                cfg.StateGen,
 		enableDebugRPCEndpoints: cfg.EnableDebugRPCEndpoints,
+		authToken:               cfg.AuthToken,
+		quotaConfig:             cfg.QuotaConfig,
+		logRequests:             cfg.LogRequests,
+		httpAPIPort:             cfg.HTTPAPIPort,
+		maxRecvMsgSize:          cfg.MaxRecvMsgSize,
+		maxSendMsgSize:          cfg.MaxSendMsgSize,
 	}
 }
 
@@ -185,32 +249,53 @@ func (s *Service) Start() {
 	s.listener = lis
 	log.WithField("address", address).Info("RPC-API listening on port")
 
-	opts := []grpc.ServerOption{
-		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
-		grpc.StreamInterceptor(middleware.ChainStreamServer(
-			recovery.StreamServerInterceptor(
+	chain := apimiddleware.NewChain(
+		apimiddleware.FromInterceptors(
+			"recovery",
+			recovery.UnaryServerInterceptor(
 				recovery.WithRecoveryHandlerContext(traceutil.RecoveryHandlerFunc),
 			),
-			grpc_prometheus.StreamServerInterceptor,
-			grpc_opentracing.StreamServerInterceptor(),
-		)),
-		grpc.UnaryInterceptor(middleware.ChainUnaryServer(
-			recovery.UnaryServerInterceptor(
+			recovery.StreamServerInterceptor(
 				recovery.WithRecoveryHandlerContext(traceutil.RecoveryHandlerFunc),
 			),
-			grpc_prometheus.UnaryServerInterceptor,
-			grpc_opentracing.UnaryServerInterceptor(),
-		)),
+		),
+		apimiddleware.FromInterceptors("metrics", grpc_prometheus.UnaryServerInterceptor, grpc_prometheus.StreamServerInterceptor),
+		apimiddleware.FromInterceptors("tracing", grpc_opentracing.UnaryServerInterceptor(), grpc_opentracing.StreamServerInterceptor()),
+	)
+	if s.logRequests {
+		chain.Use(apimiddleware.NewRequestLogger(log, slowRequestThreshold))
+	}
+	if s.authToken != "" {
+		auth := rpcauth.NewAuthenticator(s.authToken, mutatingMethods)
+		chain.Use(apimiddleware.FromInterceptors("auth", auth.UnaryServerInterceptor(), auth.StreamServerInterceptor()))
+		log.Info("Requiring auth token for mutating RPC endpoints")
+		if s.withCert == "" || s.withKey == "" {
+			log.Warn("The RPC auth token is accepted over an insecure gRPC connection. Provide a certificate and key to encrypt it")
+		}
+	}
+	if s.quotaConfig != nil {
+		chain.Use(apimiddleware.NewRateLimiter(s.quotaConfig))
+	}
+	log.WithField("middlewares", chain.Names()).Debug("Configured RPC middlewares")
+	opts := append([]grpc.ServerOption{grpc.StatsHandler(&ocgrpc.ServerHandler{})}, chain.ServerOptions()...)
+	if s.maxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(s.maxRecvMsgSize))
+	}
+	if s.maxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(s.maxSendMsgSize))
 	}
 	grpc_prometheus.EnableHandlingTimeHistogram()
 	// TODO(#791): Utilize a certificate for secure connections
 	// between beacon nodes and validator clients.
 	if s.withCert != "" && s.withKey != "" {
-		creds, err := credentials.NewServerTLSFromFile(s.withCert, s.withKey)
+		creds, err := grpcutils.ServerTLSCredentials(s.withCert, s.withKey, s.withClientCACert)
 		if err != nil {
 			log.Errorf("Could not load TLS keys: %s", err)
 			s.credentialError = err
 		}
+		if s.withClientCACert != "" {
+			log.Info("Requiring client certificates for gRPC connections")
+		}
 		opts = append(opts, grpc.Creds(creds))
 	} else {
 		log.Warn("You are using an insecure gRPC connection! Provide a certificate and key to connect securely")
@@ -237,6 +322,7 @@ func (s *Service) Start() {
 		BlockNotifier:          s.blockNotifier,
 		OperationNotifier:      s.operationNotifier,
 		P2P:                    s.p2p,
+		PeerManager:            s.peerManager,
 		BlockReceiver:          s.blockReceiver,
 		MockEth1Votes:          s.mockEth1Votes,
 		Eth1BlockFetcher:       s.powChainService,
@@ -244,12 +330,16 @@ func (s *Service) Start() {
 		SlashingsPool:          s.slashingsPool,
 		StateGen:               s.stateGen,
 	}
+	if featureconfig.Get().EnableRPCSlashingProtection {
+		validatorServer.SlashingGuard = validator.NewSlashingGuard()
+	}
 	nodeServer := &node.Server{
 		BeaconDB:           s.beaconDB,
 		Server:             s.grpcServer,
 		SyncChecker:        s.syncService,
 		GenesisTimeFetcher: s.genesisTimeFetcher,
 		PeersFetcher:       s.peersFetcher,
+		IdentityFetcher:    s.identityFetcher,
 		GenesisFetcher:     s.genesisFetcher,
 	}
 	beaconChainServer := &beacon.Server{
@@ -284,6 +374,7 @@ func (s *Service) Start() {
 			HeadFetcher:        s.headFetcher,
 		}
 		pbrpc.RegisterDebugServer(s.grpcServer, debugServer)
+		debug.RegisterStreamServer(s.grpcServer, debugServer)
 	}
 	ethpb.RegisterBeaconNodeValidatorServer(s.grpcServer, validatorServer)
 
@@ -297,11 +388,47 @@ func (s *Service) Start() {
 			}
 		}
 	}()
+	if s.httpAPIPort != 0 {
+		s.startHTTPAPI(nodeServer, beaconChainServer, validatorServer)
+	}
 	if featureconfig.Get().EnableSlasherConnection {
 		s.startSlasherClient()
 	}
 }
 
+// startHTTPAPI serves the Eth2 beacon node HTTP API, mapped onto the gRPC servers.
+func (s *Service) startHTTPAPI(
+	nodeServer ethpb.NodeServer,
+	beaconChainServer ethpb.BeaconChainServer,
+	validatorServer ethpb.BeaconNodeValidatorServer,
+) {
+	apiServer := &httpapi.Server{
+		BeaconDB:            s.beaconDB,
+		HeadFetcher:         s.headFetcher,
+		FinalizationFetcher: s.finalizationFetcher,
+		CanonicalFetcher:    s.canonicalFetcher,
+		GenesisTimeFetcher:  s.genesisTimeFetcher,
+		StateGen:            s.stateGen,
+		StateNotifier:       s.stateNotifier,
+		PeersFetcher:        s.peersFetcher,
+		IdentityFetcher:     s.identityFetcher,
+		MetadataFetcher:     s.metadataFetcher,
+		NodeServer:          nodeServer,
+		BeaconChainServer:   beaconChainServer,
+		ValidatorServer:     validatorServer,
+		AuthToken:           s.authToken,
+		EnableDebugRoutes:   s.enableDebugRPCEndpoints,
+	}
+	address := fmt.Sprintf("%s:%d", s.host, s.httpAPIPort)
+	s.httpServer = &http.Server{Addr: address, Handler: apiServer.Handler()}
+	go func() {
+		log.WithField("address", address).Info("HTTP API listening on port")
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("Could not serve HTTP API")
+		}
+	}()
+}
+
 func (s *Service) startSlasherClient() {
 	var dialOpt grpc.DialOption
 	if s.slasherCert != "" {
@@ -344,6 +471,13 @@ func (s *Service) Stop() error {
 		s.grpcServer.GracefulStop()
 		log.Debug("Initiated graceful stop of gRPC server")
 	}
+	if s.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
 	if s.slasherConn != nil {
 		if err := s.slasherConn.Close(); err != nil {
 			return err
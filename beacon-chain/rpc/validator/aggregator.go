@@ -5,6 +5,8 @@ import (
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/sirupsen/logrus"
@@ -92,11 +94,22 @@ func (as *Server) SubmitSignedAggregateSelectionProof(ctx context.Context, req *
 		req.SignedAggregateAndProof.Message.Aggregate == nil || req.SignedAggregateAndProof.Message.Aggregate.Data == nil {
 		return nil, status.Error(codes.InvalidArgument, "Signed aggregate request can't be nil")
 	}
+	if _, err := bls.SignatureFromBytes(req.SignedAggregateAndProof.Signature); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Incorrect aggregate and proof signature")
+	}
 
 	if err := as.P2P.Broadcast(ctx, req.SignedAggregateAndProof); err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not broadcast signed aggregated attestation: %v", err)
 	}
 
+	// The node does not receive its own gossip, so the aggregate is saved for the blocks it
+	// proposes as well.
+	if agg := req.SignedAggregateAndProof.Message.Aggregate; helpers.IsAggregated(agg) {
+		if err := as.AttPool.SaveAggregatedAttestation(stateTrie.CopyAttestation(agg)); err != nil {
+			log.WithError(err).Error("Could not save aggregated attestation")
+		}
+	}
+
 	log.WithFields(logrus.Fields{
 		"slot":            req.SignedAggregateAndProof.Message.Aggregate.Data.Slot,
 		"committeeIndex":  req.SignedAggregateAndProof.Message.Aggregate.Data.CommitteeIndex,
@@ -250,6 +250,44 @@ func TestSubmitAggregateAndProof_AggregateNotOk(t *testing.T) {
 	}
 }
 
+func TestSubmitSignedAggregateSelectionProof_SavesAggregate(t *testing.T) {
+	ctx := context.Background()
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 32)
+	att, err := generateAtt(beaconState, 0, privKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broadcaster := &mockp2p.MockBroadcaster{}
+	aggregatorServer := &Server{
+		AttPool: attestations.NewPool(),
+		P2P:     broadcaster,
+	}
+	req := &ethpb.SignedAggregateSubmitRequest{
+		SignedAggregateAndProof: &ethpb.SignedAggregateAttestationAndProof{
+			Message:   &ethpb.AggregateAttestationAndProof{Aggregate: att},
+			Signature: []byte{'a'},
+		},
+	}
+	if _, err := aggregatorServer.SubmitSignedAggregateSelectionProof(ctx, req); err == nil ||
+		!strings.Contains(err.Error(), "Incorrect aggregate and proof signature") {
+		t.Errorf("Wanted incorrect signature error, received %v", err)
+	}
+	if broadcaster.BroadcastCalled {
+		t.Error("Wanted aggregate with incorrect signature not to be broadcast")
+	}
+
+	req.SignedAggregateAndProof.Signature = bls.RandKey().Sign([]byte{'a'}).Marshal()
+	if _, err := aggregatorServer.SubmitSignedAggregateSelectionProof(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if !broadcaster.BroadcastCalled {
+		t.Error("Wanted aggregate to be broadcast")
+	}
+	if !reflect.DeepEqual(aggregatorServer.AttPool.AggregatedAttestations(), []*ethpb.Attestation{att}) {
+		t.Error("Wanted aggregate to be saved in the pool")
+	}
+}
+
 func generateAtt(state *beaconstate.BeaconState, index uint64, privKeys []*bls.SecretKey) (*ethpb.Attestation, error) {
 	aggBits := bitfield.NewBitlist(4)
 	aggBits.SetBitAt(index, true)
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -11,7 +12,9 @@ import (
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
@@ -29,8 +32,9 @@ func (vs *Server) GetDuties(ctx context.Context, req *ethpb.DutiesRequest) (*eth
 }
 
 // StreamDuties returns the duties assigned to a list of validators specified
-// in the request object via a server-side stream. The stream sends out new assignments in case
-// a chain re-org occurred.
+// in the request object via a server-side stream. The stream sends out new assignments at every
+// epoch start, and sends out recomputed assignments right away in case a chain re-org changed the
+// blocks the assignments depend on, invalidating the assignments sent before.
 func (vs *Server) StreamDuties(req *ethpb.DutiesRequest, stream ethpb.BeaconNodeValidator_StreamDutiesServer) error {
 	if vs.SyncChecker.Syncing() {
 		return status.Error(codes.Unavailable, "Syncing to latest head, not ready to respond")
@@ -40,16 +44,17 @@ func (vs *Server) StreamDuties(req *ethpb.DutiesRequest, stream ethpb.BeaconNode
 	// the number epochs since the genesis time, otherwise 0 by default.
 	genesisTime := vs.GenesisTimeFetcher.GenesisTime()
 	var currentEpoch uint64
-	if genesisTime.Before(roughtime.Now()) {
+	if genesisTime.Before(slotutil.Now()) {
 		currentEpoch = slotutil.EpochsSinceGenesis(vs.GenesisTimeFetcher.GenesisTime())
 	}
 	req.Epoch = currentEpoch
-	res, err := vs.duties(stream.Context(), req)
+	headRoot, err := vs.HeadFetcher.HeadRoot(stream.Context())
 	if err != nil {
-		return status.Errorf(codes.Internal, "Could not compute validator duties: %v", err)
+		return status.Errorf(codes.Internal, "Could not get head root: %v", err)
 	}
-	if err := stream.Send(res); err != nil {
-		return status.Errorf(codes.Internal, "Could not send response over stream: %v", err)
+	dependentRoots, err := vs.sendDuties(stream, req, nil /* head state */, bytesutil.ToBytes32(headRoot))
+	if err != nil {
+		return err
 	}
 
 	// We start a for loop which ticks on every epoch or a chain reorg.
@@ -64,37 +69,50 @@ func (vs *Server) StreamDuties(req *ethpb.DutiesRequest, stream ethpb.BeaconNode
 		// Ticks every epoch to submit assignments to connected validator clients.
 		case epoch := <-epochTicker.C():
 			req.Epoch = epoch
-			res, err := vs.duties(stream.Context(), req)
+			headRoot, err := vs.HeadFetcher.HeadRoot(stream.Context())
 			if err != nil {
-				return status.Errorf(codes.Internal, "Could not compute validator duties: %v", err)
+				return status.Errorf(codes.Internal, "Could not get head root: %v", err)
 			}
-			if err := stream.Send(res); err != nil {
-				return status.Errorf(codes.Internal, "Could not send response over stream: %v", err)
+			dependentRoots, err = vs.sendDuties(stream, req, nil /* head state */, bytesutil.ToBytes32(headRoot))
+			if err != nil {
+				return err
 			}
 		case ev := <-stateChannel:
 			// If a reorg occurred, we recompute duties for the connected validator clients
 			// and send another response over the server stream right away.
-			currentEpoch = slotutil.EpochsSinceGenesis(vs.GenesisTimeFetcher.GenesisTime())
-			if ev.Type == statefeed.Reorg {
-				data, ok := ev.Data.(*statefeed.ReorgData)
-				if !ok {
-					return status.Errorf(codes.Internal, "Received incorrect data type over reorg feed: %v", data)
-				}
-				newSlotEpoch := helpers.SlotToEpoch(data.NewSlot)
-				oldSlotEpoch := helpers.SlotToEpoch(data.OldSlot)
-				// We only send out new duties if a reorg across epochs occurred, otherwise
-				// validator shufflings would not have changed as a result of a reorg.
-				if newSlotEpoch >= oldSlotEpoch {
-					continue
-				}
-				req.Epoch = currentEpoch
-				res, err := vs.duties(stream.Context(), req)
-				if err != nil {
-					return status.Errorf(codes.Internal, "Could not compute validator duties: %v", err)
-				}
-				if err := stream.Send(res); err != nil {
-					return status.Errorf(codes.Internal, "Could not send response over stream: %v", err)
-				}
+			if ev.Type != statefeed.Reorg {
+				continue
+			}
+			data, ok := ev.Data.(*statefeed.ReorgData)
+			if !ok {
+				return status.Errorf(codes.Internal, "Received incorrect data type over reorg feed: %v", data)
+			}
+			// We only send out new duties if the reorg replaced a block the duties depend on,
+			// otherwise validator shufflings would not have changed as a result of the reorg.
+			newDependentRoots, err := vs.dutiesDependentRoots(stream.Context(), data.NewHeadRoot, req.Epoch)
+			if err != nil {
+				return status.Errorf(codes.Internal, "Could not get dependent roots of duties: %v", err)
+			}
+			if newDependentRoots == dependentRoots {
+				continue
+			}
+			// The head is not updated yet when the reorg event is sent, so the duties are computed
+			// from the state of the new head.
+			var headState *stateTrie.BeaconState
+			if featureconfig.Get().NewStateMgmt {
+				headState, err = vs.StateGen.StateByRoot(stream.Context(), data.NewHeadRoot)
+			} else {
+				headState, err = vs.BeaconDB.State(stream.Context(), data.NewHeadRoot)
+			}
+			if err != nil {
+				return status.Errorf(codes.Internal, "Could not get state of new head: %v", err)
+			}
+			if headState == nil {
+				return status.Error(codes.Internal, "Could not get state of new head")
+			}
+			dependentRoots, err = vs.sendDuties(stream, req, headState, data.NewHeadRoot)
+			if err != nil {
+				return err
 			}
 		case <-stream.Context().Done():
 			return status.Error(codes.Canceled, "Stream context canceled")
@@ -104,6 +122,69 @@ func (vs *Server) StreamDuties(req *ethpb.DutiesRequest, stream ethpb.BeaconNode
 	}
 }
 
+// sendDuties computes the duties of the request from the state, or the head state if nil, and
+// sends them over the stream. It returns the roots the duties depend on, on the chain of the
+// block root of the state.
+func (vs *Server) sendDuties(
+	stream ethpb.BeaconNodeValidator_StreamDutiesServer,
+	req *ethpb.DutiesRequest,
+	s *stateTrie.BeaconState,
+	root [32]byte,
+) ([2][32]byte, error) {
+	dependentRoots, err := vs.dutiesDependentRoots(stream.Context(), root, req.Epoch)
+	if err != nil {
+		return [2][32]byte{}, status.Errorf(codes.Internal, "Could not get dependent roots of duties: %v", err)
+	}
+	if s == nil {
+		s, err = vs.HeadFetcher.HeadState(stream.Context())
+		if err != nil {
+			return [2][32]byte{}, status.Errorf(codes.Internal, "Could not get head state: %v", err)
+		}
+	}
+	res, err := vs.dutiesFromState(stream.Context(), s, req)
+	if err != nil {
+		return [2][32]byte{}, status.Errorf(codes.Internal, "Could not compute validator duties: %v", err)
+	}
+	if err := stream.Send(res); err != nil {
+		return [2][32]byte{}, status.Errorf(codes.Internal, "Could not send response over stream: %v", err)
+	}
+	return dependentRoots, nil
+}
+
+// dutiesDependentRoots returns the roots of the blocks the duties of the epoch depend on, on the
+// chain of the block root: the block at the last slot of the epoch before the previous epoch,
+// whose randao mix seeds the attester shuffling of the epoch, and the block at the last slot of
+// the previous epoch, which determines the proposers of the epoch and seeds the attester
+// shuffling of the next epoch. Empty slots are filled by the latest block before them.
+func (vs *Server) dutiesDependentRoots(ctx context.Context, root [32]byte, epoch uint64) ([2][32]byte, error) {
+	var slots [2]uint64
+	if epoch > 1 {
+		slots[0] = helpers.StartSlot(epoch-1) - 1
+	}
+	if epoch > 0 {
+		slots[1] = helpers.StartSlot(epoch) - 1
+	}
+	var roots [2][32]byte
+	blk, err := vs.BeaconDB.Block(ctx, root)
+	if err != nil {
+		return roots, err
+	}
+	for i := len(slots) - 1; i >= 0; i-- {
+		for blk != nil && blk.Block != nil && blk.Block.Slot > slots[i] {
+			root = bytesutil.ToBytes32(blk.Block.ParentRoot)
+			blk, err = vs.BeaconDB.Block(ctx, root)
+			if err != nil {
+				return roots, err
+			}
+		}
+		if blk == nil || blk.Block == nil {
+			return roots, fmt.Errorf("could not find block at or before slot %d", slots[i])
+		}
+		roots[i] = root
+	}
+	return roots, nil
+}
+
 // Compute the validator duties from the head state's corresponding epoch
 // for validators public key / indices requested.
 func (vs *Server) duties(ctx context.Context, req *ethpb.DutiesRequest) (*ethpb.DutiesResponse, error) {
@@ -111,7 +192,13 @@ func (vs *Server) duties(ctx context.Context, req *ethpb.DutiesRequest) (*ethpb.
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not get head state: %v", err)
 	}
+	return vs.dutiesFromState(ctx, s, req)
+}
 
+// Compute the validator duties from the state's corresponding epoch
+// for validators public key / indices requested.
+func (vs *Server) dutiesFromState(ctx context.Context, s *stateTrie.BeaconState, req *ethpb.DutiesRequest) (*ethpb.DutiesResponse, error) {
+	var err error
 	// Advance state with empty transitions up to the requested epoch start slot.
 	if epochStartSlot := helpers.StartSlot(req.Epoch); s.Slot() < epochStartSlot {
 		s, err = state.ProcessSlots(ctx, s, epochStartSlot)
@@ -143,7 +230,10 @@ func (vs *Server) duties(ctx context.Context, req *ethpb.DutiesRequest) (*ethpb.
 		nextAssignment := &ethpb.DutiesResponse_Duty{
 			PublicKey: pubKey,
 		}
-		idx, ok := s.ValidatorIndexByPubkey(bytesutil.ToBytes48(pubKey))
+		idx, ok, err := vs.validatorIndex(ctx, s, pubKey)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not retrieve validator index: %v", err)
+		}
 		if ok {
 			assignment.ValidatorIndex = idx
 			assignment.Status = assignmentStatus(s, idx)
@@ -19,6 +19,7 @@ import (
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	mockPOW "github.com/prysmaticlabs/prysm/beacon-chain/powchain/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
@@ -341,13 +342,18 @@ func TestStreamDuties_OK(t *testing.T) {
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	// Start half way through epoch 1, the epoch ticker fires right away during the genesis epoch.
+	secondsPerEpoch := params.BeaconConfig().SecondsPerSlot * params.BeaconConfig().SlotsPerEpoch
 	vs := &Server{
 		Ctx:         ctx,
 		BeaconDB:    db,
 		HeadFetcher: &mockChain.ChainService{State: bs, Root: genesisRoot[:]},
 		SyncChecker: &mockSync.Sync{IsSyncing: false},
 		GenesisTimeFetcher: &mockChain.ChainService{
-			Genesis: time.Now(),
+			Genesis: time.Now().Add(-time.Duration(secondsPerEpoch+secondsPerEpoch/2) * time.Second),
 		},
 		StateNotifier: &mockChain.MockStateNotifier{},
 	}
@@ -355,6 +361,7 @@ func TestStreamDuties_OK(t *testing.T) {
 	// Test the first validator in registry.
 	req := &ethpb.DutiesRequest{
 		PublicKeys: [][]byte{deposits[0].Data.PublicKey},
+		Epoch:      1,
 	}
 	wantedRes, err := vs.duties(ctx, req)
 	if err != nil {
@@ -377,63 +384,109 @@ func TestStreamDuties_OK(t *testing.T) {
 	cancel()
 }
 
-func TestStreamDuties_OK_ChainReorg(t *testing.T) {
-	db := dbutil.SetupDB(t)
+// setupForks saves a genesis block, a block at the last slot of epoch 0 and its child in epoch
+// 1, and a fork block at the last slot of epoch 0.
+func setupForks(t *testing.T, beaconDB db.Database) (genesisRoot [32]byte, forkA [32]byte, childA [32]byte, forkB [32]byte) {
+	ctx := context.Background()
+	saveBlock := func(slot uint64, parent [32]byte, graffiti string) [32]byte {
+		b := testutil.NewBeaconBlock()
+		b.Block.Slot = slot
+		b.Block.ParentRoot = parent[:]
+		b.Block.Body.Graffiti = bytesutil.PadTo([]byte(graffiti), 32)
+		if err := beaconDB.SaveBlock(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		root, err := stateutil.BlockRoot(b.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return root
+	}
+	genesisRoot = saveBlock(0, [32]byte{}, "")
+	lastSlot := params.BeaconConfig().SlotsPerEpoch - 1
+	forkA = saveBlock(lastSlot, genesisRoot, "a")
+	childA = saveBlock(lastSlot+2, forkA, "a")
+	forkB = saveBlock(lastSlot, genesisRoot, "b")
+	return genesisRoot, forkA, childA, forkB
+}
 
-	genesis := blk.NewGenesisBlock([]byte{})
-	depChainStart := params.BeaconConfig().MinGenesisActiveValidatorCount
-	deposits, _, err := testutil.DeterministicDepositsAndKeys(depChainStart)
-	if err != nil {
-		t.Fatal(err)
-	}
-	eth1Data, err := testutil.DeterministicEth1Data(len(deposits))
-	if err != nil {
-		t.Fatal(err)
-	}
-	bs, err := state.GenesisBeaconState(deposits, 0, eth1Data)
-	if err != nil {
-		t.Fatalf("Could not setup genesis bs: %v", err)
-	}
-	genesisRoot, err := ssz.HashTreeRoot(genesis.Block)
-	if err != nil {
-		t.Fatalf("Could not get signing root %v", err)
+func TestDutiesDependentRoots(t *testing.T) {
+	db := dbutil.SetupDB(t)
+	ctx := context.Background()
+	genesisRoot, forkA, childA, forkB := setupForks(t, db)
+	vs := &Server{BeaconDB: db}
+
+	tests := []struct {
+		name  string
+		head  [32]byte
+		epoch uint64
+		want  [2][32]byte
+	}{
+		{name: "genesis epoch", head: childA, epoch: 0, want: [2][32]byte{genesisRoot, genesisRoot}},
+		{name: "block at dependent slot", head: forkA, epoch: 1, want: [2][32]byte{genesisRoot, forkA}},
+		{name: "descendant of dependent block", head: childA, epoch: 1, want: [2][32]byte{genesisRoot, forkA}},
+		{name: "fork at dependent slot", head: forkB, epoch: 1, want: [2][32]byte{genesisRoot, forkB}},
+		{name: "empty dependent slot", head: childA, epoch: 2, want: [2][32]byte{forkA, childA}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots, err := vs.dutiesDependentRoots(ctx, tt.head, tt.epoch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if roots != tt.want {
+				t.Errorf("Wanted dependent roots %#x, received %#x", tt.want, roots)
+			}
+		})
 	}
 
-	pubKeys := make([][]byte, len(deposits))
-	indices := make([]uint64, len(deposits))
-	for i := 0; i < len(deposits); i++ {
-		pubKeys[i] = deposits[i].Data.PublicKey
-		indices[i] = uint64(i)
+	if _, err := vs.dutiesDependentRoots(ctx, [32]byte{'x'}, 1); err == nil {
+		t.Error("Expected error for unknown block")
 	}
+}
 
-	pubkeysAs48ByteType := make([][48]byte, len(pubKeys))
-	for i, pk := range pubKeys {
-		pubkeysAs48ByteType[i] = bytesutil.ToBytes48(pk)
+func TestStreamDuties_OK_ChainReorg(t *testing.T) {
+	// The state saved in the database needs the full size vectors of the mainnet config, skipped
+	// slots must not resume from states of the minimal config cached by other tests.
+	testutil.ResetCache()
+	params.SetupTestConfigCleanup(t)
+	params.OverrideBeaconConfig(params.MainnetConfig())
+	state.SkipSlotCache.Disable()
+	defer state.SkipSlotCache.Enable()
+	db := dbutil.SetupDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	bs, _ := testutil.DeterministicGenesisState(t, 64)
+	_, forkA, childA, forkB := setupForks(t, db)
+	// Only the state of the fork is saved, recomputing the duties from the state of the other new
+	// head fails the stream.
+	if err := db.SaveState(ctx, bs, forkB); err != nil {
+		t.Fatal(err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	secondsPerEpoch := params.BeaconConfig().SecondsPerSlot * params.BeaconConfig().SlotsPerEpoch
 	vs := &Server{
 		Ctx:         ctx,
 		BeaconDB:    db,
-		HeadFetcher: &mockChain.ChainService{State: bs, Root: genesisRoot[:]},
+		HeadFetcher: &mockChain.ChainService{State: bs.Copy(), Root: forkA[:]},
 		SyncChecker: &mockSync.Sync{IsSyncing: false},
 		GenesisTimeFetcher: &mockChain.ChainService{
-			Genesis: time.Now(),
+			Genesis: time.Now().Add(-time.Duration(secondsPerEpoch+secondsPerEpoch/2) * time.Second),
 		},
 		StateNotifier: &mockChain.MockStateNotifier{},
 	}
 
 	// Test the first validator in registry.
 	req := &ethpb.DutiesRequest{
-		PublicKeys: [][]byte{deposits[0].Data.PublicKey},
+		PublicKeys: [][]byte{bs.Validators()[0].PublicKey},
+		Epoch:      1,
 	}
-	wantedRes, err := vs.duties(ctx, req)
+	wantedRes, err := vs.dutiesFromState(ctx, bs.Copy(), req)
 	if err != nil {
 		t.Fatal(err)
 	}
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	exitRoutine := make(chan bool)
+	exitRoutine := make(chan bool, 2)
 	mockStream := mock.NewMockBeaconNodeValidator_StreamDutiesServer(ctrl)
 	mockStream.EXPECT().Send(wantedRes).Return(nil)
 	mockStream.EXPECT().Send(wantedRes).Do(func(arg0 interface{}) {
@@ -443,22 +496,23 @@ func TestStreamDuties_OK_ChainReorg(t *testing.T) {
 	go func(tt *testing.T) {
 		if err := vs.StreamDuties(req, mockStream); err != nil && !strings.Contains(err.Error(), "context canceled") {
 			tt.Errorf("Could not call RPC method: %v", err)
+			exitRoutine <- true
 		}
 	}(t)
-	// Fire a reorg event within the same epoch. This should NOT
-	// trigger a recomputation not resending of duties over the stream.
+	// Fire a reorg event to a descendant of the block the duties depend on. This should NOT
+	// trigger a recomputation nor resending of duties over the stream.
 	for sent := 0; sent == 0; {
 		sent = vs.StateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Reorg,
-			Data: &statefeed.ReorgData{OldSlot: 0, NewSlot: 0},
+			Data: &statefeed.ReorgData{OldSlot: helpers.StartSlot(1) + 2, NewSlot: helpers.StartSlot(1) + 1, NewHeadRoot: childA},
 		})
 	}
-	// Fire a reorg event across epoch boundaries. This needs to trigger
+	// Fire a reorg event replacing the block the duties depend on. This needs to trigger
 	// a recomputation and resending of duties over the stream.
 	for sent := 0; sent == 0; {
 		sent = vs.StateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Reorg,
-			Data: &statefeed.ReorgData{OldSlot: helpers.StartSlot(1), NewSlot: 0},
+			Data: &statefeed.ReorgData{OldSlot: helpers.StartSlot(1) + 1, NewSlot: helpers.StartSlot(1) - 1, NewHeadRoot: forkB},
 		})
 	}
 	<-exitRoutine
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"context"
+	"time"
 
 	ptypes "github.com/gogo/protobuf/types"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -10,12 +11,14 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"go.opencensus.io/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -83,10 +86,12 @@ func (vs *Server) GetAttestationData(ctx context.Context, req *ethpb.Attestation
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not retrieve head root: %v", err)
 	}
+	// The states used below are on the chain of the head, so their recent roots are cached.
+	canonicalHeadRoot := bytesutil.ToBytes32(headRoot)
 
 	// In the case that we receive an attestation request after a newer state/block has been processed.
 	if headState.Slot() > req.Slot {
-		headRoot, err = helpers.BlockRootAtSlot(headState, req.Slot)
+		headRoot, err = helpers.HeadBlockRootAtSlot(canonicalHeadRoot, headState, req.Slot)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not get historical head root: %v", err)
 		}
@@ -119,7 +124,7 @@ func (vs *Server) GetAttestationData(ctx context.Context, req *ethpb.Attestation
 	if epochStartSlot == headState.Slot() {
 		targetRoot = headRoot[:]
 	} else {
-		targetRoot, err = helpers.BlockRootAtSlot(headState, epochStartSlot)
+		targetRoot, err = helpers.HeadBlockRootAtSlot(canonicalHeadRoot, headState, epochStartSlot)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not get target block for slot %d: %v", epochStartSlot, err)
 		}
@@ -156,6 +161,10 @@ func (vs *Server) ProposeAttestation(ctx context.Context, att *ethpb.Attestation
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not tree hash attestation: %v", err)
 	}
+	if err := vs.guardAttestation(ctx, att, root); err != nil {
+		return nil, err
+	}
+	tracer.Observe(att, tracer.Produced)
 
 	// Broadcast the unaggregated attestation on a feed to notify other services in the beacon node
 	// of a received unaggregated attestation.
@@ -187,7 +196,7 @@ func (vs *Server) ProposeAttestation(ctx context.Context, att *ethpb.Attestation
 
 // SubscribeCommitteeSubnets subscribes to the committee ID subnet given subscribe request.
 func (vs *Server) SubscribeCommitteeSubnets(ctx context.Context, req *ethpb.CommitteeSubnetsSubscribeRequest) (*ptypes.Empty, error) {
-	if len(req.Slots) != len(req.CommitteeIds) && len(req.CommitteeIds) != len(req.IsAggregator) {
+	if len(req.Slots) != len(req.CommitteeIds) || len(req.CommitteeIds) != len(req.IsAggregator) {
 		return nil, status.Error(codes.InvalidArgument, "request fields are not the same length")
 	}
 
@@ -195,8 +204,31 @@ func (vs *Server) SubscribeCommitteeSubnets(ctx context.Context, req *ethpb.Comm
 		cache.CommitteeIDs.AddAttesterCommiteeID(req.Slots[i], req.CommitteeIds[i])
 		if req.IsAggregator[i] {
 			cache.CommitteeIDs.AddAggregatorCommiteeID(req.Slots[i], req.CommitteeIds[i])
+			vs.findSubnetPeers(req.Slots[i], req.CommitteeIds[i])
 		}
 	}
 
 	return &ptypes.Empty{}, nil
 }
+
+// findSubnetPeers searches the network for peers subscribed to the subnet of the committee ID in
+// the background, so an aggregator has subnet peers to collect attestations from by its duty
+// slot. The search is abandoned once the duty slot starts.
+func (vs *Server) findSubnetPeers(slot uint64, committeeID uint64) {
+	if vs.PeerManager == nil {
+		return
+	}
+	slotStart := vs.GenesisTimeFetcher.GenesisTime().Add(
+		time.Duration(slot*params.BeaconConfig().SecondsPerSlot) * time.Second,
+	)
+	if !slotutil.Now().Before(slotStart) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithDeadline(vs.Ctx, slotStart)
+		defer cancel()
+		if _, err := vs.PeerManager.FindPeersWithSubnet(ctx, helpers.ComputeSubnetForCommittee(committeeID)); err != nil {
+			log.WithError(err).WithField("committeeID", committeeID).Debug("Could not search for subnet peers")
+		}
+	}()
+}
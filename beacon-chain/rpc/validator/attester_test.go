@@ -637,3 +637,82 @@ func TestGetAttestationData_SucceedsInFirstEpoch(t *testing.T) {
 		t.Errorf("Expected attestation info to match, received %v, wanted %v", res, expectedInfo)
 	}
 }
+
+type subnetPeerManager struct {
+	*mockp2p.TestP2P
+	searched chan uint64
+}
+
+func (m *subnetPeerManager) FindPeersWithSubnet(ctx context.Context, index uint64) (bool, error) {
+	m.searched <- index
+	return true, nil
+}
+
+func TestSubscribeCommitteeSubnets_SearchesPeersForAggregators(t *testing.T) {
+	peerManager := &subnetPeerManager{searched: make(chan uint64, 2)}
+	server := &Server{
+		Ctx:                context.Background(),
+		GenesisTimeFetcher: &mock.ChainService{Genesis: roughtime.Now()},
+		PeerManager:        peerManager,
+	}
+	req := &ethpb.CommitteeSubnetsSubscribeRequest{
+		Slots:        []uint64{4, 5},
+		CommitteeIds: []uint64{1, 2},
+		IsAggregator: []bool{false, true},
+	}
+	if _, err := server.SubscribeCommitteeSubnets(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case index := <-peerManager.searched:
+		if index != 2 {
+			t.Errorf("Searched subnet %d, wanted %d", index, 2)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Did not search for aggregator subnet peers")
+	}
+	select {
+	case index := <-peerManager.searched:
+		t.Errorf("Unexpected search for subnet %d", index)
+	default:
+	}
+}
+
+func TestSubscribeCommitteeSubnets_SkipsPastSlots(t *testing.T) {
+	peerManager := &subnetPeerManager{searched: make(chan uint64, 1)}
+	genesis := roughtime.Now().Add(-time.Duration(10*params.BeaconConfig().SecondsPerSlot) * time.Second)
+	server := &Server{
+		Ctx:                context.Background(),
+		GenesisTimeFetcher: &mock.ChainService{Genesis: genesis},
+		PeerManager:        peerManager,
+	}
+	req := &ethpb.CommitteeSubnetsSubscribeRequest{
+		Slots:        []uint64{2},
+		CommitteeIds: []uint64{3},
+		IsAggregator: []bool{true},
+	}
+	if _, err := server.SubscribeCommitteeSubnets(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if len(peerManager.searched) != 0 {
+		t.Error("Searched for peers of a subnet whose duty slot already started")
+	}
+}
+
+func TestSubscribeCommitteeSubnets_MismatchedLengths(t *testing.T) {
+	server := &Server{
+		Ctx:                context.Background(),
+		GenesisTimeFetcher: &mock.ChainService{Genesis: roughtime.Now()},
+		PeerManager:        &subnetPeerManager{searched: make(chan uint64, 1)},
+	}
+	reqs := []*ethpb.CommitteeSubnetsSubscribeRequest{
+		{Slots: []uint64{4, 5}, CommitteeIds: []uint64{1}, IsAggregator: []bool{true}},
+		{Slots: []uint64{4, 5}, CommitteeIds: []uint64{1, 2}, IsAggregator: []bool{true}},
+	}
+	for _, req := range reqs {
+		if _, err := server.SubscribeCommitteeSubnets(context.Background(), req); err == nil {
+			t.Errorf("Expected error for request fields of different lengths %v", req)
+		}
+	}
+}
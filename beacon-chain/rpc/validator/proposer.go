@@ -16,14 +16,13 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state/interop"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
-	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/trieutil"
 	"go.opencensus.io/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -121,6 +120,9 @@ func (vs *Server) ProposeBlock(ctx context.Context, blk *ethpb.SignedBeaconBlock
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not tree hash block: %v", err)
 	}
+	if err := vs.guardProposal(ctx, blk.Block, root); err != nil {
+		return nil, err
+	}
 
 	// Do not block proposal critical path with debug logging or block feed updates.
 	defer func() {
@@ -295,44 +297,28 @@ func (vs *Server) deposits(ctx context.Context, currentVote *ethpb.Eth1Data) ([]
 		return []*ethpb.Deposit{}, nil
 	}
 
-	upToEth1DataDeposits := vs.DepositFetcher.AllDeposits(ctx, latestEth1DataHeight)
-	depositData := [][]byte{}
-	for _, dep := range upToEth1DataDeposits {
-		depHash, err := ssz.HashTreeRoot(dep.Data)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not hash deposit data")
-		}
-		depositData = append(depositData, depHash[:])
-	}
-
-	depositTrie, err := trieutil.GenerateTrieFromItems(depositData, int(params.BeaconConfig().DepositContractTreeDepth))
-	if err != nil {
-		return nil, errors.Wrap(err, "could not generate historical deposit trie from deposits")
-	}
-
 	// Deposits need to be received in order of merkle index root, so this has to make sure
 	// deposits are sorted from lowest to highest.
-	var pendingDeps []*dbpb.DepositContainer
+	var indices []uint64
 	for _, dep := range allPendingContainers {
 		if uint64(dep.Index) >= headState.Eth1DepositIndex() && uint64(dep.Index) < canonicalEth1Data.DepositCount {
-			pendingDeps = append(pendingDeps, dep)
-		}
-	}
-
-	for i := range pendingDeps {
-		// Don't construct merkle proof if the number of deposits is more than max allowed in block.
-		if uint64(i) == params.BeaconConfig().MaxDeposits {
-			break
-		}
-		pendingDeps[i].Deposit, err = constructMerkleProof(depositTrie, int(pendingDeps[i].Index), pendingDeps[i].Deposit)
-		if err != nil {
-			return nil, err
+			indices = append(indices, uint64(dep.Index))
 		}
 	}
 	// Limit the return of pending deposits to not be more than max deposits allowed in block.
-	var pendingDeposits []*ethpb.Deposit
-	for i := 0; i < len(pendingDeps) && i < int(params.BeaconConfig().MaxDeposits); i++ {
-		pendingDeposits = append(pendingDeposits, pendingDeps[i].Deposit)
+	if uint64(len(indices)) > params.BeaconConfig().MaxDeposits {
+		indices = indices[:params.BeaconConfig().MaxDeposits]
+	}
+	if len(indices) == 0 {
+		return []*ethpb.Deposit{}, nil
+	}
+
+	// The proofs are against the deposit trie of all deposits up to the eth1 data block, read from
+	// a snapshot so deposits inserted meanwhile cannot change the deposit root under the proofs.
+	snapshot := vs.DepositFetcher.DepositSnapshotAtHeight(ctx, latestEth1DataHeight)
+	pendingDeposits, err := snapshot.DepositsWithProofs(indices)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get deposits with merkle proofs")
 	}
 	return pendingDeposits, nil
 }
@@ -379,38 +365,32 @@ func (vs *Server) defaultEth1DataResponse(ctx context.Context, currentHeight *bi
 		return nil, errors.Wrap(err, "could not fetch ETH1_FOLLOW_DISTANCE ancestor")
 	}
 	// Fetch all historical deposits up to an ancestor height.
-	depositsTillHeight, depositRoot := vs.DepositFetcher.DepositsNumberAndRootAtHeight(ctx, ancestorHeight)
-	if depositsTillHeight == 0 {
+	snapshot := vs.DepositFetcher.DepositSnapshotAtHeight(ctx, ancestorHeight)
+	if snapshot.DepositCount() == 0 {
 		return vs.ChainStartFetcher.ChainStartEth1Data(), nil
 	}
+	depositRoot := snapshot.DepositRoot()
 	return &ethpb.Eth1Data{
 		DepositRoot:  depositRoot[:],
 		BlockHash:    blockHash[:],
-		DepositCount: depositsTillHeight,
+		DepositCount: snapshot.DepositCount(),
 	}, nil
 }
 
-// This filters the input attestations to return a list of valid attestations to be packaged inside a beacon block.
+// This filters the input attestations to return a list of valid attestations to be packaged inside a beacon block,
+// selecting the attestations which reward the proposer the most.
 func (vs *Server) filterAttestationsForBlockInclusion(ctx context.Context, state *stateTrie.BeaconState, atts []*ethpb.Attestation) ([]*ethpb.Attestation, error) {
 	ctx, span := trace.StartSpan(ctx, "ProposerServer.filterAttestationsForBlockInclusion")
 	defer span.End()
 
-	validAtts := make([]*ethpb.Attestation, 0, len(atts))
-	inValidAtts := make([]*ethpb.Attestation, 0, len(atts))
-
-	// TODO(3916): Insert optimizations to sort out the most profitable attestations
-	for i, att := range atts {
-		if i == int(params.BeaconConfig().MaxAttestations) {
-			break
-		}
-
-		if _, err := blocks.ProcessAttestation(ctx, state, att); err != nil {
-			inValidAtts = append(inValidAtts, att)
-			continue
-
-		}
-		validAtts = append(validAtts, att)
+	validAtts, inValidAtts, err := selectAttestations(atts, int(params.BeaconConfig().MaxAttestations), func(att *ethpb.Attestation) bool {
+		_, err := blocks.ProcessAttestation(ctx, state, att)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	tracer.Pack(state.Slot(), atts, validAtts)
 
 	if err := vs.deleteAttsInPool(ctx, inValidAtts); err != nil {
 		return nil, err
@@ -442,18 +422,6 @@ func (vs *Server) deleteAttsInPool(ctx context.Context, atts []*ethpb.Attestatio
 	return nil
 }
 
-func constructMerkleProof(trie *trieutil.SparseMerkleTrie, index int, deposit *ethpb.Deposit) (*ethpb.Deposit, error) {
-	proof, err := trie.MerkleProof(index)
-	if err != nil {
-		return nil, errors.Wrapf(err, "could not generate merkle proof for deposit at index %d", index)
-	}
-	// For every deposit, we construct a Merkle proof using the powchain service's
-	// in-memory deposits trie, which is updated only once the state's LatestETH1Data
-	// property changes during a state transition after a voting period.
-	deposit.Proof = proof
-	return deposit, nil
-}
-
 func (vs *Server) packAttestations(ctx context.Context, slot uint64) ([]*ethpb.Attestation, error) {
 	ctx, span := trace.StartSpan(ctx, "validatorServer.packAttestations")
 	defer span.End()
@@ -468,20 +436,12 @@ func (vs *Server) packAttestations(ctx context.Context, slot uint64) ([]*ethpb.A
 		}
 	}
 
-	atts := vs.AttPool.AggregatedAttestations()
+	// Unaggregated attestations are considered along with the aggregated ones, as they may
+	// include attesters not covered by any aggregate.
+	atts := append(vs.AttPool.AggregatedAttestations(), vs.AttPool.UnaggregatedAttestations()...)
 	atts, err = vs.filterAttestationsForBlockInclusion(ctx, st, atts)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not filter attestations")
 	}
-
-	// If there is any room left in the block, consider unaggregated attestations as well.
-	if len(atts) < int(params.BeaconConfig().MaxAttestations) {
-		uAtts := vs.AttPool.UnaggregatedAttestations()
-		uAtts, err = vs.filterAttestationsForBlockInclusion(ctx, st, uAtts)
-		if len(uAtts)+len(atts) > int(params.BeaconConfig().MaxAttestations) {
-			uAtts = uAtts[:int(params.BeaconConfig().MaxAttestations)-len(atts)]
-		}
-		atts = append(atts, uAtts...)
-	}
 	return atts, nil
 }
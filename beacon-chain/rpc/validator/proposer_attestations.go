@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"math/bits"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+)
+
+// attestationsGroupKey identifies the attestations of the same data and committee size, of which
+// the aggregation bits are able to cover one another.
+type attestationsGroupKey struct {
+	dataRoot [32]byte
+	bitsLen  uint64
+}
+
+// attestationCandidate is an attestation to be considered for block inclusion, along with the
+// aggregation bits covered by the selected attestations of its group.
+type attestationCandidate struct {
+	att     *ethpb.Attestation
+	covered *bitfield.Bitlist
+}
+
+// selectAttestations selects up to max attestations for block inclusion, maximizing the number
+// of distinct attesters the proposer is rewarded for. Attestations of the same data only reward
+// the attesters not included yet, so attestations are picked greedily by the number of attesters
+// they add to the selected attestations of the same data, preferring the most recent attestations
+// among equals as they are rewarded more for their inclusion delay. Each picked attestation is
+// verified with the valid function, the attestations failing it are returned as invalid.
+func selectAttestations(atts []*ethpb.Attestation, max int, valid func(*ethpb.Attestation) bool) ([]*ethpb.Attestation, []*ethpb.Attestation, error) {
+	covered := make(map[attestationsGroupKey]*bitfield.Bitlist)
+	candidates := make([]*attestationCandidate, 0, len(atts))
+	for _, att := range atts {
+		dataRoot, err := stateutil.AttestationDataRoot(att.Data)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not hash attestation data")
+		}
+		key := attestationsGroupKey{dataRoot: dataRoot, bitsLen: att.AggregationBits.Len()}
+		if _, ok := covered[key]; !ok {
+			bits := bitfield.NewBitlist(key.bitsLen)
+			covered[key] = &bits
+		}
+		candidates = append(candidates, &attestationCandidate{att: att, covered: covered[key]})
+	}
+
+	selected := make([]*ethpb.Attestation, 0, max)
+	var invalid []*ethpb.Attestation
+	for len(selected) < max && len(candidates) > 0 {
+		best, bestCount := -1, 0
+		for i, c := range candidates {
+			count := uncoveredBitsCount(c.att.AggregationBits, *c.covered)
+			if count > bestCount || (count == bestCount && best >= 0 && c.att.GetData().GetSlot() > candidates[best].att.GetData().GetSlot()) {
+				best, bestCount = i, count
+			}
+		}
+		if best < 0 {
+			// None of the attestations left adds any attester.
+			break
+		}
+		c := candidates[best]
+		candidates[best] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+		if !valid(c.att) {
+			invalid = append(invalid, c.att)
+			continue
+		}
+		*c.covered = c.covered.Or(c.att.AggregationBits)
+		selected = append(selected, c.att)
+	}
+	return selected, invalid, nil
+}
+
+// uncoveredBitsCount returns the number of bits set in the aggregation bits which are not set in
+// the covered bits of the same length.
+func uncoveredBitsCount(aggBits, covered bitfield.Bitlist) int {
+	count := 0
+	for i := range aggBits {
+		count += bits.OnesCount8(aggBits[i] &^ covered[i])
+	}
+	return count
+}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func bitlistWithBits(length uint64, indices ...uint64) bitfield.Bitlist {
+	bits := bitfield.NewBitlist(length)
+	for _, i := range indices {
+		bits.SetBitAt(i, true)
+	}
+	return bits
+}
+
+func TestSelectAttestations_MaxCoverage(t *testing.T) {
+	data := &ethpb.AttestationData{Slot: 1, Target: &ethpb.Checkpoint{}, Source: &ethpb.Checkpoint{}}
+	otherData := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 1, Target: &ethpb.Checkpoint{}, Source: &ethpb.Checkpoint{}}
+	a := &ethpb.Attestation{Data: data, AggregationBits: bitlistWithBits(8, 0, 1, 2, 3)}
+	b := &ethpb.Attestation{Data: data, AggregationBits: bitlistWithBits(8, 2, 3, 4)}
+	c := &ethpb.Attestation{Data: data, AggregationBits: bitlistWithBits(8, 4, 5)}
+	d := &ethpb.Attestation{Data: data, AggregationBits: bitlistWithBits(8, 1)}
+	e := &ethpb.Attestation{Data: otherData, AggregationBits: bitlistWithBits(8, 0, 1, 2)}
+	all := func(*ethpb.Attestation) bool { return true }
+
+	tests := []struct {
+		name        string
+		atts        []*ethpb.Attestation
+		max         int
+		valid       func(*ethpb.Attestation) bool
+		wantSelect  []*ethpb.Attestation
+		wantInvalid []*ethpb.Attestation
+	}{
+		{
+			name: "picks the attestations adding the most attesters",
+			atts: []*ethpb.Attestation{d, b, c, e, a},
+			max:  10,
+			// b adds a single attester after a, so c adding two is preferred. The attesters of b
+			// and d are covered once a and c are picked.
+			valid:      all,
+			wantSelect: []*ethpb.Attestation{a, e, c},
+		},
+		{
+			name:       "stops at max",
+			atts:       []*ethpb.Attestation{d, b, c, e, a},
+			max:        2,
+			valid:      all,
+			wantSelect: []*ethpb.Attestation{a, e},
+		},
+		{
+			name:        "skips invalid",
+			atts:        []*ethpb.Attestation{b, c, a},
+			max:         10,
+			valid:       func(att *ethpb.Attestation) bool { return att != a },
+			wantSelect:  []*ethpb.Attestation{b, c},
+			wantInvalid: []*ethpb.Attestation{a},
+		},
+		{
+			name:       "no attestations",
+			max:        10,
+			valid:      all,
+			wantSelect: []*ethpb.Attestation{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, invalid, err := selectAttestations(tt.atts, tt.max, tt.valid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(selected, tt.wantSelect) {
+				t.Errorf("Wanted selected %v, received %v", tt.wantSelect, selected)
+			}
+			if !reflect.DeepEqual(invalid, tt.wantInvalid) {
+				t.Errorf("Wanted invalid %v, received %v", tt.wantInvalid, invalid)
+			}
+		})
+	}
+}
+
+func TestSelectAttestations_PrefersRecent(t *testing.T) {
+	old := &ethpb.Attestation{
+		Data:            &ethpb.AttestationData{Slot: 1, Target: &ethpb.Checkpoint{}, Source: &ethpb.Checkpoint{}},
+		AggregationBits: bitlistWithBits(8, 0, 1),
+	}
+	recent := &ethpb.Attestation{
+		Data:            &ethpb.AttestationData{Slot: 2, Target: &ethpb.Checkpoint{}, Source: &ethpb.Checkpoint{}},
+		AggregationBits: bitlistWithBits(8, 0, 1),
+	}
+	selected, _, err := selectAttestations([]*ethpb.Attestation{old, recent}, 1, func(*ethpb.Attestation) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 1 || selected[0] != recent {
+		t.Errorf("Wanted the recent attestation selected, received %v", selected)
+	}
+}
+
+func BenchmarkSelectAttestations(b *testing.B) {
+	// Aggregates and unaggregated attestations of a full epoch of committees.
+	const committees, committeeSize = 64, 128
+	atts := make([]*ethpb.Attestation, 0, committees*(committeeSize/8+4))
+	for i := uint64(0); i < committees; i++ {
+		data := &ethpb.AttestationData{Slot: i / 2, CommitteeIndex: i % 2, Target: &ethpb.Checkpoint{}, Source: &ethpb.Checkpoint{}}
+		for j := uint64(0); j < 4; j++ {
+			bits := bitfield.NewBitlist(committeeSize)
+			for k := j * 24; k < j*24+48 && k < committeeSize; k++ {
+				bits.SetBitAt(k, true)
+			}
+			atts = append(atts, &ethpb.Attestation{Data: data, AggregationBits: bits})
+		}
+		for j := uint64(0); j < committeeSize; j += 8 {
+			atts = append(atts, &ethpb.Attestation{Data: data, AggregationBits: bitlistWithBits(committeeSize, j)})
+		}
+	}
+	valid := func(*ethpb.Attestation) bool { return true }
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := selectAttestations(atts, 128, valid); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
 	pbp2p "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -62,6 +63,7 @@ type Server struct {
 	StateNotifier          statefeed.Notifier
 	BlockNotifier          blockfeed.Notifier
 	P2P                    p2p.Broadcaster
+	PeerManager            p2p.PeerManager
 	AttPool                attestations.Pool
 	SlashingsPool          *slashings.Pool
 	ExitPool               *voluntaryexits.Pool
@@ -71,6 +73,7 @@ type Server struct {
 	PendingDepositsFetcher depositcache.PendingDepositsFetcher
 	OperationNotifier      opfeed.Notifier
 	StateGen               *stategen.State
+	SlashingGuard          *SlashingGuard
 }
 
 // WaitForActivation checks if a validator public key exists in the active validator registry of the current
@@ -118,11 +121,20 @@ func (vs *Server) WaitForActivation(req *ethpb.ValidatorActivationRequest, strea
 
 // ValidatorIndex is called by a validator to get its index location in the beacon state.
 func (vs *Server) ValidatorIndex(ctx context.Context, req *ethpb.ValidatorIndexRequest) (*ethpb.ValidatorIndexResponse, error) {
+	pubKey := bytesutil.ToBytes48(req.PublicKey)
+	index, ok, err := vs.BeaconDB.ValidatorIndex(ctx, pubKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not retrieve validator index: %v", err)
+	}
+	if ok {
+		return &ethpb.ValidatorIndexResponse{Index: index}, nil
+	}
+	// Validators which entered the registry after the finalized checkpoint are only in the head state.
 	st, err := vs.HeadFetcher.HeadState(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not determine head state: %v", err)
 	}
-	index, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(req.PublicKey))
+	index, ok = st.ValidatorIndexByPubkey(pubKey)
 	if !ok {
 		return nil, status.Errorf(codes.Internal, "Could not find validator index for public key %#x not found", req.PublicKey)
 	}
@@ -130,6 +142,18 @@ func (vs *Server) ValidatorIndex(ctx context.Context, req *ethpb.ValidatorIndexR
 	return &ethpb.ValidatorIndexResponse{Index: index}, nil
 }
 
+// validatorIndex returns the registry index of a validator, looking up the finalized indices
+// saved in the db before the given state's own index mapping.
+func (vs *Server) validatorIndex(ctx context.Context, st *stateTrie.BeaconState, pubKey []byte) (uint64, bool, error) {
+	key := bytesutil.ToBytes48(pubKey)
+	index, ok, err := vs.BeaconDB.ValidatorIndex(ctx, key)
+	if err != nil || ok {
+		return index, ok, err
+	}
+	index, ok = st.ValidatorIndexByPubkey(key)
+	return index, ok, nil
+}
+
 // DomainData fetches the current domain version information from the beacon state.
 func (vs *Server) DomainData(ctx context.Context, request *ethpb.DomainRequest) (*ethpb.DomainResponse, error) {
 	fork := vs.ForkFetcher.CurrentFork()
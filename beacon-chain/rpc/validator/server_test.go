@@ -23,6 +23,7 @@ import (
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
 	pbp2p "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/mock"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -59,6 +60,28 @@ func TestValidatorIndex_OK(t *testing.T) {
 	}
 }
 
+func TestValidatorIndex_FinalizedIndexFromDB(t *testing.T) {
+	db := dbutil.SetupDB(t)
+	ctx := context.Background()
+
+	pubKey := pubKey(1)
+	if err := db.SaveValidatorIndices(ctx, 0, [][48]byte{{}, bytesutil.ToBytes48(pubKey)}); err != nil {
+		t.Fatal(err)
+	}
+	Server := &Server{
+		BeaconDB:    db,
+		HeadFetcher: &mockChain.ChainService{State: testutil.NewBeaconState()},
+	}
+
+	res, err := Server.ValidatorIndex(ctx, &ethpb.ValidatorIndexRequest{PublicKey: pubKey})
+	if err != nil {
+		t.Fatalf("Could not get validator index: %v", err)
+	}
+	if res.Index != 1 {
+		t.Errorf("Wanted index %d, received %d", 1, res.Index)
+	}
+}
+
 func TestWaitForActivation_ContextClosed(t *testing.T) {
 	db := dbutil.SetupDB(t)
 	ctx := context.Background()
@@ -0,0 +1,176 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SlashingGuard remembers the blocks and attestations submitted over RPC by validator public
+// key, and refuses submissions which would be slashable with respect to earlier ones. It is a
+// second line of defense behind the slashing protection of validator clients, e.g. for a
+// validator client which lost its protection database.
+//
+// Submissions from before the finalized checkpoint are forgotten. A valid attestation has a
+// source at or after the finalized epoch, so it cannot surround, be surrounded by or double
+// vote with an attestation targeting an epoch before it, and blocks before the finalized
+// checkpoint cannot be included anymore.
+type SlashingGuard struct {
+	lock           sync.Mutex
+	proposals      map[[48]byte]map[uint64][32]byte
+	attestations   map[[48]byte]map[uint64]*guardedAttestation
+	finalizedEpoch uint64
+}
+
+// guardedAttestation is the source and data root of an attestation, by target epoch.
+type guardedAttestation struct {
+	source uint64
+	root   [32]byte
+}
+
+// NewSlashingGuard returns a slashing guard without any submissions.
+func NewSlashingGuard() *SlashingGuard {
+	return &SlashingGuard{
+		proposals:    make(map[[48]byte]map[uint64][32]byte),
+		attestations: make(map[[48]byte]map[uint64]*guardedAttestation),
+	}
+}
+
+// CheckAndRecordProposal returns an error if the proposer already submitted a different block
+// for the slot, and records the block root otherwise. Resubmissions of the same block pass.
+func (g *SlashingGuard) CheckAndRecordProposal(pubKey [48]byte, slot uint64, root [32]byte) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if existing, ok := g.proposals[pubKey][slot]; ok && existing != root {
+		return fmt.Errorf("double proposal of slot %d, block %#x was already submitted", slot, existing[:4])
+	}
+	if g.proposals[pubKey] == nil {
+		g.proposals[pubKey] = make(map[uint64][32]byte)
+	}
+	g.proposals[pubKey][slot] = root
+	return nil
+}
+
+// CheckAndRecordAttestation returns an error if an attestation with the given source, target
+// and data root would be a double vote, surround or be surrounded by an attestation submitted
+// earlier by any of the attesters, and records it for all of them otherwise. Resubmissions of
+// the same attestation data pass.
+func (g *SlashingGuard) CheckAndRecordAttestation(pubKeys [][48]byte, source uint64, target uint64, root [32]byte) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, pubKey := range pubKeys {
+		for t, att := range g.attestations[pubKey] {
+			switch {
+			case t == target && att.root != root:
+				return fmt.Errorf("double vote for target epoch %d by validator %#x", target, pubKey[:4])
+			case source < att.source && target > t:
+				return fmt.Errorf("vote from epoch %d to %d surrounds the vote from epoch %d to %d by validator %#x",
+					source, target, att.source, t, pubKey[:4])
+			case source > att.source && target < t:
+				return fmt.Errorf("vote from epoch %d to %d is surrounded by the vote from epoch %d to %d by validator %#x",
+					source, target, att.source, t, pubKey[:4])
+			}
+		}
+	}
+	for _, pubKey := range pubKeys {
+		if g.attestations[pubKey] == nil {
+			g.attestations[pubKey] = make(map[uint64]*guardedAttestation)
+		}
+		g.attestations[pubKey][target] = &guardedAttestation{source: source, root: root}
+	}
+	return nil
+}
+
+// Prune forgets the submissions from before the finalized epoch.
+func (g *SlashingGuard) Prune(finalizedEpoch uint64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if finalizedEpoch <= g.finalizedEpoch {
+		return
+	}
+	g.finalizedEpoch = finalizedEpoch
+	finalizedSlot := helpers.StartSlot(finalizedEpoch)
+	for pubKey, proposals := range g.proposals {
+		for slot := range proposals {
+			if slot < finalizedSlot {
+				delete(proposals, slot)
+			}
+		}
+		if len(proposals) == 0 {
+			delete(g.proposals, pubKey)
+		}
+	}
+	for pubKey, atts := range g.attestations {
+		for target := range atts {
+			if target < finalizedEpoch {
+				delete(atts, target)
+			}
+		}
+		if len(atts) == 0 {
+			delete(g.attestations, pubKey)
+		}
+	}
+}
+
+// guardProposal refuses a block which is slashable with respect to the blocks submitted
+// earlier by its proposer, if the slashing guard is enabled.
+func (vs *Server) guardProposal(ctx context.Context, blk *ethpb.BeaconBlock, root [32]byte) error {
+	if vs.SlashingGuard == nil {
+		return nil
+	}
+	headState, err := vs.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not get head state: %v", err)
+	}
+	if blk.ProposerIndex >= uint64(headState.NumValidators()) {
+		return status.Errorf(codes.InvalidArgument, "Unknown proposer index %d", blk.ProposerIndex)
+	}
+	vs.SlashingGuard.Prune(vs.FinalizationFetcher.FinalizedCheckpt().Epoch)
+	if err := vs.SlashingGuard.CheckAndRecordProposal(headState.PubkeyAtIndex(blk.ProposerIndex), blk.Slot, root); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "Refusing to broadcast slashable block: %v", err)
+	}
+	return nil
+}
+
+// guardAttestation refuses an attestation which is slashable with respect to the attestations
+// submitted earlier by any of its attesters, if the slashing guard is enabled.
+func (vs *Server) guardAttestation(ctx context.Context, att *ethpb.Attestation, root [32]byte) error {
+	if vs.SlashingGuard == nil {
+		return nil
+	}
+	if att.Data.Source == nil || att.Data.Target == nil {
+		return status.Error(codes.InvalidArgument, "Attestation has no source or target checkpoint")
+	}
+	headState, err := vs.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not get head state: %v", err)
+	}
+	committee, err := helpers.BeaconCommitteeFromState(headState, att.Data.Slot, att.Data.CommitteeIndex)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not get attestation committee: %v", err)
+	}
+	if att.AggregationBits.Len() != uint64(len(committee)) {
+		return status.Error(codes.InvalidArgument, "Attestation aggregation bits do not match the committee size")
+	}
+	indices, err := helpers.AttestingIndices(att.AggregationBits, committee)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not get attesting indices: %v", err)
+	}
+	pubKeys := make([][48]byte, len(indices))
+	for i, idx := range indices {
+		pubKeys[i] = headState.PubkeyAtIndex(idx)
+	}
+	vs.SlashingGuard.Prune(vs.FinalizationFetcher.FinalizedCheckpt().Epoch)
+	if err := vs.SlashingGuard.CheckAndRecordAttestation(pubKeys, att.Data.Source.Epoch, att.Data.Target.Epoch, root); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "Refusing to broadcast slashable attestation: %v", err)
+	}
+	return nil
+}
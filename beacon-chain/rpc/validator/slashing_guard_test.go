@@ -0,0 +1,170 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
+	mockp2p "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSlashingGuard_Proposals(t *testing.T) {
+	g := NewSlashingGuard()
+	alice, bob := [48]byte{'a'}, [48]byte{'b'}
+
+	if err := g.CheckAndRecordProposal(alice, 10, [32]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CheckAndRecordProposal(alice, 10, [32]byte{1}); err != nil {
+		t.Errorf("Wanted a resubmission to pass, received %v", err)
+	}
+	if err := g.CheckAndRecordProposal(alice, 10, [32]byte{2}); err == nil || !strings.Contains(err.Error(), "double proposal") {
+		t.Errorf("Wanted a double proposal error, received %v", err)
+	}
+	if err := g.CheckAndRecordProposal(bob, 10, [32]byte{2}); err != nil {
+		t.Errorf("Wanted a proposal of another validator to pass, received %v", err)
+	}
+	if err := g.CheckAndRecordProposal(alice, 11, [32]byte{2}); err != nil {
+		t.Errorf("Wanted a proposal of another slot to pass, received %v", err)
+	}
+
+	g.Prune(helpers.SlotToEpoch(10) + 1)
+	if err := g.CheckAndRecordProposal(alice, 10, [32]byte{2}); err != nil {
+		t.Errorf("Wanted proposals before the finalized checkpoint to be forgotten, received %v", err)
+	}
+}
+
+func TestSlashingGuard_Attestations(t *testing.T) {
+	alice := [48]byte{'a'}
+	tests := []struct {
+		name   string
+		source uint64
+		target uint64
+		root   [32]byte
+		want   string
+	}{
+		{name: "resubmission", source: 1, target: 4, root: [32]byte{1}},
+		{name: "double vote", source: 2, target: 4, root: [32]byte{2}, want: "double vote"},
+		{name: "surrounding", source: 0, target: 5, root: [32]byte{2}, want: "surrounds"},
+		{name: "surrounded", source: 2, target: 3, root: [32]byte{2}, want: "surrounded"},
+		{name: "next epoch", source: 4, target: 5, root: [32]byte{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewSlashingGuard()
+			if err := g.CheckAndRecordAttestation([][48]byte{alice}, 1, 4, [32]byte{1}); err != nil {
+				t.Fatal(err)
+			}
+			err := g.CheckAndRecordAttestation([][48]byte{alice}, tt.source, tt.target, tt.root)
+			if tt.want == "" && err != nil {
+				t.Errorf("Wanted no error, received %v", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("Wanted error containing %q, received %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSlashingGuard_AttestationsRecordedForAllOrNone(t *testing.T) {
+	g := NewSlashingGuard()
+	alice, bob := [48]byte{'a'}, [48]byte{'b'}
+	if err := g.CheckAndRecordAttestation([][48]byte{alice}, 2, 3, [32]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CheckAndRecordAttestation([][48]byte{bob, alice}, 2, 3, [32]byte{2}); err == nil {
+		t.Fatal("Wanted a double vote of alice to be refused")
+	}
+	// Bob's vote was refused along with alice's, so bob may still vote for the target.
+	if err := g.CheckAndRecordAttestation([][48]byte{bob}, 2, 3, [32]byte{3}); err != nil {
+		t.Errorf("Wanted the vote of bob to pass, received %v", err)
+	}
+
+	g.Prune(4)
+	if err := g.CheckAndRecordAttestation([][48]byte{alice}, 2, 3, [32]byte{4}); err != nil {
+		t.Errorf("Wanted attestations before the finalized checkpoint to be forgotten, received %v", err)
+	}
+}
+
+func TestProposeBlock_SlashingGuard(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	params.OverrideBeaconConfig(params.MainnetConfig())
+	beaconState, _ := testutil.DeterministicGenesisState(t, 64)
+
+	c := &mock.ChainService{State: beaconState, FinalizedCheckPoint: &ethpb.Checkpoint{}}
+	proposerServer := &Server{
+		BlockReceiver:       c,
+		HeadFetcher:         c,
+		FinalizationFetcher: c,
+		BlockNotifier:       c.BlockNotifier(),
+		AttPool:             attestations.NewPool(),
+		SlashingGuard:       NewSlashingGuard(),
+	}
+	blk := &ethpb.SignedBeaconBlock{
+		Block: &ethpb.BeaconBlock{
+			Slot:          5,
+			ProposerIndex: 3,
+			ParentRoot:    make([]byte, 32),
+			StateRoot:     make([]byte, 32),
+			Body:          &ethpb.BeaconBlockBody{Graffiti: make([]byte, 32)},
+		},
+	}
+	if _, err := proposerServer.ProposeBlock(context.Background(), blk); err != nil {
+		t.Fatalf("Could not propose block: %v", err)
+	}
+	blk.Block.Body.Graffiti = bytes.Repeat([]byte{'g'}, 32)
+	_, err := proposerServer.ProposeBlock(context.Background(), blk)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Wanted a double proposal to be refused, received %v", err)
+	}
+}
+
+func TestProposeAttestation_SlashingGuard(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	params.OverrideBeaconConfig(params.MainnetConfig())
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 64)
+
+	c := &mock.ChainService{State: beaconState, FinalizedCheckPoint: &ethpb.Checkpoint{}}
+	attesterServer := &Server{
+		HeadFetcher:         c,
+		FinalizationFetcher: c,
+		P2P:                 &mockp2p.MockBroadcaster{},
+		AttPool:             attestations.NewPool(),
+		OperationNotifier:   (&mock.ChainService{}).OperationNotifier(),
+		SlashingGuard:       NewSlashingGuard(),
+	}
+	committee, err := helpers.BeaconCommitteeFromState(beaconState, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := bitfield.NewBitlist(uint64(len(committee)))
+	bits.SetBitAt(0, true)
+	att := &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			Slot:            1,
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+		},
+		AggregationBits: bits,
+		Signature:       privKeys[0].Sign([]byte("signature")).Marshal(),
+	}
+	if _, err := attesterServer.ProposeAttestation(context.Background(), att); err != nil {
+		t.Fatalf("Could not propose attestation: %v", err)
+	}
+	att.Data.BeaconBlockRoot = bytes.Repeat([]byte{'r'}, 32)
+	_, err = attesterServer.ProposeAttestation(context.Background(), att)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Wanted a double vote to be refused, received %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"encoding/binary"
 	"reflect"
 	"sync"
 
@@ -19,6 +20,7 @@ type FieldTrie struct {
 	*reference
 	fieldLayers [][]*[32]byte
 	field       fieldIndex
+	numOfElems  int // The number of elements of a compressed array, which packs several into a leaf.
 }
 
 // NewFieldTrie is the constructor for the field trie data structure. It creates the corresponding
@@ -28,7 +30,7 @@ func NewFieldTrie(field fieldIndex, elements interface{}, length uint64) (*Field
 	if elements == nil {
 		return &FieldTrie{
 			field:     field,
-			reference: &reference{1},
+			reference: &reference{refs: 1},
 			Mutex:     new(sync.Mutex),
 		}, nil
 	}
@@ -45,16 +47,24 @@ func NewFieldTrie(field fieldIndex, elements interface{}, length uint64) (*Field
 		return &FieldTrie{
 			fieldLayers: stateutil.ReturnTrieLayer(fieldRoots, length),
 			field:       field,
-			reference:   &reference{1},
+			reference:   &reference{refs: 1},
 			Mutex:       new(sync.Mutex),
 		}, nil
 	case compositeArray:
 		return &FieldTrie{
 			fieldLayers: stateutil.ReturnTrieLayerVariable(fieldRoots, length),
 			field:       field,
-			reference:   &reference{1},
+			reference:   &reference{refs: 1},
 			Mutex:       new(sync.Mutex),
 		}, nil
+	case compressedArray:
+		return &FieldTrie{
+			fieldLayers: stateutil.ReturnTrieLayerVariable(fieldRoots, length),
+			field:       field,
+			reference:   &reference{refs: 1},
+			Mutex:       new(sync.Mutex),
+			numOfElems:  reflect.ValueOf(elements).Len(),
+		}, nil
 	default:
 		return nil, errors.Errorf("unrecognized data type in field map: %v", reflect.TypeOf(datType).Name())
 	}
@@ -75,6 +85,10 @@ func (f *FieldTrie) RecomputeTrie(indices []uint64, elements interface{}) ([32]b
 	if !ok {
 		return [32]byte{}, errors.Errorf("unrecognized field in trie")
 	}
+	if datType == compressedArray {
+		// The changed elements are recomputed by the leaves they are packed into.
+		indices = chunkIndices(indices)
+	}
 	fieldRoots, err := fieldConverters(f.field, indices, elements, false)
 	if err != nil {
 		return [32]byte{}, err
@@ -92,6 +106,13 @@ func (f *FieldTrie) RecomputeTrie(indices []uint64, elements interface{}) ([32]b
 			return [32]byte{}, err
 		}
 		return stateutil.AddInMixin(fieldRoot, uint64(len(f.fieldLayers[0])))
+	case compressedArray:
+		fieldRoot, f.fieldLayers, err = stateutil.RecomputeFromLayerVariable(fieldRoots, indices, f.fieldLayers)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		f.numOfElems = reflect.ValueOf(elements).Len()
+		return stateutil.AddInMixin(fieldRoot, uint64(f.numOfElems))
 	default:
 		return [32]byte{}, errors.Errorf("unrecognized data type in field map: %v", reflect.TypeOf(datType).Name())
 	}
@@ -104,7 +125,7 @@ func (f *FieldTrie) CopyTrie() *FieldTrie {
 	if f.fieldLayers == nil {
 		return &FieldTrie{
 			field:     f.field,
-			reference: &reference{1},
+			reference: &reference{refs: 1},
 			Mutex:     new(sync.Mutex),
 		}
 	}
@@ -121,8 +142,9 @@ func (f *FieldTrie) CopyTrie() *FieldTrie {
 	return &FieldTrie{
 		fieldLayers: dstFieldTrie,
 		field:       f.field,
-		reference:   &reference{1},
+		reference:   &reference{refs: 1},
 		Mutex:       new(sync.Mutex),
+		numOfElems:  f.numOfElems,
 	}
 }
 
@@ -138,6 +160,9 @@ func (f *FieldTrie) TrieRoot() ([32]byte, error) {
 	case compositeArray:
 		trieRoot := *f.fieldLayers[len(f.fieldLayers)-1][0]
 		return stateutil.AddInMixin(trieRoot, uint64(len(f.fieldLayers[0])))
+	case compressedArray:
+		trieRoot := *f.fieldLayers[len(f.fieldLayers)-1][0]
+		return stateutil.AddInMixin(trieRoot, uint64(f.numOfElems))
 	default:
 		return [32]byte{}, errors.Errorf("unrecognized data type in field map: %v", reflect.TypeOf(datType).Name())
 	}
@@ -167,6 +192,13 @@ func fieldConverters(field fieldIndex, indices []uint64, elements interface{}, c
 				reflect.TypeOf([]*ethpb.Validator{}).Name(), reflect.TypeOf(elements).Name())
 		}
 		return handleValidatorSlice(val, indices, convertAll)
+	case balances:
+		val, ok := elements.([]uint64)
+		if !ok {
+			return nil, errors.Errorf("Wanted type of %v but got %v",
+				reflect.TypeOf([]uint64{}).Name(), reflect.TypeOf(elements).Name())
+		}
+		return handleBalanceSlice(val, indices, convertAll)
 	case previousEpochAttestations, currentEpochAttestations:
 		val, ok := elements.([]*pb.PendingAttestation)
 		if !ok {
@@ -283,3 +315,44 @@ func handlePendingAttestation(val []*pb.PendingAttestation, indices []uint64, co
 	}
 	return roots, nil
 }
+
+// handleBalanceSlice returns the leaves of the given chunk indices, which pack balancesPerChunk
+// little endian balances each, or all the leaves of the balances.
+func handleBalanceSlice(val []uint64, indices []uint64, convertAll bool) ([][32]byte, error) {
+	chunk := func(idx uint64) [32]byte {
+		var leaf [32]byte
+		for i := uint64(0); i < balancesPerChunk; i++ {
+			if j := idx*balancesPerChunk + i; j < uint64(len(val)) {
+				binary.LittleEndian.PutUint64(leaf[i*8:], val[j])
+			}
+		}
+		return leaf
+	}
+	if convertAll {
+		roots := make([][32]byte, (len(val)+balancesPerChunk-1)/balancesPerChunk)
+		for i := range roots {
+			roots[i] = chunk(uint64(i))
+		}
+		return roots, nil
+	}
+	roots := make([][32]byte, 0, len(indices))
+	for _, idx := range indices {
+		if idx*balancesPerChunk >= uint64(len(val)) {
+			return nil, errors.Errorf("chunk index %d out of range of %d balances", idx, len(val))
+		}
+		roots = append(roots, chunk(idx))
+	}
+	return roots, nil
+}
+
+// chunkIndices returns the sorted indices of the leaves which the sorted element indices of a
+// compressed array are packed into.
+func chunkIndices(indices []uint64) []uint64 {
+	chunks := make([]uint64, 0, len(indices))
+	for _, idx := range indices {
+		if c := idx / balancesPerChunk; len(chunks) == 0 || chunks[len(chunks)-1] != c {
+			chunks = append(chunks, c)
+		}
+	}
+	return chunks
+}
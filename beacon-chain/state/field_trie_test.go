@@ -104,3 +104,46 @@ func TestFieldTrie_CopyTrieImmutable(t *testing.T) {
 		t.Errorf("Wanted roots to be different, but they are the same: %#x", root)
 	}
 }
+
+func TestFieldTrie_RecomputeTrie_Balances(t *testing.T) {
+	newState, _ := testutil.DeterministicGenesisState(t, 33)
+	balLimit := (params.BeaconConfig().ValidatorRegistryLimit*8 + 31) / 32
+	// 12 represents the enum value of balances.
+	trie, err := state.NewFieldTrie(12, newState.Balances(), balLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRoot, err := stateutil.ValidatorBalancesRoot(newState.Balances())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := trie.TrieRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != expectedRoot {
+		t.Errorf("Wanted root of %#x but got %#x", expectedRoot, root)
+	}
+
+	// Balances packed into the same leaf and appended balances are recomputed.
+	if err := newState.UpdateBalancesAtIndex(5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := newState.UpdateBalancesAtIndex(6, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := newState.AppendBalance(3); err != nil {
+		t.Fatal(err)
+	}
+	expectedRoot, err = stateutil.ValidatorBalancesRoot(newState.Balances())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err = trie.RecomputeTrie([]uint64{5, 6, 33}, newState.Balances())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != expectedRoot {
+		t.Errorf("Wanted root of %#x but got %#x", expectedRoot, root)
+	}
+}
@@ -274,6 +274,27 @@ func (b *BeaconState) BlockRootAtIndex(idx uint64) ([]byte, error) {
 	return root, nil
 }
 
+// StateRootAtIndex retrieves a specific state root based on an
+// input index value.
+func (b *BeaconState) StateRootAtIndex(idx uint64) ([]byte, error) {
+	if !b.HasInnerState() {
+		return nil, ErrNilInnerState
+	}
+	if b.state.StateRoots == nil {
+		return nil, nil
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	if len(b.state.StateRoots) <= int(idx) {
+		return nil, fmt.Errorf("index %d out of range", idx)
+	}
+	root := make([]byte, 32)
+	copy(root, b.state.StateRoots[idx])
+	return root, nil
+}
+
 // StateRoots kept track of in the beacon state.
 func (b *BeaconState) StateRoots() [][]byte {
 	if !b.HasInnerState() {
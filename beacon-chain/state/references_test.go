@@ -4,8 +4,10 @@ import (
 	"reflect"
 	"runtime"
 	"runtime/debug"
+	"sync"
 	"testing"
 
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-bitfield"
 	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
@@ -344,10 +346,51 @@ func TestStateReferenceCopy_NoUnexpectedAttestationsMutation(t *testing.T) {
 	assertRefCount(t, b, previousEpochAttestations, 1)
 }
 
+func TestStateReferenceCopy_ConcurrentCopies(t *testing.T) {
+	a, err := InitializeFromProtoUnsafe(&p2ppb.BeaconState{
+		Validators: []*ethpb.Validator{{PublicKey: []byte("foo")}},
+		Balances:   []uint64{32},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Copies of the same state share the references, the counters must not lose updates.
+	copies := make([]*BeaconState, 100)
+	var wg sync.WaitGroup
+	for i := range copies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			copies[i] = a.Copy()
+		}(i)
+	}
+	wg.Wait()
+	assertRefCount(t, a, validators, uint(len(copies)+1))
+	assertRefCount(t, a, balances, uint(len(copies)+1))
+
+	for i := range copies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := copies[i].UpdateBalancesAtIndex(0, uint64(i)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assertRefCount(t, a, balances, 1)
+	assertRefCount(t, a, validators, uint(len(copies)+1))
+	if a.Balances()[0] != 32 {
+		t.Errorf("Balance of the original state was mutated to %d", a.Balances()[0])
+	}
+	runtime.KeepAlive(copies)
+}
+
 // assertRefCount checks whether reference count for a given state
 // at a given index is equal to expected amount.
 func assertRefCount(t *testing.T, b *BeaconState, idx fieldIndex, want uint) {
-	if cnt := b.sharedFieldReferences[idx].refs; cnt != want {
+	if cnt := b.sharedFieldReferences[idx].Refs(); cnt != want {
 		t.Errorf("Unexpected count of references for index %d, want: %v, got: %v", idx, want, cnt)
 	}
 }
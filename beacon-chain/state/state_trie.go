@@ -47,7 +47,7 @@ func InitializeFromProtoUnsafe(st *pbp2p.BeaconState) (*BeaconState, error) {
 		b.dirtyIndices[fieldIndex(i)] = []uint64{}
 		b.stateFieldLeaves[fieldIndex(i)] = &FieldTrie{
 			field:     fieldIndex(i),
-			reference: &reference{1},
+			reference: &reference{refs: 1},
 			Mutex:     new(sync.Mutex),
 		}
 	}
@@ -157,7 +157,7 @@ func (b *BeaconState) Copy() *BeaconState {
 	// Finalizer runs when dst is being destroyed in garbage collection.
 	runtime.SetFinalizer(dst, func(b *BeaconState) {
 		for field, v := range b.sharedFieldReferences {
-			v.refs--
+			v.MinusRef()
 			if b.stateFieldLeaves[field].reference != nil {
 				b.stateFieldLeaves[field].MinusRef()
 			}
@@ -305,6 +305,23 @@ func (b *BeaconState) rootSelector(field fieldIndex) ([32]byte, error) {
 		}
 		return stateutil.ValidatorRegistryRoot(b.state.Validators)
 	case balances:
+		if featureconfig.Get().EnableFieldTrie {
+			// Rebuilding the trie is cheaper than recomputing the branches of most balances, which
+			// all change in epoch transitions.
+			if b.rebuildTrie[field] || len(b.dirtyIndices[field]) > len(b.state.Balances)/balancesPerChunk {
+				maxBalCap := params.BeaconConfig().ValidatorRegistryLimit
+				elemSize := uint64(8)
+				balLimit := (maxBalCap*elemSize + 31) / 32
+				err := b.resetFieldTrie(field, b.state.Balances, balLimit)
+				if err != nil {
+					return [32]byte{}, err
+				}
+				b.dirtyIndices[field] = []uint64{}
+				delete(b.rebuildTrie, field)
+				return b.stateFieldLeaves[field].TrieRoot()
+			}
+			return b.recomputeFieldTrie(balances, b.state.Balances)
+		}
 		return stateutil.ValidatorBalancesRoot(b.state.Balances)
 	case randaoMixes:
 		if featureconfig.Get().EnableFieldTrie {
@@ -364,7 +381,7 @@ func (b *BeaconState) rootSelector(field fieldIndex) ([32]byte, error) {
 
 func (b *BeaconState) recomputeFieldTrie(index fieldIndex, elements interface{}) ([32]byte, error) {
 	fTrie := b.stateFieldLeaves[index]
-	if fTrie.refs > 1 {
+	if fTrie.Refs() > 1 {
 		fTrie.Lock()
 		defer fTrie.Unlock()
 		fTrie.MinusRef()
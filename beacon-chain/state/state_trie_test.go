@@ -222,6 +222,29 @@ func TestBeaconState_HashTreeRoot_EnableFieldTrie(t *testing.T) {
 			},
 			error: "",
 		},
+		{
+			name: "different balances",
+			stateModify: func(beaconState *state.BeaconState) (*state.BeaconState, error) {
+				if err := beaconState.UpdateBalancesAtIndex(5, 1); err != nil {
+					return nil, err
+				}
+				if err := beaconState.UpdateBalancesAtIndex(63, 2); err != nil {
+					return nil, err
+				}
+				return beaconState, nil
+			},
+			error: "",
+		},
+		{
+			name: "appended balance",
+			stateModify: func(beaconState *state.BeaconState) (*state.BeaconState, error) {
+				if err := beaconState.AppendBalance(3); err != nil {
+					return nil, err
+				}
+				return beaconState, nil
+			},
+			error: "",
+		},
 	}
 
 	var err error
@@ -23,8 +23,8 @@ func (s *State) HasState(ctx context.Context, blockRoot [32]byte) bool {
 }
 
 // This saves a post finalized beacon state in the hot section of the DB. On the epoch boundary,
-// it saves a full state. On an intermediate slot, it saves a back pointer to the
-// nearest epoch boundary state.
+// it saves a full state or its diff from the last full state. On an intermediate slot, it saves
+// a back pointer to the nearest epoch boundary state.
 func (s *State) saveHotState(ctx context.Context, blockRoot [32]byte, state *state.BeaconState) error {
 	ctx, span := trace.StartSpan(ctx, "stateGen.saveHotState")
 	defer span.End()
@@ -34,14 +34,11 @@ func (s *State) saveHotState(ctx context.Context, blockRoot [32]byte, state *sta
 		return nil
 	}
 
-	// Only on an epoch boundary slot, saves the whole state.
+	// Only on an epoch boundary slot, saves the state.
 	if helpers.IsEpochStart(state.Slot()) {
-		if err := s.beaconDB.SaveState(ctx, state, blockRoot); err != nil {
+		if err := s.saveEpochBoundaryState(ctx, blockRoot, state); err != nil {
 			return err
 		}
-		log.WithFields(logrus.Fields{
-			"slot":      state.Slot(),
-			"blockRoot": hex.EncodeToString(bytesutil.Trunc(blockRoot[:]))}).Info("Saved full state on epoch boundary")
 	}
 
 	// On an intermediate slots, save the hot state summary.
@@ -56,6 +53,34 @@ func (s *State) saveHotState(ctx context.Context, blockRoot [32]byte, state *sta
 	return nil
 }
 
+// This saves an epoch boundary state in full once every hotStateSnapshotInterval epoch boundary
+// states, and the states in between as diffs from the last state saved in full, which cuts the
+// size written per epoch from the size of the state to the size of its changes.
+func (s *State) saveEpochBoundaryState(ctx context.Context, blockRoot [32]byte, state *state.BeaconState) error {
+	s.hotStateBaseLock.Lock()
+	defer s.hotStateBaseLock.Unlock()
+
+	if s.hotStateBase != nil && s.hotStateBase.diffs+1 < hotStateSnapshotInterval {
+		if err := s.beaconDB.SaveStateDiff(ctx, state, blockRoot, s.hotStateBase.root); err != nil {
+			return err
+		}
+		s.hotStateBase.diffs++
+		log.WithFields(logrus.Fields{
+			"slot":      state.Slot(),
+			"blockRoot": hex.EncodeToString(bytesutil.Trunc(blockRoot[:]))}).Info("Saved state diff on epoch boundary")
+		return nil
+	}
+
+	if err := s.beaconDB.SaveState(ctx, state, blockRoot); err != nil {
+		return err
+	}
+	s.hotStateBase = &hotStateBase{root: blockRoot}
+	log.WithFields(logrus.Fields{
+		"slot":      state.Slot(),
+		"blockRoot": hex.EncodeToString(bytesutil.Trunc(blockRoot[:]))}).Info("Saved full state on epoch boundary")
+	return nil
+}
+
 // This loads a post finalized beacon state from the hot section of the DB. If necessary it will
 // replay blocks starting from the nearest epoch boundary. It returns the beacon state that
 // corresponds to the input block root.
@@ -126,6 +151,9 @@ func (s *State) loadHotStateBySlot(ctx context.Context, slot uint64) (*state.Bea
 
 	// Gather last saved state, that is where node starts to replay the blocks.
 	startState, err := s.lastSavedState(ctx, slot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get last saved state for hot state using slot")
+	}
 
 	// Gather the last saved block root and the slot number.
 	lastValidRoot, lastValidSlot, err := s.lastSavedBlock(ctx, slot)
@@ -2,6 +2,7 @@ package stategen
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
@@ -72,6 +73,53 @@ func TestSaveHotState_CanSaveOnEpochBoundary(t *testing.T) {
 	testutil.AssertLogsContain(t, hook, "Saved full state on epoch boundary")
 }
 
+func TestSaveHotState_SavesDiffsBetweenSnapshots(t *testing.T) {
+	hook := logTest.NewGlobal()
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	service := New(db, cache.NewStateSummaryCache())
+
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+	for i := uint64(1); i <= hotStateSnapshotInterval+1; i++ {
+		st := beaconState.Copy()
+		if err := st.SetSlot(i * params.BeaconConfig().SlotsPerEpoch); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.UpdateBalancesAtIndex(0, i); err != nil {
+			t.Fatal(err)
+		}
+		r := [32]byte{byte(i)}
+		if err := service.saveHotState(ctx, r, st); err != nil {
+			t.Fatal(err)
+		}
+		received, err := db.State(ctx, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if received == nil {
+			t.Fatalf("Wanted epoch boundary state %d in db", i)
+		}
+		receivedRoot, err := received.HashTreeRoot(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantedRoot, err := st.HashTreeRoot(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receivedRoot != wantedRoot {
+			t.Errorf("Epoch boundary state %d does not match the saved state", i)
+		}
+	}
+
+	// The states after the first are saved as diffs until the snapshot interval is full, then the
+	// next state is saved in full.
+	if service.hotStateBase == nil || service.hotStateBase.root != [32]byte{hotStateSnapshotInterval + 1} {
+		t.Errorf("Wanted the last state to be saved in full, received base %v", service.hotStateBase)
+	}
+	testutil.AssertLogsContain(t, hook, "Saved state diff on epoch boundary")
+}
+
 func TestSaveHotState_NoSaveNotEpochBoundary(t *testing.T) {
 	hook := logTest.NewGlobal()
 	ctx := context.Background()
@@ -240,6 +288,17 @@ func TestLoadHoteStateBySlot_CanAdvanceSlotUsingDB(t *testing.T) {
 	}
 }
 
+func TestLoadHoteStateBySlot_NoSavedState(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	service := New(db, cache.NewStateSummaryCache())
+
+	_, err := service.loadHotStateBySlot(ctx, 10)
+	if err == nil || !strings.Contains(err.Error(), "could not get last saved state") {
+		t.Errorf("Wanted error for missing saved state, received %v", err)
+	}
+}
+
 func TestLastAncestorState_CanGet(t *testing.T) {
 	ctx := context.Background()
 	db := testDB.SetupDB(t)
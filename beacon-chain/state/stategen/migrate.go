@@ -92,6 +92,12 @@ func (s *State) MigrateToCold(ctx context.Context, finalizedSlot uint64, finaliz
 					log.Warnf("Unable to delete state during migration: %v", err)
 					continue
 				}
+				s.hotStateBaseLock.Lock()
+				if s.hotStateBase != nil && s.hotStateBase.root == r {
+					// The next epoch boundary state is saved in full, not as a diff from a deleted state.
+					s.hotStateBase = nil
+				}
+				s.hotStateBaseLock.Unlock()
 				log.WithFields(logrus.Fields{
 					"slot": stateSummary.Slot,
 					"root": hex.EncodeToString(bytesutil.Trunc(r[:])),
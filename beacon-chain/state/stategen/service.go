@@ -26,6 +26,19 @@ type State struct {
 	hotStateCache           *cache.HotStateCache
 	splitInfo               *splitSlotAndRoot
 	stateSummaryCache       *cache.StateSummaryCache
+	hotStateBase            *hotStateBase
+	hotStateBaseLock        sync.Mutex
+}
+
+// hotStateSnapshotInterval is the number of epoch boundary states sharing a hot state saved in
+// full. The first of them is saved in full, the following ones as diffs from it.
+const hotStateSnapshotInterval = 8
+
+// This tracks the last epoch boundary state saved in full and the number of states saved as
+// diffs from it.
+type hotStateBase struct {
+	root  [32]byte
+	diffs uint64
 }
 
 // This tracks the split point. The point where slot and the block root of
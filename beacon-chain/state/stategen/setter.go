@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"go.opencensus.io/trace"
 )
 
@@ -25,3 +26,40 @@ func (s *State) SaveState(ctx context.Context, root [32]byte, state *state.Beaco
 func (s *State) DeleteHotStateInCache(root [32]byte) {
 	s.hotStateCache.Delete(root)
 }
+
+// SaveStateSummariesToDB saves the state summaries cached since the last finalization to
+// the DB, so the hot states they refer to can still be regenerated after a restart.
+func (s *State) SaveStateSummariesToDB(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.SaveStateSummariesToDB")
+	defer span.End()
+
+	if err := s.beaconDB.SaveStateSummaries(ctx, s.stateSummaryCache.GetAll()); err != nil {
+		return err
+	}
+	s.stateSummaryCache.Clear()
+	return nil
+}
+
+// SaveFinalizedState saves a trusted finalized state the node starts from instead of genesis,
+// such as a weak subjectivity checkpoint state. It becomes the last archived point and the
+// split point, so states are resumed and regenerated from it.
+func (s *State) SaveFinalizedState(ctx context.Context, blockRoot [32]byte, state *state.BeaconState) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.SaveFinalizedState")
+	defer span.End()
+
+	if err := s.beaconDB.SaveState(ctx, state, blockRoot); err != nil {
+		return err
+	}
+	if err := s.beaconDB.SaveStateSummary(ctx, &pb.StateSummary{Slot: state.Slot(), Root: blockRoot[:]}); err != nil {
+		return err
+	}
+	archivedIndex := state.Slot() / s.slotsPerArchivedPoint
+	if err := s.beaconDB.SaveArchivedPointRoot(ctx, blockRoot, archivedIndex); err != nil {
+		return err
+	}
+	if err := s.beaconDB.SaveLastArchivedIndex(ctx, archivedIndex); err != nil {
+		return err
+	}
+	s.splitInfo = &splitSlotAndRoot{slot: state.Slot(), root: blockRoot}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	//"github.com/gogo/protobuf/proto"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 	logTest "github.com/sirupsen/logrus/hooks/test"
@@ -100,3 +101,54 @@ func TestSaveState_HotStateCached(t *testing.T) {
 	}
 	testutil.AssertLogsDoNotContain(t, hook, "Saved full state on epoch boundary")
 }
+
+func TestSaveStateSummariesToDB(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+
+	service := New(db, cache.NewStateSummaryCache())
+	r := [32]byte{'a'}
+	service.stateSummaryCache.Put(r, &pb.StateSummary{Slot: 1, Root: r[:]})
+
+	if err := service.SaveStateSummariesToDB(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !service.beaconDB.HasStateSummary(ctx, r) {
+		t.Error("Should have saved the state summary to the DB")
+	}
+	if service.stateSummaryCache.Has(r) {
+		t.Error("Should have cleared the state summary cache")
+	}
+}
+
+func TestSaveFinalizedState_ResumesFromState(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = params.BeaconConfig().SlotsPerEpoch
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+	slot := params.BeaconConfig().SlotsPerEpoch * 3
+	if err := beaconState.SetSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+
+	r := [32]byte{'a'}
+	if err := service.SaveFinalizedState(ctx, r, beaconState); err != nil {
+		t.Fatal(err)
+	}
+	if service.splitInfo.slot != slot || service.splitInfo.root != r {
+		t.Errorf("Wanted split point at slot %d, received %d", slot, service.splitInfo.slot)
+	}
+	if db.LastArchivedIndexRoot(ctx) != r {
+		t.Error("Did not save state as the last archived point")
+	}
+
+	resumed, err := New(db, cache.NewStateSummaryCache()).Resume(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed == nil || resumed.Slot() != slot {
+		t.Errorf("Wanted to resume from slot %d, received %v", slot, resumed)
+	}
+}
@@ -1,7 +1,6 @@
 package stateutil
 
 import (
-	"bytes"
 	"errors"
 	"sync"
 
@@ -77,7 +76,7 @@ func (h *stateRootHasher) arraysRoot(input [][]byte, length uint64, fieldName st
 	}
 
 	var res [32]byte
-	res = h.merkleizeWithCache(leaves, length, fieldName, hashFunc)
+	res = h.merkleizeWithCache(leaves, length, fieldName)
 	if h.rootsCache != nil {
 		leavesCache[fieldName] = leaves
 	}
@@ -85,7 +84,7 @@ func (h *stateRootHasher) arraysRoot(input [][]byte, length uint64, fieldName st
 }
 
 func (h *stateRootHasher) merkleizeWithCache(leaves [][32]byte, length uint64,
-	fieldName string, hasher func([]byte) [32]byte) [32]byte {
+	fieldName string) [32]byte {
 	if len(leaves) == 1 {
 		var root [32]byte
 		root = leaves[0]
@@ -99,7 +98,7 @@ func (h *stateRootHasher) merkleizeWithCache(leaves [][32]byte, length uint64,
 		}
 	}
 	layers[0] = hashLayer
-	layers, hashLayer = merkleizeTrieLeaves(layers, hashLayer, hasher)
+	layers, hashLayer = merkleizeTrieLeaves(layers, hashLayer)
 	var root [32]byte
 	root = hashLayer[0]
 	if h.rootsCache != nil {
@@ -108,25 +107,16 @@ func (h *stateRootHasher) merkleizeWithCache(leaves [][32]byte, length uint64,
 	return root
 }
 
-func merkleizeTrieLeaves(layers [][][32]byte, hashLayer [][32]byte,
-	hasher func([]byte) [32]byte) ([][][32]byte, [][32]byte) {
+func merkleizeTrieLeaves(layers [][][32]byte, hashLayer [][32]byte) ([][][32]byte, [][32]byte) {
 	// We keep track of the hash layers of a Merkle trie until we reach
 	// the top layer of length 1, which contains the single root element.
 	//        [Root]      -> Top layer has length 1.
 	//    [E]       [F]   -> This layer has length 2.
 	// [A]  [B]  [C]  [D] -> The bottom layer has length 4 (needs to be a power of two).
 	i := 1
-	chunkBuffer := bytes.NewBuffer([]byte{})
-	chunkBuffer.Grow(64)
 	for len(hashLayer) > 1 && i < len(layers) {
 		layer := make([][32]byte, len(hashLayer)/2, len(hashLayer)/2)
-		for i := 0; i < len(hashLayer); i += 2 {
-			chunkBuffer.Write(hashLayer[i][:])
-			chunkBuffer.Write(hashLayer[i+1][:])
-			hashedChunk := hasher(chunkBuffer.Bytes())
-			layer[i/2] = hashedChunk
-			chunkBuffer.Reset()
-		}
+		hashutil.HashPairs(layer, hashLayer)
 		hashLayer = layer
 		layers[i] = hashLayer
 		i++
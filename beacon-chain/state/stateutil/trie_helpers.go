@@ -1,8 +1,6 @@
 package stateutil
 
 import (
-	"bytes"
-
 	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/trieutil"
 )
@@ -11,7 +9,6 @@ import (
 // provided with the elements of a fixed sized trie and the corresponding depth of
 // it.
 func ReturnTrieLayer(elements [][32]byte, length uint64) [][]*[32]byte {
-	hasher := hashutil.CustomSHA256Hasher()
 	leaves := elements
 
 	if len(leaves) == 1 {
@@ -20,7 +17,7 @@ func ReturnTrieLayer(elements [][32]byte, length uint64) [][]*[32]byte {
 	hashLayer := leaves
 	layers := make([][][32]byte, GetDepth(length)+1)
 	layers[0] = hashLayer
-	layers, _ = merkleizeTrieLeaves(layers, hashLayer, hasher)
+	layers, _ = merkleizeTrieLeaves(layers, hashLayer)
 	refLayers := make([][]*[32]byte, len(layers))
 	for i, val := range layers {
 		refLayers[i] = make([]*[32]byte, len(val))
@@ -36,7 +33,6 @@ func ReturnTrieLayer(elements [][32]byte, length uint64) [][]*[32]byte {
 // provided with the elements of a variable sized trie and the corresponding depth of
 // it.
 func ReturnTrieLayerVariable(elements [][32]byte, length uint64) [][]*[32]byte {
-	hasher := hashutil.CustomSHA256Hasher()
 	depth := GetDepth(length)
 	layers := make([][]*[32]byte, depth+1)
 	// Return zerohash at depth
@@ -51,25 +47,19 @@ func ReturnTrieLayerVariable(elements [][32]byte, length uint64) [][]*[32]byte {
 		transformedLeaves[i] = &arr
 	}
 	layers[0] = transformedLeaves
-	buffer := bytes.NewBuffer([]byte{})
-	buffer.Grow(64)
+	hashLayer := elements
 	for i := 0; i < int(depth); i++ {
-		oddNodeLength := len(layers[i])%2 == 1
-		if oddNodeLength {
-			zerohash := trieutil.ZeroHashes[i]
-			layers[i] = append(layers[i], &zerohash)
-		}
-		updatedValues := make([]*[32]byte, 0, len(layers[i])/2)
-		for j := 0; j < len(layers[i]); j += 2 {
-			buffer.Write(layers[i][j][:])
-			buffer.Write(layers[i][j+1][:])
-			concat := hasher(buffer.Bytes())
-			updatedValues = append(updatedValues, &concat)
-			buffer.Reset()
+		chunks := hashLayer
+		if len(chunks)%2 == 1 {
+			chunks = make([][32]byte, len(hashLayer)+1)
+			copy(chunks, hashLayer)
+			chunks[len(hashLayer)] = trieutil.ZeroHashes[i]
 		}
-		// remove zerohash node from tree
-		if oddNodeLength {
-			layers[i] = layers[i][:len(layers[i])-1]
+		hashLayer = make([][32]byte, len(chunks)/2)
+		hashutil.HashPairs(hashLayer, chunks)
+		updatedValues := make([]*[32]byte, len(hashLayer))
+		for j := range hashLayer {
+			updatedValues[j] = &hashLayer[j]
 		}
 		layers[i+1] = updatedValues
 	}
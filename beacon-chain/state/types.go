@@ -24,6 +24,9 @@ func init() {
 	fieldMap[validators] = compositeArray
 	fieldMap[previousEpochAttestations] = compositeArray
 	fieldMap[currentEpochAttestations] = compositeArray
+
+	// Initialize the lists of basic types packed into chunks.
+	fieldMap[balances] = compressedArray
 }
 
 type fieldIndex int
@@ -64,8 +67,12 @@ const (
 const (
 	basicArray dataType = iota
 	compositeArray
+	compressedArray
 )
 
+// balancesPerChunk is the number of balances packed into a 32 byte chunk of the balances trie.
+const balancesPerChunk = 4
+
 // fieldMap keeps track of each field
 // to its corresponding data type.
 var fieldMap map[fieldIndex]dataType
@@ -74,9 +81,11 @@ var fieldMap map[fieldIndex]dataType
 // copy-on-write for shared fields or may modify a field in place when it holds the only reference
 // to the field value. References are tracked in a map of fieldIndex -> *reference. Whenever a state
 // releases their reference to the field value, they must decrement the refs. Likewise whenever a
-// copy is performed then the state must increment the refs counter.
+// copy is performed then the state must increment the refs counter. As references are shared
+// between states which lock independently, the counter is guarded by its own lock.
 type reference struct {
 	refs uint
+	lock sync.RWMutex
 }
 
 // ErrNilInnerState returns when the inner state is nil and no copy set or get
@@ -103,11 +112,24 @@ type ReadOnlyValidator struct {
 	validator *ethpb.Validator
 }
 
+// Refs returns the number of states holding the reference.
+func (r *reference) Refs() uint {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.refs
+}
+
+// AddRef increments the number of states holding the reference.
 func (r *reference) AddRef() {
+	r.lock.Lock()
 	r.refs++
+	r.lock.Unlock()
 }
 
+// MinusRef decrements the number of states holding the reference.
 func (r *reference) MinusRef() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	// Do not reduce further if object
 	// already has 0 reference to prevent overflow.
 	if r.refs == 0 {
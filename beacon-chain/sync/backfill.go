@@ -0,0 +1,192 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/rand"
+)
+
+// backfillBatchSize is the number of slots of blocks requested from a peer at once.
+const backfillBatchSize = 64
+
+// Check for blocks to backfill once per slot.
+func backfillPeriod() time.Duration {
+	return time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+}
+
+// backfill retrieves the blocks before the origin block of a node started from a checkpoint
+// state, so the history of the chain is available to archival queries. The blocks are requested
+// from the newest to the oldest, each batch is verified to link to the oldest block saved by
+// the parent roots before it is saved in turn.
+func (r *Service) backfill() {
+	ticker := time.NewTicker(backfillPeriod())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.ctx.Done():
+			log.Debug("Context closed, exiting backfill routine")
+			return
+		}
+		// Following the head of the chain takes priority over the history.
+		if r.initialSync != nil && r.initialSync.Syncing() {
+			continue
+		}
+		for {
+			done, err := r.backfillBatch(r.ctx)
+			if err != nil {
+				log.WithError(err).Debug("Could not backfill blocks")
+				break
+			}
+			if done {
+				return
+			}
+		}
+	}
+}
+
+// backfillBatch requests a batch of the blocks before the oldest block in the database, saving
+// those linking to it. It returns true once the genesis block is saved.
+func (r *Service) backfillBatch(ctx context.Context) (bool, error) {
+	origin, err := r.db.GenesisBlock(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "could not retrieve oldest block")
+	}
+	if origin == nil || origin.Block == nil {
+		return false, errors.New("no blocks in database")
+	}
+	if origin.Block.Slot == 0 {
+		return true, nil
+	}
+
+	// The cursor skips down over empty batches, as long as no block links to the oldest one.
+	if r.backfillSlot == 0 || r.backfillSlot > origin.Block.Slot {
+		r.backfillSlot = origin.Block.Slot
+	}
+	start := uint64(0)
+	if r.backfillSlot > backfillBatchSize {
+		start = r.backfillSlot - backfillBatchSize
+	}
+	req := &pb.BeaconBlocksByRangeRequest{
+		StartSlot: start,
+		Count:     r.backfillSlot - start,
+		Step:      1,
+	}
+
+	_, _, pids := r.p2p.Peers().BestFinalized(params.BeaconConfig().MaxPeersToSync, helpers.SlotToEpoch(origin.Block.Slot))
+	if len(pids) == 0 {
+		return false, errors.New("no peers to backfill from")
+	}
+	pid := pids[rand.Int()%len(pids)]
+	blks, err := r.sendBeaconBlocksByRangeRequest(ctx, req, pid)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not request blocks from peer %s", pid)
+	}
+	blks, err = linkToParent(blks, bytesutil.ToBytes32(origin.Block.ParentRoot), start, r.backfillSlot)
+	if err != nil {
+		r.p2p.Peers().IncrementBadResponses(pid)
+		// Look for the parent from the oldest block again.
+		r.backfillSlot = origin.Block.Slot
+		return false, errors.Wrapf(err, "invalid blocks from peer %s", pid)
+	}
+	if len(blks) == 0 {
+		if start == 0 {
+			r.backfillSlot = origin.Block.Slot
+			return false, fmt.Errorf("no blocks before slot %d from peer %s", origin.Block.Slot, pid)
+		}
+		r.backfillSlot = start
+		return false, nil
+	}
+
+	if err := r.db.SaveBlocks(ctx, blks); err != nil {
+		return false, errors.Wrap(err, "could not save backfilled blocks")
+	}
+	oldest := blks[len(blks)-1]
+	oldestRoot, err := stateutil.BlockRoot(oldest.Block)
+	if err != nil {
+		return false, err
+	}
+	// The oldest block saved takes the place of the genesis block.
+	if err := r.db.SaveGenesisBlockRoot(ctx, oldestRoot); err != nil {
+		return false, errors.Wrap(err, "could not save oldest block root")
+	}
+	r.backfillSlot = oldest.Block.Slot
+	log.WithFields(logrus.Fields{
+		"slot": oldest.Block.Slot,
+		"root": fmt.Sprintf("%#x", bytesutil.Trunc(oldestRoot[:])),
+	}).Debug("Backfilled blocks")
+	if oldest.Block.Slot == 0 {
+		log.Info("Backfilled blocks down to genesis")
+		return true, nil
+	}
+	return false, nil
+}
+
+// linkToParent sorts the blocks from the newest to the oldest and verifies each of them is the
+// parent of the one before, starting from the given parent root. The blocks must be within the
+// requested slots.
+func linkToParent(blks []*ethpb.SignedBeaconBlock, parentRoot [32]byte, start, end uint64) ([]*ethpb.SignedBeaconBlock, error) {
+	sort.Slice(blks, func(i, j int) bool {
+		return blks[i].Block.Slot > blks[j].Block.Slot
+	})
+	for _, blk := range blks {
+		if blk.Block.Slot < start || blk.Block.Slot >= end {
+			return nil, fmt.Errorf("block slot %d is outside of the requested slots", blk.Block.Slot)
+		}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, err
+		}
+		if root != parentRoot {
+			return nil, fmt.Errorf("block %#x at slot %d is not the parent %#x", root, blk.Block.Slot, parentRoot)
+		}
+		parentRoot = bytesutil.ToBytes32(blk.Block.ParentRoot)
+	}
+	return blks, nil
+}
+
+// sendBeaconBlocksByRangeRequest requests a range of blocks from a peer.
+func (r *Service) sendBeaconBlocksByRangeRequest(
+	ctx context.Context,
+	req *pb.BeaconBlocksByRangeRequest,
+	id peer.ID,
+) ([]*ethpb.SignedBeaconBlock, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	stream, err := r.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := stream.Reset(); err != nil {
+			log.WithError(err).Errorf("Failed to reset stream with protocol %s", stream.Protocol())
+		}
+	}()
+	blks := make([]*ethpb.SignedBeaconBlock, 0, req.Count)
+	for i := uint64(0); i < req.Count; i++ {
+		blk, err := ReadChunkedBlock(stream, r.p2p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		blks = append(blks, blk)
+	}
+	return blks, nil
+}
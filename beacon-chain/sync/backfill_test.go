@@ -0,0 +1,165 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// backfillChain returns a chain of blocks up to the slot, skipping every third slot.
+func backfillChain(t *testing.T, slot uint64) ([]*ethpb.SignedBeaconBlock, [][32]byte) {
+	var blks []*ethpb.SignedBeaconBlock
+	var roots [][32]byte
+	parentRoot := make([]byte, 32)
+	for i := uint64(0); i <= slot; i++ {
+		if i%3 == 2 {
+			continue
+		}
+		blk := testutil.NewBeaconBlock()
+		blk.Block.Slot = i
+		blk.Block.ParentRoot = parentRoot
+		blk.Block.Body.RandaoReveal = make([]byte, 96)
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blks = append(blks, blk)
+		roots = append(roots, root)
+		parentRoot = root[:]
+	}
+	return blks, roots
+}
+
+// serveBlocksByRange makes the peer serve the blocks by range requests from the blocks.
+func serveBlocksByRange(t *testing.T, p *p2ptest.TestP2P, blks []*ethpb.SignedBeaconBlock) {
+	pcl := protocol.ID("/eth2/beacon_chain/req/beacon_blocks_by_range/1/ssz")
+	p.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		req := &pb.BeaconBlocksByRangeRequest{}
+		if err := p.Encoding().DecodeWithLength(stream, req); err != nil {
+			t.Error(err)
+			return
+		}
+		for _, blk := range blks {
+			if blk.Block.Slot < req.StartSlot || blk.Block.Slot >= req.StartSlot+req.Count {
+				continue
+			}
+			if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := p.Encoding().EncodeWithLength(stream, blk); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if err := stream.Close(); err != nil {
+			t.Log(err)
+		}
+	})
+}
+
+func setupBackfill(t *testing.T, origin *ethpb.SignedBeaconBlock) (*Service, *p2ptest.TestP2P) {
+	db := dbtest.SetupDB(t)
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Peers().Add(new(enr.Record), p2.PeerID(), nil, network.DirOutbound)
+	p1.Peers().SetConnectionState(p2.PeerID(), peers.PeerConnected)
+	p1.Peers().SetChainState(p2.PeerID(), &pb.Status{FinalizedEpoch: 10})
+
+	ctx := context.Background()
+	if err := db.SaveBlock(ctx, origin); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(origin.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	return &Service{p2p: p1, db: db, ctx: ctx}, p2
+}
+
+func TestBackfillBatch_SavesBlocksDownToGenesis(t *testing.T) {
+	blks, roots := backfillChain(t, 200)
+	origin := blks[len(blks)-1]
+	r, p2 := setupBackfill(t, origin)
+	serveBlocksByRange(t, p2, blks)
+
+	ctx := context.Background()
+	for i := 0; ; i++ {
+		if i > 10 {
+			t.Fatal("Did not backfill blocks down to genesis")
+		}
+		done, err := r.backfillBatch(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			break
+		}
+	}
+
+	for i, root := range roots {
+		if !r.db.HasBlock(ctx, root) {
+			t.Errorf("Block at slot %d was not backfilled", blks[i].Block.Slot)
+		}
+	}
+	genesis, err := r.db.GenesisBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if genesis.Block.Slot != 0 {
+		t.Errorf("Wanted the genesis block as the oldest block, received slot %d", genesis.Block.Slot)
+	}
+	done, err := r.backfillBatch(ctx)
+	if err != nil || !done {
+		t.Errorf("Wanted backfill to be done, received %v, %v", done, err)
+	}
+}
+
+func TestBackfillBatch_RejectsUnlinkedBlocks(t *testing.T) {
+	blks, _ := backfillChain(t, 100)
+	origin := blks[len(blks)-1]
+	r, p2 := setupBackfill(t, origin)
+
+	// The peer serves blocks of another chain.
+	forged := make([]*ethpb.SignedBeaconBlock, len(blks)-1)
+	for i, blk := range blks[:len(blks)-1] {
+		forged[i] = testutil.NewBeaconBlock()
+		forged[i].Block.Slot = blk.Block.Slot
+		forged[i].Block.ParentRoot = blk.Block.ParentRoot
+		forged[i].Block.StateRoot = bytesutil.PadTo([]byte("forged"), 32)
+		forged[i].Block.Body.RandaoReveal = make([]byte, 96)
+	}
+	serveBlocksByRange(t, p2, forged)
+
+	ctx := context.Background()
+	if _, err := r.backfillBatch(ctx); err == nil {
+		t.Fatal("Expected error for blocks not linking to the oldest block")
+	}
+	if bad, err := r.p2p.Peers().BadResponses(p2.PeerID()); err != nil || bad != 1 {
+		t.Errorf("Wanted a bad response for the peer, received %d", bad)
+	}
+	for _, blk := range forged {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.db.HasBlock(ctx, root) {
+			t.Errorf("Saved forged block at slot %d", blk.Block.Slot)
+		}
+	}
+}
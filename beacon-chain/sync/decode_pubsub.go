@@ -1,13 +1,15 @@
 package sync
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/gogo/protobuf/proto"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
-	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 )
 
 func (r *Service) decodePubsubMessage(msg *pubsub.Message) (proto.Message, error) {
@@ -15,19 +17,42 @@ func (r *Service) decodePubsubMessage(msg *pubsub.Message) (proto.Message, error
 		return nil, errors.New("nil pubsub message")
 	}
 	topic := msg.TopicIDs[0]
-	topic = strings.TrimSuffix(topic, r.p2p.Encoding().ProtocolSuffix())
-	topic = r.replaceForkDigest(topic)
-	base, ok := p2p.GossipTopicMappings[topic]
-	if !ok {
-		return nil, fmt.Errorf("no message mapped for topic %s", topic)
+	// The message is decoded with the encoding given by the suffix of its topic, falling
+	// back to the configured encoding for topics without a known suffix.
+	e, err := encoder.ByProtocolID(topic)
+	if err != nil {
+		e = r.p2p.Encoding()
+	}
+	topic = strings.TrimSuffix(topic, e.ProtocolSuffix())
+	digest, err := topicForkDigest(topic)
+	if err != nil {
+		return nil, err
+	}
+	base, err := r.gossipMessage(digest, r.replaceForkDigest(topic))
+	if err != nil {
+		return nil, err
 	}
 	m := proto.Clone(base)
-	if err := r.p2p.Encoding().DecodeGossip(msg.Data, m); err != nil {
+	if err := e.DecodeGossip(msg.Data, m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
+// topicForkDigest returns the fork digest of a gossip topic, which is the second element of its
+// path.
+func topicForkDigest(topic string) ([4]byte, error) {
+	subStrings := strings.Split(topic, "/")
+	if len(subStrings) < 3 {
+		return [4]byte{}, fmt.Errorf("topic %s has no fork digest", topic)
+	}
+	digest, err := hex.DecodeString(subStrings[2])
+	if err != nil || len(digest) != 4 {
+		return [4]byte{}, fmt.Errorf("topic %s has an invalid fork digest", topic)
+	}
+	return bytesutil.ToBytes4(digest), nil
+}
+
 // Replaces our fork digest with the formatter.
 func (r *Service) replaceForkDigest(topic string) string {
 	subStrings := strings.Split(topic, "/")
@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mockChain "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestService_decodePubsubMessage_TopicEncoding(t *testing.T) {
+	r := &Service{
+		p2p:   p2ptest.NewTestP2P(t),
+		chain: &mockChain.ChainService{Genesis: time.Now(), ValidatorsRoot: [32]byte{'A'}},
+	}
+	digest, err := r.forkDigest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{
+		Block: &ethpb.BeaconBlock{
+			Slot:       5,
+			ParentRoot: make([]byte, 32),
+			StateRoot:  make([]byte, 32),
+			Body: &ethpb.BeaconBlockBody{
+				RandaoReveal: make([]byte, 96),
+				Graffiti:     make([]byte, 32),
+				Eth1Data: &ethpb.Eth1Data{
+					DepositRoot: make([]byte, 32),
+					BlockHash:   make([]byte, 32),
+				},
+			},
+		},
+		Signature: make([]byte, 96),
+	}
+
+	for _, e := range []*encoder.SszNetworkEncoder{{}, {UseSnappyCompression: true}} {
+		buf := new(bytes.Buffer)
+		if _, err := e.EncodeGossip(buf, blk); err != nil {
+			t.Fatal(err)
+		}
+		msg := &pubsub.Message{
+			Message: &pubsubpb.Message{
+				Data:     buf.Bytes(),
+				TopicIDs: []string{fmt.Sprintf("/eth2/%x/beacon_block", digest) + e.ProtocolSuffix()},
+			},
+		}
+		m, err := r.decodePubsubMessage(msg)
+		if err != nil {
+			t.Fatalf("Could not decode message of topic %s: %v", msg.TopicIDs[0], err)
+		}
+		if !proto.Equal(m, blk) {
+			t.Errorf("Decoded message of topic %s does not match the published block", msg.TopicIDs[0])
+		}
+	}
+}
+
+func TestService_decodePubsubMessage_UnknownForkDigest(t *testing.T) {
+	r := &Service{
+		p2p:   p2ptest.NewTestP2P(t),
+		chain: &mockChain.ChainService{Genesis: time.Now(), ValidatorsRoot: [32]byte{'A'}},
+	}
+	for _, topic := range []string{"/eth2/b5303f2a/beacon_block", "/eth2/zz/beacon_block"} {
+		msg := &pubsub.Message{
+			Message: &pubsubpb.Message{
+				Data:     []byte{},
+				TopicIDs: []string{topic + r.p2p.Encoding().ProtocolSuffix()},
+			},
+		}
+		if _, err := r.decodePubsubMessage(msg); err == nil {
+			t.Errorf("Expected an error decoding a message of topic %s", topic)
+		}
+	}
+}
+
+// gossipTopic returns the topic of the message under the genesis fork digest of the chain of
+// the service.
+func gossipTopic(t *testing.T, r *Service, msg proto.Message) string {
+	genRoot := r.chain.GenesisValidatorRoot()
+	digest, err := helpers.ComputeForkDigest(params.BeaconConfig().GenesisForkVersion, genRoot[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf(p2p.GossipTypeMapping[reflect.TypeOf(msg)], digest)
+}
@@ -0,0 +1,35 @@
+// +build libfuzzer
+
+package sync
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// NewRegularSyncFuzz creates a sync service with its caches initialized but without
+// registering any gossip or RPC handlers, so validators can be driven directly.
+func NewRegularSyncFuzz(cfg *Config) *Service {
+	r := newService(cfg)
+	if err := r.initCaches(); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// FuzzValidateBeaconBlockPubSub exports validateBeaconBlockPubSub for fuzz testing.
+func (r *Service) FuzzValidateBeaconBlockPubSub(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	return r.validateBeaconBlockPubSub(ctx, pid, msg)
+}
+
+// FuzzValidateCommitteeIndexBeaconAttestation exports validateCommitteeIndexBeaconAttestation for fuzz testing.
+func (r *Service) FuzzValidateCommitteeIndexBeaconAttestation(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	return r.validateCommitteeIndexBeaconAttestation(ctx, pid, msg)
+}
+
+// FuzzValidateAggregateAndProof exports validateAggregateAndProof for fuzz testing.
+func (r *Service) FuzzValidateAggregateAndProof(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	return r.validateAggregateAndProof(ctx, pid, msg)
+}
@@ -43,12 +43,16 @@ const (
 	// nonSkippedSlotsFullSearchEpochs how many epochs to check in full, before resorting to random
 	// sampling of slots once per epoch
 	nonSkippedSlotsFullSearchEpochs = 10
+	// maxEmptyBatchRetries is how many other peers are asked for a range after a peer returned no
+	// blocks for it, before the range is considered to have no blocks.
+	maxEmptyBatchRetries = 2
 )
 
 var (
-	errNoPeersAvailable = errors.New("no peers available, waiting for reconnect")
-	errFetcherCtxIsDone = errors.New("fetcher's context is done, reinitialize")
-	errSlotIsTooHigh    = errors.New("slot is higher than the finalized slot")
+	errNoPeersAvailable   = errors.New("no peers available, waiting for reconnect")
+	errFetcherCtxIsDone   = errors.New("fetcher's context is done, reinitialize")
+	errSlotIsTooHigh      = errors.New("slot is higher than the finalized slot")
+	errInvalidFetchedData = errors.New("invalid data returned from peer")
 )
 
 // blocksFetcherConfig is a config to setup the block fetcher.
@@ -269,10 +273,20 @@ func (f *blocksFetcher) fetchBlocksFromSinglePeer(
 		Count:     count,
 		Step:      1,
 	}
+	emptyBatches := 0
 	for i := 0; i < len(peers); i++ {
-		if blocks, err = f.requestBlocks(ctx, req, peers[i]); err == nil {
+		if blocks, err = f.requestBlocks(ctx, req, peers[i]); err != nil {
+			continue
+		}
+		if len(blocks) > 0 || emptyBatches == maxEmptyBatchRetries {
 			return
 		}
+		// Peers which do not serve the range return no blocks as well, so ask other peers before
+		// concluding that the range has no blocks.
+		emptyBatches++
+	}
+	if emptyBatches > 0 {
+		return []*eth.SignedBeaconBlock{}, nil
 	}
 	return
 }
@@ -470,14 +484,72 @@ func (f *blocksFetcher) requestBlocks(
 			break
 		}
 		if err != nil {
+			if isTimeout(err) {
+				f.penalizeTimeout(pid)
+			}
 			return nil, err
 		}
+		if err := verifyRangeBlock(req, resp, blk); err != nil {
+			f.downscorePeer(pid)
+			return nil, errors.Wrapf(errInvalidFetchedData, "%v", err)
+		}
 		resp = append(resp, blk)
 	}
 
 	return resp, nil
 }
 
+// verifyRangeBlock checks that the next block of a response to the request is within the
+// requested range, on a requested step and after the blocks received before.
+func verifyRangeBlock(req *p2ppb.BeaconBlocksByRangeRequest, received []*eth.SignedBeaconBlock, blk *eth.SignedBeaconBlock) error {
+	if blk == nil || blk.Block == nil {
+		return errors.New("nil block")
+	}
+	if uint64(len(received)) >= req.Count {
+		return errors.Errorf("more than %d blocks", req.Count)
+	}
+	step := mathutil.Max(req.Step, 1)
+	slot := blk.Block.Slot
+	if slot < req.StartSlot || slot >= req.StartSlot+req.Count*step || (slot-req.StartSlot)%step != 0 {
+		return errors.Errorf("block at slot %d out of the requested range", slot)
+	}
+	if len(received) > 0 && slot <= received[len(received)-1].Block.Slot {
+		return errors.Errorf("block at slot %d not in increasing order", slot)
+	}
+	return nil
+}
+
+// downscorePeer counts a bad response of the peer and disconnects it once it is marked as bad.
+func (f *blocksFetcher) downscorePeer(pid peer.ID) {
+	f.p2p.Peers().IncrementBadResponses(pid)
+	f.disconnectIfBad(pid)
+}
+
+// penalizeTimeout counts a timed out request to the peer and disconnects it once it is marked as bad.
+func (f *blocksFetcher) penalizeTimeout(pid peer.ID) {
+	f.p2p.Peers().IncrementRequestTimeouts(pid)
+	f.disconnectIfBad(pid)
+}
+
+// disconnectIfBad disconnects the peer if it is marked as bad.
+func (f *blocksFetcher) disconnectIfBad(pid peer.ID) {
+	if f.p2p.Peers().IsBad(pid) {
+		log.WithField("peer", pid).Debug("Disconnecting bad peer")
+		if err := f.p2p.Disconnect(pid); err != nil {
+			log.WithError(err).Error("Failed to disconnect peer")
+		}
+	}
+}
+
+// isTimeout returns whether the error is caused by the deadline of a request or its stream.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr interface{ Timeout() bool }
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
 // getPeerLock returns peer lock for a given peer. If lock is not found, it is created.
 func (f *blocksFetcher) getPeerLock(pid peer.ID) *peerLock {
 	f.Lock()
@@ -506,7 +578,8 @@ func (f *blocksFetcher) removeStalePeerLocks(age time.Duration) {
 	}
 }
 
-// selectFailOverPeer randomly selects fail over peer from the list of available peers.
+// selectFailOverPeer randomly selects fail over peer from the list of available peers, leaving
+// out peers marked as bad.
 func (f *blocksFetcher) selectFailOverPeer(excludedPID peer.ID, peers []peer.ID) (peer.ID, []peer.ID, error) {
 	for i, pid := range peers {
 		if pid == excludedPID {
@@ -514,6 +587,7 @@ func (f *blocksFetcher) selectFailOverPeer(excludedPID peer.ID, peers []peer.ID)
 			break
 		}
 	}
+	peers = f.withoutBadPeers(peers)
 
 	if len(peers) == 0 {
 		return "", peers, errNoPeersAvailable
@@ -562,6 +636,11 @@ func (f *blocksFetcher) filterPeers(peers []peer.ID, peersPercentage float64) []
 	randGenerator.Shuffle(len(peers), func(i, j int) {
 		peers[i], peers[j] = peers[j], peers[i]
 	})
+	peers = f.withoutBadPeers(peers)
+	if len(peers) == 0 {
+		return peers
+	}
+	f.sortPeersByPreference(peers)
 
 	// Select sub-sample from peers (honoring min-max invariants).
 	required := params.BeaconConfig().MaxPeersToSync
@@ -589,6 +668,52 @@ func (f *blocksFetcher) filterPeers(peers []peer.ID, peersPercentage float64) []
 	return peers
 }
 
+// withoutBadPeers returns the peers which are not marked as bad, like peers which keep returning
+// invalid batches.
+func (f *blocksFetcher) withoutBadPeers(peers []peer.ID) []peer.ID {
+	good := peers[:0]
+	for _, pid := range peers {
+		if !f.p2p.Peers().IsBad(pid) {
+			good = append(good, pid)
+		}
+	}
+	return good
+}
+
+// sortPeersByPreference orders peers by their preference for range requests: peers with a
+// higher score first, so peers with failed requests are used last, then peers advertising a
+// higher finalized epoch and head slot in their status, as they can serve more of the history.
+// The order of equally preferred peers is kept.
+func (f *blocksFetcher) sortPeersByPreference(peers []peer.ID) {
+	type preference struct {
+		score          float64
+		finalizedEpoch uint64
+		headSlot       uint64
+	}
+	preferences := make(map[peer.ID]preference, len(peers))
+	for _, pid := range peers {
+		var pref preference
+		if score, err := f.p2p.Peers().Score(pid); err == nil {
+			pref.score = score
+		}
+		if chainState, err := f.p2p.Peers().ChainState(pid); err == nil && chainState != nil {
+			pref.finalizedEpoch = chainState.FinalizedEpoch
+			pref.headSlot = chainState.HeadSlot
+		}
+		preferences[pid] = pref
+	}
+	sort.SliceStable(peers, func(i, j int) bool {
+		p1, p2 := preferences[peers[i]], preferences[peers[j]]
+		if p1.score != p2.score {
+			return p1.score > p2.score
+		}
+		if p1.finalizedEpoch != p2.finalizedEpoch {
+			return p1.finalizedEpoch > p2.finalizedEpoch
+		}
+		return p1.headSlot > p2.headSlot
+	})
+}
+
 // nonSkippedSlotAfter checks slots after the given one in an attempt to find a non-empty future slot.
 // For efficiency only one random slot is checked per epoch, so returned slot might not be the first
 // non-skipped slot. This shouldn't be a problem, as in case of adversary peer, we might get incorrect
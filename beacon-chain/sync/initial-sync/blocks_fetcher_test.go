@@ -2,6 +2,7 @@ package initialsync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
@@ -570,7 +571,7 @@ func TestBlocksFetcher_selectFailOverPeer(t *testing.T) {
 		excludedPID peer.ID
 		peers       []peer.ID
 	}
-	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{})
+	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{p2p: p2pt.NewTestP2P(t)})
 	tests := []struct {
 		name    string
 		args    args
@@ -742,7 +743,7 @@ func TestBlocksFetcher_filterPeers(t *testing.T) {
 		peers           []weightedPeer
 		peersPercentage float64
 	}
-	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{})
+	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{p2p: p2pt.NewTestP2P(t)})
 	tests := []struct {
 		name string
 		args args
@@ -1026,3 +1027,83 @@ func TestBlocksFetcher_removeStalePeerLocks(t *testing.T) {
 		})
 	}
 }
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "i/o deadline reached" }
+func (timeoutError) Timeout() bool { return true }
+
+func TestBlocksFetcher_isTimeout(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: timeoutError{}, want: true},
+		{err: fmt.Errorf("could not read chunk: %w", context.DeadlineExceeded), want: true},
+		{err: fmt.Errorf("could not read chunk: %w", timeoutError{}), want: true},
+		{err: errors.New("stream reset"), want: false},
+	}
+	for _, tt := range tests {
+		if got := isTimeout(tt.err); got != tt.want {
+			t.Errorf("isTimeout(%v) = %v, wanted %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestBlocksFetcher_verifyRangeBlock(t *testing.T) {
+	req := &p2ppb.BeaconBlocksByRangeRequest{StartSlot: 64, Count: 4, Step: 2}
+	block := func(slot uint64) *eth.SignedBeaconBlock {
+		return &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: slot}}
+	}
+	tests := []struct {
+		name     string
+		received []*eth.SignedBeaconBlock
+		block    *eth.SignedBeaconBlock
+		valid    bool
+	}{
+		{name: "first block", block: block(64), valid: true},
+		{name: "next block", received: []*eth.SignedBeaconBlock{block(64)}, block: block(68), valid: true},
+		{name: "last slot", block: block(70), valid: true},
+		{name: "nil block", block: &eth.SignedBeaconBlock{}},
+		{name: "before range", block: block(62)},
+		{name: "after range", block: block(72)},
+		{name: "off step", block: block(65)},
+		{name: "not increasing", received: []*eth.SignedBeaconBlock{block(66)}, block: block(66)},
+		{name: "too many blocks", received: []*eth.SignedBeaconBlock{block(64), block(66), block(68), block(70)}, block: block(70)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyRangeBlock(req, tt.received, tt.block); (err == nil) != tt.valid {
+				t.Errorf("verifyRangeBlock() error = %v, wanted valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestBlocksFetcher_filterPeers_Preference(t *testing.T) {
+	p := p2pt.NewTestP2P(t)
+	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{p2p: p})
+	chainStates := map[peer.ID]*p2ppb.Status{
+		"low-head":   {FinalizedEpoch: 10, HeadSlot: 400},
+		"high-head":  {FinalizedEpoch: 10, HeadSlot: 500},
+		"finalized":  {FinalizedEpoch: 12, HeadSlot: 450},
+		"timed-out":  {FinalizedEpoch: 12, HeadSlot: 500},
+		"bad-blocks": {FinalizedEpoch: 12, HeadSlot: 500},
+	}
+	pids := make([]peer.ID, 0, len(chainStates))
+	for pid, chainState := range chainStates {
+		p.Peers().Add(nil, pid, nil, network.DirOutbound)
+		p.Peers().SetChainState(pid, chainState)
+		pids = append(pids, pid)
+	}
+	p.Peers().IncrementRequestTimeouts("timed-out")
+	for !p.Peers().IsBad("bad-blocks") {
+		p.Peers().IncrementBadResponses("bad-blocks")
+	}
+
+	got := fetcher.filterPeers(pids, 1.0)
+	want := []peer.ID{"finalized", "high-head", "low-head", "timed-out"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterPeers() got = %v, want %v", got, want)
+	}
+}
@@ -298,11 +298,13 @@ func TestBlocksQueueLoop(t *testing.T) {
 			}
 
 			var blocks []*eth.SignedBeaconBlock
-			for block := range queue.fetchedBlocks {
-				if err := processBlock(block); err != nil {
-					continue
+			for batch := range queue.fetchedBlocks {
+				for _, block := range batch {
+					if err := processBlock(block); err != nil {
+						continue
+					}
+					blocks = append(blocks, block)
 				}
-				blocks = append(blocks, block)
 			}
 
 			if err := queue.stop(); err != nil {
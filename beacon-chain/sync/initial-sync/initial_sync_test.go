@@ -188,7 +188,7 @@ func connectPeers(t *testing.T, host *p2pt.TestP2P, data []*peerData, peerStatus
 				t.Error(err)
 			}
 
-			requestedBlocks := makeSequence(req.StartSlot, req.StartSlot+(req.Count*req.Step))
+			requestedBlocks := makeSequence(req.StartSlot, req.StartSlot+(req.Count-1)*req.Step)
 
 			// Expected failure range
 			if len(sliceutil.IntersectionUint64(datum.failureSlots, requestedBlocks)) > 0 {
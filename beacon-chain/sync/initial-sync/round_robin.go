@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/paulbellamy/ratecounter"
+	"github.com/pkg/errors"
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	blockfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/block"
@@ -56,15 +57,24 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 	}
 
 	// Step 1 - Sync to end of finalized epoch.
-	for blk := range queue.fetchedBlocks {
-		s.logSyncStatus(genesis, blk.Block, counter)
-		root, err := stateutil.BlockRoot(blk.Block)
-		if err != nil {
-			log.WithError(err).Info("Cannot determine root of block")
+	for blks := range queue.fetchedBlocks {
+		if featureconfig.Get().InitSyncNoVerify {
+			for _, blk := range blks {
+				s.logSyncStatus(genesis, blk.Block, counter)
+				root, err := stateutil.BlockRoot(blk.Block)
+				if err != nil {
+					log.WithError(err).Info("Cannot determine root of block")
+					continue
+				}
+				if err := s.processBlock(ctx, blk, root); err != nil {
+					log.WithError(err).Info("Block is invalid")
+					continue
+				}
+			}
 			continue
 		}
-		if err := s.processBlock(ctx, blk, root); err != nil {
-			log.WithError(err).Info("Block is invalid")
+		if err := s.processBatchedBlocks(ctx, genesis, blks, counter); err != nil {
+			log.WithError(err).Info("Batch of blocks is invalid")
 			continue
 		}
 	}
@@ -112,14 +122,14 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 
 		for _, blk := range resp {
 			s.logSyncStatus(genesis, blk.Block, counter)
-			root, err := stateutil.BlockRoot(blk.Block)
-			if err != nil {
-				return err
-			}
-			if err := s.chain.ReceiveBlockNoPubsubForkchoice(ctx, blk, root); err != nil {
-				log.WithError(err).Error("Failed to process block, exiting init sync")
-				return nil
-			}
+		}
+		roots, err := blockRoots(resp)
+		if err != nil {
+			return err
+		}
+		if err := s.chain.ReceiveBlockBatch(ctx, resp, roots); err != nil {
+			log.WithError(err).Error("Failed to process block, exiting init sync")
+			return nil
 		}
 		if len(resp) == 0 {
 			break
@@ -180,14 +190,42 @@ func (s *Service) processBlock(ctx context.Context, blk *eth.SignedBeaconBlock,
 		Type: blockfeed.ReceivedBlock,
 		Data: &blockfeed.ReceivedBlockData{SignedBlock: blk},
 	})
-	if featureconfig.Get().InitSyncNoVerify {
-		if err := s.chain.ReceiveBlockNoVerify(ctx, blk, blockRoot); err != nil {
-			return err
-		}
-	} else {
-		if err := s.chain.ReceiveBlockNoPubsubForkchoice(ctx, blk, blockRoot); err != nil {
-			return err
+	return s.chain.ReceiveBlockNoVerify(ctx, blk, blockRoot)
+}
+
+// processBatchedBlocks processes a batch of consecutive blocks. The chain service verifies the
+// proposer signatures of the whole batch concurrently, then applies the blocks one at a time.
+func (s *Service) processBatchedBlocks(ctx context.Context, genesis time.Time, blks []*eth.SignedBeaconBlock, counter *ratecounter.RateCounter) error {
+	if len(blks) == 0 {
+		return nil
+	}
+	roots, err := blockRoots(blks)
+	if err != nil {
+		return err
+	}
+	parentRoot := bytesutil.ToBytes32(blks[0].Block.ParentRoot)
+	if !s.db.HasBlock(ctx, parentRoot) && !s.chain.HasInitSyncBlock(parentRoot) {
+		return fmt.Errorf("beacon node doesn't have a block in db with root %#x", blks[0].Block.ParentRoot)
+	}
+	for _, blk := range blks {
+		s.logSyncStatus(genesis, blk.Block, counter)
+		s.blockNotifier.BlockFeed().Send(&feed.Event{
+			Type: blockfeed.ReceivedBlock,
+			Data: &blockfeed.ReceivedBlockData{SignedBlock: blk},
+		})
+	}
+	return s.chain.ReceiveBlockBatch(ctx, blks, roots)
+}
+
+// blockRoots computes the roots of a batch of blocks.
+func blockRoots(blks []*eth.SignedBeaconBlock) ([][32]byte, error) {
+	roots := make([][32]byte, len(blks))
+	for i, blk := range blks {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not determine root of block at slot %d", blk.Block.Slot)
 		}
+		roots[i] = root
 	}
-	return nil
+	return roots, nil
 }
@@ -18,7 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/shared"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/sirupsen/logrus"
 )
 
@@ -106,7 +106,7 @@ func (s *Service) Start() {
 		genesis = time.Unix(int64(headState.GenesisTime()), 0)
 	}
 
-	if genesis.After(roughtime.Now()) {
+	if genesis.After(slotutil.Now()) {
 		s.synced = true
 		s.stateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Synced,
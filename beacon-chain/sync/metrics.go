@@ -32,6 +32,13 @@ var (
 		},
 		[]string{"topic"},
 	)
+	messageIgnoredValidationCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_message_ignored_validation_total",
+			Help: "Count of messages that were ignored by validation without penalizing the sender.",
+		},
+		[]string{"topic"},
+	)
 	messageFailedProcessingCounter = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "p2p_message_failed_processing_total",
@@ -39,6 +46,26 @@ var (
 		},
 		[]string{"topic"},
 	)
+	messageHandlerPanicCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_message_handler_panic_total",
+			Help: "Count of messages whose validation or handling panicked.",
+		},
+		[]string{"topic"},
+	)
+	malformedMessageCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "p2p_malformed_gossip_message_total",
+			Help: "Count of gossip messages rejected by the structural checks before any signature or state work.",
+		},
+	)
+	rateLimitedRequestsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_rpc_rate_limited_requests_total",
+			Help: "Count of block requests refused because the peer or global quota was exhausted.",
+		},
+		[]string{"topic", "quota"},
+	)
 	numberOfTimesResyncedCounter = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "number_of_times_resynced",
@@ -0,0 +1,32 @@
+package sync
+
+import (
+	"encoding/hex"
+	"runtime/debug"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/sirupsen/logrus"
+)
+
+// maxLoggedPanicBytes limits the message bytes logged when a handler panics.
+const maxLoggedPanicBytes = 4096
+
+// handlePanic is called with the value recovered from a panic in a gossip or req/resp
+// handler. It logs the raw message, penalizes the peer which sent it and disconnects the
+// peer once it is marked as bad, so a malformed message cannot crash the node.
+func (r *Service) handlePanic(topic string, pid peer.ID, data []byte, recovered interface{}) {
+	messageHandlerPanicCounter.WithLabelValues(topic).Inc()
+	logged := data
+	if len(logged) > maxLoggedPanicBytes {
+		logged = logged[:maxLoggedPanicBytes]
+	}
+	log.WithFields(logrus.Fields{
+		"topic":   topic,
+		"peer":    pid.Pretty(),
+		"panic":   recovered,
+		"size":    len(data),
+		"message": hex.EncodeToString(logged),
+	}).Error("Panic while handling p2p message, recovering")
+	debug.PrintStack()
+	r.downscorePeer(pid)
+}
@@ -0,0 +1,68 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+)
+
+func TestWrapAndReportValidation_RecoversFromPanic(t *testing.T) {
+	p := p2ptest.NewTestP2P(t)
+	r := &Service{
+		ctx: context.Background(),
+		p2p: p,
+	}
+	pid := peer.ID("sender")
+	_, validate := r.wrapAndReportValidation("/testing/panic", func(context.Context, peer.ID, *pubsub.Message) pubsub.ValidationResult {
+		panic("malformed message")
+	})
+	msg := &pubsub.Message{Message: &pubsubpb.Message{Data: []byte{0xde, 0xad}}}
+
+	if res := validate(context.Background(), pid, msg); res != pubsub.ValidationReject {
+		t.Errorf("Wanted validation result %v, received %v", pubsub.ValidationReject, res)
+	}
+	badResponses, err := p.Peers().BadResponses(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if badResponses != 1 {
+		t.Errorf("Wanted 1 bad response of the sending peer, received %d", badResponses)
+	}
+}
+
+func TestWrapAndReportValidation_PenalizesRejectedGossip(t *testing.T) {
+	p := p2ptest.NewTestP2P(t)
+	r := &Service{
+		ctx: context.Background(),
+		p2p: p,
+	}
+	results := map[peer.ID]pubsub.ValidationResult{
+		"rejected": pubsub.ValidationReject,
+		"ignored":  pubsub.ValidationIgnore,
+		"accepted": pubsub.ValidationAccept,
+	}
+	_, validate := r.wrapAndReportValidation("/testing/results", func(_ context.Context, pid peer.ID, _ *pubsub.Message) pubsub.ValidationResult {
+		return results[pid]
+	})
+	for pid, want := range results {
+		msg := &pubsub.Message{Message: &pubsubpb.Message{}}
+		if res := validate(context.Background(), pid, msg); res != want {
+			t.Errorf("Wanted validation result %v, received %v", want, res)
+		}
+	}
+
+	for pid, want := range map[peer.ID]int{"rejected": 1, "ignored": 0, "accepted": 0} {
+		penalties, err := p.Peers().GossipPenalties(pid)
+		if err != nil {
+			// Peers which were never penalized are unknown to the peer status.
+			penalties = 0
+		}
+		if penalties != want {
+			t.Errorf("Wanted %d gossip penalties of peer %s, received %d", want, pid, penalties)
+		}
+	}
+}
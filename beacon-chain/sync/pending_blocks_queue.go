@@ -21,6 +21,10 @@ import (
 
 var processPendingBlocksPeriod = slotutil.DivideSlotBy(3 /* times per slot */)
 
+// maxPendingBlocks bounds the number of gossip blocks waiting for their parents, so peers
+// sending blocks of unknown chains cannot grow the queue without bound.
+const maxPendingBlocks = 256
+
 // processes pending blocks queue on every processPendingBlocksPeriod
 func (r *Service) processPendingBlocksQueue() {
 	ctx := context.Background()
@@ -44,6 +48,8 @@ func (r *Service) processPendingBlocks(ctx context.Context) error {
 		return errors.Wrap(err, "could not validate pending slots")
 	}
 	slots := r.sortedPendingSlots()
+	// Parents already requested in this pass, as several pending blocks may share a parent.
+	requestedParents := make(map[[32]byte]bool)
 
 	span.AddAttributes(
 		trace.Int64Attribute("numSlots", int64(len(slots))),
@@ -62,20 +68,26 @@ func (r *Service) processPendingBlocks(ctx context.Context) error {
 			span.End()
 			continue
 		}
-		r.pendingQueueLock.RUnlock()
 		inPendingQueue := r.seenPendingBlocks[bytesutil.ToBytes32(b.Block.ParentRoot)]
+		r.pendingQueueLock.RUnlock()
 
-		inDB := r.db.HasBlock(ctx, bytesutil.ToBytes32(b.Block.ParentRoot))
+		parentRoot := bytesutil.ToBytes32(b.Block.ParentRoot)
+		inDB := r.db.HasBlock(ctx, parentRoot)
 		hasPeer := len(pids) != 0
 
-		// Only request for missing parent block if it's not in DB, not in pending cache
-		// and has peer in the peer list.
+		// Only request for missing parent block if it's not in DB, not in pending cache,
+		// not requested yet in this pass and has peer in the peer list.
 		if !inPendingQueue && !inDB && hasPeer {
+			if requestedParents[parentRoot] {
+				span.End()
+				continue
+			}
+			requestedParents[parentRoot] = true
 			log.WithFields(logrus.Fields{
 				"currentSlot": b.Block.Slot,
 				"parentRoot":  hex.EncodeToString(bytesutil.Trunc(b.Block.ParentRoot)),
 			}).Info("Requesting parent block")
-			req := [][32]byte{bytesutil.ToBytes32(b.Block.ParentRoot)}
+			req := [][32]byte{parentRoot}
 
 			// Start with a random peer to query, but choose the first peer in our unsorted list that claims to
 			// have a head slot newer than the block slot we are requesting.
@@ -137,6 +149,19 @@ func (r *Service) processPendingBlocks(ctx context.Context) error {
 	return nil
 }
 
+// insertPendingBlock queues a block until its parent is known. Blocks received over gossip are
+// only queued while the queue has room, as they may be of chains which never complete.
+func (r *Service) insertPendingBlock(b *ethpb.SignedBeaconBlock, blkRoot [32]byte, fromGossip bool) bool {
+	r.pendingQueueLock.Lock()
+	defer r.pendingQueueLock.Unlock()
+	if fromGossip && len(r.slotToPendingBlocks) >= maxPendingBlocks {
+		return false
+	}
+	r.slotToPendingBlocks[b.Block.Slot] = b
+	r.seenPendingBlocks[blkRoot] = true
+	return true
+}
+
 func (r *Service) sortedPendingSlots() []uint64 {
 	r.pendingQueueLock.RLock()
 	defer r.pendingQueueLock.RUnlock()
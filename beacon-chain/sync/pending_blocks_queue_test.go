@@ -329,3 +329,32 @@ func TestService_sortedPendingSlots(t *testing.T) {
 		t.Errorf("unexpected pending slots list, want: %v, got: %v", want, got)
 	}
 }
+
+func TestService_insertPendingBlock_GossipBounded(t *testing.T) {
+	r := &Service{
+		slotToPendingBlocks: make(map[uint64]*ethpb.SignedBeaconBlock),
+		seenPendingBlocks:   make(map[[32]byte]bool),
+	}
+
+	for i := uint64(0); i < maxPendingBlocks; i++ {
+		b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: i}}
+		if !r.insertPendingBlock(b, [32]byte{byte(i), byte(i >> 8)}, true) {
+			t.Fatalf("Expected block at slot %d to be queued", i)
+		}
+	}
+	b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: maxPendingBlocks}}
+	root := [32]byte{'a'}
+	if r.insertPendingBlock(b, root, true) {
+		t.Error("Expected gossip block to be dropped when the queue is full")
+	}
+	if r.seenPendingBlocks[root] {
+		t.Error("Dropped block marked as seen")
+	}
+	// Requested parents are queued regardless, so pending chains can complete.
+	if !r.insertPendingBlock(b, root, false) {
+		t.Error("Expected requested block to be queued")
+	}
+	if len(r.slotToPendingBlocks) != maxPendingBlocks+1 {
+		t.Errorf("Expected %d pending blocks, got %d", maxPendingBlocks+1, len(r.slotToPendingBlocks))
+	}
+}
@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"github.com/kevinms/leakybucket-go"
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+)
+
+// globalBlocksBucket is the key of the single bucket in the global blocks rate limiter which
+// is shared by all peers.
+const globalBlocksBucket = "global"
+
+// newGlobalBlocksRateLimiter returns the limiter of blocks served to all peers combined, or nil
+// if the global quota is disabled.
+func newGlobalBlocksRateLimiter() *leakybucket.Collector {
+	perSecond := flags.Get().BlockBatchLimit * flags.Get().BlockBatchLimitGlobalFactor
+	if perSecond == 0 {
+		return nil
+	}
+	burst := int64(flags.Get().BlockBatchLimitBurstFactor * perSecond)
+	return leakybucket.NewCollector(float64(perSecond), burst, false /* deleteEmptyBuckets */)
+}
+
+// reserveBlocksQuota reserves count blocks of the quotas of the peer on the stream and of the
+// node, reporting whether they may be served. Blocks reserved by requests in flight are not
+// available to other requests, so concurrent requests cannot exceed the quotas together.
+// A request beyond the peer's own quota counts as a bad response from the peer, while a request
+// beyond the global quota is only refused, as the peer is not at fault for the load of others.
+// In both cases the spec's rate limited error is written to the stream. Reserved blocks must be
+// released with releaseBlocksQuota.
+func (r *Service) reserveBlocksQuota(stream libp2pcore.Stream, count int64) bool {
+	pid := stream.Conn().RemotePeer()
+	r.blocksQuotaLock.Lock()
+	if r.reservedBlocks == nil {
+		r.reservedBlocks = make(map[string]int64)
+	}
+	if count > r.blocksRateLimiter.Remaining(pid.String())-r.reservedBlocks[pid.String()] {
+		r.blocksQuotaLock.Unlock()
+		rateLimitedRequestsCounter.WithLabelValues(string(stream.Protocol()), "peer").Inc()
+		r.p2p.Peers().IncrementBadResponses(pid)
+		if r.p2p.Peers().IsBad(pid) {
+			log.WithField("peer", pid).Debug("Disconnecting bad peer")
+			defer func() {
+				if err := r.p2p.Disconnect(pid); err != nil {
+					log.WithError(err).Error("Failed to disconnect peer")
+				}
+			}()
+		}
+		r.writeErrorResponseToStream(responseCodeInvalidRequest, rateLimitedError, stream)
+		return false
+	}
+	if r.globalBlocksRateLimiter != nil &&
+		count > r.globalBlocksRateLimiter.Remaining(globalBlocksBucket)-r.reservedBlocks[globalBlocksBucket] {
+		r.blocksQuotaLock.Unlock()
+		rateLimitedRequestsCounter.WithLabelValues(string(stream.Protocol()), "global").Inc()
+		r.writeErrorResponseToStream(responseCodeInvalidRequest, rateLimitedError, stream)
+		return false
+	}
+	r.reservedBlocks[pid.String()] += count
+	if r.globalBlocksRateLimiter != nil {
+		r.reservedBlocks[globalBlocksBucket] += count
+	}
+	r.blocksQuotaLock.Unlock()
+	return true
+}
+
+// releaseBlocksQuota releases count blocks reserved with reserveBlocksQuota, of which served
+// blocks were served, or looked up for the request. The quotas of the peer on the stream and of
+// the node are decreased by the served blocks, while the unused blocks are returned to them.
+func (r *Service) releaseBlocksQuota(stream libp2pcore.Stream, count int64, served int64) {
+	key := stream.Conn().RemotePeer().String()
+	r.blocksQuotaLock.Lock()
+	defer r.blocksQuotaLock.Unlock()
+	r.blocksRateLimiter.Add(key, served)
+	if r.reservedBlocks[key] -= count; r.reservedBlocks[key] <= 0 {
+		delete(r.reservedBlocks, key)
+	}
+	if r.globalBlocksRateLimiter != nil {
+		r.globalBlocksRateLimiter.Add(globalBlocksBucket, served)
+		if r.reservedBlocks[globalBlocksBucket] -= count; r.reservedBlocks[globalBlocksBucket] <= 0 {
+			delete(r.reservedBlocks, globalBlocksBucket)
+		}
+	}
+}
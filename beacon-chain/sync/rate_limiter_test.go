@@ -0,0 +1,109 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevinms/leakybucket-go"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	db "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestBeaconBlocksRootRPCHandler_GlobalRateLimit(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	d := db.SetupDB(t)
+
+	r := &Service{
+		p2p:                     p1,
+		db:                      d,
+		blocksRateLimiter:       leakybucket.NewCollector(10000, 10000, false),
+		globalBlocksRateLimiter: leakybucket.NewCollector(0.000001, 4, false),
+	}
+	pcl := protocol.ID("/testing")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		code, errMsg, err := ReadStatusCode(stream, p2.Encoding())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != responseCodeInvalidRequest {
+			t.Errorf("Expected response code %d, got %d", responseCodeInvalidRequest, code)
+		}
+		if errMsg != rateLimitedError {
+			t.Errorf("Expected error %q, got %q", rateLimitedError, errMsg)
+		}
+	})
+
+	stream, err := p1.Host.NewStream(context.Background(), p2.Host.ID(), pcl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := make([][32]byte, 5)
+	if err := r.beaconBlocksRootRPCHandler(context.Background(), roots, stream); err == nil || err.Error() != rateLimitedError {
+		t.Errorf("Expected rate limited error, got %v", err)
+	}
+	if testutil.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+
+	if badResponses, err := p1.Peers().BadResponses(p2.PeerID()); err == nil && badResponses != 0 {
+		t.Errorf("Expected the peer not to be penalized for the global quota, got %d bad responses", badResponses)
+	}
+	if remaining := r.blocksRateLimiter.Remaining(p2.PeerID().String()); remaining != 10000 {
+		t.Errorf("Expected the peer quota to be untouched, got %d remaining", remaining)
+	}
+}
+
+func TestReserveBlocksQuota(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+
+	r := &Service{
+		p2p:                     p1,
+		blocksRateLimiter:       leakybucket.NewCollector(0.000001, 100, false),
+		globalBlocksRateLimiter: leakybucket.NewCollector(0.000001, 8, false),
+	}
+	pcl := protocol.ID("/testing")
+	p2.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		if _, _, err := ReadStatusCode(stream, p2.Encoding()); err != nil {
+			t.Log(err)
+		}
+	})
+	newStream := func() network.Stream {
+		stream, err := p1.Host.NewStream(context.Background(), p2.Host.ID(), pcl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stream
+	}
+
+	first, second := newStream(), newStream()
+	if !r.reserveBlocksQuota(first, 6) {
+		t.Fatal("Expected the first request to reserve blocks within the quotas")
+	}
+	// The blocks reserved by the first request are not available until it is done.
+	if r.reserveBlocksQuota(second, 6) {
+		t.Error("Expected the second request to exceed the global quota with the reserved blocks")
+	}
+	r.releaseBlocksQuota(first, 6, 2)
+	if remaining := r.globalBlocksRateLimiter.Remaining(globalBlocksBucket); remaining != 6 {
+		t.Errorf("Expected the unused blocks to be returned to the global quota, got %d remaining", remaining)
+	}
+	if remaining := r.blocksRateLimiter.Remaining(p2.PeerID().String()); remaining != 98 {
+		t.Errorf("Expected the unused blocks to be returned to the peer quota, got %d remaining", remaining)
+	}
+	if !r.reserveBlocksQuota(newStream(), 6) {
+		t.Error("Expected a request to reserve the returned blocks")
+	}
+}
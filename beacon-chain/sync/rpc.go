@@ -1,14 +1,17 @@
 package sync
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"reflect"
 	"strings"
 
 	libp2pcore "github.com/libp2p/go-libp2p-core"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
-	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/msgtrace"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
@@ -30,38 +33,33 @@ var maxChunkSize = params.BeaconNetworkConfig().MaxChunkSize
 // not be relayed to the peer.
 type rpcHandler func(context.Context, interface{}, libp2pcore.Stream) error
 
-// registerRPCHandlers for p2p RPC.
+// registerRPCHandlers for p2p RPC. The request type of each rpc method is the one of the
+// schema version of the current fork.
 func (r *Service) registerRPCHandlers() {
-	r.registerRPC(
-		p2p.RPCStatusTopic,
-		&pb.Status{},
-		r.statusRPCHandler,
-	)
-	r.registerRPC(
-		p2p.RPCGoodByeTopic,
-		new(uint64),
-		r.goodbyeRPCHandler,
-	)
-	r.registerRPC(
-		p2p.RPCBlocksByRangeTopic,
-		&pb.BeaconBlocksByRangeRequest{},
-		r.beaconBlocksByRangeRPCHandler,
-	)
-	r.registerRPC(
-		p2p.RPCBlocksByRootTopic,
-		[][32]byte{},
-		r.beaconBlocksRootRPCHandler,
-	)
-	r.registerRPC(
-		p2p.RPCPingTopic,
-		new(uint64),
-		r.pingHandler,
-	)
-	r.registerRPC(
-		p2p.RPCMetaDataTopic,
-		new(interface{}),
-		r.metaDataHandler,
-	)
+	handlers := map[string]rpcHandler{
+		p2p.RPCStatusTopic:        r.statusRPCHandler,
+		p2p.RPCGoodByeTopic:       r.goodbyeRPCHandler,
+		p2p.RPCBlocksByRangeTopic: r.beaconBlocksByRangeRPCHandler,
+		p2p.RPCBlocksByRootTopic:  r.beaconBlocksRootRPCHandler,
+		p2p.RPCPingTopic:          r.pingHandler,
+		p2p.RPCMetaDataTopic:      r.metaDataHandler,
+		p2p.RPCStateSnapshotTopic: r.stateSnapshotRPCHandler,
+	}
+	digest, err := r.forkDigest()
+	if err != nil {
+		log.WithError(err).Fatal("Could not compute fork digest")
+	}
+	registry, err := r.messages()
+	if err != nil {
+		log.WithError(err).Fatal("Could not create message registry")
+	}
+	for topic, handle := range handlers {
+		base, err := registry.RPCMessage(digest, topic)
+		if err != nil {
+			log.WithError(err).Fatal("Could not register rpc handler")
+		}
+		r.registerRPC(topic, base, handle)
+	}
 }
 
 // registerRPC for a given topic with an expected protobuf message type.
@@ -82,6 +80,23 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 		span.AddAttributes(trace.StringAttribute("peer", stream.Conn().RemotePeer().Pretty()))
 		log := log.WithField("peer", stream.Conn().RemotePeer().Pretty())
 
+		// The request bytes are kept to be logged if the handler panics.
+		var data bytes.Buffer
+		reader := io.TeeReader(stream, &data)
+		// The request is traced once handled, with its decoded message and the handling error.
+		var request interface{}
+		var handleErr error
+		defer func() {
+			msgtrace.Record(msgtrace.Inbound, msgtrace.RPC, topic, stream.Conn().RemotePeer(), data.Len(), request, msgtrace.Result(handleErr))
+		}()
+		defer func() {
+			if rec := recover(); rec != nil {
+				handleErr = fmt.Errorf("panic occurred: %v", rec)
+				traceutil.AnnotateError(span, handleErr)
+				r.handlePanic(topic, stream.Conn().RemotePeer(), data.Bytes(), rec)
+			}
+		}()
+
 		if err := stream.SetReadDeadline(roughtime.Now().Add(ttfbTimeout)); err != nil {
 			log.WithError(err).Error("Could not set stream read deadline")
 			return
@@ -94,6 +109,7 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 		// do not decode anything.
 		if strings.Contains(topic, p2p.RPCMetaDataTopic) {
 			if err := handle(ctx, new(interface{}), stream); err != nil {
+				handleErr = err
 				messageFailedProcessingCounter.WithLabelValues(topic).Inc()
 				if err != errWrongForkDigestVersion {
 					log.WithError(err).Warn("Failed to handle p2p RPC")
@@ -109,7 +125,8 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 		t := reflect.TypeOf(base)
 		if t.Kind() == reflect.Ptr {
 			msg := reflect.New(t.Elem())
-			if err := r.p2p.Encoding().DecodeWithLength(stream, msg.Interface()); err != nil {
+			if err := r.p2p.Encoding().DecodeWithLength(reader, msg.Interface()); err != nil {
+				handleErr = err
 				// Debug logs for goodbye/status errors
 				if strings.Contains(topic, p2p.RPCGoodByeTopic) || strings.Contains(topic, p2p.RPCStatusTopic) {
 					log.WithError(err).Debug("Failed to decode goodbye stream message")
@@ -120,7 +137,9 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 				traceutil.AnnotateError(span, err)
 				return
 			}
-			if err := handle(ctx, msg.Interface(), stream); err != nil {
+			request = msg.Interface()
+			if err := handle(ctx, request, stream); err != nil {
+				handleErr = err
 				messageFailedProcessingCounter.WithLabelValues(topic).Inc()
 				if err != errWrongForkDigestVersion {
 					log.WithError(err).Warn("Failed to handle p2p RPC")
@@ -129,12 +148,15 @@ func (r *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 			}
 		} else {
 			msg := reflect.New(t)
-			if err := r.p2p.Encoding().DecodeWithLength(stream, msg.Interface()); err != nil {
+			if err := r.p2p.Encoding().DecodeWithLength(reader, msg.Interface()); err != nil {
+				handleErr = err
 				log.WithError(err).Warn("Failed to decode stream message")
 				traceutil.AnnotateError(span, err)
 				return
 			}
-			if err := handle(ctx, msg.Elem().Interface(), stream); err != nil {
+			request = msg.Elem().Interface()
+			if err := handle(ctx, request, stream); err != nil {
+				handleErr = err
 				messageFailedProcessingCounter.WithLabelValues(topic).Inc()
 				if err != errWrongForkDigestVersion {
 					log.WithError(err).Warn("Failed to handle p2p RPC")
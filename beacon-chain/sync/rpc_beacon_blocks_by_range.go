@@ -62,23 +62,13 @@ func (r *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 		trace.Int64Attribute("remaining_capacity", remainingBucketCapacity),
 	)
 	for startSlot <= endReqSlot {
-		remainingBucketCapacity = r.blocksRateLimiter.Remaining(stream.Conn().RemotePeer().String())
-		if int64(allowedBlocksPerSecond) > remainingBucketCapacity {
-			r.p2p.Peers().IncrementBadResponses(stream.Conn().RemotePeer())
-			if r.p2p.Peers().IsBad(stream.Conn().RemotePeer()) {
-				log.Debug("Disconnecting bad peer")
-				defer func() {
-					if err := r.p2p.Disconnect(stream.Conn().RemotePeer()); err != nil {
-						log.WithError(err).Error("Failed to disconnect peer")
-					}
-				}()
-			}
-			r.writeErrorResponseToStream(responseCodeInvalidRequest, rateLimitedError, stream)
+		if !r.reserveBlocksQuota(stream, int64(allowedBlocksPerSecond)) {
 			return errors.New(rateLimitedError)
 		}
 
 		// TODO(3147): Update this with reasonable constraints.
 		if endSlot-startSlot > rangeLimit || m.Step == 0 {
+			r.releaseBlocksQuota(stream, int64(allowedBlocksPerSecond), 0)
 			r.writeErrorResponseToStream(responseCodeInvalidRequest, stepError, stream)
 			err := errors.New(stepError)
 			traceutil.AnnotateError(span, err)
@@ -86,14 +76,16 @@ func (r *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 		}
 
 		if err := r.writeBlockRangeToStream(ctx, startSlot, endSlot, m.Step, stream); err != nil {
+			r.releaseBlocksQuota(stream, int64(allowedBlocksPerSecond), 0)
 			return err
 		}
 
 		// Decrease allowed blocks capacity by the number of streamed blocks.
+		var served int64
 		if startSlot <= endSlot {
-			r.blocksRateLimiter.Add(
-				stream.Conn().RemotePeer().String(), int64(1+(endSlot-startSlot)/m.Step))
+			served = int64(1 + (endSlot-startSlot)/m.Step)
 		}
+		r.releaseBlocksQuota(stream, int64(allowedBlocksPerSecond), served)
 
 		// Recalculate start and end slots for the next batch to be returned to the remote peer.
 		startSlot = endSlot + m.Step
@@ -117,6 +109,18 @@ func (r *Service) writeBlockRangeToStream(ctx context.Context, startSlot, endSlo
 	ctx, span := trace.StartSpan(ctx, "sync.WriteBlockRangeToStream")
 	defer span.End()
 
+	if r.coldHistory != nil && startSlot >= r.coldHistory.StartSlot() && startSlot < r.coldHistory.EndSlot() {
+		next, err := r.writeColdBlockRangeToStream(startSlot, endSlot, step, stream)
+		if err != nil {
+			traceutil.AnnotateError(span, err)
+			return err
+		}
+		if next > endSlot {
+			return nil
+		}
+		startSlot = next
+	}
+
 	filter := filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(endSlot).SetSlotStep(step)
 	blks, err := r.db.Blocks(ctx, filter)
 	if err != nil {
@@ -141,8 +145,11 @@ func (r *Service) writeBlockRangeToStream(ctx context.Context, startSlot, endSlo
 			traceutil.AnnotateError(span, err)
 			return err
 		}
-		blks = append([]*ethpb.SignedBeaconBlock{genBlock}, blks...)
-		roots = append([][32]byte{genRoot}, roots...)
+		// A node started from a checkpoint state has its checkpoint block in place of genesis.
+		if genBlock.Block.Slot == 0 {
+			blks = append([]*ethpb.SignedBeaconBlock{genBlock}, blks...)
+			roots = append([][32]byte{genRoot}, roots...)
+		}
 	}
 	blks, roots = r.sortBlocksAndRoots(blks, roots)
 	checkpoint, err := r.db.FinalizedCheckpoint(ctx)
@@ -170,6 +177,29 @@ func (r *Service) writeBlockRangeToStream(ctx context.Context, startSlot, endSlo
 	return nil
 }
 
+// writeColdBlockRangeToStream writes the part of the range covered by the cold history and
+// returns the first requested slot after it. Archived blocks are finalized, so they are
+// written without further checks.
+func (r *Service) writeColdBlockRangeToStream(startSlot, endSlot, step uint64, stream libp2pcore.Stream) (uint64, error) {
+	coldEnd := endSlot
+	if coldEnd >= r.coldHistory.EndSlot() {
+		coldEnd = r.coldHistory.EndSlot() - 1
+	}
+	blks, err := r.coldHistory.BlocksByRange(startSlot, coldEnd, step)
+	if err != nil {
+		log.WithError(err).Error("Failed to retrieve blocks from cold history")
+		r.writeErrorResponseToStream(responseCodeServerError, genericError, stream)
+		return 0, err
+	}
+	for _, b := range blks {
+		if err := r.chunkWriter(stream, b); err != nil {
+			log.WithError(err).Error("Failed to send a chunked response")
+			return 0, err
+		}
+	}
+	return startSlot + ((coldEnd-startSlot)/step+1)*step, nil
+}
+
 func (r *Service) writeErrorResponseToStream(responseCode byte, reason string, stream libp2pcore.Stream) {
 	resp, err := r.generateErrorResponse(responseCode, reason)
 	if err != nil {
@@ -370,3 +370,80 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 	})
 }
+
+type mockColdHistory struct {
+	start, end uint64
+}
+
+func (m *mockColdHistory) StartSlot() uint64 {
+	return m.start
+}
+
+func (m *mockColdHistory) EndSlot() uint64 {
+	return m.end
+}
+
+func (m *mockColdHistory) BlocksByRange(startSlot uint64, endSlot uint64, step uint64) ([]*ethpb.SignedBeaconBlock, error) {
+	var blks []*ethpb.SignedBeaconBlock
+	for i := startSlot; i <= endSlot; i += step {
+		blks = append(blks, &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: i}})
+	}
+	return blks, nil
+}
+
+func TestRPCBeaconBlocksByRange_ServesFromColdHistory(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	if len(p1.Host.Network().Peers()) != 1 {
+		t.Error("Expected peers to be connected")
+	}
+	d := db.SetupDB(t)
+
+	req := &pb.BeaconBlocksByRangeRequest{
+		StartSlot: 90,
+		Step:      2,
+		Count:     10,
+	}
+	// Only the blocks after the cold history are in the database.
+	for i := uint64(100); i < req.StartSlot+(req.Step*req.Count); i += req.Step {
+		if err := d.SaveBlock(context.Background(), &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Service{
+		p2p:               p1,
+		db:                d,
+		blocksRateLimiter: leakybucket.NewCollector(0.000001, int64(req.Count*10), false),
+		coldHistory:       &mockColdHistory{start: 0, end: 100},
+	}
+	pcl := protocol.ID("/testing")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		for i := req.StartSlot; i < req.StartSlot+req.Count*req.Step; i += req.Step {
+			expectSuccess(t, r, stream)
+			res := &ethpb.SignedBeaconBlock{}
+			if err := r.p2p.Encoding().DecodeWithLength(stream, res); err != nil {
+				t.Error(err)
+			}
+			if res.Block.Slot != i {
+				t.Errorf("Wanted block at slot %d, received %d", i, res.Block.Slot)
+			}
+		}
+	})
+
+	stream1, err := p1.Host.NewStream(context.Background(), p2.Host.ID(), pcl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.beaconBlocksByRangeRPCHandler(context.Background(), req, stream1); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if testutil.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+}
@@ -41,11 +41,7 @@ func (r *Service) sendRecentBeaconBlocksRequest(ctx context.Context, blockRoots
 		if err != nil {
 			return err
 		}
-		r.pendingQueueLock.Lock()
-		r.slotToPendingBlocks[blk.Block.Slot] = blk
-		r.seenPendingBlocks[blkRoot] = true
-		r.pendingQueueLock.Unlock()
-
+		r.insertPendingBlock(blk, blkRoot, false /* fromGossip */)
 	}
 	return nil
 }
@@ -78,30 +74,18 @@ func (r *Service) beaconBlocksRootRPCHandler(ctx context.Context, msg interface{
 		return errors.New("no block roots provided")
 	}
 
-	if int64(len(blockRoots)) > r.blocksRateLimiter.Remaining(stream.Conn().RemotePeer().String()) {
-		r.p2p.Peers().IncrementBadResponses(stream.Conn().RemotePeer())
-		if r.p2p.Peers().IsBad(stream.Conn().RemotePeer()) {
-			log.Debug("Disconnecting bad peer")
-			defer func() {
-				if err := r.p2p.Disconnect(stream.Conn().RemotePeer()); err != nil {
-					log.WithError(err).Error("Failed to disconnect peer")
-				}
-			}()
-		}
-		resp, err := r.generateErrorResponse(responseCodeInvalidRequest, rateLimitedError)
-		if err != nil {
-			log.WithError(err).Error("Failed to generate a response error")
-		} else {
-			if _, err := stream.Write(resp); err != nil {
-				log.WithError(err).Errorf("Failed to write to stream")
-			}
-		}
+	if !r.reserveBlocksQuota(stream, int64(len(blockRoots))) {
 		return errors.New(rateLimitedError)
 	}
-
-	r.blocksRateLimiter.Add(stream.Conn().RemotePeer().String(), int64(len(blockRoots)))
+	// Every root looked up in the DB counts against the quotas, whether or not its block is found,
+	// so requests for unknown roots cannot read the DB free of charge.
+	var lookedUp int64
+	defer func() {
+		r.releaseBlocksQuota(stream, int64(len(blockRoots)), lookedUp)
+	}()
 
 	for _, root := range blockRoots {
+		lookedUp++
 		blk, err := r.db.Block(ctx, root)
 		if err != nil {
 			log.WithError(err).Error("Failed to fetch block")
@@ -160,3 +160,44 @@ func TestRecentBeaconBlocks_RPCRequestSent(t *testing.T) {
 		t.Fatal("Did not receive stream within 1 sec")
 	}
 }
+
+func TestBeaconBlocksRootRPCHandler_UnknownRootsCountAgainstQuota(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	d := db.SetupDB(t)
+
+	r := &Service{
+		p2p:               p1,
+		db:                d,
+		blocksRateLimiter: leakybucket.NewCollector(0.000001, 8, false),
+	}
+	pcl := protocol.ID("/testing")
+	p2.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		if _, _, err := ReadStatusCode(stream, p2.Encoding()); err != nil {
+			t.Log(err)
+		}
+	})
+
+	// None of the requested blocks are in the DB, yet every lookup is charged.
+	roots := [][32]byte{{'a'}, {'b'}, {'c'}, {'d'}}
+	for i := 0; i < 2; i++ {
+		stream, err := p1.Host.NewStream(context.Background(), p2.Host.ID(), pcl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.beaconBlocksRootRPCHandler(context.Background(), roots, stream); err != nil {
+			t.Fatalf("Request %d: unexpected error %v", i, err)
+		}
+	}
+	if remaining := r.blocksRateLimiter.Remaining(p2.PeerID().String()); remaining != 0 {
+		t.Errorf("Expected the lookups of unknown roots to use up the quota, got %d remaining", remaining)
+	}
+	stream, err := p1.Host.NewStream(context.Background(), p2.Host.ID(), pcl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.beaconBlocksRootRPCHandler(context.Background(), roots, stream); err == nil || err.Error() != rateLimitedError {
+		t.Errorf("Expected rate limited error for repeated requests of unknown roots, got %v", err)
+	}
+}
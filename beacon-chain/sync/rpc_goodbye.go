@@ -3,6 +3,7 @@ package sync
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	libp2pcore "github.com/libp2p/go-libp2p-core"
@@ -78,6 +79,22 @@ func (r *Service) sendGoodByeMessage(ctx context.Context, code uint64, id peer.I
 	return nil
 }
 
+// sendShutdownGoodbyes tells all connected peers that the node is shutting down, waiting
+// for the messages to be sent.
+func (r *Service) sendShutdownGoodbyes() {
+	var wg sync.WaitGroup
+	for _, pid := range r.p2p.Peers().Connected() {
+		wg.Add(1)
+		go func(id peer.ID) {
+			defer wg.Done()
+			if err := r.sendGoodByeMessage(context.Background(), codeClientShutdown, id); err != nil {
+				log.WithError(err).WithField("peer", id).Debug("Could not send goodbye message to peer")
+			}
+		}(pid)
+	}
+	wg.Wait()
+}
+
 // sends a goodbye message for a generic error
 func (r *Service) sendGenericGoodbyeMessage(ctx context.Context, id peer.ID) error {
 	return r.sendGoodByeMessage(ctx, codeGenericError, id)
@@ -0,0 +1,224 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+const (
+	// stateSnapshotChunkSize is the number of state bytes served per request, well within the
+	// max chunk size of the response.
+	stateSnapshotChunkSize = 1 << 19
+	// maxStateSnapshotSize bounds the size of a state a peer may announce, so the node does not
+	// buffer arbitrary amounts of data.
+	maxStateSnapshotSize = 1 << 30
+	// Chunks a peer may request per second and at once.
+	allowedSnapshotChunksPerSecond = 4
+	allowedSnapshotChunksBurst     = 64
+)
+
+// StateSnapshotRequest asks for the SSZ encoded state of a finalized block, starting at the
+// given byte offset.
+type StateSnapshotRequest struct {
+	BlockRoot []byte `ssz-size:"32"`
+	Offset    uint64
+}
+
+func init() {
+	p2p.RegisterRPCMessage(p2p.Phase0Schema, p2p.RPCStateSnapshotTopic, &StateSnapshotRequest{})
+}
+
+// StateSnapshotChunk is a part of the SSZ encoded state starting at the requested offset.
+type StateSnapshotChunk struct {
+	TotalSize uint64
+	Data      []byte `ssz-max:"524288"`
+}
+
+// FetchStateSnapshot downloads the state of the finalized block with the given root from a peer,
+// one chunk per request. The state is only returned if it matches the block root, so the peer is
+// not trusted beyond serving the data.
+func (r *Service) FetchStateSnapshot(ctx context.Context, pid peer.ID, blockRoot [32]byte) (*stateTrie.BeaconState, error) {
+	var enc []byte
+	var totalSize uint64
+	for {
+		chunk, err := r.sendStateSnapshotRequest(ctx, pid, &StateSnapshotRequest{BlockRoot: blockRoot[:], Offset: uint64(len(enc))})
+		if err != nil {
+			return nil, err
+		}
+		if enc == nil {
+			if chunk.TotalSize == 0 || chunk.TotalSize > maxStateSnapshotSize {
+				r.p2p.Peers().IncrementBadResponses(pid)
+				return nil, fmt.Errorf("peer announced a state snapshot of %d bytes", chunk.TotalSize)
+			}
+			totalSize = chunk.TotalSize
+			enc = make([]byte, 0, totalSize)
+		}
+		if chunk.TotalSize != totalSize || len(chunk.Data) == 0 || uint64(len(enc)+len(chunk.Data)) > totalSize {
+			r.p2p.Peers().IncrementBadResponses(pid)
+			return nil, errors.New("peer sent an inconsistent state snapshot chunk")
+		}
+		enc = append(enc, chunk.Data...)
+		if uint64(len(enc)) == totalSize {
+			break
+		}
+	}
+	st, err := verifyStateSnapshot(ctx, blockRoot, enc)
+	if err != nil {
+		r.p2p.Peers().IncrementBadResponses(pid)
+		return nil, err
+	}
+	return st, nil
+}
+
+func (r *Service) sendStateSnapshotRequest(ctx context.Context, pid peer.ID, req *StateSnapshotRequest) (*StateSnapshotChunk, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	stream, err := r.p2p.Send(ctx, req, p2p.RPCStateSnapshotTopic, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := stream.Reset(); err != nil {
+			log.WithError(err).Errorf("Failed to reset stream with protocol %s", stream.Protocol())
+		}
+	}()
+	chunk := &StateSnapshotChunk{}
+	if err := readResponseChunk(stream, r.p2p, chunk); err != nil {
+		return nil, err
+	}
+	return chunk, nil
+}
+
+// verifyStateSnapshot decodes a state and checks that it is the post state of the block with
+// the given root: the latest block header of the state, completed with the state root, must
+// hash to the block root.
+func verifyStateSnapshot(ctx context.Context, blockRoot [32]byte, enc []byte) (*stateTrie.BeaconState, error) {
+	pbState := &pb.BeaconState{}
+	if err := pbState.UnmarshalSSZ(enc); err != nil {
+		return nil, errors.Wrap(err, "could not decode state snapshot")
+	}
+	st, err := stateTrie.InitializeFromProtoUnsafe(pbState)
+	if err != nil {
+		return nil, err
+	}
+	stateRoot, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute state root")
+	}
+	header := st.LatestBlockHeader()
+	if header == nil {
+		return nil, errors.New("state snapshot has no latest block header")
+	}
+	if bytesutil.ToBytes32(header.StateRoot) == params.BeaconConfig().ZeroHash {
+		header.StateRoot = stateRoot[:]
+	}
+	root, err := stateutil.BlockHeaderRoot(header)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block root")
+	}
+	if root != blockRoot {
+		return nil, fmt.Errorf("state snapshot belongs to block %#x, wanted %#x", root, blockRoot)
+	}
+	return st, nil
+}
+
+// stateSnapshotRPCHandler serves a chunk of the SSZ encoded state of a finalized block.
+func (r *Service) stateSnapshotRPCHandler(ctx context.Context, msg interface{}, stream libp2pcore.Stream) error {
+	defer func() {
+		if err := stream.Close(); err != nil {
+			log.WithError(err).Error("Failed to close stream")
+		}
+	}()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	setRPCStreamDeadlines(stream)
+	log := log.WithField("handler", "state_snapshot")
+
+	req, ok := msg.(*StateSnapshotRequest)
+	if !ok {
+		return errors.New("message is not type *StateSnapshotRequest")
+	}
+	writeError := func(code byte, reason string) {
+		resp, err := r.generateErrorResponse(code, reason)
+		if err != nil {
+			log.WithError(err).Error("Failed to generate a response error")
+		} else if _, err := stream.Write(resp); err != nil {
+			log.WithError(err).Errorf("Failed to write to stream")
+		}
+	}
+
+	pid := stream.Conn().RemotePeer().String()
+	if r.stateSnapshotRateLimiter.Remaining(pid) < 1 {
+		writeError(responseCodeInvalidRequest, rateLimitedError)
+		return errors.New(rateLimitedError)
+	}
+	r.stateSnapshotRateLimiter.Add(pid, 1)
+
+	blockRoot := bytesutil.ToBytes32(req.BlockRoot)
+	if !r.db.IsFinalizedBlock(ctx, blockRoot) {
+		writeError(responseCodeInvalidRequest, "block is not finalized")
+		return errors.New("requested state of a block which is not finalized")
+	}
+	enc, err := r.stateSnapshot(ctx, blockRoot)
+	if err != nil {
+		log.WithError(err).Error("Failed to get state snapshot")
+		writeError(responseCodeServerError, genericError)
+		return err
+	}
+	if req.Offset >= uint64(len(enc)) {
+		writeError(responseCodeInvalidRequest, "offset out of range")
+		return errors.New("state snapshot offset out of range")
+	}
+	end := req.Offset + stateSnapshotChunkSize
+	if end > uint64(len(enc)) {
+		end = uint64(len(enc))
+	}
+	return r.chunkWriter(stream, &StateSnapshotChunk{
+		TotalSize: uint64(len(enc)),
+		Data:      enc[req.Offset:end],
+	})
+}
+
+// stateSnapshot returns the SSZ encoded state of a block. The last encoded state is kept, as
+// peers request the chunks of one state one after the other.
+func (r *Service) stateSnapshot(ctx context.Context, blockRoot [32]byte) ([]byte, error) {
+	r.stateSnapshotLock.Lock()
+	defer r.stateSnapshotLock.Unlock()
+	if r.stateSnapshotRoot == blockRoot && r.stateSnapshotEnc != nil {
+		return r.stateSnapshotEnc, nil
+	}
+
+	var st *stateTrie.BeaconState
+	var err error
+	if featureconfig.Get().NewStateMgmt {
+		st, err = r.stateGen.StateByRoot(ctx, blockRoot)
+	} else {
+		st, err = r.db.State(ctx, blockRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, fmt.Errorf("no state found for block %#x", blockRoot)
+	}
+	enc, err := st.InnerStateUnsafe().MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	r.stateSnapshotRoot = blockRoot
+	r.stateSnapshotEnc = enc
+	return enc, nil
+}
@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevinms/leakybucket-go"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	db "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// snapshotBlockRoot returns the root of the block the state is the post state of.
+func snapshotBlockRoot(t *testing.T, st *stateTrie.BeaconState) [32]byte {
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := st.LatestBlockHeader()
+	header.StateRoot = stateRoot[:]
+	root, err := stateutil.BlockHeaderRoot(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestFetchStateSnapshot(t *testing.T) {
+	ctx := context.Background()
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+
+	d := db.SetupDB(t)
+	st, _ := testutil.DeterministicGenesisState(t, 64)
+	blockRoot := snapshotBlockRoot(t, st)
+	if err := d.SaveState(ctx, st, blockRoot); err != nil {
+		t.Fatal(err)
+	}
+	// The genesis block counts as finalized.
+	if err := d.SaveGenesisBlockRoot(ctx, blockRoot); err != nil {
+		t.Fatal(err)
+	}
+	server := &Service{p2p: p2, db: d, stateSnapshotRateLimiter: leakybucket.NewCollector(10000, 10000, false)}
+
+	var wg sync.WaitGroup
+	var requests int
+	pcl := protocol.ID(p2p.RPCStateSnapshotTopic + p2.Encoding().ProtocolSuffix())
+	p2.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		wg.Add(1)
+		defer wg.Done()
+		requests++
+		req := &StateSnapshotRequest{}
+		if err := p2.Encoding().DecodeWithLength(stream, req); err != nil {
+			t.Error(err)
+			return
+		}
+		// Errors are relayed to the requesting peer in the response.
+		_ = server.stateSnapshotRPCHandler(ctx, req, stream)
+	})
+
+	r := &Service{p2p: p1}
+	got, err := r.FetchStateSnapshot(ctx, p2.PeerID(), blockRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if testutil.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+	if requests < 2 {
+		t.Errorf("Expected the state to be fetched in several chunks, received %d", requests)
+	}
+	wantRoot, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotRoot, err := got.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotRoot != wantRoot {
+		t.Errorf("Wanted state root %#x, received %#x", wantRoot, gotRoot)
+	}
+
+	// A state is not accepted for another block.
+	if _, err := r.FetchStateSnapshot(ctx, p2.PeerID(), [32]byte{'a'}); err == nil {
+		t.Error("Expected error fetching the state of a block which is not finalized")
+	}
+}
+
+func TestVerifyStateSnapshot_WrongRoot(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 8)
+	enc, err := st.InnerStateUnsafe().MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyStateSnapshot(context.Background(), snapshotBlockRoot(t, st), enc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyStateSnapshot(context.Background(), [32]byte{'a'}, enc); err == nil {
+		t.Error("Expected error verifying a state against another block root")
+	}
+}
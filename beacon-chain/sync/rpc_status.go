@@ -50,7 +50,7 @@ func (r *Service) resyncIfBehind() {
 	// Run sixteen times per epoch.
 	interval := time.Duration(int64(millisecondsPerEpoch)/16) * time.Millisecond
 	runutil.RunEvery(r.ctx, interval, func() {
-		currentEpoch := uint64(roughtime.Now().Unix()-r.chain.GenesisTime().Unix()) / (params.BeaconConfig().SecondsPerSlot * params.BeaconConfig().SlotsPerEpoch)
+		currentEpoch := uint64(slotutil.Now().Unix()-r.chain.GenesisTime().Unix()) / (params.BeaconConfig().SecondsPerSlot * params.BeaconConfig().SlotsPerEpoch)
 		syncedEpoch := helpers.SlotToEpoch(r.chain.HeadSlot())
 		if r.initialSync != nil && !r.initialSync.Syncing() && syncedEpoch < currentEpoch-1 {
 			_, highestEpoch, _ := r.p2p.Peers().BestFinalized(params.BeaconConfig().MaxPeersToSync, syncedEpoch)
@@ -272,6 +272,15 @@ func (r *Service) validateStatusMessage(ctx context.Context, msg *pb.Status, str
 	if finalizedAtGenesis && rootIsEqual {
 		return nil
 	}
+	// A node started from a checkpoint state has no blocks before its checkpoint block, so older
+	// finalized roots of peers can't be verified.
+	origin, err := r.db.GenesisBlock(ctx)
+	if err != nil {
+		return errGeneric
+	}
+	if origin != nil && helpers.StartSlot(msg.FinalizedEpoch) < origin.Block.Slot {
+		return nil
+	}
 	if !r.db.IsFinalizedBlock(context.Background(), bytesutil.ToBytes32(msg.FinalizedRoot)) {
 		return errInvalidFinalizedRoot
 	}
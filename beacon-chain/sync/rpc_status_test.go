@@ -648,6 +648,7 @@ func TestStatusRPCRequest_BadPeerHandshake(t *testing.T) {
 			Genesis:        time.Now(),
 			ValidatorsRoot: [32]byte{'A'},
 		},
+		db:  testingDB.SetupDB(t),
 		ctx: context.Background(),
 	}
 
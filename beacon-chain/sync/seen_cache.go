@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+// Key prefixes of the persisted seen block and attestation cache entries.
+const (
+	seenBlockPrefix       = "b"
+	seenAttestationPrefix = "a"
+)
+
+// seenCachePersistDuration is how long a seen gossip message is worth remembering across a
+// restart. Older messages fall outside the gossip propagation window and are rejected anyway.
+func seenCachePersistDuration() time.Duration {
+	return time.Duration(2*params.BeaconConfig().SlotsPerEpoch*params.BeaconConfig().SecondsPerSlot) * time.Second
+}
+
+// saveSeenCaches persists the recently seen blocks and attestations, so a restarted node does
+// not revalidate and rebroadcast gossip it already propagated.
+func (r *Service) saveSeenCaches(ctx context.Context) error {
+	if r.seenBlockCache == nil || r.seenAttestationCache == nil {
+		return nil
+	}
+	cutoff := roughtime.Now().Add(-seenCachePersistDuration()).Unix()
+	seen := make(map[string]int64)
+	collectSeen(seen, seenBlockPrefix, r.seenBlockCache, &r.seenBlockLock, cutoff)
+	collectSeen(seen, seenAttestationPrefix, r.seenAttestationCache, &r.seenAttestationLock, cutoff)
+	if err := r.db.SaveSeenGossip(ctx, seen); err != nil {
+		return errors.Wrap(err, "could not save seen gossip")
+	}
+	return nil
+}
+
+// loadSeenCaches fills the seen block and attestation caches from the gossip seen before the
+// last shutdown, leaving out the entries which are no longer recent.
+func (r *Service) loadSeenCaches(ctx context.Context) error {
+	seen, err := r.db.SeenGossip(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve seen gossip")
+	}
+	cutoff := roughtime.Now().Add(-seenCachePersistDuration()).Unix()
+	keys := make([]string, 0, len(seen))
+	for k, t := range seen {
+		if t >= cutoff && len(k) > 0 {
+			keys = append(keys, k)
+		}
+	}
+	// Add the oldest entries first, so they are the first to be evicted.
+	sort.Slice(keys, func(i, j int) bool {
+		return seen[keys[i]] < seen[keys[j]]
+	})
+	for _, k := range keys {
+		switch k[:1] {
+		case seenBlockPrefix:
+			r.seenBlockLock.Lock()
+			r.seenBlockCache.Add(k[1:], seen[k])
+			r.seenBlockLock.Unlock()
+		case seenAttestationPrefix:
+			r.seenAttestationLock.Lock()
+			r.seenAttestationCache.Add(k[1:], seen[k])
+			r.seenAttestationLock.Unlock()
+		}
+	}
+	return nil
+}
+
+// collectSeen adds the cache entries seen at or after the cutoff to the seen map, under the
+// given key prefix.
+func collectSeen(seen map[string]int64, prefix string, cache *lru.Cache, lock *sync.RWMutex, cutoff int64) {
+	lock.RLock()
+	defer lock.RUnlock()
+	for _, k := range cache.Keys() {
+		key, ok := k.(string)
+		if !ok {
+			continue
+		}
+		v, ok := cache.Peek(k)
+		if !ok {
+			continue
+		}
+		t, ok := v.(int64)
+		if !ok || t < cutoff {
+			continue
+		}
+		seen[prefix+key] = t
+	}
+}
@@ -0,0 +1,46 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+func TestSeenCaches_SaveAndLoad(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.SetupDB(t)
+	r := &Service{db: db}
+	if err := r.initCaches(); err != nil {
+		t.Fatal(err)
+	}
+	now := roughtime.Now().Unix()
+	stale := roughtime.Now().Add(-2 * seenCachePersistDuration()).Unix()
+	r.seenBlockCache.Add("block", now)
+	r.seenBlockCache.Add("old block", stale)
+	r.seenAttestationCache.Add("attestation", now)
+	if err := r.saveSeenCaches(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := &Service{db: db}
+	if err := restarted.initCaches(); err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.loadSeenCaches(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := restarted.seenBlockCache.Get("block"); !ok || v.(int64) != now {
+		t.Errorf("Wanted block seen at %d, received %v", now, v)
+	}
+	if _, ok := restarted.seenBlockCache.Get("old block"); ok {
+		t.Error("Wanted stale block to not be loaded")
+	}
+	if _, ok := restarted.seenAttestationCache.Get("attestation"); !ok {
+		t.Error("Wanted attestation to be loaded")
+	}
+	if _, ok := restarted.seenBlockCache.Get("attestation"); ok {
+		t.Error("Wanted attestation to not be loaded into the block cache")
+	}
+}
@@ -28,8 +28,8 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/shared"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/runutil"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 )
 
 var _ = shared.Service(&Service{})
@@ -55,6 +55,14 @@ type Config struct {
 	AttestationNotifier operation.Notifier
 	StateSummaryCache   *cache.StateSummaryCache
 	StateGen            *stategen.State
+	ColdHistory         ColdHistory
+}
+
+// ColdHistory serves finalized blocks from outside of the database, such as era archive files.
+type ColdHistory interface {
+	StartSlot() uint64
+	EndSlot() uint64
+	BlocksByRange(startSlot uint64, endSlot uint64, step uint64) ([]*ethpb.SignedBeaconBlock, error)
 }
 
 // This defines the interface for interacting with block chain service
@@ -90,6 +98,9 @@ type Service struct {
 	stateNotifier             statefeed.Notifier
 	blockNotifier             blockfeed.Notifier
 	blocksRateLimiter         *leakybucket.Collector
+	globalBlocksRateLimiter   *leakybucket.Collector
+	blocksQuotaLock           sync.Mutex
+	reservedBlocks            map[string]int64
 	attestationNotifier       operation.Notifier
 	seenBlockLock             sync.RWMutex
 	seenBlockCache            *lru.Cache
@@ -103,38 +114,54 @@ type Service struct {
 	seenAttesterSlashingCache *lru.Cache
 	stateSummaryCache         *cache.StateSummaryCache
 	stateGen                  *stategen.State
+	coldHistory               ColdHistory
+	stateSnapshotRateLimiter  *leakybucket.Collector
+	stateSnapshotLock         sync.Mutex
+	stateSnapshotRoot         [32]byte
+	stateSnapshotEnc          []byte
+	backfillSlot              uint64
+	messageRegistryLock       sync.Mutex
+	messageRegistry           *p2p.MessageRegistry
 }
 
 // NewRegularSync service.
 func NewRegularSync(cfg *Config) *Service {
+	r := newService(cfg)
+	go r.registerHandlers()
+	return r
+}
+
+func newService(cfg *Config) *Service {
 	// Intialize block limits.
 	allowedBlocksPerSecond := float64(flags.Get().BlockBatchLimit)
 	allowedBlocksBurst := int64(flags.Get().BlockBatchLimitBurstFactor * flags.Get().BlockBatchLimit)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	r := &Service{
-		ctx:                  ctx,
-		cancel:               cancel,
-		db:                   cfg.DB,
-		p2p:                  cfg.P2P,
-		attPool:              cfg.AttPool,
-		exitPool:             cfg.ExitPool,
-		slashingPool:         cfg.SlashingPool,
-		chain:                cfg.Chain,
-		initialSync:          cfg.InitialSync,
-		attestationNotifier:  cfg.AttestationNotifier,
-		slotToPendingBlocks:  make(map[uint64]*ethpb.SignedBeaconBlock),
-		seenPendingBlocks:    make(map[[32]byte]bool),
-		blkRootToPendingAtts: make(map[[32]byte][]*ethpb.SignedAggregateAttestationAndProof),
-		stateNotifier:        cfg.StateNotifier,
-		blockNotifier:        cfg.BlockNotifier,
-		stateSummaryCache:    cfg.StateSummaryCache,
-		stateGen:             cfg.StateGen,
-		blocksRateLimiter:    leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */),
+		ctx:                     ctx,
+		cancel:                  cancel,
+		db:                      cfg.DB,
+		p2p:                     cfg.P2P,
+		attPool:                 cfg.AttPool,
+		exitPool:                cfg.ExitPool,
+		slashingPool:            cfg.SlashingPool,
+		chain:                   cfg.Chain,
+		initialSync:             cfg.InitialSync,
+		attestationNotifier:     cfg.AttestationNotifier,
+		slotToPendingBlocks:     make(map[uint64]*ethpb.SignedBeaconBlock),
+		seenPendingBlocks:       make(map[[32]byte]bool),
+		blkRootToPendingAtts:    make(map[[32]byte][]*ethpb.SignedAggregateAttestationAndProof),
+		stateNotifier:           cfg.StateNotifier,
+		blockNotifier:           cfg.BlockNotifier,
+		stateSummaryCache:       cfg.StateSummaryCache,
+		stateGen:                cfg.StateGen,
+		coldHistory:             cfg.ColdHistory,
+		blocksRateLimiter:       leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */),
+		globalBlocksRateLimiter: newGlobalBlocksRateLimiter(),
+		stateSnapshotRateLimiter: leakybucket.NewCollector(
+			allowedSnapshotChunksPerSecond, allowedSnapshotChunksBurst, false, /* deleteEmptyBuckets */
+		),
 	}
-
-	go r.registerHandlers()
-
 	return r
 }
 
@@ -143,6 +170,9 @@ func (r *Service) Start() {
 	if err := r.initCaches(); err != nil {
 		panic(err)
 	}
+	if err := r.loadSeenCaches(r.ctx); err != nil {
+		log.WithError(err).Warn("Could not load seen gossip from before the last shutdown")
+	}
 
 	r.p2p.AddConnectionHandler(r.reValidatePeer, r.sendGenericGoodbyeMessage)
 	r.p2p.AddDisconnectionHandler(r.removeDisconnectedPeerStatus)
@@ -151,14 +181,26 @@ func (r *Service) Start() {
 	r.processPendingAttsQueue()
 	r.maintainPeerStatuses()
 	r.resyncIfBehind()
+	go r.backfill()
 
 	// Update sync metrics.
 	runutil.RunEvery(r.ctx, time.Second*10, r.updateMetrics)
 }
 
-// Stop the regular sync service.
+// Stop the regular sync service. Gossip is no longer accepted once the service context is
+// cancelled and the topic validators are removed, then connected peers are sent a goodbye
+// message before the p2p service closes the connections.
 func (r *Service) Stop() error {
-	defer r.cancel()
+	r.cancel()
+	for _, topic := range r.p2p.PubSub().GetTopics() {
+		if err := r.p2p.PubSub().UnregisterTopicValidator(topic); err != nil {
+			log.WithError(err).WithField("topic", topic).Debug("Could not unregister topic validator")
+		}
+	}
+	if err := r.saveSeenCaches(context.Background()); err != nil {
+		log.WithError(err).Error("Could not save seen gossip")
+	}
+	r.sendShutdownGoodbyes()
 	return nil
 }
 
@@ -225,9 +267,9 @@ func (r *Service) registerHandlers() {
 					return
 				}
 				log.WithField("starttime", data.StartTime).Debug("Received state initialized event")
-				if data.StartTime.After(roughtime.Now()) {
+				if data.StartTime.After(slotutil.Now()) {
 					stateSub.Unsubscribe()
-					time.Sleep(roughtime.Until(data.StartTime))
+					<-slotutil.After(slotutil.Until(data.StartTime))
 				}
 				r.chainStarted = true
 			}
@@ -1,11 +1,10 @@
 package sync
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"reflect"
-	"runtime/debug"
-	"strings"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -14,8 +13,8 @@ import (
 	pb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/msgtrace"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
-	"github.com/prysmaticlabs/prysm/shared/messagehandler"
 	"github.com/prysmaticlabs/prysm/shared/p2putils"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
@@ -88,18 +87,22 @@ func (r *Service) registerSubscribers() {
 // subscribe to a given topic with a given validator and subscription handler.
 // The base protobuf message is used to initialize new messages for decoding.
 func (r *Service) subscribe(topic string, validator pubsub.ValidatorEx, handle subHandler) *pubsub.Subscription {
-	base := p2p.GossipTopicMappings[topic]
-	if base == nil {
-		panic(fmt.Sprintf("%s is not mapped to any message in GossipTopicMappings", topic))
+	digest, err := r.forkDigest()
+	if err != nil {
+		log.WithError(err).Fatal("Could not compute fork digest")
 	}
-	return r.subscribeWithBase(base, r.addDigestToTopic(topic), validator, handle)
+	base, err := r.gossipMessage(digest, topic)
+	if err != nil {
+		panic(err)
+	}
+	return r.subscribeWithBase(base, fmt.Sprintf(topic, digest), validator, handle)
 }
 
 func (r *Service) subscribeWithBase(base proto.Message, topic string, validator pubsub.ValidatorEx, handle subHandler) *pubsub.Subscription {
 	topic += r.p2p.Encoding().ProtocolSuffix()
 	log := log.WithField("topic", topic)
 
-	if err := r.p2p.PubSub().RegisterTopicValidator(wrapAndReportValidation(topic, validator)); err != nil {
+	if err := r.p2p.PubSub().RegisterTopicValidator(r.wrapAndReportValidation(topic, validator)); err != nil {
 		log.WithError(err).Error("Failed to register validator")
 	}
 
@@ -120,10 +123,9 @@ func (r *Service) subscribeWithBase(base proto.Message, topic string, validator
 		defer span.End()
 
 		defer func() {
-			if r := recover(); r != nil {
-				traceutil.AnnotateError(span, fmt.Errorf("panic occurred: %v", r))
-				log.WithField("error", r).Error("Panic occurred")
-				debug.PrintStack()
+			if rec := recover(); rec != nil {
+				traceutil.AnnotateError(span, fmt.Errorf("panic occurred: %v", rec))
+				r.handlePanic(topic, msg.ReceivedFrom, msg.Data, rec)
 			}
 		}()
 
@@ -166,21 +168,51 @@ func (r *Service) subscribeWithBase(base proto.Message, topic string, validator
 }
 
 // Wrap the pubsub validator with a metric monitoring function. This function increments the
-// appropriate counter if the particular message fails to validate.
-func wrapAndReportValidation(topic string, v pubsub.ValidatorEx) (string, pubsub.ValidatorEx) {
-	return topic, func(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
-		defer messagehandler.HandlePanic(ctx, msg)
+// appropriate counter if the particular message fails to validate. Messages whose validation
+// panics are rejected.
+func (r *Service) wrapAndReportValidation(topic string, v pubsub.ValidatorEx) (string, pubsub.ValidatorEx) {
+	return topic, func(ctx context.Context, pid peer.ID, msg *pubsub.Message) (res pubsub.ValidationResult) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				r.handlePanic(topic, pid, msg.Data, rec)
+				messageFailedValidationCounter.WithLabelValues(topic).Inc()
+				res = pubsub.ValidationReject
+			}
+		}()
 		ctx, cancel := context.WithTimeout(ctx, pubsubMessageTimeout)
 		defer cancel()
 		messageReceivedCounter.WithLabelValues(topic).Inc()
 		b := v(ctx, pid, msg)
-		if b == pubsub.ValidationReject {
+		msgtrace.Record(msgtrace.Inbound, msgtrace.Gossip, topic, pid, len(msg.Data), msg.ValidatorData, validationResultName(b))
+		switch b {
+		case pubsub.ValidationReject:
+			// Rejected messages are invalid by the spec rules rather than merely useless, so the
+			// peer which forwarded one is penalized instead of the message being silently dropped.
 			messageFailedValidationCounter.WithLabelValues(topic).Inc()
+			if pid != r.p2p.PeerID() {
+				r.penalizeGossip(pid)
+			}
+		case pubsub.ValidationIgnore:
+			messageIgnoredValidationCounter.WithLabelValues(topic).Inc()
 		}
 		return b
 	}
 }
 
+// validationResultName returns the name of the validation result recorded with traced messages.
+func validationResultName(res pubsub.ValidationResult) string {
+	switch res {
+	case pubsub.ValidationAccept:
+		return "accept"
+	case pubsub.ValidationIgnore:
+		return "ignore"
+	case pubsub.ValidationReject:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
 // subscribe to a dynamically changing list of subnets. This method expects a fmt compatible
 // string for the topic name and the list of subnets for subscribed topics that should be
 // maintained.
@@ -189,14 +221,14 @@ func (r *Service) subscribeDynamicWithSubnets(
 	validate pubsub.ValidatorEx,
 	handle subHandler,
 ) {
-	base := p2p.GossipTopicMappings[topicFormat]
-	if base == nil {
-		log.Fatalf("%s is not mapped to any message in GossipTopicMappings", topicFormat)
-	}
 	digest, err := r.forkDigest()
 	if err != nil {
 		log.WithError(err).Fatal("Could not compute fork digest")
 	}
+	base, err := r.gossipMessage(digest, topicFormat)
+	if err != nil {
+		log.WithError(err).Fatal("Could not subscribe to topic")
+	}
 	subscriptions := make(map[uint64]*pubsub.Subscription, params.BeaconConfig().MaxCommitteesPerSlot)
 	genesis := r.chain.GenesisTime()
 	ticker := slotutil.GetSlotTicker(genesis, params.BeaconConfig().SecondsPerSlot)
@@ -242,14 +274,14 @@ func (r *Service) subscribeDynamicWithSubnets(
 // determine the appropriate number of topics. This method supports only sequential number ranges
 // for topics.
 func (r *Service) subscribeDynamic(topicFormat string, determineSubsLen func() int, validate pubsub.ValidatorEx, handle subHandler) {
-	base := p2p.GossipTopicMappings[topicFormat]
-	if base == nil {
-		log.Fatalf("%s is not mapped to any message in GossipTopicMappings", topicFormat)
-	}
 	digest, err := r.forkDigest()
 	if err != nil {
 		log.WithError(err).Fatal("Could not compute fork digest")
 	}
+	base, err := r.gossipMessage(digest, topicFormat)
+	if err != nil {
+		log.WithError(err).Fatal("Could not subscribe to topic")
+	}
 	var subscriptions []*pubsub.Subscription
 
 	stateChannel := make(chan *feed.Event, 1)
@@ -324,7 +356,9 @@ func (r *Service) subscribeAggregatorSubnet(subscriptions map[uint64]*pubsub.Sub
 		log.Debugf("No peers found subscribed to attestation gossip subnet with "+
 			"committee index %d. Searching network for peers subscribed to the subnet.", idx)
 		go func(idx uint64) {
-			_, err := r.p2p.FindPeersWithSubnet(idx)
+			ctx, cancel := context.WithTimeout(r.ctx, subnetLookupTimeout())
+			defer cancel()
+			_, err := r.p2p.FindPeersWithSubnet(ctx, idx)
 			if err != nil {
 				log.Errorf("Could not search for peers: %v", err)
 				return
@@ -343,7 +377,9 @@ func (r *Service) lookupAttesterSubnets(digest [4]byte, idx uint64) {
 			"committee index %d. Searching network for peers subscribed to the subnet.", idx)
 		go func(idx uint64) {
 			// perform a search for peers with the desired committee index.
-			_, err := r.p2p.FindPeersWithSubnet(idx)
+			ctx, cancel := context.WithTimeout(r.ctx, subnetLookupTimeout())
+			defer cancel()
+			_, err := r.p2p.FindPeersWithSubnet(ctx, idx)
 			if err != nil {
 				log.Errorf("Could not search for peers: %v", err)
 				return
@@ -352,25 +388,45 @@ func (r *Service) lookupAttesterSubnets(digest [4]byte, idx uint64) {
 	}
 }
 
+// subnetLookupTimeout bounds a search for subnet peers to a single slot, after which the
+// next round of subnet subscriptions decides whether another search is needed.
+func subnetLookupTimeout() time.Duration {
+	return time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+}
+
 // find if we have peers who are subscribed to the same subnet
 func (r *Service) validPeersExist(subnetTopic string, idx uint64) bool {
 	numOfPeers := r.p2p.PubSub().ListPeers(subnetTopic + r.p2p.Encoding().ProtocolSuffix())
 	return len(r.p2p.Peers().SubscribedToSubnet(idx)) > 0 || len(numOfPeers) > 0
 }
 
-// Add fork digest to topic.
-func (r *Service) addDigestToTopic(topic string) string {
-	if !strings.Contains(topic, "%x") {
-		log.Fatal("Topic does not have appropriate formatter for digest")
+func (r *Service) forkDigest() ([4]byte, error) {
+	genRoot := r.chain.GenesisValidatorRoot()
+	return p2putils.CreateForkDigest(r.chain.GenesisTime(), genRoot[:])
+}
+
+// messages returns the message registry of the chain, which is created once the genesis
+// validators root is known.
+func (r *Service) messages() (*p2p.MessageRegistry, error) {
+	r.messageRegistryLock.Lock()
+	defer r.messageRegistryLock.Unlock()
+	genRoot := r.chain.GenesisValidatorRoot()
+	if r.messageRegistry != nil && bytes.Equal(r.messageRegistry.GenesisValidatorsRoot(), genRoot[:]) {
+		return r.messageRegistry, nil
 	}
-	digest, err := r.forkDigest()
+	registry, err := p2p.NewMessageRegistry(genRoot[:])
 	if err != nil {
-		log.WithError(err).Fatal("Could not compute fork digest")
+		return nil, err
 	}
-	return fmt.Sprintf(topic, digest)
+	r.messageRegistry = registry
+	return registry, nil
 }
 
-func (r *Service) forkDigest() ([4]byte, error) {
-	genRoot := r.chain.GenesisValidatorRoot()
-	return p2putils.CreateForkDigest(r.chain.GenesisTime(), genRoot[:])
+// gossipMessage returns the base message of the gossip topic format under the fork digest.
+func (r *Service) gossipMessage(digest [4]byte, topicFormat string) (proto.Message, error) {
+	registry, err := r.messages()
+	if err != nil {
+		return nil, err
+	}
+	return registry.GossipMessage(digest, topicFormat)
 }
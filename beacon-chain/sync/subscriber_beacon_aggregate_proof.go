@@ -10,6 +10,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 )
 
 // beaconAggregateProofSubscriber forwards the incoming validated aggregated attestation and proof to the
@@ -38,6 +39,7 @@ func (r *Service) beaconAggregateProofSubscriber(ctx context.Context, msg proto.
 	if !helpers.IsAggregated(a.Message.Aggregate) {
 		return r.attPool.SaveUnaggregatedAttestation(a.Message.Aggregate)
 	}
+	tracer.Observe(a.Message.Aggregate, tracer.Aggregated)
 
 	return r.attPool.SaveAggregatedAttestation(a.Message.Aggregate)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
 )
@@ -25,6 +26,7 @@ func (r *Service) committeeIndexBeaconAttestationSubscriber(ctx context.Context,
 		return errors.New("nil attestation")
 	}
 	r.setSeenCommitteeIndicesSlot(a.Data.Slot, a.Data.CommitteeIndex, a.AggregationBits)
+	tracer.Observe(a, tracer.Received)
 
 	exists, err := r.attPool.HasAggregatedAttestation(a)
 	if err != nil {
@@ -58,22 +60,29 @@ func (r *Service) persistentCommitteeIndices() []uint64 {
 	return cache.CommitteeIDs.GetAllCommittees()
 }
 
+// aggregatorCommitteeIndices returns the subnets of the committees announced by aggregators
+// from the current slot up to an epoch ahead, so the node joins the subnet of an aggregation duty
+// of the next epoch an epoch before the duty and has a full mesh of subnet peers by then.
 func (r *Service) aggregatorCommitteeIndices(currentSlot uint64) []uint64 {
-	endEpoch := helpers.SlotToEpoch(currentSlot) + 1
-	endSlot := endEpoch * params.BeaconConfig().SlotsPerEpoch
-	commIds := []uint64{}
+	endSlot := currentSlot + params.BeaconConfig().SlotsPerEpoch
+	subnets := []uint64{}
 	for i := currentSlot; i <= endSlot; i++ {
-		commIds = append(commIds, cache.CommitteeIDs.GetAggregatorCommitteeIDs(i)...)
+		for _, id := range cache.CommitteeIDs.GetAggregatorCommitteeIDs(i) {
+			subnets = append(subnets, helpers.ComputeSubnetForCommittee(id))
+		}
 	}
-	return sliceutil.SetUint64(commIds)
+	return sliceutil.SetUint64(subnets)
 }
 
+// attesterCommitteeIndices returns the subnets of the committees announced by attesters from the
+// current slot up to an epoch ahead, for which the node looks up subnet peers to publish to.
 func (r *Service) attesterCommitteeIndices(currentSlot uint64) []uint64 {
-	endEpoch := helpers.SlotToEpoch(currentSlot) + 1
-	endSlot := endEpoch * params.BeaconConfig().SlotsPerEpoch
-	commIds := []uint64{}
+	endSlot := currentSlot + params.BeaconConfig().SlotsPerEpoch
+	subnets := []uint64{}
 	for i := currentSlot; i <= endSlot; i++ {
-		commIds = append(commIds, cache.CommitteeIDs.GetAttesterCommitteeIDs(i)...)
+		for _, id := range cache.CommitteeIDs.GetAttesterCommitteeIDs(i) {
+			subnets = append(subnets, helpers.ComputeSubnetForCommittee(id))
+		}
 	}
-	return sliceutil.SetUint64(commIds)
+	return sliceutil.SetUint64(subnets)
 }
@@ -114,3 +114,19 @@ func TestService_committeeIndexBeaconAttestationSubscriber_ValidMessage(t *testi
 		t.Error("No attestations put into pool")
 	}
 }
+
+func TestService_aggregatorCommitteeIndices_EpochLookahead(t *testing.T) {
+	r := &Service{}
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	currentSlot := 1000*slotsPerEpoch + slotsPerEpoch - 1
+	subnetCount := params.BeaconNetworkConfig().AttestationSubnetCount
+
+	// An aggregation duty at the end of the next epoch, and one past the lookahead.
+	cache.CommitteeIDs.AddAggregatorCommiteeID(currentSlot+slotsPerEpoch, subnetCount+2)
+	cache.CommitteeIDs.AddAggregatorCommiteeID(currentSlot+slotsPerEpoch+1, 5)
+
+	subnets := r.aggregatorCommitteeIndices(currentSlot)
+	if len(subnets) != 1 || subnets[0] != 2 {
+		t.Errorf("Wanted the subnet of the duty an epoch ahead, received %v", subnets)
+	}
+}
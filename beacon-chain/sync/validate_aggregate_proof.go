@@ -11,6 +11,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/attestationutil"
 	"github.com/prysmaticlabs/prysm/shared/bls"
@@ -18,6 +19,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
 )
@@ -49,7 +51,12 @@ func (r *Service) validateAggregateAndProof(ctx context.Context, pid peer.ID, ms
 		return pubsub.ValidationReject
 	}
 
-	if m.Message == nil || m.Message.Aggregate == nil || m.Message.Aggregate.Data == nil {
+	if err := verifyAggregateStructure(m); err != nil {
+		r.penalizeMalformed(pid, err)
+		return pubsub.ValidationReject
+	}
+	if err := verifyAttestationTargetEpoch(m.Message.Aggregate.Data); err != nil {
+		traceutil.AnnotateError(span, err)
 		return pubsub.ValidationReject
 	}
 	// Verify this is the first aggregate received from the aggregator with index and slot.
@@ -89,6 +96,7 @@ func (r *Service) validateAggregatedAtt(ctx context.Context, signed *ethpb.Signe
 	attSlot := signed.Message.Aggregate.Data.Slot
 	if err := validateAggregateAttTime(attSlot, uint64(r.chain.GenesisTime().Unix())); err != nil {
 		traceutil.AnnotateError(span, err)
+		tracer.Drop(signed.Message.Aggregate, tracer.Late)
 		return pubsub.ValidationIgnore
 	}
 
@@ -139,11 +147,7 @@ func (r *Service) validateAggregatedAtt(ctx context.Context, signed *ethpb.Signe
 func (r *Service) validateBlockInAttestation(ctx context.Context, s *ethpb.SignedAggregateAttestationAndProof) bool {
 	a := s.Message
 	// Verify the block being voted and the processed state is in DB. The block should have passed validation if it's in the DB.
-	blockRoot := bytesutil.ToBytes32(a.Aggregate.Data.BeaconBlockRoot)
-	hasStateSummary := featureconfig.Get().NewStateMgmt && r.db.HasStateSummary(ctx, blockRoot) || r.stateSummaryCache.Has(blockRoot)
-	hasState := r.db.HasState(ctx, blockRoot) || hasStateSummary
-	hasBlock := r.db.HasBlock(ctx, blockRoot)
-	if !(hasState && hasBlock) {
+	if !r.hasBlockAndState(ctx, bytesutil.ToBytes32(a.Aggregate.Data.BeaconBlockRoot), a.Aggregate.Data.Slot) {
 		// A node doesn't have the block, it'll request from peer while saving the pending attestation to a queue.
 		r.savePendingAtt(s)
 		return false
@@ -151,6 +155,19 @@ func (r *Service) validateBlockInAttestation(ctx context.Context, s *ethpb.Signe
 	return true
 }
 
+// hasBlockAndState returns true if the block voted for at the slot and its processed state are
+// in the DB. Votes for the canonical chain of the head are found in the recent roots cache,
+// without reading the DB.
+func (r *Service) hasBlockAndState(ctx context.Context, blockRoot [32]byte, slot uint64) bool {
+	headRoot, err := r.chain.HeadRoot(ctx)
+	if err == nil && helpers.IsRecentHeadBlockRoot(bytesutil.ToBytes32(headRoot), blockRoot, slot) {
+		return true
+	}
+	hasStateSummary := featureconfig.Get().NewStateMgmt && r.db.HasStateSummary(ctx, blockRoot) || r.stateSummaryCache.Has(blockRoot)
+	hasState := r.db.HasState(ctx, blockRoot) || hasStateSummary
+	return hasState && r.db.HasBlock(ctx, blockRoot)
+}
+
 // Returns true if the node has received aggregate for the aggregator with index and target epoch.
 func (r *Service) hasSeenAggregatorIndexEpoch(epoch uint64, aggregatorIndex uint64) bool {
 	r.seenAttestationLock.RLock()
@@ -165,7 +182,7 @@ func (r *Service) setAggregatorIndexEpochSeen(epoch uint64, aggregatorIndex uint
 	r.seenAttestationLock.Lock()
 	defer r.seenAttestationLock.Unlock()
 	b := append(bytesutil.Bytes32(epoch), bytesutil.Bytes32(aggregatorIndex)...)
-	r.seenAttestationCache.Add(string(b), true)
+	r.seenAttestationCache.Add(string(b), roughtime.Now().Unix())
 }
 
 // This validates the aggregator's index in state is within the attesting indices of the attestation.
@@ -198,7 +215,7 @@ func validateAggregateAttTime(attSlot uint64, genesisTime uint64) error {
 	attTime := 1000 * (genesisTime + (attSlot * params.BeaconConfig().SecondsPerSlot))
 	attSlotRange := attSlot + params.BeaconNetworkConfig().AttestationPropagationSlotRange
 	attTimeRange := 1000 * (genesisTime + (attSlotRange * params.BeaconConfig().SecondsPerSlot))
-	currentTimeInSec := roughtime.Now().Unix()
+	currentTimeInSec := slotutil.Now().Unix()
 	currentTime := 1000 * currentTimeInSec
 
 	// Verify attestation slot is within the last ATTESTATION_PROPAGATION_SLOT_RANGE slots.
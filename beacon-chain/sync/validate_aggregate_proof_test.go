@@ -3,7 +3,6 @@ package sync
 import (
 	"bytes"
 	"context"
-	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -18,7 +17,6 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
-	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
@@ -146,6 +144,7 @@ func TestValidateAggregateAndProof_NoBlock(t *testing.T) {
 		blkRootToPendingAtts: make(map[[32]byte][]*ethpb.SignedAggregateAttestationAndProof),
 		seenAttestationCache: c,
 		stateSummaryCache:    cache.NewStateSummaryCache(),
+		chain:                &mock.ChainService{Genesis: time.Now()},
 	}
 
 	buf := new(bytes.Buffer)
@@ -157,7 +156,7 @@ func TestValidateAggregateAndProof_NoBlock(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(signedAggregateAndProof)],
+				gossipTopic(t, r, signedAggregateAndProof),
 			},
 		},
 	}
@@ -232,7 +231,7 @@ func TestValidateAggregateAndProof_NotWithinSlotRange(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(signedAggregateAndProof)],
+				gossipTopic(t, r, signedAggregateAndProof),
 			},
 		},
 	}
@@ -252,7 +251,7 @@ func TestValidateAggregateAndProof_NotWithinSlotRange(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(signedAggregateAndProof)],
+				gossipTopic(t, r, signedAggregateAndProof),
 			},
 		},
 	}
@@ -321,7 +320,7 @@ func TestValidateAggregateAndProof_ExistedInPool(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(signedAggregateAndProof)],
+				gossipTopic(t, r, signedAggregateAndProof),
 			},
 		},
 	}
@@ -450,7 +449,7 @@ func TestValidateAggregateAndProofWithNewStateMgmt_CanValidate(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(signedAggregateAndProof)],
+				gossipTopic(t, r, signedAggregateAndProof),
 			},
 		},
 	}
@@ -577,7 +576,7 @@ func TestVerifyIndexInCommittee_SeenAggregatorEpoch(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(signedAggregateAndProof)],
+				gossipTopic(t, r, signedAggregateAndProof),
 			},
 		},
 	}
@@ -596,7 +595,7 @@ func TestVerifyIndexInCommittee_SeenAggregatorEpoch(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(signedAggregateAndProof)],
+				gossipTopic(t, r, signedAggregateAndProof),
 			},
 		},
 	}
@@ -606,3 +605,41 @@ func TestVerifyIndexInCommittee_SeenAggregatorEpoch(t *testing.T) {
 		t.Fatal("Validated status is true")
 	}
 }
+
+func TestHasBlockAndState_RecentHeadBlock(t *testing.T) {
+	db := dbtest.SetupDB(t)
+	ctx := context.Background()
+
+	headRoot := [32]byte{'h'}
+	headState := testutil.NewBeaconState()
+	if err := headState.UpdateBlockRootAtIndex(3, [32]byte{'a'}); err != nil {
+		t.Fatal(err)
+	}
+	if err := headState.SetSlot(5); err != nil {
+		t.Fatal(err)
+	}
+	if err := helpers.UpdateRecentRootsCache(headRoot, headState); err != nil {
+		t.Fatal(err)
+	}
+	defer helpers.ClearRecentRootsCache()
+	r := &Service{
+		db:                db,
+		stateSummaryCache: cache.NewStateSummaryCache(),
+		chain:             &mock.ChainService{Root: headRoot[:]},
+	}
+
+	// Votes for the canonical chain of the head are found without the blocks in the DB.
+	if !r.hasBlockAndState(ctx, [32]byte{'a'}, 3) {
+		t.Error("Wanted the canonical block at slot 3 to be found in the recent roots cache")
+	}
+	if !r.hasBlockAndState(ctx, headRoot, 6) {
+		t.Error("Wanted the head block to be found after the head slot")
+	}
+	if r.hasBlockAndState(ctx, [32]byte{'a'}, 4) {
+		t.Error("Wanted a block of another slot not to be found")
+	}
+	r.chain = &mock.ChainService{Root: []byte{'o'}}
+	if r.hasBlockAndState(ctx, [32]byte{'a'}, 3) {
+		t.Error("Wanted the block not to be found for another head")
+	}
+}
@@ -8,7 +8,10 @@ import (
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
@@ -72,6 +75,9 @@ func (r *Service) validateAttesterSlashing(ctx context.Context, pid peer.ID, msg
 	if err := blocks.VerifyAttesterSlashing(ctx, s, slashing); err != nil {
 		return pubsub.ValidationReject
 	}
+	if !hasSlashableIndex(s, slashing) {
+		return pubsub.ValidationReject
+	}
 
 	msg.ValidatorData = slashing // Used in downstream subscriber
 	return pubsub.ValidationAccept
@@ -82,17 +88,7 @@ func (r *Service) hasSeenAttesterSlashingIndices(indices1 []uint64, indices2 []u
 	r.seenAttesterSlashingLock.RLock()
 	defer r.seenAttesterSlashingLock.RUnlock()
 
-	slashableIndices := sliceutil.IntersectionUint64(indices1, indices2)
-	sort.SliceStable(slashableIndices, func(i, j int) bool {
-		return slashableIndices[i] < slashableIndices[j]
-	})
-	IndicesInBytes := make([]byte, 0, len(slashableIndices))
-	for _, i := range slashableIndices {
-		IndicesInBytes = append(IndicesInBytes, byte(i))
-	}
-	b := hashutil.FastSum256(IndicesInBytes)
-
-	_, seen := r.seenAttesterSlashingCache.Get(b)
+	_, seen := r.seenAttesterSlashingCache.Get(attesterSlashingIndicesKey(indices1, indices2))
 	return seen
 }
 
@@ -101,15 +97,35 @@ func (r *Service) setAttesterSlashingIndicesSeen(indices1 []uint64, indices2 []u
 	r.seenAttesterSlashingLock.Lock()
 	defer r.seenAttesterSlashingLock.Unlock()
 
+	r.seenAttesterSlashingCache.Add(attesterSlashingIndicesKey(indices1, indices2), true)
+}
+
+// attesterSlashingIndicesKey returns the key of the sorted slashable indices of an attester slashing
+// in the attester slashing cache.
+func attesterSlashingIndicesKey(indices1 []uint64, indices2 []uint64) [32]byte {
 	slashableIndices := sliceutil.IntersectionUint64(indices1, indices2)
 	sort.SliceStable(slashableIndices, func(i, j int) bool {
 		return slashableIndices[i] < slashableIndices[j]
 	})
-	IndicesInBytes := make([]byte, 0, len(slashableIndices))
+	indicesInBytes := make([]byte, 0, 8*len(slashableIndices))
 	for _, i := range slashableIndices {
-		IndicesInBytes = append(IndicesInBytes, byte(i))
+		indicesInBytes = append(indicesInBytes, bytesutil.Bytes8(i)...)
 	}
-	b := hashutil.FastSum256(IndicesInBytes)
+	return hashutil.FastSum256(indicesInBytes)
+}
 
-	r.seenAttesterSlashingCache.Add(b, true)
+// hasSlashableIndex returns true if a validator in the intersection of the attesting indices of an
+// attester slashing can still be slashed, otherwise the slashing would be rejected in a block.
+func hasSlashableIndex(s *stateTrie.BeaconState, slashing *ethpb.AttesterSlashing) bool {
+	epoch := helpers.CurrentEpoch(s)
+	for _, i := range sliceutil.IntersectionUint64(slashing.Attestation_1.AttestingIndices, slashing.Attestation_2.AttestingIndices) {
+		val, err := s.ValidatorAtIndexReadOnly(i)
+		if err != nil {
+			continue
+		}
+		if helpers.IsSlashableValidatorUsingTrie(val, epoch) {
+			return true
+		}
+	}
+	return false
 }
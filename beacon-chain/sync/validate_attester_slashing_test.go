@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"math/rand"
-	"reflect"
 	"testing"
 	"time"
 
@@ -14,7 +13,6 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
-	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
@@ -113,7 +111,7 @@ func TestValidateAttesterSlashing_ValidSlashing(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(slashing)],
+				gossipTopic(t, r, slashing),
 			},
 		},
 	}
@@ -157,7 +155,7 @@ func TestValidateAttesterSlashing_ContextTimeout(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(slashing)],
+				gossipTopic(t, r, slashing),
 			},
 		},
 	}
@@ -188,7 +186,7 @@ func TestValidateAttesterSlashing_Syncing(t *testing.T) {
 		Message: &pubsubpb.Message{
 			Data: buf.Bytes(),
 			TopicIDs: []string{
-				p2p.GossipTypeMapping[reflect.TypeOf(slashing)],
+				gossipTopic(t, r, slashing),
 			},
 		},
 	}
@@ -197,3 +195,61 @@ func TestValidateAttesterSlashing_Syncing(t *testing.T) {
 		t.Error("Passed validation")
 	}
 }
+
+func TestValidateAttesterSlashing_NoSlashableIndex(t *testing.T) {
+	p := p2ptest.NewTestP2P(t)
+	ctx := context.Background()
+
+	slashing, s := setupValidAttesterSlashing(t)
+	vals := s.Validators()
+	for _, i := range slashing.Attestation_1.AttestingIndices {
+		vals[i].Slashed = true
+	}
+	if err := s.SetValidators(vals); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := lru.New(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Service{
+		p2p:                       p,
+		chain:                     &mock.ChainService{State: s},
+		initialSync:               &mockSync.Sync{IsSyncing: false},
+		seenAttesterSlashingCache: c,
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := p.Encoding().Encode(buf, slashing); err != nil {
+		t.Fatal(err)
+	}
+	msg := &pubsub.Message{
+		Message: &pubsubpb.Message{
+			Data: buf.Bytes(),
+			TopicIDs: []string{
+				gossipTopic(t, r, slashing),
+			},
+		},
+	}
+	if r.validateAttesterSlashing(ctx, "foobar", msg) != pubsub.ValidationReject {
+		t.Error("Wanted a slashing of already slashed validators to be rejected")
+	}
+}
+
+func TestAttesterSlashingIndicesSeen(t *testing.T) {
+	c, err := lru.New(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Service{seenAttesterSlashingCache: c}
+
+	r.setAttesterSlashingIndicesSeen([]uint64{1, 256, 3}, []uint64{256, 1})
+	if !r.hasSeenAttesterSlashingIndices([]uint64{256, 1}, []uint64{1, 256}) {
+		t.Error("Wanted the slashed indices to be seen in any order")
+	}
+	// Indices which only agree in their lowest byte are different.
+	if r.hasSeenAttesterSlashingIndices([]uint64{0, 1}, []uint64{0, 1}) {
+		t.Error("Wanted other slashed indices not to be seen")
+	}
+}
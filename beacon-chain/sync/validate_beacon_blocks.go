@@ -13,6 +13,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
 )
@@ -50,7 +51,8 @@ func (r *Service) validateBeaconBlockPubSub(ctx context.Context, pid peer.ID, ms
 		return pubsub.ValidationReject
 	}
 
-	if blk.Block == nil {
+	if err := verifyBlockStructure(blk); err != nil {
+		r.penalizeMalformed(pid, err)
 		return pubsub.ValidationReject
 	}
 
@@ -91,10 +93,9 @@ func (r *Service) validateBeaconBlockPubSub(ctx context.Context, pid peer.ID, ms
 
 	// Handle block when the parent is unknown.
 	if !r.db.HasBlock(ctx, bytesutil.ToBytes32(blk.Block.ParentRoot)) {
-		r.pendingQueueLock.Lock()
-		r.slotToPendingBlocks[blk.Block.Slot] = blk
-		r.seenPendingBlocks[blockRoot] = true
-		r.pendingQueueLock.Unlock()
+		if !r.insertPendingBlock(blk, blockRoot, true /* fromGossip */) {
+			log.WithField("blockSlot", blk.Block.Slot).Debug("Pending blocks queue is full, ignoring block with unknown parent")
+		}
 		return pubsub.ValidationIgnore
 	}
 
@@ -130,6 +131,9 @@ func (r *Service) validateBeaconBlockPubSub(ctx context.Context, pid peer.ID, ms
 			log.WithError(err).WithField("blockSlot", blk.Block.Slot).Warn("Incorrect proposer index")
 			return pubsub.ValidationReject
 		}
+		// The block is the first with a valid signature for the proposer and slot, later blocks
+		// are ignored even before this one is processed.
+		r.setSeenBlockIndexSlot(blk.Block.Slot, blk.Block.ProposerIndex)
 	}
 
 	msg.ValidatorData = blk // Used in downstream subscriber
@@ -150,7 +154,7 @@ func (r *Service) setSeenBlockIndexSlot(slot uint64, proposerIdx uint64) {
 	r.seenBlockLock.Lock()
 	defer r.seenBlockLock.Unlock()
 	b := append(bytesutil.Bytes32(slot), bytesutil.Bytes32(proposerIdx)...)
-	r.seenBlockCache.Add(string(b), true)
+	r.seenBlockCache.Add(string(b), roughtime.Now().Unix())
 }
 
 // This captures metrics for block arrival time by subtracts slot start time.
@@ -159,7 +163,7 @@ func captureArrivalTimeMetric(genesisTime uint64, currentSlot uint64) error {
 	if err != nil {
 		return err
 	}
-	diffMs := roughtime.Now().Sub(startTime) / time.Millisecond
+	diffMs := slotutil.Now().Sub(startTime) / time.Millisecond
 	arrivalBlockPropagationHistogram.Observe(float64(diffMs))
 
 	return nil
@@ -8,17 +8,24 @@ import (
 
 	"github.com/libp2p/go-libp2p-core/peer"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/pkg/errors"
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/tracer"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"go.opencensus.io/trace"
 )
 
 // Validation
 // - The attestation's committee index (attestation.data.index) is for the correct subnet.
+// - The attestation's target epoch matches its slot (attestation.data.target.epoch == compute_epoch_at_slot(attestation.data.slot)).
+// - The committee index is within the committee count per slot (attestation.data.index < get_committee_count_per_slot(state, attestation.data.target.epoch)).
 // - The attestation is unaggregated -- that is, it has exactly one participating validator (len([bit for bit in attestation.aggregation_bits if bit == 0b1]) == 1).
 // - The block being voted for (attestation.data.beacon_block_root) passes validation.
 // - attestation.data.slot is within the last ATTESTATION_PROPAGATION_SLOT_RANGE slots (attestation.data.slot + ATTESTATION_PROPAGATION_SLOT_RANGE >= current_slot >= attestation.data.slot).
@@ -54,7 +61,8 @@ func (s *Service) validateCommitteeIndexBeaconAttestation(ctx context.Context, p
 		return pubsub.ValidationReject
 	}
 
-	if att.Data == nil {
+	if err := verifyAttestationStructure(att); err != nil {
+		s.penalizeMalformed(pid, err)
 		return pubsub.ValidationReject
 	}
 	// Verify this the first attestation received for the participating validator for the slot.
@@ -69,14 +77,13 @@ func (s *Service) validateCommitteeIndexBeaconAttestation(ctx context.Context, p
 		traceutil.AnnotateError(span, err)
 		return pubsub.ValidationIgnore
 	}
-	preState, err := s.chain.AttestationPreState(ctx, att)
-	if err != nil {
-		log.WithError(err).Error("Failed to retrieve pre state")
-		traceutil.AnnotateError(span, err)
-		return pubsub.ValidationIgnore
+	if !strings.HasPrefix(originalTopic, fmt.Sprintf(format, digest, helpers.ComputeSubnetForCommittee(att.Data.CommitteeIndex))) {
+		return pubsub.ValidationReject
 	}
-	if !strings.HasPrefix(originalTopic, fmt.Sprintf(format, digest, att.Data.CommitteeIndex)) {
-		return pubsub.ValidationIgnore
+
+	if err := verifyAttestationTargetEpoch(att.Data); err != nil {
+		traceutil.AnnotateError(span, err)
+		return pubsub.ValidationReject
 	}
 
 	// Attestation must be unaggregated.
@@ -87,20 +94,28 @@ func (s *Service) validateCommitteeIndexBeaconAttestation(ctx context.Context, p
 	// Attestation's slot is within ATTESTATION_PROPAGATION_SLOT_RANGE.
 	if err := validateAggregateAttTime(att.Data.Slot, uint64(s.chain.GenesisTime().Unix())); err != nil {
 		traceutil.AnnotateError(span, err)
+		tracer.Drop(att, tracer.Late)
 		return pubsub.ValidationIgnore
 	}
 
 	// Verify the block being voted and the processed state is in DB and. The block should have passed validation if it's in the DB.
-	blockRoot := bytesutil.ToBytes32(att.Data.BeaconBlockRoot)
-	hasStateSummary := featureconfig.Get().NewStateMgmt && s.db.HasStateSummary(ctx, blockRoot) || s.stateSummaryCache.Has(blockRoot)
-	hasState := s.db.HasState(ctx, blockRoot) || hasStateSummary
-	hasBlock := s.db.HasBlock(ctx, blockRoot)
-	if !(hasState && hasBlock) {
+	if !s.hasBlockAndState(ctx, bytesutil.ToBytes32(att.Data.BeaconBlockRoot), att.Data.Slot) {
 		// A node doesn't have the block, it'll request from peer while saving the pending attestation to a queue.
 		s.savePendingAtt(&eth.SignedAggregateAttestationAndProof{Message: &eth.AggregateAttestationAndProof{Aggregate: att}})
 		return pubsub.ValidationIgnore
 	}
 
+	preState, err := s.chain.AttestationPreState(ctx, att)
+	if err != nil {
+		log.WithError(err).Error("Failed to retrieve pre state")
+		traceutil.AnnotateError(span, err)
+		return pubsub.ValidationIgnore
+	}
+	if err := verifyCommitteeIndex(preState, att.Data); err != nil {
+		traceutil.AnnotateError(span, err)
+		return pubsub.ValidationReject
+	}
+
 	// Attestation's signature is a valid BLS signature and belongs to correct public key..
 	if !featureconfig.Get().DisableStrictAttestationPubsubVerification {
 		if err := blocks.VerifyAttestation(ctx, preState, att); err != nil {
@@ -133,5 +148,27 @@ func (s *Service) setSeenCommitteeIndicesSlot(slot uint64, committeeID uint64, a
 	defer s.seenAttestationLock.Unlock()
 	b := append(bytesutil.Bytes32(slot), bytesutil.Bytes32(committeeID)...)
 	b = append(b, aggregateBits...)
-	s.seenAttestationCache.Add(string(b), true)
+	s.seenAttestationCache.Add(string(b), roughtime.Now().Unix())
+}
+
+// verifyAttestationTargetEpoch checks that the target epoch of the attestation data is the epoch of
+// its slot, as an attestation voting for another target can never be included in a block.
+func verifyAttestationTargetEpoch(data *eth.AttestationData) error {
+	if epoch := helpers.SlotToEpoch(data.Slot); data.Target.Epoch != epoch {
+		return fmt.Errorf("target epoch %d does not match epoch %d of slot %d", data.Target.Epoch, epoch, data.Slot)
+	}
+	return nil
+}
+
+// verifyCommitteeIndex checks that the committee index of the attestation data is within the
+// number of committees per slot in the target epoch of the state.
+func verifyCommitteeIndex(s *stateTrie.BeaconState, data *eth.AttestationData) error {
+	activeCount, err := helpers.ActiveValidatorCount(s, data.Target.Epoch)
+	if err != nil {
+		return errors.Wrap(err, "could not get active validator count")
+	}
+	if count := helpers.SlotCommitteeCount(activeCount); data.CommitteeIndex >= count {
+		return fmt.Errorf("committee index %d is not below the committee count %d", data.CommitteeIndex, count)
+	}
+	return nil
 }
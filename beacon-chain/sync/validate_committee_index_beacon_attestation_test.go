@@ -193,3 +193,29 @@ func TestService_validateCommitteeIndexBeaconAttestation(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyAttestationTargetEpoch(t *testing.T) {
+	slot := 3*params.BeaconConfig().SlotsPerEpoch + 1
+	if err := verifyAttestationTargetEpoch(&ethpb.AttestationData{Slot: slot, Target: &ethpb.Checkpoint{Epoch: 3}}); err != nil {
+		t.Errorf("Wanted no error for the target of the slot epoch, received %v", err)
+	}
+	if err := verifyAttestationTargetEpoch(&ethpb.AttestationData{Slot: slot, Target: &ethpb.Checkpoint{Epoch: 2}}); err == nil {
+		t.Error("Expected error for a target of another epoch")
+	}
+}
+
+func TestVerifyCommitteeIndex(t *testing.T) {
+	// Enough validators for two committees per slot.
+	validators := 2 * params.BeaconConfig().SlotsPerEpoch * params.BeaconConfig().TargetCommitteeSize
+	s, _ := testutil.DeterministicGenesisState(t, validators)
+	data := &ethpb.AttestationData{Target: &ethpb.Checkpoint{}}
+
+	data.CommitteeIndex = 1
+	if err := verifyCommitteeIndex(s, data); err != nil {
+		t.Errorf("Wanted no error for a committee index within the committee count, received %v", err)
+	}
+	data.CommitteeIndex = 2
+	if err := verifyCommitteeIndex(s, data); err == nil {
+		t.Error("Expected error for a committee index beyond the committee count")
+	}
+}
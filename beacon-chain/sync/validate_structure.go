@@ -0,0 +1,121 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// The structural checks below run on decoded gossip objects before any signature or state
+// work. Fixed size fields are already enforced by the SSZ decoding, so they bound the
+// variable length lists and bitfields by the limits of the spec, which lets oversized
+// garbage be rejected cheaply.
+
+// verifyBlockStructure checks the operation counts of a block against the maximums of the spec.
+func verifyBlockStructure(blk *ethpb.SignedBeaconBlock) error {
+	if blk == nil || blk.Block == nil || blk.Block.Body == nil {
+		return errors.New("nil block or block body")
+	}
+	body := blk.Block.Body
+	cfg := params.BeaconConfig()
+	if err := verifyCount("proposer slashings", len(body.ProposerSlashings), cfg.MaxProposerSlashings); err != nil {
+		return err
+	}
+	if err := verifyCount("attester slashings", len(body.AttesterSlashings), cfg.MaxAttesterSlashings); err != nil {
+		return err
+	}
+	if err := verifyCount("attestations", len(body.Attestations), cfg.MaxAttestations); err != nil {
+		return err
+	}
+	if err := verifyCount("deposits", len(body.Deposits), cfg.MaxDeposits); err != nil {
+		return err
+	}
+	if err := verifyCount("voluntary exits", len(body.VoluntaryExits), cfg.MaxVoluntaryExits); err != nil {
+		return err
+	}
+	for _, s := range body.AttesterSlashings {
+		if s == nil || s.Attestation_1 == nil || s.Attestation_2 == nil {
+			return errors.New("nil attester slashing")
+		}
+		if err := verifyCount("attesting indices", len(s.Attestation_1.AttestingIndices), cfg.MaxValidatorsPerCommittee); err != nil {
+			return err
+		}
+		if err := verifyCount("attesting indices", len(s.Attestation_2.AttestingIndices), cfg.MaxValidatorsPerCommittee); err != nil {
+			return err
+		}
+	}
+	for _, att := range body.Attestations {
+		if err := verifyAttestationStructure(att); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyAttestationStructure checks that an attestation has data and a well formed
+// aggregation bitlist no longer than the maximum committee size.
+func verifyAttestationStructure(att *ethpb.Attestation) error {
+	if att == nil || att.Data == nil || att.Data.Source == nil || att.Data.Target == nil {
+		return errors.New("nil attestation or attestation data")
+	}
+	bits := att.AggregationBits
+	if len(bits) == 0 || bits[len(bits)-1] == 0 {
+		return errors.New("aggregation bits have no length bit")
+	}
+	return verifyCount("aggregation bits", int(bitfield.Bitlist(bits).Len()), params.BeaconConfig().MaxValidatorsPerCommittee)
+}
+
+// verifyAggregateStructure checks the structure of a signed aggregate and its attestation.
+func verifyAggregateStructure(agg *ethpb.SignedAggregateAttestationAndProof) error {
+	if agg == nil || agg.Message == nil {
+		return errors.New("nil aggregate")
+	}
+	return verifyAttestationStructure(agg.Message.Aggregate)
+}
+
+func verifyCount(name string, count int, max uint64) error {
+	if uint64(count) > max {
+		return fmt.Errorf("%d %s exceed the maximum of %d", count, name, max)
+	}
+	return nil
+}
+
+// penalizeMalformed records a structurally invalid message. The peer which sent it is
+// penalized once the message is rejected by the topic validator.
+func (r *Service) penalizeMalformed(pid peer.ID, err error) {
+	malformedMessageCounter.Inc()
+	log.WithError(err).WithField("peer", pid.Pretty()).Debug("Rejecting malformed gossip message")
+}
+
+// downscorePeer counts a bad response of the peer and disconnects it once it is marked as bad.
+func (r *Service) downscorePeer(pid peer.ID) {
+	if pid == "" {
+		return
+	}
+	r.p2p.Peers().IncrementBadResponses(pid)
+	r.disconnectIfBad(pid)
+}
+
+// penalizeGossip counts a rejected gossip message of the peer and disconnects it once it is
+// marked as bad.
+func (r *Service) penalizeGossip(pid peer.ID) {
+	if pid == "" {
+		return
+	}
+	r.p2p.Peers().IncrementGossipPenalties(pid)
+	r.disconnectIfBad(pid)
+}
+
+// disconnectIfBad disconnects the peer if it is marked as bad.
+func (r *Service) disconnectIfBad(pid peer.ID) {
+	if r.p2p.Peers().IsBad(pid) {
+		log.WithField("peer", pid.Pretty()).Debug("Disconnecting bad peer")
+		if err := r.p2p.Disconnect(pid); err != nil {
+			log.WithError(err).Error("Failed to disconnect peer")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestVerifyAttestationStructure(t *testing.T) {
+	data := &ethpb.AttestationData{Source: &ethpb.Checkpoint{}, Target: &ethpb.Checkpoint{}}
+	tooLong := bitfield.NewBitlist(params.BeaconConfig().MaxValidatorsPerCommittee + 1)
+	tests := []struct {
+		name    string
+		att     *ethpb.Attestation
+		wantErr string
+	}{
+		{
+			name: "valid",
+			att:  &ethpb.Attestation{Data: data, AggregationBits: bitfield.NewBitlist(128)},
+		},
+		{
+			name:    "nil data",
+			att:     &ethpb.Attestation{AggregationBits: bitfield.NewBitlist(128)},
+			wantErr: "nil attestation",
+		},
+		{
+			name:    "missing length bit",
+			att:     &ethpb.Attestation{Data: data, AggregationBits: []byte{0x01, 0x00}},
+			wantErr: "no length bit",
+		},
+		{
+			name:    "too many bits",
+			att:     &ethpb.Attestation{Data: data, AggregationBits: tooLong},
+			wantErr: "exceed the maximum",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyAttestationStructure(tt.att)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Wanted error containing %q, received %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyBlockStructure_TooManyOperations(t *testing.T) {
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Body: &ethpb.BeaconBlockBody{}}}
+	if err := verifyBlockStructure(blk); err != nil {
+		t.Fatal(err)
+	}
+	blk.Block.Body.VoluntaryExits = make([]*ethpb.SignedVoluntaryExit, params.BeaconConfig().MaxVoluntaryExits+1)
+	if err := verifyBlockStructure(blk); err == nil || !strings.Contains(err.Error(), "voluntary exits") {
+		t.Errorf("Expected error for too many voluntary exits, received %v", err)
+	}
+	if err := verifyBlockStructure(&ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{}}); err == nil {
+		t.Error("Expected error for nil block body")
+	}
+}
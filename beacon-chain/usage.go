@@ -51,18 +51,27 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.P2PTCPPort,
 			cmd.DataDirFlag,
 			cmd.VerbosityFlag,
+			cmd.VModuleFlag,
 			cmd.EnableTracingFlag,
 			cmd.TracingProcessNameFlag,
 			cmd.TracingEndpointFlag,
 			cmd.TraceSampleFractionFlag,
 			flags.MonitoringPortFlag,
 			cmd.DisableMonitoringFlag,
+			cmd.ClientStatsAPIURLFlag,
+			cmd.ClientStatsIntervalFlag,
+			cmd.EnableRoughtimeFlag,
+			cmd.RoughtimeIntervalFlag,
+			cmd.RoughtimeMaxOffsetFlag,
+			cmd.SlotsPerSecondFlag,
 			cmd.MaxGoroutines,
 			cmd.ForceClearDB,
 			cmd.ClearDB,
 			cmd.ConfigFileFlag,
 			cmd.ChainConfigFileFlag,
+			cmd.NetworkFlag,
 			cmd.GrpcMaxCallRecvMsgSizeFlag,
+			cmd.GrpcMaxCallSendMsgSizeFlag,
 		},
 	},
 	{
@@ -86,10 +95,19 @@ var appHelpFlagGroups = []flagGroup{
 			flags.RPCHost,
 			flags.RPCPort,
 			flags.RPCMaxPageSize,
+			flags.RPCMaxRecvMsgSizeFlag,
+			flags.RPCMaxSendMsgSizeFlag,
 			flags.CertFlag,
 			flags.KeyFlag,
+			flags.ClientCACertFlag,
+			flags.RPCAuthFlag,
+			flags.RPCAuthTokenFileFlag,
+			flags.RPCQuotaConfigFlag,
+			flags.DisableRPCQuotasFlag,
+			flags.RPCLogRequestsFlag,
 			flags.DisableGRPCGateway,
 			flags.GRPCGatewayPort,
+			flags.GPRCGatewayCorsDomain,
 			flags.HTTPWeb3ProviderFlag,
 			flags.SetGCPercent,
 			flags.UnsafeSync,
@@ -99,7 +117,18 @@ var appHelpFlagGroups = []flagGroup{
 			flags.DisableDiscv5,
 			flags.BlockBatchLimit,
 			flags.BlockBatchLimitBurstFactor,
+			flags.BlockBatchLimitGlobalFactor,
 			flags.EnableDebugRPCEndpoints,
+			flags.EraDirFlag,
+			flags.ShutdownTimeoutFlag,
+			flags.DiskWarnThresholdFlag,
+			flags.DiskEmergencyThresholdFlag,
+			flags.PruneStatesFlag,
+			flags.RetentionEpochsFlag,
+			flags.WeakSubjectivityCheckpointFlag,
+			flags.CheckpointStateFlag,
+			flags.CheckpointBlockFlag,
+			flags.GenesisStateFlag,
 			flags.SlotsPerArchivedPoint,
 		},
 	},
@@ -110,12 +139,16 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.P2PHost,
 			cmd.P2PHostDNS,
 			cmd.P2PMaxPeers,
+			cmd.P2PMaxInboundPeers,
+			cmd.P2PMaxOutboundPeers,
 			cmd.P2PPrivKey,
 			cmd.P2PMetadata,
 			cmd.P2PWhitelist,
 			cmd.P2PBlacklist,
 			cmd.StaticPeers,
+			cmd.TrustedPeers,
 			cmd.EnableUPnPFlag,
+			cmd.DisableNATFlag,
 			cmd.P2PEncoding,
 			cmd.P2PPubsub,
 			flags.MinSyncPeers,
@@ -149,6 +182,18 @@ var appHelpFlagGroups = []flagGroup{
 			flags.ArchiveAttestationsFlag,
 		},
 	},
+	{
+		Name: "export",
+		Flags: []cli.Flag{
+			flags.ExportPostgresURLFlag,
+			flags.ExportKafkaURLFlag,
+			flags.EnableGraphQLFlag,
+			flags.GraphQLMaxComplexityFlag,
+			flags.ExplorerAPIPortFlag,
+			flags.HTTPAPIPortFlag,
+			flags.CacheMemoryBudgetFlag,
+		},
+	},
 }
 
 func init() {
@@ -894,6 +894,12 @@ def prysm_deps():
         sum = "h1:RPNrshWIDI6G2gRW9EHilWtl7Z6Sb1BR0xunSBf0SNc=",
         version = "v1.1.0",
     )
+    go_repository(
+        name = "com_github_lib_pq",
+        importpath = "github.com/lib/pq",
+        sum = "h1:h93mCPfUSkaul3Ka/VG8uZdmW1uMHDGxzu0NWHuJmHY=",
+        version = "v1.7.0",
+    )
     go_repository(
         name = "com_github_libp2p_go_conn_security",
         importpath = "github.com/libp2p/go-conn-security",
@@ -1432,6 +1438,12 @@ def prysm_deps():
         sum = "h1:nOGnQDM7FYENwehXlg/kFVnos3rEvtKTjRvOWSzb6H4=",
         version = "v1.5.1",
     )
+    go_repository(
+        name = "com_github_supranational_blst",
+        importpath = "github.com/supranational/blst",
+        sum = "h1:xNMoHRJOTwMn63ip6qoWJ2Ymgvj7E2b9jY2FAwY+qRo=",
+        version = "v0.3.14",
+    )
     go_repository(
         name = "com_github_tarm_serial",
         importpath = "github.com/tarm/serial",
@@ -2866,8 +2878,8 @@ def prysm_deps():
     go_repository(
         name = "org_golang_x_sys",
         importpath = "golang.org/x/sys",
-        sum = "h1:rITEj+UZHYC927n8GT97eC3zrpzXdb/voyeOuVKS46o=",
-        version = "v0.0.0-20200523222454-059865788121",
+        sum = "h1:+Nyd8tzPX9R7BWHguqsrbFdRx3WQ/1ib8I44HXV5yTA=",
+        version = "v0.0.0-20200930185726-fdedc70b468f",
     )
     go_repository(
         name = "org_golang_x_text",
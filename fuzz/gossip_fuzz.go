@@ -0,0 +1,168 @@
+// +build libfuzzer
+
+package fuzz
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
+	"github.com/prysmaticlabs/prysm/shared/p2putils"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/sirupsen/logrus"
+)
+
+const gossipFuzzValidators = 64
+
+// fuzzPeer is the sender of all fuzzed gossip messages. It must differ from the local
+// peer ID, as messages from ourselves are accepted without validation.
+const fuzzPeer = peer.ID("fuzzer")
+
+var gossipSync *sync.Service
+var gossipGenesis *stateTrie.BeaconState
+var gossipDigest [4]byte
+var gossipP2P *p2p.Service
+
+func init() {
+	logrus.SetLevel(logrus.PanicLevel)
+	params.UseMainnetConfig()
+
+	var err error
+	gossipP2P, err = p2p.NewService(&p2p.Config{
+		NoDiscovery: true,
+		Encoding:    "ssz",
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "could not create new p2p service"))
+	}
+
+	deposits, _, err := testutil.DeterministicDepositsAndKeys(gossipFuzzValidators)
+	if err != nil {
+		panic(errors.Wrap(err, "could not create deposits"))
+	}
+	eth1Data, err := testutil.DeterministicEth1Data(len(deposits))
+	if err != nil {
+		panic(errors.Wrap(err, "could not create eth1 data"))
+	}
+	// Genesis is placed far enough in the past that fuzzed slots can pass the clock checks.
+	genesisTime := time.Now().Add(-time.Hour)
+	gossipGenesis, err = state.GenesisBeaconState(deposits, uint64(genesisTime.Unix()), eth1Data)
+	if err != nil {
+		panic(errors.Wrap(err, "could not create genesis state"))
+	}
+
+	dir, err := ioutil.TempDir("", "gossipfuzz")
+	if err != nil {
+		panic(err)
+	}
+	stateSummaryCache := cache.NewStateSummaryCache()
+	beaconDB, err := db.NewDB(dir, stateSummaryCache)
+	if err != nil {
+		panic(errors.Wrap(err, "could not create database"))
+	}
+	ctx := context.Background()
+	genesisBlock := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{StateRoot: make([]byte, 32), ParentRoot: make([]byte, 32)}}
+	genesisRoot, err := stateutil.BlockRoot(genesisBlock.Block)
+	if err != nil {
+		panic(err)
+	}
+	if err := beaconDB.SaveBlock(ctx, genesisBlock); err != nil {
+		panic(errors.Wrap(err, "could not save genesis block"))
+	}
+	if err := beaconDB.SaveState(ctx, gossipGenesis.Copy(), genesisRoot); err != nil {
+		panic(errors.Wrap(err, "could not save genesis state"))
+	}
+
+	var validatorsRoot [32]byte
+	copy(validatorsRoot[:], gossipGenesis.GenesisValidatorRoot())
+	chain := &mock.ChainService{
+		State:               gossipGenesis.Copy(),
+		Root:                genesisRoot[:],
+		Block:               genesisBlock,
+		FinalizedCheckPoint: &ethpb.Checkpoint{Root: genesisRoot[:]},
+		Genesis:             genesisTime,
+		ValidatorsRoot:      validatorsRoot,
+		Fork:                gossipGenesis.Fork(),
+		DB:                  beaconDB,
+		ValidAttestation:    true,
+	}
+	gossipDigest, err = p2putils.CreateForkDigest(genesisTime, validatorsRoot[:])
+	if err != nil {
+		panic(errors.Wrap(err, "could not create fork digest"))
+	}
+
+	gossipSync = sync.NewRegularSyncFuzz(&sync.Config{
+		P2P:                 gossipP2P,
+		DB:                  beaconDB,
+		AttPool:             attestations.NewPool(),
+		Chain:               chain,
+		StateNotifier:       chain.StateNotifier(),
+		AttestationNotifier: chain.OperationNotifier(),
+		InitialSync:         &mockSync.Sync{IsSyncing: false},
+		StateSummaryCache:   stateSummaryCache,
+	})
+}
+
+// gossipMessage wraps the fuzzed bytes in a pubsub message for the topic of the given type.
+func gossipMessage(b []byte, m proto.Message, args ...interface{}) *pubsub.Message {
+	format := p2p.GossipTypeMapping[reflect.TypeOf(m)]
+	topic := fmt.Sprintf(format, append([]interface{}{gossipDigest}, args...)...) + gossipP2P.Encoding().ProtocolSuffix()
+	return &pubsub.Message{
+		Message: &pubsub_pb.Message{
+			Data:     b,
+			TopicIDs: []string{topic},
+		},
+	}
+}
+
+// BeaconFuzzGossipBlock feeds the input as a gossiped beacon block through block
+// validation and, if it is accepted, through the state transition on the genesis state.
+func BeaconFuzzGossipBlock(b []byte) ([]byte, bool) {
+	ctx := context.Background()
+	msg := gossipMessage(b, &ethpb.SignedBeaconBlock{})
+	if res := gossipSync.FuzzValidateBeaconBlockPubSub(ctx, fuzzPeer, msg); res != pubsub.ValidationAccept {
+		return nil, false
+	}
+	blk, ok := msg.ValidatorData.(*ethpb.SignedBeaconBlock)
+	if !ok {
+		panic("accepted block was not set as validator data")
+	}
+	post, err := state.ExecuteStateTransitionNoVerifyAttSigs(ctx, gossipGenesis.Copy(), blk)
+	if err != nil {
+		return fail(err)
+	}
+	return success(post)
+}
+
+// BeaconFuzzGossipAttestation feeds the input as an unaggregated attestation gossiped
+// on the subnet of committee index 0 through attestation validation.
+func BeaconFuzzGossipAttestation(b []byte) {
+	msg := gossipMessage(b, &ethpb.Attestation{}, 0)
+	_ = gossipSync.FuzzValidateCommitteeIndexBeaconAttestation(context.Background(), fuzzPeer, msg)
+}
+
+// BeaconFuzzGossipAggregate feeds the input as a gossiped signed aggregate and proof
+// through aggregate validation.
+func BeaconFuzzGossipAggregate(b []byte) {
+	msg := gossipMessage(b, &ethpb.SignedAggregateAttestationAndProof{})
+	_ = gossipSync.FuzzValidateAggregateAndProof(context.Background(), fuzzPeer, msg)
+}
@@ -0,0 +1,181 @@
+package fuzz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// preStatePathENV is the path of an SSZ encoded beacon state, e.g. written by the beacon-chain
+// db dump command, used as the pre state of the state transition fuzz target instead of the
+// interop genesis state.
+const preStatePathENV = "PRE_STATE_PATH"
+
+// interopValidatorCount is the number of validators of the default interop genesis pre state, which
+// other clients can generate from the same interop keys for differential fuzzing.
+const interopValidatorCount = 64
+
+// maxSlotDistance bounds the number of slots processed before the block, so inputs with far
+// future slots do not stall the fuzzer in empty slot processing. Blocks further ahead of the pre
+// state are skipped.
+const maxSlotDistance = 64
+
+var (
+	preStateOnce sync.Once
+	preState     *stateTrie.BeaconState
+	preStateRoot [32]byte
+	preStateErr  error
+)
+
+// BeaconFuzzStateTransition implements libfuzzer and beacon fuzz interface. It runs the full state
+// transition, slot and epoch processing included, of an SSZ encoded signed block against a fixed
+// pre state. Signatures and the block state root are not verified, like the spec state_transition
+// with validate_state_root=False, so the fuzzer can reach the block operations. The SSZ encoded
+// post state is returned for the comparison with the post states of other clients.
+//
+// Invariants the post state of a successful transition must hold are checked, and their
+// violations panic so they are reported like crashes.
+func BeaconFuzzStateTransition(b []byte) ([]byte, bool) {
+	params.UseMainnetConfig()
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := blk.UnmarshalSSZ(b); err != nil {
+		return fail(err)
+	}
+	if blk.Block == nil || blk.Block.Body == nil {
+		return nil, false
+	}
+	pre, err := stateTransitionPreState()
+	if err != nil {
+		panic(err)
+	}
+	if blk.Block.Slot > pre.Slot()+maxSlotDistance {
+		return nil, false
+	}
+
+	ctx := context.Background()
+	post, err := state.ProcessSlots(ctx, pre.Copy(), blk.Block.Slot)
+	if err != nil {
+		return fail(err)
+	}
+	_, post, err = state.ProcessBlockNoVerifyAnySig(ctx, post, blk)
+	if err != nil {
+		return fail(err)
+	}
+	if err := checkStateTransitionInvariants(ctx, pre, blk.Block, post); err != nil {
+		panic(err)
+	}
+	return success(post)
+}
+
+// FuzzStateTransition implements the go-fuzz interface of BeaconFuzzStateTransition, giving
+// priority to the inputs which make it through the state transition.
+func FuzzStateTransition(b []byte) int {
+	if _, ok := BeaconFuzzStateTransition(b); ok {
+		return 1
+	}
+	return 0
+}
+
+// stateTransitionPreState loads the pre state of the state transition fuzz target once. The
+// fuzz target copies it for each input.
+func stateTransitionPreState() (*stateTrie.BeaconState, error) {
+	preStateOnce.Do(func() {
+		preState, preStateErr = loadStateTransitionPreState()
+		if preStateErr != nil {
+			return
+		}
+		preStateRoot, preStateErr = preState.HashTreeRoot(context.Background())
+	})
+	return preState, preStateErr
+}
+
+func loadStateTransitionPreState() (*stateTrie.BeaconState, error) {
+	if path, ok := os.LookupEnv(preStatePathENV); ok {
+		enc, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read pre state")
+		}
+		s := &pb.BeaconState{}
+		if err := s.UnmarshalSSZ(enc); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal pre state")
+		}
+		return stateTrie.InitializeFromProto(s)
+	}
+	deposits, _, err := testutil.DeterministicDepositsAndKeys(interopValidatorCount)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate interop deposits")
+	}
+	eth1Data, err := testutil.DeterministicEth1Data(len(deposits))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate interop eth1 data")
+	}
+	return state.GenesisBeaconState(deposits, 0, eth1Data)
+}
+
+// checkStateTransitionInvariants returns an error if the post state of the transition of the pre
+// state by the block is inconsistent, or if the transition modified the pre state.
+func checkStateTransitionInvariants(
+	ctx context.Context,
+	pre *stateTrie.BeaconState,
+	blk *ethpb.BeaconBlock,
+	post *stateTrie.BeaconState,
+) error {
+	if post.Slot() != blk.Slot {
+		return fmt.Errorf("post state slot %d is not the block slot %d", post.Slot(), blk.Slot)
+	}
+	header := post.LatestBlockHeader()
+	if header.Slot != blk.Slot || !bytes.Equal(header.ParentRoot, blk.ParentRoot) {
+		return fmt.Errorf("latest block header %v is not the header of the block", header)
+	}
+	if len(post.Validators()) != len(post.Balances()) {
+		return fmt.Errorf("post state has %d validators and %d balances", len(post.Validators()), len(post.Balances()))
+	}
+	if post.NumValidators() < pre.NumValidators() {
+		return fmt.Errorf("validator registry shrank from %d to %d", pre.NumValidators(), post.NumValidators())
+	}
+	// The cached field roots of the state trie must match the root of the state computed from
+	// scratch, and the SSZ encoding of the state must survive a round trip.
+	root, err := post.HashTreeRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not hash post state")
+	}
+	uncached, err := stateutil.HashTreeRootState(post.CloneInnerState())
+	if err != nil {
+		return errors.Wrap(err, "could not hash post state from scratch")
+	}
+	if root != uncached {
+		return fmt.Errorf("post state root %#x does not match its root computed from scratch %#x", root, uncached)
+	}
+	enc, err := post.InnerStateUnsafe().MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not encode post state")
+	}
+	decoded := &pb.BeaconState{}
+	if err := decoded.UnmarshalSSZ(enc); err != nil {
+		return errors.Wrap(err, "could not decode post state")
+	}
+	reenc, err := decoded.MarshalSSZ()
+	if err != nil {
+		return errors.Wrap(err, "could not encode decoded post state")
+	}
+	if !bytes.Equal(enc, reenc) {
+		return errors.New("post state SSZ encoding changed by a round trip")
+	}
+	// The transition works on a copy, the fixed pre state must never change.
+	if r, err := pre.HashTreeRoot(ctx); err != nil || r != preStateRoot {
+		return fmt.Errorf("pre state root changed from %#x to %#x: %v", preStateRoot, r, err)
+	}
+	return nil
+}
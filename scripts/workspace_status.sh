@@ -1,5 +1,8 @@
 #!/bin/bash
 
 echo STABLE_GIT_COMMIT $(git rev-parse HEAD)
+echo STABLE_GIT_TAG $(git describe --tags --abbrev=0 2>/dev/null || echo Unknown)
+# The build date is the commit time so that builds of the same commit are identical.
+echo STABLE_GIT_COMMIT_TIME $(git show -s --format=%cI HEAD)
 echo DATE $(date --rfc-3339=seconds --utc)
 echo DOCKER_TAG $(git rev-parse --abbrev-ref HEAD)-$(git rev-parse --short=6 HEAD)
@@ -1,16 +1,20 @@
 // Package bls implements a go-wrapper around a library implementing the
 // the BLS12-381 curve and signature scheme. This package exposes a public API for
 // verifying and aggregating BLS signatures used by Ethereum 2.0.
+//
+// Keys and signatures are handled by herumi's library, or by blst when the client is built with
+// the blst_enabled tag or run with --bls-implementation=blst.
 package bls
 
 import (
 	"fmt"
 
-	"github.com/dgraph-io/ristretto"
 	bls12 "github.com/herumi/bls-eth-go-binary/bls"
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/cachemanager"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	blst "github.com/supranational/blst/bindings/go"
 )
 
 func init() {
@@ -25,11 +29,14 @@ func init() {
 // DomainByteLength length of domain byte array.
 const DomainByteLength = 4
 
-var maxKeys = int64(100000)
-var pubkeyCache, _ = ristretto.NewCache(&ristretto.Config{
-	NumCounters: maxKeys,
-	MaxCost:     1 << 19, // 500 kb is cache max size
-	BufferItems: 64,
+var maxKeys = 100000
+
+// pubkeyCost is the size in bytes of a cached public key and its serialized key.
+const pubkeyCost = 192
+
+var pubkeyCache = cachemanager.NewCache(cachemanager.Config{
+	Name:       "pubkey",
+	MaxEntries: maxKeys,
 })
 
 // CurveOrder for the BLS12-381 curve.
@@ -38,23 +45,29 @@ const CurveOrder = "524358751751261904794477405081859658376905525005276378226036
 // Signature used in the BLS signature scheme.
 type Signature struct {
 	s *bls12.Sign
+	b *blst.P2Affine
 }
 
 // PublicKey used in the BLS signature scheme.
 type PublicKey struct {
 	p *bls12.PublicKey
+	b *blst.P1Affine
 }
 
 // SecretKey used in the BLS signature scheme.
 type SecretKey struct {
 	p *bls12.SecretKey
+	b *blst.SecretKey
 }
 
 // RandKey creates a new private key using a random method provided as an io.Reader.
 func RandKey() *SecretKey {
+	if useBlst() {
+		return blstRandKey()
+	}
 	secKey := &bls12.SecretKey{}
 	secKey.SetByCSPRNG()
-	return &SecretKey{secKey}
+	return &SecretKey{p: secKey}
 }
 
 // SecretKeyFromBytes creates a BLS private key from a BigEndian byte slice.
@@ -62,6 +75,9 @@ func SecretKeyFromBytes(privKey []byte) (*SecretKey, error) {
 	if len(privKey) != params.BeaconConfig().BLSSecretKeyLength {
 		return nil, fmt.Errorf("secret key must be %d bytes", params.BeaconConfig().BLSSecretKeyLength)
 	}
+	if useBlst() {
+		return blstSecretKeyFromBytes(privKey)
+	}
 	secKey := &bls12.SecretKey{}
 	err := secKey.Deserialize(privKey)
 	if err != nil {
@@ -78,20 +94,34 @@ func PublicKeyFromBytes(pubKey []byte) (*PublicKey, error) {
 	if len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
 		return nil, fmt.Errorf("public key must be %d bytes", params.BeaconConfig().BLSPubkeyLength)
 	}
-	if cv, ok := pubkeyCache.Get(string(pubKey)); ok {
+	// Keys are cached per library, as they cannot be used with the other one.
+	cacheKey := string(pubKey)
+	if useBlst() {
+		cacheKey = featureconfig.BlstBLS + cacheKey
+	}
+	if cv, ok := pubkeyCache.Get(cacheKey); ok {
 		return cv.(*PublicKey).Copy()
 	}
-	p := &bls12.PublicKey{}
-	err := p.Deserialize(pubKey)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not unmarshal bytes into public key")
+	var pubKeyObj *PublicKey
+	if useBlst() {
+		var err error
+		pubKeyObj, err = blstPublicKeyFromBytes(pubKey)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p := &bls12.PublicKey{}
+		err := p.Deserialize(pubKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal bytes into public key")
+		}
+		pubKeyObj = &PublicKey{p: p}
 	}
-	pubKeyObj := &PublicKey{p: p}
 	copiedKey, err := pubKeyObj.Copy()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not copy public key")
 	}
-	pubkeyCache.Set(string(pubKey), copiedKey, 48)
+	pubkeyCache.Set(cacheKey, copiedKey, pubkeyCost)
 	return pubKeyObj, nil
 }
 
@@ -103,6 +133,9 @@ func SignatureFromBytes(sig []byte) (*Signature, error) {
 	if len(sig) != params.BeaconConfig().BLSSignatureLength {
 		return nil, fmt.Errorf("signature must be %d bytes", params.BeaconConfig().BLSSignatureLength)
 	}
+	if useBlst() {
+		return blstSignatureFromBytes(sig)
+	}
 	signature := &bls12.Sign{}
 	err := signature.Deserialize(sig)
 	if err != nil {
@@ -113,6 +146,9 @@ func SignatureFromBytes(sig []byte) (*Signature, error) {
 
 // PublicKey obtains the public key corresponding to the BLS secret key.
 func (s *SecretKey) PublicKey() *PublicKey {
+	if s.b != nil {
+		return &PublicKey{b: new(blst.P1Affine).From(s.b)}
+	}
 	return &PublicKey{p: s.p.GetPublicKey()}
 }
 
@@ -128,12 +164,18 @@ func (s *SecretKey) Sign(msg []byte) *Signature {
 	if featureconfig.Get().SkipBLSVerify {
 		return &Signature{}
 	}
+	if s.b != nil {
+		return &Signature{b: blstSign(s.b, msg)}
+	}
 	signature := s.p.SignByte(msg)
 	return &Signature{s: signature}
 }
 
 // Marshal a secret key into a LittleEndian byte slice.
 func (s *SecretKey) Marshal() []byte {
+	if s.b != nil {
+		return s.b.Serialize()
+	}
 	keyBytes := s.p.Serialize()
 	if len(keyBytes) < params.BeaconConfig().BLSSecretKeyLength {
 		emptyBytes := make([]byte, params.BeaconConfig().BLSSecretKeyLength-len(keyBytes))
@@ -144,11 +186,18 @@ func (s *SecretKey) Marshal() []byte {
 
 // Marshal a public key into a LittleEndian byte slice.
 func (p *PublicKey) Marshal() []byte {
+	if p.b != nil {
+		return p.b.Compress()
+	}
 	return p.p.Serialize()
 }
 
 // Copy the public key to a new pointer reference.
 func (p *PublicKey) Copy() (*PublicKey, error) {
+	if p.b != nil {
+		np := *p.b
+		return &PublicKey{b: &np}, nil
+	}
 	np := *p.p
 	return &PublicKey{p: &np}, nil
 }
@@ -158,6 +207,13 @@ func (p *PublicKey) Aggregate(p2 *PublicKey) *PublicKey {
 	if featureconfig.Get().SkipBLSVerify {
 		return p
 	}
+	if p.b != nil {
+		agg := new(blst.P1Aggregate)
+		agg.Add(p.b, false)
+		agg.Add(p2.b, false)
+		p.b = agg.ToAffine()
+		return p
+	}
 	p.p.Add(p2.p)
 	return p
 }
@@ -175,6 +231,9 @@ func (s *Signature) Verify(pubKey *PublicKey, msg []byte) bool {
 	if featureconfig.Get().SkipBLSVerify {
 		return true
 	}
+	if s.b != nil {
+		return pairingCheck(s.b, []*blst.P1Affine{pubKey.b}, []*blst.P2Affine{hashToG2(msg)})
+	}
 	return s.s.VerifyByte(pubKey.p, msg)
 }
 
@@ -202,6 +261,9 @@ func (s *Signature) AggregateVerify(pubKeys []*PublicKey, msgs [][32]byte) bool
 	if size != len(msgs) {
 		return false
 	}
+	if s.b != nil {
+		return blstAggregateVerify(s.b, pubKeys, msgs)
+	}
 	msgSlices := []byte{}
 	var rawKeys []bls12.PublicKey
 	for i := 0; i < size; i++ {
@@ -228,6 +290,9 @@ func (s *Signature) FastAggregateVerify(pubKeys []*PublicKey, msg [32]byte) bool
 	if len(pubKeys) == 0 {
 		return false
 	}
+	if s.b != nil {
+		return blstFastAggregateVerify(s.b, pubKeys, msg)
+	}
 	rawKeys := make([]bls12.PublicKey, len(pubKeys))
 	for i := 0; i < len(pubKeys); i++ {
 		rawKeys[i] = *pubKeys[i].p
@@ -238,6 +303,9 @@ func (s *Signature) FastAggregateVerify(pubKeys []*PublicKey, msg [32]byte) bool
 
 // NewAggregateSignature creates a blank aggregate signature.
 func NewAggregateSignature() *Signature {
+	if useBlst() {
+		return &Signature{b: hashToG2([]byte{'m', 'o', 'c', 'k'})}
+	}
 	return &Signature{s: bls12.HashAndMapToSignature([]byte{'m', 'o', 'c', 'k'})}
 }
 
@@ -249,6 +317,9 @@ func AggregateSignatures(sigs []*Signature) *Signature {
 	if featureconfig.Get().SkipBLSVerify {
 		return sigs[0]
 	}
+	if sigs[0].b != nil {
+		return blstAggregateSignatures(sigs)
+	}
 
 	// Copy signature
 	signature := *sigs[0].s
@@ -276,6 +347,9 @@ func (s *Signature) Marshal() []byte {
 	if featureconfig.Get().SkipBLSVerify {
 		return make([]byte, params.BeaconConfig().BLSSignatureLength)
 	}
+	if s.b != nil {
+		return s.b.Compress()
+	}
 
 	return s.s.Serialize()
 }
@@ -3,6 +3,7 @@ package bls_test
 import (
 	"bytes"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/bls"
@@ -111,7 +112,7 @@ func TestSecretKeyFromBytes(t *testing.T) {
 		{
 			name:  "Bad",
 			input: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
-			err:   errors.New("could not unmarshal bytes into secret key: err blsSecretKeyDeserialize ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+			err:   errors.New("could not unmarshal bytes into secret key"),
 		},
 		{
 			name:  "Good",
@@ -125,7 +126,7 @@ func TestSecretKeyFromBytes(t *testing.T) {
 			if test.err != nil {
 				if err == nil {
 					t.Errorf("No error returned: expected %v", test.err)
-				} else if test.err.Error() != err.Error() {
+				} else if !strings.HasPrefix(err.Error(), test.err.Error()) {
 					t.Errorf("Unexpected error returned: expected %v, received %v", test.err, err)
 				}
 			} else {
@@ -170,7 +171,7 @@ func TestPublicKeyFromBytes(t *testing.T) {
 		{
 			name:  "Bad",
 			input: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
-			err:   errors.New("could not unmarshal bytes into public key: err blsPublicKeyDeserialize 000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"),
+			err:   errors.New("could not unmarshal bytes into public key"),
 		},
 		{
 			name:  "Good",
@@ -184,7 +185,7 @@ func TestPublicKeyFromBytes(t *testing.T) {
 			if test.err != nil {
 				if err == nil {
 					t.Errorf("No error returned: expected %v", test.err)
-				} else if test.err.Error() != err.Error() {
+				} else if !strings.HasPrefix(err.Error(), test.err.Error()) {
 					t.Errorf("Unexpected error returned: expected %v, received %v", test.err, err)
 				}
 			} else {
@@ -229,7 +230,7 @@ func TestSignatureFromBytes(t *testing.T) {
 		{
 			name:  "Bad",
 			input: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
-			err:   errors.New("could not unmarshal bytes into signature: err blsSignatureDeserialize 000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"),
+			err:   errors.New("could not unmarshal bytes into signature"),
 		},
 		{
 			name:  "Good",
@@ -243,7 +244,7 @@ func TestSignatureFromBytes(t *testing.T) {
 			if test.err != nil {
 				if err == nil {
 					t.Errorf("No error returned: expected %v", test.err)
-				} else if test.err.Error() != err.Error() {
+				} else if !strings.HasPrefix(err.Error(), test.err.Error()) {
 					t.Errorf("Unexpected error returned: expected %v, received %v", test.err, err)
 				}
 			} else {
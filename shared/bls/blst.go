@@ -0,0 +1,153 @@
+package bls
+
+import (
+	"crypto/rand"
+
+	bls12 "github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	blst "github.com/supranational/blst/bindings/go"
+)
+
+var g1Generator = blst.P1Generator().ToAffine()
+
+// useBlst reports whether new keys and signatures are created with blst instead of herumi.
+// Keys and signatures keep the library they were created with.
+func useBlst() bool {
+	switch featureconfig.Get().BLSImplementation {
+	case featureconfig.BlstBLS:
+		return true
+	case featureconfig.HerumiBLS:
+		return false
+	}
+	return defaultImplementation == featureconfig.BlstBLS
+}
+
+// hashToG2 maps a message to a point of G2. blst only implements the hash to curve of later
+// drafts of the IETF specification, so the message is hashed with herumi in the draft 05 Eth
+// mode that the spec requires and the point is handed over to blst.
+func hashToG2(msg []byte) *blst.P2Affine {
+	return new(blst.P2Affine).Deserialize(bls12.HashAndMapToSignature(msg).SerializeUncompressed())
+}
+
+// pairingCheck reports whether e(g1, sig) equals the product of e(pubKeys[i], points[i]).
+func pairingCheck(sig *blst.P2Affine, pubKeys []*blst.P1Affine, points []*blst.P2Affine) bool {
+	ctx := blst.PairingCtx(false, nil)
+	for i := range pubKeys {
+		blst.PairingRawAggregate(ctx, points[i], pubKeys[i])
+	}
+	blst.PairingCommit(ctx)
+	return blst.PairingFinalVerify(ctx, blst.Fp12MillerLoop(sig, g1Generator))
+}
+
+func blstRandKey() *SecretKey {
+	ikm := make([]byte, 32)
+	if _, err := rand.Read(ikm); err != nil {
+		panic(err)
+	}
+	return &SecretKey{b: blst.KeyGen(ikm)}
+}
+
+func blstSecretKeyFromBytes(privKey []byte) (*SecretKey, error) {
+	secKey := new(blst.SecretKey).Deserialize(privKey)
+	if secKey == nil || !secKey.Valid() {
+		return nil, errors.New("could not unmarshal bytes into secret key")
+	}
+	return &SecretKey{b: secKey}, nil
+}
+
+func blstPublicKeyFromBytes(pubKey []byte) (*PublicKey, error) {
+	p := new(blst.P1Affine).Uncompress(pubKey)
+	if p == nil {
+		return nil, errors.New("could not unmarshal bytes into public key")
+	}
+	if !p.InG1() {
+		return nil, errors.New("public key is not in the G1 subgroup")
+	}
+	return &PublicKey{b: p}, nil
+}
+
+func blstSignatureFromBytes(sig []byte) (*Signature, error) {
+	s := new(blst.P2Affine).Uncompress(sig)
+	if s == nil {
+		return nil, errors.New("could not unmarshal bytes into signature")
+	}
+	if !s.SigValidate(false) {
+		return nil, errors.New("signature is not in the G2 subgroup")
+	}
+	return &Signature{b: s}, nil
+}
+
+func blstSign(secKey *blst.SecretKey, msg []byte) *blst.P2Affine {
+	var h blst.P2
+	h.FromAffine(hashToG2(msg))
+	return h.Mult(secKey).ToAffine()
+}
+
+func blstAggregateVerify(sig *blst.P2Affine, pubKeys []*PublicKey, msgs [][32]byte) bool {
+	seen := make(map[[32]byte]bool, len(msgs))
+	rawKeys := make([]*blst.P1Affine, len(pubKeys))
+	points := make([]*blst.P2Affine, len(msgs))
+	for i := range msgs {
+		// Aggregate verification requires distinct messages, as with herumi.
+		if seen[msgs[i]] {
+			return false
+		}
+		seen[msgs[i]] = true
+		rawKeys[i] = pubKeys[i].b
+		points[i] = hashToG2(msgs[i][:])
+	}
+	return pairingCheck(sig, rawKeys, points)
+}
+
+func blstFastAggregateVerify(sig *blst.P2Affine, pubKeys []*PublicKey, msg [32]byte) bool {
+	agg := new(blst.P1Aggregate)
+	for _, p := range pubKeys {
+		agg.Add(p.b, false)
+	}
+	return pairingCheck(sig, []*blst.P1Affine{agg.ToAffine()}, []*blst.P2Affine{hashToG2(msg[:])})
+}
+
+func blstAggregateSignatures(sigs []*Signature) *Signature {
+	agg := new(blst.P2Aggregate)
+	for _, s := range sigs {
+		agg.Add(s.b, false)
+	}
+	return &Signature{b: agg.ToAffine()}
+}
+
+// blstVerifyMultipleSignatures is VerifyMultipleSignatures for signatures and public keys
+// created with blst.
+func blstVerifyMultipleSignatures(sigs []*Signature, msgs [][32]byte, pubKeys []*PublicKey) (bool, error) {
+	aggSig := new(blst.P2Aggregate)
+	// The scaled public keys of signatures of the same message are added up, so that the
+	// message is only hashed and paired once.
+	msgIndices := make(map[[32]byte]int, len(msgs))
+	keys := make([]*blst.P1Aggregate, 0, len(pubKeys))
+	points := make([]*blst.P2Affine, 0, len(msgs))
+	r := make([]byte, 8)
+	for i := range sigs {
+		if _, err := rand.Read(r); err != nil {
+			return false, errors.Wrap(err, "could not generate random scalar")
+		}
+		var sig blst.P2
+		sig.FromAffine(sigs[i].b)
+		aggSig.Add(sig.Mult(r).ToAffine(), false)
+
+		var key blst.P1
+		key.FromAffine(pubKeys[i].b)
+		j, ok := msgIndices[msgs[i]]
+		if !ok {
+			j = len(keys)
+			msgIndices[msgs[i]] = j
+			keys = append(keys, new(blst.P1Aggregate))
+			points = append(points, hashToG2(msgs[i][:]))
+		}
+		keys[j].Add(key.Mult(r).ToAffine(), false)
+	}
+	rawKeys := make([]*blst.P1Affine, len(keys))
+	for i, k := range keys {
+		rawKeys[i] = k.ToAffine()
+	}
+	return pairingCheck(aggSig.ToAffine(), rawKeys, points), nil
+}
@@ -0,0 +1,66 @@
+package bls_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+func useImplementation(impl string) func() {
+	return featureconfig.InitWithReset(&featureconfig.Flags{BLSImplementation: impl})
+}
+
+func TestBlst(t *testing.T) {
+	reset := useImplementation(featureconfig.BlstBLS)
+	defer reset()
+	t.Run("MarshalUnmarshal", TestMarshalUnmarshal)
+	t.Run("SignVerify", TestSignVerify)
+	t.Run("AggregateVerify", TestAggregateVerify)
+	t.Run("FastAggregateVerify", TestFastAggregateVerify)
+	t.Run("VerifyMultipleSignatures", TestVerifyMultipleSignatures)
+	t.Run("VerifyMultipleSignatures_SwappedSignatures", TestVerifyMultipleSignatures_SwappedSignatures)
+}
+
+func TestBlst_CompatibleWithHerumi(t *testing.T) {
+	msg := [32]byte{'h', 'e', 'l', 'l', 'o'}
+	secKey := bls.RandKey().Marshal()
+
+	sign := func(impl string) (pub []byte, sig []byte) {
+		reset := useImplementation(impl)
+		defer reset()
+		priv, err := bls.SecretKeyFromBytes(secKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return priv.PublicKey().Marshal(), priv.Sign(msg[:]).Marshal()
+	}
+	herumiPub, herumiSig := sign(featureconfig.HerumiBLS)
+	blstPub, blstSig := sign(featureconfig.BlstBLS)
+	if !bytes.Equal(herumiPub, blstPub) {
+		t.Errorf("Public keys not equal, herumi %#x != blst %#x", herumiPub, blstPub)
+	}
+	if !bytes.Equal(herumiSig, blstSig) {
+		t.Errorf("Signatures not equal, herumi %#x != blst %#x", herumiSig, blstSig)
+	}
+
+	// Signatures of either library verify with the other one.
+	for _, impl := range []string{featureconfig.HerumiBLS, featureconfig.BlstBLS} {
+		reset := useImplementation(impl)
+		pub, err := bls.PublicKeyFromBytes(blstPub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, b := range [][]byte{herumiSig, blstSig} {
+			sig, err := bls.SignatureFromBytes(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sig.Verify(pub, msg[:]) {
+				t.Errorf("Signature did not verify with %s", impl)
+			}
+		}
+		reset()
+	}
+}
@@ -0,0 +1,8 @@
+// +build blst_enabled
+
+package bls
+
+import "github.com/prysmaticlabs/prysm/shared/featureconfig"
+
+// defaultImplementation is the BLS library used unless --bls-implementation is set.
+const defaultImplementation = featureconfig.BlstBLS
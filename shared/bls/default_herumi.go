@@ -0,0 +1,9 @@
+// +build !blst_enabled
+
+package bls
+
+import "github.com/prysmaticlabs/prysm/shared/featureconfig"
+
+// defaultImplementation is the BLS library used unless --bls-implementation is set. Builds with
+// the blst_enabled tag default to blst.
+const defaultImplementation = featureconfig.HerumiBLS
@@ -0,0 +1,105 @@
+package bls
+
+import (
+	"fmt"
+
+	bls12 "github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+)
+
+// SignatureSet is a collection of signatures with the public keys and messages they are
+// verified against, so they can be verified together. Descriptions name what each signature
+// signs, to report which one is invalid.
+type SignatureSet struct {
+	Signatures   []*Signature
+	PublicKeys   []*PublicKey
+	Messages     [][32]byte
+	Descriptions []string
+}
+
+// NewSet returns an empty signature set.
+func NewSet() *SignatureSet {
+	return &SignatureSet{}
+}
+
+// Add a signature of a message with the public key it is verified against to the set.
+func (s *SignatureSet) Add(sig *Signature, pubKey *PublicKey, msg [32]byte, description string) {
+	s.Signatures = append(s.Signatures, sig)
+	s.PublicKeys = append(s.PublicKeys, pubKey)
+	s.Messages = append(s.Messages, msg)
+	s.Descriptions = append(s.Descriptions, description)
+}
+
+// Join appends the signatures of another set to the set.
+func (s *SignatureSet) Join(set *SignatureSet) *SignatureSet {
+	s.Signatures = append(s.Signatures, set.Signatures...)
+	s.PublicKeys = append(s.PublicKeys, set.PublicKeys...)
+	s.Messages = append(s.Messages, set.Messages...)
+	s.Descriptions = append(s.Descriptions, set.Descriptions...)
+	return s
+}
+
+// Verify all the signatures of the set as a batch.
+func (s *SignatureSet) Verify() (bool, error) {
+	return VerifyMultipleSignatures(s.Signatures, s.Messages, s.PublicKeys)
+}
+
+// VerifyEach verifies the signatures of the set one by one and returns the description of
+// the first invalid signature. It is used to attribute the failure of a batch verification.
+func (s *SignatureSet) VerifyEach() (string, bool) {
+	for i, sig := range s.Signatures {
+		if !sig.Verify(s.PublicKeys[i], s.Messages[i][:]) {
+			return s.Descriptions[i], false
+		}
+	}
+	return "", true
+}
+
+// VerifyMultipleSignatures verifies a batch of signatures, each against its own public key and
+// message, at about the cost of a single aggregate verification.
+//
+// Each signature and public key is multiplied by a random scalar before aggregation, so that
+// invalid signatures cannot cancel each other out: the batch only verifies if every signature
+// does, except with negligible probability.
+func VerifyMultipleSignatures(sigs []*Signature, msgs [][32]byte, pubKeys []*PublicKey) (bool, error) {
+	if featureconfig.Get().SkipBLSVerify {
+		return true, nil
+	}
+	if len(sigs) != len(msgs) || len(sigs) != len(pubKeys) {
+		return false, fmt.Errorf("provided %d signatures, %d messages and %d public keys", len(sigs), len(msgs), len(pubKeys))
+	}
+	if len(sigs) == 0 {
+		return true, nil
+	}
+	if sigs[0].b != nil {
+		return blstVerifyMultipleSignatures(sigs, msgs, pubKeys)
+	}
+
+	var aggSig bls12.G2
+	aggSig.Clear()
+	// Aggregate verification requires distinct messages, so the scaled public keys of signatures
+	// of the same message are added up.
+	msgIndices := make(map[[32]byte]int, len(msgs))
+	rawKeys := make([]bls12.PublicKey, 0, len(pubKeys))
+	msgSlices := make([]byte, 0, 32*len(msgs))
+	for i := range sigs {
+		var r bls12.Fr
+		r.SetByCSPRNG()
+
+		var sig bls12.G2
+		bls12.G2Mul(&sig, bls12.CastFromSign(sigs[i].s), &r)
+		bls12.G2Add(&aggSig, &aggSig, &sig)
+
+		var key bls12.G1
+		bls12.G1Mul(&key, bls12.CastFromPublicKey(pubKeys[i].p), &r)
+		if j, ok := msgIndices[msgs[i]]; ok {
+			sum := bls12.CastFromPublicKey(&rawKeys[j])
+			bls12.G1Add(sum, sum, &key)
+			continue
+		}
+		msgIndices[msgs[i]] = len(rawKeys)
+		rawKeys = append(rawKeys, *bls12.CastToPublicKey(&key))
+		msgSlices = append(msgSlices, msgs[i][:]...)
+	}
+	return bls12.CastToSign(&aggSig).AggregateVerify(rawKeys, msgSlices), nil
+}
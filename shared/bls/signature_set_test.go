@@ -0,0 +1,59 @@
+package bls_test
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+func signatureSet(n int) *bls.SignatureSet {
+	set := bls.NewSet()
+	for i := 0; i < n; i++ {
+		priv := bls.RandKey()
+		msg := [32]byte{'m', byte(i)}
+		set.Add(priv.Sign(msg[:]), priv.PublicKey(), msg, "test")
+	}
+	return set
+}
+
+func TestVerifyMultipleSignatures(t *testing.T) {
+	set := signatureSet(10)
+	// Signatures of the same message are verified too.
+	priv := bls.RandKey()
+	set.Add(priv.Sign(set.Messages[0][:]), priv.PublicKey(), set.Messages[0], "same message")
+
+	valid, err := set.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("Expected signature set to verify")
+	}
+	if _, ok := set.VerifyEach(); !ok {
+		t.Error("Expected each signature to verify")
+	}
+}
+
+func TestVerifyMultipleSignatures_SwappedSignatures(t *testing.T) {
+	set := signatureSet(4)
+	// The sum of the signatures is unchanged, which a plain aggregate verification would accept.
+	set.Signatures[1], set.Signatures[2] = set.Signatures[2], set.Signatures[1]
+	set.Descriptions[1] = "swapped"
+	valid, err := set.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Error("Expected signature set with swapped signatures not to verify")
+	}
+	if desc, ok := set.VerifyEach(); ok || desc != "swapped" {
+		t.Errorf("Expected the swapped signature to be reported, received %q", desc)
+	}
+}
+
+func TestVerifyMultipleSignatures_MismatchedLengths(t *testing.T) {
+	set := signatureSet(2)
+	if _, err := bls.VerifyMultipleSignatures(set.Signatures, set.Messages[:1], set.PublicKeys); err == nil {
+		t.Error("Expected error for mismatched lengths")
+	}
+}
@@ -0,0 +1,38 @@
+// Package bufutil provides a pool of byte buffers reused across the encoding and decoding of
+// SSZ objects, which otherwise allocate a new buffer for every message handled.
+package bufutil
+
+import (
+	"sync"
+)
+
+// maxPooledCapacity bounds the buffers kept in the pool, so the occasional large object
+// does not keep its buffer alive for the lifetime of the process.
+const maxPooledCapacity = 1 << 22
+
+var pool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 1024)
+		return &b
+	},
+}
+
+// Get returns an empty buffer from the pool with at least the given capacity. The buffer is
+// handed back with Put once its contents are no longer referenced.
+func Get(size int) *[]byte {
+	buf := pool.Get().(*[]byte)
+	if cap(*buf) < size {
+		*buf = make([]byte, 0, size)
+	}
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// Put returns a buffer to the pool. Callers which appended past the capacity of the buffer
+// should store the grown slice in it first, so the larger allocation is reused.
+func Put(buf *[]byte) {
+	if buf == nil || cap(*buf) > maxPooledCapacity {
+		return
+	}
+	pool.Put(buf)
+}
@@ -0,0 +1,27 @@
+package bufutil
+
+import (
+	"testing"
+)
+
+func TestGet_Capacity(t *testing.T) {
+	buf := Get(4096)
+	if len(*buf) != 0 || cap(*buf) < 4096 {
+		t.Errorf("Wanted empty buffer of capacity 4096, received length %d and capacity %d", len(*buf), cap(*buf))
+	}
+	*buf = append(*buf, 'a')
+	Put(buf)
+
+	buf = Get(8)
+	if len(*buf) != 0 {
+		t.Errorf("Wanted empty buffer, received length %d", len(*buf))
+	}
+	Put(buf)
+}
+
+func TestPut_DropsLargeBuffers(t *testing.T) {
+	b := make([]byte, 0, maxPooledCapacity+1)
+	// Must not panic nor keep the buffer.
+	Put(&b)
+	Put(nil)
+}
@@ -0,0 +1,230 @@
+package cachemanager
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// timeNow is replaced in tests to expire entries without waiting.
+var timeNow = time.Now
+
+// Config defines the limits of a cache.
+type Config struct {
+	// Name identifies the cache in metrics. Registering a cache replaces any previous cache
+	// of the same name.
+	Name string
+	// MaxEntries is the number of entries above which the least recently used entry is evicted.
+	// No limit when 0.
+	MaxEntries int
+	// TTL is how long an entry is kept after it is set. No expiry when 0.
+	TTL time.Duration
+}
+
+// Cache is a least recently used cache whose entries have a cost, an estimate of their size in
+// bytes which counts against the memory budget of its manager.
+type Cache struct {
+	cfg     Config
+	manager *Manager
+	lock    sync.Mutex
+	ll      *list.List
+	items   map[interface{}]*list.Element
+	cost    int64
+	// detached is set when the cache is replaced in its manager, after which it no longer
+	// counts against the budget or reports metrics.
+	detached bool
+}
+
+type entry struct {
+	key    interface{}
+	value  interface{}
+	cost   int64
+	expiry time.Time
+}
+
+// Get returns the value of the key, if the key exists and has not expired.
+func (c *Cache) Get(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	el, ok := c.items[key]
+	if ok && c.expired(el.Value.(*entry)) {
+		c.removeElement(el, reasonExpired)
+		ok = false
+	}
+	if !ok {
+		c.recordMiss()
+		c.lock.Unlock()
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	c.recordHit()
+	value := el.Value.(*entry).value
+	c.lock.Unlock()
+	return value, true
+}
+
+// Peek returns the value of the key like Get, without updating its recency or the hit and miss
+// metrics.
+func (c *Cache) Peek(key interface{}) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	el, ok := c.items[key]
+	if !ok || c.expired(el.Value.(*entry)) {
+		return nil, false
+	}
+	return el.Value.(*entry).value, true
+}
+
+// Contains returns true if the key exists and has not expired, without updating its recency.
+func (c *Cache) Contains(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	el, ok := c.items[key]
+	return ok && !c.expired(el.Value.(*entry))
+}
+
+// Set adds or replaces the value of the key with the given cost. Least recently used entries
+// are evicted when the cache exceeds its number of entries or the manager its budget.
+func (c *Cache) Set(key, value interface{}, cost int64) {
+	c.lock.Lock()
+	var expiry time.Time
+	if c.cfg.TTL > 0 {
+		expiry = timeNow().Add(c.cfg.TTL)
+	}
+	delta := cost
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		delta -= e.cost
+		e.value, e.cost, e.expiry = value, cost, expiry
+		c.ll.MoveToFront(el)
+		c.cost += delta
+		c.account(delta)
+	} else {
+		c.items[key] = c.ll.PushFront(&entry{key: key, value: value, cost: cost, expiry: expiry})
+		c.cost += cost
+		c.account(cost)
+	}
+	for c.cfg.MaxEntries > 0 && c.ll.Len() > c.cfg.MaxEntries {
+		c.removeElement(c.ll.Back(), reasonCapacity)
+	}
+	c.updateSizeMetrics()
+	detached := c.detached
+	c.lock.Unlock()
+
+	if !detached && delta > 0 {
+		c.manager.reclaim()
+	}
+}
+
+// Delete removes the key, returning true if it existed.
+func (c *Cache) Delete(key interface{}) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	el, ok := c.items[key]
+	if ok {
+		c.removeElement(el, "")
+		c.updateSizeMetrics()
+	}
+	return ok
+}
+
+// Keys returns the keys of the cache, from most to least recently used.
+func (c *Cache) Keys() []interface{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	keys := make([]interface{}, 0, c.ll.Len())
+	for el := c.ll.Front(); el != nil; el = el.Next() {
+		keys = append(keys, el.Value.(*entry).key)
+	}
+	return keys
+}
+
+// Len returns the number of entries of the cache, including expired entries not yet evicted.
+func (c *Cache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.ll.Len()
+}
+
+// Cost returns the total cost of the entries of the cache.
+func (c *Cache) Cost() int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.cost
+}
+
+// Purge removes all entries of the cache.
+func (c *Cache) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for c.ll.Len() > 0 {
+		c.removeElement(c.ll.Back(), "")
+	}
+	c.updateSizeMetrics()
+}
+
+// evictForBudget evicts the expired entries of the cache or, if none expired, its least recently
+// used entry. It returns false if the cache is empty.
+func (c *Cache) evictForBudget() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.ll.Len() == 0 {
+		return false
+	}
+	evicted := false
+	for el := c.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if c.expired(el.Value.(*entry)) {
+			c.removeElement(el, reasonExpired)
+			evicted = true
+		}
+		el = prev
+	}
+	if !evicted {
+		c.removeElement(c.ll.Back(), reasonBudget)
+	}
+	c.updateSizeMetrics()
+	return true
+}
+
+func (c *Cache) expired(e *entry) bool {
+	return !e.expiry.IsZero() && timeNow().After(e.expiry)
+}
+
+// removeElement removes an entry, counting it as evicted for the given reason unless the
+// reason is empty. The caller holds the lock.
+func (c *Cache) removeElement(el *list.Element, reason string) {
+	e := c.ll.Remove(el).(*entry)
+	delete(c.items, e.key)
+	c.cost -= e.cost
+	c.account(-e.cost)
+	if reason != "" && !c.detached {
+		evictionsCounter.WithLabelValues(c.cfg.Name, reason).Inc()
+	}
+}
+
+// account adds delta to the memory used of the manager. The caller holds the lock.
+func (c *Cache) account(delta int64) {
+	if !c.detached {
+		c.manager.account(delta)
+	}
+}
+
+func (c *Cache) recordHit() {
+	if !c.detached {
+		hitsCounter.WithLabelValues(c.cfg.Name).Inc()
+	}
+}
+
+func (c *Cache) recordMiss() {
+	if !c.detached {
+		missesCounter.WithLabelValues(c.cfg.Name).Inc()
+	}
+}
+
+// updateSizeMetrics reports the entries and cost of the cache. The caller holds the lock.
+func (c *Cache) updateSizeMetrics() {
+	if !c.detached {
+		entriesGauge.WithLabelValues(c.cfg.Name).Set(float64(c.ll.Len()))
+		sizeGauge.WithLabelValues(c.cfg.Name).Set(float64(c.cost))
+	}
+}
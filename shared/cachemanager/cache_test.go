@@ -0,0 +1,108 @@
+package cachemanager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewManager(0).NewCache(Config{Name: "test", MaxEntries: 2})
+	c.Set("a", 1, 1)
+	c.Set("b", 2, 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Expected a in cache")
+	}
+	c.Set("c", 3, 1)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("Expected least recently used entry b to be evicted")
+	}
+	if want := []interface{}{"c", "a"}; !reflect.DeepEqual(c.Keys(), want) {
+		t.Errorf("Wanted keys %v, received %v", want, c.Keys())
+	}
+	if c.Cost() != 2 {
+		t.Errorf("Wanted cost 2, received %d", c.Cost())
+	}
+}
+
+func TestCache_Expiry(t *testing.T) {
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	m := NewManager(0)
+	c := m.NewCache(Config{Name: "test", TTL: time.Minute})
+	c.Set("a", 1, 10)
+	now = now.Add(30 * time.Second)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Wanted 1 before expiry, received %v", v)
+	}
+	now = now.Add(time.Minute)
+	if c.Contains("a") {
+		t.Error("Expected a to be expired")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("Expected a to be expired")
+	}
+	if c.Len() != 0 || m.Used() != 0 {
+		t.Errorf("Expected expired entry to be removed, %d entries using %d bytes", c.Len(), m.Used())
+	}
+}
+
+func TestManager_Budget(t *testing.T) {
+	m := NewManager(100)
+	states := m.NewCache(Config{Name: "states"})
+	pubkeys := m.NewCache(Config{Name: "pubkeys"})
+	for i := 0; i < 5; i++ {
+		pubkeys.Set(i, i, 4)
+	}
+	states.Set("a", "a", 40)
+	states.Set("b", "b", 40)
+	if m.Used() != 100 {
+		t.Fatalf("Wanted 100 bytes used, received %d", m.Used())
+	}
+
+	// The largest cache is evicted from when the budget is exceeded.
+	states.Set("c", "c", 40)
+	if states.Contains("a") || !states.Contains("b") || !states.Contains("c") {
+		t.Errorf("Expected least recently used state to be evicted, received %v", states.Keys())
+	}
+	if pubkeys.Len() != 5 {
+		t.Errorf("Expected public keys to be kept, received %d", pubkeys.Len())
+	}
+	if m.Used() != 100 {
+		t.Errorf("Wanted 100 bytes used, received %d", m.Used())
+	}
+
+	m.SetBudget(50)
+	if m.Used() > 50 {
+		t.Errorf("Wanted at most 50 bytes used, received %d", m.Used())
+	}
+	m.SetBudget(0)
+	states.Set("d", "d", 1000)
+	if !states.Contains("d") {
+		t.Error("Expected entry to be kept without budget")
+	}
+}
+
+func TestManager_ReplacesCacheOfSameName(t *testing.T) {
+	m := NewManager(0)
+	old := m.NewCache(Config{Name: "test"})
+	old.Set("a", 1, 10)
+	c := m.NewCache(Config{Name: "test"})
+	if m.Used() != 0 {
+		t.Errorf("Expected replaced cache to be released, received %d bytes used", m.Used())
+	}
+	old.Set("b", 2, 10)
+	c.Set("a", 1, 5)
+	if m.Used() != 5 {
+		t.Errorf("Wanted 5 bytes used, received %d", m.Used())
+	}
+	if !c.Delete("a") || c.Delete("a") {
+		t.Error("Expected a to be deleted once")
+	}
+	if m.Used() != 0 {
+		t.Errorf("Wanted no bytes used, received %d", m.Used())
+	}
+}
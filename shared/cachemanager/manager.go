@@ -0,0 +1,120 @@
+// Package cachemanager provides least recently used caches with time based expiry which share
+// a global memory budget. Each cache reports its hits, misses, evictions, entries and size, so
+// that the limits of the committee, hot state, domain and public key caches can be tuned.
+package cachemanager
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	reasonCapacity = "capacity"
+	reasonBudget   = "budget"
+	reasonExpired  = "expired"
+)
+
+var defaultManager = NewManager(0)
+
+// Manager registers caches and evicts their entries while their total cost exceeds its budget.
+type Manager struct {
+	budget int64
+	used   int64
+	// lock guards caches and serializes reclaiming memory.
+	lock   sync.Mutex
+	caches map[string]*Cache
+}
+
+// NewManager creates a manager with a budget in bytes. No budget is enforced when 0.
+func NewManager(budget int64) *Manager {
+	return &Manager{
+		budget: budget,
+		caches: make(map[string]*Cache),
+	}
+}
+
+// Default returns the manager of the caches of the process.
+func Default() *Manager {
+	return defaultManager
+}
+
+// NewCache registers a cache with the default manager.
+func NewCache(cfg Config) *Cache {
+	return defaultManager.NewCache(cfg)
+}
+
+// NewCache registers a cache with the manager, replacing any previous cache of the same name.
+// The replaced cache keeps working but no longer counts against the budget.
+func (m *Manager) NewCache(cfg Config) *Cache {
+	c := &Cache{
+		cfg:     cfg,
+		manager: m,
+		ll:      list.New(),
+		items:   make(map[interface{}]*list.Element),
+	}
+	m.lock.Lock()
+	old := m.caches[cfg.Name]
+	m.caches[cfg.Name] = c
+	m.lock.Unlock()
+
+	if old != nil {
+		old.lock.Lock()
+		m.account(-old.cost)
+		old.detached = true
+		old.lock.Unlock()
+	}
+	c.updateSizeMetrics()
+	return c
+}
+
+// SetBudget sets the budget in bytes and evicts entries until the caches fit within it. No
+// budget is enforced when 0.
+func (m *Manager) SetBudget(budget int64) {
+	atomic.StoreInt64(&m.budget, budget)
+	if m == defaultManager {
+		budgetGauge.Set(float64(budget))
+	}
+	m.reclaim()
+}
+
+// Budget returns the budget in bytes.
+func (m *Manager) Budget() int64 {
+	return atomic.LoadInt64(&m.budget)
+}
+
+// Used returns the total cost of the entries of the registered caches.
+func (m *Manager) Used() int64 {
+	return atomic.LoadInt64(&m.used)
+}
+
+func (m *Manager) account(delta int64) {
+	used := atomic.AddInt64(&m.used, delta)
+	if m == defaultManager {
+		usedGauge.Set(float64(used))
+	}
+}
+
+// reclaim evicts entries of the largest cache until the total cost is within the budget.
+// Evicting from the largest cache keeps a cache with many cheap entries, such as public keys,
+// from being emptied by a cache with few expensive entries, such as states.
+func (m *Manager) reclaim() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for {
+		budget := m.Budget()
+		if budget == 0 || m.Used() <= budget {
+			return
+		}
+		var largest *Cache
+		var largestCost int64
+		for _, c := range m.caches {
+			if cost := c.Cost(); cost > largestCost {
+				largest, largestCost = c, cost
+			}
+		}
+		if largest == nil || !largest.evictForBudget() {
+			return
+		}
+	}
+}
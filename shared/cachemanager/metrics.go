@@ -0,0 +1,37 @@
+package cachemanager
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	hitsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_hits_total",
+		Help: "The number of lookups of a key present in the cache.",
+	}, []string{"cache"})
+	missesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_misses_total",
+		Help: "The number of lookups of a key missing or expired in the cache.",
+	}, []string{"cache"})
+	evictionsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_evictions_total",
+		Help: "The number of entries evicted from the cache, by reason: capacity, budget or expired.",
+	}, []string{"cache", "reason"})
+	entriesGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cache_entries",
+		Help: "The number of entries in the cache.",
+	}, []string{"cache"})
+	sizeGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cache_size_bytes",
+		Help: "The estimated size in bytes of the entries in the cache.",
+	}, []string{"cache"})
+	budgetGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cache_memory_budget_bytes",
+		Help: "The memory budget in bytes shared by the caches, 0 if unlimited.",
+	})
+	usedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cache_memory_used_bytes",
+		Help: "The estimated size in bytes of the entries of all caches.",
+	})
+)
@@ -0,0 +1,72 @@
+package clientstats
+
+import (
+	dto "github.com/prometheus/client_model/go"
+)
+
+// metricSet indexes gathered metric families by name.
+type metricSet map[string]*dto.MetricFamily
+
+func newMetricSet(families []*dto.MetricFamily) metricSet {
+	m := make(metricSet, len(families))
+	for _, f := range families {
+		m[f.GetName()] = f
+	}
+	return m
+}
+
+// sum adds up the values of all series of a gauge, counter or untyped metric.
+func (m metricSet) sum(name string) float64 {
+	return m.sumWhere(name, func(*dto.Metric) bool { return true })
+}
+
+// sumLabel adds up the values of the series whose label has the given value.
+func (m metricSet) sumLabel(name string, label string, value string) float64 {
+	return m.sumWhere(name, func(metric *dto.Metric) bool {
+		for _, l := range metric.GetLabel() {
+			if l.GetName() == label {
+				return l.GetValue() == value
+			}
+		}
+		return false
+	})
+}
+
+// count returns the number of series of a metric whose value satisfies the filter.
+func (m metricSet) count(name string, filter func(float64) bool) int64 {
+	var n int64
+	m.sumWhere(name, func(metric *dto.Metric) bool {
+		if filter(value(metric)) {
+			n++
+		}
+		return false
+	})
+	return n
+}
+
+func (m metricSet) sumWhere(name string, filter func(*dto.Metric) bool) float64 {
+	f, ok := m[name]
+	if !ok {
+		return 0
+	}
+	var total float64
+	for _, metric := range f.GetMetric() {
+		if filter(metric) {
+			total += value(metric)
+		}
+	}
+	return total
+}
+
+func value(metric *dto.Metric) float64 {
+	switch {
+	case metric.Gauge != nil:
+		return metric.Gauge.GetValue()
+	case metric.Counter != nil:
+		return metric.Counter.GetValue()
+	case metric.Untyped != nil:
+		return metric.Untyped.GetValue()
+	default:
+		return 0
+	}
+}
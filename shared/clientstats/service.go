@@ -0,0 +1,273 @@
+// Package clientstats defines an opt-in service which periodically reports beacon node
+// and validator telemetry to a remote endpoint in the client-stats JSON schema, for
+// monitoring fleets of nodes from a single dashboard.
+package clientstats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/version"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "clientstats")
+
+var _ = shared.Service(&Service{})
+
+// defaultInterval is used when no positive report interval is configured.
+const defaultInterval = time.Minute
+
+// requestTimeout bounds each report request so a slow endpoint cannot stall reporting.
+const requestTimeout = 10 * time.Second
+
+// Config for the client-stats service.
+type Config struct {
+	// URL is the endpoint the stats are POSTed to.
+	URL string
+	// Interval between two reports.
+	Interval time.Duration
+	// Process is either ProcessBeaconNode or ProcessValidator.
+	Process string
+	// DatabaseDir is the beacon node database directory, used to report its disk usage.
+	DatabaseDir string
+	// Gatherer is the source of the reported metrics, defaults to the prometheus default gatherer.
+	Gatherer prometheus.Gatherer
+}
+
+// Service periodically reports the stats of the running process.
+type Service struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	cfg     *Config
+	client  *http.Client
+	lock    sync.RWMutex
+	url     string
+	lastErr error
+}
+
+// NewService creates a new client-stats service.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	if cfg.Gatherer == nil {
+		cfg.Gatherer = prometheus.DefaultGatherer
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	return &Service{
+		ctx:    ctx,
+		cancel: cancel,
+		cfg:    cfg,
+		client: &http.Client{Timeout: requestTimeout},
+		url:    cfg.URL,
+	}
+}
+
+// Start the reporting loop.
+func (s *Service) Start() {
+	log.WithFields(logrus.Fields{
+		"url":      s.cfg.URL,
+		"interval": s.cfg.Interval,
+	}).Info("Reporting client stats")
+	go s.run()
+}
+
+// Stop the reporting loop.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// SetURL changes the endpoint of the following reports.
+func (s *Service) SetURL(url string) error {
+	if url == "" {
+		return errors.New("empty stats endpoint, restart to stop reporting")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.url = url
+	return nil
+}
+
+// Status returns the error of the last report, if it failed.
+func (s *Service) Status() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.lastErr
+}
+
+func (s *Service) run() {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			err := s.report(s.ctx)
+			if err != nil {
+				log.WithError(err).Debug("Could not report client stats")
+			}
+			s.lock.Lock()
+			s.lastErr = err
+			s.lock.Unlock()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// report gathers the current stats and POSTs them to the configured endpoint.
+func (s *Service) report(ctx context.Context) error {
+	stats, err := s.collect()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(stats)
+	if err != nil {
+		return errors.Wrap(err, "could not encode stats")
+	}
+	s.lock.RLock()
+	url := s.url
+	s.lock.RUnlock()
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "could not send stats")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("stats endpoint returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// collect builds the process and system stats objects from the gathered metrics.
+func (s *Service) collect() ([]interface{}, error) {
+	families, err := s.cfg.Gatherer.Gather()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not gather metrics")
+	}
+	m := newMetricSet(families)
+	now := time.Now()
+
+	process := ProcessStats{
+		CommonStats:            commonStats(now, s.cfg.Process),
+		CPUProcessSecondsTotal: int64(m.sum("process_cpu_seconds_total")),
+		MemoryProcessBytes:     int64(m.sum("process_resident_memory_bytes")),
+		ClientName:             ClientName,
+		ClientVersion:          version.GetVersion(),
+	}
+	var stats interface{}
+	switch s.cfg.Process {
+	case ProcessBeaconNode:
+		stats = s.beaconNodeStats(m, process)
+	case ProcessValidator:
+		stats = validatorStats(m, process)
+	default:
+		return nil, fmt.Errorf("unknown process %q", s.cfg.Process)
+	}
+	system := &SystemStats{
+		CommonStats: commonStats(now, ProcessSystem),
+		CPUCores:    runtime.NumCPU(),
+		CPUThreads:  runtime.NumCPU(),
+		MiscOS:      miscOS(),
+	}
+	return []interface{}{stats, system}, nil
+}
+
+func (s *Service) beaconNodeStats(m metricSet, process ProcessStats) *BeaconNodeStats {
+	headSlot := m.sum("beacon_head_slot")
+	clockSlot := m.sum("beacon_clock_time_slot")
+	stats := &BeaconNodeStats{
+		ProcessStats:          process,
+		NetworkPeersConnected: int64(m.sumLabel("p2p_peer_count", "state", "Connected")),
+		SyncEth1Connected:     m.sum("powchain_sync_eth1_connected") > 0,
+		// The node is considered synced when its head is within an epoch of the wall clock.
+		SyncEth2Synced:     clockSlot > 0 && headSlot+float64(params.BeaconConfig().SlotsPerEpoch) >= clockSlot,
+		SyncBeaconHeadSlot: int64(headSlot),
+	}
+	if s.cfg.DatabaseDir != "" {
+		stats.DiskBeaconchainBytesTotal = dirSize(s.cfg.DatabaseDir)
+	}
+	return stats
+}
+
+// dirSize returns the total size of the files in a directory, ignoring unreadable entries.
+func dirSize(dir string) int64 {
+	var size int64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Debug("Could not compute database size")
+	}
+	return size
+}
+
+func validatorStats(m metricSet, process ProcessStats) *ValidatorStats {
+	isActive := func(status float64) bool {
+		switch ethpb.ValidatorStatus(int32(status)) {
+		case ethpb.ValidatorStatus_ACTIVE, ethpb.ValidatorStatus_EXITING, ethpb.ValidatorStatus_SLASHING:
+			return true
+		}
+		return false
+	}
+	stats := &ValidatorStats{
+		ProcessStats:    process,
+		ValidatorTotal:  m.count("validator_statuses", func(float64) bool { return true }),
+		ValidatorActive: m.count("validator_statuses", isActive),
+	}
+	successful := m.sum("validator_successful_attestations")
+	failed := m.sum("validator_failed_attestations")
+	if successful+failed > 0 {
+		stats.AttestationEffectiveness = successful / (successful + failed)
+	}
+	return stats
+}
+
+func commonStats(now time.Time, process string) CommonStats {
+	return CommonStats{
+		Version:   APIVersion,
+		Timestamp: now.UnixNano() / int64(time.Millisecond),
+		Process:   process,
+	}
+}
+
+// miscOS returns the operating system in the abbreviated form of the schema.
+func miscOS() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "lin"
+	case "windows":
+		return "win"
+	case "darwin":
+		return "mac"
+	default:
+		return "unk"
+	}
+}
@@ -0,0 +1,135 @@
+package clientstats
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestService_ReportValidatorStats(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	statuses := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "validator_statuses"}, []string{"pubkey"})
+	success := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "validator_successful_attestations"}, []string{"pubkey"})
+	failed := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "validator_failed_attestations"}, []string{"pubkey"})
+	reg.MustRegister(statuses, success, failed)
+	statuses.WithLabelValues("a").Set(3) // Active.
+	statuses.WithLabelValues("b").Set(2) // Pending.
+	statuses.WithLabelValues("c").Set(4) // Exiting.
+	success.WithLabelValues("a").Add(3)
+	failed.WithLabelValues("a").Add(1)
+
+	var received []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Fatal(err)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewService(context.Background(), &Config{
+		URL:      srv.URL,
+		Process:  ProcessValidator,
+		Gatherer: reg,
+	})
+	if err := s.report(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("Wanted validator and system stats, received %d objects", len(received))
+	}
+	v := received[0]
+	if v["process"] != ProcessValidator || v["client_name"] != ClientName {
+		t.Errorf("Unexpected process fields: %v", v)
+	}
+	if v["validator_total"] != float64(3) || v["validator_active"] != float64(2) {
+		t.Errorf("Wanted 3 total and 2 active validators, received %v and %v", v["validator_total"], v["validator_active"])
+	}
+	if v["attestation_effectiveness"] != 0.75 {
+		t.Errorf("Wanted attestation effectiveness 0.75, received %v", v["attestation_effectiveness"])
+	}
+	if received[1]["process"] != ProcessSystem {
+		t.Errorf("Wanted system stats, received %v", received[1]["process"])
+	}
+}
+
+func TestService_ReportBeaconNodeStats(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	peers := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "p2p_peer_count"}, []string{"state"})
+	head := prometheus.NewGauge(prometheus.GaugeOpts{Name: "beacon_head_slot"})
+	clock := prometheus.NewGauge(prometheus.GaugeOpts{Name: "beacon_clock_time_slot"})
+	reg.MustRegister(peers, head, clock)
+	peers.WithLabelValues("Connected").Set(12)
+	peers.WithLabelValues("Disconnected").Set(4)
+	head.Set(100)
+	clock.Set(101)
+
+	s := NewService(context.Background(), &Config{Process: ProcessBeaconNode, Gatherer: reg})
+	stats, err := s.collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok := stats[0].(*BeaconNodeStats)
+	if !ok {
+		t.Fatalf("Wanted beacon node stats, received %T", stats[0])
+	}
+	if b.NetworkPeersConnected != 12 {
+		t.Errorf("Wanted 12 connected peers, received %d", b.NetworkPeersConnected)
+	}
+	if !b.SyncEth2Synced || b.SyncBeaconHeadSlot != 100 {
+		t.Errorf("Wanted synced at head slot 100, received synced=%v slot=%d", b.SyncEth2Synced, b.SyncBeaconHeadSlot)
+	}
+	if b.SyncEth1Connected {
+		t.Error("Expected eth1 to be reported as not connected")
+	}
+}
+
+func TestService_ReportFailsOnErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	s := NewService(context.Background(), &Config{
+		URL:      srv.URL,
+		Process:  ProcessValidator,
+		Gatherer: prometheus.NewRegistry(),
+	})
+	if err := s.report(context.Background()); err == nil {
+		t.Error("Expected error for bad request status")
+	}
+}
+
+func TestService_SetURL(t *testing.T) {
+	var reports int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reports++
+	}))
+	defer srv.Close()
+
+	s := NewService(context.Background(), &Config{
+		URL:      "http://127.0.0.1:0",
+		Process:  ProcessValidator,
+		Gatherer: prometheus.NewRegistry(),
+	})
+	if err := s.SetURL(""); err == nil {
+		t.Error("Expected error for an empty endpoint")
+	}
+	if err := s.SetURL(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.report(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if reports != 1 {
+		t.Errorf("Wanted 1 report to the new endpoint, received %d", reports)
+	}
+}
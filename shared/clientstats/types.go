@@ -0,0 +1,65 @@
+package clientstats
+
+// Process names used in the client-stats schema.
+const (
+	ProcessBeaconNode = "beaconnode"
+	ProcessValidator  = "validator"
+	ProcessSystem     = "system"
+)
+
+// APIVersion is the version of the client-stats schema implemented by this package.
+const APIVersion = 1
+
+// ClientName is reported as the client_name of every process.
+const ClientName = "prysm"
+
+// CommonStats are the fields shared by every object in the client-stats schema.
+type CommonStats struct {
+	Version   int    `json:"version"`
+	Timestamp int64  `json:"timestamp"`
+	Process   string `json:"process"`
+}
+
+// ProcessStats are the fields shared by the beacon node and validator processes.
+type ProcessStats struct {
+	CommonStats
+	CPUProcessSecondsTotal     int64  `json:"cpu_process_seconds_total"`
+	MemoryProcessBytes         int64  `json:"memory_process_bytes"`
+	ClientName                 string `json:"client_name"`
+	ClientVersion              string `json:"client_version"`
+	ClientBuild                int64  `json:"client_build"`
+	SyncEth2FallbackConfigured bool   `json:"sync_eth2_fallback_configured"`
+	SyncEth2FallbackConnected  bool   `json:"sync_eth2_fallback_connected"`
+}
+
+// BeaconNodeStats is the client-stats object of a beacon node process.
+type BeaconNodeStats struct {
+	ProcessStats
+	DiskBeaconchainBytesTotal       int64 `json:"disk_beaconchain_bytes_total"`
+	NetworkLibp2pBytesTotalReceive  int64 `json:"network_libp2p_bytes_total_receive"`
+	NetworkLibp2pBytesTotalTransmit int64 `json:"network_libp2p_bytes_total_transmit"`
+	NetworkPeersConnected           int64 `json:"network_peers_connected"`
+	SyncEth1Connected               bool  `json:"sync_eth1_connected"`
+	SyncEth2Synced                  bool  `json:"sync_eth2_synced"`
+	SyncBeaconHeadSlot              int64 `json:"sync_beacon_head_slot"`
+	SyncEth1FallbackConfigured      bool  `json:"sync_eth1_fallback_configured"`
+	SyncEth1FallbackConnected       bool  `json:"sync_eth1_fallback_connected"`
+	SlasherActive                   bool  `json:"slasher_active"`
+}
+
+// ValidatorStats is the client-stats object of a validator process. AttestationEffectiveness
+// is a Prysm extension to the schema holding the share of successful attestations in [0, 1].
+type ValidatorStats struct {
+	ProcessStats
+	ValidatorTotal           int64   `json:"validator_total"`
+	ValidatorActive          int64   `json:"validator_active"`
+	AttestationEffectiveness float64 `json:"attestation_effectiveness"`
+}
+
+// SystemStats is the client-stats object describing the host the process runs on.
+type SystemStats struct {
+	CommonStats
+	CPUCores   int    `json:"cpu_cores"`
+	CPUThreads int    `json:"cpu_threads"`
+	MiscOS     string `json:"misc_os"`
+}
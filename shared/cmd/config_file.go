@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/prysmaticlabs/prysm/shared/logutil"
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+)
+
+// LoadFlagsFromConfigFile sets the flags which are not given on the command line from the
+// YAML file of --config-file, if specified.
+func LoadFlagsFromConfigFile(ctx *cli.Context, flags []cli.Flag) error {
+	if !ctx.IsSet(ConfigFileFlag.Name) {
+		return nil
+	}
+	return altsrc.InitInputSourceWithContext(flags, altsrc.NewYamlSourceFromFlagFunc(ConfigFileFlag.Name))(ctx)
+}
+
+// WriteEffectiveConfig writes the value of every flag, sorted by name, marking the flags set
+// on the command line, in the config file or through the environment. Values of secrets
+// registered for redaction are masked.
+func WriteEffectiveConfig(w io.Writer, ctx *cli.Context, flags []cli.Flag) error {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, f.Names()[0])
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		source := "default"
+		if ctx.IsSet(name) {
+			source = "set"
+		}
+		// The string lookup renders the value of any flag type.
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", name, logutil.Redact(ctx.String(name)), source); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
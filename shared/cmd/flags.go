@@ -2,6 +2,9 @@
 package cmd
 
 import (
+	"math"
+	"time"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -12,6 +15,11 @@ var (
 		Usage: "Logging verbosity (trace, debug, info=default, warn, error, fatal, panic)",
 		Value: "info",
 	}
+	// VModuleFlag defines the log levels of individual modules.
+	VModuleFlag = &cli.StringFlag{
+		Name:  "vmodule",
+		Usage: "Logging verbosity of individual modules overriding --verbosity, as comma separated module=level pairs (e.g. sync=debug,p2p=warn)",
+	}
 	// DataDirFlag defines a path on disk.
 	DataDirFlag = &cli.StringFlag{
 		Name:  "datadir",
@@ -46,6 +54,41 @@ var (
 		Name:  "disable-monitoring",
 		Usage: "Disable monitoring service.",
 	}
+	// ClientStatsAPIURLFlag enables the client-stats service and defines the endpoint stats are posted to.
+	ClientStatsAPIURLFlag = &cli.StringFlag{
+		Name:  "clientstats-api-url",
+		Usage: "Opt-in URL of a client-stats endpoint that beacon node or validator telemetry is periodically posted to.",
+	}
+	// ClientStatsIntervalFlag defines the interval between two client-stats reports.
+	ClientStatsIntervalFlag = &cli.DurationFlag{
+		Name:  "clientstats-interval",
+		Usage: "Interval between two reports to the --clientstats-api-url endpoint.",
+		Value: time.Minute,
+	}
+	// EnableRoughtimeFlag enables the adjustment of the clock by the roughtime servers.
+	EnableRoughtimeFlag = &cli.BoolFlag{
+		Name: "enable-roughtime",
+		Usage: "Adjust the clock used for slot timing by the average offset reported by the roughtime servers, " +
+			"for hosts whose system clock cannot be corrected. Prefer fixing the system clock with NTP instead.",
+	}
+	// RoughtimeIntervalFlag defines the interval between two clock adjustments.
+	RoughtimeIntervalFlag = &cli.DurationFlag{
+		Name:  "roughtime-interval",
+		Usage: "Interval between two queries of the roughtime servers with --enable-roughtime.",
+		Value: time.Hour,
+	}
+	// RoughtimeMaxOffsetFlag defines the largest clock adjustment which is applied.
+	RoughtimeMaxOffsetFlag = &cli.DurationFlag{
+		Name:  "roughtime-max-offset",
+		Usage: "Largest clock offset applied with --enable-roughtime, larger offsets are rejected as faulty.",
+		Value: 15 * time.Second,
+	}
+	// SlotsPerSecondFlag runs an accelerated slot clock for development.
+	SlotsPerSecondFlag = &cli.Float64Flag{
+		Name: "slots-per-second",
+		Usage: "Development only: run the slot clock faster from genesis so this many slots pass per second. " +
+			"The beacon node and validator client of a local chain must use the same value.",
+	}
 	// NoDiscovery specifies whether we are running a local network and have no need for connecting
 	// to the bootstrap nodes in the cloud
 	NoDiscovery = &cli.BoolFlag{
@@ -57,6 +100,12 @@ var (
 		Name:  "peer",
 		Usage: "Connect with this peer. This flag may be used multiple times.",
 	}
+	// TrustedPeers specifies a set of peers which are never banned nor rejected at the peer limits.
+	TrustedPeers = &cli.StringSliceFlag{
+		Name: "trusted-peer",
+		Usage: "Trust this peer, which is never banned for its score nor rejected at the peer limits. " +
+			"This flag may be used multiple times.",
+	}
 	// BootstrapNode tells the beacon node which bootstrap node to connect to
 	BootstrapNode = &cli.StringFlag{
 		Name:  "bootstrap-node",
@@ -118,6 +167,16 @@ var (
 		Usage: "The max number of p2p peers to maintain.",
 		Value: 30,
 	}
+	// P2PMaxInboundPeers defines a flag to specify the max number of peers connected inbound in libp2p.
+	P2PMaxInboundPeers = &cli.Int64Flag{
+		Name:  "p2p-max-inbound-peers",
+		Usage: "The max number of p2p peers connecting to the node, leaving the other peer slots to dialed peers. 0 for no limit.",
+	}
+	// P2PMaxOutboundPeers defines a flag to specify the max number of peers connected outbound in libp2p.
+	P2PMaxOutboundPeers = &cli.Int64Flag{
+		Name:  "p2p-max-outbound-peers",
+		Usage: "The max number of p2p peers dialed by the node. 0 for no limit.",
+	}
 	// P2PWhitelist defines a CIDR subnet to exclusively allow connections.
 	P2PWhitelist = &cli.StringFlag{
 		Name: "p2p-whitelist",
@@ -135,7 +194,7 @@ var (
 	// P2PEncoding defines the encoding format for p2p messages.
 	P2PEncoding = &cli.StringFlag{
 		Name:  "p2p-encoding",
-		Usage: "The encoding format of messages sent over the wire. The default is 0, which represents ssz",
+		Usage: "The encoding format of messages sent over the wire. Supported values are: ssz, ssz-snappy",
 		Value: "ssz-snappy",
 	}
 	// P2PPubsub defines the pubsub router to use for p2p messages.
@@ -171,10 +230,16 @@ var (
 		Name:  "log-file",
 		Usage: "Specify log file name, relative or absolute",
 	}
-	// EnableUPnPFlag specifies if UPnP should be enabled or not. The default value is false.
+	// EnableUPnPFlag restricts the port mapping of the node to UPnP. The default value is false.
 	EnableUPnPFlag = &cli.BoolFlag{
 		Name:  "enable-upnp",
-		Usage: "Enable the service (Beacon chain or Validator) to use UPnP when possible.",
+		Usage: "Map the p2p ports on the router with UPnP only, instead of whichever of UPnP and NAT-PMP the router supports.",
+	}
+	// DisableNATFlag disables the port mapping and external IP detection of the node. The default value is false.
+	DisableNATFlag = &cli.BoolFlag{
+		Name: "disable-nat",
+		Usage: "Disable mapping the p2p ports on the router with UPnP or NAT-PMP, which is done by default, and " +
+			"advertising the external IP address of the router once the ports are mapped.",
 	}
 	// ConfigFileFlag specifies the filepath to load flag values.
 	ConfigFileFlag = &cli.StringFlag{
@@ -186,10 +251,22 @@ var (
 		Name:  "chain-config-file",
 		Usage: "The path to a YAML file with chain config values",
 	}
+	// NetworkFlag selects a known network, which determines the chain config, bootnodes, deposit
+	// contract and a data directory separate from the other networks.
+	NetworkFlag = &cli.StringFlag{
+		Name:  "network",
+		Usage: "Name of a known network to join, such as testnet or minimal. The node's data is kept in a subdirectory of --datadir named after the network",
+	}
 	// GrpcMaxCallRecvMsgSizeFlag defines the max call message size for GRPC
 	GrpcMaxCallRecvMsgSizeFlag = &cli.IntFlag{
 		Name:  "grpc-max-msg-size",
 		Usage: "Integer to define max recieve message call size (default: 4194304 (for 4MB))",
 		Value: 1 << 22,
 	}
+	// GrpcMaxCallSendMsgSizeFlag defines the max send message size of gRPC clients.
+	GrpcMaxCallSendMsgSizeFlag = &cli.IntFlag{
+		Name:  "grpc-max-send-msg-size",
+		Usage: "Integer to define max send message call size (default: 2147483647, unlimited)",
+		Value: math.MaxInt32,
+	}
 )
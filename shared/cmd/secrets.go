@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
+	"github.com/urfave/cli/v2"
+)
+
+// SecretEnvVar returns the environment variable a secret flag can be provided with,
+// PRYSM_ followed by the upper case flag name, e.g. PRYSM_HTTP_WEB3PROVIDER.
+func SecretEnvVar(flagName string) string {
+	return "PRYSM_" + strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+// LoadSecrets fills secret flags which are not set on the command line from the
+// environment, and registers their values for redaction from logs. For each flag, the
+// variable from SecretEnvVar and any variable in the flag's EnvVars is checked, as is
+// the same variable with a _FILE suffix naming a file to read the value from, which
+// suits secret mounts of container orchestrators. Flags left at their default values are
+// not registered, as the defaults are public.
+func LoadSecrets(ctx *cli.Context, secretFlags []*cli.StringFlag) error {
+	for _, f := range secretFlags {
+		if !ctx.IsSet(f.Name) {
+			value, err := secretFromEnv(append(f.EnvVars, SecretEnvVar(f.Name)))
+			if err != nil {
+				return errors.Wrapf(err, "could not load --%s", f.Name)
+			}
+			if value == "" {
+				continue
+			}
+			if err := ctx.Set(f.Name, value); err != nil {
+				return errors.Wrapf(err, "could not set --%s", f.Name)
+			}
+		}
+		logutil.RegisterSecret(ctx.String(f.Name))
+	}
+	return nil
+}
+
+func secretFromEnv(envVars []string) (string, error) {
+	for _, name := range envVars {
+		if v := os.Getenv(name); v != "" {
+			return v, nil
+		}
+		if path := os.Getenv(name + "_FILE"); path != "" {
+			// #nosec G304
+			enc, err := ioutil.ReadFile(path)
+			if err != nil {
+				return "", errors.Wrapf(err, "could not read file from %s_FILE", name)
+			}
+			return strings.TrimRight(string(enc), "\r\n"), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/logutil"
+	"github.com/urfave/cli/v2"
+)
+
+func TestLoadSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	fromFile := &cli.StringFlag{Name: "test-secret-file-flag"}
+	fromEnv := &cli.StringFlag{Name: "test-secret-env-flag"}
+	fromCLI := &cli.StringFlag{Name: "test-secret-cli-flag"}
+	unset := &cli.StringFlag{Name: "test-secret-unset-flag", Value: "http://localhost:8545"}
+
+	secretFile := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(secretFile, []byte("secret-from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	envs := map[string]string{
+		SecretEnvVar(fromFile.Name) + "_FILE": secretFile,
+		SecretEnvVar(fromEnv.Name):            "secret-from-env",
+		SecretEnvVar(fromCLI.Name):            "ignored-secret",
+	}
+	for k, v := range envs {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		for k := range envs {
+			if err := os.Unsetenv(k); err != nil {
+				t.Log(err)
+			}
+		}
+	}()
+
+	set := flag.NewFlagSet("test", 0)
+	set.String(fromFile.Name, "", "")
+	set.String(fromEnv.Name, "", "")
+	set.String(fromCLI.Name, "", "")
+	set.String(unset.Name, unset.Value, "")
+	if err := set.Parse([]string{"--" + fromCLI.Name, "secret-from-cli"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := cli.NewContext(&cli.App{}, set, nil)
+	if err := LoadSecrets(ctx, []*cli.StringFlag{fromFile, fromEnv, fromCLI, unset}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		fromFile.Name: "secret-from-file",
+		fromEnv.Name:  "secret-from-env",
+		fromCLI.Name:  "secret-from-cli",
+	}
+	for name, value := range want {
+		if got := ctx.String(name); got != value {
+			t.Errorf("Expected --%s to be %q, received %q", name, value, got)
+		}
+		if strings.Contains(logutil.Redact("value "+value), value) {
+			t.Errorf("Expected %q to be registered for redaction", value)
+		}
+	}
+	if redacted := logutil.Redact("value " + unset.Value); redacted != "value "+unset.Value {
+		t.Errorf("Expected the default value of an unset flag not to be redacted, received %q", redacted)
+	}
+}
+
+func TestSecretEnvVar(t *testing.T) {
+	if v := SecretEnvVar("http-web3provider"); v != "PRYSM_HTTP_WEB3PROVIDER" {
+		t.Errorf("Unexpected environment variable %s", v)
+	}
+}
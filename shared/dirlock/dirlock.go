@@ -0,0 +1,42 @@
+// Package dirlock takes advisory locks on data directories, so that two processes never
+// use the same database at once. Running a second beacon node or validator client on the
+// same data directory by accident could otherwise corrupt the database or, for a
+// validator client, sign conflicting messages.
+package dirlock
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/tsdb/fileutil"
+)
+
+const (
+	// BeaconLockFileName is the name of the lock file a beacon node takes in its data directory.
+	BeaconLockFileName = "beacon.lock"
+	// ValidatorLockFileName is the name of the lock file a validator client takes in its data directory.
+	ValidatorLockFileName = "validator.lock"
+)
+
+// Releaser releases a lock taken with Acquire.
+type Releaser = fileutil.Releaser
+
+// Acquire takes the lock file with the given name in dir, creating the directory if it
+// does not exist yet. It fails right away if another process holds the lock. The lock is
+// released by the operating system if the process exits without releasing it.
+func Acquire(dir string, name string) (Releaser, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "could not create data directory %s", dir)
+	}
+	lockPath := filepath.Join(dir, name)
+	releaser, _, err := fileutil.Flock(lockPath)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"could not lock %s, the data directory is in use by another process, stop it or use a different --datadir",
+			lockPath,
+		)
+	}
+	return releaser, nil
+}
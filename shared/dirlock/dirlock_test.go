@@ -0,0 +1,49 @@
+package dirlock
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquire_FailsWhileLocked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dirlock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	dataDir := filepath.Join(dir, "datadir")
+
+	lock, err := Acquire(dataDir, BeaconLockFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Acquire(dataDir, BeaconLockFileName); err == nil {
+		t.Fatal("Expected second lock of the data directory to fail")
+	}
+
+	// A different process type locks its own file.
+	validatorLock, err := Acquire(dataDir, ValidatorLockFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validatorLock.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	lock, err = Acquire(dataDir, BeaconLockFileName)
+	if err != nil {
+		t.Fatalf("Could not lock data directory after release: %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+}
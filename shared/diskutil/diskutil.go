@@ -0,0 +1,8 @@
+// Package diskutil reports the free disk space of the file system holding a path.
+package diskutil
+
+// AvailableBytes returns the number of bytes available to unprivileged users on the file
+// system holding path.
+func AvailableBytes(path string) (uint64, error) {
+	return availableBytes(path)
+}
@@ -0,0 +1,19 @@
+package diskutil
+
+import (
+	"os"
+	"testing"
+)
+
+func TestAvailableBytes(t *testing.T) {
+	available, err := AvailableBytes(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available == 0 {
+		t.Error("Expected free space in the temporary directory")
+	}
+	if _, err := AvailableBytes("/does/not/exist"); err == nil {
+		t.Error("Expected error for a missing path")
+	}
+}
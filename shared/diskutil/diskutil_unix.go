@@ -0,0 +1,17 @@
+// +build !windows
+
+package diskutil
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func availableBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, errors.Wrapf(err, "could not get file system statistics of %s", path)
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
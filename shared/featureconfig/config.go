@@ -20,6 +20,8 @@ The process for implementing new features using this package is as follows:
 package featureconfig
 
 import (
+	"sync"
+
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
@@ -27,6 +29,12 @@ import (
 
 var log = logrus.WithField("prefix", "flags")
 
+// BLS libraries which can be selected with the --bls-implementation flag.
+const (
+	HerumiBLS = "herumi"
+	BlstBLS   = "blst"
+)
+
 // Flags is a struct to represent which features the client will perform on runtime.
 type Flags struct {
 	// Configuration related flags.
@@ -56,9 +64,13 @@ type Flags struct {
 	NoInitSyncBatchSaveBlocks                  bool // NoInitSyncBatchSaveBlocks disables batch save blocks mode during initial syncing.
 	EnableStateRefCopy                         bool // EnableStateRefCopy copies the references to objects instead of the objects themselves when copying state fields.
 	WaitForSynced                              bool // WaitForSynced uses WaitForSynced in validator startup to ensure it can communicate with the beacon node as soon as possible.
+	EnableStreamDuties                         bool // EnableStreamDuties makes the validator client receive its duties over a stream pushed by the beacon node instead of polling for them every epoch.
 	SkipRegenHistoricalStates                  bool // SkipRegenHistoricalState skips regenerating historical states from genesis to last finalized. This enables a quick switch over to using new-state-mgmt.
 	EnableInitSyncWeightedRoundRobin           bool // EnableInitSyncWeightedRoundRobin enables weighted round robin fetching optimization in initial syncing.
 	ReduceAttesterStateCopy                    bool // ReduceAttesterStateCopy reduces head state copies for attester rpc.
+	EnableRPCSlashingProtection                bool // EnableRPCSlashingProtection refuses to broadcast slashable blocks and attestations submitted over RPC.
+	EnableAttestationTracing                   bool // EnableAttestationTracing traces attestations from production to block inclusion, recording why they were dropped.
+	EnableP2PMessageTracing                    bool // EnableP2PMessageTracing records the latest gossip and req/resp messages exchanged with peers.
 
 	// DisableForkChoice disables using LMD-GHOST fork choice to update
 	// the head of the chain based on attestations and instead accepts any valid received block
@@ -77,13 +89,24 @@ type Flags struct {
 	EnableBlockTreeCache    bool // EnableBlockTreeCache enable fork choice service to maintain latest filtered block tree.
 
 	KafkaBootstrapServers string // KafkaBootstrapServers to find kafka servers to stream blocks, attestations, etc.
+	BLSImplementation     string // BLSImplementation is the BLS library used for keys and signatures, HerumiBLS or BlstBLS.
 	CustomGenesisDelay    uint64 // CustomGenesisDelay signals how long of a delay to set to start the chain.
+	P2PMessageTraceFile   string // P2PMessageTraceFile is the file traced p2p messages are appended to, if set.
+
+	// EnabledFlags lists the names of the feature flags set when the client was configured.
+	EnabledFlags []string
 }
 
 var featureConfig *Flags
 
+// featureConfigLock guards the featureConfig pointer, which is swapped when a feature is
+// toggled at runtime. The config it points to is never mutated.
+var featureConfigLock sync.RWMutex
+
 // Get retrieves feature config.
 func Get() *Flags {
+	featureConfigLock.RLock()
+	defer featureConfigLock.RUnlock()
 	if featureConfig == nil {
 		return &Flags{}
 	}
@@ -92,7 +115,10 @@ func Get() *Flags {
 
 // Init sets the global config equal to the config that is passed in.
 func Init(c *Flags) {
+	featureConfigLock.Lock()
 	featureConfig = c
+	featureConfigLock.Unlock()
+	updateFeatureMetrics(c)
 }
 
 // InitWithReset sets the global config and returns function that is used to reset configuration.
@@ -220,6 +246,20 @@ func ConfigureBeaconChain(ctx *cli.Context) {
 		log.Warn("Enabling feature that reduces attester state copy")
 		cfg.ReduceAttesterStateCopy = true
 	}
+	if ctx.Bool(enableRPCSlashingProtectionFlag.Name) {
+		log.Warn("Enabling slashing protection of blocks and attestations submitted over RPC")
+		cfg.EnableRPCSlashingProtection = true
+	}
+	if ctx.Bool(enableAttestationTracingFlag.Name) {
+		log.Warn("Enabling tracing of attestations from production to block inclusion")
+		cfg.EnableAttestationTracing = true
+	}
+	if ctx.Bool(enableP2PMessageTracingFlag.Name) {
+		log.Warn("Enabling tracing of p2p messages")
+		cfg.EnableP2PMessageTracing = true
+		cfg.P2PMessageTraceFile = ctx.String(p2pMessageTraceFileFlag.Name)
+	}
+	cfg.EnabledFlags = enabledFlagNames(ctx, BeaconChainFlags)
 	Init(cfg)
 }
 
@@ -237,6 +277,7 @@ func ConfigureSlasher(ctx *cli.Context) {
 		log.Warn("Disabling slasher lookback")
 		cfg.DisableLookback = true
 	}
+	cfg.EnabledFlags = enabledFlagNames(ctx, SlasherFlags)
 	Init(cfg)
 }
 
@@ -263,6 +304,11 @@ func ConfigureValidator(ctx *cli.Context) {
 		log.Warn("Disabled domain data cache.")
 		cfg.EnableDomainDataCache = false
 	}
+	if ctx.Bool(enableStreamDutiesFlag.Name) {
+		log.Warn("Enabled streaming of validator duties from the beacon node.")
+		cfg.EnableStreamDuties = true
+	}
+	cfg.EnabledFlags = enabledFlagNames(ctx, ValidatorFlags)
 	Init(cfg)
 }
 
@@ -296,5 +342,29 @@ func configureConfig(ctx *cli.Context, cfg *Flags) *Flags {
 		cfg.MinimalConfig = true
 		params.UseE2EConfig()
 	}
+	if ctx.IsSet(blsImplementationFlag.Name) {
+		impl := ctx.String(blsImplementationFlag.Name)
+		if impl != HerumiBLS && impl != BlstBLS {
+			log.Fatalf("Unknown BLS implementation %q, must be %s or %s", impl, HerumiBLS, BlstBLS)
+		}
+		log.Warnf("Using the %s BLS implementation", impl)
+		cfg.BLSImplementation = impl
+	}
 	return cfg
 }
+
+// enabledFlagNames returns the names of the flags which are set, skipping deprecated flags.
+func enabledFlagNames(ctx *cli.Context, flags []cli.Flag) []string {
+	deprecated := make(map[string]bool, len(deprecatedFlags))
+	for _, f := range deprecatedFlags {
+		deprecated[f.Names()[0]] = true
+	}
+	var names []string
+	for _, f := range flags {
+		name := f.Names()[0]
+		if !deprecated[name] && ctx.IsSet(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -27,3 +27,32 @@ func TestConfigureBeaconConfig(t *testing.T) {
 		t.Errorf("MinimalConfig in FeatureFlags incorrect. Wanted true, got false")
 	}
 }
+
+func TestConfigureBeaconConfig_EnabledFlags(t *testing.T) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.Bool(minimalConfigFlag.Name, false, "test")
+	set.Bool(enableNewStateMgmt.Name, false, "test")
+	if err := set.Set(minimalConfigFlag.Name, "true"); err != nil {
+		t.Fatal(err)
+	}
+	context := cli.NewContext(&app, set, nil)
+	ConfigureBeaconChain(context)
+	if c := Get(); len(c.EnabledFlags) != 1 || c.EnabledFlags[0] != minimalConfigFlag.Name {
+		t.Errorf("Wanted enabled flags %v, received %v", []string{minimalConfigFlag.Name}, c.EnabledFlags)
+	}
+}
+
+func TestConfigureValidator_BLSImplementation(t *testing.T) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String(blsImplementationFlag.Name, "", "test")
+	if err := set.Set(blsImplementationFlag.Name, BlstBLS); err != nil {
+		t.Fatal(err)
+	}
+	context := cli.NewContext(&app, set, nil)
+	ConfigureValidator(context)
+	if c := Get(); c.BLSImplementation != BlstBLS {
+		t.Errorf("BLSImplementation in FeatureFlags incorrect. Wanted %s, got %s", BlstBLS, c.BLSImplementation)
+	}
+}
@@ -45,6 +45,11 @@ var (
 		Name:  "skip-bls-verify",
 		Usage: "Whether or not to skip BLS verification of signature at runtime, this is unsafe and should only be used for development",
 	}
+	blsImplementationFlag = &cli.StringFlag{
+		Name: "bls-implementation",
+		Usage: "BLS library used to sign and verify signatures, herumi or blst. Defaults to blst in builds " +
+			"with the blst_enabled tag and to herumi otherwise.",
+	}
 	enableBackupWebhookFlag = &cli.BoolFlag{
 		Name:  "enable-db-backup-webhook",
 		Usage: "Serve HTTP handler to initiate database backups. The handler is served on the monitoring port at path /db/backup.",
@@ -141,6 +146,10 @@ var (
 		Name:  "wait-for-synced",
 		Usage: "Uses WaitForSynced for validator startup, to ensure a validator is able to communicate with the beacon node as quick as possible",
 	}
+	enableStreamDutiesFlag = &cli.BoolFlag{
+		Name:  "enable-stream-duties",
+		Usage: "Receives validator duties pushed by the beacon node at every epoch and after reorgs, instead of polling for them every epoch",
+	}
 	enableHistoricalDetectionFlag = &cli.BoolFlag{
 		Name:  "enable-historical-detection",
 		Usage: "Enables historical attestation detection for the slasher",
@@ -161,6 +170,27 @@ var (
 		Name:  "reduce-attester-state-copy",
 		Usage: "Reduces the amount of state copies for attester rpc",
 	}
+	enableRPCSlashingProtectionFlag = &cli.BoolFlag{
+		Name: "enable-rpc-slashing-protection",
+		Usage: "Refuses to broadcast blocks and attestations submitted over RPC which are slashable with respect to " +
+			"earlier submissions of the same validator, as a second line of defense behind the slashing protection " +
+			"of the validator client. Submissions are remembered in memory back to the finalized checkpoint",
+	}
+	enableAttestationTracingFlag = &cli.BoolFlag{
+		Name: "enable-attestation-tracing",
+		Usage: "Traces attestations from their production through gossip, aggregation and block inclusion, " +
+			"recording why they were dropped, and serves the traces by validator index and slot under " +
+			"/prysm/v1/debug/attestations of the HTTP API",
+	}
+	enableP2PMessageTracingFlag = &cli.BoolFlag{
+		Name: "enable-p2p-message-tracing",
+		Usage: "Records the latest gossip and req/resp messages exchanged with peers, with their topic, peer, size, " +
+			"decoded type and validation result, and serves them under /prysm/v1/debug/p2p/messages of the HTTP API",
+	}
+	p2pMessageTraceFileFlag = &cli.StringFlag{
+		Name:  "p2p-message-trace-file",
+		Usage: "Appends the messages recorded with --enable-p2p-message-tracing to this file as JSON lines",
+	}
 )
 
 // devModeFlags holds list of flags that are set when development mode is on.
@@ -224,11 +254,6 @@ var (
 		Usage:  deprecatedUsage,
 		Hidden: true,
 	}
-	deprecatedEnablePruneBoundaryStateFlag = &cli.BoolFlag{
-		Name:   "prune-states",
-		Usage:  deprecatedUsage,
-		Hidden: true,
-	}
 	deprecatedEnableActiveIndicesCacheFlag = &cli.BoolFlag{
 		Name:   "enable-active-indices-cache",
 		Usage:  deprecatedUsage,
@@ -402,7 +427,6 @@ var deprecatedFlags = []cli.Flag{
 	deprecatedOptimizeProcessEpochFlag,
 	deprecatedEnableSnappyDBCompressionFlag,
 	deprecatedEnableSkipSlotsCacheFlag,
-	deprecatedEnablePruneBoundaryStateFlag,
 	deprecatedEnableActiveIndicesCacheFlag,
 	deprecatedEnableActiveCountCacheFlag,
 	deprecatedEnableCustomStateSSZFlag,
@@ -446,6 +470,8 @@ var ValidatorFlags = append(deprecatedFlags, []cli.Flag{
 	enableExternalSlasherProtectionFlag,
 	disableDomainDataCacheFlag,
 	waitForSyncedFlag,
+	enableStreamDutiesFlag,
+	blsImplementationFlag,
 }...)
 
 // SlasherFlags contains a list of all the feature flags that apply to the slasher client.
@@ -453,6 +479,7 @@ var SlasherFlags = append(deprecatedFlags, []cli.Flag{
 	e2eConfigFlag,
 	enableHistoricalDetectionFlag,
 	disableLookbackFlag,
+	blsImplementationFlag,
 }...)
 
 // E2EValidatorFlags contains a list of the validator feature flags to be tested in E2E.
@@ -474,6 +501,7 @@ var BeaconChainFlags = append(deprecatedFlags, []cli.Flag{
 	disableSSZCache,
 	initSyncVerifyEverythingFlag,
 	skipBLSVerifyFlag,
+	blsImplementationFlag,
 	kafkaBootstrapServersFlag,
 	enableBackupWebhookFlag,
 	enableSlasherFlag,
@@ -493,6 +521,10 @@ var BeaconChainFlags = append(deprecatedFlags, []cli.Flag{
 	disableFieldTrie,
 	disableStateRefCopy,
 	reduceAttesterStateCopy,
+	enableRPCSlashingProtectionFlag,
+	enableAttestationTracingFlag,
+	enableP2PMessageTracingFlag,
+	p2pMessageTraceFileFlag,
 }...)
 
 // E2EBeaconChainFlags contains a list of the beacon chain feature flags to be tested in E2E.
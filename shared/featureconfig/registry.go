@@ -0,0 +1,125 @@
+package featureconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var featureEnabledGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "feature_enabled",
+	Help: "Whether a feature of the feature config is enabled (1) or not (0).",
+}, []string{"feature", "runtime_toggle"})
+
+// runtimeFeatures are the features which do not affect consensus and are read on every use,
+// so they may be toggled while the client runs. All other features only take effect when
+// the client starts or could lead to a different view of the chain.
+var runtimeFeatures = map[string]bool{
+	"WriteSSZStateTransitions":  true,
+	"CheckHeadState":            true,
+	"EnableSSZCache":            true,
+	"EnableEth1DataVoteCache":   true,
+	"EnableDomainDataCache":     true,
+	"DisableBroadcastSlashings": true,
+	"ReduceAttesterStateCopy":   true,
+}
+
+// Feature is an entry of the feature registry.
+type Feature struct {
+	Name          string `json:"name"`
+	Enabled       bool   `json:"enabled"`
+	RuntimeToggle bool   `json:"runtime_toggle"`
+}
+
+// Features returns the registry of all boolean features of the current config, sorted by name.
+func Features() []*Feature {
+	return features(Get())
+}
+
+func features(c *Flags) []*Feature {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	var fs []*Feature
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() != reflect.Bool {
+			continue
+		}
+		name := t.Field(i).Name
+		fs = append(fs, &Feature{
+			Name:          name,
+			Enabled:       v.Field(i).Bool(),
+			RuntimeToggle: runtimeFeatures[name],
+		})
+	}
+	sort.Slice(fs, func(i, j int) bool {
+		return fs[i].Name < fs[j].Name
+	})
+	return fs
+}
+
+// SetFeature toggles a feature of the running client. Only features in the runtime toggle
+// set may be changed.
+func SetFeature(name string, enabled bool) error {
+	if !runtimeFeatures[name] {
+		return fmt.Errorf("feature %q cannot be toggled at runtime", name)
+	}
+	featureConfigLock.Lock()
+	c := &Flags{}
+	if featureConfig != nil {
+		*c = *featureConfig
+	}
+	reflect.ValueOf(c).Elem().FieldByName(name).SetBool(enabled)
+	featureConfig = c
+	featureConfigLock.Unlock()
+
+	updateFeatureMetrics(c)
+	log.WithField("feature", name).WithField("enabled", enabled).Warn("Toggled feature at runtime")
+	return nil
+}
+
+func updateFeatureMetrics(c *Flags) {
+	if c == nil {
+		c = &Flags{}
+	}
+	for _, f := range features(c) {
+		value := 0.0
+		if f.Enabled {
+			value = 1
+		}
+		featureEnabledGauge.WithLabelValues(f.Name, strconv.FormatBool(f.RuntimeToggle)).Set(value)
+	}
+}
+
+// FeaturesHandler serves the feature registry as JSON.
+func FeaturesHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(Features()); err != nil {
+		log.WithError(err).Error("Could not write features response")
+	}
+}
+
+// ToggleHandler toggles the feature given by the name query parameter to the enabled query
+// parameter, e.g. POST /features/toggle?name=EnableSSZCache&enabled=false. It must only be
+// served behind authentication.
+func ToggleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	enabled, err := strconv.ParseBool(r.URL.Query().Get("enabled"))
+	if err != nil {
+		http.Error(w, "invalid enabled parameter", http.StatusBadRequest)
+		return
+	}
+	if err := SetFeature(r.URL.Query().Get("name"), enabled); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	FeaturesHandler(w, r)
+}
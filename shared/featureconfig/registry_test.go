@@ -0,0 +1,66 @@
+package featureconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetFeature(t *testing.T) {
+	resetCfg := InitWithReset(&Flags{EnableSSZCache: true, NewStateMgmt: true})
+	defer resetCfg()
+
+	before := Get()
+	if err := SetFeature("EnableSSZCache", false); err != nil {
+		t.Fatal(err)
+	}
+	if Get().EnableSSZCache {
+		t.Error("Expected EnableSSZCache to be disabled")
+	}
+	if !Get().NewStateMgmt {
+		t.Error("Expected other features to be unchanged")
+	}
+	if !before.EnableSSZCache {
+		t.Error("Expected the previous config not to be mutated")
+	}
+	if err := SetFeature("NewStateMgmt", false); err == nil {
+		t.Error("Expected error toggling a feature which is not runtime toggleable")
+	}
+	if err := SetFeature("DoesNotExist", true); err == nil {
+		t.Error("Expected error toggling an unknown feature")
+	}
+}
+
+func TestToggleHandler(t *testing.T) {
+	resetCfg := InitWithReset(&Flags{})
+	defer resetCfg()
+
+	rec := httptest.NewRecorder()
+	ToggleHandler(rec, httptest.NewRequest(http.MethodPost, "/features/toggle?name=CheckHeadState&enabled=true", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var fs []*Feature
+	if err := json.NewDecoder(rec.Body).Decode(&fs); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, f := range fs {
+		if f.Name == "CheckHeadState" {
+			found = f.Enabled && f.RuntimeToggle
+		}
+	}
+	if !found {
+		t.Error("Expected CheckHeadState to be listed as enabled runtime toggle")
+	}
+
+	rec = httptest.NewRecorder()
+	ToggleHandler(rec, httptest.NewRequest(http.MethodGet, "/features/toggle?name=CheckHeadState&enabled=false", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Wanted status %d, received %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	if !Get().CheckHeadState {
+		t.Error("Expected feature to be unchanged by GET request")
+	}
+}
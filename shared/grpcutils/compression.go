@@ -0,0 +1,35 @@
+package grpcutils
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// LargeResponseMethods are the gRPC methods responding with whole beacon states or blocks,
+// several megabytes on mainnet, which are worth compressing.
+var LargeResponseMethods = map[string]bool{
+	"/ethereum.beacon.rpc.v1.Debug/GetBeaconState":          true,
+	"/ethereum.beacon.rpc.v1.Debug/GetBlock":                true,
+	"/ethereum.beacon.rpc.v1.DebugStream/StreamBeaconState": true,
+}
+
+// CompressLargeResponses is a client interceptor compressing the calls to the
+// LargeResponseMethods with gzip. A gRPC server compresses its responses with the compressor of
+// the request, so the large responses are compressed as well, while the other calls are not
+// slowed down by compression.
+func CompressLargeResponses(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if LargeResponseMethods[method] {
+		opts = append(opts, grpc.UseCompressor(gzip.Name))
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// CompressLargeStreamResponses is the stream counterpart of CompressLargeResponses.
+func CompressLargeStreamResponses(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if LargeResponseMethods[method] {
+		opts = append(opts, grpc.UseCompressor(gzip.Name))
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
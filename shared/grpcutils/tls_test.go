@@ -0,0 +1,143 @@
+package grpcutils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert  *x509.Certificate
+	key   *ecdsa.PrivateKey
+	cFile string
+	kFile string
+}
+
+func writeTestCert(t *testing.T, dir string, name string, parent *testCert, isCA bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	signerCert, signerKey := tmpl, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := &testCert{
+		cert:  cert,
+		key:   key,
+		cFile: filepath.Join(dir, name+".crt"),
+		kFile: filepath.Join(dir, name+".key"),
+	}
+	if err := ioutil.WriteFile(tc.cFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(tc.kFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return tc
+}
+
+func TestMutualTLSHandshake(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grpcutils")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	ca := writeTestCert(t, dir, "ca", nil, true)
+	otherCA := writeTestCert(t, dir, "other-ca", nil, true)
+	server := writeTestCert(t, dir, "server", ca, false)
+	client := writeTestCert(t, dir, "client", ca, false)
+	rogue := writeTestCert(t, dir, "rogue", otherCA, false)
+
+	serverCreds, err := ServerTLSCredentials(server.cFile, server.kFile, ca.cFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cert    string
+		key     string
+		wantErr bool
+	}{
+		{name: "trusted client certificate", cert: client.cFile, key: client.kFile},
+		{name: "no client certificate", wantErr: true},
+		{name: "untrusted client certificate", cert: rogue.cFile, key: rogue.kFile, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientCreds, err := ClientTLSCredentials(ca.cFile, tt.cert, tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			serverConn, clientConn := net.Pipe()
+			serverErr := make(chan error, 1)
+			go func() {
+				_, _, err := serverCreds.ServerHandshake(serverConn)
+				serverErr <- err
+				_ = serverConn.Close()
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, _, clientErr := clientCreds.ClientHandshake(ctx, "localhost", clientConn)
+			// Keep reading, as the server writes session tickets or alerts after the
+			// client has completed its side of the handshake.
+			go func() {
+				_, _ = io.Copy(ioutil.Discard, clientConn)
+			}()
+			err = <-serverErr
+			_ = clientConn.Close()
+			if tt.wantErr && err == nil {
+				t.Error("Expected server to reject the client")
+			}
+			if !tt.wantErr && (err != nil || clientErr != nil) {
+				t.Errorf("Expected handshake to succeed, server error %v, client error %v", err, clientErr)
+			}
+		})
+	}
+}
+
+func TestClientTLSCredentials_CertWithoutKey(t *testing.T) {
+	if _, err := ClientTLSCredentials("", "client.crt", ""); err == nil {
+		t.Error("Expected error for certificate without key")
+	}
+}
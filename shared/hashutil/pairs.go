@@ -0,0 +1,70 @@
+package hashutil
+
+import (
+	"runtime"
+	"sync"
+
+	"golang.org/x/sys/cpu"
+)
+
+// minParallelPairs is the number of pairs from which a layer is hashed with a multi-buffer
+// implementation or by several workers. Smaller layers are not worth the scheduling.
+const minParallelPairs = 1 << 10
+
+// multiBufferHashPairs hashes the pairs of a layer with a multi-buffer sha256 implementation
+// hashing several messages at once. It is nil if the CPU does not support one.
+var multiBufferHashPairs func(dst [][32]byte, chunks [][32]byte)
+
+func init() {
+	if avx512Supported && cpu.X86.HasAVX512F && cpu.X86.HasAVX512DQ && cpu.X86.HasAVX512BW && cpu.X86.HasAVX512VL {
+		multiBufferHashPairs = avx512HashPairs
+	}
+}
+
+// HashPairs hashes the concatenation of each pair of consecutive 32 byte chunks into dst, which
+// computes a layer of a merkle tree from the layer below it. The number of chunks must be even
+// and dst must hold half as many elements.
+//
+// Large layers are hashed with the multi-buffer AVX512 implementation if the CPU supports it,
+// or split among the available cores otherwise. The single buffer implementation already uses
+// the SHA extensions or AVX2 where available.
+func HashPairs(dst [][32]byte, chunks [][32]byte) {
+	if len(dst) < minParallelPairs {
+		hashPairs(dst, chunks)
+		return
+	}
+	if multiBufferHashPairs != nil {
+		multiBufferHashPairs(dst, chunks)
+		return
+	}
+	workers := runtime.GOMAXPROCS(0)
+	if workers == 1 {
+		hashPairs(dst, chunks)
+		return
+	}
+	perWorker := (len(dst) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(dst); start += perWorker {
+		end := start + perWorker
+		if end > len(dst) {
+			end = len(dst)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			hashPairs(dst[start:end], chunks[2*start:2*end])
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// hashPairs hashes the pairs one after the other.
+func hashPairs(dst [][32]byte, chunks [][32]byte) {
+	hasher := CustomSHA256Hasher()
+	var buf [64]byte
+	for i := range dst {
+		copy(buf[:32], chunks[2*i][:])
+		copy(buf[32:], chunks[2*i+1][:])
+		dst[i] = hasher(buf[:])
+	}
+}
@@ -0,0 +1,56 @@
+// +build !noasm,!appengine
+
+package hashutil
+
+import (
+	"hash"
+	"sync"
+
+	"github.com/minio/sha256-simd"
+)
+
+// avx512Supported reports whether the AVX512 implementation is compiled in.
+const avx512Supported = true
+
+// avx512Lanes is the number of messages the AVX512 implementation hashes at once.
+const avx512Lanes = 16
+
+var (
+	avx512Lock    sync.Mutex
+	avx512Digests []hash.Hash
+)
+
+// avx512HashPairs hashes the pairs with one digest per lane of the AVX512 server, each fed by
+// its own goroutine, so the server hashes full batches of 16 messages. The digests are created
+// once with consecutive ids, which the server maps to distinct lanes, and layers are hashed one
+// at a time.
+func avx512HashPairs(dst [][32]byte, chunks [][32]byte) {
+	avx512Lock.Lock()
+	defer avx512Lock.Unlock()
+	if avx512Digests == nil {
+		server := sha256.NewAvx512Server()
+		avx512Digests = make([]hash.Hash, avx512Lanes)
+		for i := range avx512Digests {
+			avx512Digests[i] = sha256.NewAvx512(server)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(avx512Lanes)
+	for lane := 0; lane < avx512Lanes; lane++ {
+		go func(lane int) {
+			defer wg.Done()
+			h := avx512Digests[lane]
+			var buf [64]byte
+			for i := lane; i < len(dst); i += avx512Lanes {
+				copy(buf[:32], chunks[2*i][:])
+				copy(buf[32:], chunks[2*i+1][:])
+				h.Reset()
+				// #nosec G104
+				h.Write(buf[:])
+				h.Sum(dst[i][:0])
+			}
+		}(lane)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,10 @@
+// +build !amd64 noasm appengine
+
+package hashutil
+
+// avx512Supported reports whether the AVX512 implementation is compiled in.
+const avx512Supported = false
+
+func avx512HashPairs(dst [][32]byte, chunks [][32]byte) {
+	hashPairs(dst, chunks)
+}
@@ -0,0 +1,54 @@
+package hashutil
+
+import (
+	"testing"
+)
+
+func pairsFixture(n int) ([][32]byte, [][32]byte) {
+	chunks := make([][32]byte, 2*n)
+	for i := range chunks {
+		chunks[i][0] = byte(i)
+		chunks[i][1] = byte(i >> 8)
+	}
+	want := make([][32]byte, n)
+	for i := range want {
+		want[i] = Hash(append(chunks[2*i][:], chunks[2*i+1][:]...))
+	}
+	return chunks, want
+}
+
+func TestHashPairs(t *testing.T) {
+	for _, n := range []int{1, 7, minParallelPairs, 3*minParallelPairs + 5} {
+		chunks, want := pairsFixture(n)
+		got := make([][32]byte, n)
+		HashPairs(got, chunks)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("Hashing %d pairs: wanted %#x at %d, received %#x", n, want[i], i, got[i])
+			}
+		}
+	}
+}
+
+func TestHashPairs_MultiBuffer(t *testing.T) {
+	if multiBufferHashPairs == nil {
+		t.Skip("CPU does not support multi-buffer hashing")
+	}
+	chunks, want := pairsFixture(minParallelPairs + 3)
+	got := make([][32]byte, len(want))
+	multiBufferHashPairs(got, chunks)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Wanted %#x at %d, received %#x", want[i], i, got[i])
+		}
+	}
+}
+
+func BenchmarkHashPairs(b *testing.B) {
+	chunks, want := pairsFixture(1 << 16)
+	got := make([][32]byte, len(want))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HashPairs(got, chunks)
+	}
+}
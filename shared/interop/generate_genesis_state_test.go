@@ -50,3 +50,28 @@ func TestGenerateGenesisState(t *testing.T) {
 		t.Errorf("Wanted genesis time 0, received %d", genesisState.GenesisTime())
 	}
 }
+
+func TestGenerateGenesisStateFromDepositData(t *testing.T) {
+	numValidators := uint64(16)
+	privKeys, pubKeys, err := interop.DeterministicallyGenerateKeys(0 /*startIndex*/, numValidators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	depositDataItems, depositDataRoots, err := interop.DepositDataFromKeys(privKeys, pubKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisState, deposits, err := interop.GenerateGenesisStateFromDepositData(100, depositDataItems, depositDataRoots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != int(numValidators) {
+		t.Errorf("Wanted %d deposits, received %d", numValidators, len(deposits))
+	}
+	if len(genesisState.Validators) != int(numValidators) {
+		t.Errorf("Wanted %d validators, received %d", numValidators, len(genesisState.Validators))
+	}
+	if genesisState.GenesisTime != 100 {
+		t.Errorf("Wanted genesis time 100, received %d", genesisState.GenesisTime)
+	}
+}
@@ -0,0 +1,82 @@
+package logutil
+
+import (
+	"fmt"
+
+	joonix "github.com/joonix/log"
+	"github.com/sirupsen/logrus"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+)
+
+// fieldAliases maps field names used across the code base to the consistent names of the
+// json output, so log pipelines can index the slot, root and peer of any entry alike.
+var fieldAliases = map[string]string{
+	"blockSlot": "slot",
+	"blockRoot": "root",
+	"peer":      "peerID",
+}
+
+// ConfigureFormatter sets the formatter of the standard logger for the given format, either
+// text, json or fluentd. Colors of the text format are disabled when logs are written to a
+// file, as the ANSI codes are seen as gibberish in the log files. The formatter drops the
+// entries above the level of their module, see SetVerbosity.
+func ConfigureFormatter(format string, disableColors bool) error {
+	var f logrus.Formatter
+	normalize := false
+	switch format {
+	case "text":
+		formatter := new(prefixed.TextFormatter)
+		formatter.TimestampFormat = "2006-01-02 15:04:05"
+		formatter.FullTimestamp = true
+		formatter.DisableColors = disableColors
+		f = formatter
+	case "fluentd":
+		formatter := joonix.NewFormatter()
+		if err := joonix.DisableTimestampFormat(formatter); err != nil {
+			return err
+		}
+		f = formatter
+	case "json":
+		f = &logrus.JSONFormatter{}
+		normalize = true
+	default:
+		return fmt.Errorf("unknown log format %s", format)
+	}
+	logrus.SetFormatter(&moduleFormatter{formatter: f, normalize: normalize})
+	return nil
+}
+
+// moduleFormatter drops the entries above the level of their module and, if normalize is set,
+// renames aliased fields before passing entries on to the wrapped formatter.
+type moduleFormatter struct {
+	formatter logrus.Formatter
+	normalize bool
+}
+
+// Format the entry, returning no output for entries which are filtered out.
+func (f *moduleFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if !enabled(entry) {
+		return nil, nil
+	}
+	if !f.normalize {
+		return f.formatter.Format(entry)
+	}
+	// The fields may be shared with the entry of a package logger, so they are copied.
+	data := make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	for from, to := range fieldAliases {
+		v, ok := data[from]
+		if !ok {
+			continue
+		}
+		if _, exists := data[to]; !exists {
+			delete(data, from)
+			data[to] = v
+		}
+	}
+	normalized := *entry
+	normalized.Data = data
+	return f.formatter.Format(&normalized)
+}
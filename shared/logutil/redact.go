@@ -0,0 +1,89 @@
+package logutil
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redacted replaces secret values in log output.
+const Redacted = "[REDACTED]"
+
+// minSecretLength avoids redacting short values, such as single characters, which
+// would mangle unrelated log output.
+const minSecretLength = 4
+
+var secrets = struct {
+	sync.RWMutex
+	values []string
+}{}
+
+// RegisterSecret adds a value which must never appear in log output, such as a
+// password, API token or an endpoint URL containing credentials.
+func RegisterSecret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	secrets.Lock()
+	defer secrets.Unlock()
+	for _, v := range secrets.values {
+		if v == value {
+			return
+		}
+	}
+	secrets.values = append(secrets.values, value)
+}
+
+// Redact replaces all registered secrets in s.
+func Redact(s string) string {
+	secrets.RLock()
+	defer secrets.RUnlock()
+	for _, v := range secrets.values {
+		if strings.Contains(s, v) {
+			s = strings.Replace(s, v, Redacted, -1)
+		}
+	}
+	return s
+}
+
+// RedactionHook is a logrus hook removing registered secrets from the message and
+// fields of log entries before they are written.
+type RedactionHook struct{}
+
+// Levels of the hook, all of them.
+func (h *RedactionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redacts the entry.
+func (h *RedactionHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Redact(entry.Message)
+	var data logrus.Fields
+	for k, v := range entry.Data {
+		var s string
+		switch v := v.(type) {
+		case string:
+			s = v
+		case error:
+			s = v.Error()
+		default:
+			continue
+		}
+		if r := Redact(s); r != s {
+			// The data map may be shared with the logger the entry was created from,
+			// so it is copied before modification.
+			if data == nil {
+				data = make(logrus.Fields, len(entry.Data))
+				for dk, dv := range entry.Data {
+					data[dk] = dv
+				}
+			}
+			data[k] = r
+		}
+	}
+	if data != nil {
+		entry.Data = data
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package logutil
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedactionHook(t *testing.T) {
+	RegisterSecret("https://mainnet.infura.io/v3/projectsecret")
+	RegisterSecret("hunter2hunter2")
+	RegisterSecret("abc")
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.AddHook(&RedactionHook{})
+	base := logger.WithField("endpoint", "https://mainnet.infura.io/v3/projectsecret")
+
+	base.WithError(errors.New("wrong password hunter2hunter2")).Error("Could not connect to https://mainnet.infura.io/v3/projectsecret")
+	out := buf.String()
+	for _, secret := range []string{"projectsecret", "hunter2hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Secret %q found in log output %q", secret, out)
+		}
+	}
+	if !strings.Contains(out, Redacted) {
+		t.Errorf("Expected redacted marker in log output %q", out)
+	}
+	if base.Data["endpoint"] != "https://mainnet.infura.io/v3/projectsecret" {
+		t.Error("Expected the fields of the parent entry to be left untouched")
+	}
+	if r := Redact("abc"); r != "abc" {
+		t.Errorf("Expected short values not to be registered, received %q", r)
+	}
+}
@@ -0,0 +1,118 @@
+package logutil
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// levels holds the verbosity of the modules, which are identified by the prefix field of
+// their loggers, and the base verbosity of everything else.
+var levels = struct {
+	sync.RWMutex
+	base    logrus.Level
+	modules map[string]logrus.Level
+}{
+	base:    logrus.InfoLevel,
+	modules: map[string]logrus.Level{},
+}
+
+// SetVerbosity sets the base log level from verbosity and the levels of individual modules
+// from vmodule, a comma separated list of module=level pairs such as "sync=debug,p2p=warn".
+// The level of the standard logger is set to the most verbose of them, and entries above the
+// level of their module are dropped by the formatter set with ConfigureFormatter and by the
+// hooks wrapped with ModuleLevelHook.
+func SetVerbosity(verbosity string, vmodule string) error {
+	base, err := logrus.ParseLevel(verbosity)
+	if err != nil {
+		return err
+	}
+	modules, err := ParseModuleLevels(vmodule)
+	if err != nil {
+		return err
+	}
+	levels.Lock()
+	levels.base = base
+	levels.modules = modules
+	levels.Unlock()
+	applyLevels()
+	return nil
+}
+
+// SetBaseLevel changes the base log level at runtime, keeping the levels of the modules.
+func SetBaseLevel(level logrus.Level) {
+	levels.Lock()
+	levels.base = level
+	levels.Unlock()
+	applyLevels()
+}
+
+// ParseModuleLevels parses a comma separated list of module=level pairs.
+func ParseModuleLevels(vmodule string) (map[string]logrus.Level, error) {
+	modules := make(map[string]logrus.Level)
+	for _, pair := range strings.Split(vmodule, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid module log level %q, expected module=level", pair)
+		}
+		level, err := logrus.ParseLevel(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid level of module %s: %v", parts[0], err)
+		}
+		modules[strings.TrimSpace(parts[0])] = level
+	}
+	return modules, nil
+}
+
+// applyLevels sets the level of the standard logger to the most verbose configured level, so
+// entries of verbose modules reach the formatter.
+func applyLevels() {
+	levels.RLock()
+	defer levels.RUnlock()
+	max := levels.base
+	for _, l := range levels.modules {
+		if l > max {
+			max = l
+		}
+	}
+	logrus.SetLevel(max)
+}
+
+// enabled reports whether an entry is within the level of its module.
+func enabled(entry *logrus.Entry) bool {
+	levels.RLock()
+	defer levels.RUnlock()
+	level := levels.base
+	if prefix, ok := entry.Data["prefix"].(string); ok {
+		if l, ok := levels.modules[prefix]; ok {
+			level = l
+		}
+	}
+	return entry.Level <= level
+}
+
+// ModuleLevelHook wraps a hook so that it only fires for the entries within the level of their
+// module. The standard logger passes the entries of every module up to the most verbose level
+// to its hooks, while the formatter drops the ones filtered out, so hooks which count or ship
+// entries must be wrapped to see the same entries as the log output.
+func ModuleLevelHook(hook logrus.Hook) logrus.Hook {
+	return &moduleLevelHook{Hook: hook}
+}
+
+type moduleLevelHook struct {
+	logrus.Hook
+}
+
+// Fire the wrapped hook if the entry is within the level of its module.
+func (h *moduleLevelHook) Fire(entry *logrus.Entry) error {
+	if !enabled(entry) {
+		return nil
+	}
+	return h.Hook.Fire(entry)
+}
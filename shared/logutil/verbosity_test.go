@@ -0,0 +1,104 @@
+package logutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestParseModuleLevels(t *testing.T) {
+	modules, err := ParseModuleLevels(" sync=debug, p2p=warn,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 2 || modules["sync"] != logrus.DebugLevel || modules["p2p"] != logrus.WarnLevel {
+		t.Errorf("Unexpected module levels %v", modules)
+	}
+	for _, vmodule := range []string{"sync", "=debug", "sync=loud"} {
+		if _, err := ParseModuleLevels(vmodule); err == nil {
+			t.Errorf("Expected %q to fail", vmodule)
+		}
+	}
+}
+
+func TestModuleFormatter_FiltersAndNormalizes(t *testing.T) {
+	defer func() {
+		if err := SetVerbosity("info", ""); err != nil {
+			t.Fatal(err)
+		}
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}()
+	if err := SetVerbosity("info", "sync=debug"); err != nil {
+		t.Fatal(err)
+	}
+	if logrus.GetLevel() != logrus.DebugLevel {
+		t.Errorf("Expected the standard logger at the most verbose level, got %s", logrus.GetLevel())
+	}
+	if err := ConfigureFormatter("json", true); err != nil {
+		t.Fatal(err)
+	}
+	f := logrus.StandardLogger().Formatter
+
+	p2pEntry := logrus.WithField("prefix", "p2p")
+	p2pEntry.Level = logrus.DebugLevel
+	out, err := f.Format(p2pEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Errorf("Expected debug entry of p2p to be dropped, got %s", out)
+	}
+
+	syncEntry := logrus.WithFields(logrus.Fields{"prefix": "sync", "blockSlot": 5})
+	syncEntry.Level = logrus.DebugLevel
+	out, err = f.Format(syncEntry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(bytes.TrimSpace(out), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fields["blockSlot"]; ok {
+		t.Error("Expected blockSlot to be renamed")
+	}
+	if fields["slot"] != float64(5) {
+		t.Errorf("Expected slot 5, got %v", fields["slot"])
+	}
+	if _, ok := syncEntry.Data["blockSlot"]; !ok {
+		t.Error("Expected the fields of the entry to be left untouched")
+	}
+	if err := ConfigureFormatter("xml", true); err == nil {
+		t.Error("Expected unknown format to fail")
+	}
+}
+
+func TestModuleLevelHook(t *testing.T) {
+	defer func() {
+		if err := SetVerbosity("info", ""); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := SetVerbosity("warn", "sync=debug"); err != nil {
+		t.Fatal(err)
+	}
+	hook := &test.Hook{}
+	logger := logrus.New()
+	logger.SetLevel(logrus.GetLevel())
+	logger.AddHook(ModuleLevelHook(hook))
+
+	logger.WithField("prefix", "p2p").Info("dropped")
+	logger.WithField("prefix", "sync").Debug("kept")
+	logger.WithField("prefix", "p2p").Warn("kept")
+	if len(hook.Entries) != 2 {
+		t.Fatalf("Wanted 2 entries within the levels of their modules, received %d", len(hook.Entries))
+	}
+	for _, entry := range hook.Entries {
+		if entry.Message != "kept" {
+			t.Errorf("Unexpected entry %q of %v passed to the hook", entry.Message, entry.Data["prefix"])
+		}
+	}
+}
@@ -28,10 +28,10 @@ func Scatter(inputLen int, sFunc func(int, int, *sync.RWMutex) (interface{}, err
 	if inputLen%chunkSize != 0 {
 		workers++
 	}
+	// The channels are not closed, as workers may still send to them after the first error is
+	// returned. They are buffered so that such workers do not block.
 	resultCh := make(chan *WorkerResults, workers)
-	defer close(resultCh)
 	errorCh := make(chan error, workers)
-	defer close(errorCh)
 	mutex := new(sync.RWMutex)
 	for worker := 0; worker < workers; worker++ {
 		offset := worker * chunkSize
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/prysmaticlabs/prysm/shared/mputil"
 )
@@ -116,3 +117,20 @@ func TestError(t *testing.T) {
 		t.Fatalf("Missing expected error")
 	}
 }
+
+func TestError_WorkersStillRunning(t *testing.T) {
+	release := make(chan struct{})
+	_, err := mputil.Scatter(1024, func(offset int, entries int, _ *sync.RWMutex) (interface{}, error) {
+		if offset == 0 {
+			return nil, errors.New("bad offset")
+		}
+		<-release
+		return nil, errors.New("late error")
+	})
+	if err == nil {
+		t.Fatal("Missing expected error")
+	}
+	// Workers failing after the error is returned must not panic.
+	close(release)
+	time.Sleep(50 * time.Millisecond)
+}
@@ -2,12 +2,15 @@
 package p2putils
 
 import (
+	"math"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 )
 
 // CreateForkDigest creates a fork digest from a genesis time and genesis
@@ -63,3 +66,68 @@ func Fork(
 		Epoch:           forkEpoch,
 	}, nil
 }
+
+// NextForkData returns the version and epoch of the next fork planned by the active config. The
+// current fork version of the given epoch is returned when no fork is planned.
+func NextForkData(currentEpoch uint64) ([]byte, uint64, error) {
+	nextForkEpoch := params.BeaconConfig().NextForkEpoch
+	nextForkVersion := params.BeaconConfig().NextForkVersion
+	// Set to the current fork version if our next fork is not planned.
+	if nextForkEpoch == math.MaxUint64 {
+		fork, err := Fork(currentEpoch)
+		if err != nil {
+			return nil, 0, err
+		}
+		nextForkVersion = fork.CurrentVersion
+	}
+	return nextForkVersion, nextForkEpoch, nil
+}
+
+// NextForkDigest returns the fork digest of the next fork planned by the active config, which
+// is the current fork digest when no fork is planned.
+func NextForkDigest(genesisTime time.Time, genesisValidatorsRoot []byte) ([4]byte, error) {
+	if len(genesisValidatorsRoot) == 0 {
+		return [4]byte{}, errors.New("genesis validators root is not set")
+	}
+	nextForkVersion, _, err := NextForkData(currentEpoch(genesisTime))
+	if err != nil {
+		return [4]byte{}, err
+	}
+	return helpers.ComputeForkDigest(nextForkVersion, genesisValidatorsRoot)
+}
+
+// CreateENRForkID creates the eth2 fork entry of a node's ENR, from the current fork digest and
+// the next fork planned by the active config.
+func CreateENRForkID(genesisTime time.Time, genesisValidatorsRoot []byte) (*pb.ENRForkID, error) {
+	digest, err := CreateForkDigest(genesisTime, genesisValidatorsRoot)
+	if err != nil {
+		return nil, err
+	}
+	nextForkVersion, nextForkEpoch, err := NextForkData(currentEpoch(genesisTime))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ENRForkID{
+		CurrentForkDigest: digest[:],
+		NextForkVersion:   nextForkVersion,
+		NextForkEpoch:     nextForkEpoch,
+	}, nil
+}
+
+// ENRForkEntry returns the ssz encoded eth2 fork entry of a node's ENR, as stored under the
+// eth2 ENR key.
+func ENRForkEntry(genesisTime time.Time, genesisValidatorsRoot []byte) ([]byte, error) {
+	enrForkID, err := CreateENRForkID(genesisTime, genesisValidatorsRoot)
+	if err != nil {
+		return nil, err
+	}
+	return ssz.Marshal(enrForkID)
+}
+
+// currentEpoch returns the epoch at the current time, or 0 before genesis.
+func currentEpoch(genesisTime time.Time) uint64 {
+	if slotutil.Now().Before(genesisTime) {
+		return 0
+	}
+	return helpers.SlotToEpoch(helpers.SlotsSince(genesisTime))
+}
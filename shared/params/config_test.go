@@ -32,3 +32,24 @@ func TestOverrideBeaconConfigTestTeardown(t *testing.T) {
 		t.Fatal("Parameter update has been leaked out of previous test")
 	}
 }
+
+func TestBeaconChainConfig_Checksum(t *testing.T) {
+	mainnet, err := params.MainnetConfig().Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := params.MainnetConfig().Copy().Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mainnet != again {
+		t.Error("Expected equal configs to have the same checksum")
+	}
+	minimal, err := params.MinimalSpecConfig().Checksum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mainnet == minimal {
+		t.Error("Expected different configs to have different checksums")
+	}
+}
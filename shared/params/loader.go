@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
@@ -12,9 +13,21 @@ import (
 // LoadChainConfigFile load, convert hex values into valid param yaml format,
 // unmarshal , and apply beacon chain config file.
 func LoadChainConfigFile(chainConfigFileName string) {
+	conf, err := ReadChainConfigFile(chainConfigFileName)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load chain config file.")
+	}
+	log.Debugf("Config file values: %+v", conf)
+	OverrideBeaconConfig(conf)
+}
+
+// ReadChainConfigFile parses a chain config file on top of the current beacon chain
+// config without applying it.
+func ReadChainConfigFile(chainConfigFileName string) (*BeaconChainConfig, error) {
+	// #nosec G304
 	yamlFile, err := ioutil.ReadFile(chainConfigFileName)
 	if err != nil {
-		log.WithError(err).Fatal("Failed to read chain config file.")
+		return nil, errors.Wrap(err, "could not read chain config file")
 	}
 	// Convert 0x hex inputs to fixed bytes arrays
 	lines := strings.Split(string(yamlFile), "\n")
@@ -25,12 +38,11 @@ func LoadChainConfigFile(chainConfigFileName string) {
 		}
 	}
 	yamlFile = []byte(strings.Join(lines, "\n"))
-	conf := BeaconConfig()
+	conf := BeaconConfig().Copy()
 	if err := yaml.Unmarshal(yamlFile, conf); err != nil {
-		log.WithError(err).Fatal("Failed to parse chain config yaml file.")
+		return nil, errors.Wrap(err, "could not parse chain config yaml file")
 	}
-	log.Debugf("Config file values: %+v", conf)
-	OverrideBeaconConfig(conf)
+	return conf, nil
 }
 
 func replaceHexStringWithYAMLFormat(line string) []string {
@@ -1,6 +1,9 @@
 package params
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
 	"path"
 	"strings"
 	"testing"
@@ -35,6 +38,72 @@ func TestLoadConfigFile(t *testing.T) {
 	}
 }
 
+func TestReadChainConfigFile_DoesNotApply(t *testing.T) {
+	SetupTestConfigCleanup(t)
+	OverrideBeaconConfig(MainnetConfig())
+	conf, err := ReadChainConfigFile(ConfigFilePath(t, "minimal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.SlotsPerEpoch != MinimalSpecConfig().SlotsPerEpoch {
+		t.Errorf("Wanted SlotsPerEpoch %d, received %d", MinimalSpecConfig().SlotsPerEpoch, conf.SlotsPerEpoch)
+	}
+	if BeaconConfig().SlotsPerEpoch != MainnetConfig().SlotsPerEpoch {
+		t.Error("Expected the current config to be unchanged")
+	}
+	if _, err := ReadChainConfigFile("/does/not/exist/config.yaml"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestReadChainConfigFile_CustomTestnet(t *testing.T) {
+	SetupTestConfigCleanup(t)
+	OverrideBeaconConfig(MainnetConfig())
+	f, err := ioutil.TempFile("", "custom_config*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := f.Name()
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content := "CONFIG_NAME: custom\n" +
+		"GENESIS_FORK_VERSION: 0x00000042\n" +
+		"DOMAIN_BEACON_PROPOSER: 0x07000000\n" +
+		"SECONDS_PER_SLOT: 3\n" +
+		"ETH1_FOLLOW_DISTANCE: 8\n" +
+		"SAFE_SLOTS_TO_UPDATE_JUSTIFIED: 4\n" +
+		"TARGET_AGGREGATORS_PER_COMMITTEE: 2\n"
+	if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.Remove(file); err != nil {
+			t.Error(err)
+		}
+	})
+
+	conf, err := ReadChainConfigFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.ConfigName != "custom" {
+		t.Errorf("Wanted config name custom, received %s", conf.ConfigName)
+	}
+	if !bytes.Equal(conf.GenesisForkVersion, []byte{0, 0, 0, 0x42}) {
+		t.Errorf("Wanted genesis fork version 0x00000042, received %#x", conf.GenesisForkVersion)
+	}
+	if conf.DomainBeaconProposer != [4]byte{7, 0, 0, 0} {
+		t.Errorf("Wanted proposer domain 0x07000000, received %#x", conf.DomainBeaconProposer)
+	}
+	if conf.SecondsPerSlot != 3 || conf.Eth1FollowDistance != 8 || conf.SafeSlotsToUpdateJustified != 4 || conf.TargetAggregatorsPerCommittee != 2 {
+		t.Errorf("Wanted time and committee parameters from the file, received %+v", conf)
+	}
+	if conf.SlotsPerEpoch != MainnetConfig().SlotsPerEpoch {
+		t.Error("Wanted parameters missing from the file to keep their current value")
+	}
+}
+
 func Test_replaceHexStringWithYAMLFormat(t *testing.T) {
 
 	testLines := []struct {
@@ -0,0 +1,63 @@
+package params
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Network is a known eth2 network, whose name determines the chain config and the connection
+// details the node uses.
+type Network struct {
+	Name string
+	// BeaconConfig returns the chain config of the network.
+	BeaconConfig func() *BeaconChainConfig
+	// BootstrapNodes are the bootnode multiaddrs and ENRs of the network.
+	BootstrapNodes []string
+	// DepositContractAddress is the eth1 deposit contract of the network, empty when the
+	// contract has to be provided, such as for local networks.
+	DepositContractAddress string
+	// GenesisValidatorsRoot is the genesis validators root of the network, empty until the
+	// network has launched.
+	GenesisValidatorsRoot []byte
+	// GenesisState is the SSZ encoded genesis state of the network, embedded once the network has
+	// launched so nodes start from it instead of waiting for the genesis of the deposit contract.
+	GenesisState []byte
+}
+
+// TestnetName is the name of the public Prysm test network, which the node joins by default.
+const TestnetName = "testnet"
+
+var networks = map[string]*Network{
+	TestnetName: {
+		Name:         TestnetName,
+		BeaconConfig: MainnetConfig,
+		BootstrapNodes: []string{
+			"/dns4/prylabs.net/tcp/30001/p2p/16Uiu2HAm7Qwe19vz9WzD2Mxn7fXd1vgHHp4iccuyq7TxwRXoAGfc",
+			"enr:-Ku4QAGwOT9StqmwI5LHaIymIO4ooFKfNkEjWa0f1P8OsElgBh2Ijb-GrD_-b9W4kcPFcwmHQEy5RncqXNqdpVo1heoBh2F0dG5ldHOIAAAAAAAAAACEZXRoMpAAAAAAAAAAAP__________gmlkgnY0gmlwhBLf22SJc2VjcDI1NmsxoQJxCnE6v_x2ekgY_uoE1rtwzvGy40mq9eD66XfHPBWgIIN1ZHCCD6A",
+		},
+		DepositContractAddress: "0x5cA1e00004366Ac85f492887AAab12d0e6418876",
+	},
+	"minimal": {
+		Name:         "minimal",
+		BeaconConfig: MinimalSpecConfig,
+	},
+}
+
+// NetworkByName returns the known network with the given name.
+func NetworkByName(name string) (*Network, error) {
+	n, ok := networks[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown network %q, known networks are %v", name, NetworkNames())
+	}
+	return n, nil
+}
+
+// NetworkNames returns the sorted names of the known networks.
+func NetworkNames() []string {
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
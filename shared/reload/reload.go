@@ -0,0 +1,181 @@
+// Package reload re-reads the configuration of a running process on SIGHUP, applies the
+// settings which support it, and reports the changed values which require a restart.
+package reload
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var log = logrus.WithField("prefix", "reload")
+
+// Setting is a configuration value which can be changed without a restart.
+type Setting struct {
+	// Name identifies the setting in logs.
+	Name string
+	// Flags the value is derived from. Changes to them are not reported as requiring a restart.
+	Flags []string
+	// Value computes the setting from the flags, e.g. by reading the file a flag points to.
+	Value func(ctx *cli.Context) (string, error)
+	// Apply the new value to the running process.
+	Apply func(value string) error
+}
+
+// FlagSetting is a setting whose value is the value of a single flag.
+func FlagSetting(name string, flagName string, apply func(value string) error) *Setting {
+	return &Setting{
+		Name:  name,
+		Flags: []string{flagName},
+		Value: func(ctx *cli.Context) (string, error) {
+			return ctx.String(flagName), nil
+		},
+		Apply: apply,
+	}
+}
+
+// LogLevel is the setting of the log level from --verbosity.
+func LogLevel() *Setting {
+	return FlagSetting("log level", cmd.VerbosityFlag.Name, func(value string) error {
+		level, err := logrus.ParseLevel(value)
+		if err != nil {
+			return err
+		}
+		logutil.SetBaseLevel(level)
+		return nil
+	})
+}
+
+// Config for the reloader.
+type Config struct {
+	// Flags of the process, parsed again on reload.
+	Flags []cli.Flag
+	// Args are the command line arguments of the process, without the program name.
+	Args []string
+	// Prepare fills the flags from the other sources the process uses at startup, such
+	// as the config file and the environment.
+	Prepare func(ctx *cli.Context) error
+	// Settings which are applied on reload.
+	Settings []*Setting
+}
+
+// Reloader keeps the values the process runs with and updates them on reload.
+type Reloader struct {
+	cfg       *Config
+	flagVals  map[string]string
+	settings  map[string]string
+	reloadFor map[string]bool
+}
+
+// New creates a reloader from the context the process was started with.
+func New(cliCtx *cli.Context, cfg *Config) (*Reloader, error) {
+	r := &Reloader{
+		cfg:       cfg,
+		flagVals:  flagValues(cliCtx, cfg.Flags),
+		settings:  make(map[string]string, len(cfg.Settings)),
+		reloadFor: make(map[string]bool),
+	}
+	for _, s := range cfg.Settings {
+		v, err := s.Value(cliCtx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", s.Name)
+		}
+		r.settings[s.Name] = v
+		for _, name := range s.Flags {
+			r.reloadFor[name] = true
+		}
+	}
+	return r, nil
+}
+
+// Run reloads the configuration on every SIGHUP until the context is canceled.
+func (r *Reloader) Run(ctx context.Context) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGHUP)
+	defer signal.Stop(sigc)
+	for {
+		select {
+		case <-sigc:
+			log.Info("Got SIGHUP, reloading configuration")
+			if err := r.Reload(); err != nil {
+				log.WithError(err).Error("Could not reload configuration")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Reload parses the configuration again, applies the changed settings and logs the changed
+// flags which only take effect after a restart. Settings which cannot be applied keep their
+// previous value and are retried on the next reload.
+func (r *Reloader) Reload() error {
+	set := flag.NewFlagSet("reload", flag.ContinueOnError)
+	for _, f := range r.cfg.Flags {
+		if err := f.Apply(set); err != nil {
+			return err
+		}
+	}
+	if err := set.Parse(r.cfg.Args); err != nil {
+		return errors.Wrap(err, "could not parse command line")
+	}
+	cliCtx := cli.NewContext(&cli.App{}, set, nil)
+	if r.cfg.Prepare != nil {
+		if err := r.cfg.Prepare(cliCtx); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range r.cfg.Settings {
+		v, err := s.Value(cliCtx)
+		if err != nil {
+			log.WithError(err).WithField("setting", s.Name).Error("Could not read configuration value")
+			continue
+		}
+		old := r.settings[s.Name]
+		if v == old {
+			continue
+		}
+		fields := logrus.Fields{
+			"setting": s.Name,
+			"old":     logutil.Redact(old),
+			"new":     logutil.Redact(v),
+		}
+		if err := s.Apply(v); err != nil {
+			log.WithError(err).WithFields(fields).Error("Could not apply configuration value")
+			continue
+		}
+		r.settings[s.Name] = v
+		log.WithFields(fields).Info("Applied configuration value")
+	}
+
+	for name, v := range flagValues(cliCtx, r.cfg.Flags) {
+		if r.reloadFor[name] || v == r.flagVals[name] {
+			continue
+		}
+		log.WithFields(logrus.Fields{
+			"flag":    name,
+			"running": logutil.Redact(r.flagVals[name]),
+			"new":     logutil.Redact(v),
+		}).Warn("Configuration value changed, restart to apply it")
+	}
+	return nil
+}
+
+// flagValues renders the value of every flag, which works for any flag type.
+func flagValues(ctx *cli.Context, flags []cli.Flag) map[string]string {
+	vals := make(map[string]string, len(flags))
+	for _, f := range flags {
+		name := f.Names()[0]
+		vals[name] = ctx.String(name)
+	}
+	return vals
+}
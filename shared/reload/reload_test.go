@@ -0,0 +1,85 @@
+package reload
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/urfave/cli/v2"
+)
+
+func TestReloader_Reload(t *testing.T) {
+	hook := logTest.NewGlobal()
+	levelFlag := &cli.StringFlag{Name: "test-level", Value: "info"}
+	portFlag := &cli.IntFlag{Name: "test-port", Value: 4000}
+	flags := []cli.Flag{levelFlag, portFlag}
+
+	// The config file is simulated by setting the flags in Prepare.
+	fileValues := map[string]string{}
+	prepare := func(ctx *cli.Context) error {
+		for k, v := range fileValues {
+			if err := ctx.Set(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var applied []string
+	var applyErr error
+	level := FlagSetting("log level", levelFlag.Name, func(v string) error {
+		if applyErr != nil {
+			return applyErr
+		}
+		applied = append(applied, v)
+		return nil
+	})
+
+	set := flag.NewFlagSet("test", 0)
+	for _, f := range flags {
+		if err := f.Apply(set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := New(cli.NewContext(&cli.App{}, set, nil), &Config{
+		Flags:    flags,
+		Prepare:  prepare,
+		Settings: []*Setting{level},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("Expected nothing to be applied without changes, applied %v", applied)
+	}
+
+	fileValues[levelFlag.Name] = "debug"
+	fileValues[portFlag.Name] = "5000"
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0] != "debug" {
+		t.Errorf("Wanted debug to be applied, applied %v", applied)
+	}
+	testutil.AssertLogsContain(t, hook, "Applied configuration value")
+	testutil.AssertLogsContain(t, hook, "Configuration value changed, restart to apply it")
+
+	// A failed apply keeps the running value, so it is retried on the next reload.
+	fileValues[levelFlag.Name] = "trace"
+	applyErr = errors.New("unsupported")
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	applyErr = nil
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 2 || applied[1] != "trace" {
+		t.Errorf("Wanted trace to be applied on retry, applied %v", applied)
+	}
+}
@@ -2,31 +2,25 @@
 package roughtime
 
 import (
+	"sync/atomic"
 	"time"
 
-	rt "github.com/cloudflare/roughtime"
 	"github.com/sirupsen/logrus"
 )
 
 // offset is the difference between the system time and the time returned by
-// the roughtime server
-var offset time.Duration
+// the roughtime servers, in nanoseconds. It is zero unless the roughtime service runs.
+var offset int64
 
 var log = logrus.WithField("prefix", "roughtime")
 
-func init() {
-	t0 := time.Now()
-
-	results := rt.Do(rt.Ecosystem, rt.DefaultQueryAttempts, rt.DefaultQueryTimeout, nil)
+// Offset returns the offset applied to the system time.
+func Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&offset))
+}
 
-	// Compute the average difference between the system's time and the
-	// Roughtime responses from the servers, rejecting responses whose radii
-	// are larger than 2 seconds.
-	var err error
-	offset, err = rt.AvgDeltaWithRadiusThresh(results, t0, 2*time.Second)
-	if err != nil {
-		log.WithError(err).Error("Failed to calculate roughtime offset")
-	}
+func setOffset(d time.Duration) {
+	atomic.StoreInt64(&offset, int64(d))
 }
 
 // Since returns the duration since t, based on the roughtime response
@@ -41,5 +35,5 @@ func Until(t time.Time) time.Duration {
 
 // Now returns the current local time given the roughtime offset.
 func Now() time.Time {
-	return time.Now().Add(offset)
+	return time.Now().Add(Offset())
 }
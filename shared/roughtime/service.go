@@ -0,0 +1,118 @@
+package roughtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	rt "github.com/cloudflare/roughtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = shared.Service(&Service{})
+
+var offsetGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "roughtime_clock_offset_seconds",
+	Help: "Offset applied to the system clock, as computed from the roughtime servers.",
+})
+
+// maxRadius rejects server responses whose uncertainty is larger than this.
+const maxRadius = 2 * time.Second
+
+// Config for the roughtime service.
+type Config struct {
+	// Interval between two recalibrations of the offset.
+	Interval time.Duration
+	// MaxOffset is the largest offset applied. A larger offset is most likely caused by
+	// faulty servers and is rejected.
+	MaxOffset time.Duration
+}
+
+// Service periodically queries the roughtime ecosystem servers and adjusts the clock
+// returned by Now by the average difference to the system time. It is opt-in, for hosts
+// whose system clock cannot be corrected by the operator.
+type Service struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	cfg     *Config
+	query   func() (time.Duration, error)
+	lock    sync.RWMutex
+	lastErr error
+}
+
+// NewService creates a new roughtime service.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:    ctx,
+		cancel: cancel,
+		cfg:    cfg,
+		query:  queryEcosystem,
+	}
+}
+
+// Start calibrates the clock once before returning, so the services started after it
+// use the adjusted clock, and then recalibrates it periodically.
+func (s *Service) Start() {
+	s.calibrate()
+	go s.run()
+}
+
+// Stop the recalibration loop. The last offset stays applied.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status returns the error of the last calibration, if it failed.
+func (s *Service) Status() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.lastErr
+}
+
+func (s *Service) run() {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.calibrate()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// calibrate queries the servers and applies the offset if it is within the sanity bounds.
+func (s *Service) calibrate() {
+	d, err := s.query()
+	if err == nil && (d > s.cfg.MaxOffset || d < -s.cfg.MaxOffset) {
+		err = fmt.Errorf("offset %v exceeds the maximum offset of %v", d, s.cfg.MaxOffset)
+	}
+	s.lock.Lock()
+	s.lastErr = err
+	s.lock.Unlock()
+	if err != nil {
+		log.WithError(err).Error("Could not calibrate clock, keeping the previous offset")
+		return
+	}
+	setOffset(d)
+	offsetGauge.Set(d.Seconds())
+	log.WithFields(logrus.Fields{
+		"offset": d,
+	}).Debug("Calibrated clock")
+}
+
+// queryEcosystem computes the average difference between the system time and the
+// responses of the roughtime ecosystem servers, rejecting responses whose radii are
+// larger than maxRadius.
+func queryEcosystem() (time.Duration, error) {
+	t0 := time.Now()
+	results := rt.Do(rt.Ecosystem, rt.DefaultQueryAttempts, rt.DefaultQueryTimeout, nil)
+	return rt.AvgDeltaWithRadiusThresh(results, t0, maxRadius)
+}
@@ -0,0 +1,43 @@
+package roughtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCalibrate(t *testing.T) {
+	defer setOffset(0)
+	s := NewService(context.Background(), &Config{Interval: time.Hour, MaxOffset: 10 * time.Second})
+
+	s.query = func() (time.Duration, error) {
+		return 3 * time.Second, nil
+	}
+	s.calibrate()
+	if err := s.Status(); err != nil {
+		t.Fatal(err)
+	}
+	if Offset() != 3*time.Second {
+		t.Errorf("Wanted offset %v, received %v", 3*time.Second, Offset())
+	}
+
+	// Offsets out of bounds and failed queries keep the previous offset.
+	s.query = func() (time.Duration, error) {
+		return -time.Minute, nil
+	}
+	s.calibrate()
+	if err := s.Status(); err == nil {
+		t.Error("Expected error for offset exceeding the maximum offset")
+	}
+	s.query = func() (time.Duration, error) {
+		return 0, errors.New("no servers")
+	}
+	s.calibrate()
+	if err := s.Status(); err == nil {
+		t.Error("Expected error for failed query")
+	}
+	if Offset() != 3*time.Second {
+		t.Errorf("Wanted offset %v, received %v", 3*time.Second, Offset())
+	}
+}
@@ -0,0 +1,157 @@
+// Package rpcauth implements bearer token authentication of the gRPC API of a beacon
+// node. The node generates a random token on first start and stores it in a file that
+// clients, such as validator clients, read to authenticate their calls.
+package rpcauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenFileName is the default name of the token file in the data directory.
+const TokenFileName = "auth-token"
+
+// metadataKey is the gRPC metadata key of the token. The JSON-HTTP gateway forwards the
+// Authorization header under the same key.
+const metadataKey = "authorization"
+
+const bearerPrefix = "Bearer "
+
+const tokenLength = 32
+
+// LoadOrCreateToken reads the token from the given file, generating and storing a new
+// random token if the file does not exist yet.
+func LoadOrCreateToken(path string) (string, error) {
+	token, err := ReadToken(path)
+	if err == nil {
+		return token, nil
+	}
+	if !os.IsNotExist(errors.Cause(err)) {
+		return "", err
+	}
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "could not generate token")
+	}
+	token = hex.EncodeToString(b)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", errors.Wrap(err, "could not create token directory")
+	}
+	if err := ioutil.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", errors.Wrap(err, "could not write token file")
+	}
+	return token, nil
+}
+
+// ReadToken reads the token from the given file.
+func ReadToken(path string) (string, error) {
+	// #nosec G304
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read token file")
+	}
+	token := strings.TrimSpace(string(enc))
+	if token == "" {
+		return "", errors.New("token file is empty")
+	}
+	return token, nil
+}
+
+// Authenticator checks the token of calls to a set of protected gRPC methods.
+type Authenticator struct {
+	token     []byte
+	protected map[string]bool
+}
+
+// NewAuthenticator returns an authenticator requiring the token for the given full
+// gRPC method names, such as /ethereum.eth.v1alpha1.BeaconNodeValidator/ProposeBlock.
+func NewAuthenticator(token string, protectedMethods []string) *Authenticator {
+	protected := make(map[string]bool, len(protectedMethods))
+	for _, m := range protectedMethods {
+		protected[m] = true
+	}
+	return &Authenticator{
+		token:     []byte(token),
+		protected: protected,
+	}
+}
+
+// UnaryServerInterceptor rejects unary calls to protected methods without a valid token.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streams of protected methods without a valid token.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (a *Authenticator) authorize(ctx context.Context, method string) error {
+	if !a.protected[method] {
+		return nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+	for _, v := range md.Get(metadataKey) {
+		token := []byte(strings.TrimPrefix(v, bearerPrefix))
+		if subtle.ConstantTimeCompare(token, a.token) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid or missing authorization token")
+}
+
+// RequireToken wraps an HTTP handler to reject requests without the token as bearer token
+// in their Authorization header.
+func RequireToken(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		given := []byte(strings.TrimPrefix(r.Header.Get("Authorization"), bearerPrefix))
+		if token == "" || subtle.ConstantTimeCompare(given, []byte(token)) != 1 {
+			http.Error(w, "invalid or missing authorization token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// TokenCredentials attaches the token to every call of a client connection.
+type TokenCredentials string
+
+var _ = credentials.PerRPCCredentials(TokenCredentials(""))
+
+// GetRequestMetadata returns the authorization metadata of a call.
+func (t TokenCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{metadataKey: bearerPrefix + string(t)}, nil
+}
+
+// RequireTransportSecurity is false, as beacon nodes may be reached over an insecure
+// connection on a private network.
+func (t TokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
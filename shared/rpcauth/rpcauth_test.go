@@ -0,0 +1,127 @@
+package rpcauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoadOrCreateToken(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(), "rpcauth")
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	path := filepath.Join(dir, TokenFileName)
+
+	token, err := LoadOrCreateToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(token) != 2*tokenLength {
+		t.Errorf("Expected token of %d characters, received %q", 2*tokenLength, token)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected token file permissions 0600, received %v", info.Mode().Perm())
+	}
+	again, err := LoadOrCreateToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != token {
+		t.Errorf("Expected token to be reused, received %q and %q", token, again)
+	}
+}
+
+func TestAuthenticator_UnaryServerInterceptor(t *testing.T) {
+	const protected = "/test.Service/Mutate"
+	interceptor := NewAuthenticator("secret", []string{protected}).UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "unprotected method", method: "/test.Service/Read", wantCode: codes.OK},
+		{name: "missing metadata", method: protected, wantCode: codes.Unauthenticated},
+		{name: "wrong token", method: protected, md: metadata.Pairs(metadataKey, "Bearer wrong"), wantCode: codes.Unauthenticated},
+		{name: "valid token", method: protected, md: metadata.Pairs(metadataKey, "Bearer secret"), wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("Expected code %v, received %v", tt.wantCode, code)
+			}
+		})
+	}
+}
+
+func TestTokenCredentials(t *testing.T) {
+	md, err := TokenCredentials("secret").GetRequestMetadata(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	interceptor := NewAuthenticator("secret", []string{"/test.Service/Mutate"}).UnaryServerInterceptor()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(md))
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Mutate"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Errorf("Expected token credentials to be accepted, received %v", err)
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	handler := RequireToken("secret", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tests := []struct {
+		header string
+		want   int
+	}{
+		{header: "", want: http.StatusUnauthorized},
+		{header: "Bearer wrong", want: http.StatusUnauthorized},
+		{header: "Bearer secret", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Authorization %q: wanted status %d, received %d", tt.header, tt.want, rec.Code)
+		}
+	}
+
+	// An empty token rejects all requests.
+	rec := httptest.NewRecorder()
+	RequireToken("", handler)(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Wanted status %d with an empty token, received %d", http.StatusUnauthorized, rec.Code)
+	}
+}
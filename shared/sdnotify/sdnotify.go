@@ -0,0 +1,71 @@
+// Package sdnotify implements the systemd service notification protocol, so a process run
+// as a systemd service of Type=notify can report when it is ready and send keepalives to
+// the systemd watchdog.
+package sdnotify
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "sdnotify")
+
+const (
+	// Ready tells systemd that the service finished starting up.
+	Ready = "READY=1"
+	// Stopping tells systemd that the service is shutting down.
+	Stopping = "STOPPING=1"
+	// Watchdog is the keepalive which resets the watchdog timer.
+	Watchdog = "WATCHDOG=1"
+)
+
+// Notify sends a state to the socket named by NOTIFY_SOCKET. It does nothing and returns
+// false if the process was not started by systemd with notification support.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// A leading @ refers to the abstract namespace, which the net package handles.
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, errors.Wrap(err, "could not connect to notification socket")
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.WithError(err).Debug("Could not close notification socket")
+		}
+	}()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, errors.Wrap(err, "could not write to notification socket")
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the watchdog timeout of the service from WATCHDOG_USEC, or 0 if the
+// watchdog is disabled or, as given by WATCHDOG_PID, meant for another process.
+func WatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv("WATCHDOG_USEC")
+	if usec == "" {
+		return 0, nil
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" {
+		p, err := strconv.Atoi(pid)
+		if err != nil {
+			return 0, errors.Wrap(err, "could not parse WATCHDOG_PID")
+		}
+		if p != os.Getpid() {
+			return 0, nil
+		}
+	}
+	n, err := strconv.ParseUint(usec, 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not parse WATCHDOG_USEC")
+	}
+	return time.Duration(n) * time.Microsecond, nil
+}
@@ -0,0 +1,137 @@
+package sdnotify
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func listen(t *testing.T) (*net.UnixConn, func()) {
+	dir, err := ioutil.TempDir("", "sdnotify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("NOTIFY_SOCKET", socket); err != nil {
+		t.Fatal(err)
+	}
+	return conn, func() {
+		if err := os.Unsetenv("NOTIFY_SOCKET"); err != nil {
+			t.Log(err)
+		}
+		if err := conn.Close(); err != nil {
+			t.Log(err)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	}
+}
+
+func read(t *testing.T, conn *net.UnixConn) string {
+	if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+func TestNotify(t *testing.T) {
+	if sent, err := Notify(Ready); err != nil || sent {
+		t.Fatalf("Expected nothing to be sent without a socket, received %v, %v", sent, err)
+	}
+
+	conn, cleanup := listen(t)
+	defer cleanup()
+	sent, err := Notify(Ready)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sent {
+		t.Fatal("Expected the state to be sent")
+	}
+	if got := read(t, conn); got != Ready {
+		t.Errorf("Wanted %q, received %q", Ready, got)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	defer func() {
+		if err := os.Unsetenv("WATCHDOG_USEC"); err != nil {
+			t.Log(err)
+		}
+		if err := os.Unsetenv("WATCHDOG_PID"); err != nil {
+			t.Log(err)
+		}
+	}()
+	tests := []struct {
+		usec    string
+		pid     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{usec: "", want: 0},
+		{usec: "30000000", want: 30 * time.Second},
+		{usec: "30000000", pid: strconv.Itoa(os.Getpid()), want: 30 * time.Second},
+		{usec: "30000000", pid: strconv.Itoa(os.Getpid() + 1), want: 0},
+		{usec: "thirty", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := os.Setenv("WATCHDOG_USEC", tt.usec); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Setenv("WATCHDOG_PID", tt.pid); err != nil {
+			t.Fatal(err)
+		}
+		got, err := WatchdogInterval()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("WATCHDOG_USEC=%s: unexpected error %v", tt.usec, err)
+		}
+		if got != tt.want {
+			t.Errorf("WATCHDOG_USEC=%s WATCHDOG_PID=%s: wanted %v, received %v", tt.usec, tt.pid, tt.want, got)
+		}
+	}
+}
+
+func TestService_WithholdsKeepaliveOnFailedCheck(t *testing.T) {
+	conn, cleanup := listen(t)
+	defer cleanup()
+
+	var checkErr error
+	s := NewService(context.Background(), []Check{
+		{Name: "test", Check: func(_ context.Context) error { return checkErr }},
+	})
+	s.keepalive()
+	if got := read(t, conn); got != Watchdog {
+		t.Errorf("Wanted %q, received %q", Watchdog, got)
+	}
+	if err := s.Status(); err != nil {
+		t.Errorf("Unexpected status %v", err)
+	}
+
+	checkErr = errors.New("stalled")
+	s.keepalive()
+	if err := conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Read(make([]byte, 64)); err == nil {
+		t.Error("Expected no keepalive after a failed check")
+	}
+	if err := s.Status(); err == nil || err.Error() != "test: stalled" {
+		t.Errorf("Unexpected status %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package sdnotify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared"
+)
+
+var _ = shared.Service(&Service{})
+
+// Check reports whether a part of the process is still making progress.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Service sends keepalives to the systemd watchdog at half the watchdog timeout, as long as
+// all liveness checks pass. When a check fails, the keepalives stop and systemd restarts the
+// process once the timeout expires.
+type Service struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	checks  []Check
+	lock    sync.RWMutex
+	lastErr error
+}
+
+// NewService creates a new watchdog service with the given liveness checks.
+func NewService(ctx context.Context, checks []Check) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:    ctx,
+		cancel: cancel,
+		checks: checks,
+	}
+}
+
+// Start sending keepalives if the watchdog is enabled for the service.
+func (s *Service) Start() {
+	timeout, err := WatchdogInterval()
+	if err != nil {
+		log.WithError(err).Error("Could not read watchdog timeout, not sending keepalives")
+		return
+	}
+	if timeout == 0 {
+		return
+	}
+	log.WithField("timeout", timeout).Info("Sending keepalives to the systemd watchdog")
+	go s.run(timeout / 2)
+}
+
+// Stop sending keepalives.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status returns the failure of the last liveness check, if any.
+func (s *Service) Status() error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.lastErr
+}
+
+func (s *Service) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.keepalive()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// keepalive runs the liveness checks and notifies the watchdog if they all pass.
+func (s *Service) keepalive() {
+	err := s.runChecks()
+	s.lock.Lock()
+	s.lastErr = err
+	s.lock.Unlock()
+	if err != nil {
+		log.WithError(err).Error("Liveness check failed, withholding watchdog keepalive")
+		return
+	}
+	if _, err := Notify(Watchdog); err != nil {
+		log.WithError(err).Error("Could not send watchdog keepalive")
+	}
+}
+
+func (s *Service) runChecks() error {
+	for _, c := range s.checks {
+		if err := c.Check(s.ctx); err != nil {
+			return errors.Wrapf(err, "%s", c.Name)
+		}
+	}
+	return nil
+}
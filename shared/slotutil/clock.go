@@ -0,0 +1,137 @@
+package slotutil
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+// Clock provides the current time and timers to the slot computations of the beacon node and
+// validator client. The roughtime clock is used unless another clock is set with SetClock, such
+// as a scaled clock for accelerated local chains or a fake clock in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Anchorer is implemented by clocks which run relative to the genesis time of the chain.
+type Anchorer interface {
+	Anchor(genesisTime time.Time)
+}
+
+var (
+	clockLock sync.RWMutex
+	clock     Clock = RoughtimeClock{}
+)
+
+// SetClock sets the clock used for slot computations.
+func SetClock(c Clock) {
+	clockLock.Lock()
+	defer clockLock.Unlock()
+	clock = c
+}
+
+// CurrentClock returns the clock used for slot computations.
+func CurrentClock() Clock {
+	clockLock.RLock()
+	defer clockLock.RUnlock()
+	return clock
+}
+
+// AnchorClock anchors the clock at the genesis time once it is known, if the clock runs relative
+// to genesis.
+func AnchorClock(genesisTime time.Time) {
+	if a, ok := CurrentClock().(Anchorer); ok {
+		a.Anchor(genesisTime)
+	}
+}
+
+// Now returns the current time of the clock.
+func Now() time.Time {
+	return CurrentClock().Now()
+}
+
+// Since returns the duration since t on the clock.
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
+
+// Until returns the duration until t on the clock.
+func Until(t time.Time) time.Duration {
+	return t.Sub(Now())
+}
+
+// After waits for the duration to elapse on the clock and then sends the time on the channel.
+func After(d time.Duration) <-chan time.Time {
+	return CurrentClock().After(d)
+}
+
+// RoughtimeClock is the clock of the system time adjusted by the roughtime offset.
+type RoughtimeClock struct{}
+
+// Now --
+func (RoughtimeClock) Now() time.Time {
+	return roughtime.Now()
+}
+
+// After --
+func (RoughtimeClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// ScaledClock runs faster than the roughtime clock by a factor once anchored at the genesis time,
+// so slots pass faster for accelerated local chains. Clocks of different processes scaled by the
+// same factor agree on the current slot, as they are anchored at the same genesis time. Before
+// being anchored, the clock runs as the roughtime clock.
+type ScaledClock struct {
+	factor float64
+	lock   sync.RWMutex
+	anchor time.Time
+}
+
+// NewScaledClock returns a clock running faster by the factor, which must be positive.
+func NewScaledClock(factor float64) *ScaledClock {
+	return &ScaledClock{factor: factor}
+}
+
+// NewClockWithSlotsPerSecond returns a clock which runs the number of slots of the given duration
+// per second.
+func NewClockWithSlotsPerSecond(slotsPerSecond float64, secondsPerSlot uint64) *ScaledClock {
+	return NewScaledClock(slotsPerSecond * float64(secondsPerSlot))
+}
+
+// Anchor the clock at the genesis time.
+func (c *ScaledClock) Anchor(genesisTime time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.anchor = genesisTime
+}
+
+// Now --
+func (c *ScaledClock) Now() time.Time {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	now := roughtime.Now()
+	if c.anchor.IsZero() {
+		return now
+	}
+	return c.anchor.Add(time.Duration(float64(now.Sub(c.anchor)) * c.factor))
+}
+
+// After --
+func (c *ScaledClock) After(d time.Duration) <-chan time.Time {
+	c.lock.RLock()
+	anchored := !c.anchor.IsZero()
+	c.lock.RUnlock()
+	if !anchored {
+		return time.After(d)
+	}
+	ch := make(chan time.Time, 1)
+	timer := time.NewTimer(time.Duration(float64(d) / c.factor))
+	go func() {
+		<-timer.C
+		ch <- c.Now()
+	}()
+	return ch
+}
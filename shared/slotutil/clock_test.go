@@ -0,0 +1,55 @@
+package slotutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	mock "github.com/prysmaticlabs/prysm/shared/slotutil/testing"
+)
+
+func TestSlotTicker_FakeClock(t *testing.T) {
+	genesisTime := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := mock.NewFakeClock(genesisTime.Add(time.Second))
+	SetClock(clk)
+	defer SetClock(RoughtimeClock{})
+
+	ticker := GetSlotTicker(genesisTime, 8)
+	defer ticker.Done()
+	// The ticker starts after genesis and ticks the current slot right away.
+	if slot := <-ticker.C(); slot != 0 {
+		t.Fatalf("Expected slot 0, got %d", slot)
+	}
+	for want := uint64(1); want <= 3; want++ {
+		for clk.Waiters() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clk.Advance(8 * time.Second)
+		if slot := <-ticker.C(); slot != want {
+			t.Fatalf("Expected slot %d, got %d", want, slot)
+		}
+	}
+	if slots := SlotsSinceGenesis(genesisTime); slots != 3 {
+		t.Errorf("Expected 3 slots since genesis, got %d", slots)
+	}
+}
+
+func TestScaledClock(t *testing.T) {
+	c := NewClockWithSlotsPerSecond(2, 6)
+	if d := c.Now().Sub(roughtime.Now()); d > time.Second || d < -time.Second {
+		t.Errorf("Expected the clock to run as the roughtime clock before being anchored, off by %s", d)
+	}
+
+	genesisTime := roughtime.Now().Add(-time.Second)
+	c.Anchor(genesisTime)
+	// A second since genesis are 12 seconds on a clock running 12 times faster.
+	if d := c.Now().Sub(genesisTime); d < 11*time.Second || d > 13*time.Second {
+		t.Errorf("Expected about 12 seconds since genesis, got %s", d)
+	}
+
+	start := roughtime.Now()
+	<-c.After(600 * time.Millisecond)
+	if d := roughtime.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Expected timer to fire after about 50ms, took %s", d)
+	}
+}
@@ -3,8 +3,6 @@ package slotutil
 
 import (
 	"time"
-
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
 )
 
 // The Ticker interface defines a type which can expose a
@@ -47,7 +45,7 @@ func GetSlotTicker(genesisTime time.Time, secondsPerSlot uint64) *SlotTicker {
 		c:    make(chan uint64),
 		done: make(chan struct{}),
 	}
-	ticker.start(genesisTime, secondsPerSlot, roughtime.Since, roughtime.Until, time.After)
+	ticker.start(genesisTime, secondsPerSlot, Since, Until, After)
 	return ticker
 }
 
@@ -64,7 +62,7 @@ func GetSlotTickerWithOffset(genesisTime time.Time, offset time.Duration, second
 		c:    make(chan uint64),
 		done: make(chan struct{}),
 	}
-	ticker.start(genesisTime.Add(offset), secondsPerSlot, roughtime.Since, roughtime.Until, time.After)
+	ticker.start(genesisTime.Add(offset), secondsPerSlot, Since, Until, After)
 	return ticker
 }
 
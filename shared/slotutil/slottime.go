@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
 )
 
 // SlotStartTime returns the start time in terms of its unix epoch
@@ -18,7 +17,7 @@ func SlotStartTime(genesis uint64, slot uint64) time.Time {
 // SlotsSinceGenesis returns the number of slots since
 // the provided genesis time.
 func SlotsSinceGenesis(genesis time.Time) uint64 {
-	return uint64(roughtime.Since(genesis).Seconds()) / params.BeaconConfig().SecondsPerSlot
+	return uint64(Since(genesis).Seconds()) / params.BeaconConfig().SecondsPerSlot
 }
 
 // EpochsSinceGenesis returns the number of slots since
@@ -3,3 +3,5 @@ package testing
 import "github.com/prysmaticlabs/prysm/shared/slotutil"
 
 var _ = slotutil.Ticker(&MockTicker{})
+
+var _ = slotutil.Clock(&FakeClock{})
@@ -126,6 +126,23 @@ func (m *SparseMerkleTrie) Insert(item []byte, index int) {
 	}
 }
 
+// Copy returns a copy of the trie which is not affected by later insertions into the trie. The
+// nodes are shared between the copies, as insertions replace nodes rather than modify them.
+func (m *SparseMerkleTrie) Copy() *SparseMerkleTrie {
+	branches := make([][][]byte, len(m.branches))
+	for i, layer := range m.branches {
+		branches[i] = make([][]byte, len(layer))
+		copy(branches[i], layer)
+	}
+	originalItems := make([][]byte, len(m.originalItems))
+	copy(originalItems, m.originalItems)
+	return &SparseMerkleTrie{
+		depth:         m.depth,
+		branches:      branches,
+		originalItems: originalItems,
+	}
+}
+
 // MerkleProof computes a proof from a trie's branches using a Merkle index.
 func (m *SparseMerkleTrie) MerkleProof(index int) ([][]byte, error) {
 	merkleIndex := uint(index)
@@ -164,6 +181,60 @@ func (m *SparseMerkleTrie) HashTreeRoot() [32]byte {
 	return hashutil.Hash(newNode)
 }
 
+// PrefixHashTreeRoot returns the hash tree root, as defined in the deposit contract, of the trie
+// holding only the first count items. It uses the nodes of the trie which only cover these items,
+// so it takes a number of hashes in the order of the depth instead of regenerating the trie.
+func (m *SparseMerkleTrie) PrefixHashTreeRoot(count uint64) ([32]byte, error) {
+	if count > uint64(len(m.originalItems)) {
+		return [32]byte{}, fmt.Errorf("count out of range in trie, max range: %d, received: %d", len(m.originalItems), count)
+	}
+	var zeroBytes [32]byte
+	newNode := append(m.prefixNode(m.depth, 0, count), bytesutil.Bytes8(count)...)
+	newNode = append(newNode, zeroBytes[:24]...)
+	return hashutil.Hash(newNode), nil
+}
+
+// PrefixMerkleProof computes a proof of the item at the Merkle index against the trie holding
+// only the first count items, as MerkleProof does for the whole trie.
+func (m *SparseMerkleTrie) PrefixMerkleProof(index int, count uint64) ([][]byte, error) {
+	if count > uint64(len(m.originalItems)) {
+		return nil, fmt.Errorf("count out of range in trie, max range: %d, received: %d", len(m.originalItems), count)
+	}
+	if index < 0 || uint64(index) >= count {
+		return nil, fmt.Errorf("merkle index out of range in trie, max range: %d, received: %d", count, index)
+	}
+	merkleIndex := uint64(index)
+	proof := make([][]byte, m.depth+1)
+	for i := uint(0); i < m.depth; i++ {
+		proof[i] = m.prefixNode(i, (merkleIndex>>i)^1, count)
+	}
+	enc := [32]byte{}
+	binary.LittleEndian.PutUint64(enc[:], count)
+	proof[len(proof)-1] = enc[:]
+	return proof, nil
+}
+
+// prefixNode returns the node at the layer and index of the trie holding only the first count
+// items. Nodes covering only these items are read from the trie, and nodes covering none of them
+// are zero hashes, so at most one node per layer is hashed again.
+func (m *SparseMerkleTrie) prefixNode(layer uint, index uint64, count uint64) []byte {
+	first := index << layer
+	if first >= count {
+		return ZeroHashes[layer][:]
+	}
+	if first+(1<<layer) <= count {
+		if index < uint64(len(m.branches[layer])) {
+			node := bytesutil.ToBytes32(m.branches[layer][index])
+			return node[:]
+		}
+		return ZeroHashes[layer][:]
+	}
+	left := m.prefixNode(layer-1, 2*index, count)
+	right := m.prefixNode(layer-1, 2*index+1, count)
+	node := hashutil.Hash(append(left, right...))
+	return node[:]
+}
+
 // ToProto converts the underlying trie into its corresponding
 // proto object
 func (m *SparseMerkleTrie) ToProto() *protodb.SparseMerkleTrie {
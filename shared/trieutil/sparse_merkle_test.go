@@ -182,6 +182,72 @@ func TestMerkleTrie_VerifyMerkleProof_TrieUpdated(t *testing.T) {
 	m.Insert([]byte{6}, 15)
 }
 
+func TestMerkleTrie_PrefixProofs(t *testing.T) {
+	m, err := NewTrie(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items [][]byte
+	for i := 0; i < 13; i++ {
+		items = append(items, []byte{byte(i + 1)})
+		m.Insert(items[i], i)
+	}
+	for count := 1; count <= len(items); count++ {
+		prefix, err := GenerateTrieFromItems(items[:count], 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := m.PrefixHashTreeRoot(uint64(count))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != prefix.HashTreeRoot() {
+			t.Errorf("Wanted the root of the first %d items %#x, received %#x", count, prefix.HashTreeRoot(), root)
+		}
+		for i := 0; i < count; i++ {
+			proof, err := m.PrefixMerkleProof(i, uint64(count))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := prefix.MerkleProof(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(proof, want) {
+				t.Errorf("Wanted the proof of item %d of the first %d items to match the proof of a trie of these items", i, count)
+			}
+			prefixRoot := prefix.Root()
+			if !VerifyMerkleBranch(prefixRoot[:], items[i], i, proof) {
+				t.Errorf("Proof of item %d of the first %d items did not verify", i, count)
+			}
+		}
+	}
+
+	if _, err := m.PrefixMerkleProof(5, 5); err == nil {
+		t.Error("Expected an error for an index outside of the prefix")
+	}
+	if _, err := m.PrefixHashTreeRoot(uint64(len(items) + 1)); err == nil {
+		t.Error("Expected an error for a count larger than the number of items")
+	}
+}
+
+func TestMerkleTrie_Copy(t *testing.T) {
+	m, err := GenerateTrieFromItems([][]byte{{1}, {2}, {3}}, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := m.HashTreeRoot()
+	cpy := m.Copy()
+	m.Insert([]byte{4}, 3)
+	m.Insert([]byte{5}, 1)
+	if cpy.HashTreeRoot() != root {
+		t.Errorf("Wanted the root of the copy to remain %#x, received %#x", root, cpy.HashTreeRoot())
+	}
+	if m.HashTreeRoot() == root {
+		t.Error("Expected the root of the trie to change")
+	}
+}
+
 func TestRoundtripProto_OK(t *testing.T) {
 	items := [][]byte{
 		{1},
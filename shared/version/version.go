@@ -6,17 +6,47 @@ import (
 	"fmt"
 	"log"
 	"os/exec"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 )
 
-// The value of these vars are set through linker options.
+// The value of these vars are set through linker options. The build date is the time of the
+// commit rather than the time of the build, so that release builds are reproducible.
 var gitCommit = "Local build"
+var gitTag = "Unknown"
 var buildDate = "Moments ago"
 
+var resolveOnce sync.Once
+
+// BuildInfo describes the build of the running binary.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
 // GetVersion returns the version string of this build.
 func GetVersion() string {
-	// if doing a local build, these values are not interpolated
+	resolveOnce.Do(resolve)
+	return fmt.Sprintf("Prysm/Git commit: %s. Built at: %s", gitCommit, buildDate)
+}
+
+// GetBuildInfo returns the semantic version, git commit, build date and Go version of this build.
+func GetBuildInfo() *BuildInfo {
+	resolveOnce.Do(resolve)
+	return &BuildInfo{
+		Version:   gitTag,
+		GitCommit: gitCommit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// resolve fills in the values which are not interpolated when doing a local build.
+func resolve() {
 	if gitCommit == "{STABLE_GIT_COMMIT}" {
 		commit, err := exec.Command("git", "rev-parse", "HEAD").Output()
 		if err != nil {
@@ -25,9 +55,22 @@ func GetVersion() string {
 			gitCommit = strings.TrimRight(string(commit), "\r\n")
 		}
 	}
-	if buildDate == "{DATE}" {
-		now := time.Now().Format(time.RFC3339)
-		buildDate = now
+	if gitTag == "{STABLE_GIT_TAG}" {
+		tag, err := exec.Command("git", "describe", "--tags", "--abbrev=0").Output()
+		if err != nil {
+			log.Println(err)
+			gitTag = "Unknown"
+		} else {
+			gitTag = strings.TrimRight(string(tag), "\r\n")
+		}
+	}
+	if buildDate == "{STABLE_GIT_COMMIT_TIME}" {
+		commitTime, err := exec.Command("git", "show", "-s", "--format=%cI", "HEAD").Output()
+		if err != nil {
+			log.Println(err)
+			buildDate = time.Now().Format(time.RFC3339)
+		} else {
+			buildDate = strings.TrimRight(string(commitTime), "\r\n")
+		}
 	}
-	return fmt.Sprintf("Prysm/Git commit: %s. Built at: %s", gitCommit, buildDate)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/shared/event"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
 	"github.com/prysmaticlabs/prysm/slasher/cache"
 	"github.com/prysmaticlabs/prysm/slasher/db"
 	"github.com/sirupsen/logrus"
@@ -42,7 +43,8 @@ type ChainFetcher interface {
 type Service struct {
 	ctx                         context.Context
 	cancel                      context.CancelFunc
-	cert                        string
+	creds                       credentials.TransportCredentials
+	authToken                   string
 	conn                        *grpc.ClientConn
 	provider                    string
 	beaconClient                ethpb.BeaconChainClient
@@ -64,7 +66,8 @@ type Service struct {
 // Config options for the beaconclient service.
 type Config struct {
 	BeaconProvider        string
-	BeaconCert            string
+	BeaconCreds           credentials.TransportCredentials
+	AuthToken             string
 	SlasherDB             db.Database
 	ProposerSlashingsFeed *event.Feed
 	AttesterSlashingsFeed *event.Feed
@@ -82,7 +85,8 @@ func NewBeaconClientService(ctx context.Context, cfg *Config) (*Service, error)
 	}
 
 	return &Service{
-		cert:                        cfg.BeaconCert,
+		creds:                       cfg.BeaconCreds,
+		authToken:                   cfg.AuthToken,
 		ctx:                         ctx,
 		cancel:                      cancel,
 		provider:                    cfg.BeaconProvider,
@@ -145,12 +149,8 @@ func (bs *Service) Status() error {
 // after they are detected by other services in the slasher.
 func (bs *Service) Start() {
 	var dialOpt grpc.DialOption
-	if bs.cert != "" {
-		creds, err := credentials.NewClientTLSFromFile(bs.cert, "")
-		if err != nil {
-			log.Errorf("Could not get valid credentials: %v", err)
-		}
-		dialOpt = grpc.WithTransportCredentials(creds)
+	if bs.creds != nil {
+		dialOpt = grpc.WithTransportCredentials(bs.creds)
 	} else {
 		dialOpt = grpc.WithInsecure()
 		log.Warn(
@@ -169,6 +169,12 @@ func (bs *Service) Start() {
 			grpc_prometheus.UnaryClientInterceptor,
 		)),
 	}
+	if bs.authToken != "" {
+		if bs.creds == nil {
+			log.Warn("The beacon node auth token is sent over an insecure gRPC connection. Provide the certificate of the beacon node to encrypt it")
+		}
+		beaconOpts = append(beaconOpts, grpc.WithPerRPCCredentials(rpcauth.TokenCredentials(bs.authToken)))
+	}
 	conn, err := grpc.DialContext(bs.ctx, bs.provider, beaconOpts...)
 	if err != nil {
 		log.Fatalf("Could not dial endpoint: %s, %v", bs.provider, err)
@@ -70,7 +70,7 @@ func (ds *Service) DetectAttesterSlashings(
 			slashingList = append(slashingList, ss)
 		}
 	}
-	if err = ds.slasherDB.SaveAttesterSlashings(ctx, status.Active, slashings); err != nil {
+	if err = ds.slasherDB.SaveAttesterSlashings(ctx, status.Active, slashingList); err != nil {
 		return nil, err
 	}
 	return slashingList, nil
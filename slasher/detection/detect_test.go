@@ -7,6 +7,7 @@ import (
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	slasherDB "github.com/prysmaticlabs/prysm/slasher/db"
 	testDB "github.com/prysmaticlabs/prysm/slasher/db/testing"
 	status "github.com/prysmaticlabs/prysm/slasher/db/types"
 	"github.com/prysmaticlabs/prysm/slasher/detection/attestations"
@@ -344,6 +345,71 @@ func TestDetect_detectAttesterSlashings_Double(t *testing.T) {
 	}
 }
 
+// savedSlashingsDB records the attester slashings saved to the slasher db.
+type savedSlashingsDB struct {
+	slasherDB.Database
+	saved []*ethpb.AttesterSlashing
+}
+
+func (db *savedSlashingsDB) SaveAttesterSlashings(ctx context.Context, st status.SlashingStatus, slashings []*ethpb.AttesterSlashing) error {
+	db.saved = append(db.saved, slashings...)
+	return db.Database.SaveAttesterSlashings(ctx, st, slashings)
+}
+
+func TestDetect_detectAttesterSlashings_SavesDeduplicated(t *testing.T) {
+	// Both validators of the saved attestation double vote with the incoming attestation, so the
+	// detector reports a result for each of them which maps to the same slashing.
+	savedAtt := &ethpb.IndexedAttestation{
+		AttestingIndices: []uint64{0, 4},
+		Data: &ethpb.AttestationData{
+			Source:          &ethpb.Checkpoint{Epoch: 2},
+			Target:          &ethpb.Checkpoint{Epoch: 4},
+			BeaconBlockRoot: bytesutil.PadTo([]byte("good block root"), 32),
+		},
+		Signature: bytesutil.PadTo([]byte{1, 2}, 96),
+	}
+	incomingAtt := &ethpb.IndexedAttestation{
+		AttestingIndices: []uint64{0, 4},
+		Data: &ethpb.AttestationData{
+			Source:          &ethpb.Checkpoint{Epoch: 2},
+			Target:          &ethpb.Checkpoint{Epoch: 4},
+			BeaconBlockRoot: bytesutil.PadTo([]byte("bad block root"), 32),
+		},
+		Signature: bytesutil.PadTo([]byte{1, 3}, 96),
+	}
+	db := &savedSlashingsDB{Database: testDB.SetupSlasherDB(t, false)}
+	ctx := context.Background()
+	ds := Service{
+		ctx:                ctx,
+		slasherDB:          db,
+		minMaxSpanDetector: attestations.NewSpanDetector(db),
+	}
+	if err := db.SaveIndexedAttestations(ctx, []*ethpb.IndexedAttestation{savedAtt}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.minMaxSpanDetector.UpdateSpans(ctx, savedAtt); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ds.minMaxSpanDetector.DetectSlashingsForAttestation(ctx, incomingAtt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Wanted a detection result for each validator, received %d", len(results))
+	}
+	slashings, err := ds.DetectAttesterSlashings(ctx, incomingAtt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slashings) != 1 {
+		t.Errorf("Wanted 1 slashing returned, received %d", len(slashings))
+	}
+	if len(db.saved) != 1 {
+		t.Errorf("Wanted 1 slashing saved, received %d", len(db.saved))
+	}
+}
+
 func TestDetect_detectProposerSlashing(t *testing.T) {
 	type testStruct struct {
 		name        string
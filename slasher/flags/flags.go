@@ -12,6 +12,21 @@ var (
 		Name:  "beacon-tls-cert",
 		Usage: "Certificate for secure beacon gRPC connection. Pass this in order to use beacon gRPC securely.",
 	}
+	// BeaconClientCertFlag defines the certificate the slasher presents to the beacon node.
+	BeaconClientCertFlag = &cli.StringFlag{
+		Name:  "beacon-tls-client-cert",
+		Usage: "Client certificate for mutual TLS with a beacon node started with --tls-client-ca. Pass this and the beacon-tls-client-key flag",
+	}
+	// BeaconClientKeyFlag defines the key of the client certificate.
+	BeaconClientKeyFlag = &cli.StringFlag{
+		Name:  "beacon-tls-client-key",
+		Usage: "Key of the client certificate for mutual TLS with a beacon node",
+	}
+	// BeaconRPCAuthTokenFileFlag defines a file holding the auth token of the beacon node RPC endpoint.
+	BeaconRPCAuthTokenFileFlag = &cli.StringFlag{
+		Name:  "beacon-rpc-auth-token-file",
+		Usage: "Path to the auth token file of a beacon node started with --rpc-auth, required to submit slashings to it",
+	}
 	// BeaconRPCProviderFlag defines a flag for the beacon host ip or address.
 	BeaconRPCProviderFlag = &cli.StringFlag{
 		Name:  "beacon-rpc-provider",
@@ -4,11 +4,9 @@
 package main
 
 import (
-	"fmt"
 	"os"
 	"runtime"
 
-	joonix "github.com/joonix/log"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
@@ -19,18 +17,14 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	"github.com/urfave/cli/v2/altsrc"
-	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 )
 
 var log = logrus.WithField("prefix", "main")
 
 func startSlasher(cliCtx *cli.Context) error {
-	verbosity := cliCtx.String(cmd.VerbosityFlag.Name)
-	level, err := logrus.ParseLevel(verbosity)
-	if err != nil {
+	if err := logutil.SetVerbosity(cliCtx.String(cmd.VerbosityFlag.Name), cliCtx.String(cmd.VModuleFlag.Name)); err != nil {
 		return err
 	}
-	logrus.SetLevel(level)
 	slasher, err := node.NewSlasherNode(cliCtx)
 	if err != nil {
 		return err
@@ -41,6 +35,7 @@ func startSlasher(cliCtx *cli.Context) error {
 
 var appFlags = []cli.Flag{
 	cmd.VerbosityFlag,
+	cmd.VModuleFlag,
 	cmd.DataDirFlag,
 	cmd.EnableTracingFlag,
 	cmd.TracingProcessNameFlag,
@@ -64,6 +59,9 @@ var appFlags = []cli.Flag{
 	flags.KeyFlag,
 	flags.RebuildSpanMapsFlag,
 	flags.BeaconCertFlag,
+	flags.BeaconClientCertFlag,
+	flags.BeaconClientKeyFlag,
+	flags.BeaconRPCAuthTokenFileFlag,
 	flags.BeaconRPCProviderFlag,
 }
 
@@ -89,25 +87,11 @@ func main() {
 			}
 		}
 
-		format := ctx.String(cmd.LogFormat.Name)
-		switch format {
-		case "text":
-			formatter := new(prefixed.TextFormatter)
-			formatter.TimestampFormat = "2006-01-02 15:04:05"
-			formatter.FullTimestamp = true
-			// If persistent log files are written - we disable the log messages coloring because
-			// the colors are ANSI codes and seen as Gibberish in the log files.
-			formatter.DisableColors = ctx.String(cmd.LogFileName.Name) != ""
-			logrus.SetFormatter(formatter)
-			break
-		case "fluentd":
-			logrus.SetFormatter(joonix.NewFormatter())
-			break
-		case "json":
-			logrus.SetFormatter(&logrus.JSONFormatter{})
-			break
-		default:
-			return fmt.Errorf("unknown log format %s", format)
+		// If persistent log files are written - we disable the log messages coloring because
+		// the colors are ANSI codes and seen as gibberish in the log files.
+		disableColors := ctx.String(cmd.LogFileName.Name) != ""
+		if err := logutil.ConfigureFormatter(ctx.String(cmd.LogFormat.Name), disableColors); err != nil {
+			return err
 		}
 
 		logFileName := ctx.String(cmd.LogFileName.Name)
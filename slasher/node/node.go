@@ -18,7 +18,10 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/grpcutils"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
 	"github.com/prysmaticlabs/prysm/shared/prometheus"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
 	"github.com/prysmaticlabs/prysm/shared/tracing"
 	"github.com/prysmaticlabs/prysm/slasher/beaconclient"
 	"github.com/prysmaticlabs/prysm/slasher/db"
@@ -28,6 +31,7 @@ import (
 	"github.com/prysmaticlabs/prysm/slasher/rpc"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc/credentials"
 )
 
 var log = logrus.WithField("prefix", "node")
@@ -145,7 +149,7 @@ func (s *SlasherNode) registerPrometheusService() error {
 		fmt.Sprintf(":%d", s.cliCtx.Int64(flags.MonitoringPortFlag.Name)),
 		s.services,
 	)
-	logrus.AddHook(prometheus.NewLogrusCollector())
+	logrus.AddHook(logutil.ModuleLevelHook(prometheus.NewLogrusCollector()))
 	return s.services.RegisterService(service)
 }
 
@@ -185,14 +189,35 @@ func (s *SlasherNode) startDB() error {
 }
 
 func (s *SlasherNode) registerBeaconClientService() error {
-	beaconCert := s.cliCtx.String(flags.BeaconCertFlag.Name)
 	beaconProvider := s.cliCtx.String(flags.BeaconRPCProviderFlag.Name)
 	if beaconProvider == "" {
 		beaconProvider = flags.BeaconRPCProviderFlag.Value
 	}
+	var beaconCreds credentials.TransportCredentials
+	if beaconCert := s.cliCtx.String(flags.BeaconCertFlag.Name); beaconCert != "" {
+		creds, err := grpcutils.ClientTLSCredentials(
+			beaconCert,
+			s.cliCtx.String(flags.BeaconClientCertFlag.Name),
+			s.cliCtx.String(flags.BeaconClientKeyFlag.Name),
+		)
+		if err != nil {
+			return errors.Wrap(err, "invalid beacon node TLS configuration")
+		}
+		beaconCreds = creds
+	}
+	var authToken string
+	if tokenFile := s.cliCtx.String(flags.BeaconRPCAuthTokenFileFlag.Name); tokenFile != "" {
+		token, err := rpcauth.ReadToken(tokenFile)
+		if err != nil {
+			return errors.Wrap(err, "could not read beacon node auth token")
+		}
+		logutil.RegisterSecret(token)
+		authToken = token
+	}
 
 	bs, err := beaconclient.NewBeaconClientService(s.ctx, &beaconclient.Config{
-		BeaconCert:            beaconCert,
+		BeaconCreds:           beaconCreds,
+		AuthToken:             authToken,
 		SlasherDB:             s.db,
 		BeaconProvider:        beaconProvider,
 		AttesterSlashingsFeed: s.attesterSlashingsFeed,
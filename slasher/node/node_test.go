@@ -40,3 +40,25 @@ func TestNodeClose_OK(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNewSlasherNode_MissingAuthToken(t *testing.T) {
+	tmp := fmt.Sprintf("%s/datadirtest3", testutil.TempDir())
+	if err := os.RemoveAll(tmp); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tmp); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String("beacon-rpc-provider", "localhost:4232", "beacon node RPC server")
+	set.String("beacon-rpc-auth-token-file", fmt.Sprintf("%s/missing-token", tmp), "beacon node auth token")
+	set.String("datadir", tmp, "node data directory")
+
+	if _, err := NewSlasherNode(cli.NewContext(&app, set, nil)); err == nil {
+		t.Error("Wanted an error for a missing auth token file")
+	}
+}
@@ -45,6 +45,7 @@ var appHelpFlagGroups = []flagGroup{
 		Name: "cmd",
 		Flags: []cli.Flag{
 			cmd.VerbosityFlag,
+			cmd.VModuleFlag,
 			cmd.DataDirFlag,
 			cmd.EnableTracingFlag,
 			cmd.TracingProcessNameFlag,
@@ -73,6 +74,9 @@ var appHelpFlagGroups = []flagGroup{
 		Name: "slasher",
 		Flags: []cli.Flag{
 			flags.BeaconCertFlag,
+			flags.BeaconClientCertFlag,
+			flags.BeaconClientKeyFlag,
+			flags.BeaconRPCAuthTokenFileFlag,
 			flags.CertFlag,
 			flags.KeyFlag,
 			flags.RPCPort,
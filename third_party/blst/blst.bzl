@@ -0,0 +1,23 @@
+load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")
+
+"""
+Supranational's blst library, with its go bindings. The archive is the go module zip of
+github.com/supranational/blst.
+"""
+
+def blst_dependencies():
+    _maybe(
+        http_archive,
+        name = "supranational_blst",
+        strip_prefix = "github.com/supranational/blst@v0.3.14",
+        urls = [
+            "https://proxy.golang.org/github.com/supranational/blst/@v/v0.3.14.zip",
+        ],
+        type = "zip",
+        sha256 = "74bd51ab041eedc4aa8d16b51d8b7aa64183aba2b7283f83d6bd0c45ebcfb9cb",
+        build_file = "@prysm//third_party/blst:blst.BUILD",
+    )
+
+def _maybe(repo_rule, name, **kwargs):
+    if name not in native.existing_rules():
+        repo_rule(name = name, **kwargs)
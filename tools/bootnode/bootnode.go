@@ -17,9 +17,12 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -38,6 +41,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
@@ -60,6 +64,7 @@ var (
 	metricsPort   = flag.Int("metrics-port", 5000, "Port to listen for connections")
 	externalIP    = flag.String("external-ip", "", "External IP for the bootnode")
 	disableKad    = flag.Bool("disable-kad", false, "Disables the bootnode from running kademlia dht")
+	dataDir       = flag.String("datadir", "", "Directory to persist the bootnode private key and node database across restarts")
 	log           = logrus.WithField("prefix", "bootnode")
 	kadPeersCount = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "bootstrap_node_kaddht_peers",
@@ -69,10 +74,25 @@ var (
 		Name: "bootstrap_node_discv5_peers",
 		Help: "The current number of discv5 peers of the bootstrap node",
 	})
+	discv5LookupDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bootstrap_node_discv5_lookup_duration_seconds",
+		Help:    "Duration of the random discv5 lookups performed by the bootstrap node to refresh its table",
+		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30},
+	})
+	discv5LookupResults = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bootstrap_node_discv5_lookup_results",
+		Help: "The number of nodes returned by the last random discv5 lookup of the bootstrap node",
+	})
+	enrRequestsCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bootstrap_node_enr_requests_total",
+		Help: "The number of times the bootstrap node ENR was served over HTTP",
+	})
 )
 
 const dhtProtocol = "/prysm/0.0.0/dht"
 const defaultIP = "127.0.0.1"
+const privateKeyFileName = "network-keys"
+const nodeDBDirName = "nodes"
 
 type handler struct {
 	listener *discover.UDPv5
@@ -122,16 +142,24 @@ func main() {
 	}
 	mux := http.NewServeMux()
 	mux.HandleFunc("/p2p", handler.httpHandler)
+	mux.HandleFunc("/enr", handler.enrHandler)
+	mux.Handle("/metrics", promhttp.Handler())
 
-	if err := http.ListenAndServe(fmt.Sprintf(":%d", *metricsPort), mux); err != nil {
-		log.Fatalf("Failed to start server %v", err)
-	}
+	go func() {
+		if err := http.ListenAndServe(fmt.Sprintf(":%d", *metricsPort), mux); err != nil {
+			log.Fatalf("Failed to start server %v", err)
+		}
+	}()
 
 	// Update metrics once per slot.
 	slotDuration := time.Duration(params.BeaconConfig().SecondsPerSlot)
 	runutil.RunEvery(context.Background(), slotDuration*time.Second, func() {
 		updateMetrics(listener, dhtValue)
 	})
+	// Perform a random lookup once per epoch to keep the table fresh and track query latency.
+	runutil.RunEvery(context.Background(), slotDuration*time.Duration(params.BeaconConfig().SlotsPerEpoch)*time.Second, func() {
+		randomLookup(listener)
+	})
 
 	select {}
 }
@@ -224,8 +252,24 @@ func (h *handler) httpHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// enrHandler serves the bootnode's ENR so it can be easily distributed to testnet participants.
+func (h *handler) enrHandler(w http.ResponseWriter, _ *http.Request) {
+	enrRequestsCount.Inc()
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(h.listener.Self().String() + "\n")); err != nil {
+		log.WithError(err).Error("Failed to write to http response")
+	}
+}
+
 func createLocalNode(privKey *ecdsa.PrivateKey, ipAddr net.IP, port int) (*enode.LocalNode, error) {
-	db, err := enode.OpenDB("")
+	// An empty path opens an in-memory database, otherwise the node record and its sequence
+	// number are persisted across restarts.
+	dbPath := ""
+	if *dataDir != "" {
+		dbPath = filepath.Join(*dataDir, nodeDBDirName)
+	}
+	db, err := enode.OpenDB(dbPath)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not open node's peer database")
 	}
@@ -272,6 +316,13 @@ func extractPrivateKey() (*ecdsa.PrivateKey, crypto.PrivKey) {
 		interfaceKey = unmarshalledKey
 		privKey = (*ecdsa.PrivateKey)((*btcec.PrivateKey)(unmarshalledKey.(*crypto.Secp256k1PrivateKey)))
 
+	} else if *dataDir != "" {
+		privInterfaceKey, err := loadOrCreatePrivateKey(*dataDir)
+		if err != nil {
+			panic(err)
+		}
+		interfaceKey = privInterfaceKey
+		privKey = (*ecdsa.PrivateKey)((*btcec.PrivateKey)(privInterfaceKey.(*crypto.Secp256k1PrivateKey)))
 	} else {
 		privInterfaceKey, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
 		if err != nil {
@@ -290,6 +341,51 @@ func extractPrivateKey() (*ecdsa.PrivateKey, crypto.PrivKey) {
 	return privKey, interfaceKey
 }
 
+// loadOrCreatePrivateKey reads the hex encoded private key persisted in the given directory,
+// generating and persisting a new one if none exists yet, so the bootnode keeps the same
+// identity and ENR across restarts.
+func loadOrCreatePrivateKey(dir string) (crypto.PrivKey, error) {
+	keyPath := filepath.Join(dir, privateKeyFileName)
+	if enc, err := ioutil.ReadFile(keyPath); err == nil {
+		dst, err := hex.DecodeString(strings.TrimSpace(string(enc)))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode persisted private key")
+		}
+		log.WithField("path", keyPath).Info("Loaded persisted private key")
+		return crypto.UnmarshalSecp256k1PrivateKey(dst)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	privKey, _, err := crypto.GenerateSecp256k1Key(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := privKey.Raw()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(keyPath, []byte(hex.EncodeToString(raw)), 0600); err != nil {
+		return nil, err
+	}
+	log.WithField("path", keyPath).Info("Generated and persisted new private key")
+	return privKey, nil
+}
+
+func randomLookup(listener *discover.UDPv5) {
+	var target enode.ID
+	if _, err := rand.Read(target[:]); err != nil {
+		log.WithError(err).Error("Could not generate random lookup target")
+		return
+	}
+	start := time.Now()
+	nodes := listener.Lookup(target)
+	discv5LookupDuration.Observe(time.Since(start).Seconds())
+	discv5LookupResults.Set(float64(len(nodes)))
+}
+
 func updateMetrics(listener *discover.UDPv5, dht *kaddht.IpfsDHT) {
 	if dht != nil {
 		kadPeersCount.Set(float64(len(dht.Host().Peerstore().Peers())))
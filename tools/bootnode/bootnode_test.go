@@ -4,6 +4,8 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
@@ -88,3 +90,27 @@ func TestPrivateKey_ParsesCorrectly(t *testing.T) {
 	}
 	*privateKey = ""
 }
+
+func TestPrivateKey_PersistedInDataDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootnode")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	first, err := loadOrCreatePrivateKey(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := loadOrCreatePrivateKey(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Equals(second) {
+		t.Error("Expected the persisted private key to be reused")
+	}
+}
@@ -1,20 +1,46 @@
 package main
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"strings"
 
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/interop"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
 
+// depositDataJSON is the deposit data format written by the eth2 deposit tooling.
+type depositDataJSON struct {
+	PubKey                string `json:"pubkey"`
+	Amount                uint64 `json:"amount"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+	Signature             string `json:"signature"`
+}
+
+// interopKey is a single deterministic interop key pair, written in the same
+// format as the interop keygen test vectors.
+type interopKey struct {
+	Index   uint64 `json:"index"`
+	PrivKey string `json:"privkey"`
+	PubKey  string `json:"pubkey"`
+}
+
 var (
 	numValidators    = flag.Int("num-validators", 0, "Number of validators to deterministically include in the generated genesis state")
 	useMainnetConfig = flag.Bool("mainnet-config", false, "Select whether genesis state should be generated with mainnet or minimal (default) params")
+	chainConfigFile  = flag.String("chain-config-file", "", "Optional YAML chain config file applied on top of the selected params")
+	depositJSONFile  = flag.String("deposit-json-file", "", "Path to a deposit data JSON file to use instead of deterministic interop keys")
+	keysOutputFile   = flag.String("output-keys", "", "Output filename of the YAML list of deterministic interop keys used in the genesis state")
 	genesisTime      = flag.Uint64("genesis-time", 0, "Unix timestamp used as the genesis time in the generated genesis state (defaults to now)")
 	sszOutputFile    = flag.String("output-ssz", "", "Output filename of the SSZ marshaling of the generated genesis state")
 	yamlOutputFile   = flag.String("output-yaml", "", "Output filename of the YAML marshaling of the generated genesis state")
@@ -23,8 +49,14 @@ var (
 
 func main() {
 	flag.Parse()
-	if *numValidators == 0 {
-		log.Fatal("Expected --num-validators to have been provided, received 0")
+	if *numValidators == 0 && *depositJSONFile == "" {
+		log.Fatal("Expected --num-validators or --deposit-json-file to have been provided")
+	}
+	if *numValidators != 0 && *depositJSONFile != "" {
+		log.Fatal("Only one of --num-validators or --deposit-json-file may be provided")
+	}
+	if *keysOutputFile != "" && *depositJSONFile != "" {
+		log.Fatal("--output-keys can only be used with deterministic interop keys")
 	}
 	if *genesisTime == 0 {
 		log.Print("No --genesis-time specified, defaulting to now")
@@ -35,11 +67,26 @@ func main() {
 	if !*useMainnetConfig {
 		params.OverrideBeaconConfig(params.MinimalSpecConfig())
 	}
+	if *chainConfigFile != "" {
+		params.LoadChainConfigFile(*chainConfigFile)
+	}
 
-	genesisState, _, err := interop.GenerateGenesisState(*genesisTime, uint64(*numValidators))
+	var genesisState *pb.BeaconState
+	var err error
+	if *depositJSONFile != "" {
+		genesisState, err = genesisStateFromJSONDeposits(*depositJSONFile)
+	} else {
+		genesisState, _, err = interop.GenerateGenesisState(*genesisTime, uint64(*numValidators))
+	}
 	if err != nil {
 		log.Fatalf("Could not generate genesis beacon state: %v", err)
 	}
+	if *keysOutputFile != "" {
+		if err := writeInteropKeys(*keysOutputFile, uint64(*numValidators)); err != nil {
+			log.Fatalf("Could not write interop keys: %v", err)
+		}
+		log.Printf("Done writing to %s", *keysOutputFile)
+	}
 	if *sszOutputFile != "" {
 		encodedState, err := ssz.Marshal(genesisState)
 		if err != nil {
@@ -71,3 +118,71 @@ func main() {
 		log.Printf("Done writing to %s", *jsonOutputFile)
 	}
 }
+
+func genesisStateFromJSONDeposits(fileName string) (*pb.BeaconState, error) {
+	enc, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var depositJSON []*depositDataJSON
+	if err := json.Unmarshal(enc, &depositJSON); err != nil {
+		return nil, err
+	}
+	depositDataList := make([]*ethpb.Deposit_Data, len(depositJSON))
+	depositDataRoots := make([][]byte, len(depositJSON))
+	for i, item := range depositJSON {
+		data, err := depositJSONToDepositData(item)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode deposit data at index %d", i)
+		}
+		root, err := ssz.HashTreeRoot(data)
+		if err != nil {
+			return nil, err
+		}
+		depositDataList[i] = data
+		depositDataRoots[i] = root[:]
+	}
+	genesisState, _, err := interop.GenerateGenesisStateFromDepositData(*genesisTime, depositDataList, depositDataRoots)
+	return genesisState, err
+}
+
+func depositJSONToDepositData(item *depositDataJSON) (*ethpb.Deposit_Data, error) {
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(item.PubKey, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid pubkey")
+	}
+	withdrawalCreds, err := hex.DecodeString(strings.TrimPrefix(item.WithdrawalCredentials, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid withdrawal credentials")
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(item.Signature, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+	return &ethpb.Deposit_Data{
+		PublicKey:             pubKey,
+		WithdrawalCredentials: withdrawalCreds,
+		Amount:                item.Amount,
+		Signature:             signature,
+	}, nil
+}
+
+func writeInteropKeys(fileName string, numKeys uint64) error {
+	privKeys, pubKeys, err := interop.DeterministicallyGenerateKeys(0 /*startIndex*/, numKeys)
+	if err != nil {
+		return err
+	}
+	keys := make([]*interopKey, numKeys)
+	for i := uint64(0); i < numKeys; i++ {
+		keys[i] = &interopKey{
+			Index:   i,
+			PrivKey: fmt.Sprintf("%#x", privKeys[i].Marshal()),
+			PubKey:  fmt.Sprintf("%#x", pubKeys[i].Marshal()),
+		}
+	}
+	enc, err := yaml.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, enc, 0600)
+}
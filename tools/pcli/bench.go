@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	log "github.com/sirupsen/logrus"
+)
+
+// blsImplementations are the BLS libraries compared by the bls benchmark.
+var blsImplementations = []string{featureconfig.HerumiBLS, featureconfig.BlstBLS}
+
+type blsBenchmark struct {
+	name string
+	run  func(b *testing.B)
+}
+
+// blsBenchmarks returns the benchmarks of the BLS operations done by the clients, with
+// aggregates of aggregateSize signatures. Keys and signatures are created with the BLS
+// library configured when it is called.
+func blsBenchmarks(aggregateSize int) []blsBenchmark {
+	msg := [32]byte{'b', 'e', 'n', 'c', 'h'}
+	pubKeys := make([]*bls.PublicKey, aggregateSize)
+	sigs := make([]*bls.Signature, aggregateSize)
+	msgs := make([][32]byte, aggregateSize)
+	set := bls.NewSet()
+	for i := range sigs {
+		secKey := bls.RandKey()
+		pubKeys[i] = secKey.PublicKey()
+		sigs[i] = secKey.Sign(msg[:])
+		msgs[i] = [32]byte{byte(i), byte(i >> 8)}
+		set.Add(secKey.Sign(msgs[i][:]), pubKeys[i], msgs[i], "")
+	}
+	aggSig := bls.AggregateSignatures(sigs)
+	sigBytes := sigs[0].Marshal()
+	pubKeyBytes := pubKeys[0].Marshal()
+
+	return []blsBenchmark{
+		{
+			name: "sign",
+			run: func(b *testing.B) {
+				secKey := bls.RandKey()
+				for i := 0; i < b.N; i++ {
+					secKey.Sign(msg[:])
+				}
+			},
+		},
+		{
+			name: "verify",
+			run: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					sigs[0].Verify(pubKeys[0], msg[:])
+				}
+			},
+		},
+		{
+			name: "signature from bytes",
+			run: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := bls.SignatureFromBytes(sigBytes); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		},
+		{
+			// Public keys are cached, so this is mostly a cache lookup as in the clients.
+			name: "public key from bytes",
+			run: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := bls.PublicKeyFromBytes(pubKeyBytes); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		},
+		{
+			name: fmt.Sprintf("aggregate %d signatures", aggregateSize),
+			run: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					bls.AggregateSignatures(sigs)
+				}
+			},
+		},
+		{
+			name: fmt.Sprintf("fast aggregate verify %d keys", aggregateSize),
+			run: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					aggSig.FastAggregateVerify(pubKeys, msg)
+				}
+			},
+		},
+		{
+			name: fmt.Sprintf("batch verify %d signatures", aggregateSize),
+			run: func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := set.Verify(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			},
+		},
+	}
+}
+
+// benchmarkBLS runs the BLS benchmarks with each BLS library and writes the time per
+// operation and the speedup of blst over herumi to w.
+func benchmarkBLS(w io.Writer, aggregateSize int) error {
+	if aggregateSize < 1 {
+		return fmt.Errorf("aggregate size must be at least 1, received %d", aggregateSize)
+	}
+	defer featureconfig.Init(featureconfig.Get())
+
+	var names []string
+	results := make(map[string][]time.Duration, len(blsImplementations))
+	for _, impl := range blsImplementations {
+		featureconfig.Init(&featureconfig.Flags{BLSImplementation: impl})
+		benchmarks := blsBenchmarks(aggregateSize)
+		names = names[:0]
+		for _, bm := range benchmarks {
+			log.WithField("implementation", impl).Infof("Benchmarking %s", bm.name)
+			res := testing.Benchmark(bm.run)
+			results[impl] = append(results[impl], time.Duration(res.NsPerOp()))
+			names = append(names, bm.name)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "operation\therumi\tblst\tspeedup")
+	for i, name := range names {
+		herumi, blst := results[featureconfig.HerumiBLS][i], results[featureconfig.BlstBLS][i]
+		speedup := "-"
+		if blst > 0 {
+			speedup = fmt.Sprintf("%.2fx", float64(herumi)/float64(blst))
+		}
+		fmt.Fprintf(tw, "%s\t%v\t%v\t%s\n", name, herumi, blst, speedup)
+	}
+	return tw.Flush()
+}
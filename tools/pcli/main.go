@@ -3,9 +3,11 @@ package main
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -27,8 +29,11 @@ func main() {
 	var blockPath string
 	var preStatePath string
 	var expectedPostStatePath string
+	var postStateOutputPath string
 	var sszPath string
 	var sszType string
+	var outputFormat string
+	var aggregateSize int
 
 	customFormatter := new(prefixed.TextFormatter)
 	customFormatter.TimestampFormat = "2006-01-02 15:04:05"
@@ -52,51 +57,49 @@ func main() {
 				},
 				&cli.StringFlag{
 					Name: "data-type",
-					Usage: "ssz file data type: " +
-						"block|" +
-						"signed_block|" +
-						"attestation|" +
-						"block_header|" +
-						"deposit|" +
-						"proposer_slashing|" +
-						"signed_block_header|" +
-						"signed_voluntary_exit|" +
-						"voluntary_exit|" +
-						"state",
+					Usage: "ssz file data type, either a spec container name (e.g. BeaconState) or one of: " +
+						strings.Join(sszTypeNames(), "|"),
 					Required:    true,
 					Destination: &sszType,
 				},
+				&cli.StringFlag{
+					Name:        "output-format",
+					Usage:       "output format of the decoded data: text|json",
+					Value:       "text",
+					Destination: &outputFormat,
+				},
 			},
 			Action: func(c *cli.Context) error {
-				var data interface{}
-				switch sszType {
-				case "block":
-					data = &ethpb.BeaconBlock{}
-				case "signed_block":
-					data = &ethpb.SignedBeaconBlock{}
-				case "attestation":
-					data = &ethpb.Attestation{}
-				case "block_header":
-					data = &ethpb.BeaconBlockHeader{}
-				case "deposit":
-					data = &ethpb.Deposit{}
-				case "proposer_slashing":
-					data = &ethpb.ProposerSlashing{}
-				case "signed_block_header":
-					data = &ethpb.SignedBeaconBlockHeader{}
-				case "signed_voluntary_exit":
-					data = &ethpb.SignedVoluntaryExit{}
-				case "voluntary_exit":
-					data = &ethpb.VoluntaryExit{}
-				case "state":
-					data = &pb.BeaconState{}
-				default:
-					log.Fatal("Invalid type")
+				data, err := newSSZObject(sszType)
+				if err != nil {
+					log.Fatal(err)
 				}
-				prettyPrint(sszPath, data)
+				prettyPrint(sszPath, data, outputFormat)
 				return nil
 			},
 		},
+		{
+			Name:     "bench",
+			Category: "bench",
+			Usage:    "Subcommand to run benchmarks",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "bls",
+					Usage: "compare the time per BLS operation of the herumi and blst libraries",
+					Flags: []cli.Flag{
+						&cli.IntFlag{
+							Name:        "aggregate-size",
+							Usage:       "Number of signatures and public keys in the aggregate operations",
+							Value:       128,
+							Destination: &aggregateSize,
+						},
+					},
+					Action: func(c *cli.Context) error {
+						return benchmarkBLS(os.Stdout, aggregateSize)
+					},
+				},
+			},
+		},
 		{
 			Name:     "state-transition",
 			Category: "state-transition",
@@ -117,6 +120,11 @@ func main() {
 					Usage:       "Path to expected post state file(ssz)",
 					Destination: &expectedPostStatePath,
 				},
+				&cli.StringFlag{
+					Name:        "output-post-state-path",
+					Usage:       "Path to write the resulting post state file(ssz)",
+					Destination: &postStateOutputPath,
+				},
 			},
 			Action: func(c *cli.Context) error {
 				if blockPath == "" {
@@ -178,7 +186,21 @@ func main() {
 					log.Fatal(err)
 				}
 				postRoot, err := postState.HashTreeRoot(context.Background())
-				log.Infof("Finished state transition with post state root of %#x", postRoot)
+				if err != nil {
+					log.Fatal(err)
+				}
+				log.WithField("postStateSlot", postState.Slot()).Infof("Finished state transition with post state root of %#x", postRoot)
+
+				if postStateOutputPath != "" {
+					enc, err := postState.InnerStateUnsafe().MarshalSSZ()
+					if err != nil {
+						log.Fatal(err)
+					}
+					if err := ioutil.WriteFile(postStateOutputPath, enc, 0644); err != nil {
+						log.Fatal(err)
+					}
+					log.Infof("Wrote post state to %s", postStateOutputPath)
+				}
 
 				// Diff the state if a post state is provided.
 				if expectedPostStatePath != "" {
@@ -189,7 +211,9 @@ func main() {
 					if !ssz.DeepEqual(expectedState, postState.InnerStateUnsafe()) {
 						diff, _ := messagediff.PrettyDiff(expectedState, postState.InnerStateUnsafe())
 						log.Errorf("Derived state differs from provided post state: %s", diff)
+						return fmt.Errorf("post state root %#x does not match expected post state", postRoot)
 					}
+					log.Info("Derived state matches provided post state")
 				}
 				return nil
 			},
@@ -210,12 +234,31 @@ func dataFetcher(fPath string, data interface{}) error {
 	return ssz.Unmarshal(rawFile, data)
 }
 
-func prettyPrint(sszPath string, data interface{}) {
+func prettyPrint(sszPath string, data interface{}, format string) {
 	if err := dataFetcher(sszPath, data); err != nil {
 		log.Fatal(err)
 	}
-	str := pretty.Sprint(data)
-	re := regexp.MustCompile("(?m)[\r\n]+^.*XXX_.*$")
-	str = re.ReplaceAllString(str, "")
-	fmt.Print(str)
+	root, err := ssz.HashTreeRoot(data)
+	if err != nil {
+		log.Fatalf("Could not compute hash tree root: %v", err)
+	}
+	switch format {
+	case "json":
+		enc, err := json.MarshalIndent(map[string]interface{}{
+			"hash_tree_root": fmt.Sprintf("%#x", root),
+			"data":           jsonFriendly(reflect.ValueOf(data)),
+		}, "", "  ")
+		if err != nil {
+			log.Fatalf("Could not json marshal data: %v", err)
+		}
+		fmt.Println(string(enc))
+	case "text":
+		str := pretty.Sprint(data)
+		re := regexp.MustCompile("(?m)[\r\n]+^.*XXX_.*$")
+		str = re.ReplaceAllString(str, "")
+		fmt.Print(str)
+		fmt.Printf("\nhash_tree_root: %#x\n", root)
+	default:
+		log.Fatalf("Unknown output format %q, wanted text|json", format)
+	}
 }
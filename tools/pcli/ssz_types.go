@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// sszTypes maps the supported data type names to constructors of empty objects.
+// Both the snake case names and the spec container names (e.g. BeaconState) are accepted.
+var sszTypes = map[string]func() interface{}{
+	"block":                           func() interface{} { return &ethpb.BeaconBlock{} },
+	"signed_block":                    func() interface{} { return &ethpb.SignedBeaconBlock{} },
+	"block_body":                      func() interface{} { return &ethpb.BeaconBlockBody{} },
+	"attestation":                     func() interface{} { return &ethpb.Attestation{} },
+	"attestation_data":                func() interface{} { return &ethpb.AttestationData{} },
+	"indexed_attestation":             func() interface{} { return &ethpb.IndexedAttestation{} },
+	"aggregate_attestation_and_proof": func() interface{} { return &ethpb.AggregateAttestationAndProof{} },
+	"signed_aggregate_attestation_and_proof": func() interface{} {
+		return &ethpb.SignedAggregateAttestationAndProof{}
+	},
+	"attester_slashing":     func() interface{} { return &ethpb.AttesterSlashing{} },
+	"block_header":          func() interface{} { return &ethpb.BeaconBlockHeader{} },
+	"checkpoint":            func() interface{} { return &ethpb.Checkpoint{} },
+	"deposit":               func() interface{} { return &ethpb.Deposit{} },
+	"deposit_data":          func() interface{} { return &ethpb.Deposit_Data{} },
+	"eth1_data":             func() interface{} { return &ethpb.Eth1Data{} },
+	"fork":                  func() interface{} { return &pb.Fork{} },
+	"historical_batch":      func() interface{} { return &pb.HistoricalBatch{} },
+	"proposer_slashing":     func() interface{} { return &ethpb.ProposerSlashing{} },
+	"signed_block_header":   func() interface{} { return &ethpb.SignedBeaconBlockHeader{} },
+	"signed_voluntary_exit": func() interface{} { return &ethpb.SignedVoluntaryExit{} },
+	"validator":             func() interface{} { return &ethpb.Validator{} },
+	"voluntary_exit":        func() interface{} { return &ethpb.VoluntaryExit{} },
+	"state":                 func() interface{} { return &pb.BeaconState{} },
+}
+
+// specTypeNames maps spec container names to the snake case names above.
+var specTypeNames = map[string]string{
+	"BeaconBlock":                        "block",
+	"SignedBeaconBlock":                  "signed_block",
+	"BeaconBlockBody":                    "block_body",
+	"Attestation":                        "attestation",
+	"AttestationData":                    "attestation_data",
+	"IndexedAttestation":                 "indexed_attestation",
+	"AggregateAndProof":                  "aggregate_attestation_and_proof",
+	"SignedAggregateAndProof":            "signed_aggregate_attestation_and_proof",
+	"SignedAggregateAttestationAndProof": "signed_aggregate_attestation_and_proof",
+	"AttesterSlashing":                   "attester_slashing",
+	"BeaconBlockHeader":                  "block_header",
+	"Checkpoint":                         "checkpoint",
+	"Deposit":                            "deposit",
+	"DepositData":                        "deposit_data",
+	"Eth1Data":                           "eth1_data",
+	"Fork":                               "fork",
+	"HistoricalBatch":                    "historical_batch",
+	"ProposerSlashing":                   "proposer_slashing",
+	"SignedBeaconBlockHeader":            "signed_block_header",
+	"SignedVoluntaryExit":                "signed_voluntary_exit",
+	"Validator":                          "validator",
+	"VoluntaryExit":                      "voluntary_exit",
+	"BeaconState":                        "state",
+}
+
+// newSSZObject returns an empty object for the given data type name.
+func newSSZObject(name string) (interface{}, error) {
+	if snake, ok := specTypeNames[name]; ok {
+		name = snake
+	}
+	ctor, ok := sszTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown data type %q, wanted one of: %s", name, strings.Join(sszTypeNames(), "|"))
+	}
+	return ctor(), nil
+}
+
+func sszTypeNames() []string {
+	names := make([]string, 0, len(sszTypes))
+	for k := range sszTypes {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonFriendly converts a decoded SSZ object into plain maps, slices and values
+// suitable for JSON encoding, rendering byte slices as 0x-prefixed hex strings
+// and dropping protobuf internal fields.
+func jsonFriendly(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return jsonFriendly(v.Elem())
+	case reflect.Struct:
+		out := make(map[string]interface{})
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" || strings.HasPrefix(f.Name, "XXX_") {
+				continue
+			}
+			out[f.Name] = jsonFriendly(v.Field(i))
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return fmt.Sprintf("%#x", b)
+		}
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = jsonFriendly(v.Index(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
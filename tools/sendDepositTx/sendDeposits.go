@@ -4,20 +4,19 @@ import (
 	"bufio"
 	"encoding/hex"
 	"fmt"
-	"io/ioutil"
 	"math/big"
 	"os"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 	contracts "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
 	prysmKeyStore "github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/sirupsen/logrus"
@@ -47,6 +46,12 @@ func main() {
 	customFormatter.FullTimestamp = true
 	logrus.SetFormatter(customFormatter)
 
+	privKeyFlag := &cli.StringFlag{
+		Name:        "privKey",
+		Usage:       "Private key to send ETH transaction, can also be provided with PRYSM_PRIVKEY or PRYSM_PRIVKEY_FILE",
+		Destination: &privKeyString,
+	}
+
 	app := cli.App{}
 	app.Name = "sendDepositTx"
 	app.Usage = "this is a util to send deposit transactions"
@@ -79,11 +84,7 @@ func main() {
 			Usage:       "Password file for unlock account",
 			Destination: &passwordFile,
 		},
-		&cli.StringFlag{
-			Name:        "privKey",
-			Usage:       "Private key to send ETH transaction",
-			Destination: &privKeyString,
-		},
+		privKeyFlag,
 		&cli.StringFlag{
 			Name:        "depositContract",
 			Usage:       "Address of the deposit contract",
@@ -114,6 +115,11 @@ func main() {
 		},
 	}
 
+	app.Commands = []*cli.Command{submitCommand}
+	app.Before = func(c *cli.Context) error {
+		logrus.AddHook(&logutil.RedactionHook{})
+		return cmd.LoadSecrets(c, []*cli.StringFlag{privKeyFlag})
+	}
 	app.Action = func(c *cli.Context) error {
 		// Set up RPC client
 		var rpcClient *rpc.Client
@@ -133,30 +139,12 @@ func main() {
 		client := ethclient.NewClient(rpcClient)
 		depositAmountInGwei := uint64(depositAmount)
 
-		if privKeyString != "" {
-			// User inputs private key, sign tx with private key
-			privKey, err := crypto.HexToECDSA(privKeyString)
-			if err != nil {
-				return err
-			}
-			txOps = bind.NewKeyedTransactor(privKey)
-			txOps.Value = new(big.Int).Mul(big.NewInt(depositAmount), big.NewInt(1e9))
-		} else {
-			// User inputs keystore json file, sign tx with keystore json
-			password := loadTextFromFile(passwordFile)
-
-			// #nosec - Inclusion of file via variable is OK for this tool.
-			keyJSON, err := ioutil.ReadFile(keystoreUTCPath)
-			if err != nil {
-				return err
-			}
-			privKey, err := keystore.DecryptKey(keyJSON, password)
-			if err != nil {
-				return err
-			}
-
-			txOps = bind.NewKeyedTransactor(privKey.PrivateKey)
-			txOps.Value = new(big.Int).Mul(big.NewInt(depositAmount), big.NewInt(1e9))
+		txOps, err = transactor(privKeyString, keystoreUTCPath, passwordFile)
+		if err != nil {
+			return err
+		}
+		txOps.Value = new(big.Int).Mul(big.NewInt(depositAmount), big.NewInt(1e9))
+		if privKeyString == "" {
 			txOps.GasLimit = 500000
 		}
 
@@ -179,7 +167,7 @@ func main() {
 			prefix := params.BeaconConfig().ValidatorPrivkeyFileName
 			validatorKeys, err = store.GetKeys(prysmKeystorePath, prefix, rawPassword, false /* warnOnFail */)
 			if err != nil {
-				log.WithField("path", prysmKeystorePath).WithField("password", rawPassword).Errorf("Could not get keys: %v", err)
+				log.WithField("path", prysmKeystorePath).Errorf("Could not get keys: %v", err)
 			}
 		}
 
@@ -215,6 +203,8 @@ func main() {
 	}
 }
 
+// loadTextFromFile returns the first word of the file, which is a password, so it is registered
+// as a secret to redact from the logs.
 func loadTextFromFile(filepath string) string {
 	// #nosec - Inclusion of file via variable is OK for this tool.
 	file, err := os.Open(filepath)
@@ -225,5 +215,6 @@ func loadTextFromFile(filepath string) string {
 	scanner := bufio.NewScanner(file)
 	scanner.Split(bufio.ScanWords)
 	scanner.Scan()
+	logutil.RegisterSecret(scanner.Text())
 	return scanner.Text()
 }
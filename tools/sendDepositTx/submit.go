@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	contracts "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	depositJSONFileFlag = &cli.StringFlag{
+		Name:     "deposit-json-file",
+		Usage:    "Path to a deposit_data.json file, as written by testnet-keys-gen or the eth2 deposit tooling",
+		Required: true,
+	}
+	nonceFlag = &cli.Int64Flag{
+		Name:  "nonce",
+		Usage: "Nonce of the first deposit transaction, defaults to the pending nonce of the sending account",
+		Value: -1,
+	}
+	startIndexFlag = &cli.Uint64Flag{
+		Name:  "start-index",
+		Usage: "Index of the first entry of the deposit data file to send, to resume a failed run without sending the earlier deposits again",
+	}
+	gasPriceFlag = &cli.Uint64Flag{
+		Name:  "gas-price",
+		Usage: "Gas price in gwei for the deposit transactions, defaults to the price suggested by the eth1 node",
+	}
+	maxGasPriceFlag = &cli.Uint64Flag{
+		Name:  "max-gas-price",
+		Usage: "Refuse to submit deposits if the gas price in gwei is above this value (0 disables the check)",
+	}
+	gasLimitFlag = &cli.Uint64Flag{
+		Name:  "gas-limit",
+		Usage: "Gas limit of each deposit transaction",
+		Value: 500000,
+	}
+	dryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Validate the deposit data and print the transactions that would be sent without submitting them",
+	}
+)
+
+var submitCommand = &cli.Command{
+	Name:  "submit",
+	Usage: "submits the deposits from a deposit data file to the deposit contract",
+	Description: `reads a deposit data file and sends one deposit transaction per entry to the deposit contract,
+using the connection, account and contract flags of the main command`,
+	Flags: []cli.Flag{
+		depositJSONFileFlag,
+		nonceFlag,
+		startIndexFlag,
+		gasPriceFlag,
+		maxGasPriceFlag,
+		gasLimitFlag,
+		dryRunFlag,
+	},
+	Action: submitDepositsCmd,
+}
+
+// depositDataJSON is a single deposit data entry in the format of the eth2 deposit tooling.
+type depositDataJSON struct {
+	PubKey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	Signature             string `json:"signature"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+}
+
+// pendingDeposit is a validated deposit ready to be sent to the contract.
+type pendingDeposit struct {
+	data *ethpb.Deposit_Data
+	root [32]byte
+}
+
+// submitConfig holds the transaction options of a deposit submission.
+type submitConfig struct {
+	nonce       int64
+	startIndex  uint64
+	gasPrice    *big.Int
+	maxGasPrice *big.Int
+	gasLimit    uint64
+	dryRun      bool
+	delay       time.Duration
+}
+
+func submitDepositsCmd(c *cli.Context) error {
+	enc, err := ioutil.ReadFile(c.String(depositJSONFileFlag.Name))
+	if err != nil {
+		return errors.Wrap(err, "could not read deposit data file")
+	}
+	deposits, err := decodeDeposits(enc)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.String("httpPath")
+	if ipc := c.String("ipcPath"); ipc != "" {
+		endpoint = ipc
+	}
+	rpcClient, err := rpc.Dial(endpoint)
+	if err != nil {
+		return errors.Wrap(err, "could not connect to eth1 endpoint")
+	}
+	client := ethclient.NewClient(rpcClient)
+	defer client.Close()
+
+	txOps, err := transactor(c.String("privKey"), c.String("keystoreUTCPath"), c.String("passwordFile"))
+	if err != nil {
+		return err
+	}
+	contractAddr := common.HexToAddress(c.String("depositContract"))
+	depositContract, err := contracts.NewDepositContract(contractAddr, client)
+	if err != nil {
+		return errors.Wrap(err, "could not bind deposit contract")
+	}
+
+	cfg := &submitConfig{
+		nonce:      c.Int64(nonceFlag.Name),
+		startIndex: c.Uint64(startIndexFlag.Name),
+		gasLimit:   c.Uint64(gasLimitFlag.Name),
+		dryRun:     c.Bool(dryRunFlag.Name),
+		delay:      time.Duration(c.Int64("depositDelay")) * time.Second,
+	}
+	if p := c.Uint64(gasPriceFlag.Name); p != 0 {
+		cfg.gasPrice = gweiToWei(p)
+	}
+	if p := c.Uint64(maxGasPriceFlag.Name); p != 0 {
+		cfg.maxGasPrice = gweiToWei(p)
+	}
+	_, err = submitDeposits(context.Background(), client, depositContract, txOps, deposits, cfg)
+	return err
+}
+
+// transactor creates the transaction signer from either a raw private key or an
+// eth1 keystore file unlocked with the password file.
+func transactor(privKeyString string, keystorePath string, passwordFile string) (*bind.TransactOpts, error) {
+	if privKeyString != "" {
+		privKey, err := crypto.HexToECDSA(strings.TrimPrefix(privKeyString, "0x"))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse private key")
+		}
+		return bind.NewKeyedTransactor(privKey), nil
+	}
+	// #nosec - Inclusion of file via variable is OK for this tool.
+	keyJSON, err := ioutil.ReadFile(keystorePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read keystore")
+	}
+	key, err := keystore.DecryptKey(keyJSON, loadTextFromFile(passwordFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt keystore")
+	}
+	return bind.NewKeyedTransactor(key.PrivateKey), nil
+}
+
+// decodeDeposits parses a deposit data file and checks that each entry is well formed
+// and that its deposit data root matches the hash tree root of the deposit data, so
+// corrupted files are rejected before any ether is spent.
+func decodeDeposits(enc []byte) ([]*pendingDeposit, error) {
+	var items []*depositDataJSON
+	if err := json.Unmarshal(enc, &items); err != nil {
+		return nil, errors.Wrap(err, "could not decode deposit data file")
+	}
+	if len(items) == 0 {
+		return nil, errors.New("deposit data file contains no deposits")
+	}
+	deposits := make([]*pendingDeposit, len(items))
+	for i, item := range items {
+		if item == nil {
+			return nil, fmt.Errorf("deposit %d is empty", i)
+		}
+		d, err := item.toPendingDeposit()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid deposit %d", i)
+		}
+		deposits[i] = d
+	}
+	return deposits, nil
+}
+
+func (d *depositDataJSON) toPendingDeposit() (*pendingDeposit, error) {
+	pubKey, err := decodeHex(d.PubKey, params.BeaconConfig().BLSPubkeyLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "pubkey")
+	}
+	creds, err := decodeHex(d.WithdrawalCredentials, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "withdrawal_credentials")
+	}
+	sig, err := decodeHex(d.Signature, params.BeaconConfig().BLSSignatureLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "signature")
+	}
+	root, err := decodeHex(d.DepositDataRoot, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "deposit_data_root")
+	}
+	if d.Amount < params.BeaconConfig().MinDepositAmount {
+		return nil, fmt.Errorf("amount %d is below the minimum deposit amount %d", d.Amount, params.BeaconConfig().MinDepositAmount)
+	}
+	data := &ethpb.Deposit_Data{
+		PublicKey:             pubKey,
+		WithdrawalCredentials: creds,
+		Amount:                d.Amount,
+		Signature:             sig,
+	}
+	htr, err := ssz.HashTreeRoot(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not hash deposit data")
+	}
+	if !bytes.Equal(htr[:], root) {
+		return nil, fmt.Errorf("deposit_data_root %#x does not match hash tree root %#x", root, htr)
+	}
+	return &pendingDeposit{data: data, root: htr}, nil
+}
+
+func decodeHex(s string, length int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != length {
+		return nil, fmt.Errorf("wanted %d bytes, received %d", length, len(b))
+	}
+	return b, nil
+}
+
+// submitDeposits sends a transaction for every deposit from the start index on, managing the
+// nonce locally so deposits can be submitted back to back without waiting for each to be mined.
+// A failed submission stops the run and reports the index and nonce to resume from, so the
+// deposits already sent are not sent again.
+func submitDeposits(
+	ctx context.Context,
+	backend bind.ContractBackend,
+	depositContract *contracts.DepositContract,
+	txOps *bind.TransactOpts,
+	deposits []*pendingDeposit,
+	cfg *submitConfig,
+) ([]common.Hash, error) {
+	if cfg.startIndex >= uint64(len(deposits)) {
+		return nil, fmt.Errorf("start index %d is not below the number of deposits %d", cfg.startIndex, len(deposits))
+	}
+	nonce := uint64(cfg.nonce)
+	if cfg.nonce < 0 {
+		n, err := backend.PendingNonceAt(ctx, txOps.From)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch account nonce")
+		}
+		nonce = n
+	}
+	gasPrice := cfg.gasPrice
+	if gasPrice == nil {
+		p, err := backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch gas price")
+		}
+		gasPrice = p
+	}
+	if cfg.maxGasPrice != nil && gasPrice.Cmp(cfg.maxGasPrice) > 0 {
+		return nil, fmt.Errorf("gas price %d wei is above the maximum of %d wei", gasPrice, cfg.maxGasPrice)
+	}
+
+	hashes := make([]common.Hash, 0, uint64(len(deposits))-cfg.startIndex)
+	for i := int(cfg.startIndex); i < len(deposits); i++ {
+		d := deposits[i]
+		opts := &bind.TransactOpts{
+			From:     txOps.From,
+			Signer:   txOps.Signer,
+			Nonce:    new(big.Int).SetUint64(nonce),
+			Value:    gweiToWei(d.data.Amount),
+			GasPrice: gasPrice,
+			GasLimit: cfg.gasLimit,
+			Context:  ctx,
+		}
+		logFields := logrus.Fields{
+			"index":    i,
+			"nonce":    nonce,
+			"pubkey":   fmt.Sprintf("%#x", d.data.PublicKey),
+			"amount":   d.data.Amount,
+			"gasPrice": gasPrice,
+		}
+		if cfg.dryRun {
+			log.WithFields(logFields).Info("Dry run, not sending deposit")
+			nonce++
+			continue
+		}
+		tx, err := depositContract.Deposit(opts, d.data.PublicKey, d.data.WithdrawalCredentials, d.data.Signature, d.root)
+		if err != nil {
+			return hashes, errors.Wrapf(err, "could not send deposit %d, resume with --start-index=%d --nonce=%d", i, i, nonce)
+		}
+		hashes = append(hashes, tx.Hash())
+		logFields["txHash"] = fmt.Sprintf("%#x", tx.Hash())
+		log.WithFields(logFields).Info("Deposit sent")
+		nonce++
+		if cfg.delay > 0 && i < len(deposits)-1 {
+			time.Sleep(cfg.delay)
+		}
+	}
+	return hashes, nil
+}
+
+func gweiToWei(gwei uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(gwei), big.NewInt(1e9))
+}
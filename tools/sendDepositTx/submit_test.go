@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	contracts "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
+	"github.com/prysmaticlabs/prysm/shared/interop"
+)
+
+func depositFile(t *testing.T, n uint64) []*depositDataJSON {
+	privKeys, pubKeys, err := interop.DeterministicallyGenerateKeys(0, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, roots, err := interop.DepositDataFromKeys(privKeys, pubKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]*depositDataJSON, len(items))
+	for i, item := range items {
+		out[i] = &depositDataJSON{
+			PubKey:                hex.EncodeToString(item.PublicKey),
+			WithdrawalCredentials: hex.EncodeToString(item.WithdrawalCredentials),
+			Amount:                item.Amount,
+			Signature:             "0x" + hex.EncodeToString(item.Signature),
+			DepositDataRoot:       hex.EncodeToString(roots[i]),
+		}
+	}
+	return out
+}
+
+func TestDecodeDeposits_RejectsBadRoot(t *testing.T) {
+	items := depositFile(t, 2)
+	items[1].DepositDataRoot = hex.EncodeToString(make([]byte, 32))
+	enc, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decodeDeposits(enc); err == nil || !strings.Contains(err.Error(), "invalid deposit 1") {
+		t.Errorf("Expected invalid deposit 1 error, received %v", err)
+	}
+}
+
+func TestSubmitDeposits(t *testing.T) {
+	testAcc, err := contracts.Setup()
+	if err != nil {
+		t.Fatalf("Unable to set up simulated backend %v", err)
+	}
+	testAcc.Backend.Commit()
+
+	enc, err := json.Marshal(depositFile(t, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deposits, err := decodeDeposits(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	query := ethereum.FilterQuery{Addresses: []common.Address{testAcc.ContractAddr}}
+
+	startNonce, err := testAcc.Backend.PendingNonceAt(ctx, testAcc.TxOpts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &submitConfig{nonce: -1, gasLimit: 1000000, dryRun: true}
+	hashes, err := submitDeposits(ctx, testAcc.Backend, testAcc.Contract, testAcc.TxOpts, deposits, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAcc.Backend.Commit()
+	logs, err := testAcc.Backend.FilterLogs(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 0 || len(logs) != 0 {
+		t.Fatalf("Expected no deposits to be sent in a dry run, received %d", len(logs))
+	}
+
+	cfg.dryRun = false
+	hashes, err = submitDeposits(ctx, testAcc.Backend, testAcc.Contract, testAcc.TxOpts, deposits, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAcc.Backend.Commit()
+	logs, err = testAcc.Backend.FilterLogs(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 3 || len(logs) != 3 {
+		t.Fatalf("Wanted 3 deposits, received %d transactions and %d logs", len(hashes), len(logs))
+	}
+	nonce, err := testAcc.Backend.PendingNonceAt(ctx, testAcc.TxOpts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != startNonce+3 {
+		t.Errorf("Wanted account nonce %d, received %d", startNonce+3, nonce)
+	}
+}
+
+// failingBackend fails to send the transaction at the given position among the sent transactions.
+type failingBackend struct {
+	bind.ContractBackend
+	sent   int
+	failAt int
+}
+
+func (b *failingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	defer func() { b.sent++ }()
+	if b.sent == b.failAt {
+		return errors.New("connection refused")
+	}
+	return b.ContractBackend.SendTransaction(ctx, tx)
+}
+
+func TestSubmitDeposits_Resume(t *testing.T) {
+	testAcc, err := contracts.Setup()
+	if err != nil {
+		t.Fatalf("Unable to set up simulated backend %v", err)
+	}
+	testAcc.Backend.Commit()
+
+	enc, err := json.Marshal(depositFile(t, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deposits, err := decodeDeposits(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	query := ethereum.FilterQuery{Addresses: []common.Address{testAcc.ContractAddr}}
+	startNonce, err := testAcc.Backend.PendingNonceAt(ctx, testAcc.TxOpts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second deposit fails to be sent.
+	failing := &failingBackend{ContractBackend: testAcc.Backend, failAt: 1}
+	failingContract, err := contracts.NewDepositContract(testAcc.ContractAddr, failing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &submitConfig{nonce: -1, gasLimit: 1000000}
+	hashes, err := submitDeposits(ctx, failing, failingContract, testAcc.TxOpts, deposits, cfg)
+	wanted := fmt.Sprintf("resume with --start-index=1 --nonce=%d", startNonce+1)
+	if err == nil || !strings.Contains(err.Error(), wanted) {
+		t.Fatalf("Expected error containing %q, received %v", wanted, err)
+	}
+	if len(hashes) != 1 {
+		t.Fatalf("Wanted 1 deposit sent before the failure, received %d", len(hashes))
+	}
+
+	// Resuming as advised only sends the remaining deposits.
+	cfg = &submitConfig{nonce: int64(startNonce + 1), startIndex: 1, gasLimit: 1000000}
+	hashes, err = submitDeposits(ctx, testAcc.Backend, testAcc.Contract, testAcc.TxOpts, deposits, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAcc.Backend.Commit()
+	logs, err := testAcc.Backend.FilterLogs(ctx, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 2 || len(logs) != 3 {
+		t.Fatalf("Wanted 2 resumed deposits and 3 deposits in total, received %d transactions and %d logs", len(hashes), len(logs))
+	}
+	for i, l := range logs {
+		pubKey, _, _, _, _, err := contracts.UnpackDepositLogData(l.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pubKey, deposits[i].data.PublicKey) {
+			t.Errorf("Wanted deposit %d to be sent once and in order, received pubkey %#x", i, pubKey)
+		}
+	}
+
+	cfg.startIndex = 3
+	if _, err := submitDeposits(ctx, testAcc.Backend, testAcc.Contract, testAcc.TxOpts, deposits, cfg); err == nil {
+		t.Error("Expected an error for a start index past the last deposit")
+	}
+}
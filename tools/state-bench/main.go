@@ -0,0 +1,231 @@
+/**
+ * State transition benchmark
+ *
+ * Loads a captured beacon state from an SSZ file or from a beacon node database and
+ * measures hash tree root, epoch transition and (optionally) block transition timings.
+ * Reports can be written as JSON and compared against a report from another revision
+ * to produce comparable numbers for performance pull requests.
+ *
+ * Usage: Run state-bench --help for flag options.
+ */
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/version"
+)
+
+var (
+	statePath   = flag.String("state-path", "", "Path to a beacon state SSZ file to benchmark with")
+	datadir     = flag.String("datadir", "", "Path to a beacon node database directory to load the state from, used if --state-path is not set")
+	slot        = flag.Uint64("slot", 0, "Load the highest state at or below this slot from the database (defaults to the highest state)")
+	blockPath   = flag.String("block-path", "", "Optional path to a signed beacon block SSZ file to benchmark the block transition with")
+	runs        = flag.Int("runs", 10, "Number of runs for each benchmark")
+	minimal     = flag.Bool("minimal-config", false, "Use the minimal spec config instead of mainnet")
+	outputPath  = flag.String("output", "", "Optional path to write the JSON report to")
+	comparePath = flag.String("compare", "", "Optional path of a JSON report from another revision to compare against")
+)
+
+// result contains the timings of a single benchmarked operation.
+type result struct {
+	Name   string          `json:"name"`
+	Runs   int             `json:"runs"`
+	Min    time.Duration   `json:"min_ns"`
+	Median time.Duration   `json:"median_ns"`
+	Mean   time.Duration   `json:"mean_ns"`
+	Max    time.Duration   `json:"max_ns"`
+	Raw    []time.Duration `json:"raw_ns"`
+}
+
+// report is the full output of a benchmark run.
+type report struct {
+	Version       string    `json:"version"`
+	Timestamp     time.Time `json:"timestamp"`
+	StateSlot     uint64    `json:"state_slot"`
+	NumValidators int       `json:"num_validators"`
+	Results       []*result `json:"results"`
+}
+
+func main() {
+	flag.Parse()
+	if *runs <= 0 {
+		log.Fatal("Expected --runs to be greater than 0")
+	}
+	if *minimal {
+		params.OverrideBeaconConfig(params.MinimalSpecConfig())
+	}
+	ctx := context.Background()
+
+	st, err := loadState(ctx)
+	if err != nil {
+		log.Fatalf("Could not load state: %v", err)
+	}
+	r := &report{
+		Version:       version.GetVersion(),
+		Timestamp:     time.Now(),
+		StateSlot:     st.Slot(),
+		NumValidators: st.NumValidators(),
+	}
+	log.Printf("Benchmarking state at slot %d with %d validators", st.Slot(), st.NumValidators())
+
+	r.Results = append(r.Results, measure("hash_tree_root", *runs, func() error {
+		_, err := st.Copy().HashTreeRoot(ctx)
+		return err
+	}))
+	nextEpochSlot := helpers.StartSlot(helpers.NextEpoch(st))
+	r.Results = append(r.Results, measure("epoch_transition", *runs, func() error {
+		_, err := state.ProcessSlots(ctx, st.Copy(), nextEpochSlot)
+		return err
+	}))
+	if *blockPath != "" {
+		blk := &ethpb.SignedBeaconBlock{}
+		if err := readSSZ(*blockPath, blk); err != nil {
+			log.Fatalf("Could not load block: %v", err)
+		}
+		r.Results = append(r.Results, measure("block_transition", *runs, func() error {
+			_, err := state.ExecuteStateTransition(ctx, st.Copy(), blk)
+			return err
+		}))
+	}
+
+	var baseline *report
+	if *comparePath != "" {
+		baseline = &report{}
+		enc, err := ioutil.ReadFile(*comparePath)
+		if err != nil {
+			log.Fatalf("Could not read comparison report: %v", err)
+		}
+		if err := json.Unmarshal(enc, baseline); err != nil {
+			log.Fatalf("Could not decode comparison report: %v", err)
+		}
+	}
+	printReport(r, baseline)
+
+	if *outputPath != "" {
+		enc, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			log.Fatalf("Could not encode report: %v", err)
+		}
+		if err := ioutil.WriteFile(*outputPath, enc, 0644); err != nil {
+			log.Fatalf("Could not write report: %v", err)
+		}
+		log.Printf("Done writing to %s", *outputPath)
+	}
+}
+
+func loadState(ctx context.Context) (*stateTrie.BeaconState, error) {
+	if *statePath != "" {
+		st := &pb.BeaconState{}
+		if err := readSSZ(*statePath, st); err != nil {
+			return nil, err
+		}
+		return stateTrie.InitializeFromProto(st)
+	}
+	if *datadir == "" {
+		return nil, fmt.Errorf("expected --state-path or --datadir to have been provided")
+	}
+	d, err := db.NewDB(*datadir, cache.NewStateSummaryCache())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			log.Printf("Could not close database: %v", err)
+		}
+	}()
+	var states []*stateTrie.BeaconState
+	if *slot == 0 {
+		states, err = d.HighestSlotStates(ctx)
+	} else {
+		states, err = d.HighestSlotStatesBelow(ctx, *slot+1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 || states[0] == nil {
+		return nil, fmt.Errorf("no state found in database")
+	}
+	return states[0], nil
+}
+
+func readSSZ(fPath string, data interface{}) error {
+	enc, err := ioutil.ReadFile(fPath)
+	if err != nil {
+		return err
+	}
+	return ssz.Unmarshal(enc, data)
+}
+
+// measure runs the function the requested number of times and summarizes the timings.
+// Each run is given a fresh copy of its inputs by the caller, so copy time is included.
+func measure(name string, n int, f func() error) *result {
+	raw := make([]time.Duration, n)
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		if err := f(); err != nil {
+			log.Fatalf("Benchmark %s failed: %v", name, err)
+		}
+		raw[i] = time.Since(start)
+	}
+	sorted := make([]time.Duration, n)
+	copy(sorted, raw)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return &result{
+		Name:   name,
+		Runs:   n,
+		Min:    sorted[0],
+		Median: sorted[n/2],
+		Mean:   total / time.Duration(n),
+		Max:    sorted[n-1],
+		Raw:    raw,
+	}
+}
+
+func printReport(r *report, baseline *report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if baseline == nil {
+		fmt.Fprintln(w, "BENCHMARK\tRUNS\tMIN\tMEDIAN\tMEAN\tMAX")
+	} else {
+		fmt.Fprintf(w, "BENCHMARK\tRUNS\tMIN\tMEDIAN\tMEAN\tMAX\tMEDIAN (%s)\tDELTA\n", baseline.Version)
+	}
+	for _, res := range r.Results {
+		line := fmt.Sprintf("%s\t%d\t%v\t%v\t%v\t%v", res.Name, res.Runs, res.Min, res.Median, res.Mean, res.Max)
+		if baseline != nil {
+			for _, old := range baseline.Results {
+				if old.Name != res.Name || old.Median == 0 {
+					continue
+				}
+				delta := (float64(res.Median) - float64(old.Median)) / float64(old.Median) * 100
+				line += fmt.Sprintf("\t%v\t%+.2f%%", old.Median, math.Round(delta*100)/100)
+			}
+		}
+		fmt.Fprintln(w, line)
+	}
+	if err := w.Flush(); err != nil {
+		log.Printf("Could not flush report: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+/**
+ * Testnet keys generator
+ *
+ * Generates N validator keystores in the EIP-2335 format along with their
+ * deposit data, for bringing up large private testnets. Generation is parallelized
+ * across workers and resumable: validators whose keystore and deposit files already
+ * exist in the output directory are skipped. The aggregated deposit_data.json can be
+ * passed to genesis-state-gen --deposit-json-file to create a pre-funded genesis state.
+ *
+ * Usage: Run testnet-keys-gen --help for flag options.
+ */
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/interop"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+var (
+	numValidators = flag.Uint64("num-validators", 0, "Number of validator keystores to generate")
+	startIndex    = flag.Uint64("start-index", 0, "Index of the first validator to generate")
+	outputDir     = flag.String("output-dir", "", "Directory to write keystores and deposit data to")
+	passwordFile  = flag.String("password-file", "", "Path to a file containing the password used to encrypt the keystores")
+	deterministic = flag.Bool("deterministic", false, "Use the deterministic interop key scheme instead of random keys")
+	workers       = flag.Int("workers", 4, "Number of keystores to generate in parallel")
+	minimalConfig = flag.Bool("minimal-config", false, "Use the minimal spec config for deposit amounts and domains")
+)
+
+const (
+	keystoresDirName   = "keystores"
+	depositsDirName    = "deposits"
+	depositDataFile    = "deposit_data.json"
+	keystoreFileFormat = "keystore-%d.json"
+	depositFileFormat  = "deposit-%d.json"
+)
+
+// keystoreJSON is an EIP-2335 keystore.
+type keystoreJSON struct {
+	Crypto  map[string]interface{} `json:"crypto"`
+	PubKey  string                 `json:"pubkey"`
+	Path    string                 `json:"path"`
+	UUID    string                 `json:"uuid"`
+	Version uint                   `json:"version"`
+}
+
+// depositDataJSON is a single deposit data entry in the format of the eth2 deposit tooling.
+type depositDataJSON struct {
+	PubKey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	Signature             string `json:"signature"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+}
+
+func main() {
+	flag.Parse()
+	if *numValidators == 0 {
+		log.Fatal("Expected --num-validators to have been provided, received 0")
+	}
+	if *outputDir == "" {
+		log.Fatal("Expected --output-dir to have been provided")
+	}
+	if *passwordFile == "" {
+		log.Fatal("Expected --password-file to have been provided")
+	}
+	if *workers <= 0 {
+		log.Fatal("Expected --workers to be greater than 0")
+	}
+	if *minimalConfig {
+		params.OverrideBeaconConfig(params.MinimalSpecConfig())
+	}
+	enc, err := ioutil.ReadFile(*passwordFile)
+	if err != nil {
+		log.Fatalf("Could not read password file: %v", err)
+	}
+	password := strings.TrimRight(string(enc), "\r\n")
+
+	for _, dir := range []string{keystoresDirName, depositsDirName} {
+		if err := os.MkdirAll(filepath.Join(*outputDir, dir), 0700); err != nil {
+			log.Fatalf("Could not create output directory: %v", err)
+		}
+	}
+
+	deposits := make([]*depositDataJSON, *numValidators)
+	indices := make(chan uint64)
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				d, err := generateValidator(i, password)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = errors.Wrapf(err, "could not generate validator %d", i)
+					})
+					continue
+				}
+				deposits[i-*startIndex] = d
+			}
+		}()
+	}
+	for i := *startIndex; i < *startIndex+*numValidators; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+	if firstErr != nil {
+		log.Fatalf("%v, rerun the same command to resume", firstErr)
+	}
+
+	out, err := json.MarshalIndent(deposits, "", "  ")
+	if err != nil {
+		log.Fatalf("Could not encode deposit data: %v", err)
+	}
+	depositPath := filepath.Join(*outputDir, depositDataFile)
+	if err := ioutil.WriteFile(depositPath, out, 0644); err != nil {
+		log.Fatalf("Could not write deposit data: %v", err)
+	}
+	log.Printf("Done generating %d validators, deposit data written to %s", *numValidators, depositPath)
+}
+
+// generateValidator creates the keystore and deposit data for a single validator index.
+// If both files already exist, the persisted deposit data is returned instead so an
+// interrupted run can be resumed.
+func generateValidator(index uint64, password string) (*depositDataJSON, error) {
+	keystorePath := filepath.Join(*outputDir, keystoresDirName, fmt.Sprintf(keystoreFileFormat, index))
+	depositPath := filepath.Join(*outputDir, depositsDirName, fmt.Sprintf(depositFileFormat, index))
+	if fileExists(keystorePath) && fileExists(depositPath) {
+		enc, err := ioutil.ReadFile(depositPath)
+		if err != nil {
+			return nil, err
+		}
+		d := &depositDataJSON{}
+		if err := json.Unmarshal(enc, d); err != nil {
+			return nil, errors.Wrap(err, "could not decode existing deposit data")
+		}
+		return d, nil
+	}
+
+	var secretKey *bls.SecretKey
+	if *deterministic {
+		sks, _, err := interop.DeterministicallyGenerateKeys(index, 1)
+		if err != nil {
+			return nil, err
+		}
+		secretKey = sks[0]
+	} else {
+		secretKey = bls.RandKey()
+	}
+	pubKey := secretKey.PublicKey()
+
+	cryptoFields, err := keystorev4.New().Encrypt(secretKey.Marshal(), []byte(password))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt secret key")
+	}
+	ks := &keystoreJSON{
+		Crypto:  cryptoFields,
+		PubKey:  hex.EncodeToString(pubKey.Marshal()),
+		UUID:    uuid.NewRandom().String(),
+		Version: 4,
+	}
+	items, roots, err := interop.DepositDataFromKeys([]*bls.SecretKey{secretKey}, []*bls.PublicKey{pubKey})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create deposit data")
+	}
+	d := &depositDataJSON{
+		PubKey:                hex.EncodeToString(items[0].PublicKey),
+		WithdrawalCredentials: hex.EncodeToString(items[0].WithdrawalCredentials),
+		Amount:                items[0].Amount,
+		Signature:             hex.EncodeToString(items[0].Signature),
+		DepositDataRoot:       hex.EncodeToString(roots[0]),
+	}
+
+	// The deposit file is written last so its presence marks the validator as complete.
+	if err := writeJSON(keystorePath, ks); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(depositPath, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func writeJSON(fPath string, v interface{}) error {
+	enc, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fPath, enc, 0600)
+}
+
+func fileExists(fPath string) bool {
+	_, err := os.Stat(fPath)
+	return err == nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -17,6 +18,7 @@ import (
 	contract "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/flags"
@@ -195,10 +197,35 @@ func DefaultValidatorDir() string {
 }
 
 // HandleEmptyKeystoreFlags checks what the set flags are and allows the user to manually enter them if they're empty.
+// The password may also be read from the file given by --password-file. With --non-interactive, missing
+// values are an error instead of a prompt.
 func HandleEmptyKeystoreFlags(cliCtx *cli.Context, confirmPassword bool) (string, string, error) {
 	path := cliCtx.String(flags.KeystorePathFlag.Name)
 	passphrase := cliCtx.String(flags.PasswordFlag.Name)
 
+	if passphrase == "" && cliCtx.String(flags.PasswordFileFlag.Name) != "" {
+		enc, err := ioutil.ReadFile(cliCtx.String(flags.PasswordFileFlag.Name))
+		if err != nil {
+			return path, passphrase, errors.Wrap(err, "could not read password file")
+		}
+		passphrase = strings.TrimRight(string(enc), "\r\n")
+		logutil.RegisterSecret(passphrase)
+	}
+
+	if cliCtx.Bool(flags.NonInteractiveFlag.Name) {
+		if path == "" {
+			return path, passphrase, fmt.Errorf("--%s is required in non-interactive mode", flags.KeystorePathFlag.Name)
+		}
+		if passphrase == "" {
+			return path, passphrase, fmt.Errorf(
+				"--%s or --%s is required in non-interactive mode",
+				flags.PasswordFlag.Name,
+				flags.PasswordFileFlag.Name,
+			)
+		}
+		return path, passphrase, nil
+	}
+
 	if path == "" {
 		path = DefaultValidatorDir()
 		log.Infof("Please specify the keystore path for your private keys (default: %q):", path)
@@ -106,6 +106,43 @@ func TestHandleEmptyFlags_FlagsSet(t *testing.T) {
 	}
 }
 
+func TestHandleEmptyFlags_PasswordFile(t *testing.T) {
+	passwordFile := testutil.TempDir() + "/password.txt"
+	if err := ioutil.WriteFile(passwordFile, []byte("password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Remove(passwordFile); err != nil {
+			t.Logf("Could not remove file: %v", err)
+		}
+	}()
+
+	app := &cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String(flags.KeystorePathFlag.Name, "~/path/given", "set keystore path")
+	set.String(flags.PasswordFileFlag.Name, passwordFile, "set password file")
+	set.Bool(flags.NonInteractiveFlag.Name, true, "set non-interactive")
+	ctx := cli.NewContext(app, set, nil)
+	_, passphrase, err := HandleEmptyKeystoreFlags(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passphrase != "password" {
+		t.Fatalf("Expected password to be read from file, received %q", passphrase)
+	}
+}
+
+func TestHandleEmptyFlags_NonInteractiveMissingPassword(t *testing.T) {
+	app := &cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String(flags.KeystorePathFlag.Name, "~/path/given", "set keystore path")
+	set.Bool(flags.NonInteractiveFlag.Name, true, "set non-interactive")
+	ctx := cli.NewContext(app, set, nil)
+	if _, _, err := HandleEmptyKeystoreFlags(ctx, false); err == nil {
+		t.Fatal("Expected error for missing password in non-interactive mode")
+	}
+}
+
 func TestChangePassword_KeyEncryptedWithNewPassword(t *testing.T) {
 	directory := testutil.TempDir() + "/testkeystore"
 	defer func() {
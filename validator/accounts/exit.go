@@ -0,0 +1,103 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/p2putils"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// ExitWarning is shown before proposing voluntary exits, which cannot be undone.
+const ExitWarning = "Voluntary exits cannot be reversed: exited validators are unable to validate again and " +
+	"their balance is locked until withdrawals are enabled. Do you want to exit the validators? (Y/N)"
+
+// SignFunc signs a voluntary exit with the domain with the key of the public key.
+type SignFunc func(pubKey [48]byte, exit *ethpb.VoluntaryExit, domain []byte) (*bls.Signature, error)
+
+// ProposeExits signs voluntary exits of the validators of the public keys at the current epoch
+// and proposes them to the beacon node. The exits are signed with the domain of the fork active
+// at the current epoch, as computed from the genesis of the beacon node.
+func ProposeExits(
+	ctx context.Context,
+	validatorClient ethpb.BeaconNodeValidatorClient,
+	nodeClient ethpb.NodeClient,
+	pubKeys [][48]byte,
+	sign SignFunc,
+) error {
+	ctx, span := trace.StartSpan(ctx, "accounts.ProposeExits")
+	defer span.End()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second /* Cancel if running over thirty seconds. */)
+	defer cancel()
+
+	genesis, err := nodeClient.GetGenesis(ctx, &ptypes.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis")
+	}
+	genesisTime, err := ptypes.TimestampFromProto(genesis.GenesisTime)
+	if err != nil {
+		return errors.Wrap(err, "could not decode genesis time")
+	}
+	epoch := helpers.SlotToEpoch(helpers.SlotsSince(genesisTime))
+	fork, err := p2putils.Fork(epoch)
+	if err != nil {
+		return errors.Wrap(err, "could not get fork")
+	}
+	domain, err := helpers.ComputeDomain(params.BeaconConfig().DomainVoluntaryExit, fork.CurrentVersion, genesis.GenesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not compute voluntary exit domain")
+	}
+
+	var failed int
+	for _, pubKey := range pubKeys {
+		if err := proposeExit(ctx, validatorClient, pubKey, epoch, domain, sign); err != nil {
+			log.WithError(err).WithField("publicKey", fmt.Sprintf("%#x", pubKey)).Error("Could not exit validator")
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("could not exit %d of %d validators", failed, len(pubKeys))
+	}
+	return nil
+}
+
+// proposeExit signs a voluntary exit of the validator of the public key at the epoch and
+// proposes it to the beacon node.
+func proposeExit(
+	ctx context.Context,
+	validatorClient ethpb.BeaconNodeValidatorClient,
+	pubKey [48]byte,
+	epoch uint64,
+	domain []byte,
+	sign SignFunc,
+) error {
+	resp, err := validatorClient.ValidatorIndex(ctx, &ethpb.ValidatorIndexRequest{PublicKey: pubKey[:]})
+	if err != nil {
+		return errors.Wrap(err, "could not get validator index")
+	}
+	exit := &ethpb.VoluntaryExit{Epoch: epoch, ValidatorIndex: resp.Index}
+	sig, err := sign(pubKey, exit, domain)
+	if err != nil {
+		return errors.Wrap(err, "could not sign voluntary exit")
+	}
+	if _, err := validatorClient.ProposeExit(ctx, &ethpb.SignedVoluntaryExit{
+		Exit:      exit,
+		Signature: sig.Marshal(),
+	}); err != nil {
+		return errors.Wrap(err, "could not propose voluntary exit")
+	}
+	log.WithFields(logrus.Fields{
+		"publicKey": fmt.Sprintf("%#x", pubKey),
+		"index":     resp.Index,
+		"epoch":     epoch,
+	}).Info("Proposed voluntary exit")
+	return nil
+}
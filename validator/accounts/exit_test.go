@@ -0,0 +1,98 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestProposeExits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	validatorClient := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	nodeClient := mock.NewMockNodeClient(ctrl)
+
+	sk := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	genesisValidatorsRoot := bytesutil.PadTo([]byte("genesis"), 32)
+	epochDuration := time.Duration(params.BeaconConfig().SlotsPerEpoch*params.BeaconConfig().SecondsPerSlot) * time.Second
+	genesisTime, err := types.TimestampProto(time.Now().Add(-10*epochDuration - time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeClient.EXPECT().GetGenesis(gomock.Any(), gomock.Any()).Return(&ethpb.Genesis{
+		GenesisTime:           genesisTime,
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+	}, nil)
+	validatorClient.EXPECT().ValidatorIndex(gomock.Any(), &ethpb.ValidatorIndexRequest{PublicKey: pubKey[:]}).
+		Return(&ethpb.ValidatorIndexResponse{Index: 7}, nil)
+
+	var proposed *ethpb.SignedVoluntaryExit
+	validatorClient.EXPECT().ProposeExit(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, exit *ethpb.SignedVoluntaryExit, _ ...interface{}) (*types.Empty, error) {
+			proposed = exit
+			return &types.Empty{}, nil
+		})
+
+	sign := func(_ [48]byte, exit *ethpb.VoluntaryExit, domain []byte) (*bls.Signature, error) {
+		root, err := helpers.ComputeSigningRoot(exit, domain)
+		if err != nil {
+			return nil, err
+		}
+		return sk.Sign(root[:]), nil
+	}
+	if err := ProposeExits(context.Background(), validatorClient, nodeClient, [][48]byte{pubKey}, sign); err != nil {
+		t.Fatal(err)
+	}
+
+	if proposed.Exit.Epoch != 10 || proposed.Exit.ValidatorIndex != 7 {
+		t.Errorf("Unexpected voluntary exit %v", proposed.Exit)
+	}
+	domain, err := helpers.ComputeDomain(params.BeaconConfig().DomainVoluntaryExit, params.BeaconConfig().GenesisForkVersion, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := helpers.ComputeSigningRoot(proposed.Exit, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := bls.SignatureFromBytes(proposed.Signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(sk.PublicKey(), root[:]) {
+		t.Error("Voluntary exit signature does not verify with the voluntary exit domain")
+	}
+}
+
+func TestProposeExits_Failed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	validatorClient := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	nodeClient := mock.NewMockNodeClient(ctrl)
+
+	nodeClient.EXPECT().GetGenesis(gomock.Any(), gomock.Any()).Return(&ethpb.Genesis{
+		GenesisTime:           types.TimestampNow(),
+		GenesisValidatorsRoot: make([]byte, 32),
+	}, nil)
+	validatorClient.EXPECT().ValidatorIndex(gomock.Any(), gomock.Any()).
+		Return(nil, context.DeadlineExceeded).Times(2)
+
+	sign := func(_ [48]byte, _ *ethpb.VoluntaryExit, _ []byte) (*bls.Signature, error) {
+		t.Fatal("Unexpected signing of an unknown validator")
+		return nil, nil
+	}
+	err := ProposeExits(context.Background(), validatorClient, nodeClient, [][48]byte{{1}, {2}}, sign)
+	if err == nil || err.Error() != "could not exit 2 of 2 validators" {
+		t.Errorf("Wanted error for the failed exits, received %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package accounts
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/mathutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"go.opencensus.io/trace"
+)
+
+// secondsPerYear is the length of a Julian year, used to annualize rewards.
+const secondsPerYear = 365.25 * 24 * 60 * 60
+
+// Statistics holds the network participation and the balances and projected rewards of
+// validators. Projections assume the validators attest perfectly and the participation of
+// the network and its total active balance stay the same for a year.
+type Statistics struct {
+	Epoch              uint64                 `json:"epoch"`
+	ParticipationRate  float64                `json:"participation_rate"`
+	TotalActiveBalance uint64                 `json:"total_active_balance_gwei"`
+	Validators         []*ValidatorStatistics `json:"validators"`
+}
+
+// ValidatorStatistics holds the balances and projected rewards of a validator.
+type ValidatorStatistics struct {
+	PublicKey             string  `json:"public_key"`
+	Index                 uint64  `json:"index"`
+	Balance               uint64  `json:"balance_gwei"`
+	EffectiveBalance      uint64  `json:"effective_balance_gwei"`
+	APR                   float64 `json:"apr"`
+	ProjectedAnnualReward uint64  `json:"projected_annual_reward_gwei"`
+}
+
+// RunStatisticsCommand is the entry point to the `validator accounts statistics` command.
+func RunStatisticsCommand(w io.Writer, format string, pubKeys [][]byte, beaconClient ethpb.BeaconChainClient) error {
+	stats, err := FetchStatistics(context.Background(), beaconClient, pubKeys)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch statistics from the beacon node")
+	}
+	return WriteStatistics(w, format, stats)
+}
+
+// FetchStatistics queries the beacon node for the balances of the validators of the public
+// keys and the participation of the network in the previous epoch, and projects the annual
+// rewards of the validators from them. Keys unknown to the beacon node are left out.
+func FetchStatistics(ctx context.Context, beaconClient ethpb.BeaconChainClient, pubKeys [][]byte) (*Statistics, error) {
+	ctx, span := trace.StartSpan(ctx, "accounts.FetchStatistics")
+	defer span.End()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second /* Cancel if running over thirty seconds. */)
+	defer cancel()
+
+	participation, err := beaconClient.GetValidatorParticipation(ctx, &ethpb.GetValidatorParticipationRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get network participation")
+	}
+	if participation.Participation == nil {
+		return nil, errors.New("beacon node returned no participation")
+	}
+	stats := &Statistics{
+		Epoch:              participation.Epoch,
+		ParticipationRate:  float64(participation.Participation.GlobalParticipationRate),
+		TotalActiveBalance: participation.Participation.EligibleEther,
+	}
+
+	balances := make(map[uint64]uint64)
+	req := &ethpb.ListValidatorBalancesRequest{PublicKeys: pubKeys}
+	for {
+		resp, err := beaconClient.ListValidatorBalances(ctx, req)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list validator balances")
+		}
+		for _, b := range resp.Balances {
+			balances[b.Index] = b.Balance
+		}
+		if resp.NextPageToken == "" || len(balances) >= len(pubKeys) {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	valReq := &ethpb.ListValidatorsRequest{PublicKeys: pubKeys}
+	for {
+		resp, err := beaconClient.ListValidators(ctx, valReq)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list validators")
+		}
+		for _, v := range resp.ValidatorList {
+			if v.Validator == nil {
+				continue
+			}
+			reward := projectAnnualReward(v.Validator.EffectiveBalance, stats.TotalActiveBalance, stats.ParticipationRate)
+			vs := &ValidatorStatistics{
+				PublicKey:             fmt.Sprintf("%#x", v.Validator.PublicKey),
+				Index:                 v.Index,
+				Balance:               balances[v.Index],
+				EffectiveBalance:      v.Validator.EffectiveBalance,
+				ProjectedAnnualReward: reward,
+			}
+			if vs.EffectiveBalance > 0 {
+				vs.APR = float64(reward) / float64(vs.EffectiveBalance)
+			}
+			stats.Validators = append(stats.Validators, vs)
+		}
+		if resp.NextPageToken == "" || len(stats.Validators) >= len(pubKeys) {
+			break
+		}
+		valReq.PageToken = resp.NextPageToken
+	}
+	return stats, nil
+}
+
+// projectAnnualReward estimates the rewards in Gwei of a validator attesting perfectly for a
+// year. Per epoch, such a validator earns the share of the network participation of its base
+// reward for each of the source, target and head votes, the inclusion reward of a minimal
+// inclusion delay, and on average the proposer reward for the attestations of the participating
+// validators.
+func projectAnnualReward(effectiveBalance uint64, totalActiveBalance uint64, participation float64) uint64 {
+	cfg := params.BeaconConfig()
+	if totalActiveBalance == 0 {
+		return 0
+	}
+	baseReward := effectiveBalance * cfg.BaseRewardFactor / mathutil.IntegerSquareRoot(totalActiveBalance) / cfg.BaseRewardsPerEpoch
+	proposerReward := baseReward / cfg.ProposerRewardQuotient
+	perEpoch := 3*float64(baseReward)*participation +
+		float64(baseReward-proposerReward) +
+		float64(proposerReward)*participation
+	epochsPerYear := secondsPerYear / float64(cfg.SecondsPerSlot*cfg.SlotsPerEpoch)
+	return uint64(perEpoch * epochsPerYear)
+}
+
+// WriteStatistics writes the statistics in the given format, either text, json or csv.
+func WriteStatistics(w io.Writer, format string, stats *Statistics) error {
+	switch format {
+	case "json":
+		if stats.Validators == nil {
+			stats.Validators = []*ValidatorStatistics{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{
+			"epoch", "participation_rate", "total_active_balance_gwei", "public_key", "index",
+			"balance_gwei", "effective_balance_gwei", "apr", "projected_annual_reward_gwei",
+		}); err != nil {
+			return err
+		}
+		for _, v := range stats.Validators {
+			if err := cw.Write([]string{
+				strconv.FormatUint(stats.Epoch, 10),
+				strconv.FormatFloat(stats.ParticipationRate, 'f', 4, 64),
+				strconv.FormatUint(stats.TotalActiveBalance, 10),
+				v.PublicKey,
+				strconv.FormatUint(v.Index, 10),
+				strconv.FormatUint(v.Balance, 10),
+				strconv.FormatUint(v.EffectiveBalance, 10),
+				strconv.FormatFloat(v.APR, 'f', 6, 64),
+				strconv.FormatUint(v.ProjectedAnnualReward, 10),
+			}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case "text", "":
+		gwei := float64(params.BeaconConfig().GweiPerEth)
+		if _, err := fmt.Fprintf(w, "Epoch %d: participation %.2f%%, total active balance %.0f ETH\n",
+			stats.Epoch, stats.ParticipationRate*100, float64(stats.TotalActiveBalance)/gwei); err != nil {
+			return err
+		}
+		for _, v := range stats.Validators {
+			if _, err := fmt.Fprintf(w, "%s\t%d\tbalance %.9f ETH\teffective balance %.0f ETH\tAPR %.2f%%\tprojected %.9f ETH/year\n",
+				v.PublicKey, v.Index, float64(v.Balance)/gwei, float64(v.EffectiveBalance)/gwei,
+				v.APR*100, float64(v.ProjectedAnnualReward)/gwei); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
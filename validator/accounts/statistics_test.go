@@ -0,0 +1,91 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestFetchStatistics_OK(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	maxBal := params.BeaconConfig().MaxEffectiveBalance
+	pubKeys := [][]byte{{1}, {2}}
+
+	mockClient := mock.NewMockBeaconChainClient(ctrl)
+	mockClient.EXPECT().GetValidatorParticipation(gomock.Any(), gomock.Any()).Return(&ethpb.ValidatorParticipationResponse{
+		Epoch: 10,
+		Participation: &ethpb.ValidatorParticipation{
+			GlobalParticipationRate: 1,
+			VotedEther:              1000000 * params.BeaconConfig().GweiPerEth,
+			EligibleEther:           1000000 * params.BeaconConfig().GweiPerEth,
+		},
+	}, nil)
+	mockClient.EXPECT().ListValidatorBalances(gomock.Any(), &ethpb.ListValidatorBalancesRequest{PublicKeys: pubKeys}).Return(&ethpb.ValidatorBalances{
+		Balances: []*ethpb.ValidatorBalances_Balance{
+			{PublicKey: pubKeys[0], Index: 3, Balance: maxBal + 1},
+			{PublicKey: pubKeys[1], Index: 5, Balance: maxBal / 2},
+		},
+	}, nil)
+	mockClient.EXPECT().ListValidators(gomock.Any(), &ethpb.ListValidatorsRequest{PublicKeys: pubKeys}).Return(&ethpb.Validators{
+		ValidatorList: []*ethpb.Validators_ValidatorContainer{
+			{Index: 3, Validator: &ethpb.Validator{PublicKey: pubKeys[0], EffectiveBalance: maxBal}},
+			{Index: 5, Validator: &ethpb.Validator{PublicKey: pubKeys[1], EffectiveBalance: maxBal / 2}},
+		},
+	}, nil)
+
+	stats, err := FetchStatistics(ctx, mockClient, pubKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Validators) != 2 {
+		t.Fatalf("Expected statistics of 2 validators, got %d", len(stats.Validators))
+	}
+	full, half := stats.Validators[0], stats.Validators[1]
+	if full.Balance != maxBal+1 || half.Balance != maxBal/2 {
+		t.Errorf("Unexpected balances %d and %d", full.Balance, half.Balance)
+	}
+	// About 16.6% for a million ETH staked at full participation.
+	if full.APR < 0.16 || full.APR > 0.17 {
+		t.Errorf("Unexpected APR %f", full.APR)
+	}
+	if full.ProjectedAnnualReward <= half.ProjectedAnnualReward {
+		t.Errorf("Expected the larger effective balance to earn more, got %d and %d",
+			full.ProjectedAnnualReward, half.ProjectedAnnualReward)
+	}
+}
+
+func TestWriteStatistics_CSV(t *testing.T) {
+	stats := &Statistics{
+		Epoch:              10,
+		ParticipationRate:  0.5,
+		TotalActiveBalance: 100,
+		Validators: []*ValidatorStatistics{
+			{PublicKey: "0x01", Index: 3, Balance: 32, EffectiveBalance: 32, APR: 0.05, ProjectedAnnualReward: 1},
+		},
+	}
+	buf := new(bytes.Buffer)
+	if err := WriteStatistics(buf, "csv", stats); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected a header and 1 record, got %d rows", len(records))
+	}
+	if records[1][3] != "0x01" || records[1][4] != "3" {
+		t.Errorf("Unexpected record %v", records[1])
+	}
+	if err := WriteStatistics(buf, "yaml", stats); err == nil {
+		t.Error("Expected unknown format to fail")
+	}
+}
@@ -0,0 +1,189 @@
+package accounts
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// Account describes a validator key stored in an encrypted keystore file.
+type Account struct {
+	PublicKey    string `json:"public_key"`
+	KeystoreFile string `json:"keystore_file"`
+}
+
+// ListAccounts returns the validator accounts of a keystore directory which can be
+// decrypted with the password, sorted by public key.
+func ListAccounts(keystorePath string, password string) ([]*Account, error) {
+	files, err := accountFiles(keystorePath, password)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*Account, 0, len(files))
+	for pubKey, file := range files {
+		accounts = append(accounts, &Account{PublicKey: "0x" + pubKey, KeystoreFile: file})
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].PublicKey < accounts[j].PublicKey
+	})
+	return accounts, nil
+}
+
+// DeleteAccounts removes the keystore files of the given validator public keys. Nothing
+// is deleted if any of the keys is not found in the keystore.
+func DeleteAccounts(keystorePath string, password string, pubKeys []string) ([]*Account, error) {
+	if len(pubKeys) == 0 {
+		return nil, errors.New("no public keys to delete")
+	}
+	selected, err := selectAccounts(keystorePath, password, pubKeys)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range selected {
+		if err := os.Remove(a.KeystoreFile); err != nil {
+			return nil, errors.Wrapf(err, "could not delete keystore file of %s", a.PublicKey)
+		}
+	}
+	return selected, nil
+}
+
+// BackupAccounts copies the encrypted keystore files of the given validator public keys,
+// or of all accounts if none are given, to the backup directory. Existing files in the
+// backup directory are never overwritten. The returned accounts refer to the backup files.
+func BackupAccounts(keystorePath string, password string, backupDir string, pubKeys []string) ([]*Account, error) {
+	if backupDir == "" {
+		return nil, errors.New("no backup directory given")
+	}
+	var selected []*Account
+	var err error
+	if len(pubKeys) == 0 {
+		selected, err = ListAccounts(keystorePath, password)
+	} else {
+		selected, err = selectAccounts(keystorePath, password, pubKeys)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create backup directory")
+	}
+	backedUp := make([]*Account, len(selected))
+	for i, a := range selected {
+		// #nosec G304
+		enc, err := ioutil.ReadFile(a.KeystoreFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read keystore file of %s", a.PublicKey)
+		}
+		target := filepath.Join(backupDir, filepath.Base(a.KeystoreFile))
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create backup file of %s", a.PublicKey)
+		}
+		if _, err := f.Write(enc); err != nil {
+			_ = f.Close()
+			return nil, errors.Wrapf(err, "could not write backup file of %s", a.PublicKey)
+		}
+		if err := f.Close(); err != nil {
+			return nil, errors.Wrapf(err, "could not write backup file of %s", a.PublicKey)
+		}
+		backedUp[i] = &Account{PublicKey: a.PublicKey, KeystoreFile: target}
+	}
+	return backedUp, nil
+}
+
+// ParsePublicKeys parses a comma separated list of hex encoded validator public keys.
+func ParsePublicKeys(s string) ([]string, error) {
+	var pubKeys []string
+	for _, k := range strings.Split(s, ",") {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		b, err := hex.DecodeString(strings.TrimPrefix(k, "0x"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode public key %s", k)
+		}
+		if len(b) != params.BeaconConfig().BLSPubkeyLength {
+			return nil, fmt.Errorf("public key %s has %d bytes, wanted %d", k, len(b), params.BeaconConfig().BLSPubkeyLength)
+		}
+		pubKeys = append(pubKeys, hex.EncodeToString(b))
+	}
+	return pubKeys, nil
+}
+
+// WriteAccounts writes the accounts to w, either as a JSON array or one line per account.
+func WriteAccounts(w io.Writer, format string, accounts []*Account) error {
+	switch format {
+	case "json":
+		if accounts == nil {
+			accounts = []*Account{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(accounts)
+	case "text", "":
+		for _, a := range accounts {
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", a.PublicKey, a.KeystoreFile); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// selectAccounts returns the accounts of the given public keys, failing if any is missing.
+func selectAccounts(keystorePath string, password string, pubKeys []string) ([]*Account, error) {
+	files, err := accountFiles(keystorePath, password)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]*Account, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		file, ok := files[pubKey]
+		if !ok {
+			return nil, fmt.Errorf("no account with public key 0x%s found in keystore", pubKey)
+		}
+		selected[i] = &Account{PublicKey: "0x" + pubKey, KeystoreFile: file}
+	}
+	return selected, nil
+}
+
+// accountFiles maps the hex encoded public keys of the validator keys in a keystore
+// directory to their files. Files which cannot be decrypted with the password are skipped.
+func accountFiles(keystorePath string, password string) (map[string]string, error) {
+	entries, err := ioutil.ReadDir(keystorePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read keystore directory %s", keystorePath)
+	}
+	prefix := strings.TrimPrefix(params.BeaconConfig().ValidatorPrivkeyFileName, "/")
+	files := make(map[string]string)
+	for _, e := range entries {
+		if !e.Mode().IsRegular() || !strings.Contains(e.Name(), prefix) {
+			continue
+		}
+		file := filepath.Join(keystorePath, e.Name())
+		// #nosec G304
+		keyJSON, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read keystore file %s", file)
+		}
+		key, err := keystore.DecryptKey(keyJSON, password)
+		if err != nil {
+			continue
+		}
+		files[hex.EncodeToString(key.PublicKey.Marshal())] = file
+	}
+	return files, nil
+}
@@ -0,0 +1,160 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func storeTestKeys(t *testing.T, directory string, password string, n int) []string {
+	ks := keystore.NewKeystore(directory)
+	pubKeys := make([]string, n)
+	for i := 0; i < n; i++ {
+		key, err := keystore.NewKey()
+		if err != nil {
+			t.Fatalf("Cannot create new key: %v", err)
+		}
+		pubKey := hex.EncodeToString(key.PublicKey.Marshal())
+		if err := ks.StoreKey(directory+params.BeaconConfig().ValidatorPrivkeyFileName+pubKey[:12], key, password); err != nil {
+			t.Fatalf("Unable to store key %v", err)
+		}
+		pubKeys[i] = pubKey
+	}
+	return pubKeys
+}
+
+func TestListAccounts(t *testing.T) {
+	directory := testutil.TempDir() + "/testkeystore"
+	defer func() {
+		if err := os.RemoveAll(directory); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	pubKeys := storeTestKeys(t, directory, "password", 3)
+	storeTestKeys(t, directory, "other", 1)
+
+	list, err := ListAccounts(directory, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(pubKeys) {
+		t.Fatalf("Expected %d accounts, received %d", len(pubKeys), len(list))
+	}
+	found := make(map[string]bool)
+	for _, a := range list {
+		found[a.PublicKey] = true
+	}
+	for _, pubKey := range pubKeys {
+		if !found["0x"+pubKey] {
+			t.Errorf("Account %s not listed", pubKey)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAccounts(&buf, "json", list); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*Account
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(list) || decoded[0].PublicKey != list[0].PublicKey {
+		t.Errorf("Unexpected JSON output %s", buf.String())
+	}
+}
+
+func TestDeleteAccounts(t *testing.T) {
+	directory := testutil.TempDir() + "/testkeystore"
+	defer func() {
+		if err := os.RemoveAll(directory); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	pubKeys := storeTestKeys(t, directory, "password", 2)
+
+	missing := make([]byte, params.BeaconConfig().BLSPubkeyLength)
+	if _, err := DeleteAccounts(directory, "password", []string{pubKeys[0], hex.EncodeToString(missing)}); err == nil {
+		t.Fatal("Expected error deleting unknown account")
+	}
+	list, err := ListAccounts(directory, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("Expected no account to be deleted, %d left", len(list))
+	}
+
+	deleted, err := DeleteAccounts(directory, "password", pubKeys[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].PublicKey != "0x"+pubKeys[0] {
+		t.Fatalf("Unexpected deleted accounts %v", deleted)
+	}
+	list, err = ListAccounts(directory, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].PublicKey != "0x"+pubKeys[1] {
+		t.Fatalf("Unexpected remaining accounts %v", list)
+	}
+}
+
+func TestBackupAccounts(t *testing.T) {
+	directory := testutil.TempDir() + "/testkeystore"
+	backupDir := testutil.TempDir() + "/testbackup"
+	defer func() {
+		for _, dir := range []string{directory, backupDir} {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Logf("Could not remove directory: %v", err)
+			}
+		}
+	}()
+	pubKeys := storeTestKeys(t, directory, "password", 2)
+
+	backedUp, err := BackupAccounts(directory, "password", backupDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backedUp) != len(pubKeys) {
+		t.Fatalf("Expected %d backed up accounts, received %d", len(pubKeys), len(backedUp))
+	}
+	for _, a := range backedUp {
+		if filepath.Dir(a.KeystoreFile) != backupDir {
+			t.Errorf("Backup file %s not in backup directory", a.KeystoreFile)
+		}
+	}
+	list, err := ListAccounts(backupDir, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(pubKeys) {
+		t.Fatalf("Expected %d accounts in backup, received %d", len(pubKeys), len(list))
+	}
+
+	if _, err := BackupAccounts(directory, "password", backupDir, pubKeys[:1]); err == nil {
+		t.Error("Expected error overwriting an existing backup")
+	}
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	key := make([]byte, params.BeaconConfig().BLSPubkeyLength)
+	key[0] = 0xab
+	pubKeys, err := ParsePublicKeys("0x" + hex.EncodeToString(key) + ", ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubKeys) != 1 || pubKeys[0] != hex.EncodeToString(key) {
+		t.Errorf("Unexpected public keys %v", pubKeys)
+	}
+	if _, err := ParsePublicKeys("0xabcd"); err == nil {
+		t.Error("Expected error for short public key")
+	}
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// beaconNodesScheme is the resolver scheme of the connection routed to the selected beacon node.
+const beaconNodesScheme = "beacon-nodes"
+
+// healthCheckTimeout is the time to wait for the health of a beacon node.
+const healthCheckTimeout = 5 * time.Second
+
+// Check the health of the beacon nodes once per slot.
+func healthCheckPeriod() time.Duration {
+	return time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+}
+
+// maxHeadSlotLag is the number of slots a beacon node may be behind the others before it is
+// no longer preferred.
+func maxHeadSlotLag() uint64 {
+	return params.BeaconConfig().SlotsPerEpoch
+}
+
+// endpointState is the last known health of a beacon node endpoint.
+type endpointState struct {
+	endpoint  string
+	conn      *grpc.ClientConn
+	node      ethpb.NodeClient
+	beacon    ethpb.BeaconChainClient
+	reachable bool
+	syncing   bool
+	headSlot  uint64
+}
+
+// connManager keeps the connection of the validator client to one of several beacon nodes. The
+// health of each node is checked with a connection of its own, the connection used for duties is
+// routed to the selected node and fails over to another node once the selected one becomes
+// unreachable or falls behind.
+type connManager struct {
+	endpoints []*endpointState
+	resolver  *manual.Resolver
+	current   int
+	lock      sync.RWMutex
+}
+
+// newConnManager dials the beacon node endpoints, returning the connection manager and the
+// connection routed to the selected beacon node.
+func newConnManager(ctx context.Context, endpoints []string, dialOpts []grpc.DialOption) (*connManager, *grpc.ClientConn, error) {
+	if len(endpoints) == 0 {
+		return nil, nil, errors.New("no beacon node endpoints")
+	}
+	m := &connManager{
+		endpoints: make([]*endpointState, len(endpoints)),
+		resolver:  manual.NewBuilderWithScheme(beaconNodesScheme),
+	}
+	for i, endpoint := range endpoints {
+		conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
+		if err != nil {
+			m.close()
+			return nil, nil, errors.Wrapf(err, "could not dial endpoint %s", endpoint)
+		}
+		m.endpoints[i] = &endpointState{
+			endpoint:  endpoint,
+			conn:      conn,
+			node:      ethpb.NewNodeClient(conn),
+			beacon:    ethpb.NewBeaconChainClient(conn),
+			reachable: true,
+		}
+	}
+	m.resolver.InitialState(resolver.State{Addresses: []resolver.Address{endpointAddress(endpoints[0])}})
+	conn, err := grpc.DialContext(ctx, beaconNodesScheme+":///"+endpoints[0], append(dialOpts, grpc.WithResolvers(m.resolver))...)
+	if err != nil {
+		m.close()
+		return nil, nil, errors.Wrap(err, "could not dial beacon nodes")
+	}
+	return m, conn, nil
+}
+
+// endpointAddress returns the resolver address of the endpoint, verifying the host of the
+// endpoint for secure connections.
+func endpointAddress(endpoint string) resolver.Address {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		host = endpoint
+	}
+	return resolver.Address{Addr: endpoint, ServerName: host}
+}
+
+// run checks the health of the beacon nodes periodically, failing over to another beacon node
+// when needed.
+func (m *connManager) run(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckPeriod())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.checkHealth(ctx)
+			m.failover()
+		case <-ctx.Done():
+			log.Debug("Context closed, exiting beacon node health check routine")
+			return
+		}
+	}
+}
+
+// checkHealth updates the state of each beacon node with its sync status and head slot.
+func (m *connManager) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range m.endpoints {
+		wg.Add(1)
+		go func(e *endpointState) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			reachable, syncing, headSlot := true, false, uint64(0)
+			status, err := e.node.GetSyncStatus(ctx, &ptypes.Empty{})
+			if err == nil {
+				syncing = status.Syncing
+				var head *ethpb.ChainHead
+				head, err = e.beacon.GetChainHead(ctx, &ptypes.Empty{})
+				if err == nil {
+					headSlot = head.HeadSlot
+				}
+			}
+			if err != nil {
+				log.WithError(err).WithField("endpoint", e.endpoint).Debug("Beacon node health check failed")
+				reachable = false
+			}
+			m.lock.Lock()
+			e.reachable, e.syncing, e.headSlot = reachable, syncing, headSlot
+			m.lock.Unlock()
+		}(e)
+	}
+	wg.Wait()
+}
+
+// failover routes the connection to another beacon node, if one is preferred over the current.
+func (m *connManager) failover() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	selected := m.selectEndpoint()
+	if selected == m.current {
+		return
+	}
+	log.WithFields(logrus.Fields{
+		"from": m.endpoints[m.current].endpoint,
+		"to":   m.endpoints[selected].endpoint,
+	}).Warn("Failing over to another beacon node")
+	m.current = selected
+	m.resolver.UpdateState(resolver.State{Addresses: []resolver.Address{endpointAddress(m.endpoints[selected].endpoint)}})
+}
+
+// selectEndpoint returns the beacon node to connect to. Synced beacon nodes close to the highest
+// head slot are preferred in the order of the endpoints, the current beacon node is kept as long
+// as it is preferred, or as long as no other beacon node is reachable.
+func (m *connManager) selectEndpoint() int {
+	var highest uint64
+	for _, e := range m.endpoints {
+		if e.reachable && e.headSlot > highest {
+			highest = e.headSlot
+		}
+	}
+	healthy := func(e *endpointState) bool {
+		return e.reachable && !e.syncing && e.headSlot+maxHeadSlotLag() >= highest
+	}
+	if healthy(m.endpoints[m.current]) {
+		return m.current
+	}
+	for i, e := range m.endpoints {
+		if healthy(e) {
+			return i
+		}
+	}
+	if m.endpoints[m.current].reachable {
+		return m.current
+	}
+	for i, e := range m.endpoints {
+		if e.reachable {
+			return i
+		}
+	}
+	return m.current
+}
+
+// currentEndpoint returns the endpoint of the beacon node the connection is routed to.
+func (m *connManager) currentEndpoint() string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.endpoints[m.current].endpoint
+}
+
+// close closes the health check connections of the beacon nodes.
+func (m *connManager) close() {
+	for _, e := range m.endpoints {
+		if e == nil || e.conn == nil {
+			continue
+		}
+		if err := e.conn.Close(); err != nil {
+			log.WithError(err).WithField("endpoint", e.endpoint).Debug("Could not close connection")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"google.golang.org/grpc"
+)
+
+func TestConnManager_SelectEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   int
+		endpoints []*endpointState
+		want      int
+	}{
+		{
+			name:    "keeps healthy current",
+			current: 1,
+			endpoints: []*endpointState{
+				{reachable: true, headSlot: 100},
+				{reachable: true, headSlot: 100},
+			},
+			want: 1,
+		},
+		{
+			name:    "fails over from unreachable",
+			current: 0,
+			endpoints: []*endpointState{
+				{reachable: false},
+				{reachable: true, headSlot: 100},
+			},
+			want: 1,
+		},
+		{
+			name:    "fails over from syncing",
+			current: 0,
+			endpoints: []*endpointState{
+				{reachable: true, syncing: true, headSlot: 100},
+				{reachable: true, syncing: true, headSlot: 100},
+				{reachable: true, headSlot: 100},
+			},
+			want: 2,
+		},
+		{
+			name:    "fails over from behind",
+			current: 0,
+			endpoints: []*endpointState{
+				{reachable: true, headSlot: 100},
+				{reachable: true, headSlot: 100 + maxHeadSlotLag() + 1},
+			},
+			want: 1,
+		},
+		{
+			name:    "keeps reachable current without healthy",
+			current: 1,
+			endpoints: []*endpointState{
+				{reachable: false},
+				{reachable: true, syncing: true},
+			},
+			want: 1,
+		},
+		{
+			name:    "fails over to reachable without healthy",
+			current: 0,
+			endpoints: []*endpointState{
+				{reachable: false},
+				{reachable: true, syncing: true},
+			},
+			want: 1,
+		},
+		{
+			name:    "keeps current without reachable",
+			current: 1,
+			endpoints: []*endpointState{
+				{reachable: false},
+				{reachable: false},
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &connManager{endpoints: tt.endpoints, current: tt.current}
+			if got := m.selectEndpoint(); got != tt.want {
+				t.Errorf("Wanted endpoint %d, received %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConnManager_FailsOver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	m, conn, err := newConnManager(ctx, []string{"localhost:4000", "localhost:4001"}, []grpc.DialOption{grpc.WithInsecure()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		m.close()
+		if err := conn.Close(); err != nil {
+			t.Log(err)
+		}
+	}()
+	if m.currentEndpoint() != "localhost:4000" {
+		t.Errorf("Wanted the first endpoint, received %s", m.currentEndpoint())
+	}
+
+	// The first beacon node is unreachable.
+	node0 := mock.NewMockNodeClient(ctrl)
+	node0.EXPECT().GetSyncStatus(gomock.Any(), gomock.Any()).Return(nil, errors.New("unavailable"))
+	m.endpoints[0].node = node0
+	node1 := mock.NewMockNodeClient(ctrl)
+	node1.EXPECT().GetSyncStatus(gomock.Any(), gomock.Any()).Return(&ethpb.SyncStatus{Syncing: false}, nil)
+	beacon1 := mock.NewMockBeaconChainClient(ctrl)
+	beacon1.EXPECT().GetChainHead(gomock.Any(), gomock.Any()).Return(&ethpb.ChainHead{HeadSlot: 10}, nil)
+	m.endpoints[1].node = node1
+	m.endpoints[1].beacon = beacon1
+
+	m.checkHealth(ctx)
+	if m.endpoints[0].reachable {
+		t.Error("Expected the first beacon node to be unreachable")
+	}
+	if !m.endpoints[1].reachable || m.endpoints[1].headSlot != 10 {
+		t.Errorf("Unexpected state of the second beacon node %+v", m.endpoints[1])
+	}
+	m.failover()
+	if m.currentEndpoint() != "localhost:4001" {
+		t.Errorf("Wanted failover to the second endpoint, received %s", m.currentEndpoint())
+	}
+}
+
+func TestEndpointAddress(t *testing.T) {
+	addr := endpointAddress("beacon.example.com:4000")
+	if addr.Addr != "beacon.example.com:4000" || addr.ServerName != "beacon.example.com" {
+		t.Errorf("Unexpected address %+v", addr)
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/sirupsen/logrus"
+)
+
+// livenessTimeout is the time to wait for the liveness of validators from the beacon node.
+const livenessTimeout = 10 * time.Second
+
+// livenessFetcher returns whether the validators of the indices were live in an epoch.
+type livenessFetcher interface {
+	Liveness(ctx context.Context, epoch uint64, indices []uint64) (map[uint64]bool, error)
+}
+
+// httpLivenessFetcher fetches the liveness of validators from the Eth2 HTTP API of a beacon node.
+type httpLivenessFetcher struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPLivenessFetcher(url string) *httpLivenessFetcher {
+	return &httpLivenessFetcher{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: livenessTimeout},
+	}
+}
+
+// Liveness returns whether the validators of the indices were live in the epoch.
+func (f *httpLivenessFetcher) Liveness(ctx context.Context, epoch uint64, indices []uint64) (map[uint64]bool, error) {
+	reqIndices := make([]string, len(indices))
+	for i, index := range indices {
+		reqIndices[i] = strconv.FormatUint(index, 10)
+	}
+	body, err := json.Marshal(reqIndices)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/eth/v1/validator/liveness/%d", f.url, epoch), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("beacon node returned status %s", resp.Status)
+	}
+	res := &struct {
+		Data []struct {
+			Index  uint64 `json:"index,string"`
+			IsLive bool   `json:"is_live"`
+		} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return nil, errors.Wrap(err, "could not decode liveness")
+	}
+	live := make(map[uint64]bool, len(res.Data))
+	for _, d := range res.Data {
+		live[d.Index] = d.IsLive
+	}
+	return live, nil
+}
+
+// CheckDoppelganger watches the network for the configured number of epochs before the
+// validator performs its duties, returning an error if any of its validators is seen live,
+// which means the keys are validating elsewhere. Only epochs after the current one are watched,
+// so a validator client restarting does not detect its own previous attestations.
+func (v *validator) CheckDoppelganger(ctx context.Context) error {
+	if v.doppelgangerEpochs == 0 || v.liveness == nil {
+		return nil
+	}
+	pubKeysByIndex, err := v.activeValidators(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pubKeysByIndex) == 0 {
+		return nil
+	}
+	headSlot, err := v.CanonicalHeadSlot(ctx)
+	if err != nil {
+		return err
+	}
+	startEpoch := helpers.SlotToEpoch(headSlot) + 1
+	endEpoch := startEpoch + v.doppelgangerEpochs
+	log.WithFields(logrus.Fields{
+		"validators": len(pubKeysByIndex),
+		"epochs":     v.doppelgangerEpochs,
+	}).Info("Watching the network for validators with the same keys before performing duties")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case slot := <-v.NextSlot():
+			if !helpers.IsEpochStart(slot) {
+				continue
+			}
+			epoch := helpers.SlotToEpoch(slot)
+			// The attestations of an epoch are included up to the end of the next epoch, so each
+			// epoch is checked again once that epoch is over.
+			for back := uint64(1); back <= 2 && back <= epoch; back++ {
+				checked := epoch - back
+				if checked < startEpoch || checked >= endEpoch {
+					continue
+				}
+				if err := v.checkLiveness(ctx, checked, pubKeysByIndex); err != nil {
+					return err
+				}
+			}
+			if epoch > endEpoch {
+				log.Info("No validators with the same keys found, performing duties")
+				return nil
+			}
+		}
+	}
+}
+
+// activeValidators returns the public keys of the active validators of the validator client by
+// their indices. Only active validators are able to validate elsewhere.
+func (v *validator) activeValidators(ctx context.Context) (map[uint64][]byte, error) {
+	pubKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get validating keys")
+	}
+	req := &ethpb.MultipleValidatorStatusRequest{PublicKeys: make([][]byte, len(pubKeys))}
+	for i := range pubKeys {
+		req.PublicKeys[i] = pubKeys[i][:]
+	}
+	resp, err := v.validatorClient.MultipleValidatorStatus(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get validator statuses")
+	}
+	pubKeysByIndex := make(map[uint64][]byte, len(resp.Indices))
+	for i, st := range resp.Statuses {
+		switch st.Status {
+		case ethpb.ValidatorStatus_ACTIVE, ethpb.ValidatorStatus_EXITING, ethpb.ValidatorStatus_SLASHING:
+			pubKeysByIndex[resp.Indices[i]] = resp.PublicKeys[i]
+		}
+	}
+	return pubKeysByIndex, nil
+}
+
+// checkLiveness returns an error if any of the validators was live in the epoch.
+func (v *validator) checkLiveness(ctx context.Context, epoch uint64, pubKeysByIndex map[uint64][]byte) error {
+	indices := make([]uint64, 0, len(pubKeysByIndex))
+	for index := range pubKeysByIndex {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+	live, err := v.liveness.Liveness(ctx, epoch, indices)
+	if err != nil {
+		return errors.Wrapf(err, "could not get liveness of validators at epoch %d", epoch)
+	}
+	var duplicates []string
+	for _, index := range indices {
+		if live[index] {
+			duplicates = append(duplicates, fmt.Sprintf("%#x", bytesutil.Trunc(pubKeysByIndex[index])))
+		}
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("validators %s are live at epoch %d, the keys are validating elsewhere", strings.Join(duplicates, ", "), epoch)
+	}
+	return nil
+}
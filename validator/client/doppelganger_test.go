@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+)
+
+type fakeLivenessFetcher struct {
+	live   map[uint64]map[uint64]bool
+	epochs []uint64
+}
+
+func (f *fakeLivenessFetcher) Liveness(_ context.Context, epoch uint64, indices []uint64) (map[uint64]bool, error) {
+	f.epochs = append(f.epochs, epoch)
+	live := make(map[uint64]bool)
+	for _, index := range indices {
+		live[index] = f.live[epoch][index]
+	}
+	return live, nil
+}
+
+func TestHTTPLivenessFetcher_Liveness(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/eth/v1/validator/liveness/5" {
+			http.NotFound(w, r)
+			return
+		}
+		var indices []string
+		if err := json.NewDecoder(r.Body).Decode(&indices); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data := make([]interface{}, len(indices))
+		for i, index := range indices {
+			data[i] = map[string]interface{}{"index": index, "is_live": index == "2"}
+		}
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"data": data}); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	f := newHTTPLivenessFetcher(srv.URL + "/")
+	live, err := f.Liveness(context.Background(), 5, []uint64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[uint64]bool{1: false, 2: true}; !reflect.DeepEqual(live, want) {
+		t.Errorf("Wanted liveness %v, received %v", want, live)
+	}
+	if _, err := f.Liveness(context.Background(), 6, []uint64{1}); err == nil {
+		t.Error("Expected error for a failed request")
+	}
+}
+
+func TestCheckDoppelganger_Disabled(t *testing.T) {
+	v := &validator{liveness: &fakeLivenessFetcher{}}
+	if err := v.CheckDoppelganger(context.Background()); err != nil {
+		t.Errorf("Wanted no error when disabled, received %v", err)
+	}
+}
+
+func TestActiveValidators(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	v := &validator{validatorClient: client, keyManager: testKeyManager}
+
+	keys, err := testKeyManager.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.EXPECT().MultipleValidatorStatus(gomock.Any(), gomock.Any()).Return(&ethpb.MultipleValidatorStatusResponse{
+		PublicKeys: [][]byte{keys[0][:], []byte("pending"), []byte("exiting")},
+		Statuses: []*ethpb.ValidatorStatusResponse{
+			{Status: ethpb.ValidatorStatus_ACTIVE},
+			{Status: ethpb.ValidatorStatus_PENDING},
+			{Status: ethpb.ValidatorStatus_EXITING},
+		},
+		Indices: []uint64{3, 4, 5},
+	}, nil)
+
+	active, err := v.activeValidators(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint64][]byte{3: keys[0][:], 5: []byte("exiting")}
+	if !reflect.DeepEqual(active, want) {
+		t.Errorf("Wanted active validators %v, received %v", want, active)
+	}
+}
+
+func TestCheckLiveness(t *testing.T) {
+	liveness := &fakeLivenessFetcher{live: map[uint64]map[uint64]bool{
+		4: {7: true},
+	}}
+	v := &validator{liveness: liveness}
+	pubKeysByIndex := map[uint64][]byte{3: []byte("validator3"), 7: []byte("validator7")}
+
+	if err := v.checkLiveness(context.Background(), 3, pubKeysByIndex); err != nil {
+		t.Errorf("Wanted no error without live validators, received %v", err)
+	}
+	err := v.checkLiveness(context.Background(), 4, pubKeysByIndex)
+	if err == nil || !strings.Contains(err.Error(), "76616c696461") {
+		t.Errorf("Wanted error naming the live validator, received %v", err)
+	}
+	if !reflect.DeepEqual(liveness.epochs, []uint64{3, 4}) {
+		t.Errorf("Unexpected liveness requests %v", liveness.epochs)
+	}
+}
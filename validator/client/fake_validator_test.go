@@ -10,6 +10,7 @@ var _ = Validator(&fakeValidator{})
 type fakeValidator struct {
 	DoneCalled                       bool
 	WaitForActivationCalled          bool
+	CheckDoppelgangerCalled          bool
 	WaitForChainStartCalled          bool
 	WaitForSyncCalled                bool
 	WaitForSyncedCalled              bool
@@ -30,6 +31,7 @@ type fakeValidator struct {
 	NextSlotRet                      <-chan uint64
 	PublicKey                        string
 	UpdateDutiesRet                  error
+	StreamDutiesCalled               chan bool
 	RolesAtRet                       []validatorRole
 }
 
@@ -47,6 +49,11 @@ func (fv *fakeValidator) WaitForActivation(_ context.Context) error {
 	return nil
 }
 
+func (fv *fakeValidator) CheckDoppelganger(_ context.Context) error {
+	fv.CheckDoppelgangerCalled = true
+	return nil
+}
+
 func (fv *fakeValidator) WaitForSync(_ context.Context) error {
 	fv.WaitForSyncCalled = true
 	return nil
@@ -78,6 +85,16 @@ func (fv *fakeValidator) UpdateDuties(_ context.Context, slot uint64) error {
 	return fv.UpdateDutiesRet
 }
 
+func (fv *fakeValidator) UpdateDutyCountdowns(_ uint64) {}
+
+func (fv *fakeValidator) StreamDuties(ctx context.Context) error {
+	if fv.StreamDutiesCalled != nil {
+		fv.StreamDutiesCalled <- true
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
 func (fv *fakeValidator) UpdateProtections(_ context.Context, slot uint64) error {
 	fv.UpdateProtectionsCalled = true
 	return nil
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// GraffitiOrdered rotates through the graffiti of a list in order, one per proposal.
+	GraffitiOrdered = "ordered"
+	// GraffitiRandom picks the graffiti of a list at random on every proposal.
+	GraffitiRandom = "random"
+)
+
+// Graffiti selects the graffiti included in the blocks proposed by each validator key. It is read
+// from a YAML file such as
+//
+//	default: "Prysm"
+//	mode: random
+//	specific:
+//	  "0xa99a...": "my graffiti"
+//	  "0xb0c1...": ["first", "second"]
+//
+// where every graffiti is a string or a list of strings rotated through according to the mode,
+// ordered by default. Keys which are not listed in specific use the default graffiti.
+type Graffiti struct {
+	Default  graffitiList            `yaml:"default"`
+	Mode     string                  `yaml:"mode,omitempty"`
+	Specific map[string]graffitiList `yaml:"specific,omitempty"`
+
+	specific map[[48]byte]graffitiList
+	lock     sync.Mutex
+	next     map[[48]byte]int
+	rand     *rand.Rand
+}
+
+// graffitiList is a list of graffiti which can also be given as a single string.
+type graffitiList []string
+
+// UnmarshalYAML accepts a single string as well as a list of strings.
+func (l *graffitiList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		*l = graffitiList{s}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// ParseGraffiti parses a graffiti configuration. Content which is not a YAML mapping, like the
+// plain graffiti files read before, is the graffiti of all keys.
+func ParseGraffiti(content string) (*Graffiti, error) {
+	var mapping map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &mapping); err != nil || mapping == nil {
+		return newGraffiti(&Graffiti{Default: graffitiList{strings.TrimRight(content, "\r\n")}})
+	}
+	g := &Graffiti{}
+	if err := yaml.UnmarshalStrict([]byte(content), g); err != nil {
+		return nil, errors.Wrap(err, "could not parse graffiti configuration")
+	}
+	return newGraffiti(g)
+}
+
+func newGraffiti(g *Graffiti) (*Graffiti, error) {
+	switch g.Mode {
+	case "", GraffitiOrdered, GraffitiRandom:
+	default:
+		return nil, errors.Errorf("unknown graffiti mode %q, expected %q or %q", g.Mode, GraffitiOrdered, GraffitiRandom)
+	}
+	g.specific = make(map[[48]byte]graffitiList, len(g.Specific))
+	for key, list := range g.Specific {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil || len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+			return nil, errors.Errorf("invalid public key %q in graffiti configuration", key)
+		}
+		g.specific[bytesutil.ToBytes48(pubKey)] = list
+	}
+	g.next = make(map[[48]byte]int)
+	g.rand = rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404
+	return g, nil
+}
+
+// forKey returns the graffiti of the next block proposed by the validator key.
+func (g *Graffiti) forKey(pubKey [48]byte) []byte {
+	if g == nil {
+		return nil
+	}
+	list, ok := g.specific[pubKey]
+	if !ok {
+		list = g.Default
+	}
+	switch len(list) {
+	case 0:
+		return nil
+	case 1:
+		return []byte(list[0])
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.Mode == GraffitiRandom {
+		return []byte(list[g.rand.Intn(len(list))])
+	}
+	i := g.next[pubKey] % len(list)
+	g.next[pubKey] = i + 1
+	return []byte(list[i])
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+func TestParseGraffiti_PlainString(t *testing.T) {
+	g, err := ParseGraffiti("my graffiti\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(g.forKey([48]byte{1})); got != "my graffiti" {
+		t.Errorf("Wanted plain graffiti, received %q", got)
+	}
+
+	var nilGraffiti *Graffiti
+	if got := nilGraffiti.forKey([48]byte{1}); got != nil {
+		t.Errorf("Wanted no graffiti, received %q", got)
+	}
+}
+
+func TestParseGraffiti_Specific(t *testing.T) {
+	specificKey := bytesutil.ToBytes48([]byte{1})
+	listKey := bytesutil.ToBytes48([]byte{2})
+	g, err := ParseGraffiti(fmt.Sprintf(`
+default: "default"
+specific:
+  "%#x": "specific"
+  "%#x": ["first", "second", "third"]
+`, specificKey, listKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(g.forKey([48]byte{3})); got != "default" {
+		t.Errorf("Wanted default graffiti, received %q", got)
+	}
+	for i := 0; i < 2; i++ {
+		if got := string(g.forKey(specificKey)); got != "specific" {
+			t.Errorf("Wanted specific graffiti, received %q", got)
+		}
+	}
+	for _, want := range []string{"first", "second", "third", "first"} {
+		if got := string(g.forKey(listKey)); got != want {
+			t.Errorf("Wanted ordered graffiti %q, received %q", want, got)
+		}
+	}
+}
+
+func TestParseGraffiti_Random(t *testing.T) {
+	g, err := ParseGraffiti(`
+default: ["first", "second"]
+mode: random
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		seen[string(g.forKey([48]byte{1}))] = true
+	}
+	if len(seen) != 2 || !seen["first"] || !seen["second"] {
+		t.Errorf("Wanted both graffiti at random, received %v", seen)
+	}
+}
+
+func TestParseGraffiti_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown mode", content: "default: x\nmode: shuffled\n"},
+		{name: "invalid key", content: "specific:\n  \"0x1234\": x\n"},
+		{name: "unknown field", content: "default: x\nspecifc: {}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseGraffiti(tt.content); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+// interchangeFormatVersion is the version of the slashing protection interchange format
+// (EIP-3076) which can be imported.
+const interchangeFormatVersion = "5"
+
+// Interchange is the standard slashing protection interchange format, exchanging the signing
+// history of validators between clients.
+type Interchange struct {
+	Metadata struct {
+		InterchangeFormatVersion string `json:"interchange_format_version"`
+		GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+	} `json:"metadata"`
+	Data []*InterchangeData `json:"data"`
+}
+
+// InterchangeData is the signing history of a single validator.
+type InterchangeData struct {
+	PublicKey          string                    `json:"pubkey"`
+	SignedBlocks       []*InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []*InterchangeAttestation `json:"signed_attestations"`
+}
+
+// InterchangeBlock is a block signed by a validator.
+type InterchangeBlock struct {
+	Slot        uint64 `json:"slot,string"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// InterchangeAttestation is an attestation signed by a validator.
+type InterchangeAttestation struct {
+	SourceEpoch uint64 `json:"source_epoch,string"`
+	TargetEpoch uint64 `json:"target_epoch,string"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// ImportSlashingProtectionInterchange reads an interchange file of the network with the given
+// genesis validators root and records its blocks and attestations in the validator db, so they
+// are never signed again. Nothing is imported if the interchange is invalid or belongs to
+// another network.
+func ImportSlashingProtectionInterchange(
+	ctx context.Context,
+	valDB *db.Store,
+	genesisValidatorsRoot []byte,
+	r io.Reader,
+) error {
+	interchange := &Interchange{}
+	if err := json.NewDecoder(r).Decode(interchange); err != nil {
+		return errors.Wrap(err, "could not decode slashing protection interchange")
+	}
+	if interchange.Metadata.InterchangeFormatVersion != interchangeFormatVersion {
+		return fmt.Errorf(
+			"unsupported interchange format version %s, wanted %s",
+			interchange.Metadata.InterchangeFormatVersion,
+			interchangeFormatVersion,
+		)
+	}
+	root, err := decodeHex(interchange.Metadata.GenesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "invalid genesis validators root")
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot) {
+		return fmt.Errorf("interchange genesis validators root %#x does not match %#x", root, genesisValidatorsRoot)
+	}
+	pubKeys := make([][48]byte, len(interchange.Data))
+	for i, data := range interchange.Data {
+		pubKey, err := decodeHex(data.PublicKey)
+		if err != nil || len(pubKey) != 48 {
+			return fmt.Errorf("invalid public key %s", data.PublicKey)
+		}
+		pubKeys[i] = bytesutil.ToBytes48(pubKey)
+		for _, att := range data.SignedAttestations {
+			if att.SourceEpoch > att.TargetEpoch {
+				return fmt.Errorf("attestation of %s has source epoch %d after target epoch %d", data.PublicKey, att.SourceEpoch, att.TargetEpoch)
+			}
+		}
+	}
+
+	if err := valDB.UpdatePublicKeysBuckets(pubKeys); err != nil {
+		return errors.Wrap(err, "could not initialize proposal histories")
+	}
+	histories, err := valDB.AttestationHistoryForPubKeys(ctx, pubKeys)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve attestation histories")
+	}
+	for i, data := range interchange.Data {
+		for _, blk := range data.SignedBlocks {
+			epoch := blk.Slot / params.BeaconConfig().SlotsPerEpoch
+			slotBits, err := valDB.ProposalHistoryForEpoch(ctx, pubKeys[i][:], epoch)
+			if err != nil {
+				return errors.Wrapf(err, "could not retrieve proposal history of %s", data.PublicKey)
+			}
+			slotBits.SetBitAt(blk.Slot%params.BeaconConfig().SlotsPerEpoch, true)
+			if err := valDB.SaveProposalHistoryForEpoch(ctx, pubKeys[i][:], epoch, slotBits); err != nil {
+				return errors.Wrapf(err, "could not save proposal history of %s", data.PublicKey)
+			}
+		}
+		for _, att := range data.SignedAttestations {
+			histories[pubKeys[i]] = markAttestationForTargetEpoch(histories[pubKeys[i]], att.SourceEpoch, att.TargetEpoch)
+		}
+	}
+	if err := valDB.SaveAttestationHistoryForPubKeys(ctx, histories); err != nil {
+		return errors.Wrap(err, "could not save attestation histories")
+	}
+	return nil
+}
+
+// ExportSlashingProtectionInterchange writes the blocks and attestations recorded in the validator
+// db as an interchange of the network with the given genesis validators root. Only the history
+// kept in the db, within the weak subjectivity period, is exported, without signing roots.
+func ExportSlashingProtectionInterchange(
+	ctx context.Context,
+	valDB *db.Store,
+	genesisValidatorsRoot []byte,
+	w io.Writer,
+) error {
+	pubKeys, err := valDB.PublicKeys()
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve validator public keys")
+	}
+	histories, err := valDB.AttestationHistoryForPubKeys(ctx, pubKeys)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve attestation histories")
+	}
+
+	interchange := &Interchange{Data: make([]*InterchangeData, 0, len(pubKeys))}
+	interchange.Metadata.InterchangeFormatVersion = interchangeFormatVersion
+	interchange.Metadata.GenesisValidatorsRoot = fmt.Sprintf("%#x", genesisValidatorsRoot)
+	for _, pubKey := range pubKeys {
+		data := &InterchangeData{
+			PublicKey:          fmt.Sprintf("%#x", pubKey),
+			SignedBlocks:       []*InterchangeBlock{},
+			SignedAttestations: []*InterchangeAttestation{},
+		}
+		slots, err := valDB.ProposedSlots(ctx, pubKey[:])
+		if err != nil {
+			return errors.Wrapf(err, "could not retrieve proposal history of %#x", pubKey)
+		}
+		for _, slot := range slots {
+			data.SignedBlocks = append(data.SignedBlocks, &InterchangeBlock{Slot: slot})
+		}
+		data.SignedAttestations = signedAttestations(histories[pubKey])
+		interchange.Data = append(interchange.Data, data)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(interchange); err != nil {
+		return errors.Wrap(err, "could not encode slashing protection interchange")
+	}
+	return nil
+}
+
+// signedAttestations returns the attestations recorded in an attestation history, by ascending
+// target epoch.
+func signedAttestations(history *slashpb.AttestationHistory) []*InterchangeAttestation {
+	atts := []*InterchangeAttestation{}
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	var oldest uint64
+	if history.LatestEpochWritten >= wsPeriod {
+		oldest = history.LatestEpochWritten - wsPeriod + 1
+	}
+	for target := oldest; target <= history.LatestEpochWritten; target++ {
+		source, ok := history.TargetToSource[target%wsPeriod]
+		if !ok || source == params.BeaconConfig().FarFutureEpoch {
+			continue
+		}
+		atts = append(atts, &InterchangeAttestation{SourceEpoch: source, TargetEpoch: target})
+	}
+	return atts
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
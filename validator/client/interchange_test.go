@@ -0,0 +1,243 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+// interchangeTestsDirEnv names a directory of further test cases of the standard slashing
+// protection interchange test suite to run along with the cases vendored under testdata, such as
+// the tests/generated directory of a checkout of the slashing-protection-interchange-tests repository.
+const interchangeTestsDirEnv = "SLASHING_PROTECTION_INTERCHANGE_TESTS"
+
+// interchangeTestCase is a test case of the standard slashing protection interchange test suite.
+// Each step imports an interchange, then attempts to sign blocks and attestations.
+type interchangeTestCase struct {
+	Name                  string `json:"name"`
+	GenesisValidatorsRoot string `json:"genesis_validators_root"`
+	Steps                 []struct {
+		ShouldSucceed bool            `json:"should_succeed"`
+		Interchange   json.RawMessage `json:"interchange"`
+		Blocks        []struct {
+			PublicKey     string `json:"pubkey"`
+			Slot          uint64 `json:"slot,string"`
+			ShouldSucceed bool   `json:"should_succeed"`
+		} `json:"blocks"`
+		Attestations []struct {
+			PublicKey     string `json:"pubkey"`
+			SourceEpoch   uint64 `json:"source_epoch,string"`
+			TargetEpoch   uint64 `json:"target_epoch,string"`
+			ShouldSucceed bool   `json:"should_succeed"`
+		} `json:"attestations"`
+	} `json:"steps"`
+}
+
+func TestSlashingProtectionInterchange(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "slashing-protection-interchange", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir := os.Getenv(interchangeTestsDirEnv); dir != "" {
+		extra, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, extra...)
+	}
+	if len(files) == 0 {
+		t.Fatal("No interchange test cases found")
+	}
+	for _, file := range files {
+		enc, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tc := &interchangeTestCase{}
+		if err := json.Unmarshal(enc, tc); err != nil {
+			t.Fatalf("Could not decode %s: %v", file, err)
+		}
+		t.Run(tc.Name, func(t *testing.T) {
+			runInterchangeTestCase(t, tc)
+		})
+	}
+}
+
+func runInterchangeTestCase(t *testing.T, tc *interchangeTestCase) {
+	ctx := context.Background()
+	genesisValidatorsRoot, err := decodeHex(tc.GenesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The proposal histories are only initialized for the validators the db is created with.
+	var pubKeys [][48]byte
+	seen := make(map[[48]byte]bool)
+	addPubKey := func(s string) {
+		pubKey, err := decodeHex(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := bytesutil.ToBytes48(pubKey)
+		if !seen[key] {
+			seen[key] = true
+			pubKeys = append(pubKeys, key)
+		}
+	}
+	for _, step := range tc.Steps {
+		interchange := &Interchange{}
+		if err := json.Unmarshal(step.Interchange, interchange); err == nil {
+			for _, data := range interchange.Data {
+				addPubKey(data.PublicKey)
+			}
+		}
+		for _, blk := range step.Blocks {
+			addPubKey(blk.PublicKey)
+		}
+		for _, att := range step.Attestations {
+			addPubKey(att.PublicKey)
+		}
+	}
+	valDB := db.SetupDB(t, pubKeys)
+	v := &validator{db: valDB}
+
+	for i, step := range tc.Steps {
+		err := ImportSlashingProtectionInterchange(ctx, valDB, genesisValidatorsRoot, bytes.NewReader(step.Interchange))
+		if step.ShouldSucceed && err != nil {
+			t.Fatalf("Step %d: could not import interchange: %v", i, err)
+		}
+		if !step.ShouldSucceed && err == nil {
+			t.Fatalf("Step %d: expected interchange import to fail", i)
+		}
+		for _, blk := range step.Blocks {
+			pubKey, err := decodeHex(blk.PublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signed, err := attemptBlockSigning(ctx, v, bytesutil.ToBytes48(pubKey), blk.Slot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signed != blk.ShouldSucceed {
+				t.Errorf("Step %d: wanted signing block at slot %d by %s permitted %v, received %v", i, blk.Slot, blk.PublicKey, blk.ShouldSucceed, signed)
+			}
+		}
+		for _, att := range step.Attestations {
+			pubKey, err := decodeHex(att.PublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			signed, err := attemptAttestationSigning(ctx, v, bytesutil.ToBytes48(pubKey), att.SourceEpoch, att.TargetEpoch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signed != att.ShouldSucceed {
+				t.Errorf(
+					"Step %d: wanted signing attestation with source %d and target %d by %s permitted %v, received %v",
+					i, att.SourceEpoch, att.TargetEpoch, att.PublicKey, att.ShouldSucceed, signed,
+				)
+			}
+		}
+	}
+}
+
+func TestSlashingProtectionInterchange_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	root := bytes.Repeat([]byte{0x0a}, 32)
+	pubKey := "0x" + strings.Repeat("b8", 48)
+	interchange := fmt.Sprintf(`{
+  "metadata": {"interchange_format_version": "5", "genesis_validators_root": "%#x"},
+  "data": [{
+    "pubkey": "%s",
+    "signed_blocks": [{"slot": "3"}, {"slot": "70"}],
+    "signed_attestations": [{"source_epoch": "1", "target_epoch": "2"}, {"source_epoch": "2", "target_epoch": "5"}]
+  }]
+}`, root, pubKey)
+
+	// The db is created without the public key of the interchange.
+	valDB := db.SetupDB(t, nil)
+	if err := ImportSlashingProtectionInterchange(ctx, valDB, root, strings.NewReader(interchange)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ExportSlashingProtectionInterchange(ctx, valDB, root, &buf); err != nil {
+		t.Fatal(err)
+	}
+	exported := &Interchange{}
+	if err := json.Unmarshal(buf.Bytes(), exported); err != nil {
+		t.Fatal(err)
+	}
+	if exported.Metadata.InterchangeFormatVersion != interchangeFormatVersion ||
+		exported.Metadata.GenesisValidatorsRoot != fmt.Sprintf("%#x", root) {
+		t.Errorf("Unexpected metadata %+v", exported.Metadata)
+	}
+	if len(exported.Data) != 1 || exported.Data[0].PublicKey != pubKey {
+		t.Fatalf("Wanted the history of %s, received %+v", pubKey, exported.Data)
+	}
+	var slots []uint64
+	for _, blk := range exported.Data[0].SignedBlocks {
+		slots = append(slots, blk.Slot)
+	}
+	if !reflect.DeepEqual(slots, []uint64{3, 70}) {
+		t.Errorf("Wanted signed blocks at slots [3 70], received %v", slots)
+	}
+	var epochs [][2]uint64
+	for _, att := range exported.Data[0].SignedAttestations {
+		epochs = append(epochs, [2]uint64{att.SourceEpoch, att.TargetEpoch})
+	}
+	if !reflect.DeepEqual(epochs, [][2]uint64{{1, 2}, {2, 5}}) {
+		t.Errorf("Wanted signed attestations [[1 2] [2 5]], received %v", epochs)
+	}
+
+	// The exported history protects another db it is imported in.
+	otherDB := db.SetupDB(t, nil)
+	if err := ImportSlashingProtectionInterchange(ctx, otherDB, root, &buf); err != nil {
+		t.Fatal(err)
+	}
+	key, err := decodeHex(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &validator{db: otherDB}
+	if signed, err := attemptBlockSigning(ctx, v, bytesutil.ToBytes48(key), 70); err != nil || signed {
+		t.Errorf("Expected block at slot 70 not to be signed again, received %v, %v", signed, err)
+	}
+	if signed, err := attemptAttestationSigning(ctx, v, bytesutil.ToBytes48(key), 0, 6); err != nil || signed {
+		t.Errorf("Expected surrounding attestation not to be signed, received %v, %v", signed, err)
+	}
+}
+
+// attemptBlockSigning runs the proposal protection of the validator as ProposeBlock does, recording
+// the block if it is permitted.
+func attemptBlockSigning(ctx context.Context, v *validator, pubKey [48]byte, slot uint64) (bool, error) {
+	slotBits, proposed, err := v.proposalHistory(ctx, pubKey, slot)
+	if err != nil || proposed {
+		return false, err
+	}
+	return true, v.saveProposal(ctx, pubKey, slot, slotBits)
+}
+
+// attemptAttestationSigning runs the attestation protection of the validator as SubmitAttestation
+// does for an attester duty, recording the attestation if it is permitted.
+func attemptAttestationSigning(ctx context.Context, v *validator, pubKey [48]byte, source uint64, target uint64) (bool, error) {
+	v.duties = &ethpb.DutiesResponse{Duties: []*ethpb.DutiesResponse_Duty{{PublicKey: pubKey[:]}}}
+	if err := v.UpdateProtections(ctx, 0); err != nil {
+		return false, err
+	}
+	if v.isSlashableAttestation(pubKey, source, target) {
+		return false, nil
+	}
+	v.markAttestation(pubKey, source, target)
+	return true, v.SaveProtections(ctx)
+}
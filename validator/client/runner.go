@@ -10,6 +10,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"go.opencensus.io/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -22,11 +23,14 @@ type Validator interface {
 	WaitForSync(ctx context.Context) error
 	WaitForSynced(ctx context.Context) error
 	WaitForActivation(ctx context.Context) error
+	CheckDoppelganger(ctx context.Context) error
 	CanonicalHeadSlot(ctx context.Context) (uint64, error)
 	NextSlot() <-chan uint64
 	SlotDeadline(slot uint64) time.Time
 	LogValidatorGainsAndLosses(ctx context.Context, slot uint64) error
 	UpdateDuties(ctx context.Context, slot uint64) error
+	UpdateDutyCountdowns(slot uint64)
+	StreamDuties(ctx context.Context) error
 	UpdateProtections(ctx context.Context, slot uint64) error
 	RolesAt(ctx context.Context, slot uint64) (map[[48]byte][]validatorRole, error) // validator pubKey -> roles
 	SubmitAttestation(ctx context.Context, slot uint64, pubKey [48]byte)
@@ -43,10 +47,11 @@ type Validator interface {
 // Order of operations:
 // 1 - Initialize validator data
 // 2 - Wait for validator activation
-// 3 - Wait for the next slot start
-// 4 - Update assignments
-// 5 - Determine role at current slot
-// 6 - Perform assigned role, if any
+// 3 - Watch the network for validators with the same keys, if enabled
+// 4 - Wait for the next slot start
+// 5 - Update assignments, unless they are streamed by the beacon node
+// 6 - Determine role at current slot
+// 7 - Perform assigned role, if any
 func run(ctx context.Context, v Validator) {
 	defer v.Done()
 	if featureconfig.Get().WaitForSynced {
@@ -64,6 +69,9 @@ func run(ctx context.Context, v Validator) {
 	if err := v.WaitForActivation(ctx); err != nil {
 		log.Fatalf("Could not wait for validator activation: %v", err)
 	}
+	if err := v.CheckDoppelganger(ctx); err != nil {
+		log.Fatalf("Doppelganger detection failed, refusing to perform duties: %v", err)
+	}
 	headSlot, err := v.CanonicalHeadSlot(ctx)
 	if err != nil {
 		log.Fatalf("Could not get current canonical head slot: %v", err)
@@ -71,6 +79,9 @@ func run(ctx context.Context, v Validator) {
 	if err := v.UpdateDuties(ctx, headSlot); err != nil {
 		handleAssignmentError(err, headSlot)
 	}
+	if featureconfig.Get().EnableStreamDuties {
+		go streamDuties(ctx, v)
+	}
 	for {
 		ctx, span := trace.StartSpan(ctx, "validator.processSlot")
 
@@ -90,13 +101,17 @@ func run(ctx context.Context, v Validator) {
 			}
 
 			// Keep trying to update assignments if they are nil or if we are past an
-			// epoch transition in the beacon node's state.
-			if err := v.UpdateDuties(ctx, slot); err != nil {
-				handleAssignmentError(err, slot)
-				cancel()
-				span.End()
-				continue
+			// epoch transition in the beacon node's state. Streamed assignments are
+			// updated as the beacon node pushes them.
+			if !featureconfig.Get().EnableStreamDuties {
+				if err := v.UpdateDuties(ctx, slot); err != nil {
+					handleAssignmentError(err, slot)
+					cancel()
+					span.End()
+					continue
+				}
 			}
+			v.UpdateDutyCountdowns(slot)
 
 			if featureconfig.Get().ProtectAttester {
 				if err := v.UpdateProtections(ctx, slot); err != nil {
@@ -151,6 +166,23 @@ func run(ctx context.Context, v Validator) {
 	}
 }
 
+// streamDuties keeps receiving the validator assignments streamed by the beacon node, opening a
+// new stream a slot after the previous one ended, until the context is canceled.
+func streamDuties(ctx context.Context, v Validator) {
+	for {
+		err := v.StreamDuties(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.WithError(err).Error("Validator duties stream ended, reconnecting")
+		select {
+		case <-ctx.Done():
+			return
+		case <-slotutil.After(time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second):
+		}
+	}
+}
+
 func handleAssignmentError(err error, slot uint64) {
 	if errCode, ok := status.FromError(err); ok && errCode.Code() == codes.NotFound {
 		log.WithField(
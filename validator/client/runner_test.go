@@ -97,6 +97,33 @@ func TestUpdateDuties_HandlesError(t *testing.T) {
 	testutil.AssertLogsContain(t, hook, "Failed to update assignments")
 }
 
+func TestStreamDuties_SkipsUpdateDuties(t *testing.T) {
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableStreamDuties: true})
+	defer reset()
+	v := &fakeValidator{StreamDutiesCalled: make(chan bool, 1)}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	slot := uint64(55)
+	ticker := make(chan uint64)
+	v.NextSlotRet = ticker
+	go func() {
+		ticker <- slot
+
+		cancel()
+	}()
+
+	run(ctx, v)
+
+	select {
+	case <-v.StreamDutiesCalled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected StreamDuties() to be called")
+	}
+	if v.UpdateDutiesArg1 == slot {
+		t.Errorf("Expected UpdateDuties not to be called for slot %d", slot)
+	}
+}
+
 func TestRoleAt_NextSlot(t *testing.T) {
 	v := &fakeValidator{}
 	ctx, cancel := context.WithCancel(context.Background())
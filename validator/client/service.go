@@ -5,6 +5,7 @@ package client
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/dgraph-io/ristretto"
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -14,19 +15,17 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
-	"github.com/prysmaticlabs/go-ssz"
-	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/bls"
-	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/grpcutils"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
+	"github.com/prysmaticlabs/prysm/validator/accounts"
 	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	slashingprotection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/plugin/ocgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -35,21 +34,29 @@ var log = logrus.WithField("prefix", "validator")
 // ValidatorService represents a service to manage the validator client
 // routine.
 type ValidatorService struct {
-	ctx                  context.Context
-	cancel               context.CancelFunc
-	validator            Validator
-	graffiti             []byte
-	conn                 *grpc.ClientConn
-	endpoint             string
-	withCert             string
-	dataDir              string
-	keyManager           keymanager.KeyManager
-	logValidatorBalances bool
-	emitAccountMetrics   bool
-	maxCallRecvMsgSize   int
-	grpcRetries          uint
-	grpcHeaders          []string
-	protector            slashingprotection.Protector
+	ctx                   context.Context
+	cancel                context.CancelFunc
+	validator             Validator
+	graffiti              *Graffiti
+	graffitiLock          sync.Mutex
+	conn                  *grpc.ClientConn
+	conns                 *connManager
+	endpoint              string
+	withCert              string
+	withClientCert        string
+	withClientKey         string
+	dataDir               string
+	keyManager            keymanager.KeyManager
+	logValidatorBalances  bool
+	emitAccountMetrics    bool
+	accountMetricsMaxKeys uint64
+	maxCallRecvMsgSize    int
+	grpcRetries           uint
+	grpcHeaders           []string
+	authToken             string
+	protector             slashingprotection.Protector
+	beaconHTTPProvider    string
+	doppelgangerEpochs    uint64
 }
 
 // Config for the validator service.
@@ -57,14 +64,20 @@ type Config struct {
 	Endpoint                   string
 	DataDir                    string
 	CertFlag                   string
-	GraffitiFlag               string
+	ClientCertFlag             string
+	ClientKeyFlag              string
+	Graffiti                   *Graffiti
 	KeyManager                 keymanager.KeyManager
 	LogValidatorBalances       bool
 	EmitAccountMetrics         bool
+	AccountMetricsMaxKeys      uint64
 	GrpcMaxCallRecvMsgSizeFlag int
 	GrpcRetriesFlag            uint
 	GrpcHeadersFlag            string
+	AuthToken                  string
 	Protector                  slashingprotection.Protector
+	BeaconHTTPProvider         string
+	DoppelgangerEpochs         uint64
 }
 
 // NewValidatorService creates a new validator service for the service
@@ -72,19 +85,25 @@ type Config struct {
 func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	return &ValidatorService{
-		ctx:                  ctx,
-		cancel:               cancel,
-		endpoint:             cfg.Endpoint,
-		withCert:             cfg.CertFlag,
-		dataDir:              cfg.DataDir,
-		graffiti:             []byte(cfg.GraffitiFlag),
-		keyManager:           cfg.KeyManager,
-		logValidatorBalances: cfg.LogValidatorBalances,
-		emitAccountMetrics:   cfg.EmitAccountMetrics,
-		maxCallRecvMsgSize:   cfg.GrpcMaxCallRecvMsgSizeFlag,
-		grpcRetries:          cfg.GrpcRetriesFlag,
-		grpcHeaders:          strings.Split(cfg.GrpcHeadersFlag, ","),
-		protector:            cfg.Protector,
+		ctx:                   ctx,
+		cancel:                cancel,
+		endpoint:              cfg.Endpoint,
+		withCert:              cfg.CertFlag,
+		withClientCert:        cfg.ClientCertFlag,
+		withClientKey:         cfg.ClientKeyFlag,
+		dataDir:               cfg.DataDir,
+		graffiti:              cfg.Graffiti,
+		keyManager:            cfg.KeyManager,
+		logValidatorBalances:  cfg.LogValidatorBalances,
+		emitAccountMetrics:    cfg.EmitAccountMetrics,
+		accountMetricsMaxKeys: cfg.AccountMetricsMaxKeys,
+		maxCallRecvMsgSize:    cfg.GrpcMaxCallRecvMsgSizeFlag,
+		grpcRetries:           cfg.GrpcRetriesFlag,
+		grpcHeaders:           strings.Split(cfg.GrpcHeadersFlag, ","),
+		authToken:             cfg.AuthToken,
+		protector:             cfg.Protector,
+		beaconHTTPProvider:    cfg.BeaconHTTPProvider,
+		doppelgangerEpochs:    cfg.DoppelgangerEpochs,
 	}, nil
 }
 
@@ -96,15 +115,42 @@ func (v *ValidatorService) Start() {
 		grpc_prometheus.StreamClientInterceptor,
 		grpc_retry.StreamClientInterceptor(),
 	))
+	extraOpts := []grpc.DialOption{streamInterceptor}
+	if v.authToken != "" {
+		if v.withCert == "" && v.withClientCert == "" {
+			log.Warn("The beacon node auth token is sent over an insecure gRPC connection. Provide the certificate of the beacon node to encrypt it")
+		}
+		extraOpts = append(extraOpts, grpc.WithPerRPCCredentials(rpcauth.TokenCredentials(v.authToken)))
+	}
 	dialOpts := ConstructDialOptions(
-		v.maxCallRecvMsgSize, v.withCert, v.grpcHeaders, v.grpcRetries, streamInterceptor)
+		v.maxCallRecvMsgSize, v.withCert, v.withClientCert, v.withClientKey, v.grpcHeaders, v.grpcRetries, extraOpts...)
 	if dialOpts == nil {
 		return
 	}
-	conn, err := grpc.DialContext(v.ctx, v.endpoint, dialOpts...)
-	if err != nil {
-		log.Errorf("Could not dial endpoint: %s, %v", v.endpoint, err)
-		return
+	var endpoints []string
+	for _, endpoint := range strings.Split(v.endpoint, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	var conn *grpc.ClientConn
+	if len(endpoints) > 1 {
+		// Fail over between the beacon nodes.
+		conns, c, err := newConnManager(v.ctx, endpoints, dialOpts)
+		if err != nil {
+			log.Errorf("Could not dial endpoints: %s, %v", v.endpoint, err)
+			return
+		}
+		v.conns = conns
+		conn = c
+		go conns.run(v.ctx)
+	} else {
+		c, err := grpc.DialContext(v.ctx, v.endpoint, dialOpts...)
+		if err != nil {
+			log.Errorf("Could not dial endpoint: %s, %v", v.endpoint, err)
+			return
+		}
+		conn = c
 	}
 	log.Debug("Successfully started gRPC connection")
 
@@ -136,6 +182,13 @@ func (v *ValidatorService) Start() {
 		return
 	}
 
+	var liveness livenessFetcher
+	if v.beaconHTTPProvider != "" {
+		liveness = newHTTPLivenessFetcher(v.beaconHTTPProvider)
+	}
+
+	v.graffitiLock.Lock()
+	defer v.graffitiLock.Unlock()
 	v.validator = &validator{
 		db:                             valDB,
 		validatorClient:                ethpb.NewBeaconNodeValidatorClient(v.conn),
@@ -145,19 +198,61 @@ func (v *ValidatorService) Start() {
 		graffiti:                       v.graffiti,
 		logValidatorBalances:           v.logValidatorBalances,
 		emitAccountMetrics:             v.emitAccountMetrics,
+		accountMetricsMaxKeys:          v.accountMetricsMaxKeys,
 		prevBalance:                    make(map[[48]byte]uint64),
 		attLogs:                        make(map[[32]byte]*attSubmitted),
 		domainDataCache:                cache,
 		aggregatedSlotCommitteeIDCache: aggregatedSlotCommitteeIDCache,
 		protector:                      v.protector,
+		liveness:                       liveness,
+		doppelgangerEpochs:             v.doppelgangerEpochs,
 	}
 	go run(v.ctx, v.validator)
 }
 
+// Graffiti returns the graffiti included in the blocks proposed.
+func (v *ValidatorService) Graffiti() *Graffiti {
+	v.graffitiLock.Lock()
+	defer v.graffitiLock.Unlock()
+	return v.graffiti
+}
+
+// SetGraffiti changes the graffiti included in the blocks proposed from now on.
+func (v *ValidatorService) SetGraffiti(graffiti *Graffiti) {
+	v.graffitiLock.Lock()
+	defer v.graffitiLock.Unlock()
+	v.graffiti = graffiti
+	if val, ok := v.validator.(*validator); ok {
+		val.graffitiLock.Lock()
+		val.graffiti = graffiti
+		val.graffitiLock.Unlock()
+	}
+}
+
+// ProposeExits signs voluntary exits of the validators of the public keys with the key manager
+// of the service and proposes them to the beacon node of the service.
+func (v *ValidatorService) ProposeExits(ctx context.Context, pubKeys [][48]byte) error {
+	if v.conn == nil {
+		return errors.New("no connection to beacon RPC")
+	}
+	return accounts.ProposeExits(
+		ctx,
+		ethpb.NewBeaconNodeValidatorClient(v.conn),
+		ethpb.NewNodeClient(v.conn),
+		pubKeys,
+		func(pubKey [48]byte, exit *ethpb.VoluntaryExit, domain []byte) (*bls.Signature, error) {
+			return keymanager.SignObject(v.keyManager, pubKey, keymanager.SignTypeVoluntaryExit, exit, domain)
+		},
+	)
+}
+
 // Stop the validator service.
 func (v *ValidatorService) Stop() error {
 	v.cancel()
 	log.Info("Stopping service")
+	if v.conns != nil {
+		v.conns.close()
+	}
 	if v.conn != nil {
 		return v.conn.Close()
 	}
@@ -174,34 +269,20 @@ func (v *ValidatorService) Status() error {
 	return nil
 }
 
-// signObject signs a generic object, with protection if available.
-func (v *validator) signObject(pubKey [48]byte, object interface{}, domain []byte) (*bls.Signature, error) {
-	if protectingKeymanager, supported := v.keyManager.(keymanager.ProtectingKeyManager); supported {
-		root, err := ssz.HashTreeRoot(object)
-		if err != nil {
-			return nil, err
-		}
-		return protectingKeymanager.SignGeneric(pubKey, root, bytesutil.ToBytes32(domain))
-	}
-
-	root, err := helpers.ComputeSigningRoot(object, domain)
-	if err != nil {
-		return nil, err
-	}
-	return v.keyManager.Sign(pubKey, root)
-}
-
-// ConstructDialOptions constructs a list of grpc dial options
+// ConstructDialOptions constructs a list of grpc dial options. The client certificate
+// and key are optional and only used for mutual TLS.
 func ConstructDialOptions(
 	maxCallRecvMsgSize int,
 	withCert string,
+	withClientCert string,
+	withClientKey string,
 	grpcHeaders []string,
 	grpcRetries uint,
 	extraOpts ...grpc.DialOption,
 ) []grpc.DialOption {
 	var transportSecurity grpc.DialOption
-	if withCert != "" {
-		creds, err := credentials.NewClientTLSFromFile(withCert, "")
+	if withCert != "" || withClientCert != "" {
+		creds, err := grpcutils.ClientTLSCredentials(withCert, withClientCert, withClientKey)
 		if err != nil {
 			log.Errorf("Could not get valid credentials: %v", err)
 			return nil
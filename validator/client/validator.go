@@ -3,7 +3,6 @@ package client
 
 import (
 	"context"
-	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -24,7 +23,6 @@ import (
 	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
-	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/validator/db"
@@ -48,14 +46,19 @@ type validator struct {
 	ticker                             *slotutil.SlotTicker
 	db                                 *db.Store
 	duties                             *ethpb.DutiesResponse
+	dutiesLock                         sync.RWMutex
 	validatorClient                    ethpb.BeaconNodeValidatorClient
 	beaconClient                       ethpb.BeaconChainClient
-	graffiti                           []byte
+	graffiti                           *Graffiti
+	graffitiLock                       sync.RWMutex
 	node                               ethpb.NodeClient
 	keyManager                         keymanager.KeyManager
 	prevBalance                        map[[48]byte]uint64
 	logValidatorBalances               bool
 	emitAccountMetrics                 bool
+	accountMetricsMaxKeys              uint64
+	accountMetricsKeys                 map[[48]byte]bool
+	accountMetricsKeysLock             sync.Mutex
 	attLogs                            map[[32]byte]*attSubmitted
 	attLogsLock                        sync.Mutex
 	domainDataLock                     sync.Mutex
@@ -65,6 +68,8 @@ type validator struct {
 	attesterHistoryByPubKey            map[[48]byte]*slashpb.AttestationHistory
 	attesterHistoryByPubKeyLock        sync.RWMutex
 	protector                          slashingprotection.Protector
+	liveness                           livenessFetcher
+	doppelgangerEpochs                 uint64
 }
 
 var validatorStatusesGaugeVec = promauto.NewGaugeVec(
@@ -115,6 +120,7 @@ func (v *validator) WaitForChainStart(ctx context.Context) error {
 	}
 	// Once the ChainStart log is received, we update the genesis time of the validator client
 	// and begin a slot ticker used to track the current slot the beacon node is in.
+	slotutil.AnchorClock(time.Unix(int64(v.genesisTime), 0))
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
 	log.WithField("genesisTime", time.Unix(int64(v.genesisTime), 0)).Info("Beacon chain started")
 	return nil
@@ -136,7 +142,7 @@ func (v *validator) WaitForSync(ctx context.Context) error {
 	for {
 		select {
 		// Poll every half slot.
-		case <-time.After(slotutil.DivideSlotBy(2 /* twice per slot */)):
+		case <-slotutil.After(slotutil.DivideSlotBy(2 /* twice per slot */)):
 			s, err := v.node.GetSyncStatus(ctx, &ptypes.Empty{})
 			if err != nil {
 				return errors.Wrap(err, "could not get sync status")
@@ -180,6 +186,7 @@ func (v *validator) WaitForSynced(ctx context.Context) error {
 	}
 	// Once the Synced log is received, we update the genesis time of the validator client
 	// and begin a slot ticker used to track the current slot the beacon node is in.
+	slotutil.AnchorClock(time.Unix(int64(v.genesisTime), 0))
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
 	log.WithField("genesisTime", time.Unix(int64(v.genesisTime), 0)).Info("Chain has started and the beacon node is synced")
 	return nil
@@ -230,6 +237,7 @@ func (v *validator) WaitForActivation(ctx context.Context) error {
 			break
 		}
 	}
+	slotutil.AnchorClock(time.Unix(int64(v.genesisTime), 0))
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
 
 	return nil
@@ -248,8 +256,9 @@ func (v *validator) checkAndLogValidatorStatus(validatorStatuses []*ethpb.Valida
 		}
 		log := log.WithFields(fields)
 		if v.emitAccountMetrics {
-			fmtKey := fmt.Sprintf("%#x", status.PublicKey)
-			validatorStatusesGaugeVec.WithLabelValues(fmtKey).Set(float64(status.Status.Status))
+			if fmtKey := v.accountMetricsLabel(status.PublicKey); fmtKey != otherAccountsLabel {
+				validatorStatusesGaugeVec.WithLabelValues(fmtKey).Set(float64(status.Status.Status))
+			}
 		}
 		switch status.Status.Status {
 		case ethpb.ValidatorStatus_UNKNOWN_STATUS:
@@ -315,7 +324,10 @@ func (v *validator) SlotDeadline(slot uint64) time.Time {
 // list of upcoming assignments needs to be updated. For example, at the
 // beginning of a new epoch.
 func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
-	if slot%params.BeaconConfig().SlotsPerEpoch != 0 && v.duties != nil {
+	v.dutiesLock.RLock()
+	haveDuties := v.duties != nil
+	v.dutiesLock.RUnlock()
+	if slot%params.BeaconConfig().SlotsPerEpoch != 0 && haveDuties {
 		// Do nothing if not epoch start AND assignments already exist.
 		return nil
 	}
@@ -337,41 +349,17 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 	// If duties is nil it means we have had no prior duties and just started up.
 	resp, err := v.validatorClient.GetDuties(ctx, req)
 	if err != nil {
+		v.dutiesLock.Lock()
 		v.duties = nil // Clear assignments so we know to retry the request.
+		v.dutiesLock.Unlock()
 		log.Error(err)
 		return err
 	}
 
+	v.dutiesLock.Lock()
 	v.duties = resp
-	v.logDuties(slot, v.duties.Duties)
-	subscribeSlots := make([]uint64, 0, len(validatingKeys))
-	subscribeCommitteeIDs := make([]uint64, 0, len(validatingKeys))
-	subscribeIsAggregator := make([]bool, 0, len(validatingKeys))
-	alreadySubscribed := make(map[[64]byte]bool)
-
-	for _, duty := range v.duties.Duties {
-		if duty.Status == ethpb.ValidatorStatus_ACTIVE || duty.Status == ethpb.ValidatorStatus_EXITING {
-			attesterSlot := duty.AttesterSlot
-			committeeIndex := duty.CommitteeIndex
-
-			alreadySubscribedKey := validatorSubscribeKey(attesterSlot, committeeIndex)
-			if _, ok := alreadySubscribed[alreadySubscribedKey]; ok {
-				continue
-			}
-
-			aggregator, err := v.isAggregator(ctx, duty.Committee, attesterSlot, bytesutil.ToBytes48(duty.PublicKey))
-			if err != nil {
-				return errors.Wrap(err, "could not check if a validator is an aggregator")
-			}
-			if aggregator {
-				alreadySubscribed[alreadySubscribedKey] = true
-			}
-
-			subscribeSlots = append(subscribeSlots, attesterSlot)
-			subscribeCommitteeIDs = append(subscribeCommitteeIDs, committeeIndex)
-			subscribeIsAggregator = append(subscribeIsAggregator, aggregator)
-		}
-	}
+	v.dutiesLock.Unlock()
+	v.logDuties(slot, resp.Duties)
 
 	// Notify beacon node to subscribe to the attester and aggregator subnets for the next epoch.
 	req.Epoch++
@@ -380,8 +368,69 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 		log.Error(err)
 		return err
 	}
-	for _, duty := range dutiesNextEpoch.Duties {
-		if duty.Status == ethpb.ValidatorStatus_ACTIVE || duty.Status == ethpb.ValidatorStatus_EXITING {
+	return v.subscribeToSubnets(ctx, resp.Duties, dutiesNextEpoch.Duties)
+}
+
+// StreamDuties receives the duties of the validating keys from a server-side stream of the beacon
+// node, which sends new duties at every epoch start and after reorgs that change the duties. It
+// replaces polling for the duties in UpdateDuties, and returns when the stream ends or fails.
+func (v *validator) StreamDuties(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "validator.StreamDuties")
+	defer span.End()
+
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return err
+	}
+	req := &ethpb.DutiesRequest{
+		PublicKeys: bytesutil.FromBytes48Array(validatingKeys),
+	}
+	stream, err := v.validatorClient.StreamDuties(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "could not setup validator duties streaming client")
+	}
+	for {
+		resp, err := stream.Recv()
+		// If the stream is closed, we stop the loop.
+		if err == io.EOF {
+			return nil
+		}
+		// If context is canceled we stop the loop.
+		if ctx.Err() == context.Canceled {
+			return errors.Wrap(ctx.Err(), "context has been canceled so shutting down the loop")
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not receive duties from stream")
+		}
+
+		v.dutiesLock.Lock()
+		v.duties = resp
+		v.dutiesLock.Unlock()
+		slot := slotutil.SlotsSinceGenesis(time.Unix(int64(v.genesisTime), 0))
+		v.logDuties(slot, resp.CurrentEpochDuties)
+		if err := v.subscribeToSubnets(ctx, resp.CurrentEpochDuties, resp.NextEpochDuties); err != nil {
+			log.WithError(err).Error("Could not subscribe to committee subnets")
+		}
+	}
+}
+
+// subscribeToSubnets notifies the beacon node to subscribe to the attester and aggregator subnets
+// of the active validators' duties.
+func (v *validator) subscribeToSubnets(ctx context.Context, duties ...[]*ethpb.DutiesResponse_Duty) error {
+	var count int
+	for _, d := range duties {
+		count += len(d)
+	}
+	subscribeSlots := make([]uint64, 0, count)
+	subscribeCommitteeIDs := make([]uint64, 0, count)
+	subscribeIsAggregator := make([]bool, 0, count)
+	alreadySubscribed := make(map[[64]byte]bool)
+
+	for _, epochDuties := range duties {
+		for _, duty := range epochDuties {
+			if duty.Status != ethpb.ValidatorStatus_ACTIVE && duty.Status != ethpb.ValidatorStatus_EXITING {
+				continue
+			}
 			attesterSlot := duty.AttesterSlot
 			committeeIndex := duty.CommitteeIndex
 
@@ -404,12 +453,11 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 		}
 	}
 
-	_, err = v.validatorClient.SubscribeCommitteeSubnets(ctx, &ethpb.CommitteeSubnetsSubscribeRequest{
+	_, err := v.validatorClient.SubscribeCommitteeSubnets(ctx, &ethpb.CommitteeSubnetsSubscribeRequest{
 		Slots:        subscribeSlots,
 		CommitteeIds: subscribeCommitteeIDs,
 		IsAggregator: subscribeIsAggregator,
 	})
-
 	return err
 }
 
@@ -417,6 +465,11 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 // validator is known to not have a roles at the at slot. Returns UNKNOWN if the
 // validator assignments are unknown. Otherwise returns a valid validatorRole map.
 func (v *validator) RolesAt(ctx context.Context, slot uint64) (map[[48]byte][]validatorRole, error) {
+	v.dutiesLock.RLock()
+	defer v.dutiesLock.RUnlock()
+	if v.duties == nil {
+		return nil, errors.New("no duties for validators")
+	}
 	rolesAt := make(map[[48]byte][]validatorRole)
 	for _, duty := range v.duties.Duties {
 		var roles []validatorRole
@@ -458,6 +511,7 @@ func (v *validator) RolesAt(ctx context.Context, slot uint64) (map[[48]byte][]va
 // UpdateProtections goes through the duties of the given slot and fetches the required validator history,
 // assigning it in validator.
 func (v *validator) UpdateProtections(ctx context.Context, slot uint64) error {
+	v.dutiesLock.RLock()
 	attestingPubKeys := make([][48]byte, 0, len(v.duties.Duties))
 	for _, duty := range v.duties.Duties {
 		if duty == nil {
@@ -467,6 +521,7 @@ func (v *validator) UpdateProtections(ctx context.Context, slot uint64) error {
 			attestingPubKeys = append(attestingPubKeys, bytesutil.ToBytes48(duty.PublicKey))
 		}
 	}
+	v.dutiesLock.RUnlock()
 	attHistoryByPubKey, err := v.db.AttestationHistoryForPubKeys(ctx, attestingPubKeys)
 	if err != nil {
 		return errors.Wrap(err, "could not get attester history")
@@ -487,19 +542,11 @@ func (v *validator) SaveProtections(ctx context.Context) error {
 // isAggregator checks if a validator is an aggregator of a given slot, it uses the selection algorithm outlined in:
 // https://github.com/ethereum/eth2.0-specs/blob/v0.9.3/specs/validator/0_beacon-chain-validator.md#aggregation-selection
 func (v *validator) isAggregator(ctx context.Context, committee []uint64, slot uint64, pubKey [48]byte) (bool, error) {
-	modulo := uint64(1)
-	if len(committee)/int(params.BeaconConfig().TargetAggregatorsPerCommittee) > 1 {
-		modulo = uint64(len(committee)) / params.BeaconConfig().TargetAggregatorsPerCommittee
-	}
-
 	slotSig, err := v.signSlot(ctx, pubKey, slot)
 	if err != nil {
 		return false, err
 	}
-
-	b := hashutil.Hash(slotSig)
-
-	return binary.LittleEndian.Uint64(b[:8])%modulo == 0, nil
+	return helpers.IsAggregator(uint64(len(committee)), slotSig)
 }
 
 // UpdateDomainDataCaches by making calls for all of the possible domain data. These can change when
@@ -564,8 +611,9 @@ func (v *validator) logDuties(slot uint64, duties []*ethpb.DutiesResponse_Duty)
 
 	for _, duty := range duties {
 		if v.emitAccountMetrics {
-			fmtKey := fmt.Sprintf("%#x", duty.PublicKey)
-			validatorStatusesGaugeVec.WithLabelValues(fmtKey).Set(float64(duty.Status))
+			if fmtKey := v.accountMetricsLabel(duty.PublicKey); fmtKey != otherAccountsLabel {
+				validatorStatusesGaugeVec.WithLabelValues(fmtKey).Set(float64(duty.Status))
+			}
 		}
 
 		// Only interested in validators who are attesting/proposing.
@@ -11,8 +11,8 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"go.opencensus.io/trace"
 )
 
@@ -48,7 +48,7 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 	defer span.End()
 
 	span.AddAttributes(trace.StringAttribute("validator", fmt.Sprintf("%#x", pubKey)))
-	fmtKey := fmt.Sprintf("%#x", pubKey[:])
+	fmtKey := v.accountMetricsLabel(pubKey[:])
 
 	duty, err := v.duty(pubKey)
 	if err != nil {
@@ -99,6 +99,10 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 	sig, err := v.aggregateAndProofSig(ctx, pubKey, res.AggregateAndProof)
 	if err != nil {
 		log.Errorf("Could not sign aggregate and proof: %v", err)
+		if v.emitAccountMetrics {
+			validatorAggFailVec.WithLabelValues(fmtKey).Inc()
+		}
+		return
 	}
 	_, err = v.validatorClient.SubmitSignedAggregateSelectionProof(ctx, &ethpb.SignedAggregateSubmitRequest{
 		SignedAggregateAndProof: &ethpb.SignedAggregateAttestationAndProof{
@@ -135,7 +139,7 @@ func (v *validator) signSlot(ctx context.Context, pubKey [48]byte, slot uint64)
 		return nil, err
 	}
 
-	sig, err := v.signObject(pubKey, slot, domain.SignatureDomain)
+	sig, err := keymanager.SignObject(v.keyManager, pubKey, keymanager.SignTypeAggregationSlot, slot, domain.SignatureDomain)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to sign slot")
 	}
@@ -156,7 +160,7 @@ func (v *validator) waitToSlotTwoThirds(ctx context.Context, slot uint64) {
 
 	startTime := slotutil.SlotStartTime(v.genesisTime, slot)
 	finalTime := startTime.Add(delay)
-	time.Sleep(roughtime.Until(finalTime))
+	<-slotutil.After(slotutil.Until(finalTime))
 }
 
 // This returns the signature of validator signing over aggregate and
@@ -166,7 +170,7 @@ func (v *validator) aggregateAndProofSig(ctx context.Context, pubKey [48]byte, a
 	if err != nil {
 		return nil, err
 	}
-	sig, err := v.signObject(pubKey, agg, d.SignatureDomain)
+	sig, err := keymanager.SignObject(v.keyManager, pubKey, keymanager.SignTypeAggregateAndProof, agg, d.SignatureDomain)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -65,6 +66,44 @@ func TestSubmitAggregateAndProof_Ok(t *testing.T) {
 	validator.SubmitAggregateAndProof(context.Background(), 0, validatorPubKey)
 }
 
+func TestSubmitAggregateAndProof_SignFailure(t *testing.T) {
+	hook := logTest.NewGlobal()
+	validator, m, finish := setup(t)
+	defer finish()
+	validator.duties = &ethpb.DutiesResponse{
+		Duties: []*ethpb.DutiesResponse_Duty{
+			{
+				PublicKey: validatorKey.PublicKey.Marshal(),
+			},
+		},
+	}
+
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), // epoch
+	).Return(&ethpb.DomainResponse{}, nil /*err*/)
+
+	m.validatorClient.EXPECT().SubmitAggregateSelectionProof(
+		gomock.Any(), // ctx
+		gomock.AssignableToTypeOf(&ethpb.AggregateSelectionRequest{}),
+	).Return(&ethpb.AggregateSelectionResponse{
+		AggregateAndProof: &ethpb.AggregateAttestationAndProof{
+			AggregatorIndex: 0,
+			Aggregate:       &ethpb.Attestation{Data: &ethpb.AttestationData{}},
+			SelectionProof:  nil,
+		},
+	}, nil)
+
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), // epoch
+	).Return(nil /*response*/, errors.New("bad domain root"))
+
+	// The unsigned aggregate and proof must not be submitted.
+	validator.SubmitAggregateAndProof(context.Background(), 0, validatorPubKey)
+	testutil.AssertLogsContain(t, hook, "Could not sign aggregate and proof")
+}
+
 func TestWaitForSlotTwoThird_WaitCorrectly(t *testing.T) {
 	validator, _, finish := setup(t)
 	defer finish()
@@ -18,7 +18,6 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
-	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/sirupsen/logrus"
@@ -67,7 +66,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	defer span.End()
 	span.AddAttributes(trace.StringAttribute("validator", fmt.Sprintf("%#x", pubKey)))
 
-	fmtKey := fmt.Sprintf("%#x", pubKey[:])
+	fmtKey := v.accountMetricsLabel(pubKey[:])
 	log := log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:]))).WithField("slot", slot)
 	duty, err := v.duty(pubKey)
 	if err != nil {
@@ -81,10 +80,6 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 		log.Debug("Empty committee for validator duty, not attesting")
 		return
 	}
-	v.attesterHistoryByPubKeyLock.RLock()
-	attesterHistory := v.attesterHistoryByPubKey[pubKey]
-	v.attesterHistoryByPubKeyLock.RUnlock()
-
 	v.waitToSlotOneThird(ctx, slot)
 
 	req := &ethpb.AttestationDataRequest{
@@ -101,7 +96,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	}
 
 	if featureconfig.Get().ProtectAttester {
-		if isNewAttSlashable(attesterHistory, data.Source.Epoch, data.Target.Epoch) {
+		if v.isSlashableAttestation(pubKey, data.Source.Epoch, data.Target.Epoch) {
 			log.WithFields(logrus.Fields{
 				"sourceEpoch": data.Source.Epoch,
 				"targetEpoch": data.Target.Epoch,
@@ -182,10 +177,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 	}
 
 	if featureconfig.Get().ProtectAttester {
-		attesterHistory = markAttestationForTargetEpoch(attesterHistory, data.Source.Epoch, data.Target.Epoch)
-		v.attesterHistoryByPubKeyLock.Lock()
-		v.attesterHistoryByPubKey[pubKey] = attesterHistory
-		v.attesterHistoryByPubKeyLock.Unlock()
+		v.markAttestation(pubKey, data.Source.Epoch, data.Target.Epoch)
 	}
 
 	if v.emitAccountMetrics {
@@ -205,6 +197,8 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 
 // Given the validator public key, this gets the validator assignment.
 func (v *validator) duty(pubKey [48]byte) (*ethpb.DutiesResponse_Duty, error) {
+	v.dutiesLock.RLock()
+	defer v.dutiesLock.RUnlock()
 	if v.duties == nil {
 		return nil, errors.New("no duties for validators")
 	}
@@ -262,6 +256,22 @@ func (v *validator) saveAttesterIndexToData(data *ethpb.AttestationData, index u
 	return nil
 }
 
+// isSlashableAttestation returns whether an attestation of the source and target epochs is slashable
+// given the attestation history of the validator, loaded by UpdateProtections.
+func (v *validator) isSlashableAttestation(pubKey [48]byte, sourceEpoch uint64, targetEpoch uint64) bool {
+	v.attesterHistoryByPubKeyLock.RLock()
+	defer v.attesterHistoryByPubKeyLock.RUnlock()
+	return isNewAttSlashable(v.attesterHistoryByPubKey[pubKey], sourceEpoch, targetEpoch)
+}
+
+// markAttestation records an attestation of the source and target epochs in the attestation history
+// of the validator, which is saved by SaveProtections.
+func (v *validator) markAttestation(pubKey [48]byte, sourceEpoch uint64, targetEpoch uint64) {
+	v.attesterHistoryByPubKeyLock.Lock()
+	defer v.attesterHistoryByPubKeyLock.Unlock()
+	v.attesterHistoryByPubKey[pubKey] = markAttestationForTargetEpoch(v.attesterHistoryByPubKey[pubKey], sourceEpoch, targetEpoch)
+}
+
 // isNewAttSlashable uses the attestation history to determine if an attestation of sourceEpoch
 // and targetEpoch would be slashable. It can detect double, surrounding, and surrounded votes.
 func isNewAttSlashable(history *slashpb.AttestationHistory, sourceEpoch uint64, targetEpoch uint64) bool {
@@ -336,5 +346,5 @@ func (v *validator) waitToSlotOneThird(ctx context.Context, slot uint64) {
 	delay := slotutil.DivideSlotBy(3 /* a third of the slot duration */)
 	startTime := slotutil.SlotStartTime(v.genesisTime, slot)
 	finalTime := startTime.Add(delay)
-	time.Sleep(roughtime.Until(finalTime))
+	<-slotutil.After(slotutil.Until(finalTime))
 }
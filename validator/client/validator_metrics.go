@@ -12,18 +12,91 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-var validatorBalancesGaugeVec = promauto.NewGaugeVec(
-	prometheus.GaugeOpts{
-		Namespace: "validator",
-		Name:      "balance",
-		Help:      "current validator balance.",
-	},
-	[]string{
-		// validator pubkey
-		"pubkey",
-	},
+// otherAccountsLabel is the pubkey label of the per account counters of the validating keys
+// beyond the account metrics key cap. The per account gauges are not emitted for these keys.
+const otherAccountsLabel = "other"
+
+var (
+	validatorBalancesGaugeVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "balance",
+			Help:      "current validator balance.",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+		},
+	)
+	validatorInclusionSuccessVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "included_attestations",
+			Help:      "Count the epochs in which an attestation of the validator was included on chain.",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+		},
+	)
+	validatorInclusionFailVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "missed_attestations",
+			Help:      "Count the epochs in which no attestation of the validator was included on chain.",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+		},
+	)
+	validatorNextAttestationGaugeVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "next_attestation_seconds",
+			Help:      "Seconds until the start of the slot of the next known attestation duty.",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+		},
+	)
+	validatorNextProposalGaugeVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "next_proposal_seconds",
+			Help:      "Seconds until the start of the slot of the next known proposal duty.",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+		},
+	)
 )
 
+// accountMetricsLabel returns the pubkey label of the per account metrics of a validating key.
+// Only the first keys seen, up to the account metrics key cap, get their own label so operators
+// with many keys can bound the cardinality of the metrics. The other keys share the
+// otherAccountsLabel, which callers skip for gauges.
+func (v *validator) accountMetricsLabel(pubKey []byte) string {
+	if v.accountMetricsMaxKeys == 0 {
+		return fmt.Sprintf("%#x", pubKey)
+	}
+	key := bytesutil.ToBytes48(pubKey)
+	v.accountMetricsKeysLock.Lock()
+	defer v.accountMetricsKeysLock.Unlock()
+	if v.accountMetricsKeys == nil {
+		v.accountMetricsKeys = make(map[[48]byte]bool)
+	}
+	if !v.accountMetricsKeys[key] {
+		if uint64(len(v.accountMetricsKeys)) >= v.accountMetricsMaxKeys {
+			return otherAccountsLabel
+		}
+		v.accountMetricsKeys[key] = true
+	}
+	return fmt.Sprintf("%#x", pubKey)
+}
+
 // LogValidatorGainsAndLosses logs important metrics related to this validator client's
 // responsibilities throughout the beacon chain's lifecycle. It logs absolute accrued rewards
 // and penalties over time, percentage gain/loss, and gives the end user a better idea
@@ -33,7 +106,7 @@ func (v *validator) LogValidatorGainsAndLosses(ctx context.Context, slot uint64)
 		// Do nothing unless we are at the start of the epoch, and not in the first epoch.
 		return nil
 	}
-	if !v.logValidatorBalances {
+	if !v.logValidatorBalances && !v.emitAccountMetrics {
 		return nil
 	}
 
@@ -53,8 +126,9 @@ func (v *validator) LogValidatorGainsAndLosses(ctx context.Context, slot uint64)
 
 	if v.emitAccountMetrics {
 		for _, missingPubKey := range resp.MissingValidators {
-			fmtKey := fmt.Sprintf("%#x", missingPubKey[:])
-			validatorBalancesGaugeVec.WithLabelValues(fmtKey).Set(0)
+			if fmtKey := v.accountMetricsLabel(missingPubKey); fmtKey != otherAccountsLabel {
+				validatorBalancesGaugeVec.WithLabelValues(fmtKey).Set(0)
+			}
 		}
 	}
 
@@ -78,25 +152,34 @@ func (v *validator) LogValidatorGainsAndLosses(ctx context.Context, slot uint64)
 			newBalance := float64(resp.BalancesAfterEpochTransition[i]) / gweiPerEth
 			prevBalance := float64(resp.BalancesBeforeEpochTransition[i]) / gweiPerEth
 			percentNet := (newBalance - prevBalance) / prevBalance
-			log.WithFields(logrus.Fields{
-				"pubKey":               truncatedKey,
-				"epoch":                prevEpoch,
-				"correctlyVotedSource": resp.CorrectlyVotedSource[i],
-				"correctlyVotedTarget": resp.CorrectlyVotedTarget[i],
-				"correctlyVotedHead":   resp.CorrectlyVotedHead[i],
-				"inclusionSlot":        resp.InclusionSlots[i],
-				"inclusionDistance":    resp.InclusionDistances[i],
-				"oldBalance":           prevBalance,
-				"newBalance":           newBalance,
-				"percentChange":        fmt.Sprintf("%.5f%%", percentNet*100),
-			}).Info("Previous epoch voting summary")
+			if v.logValidatorBalances {
+				log.WithFields(logrus.Fields{
+					"pubKey":               truncatedKey,
+					"epoch":                prevEpoch,
+					"correctlyVotedSource": resp.CorrectlyVotedSource[i],
+					"correctlyVotedTarget": resp.CorrectlyVotedTarget[i],
+					"correctlyVotedHead":   resp.CorrectlyVotedHead[i],
+					"inclusionSlot":        resp.InclusionSlots[i],
+					"inclusionDistance":    resp.InclusionDistances[i],
+					"oldBalance":           prevBalance,
+					"newBalance":           newBalance,
+					"percentChange":        fmt.Sprintf("%.5f%%", percentNet*100),
+				}).Info("Previous epoch voting summary")
+			}
 			if v.emitAccountMetrics {
-				validatorBalancesGaugeVec.WithLabelValues(truncatedKey).Set(newBalance)
+				if fmtKey := v.accountMetricsLabel(pubKey); fmtKey != otherAccountsLabel {
+					validatorBalancesGaugeVec.WithLabelValues(fmtKey).Set(newBalance)
+				}
 			}
 		}
 
 		if resp.InclusionSlots[i] != ^uint64(0) {
 			included++
+			if v.emitAccountMetrics {
+				validatorInclusionSuccessVec.WithLabelValues(v.accountMetricsLabel(pubKey)).Inc()
+			}
+		} else if v.emitAccountMetrics {
+			validatorInclusionFailVec.WithLabelValues(v.accountMetricsLabel(pubKey)).Inc()
 		}
 		if resp.CorrectlyVotedSource[i] {
 			votedSource++
@@ -110,6 +193,9 @@ func (v *validator) LogValidatorGainsAndLosses(ctx context.Context, slot uint64)
 		v.prevBalance[pubKeyBytes] = resp.BalancesBeforeEpochTransition[i]
 	}
 
+	if !v.logValidatorBalances {
+		return nil
+	}
 	log.WithFields(logrus.Fields{
 		"epoch":                          prevEpoch,
 		"attestationInclusionPercentage": fmt.Sprintf("%.0f%%", (float64(included)/float64(len(resp.InclusionSlots)))*100),
@@ -120,3 +206,62 @@ func (v *validator) LogValidatorGainsAndLosses(ctx context.Context, slot uint64)
 
 	return nil
 }
+
+// UpdateDutyCountdowns sets the countdowns to the next known attestation and proposal duties of
+// each validating key, as of the start of the given slot. The countdowns of keys without a known
+// upcoming duty are removed.
+func (v *validator) UpdateDutyCountdowns(slot uint64) {
+	if !v.emitAccountMetrics {
+		return
+	}
+	v.dutiesLock.RLock()
+	defer v.dutiesLock.RUnlock()
+	if v.duties == nil {
+		return
+	}
+
+	nextAttestation := make(map[string]uint64)
+	nextProposal := make(map[string]uint64)
+	for _, duties := range [][]*ethpb.DutiesResponse_Duty{v.duties.Duties, v.duties.CurrentEpochDuties, v.duties.NextEpochDuties} {
+		for _, duty := range duties {
+			if duty == nil {
+				continue
+			}
+			fmtKey := v.accountMetricsLabel(duty.PublicKey)
+			if fmtKey == otherAccountsLabel {
+				continue
+			}
+			if _, ok := nextAttestation[fmtKey]; !ok {
+				nextAttestation[fmtKey] = ^uint64(0)
+				nextProposal[fmtKey] = ^uint64(0)
+			}
+			if duty.Status != ethpb.ValidatorStatus_ACTIVE && duty.Status != ethpb.ValidatorStatus_EXITING {
+				continue
+			}
+			if duty.AttesterSlot >= slot && duty.AttesterSlot < nextAttestation[fmtKey] {
+				nextAttestation[fmtKey] = duty.AttesterSlot
+			}
+			for _, proposerSlot := range duty.ProposerSlots {
+				if proposerSlot >= slot && proposerSlot < nextProposal[fmtKey] {
+					nextProposal[fmtKey] = proposerSlot
+				}
+			}
+		}
+	}
+
+	secondsPerSlot := params.BeaconConfig().SecondsPerSlot
+	for fmtKey, dutySlot := range nextAttestation {
+		if dutySlot == ^uint64(0) {
+			validatorNextAttestationGaugeVec.DeleteLabelValues(fmtKey)
+			continue
+		}
+		validatorNextAttestationGaugeVec.WithLabelValues(fmtKey).Set(float64((dutySlot - slot) * secondsPerSlot))
+	}
+	for fmtKey, dutySlot := range nextProposal {
+		if dutySlot == ^uint64(0) {
+			validatorNextProposalGaugeVec.DeleteLabelValues(fmtKey)
+			continue
+		}
+		validatorNextProposalGaugeVec.WithLabelValues(fmtKey).Set(float64((dutySlot - slot) * secondsPerSlot))
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestAccountMetricsLabel_Cap(t *testing.T) {
+	v := &validator{accountMetricsMaxKeys: 2}
+	keys := [][]byte{{'a'}, {'b'}, {'c'}}
+
+	for _, key := range keys[:2] {
+		if got, want := v.accountMetricsLabel(key), fmt.Sprintf("%#x", key); got != want {
+			t.Errorf("Wanted label %s, received %s", want, got)
+		}
+	}
+	if got := v.accountMetricsLabel(keys[2]); got != otherAccountsLabel {
+		t.Errorf("Wanted the key beyond the cap to be labeled %s, received %s", otherAccountsLabel, got)
+	}
+	// Keys which got their own label keep it.
+	if got, want := v.accountMetricsLabel(keys[0]), fmt.Sprintf("%#x", keys[0]); got != want {
+		t.Errorf("Wanted label %s, received %s", want, got)
+	}
+
+	v = &validator{}
+	for _, key := range keys {
+		if got, want := v.accountMetricsLabel(key), fmt.Sprintf("%#x", key); got != want {
+			t.Errorf("Wanted label %s without a cap, received %s", want, got)
+		}
+	}
+}
+
+func TestUpdateDutyCountdowns(t *testing.T) {
+	attester := []byte("attester")
+	proposer := []byte("proposer")
+	exited := []byte("exited")
+	v := &validator{
+		emitAccountMetrics: true,
+		duties: &ethpb.DutiesResponse{
+			CurrentEpochDuties: []*ethpb.DutiesResponse_Duty{
+				{PublicKey: attester, AttesterSlot: 3, Status: ethpb.ValidatorStatus_ACTIVE},
+				{PublicKey: proposer, AttesterSlot: 1, ProposerSlots: []uint64{2, 6}, Status: ethpb.ValidatorStatus_ACTIVE},
+				{PublicKey: exited, AttesterSlot: 5, Status: ethpb.ValidatorStatus_EXITED},
+			},
+			NextEpochDuties: []*ethpb.DutiesResponse_Duty{
+				{PublicKey: proposer, AttesterSlot: 40, Status: ethpb.ValidatorStatus_ACTIVE},
+			},
+		},
+	}
+	v.UpdateDutyCountdowns(2)
+
+	secondsPerSlot := float64(params.BeaconConfig().SecondsPerSlot)
+	if got := promtestutil.ToFloat64(validatorNextAttestationGaugeVec.WithLabelValues(fmt.Sprintf("%#x", attester))); got != secondsPerSlot {
+		t.Errorf("Wanted the next attestation in %v seconds, received %v", secondsPerSlot, got)
+	}
+	// The attestation of the current epoch passed, the next one is in the next epoch.
+	if got := promtestutil.ToFloat64(validatorNextAttestationGaugeVec.WithLabelValues(fmt.Sprintf("%#x", proposer))); got != 38*secondsPerSlot {
+		t.Errorf("Wanted the next attestation in %v seconds, received %v", 38*secondsPerSlot, got)
+	}
+	if got := promtestutil.ToFloat64(validatorNextProposalGaugeVec.WithLabelValues(fmt.Sprintf("%#x", proposer))); got != 0 {
+		t.Errorf("Wanted the proposal of the current slot, received %v seconds", got)
+	}
+	// Keys without upcoming duties have no countdown.
+	if validatorNextAttestationGaugeVec.DeleteLabelValues(fmt.Sprintf("%#x", exited)) {
+		t.Error("Wanted no attestation countdown of an exited validator")
+	}
+	if validatorNextProposalGaugeVec.DeleteLabelValues(fmt.Sprintf("%#x", attester)) {
+		t.Error("Wanted no proposal countdown of a validator without proposals")
+	}
+}
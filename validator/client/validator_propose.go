@@ -67,7 +67,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	}
 	ctx, span := trace.StartSpan(ctx, "validator.ProposeBlock")
 	defer span.End()
-	fmtKey := fmt.Sprintf("%#x", pubKey[:])
+	fmtKey := v.accountMetricsLabel(pubKey[:])
 
 	span.AddAttributes(trace.StringAttribute("validator", fmt.Sprintf("%#x", pubKey)))
 	log := log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:])))
@@ -84,10 +84,13 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	}
 
 	// Request block from beacon node
+	v.graffitiLock.RLock()
+	graffiti := v.graffiti.forKey(pubKey)
+	v.graffitiLock.RUnlock()
 	b, err := v.validatorClient.GetBlock(ctx, &ethpb.BlockRequest{
 		Slot:         slot,
 		RandaoReveal: randaoReveal,
-		Graffiti:     v.graffiti,
+		Graffiti:     graffiti,
 	})
 	if err != nil {
 		log.WithField("blockSlot", slot).WithError(err).Error("Failed to request block from beacon node")
@@ -99,7 +102,8 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 
 	var slotBits bitfield.Bitlist
 	if featureconfig.Get().ProtectProposer {
-		slotBits, err = v.db.ProposalHistoryForEpoch(ctx, pubKey[:], epoch)
+		var proposed bool
+		slotBits, proposed, err = v.proposalHistory(ctx, pubKey, slot)
 		if err != nil {
 			log.WithError(err).Error("Failed to get proposal history")
 			if v.emitAccountMetrics {
@@ -109,7 +113,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 		}
 
 		// If the bit for the current slot is marked, do not propose.
-		if slotBits.BitAt(slot % params.BeaconConfig().SlotsPerEpoch) {
+		if proposed {
 			log.WithField("epoch", epoch).Error("Tried to sign a double proposal, rejected")
 			if v.emitAccountMetrics {
 				validatorProposeFailVec.WithLabelValues(fmtKey).Inc()
@@ -157,8 +161,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	}
 
 	if featureconfig.Get().ProtectProposer {
-		slotBits.SetBitAt(slot%params.BeaconConfig().SlotsPerEpoch, true)
-		if err := v.db.SaveProposalHistoryForEpoch(ctx, pubKey[:], epoch, slotBits); err != nil {
+		if err := v.saveProposal(ctx, pubKey, slot, slotBits); err != nil {
 			log.WithError(err).Error("Failed to save updated proposal history")
 			if v.emitAccountMetrics {
 				validatorProposeFailVec.WithLabelValues(fmtKey).Inc()
@@ -186,6 +189,24 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	}).Info("Submitted new block")
 }
 
+// proposalHistory returns the proposal history of the validator for the epoch of the slot, and
+// whether the validator already proposed a block at the slot.
+func (v *validator) proposalHistory(ctx context.Context, pubKey [48]byte, slot uint64) (bitfield.Bitlist, bool, error) {
+	epoch := slot / params.BeaconConfig().SlotsPerEpoch
+	slotBits, err := v.db.ProposalHistoryForEpoch(ctx, pubKey[:], epoch)
+	if err != nil {
+		return nil, false, err
+	}
+	return slotBits, slotBits.BitAt(slot % params.BeaconConfig().SlotsPerEpoch), nil
+}
+
+// saveProposal marks the slot in the proposal history of the validator returned by proposalHistory.
+func (v *validator) saveProposal(ctx context.Context, pubKey [48]byte, slot uint64, slotBits bitfield.Bitlist) error {
+	epoch := slot / params.BeaconConfig().SlotsPerEpoch
+	slotBits.SetBitAt(slot%params.BeaconConfig().SlotsPerEpoch, true)
+	return v.db.SaveProposalHistoryForEpoch(ctx, pubKey[:], epoch, slotBits)
+}
+
 // ProposeExit --
 func (v *validator) ProposeExit(ctx context.Context, exit *ethpb.VoluntaryExit) error {
 	return errors.New("unimplemented")
@@ -198,7 +219,7 @@ func (v *validator) signRandaoReveal(ctx context.Context, pubKey [48]byte, epoch
 		return nil, errors.Wrap(err, "could not get domain data")
 	}
 
-	randaoReveal, err := v.signObject(pubKey, epoch, domain.SignatureDomain)
+	randaoReveal, err := keymanager.SignObject(v.keyManager, pubKey, keymanager.SignTypeRandaoReveal, epoch, domain.SignatureDomain)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not sign reveal")
 	}
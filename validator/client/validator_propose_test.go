@@ -44,7 +44,6 @@ func setup(t *testing.T) (*validator, *mocks, func()) {
 		db:                             valDB,
 		validatorClient:                m.validatorClient,
 		keyManager:                     testKeyManager,
-		graffiti:                       []byte{},
 		attLogs:                        make(map[[32]byte]*attSubmitted),
 		aggregatedSlotCommitteeIDCache: aggregatedSlotCommitteeIDCache,
 		attesterHistoryByPubKey:        attHistoryByPubKey,
@@ -309,7 +308,12 @@ func TestProposeBlock_BroadcastsBlock_WithGraffiti(t *testing.T) {
 	validator, m, finish := setup(t)
 	defer finish()
 
-	validator.graffiti = []byte("12345678901234567890123456789012")
+	graffiti := "12345678901234567890123456789012"
+	var err error
+	validator.graffiti, err = ParseGraffiti(graffiti)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	m.validatorClient.EXPECT().DomainData(
 		gomock.Any(), // ctx
@@ -319,7 +323,9 @@ func TestProposeBlock_BroadcastsBlock_WithGraffiti(t *testing.T) {
 	m.validatorClient.EXPECT().GetBlock(
 		gomock.Any(), // ctx
 		gomock.Any(),
-	).Return(&ethpb.BeaconBlock{Body: &ethpb.BeaconBlockBody{Graffiti: validator.graffiti}}, nil /*err*/)
+	).DoAndReturn(func(ctx context.Context, req *ethpb.BlockRequest) (*ethpb.BeaconBlock, error) {
+		return &ethpb.BeaconBlock{Body: &ethpb.BeaconBlockBody{Graffiti: req.Graffiti}}, nil
+	})
 
 	m.validatorClient.EXPECT().DomainData(
 		gomock.Any(), // ctx
@@ -338,7 +344,7 @@ func TestProposeBlock_BroadcastsBlock_WithGraffiti(t *testing.T) {
 
 	validator.ProposeBlock(context.Background(), 1, validatorPubKey)
 
-	if string(sentBlock.Block.Body.Graffiti) != string(validator.graffiti) {
-		t.Errorf("Block was broadcast with the wrong graffiti field, wanted \"%v\", got \"%v\"", string(validator.graffiti), string(sentBlock.Block.Body.Graffiti))
+	if string(sentBlock.Block.Body.Graffiti) != graffiti {
+		t.Errorf("Block was broadcast with the wrong graffiti field, wanted \"%v\", got \"%v\"", graffiti, string(sentBlock.Block.Body.Graffiti))
 	}
 }
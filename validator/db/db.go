@@ -88,7 +88,7 @@ func NewKVStore(dirPath string, pubKeys [][48]byte) (*Store, error) {
 	}
 
 	// Initialize the required public keys into the DB to ensure they're not empty.
-	if err := kv.initializeSubBuckets(pubKeys); err != nil {
+	if err := kv.UpdatePublicKeysBuckets(pubKeys); err != nil {
 		return nil, err
 	}
 
@@ -112,6 +112,31 @@ func GetKVStore(directory string) (*Store, error) {
 	return &Store{db: boltDb, databasePath: directory}, nil
 }
 
+// PublicKeys returns the validator public keys with a proposal or attestation history in the db.
+func (db *Store) PublicKeys() ([][48]byte, error) {
+	var keys [][]byte
+	err := db.view(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{historicProposalsBucket, historicAttestationsBucket} {
+			if err := tx.Bucket(name).ForEach(func(pubKey, _ []byte) error {
+				keys = append(keys, append([]byte{}, pubKey...))
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys = removeDuplicateKeys(keys)
+	pubKeys := make([][48]byte, len(keys))
+	for i, key := range keys {
+		copy(pubKeys[i][:], key)
+	}
+	return pubKeys, nil
+}
+
 // Size returns the db size in bytes.
 func (db *Store) Size() (int64, error) {
 	var size int64
@@ -61,6 +61,33 @@ func (db *Store) SaveProposalHistoryForEpoch(ctx context.Context, pubKey []byte,
 	return err
 }
 
+// ProposedSlots returns the slots of the blocks in the proposal history of a validator public key,
+// in ascending order.
+func (db *Store) ProposedSlots(ctx context.Context, publicKey []byte) ([]uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.ProposedSlots")
+	defer span.End()
+
+	var slots []uint64
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historicProposalsBucket)
+		valBucket := bucket.Bucket(publicKey)
+		if valBucket == nil {
+			return nil
+		}
+		return valBucket.ForEach(func(k, v []byte) error {
+			epoch := binary.LittleEndian.Uint64(k)
+			slotBits := bitfield.Bitlist(v)
+			for i := uint64(0); i < params.BeaconConfig().SlotsPerEpoch && i < slotBits.Len(); i++ {
+				if slotBits.BitAt(i) {
+					slots = append(slots, epoch*params.BeaconConfig().SlotsPerEpoch+i)
+				}
+			}
+			return nil
+		})
+	})
+	return slots, err
+}
+
 // DeleteProposalHistory deletes the proposal history for the corresponding validator public key.
 func (db *Store) DeleteProposalHistory(ctx context.Context, pubkey []byte) error {
 	ctx, span := trace.StartSpan(ctx, "Validator.DeleteProposalHistory")
@@ -92,7 +119,9 @@ func pruneProposalHistory(valBucket *bolt.Bucket, newestEpoch uint64) error {
 	return nil
 }
 
-func (db *Store) initializeSubBuckets(pubKeys [][48]byte) error {
+// UpdatePublicKeysBuckets initializes the proposal histories of the validator public keys which
+// do not have one yet.
+func (db *Store) UpdatePublicKeysBuckets(pubKeys [][48]byte) error {
 	return db.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(historicProposalsBucket)
 		for _, pubKey := range pubKeys {
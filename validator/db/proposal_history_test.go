@@ -176,6 +176,63 @@ func TestProposalHistoryForEpoch_MultipleEpochs(t *testing.T) {
 	}
 }
 
+func TestProposedSlots_OK(t *testing.T) {
+	pubkey := [48]byte{3}
+	db := SetupDB(t, [][48]byte{pubkey})
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+
+	slotBits := bitfield.NewBitlist(slotsPerEpoch)
+	slotBits.SetBitAt(0, true)
+	if err := db.SaveProposalHistoryForEpoch(context.Background(), pubkey[:], 0, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	slotBits = bitfield.NewBitlist(slotsPerEpoch)
+	slotBits.SetBitAt(1, true)
+	slotBits.SetBitAt(5, true)
+	if err := db.SaveProposalHistoryForEpoch(context.Background(), pubkey[:], 2, slotBits); err != nil {
+		t.Fatal(err)
+	}
+
+	slots, err := db.ProposedSlots(context.Background(), pubkey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint64{0, 2*slotsPerEpoch + 1, 2*slotsPerEpoch + 5}
+	if !reflect.DeepEqual(slots, want) {
+		t.Errorf("Wanted proposed slots %v, received %v", want, slots)
+	}
+
+	slots, err = db.ProposedSlots(context.Background(), []byte{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 0 {
+		t.Errorf("Expected no proposed slots for unknown public key, received %v", slots)
+	}
+}
+
+func TestPublicKeys_ProposalsAndAttestations(t *testing.T) {
+	pubkeys := [][48]byte{{1}, {2}}
+	db := SetupDB(t, pubkeys)
+	attester := [48]byte{3}
+	histories, err := db.AttestationHistoryForPubKeys(context.Background(), [][48]byte{pubkeys[1], attester})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAttestationHistoryForPubKeys(context.Background(), histories); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := db.PublicKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][48]byte{{1}, {2}, {3}}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("Wanted public keys %v, received %v", want, keys)
+	}
+}
+
 func TestPruneProposalHistory_OK(t *testing.T) {
 	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
 	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
@@ -3,6 +3,8 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -14,17 +16,51 @@ var (
 			"of validating keys may wish to disable granular prometheus metrics as it increases " +
 			"the data cardinality.",
 	}
+	// AccountMetricsMaxKeysFlag caps the number of validating keys with their own prometheus labels.
+	AccountMetricsMaxKeysFlag = &cli.Uint64Flag{
+		Name: "account-metrics-max-keys",
+		Usage: "Maximum number of validating keys with their own labels in the account metrics. The counters " +
+			"of further keys are reported under the \"other\" label, and their gauges are not reported. Unlimited when 0",
+	}
 	// BeaconRPCProviderFlag defines a beacon node RPC endpoint.
 	BeaconRPCProviderFlag = &cli.StringFlag{
-		Name:  "beacon-rpc-provider",
-		Usage: "Beacon node RPC provider endpoint",
+		Name: "beacon-rpc-provider",
+		Usage: "Beacon node RPC provider endpoint. A comma separated list of endpoints fails over between the beacon nodes, " +
+			"preferring synced ones in the order given",
 		Value: "localhost:4000",
 	}
+	// BeaconRPCAuthTokenFileFlag defines a file holding the auth token of the beacon node RPC endpoint.
+	BeaconRPCAuthTokenFileFlag = &cli.StringFlag{
+		Name:  "beacon-rpc-auth-token-file",
+		Usage: "Path to the auth token file of a beacon node started with --rpc-auth",
+	}
+	// BeaconHTTPProviderFlag defines the URL of the Eth2 HTTP API of the beacon node.
+	BeaconHTTPProviderFlag = &cli.StringFlag{
+		Name:  "beacon-http-provider",
+		Usage: "URL of the Eth2 HTTP API of a beacon node started with --http-api-port, used for doppelganger detection",
+	}
+	// DoppelgangerDetectionEpochsFlag defines the number of epochs to watch for the validators
+	// attesting elsewhere before performing duties.
+	DoppelgangerDetectionEpochsFlag = &cli.Uint64Flag{
+		Name: "doppelganger-detection-epochs",
+		Usage: "Number of epochs to watch the network for validators with the same keys before performing " +
+			"duties, refusing to start if any are found. Requires --beacon-http-provider. Disabled when 0",
+	}
 	// CertFlag defines a flag for the node's TLS certificate.
 	CertFlag = &cli.StringFlag{
 		Name:  "tls-cert",
 		Usage: "Certificate for secure gRPC. Pass this and the tls-key flag in order to use gRPC securely.",
 	}
+	// ClientCertFlag defines the certificate the validator client presents to the beacon node.
+	ClientCertFlag = &cli.StringFlag{
+		Name:  "tls-client-cert",
+		Usage: "Client certificate for mutual TLS with a beacon node started with --tls-client-ca. Pass this and the tls-client-key flag",
+	}
+	// ClientKeyFlag defines the key of the client certificate.
+	ClientKeyFlag = &cli.StringFlag{
+		Name:  "tls-client-key",
+		Usage: "Key of the client certificate for mutual TLS with a beacon node",
+	}
 	// SlasherRPCProviderFlag defines a slasher node RPC endpoint.
 	SlasherRPCProviderFlag = &cli.StringFlag{
 		Name:  "slasher-rpc-provider",
@@ -46,6 +82,13 @@ var (
 		Name:  "graffiti",
 		Usage: "String to include in proposed blocks",
 	}
+	// GraffitiFileFlag defines a file holding the graffiti configuration, which is read again on SIGHUP.
+	GraffitiFileFlag = &cli.StringFlag{
+		Name: "graffiti-file",
+		Usage: "File containing the string to include in proposed blocks, or a YAML configuration with a default " +
+			"graffiti, the graffiti of specific public keys and the rotation mode of graffiti lists (ordered or random), " +
+			"overriding --graffiti. It is read again on SIGHUP",
+	}
 	// GrpcRetriesFlag defines the number of times to retry a failed gRPC request.
 	GrpcRetriesFlag = &cli.UintFlag{
 		Name:  "grpc-retries",
@@ -61,7 +104,7 @@ var (
 	// KeyManager specifies the key manager to use.
 	KeyManager = &cli.StringFlag{
 		Name:  "keymanager",
-		Usage: "The keymanger to use (unencrypted, interop, keystore, wallet)",
+		Usage: "The keymanger to use (unencrypted, interop, keystore, wallet, remote, unified, web3signer)",
 		Value: "",
 	}
 	// KeyManagerOpts specifies the key manager options.
@@ -81,10 +124,66 @@ var (
 		Usage: "Port used to listening and respond metrics for prometheus.",
 		Value: 8081,
 	}
+	// WebFlag enables the local HTTP API and web UI managing the validator wallet.
+	WebFlag = &cli.BoolFlag{
+		Name: "web",
+		Usage: "Serve an HTTP API and web UI to list and import the accounts of the wallet of --keymanager=unified, " +
+			"set the graffiti and exit validators, authenticated with tokens of 'validator web auth'",
+	}
+	// WebHostFlag defines the host the web API listens on.
+	WebHostFlag = &cli.StringFlag{
+		Name:  "web-host",
+		Usage: "Host on which the web API and UI listen. Only expose it beyond localhost behind a TLS proxy",
+		Value: "127.0.0.1",
+	}
+	// WebPortFlag defines the port the web API listens on.
+	WebPortFlag = &cli.IntFlag{
+		Name:  "web-port",
+		Usage: "Port on which the web API and UI listen",
+		Value: 7500,
+	}
+	// WebTokenExpiryFlag defines how long the tokens issued by 'validator web auth' are valid.
+	WebTokenExpiryFlag = &cli.DurationFlag{
+		Name:  "web-token-expiry",
+		Usage: "How long the issued web API token is valid",
+		Value: 24 * time.Hour,
+	}
 	// PasswordFlag defines the password value for storing and retrieving validator private keys from the keystore.
 	PasswordFlag = &cli.StringFlag{
-		Name:  "password",
-		Usage: "String value of the password for your validator private keys",
+		Name:    "password",
+		Usage:   "String value of the password for your validator private keys",
+		EnvVars: []string{"VALIDATOR_PASSWORD"},
+	}
+	// PasswordFileFlag defines a file to read the password for the validator private keys from.
+	PasswordFileFlag = &cli.StringFlag{
+		Name:  "password-file",
+		Usage: "Path to a file containing the password for your validator private keys",
+	}
+	// NonInteractiveFlag makes account commands fail instead of prompting for missing input.
+	NonInteractiveFlag = &cli.BoolFlag{
+		Name:  "non-interactive",
+		Usage: "Never prompt for input, fail if the keystore path or password are not provided by flags, files or environment",
+	}
+	// OutputFormatFlag defines the output format of account commands.
+	OutputFormatFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "Output format of the command, either text or json, or csv for statistics",
+		Value: "text",
+	}
+	// PublicKeysFlag defines a comma separated list of validator public keys to operate on.
+	PublicKeysFlag = &cli.StringFlag{
+		Name:  "public-keys",
+		Usage: "Comma separated list of hex encoded validator public keys to operate on",
+	}
+	// ForceExitFlag skips the confirmation prompt of voluntary exits.
+	ForceExitFlag = &cli.BoolFlag{
+		Name:  "force-exit",
+		Usage: "Propose the voluntary exits without confirmation, exited validators are unable to validate again",
+	}
+	// BackupDirFlag defines the directory account backups are written to.
+	BackupDirFlag = &cli.StringFlag{
+		Name:  "backup-dir",
+		Usage: "Directory to write the backed up encrypted keystore files to",
 	}
 	// SourceDirectories defines the locations of the source validator databases while managing validators.
 	SourceDirectories = &cli.StringFlag{
@@ -101,6 +200,17 @@ var (
 		Name:  "target-dir",
 		Usage: "The directory of the target validator database",
 	}
+	// SlashingProtectionFileFlag defines the location of a slashing protection interchange file.
+	SlashingProtectionFileFlag = &cli.StringFlag{
+		Name:  "slashing-protection-file",
+		Usage: "Path of the slashing protection interchange (EIP-3076) JSON file to import or export",
+	}
+	// GenesisValidatorsRootFlag defines the genesis validators root of the network of a slashing
+	// protection interchange.
+	GenesisValidatorsRootFlag = &cli.StringFlag{
+		Name:  "genesis-validators-root",
+		Usage: "Hex encoded genesis validators root of the network of the slashing protection interchange",
+	}
 	// UnencryptedKeysFlag specifies a file path of a JSON file of unencrypted validator keys as an
 	// alternative from launching the validator client from decrypting a keystore directory.
 	UnencryptedKeysFlag = &cli.StringFlag{
@@ -108,4 +218,83 @@ var (
 		Usage: "Filepath to a JSON file of unencrypted validator keys for easier launching of the validator client",
 		Value: "",
 	}
+	// WalletDirFlag defines the directory of a validator wallet.
+	WalletDirFlag = &cli.StringFlag{
+		Name:  "wallet-dir",
+		Usage: "Directory of the validator wallet",
+	}
+	// AccountNameFlag defines the name of a wallet account.
+	AccountNameFlag = &cli.StringFlag{
+		Name:  "account-name",
+		Usage: "Name of the wallet account",
+	}
+	// AccountMetadataFlag defines metadata of a wallet account.
+	AccountMetadataFlag = &cli.StringSliceFlag{
+		Name:  "metadata",
+		Usage: "Metadata of the wallet account as key=value, can be given several times",
+	}
+	// NumAccountsFlag defines the number of wallet accounts to derive.
+	NumAccountsFlag = &cli.IntFlag{
+		Name:  "num-accounts",
+		Usage: "Number of accounts to derive from the wallet seed, named after the account name and their validator index",
+	}
+	// MnemonicFileFlag defines a file to read the mnemonic of a recovered wallet from.
+	MnemonicFileFlag = &cli.StringFlag{
+		Name:  "mnemonic-file",
+		Usage: "Path to a file containing the mnemonic of the wallet to recover",
+	}
+	// KeystoreFileFlag defines the EIP-2335 keystore file imported into a wallet.
+	KeystoreFileFlag = &cli.StringFlag{
+		Name:  "keystore-file",
+		Usage: "Path to the EIP-2335 keystore file to import",
+	}
+	// KeystorePasswordFileFlag defines a file to read the password of an imported keystore from.
+	KeystorePasswordFileFlag = &cli.StringFlag{
+		Name:  "keystore-password-file",
+		Usage: "Path to a file containing the password of the imported or exported keystores",
+	}
+	// KeystoreDirFlag defines the directory of the EIP-2335 keystore files imported into or
+	// exported from a wallet.
+	KeystoreDirFlag = &cli.StringFlag{
+		Name:  "keystore-dir",
+		Usage: "Path to the directory of the EIP-2335 keystore files to import or export",
+	}
+	// KeystorePasswordsDirFlag defines the directory of the password files of the keystores
+	// imported from a directory.
+	KeystorePasswordsDirFlag = &cli.StringFlag{
+		Name: "keystore-passwords-dir",
+		Usage: "Path to a directory containing the password of each imported keystore file, " +
+			"in a file of the same name with a .txt extension",
+	}
+	// KeystoreKDFFlag defines the key derivation function of exported keystores.
+	KeystoreKDFFlag = &cli.StringFlag{
+		Name:  "keystore-kdf",
+		Usage: "Key derivation function of the exported keystores, scrypt or pbkdf2",
+		Value: "scrypt",
+	}
+	// RemoteAccountFlag defines the name of an account of the remote signer.
+	RemoteAccountFlag = &cli.StringFlag{
+		Name:  "remote-account",
+		Usage: "Name of the account on the remote signer, of the form <wallet name>/<account name>",
+	}
+	// RemoteSignerFlag defines the location of the remote signer of a wallet.
+	RemoteSignerFlag = &cli.StringFlag{
+		Name:  "remote-signer",
+		Usage: "Address of the remote signer holding the keys of the remote accounts",
+	}
+	// RemoteSignerCACertFlag defines the CA certificate of the remote signer.
+	RemoteSignerCACertFlag = &cli.StringFlag{
+		Name:  "remote-signer-ca-cert",
+		Usage: "Path to the certificate of the CA which signed the certificate of the remote signer",
+	}
+	// RemoteSignerClientCertFlag defines the client certificate used to connect to the remote signer.
+	RemoteSignerClientCertFlag = &cli.StringFlag{
+		Name:  "remote-signer-client-cert",
+		Usage: "Path to the client certificate used to connect to the remote signer",
+	}
+	// RemoteSignerClientKeyFlag defines the client key used to connect to the remote signer.
+	RemoteSignerClientKeyFlag = &cli.StringFlag{
+		Name:  "remote-signer-client-key",
+		Usage: "Path to the client key used to connect to the remote signer",
+	}
 )
@@ -4,7 +4,10 @@ import (
 	"errors"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 )
 
 // ErrNoSuchKey is returned whenever a request is made for a key of which a key manager is unaware.
@@ -37,3 +40,39 @@ type ProtectingKeyManager interface {
 	// SignAttestation signs an attestation for the validator to broadcast.
 	SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error)
 }
+
+// Signing types of the objects signed by SignObject, as named by the Web3Signer API.
+const (
+	SignTypeAggregationSlot   = "AGGREGATION_SLOT"
+	SignTypeAggregateAndProof = "AGGREGATE_AND_PROOF"
+	SignTypeRandaoReveal      = "RANDAO_REVEAL"
+	SignTypeVoluntaryExit     = "VOLUNTARY_EXIT"
+)
+
+// TypedKeyManager provides access to a keymanager that needs the type and the contents of the
+// objects it signs, like remote signers checking what they sign.
+type TypedKeyManager interface {
+	// SignTyped signs an object of the signing type with the domain.
+	SignTyped(pubKey [48]byte, signType string, object interface{}, domain [32]byte) (*bls.Signature, error)
+}
+
+// SignObject signs an object of the signing type with the domain, passing the object to the key
+// manager if it signs typed objects, and with protection if available.
+func SignObject(km KeyManager, pubKey [48]byte, signType string, object interface{}, domain []byte) (*bls.Signature, error) {
+	if typedKeyManager, supported := km.(TypedKeyManager); supported {
+		return typedKeyManager.SignTyped(pubKey, signType, object, bytesutil.ToBytes32(domain))
+	}
+	if protectingKeyManager, supported := km.(ProtectingKeyManager); supported {
+		root, err := ssz.HashTreeRoot(object)
+		if err != nil {
+			return nil, err
+		}
+		return protectingKeyManager.SignGeneric(pubKey, root, bytesutil.ToBytes32(domain))
+	}
+
+	root, err := helpers.ComputeSigningRoot(object, domain)
+	if err != nil {
+		return nil, err
+	}
+	return km.Sign(pubKey, root)
+}
@@ -87,30 +87,51 @@ func NewRemoteWallet(input string) (KeyManager, string, error) {
 		return nil, remoteOptsHelp, errors.New("at least one account specifier is required")
 	}
 
+	conn, err := dialRemote(opts.Location, opts.Certificates)
+	if err != nil {
+		return nil, remoteOptsHelp, err
+	}
+
+	km := &Remote{
+		conn:  conn,
+		paths: opts.Accounts,
+	}
+
+	err = km.RefreshValidatingKeys()
+	if err != nil {
+		return nil, remoteOptsHelp, errors.Wrap(err, "failed to fetch accounts from remote wallet")
+	}
+
+	return km, remoteOptsHelp, nil
+}
+
+// dialRemote connects to the walletd instance at the location, authenticating with the client
+// certificate.
+func dialRemote(location string, certs *remoteCertificateOpts) (*grpc.ClientConn, error) {
 	// Load the client certificates.
-	if opts.Certificates == nil {
-		return nil, remoteOptsHelp, errors.New("certificates are required")
+	if certs == nil {
+		return nil, errors.New("certificates are required")
 	}
-	if opts.Certificates.ClientCert == "" {
-		return nil, remoteOptsHelp, errors.New("client certificate is required")
+	if certs.ClientCert == "" {
+		return nil, errors.New("client certificate is required")
 	}
-	if opts.Certificates.ClientKey == "" {
-		return nil, remoteOptsHelp, errors.New("client key is required")
+	if certs.ClientKey == "" {
+		return nil, errors.New("client key is required")
 	}
-	clientPair, err := tls.LoadX509KeyPair(opts.Certificates.ClientCert, opts.Certificates.ClientKey)
+	clientPair, err := tls.LoadX509KeyPair(certs.ClientCert, certs.ClientKey)
 	if err != nil {
-		return nil, remoteOptsHelp, errors.Wrap(err, "failed to obtain client's certificate and/or key")
+		return nil, errors.Wrap(err, "failed to obtain client's certificate and/or key")
 	}
 
 	// Load the CA for the server certificate if present.
 	cp := x509.NewCertPool()
-	if opts.Certificates.CACert != "" {
-		serverCA, err := ioutil.ReadFile(opts.Certificates.CACert)
+	if certs.CACert != "" {
+		serverCA, err := ioutil.ReadFile(certs.CACert)
 		if err != nil {
-			return nil, remoteOptsHelp, errors.Wrap(err, "failed to obtain server's CA certificate")
+			return nil, errors.Wrap(err, "failed to obtain server's CA certificate")
 		}
 		if !cp.AppendCertsFromPEM(serverCA) {
-			return nil, remoteOptsHelp, errors.Wrap(err, "failed to add server's CA certificate to pool")
+			return nil, errors.Wrap(err, "failed to add server's CA certificate to pool")
 		}
 	}
 
@@ -127,22 +148,11 @@ func NewRemoteWallet(input string) (KeyManager, string, error) {
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize)),
 	}
 
-	conn, err := grpc.Dial(opts.Location, grpcOpts...)
-	if err != nil {
-		return nil, remoteOptsHelp, errors.New("failed to connect to remote wallet")
-	}
-
-	km := &Remote{
-		conn:  conn,
-		paths: opts.Accounts,
-	}
-
-	err = km.RefreshValidatingKeys()
+	conn, err := grpc.Dial(location, grpcOpts...)
 	if err != nil {
-		return nil, remoteOptsHelp, errors.Wrap(err, "failed to fetch accounts from remote wallet")
+		return nil, errors.New("failed to connect to remote wallet")
 	}
-
-	return km, remoteOptsHelp, nil
+	return conn, nil
 }
 
 // FetchValidatingKeys fetches the list of public keys that should be used to validate with.
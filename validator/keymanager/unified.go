@@ -0,0 +1,188 @@
+package keymanager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/wallet"
+)
+
+// Unified is a key manager for the accounts of a validator wallet. The secret keys of derived
+// and imported accounts are held directly, remote accounts are signed for by the remote signer
+// of the wallet.
+type Unified struct {
+	direct *Direct
+	// remote is nil if the wallet has no remote accounts.
+	remote *Remote
+
+	walletDir      string
+	walletPassword string
+}
+
+type unifiedOpts struct {
+	Path       string `json:"path"`
+	Passphrase string `json:"passphrase"`
+}
+
+var unifiedOptsHelp = `The unified key manager uses the derived, imported and remote accounts of a
+validator wallet, as managed by the 'wallet' command.  The options are:
+  - path This is the directory of the wallet
+  - passphrase This is the password of the wallet.  If it starts with '$' it is read
+    from the environment variable of that name
+
+A sample set of options are:
+  {
+    "path":       "/home/me/wallet", // Use the wallet in '/home/me/wallet'
+    "passphrase": "$WALLET_PASSWORD" // Read the wallet password from the WALLET_PASSWORD environment variable
+  }`
+
+// NewUnified creates a key manager populated with the accounts of the wallet at the given path.
+func NewUnified(input string) (KeyManager, string, error) {
+	opts := &unifiedOpts{}
+	if err := json.Unmarshal([]byte(input), opts); err != nil {
+		return nil, unifiedOptsHelp, err
+	}
+	if opts.Path == "" {
+		return nil, unifiedOptsHelp, errors.New("wallet path is required")
+	}
+	if strings.HasPrefix(opts.Passphrase, "$") {
+		if envPassphrase := os.Getenv(strings.TrimPrefix(opts.Passphrase, "$")); envPassphrase != "" {
+			opts.Passphrase = envPassphrase
+		}
+	}
+	w, err := wallet.Open(opts.Path, opts.Passphrase)
+	if err != nil {
+		return nil, unifiedOptsHelp, err
+	}
+	km, err := newUnified(w)
+	if err != nil {
+		return nil, unifiedOptsHelp, err
+	}
+	km.walletDir = opts.Path
+	km.walletPassword = opts.Passphrase
+	return km, unifiedOptsHelp, nil
+}
+
+func newUnified(w *wallet.Wallet) (*Unified, error) {
+	var sks []*bls.SecretKey
+	remoteAccounts := make(map[[48]byte]*accountInfo)
+	for _, a := range w.Accounts() {
+		if a.Kind == wallet.Remote {
+			pubKey, err := hex.DecodeString(strings.TrimPrefix(a.PublicKey, "0x"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not decode public key of account %q", a.Name)
+			}
+			remoteAccounts[bytesutil.ToBytes48(pubKey)] = &accountInfo{Name: a.RemoteAccount, PubKey: pubKey}
+			continue
+		}
+		sk, err := w.SecretKey(a.Name)
+		if err != nil {
+			return nil, err
+		}
+		sks = append(sks, sk)
+	}
+
+	km := &Unified{direct: NewDirect(sks)}
+	if len(remoteAccounts) > 0 {
+		rs := w.RemoteSigner()
+		if rs == nil {
+			return nil, errors.New("wallet has remote accounts but no remote signer")
+		}
+		conn, err := dialRemote(rs.Location, &remoteCertificateOpts{
+			CACert:     rs.CACert,
+			ClientCert: rs.ClientCert,
+			ClientKey:  rs.ClientKey,
+		})
+		if err != nil {
+			return nil, err
+		}
+		km.remote = &Remote{conn: conn, accounts: remoteAccounts}
+	}
+	log.WithField("local", len(sks)).WithField("remote", len(remoteAccounts)).Info("Loaded wallet accounts")
+	return km, nil
+}
+
+// OpenWallet opens the wallet of the key manager again, to manage its accounts. Accounts added
+// to the wallet are only validated with once the key manager is created again.
+func (km *Unified) OpenWallet() (*wallet.Wallet, error) {
+	return wallet.Open(km.walletDir, km.walletPassword)
+}
+
+// FetchValidatingKeys fetches the list of public keys that should be used to validate with.
+func (km *Unified) FetchValidatingKeys() ([][48]byte, error) {
+	keys, err := km.direct.FetchValidatingKeys()
+	if err != nil {
+		return nil, err
+	}
+	if km.remote != nil {
+		remoteKeys, err := km.remote.FetchValidatingKeys()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, remoteKeys...)
+	}
+	return keys, nil
+}
+
+// Sign signs a message for the validator to broadcast. Remote accounts do not support
+// unprotected signing.
+func (km *Unified) Sign(pubKey [48]byte, root [32]byte) (*bls.Signature, error) {
+	if km.isRemote(pubKey) {
+		return km.remote.Sign(pubKey, root)
+	}
+	return km.direct.Sign(pubKey, root)
+}
+
+// SignGeneric signs a generic root.
+func (km *Unified) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (*bls.Signature, error) {
+	if km.isRemote(pubKey) {
+		return km.remote.SignGeneric(pubKey, root, domain)
+	}
+	signingRoot, err := ssz.HashTreeRoot(&p2ppb.SigningRoot{ObjectRoot: root[:], Domain: domain[:]})
+	if err != nil {
+		return nil, err
+	}
+	return km.direct.Sign(pubKey, signingRoot)
+}
+
+// SignProposal signs a block proposal for the validator to broadcast.
+func (km *Unified) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (*bls.Signature, error) {
+	if km.isRemote(pubKey) {
+		return km.remote.SignProposal(pubKey, domain, data)
+	}
+	// The root of a block header is the root of its block.
+	root, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.direct.Sign(pubKey, root)
+}
+
+// SignAttestation signs an attestation for the validator to broadcast.
+func (km *Unified) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error) {
+	if km.isRemote(pubKey) {
+		return km.remote.SignAttestation(pubKey, domain, data)
+	}
+	root, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.direct.Sign(pubKey, root)
+}
+
+func (km *Unified) isRemote(pubKey [48]byte) bool {
+	if km.remote == nil {
+		return false
+	}
+	_, ok := km.remote.accounts[pubKey]
+	return ok
+}
@@ -0,0 +1,109 @@
+package keymanager_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	validatorwallet "github.com/prysmaticlabs/prysm/validator/wallet"
+)
+
+func setupUnifiedWallet(t *testing.T) (string, *validatorwallet.Wallet) {
+	dir, err := ioutil.TempDir("", "unified")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	})
+	dir = filepath.Join(dir, "wallet")
+	w, _, err := validatorwallet.Create(dir, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, w
+}
+
+func TestUnified_SignsForLocalAccounts(t *testing.T) {
+	dir, w := setupUnifiedWallet(t)
+	if _, err := w.DeriveAccount("derived", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.DeriveAccount("other", nil); err != nil {
+		t.Fatal(err)
+	}
+	sk, err := w.SecretKey("derived")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := keymanager.NewUnified(fmt.Sprintf(`{"path":%q,"passphrase":"wrong"}`, dir)); err == nil {
+		t.Error("Opened wallet with wrong passphrase")
+	}
+	if err := os.Setenv("UNIFIED_TEST_PASSPHRASE", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	km, _, err := keymanager.NewUnified(fmt.Sprintf(`{"path":%q,"passphrase":"$UNIFIED_TEST_PASSPHRASE"}`, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Wanted 2 validating keys, received %d", len(keys))
+	}
+
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	domain := bytesutil.ToBytes32([]byte("domain"))
+	data := &ethpb.AttestationData{
+		Slot:            5,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+	}
+	sig, err := km.(keymanager.ProtectingKeyManager).SignAttestation(pubKey, domain, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(sk.PublicKey(), root[:]) {
+		t.Error("Attestation signature does not verify against the signing root")
+	}
+
+	if _, err := km.Sign(bytesutil.ToBytes48(bls.RandKey().PublicKey().Marshal()), root); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Wanted %v for unknown key, received %v", keymanager.ErrNoSuchKey, err)
+	}
+}
+
+func TestUnified_RemoteSignerRequired(t *testing.T) {
+	dir, w := setupUnifiedWallet(t)
+	if err := w.SetRemoteSigner(&validatorwallet.RemoteSigner{
+		Location:   "localhost:12345",
+		ClientCert: filepath.Join(dir, "missing.crt"),
+		ClientKey:  filepath.Join(dir, "missing.key"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.AddRemoteAccount("remote", bls.RandKey().PublicKey().Marshal(), "Validators/1", nil); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := keymanager.NewUnified(fmt.Sprintf(`{"path":%q,"passphrase":"secret"}`, dir))
+	if err == nil || !strings.Contains(err.Error(), "client's certificate") {
+		t.Errorf("Wanted error loading the remote signer certificate, received %v", err)
+	}
+}
@@ -0,0 +1,431 @@
+package keymanager
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/p2putils"
+)
+
+const (
+	// web3SignerTimeout is the time to wait for a response of the remote signer.
+	web3SignerTimeout        = 10 * time.Second
+	web3SignerPublicKeysPath = "/api/v1/eth2/publicKeys"
+	web3SignerSignPath       = "/api/v1/eth2/sign/"
+)
+
+// Web3Signer is a key manager that delegates signing to a remote signer implementing the
+// Web3Signer HTTP API. Each signing request is routed to the signer by the public key of the
+// validator, the private keys never leave the signer.
+type Web3Signer struct {
+	url                   string
+	client                *http.Client
+	pubKeys               map[[48]byte]bool
+	genesisValidatorsRoot []byte
+}
+
+type web3SignerOpts struct {
+	URL                   string                 `json:"url"`
+	PublicKeys            []string               `json:"public_keys"`
+	GenesisValidatorsRoot string                 `json:"genesis_validators_root"`
+	Certificates          *remoteCertificateOpts `json:"certificates"`
+}
+
+var web3SignerOptsHelp = `The web3signer key manager signs with a remote signer implementing the Web3Signer
+HTTP API.  The options are:
+  - url This is the base URL of the remote signer
+  - public_keys This is a list of the public keys to validate with.  If not supplied
+    all the public keys of the remote signer are used
+  - genesis_validators_root This is the genesis validators root of the network, which the
+    remote signer computes the signing domains with
+  - certificates This optionally provides paths to certificates for TLS:
+    - ca_cert This is the path to the server's certificate authority certificate file
+    - client_cert This is the path to the client's certificate file
+    - client_key This is the path to the client's key file
+
+An sample keymanager options file (with annotations; these should be removed if
+using this as a template) is:
+
+  {
+    "url":         "https://signer.example.com:9000", // Connect to the signer at signer.example.com on port 9000
+    "public_keys": ["0xa99a...e44c"],                   // Only validate with this public key
+    "genesis_validators_root": "0x0433...c2c8",         // Genesis validators root of the network
+    "certificates": {
+      "ca_cert": "/home/eth2/certs/ca.crt"         // Certificate file for the CA that signed the server's certificate
+      "client_cert": "/home/eth2/certs/client.crt" // Certificate file for this client
+      "client_key": "/home/eth2/certs/client.key"  // Key file for this client
+    }
+  }`
+
+// web3SignerRequest is the body of a signing request. The signed object is sent along with its
+// type, its signing root and the fork it is signed in, so the signer can check it.
+type web3SignerRequest struct {
+	Type              string                       `json:"type"`
+	ForkInfo          *web3SignerForkInfo          `json:"fork_info"`
+	SigningRoot       string                       `json:"signingRoot"`
+	Block             *web3SignerBlockHeader       `json:"block,omitempty"`
+	Attestation       *web3SignerAttestation       `json:"attestation,omitempty"`
+	AggregationSlot   *web3SignerAggregationSlot   `json:"aggregation_slot,omitempty"`
+	AggregateAndProof *web3SignerAggregateAndProof `json:"aggregate_and_proof,omitempty"`
+	RandaoReveal      *web3SignerRandaoReveal      `json:"randao_reveal,omitempty"`
+	VoluntaryExit     *web3SignerVoluntaryExit     `json:"voluntary_exit,omitempty"`
+}
+
+type web3SignerForkInfo struct {
+	Fork                  *web3SignerFork `json:"fork"`
+	GenesisValidatorsRoot string          `json:"genesis_validators_root"`
+}
+
+type web3SignerFork struct {
+	PreviousVersion string `json:"previous_version"`
+	CurrentVersion  string `json:"current_version"`
+	Epoch           uint64 `json:"epoch,string"`
+}
+
+type web3SignerBlockHeader struct {
+	Slot          uint64 `json:"slot,string"`
+	ProposerIndex uint64 `json:"proposer_index,string"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+	BodyRoot      string `json:"body_root"`
+}
+
+type web3SignerAttestation struct {
+	Slot            uint64                `json:"slot,string"`
+	Index           uint64                `json:"index,string"`
+	BeaconBlockRoot string                `json:"beacon_block_root"`
+	Source          *web3SignerCheckpoint `json:"source"`
+	Target          *web3SignerCheckpoint `json:"target"`
+}
+
+type web3SignerCheckpoint struct {
+	Epoch uint64 `json:"epoch,string"`
+	Root  string `json:"root"`
+}
+
+type web3SignerAggregationSlot struct {
+	Slot uint64 `json:"slot,string"`
+}
+
+type web3SignerAggregateAndProof struct {
+	AggregatorIndex uint64               `json:"aggregator_index,string"`
+	Aggregate       *web3SignerAggregate `json:"aggregate"`
+	SelectionProof  string               `json:"selection_proof"`
+}
+
+type web3SignerAggregate struct {
+	AggregationBits string                 `json:"aggregation_bits"`
+	Data            *web3SignerAttestation `json:"data"`
+	Signature       string                 `json:"signature"`
+}
+
+type web3SignerRandaoReveal struct {
+	Epoch uint64 `json:"epoch,string"`
+}
+
+type web3SignerVoluntaryExit struct {
+	Epoch          uint64 `json:"epoch,string"`
+	ValidatorIndex uint64 `json:"validator_index,string"`
+}
+
+// NewWeb3Signer creates a key manager signing with the remote signer at the URL of the options.
+func NewWeb3Signer(input string) (KeyManager, string, error) {
+	opts := &web3SignerOpts{}
+	if err := json.Unmarshal([]byte(input), opts); err != nil {
+		return nil, web3SignerOptsHelp, err
+	}
+	if opts.URL == "" {
+		return nil, web3SignerOptsHelp, errors.New("remote signer URL is required")
+	}
+	genesisValidatorsRoot, err := hex.DecodeString(strings.TrimPrefix(opts.GenesisValidatorsRoot, "0x"))
+	if err != nil || len(genesisValidatorsRoot) != 32 {
+		return nil, web3SignerOptsHelp, errors.New("genesis validators root of 32 bytes is required")
+	}
+	client, err := web3SignerClient(opts.Certificates)
+	if err != nil {
+		return nil, web3SignerOptsHelp, err
+	}
+	km := &Web3Signer{
+		url:                   strings.TrimSuffix(opts.URL, "/"),
+		client:                client,
+		genesisValidatorsRoot: genesisValidatorsRoot,
+	}
+
+	pubKeys := opts.PublicKeys
+	if len(pubKeys) == 0 {
+		if pubKeys, err = km.remotePublicKeys(); err != nil {
+			return nil, web3SignerOptsHelp, errors.Wrap(err, "failed to fetch public keys from remote signer")
+		}
+	}
+	km.pubKeys = make(map[[48]byte]bool, len(pubKeys))
+	for _, s := range pubKeys {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil || len(pubKey) != 48 {
+			return nil, web3SignerOptsHelp, fmt.Errorf("invalid public key %s", s)
+		}
+		km.pubKeys[bytesutil.ToBytes48(pubKey)] = true
+	}
+	return km, web3SignerOptsHelp, nil
+}
+
+// web3SignerClient returns an HTTP client verifying the server with the CA certificate and
+// authenticating with the client certificate, if given.
+func web3SignerClient(certs *remoteCertificateOpts) (*http.Client, error) {
+	tlsCfg := &tls.Config{}
+	if certs != nil {
+		if certs.CACert != "" {
+			serverCA, err := ioutil.ReadFile(certs.CACert)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to obtain server's CA certificate")
+			}
+			cp := x509.NewCertPool()
+			if !cp.AppendCertsFromPEM(serverCA) {
+				return nil, errors.New("failed to add server's CA certificate to pool")
+			}
+			tlsCfg.RootCAs = cp
+		}
+		if certs.ClientCert != "" || certs.ClientKey != "" {
+			clientPair, err := tls.LoadX509KeyPair(certs.ClientCert, certs.ClientKey)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to obtain client's certificate and/or key")
+			}
+			tlsCfg.Certificates = []tls.Certificate{clientPair}
+		}
+	}
+	return &http.Client{
+		Timeout:   web3SignerTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsCfg},
+	}, nil
+}
+
+// remotePublicKeys returns the public keys the remote signer holds the private keys of.
+func (km *Web3Signer) remotePublicKeys() ([]string, error) {
+	resp, err := km.client.Get(km.url + web3SignerPublicKeysPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer returned status %s", resp.Status)
+	}
+	var pubKeys []string
+	if err := json.NewDecoder(resp.Body).Decode(&pubKeys); err != nil {
+		return nil, errors.Wrap(err, "could not decode public keys")
+	}
+	return pubKeys, nil
+}
+
+// FetchValidatingKeys fetches the list of public keys that should be used to validate with.
+func (km *Web3Signer) FetchValidatingKeys() ([][48]byte, error) {
+	res := make([][48]byte, 0, len(km.pubKeys))
+	for pubKey := range km.pubKeys {
+		res = append(res, pubKey)
+	}
+	return res, nil
+}
+
+// Sign does not sign, as the remote signer only signs objects of known types, which are signed
+// with SignTyped, SignProposal and SignAttestation instead.
+func (km *Web3Signer) Sign(pubKey [48]byte, _ [32]byte) (*bls.Signature, error) {
+	if !km.pubKeys[pubKey] {
+		return nil, ErrNoSuchKey
+	}
+	log.Debug("Remote signer cannot sign a root without the signed object")
+	return nil, ErrCannotSign
+}
+
+// SignGeneric does not sign, see Sign.
+func (km *Web3Signer) SignGeneric(pubKey [48]byte, root [32]byte, _ [32]byte) (*bls.Signature, error) {
+	return km.Sign(pubKey, root)
+}
+
+// SignTyped signs an object of the signing type with the domain.
+func (km *Web3Signer) SignTyped(pubKey [48]byte, signType string, object interface{}, domain [32]byte) (*bls.Signature, error) {
+	req := &web3SignerRequest{Type: signType}
+	var epoch uint64
+	switch obj := object.(type) {
+	case uint64:
+		switch signType {
+		case SignTypeAggregationSlot:
+			req.AggregationSlot = &web3SignerAggregationSlot{Slot: obj}
+			epoch = helpers.SlotToEpoch(obj)
+		case SignTypeRandaoReveal:
+			req.RandaoReveal = &web3SignerRandaoReveal{Epoch: obj}
+			epoch = obj
+		default:
+			return nil, fmt.Errorf("unsupported signing type %s of a number", signType)
+		}
+	case *ethpb.AggregateAttestationAndProof:
+		if signType != SignTypeAggregateAndProof {
+			return nil, fmt.Errorf("unsupported signing type %s of an aggregate and proof", signType)
+		}
+		req.AggregateAndProof = &web3SignerAggregateAndProof{
+			AggregatorIndex: obj.AggregatorIndex,
+			Aggregate: &web3SignerAggregate{
+				AggregationBits: fmt.Sprintf("%#x", []byte(obj.Aggregate.AggregationBits)),
+				Data:            web3SignerAttestationData(obj.Aggregate.Data),
+				Signature:       fmt.Sprintf("%#x", obj.Aggregate.Signature),
+			},
+			SelectionProof: fmt.Sprintf("%#x", obj.SelectionProof),
+		}
+		epoch = helpers.SlotToEpoch(obj.Aggregate.Data.Slot)
+	case *ethpb.VoluntaryExit:
+		if signType != SignTypeVoluntaryExit {
+			return nil, fmt.Errorf("unsupported signing type %s of a voluntary exit", signType)
+		}
+		req.VoluntaryExit = &web3SignerVoluntaryExit{Epoch: obj.Epoch, ValidatorIndex: obj.ValidatorIndex}
+		epoch = obj.Epoch
+	default:
+		return nil, fmt.Errorf("unsupported signed object %T", object)
+	}
+	root, err := helpers.ComputeSigningRoot(object, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	req.SigningRoot = fmt.Sprintf("%#x", root)
+	return km.sign(pubKey, epoch, req)
+}
+
+// SignProposal signs a block proposal for the validator to broadcast.
+func (km *Web3Signer) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (*bls.Signature, error) {
+	// The root of a block header is the root of its block.
+	root, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, helpers.SlotToEpoch(data.Slot), &web3SignerRequest{
+		Type:        "BLOCK",
+		SigningRoot: fmt.Sprintf("%#x", root),
+		Block: &web3SignerBlockHeader{
+			Slot:          data.Slot,
+			ProposerIndex: data.ProposerIndex,
+			ParentRoot:    fmt.Sprintf("%#x", data.ParentRoot),
+			StateRoot:     fmt.Sprintf("%#x", data.StateRoot),
+			BodyRoot:      fmt.Sprintf("%#x", data.BodyRoot),
+		},
+	})
+}
+
+// SignAttestation signs an attestation for the validator to broadcast.
+func (km *Web3Signer) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error) {
+	root, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, data.Target.Epoch, &web3SignerRequest{
+		Type:        "ATTESTATION",
+		SigningRoot: fmt.Sprintf("%#x", root),
+		Attestation: web3SignerAttestationData(data),
+	})
+}
+
+func web3SignerAttestationData(data *ethpb.AttestationData) *web3SignerAttestation {
+	return &web3SignerAttestation{
+		Slot:            data.Slot,
+		Index:           data.CommitteeIndex,
+		BeaconBlockRoot: fmt.Sprintf("%#x", data.BeaconBlockRoot),
+		Source:          &web3SignerCheckpoint{Epoch: data.Source.Epoch, Root: fmt.Sprintf("%#x", data.Source.Root)},
+		Target:          &web3SignerCheckpoint{Epoch: data.Target.Epoch, Root: fmt.Sprintf("%#x", data.Target.Root)},
+	}
+}
+
+// forkInfo returns the fork active at the epoch, which the object signed at the epoch is signed in.
+func (km *Web3Signer) forkInfo(epoch uint64) (*web3SignerForkInfo, error) {
+	fork, err := p2putils.Fork(epoch)
+	if err != nil {
+		return nil, err
+	}
+	return &web3SignerForkInfo{
+		Fork: &web3SignerFork{
+			PreviousVersion: fmt.Sprintf("%#x", fork.PreviousVersion),
+			CurrentVersion:  fmt.Sprintf("%#x", fork.CurrentVersion),
+			Epoch:           fork.Epoch,
+		},
+		GenesisValidatorsRoot: fmt.Sprintf("%#x", km.genesisValidatorsRoot),
+	}, nil
+}
+
+// sign sends the signing request of an object signed at the epoch to the remote signer, for the
+// private key of the public key.
+func (km *Web3Signer) sign(pubKey [48]byte, epoch uint64, req *web3SignerRequest) (*bls.Signature, error) {
+	if !km.pubKeys[pubKey] {
+		return nil, ErrNoSuchKey
+	}
+	forkInfo, err := km.forkInfo(epoch)
+	if err != nil {
+		return nil, err
+	}
+	req.ForkInfo = forkInfo
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s%#x", km.url, web3SignerSignPath, pubKey), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/plain")
+	resp, err := km.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrNoSuchKey
+	case http.StatusPreconditionFailed:
+		// The signer refuses to sign slashable messages.
+		return nil, ErrDenied
+	default:
+		log.WithField("status", resp.Status).Debug("Remote signer failed to sign")
+		return nil, ErrCannotSign
+	}
+	enc, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseWeb3SignerSignature(enc)
+}
+
+// parseWeb3SignerSignature parses the signature of a response, which is either the hex encoded
+// signature as plain text or a JSON object with the signature.
+func parseWeb3SignerSignature(enc []byte) (*bls.Signature, error) {
+	s := strings.TrimSpace(string(enc))
+	if strings.HasPrefix(s, "{") {
+		res := &struct {
+			Signature string `json:"signature"`
+		}{}
+		if err := json.Unmarshal([]byte(s), res); err != nil {
+			return nil, errors.Wrap(err, "could not decode signature response")
+		}
+		s = res.Signature
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode signature")
+	}
+	return bls.SignatureFromBytes(sig)
+}
@@ -0,0 +1,226 @@
+package keymanager_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+// web3SignerServer is a remote signer holding a single key. It signs the signing root of the
+// requests with a type and fork info, refusing to sign a block header of the same slot twice.
+func web3SignerServer(t *testing.T, sk *bls.SecretKey) (*httptest.Server, *[]map[string]interface{}) {
+	pubKey := fmt.Sprintf("%#x", sk.PublicKey().Marshal())
+	var requests []map[string]interface{}
+	signedSlots := make(map[string]bool)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/eth2/publicKeys":
+			if err := json.NewEncoder(w).Encode([]string{pubKey}); err != nil {
+				t.Error(err)
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/eth2/sign/"+pubKey:
+			req := make(map[string]interface{})
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			requests = append(requests, req)
+			if req["type"] == "" || req["fork_info"] == nil {
+				http.Error(w, "missing type or fork info", http.StatusBadRequest)
+				return
+			}
+			if block, ok := req["block"].(map[string]interface{}); ok {
+				slot := block["slot"].(string)
+				if signedSlots[slot] {
+					http.Error(w, "slashable", http.StatusPreconditionFailed)
+					return
+				}
+				signedSlots[slot] = true
+			}
+			signingRoot, err := hex.DecodeString(strings.TrimPrefix(req["signingRoot"].(string), "0x"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "%#x", sk.Sign(signingRoot).Marshal()); err != nil {
+				t.Error(err)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+// web3SignerOpts returns the key manager options for the server, trusting its certificate.
+func web3SignerOpts(t *testing.T, srv *httptest.Server, extra string) string {
+	dir, err := ioutil.TempDir("", "web3signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	})
+	caCert := filepath.Join(dir, "ca.crt")
+	enc := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := ioutil.WriteFile(caCert, enc, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf(`{"url":%q,"genesis_validators_root":"%#x","certificates":{"ca_cert":%q}%s}`, srv.URL, make([]byte, 32), caCert, extra)
+}
+
+func TestWeb3Signer_Signs(t *testing.T) {
+	sk := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	srv, requests := web3SignerServer(t, sk)
+	km, _, err := keymanager.NewWeb3Signer(web3SignerOpts(t, srv, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != pubKey {
+		t.Fatalf("Wanted the public key of the remote signer, received %#x", keys)
+	}
+	protecting, ok := km.(keymanager.ProtectingKeyManager)
+	if !ok {
+		t.Fatal("Expected the remote signer key manager to be protecting")
+	}
+
+	domain := bytesutil.ToBytes32([]byte("domain"))
+	header := &ethpb.BeaconBlockHeader{
+		Slot:       5,
+		ParentRoot: make([]byte, 32),
+		StateRoot:  make([]byte, 32),
+		BodyRoot:   make([]byte, 32),
+	}
+	sig, err := protecting.SignProposal(pubKey, domain, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := helpers.ComputeSigningRoot(header, domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(sk.PublicKey(), root[:]) {
+		t.Error("Proposal signature does not verify")
+	}
+	if _, err := protecting.SignProposal(pubKey, domain, header); err != keymanager.ErrDenied {
+		t.Errorf("Wanted signing a block of the same slot to be denied, received %v", err)
+	}
+
+	data := &ethpb.AttestationData{
+		Slot:            5,
+		CommitteeIndex:  2,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+	}
+	sig, err = protecting.SignAttestation(pubKey, domain, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err = helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(sk.PublicKey(), root[:]) {
+		t.Error("Attestation signature does not verify")
+	}
+	att := (*requests)[len(*requests)-1]
+	if att["type"] != "ATTESTATION" || att["attestation"].(map[string]interface{})["index"] != "2" {
+		t.Errorf("Unexpected attestation signing request %v", att)
+	}
+
+	forkInfo := att["fork_info"].(map[string]interface{})
+	if forkInfo["genesis_validators_root"] != fmt.Sprintf("%#x", make([]byte, 32)) {
+		t.Errorf("Unexpected fork info %v", forkInfo)
+	}
+
+	typed, ok := km.(keymanager.TypedKeyManager)
+	if !ok {
+		t.Fatal("Expected the remote signer key manager to sign typed objects")
+	}
+	sig, err = typed.SignTyped(pubKey, keymanager.SignTypeAggregationSlot, uint64(9), domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err = helpers.ComputeSigningRoot(uint64(9), domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(sk.PublicKey(), root[:]) {
+		t.Error("Slot signature does not verify")
+	}
+	slot := (*requests)[len(*requests)-1]
+	if slot["type"] != "AGGREGATION_SLOT" || slot["aggregation_slot"].(map[string]interface{})["slot"] != "9" {
+		t.Errorf("Unexpected aggregation slot signing request %v", slot)
+	}
+	exit := &ethpb.VoluntaryExit{Epoch: 3, ValidatorIndex: 7}
+	if _, err := typed.SignTyped(pubKey, keymanager.SignTypeVoluntaryExit, exit, domain); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := typed.SignTyped(pubKey, keymanager.SignTypeRandaoReveal, exit, domain); err == nil {
+		t.Error("Expected error for a voluntary exit signed as a randao reveal")
+	}
+
+	// The remote signer does not sign roots without the signed object.
+	root = bytesutil.ToBytes32([]byte("root"))
+	if _, err := km.Sign(pubKey, root); err != keymanager.ErrCannotSign {
+		t.Errorf("Wanted %v for an untyped root, received %v", keymanager.ErrCannotSign, err)
+	}
+	if _, err := km.Sign(bytesutil.ToBytes48([]byte("unknown")), root); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Wanted %v for an unknown key, received %v", keymanager.ErrNoSuchKey, err)
+	}
+}
+
+func TestWeb3Signer_Opts(t *testing.T) {
+	sk := bls.RandKey()
+	srv, _ := web3SignerServer(t, sk)
+	other := bls.RandKey().PublicKey().Marshal()
+
+	km, _, err := keymanager.NewWeb3Signer(web3SignerOpts(t, srv, fmt.Sprintf(`,"public_keys":["%#x"]`, other)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != bytesutil.ToBytes48(other) {
+		t.Errorf("Wanted only the configured public key, received %#x", keys)
+	}
+	// The key is not held by the remote signer.
+	if _, err := km.Sign(bytesutil.ToBytes48(other), [32]byte{}); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Wanted %v, received %v", keymanager.ErrNoSuchKey, err)
+	}
+
+	if _, _, err := keymanager.NewWeb3Signer(fmt.Sprintf(`{"url":%q,"genesis_validators_root":"%#x"}`, srv.URL, make([]byte, 32))); err == nil {
+		t.Error("Expected error for a server certificate which is not trusted")
+	}
+	if _, _, err := keymanager.NewWeb3Signer(fmt.Sprintf(`{"url":%q}`, srv.URL)); err == nil {
+		t.Error("Expected error without a genesis validators root")
+	}
+	if _, _, err := keymanager.NewWeb3Signer(`{}`); err == nil {
+		t.Error("Expected error without a remote signer URL")
+	}
+}
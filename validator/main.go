@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"runtime"
@@ -12,23 +13,25 @@ import (
 	"strings"
 	"time"
 
-	joonix "github.com/joonix/log"
+	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/logutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/reload"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/prysmaticlabs/prysm/validator/accounts"
 	"github.com/prysmaticlabs/prysm/validator/client"
 	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/prysmaticlabs/prysm/validator/node"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
-	"github.com/urfave/cli/v2/altsrc"
-	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 	_ "go.uber.org/automaxprocs"
 	"google.golang.org/grpc"
 )
@@ -40,19 +43,47 @@ func startNode(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	reloader, err := reload.New(ctx, &reload.Config{
+		Flags:    appFlags,
+		Args:     os.Args[1:],
+		Prepare:  prepareFlags,
+		Settings: validatorClient.ReloadSettings(),
+	})
+	if err != nil {
+		return err
+	}
+	reloadCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Run(reloadCtx)
 	validatorClient.Start()
 	return nil
 }
 
+// prepareFlags fills the flags from the config file and the environment, at startup and
+// when the configuration is reloaded.
+func prepareFlags(ctx *cli.Context) error {
+	if err := cmd.LoadFlagsFromConfigFile(ctx, appFlags); err != nil {
+		return err
+	}
+	return cmd.LoadSecrets(ctx, secretFlags)
+}
+
 var appFlags = []cli.Flag{
 	flags.BeaconRPCProviderFlag,
+	flags.BeaconRPCAuthTokenFileFlag,
+	flags.BeaconHTTPProviderFlag,
+	flags.DoppelgangerDetectionEpochsFlag,
 	flags.CertFlag,
+	flags.ClientCertFlag,
+	flags.ClientKeyFlag,
 	flags.GraffitiFlag,
+	flags.GraffitiFileFlag,
 	flags.KeystorePathFlag,
 	flags.SourceDirectories,
 	flags.SourceDirectory,
 	flags.TargetDirectory,
 	flags.PasswordFlag,
+	flags.PasswordFileFlag,
 	flags.DisablePenaltyRewardLogFlag,
 	flags.UnencryptedKeysFlag,
 	flags.InteropStartIndex,
@@ -62,10 +93,21 @@ var appFlags = []cli.Flag{
 	flags.KeyManager,
 	flags.KeyManagerOpts,
 	flags.DisableAccountMetricsFlag,
+	flags.AccountMetricsMaxKeysFlag,
 	flags.MonitoringPortFlag,
+	flags.WebFlag,
+	flags.WebHostFlag,
+	flags.WebPortFlag,
+	cmd.ClientStatsAPIURLFlag,
+	cmd.ClientStatsIntervalFlag,
+	cmd.EnableRoughtimeFlag,
+	cmd.RoughtimeIntervalFlag,
+	cmd.RoughtimeMaxOffsetFlag,
+	cmd.SlotsPerSecondFlag,
 	flags.SlasherRPCProviderFlag,
 	flags.SlasherCertFlag,
 	cmd.VerbosityFlag,
+	cmd.VModuleFlag,
 	cmd.DataDirFlag,
 	cmd.ClearDB,
 	cmd.ForceClearDB,
@@ -90,6 +132,15 @@ func init() {
 	appFlags = cmd.WrapFlags(append(appFlags, featureconfig.ValidatorFlags...))
 }
 
+// secretFlags may contain credentials. They can be provided through the environment and
+// are redacted from logs.
+var secretFlags = []*cli.StringFlag{
+	flags.PasswordFlag,
+	flags.KeyManagerOpts,
+	flags.GrpcHeadersFlag,
+	cmd.ClientStatsAPIURLFlag,
+}
+
 func main() {
 	app := cli.App{}
 	app.Name = "validator"
@@ -149,50 +200,179 @@ contract in order to activate the validator client`,
 						return nil
 					},
 				},
+				{
+					Name:        "list",
+					Description: `lists the public keys and keystore files of the validator accounts in a keystore`,
+					Flags: []cli.Flag{
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+						flags.PasswordFileFlag,
+						flags.NonInteractiveFlag,
+						flags.OutputFormatFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+						if err != nil {
+							return err
+						}
+						list, err := accounts.ListAccounts(keystorePath, passphrase)
+						if err != nil {
+							return err
+						}
+						return accounts.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), list)
+					},
+				},
+				{
+					Name:        "delete",
+					Description: `deletes the keystore files of the validator accounts with the given public keys`,
+					Flags: []cli.Flag{
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+						flags.PasswordFileFlag,
+						flags.NonInteractiveFlag,
+						flags.OutputFormatFlag,
+						flags.PublicKeysFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						pubKeys, err := accounts.ParsePublicKeys(cliCtx.String(flags.PublicKeysFlag.Name))
+						if err != nil {
+							return err
+						}
+						keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+						if err != nil {
+							return err
+						}
+						deleted, err := accounts.DeleteAccounts(keystorePath, passphrase, pubKeys)
+						if err != nil {
+							return err
+						}
+						return accounts.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), deleted)
+					},
+				},
+				{
+					Name: "backup",
+					Description: `copies the encrypted keystore files of the validator accounts with the given public keys,
+or of all accounts if none are given, to a backup directory`,
+					Flags: []cli.Flag{
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+						flags.PasswordFileFlag,
+						flags.NonInteractiveFlag,
+						flags.OutputFormatFlag,
+						flags.PublicKeysFlag,
+						flags.BackupDirFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						pubKeys, err := accounts.ParsePublicKeys(cliCtx.String(flags.PublicKeysFlag.Name))
+						if err != nil {
+							return err
+						}
+						keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+						if err != nil {
+							return err
+						}
+						backedUp, err := accounts.BackupAccounts(keystorePath, passphrase, cliCtx.String(flags.BackupDirFlag.Name), pubKeys)
+						if err != nil {
+							return err
+						}
+						return accounts.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), backedUp)
+					},
+				},
 				{
 					Name:        "status",
 					Description: `list the validator status for existing validator keys`,
-					Flags: []cli.Flag{
-						cmd.GrpcMaxCallRecvMsgSizeFlag,
-						flags.BeaconRPCProviderFlag,
-						flags.CertFlag,
-						flags.GrpcHeadersFlag,
-						flags.GrpcRetriesFlag,
+					Flags: append(beaconNodeFlags,
 						flags.KeyManager,
 						flags.KeyManagerOpts,
+					),
+					Action: func(cliCtx *cli.Context) error {
+						pubKeys, err := accountPublicKeys(cliCtx)
+						if err != nil {
+							return err
+						}
+						conn, err := dialBeaconNode(cliCtx)
+						if err != nil {
+							return err
+						}
+						err = accounts.RunStatusCommand(pubKeys, ethpb.NewBeaconNodeValidatorClient(conn))
+						if closed := conn.Close(); closed != nil {
+							log.WithError(closed).Error("Could not close connection to beacon node")
+						}
+						return err
 					},
+				},
+				{
+					Name: "statistics",
+					Description: `prints the balances of the validators of existing validator keys with their estimated
+APR and projected annual rewards at the current network participation`,
+					Flags: append(beaconNodeFlags,
+						flags.KeyManager,
+						flags.KeyManagerOpts,
+						flags.OutputFormatFlag,
+					),
 					Action: func(cliCtx *cli.Context) error {
-						var err error
-						var pubKeys [][]byte
-						if cliCtx.String(flags.KeyManager.Name) != "" {
-							pubKeysBytes48, success := node.ExtractPublicKeysFromKeyManager(cliCtx)
-							pubKeys, err = bytesutil.FromBytes48Array(pubKeysBytes48), success
-						} else {
-							keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+						pubKeys, err := accountPublicKeys(cliCtx)
+						if err != nil {
+							return err
+						}
+						conn, err := dialBeaconNode(cliCtx)
+						if err != nil {
+							return err
+						}
+						err = accounts.RunStatisticsCommand(
+							os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), pubKeys, ethpb.NewBeaconChainClient(conn),
+						)
+						if closed := conn.Close(); closed != nil {
+							log.WithError(closed).Error("Could not close connection to beacon node")
+						}
+						return err
+					},
+				},
+				{
+					Name: "exit",
+					Description: `proposes voluntary exits of the validators with the given public keys, signed with the
+keys of the key manager - exited validators are unable to validate again`,
+					Flags: append(beaconNodeFlags,
+						flags.KeyManager,
+						flags.KeyManagerOpts,
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+						flags.PublicKeysFlag,
+						flags.ForceExitFlag,
+					),
+					Action: func(cliCtx *cli.Context) error {
+						pubKeys, err := accounts.ParsePublicKeys(cliCtx.String(flags.PublicKeysFlag.Name))
+						if err != nil {
+							return err
+						}
+						if len(pubKeys) == 0 {
+							return errors.New("no public keys given to exit")
+						}
+						km, err := node.SelectKeyManager(cliCtx)
+						if err != nil {
+							return err
+						}
+						exitKeys, err := keyManagerPublicKeys(km, pubKeys)
+						if err != nil {
+							return err
+						}
+						if !cliCtx.Bool(flags.ForceExitFlag.Name) {
+							confirmed, err := cmd.ConfirmAction(accounts.ExitWarning, "No voluntary exits have been proposed.")
 							if err != nil {
 								return err
 							}
-							pubKeys, err = accounts.ExtractPublicKeysFromKeyStore(keystorePath, passphrase)
+							if !confirmed {
+								return nil
+							}
 						}
+						conn, err := dialBeaconNode(cliCtx)
 						if err != nil {
 							return err
 						}
-						ctx, cancel := context.WithTimeout(
-							context.Background(), 10*time.Second /* Cancel if cannot connect to beacon node in 10 seconds. */)
-						defer cancel()
-						dialOpts := client.ConstructDialOptions(
-							cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
-							cliCtx.String(flags.CertFlag.Name),
-							strings.Split(cliCtx.String(flags.GrpcHeadersFlag.Name), ","),
-							cliCtx.Uint(flags.GrpcRetriesFlag.Name),
-							grpc.WithBlock())
-						endpoint := cliCtx.String(flags.BeaconRPCProviderFlag.Name)
-						conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
-						if err != nil {
-							log.WithError(err).Fatalf("Failed to dial beacon node endpoint at %s", endpoint)
-							return err
+						sign := func(pubKey [48]byte, exit *ethpb.VoluntaryExit, domain []byte) (*bls.Signature, error) {
+							return keymanager.SignObject(km, pubKey, keymanager.SignTypeVoluntaryExit, exit, domain)
 						}
-						err = accounts.RunStatusCommand(pubKeys, ethpb.NewBeaconNodeValidatorClient(conn))
+						err = accounts.ProposeExits(context.Background(), ethpb.NewBeaconNodeValidatorClient(conn), ethpb.NewNodeClient(conn), exitKeys, sign)
 						if closed := conn.Close(); closed != nil {
 							log.WithError(closed).Error("Could not close connection to beacon node")
 						}
@@ -271,40 +451,25 @@ contract in order to activate the validator client`,
 				},
 			},
 		},
+		walletCommand(),
+		webCommand(),
+		slashingProtectionCommand(),
+		validateConfigCommand(appFlags),
 	}
 
 	app.Flags = appFlags
 
 	app.Before = func(ctx *cli.Context) error {
-		if ctx.IsSet(cmd.ConfigFileFlag.Name) {
-			if err := altsrc.InitInputSourceWithContext(appFlags, altsrc.NewYamlSourceFromFlagFunc(cmd.ConfigFileFlag.Name))(ctx); err != nil {
-				return err
-			}
+		logrus.AddHook(&logutil.RedactionHook{})
+		if err := prepareFlags(ctx); err != nil {
+			return err
 		}
 
-		format := ctx.String(cmd.LogFormat.Name)
-		switch format {
-		case "text":
-			formatter := new(prefixed.TextFormatter)
-			formatter.TimestampFormat = "2006-01-02 15:04:05"
-			formatter.FullTimestamp = true
-			// If persistent log files are written - we disable the log messages coloring because
-			// the colors are ANSI codes and seen as Gibberish in the log files.
-			formatter.DisableColors = ctx.String(cmd.LogFileName.Name) != ""
-			logrus.SetFormatter(formatter)
-			break
-		case "fluentd":
-			f := joonix.NewFormatter()
-			if err := joonix.DisableTimestampFormat(f); err != nil {
-				panic(err)
-			}
-			logrus.SetFormatter(f)
-			break
-		case "json":
-			logrus.SetFormatter(&logrus.JSONFormatter{})
-			break
-		default:
-			return fmt.Errorf("unknown log format %s", format)
+		// If persistent log files are written - we disable the log messages coloring because
+		// the colors are ANSI codes and seen as gibberish in the log files.
+		disableColors := ctx.String(cmd.LogFileName.Name) != ""
+		if err := logutil.ConfigureFormatter(ctx.String(cmd.LogFormat.Name), disableColors); err != nil {
+			return err
 		}
 
 		logFileName := ctx.String(cmd.LogFileName.Name)
@@ -325,8 +490,10 @@ contract in order to activate the validator client`,
 
 	defer func() {
 		if x := recover(); x != nil {
-			log.Errorf("Runtime panic: %v\n%v", x, string(runtimeDebug.Stack()))
-			panic(x)
+			// The panic is not raised again, as the runtime would print its value and stack to
+			// stderr without redacting secrets.
+			log.Errorf("Runtime panic: %v\n%v", logutil.Redact(fmt.Sprint(x)), logutil.Redact(string(runtimeDebug.Stack())))
+			os.Exit(1)
 		}
 	}()
 
@@ -335,3 +502,83 @@ contract in order to activate the validator client`,
 		os.Exit(1)
 	}
 }
+
+// accountPublicKeys returns the public keys of the key manager, or of the keystore if no key
+// manager is given.
+func accountPublicKeys(cliCtx *cli.Context) ([][]byte, error) {
+	if cliCtx.String(flags.KeyManager.Name) != "" {
+		pubKeys, err := node.ExtractPublicKeysFromKeyManager(cliCtx)
+		return bytesutil.FromBytes48Array(pubKeys), err
+	}
+	keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+	if err != nil {
+		return nil, err
+	}
+	return accounts.ExtractPublicKeysFromKeyStore(keystorePath, passphrase)
+}
+
+// beaconNodeFlags are the flags of the account commands connecting to a beacon node.
+var beaconNodeFlags = []cli.Flag{
+	cmd.GrpcMaxCallRecvMsgSizeFlag,
+	flags.BeaconRPCProviderFlag,
+	flags.BeaconRPCAuthTokenFileFlag,
+	flags.CertFlag,
+	flags.ClientCertFlag,
+	flags.ClientKeyFlag,
+	flags.GrpcHeadersFlag,
+	flags.GrpcRetriesFlag,
+}
+
+// dialBeaconNode connects to the beacon node of the beacon node flags, failing if it cannot
+// connect in 10 seconds.
+func dialBeaconNode(cliCtx *cli.Context) (*grpc.ClientConn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	extraOpts := []grpc.DialOption{grpc.WithBlock()}
+	if tokenFile := cliCtx.String(flags.BeaconRPCAuthTokenFileFlag.Name); tokenFile != "" {
+		token, err := rpcauth.ReadToken(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		extraOpts = append(extraOpts, grpc.WithPerRPCCredentials(rpcauth.TokenCredentials(token)))
+	}
+	dialOpts := client.ConstructDialOptions(
+		cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
+		cliCtx.String(flags.CertFlag.Name),
+		cliCtx.String(flags.ClientCertFlag.Name),
+		cliCtx.String(flags.ClientKeyFlag.Name),
+		strings.Split(cliCtx.String(flags.GrpcHeadersFlag.Name), ","),
+		cliCtx.Uint(flags.GrpcRetriesFlag.Name),
+		extraOpts...)
+	endpoint := cliCtx.String(flags.BeaconRPCProviderFlag.Name)
+	conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial beacon node endpoint at %s", endpoint)
+	}
+	return conn, nil
+}
+
+// keyManagerPublicKeys returns the hex encoded public keys as validating keys of the key manager,
+// failing if any of them is not managed by the key manager.
+func keyManagerPublicKeys(km keymanager.KeyManager, pubKeys []string) ([][48]byte, error) {
+	validatingKeys, err := km.FetchValidatingKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get validating keys")
+	}
+	managed := make(map[string]bool, len(validatingKeys))
+	for _, k := range validatingKeys {
+		managed[hex.EncodeToString(k[:])] = true
+	}
+	keys := make([][48]byte, len(pubKeys))
+	for i, k := range pubKeys {
+		if !managed[k] {
+			return nil, fmt.Errorf("public key %s is not managed by the key manager", k)
+		}
+		b, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode public key %s", k)
+		}
+		keys[i] = bytesutil.ToBytes48(b)
+	}
+	return keys, nil
+}
@@ -0,0 +1,55 @@
+package node
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/grpcutils"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/urfave/cli/v2"
+)
+
+// ValidateConfig cross-checks the flags of a validator client and the files they refer to
+// without loading any key or connecting to the beacon node. The feature config must be set
+// up before. All problems found are returned, so they can be fixed at once.
+func ValidateConfig(cliCtx *cli.Context) []error {
+	var errs []error
+	if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+		if _, err := params.ReadChainConfigFile(cliCtx.String(cmd.ChainConfigFileFlag.Name)); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid --%s", cmd.ChainConfigFileFlag.Name))
+		}
+	}
+
+	switch manager := strings.ToLower(cliCtx.String(flags.KeyManager.Name)); manager {
+	case "", "interop", "unencrypted", "keystore", "wallet", "remote", "unified", "web3signer":
+	default:
+		errs = append(errs, fmt.Errorf("unknown keymanager %q", manager))
+	}
+	if opts := cliCtx.String(flags.KeyManagerOpts.Name); opts != "" && !strings.HasPrefix(opts, "{") {
+		if _, err := os.Stat(opts); err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid --%s", flags.KeyManagerOpts.Name))
+		}
+	}
+
+	cert := cliCtx.String(flags.CertFlag.Name)
+	clientCert := cliCtx.String(flags.ClientCertFlag.Name)
+	clientKey := cliCtx.String(flags.ClientKeyFlag.Name)
+	if cert != "" || clientCert != "" || clientKey != "" {
+		if _, err := grpcutils.ClientTLSCredentials(cert, clientCert, clientKey); err != nil {
+			errs = append(errs, errors.Wrap(err, "invalid TLS configuration"))
+		}
+	}
+
+	if featureconfig.Get().SlasherProtection && cliCtx.String(flags.SlasherRPCProviderFlag.Name) == "" {
+		errs = append(errs, errors.New("external slasher feature flag is set but no slasher endpoint is configured"))
+	}
+	if cliCtx.Bool(cmd.EnableRoughtimeFlag.Name) && cliCtx.Duration(cmd.RoughtimeIntervalFlag.Name) <= 0 {
+		errs = append(errs, fmt.Errorf("--%s must be positive", cmd.RoughtimeIntervalFlag.Name))
+	}
+	return errs
+}
@@ -0,0 +1,77 @@
+package node
+
+import (
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/urfave/cli/v2"
+)
+
+func TestValidateConfig(t *testing.T) {
+	resetCfg := featureconfig.Get()
+	defer featureconfig.Init(resetCfg)
+
+	tests := []struct {
+		name              string
+		args              []string
+		slasherProtection bool
+		wantErr           []string
+	}{
+		{
+			name: "valid",
+			args: []string{"--" + flags.KeyManager.Name, "interop", "--" + flags.KeyManagerOpts.Name, `{"keys":1}`},
+		},
+		{
+			name: "web3signer",
+			args: []string{"--" + flags.KeyManager.Name, "web3signer", "--" + flags.KeyManagerOpts.Name, `{"url":"https://localhost:9000"}`},
+		},
+		{
+			name: "all problems reported",
+			args: []string{
+				"--" + flags.KeyManager.Name, "hsm",
+				"--" + flags.KeyManagerOpts.Name, "/does/not/exist/opts.json",
+				"--" + flags.ClientCertFlag.Name, "client.crt",
+				"--" + cmd.EnableRoughtimeFlag.Name,
+				"--" + cmd.RoughtimeIntervalFlag.Name, "-1m",
+			},
+			slasherProtection: true,
+			wantErr: []string{
+				`unknown keymanager "hsm"`,
+				"invalid --keymanageropts",
+				"invalid TLS configuration",
+				"no slasher endpoint is configured",
+				"--roughtime-interval must be positive",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			featureconfig.Init(&featureconfig.Flags{SlasherProtection: tt.slasherProtection})
+			set := flag.NewFlagSet("test", 0)
+			set.String(flags.KeyManager.Name, "", "")
+			set.String(flags.KeyManagerOpts.Name, "", "")
+			set.String(flags.CertFlag.Name, "", "")
+			set.String(flags.ClientCertFlag.Name, "", "")
+			set.String(flags.ClientKeyFlag.Name, "", "")
+			set.String(flags.SlasherRPCProviderFlag.Name, "", "")
+			set.Bool(cmd.EnableRoughtimeFlag.Name, false, "")
+			set.Duration(cmd.RoughtimeIntervalFlag.Name, cmd.RoughtimeIntervalFlag.Value, "")
+			if err := set.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			errs := ValidateConfig(cli.NewContext(&cli.App{}, set, nil))
+			if len(errs) != len(tt.wantErr) {
+				t.Fatalf("Wanted %d errors, received %v", len(tt.wantErr), errs)
+			}
+			for i, want := range tt.wantErr {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("Wanted error containing %q, received %v", want, errs[i])
+				}
+			}
+		})
+	}
+}
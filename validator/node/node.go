@@ -9,17 +9,25 @@ import (
 	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
 
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/clientstats"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
+	"github.com/prysmaticlabs/prysm/shared/dirlock"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/prometheus"
+	"github.com/prysmaticlabs/prysm/shared/reload"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/shared/tracing"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/prysmaticlabs/prysm/validator/client"
@@ -27,8 +35,10 @@ import (
 	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	slashing_protection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
+	"github.com/prysmaticlabs/prysm/validator/web"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
 )
 
 var log = logrus.WithField("prefix", "node")
@@ -41,6 +51,7 @@ type ValidatorClient struct {
 	services *shared.ServiceRegistry // Lifecycle and service store.
 	lock     sync.RWMutex
 	stop     chan struct{} // Channel to wait for termination notifications.
+	dirLock  dirlock.Releaser
 }
 
 // NewValidatorClient creates a new, Ethereum Serenity validator client.
@@ -55,12 +66,9 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 		return nil, err
 	}
 
-	verbosity := cliCtx.String(cmd.VerbosityFlag.Name)
-	level, err := logrus.ParseLevel(verbosity)
-	if err != nil {
+	if err := logutil.SetVerbosity(cliCtx.String(cmd.VerbosityFlag.Name), cliCtx.String(cmd.VModuleFlag.Name)); err != nil {
 		return nil, err
 	}
-	logrus.SetLevel(level)
 
 	registry := shared.NewServiceRegistry()
 	ValidatorClient := &ValidatorClient{
@@ -76,7 +84,11 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 
 	featureconfig.ConfigureValidator(cliCtx)
 
-	keyManager, err := selectKeyManager(cliCtx)
+	if errs := ValidateConfig(cliCtx); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	keyManager, err := SelectKeyManager(cliCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -98,23 +110,37 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 	clearFlag := cliCtx.Bool(cmd.ClearDB.Name)
 	forceClearFlag := cliCtx.Bool(cmd.ForceClearDB.Name)
 	dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+	if dataDir == "" {
+		dataDir = cmd.DefaultDataDir()
+	}
+	// Fail before any key is used if another validator client runs on the same data directory.
+	dirLock, err := dirlock.Acquire(dataDir, dirlock.ValidatorLockFileName)
+	if err != nil {
+		return nil, err
+	}
+	ValidatorClient.dirLock = dirLock
 	if clearFlag || forceClearFlag {
 		pubkeys, err := keyManager.FetchValidatingKeys()
 		if err != nil {
 			return nil, err
 		}
-		if dataDir == "" {
-			dataDir = cmd.DefaultDataDir()
-		}
 		if err := clearDB(dataDir, pubkeys, forceClearFlag); err != nil {
 			return nil, err
 		}
 	}
 	log.WithField("databasePath", dataDir).Info("Checking DB")
 
+	// The clock is adjusted before the validator client starts timing its duties.
+	if err := ValidatorClient.registerRoughtimeService(); err != nil {
+		return nil, err
+	}
+	configureSlotClock(cliCtx)
 	if err := ValidatorClient.registerPrometheusService(); err != nil {
 		return nil, err
 	}
+	if err := ValidatorClient.registerClientStatsService(); err != nil {
+		return nil, err
+	}
 	if featureconfig.Get().SlasherProtection {
 		if err := ValidatorClient.registerSlasherClientService(); err != nil {
 			return nil, err
@@ -123,6 +149,11 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 	if err := ValidatorClient.registerClientService(keyManager); err != nil {
 		return nil, err
 	}
+	if cliCtx.Bool(flags.WebFlag.Name) {
+		if err := ValidatorClient.registerWebService(keyManager, dataDir); err != nil {
+			return nil, err
+		}
+	}
 
 	return ValidatorClient, nil
 }
@@ -168,6 +199,9 @@ func (s *ValidatorClient) Close() {
 
 	s.services.StopAll()
 	log.Info("Stopping sharding validator")
+	if err := s.dirLock.Release(); err != nil {
+		log.WithError(err).Error("Failed to release data directory lock")
+	}
 
 	close(s.stop)
 }
@@ -177,19 +211,73 @@ func (s *ValidatorClient) registerPrometheusService() error {
 		fmt.Sprintf(":%d", s.cliCtx.Int64(flags.MonitoringPortFlag.Name)),
 		s.services,
 	)
-	logrus.AddHook(prometheus.NewLogrusCollector())
+	logrus.AddHook(logutil.ModuleLevelHook(prometheus.NewLogrusCollector()))
 	return s.services.RegisterService(service)
 }
 
+func (s *ValidatorClient) registerClientStatsService() error {
+	url := s.cliCtx.String(cmd.ClientStatsAPIURLFlag.Name)
+	if url == "" {
+		return nil
+	}
+	svc := clientstats.NewService(context.Background(), &clientstats.Config{
+		URL:      url,
+		Interval: s.cliCtx.Duration(cmd.ClientStatsIntervalFlag.Name),
+		Process:  clientstats.ProcessValidator,
+	})
+	return s.services.RegisterService(svc)
+}
+
+// configureSlotClock sets an accelerated slot clock if requested for development.
+func configureSlotClock(cliCtx *cli.Context) {
+	slotsPerSecond := cliCtx.Float64(cmd.SlotsPerSecondFlag.Name)
+	if slotsPerSecond <= 0 {
+		return
+	}
+	log.WithField("slotsPerSecond", slotsPerSecond).Warn("Running an accelerated slot clock, for development only")
+	slotutil.SetClock(slotutil.NewClockWithSlotsPerSecond(slotsPerSecond, params.BeaconConfig().SecondsPerSlot))
+}
+
+func (s *ValidatorClient) registerRoughtimeService() error {
+	if !s.cliCtx.Bool(cmd.EnableRoughtimeFlag.Name) {
+		return nil
+	}
+	svc := roughtime.NewService(context.Background(), &roughtime.Config{
+		Interval:  s.cliCtx.Duration(cmd.RoughtimeIntervalFlag.Name),
+		MaxOffset: s.cliCtx.Duration(cmd.RoughtimeMaxOffsetFlag.Name),
+	})
+	return s.services.RegisterService(svc)
+}
+
 func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager) error {
 	endpoint := s.cliCtx.String(flags.BeaconRPCProviderFlag.Name)
 	dataDir := s.cliCtx.String(cmd.DataDirFlag.Name)
 	logValidatorBalances := !s.cliCtx.Bool(flags.DisablePenaltyRewardLogFlag.Name)
 	emitAccountMetrics := !s.cliCtx.Bool(flags.DisableAccountMetricsFlag.Name)
 	cert := s.cliCtx.String(flags.CertFlag.Name)
-	graffiti := s.cliCtx.String(flags.GraffitiFlag.Name)
+	graffitiConfig, err := readGraffiti(s.cliCtx)
+	if err != nil {
+		return err
+	}
+	graffiti, err := client.ParseGraffiti(graffitiConfig)
+	if err != nil {
+		return err
+	}
 	maxCallRecvMsgSize := s.cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name)
 	grpcRetries := s.cliCtx.Uint(flags.GrpcRetriesFlag.Name)
+	var authToken string
+	if tokenFile := s.cliCtx.String(flags.BeaconRPCAuthTokenFileFlag.Name); tokenFile != "" {
+		token, err := rpcauth.ReadToken(tokenFile)
+		if err != nil {
+			return errors.Wrap(err, "could not read beacon node auth token")
+		}
+		logutil.RegisterSecret(token)
+		authToken = token
+	}
+	doppelgangerEpochs := s.cliCtx.Uint64(flags.DoppelgangerDetectionEpochsFlag.Name)
+	if doppelgangerEpochs > 0 && s.cliCtx.String(flags.BeaconHTTPProviderFlag.Name) == "" {
+		return fmt.Errorf("--%s requires --%s", flags.DoppelgangerDetectionEpochsFlag.Name, flags.BeaconHTTPProviderFlag.Name)
+	}
 	var sp *slashing_protection.Service
 	var protector slashing_protection.Protector
 	if err := s.services.FetchService(&sp); err == nil {
@@ -201,12 +289,18 @@ func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager
 		KeyManager:                 keyManager,
 		LogValidatorBalances:       logValidatorBalances,
 		EmitAccountMetrics:         emitAccountMetrics,
+		AccountMetricsMaxKeys:      s.cliCtx.Uint64(flags.AccountMetricsMaxKeysFlag.Name),
 		CertFlag:                   cert,
-		GraffitiFlag:               graffiti,
+		ClientCertFlag:             s.cliCtx.String(flags.ClientCertFlag.Name),
+		ClientKeyFlag:              s.cliCtx.String(flags.ClientKeyFlag.Name),
+		Graffiti:                   graffiti,
 		GrpcMaxCallRecvMsgSizeFlag: maxCallRecvMsgSize,
 		GrpcRetriesFlag:            grpcRetries,
 		GrpcHeadersFlag:            s.cliCtx.String(flags.GrpcHeadersFlag.Name),
+		AuthToken:                  authToken,
 		Protector:                  protector,
+		BeaconHTTPProvider:         s.cliCtx.String(flags.BeaconHTTPProviderFlag.Name),
+		DoppelgangerEpochs:         doppelgangerEpochs,
 	})
 
 	if err != nil {
@@ -214,12 +308,34 @@ func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager
 	}
 	return s.services.RegisterService(v)
 }
-func (s *ValidatorClient) registerSlasherClientService() error {
-	endpoint := s.cliCtx.String(flags.SlasherRPCProviderFlag.Name)
-	if endpoint == "" {
-		return errors.New("external slasher feature flag is set but no slasher endpoint is configured")
 
+// registerWebService registers the web API and UI managing the validator client, authenticated
+// with tokens signed with the secret in the data directory.
+func (s *ValidatorClient) registerWebService(keyManager keymanager.KeyManager, dataDir string) error {
+	var vs *client.ValidatorService
+	if err := s.services.FetchService(&vs); err != nil {
+		return err
+	}
+	secret, err := rpcauth.LoadOrCreateToken(filepath.Join(dataDir, web.SecretFileName))
+	if err != nil {
+		return errors.Wrap(err, "could not load web API secret")
 	}
+	logutil.RegisterSecret(secret)
+	cfg := &web.Config{
+		Host:       s.cliCtx.String(flags.WebHostFlag.Name),
+		Port:       s.cliCtx.Int(flags.WebPortFlag.Name),
+		Secret:     secret,
+		KeyManager: keyManager,
+		Validator:  vs,
+	}
+	if unified, ok := keyManager.(*keymanager.Unified); ok {
+		cfg.OpenWallet = unified.OpenWallet
+	}
+	return s.services.RegisterService(web.NewServer(cfg))
+}
+
+func (s *ValidatorClient) registerSlasherClientService() error {
+	endpoint := s.cliCtx.String(flags.SlasherRPCProviderFlag.Name)
 	cert := s.cliCtx.String(flags.SlasherCertFlag.Name)
 	maxCallRecvMsgSize := s.cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name)
 	grpcRetries := s.cliCtx.Uint(flags.GrpcRetriesFlag.Name)
@@ -236,8 +352,8 @@ func (s *ValidatorClient) registerSlasherClientService() error {
 	return s.services.RegisterService(sp)
 }
 
-// selectKeyManager selects the key manager depending on the options provided by the user.
-func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
+// SelectKeyManager selects the key manager depending on the options provided by the user.
+func SelectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 	manager := strings.ToLower(ctx.String(flags.KeyManager.Name))
 	opts := ctx.String(flags.KeyManagerOpts.Name)
 	if opts == "" {
@@ -291,6 +407,10 @@ func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 		km, help, err = keymanager.NewWallet(opts)
 	case "remote":
 		km, help, err = keymanager.NewRemoteWallet(opts)
+	case "unified":
+		km, help, err = keymanager.NewUnified(opts)
+	case "web3signer":
+		km, help, err = keymanager.NewWeb3Signer(opts)
 	default:
 		return nil, fmt.Errorf("unknown keymanager %q", manager)
 	}
@@ -333,9 +453,55 @@ func clearDB(dataDir string, pubkeys [][48]byte, force bool) error {
 
 // ExtractPublicKeysFromKeyManager extracts only the public keys from the specified key manager.
 func ExtractPublicKeysFromKeyManager(ctx *cli.Context) ([][48]byte, error) {
-	km, err := selectKeyManager(ctx)
+	km, err := SelectKeyManager(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return km.FetchValidatingKeys()
 }
+
+// ReloadSettings returns the settings which are applied again on SIGHUP: the log level, the
+// graffiti and the client stats endpoint.
+func (s *ValidatorClient) ReloadSettings() []*reload.Setting {
+	settings := []*reload.Setting{reload.LogLevel()}
+	var vs *client.ValidatorService
+	if err := s.services.FetchService(&vs); err == nil {
+		settings = append(settings, &reload.Setting{
+			Name:  "graffiti",
+			Flags: []string{flags.GraffitiFlag.Name, flags.GraffitiFileFlag.Name},
+			Value: readGraffiti,
+			Apply: func(value string) error {
+				graffiti, err := client.ParseGraffiti(value)
+				if err != nil {
+					return err
+				}
+				vs.SetGraffiti(graffiti)
+				return nil
+			},
+		})
+	}
+	var stats *clientstats.Service
+	if err := s.services.FetchService(&stats); err == nil {
+		settings = append(settings, reload.FlagSetting("client stats endpoint", cmd.ClientStatsAPIURLFlag.Name, stats.SetURL))
+	}
+	return settings
+}
+
+// readGraffiti returns the graffiti configuration of --graffiti-file if set, or the configuration
+// using --graffiti for all keys otherwise.
+func readGraffiti(cliCtx *cli.Context) (string, error) {
+	path := cliCtx.String(flags.GraffitiFileFlag.Name)
+	if path == "" {
+		b, err := yaml.Marshal(&client.Graffiti{Default: []string{cliCtx.String(flags.GraffitiFlag.Name)}})
+		if err != nil {
+			return "", errors.Wrap(err, "could not encode graffiti")
+		}
+		return string(b), nil
+	}
+	// #nosec G304
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read graffiti file")
+	}
+	return string(b), nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/urfave/cli/v2"
+)
+
+// slashingProtectionCommand imports and exports the signing history of the validator db in the
+// standard slashing protection interchange format, to move validators between clients safely.
+func slashingProtectionCommand() *cli.Command {
+	interchangeFlags := []cli.Flag{
+		cmd.DataDirFlag,
+		flags.SlashingProtectionFileFlag,
+		flags.GenesisValidatorsRootFlag,
+	}
+	return &cli.Command{
+		Name:     "slashing-protection",
+		Category: "slashing-protection",
+		Usage:    "imports and exports the slashing protection history of the validator database",
+		Subcommands: []*cli.Command{
+			{
+				Name: "import",
+				Description: `records the blocks and attestations of a slashing protection interchange file in the
+validator database, so they are never signed again`,
+				Flags: interchangeFlags,
+				Action: func(cliCtx *cli.Context) error {
+					root, err := genesisValidatorsRoot(cliCtx)
+					if err != nil {
+						return err
+					}
+					// #nosec G304
+					f, err := os.Open(cliCtx.String(flags.SlashingProtectionFileFlag.Name))
+					if err != nil {
+						return errors.Wrap(err, "could not open slashing protection file")
+					}
+					defer func() {
+						if err := f.Close(); err != nil {
+							log.WithError(err).Error("Could not close slashing protection file")
+						}
+					}()
+					valDB, err := db.NewKVStore(cliCtx.String(cmd.DataDirFlag.Name), nil)
+					if err != nil {
+						return errors.Wrap(err, "could not open validator database")
+					}
+					defer func() {
+						if err := valDB.Close(); err != nil {
+							log.WithError(err).Error("Could not close validator database")
+						}
+					}()
+					if err := client.ImportSlashingProtectionInterchange(context.Background(), valDB, root, f); err != nil {
+						return err
+					}
+					log.Info("Imported slashing protection history")
+					return nil
+				},
+			},
+			{
+				Name:        "export",
+				Description: `writes the blocks and attestations of the validator database to a slashing protection interchange file`,
+				Flags:       interchangeFlags,
+				Action: func(cliCtx *cli.Context) error {
+					root, err := genesisValidatorsRoot(cliCtx)
+					if err != nil {
+						return err
+					}
+					valDB, err := db.GetKVStore(cliCtx.String(cmd.DataDirFlag.Name))
+					if err != nil {
+						return errors.Wrap(err, "could not open validator database")
+					}
+					if valDB == nil {
+						return errors.New("no validator database found in data directory")
+					}
+					defer func() {
+						if err := valDB.Close(); err != nil {
+							log.WithError(err).Error("Could not close validator database")
+						}
+					}()
+					path := cliCtx.String(flags.SlashingProtectionFileFlag.Name)
+					if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+						return errors.Wrap(err, "could not create directory of slashing protection file")
+					}
+					f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+					if err != nil {
+						return errors.Wrap(err, "could not create slashing protection file")
+					}
+					if err := client.ExportSlashingProtectionInterchange(context.Background(), valDB, root, f); err != nil {
+						_ = f.Close()
+						return err
+					}
+					if err := f.Close(); err != nil {
+						return errors.Wrap(err, "could not write slashing protection file")
+					}
+					log.WithField("file", path).Info("Exported slashing protection history")
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func genesisValidatorsRoot(cliCtx *cli.Context) ([]byte, error) {
+	if !cliCtx.IsSet(flags.GenesisValidatorsRootFlag.Name) {
+		return nil, errors.New("the genesis validators root of the network is required")
+	}
+	root, err := hex.DecodeString(strings.TrimPrefix(cliCtx.String(flags.GenesisValidatorsRootFlag.Name), "0x"))
+	if err != nil || len(root) != 32 {
+		return nil, errors.New("genesis validators root must be 32 hex encoded bytes")
+	}
+	return root, nil
+}
@@ -45,6 +45,7 @@ var appHelpFlagGroups = []flagGroup{
 		Name: "cmd",
 		Flags: []cli.Flag{
 			cmd.VerbosityFlag,
+			cmd.VModuleFlag,
 			cmd.DataDirFlag,
 			cmd.ClearDB,
 			cmd.ForceClearDB,
@@ -53,6 +54,12 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.TracingEndpointFlag,
 			cmd.TraceSampleFractionFlag,
 			flags.MonitoringPortFlag,
+			cmd.ClientStatsAPIURLFlag,
+			cmd.ClientStatsIntervalFlag,
+			cmd.EnableRoughtimeFlag,
+			cmd.RoughtimeIntervalFlag,
+			cmd.RoughtimeMaxOffsetFlag,
+			cmd.SlotsPerSecondFlag,
 			cmd.LogFormat,
 			cmd.LogFileName,
 			cmd.ConfigFileFlag,
@@ -75,14 +82,21 @@ var appHelpFlagGroups = []flagGroup{
 		Name: "validator",
 		Flags: []cli.Flag{
 			flags.BeaconRPCProviderFlag,
+			flags.BeaconRPCAuthTokenFileFlag,
+			flags.BeaconHTTPProviderFlag,
+			flags.DoppelgangerDetectionEpochsFlag,
 			flags.CertFlag,
+			flags.ClientCertFlag,
+			flags.ClientKeyFlag,
 			flags.KeyManager,
 			flags.KeyManagerOpts,
 			flags.KeystorePathFlag,
 			flags.PasswordFlag,
+			flags.PasswordFileFlag,
 			flags.DisablePenaltyRewardLogFlag,
 			flags.UnencryptedKeysFlag,
 			flags.GraffitiFlag,
+			flags.GraffitiFileFlag,
 			flags.GrpcRetriesFlag,
 			flags.GrpcHeadersFlag,
 			flags.SlasherRPCProviderFlag,
@@ -91,6 +105,10 @@ var appHelpFlagGroups = []flagGroup{
 			flags.SourceDirectory,
 			flags.TargetDirectory,
 			flags.DisableAccountMetricsFlag,
+			flags.AccountMetricsMaxKeysFlag,
+			flags.WebFlag,
+			flags.WebHostFlag,
+			flags.WebPortFlag,
 		},
 	},
 	{
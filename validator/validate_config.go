@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/node"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// validateConfigCommand takes the same flags as the validator client, so a deploy pipeline
+// can check a configuration by inserting the command name into the client's command line.
+func validateConfigCommand(flags []cli.Flag) *cli.Command {
+	return &cli.Command{
+		Name:  "validate-config",
+		Usage: "checks the validator client flags and config files and prints the effective configuration",
+		Description: `loads the flags from the command line, the environment and --config-file, cross-checks them
+as the validator client does at startup, prints the value of every flag and the chain config checksum,
+and exits with a non-zero status if the configuration is invalid`,
+		Flags:  flags,
+		Action: validateConfig,
+	}
+}
+
+func validateConfig(cliCtx *cli.Context) error {
+	if err := cmd.LoadFlagsFromConfigFile(cliCtx, cliCtx.Command.Flags); err != nil {
+		return errors.Wrap(err, "could not load config file")
+	}
+	if err := cmd.LoadSecrets(cliCtx, secretFlags); err != nil {
+		return err
+	}
+	featureconfig.ConfigureValidator(cliCtx)
+	errs := node.ValidateConfig(cliCtx)
+
+	if err := cmd.WriteEffectiveConfig(os.Stdout, cliCtx, cliCtx.Command.Flags); err != nil {
+		return err
+	}
+	chainConfig := params.BeaconConfig()
+	if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+		if c, err := params.ReadChainConfigFile(cliCtx.String(cmd.ChainConfigFileFlag.Name)); err == nil {
+			chainConfig = c
+		}
+	}
+	checksum, err := chainConfig.Checksum()
+	if err != nil {
+		return errors.Wrap(err, "could not compute chain config checksum")
+	}
+	fmt.Printf("\nChain config checksum: %#x\n", checksum)
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			logrus.WithError(err).Error("Invalid configuration")
+		}
+		return fmt.Errorf("found %d configuration errors", len(errs))
+	}
+	logrus.Info("Configuration is valid")
+	return nil
+}
@@ -0,0 +1,106 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of a wallet account, which determines where its secret key is held.
+type Kind string
+
+const (
+	// Derived accounts have their secret key derived from the wallet seed.
+	Derived Kind = "derived"
+	// Imported accounts have their secret key imported from a keystore and stored encrypted
+	// in the wallet.
+	Imported Kind = "imported"
+	// Remote accounts have their secret key held by a remote signer, the wallet only refers
+	// to the account of the signer.
+	Remote Kind = "remote"
+)
+
+// Account is a validator account of a wallet.
+type Account struct {
+	Name      string `json:"name"`
+	Kind      Kind   `json:"kind"`
+	PublicKey string `json:"public_key"`
+	// Path is the EIP-2334 derivation path of derived accounts.
+	Path string `json:"path,omitempty"`
+	// Crypto is the EIP-2335 crypto module of the secret key of imported accounts, encrypted
+	// with the wallet password.
+	Crypto map[string]interface{} `json:"crypto,omitempty"`
+	// RemoteAccount is the name of the account on the remote signer of remote accounts.
+	RemoteAccount string `json:"remote_account,omitempty"`
+	// Metadata are free form labels of the account, such as its owner or deposit status.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// copy returns a copy of the account without its secret material.
+func (a *Account) copy() *Account {
+	c := &Account{
+		Name:          a.Name,
+		Kind:          a.Kind,
+		PublicKey:     a.PublicKey,
+		Path:          a.Path,
+		RemoteAccount: a.RemoteAccount,
+	}
+	if len(a.Metadata) > 0 {
+		c.Metadata = make(map[string]string, len(a.Metadata))
+		for k, v := range a.Metadata {
+			c.Metadata[k] = v
+		}
+	}
+	return c
+}
+
+// WriteAccounts writes the accounts to w, either as a JSON array or one line per account.
+func WriteAccounts(w io.Writer, format string, accounts []*Account) error {
+	switch format {
+	case "json":
+		if accounts == nil {
+			accounts = []*Account{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(accounts)
+	case "text", "":
+		for _, a := range accounts {
+			location := a.Path
+			if a.Kind == Remote {
+				location = a.RemoteAccount
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.Name, a.Kind, a.PublicKey, location, formatMetadata(a.Metadata)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// formatMetadata formats metadata as comma separated key=value pairs sorted by key.
+func formatMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for k, v := range metadata {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ParseMetadata parses key=value pairs into metadata.
+func ParseMetadata(pairs []string) (map[string]string, error) {
+	metadata := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("metadata %q is not of the form key=value", pair)
+		}
+		metadata[parts[0]] = parts[1]
+	}
+	return metadata, nil
+}
@@ -0,0 +1,108 @@
+package wallet
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/logutil"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var log = logrus.WithField("prefix", "wallet")
+
+// HandleWalletFlags returns the wallet directory and password of the flags. The password is
+// read from --password, --password-file or, unless --non-interactive is set, the terminal.
+func HandleWalletFlags(cliCtx *cli.Context, confirmPassword bool) (string, string, error) {
+	dir := cliCtx.String(flags.WalletDirFlag.Name)
+	if dir == "" {
+		return "", "", fmt.Errorf("--%s is required", flags.WalletDirFlag.Name)
+	}
+	password := cliCtx.String(flags.PasswordFlag.Name)
+	if password == "" && cliCtx.String(flags.PasswordFileFlag.Name) != "" {
+		var err error
+		password, err = readSecretFile(cliCtx.String(flags.PasswordFileFlag.Name))
+		if err != nil {
+			return "", "", errors.Wrap(err, "could not read password file")
+		}
+	}
+	if password != "" {
+		return dir, password, nil
+	}
+	if cliCtx.Bool(flags.NonInteractiveFlag.Name) {
+		return "", "", fmt.Errorf(
+			"--%s or --%s is required in non-interactive mode",
+			flags.PasswordFlag.Name,
+			flags.PasswordFileFlag.Name,
+		)
+	}
+	log.Info("Please enter the password of the wallet")
+	password, err := cmd.EnterPassword(confirmPassword, cmd.StdInPasswordReader{})
+	if err != nil {
+		return "", "", errors.Wrap(err, "could not read entered password")
+	}
+	return dir, password, nil
+}
+
+// MnemonicFromFlags returns the mnemonic read from --mnemonic-file or, unless
+// --non-interactive is set, from standard input.
+func MnemonicFromFlags(cliCtx *cli.Context) (string, error) {
+	if file := cliCtx.String(flags.MnemonicFileFlag.Name); file != "" {
+		mnemonic, err := readSecretFile(file)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read mnemonic file")
+		}
+		return mnemonic, nil
+	}
+	if cliCtx.Bool(flags.NonInteractiveFlag.Name) {
+		return "", fmt.Errorf("--%s is required in non-interactive mode", flags.MnemonicFileFlag.Name)
+	}
+	log.Info("Please enter the mnemonic of the wallet")
+	mnemonic, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", errors.Wrap(err, "could not read mnemonic")
+	}
+	mnemonic = strings.TrimSpace(mnemonic)
+	logutil.RegisterSecret(mnemonic)
+	return mnemonic, nil
+}
+
+// KeystorePasswordFromFlags returns the password of the imported or exported keystores read
+// from --keystore-password-file or, unless --non-interactive is set, the terminal.
+func KeystorePasswordFromFlags(cliCtx *cli.Context, confirmPassword bool) (string, error) {
+	if file := cliCtx.String(flags.KeystorePasswordFileFlag.Name); file != "" {
+		password, err := readSecretFile(file)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read keystore password file")
+		}
+		return password, nil
+	}
+	if cliCtx.Bool(flags.NonInteractiveFlag.Name) {
+		return "", fmt.Errorf("--%s is required in non-interactive mode", flags.KeystorePasswordFileFlag.Name)
+	}
+	log.Info("Please enter the password of the keystores")
+	password, err := cmd.EnterPassword(confirmPassword, cmd.StdInPasswordReader{})
+	if err != nil {
+		return "", errors.Wrap(err, "could not read entered password")
+	}
+	return password, nil
+}
+
+// readSecretFile reads a secret from a file, without trailing line breaks, and redacts it
+// from the logs.
+func readSecretFile(file string) (string, error) {
+	// #nosec G304
+	enc, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimRight(string(enc), "\r\n")
+	logutil.RegisterSecret(secret)
+	return secret, nil
+}
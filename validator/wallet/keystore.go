@@ -0,0 +1,185 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+const (
+	// keystoreVersion is the EIP-2335 keystore version.
+	keystoreVersion = 4
+	// keystoreExt is the extension of keystore files, the password files of keystores
+	// imported from a directory have the same name with the passwordExt extension.
+	keystoreExt = ".json"
+	passwordExt = ".txt"
+)
+
+// keystoreFile is the content of an EIP-2335 keystore file.
+type keystoreFile struct {
+	Crypto      map[string]interface{} `json:"crypto"`
+	Description string                 `json:"description,omitempty"`
+	PubKey      string                 `json:"pubkey"`
+	Path        string                 `json:"path"`
+	UUID        string                 `json:"uuid"`
+	Version     uint                   `json:"version"`
+}
+
+// KeystoreImport is an EIP-2335 keystore to import as an account with the name, decrypted
+// with the password.
+type KeystoreImport struct {
+	Name     string
+	Keystore []byte
+	Password string
+}
+
+// ImportKeystores adds accounts whose secret keys are decrypted from EIP-2335 keystores, all
+// with the same metadata. The keys are stored encrypted with the wallet password. Either all
+// keystores are imported or none if any of them fails.
+func (w *Wallet) ImportKeystores(imports []*KeystoreImport, metadata map[string]string) ([]*Account, error) {
+	prev := w.manifest.Accounts
+	w.manifest.Accounts = append(make([]*Account, 0, len(prev)+len(imports)), prev...)
+	accounts := make([]*Account, len(imports))
+	for i, imp := range imports {
+		a, err := w.importedAccount(imp, metadata)
+		if err == nil {
+			err = w.add(a)
+		}
+		if err != nil {
+			w.manifest.Accounts = prev
+			return nil, errors.Wrapf(err, "could not import keystore of account %q", imp.Name)
+		}
+		accounts[i] = a.copy()
+	}
+	if err := w.save(); err != nil {
+		w.manifest.Accounts = prev
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// importedAccount decrypts the keystore, returning the imported account of its secret key.
+func (w *Wallet) importedAccount(imp *KeystoreImport, metadata map[string]string) (*Account, error) {
+	ks := &keystoreFile{}
+	if err := json.Unmarshal(imp.Keystore, ks); err != nil {
+		return nil, errors.Wrap(err, "could not decode keystore")
+	}
+	secret, err := keystorev4.New().Decrypt(ks.Crypto, []byte(imp.Password))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt keystore")
+	}
+	sk, err := bls.SecretKeyFromBytes(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "keystore does not hold a valid secret key")
+	}
+	pubKey := encodePublicKey(sk.PublicKey())
+	if ks.PubKey != "" && "0x"+strings.TrimPrefix(ks.PubKey, "0x") != pubKey {
+		return nil, fmt.Errorf("keystore public key %s does not match its secret key", ks.PubKey)
+	}
+	crypto, err := keystorev4.New().Encrypt(secret, []byte(w.password))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt secret key")
+	}
+	return &Account{
+		Name:      imp.Name,
+		Kind:      Imported,
+		PublicKey: pubKey,
+		Crypto:    crypto,
+		Metadata:  metadata,
+	}, nil
+}
+
+// ExportKeystore returns an EIP-2335 keystore of the secret key of a derived or imported
+// account, encrypted with the keystore password using the "scrypt" or "pbkdf2" key
+// derivation function.
+func (w *Wallet) ExportKeystore(name string, keystorePassword string, kdf string) ([]byte, error) {
+	if kdf != "scrypt" && kdf != "pbkdf2" {
+		return nil, fmt.Errorf("unsupported key derivation function %q", kdf)
+	}
+	if keystorePassword == "" {
+		return nil, errors.New("keystore password must not be empty")
+	}
+	a, err := w.account(name)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := w.SecretKey(name)
+	if err != nil {
+		return nil, err
+	}
+	crypto, err := keystorev4.New(keystorev4.WithCipher(kdf)).Encrypt(sk.Marshal(), []byte(keystorePassword))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt secret key")
+	}
+	return json.MarshalIndent(&keystoreFile{
+		Crypto:      crypto,
+		Description: a.Name,
+		PubKey:      strings.TrimPrefix(a.PublicKey, "0x"),
+		Path:        a.Path,
+		UUID:        uuid.NewRandom().String(),
+		Version:     keystoreVersion,
+	}, "", "  ")
+}
+
+// KeystoreFileName returns the name of the file of an exported keystore of the account.
+func KeystoreFileName(a *Account) string {
+	return "keystore-" + strings.TrimPrefix(a.PublicKey, "0x") + keystoreExt
+}
+
+// ReadKeystoreDir reads the keystore files of a directory to import them as accounts named
+// after the files. The keystores are decrypted with the password of the file of the same name
+// with a .txt extension in the passwords directory if given, otherwise with the shared password.
+func ReadKeystoreDir(dir string, sharedPassword string, passwordsDir string) ([]*KeystoreImport, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read keystore directory")
+	}
+	var imports []*KeystoreImport
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != keystoreExt {
+			continue
+		}
+		// #nosec G304
+		keystore, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read keystore file %s", f.Name())
+		}
+		name := strings.TrimSuffix(f.Name(), keystoreExt)
+		password := sharedPassword
+		if passwordsDir != "" {
+			password, err = readSecretFile(filepath.Join(passwordsDir, name+passwordExt))
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not read password file of keystore file %s", f.Name())
+			}
+		}
+		imports = append(imports, &KeystoreImport{Name: name, Keystore: keystore, Password: password})
+	}
+	if len(imports) == 0 {
+		return nil, fmt.Errorf("no keystore files in %s", dir)
+	}
+	return imports, nil
+}
+
+// WriteKeystore writes an exported keystore to the directory, failing if the file exists.
+func WriteKeystore(dir string, fileName string, keystore []byte) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrap(err, "could not create keystore directory")
+	}
+	f, err := os.OpenFile(filepath.Join(dir, fileName), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return errors.Wrap(err, "could not create keystore file")
+	}
+	if _, err := f.Write(keystore); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "could not write keystore file")
+	}
+	return f.Close()
+}
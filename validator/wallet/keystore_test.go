@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+func TestImportKeystores_AllOrNone(t *testing.T) {
+	w, _, err := Create(tempDir(t), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imports := []*KeystoreImport{
+		{Name: "first", Keystore: keystoreJSON(t, bls.RandKey(), "first password"), Password: "first password"},
+		{Name: "second", Keystore: keystoreJSON(t, bls.RandKey(), "second password"), Password: "wrong password"},
+	}
+	if _, err := w.ImportKeystores(imports, nil); err == nil {
+		t.Fatal("Imported keystore with wrong password")
+	}
+	if len(w.Accounts()) != 0 {
+		t.Errorf("Wanted no accounts imported after a failed import, received %d", len(w.Accounts()))
+	}
+
+	imports[1].Password = "second password"
+	accounts, err := w.ImportKeystores(imports, map[string]string{"source": "teku"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[0].Name != "first" || accounts[1].Metadata["source"] != "teku" {
+		t.Errorf("Unexpected imported accounts %v", accounts)
+	}
+	reopened, err := Open(w.dir, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.Accounts()) != 2 {
+		t.Errorf("Wanted 2 accounts saved, received %d", len(reopened.Accounts()))
+	}
+}
+
+func TestExportKeystore(t *testing.T) {
+	w, _, err := Create(tempDir(t), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived, err := w.DeriveAccount("derived", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.ExportKeystore("derived", "keystore password", "argon2"); err == nil {
+		t.Error("Exported keystore with unsupported key derivation function")
+	}
+
+	for _, kdf := range []string{"pbkdf2", "scrypt"} {
+		keystore, err := w.ExportKeystore("derived", "keystore password", kdf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ks := &keystoreFile{}
+		if err := json.Unmarshal(keystore, ks); err != nil {
+			t.Fatal(err)
+		}
+		if ks.Version != keystoreVersion || ks.Path != derived.Path || ks.UUID == "" || "0x"+ks.PubKey != derived.PublicKey {
+			t.Errorf("Unexpected %s keystore %s", kdf, keystore)
+		}
+		if ks.Crypto["kdf"].(map[string]interface{})["function"] != kdf {
+			t.Errorf("Wanted %s key derivation function, received %v", kdf, ks.Crypto["kdf"])
+		}
+
+		// The keystore is imported with the same secret key by another wallet.
+		other, _, err := Create(tempDir(t), password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		imported, err := other.ImportKeystore("imported", keystore, "keystore password", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if imported.PublicKey != derived.PublicKey {
+			t.Errorf("Imported public key %s, wanted %s", imported.PublicKey, derived.PublicKey)
+		}
+	}
+}
+
+func TestReadKeystoreDir(t *testing.T) {
+	dir := tempDir(t)
+	passwordsDir := filepath.Join(dir, "passwords")
+	if err := os.MkdirAll(passwordsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b", "a"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name+".json"), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(passwordsDir, name+".txt"), []byte(name+" password\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	imports, err := ReadKeystoreDir(dir, "shared password", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imports) != 2 || imports[0].Name != "a" || imports[1].Name != "b" ||
+		!bytes.Equal(imports[0].Keystore, []byte("a")) || imports[1].Password != "shared password" {
+		t.Errorf("Unexpected keystores read with shared password %v", imports)
+	}
+	imports, err = ReadKeystoreDir(dir, "", passwordsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imports[0].Password != "a password" || imports[1].Password != "b password" {
+		t.Errorf("Unexpected keystore passwords %q and %q", imports[0].Password, imports[1].Password)
+	}
+	if _, err := ReadKeystoreDir(passwordsDir, "shared password", ""); err == nil {
+		t.Error("Read keystores from a directory without keystore files")
+	}
+}
@@ -0,0 +1,403 @@
+// Package wallet defines a validator wallet, holding accounts derived from the wallet seed,
+// accounts imported from keystores and references to accounts of a remote signer under one
+// password, with metadata for every account.
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/tyler-smith/go-bip39"
+	util "github.com/wealdtech/go-eth2-util"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+const (
+	// ManifestFileName is the name of the file holding the wallet in the wallet directory.
+	ManifestFileName = "wallet.json"
+	manifestVersion  = 1
+	// signingKeyPath is the EIP-2334 path of the signing key of the validator with an index.
+	signingKeyPath = "m/12381/3600/%d/0/0"
+	// mnemonicEntropyBits is the entropy of new wallet mnemonics, giving 24 words.
+	mnemonicEntropyBits = 256
+)
+
+// RemoteSigner describes the connection to the remote signer holding the keys of the
+// remote accounts of the wallet.
+type RemoteSigner struct {
+	Location   string `json:"location"`
+	CACert     string `json:"ca_cert,omitempty"`
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key"`
+}
+
+// manifest is the content of the wallet file.
+type manifest struct {
+	Version int `json:"version"`
+	// Entropy is the EIP-2335 crypto module of the entropy of the wallet mnemonic, encrypted
+	// with the wallet password. The entropy is stored rather than the seed as EIP-2335 keystores
+	// hold 32 byte secrets.
+	Entropy map[string]interface{} `json:"entropy"`
+	// NextIndex is the validator index of the next derived account.
+	NextIndex    uint64        `json:"next_index"`
+	RemoteSigner *RemoteSigner `json:"remote_signer,omitempty"`
+	Accounts     []*Account    `json:"accounts"`
+}
+
+// Wallet is a validator wallet stored in a directory. A wallet is not safe for concurrent
+// use, and a wallet directory must only be opened by one process at a time.
+type Wallet struct {
+	dir      string
+	password string
+	seed     []byte
+	manifest *manifest
+}
+
+// Create creates a wallet in the directory with a new seed, returning the wallet and the
+// mnemonic of its seed, from which its derived accounts can be recovered.
+func Create(dir string, password string) (*Wallet, string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not generate entropy")
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not generate mnemonic")
+	}
+	w, err := Recover(dir, mnemonic, password)
+	if err != nil {
+		return nil, "", err
+	}
+	return w, mnemonic, nil
+}
+
+// Recover creates a wallet in the directory with the seed of the mnemonic, which must have 24
+// words. Derived accounts of the original wallet are recovered by deriving the same number of
+// accounts again.
+func Recover(dir string, mnemonic string, password string) (*Wallet, error) {
+	if password == "" {
+		return nil, errors.New("wallet password must not be empty")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ManifestFileName)); err == nil {
+		return nil, fmt.Errorf("wallet already exists in %s", dir)
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	entropy, err := bip39.EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mnemonic")
+	}
+	if len(entropy)*8 != mnemonicEntropyBits {
+		return nil, errors.New("mnemonic must have 24 words")
+	}
+	encEntropy, err := keystorev4.New().Encrypt(entropy, []byte(password))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encrypt wallet mnemonic")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create wallet directory")
+	}
+	w := &Wallet{
+		dir:      dir,
+		password: password,
+		seed:     bip39.NewSeed(mnemonic, ""),
+		manifest: &manifest{Version: manifestVersion, Entropy: encEntropy, Accounts: []*Account{}},
+	}
+	if err := w.save(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Open opens the wallet in the directory, failing if the password does not decrypt it.
+func Open(dir string, password string) (*Wallet, error) {
+	// #nosec G304
+	enc, err := ioutil.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read wallet")
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(enc, m); err != nil {
+		return nil, errors.Wrap(err, "could not decode wallet")
+	}
+	if m.Version != manifestVersion {
+		return nil, fmt.Errorf("unsupported wallet version %d", m.Version)
+	}
+	entropy, err := keystorev4.New().Decrypt(m.Entropy, []byte(password))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt wallet mnemonic, is the password correct?")
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode wallet mnemonic")
+	}
+	return &Wallet{dir: dir, password: password, seed: bip39.NewSeed(mnemonic, ""), manifest: m}, nil
+}
+
+// Accounts returns the accounts of the wallet sorted by name.
+func (w *Wallet) Accounts() []*Account {
+	accounts := make([]*Account, len(w.manifest.Accounts))
+	for i, a := range w.manifest.Accounts {
+		accounts[i] = a.copy()
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+	return accounts
+}
+
+// Account returns the account with the name.
+func (w *Wallet) Account(name string) (*Account, error) {
+	a, err := w.account(name)
+	if err != nil {
+		return nil, err
+	}
+	return a.copy(), nil
+}
+
+// DeriveAccount adds an account whose secret key is derived from the wallet seed at the
+// signing key path of the next validator index.
+func (w *Wallet) DeriveAccount(name string, metadata map[string]string) (*Account, error) {
+	a, err := w.derivedAccount(name, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.save(); err != nil {
+		return nil, err
+	}
+	return a.copy(), nil
+}
+
+// DeriveAccounts adds n accounts whose secret keys are derived from the wallet seed at the
+// signing key paths of the next validator indices, as done by the deposit CLI for the same
+// mnemonic. The accounts are named after the prefix and their validator index.
+func (w *Wallet) DeriveAccounts(n int, namePrefix string, metadata map[string]string) ([]*Account, error) {
+	prevAccounts, prevIndex := w.manifest.Accounts, w.manifest.NextIndex
+	w.manifest.Accounts = append(make([]*Account, 0, len(prevAccounts)+n), prevAccounts...)
+	accounts := make([]*Account, n)
+	for i := range accounts {
+		a, err := w.derivedAccount(fmt.Sprintf("%s-%d", namePrefix, w.manifest.NextIndex), metadata)
+		if err != nil {
+			w.manifest.Accounts, w.manifest.NextIndex = prevAccounts, prevIndex
+			return nil, err
+		}
+		accounts[i] = a.copy()
+	}
+	if err := w.save(); err != nil {
+		w.manifest.Accounts, w.manifest.NextIndex = prevAccounts, prevIndex
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// derivedAccount adds the account derived at the signing key path of the next validator index,
+// without saving the wallet.
+func (w *Wallet) derivedAccount(name string, metadata map[string]string) (*Account, error) {
+	path := fmt.Sprintf(signingKeyPath, w.manifest.NextIndex)
+	sk, err := deriveKey(w.seed, path)
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{
+		Name:      name,
+		Kind:      Derived,
+		PublicKey: encodePublicKey(sk.PublicKey()),
+		Path:      path,
+		Metadata:  metadata,
+	}
+	if err := w.add(a); err != nil {
+		return nil, err
+	}
+	w.manifest.NextIndex++
+	return a, nil
+}
+
+// ImportKeystore adds an account whose secret key is decrypted from an EIP-2335 keystore
+// with the keystore password. The key is stored encrypted with the wallet password.
+func (w *Wallet) ImportKeystore(
+	name string,
+	keystore []byte,
+	keystorePassword string,
+	metadata map[string]string,
+) (*Account, error) {
+	accounts, err := w.ImportKeystores([]*KeystoreImport{{
+		Name:     name,
+		Keystore: keystore,
+		Password: keystorePassword,
+	}}, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return accounts[0], nil
+}
+
+// AddRemoteAccount adds a reference to the account of the remote signer with the name and
+// public key. The remote signer of the wallet must be set first.
+func (w *Wallet) AddRemoteAccount(
+	name string,
+	pubKey []byte,
+	remoteAccount string,
+	metadata map[string]string,
+) (*Account, error) {
+	if w.manifest.RemoteSigner == nil {
+		return nil, errors.New("wallet has no remote signer")
+	}
+	if len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+		return nil, fmt.Errorf("public key has %d bytes, wanted %d", len(pubKey), params.BeaconConfig().BLSPubkeyLength)
+	}
+	if remoteAccount == "" {
+		return nil, errors.New("remote account name must not be empty")
+	}
+	a := &Account{
+		Name:          name,
+		Kind:          Remote,
+		PublicKey:     "0x" + hex.EncodeToString(pubKey),
+		RemoteAccount: remoteAccount,
+		Metadata:      metadata,
+	}
+	if err := w.add(a); err != nil {
+		return nil, err
+	}
+	if err := w.save(); err != nil {
+		return nil, err
+	}
+	return a.copy(), nil
+}
+
+// RemoteSigner returns the remote signer of the wallet, nil if it has none.
+func (w *Wallet) RemoteSigner() *RemoteSigner {
+	if w.manifest.RemoteSigner == nil {
+		return nil
+	}
+	rs := *w.manifest.RemoteSigner
+	return &rs
+}
+
+// SetRemoteSigner sets the remote signer of the remote accounts of the wallet.
+func (w *Wallet) SetRemoteSigner(rs *RemoteSigner) error {
+	if rs == nil || rs.Location == "" {
+		return errors.New("remote signer location must not be empty")
+	}
+	if rs.ClientCert == "" || rs.ClientKey == "" {
+		return errors.New("remote signer client certificate and key are required")
+	}
+	c := *rs
+	w.manifest.RemoteSigner = &c
+	return w.save()
+}
+
+// SetMetadata sets the metadata key of the account to the value, removing the key if the
+// value is empty.
+func (w *Wallet) SetMetadata(name string, key string, value string) error {
+	a, err := w.account(name)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return errors.New("metadata key must not be empty")
+	}
+	if value == "" {
+		delete(a.Metadata, key)
+	} else {
+		if a.Metadata == nil {
+			a.Metadata = make(map[string]string)
+		}
+		a.Metadata[key] = value
+	}
+	return w.save()
+}
+
+// DeleteAccount removes the account with the name. The validator index of a deleted derived
+// account is not reused.
+func (w *Wallet) DeleteAccount(name string) error {
+	for i, a := range w.manifest.Accounts {
+		if a.Name == name {
+			w.manifest.Accounts = append(w.manifest.Accounts[:i], w.manifest.Accounts[i+1:]...)
+			return w.save()
+		}
+	}
+	return fmt.Errorf("no account named %q", name)
+}
+
+// SecretKey returns the secret key of a derived or imported account.
+func (w *Wallet) SecretKey(name string) (*bls.SecretKey, error) {
+	a, err := w.account(name)
+	if err != nil {
+		return nil, err
+	}
+	switch a.Kind {
+	case Derived:
+		return deriveKey(w.seed, a.Path)
+	case Imported:
+		secret, err := keystorev4.New().Decrypt(a.Crypto, []byte(w.password))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt secret key of account %q", name)
+		}
+		return bls.SecretKeyFromBytes(secret)
+	default:
+		return nil, fmt.Errorf("secret key of %s account %q is not held by the wallet", a.Kind, name)
+	}
+}
+
+func (w *Wallet) account(name string) (*Account, error) {
+	for _, a := range w.manifest.Accounts {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("no account named %q", name)
+}
+
+// add adds the account, failing if its name or public key is already used.
+func (w *Wallet) add(account *Account) error {
+	if account.Name == "" {
+		return errors.New("account name must not be empty")
+	}
+	for _, a := range w.manifest.Accounts {
+		if a.Name == account.Name {
+			return fmt.Errorf("account named %q already exists", account.Name)
+		}
+		if a.PublicKey == account.PublicKey {
+			return fmt.Errorf("public key %s is already used by account %q", account.PublicKey, a.Name)
+		}
+	}
+	w.manifest.Accounts = append(w.manifest.Accounts, account)
+	return nil
+}
+
+// save writes the wallet file, replacing the previous file only once the new one is
+// completely written.
+func (w *Wallet) save() error {
+	enc, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode wallet")
+	}
+	file := filepath.Join(w.dir, ManifestFileName)
+	if err := ioutil.WriteFile(file+".tmp", enc, 0600); err != nil {
+		return errors.Wrap(err, "could not write wallet")
+	}
+	if err := os.Rename(file+".tmp", file); err != nil {
+		return errors.Wrap(err, "could not write wallet")
+	}
+	return nil
+}
+
+func deriveKey(seed []byte, path string) (*bls.SecretKey, error) {
+	key, err := util.PrivateKeyFromSeedAndPath(seed, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not derive key at path %s", path)
+	}
+	return bls.SecretKeyFromBytes(key.Marshal())
+}
+
+func encodePublicKey(pubKey *bls.PublicKey) string {
+	return "0x" + hex.EncodeToString(pubKey.Marshal())
+}
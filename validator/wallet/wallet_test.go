@@ -0,0 +1,277 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+const password = "wallet password"
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	})
+	return filepath.Join(dir, "wallet")
+}
+
+func keystoreJSON(t *testing.T, sk *bls.SecretKey, keystorePassword string) []byte {
+	crypto, err := keystorev4.New().Encrypt(sk.Marshal(), []byte(keystorePassword))
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := json.Marshal(map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  hex.EncodeToString(sk.PublicKey().Marshal()),
+		"version": 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+func TestCreateOpen(t *testing.T) {
+	dir := tempDir(t)
+	_, mnemonic, err := Create(dir, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := len(strings.Fields(mnemonic)); words != 24 {
+		t.Errorf("Wanted mnemonic of 24 words, received %d", words)
+	}
+	if _, _, err := Create(dir, password); err == nil {
+		t.Error("Created wallet over existing wallet")
+	}
+	if _, err := Open(dir, "wrong password"); err == nil {
+		t.Error("Opened wallet with wrong password")
+	}
+	if _, err := Open(dir, password); err != nil {
+		t.Errorf("Could not open wallet: %v", err)
+	}
+}
+
+func TestRecover_DerivesSameAccounts(t *testing.T) {
+	w, mnemonic, err := Create(tempDir(t), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := Recover(tempDir(t), mnemonic, "other password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		name := fmt.Sprintf("validator-%d", i)
+		a, err := w.DeriveAccount(name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := recovered.DeriveAccount(name, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.PublicKey != r.PublicKey {
+			t.Errorf("Account %d of recovered wallet has public key %s, wanted %s", i, r.PublicKey, a.PublicKey)
+		}
+		if want := fmt.Sprintf("m/12381/3600/%d/0/0", i); a.Path != want {
+			t.Errorf("Wanted path %s, received %s", want, a.Path)
+		}
+		sk, err := w.SecretKey(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pubKey := "0x" + hex.EncodeToString(sk.PublicKey().Marshal()); pubKey != a.PublicKey {
+			t.Errorf("Secret key of account %d has public key %s, wanted %s", i, pubKey, a.PublicKey)
+		}
+	}
+	if _, err := Recover(tempDir(t), "not a mnemonic", password); err == nil {
+		t.Error("Recovered wallet from invalid mnemonic")
+	}
+}
+
+func TestDeriveAccounts(t *testing.T) {
+	w, _, err := Create(tempDir(t), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.DeriveAccount("validator-2", nil); err != nil {
+		t.Fatal(err)
+	}
+	// The account derived at index 2 would be named like the existing account, so none is derived.
+	if _, err := w.DeriveAccounts(3, "validator", nil); err == nil {
+		t.Fatal("Derived accounts with existing name")
+	}
+	if len(w.Accounts()) != 1 {
+		t.Errorf("Wanted no accounts derived after a failed derivation, received %d", len(w.Accounts()))
+	}
+
+	accounts, err := w.DeriveAccounts(2, "node", map[string]string{"node": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, a := range accounts {
+		if want := fmt.Sprintf("node-%d", i+1); a.Name != want {
+			t.Errorf("Wanted account named %s, received %s", want, a.Name)
+		}
+		if want := fmt.Sprintf("m/12381/3600/%d/0/0", i+1); a.Path != want {
+			t.Errorf("Wanted path %s, received %s", want, a.Path)
+		}
+	}
+	reopened, err := Open(w.dir, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.Accounts()) != 3 || reopened.manifest.NextIndex != 3 {
+		t.Errorf("Wanted 3 accounts saved, received %d up to index %d", len(reopened.Accounts()), reopened.manifest.NextIndex)
+	}
+}
+
+func TestImportKeystore(t *testing.T) {
+	w, _, err := Create(tempDir(t), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk := bls.RandKey()
+	ks := keystoreJSON(t, sk, "keystore password")
+
+	if _, err := w.ImportKeystore("imported", ks, "wrong password", nil); err == nil {
+		t.Error("Imported keystore with wrong password")
+	}
+	a, err := w.ImportKeystore("imported", ks, "keystore password", map[string]string{"source": "launchpad"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Crypto != nil {
+		t.Error("Returned account holds secret key")
+	}
+	if _, err := w.ImportKeystore("again", ks, "keystore password", nil); err == nil {
+		t.Error("Imported the same key twice")
+	}
+
+	// The key is encrypted with the wallet password.
+	reopened, err := Open(w.dir, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := reopened.SecretKey("imported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(imported.Marshal(), sk.Marshal()) {
+		t.Error("Imported secret key differs from keystore secret key")
+	}
+}
+
+func TestAddRemoteAccount(t *testing.T) {
+	w, _, err := Create(tempDir(t), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := bls.RandKey().PublicKey().Marshal()
+	if _, err := w.AddRemoteAccount("remote", pubKey, "Validators/1", nil); err == nil {
+		t.Error("Added remote account without remote signer")
+	}
+	rs := &RemoteSigner{Location: "localhost:12345", ClientCert: "client.crt", ClientKey: "client.key"}
+	if err := w.SetRemoteSigner(rs); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.AddRemoteAccount("remote", pubKey[:47], "Validators/1", nil); err == nil {
+		t.Error("Added remote account with invalid public key")
+	}
+	if _, err := w.AddRemoteAccount("remote", pubKey, "Validators/1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.SecretKey("remote"); err == nil {
+		t.Error("Returned secret key of remote account")
+	}
+
+	reopened, err := Open(w.dir, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rs, reopened.RemoteSigner()) {
+		t.Errorf("Wanted remote signer %v, received %v", rs, reopened.RemoteSigner())
+	}
+}
+
+func TestAccounts_ListingAndMetadata(t *testing.T) {
+	w, _, err := Create(tempDir(t), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.DeriveAccount("b", map[string]string{"owner": "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.ImportKeystore("a", keystoreJSON(t, bls.RandKey(), "pw"), "pw", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.DeriveAccount("a", nil); err == nil {
+		t.Error("Added account with existing name")
+	}
+	if err := w.SetMetadata("a", "status", "deposited"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetMetadata("b", "owner", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SetMetadata("c", "owner", "bob"); err == nil {
+		t.Error("Set metadata of unknown account")
+	}
+
+	reopened, err := Open(w.dir, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := reopened.Accounts()
+	if len(accounts) != 2 || accounts[0].Name != "a" || accounts[1].Name != "b" {
+		t.Fatalf("Wanted accounts a and b, received %v", accounts)
+	}
+	if accounts[0].Kind != Imported || accounts[1].Kind != Derived {
+		t.Errorf("Wanted imported and derived account, received %s and %s", accounts[0].Kind, accounts[1].Kind)
+	}
+	if !reflect.DeepEqual(accounts[0].Metadata, map[string]string{"status": "deposited"}) {
+		t.Errorf("Unexpected metadata %v", accounts[0].Metadata)
+	}
+	if len(accounts[1].Metadata) != 0 {
+		t.Errorf("Unexpected metadata %v", accounts[1].Metadata)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteAccounts(buf, "json", accounts); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "crypto") {
+		t.Error("Listing contains encrypted secret key")
+	}
+
+	if err := reopened.DeleteAccount("b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.Account("b"); err == nil {
+		t.Error("Deleted account still exists")
+	}
+	// The index of the deleted account is not reused.
+	c, err := reopened.DeriveAccount("c", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Path != "m/12381/3600/1/0/0" {
+		t.Errorf("Wanted path of the next index, received %s", c.Path)
+	}
+}
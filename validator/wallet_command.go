@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/prysmaticlabs/prysm/validator/wallet"
+	"github.com/urfave/cli/v2"
+)
+
+// walletCommand manages the accounts of a validator wallet, which are used by the validator
+// client with --keymanager=unified.
+func walletCommand() *cli.Command {
+	passwordFlags := []cli.Flag{
+		flags.WalletDirFlag,
+		flags.PasswordFlag,
+		flags.PasswordFileFlag,
+		flags.NonInteractiveFlag,
+	}
+	return &cli.Command{
+		Name:     "wallet",
+		Category: "wallet",
+		Usage:    "manages the derived, imported and remote accounts of a validator wallet",
+		Subcommands: []*cli.Command{
+			{
+				Name: "create",
+				Description: `creates a wallet with a new seed and prints the mnemonic of the seed, which must be
+written down to recover the derived accounts of the wallet`,
+				Flags: passwordFlags,
+				Action: func(cliCtx *cli.Context) error {
+					dir, password, err := wallet.HandleWalletFlags(cliCtx, true /*confirmPassword*/)
+					if err != nil {
+						return err
+					}
+					_, mnemonic, err := wallet.Create(dir, password)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Write down the mnemonic of the wallet and keep it safe:\n\n%s\n\n", mnemonic)
+					return nil
+				},
+			},
+			{
+				Name: "recover",
+				Description: `creates a wallet with the seed of a mnemonic, such as the mnemonic of the deposit CLI,
+deriving the given number of accounts`,
+				Flags: append(passwordFlags,
+					flags.MnemonicFileFlag,
+					flags.NumAccountsFlag,
+					flags.AccountNameFlag,
+					flags.AccountMetadataFlag,
+					flags.OutputFormatFlag,
+				),
+				Action: func(cliCtx *cli.Context) error {
+					mnemonic, err := wallet.MnemonicFromFlags(cliCtx)
+					if err != nil {
+						return err
+					}
+					metadata, err := wallet.ParseMetadata(cliCtx.StringSlice(flags.AccountMetadataFlag.Name))
+					if err != nil {
+						return err
+					}
+					dir, password, err := wallet.HandleWalletFlags(cliCtx, true /*confirmPassword*/)
+					if err != nil {
+						return err
+					}
+					w, err := wallet.Recover(dir, mnemonic, password)
+					if err != nil {
+						return err
+					}
+					n := cliCtx.Int(flags.NumAccountsFlag.Name)
+					if n <= 0 {
+						return nil
+					}
+					accounts, err := w.DeriveAccounts(n, accountNamePrefix(cliCtx), metadata)
+					if err != nil {
+						return err
+					}
+					return wallet.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), accounts)
+				},
+			},
+			{
+				Name: "derive",
+				Description: `adds an account whose key is derived from the wallet seed, or the given number of
+accounts named after the account name and their validator index`,
+				Flags: append(passwordFlags,
+					flags.AccountNameFlag,
+					flags.AccountMetadataFlag,
+					flags.OutputFormatFlag,
+					flags.NumAccountsFlag,
+				),
+				Action: func(cliCtx *cli.Context) error {
+					if n := cliCtx.Int(flags.NumAccountsFlag.Name); n > 1 {
+						metadata, err := wallet.ParseMetadata(cliCtx.StringSlice(flags.AccountMetadataFlag.Name))
+						if err != nil {
+							return err
+						}
+						w, err := openWallet(cliCtx)
+						if err != nil {
+							return err
+						}
+						accounts, err := w.DeriveAccounts(n, accountNamePrefix(cliCtx), metadata)
+						if err != nil {
+							return err
+						}
+						return wallet.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), accounts)
+					}
+					return addAccount(cliCtx, func(w *wallet.Wallet, name string, metadata map[string]string) (*wallet.Account, error) {
+						return w.DeriveAccount(name, metadata)
+					})
+				},
+			},
+			{
+				Name: "import",
+				Description: `adds an account whose key is imported from an EIP-2335 keystore, or an account for
+each keystore file of a directory, named after the file - the keystores of a directory are decrypted with the
+keystore password or with their own password file`,
+				Flags: append(passwordFlags,
+					flags.AccountNameFlag,
+					flags.AccountMetadataFlag,
+					flags.OutputFormatFlag,
+					flags.KeystoreFileFlag,
+					flags.KeystoreDirFlag,
+					flags.KeystorePasswordFileFlag,
+					flags.KeystorePasswordsDirFlag,
+				),
+				Action: func(cliCtx *cli.Context) error {
+					if dir := cliCtx.String(flags.KeystoreDirFlag.Name); dir != "" {
+						return importKeystoreDir(cliCtx, dir)
+					}
+					// #nosec G304
+					keystore, err := ioutil.ReadFile(cliCtx.String(flags.KeystoreFileFlag.Name))
+					if err != nil {
+						return errors.Wrap(err, "could not read keystore file")
+					}
+					keystorePassword, err := wallet.KeystorePasswordFromFlags(cliCtx, false /*confirmPassword*/)
+					if err != nil {
+						return err
+					}
+					return addAccount(cliCtx, func(w *wallet.Wallet, name string, metadata map[string]string) (*wallet.Account, error) {
+						return w.ImportKeystore(name, keystore, keystorePassword, metadata)
+					})
+				},
+			},
+			{
+				Name: "export",
+				Description: `writes EIP-2335 keystores of the secret key of the named account, or of all derived and
+imported accounts, to a directory`,
+				Flags: append(passwordFlags,
+					flags.AccountNameFlag,
+					flags.KeystoreDirFlag,
+					flags.KeystorePasswordFileFlag,
+					flags.KeystoreKDFFlag,
+				),
+				Action: func(cliCtx *cli.Context) error {
+					dir := cliCtx.String(flags.KeystoreDirFlag.Name)
+					if dir == "" {
+						return fmt.Errorf("--%s is required", flags.KeystoreDirFlag.Name)
+					}
+					w, err := openWallet(cliCtx)
+					if err != nil {
+						return err
+					}
+					var accounts []*wallet.Account
+					if name := cliCtx.String(flags.AccountNameFlag.Name); name != "" {
+						a, err := w.Account(name)
+						if err != nil {
+							return err
+						}
+						accounts = append(accounts, a)
+					} else {
+						for _, a := range w.Accounts() {
+							if a.Kind != wallet.Remote {
+								accounts = append(accounts, a)
+							}
+						}
+					}
+					keystorePassword, err := wallet.KeystorePasswordFromFlags(cliCtx, true /*confirmPassword*/)
+					if err != nil {
+						return err
+					}
+					for _, a := range accounts {
+						keystore, err := w.ExportKeystore(a.Name, keystorePassword, cliCtx.String(flags.KeystoreKDFFlag.Name))
+						if err != nil {
+							return err
+						}
+						fileName := wallet.KeystoreFileName(a)
+						if err := wallet.WriteKeystore(dir, fileName, keystore); err != nil {
+							return err
+						}
+						fmt.Println(filepath.Join(dir, fileName))
+					}
+					return nil
+				},
+			},
+			{
+				Name: "add-remote",
+				Description: `adds a reference to an account of the remote signer, setting the remote signer of the
+wallet if the remote signer flags are given`,
+				Flags: append(passwordFlags,
+					flags.AccountNameFlag,
+					flags.AccountMetadataFlag,
+					flags.OutputFormatFlag,
+					flags.PublicKeysFlag,
+					flags.RemoteAccountFlag,
+					flags.RemoteSignerFlag,
+					flags.RemoteSignerCACertFlag,
+					flags.RemoteSignerClientCertFlag,
+					flags.RemoteSignerClientKeyFlag,
+				),
+				Action: func(cliCtx *cli.Context) error {
+					pubKey, err := hex.DecodeString(strings.TrimPrefix(cliCtx.String(flags.PublicKeysFlag.Name), "0x"))
+					if err != nil {
+						return errors.Wrap(err, "could not decode public key")
+					}
+					return addAccount(cliCtx, func(w *wallet.Wallet, name string, metadata map[string]string) (*wallet.Account, error) {
+						if location := cliCtx.String(flags.RemoteSignerFlag.Name); location != "" {
+							if err := w.SetRemoteSigner(&wallet.RemoteSigner{
+								Location:   location,
+								CACert:     cliCtx.String(flags.RemoteSignerCACertFlag.Name),
+								ClientCert: cliCtx.String(flags.RemoteSignerClientCertFlag.Name),
+								ClientKey:  cliCtx.String(flags.RemoteSignerClientKeyFlag.Name),
+							}); err != nil {
+								return nil, err
+							}
+						}
+						return w.AddRemoteAccount(name, pubKey, cliCtx.String(flags.RemoteAccountFlag.Name), metadata)
+					})
+				},
+			},
+			{
+				Name:        "set-metadata",
+				Description: `sets metadata of an account, removing the keys given with an empty value`,
+				Flags: append(passwordFlags,
+					flags.AccountNameFlag,
+					flags.AccountMetadataFlag,
+					flags.OutputFormatFlag,
+				),
+				Action: func(cliCtx *cli.Context) error {
+					metadata, err := wallet.ParseMetadata(cliCtx.StringSlice(flags.AccountMetadataFlag.Name))
+					if err != nil {
+						return err
+					}
+					w, err := openWallet(cliCtx)
+					if err != nil {
+						return err
+					}
+					name := cliCtx.String(flags.AccountNameFlag.Name)
+					for k, v := range metadata {
+						if err := w.SetMetadata(name, k, v); err != nil {
+							return err
+						}
+					}
+					a, err := w.Account(name)
+					if err != nil {
+						return err
+					}
+					return wallet.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), []*wallet.Account{a})
+				},
+			},
+			{
+				Name:        "delete",
+				Description: `removes an account from the wallet`,
+				Flags:       append(passwordFlags, flags.AccountNameFlag),
+				Action: func(cliCtx *cli.Context) error {
+					w, err := openWallet(cliCtx)
+					if err != nil {
+						return err
+					}
+					return w.DeleteAccount(cliCtx.String(flags.AccountNameFlag.Name))
+				},
+			},
+			{
+				Name:        "list",
+				Description: `lists the name, kind, public key, derivation path or remote account and metadata of the wallet accounts`,
+				Flags:       append(passwordFlags, flags.OutputFormatFlag),
+				Action: func(cliCtx *cli.Context) error {
+					w, err := openWallet(cliCtx)
+					if err != nil {
+						return err
+					}
+					return wallet.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), w.Accounts())
+				},
+			},
+		},
+	}
+}
+
+func openWallet(cliCtx *cli.Context) (*wallet.Wallet, error) {
+	dir, password, err := wallet.HandleWalletFlags(cliCtx, false /*confirmPassword*/)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.Open(dir, password)
+}
+
+// addAccount adds an account with the name and metadata of the flags to the wallet and writes it.
+func addAccount(
+	cliCtx *cli.Context,
+	add func(w *wallet.Wallet, name string, metadata map[string]string) (*wallet.Account, error),
+) error {
+	metadata, err := wallet.ParseMetadata(cliCtx.StringSlice(flags.AccountMetadataFlag.Name))
+	if err != nil {
+		return err
+	}
+	w, err := openWallet(cliCtx)
+	if err != nil {
+		return err
+	}
+	a, err := add(w, cliCtx.String(flags.AccountNameFlag.Name), metadata)
+	if err != nil {
+		return err
+	}
+	return wallet.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), []*wallet.Account{a})
+}
+
+// accountNamePrefix returns the prefix of the names of derived accounts, which defaults to
+// "validator".
+func accountNamePrefix(cliCtx *cli.Context) string {
+	if name := cliCtx.String(flags.AccountNameFlag.Name); name != "" {
+		return name
+	}
+	return "validator"
+}
+
+// importKeystoreDir imports the keystore files of the directory as accounts named after the files.
+func importKeystoreDir(cliCtx *cli.Context, dir string) error {
+	if cliCtx.String(flags.AccountNameFlag.Name) != "" {
+		return fmt.Errorf("--%s cannot be used with --%s", flags.AccountNameFlag.Name, flags.KeystoreDirFlag.Name)
+	}
+	metadata, err := wallet.ParseMetadata(cliCtx.StringSlice(flags.AccountMetadataFlag.Name))
+	if err != nil {
+		return err
+	}
+	var sharedPassword string
+	passwordsDir := cliCtx.String(flags.KeystorePasswordsDirFlag.Name)
+	if passwordsDir == "" {
+		sharedPassword, err = wallet.KeystorePasswordFromFlags(cliCtx, false /*confirmPassword*/)
+		if err != nil {
+			return err
+		}
+	}
+	imports, err := wallet.ReadKeystoreDir(dir, sharedPassword, passwordsDir)
+	if err != nil {
+		return err
+	}
+	w, err := openWallet(cliCtx)
+	if err != nil {
+		return err
+	}
+	accounts, err := w.ImportKeystores(imports, metadata)
+	if err != nil {
+		return err
+	}
+	return wallet.WriteAccounts(os.Stdout, cliCtx.String(flags.OutputFormatFlag.Name), accounts)
+}
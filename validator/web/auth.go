@@ -0,0 +1,88 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SecretFileName is the name of the file in the data directory holding the secret with which
+// the tokens of the web API are signed.
+const SecretFileName = "web-auth-secret"
+
+const bearerPrefix = "Bearer "
+
+// jwtHeader is the encoded header of the HS256 JSON web tokens of the API.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// claims are the claims of the tokens of the API.
+type claims struct {
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// IssueToken returns a JSON web token for the API signed with the secret, valid for the given
+// duration from now.
+func IssueToken(secret string, validFor time.Duration) (string, error) {
+	now := time.Now()
+	payload, err := json.Marshal(&claims{IssuedAt: now.Unix(), ExpiresAt: now.Add(validFor).Unix()})
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode token claims")
+	}
+	signed := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign(secret, signed)), nil
+}
+
+// verifyToken checks the signature and expiry of a token of the API.
+func verifyToken(secret string, token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return errors.New("malformed token")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, sign(secret, parts[0]+"."+parts[1])) {
+		return errors.New("invalid token signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errors.New("malformed token")
+	}
+	c := &claims{}
+	if err := json.Unmarshal(payload, c); err != nil {
+		return errors.New("malformed token")
+	}
+	if time.Now().Unix() >= c.ExpiresAt {
+		return errors.New("token expired")
+	}
+	return nil
+}
+
+func sign(secret string, signed string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	// Writing to a hash never fails.
+	_, _ = mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
+
+// requireToken wraps an HTTP handler to reject requests without a valid token as bearer token
+// in their Authorization header.
+func requireToken(secret string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, bearerPrefix) {
+			writeError(w, http.StatusUnauthorized, "missing authorization token")
+			return
+		}
+		if err := verifyToken(secret, strings.TrimPrefix(auth, bearerPrefix)); err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,32 @@
+package web
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueToken(t *testing.T) {
+	token, err := IssueToken("secret", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyToken("secret", token); err != nil {
+		t.Errorf("Could not verify issued token: %v", err)
+	}
+	if err := verifyToken("other secret", token); err == nil {
+		t.Error("Verified token signed with another secret")
+	}
+	parts := strings.Split(token, ".")
+	if err := verifyToken("secret", parts[0]+"."+parts[1]+"x."+parts[2]); err == nil {
+		t.Error("Verified token with modified claims")
+	}
+
+	expired, err := IssueToken("secret", -time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyToken("secret", expired); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected expired token, received %v", err)
+	}
+}
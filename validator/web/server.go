@@ -0,0 +1,337 @@
+// Package web serves a local HTTP API and web UI managing a running validator client: listing
+// and importing the accounts of its wallet, setting the graffiti and exiting validators. Requests
+// to the API are authenticated with JSON web tokens issued by the 'validator web auth' command.
+package web
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/wallet"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+var log = logrus.WithField("prefix", "web")
+
+// Validator is the validator client managed through the API.
+type Validator interface {
+	Graffiti() *client.Graffiti
+	SetGraffiti(graffiti *client.Graffiti)
+	ProposeExits(ctx context.Context, pubKeys [][48]byte) error
+}
+
+// Config of the web API.
+type Config struct {
+	Host string
+	Port int
+	// Secret signs the tokens authenticating requests.
+	Secret string
+	// OpenWallet opens the wallet of the key manager, nil if the key manager does not use a
+	// wallet, in which case the accounts cannot be listed nor imported.
+	OpenWallet func() (*wallet.Wallet, error)
+	KeyManager keymanager.KeyManager
+	Validator  Validator
+}
+
+// Server is the service serving the web API and UI.
+type Server struct {
+	cfg        *Config
+	server     *http.Server
+	failStatus error
+	// walletLock serializes the access to the wallet, which is not safe for concurrent use.
+	walletLock sync.Mutex
+}
+
+// NewServer creates the web API server.
+func NewServer(cfg *Config) *Server {
+	s := &Server{cfg: cfg}
+	s.server = &http.Server{Addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port), Handler: s.Handler()}
+	return s
+}
+
+// Handler returns the handler of the routes of the API and the UI.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.index)
+	for path, methods := range map[string]map[string]http.HandlerFunc{
+		"/api/v1/accounts":        {http.MethodGet: s.listAccounts},
+		"/api/v1/accounts/import": {http.MethodPost: s.importAccounts},
+		"/api/v1/accounts/exit":   {http.MethodPost: s.exitAccounts},
+		"/api/v1/graffiti":        {http.MethodGet: s.graffiti, http.MethodPut: s.setGraffiti},
+	} {
+		methods := methods
+		mux.HandleFunc(path, requireToken(s.cfg.Secret, func(w http.ResponseWriter, r *http.Request) {
+			handler, ok := methods[r.Method]
+			if !ok {
+				writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+				return
+			}
+			handler(w, r)
+		}))
+	}
+	return mux
+}
+
+// Start serving the API.
+func (s *Server) Start() {
+	go func() {
+		log.WithField("address", s.server.Addr).Info("Starting web API and UI")
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("Could not serve web API")
+			s.failStatus = err
+		}
+	}()
+}
+
+// Stop the server, waiting for up to a second for requests to complete.
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// Status returns an error if the server failed to listen.
+func (s *Server) Status() error {
+	return s.failStatus
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write([]byte(indexHTML)); err != nil {
+		log.WithError(err).Debug("Could not write web UI")
+	}
+}
+
+// accountJSON is an account of the wallet, active if the validator client validates with it.
+// Accounts imported while the validator client runs are active after a restart.
+type accountJSON struct {
+	Name      string            `json:"name"`
+	Kind      wallet.Kind       `json:"kind"`
+	PublicKey string            `json:"public_key"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Active    bool              `json:"active"`
+}
+
+func (s *Server) listAccounts(w http.ResponseWriter, _ *http.Request) {
+	var accounts []*wallet.Account
+	err := s.withWallet(func(wlt *wallet.Wallet) error {
+		accounts = wlt.Accounts()
+		return nil
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.accountsJSON(accounts))
+}
+
+// importRequest are EIP-2335 keystores to import as accounts of the wallet, all with the same
+// metadata.
+type importRequest struct {
+	Keystores []struct {
+		Name     string          `json:"name"`
+		Keystore json.RawMessage `json:"keystore"`
+		Password string          `json:"password"`
+	} `json:"keystores"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// importAccounts imports keystores into the wallet, either all of them or none. The validator
+// client validates with the imported accounts once it is restarted.
+func (s *Server) importAccounts(w http.ResponseWriter, r *http.Request) {
+	req := &importRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || len(req.Keystores) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid import request: %v", err))
+		return
+	}
+	imports := make([]*wallet.KeystoreImport, len(req.Keystores))
+	for i, k := range req.Keystores {
+		keystore := []byte(k.Keystore)
+		// The keystore may be given as the JSON object of the keystore file or as its content.
+		var content string
+		if err := json.Unmarshal(keystore, &content); err == nil {
+			keystore = []byte(content)
+		}
+		imports[i] = &wallet.KeystoreImport{Name: k.Name, Keystore: keystore, Password: k.Password}
+	}
+	var accounts []*wallet.Account
+	err := s.withWallet(func(wlt *wallet.Wallet) error {
+		var err error
+		accounts, err = wlt.ImportKeystores(imports, req.Metadata)
+		if err != nil {
+			// Keystores fail to import for their content or password.
+			return &httpError{code: http.StatusBadRequest, message: err.Error()}
+		}
+		return nil
+	})
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	log.WithField("accounts", len(accounts)).Info("Imported accounts, restart the validator client to validate with them")
+	writeJSON(w, http.StatusOK, s.accountsJSON(accounts))
+}
+
+// exitRequest are the public keys of the validators to exit.
+type exitRequest struct {
+	PublicKeys []string `json:"public_keys"`
+}
+
+// exitAccounts proposes voluntary exits of validators of the validator client. Exits cannot be
+// undone.
+func (s *Server) exitAccounts(w http.ResponseWriter, r *http.Request) {
+	req := &exitRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || len(req.PublicKeys) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid exit request: %v", err))
+		return
+	}
+	active, err := s.activeKeys()
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	pubKeys := make([][48]byte, len(req.PublicKeys))
+	for i, k := range req.PublicKeys {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(k, "0x"))
+		if err != nil || len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid public key %q", k))
+			return
+		}
+		pubKeys[i] = bytesutil.ToBytes48(pubKey)
+		if !active[pubKeys[i]] {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("validator %s is not validated with by the validator client", k))
+			return
+		}
+	}
+	if err := s.cfg.Validator.ProposeExits(r.Context(), pubKeys); err != nil {
+		writeErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// graffitiJSON is the graffiti configuration, in the format of --graffiti-file.
+type graffitiJSON struct {
+	Graffiti string `json:"graffiti"`
+}
+
+func (s *Server) graffiti(w http.ResponseWriter, _ *http.Request) {
+	var config string
+	if g := s.cfg.Validator.Graffiti(); g != nil {
+		b, err := yaml.Marshal(g)
+		if err != nil {
+			writeErr(w, errors.Wrap(err, "could not encode graffiti"))
+			return
+		}
+		config = string(b)
+	}
+	writeJSON(w, http.StatusOK, &graffitiJSON{Graffiti: config})
+}
+
+// setGraffiti changes the graffiti of the blocks proposed from now on, until the graffiti flags
+// are reloaded.
+func (s *Server) setGraffiti(w http.ResponseWriter, r *http.Request) {
+	req := &graffitiJSON{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid graffiti request: %v", err))
+		return
+	}
+	g, err := client.ParseGraffiti(req.Graffiti)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.cfg.Validator.SetGraffiti(g)
+	log.Info("Changed graffiti")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) withWallet(f func(*wallet.Wallet) error) error {
+	if s.cfg.OpenWallet == nil {
+		return &httpError{code: http.StatusNotFound, message: "the key manager has no wallet, use --keymanager=unified"}
+	}
+	s.walletLock.Lock()
+	defer s.walletLock.Unlock()
+	wlt, err := s.cfg.OpenWallet()
+	if err != nil {
+		return err
+	}
+	return f(wlt)
+}
+
+func (s *Server) accountsJSON(accounts []*wallet.Account) []*accountJSON {
+	active, err := s.activeKeys()
+	if err != nil {
+		log.WithError(err).Error("Could not fetch validating keys")
+	}
+	res := make([]*accountJSON, len(accounts))
+	for i, a := range accounts {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(a.PublicKey, "0x"))
+		res[i] = &accountJSON{
+			Name:      a.Name,
+			Kind:      a.Kind,
+			PublicKey: a.PublicKey,
+			Metadata:  a.Metadata,
+			Active:    err == nil && active[bytesutil.ToBytes48(pubKey)],
+		}
+	}
+	return res
+}
+
+func (s *Server) activeKeys() (map[[48]byte]bool, error) {
+	keys, err := s.cfg.KeyManager.FetchValidatingKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch validating keys")
+	}
+	active := make(map[[48]byte]bool, len(keys))
+	for _, k := range keys {
+		active[k] = true
+	}
+	return active, nil
+}
+
+// httpError is an error with the HTTP status it is served with.
+type httpError struct {
+	code    int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func writeErr(w http.ResponseWriter, err error) {
+	if e, ok := err.(*httpError); ok {
+		writeError(w, e.code, e.message)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]interface{}{"code": code, "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
@@ -0,0 +1,206 @@
+package web
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/wallet"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+const secret = "secret"
+
+type fakeValidator struct {
+	graffiti *client.Graffiti
+	exited   [][48]byte
+}
+
+func (v *fakeValidator) Graffiti() *client.Graffiti {
+	return v.graffiti
+}
+
+func (v *fakeValidator) SetGraffiti(graffiti *client.Graffiti) {
+	v.graffiti = graffiti
+}
+
+func (v *fakeValidator) ProposeExits(_ context.Context, pubKeys [][48]byte) error {
+	v.exited = append(v.exited, pubKeys...)
+	return nil
+}
+
+func serve(t *testing.T, s *Server, method string, path string, body string) *httptest.ResponseRecorder {
+	token, err := IssueToken(secret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func testWallet(t *testing.T) func() (*wallet.Wallet, error) {
+	dir, err := ioutil.TempDir("", "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	})
+	dir = filepath.Join(dir, "wallet")
+	if _, _, err := wallet.Create(dir, "password"); err != nil {
+		t.Fatal(err)
+	}
+	return func() (*wallet.Wallet, error) {
+		return wallet.Open(dir, "password")
+	}
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	s := NewServer(&Config{Secret: secret, Validator: &fakeValidator{}})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/graffiti", nil)
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Wanted 401 without token, received %d", rec.Code)
+	}
+
+	token, err := IssueToken("another secret", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Wanted 401 with token of another secret, received %d", rec.Code)
+	}
+
+	// The UI asks for the token itself.
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<html>") {
+		t.Errorf("Wanted web UI, received %d", rec.Code)
+	}
+}
+
+func TestServer_Graffiti(t *testing.T) {
+	v := &fakeValidator{}
+	s := NewServer(&Config{Secret: secret, Validator: v})
+
+	rec := serve(t, s, http.MethodPut, "/api/v1/graffiti", `{"graffiti": "default: Prysm\nmode: random\n"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	if v.graffiti == nil || v.graffiti.Mode != client.GraffitiRandom {
+		t.Errorf("Graffiti not set, received %v", v.graffiti)
+	}
+	rec = serve(t, s, http.MethodGet, "/api/v1/graffiti", "")
+	resp := &graffitiJSON{}
+	if err := json.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(resp.Graffiti, "Prysm") {
+		t.Errorf("Wanted graffiti configuration, received %q", resp.Graffiti)
+	}
+
+	rec = serve(t, s, http.MethodPut, "/api/v1/graffiti", `{"graffiti": "mode: unknown\n"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for invalid graffiti, received %d", rec.Code)
+	}
+	rec = serve(t, s, http.MethodDelete, "/api/v1/graffiti", "")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Wanted 405, received %d", rec.Code)
+	}
+}
+
+func TestServer_ImportAndListAccounts(t *testing.T) {
+	active := bls.RandKey()
+	imported := bls.RandKey()
+	s := NewServer(&Config{
+		Secret:     secret,
+		OpenWallet: testWallet(t),
+		KeyManager: keymanager.NewDirect([]*bls.SecretKey{active}),
+		Validator:  &fakeValidator{},
+	})
+
+	crypto, err := keystorev4.New().Encrypt(imported.Marshal(), []byte("keystore password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystore, err := json.Marshal(map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  hex.EncodeToString(imported.PublicKey().Marshal()),
+		"version": 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := fmt.Sprintf(`{"keystores": [{"name": "imported", "keystore": %s, "password": "%%s"}]}`, keystore)
+
+	rec := serve(t, s, http.MethodPost, "/api/v1/accounts/import", fmt.Sprintf(body, "wrong password"))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for wrong keystore password, received %d", rec.Code)
+	}
+	rec = serve(t, s, http.MethodPost, "/api/v1/accounts/import", fmt.Sprintf(body, "keystore password"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(t, s, http.MethodGet, "/api/v1/accounts", "")
+	var accounts []*accountJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &accounts); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0].Name != "imported" || accounts[0].Kind != wallet.Imported {
+		t.Fatalf("Unexpected accounts %v", accounts)
+	}
+	// The imported account is validated with after a restart.
+	if accounts[0].Active {
+		t.Error("Imported account is active")
+	}
+}
+
+func TestServer_ListAccountsWithoutWallet(t *testing.T) {
+	s := NewServer(&Config{Secret: secret, KeyManager: keymanager.NewDirect(nil), Validator: &fakeValidator{}})
+	rec := serve(t, s, http.MethodGet, "/api/v1/accounts", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted 404 without wallet, received %d", rec.Code)
+	}
+}
+
+func TestServer_ExitAccounts(t *testing.T) {
+	sk := bls.RandKey()
+	other := bls.RandKey()
+	v := &fakeValidator{}
+	s := NewServer(&Config{Secret: secret, KeyManager: keymanager.NewDirect([]*bls.SecretKey{sk}), Validator: v})
+
+	rec := serve(t, s, http.MethodPost, "/api/v1/accounts/exit", fmt.Sprintf(`{"public_keys": ["%#x"]}`, other.PublicKey().Marshal()))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted 400 for key of another validator client, received %d", rec.Code)
+	}
+	rec = serve(t, s, http.MethodPost, "/api/v1/accounts/exit", fmt.Sprintf(`{"public_keys": ["%#x"]}`, sk.PublicKey().Marshal()))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted 200, received %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(v.exited) != 1 || v.exited[0] != bytesutil.ToBytes48(sk.PublicKey().Marshal()) {
+		t.Errorf("Unexpected exited validators %v", v.exited)
+	}
+}
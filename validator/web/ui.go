@@ -0,0 +1,139 @@
+package web
+
+// indexHTML is the web UI, a single page calling the API with the token entered by the user,
+// which is kept in the session storage of the browser.
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Prysm validator</title>
+<style>
+body { font-family: sans-serif; margin: 2em; max-width: 60em; }
+table { border-collapse: collapse; width: 100%; }
+td, th { border-bottom: 1px solid #ddd; padding: 0.3em; text-align: left; font-size: 0.9em; }
+textarea { width: 100%; height: 8em; font-family: monospace; }
+.error { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Prysm validator</h1>
+<p>
+  <label>Token <input id="token" type="password" size="60"></label>
+  <button onclick="saveToken()">Use token</button>
+  <span>Issue one with <code>validator web auth</code>.</span>
+</p>
+<p id="error" class="error"></p>
+
+<h2>Accounts</h2>
+<table>
+  <thead><tr><th>Name</th><th>Kind</th><th>Public key</th><th>Active</th><th></th></tr></thead>
+  <tbody id="accounts"></tbody>
+</table>
+
+<h2>Import keystores</h2>
+<p>
+  <input id="keystores" type="file" multiple accept=".json">
+  <label>Password <input id="keystore-password" type="password"></label>
+  <button onclick="importKeystores()">Import</button>
+</p>
+<p>Imported accounts are validated with once the validator client is restarted.</p>
+
+<h2>Graffiti</h2>
+<textarea id="graffiti"></textarea>
+<p><button onclick="setGraffiti()">Set graffiti</button></p>
+
+<script>
+function token() {
+  return sessionStorage.getItem("token") || "";
+}
+
+function saveToken() {
+  sessionStorage.setItem("token", document.getElementById("token").value);
+  load();
+}
+
+async function api(method, path, body) {
+  const res = await fetch(path, {
+    method: method,
+    headers: {"Authorization": "Bearer " + token(), "Content-Type": "application/json"},
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const text = await res.text();
+  const data = text ? JSON.parse(text) : null;
+  if (!res.ok) {
+    throw new Error(data && data.message ? data.message : res.statusText);
+  }
+  return data;
+}
+
+function showError(err) {
+  document.getElementById("error").textContent = err ? err.message : "";
+}
+
+async function load() {
+  try {
+    const accounts = await api("GET", "/api/v1/accounts");
+    const rows = document.getElementById("accounts");
+    rows.innerHTML = "";
+    for (const a of accounts) {
+      const row = rows.insertRow();
+      for (const v of [a.name, a.kind, a.public_key, a.active ? "yes" : "no"]) {
+        row.insertCell().textContent = v;
+      }
+      const exit = document.createElement("button");
+      exit.textContent = "Exit";
+      exit.disabled = !a.active;
+      exit.onclick = () => exitValidator(a.public_key);
+      row.insertCell().appendChild(exit);
+    }
+    const graffiti = await api("GET", "/api/v1/graffiti");
+    document.getElementById("graffiti").value = graffiti.graffiti;
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function importKeystores() {
+  try {
+    const password = document.getElementById("keystore-password").value;
+    const keystores = [];
+    for (const f of document.getElementById("keystores").files) {
+      keystores.push({name: f.name.replace(/\.json$/, ""), keystore: await f.text(), password: password});
+    }
+    await api("POST", "/api/v1/accounts/import", {keystores: keystores});
+    await load();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function setGraffiti() {
+  try {
+    await api("PUT", "/api/v1/graffiti", {graffiti: document.getElementById("graffiti").value});
+    await load();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function exitValidator(publicKey) {
+  if (!confirm("Voluntary exits cannot be reversed. Exit validator " + publicKey + "?")) {
+    return;
+  }
+  try {
+    await api("POST", "/api/v1/accounts/exit", {public_keys: [publicKey]});
+    alert("Voluntary exit proposed.");
+  } catch (err) {
+    showError(err);
+  }
+}
+
+document.getElementById("token").value = token();
+if (token()) {
+  load();
+}
+</script>
+</body>
+</html>
+`
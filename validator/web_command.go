@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/rpcauth"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/prysmaticlabs/prysm/validator/web"
+	"github.com/urfave/cli/v2"
+)
+
+// webCommand manages the access to the web API and UI of a validator client started with --web.
+func webCommand() *cli.Command {
+	return &cli.Command{
+		Name:     "web",
+		Category: "web",
+		Usage:    "manages the access to the web API and UI of the validator client",
+		Subcommands: []*cli.Command{
+			{
+				Name: "auth",
+				Description: `prints a token authenticating requests to the web API and UI of the validator client
+using the same data directory, signed with the secret in the data directory`,
+				Flags: []cli.Flag{
+					cmd.DataDirFlag,
+					flags.WebTokenExpiryFlag,
+				},
+				Action: func(cliCtx *cli.Context) error {
+					dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+					if dataDir == "" {
+						dataDir = cmd.DefaultDataDir()
+					}
+					secret, err := rpcauth.LoadOrCreateToken(filepath.Join(dataDir, web.SecretFileName))
+					if err != nil {
+						return errors.Wrap(err, "could not load web API secret")
+					}
+					token, err := web.IssueToken(secret, cliCtx.Duration(flags.WebTokenExpiryFlag.Name))
+					if err != nil {
+						return err
+					}
+					fmt.Println(token)
+					return nil
+				},
+			},
+		},
+	}
+}